@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/api"
+)
+
+// loopbackAddr reports whether the listen address addr is reachable only
+// from this machine: localhost or a loopback IP. An address without a host
+// listens on every interface.
+func loopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// serveAPI serves the JSON API on addr instead of starting the TUI, until
+// ctx is cancelled by Ctrl+C or SIGTERM. The vault is discovered first, so
+// every request works against the same one. It returns the exit code.
+func serveAPI(ctx context.Context, addr string, env *environment, opts api.Options) int {
+	if opts.Vault == "" {
+		vault, err := env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+		opts.Vault = vault
+	}
+	opts.Client, opts.Stack, opts.Region = env.client, env.stackName, env.region.Region
+	opts.Context = ctx
+	opts.OnLockError = func(err error) {
+		fmt.Fprintf(os.Stderr, "Warning: restore lock of %s: %v\n", env.stackName, err)
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		printError(fmt.Errorf("failed to listen on -api %s: %w", addr, err))
+		return 1
+	}
+	// Clients name the address as given, e.g. localhost:8098, or as resolved
+	opts.Hosts = []string{addr, ln.Addr().String()}
+	srv := &http.Server{Handler: api.New(opts), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving the API for vault %s on http://%s/v1/\n", opts.Vault, ln.Addr())
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		printError(err)
+		return 1
+	}
+	return 0
+}
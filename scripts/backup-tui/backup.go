@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+)
+
+// batchTag is the recovery point tag that groups the backups of one run.
+const batchTag = aws.BatchTagKey
+
+// tagFlags collects repeated -tag key=value flags.
+type tagFlags map[string]string
+
+func (t tagFlags) String() string {
+	pairs := make([]string, 0, len(t))
+	for k, v := range t {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (t tagFlags) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(k) == "" {
+		return fmt.Errorf("tag must be key=value, got %q", s)
+	}
+	t[strings.TrimSpace(k)] = v
+	return nil
+}
+
+// parseStackTypes parses a -type list limited to the stack's resource
+// types, e.g. "RDS" or "RDS,EFS".
+func parseStackTypes(s string) ([]string, error) {
+	types, err := aws.ParseResourceTypes(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range types {
+		if t != "RDS" && t != "EFS" {
+			return nil, fmt.Errorf("the stack only has RDS and EFS resources, got %s", t)
+		}
+	}
+	return types, nil
+}
+
+// runBackup implements "backup-tui backup": it takes on-demand backups of
+// the stack's RDS clusters and EFS file systems, -parallel at a time, tags
+// every resulting recovery point with the -tag flags and a shared batch tag,
+// waits for them to finish, and prints a summary.
+//
+// Exit codes: 0 when every backup completed, 1 when any failed or could not
+// be started, 2 for usage errors.
+func runBackup(args []string) int {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	resourceType := fs.String("type", "", "Back up only RDS or EFS resources, or RDS,EFS (empty for all)")
+	parallel := fs.Int("parallel", 2, "How many backups run at the same time")
+	interval := fs.Duration("interval", 30*time.Second, "How often running backups are polled")
+	role := fs.String("role", "", "IAM role AWS Backup assumes (the backup plan's role if not provided)")
+	tags := tagFlags{}
+	fs.Var(tags, "tag", "Tag for every recovery point, as key=value (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	types, err := parseStackTypes(*resourceType)
+	switch {
+	case err != nil:
+		printError(fmt.Errorf("invalid -type: %w", err))
+		return 2
+	case *parallel < 1:
+		fmt.Fprintf(os.Stderr, "Error: -parallel must be at least 1, got %d\n", *parallel)
+		return 2
+	case *interval <= 0:
+		fmt.Fprintf(os.Stderr, "Error: -interval must be positive, got %s\n", *interval)
+		return 2
+	}
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printError(err)
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+	roleARN := *role
+	if roleARN == "" {
+		planRole, err := env.client.ResolvePlanRole(ctx, vaultName, false)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+		roleARN = planRole.RoleARN
+	}
+
+	all, err := env.client.StackResources(ctx, env.stackName)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	var resources []aws.ProtectedResource
+	for _, r := range all {
+		if len(types) == 0 || slices.Contains(types, r.Type) {
+			resources = append(resources, r)
+		}
+	}
+	if len(resources) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no resources to back up in stack %s\n", env.stackName)
+		return 1
+	}
+
+	batch := time.Now().UTC().Format("20060102T150405Z")
+	tags[batchTag] = batch
+	fmt.Printf("Backing up %d resource(s) to vault %s, %d at a time (tags: %s)\n\n", len(resources), vaultName, *parallel, tags)
+
+	var mu sync.Mutex
+	results := env.client.RunOnDemandBackups(ctx, resources, aws.OnDemandOptions{
+		VaultName:    vaultName,
+		IAMRoleARN:   roleARN,
+		Tags:         tags,
+		Workers:      *parallel,
+		PollInterval: *interval,
+		OnUpdate: func(b aws.OnDemandBackup) {
+			mu.Lock()
+			printBackupUpdate(os.Stdout, b)
+			mu.Unlock()
+			journalBackup(ctx, env, cfg.JournalTable(), b)
+		},
+	})
+
+	fmt.Println()
+	return printBackupSummary(os.Stdout, vaultName, batch, results)
+}
+
+// journalBackup records b's start or outcome in the config file's shared
+// journal, if it names one, so it shows in every operator's team activity
+// view. A failure is a warning: the backup carries on.
+func journalBackup(ctx context.Context, env *environment, table string, b aws.OnDemandBackup) {
+	if table == "" {
+		return
+	}
+	host, _ := os.Hostname()
+	e := aws.JournalEntry{
+		Env:          env.region.Region + "/" + env.stackName,
+		At:           time.Now(),
+		Operator:     env.client.CallerARN(),
+		Host:         host,
+		Kind:         aws.JobKindBackup,
+		ResourceType: b.Resource.Type,
+		ResourceID:   backupResourceName(b.Resource),
+		JobID:        b.JobID,
+	}
+	switch {
+	case b.Err != nil:
+		e.Event, e.Message = config.EventFailed, b.Err.Error()
+	case b.Status == nil:
+		e.Event = config.EventStarted
+	case !b.Status.IsTerminal:
+		return
+	case b.Completed():
+		e.Event = config.EventCompleted
+	default:
+		e.Event, e.Message = config.EventFailed, b.Status.StatusMessage
+	}
+	if err := env.client.RecordOperation(ctx, table, e); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// printBackupUpdate writes a timestamped progress line for b.
+func printBackupUpdate(out io.Writer, b aws.OnDemandBackup) {
+	label := fmt.Sprintf("%s %s", b.Resource.Type, backupResourceName(b.Resource))
+	switch {
+	case b.Err != nil:
+		fmt.Fprintf(out, "%s  %s: %v\n", time.Now().Format(time.TimeOnly), label, b.Err)
+	case b.Status == nil:
+		fmt.Fprintf(out, "%s  %s (%s): started\n", time.Now().Format(time.TimeOnly), label, b.JobID)
+	default:
+		line := b.Status.Status
+		if b.Status.PercentDone != "" && !b.Status.IsTerminal {
+			line += " " + b.Status.PercentDone + "%"
+		}
+		if b.Status.IsTerminal && b.Status.StatusMessage != "" {
+			line += ": " + b.Status.StatusMessage
+		}
+		fmt.Fprintf(out, "%s  %s (%s): %s\n", time.Now().Format(time.TimeOnly), label, b.JobID, line)
+	}
+}
+
+// printBackupSummary writes one line per backup with its outcome, duration,
+// and recovery point, and returns the exit code: 1 if any did not complete.
+func printBackupSummary(out io.Writer, vaultName, batch string, results []aws.OnDemandBackup) int {
+	fmt.Fprintf(out, "On-demand backups to vault %s (%s=%s):\n\n", vaultName, batchTag, batch)
+	completed := 0
+	for _, b := range results {
+		name := backupResourceName(b.Resource)
+		switch {
+		case b.Completed():
+			completed++
+			took := b.Status.CompletedAt.Sub(b.Status.CreatedAt).Round(time.Second)
+			fmt.Fprintf(out, "  ✓ %-4s %s  COMPLETED in %s\n         %s\n", b.Resource.Type, name, took, b.Status.RecoveryPointARN)
+		case b.Err != nil && b.JobID == "":
+			fmt.Fprintf(out, "  ✗ %-4s %s  not started: %v\n", b.Resource.Type, name, b.Err)
+		case b.Err != nil:
+			fmt.Fprintf(out, "  ✗ %-4s %s  not followed to the end: %v\n         job %s may still be running\n", b.Resource.Type, name, b.Err, b.JobID)
+		default:
+			status := "unknown"
+			if b.Status != nil {
+				status = b.Status.Status
+				if b.Status.StatusMessage != "" {
+					status += ": " + b.Status.StatusMessage
+				}
+			}
+			fmt.Fprintf(out, "  ✗ %-4s %s  %s\n         job %s\n", b.Resource.Type, name, status, b.JobID)
+		}
+	}
+	fmt.Fprintf(out, "\n%d of %d backup(s) completed.\n", completed, len(results))
+	if completed < len(results) {
+		return 1
+	}
+	return 0
+}
+
+// backupResourceName is the cluster name or file system ID of r.
+func backupResourceName(r aws.ProtectedResource) string {
+	if i := strings.LastIndexAny(r.ARN, "/:"); i >= 0 {
+		return r.ARN[i+1:]
+	}
+	return r.ARN
+}
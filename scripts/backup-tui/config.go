@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+)
+
+// runConfig implements "backup-tui config <subcommand>".
+func runConfig(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "Usage: backup-tui config migrate-secrets [-config file]\n       backup-tui config set-secret name < value")
+		return 2
+	}
+	switch args[0] {
+	case "migrate-secrets":
+		return runConfigMigrateSecrets(args[1:])
+	case "set-secret":
+		return runConfigSetSecret(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown config command %q. Run 'backup-tui -help' for usage.\n", args[0])
+		return 2
+	}
+}
+
+// runConfigMigrateSecrets implements "backup-tui config migrate-secrets": it
+// moves the plaintext webhook URLs, external IDs, and tokens of the config
+// file into the OS keyring and rewrites the file to reference them.
+//
+// Exit codes: 0 on success, 1 when a secret could not be stored or the file
+// rewritten, 2 for usage errors.
+func runConfigMigrateSecrets(args []string) int {
+	fs := flag.NewFlagSet("config migrate-secrets", flag.ContinueOnError)
+	path := fs.String("config", "", "Config file (default: backup-tui/config.json in the user config directory)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *path == "" {
+		var err error
+		if *path, err = config.DefaultPath(); err != nil {
+			printError(err)
+			return 1
+		}
+	}
+	names, err := config.MigrateSecrets(*path)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	if len(names) == 0 {
+		fmt.Printf("No plaintext secrets in %s.\n", *path)
+		return 0
+	}
+	for _, name := range names {
+		fmt.Printf("Moved %s to the OS keyring\n", name)
+	}
+	fmt.Printf("Rewrote %s to reference them.\n", *path)
+	return 0
+}
+
+// runConfigSetSecret implements "backup-tui config set-secret": it stores
+// the value read from standard input in the OS keyring under a name the
+// config file can reference as {"keyring": "name"}, so the value is never
+// written to the file or the shell history.
+//
+// Exit codes: 0 on success, 1 when the value could not be stored, 2 for
+// usage errors.
+func runConfigSetSecret(args []string) int {
+	fs := flag.NewFlagSet("config set-secret", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: backup-tui config set-secret name < value")
+		return 2
+	}
+	name := fs.Arg(0)
+	if stdinIsTerminal() {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+	}
+	value, _ := stdin.ReadString('\n')
+	if value = strings.TrimRight(value, "\r\n"); value == "" {
+		fmt.Fprintln(os.Stderr, "Error: no value given on standard input")
+		return 2
+	}
+	if err := config.SetSecret(name, value); err != nil {
+		printError(err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Stored %s in the OS keyring; reference it in the config as {\"keyring\": %q}\n", name, name)
+	return 0
+}
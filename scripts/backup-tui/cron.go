@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/report"
+)
+
+// runCron implements "backup-tui cron", meant for scheduled runs (e.g. a
+// weekly EventBridge-scheduled ECS task or a crontab entry): it checks
+// backup selection coverage, checks each protected resource's newest backup
+// against the RPO and each resource type's restores against the RTO (both
+// per type from the config file, falling back to -rpo), and builds the job
+// report, then prints the summary and emails it through SES and/or
+// publishes it to SNS.
+//
+// Exit codes: 0 when there are no violations, 1 when there are violations
+// or the checks or delivery failed, 2 for usage errors.
+func runCron(args []string) int {
+	fs := flag.NewFlagSet("cron", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	rpo := fs.String("rpo", "26h", "Maximum age of each resource's newest backup, e.g. 26h or 2d, for resource types without an RPO target in the config")
+	window := fs.String("window", "7d", "How far back to report jobs, e.g. 7d, 30d, or 36h")
+	emailFrom := fs.String("email-from", "", "SES-verified sender address for the summary email")
+	emailTo := fs.String("email-to", "", "Comma-separated recipients of the summary email")
+	snsTopic := fs.String("sns-topic", "", "SNS topic ARN to publish the summary to")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	maxAge, err := parseSpan("-rpo", *rpo)
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	span, err := parseWindow(*window)
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	recipients := splitList(*emailTo)
+	if (*emailFrom == "") != (len(recipients) == 0) {
+		fmt.Fprintln(os.Stderr, "Error: -email-from and -email-to must be given together")
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+
+	summary, _, _, err := collectSummary(ctx, env, vaultName, maxAge, span, cfg)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	msg := aws.SummaryMessage{Subject: summary.Subject(), Body: summary.Markdown()}
+	fmt.Print(msg.Body)
+
+	status := 0
+	if summary.Violations() > 0 {
+		status = 1
+	}
+	if *emailFrom != "" {
+		if err := env.client.SendSummaryEmail(ctx, *emailFrom, recipients, msg); err != nil {
+			printError(err)
+			status = 1
+		} else {
+			fmt.Fprintf(os.Stderr, "Emailed summary to %s\n", strings.Join(recipients, ", "))
+		}
+	}
+	if *snsTopic != "" {
+		if err := env.client.PublishSummary(ctx, *snsTopic, msg); err != nil {
+			printError(err)
+			status = 1
+		} else {
+			fmt.Fprintf(os.Stderr, "Published summary to %s\n", *snsTopic)
+		}
+	}
+	return status
+}
+
+// collectSummary checks vaultName's coverage, recovery points, and jobs of
+// the last span against the RPO/RTO targets, as "cron" reports and "serve"
+// shows them, and returns the summary with the recovery points and job
+// records it was built from.
+func collectSummary(ctx context.Context, env *environment, vaultName string, rpo, span time.Duration, cfg *config.Config) (*report.Summary, []aws.RecoveryPoint, []aws.JobRecord, error) {
+	coverage, err := env.client.CheckCoverage(ctx, env.stackName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	points, err := env.client.ListRecoveryPoints(ctx, vaultName, "")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	until := time.Now()
+	since := until.Add(-span)
+	records, err := env.client.ListJobs(ctx, vaultName, since)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	jobs := report.BuildJobs(records, since, until)
+	jobs.Stack, jobs.Vault, jobs.Region = env.stackName, vaultName, env.region.Region
+	summary := report.BuildSummary(coverage, points, jobs, rpo, cfg, until)
+	summary.Stack, summary.Vault, summary.Region = env.stackName, vaultName, env.region.Region
+	return summary, points, records, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
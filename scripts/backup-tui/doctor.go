@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// runDoctor implements "backup-tui doctor": it checks that the stack's RDS
+// cluster and EFS file systems are in a backup selection and, with -fix,
+// offers to create or update a selection for any that are not.
+//
+// Exit codes: 0 when everything is covered (or was fixed), 1 when coverage
+// is missing or the check failed, 2 for usage errors.
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	fix := fs.Bool("fix", false, "Offer to create or update a backup selection for uncovered resources")
+	yes := fs.Bool("yes", false, "Apply -fix without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+
+	results, err := env.client.CheckCoverage(ctx, env.stackName)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	missing := printCoverage(os.Stdout, env.stackName, results)
+	if missing == 0 {
+		return 0
+	}
+	if !*fix {
+		fmt.Println("\nRun 'backup-tui doctor -fix' to add the missing resources to a backup selection.")
+		return 1
+	}
+
+	return repairCoverage(ctx, env.client, env.stackName, vaultName, results, *yes, stdin, os.Stdout)
+}
+
+// printCoverage writes a coverage report and returns the number of
+// resources not covered by any backup selection.
+func printCoverage(out io.Writer, stackName string, results []aws.CoverageResult) int {
+	fmt.Fprintf(out, "Backup coverage for stack %s:\n\n", stackName)
+	if len(results) == 0 {
+		fmt.Fprintln(out, "  No RDS clusters or EFS file systems found in the stack.")
+		return 0
+	}
+
+	missing := 0
+	for _, r := range results {
+		switch {
+		case r.Covered:
+			fmt.Fprintf(out, "  ✓ %-4s %s\n         in %s/%s\n", r.Resource.Type, r.Resource.ARN, r.PlanName, r.SelectionName)
+		default:
+			missing++
+			fmt.Fprintf(out, "  ✗ %-4s %s\n         not in any backup selection\n", r.Resource.Type, r.Resource.ARN)
+			if len(r.TagSelections) > 0 {
+				fmt.Fprintf(out, "         (may be covered by tag-based selections: %s)\n", strings.Join(r.TagSelections, ", "))
+			}
+		}
+	}
+	return missing
+}
+
+// repairCoverage shows the proposed selection change as a diff, asks for
+// confirmation (unless assumeYes), and applies it.
+func repairCoverage(ctx context.Context, client *aws.BackupClient, stackName, vaultName string, results []aws.CoverageResult, assumeYes bool, in *bufio.Reader, out io.Writer) int {
+	fix, err := client.PlanCoverageFix(ctx, stackName, vaultName, results)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	if fix == nil {
+		return 0
+	}
+
+	fmt.Fprintln(out, "\nProposed change:")
+	for _, line := range fix.Diff() {
+		fmt.Fprintf(out, "  %s\n", line)
+	}
+
+	if !assumeYes && !confirm(in, out, "\nApply this change? [y/N]: ") {
+		fmt.Fprintln(out, "No changes made.")
+		return 1
+	}
+
+	if err := client.ApplyCoverageFix(ctx, fix); err != nil {
+		printError(err)
+		return 1
+	}
+	fmt.Fprintf(out, "Backup selection %s saved. Resources will be included in the next scheduled backup.\n", fix.Proposed.Name)
+	fmt.Fprintln(out, "Note: this selection is not managed by CloudFormation; consider adding the resources to the stack's backup plan as well.")
+	return 0
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes.
+// in is shared by every prompt of the command, normally stdin.
+func confirm(in *bufio.Reader, out io.Writer, prompt string) bool {
+	fmt.Fprint(out, prompt)
+	line, _ := in.ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/webhook"
+)
+
+// runDR implements "backup-tui dr <subcommand>": recovery into the separate
+// account of the config file's "crossAccount" section, for when the stack's
+// own account is compromised or lost.
+func runDR(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "Usage: backup-tui dr copy [-type RDS,EFS] [-recovery-point arn] [-restore] [-resume] [options]")
+		fmt.Fprintln(os.Stderr, "       backup-tui dr restore -recovery-point arn [-subnet-group name] [-security-groups ids] [options]")
+		fmt.Fprintln(os.Stderr, "       backup-tui dr scan [-regions us-east-1,...] [-max-age 2d] [-output text|json] [options]")
+		return 2
+	}
+	switch args[0] {
+	case "copy":
+		return runDRCopy(args[1:])
+	case "restore":
+		return runDRRestore(args[1:])
+	case "scan":
+		return runDRScan(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown dr command %q. Run 'backup-tui -help' for usage.\n", args[0])
+		return 2
+	}
+}
+
+// drOptions are the flags of the restore step, shared by "dr copy -restore"
+// and "dr restore".
+type drOptions struct {
+	subnetGroup    string
+	securityGroups string
+	interval       time.Duration
+}
+
+// register defines the restore step's flags on fs.
+func (o *drOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.subnetGroup, "subnet-group", "", "DB subnet group in the recovery account for a restored Aurora cluster (default VPC if not provided)")
+	fs.StringVar(&o.securityGroups, "security-groups", "", "Comma-separated security group IDs in the recovery account for a restored Aurora cluster")
+	fs.DurationVar(&o.interval, "interval", 30*time.Second, "How often running jobs are polled")
+}
+
+// restoreOptions returns the options of restores in the recovery account.
+func (o drOptions) restoreOptions() aws.RestoreOptions {
+	opts := aws.RestoreOptions{SubnetGroup: o.subnetGroup}
+	for _, id := range strings.Split(o.securityGroups, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			opts.SecurityGroupIDs = append(opts.SecurityGroupIDs, id)
+		}
+	}
+	return opts
+}
+
+// runDRCopy implements "backup-tui dr copy": it checks the recovery role and
+// vault, copies each resource's latest restorable backup (or the one given)
+// into the recovery vault, waits for the copies, and prints how to restore
+// them. With -restore it goes on to restore the copies there. Each run is
+// saved as it goes, and -resume continues an interrupted one.
+//
+// Exit codes: 0 when every copy (and restore) completed, 1 when any failed
+// or the recovery account could not be reached, 2 for usage errors.
+func runDRCopy(args []string) int {
+	fs := flag.NewFlagSet("dr copy", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	var dr drOptions
+	dr.register(fs)
+	resourceType := fs.String("type", "", "Copy only RDS or EFS backups, or RDS,EFS (empty for all)")
+	recoveryPoint := fs.String("recovery-point", "", "Copy this recovery point instead of each resource's latest restorable one")
+	role := fs.String("role", "", "IAM role AWS Backup copies with (the backup plan's role if not provided)")
+	restore := fs.Bool("restore", false, "Restore the copies in the recovery account once they complete")
+	resume := fs.Bool("resume", false, "Resume the interrupted run to the same recovery vault instead of copying again")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	types, err := parseStackTypes(*resourceType)
+	switch {
+	case err != nil:
+		printError(fmt.Errorf("invalid -type: %w", err))
+		return 2
+	case dr.interval <= 0:
+		fmt.Fprintf(os.Stderr, "Error: -interval must be positive, got %s\n", dr.interval)
+		return 2
+	}
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	if err := cfg.CrossAccount.Validate(); err != nil {
+		printError(err)
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+
+	fmt.Println("Step 1/3: checking the recovery account")
+	target, recovery, err := assumeRecovery(ctx, env, cfg.CrossAccount)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	roleARN := *role
+	if roleARN == "" {
+		planRole, err := env.client.ResolvePlanRole(ctx, vaultName, false)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+		roleARN = planRole.RoleARN
+	}
+
+	path := ""
+	var notifier *webhook.Notifier
+	if !env.client.Simulated() {
+		path, _ = store.DefaultWorkflowsPath()
+		if notifier, err = webhook.New(cfg.Webhooks); err != nil {
+			printError(err)
+			return 1
+		}
+	}
+	run, err := interruptedDRCopy(path, env.region.Region, vaultName, target)
+	if err != nil {
+		fmt.Printf("  ⚠ interrupted runs could not be read: %v\n", err)
+	}
+	if run != nil && !confirmResume(run, *resume) {
+		run.close()
+		run = nil
+	}
+	switch {
+	case run != nil:
+		*restore = *restore || run.Params["restore"] == "true"
+		fmt.Printf("  ✓ resuming the run started %s\n", run.StartedAt.Local().Format("2006-01-02 15:04"))
+	case *resume:
+		fmt.Fprintf(os.Stderr, "Error: no interrupted run from vault %s to vault %s to resume\n", vaultName, target.VaultName)
+		return 1
+	default:
+		points, err := copyPoints(ctx, env.client, vaultName, *recoveryPoint, types)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+		run = &drRun{path: path, Workflow: store.Workflow{
+			ID:        store.NewWorkflowID(),
+			Kind:      store.WorkflowDRCopy,
+			Region:    env.region.Region,
+			Vault:     vaultName,
+			Target:    drTarget(target),
+			StartedAt: time.Now(),
+			Params:    map[string]string{"restore": strconv.FormatBool(*restore)},
+		}}
+		for _, p := range points {
+			run.Steps = append(run.Steps, store.WorkflowStep{
+				Kind:             aws.JobKindCopy,
+				ResourceType:     p.ResourceType,
+				ResourceID:       p.ResourceID,
+				RecoveryPointARN: p.RecoveryPointARN,
+				State:            store.StepPending,
+			})
+		}
+		run.save()
+	}
+	run.notifier, run.stack = notifier, env.stackName
+
+	copySteps := slices.IndexFunc(run.Steps, func(s store.WorkflowStep) bool { return s.Kind != aws.JobKindCopy })
+	if copySteps < 0 {
+		copySteps = len(run.Steps)
+	}
+	fmt.Printf("\nStep 2/3: copying %d backup(s) from vault %s to vault %s in account %s\n\n", copySteps, vaultName, target.VaultName, target.AccountID)
+	var (
+		jobs   []store.TrackedJob
+		copies []string
+	)
+	failed := 0
+	for i, step := range run.Steps[:copySteps] {
+		switch {
+		case step.Completed():
+			fmt.Printf("  ✓ %-4s %s was copied before the interruption\n", step.ResourceType, step.ResourceID)
+			if step.Result != "" {
+				copies = append(copies, step.Result)
+			}
+		case step.Finished():
+			fmt.Printf("  ✗ %-4s %s did not copy before the interruption: %s\n", step.ResourceType, step.ResourceID, cmp.Or(step.Result, step.State))
+			failed++
+		case step.Started():
+			jobs = append(jobs, trackedStep(step, env.region.Region, vaultName))
+		default:
+			id, err := env.client.StartCrossAccountCopy(ctx, vaultName, step.RecoveryPointARN, roleARN, target)
+			run.started(i, id, err)
+			if err != nil {
+				fmt.Printf("  ✗ %s: %v\n", step.RecoveryPointARN, err)
+				failed++
+				continue
+			}
+			jobs = append(jobs, trackedStep(run.Steps[i], env.region.Region, vaultName))
+		}
+	}
+
+	failed += watchJobs(ctx, jobs, func(string) (*aws.BackupClient, error) { return env.client, nil }, dr.interval, os.Stdout,
+		func(j store.TrackedJob) {
+			var copyARN string
+			if j.State == "COMPLETED" {
+				if status, err := env.client.GetCopyJobStatus(ctx, j.JobID); err == nil && status.RecoveryPointARN != "" {
+					copyARN = status.RecoveryPointARN
+					copies = append(copies, copyARN)
+					if err := recovery.TagCreatedResource(ctx, env.client, copyARN); err != nil {
+						fmt.Printf("  ⚠ %v\n", err)
+					}
+				}
+			}
+			run.finished(j, copyARN)
+		})
+	if ctx.Err() != nil {
+		return interrupted(run)
+	}
+
+	if len(copies) == 0 {
+		run.close()
+		fmt.Fprintln(os.Stderr, "\nError: no backup was copied to the recovery account")
+		return 1
+	}
+	fmt.Printf("\nStep 3/3: restore in account %s\n\n", target.AccountID)
+	if !*restore {
+		run.close()
+		fmt.Printf("%d copy(ies) are in vault %s. Restore them in the recovery account with:\n\n", len(copies), target.VaultName)
+		for _, arn := range copies {
+			fmt.Printf("  backup-tui dr restore -recovery-point %s\n", arn)
+		}
+		if failed > 0 {
+			return 1
+		}
+		return 0
+	}
+	failed += restoreInRecovery(ctx, env.client, recovery, target, cfg.CrossAccount.RestoreRoleARN, copies, dr, run)
+	if ctx.Err() != nil {
+		return interrupted(run)
+	}
+	run.close()
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// copyPoints returns the recovery points "dr copy" copies: the one given, or
+// the latest restorable backup of each of the stack's resources of types.
+func copyPoints(ctx context.Context, client *aws.BackupClient, vaultName, recoveryPoint string, types []string) ([]aws.LatestPoint, error) {
+	if recoveryPoint != "" {
+		return []aws.LatestPoint{{RecoveryPointARN: recoveryPoint}}, nil
+	}
+	latest, err := client.LatestRestorablePoints(ctx, vaultName)
+	if err != nil {
+		return nil, err
+	}
+	var points []aws.LatestPoint
+	for _, p := range latest {
+		switch {
+		case len(types) > 0 && !slices.Contains(types, p.ResourceType):
+		case !p.Found():
+			fmt.Printf("  ✗ %-4s %s has no restorable backup to copy\n", p.ResourceType, p.ResourceID)
+		default:
+			points = append(points, p)
+		}
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no restorable backups to copy in vault %s", vaultName)
+	}
+	return points, nil
+}
+
+// interrupted reports a run stopped by a signal, which is left saved for
+// "dr copy -resume", and returns its exit code.
+func interrupted(run *drRun) int {
+	fmt.Fprintln(os.Stderr, "\nInterrupted. Jobs already started keep running on AWS.")
+	if run.path != "" {
+		fmt.Fprintln(os.Stderr, "Continue the run with: backup-tui dr copy -resume")
+	}
+	return 1
+}
+
+// runDRRestore implements "backup-tui dr restore": it restores a copied
+// recovery point in the recovery account as a new Aurora cluster or EFS
+// file system, and waits for the restore. Only the operator's credentials
+// and the recovery role are used, so it works without the stack's account.
+//
+// Exit codes: 0 when the restore completed, 1 when it failed or the
+// recovery account could not be reached, 2 for usage errors.
+func runDRRestore(args []string) int {
+	fs := flag.NewFlagSet("dr restore", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	var dr drOptions
+	dr.register(fs)
+	recoveryPoint := fs.String("recovery-point", "", "ARN of the copy in the recovery vault to restore")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	switch {
+	case *recoveryPoint == "":
+		fmt.Fprintln(os.Stderr, "Error: specify the copy to restore with -recovery-point (printed by 'backup-tui dr copy')")
+		return 2
+	case dr.interval <= 0:
+		fmt.Fprintf(os.Stderr, "Error: -interval must be positive, got %s\n", dr.interval)
+		return 2
+	}
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	if err := cfg.CrossAccount.Validate(); err != nil {
+		printError(err)
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connectClient(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	target, recovery, err := assumeRecovery(ctx, env, cfg.CrossAccount)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	if restoreInRecovery(ctx, env.client, recovery, target, cfg.CrossAccount.RestoreRoleARN, []string{*recoveryPoint}, dr, nil) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// assumeRecovery assumes the recovery role of ca and checks it can see the
+// recovery vault. The vault's region defaults to the session's.
+func assumeRecovery(ctx context.Context, env *environment, ca *config.CrossAccount) (aws.CrossAccountTarget, *aws.BackupClient, error) {
+	target := aws.CrossAccountTarget{
+		AccountID: ca.AccountID,
+		Region:    ca.Region,
+		VaultName: ca.Vault,
+		RoleARN:   ca.RoleARN,
+	}
+	if target.Region == "" {
+		target.Region = env.region.Region
+	}
+	externalID, err := ca.ExternalID.Reveal()
+	if err != nil {
+		return target, nil, fmt.Errorf("failed to read crossAccount.externalId: %w", err)
+	}
+	target.ExternalID = externalID
+
+	recovery, err := env.client.AssumeRecoveryRole(ctx, target)
+	if err != nil {
+		return target, nil, err
+	}
+	fmt.Printf("  ✓ assumed %s\n", target.RoleARN)
+	vault, err := recovery.DescribeVault(ctx, target.VaultName)
+	if err != nil {
+		return target, nil, fmt.Errorf("recovery vault %s is not reachable in account %s: %w", target.VaultName, target.AccountID, err)
+	}
+	fmt.Printf("  ✓ vault %s in %s (%d recovery point(s))\n", vault.Name, target.Region, vault.RecoveryPoints)
+	return target, recovery, nil
+}
+
+// restoreInRecovery restores each copy in the recovery vault as new
+// resources, waits for the restores, and returns how many did not complete.
+// The resources are tagged with the identity of operator, the client of the
+// stack's account. Restores already started by run, if resumed, are waited
+// for rather than started again.
+func restoreInRecovery(ctx context.Context, operator, recovery *aws.BackupClient, target aws.CrossAccountTarget, roleARN string, copies []string, dr drOptions, run *drRun) int {
+	failed := 0
+	var (
+		jobs     []store.TrackedJob
+		restored []string
+	)
+	for _, arn := range copies {
+		i := run.step(aws.JobKindRestore, arn)
+		if i >= 0 && run.Steps[i].Started() {
+			switch step := run.Steps[i]; {
+			case step.Completed():
+				restored = append(restored, fmt.Sprintf("%-4s %s", step.ResourceType, step.Result))
+			case step.Finished():
+				fmt.Printf("  ✗ %s did not restore before the interruption: %s\n", arn, step.State)
+				failed++
+			default:
+				jobs = append(jobs, trackedStep(step, target.Region, target.VaultName))
+			}
+			continue
+		}
+		details, err := recovery.DescribeRecoveryPointDetails(ctx, target.VaultName, arn)
+		if err != nil {
+			fmt.Printf("  ✗ %s: %v\n", arn, err)
+			failed++
+			continue
+		}
+		rp := aws.RecoveryPoint{RecoveryPointARN: arn, ResourceType: details.ResourceType}
+		id, err := recovery.StartSandboxRestore(ctx, target.VaultName, rp, roleARN, dr.restoreOptions())
+		if i < 0 && run != nil {
+			run.Steps = append(run.Steps, store.WorkflowStep{
+				Kind:             aws.JobKindRestore,
+				ResourceType:     details.ResourceType,
+				ResourceID:       arn[strings.LastIndex(arn, ":")+1:],
+				RecoveryPointARN: arn,
+			})
+			i = len(run.Steps) - 1
+		}
+		run.started(i, id, err)
+		if err != nil {
+			fmt.Printf("  ✗ %s: %v\n", arn, err)
+			failed++
+			continue
+		}
+		jobs = append(jobs, store.TrackedJob{
+			JobID:            id,
+			Kind:             aws.JobKindRestore,
+			Region:           target.Region,
+			Vault:            target.VaultName,
+			ResourceType:     details.ResourceType,
+			ResourceID:       arn[strings.LastIndex(arn, ":")+1:], // The copy, until the restore creates its resource
+			RecoveryPointARN: arn,
+			StartedAt:        time.Now(),
+		})
+	}
+
+	failed += watchJobs(ctx, jobs, func(string) (*aws.BackupClient, error) { return recovery, nil }, dr.interval, os.Stdout,
+		func(j store.TrackedJob) {
+			if j.State != "COMPLETED" {
+				run.finished(j, "")
+				return
+			}
+			status, err := recovery.GetRestoreJobStatus(ctx, j.JobID)
+			if err != nil {
+				run.finished(j, "restored by job "+j.JobID)
+				return
+			}
+			result := cmp.Or(status.ResourceID, "restored by job "+j.JobID)
+			restored = append(restored, fmt.Sprintf("%-4s %s", j.ResourceType, result))
+			run.finished(j, result)
+			if status.CreatedResourceARN == "" {
+				return
+			}
+			if err := recovery.TagCreatedResource(ctx, operator, status.CreatedResourceARN); err != nil {
+				fmt.Printf("  ⚠ %v\n", err)
+			}
+		})
+
+	fmt.Printf("\n%d of %d restore(s) completed in account %s.\n", len(restored), len(copies), target.AccountID)
+	for _, r := range restored {
+		fmt.Printf("  ✓ %s\n", r)
+	}
+	return failed
+}
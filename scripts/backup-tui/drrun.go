@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/webhook"
+)
+
+// drResumeWindow is how recently an interrupted "dr copy" run must have
+// been saved to be offered for resuming; copies and restores finish within
+// hours, and an older run is better started again.
+const drResumeWindow = 3 * 24 * time.Hour
+
+// drRun is the state of a "dr copy" run: one step per copy, then one per
+// restore of a copy. It is saved to the workflow file as each step starts
+// and finishes, so a run interrupted by a closed terminal or a crash can be
+// resumed with "dr copy -resume" without copying again.
+type drRun struct {
+	path     string            // Workflow file ("" when not saved, e.g. for a simulated environment)
+	notifier *webhook.Notifier // Webhooks notified as steps start and finish (nil for none)
+	stack    string
+	store.Workflow
+}
+
+// drTarget identifies the recovery account and vault a run copies to.
+func drTarget(target aws.CrossAccountTarget) string {
+	return fmt.Sprintf("%s/%s/%s", target.AccountID, target.Region, target.VaultName)
+}
+
+// interruptedDRCopy returns the most recent unfinished "dr copy" run from
+// vault to target, or nil if there is none.
+func interruptedDRCopy(path, region, vault string, target aws.CrossAccountTarget) (*drRun, error) {
+	if path == "" {
+		return nil, nil
+	}
+	workflows, err := store.LoadWorkflows(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range slices.Backward(workflows) {
+		if w.Kind == store.WorkflowDRCopy && !w.Closed && w.Region == region && w.Vault == vault &&
+			w.Target == drTarget(target) && time.Since(w.UpdatedAt) < drResumeWindow {
+			return &drRun{path: path, Workflow: w}, nil
+		}
+	}
+	return nil, nil
+}
+
+// save writes the run to the workflow file. A failure is printed but does
+// not stop the run.
+func (r *drRun) save() {
+	if r == nil || r.path == "" {
+		return
+	}
+	if err := store.SaveWorkflow(r.path, r.Workflow); err != nil {
+		fmt.Printf("  ⚠ run is not saved for resuming: %v\n", err)
+	}
+}
+
+// close saves the run as finished, so it is not offered for resuming.
+func (r *drRun) close() {
+	if r == nil {
+		return
+	}
+	r.Closed = true
+	r.save()
+}
+
+// step returns the index of the step of kind for recovery point arn, or -1.
+func (r *drRun) step(kind, arn string) int {
+	if r == nil {
+		return -1
+	}
+	return slices.IndexFunc(r.Steps, func(s store.WorkflowStep) bool {
+		return s.Kind == kind && s.RecoveryPointARN == arn
+	})
+}
+
+// started records that the step at i started job id, or failed to start.
+func (r *drRun) started(i int, id string, err error) {
+	if r == nil || i < 0 {
+		return
+	}
+	event := config.EventStarted
+	if err != nil {
+		r.Steps[i].State = "FAILED"
+		r.Steps[i].Result = err.Error()
+		event = config.EventFailed
+	} else {
+		r.Steps[i].JobID, r.Steps[i].StartedAt, r.Steps[i].State = id, time.Now(), "RUNNING"
+	}
+	r.save()
+	r.notify(i, event)
+}
+
+// finished records the final state of job j and what it produced.
+func (r *drRun) finished(j store.TrackedJob, result string) {
+	if r == nil {
+		return
+	}
+	for i, s := range r.Steps {
+		if s.JobID == j.JobID {
+			r.Steps[i].State, r.Steps[i].Result = j.State, result
+			r.save()
+			if r.Steps[i].Completed() {
+				r.notify(i, config.EventCompleted)
+			} else {
+				r.notify(i, config.EventFailed)
+			}
+			return
+		}
+	}
+}
+
+// notify posts the transition of the step at i to the webhooks. A failure
+// is printed but does not stop the run.
+func (r *drRun) notify(i int, event string) {
+	if r.notifier == nil {
+		return
+	}
+	s := r.Steps[i]
+	e := webhook.Event{
+		Event:      event,
+		Workflow:   r.Kind,
+		WorkflowID: r.ID,
+		Stack:      r.stack,
+		Region:     r.Region,
+		Vault:      r.Vault,
+		Step: webhook.Step{
+			Index:            i + 1,
+			Total:            len(r.Steps),
+			Kind:             s.Kind,
+			ResourceType:     s.ResourceType,
+			ResourceID:       s.ResourceID,
+			RecoveryPointARN: s.RecoveryPointARN,
+			JobID:            s.JobID,
+			State:            s.State,
+		},
+	}
+	if event == config.EventFailed && !s.Started() {
+		e.Step.Message = s.Result // Why it failed to start
+	}
+	if err := r.notifier.Send(context.Background(), e); err != nil {
+		fmt.Printf("  ⚠ %v\n", err)
+	}
+}
+
+// trackedStep returns the job of a started step for watchJobs.
+func trackedStep(s store.WorkflowStep, region, vault string) store.TrackedJob {
+	return store.TrackedJob{
+		JobID:            s.JobID,
+		Kind:             s.Kind,
+		Region:           region,
+		Vault:            vault,
+		ResourceType:     s.ResourceType,
+		ResourceID:       s.ResourceID,
+		RecoveryPointARN: s.RecoveryPointARN,
+		StartedAt:        s.StartedAt,
+	}
+}
+
+// confirmResume reports whether the interrupted run should be resumed:
+// always with -resume, after asking when run interactively, and never
+// otherwise. A run not resumed is discarded.
+func confirmResume(run *drRun, resume bool) bool {
+	done := len(run.Steps) - run.Unfinished()
+	fmt.Printf("  ⚠ an interrupted run saved %s was found (%d of %d step(s) finished)\n",
+		run.UpdatedAt.Local().Format("2006-01-02 15:04"), done, len(run.Steps))
+	switch {
+	case resume:
+		return true
+	case stdinIsTerminal():
+		return confirm(stdin, os.Stdout, "  Resume it instead of copying again? [y/N] ")
+	}
+	fmt.Println("  ⚠ discarding it and starting a new run; pass -resume to continue it instead")
+	return false
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+)
+
+// drScanOutput is the JSON document printed by "backup-tui dr scan -output json".
+type drScanOutput struct {
+	Stack       string             `json:"stack"`
+	Vault       string             `json:"vault"`
+	Region      string             `json:"region"`
+	GeneratedAt time.Time          `json:"generatedAt"`
+	MaxAge      string             `json:"maxAge"`
+	Regions     []aws.RegionCopies `json:"regions"`
+}
+
+// runDRScan implements "backup-tui dr scan": it looks in each DR region,
+// in parallel, for the copies cross-region copy rules made of the stack's
+// vault, and reports per resource how many there are and how old the newest
+// is. A resource with no copy, or none newer than -max-age, means a copy
+// rule is broken; copies of resources no longer in the stack are listed as
+// orphaned.
+//
+// Exit codes: 0 when every resource has a recent copy in every region, 1
+// when one does not or a region could not be scanned, 2 for usage errors.
+func runDRScan(args []string) int {
+	fs := flag.NewFlagSet("dr scan", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	regions := fs.String("regions", "", "Comma-separated DR regions to scan (default: the config file's drRegions)")
+	maxAge := fs.String("max-age", "2d", "Newest copy age beyond which a resource's copies are stale, e.g. 2d or 30h")
+	output := fs.String("output", "text", "Output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "Error: -output must be text or json, got %q\n", *output)
+		return 2
+	}
+	stale, err := parseSpan("-max-age", *maxAge)
+	if err != nil {
+		printError(err)
+		return 2
+	}
+
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	scan := cfg.DRRegions
+	if *regions != "" {
+		scan = splitList(*regions)
+	}
+	if err := config.ValidateRegions(scan); err != nil {
+		printError(err)
+		return 2
+	}
+	if len(scan) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: specify the DR regions with -regions or \"drRegions\" in the config file")
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+	stack, err := env.client.StackResources(ctx, env.stackName)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	results := env.client.ScanRegionCopies(ctx, env.client.VaultARN(vaultName), stack, scan)
+	now := time.Now()
+
+	if *output == "json" {
+		data, err := json.MarshalIndent(drScanOutput{
+			Stack:       env.stackName,
+			Vault:       vaultName,
+			Region:      env.region.Region,
+			GeneratedAt: now.UTC(),
+			MaxAge:      stale.String(),
+			Regions:     results,
+		}, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to encode output: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+	} else {
+		printDRScan(os.Stdout, vaultName, env.region.Region, results, stale, now)
+	}
+
+	for _, rc := range results {
+		if rc.Err != nil {
+			return 1
+		}
+		for _, r := range rc.Resources {
+			if !r.Orphaned && (r.Copies == 0 || r.Age(now) > stale) {
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+// printDRScan writes the copies found in each region as text.
+func printDRScan(out io.Writer, vaultName, region string, results []aws.RegionCopies, stale time.Duration, now time.Time) {
+	fmt.Fprintf(out, "Copies of vault %s (%s) in DR regions; stale after %s:\n", vaultName, region, copyAge(stale))
+	for _, rc := range results {
+		fmt.Fprintln(out)
+		if rc.Err != nil {
+			fmt.Fprintf(out, "%s\n  ✗ not scanned: %v\n", rc.Region, rc.Err)
+			continue
+		}
+		if len(rc.Vaults) == 0 {
+			fmt.Fprintf(out, "%s  (no vault holds copies)\n", rc.Region)
+		} else {
+			fmt.Fprintf(out, "%s  (vaults: %s)\n", rc.Region, strings.Join(rc.Vaults, ", "))
+		}
+		for _, r := range rc.Resources {
+			switch {
+			case r.Orphaned:
+				fmt.Fprintf(out, "  - %-4s %s  %d cop%s, newest %s ago: orphaned, no longer in the stack\n",
+					r.ResourceType, r.ResourceID, r.Copies, plural(r.Copies), copyAge(r.Age(now)))
+			case r.Copies == 0:
+				fmt.Fprintf(out, "  ✗ %-4s %s  no copies: check the plan's copy rule to %s\n", r.ResourceType, r.ResourceID, rc.Region)
+			case r.Age(now) > stale:
+				fmt.Fprintf(out, "  ✗ %-4s %s  %d cop%s, newest %s ago: stale, the copy rule may have stopped\n",
+					r.ResourceType, r.ResourceID, r.Copies, plural(r.Copies), copyAge(r.Age(now)))
+			default:
+				fmt.Fprintf(out, "  ✓ %-4s %s  %d cop%s, newest %s ago, oldest %s ago\n",
+					r.ResourceType, r.ResourceID, r.Copies, plural(r.Copies), copyAge(r.Age(now)), copyAge(now.Sub(r.Oldest)))
+			}
+		}
+	}
+}
+
+// copyAge formats an age in whole hours or days and hours, e.g. "5h" or
+// "3d4h".
+func copyAge(d time.Duration) string {
+	h := int(d.Round(time.Hour).Hours())
+	if h < 24 {
+		return fmt.Sprintf("%dh", h)
+	}
+	if h%24 == 0 {
+		return fmt.Sprintf("%dd", h/24)
+	}
+	return fmt.Sprintf("%dd%dh", h/24, h%24)
+}
+
+// plural returns the suffix of "copy" or "copies" for n.
+func plural(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
@@ -0,0 +1,591 @@
+// Package api serves the backup TUI's core operations as a local
+// JSON-over-HTTP API for automation (backup-tui -api): listing and
+// describing recovery points, starting restores, following jobs, and the
+// doctor's coverage check. It reuses the TUI's discovery and restore logic
+// in the aws package, so other tooling does not reimplement it. Its
+// restores take the stack's advisory restore lock as the TUI's do.
+//
+// Every request must present the config's bearer token and name the
+// listen address in its Host header, so neither another local user nor a
+// web page rebinding its DNS name to the address can use it. Every
+// response is a JSON object; failures are {"error": "..."} with the AWS
+// operation and request ID in "aws" when an AWS call failed.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+// defaultJobsWindow is how far back GET /v1/jobs lists jobs without since.
+const defaultJobsWindow = 7 * 24 * time.Hour
+
+// maxBody is the largest request body accepted.
+const maxBody = 64 << 10
+
+// lockPoll is how often the restores holding the restore lock are polled
+// for whether they have finished.
+const lockPoll = time.Minute
+
+// Options configure a Server.
+type Options struct {
+	Client *aws.BackupClient
+	Stack  string
+	Vault  string
+	Region string
+
+	// Token is the bearer token every request must present. Without one,
+	// every request is refused.
+	Token string
+
+	// Hosts are the Host header values requests may name: the listen
+	// address, as given and as resolved.
+	Hosts []string
+
+	// Config holds the organization's policies; its change freeze windows
+	// refuse restores unless OverrideFreeze is set. Nil sets none.
+	Config         *config.Config
+	OverrideFreeze bool
+
+	// HistoryPath is the job history file started restores are saved to,
+	// for "backup-tui watch" and the TUI ("" disables saving).
+	HistoryPath string
+
+	// LocksPath is the local restore lock file shared with the TUI ("" keeps
+	// the lock only where the config shares it).
+	LocksPath string
+
+	// Context ends the server's hold on the restore lock, e.g. on shutdown,
+	// leaving it to expire. Nil is context.Background().
+	Context context.Context
+
+	// OnLockError, if set, is called with errors keeping the restore lock
+	// of restores already started.
+	OnLockError func(error)
+}
+
+// Server is the API's http.Handler.
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+	now  func() time.Time
+
+	// The restore lock, held while any restore the server started runs
+	lockMu   sync.Mutex
+	running  int    // Restores holding the lock
+	stopLock func() // Releases the held lock
+}
+
+// New creates a Server for the stack and vault in opts.
+func New(opts Options) *Server {
+	if opts.Context == nil {
+		opts.Context = context.Background()
+	}
+	if opts.OnLockError == nil {
+		opts.OnLockError = func(error) {}
+	}
+	s := &Server{opts: opts, mux: http.NewServeMux(), now: time.Now}
+	s.mux.HandleFunc("GET /v1/environment", s.environment)
+	s.mux.HandleFunc("GET /v1/recovery-points", s.listRecoveryPoints)
+	s.mux.HandleFunc("GET /v1/recovery-point", s.describeRecoveryPoint)
+	s.mux.HandleFunc("POST /v1/restores", s.startRestore)
+	s.mux.HandleFunc("GET /v1/jobs", s.listJobs)
+	s.mux.HandleFunc("GET /v1/jobs/{id}", s.jobStatus)
+	s.mux.HandleFunc("GET /v1/doctor", s.doctor)
+	return s
+}
+
+// ServeHTTP dispatches a request to the API's handlers once its Host
+// header and bearer token are checked.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	if !allowedHost(s.opts.Hosts, r.Host) {
+		writeError(w, http.StatusMisdirectedRequest, fmt.Errorf("host %q is not the address the API listens on", r.Host))
+		return
+	}
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="backup-tui"`)
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// CheckHost wraps h to refuse requests whose Host header is not one of
+// hosts, the listen address's names, as the API does, so a web page whose
+// DNS name is rebound to a loopback address cannot reach h.
+func CheckHost(hosts []string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowedHost(hosts, r.Host) {
+			writeError(w, http.StatusMisdirectedRequest, fmt.Errorf("host %q is not the address listened on", r.Host))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether host is one of hosts, the listen address's
+// names. A host without a port is on port 80.
+func allowedHost(hosts []string, host string) bool {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), "80")
+	}
+	for _, h := range hosts {
+		if strings.EqualFold(host, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorized reports whether r presents the server's bearer token,
+// compared in constant time.
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && s.opts.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+// RecoveryPoint is a recovery point in the vault.
+type RecoveryPoint struct {
+	ARN          string    `json:"arn"`
+	ResourceType string    `json:"resourceType"`
+	ResourceID   string    `json:"resourceId"`
+	ResourceARN  string    `json:"resourceArn"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"createdAt"`
+	SizeBytes    int64     `json:"sizeBytes"`
+	MoveToColdAt time.Time `json:"moveToColdAt,omitzero"`
+	DeleteAt     time.Time `json:"deleteAt,omitzero"`
+}
+
+// Details are a recovery point's plan, encryption, and storage class.
+type Details struct {
+	CreatedBy        string            `json:"createdBy"` // Backup plan, or "" for on-demand backups
+	BackupRule       string            `json:"backupRule,omitempty"`
+	Encrypted        bool              `json:"encrypted"`
+	EncryptionKeyARN string            `json:"encryptionKeyArn,omitempty"`
+	StorageClass     string            `json:"storageClass"`
+	IAMRoleARN       string            `json:"iamRoleArn,omitempty"`
+	Tags             map[string]string `json:"tags,omitempty"`
+}
+
+// RestorePlan is what a restore of a recovery point would create.
+type RestorePlan struct {
+	ResourceType   string `json:"resourceType"`
+	ResourceID     string `json:"resourceId"`
+	ClusterID      string `json:"clusterId,omitempty"`
+	SubnetGroup    string `json:"subnetGroup,omitempty"`
+	SecurityGroups string `json:"securityGroups,omitempty"`
+	Encrypted      bool   `json:"encrypted"`
+	NewFileSystem  bool   `json:"newFileSystem,omitempty"`
+	KMSKeyID       string `json:"kmsKeyId,omitempty"`
+}
+
+// RestoreRequest is the body of POST /v1/restores.
+type RestoreRequest struct {
+	RecoveryPointARN string   `json:"recoveryPointArn"`
+	KMSKeyID         string   `json:"kmsKeyId,omitempty"`
+	SubnetGroup      string   `json:"subnetGroup,omitempty"`
+	SecurityGroupIDs []string `json:"securityGroupIds,omitempty"`
+	ClusterSuffix    string   `json:"clusterSuffix,omitempty"` // Restores an RDS backup to a new cluster named with it
+}
+
+// JobStatus is the status of a job, from GET /v1/jobs/{id}.
+type JobStatus struct {
+	JobID              string    `json:"jobId"`
+	Kind               string    `json:"kind"`
+	Status             string    `json:"status"`
+	Finished           bool      `json:"finished"`
+	PercentDone        string    `json:"percentDone,omitempty"`
+	StatusMessage      string    `json:"statusMessage,omitempty"`
+	ResourceType       string    `json:"resourceType,omitempty"`
+	ResourceID         string    `json:"resourceId,omitempty"`
+	RecoveryPointARN   string    `json:"recoveryPointArn,omitempty"`
+	CreatedResourceARN string    `json:"createdResourceArn,omitempty"`
+	CreatedAt          time.Time `json:"createdAt,omitzero"`
+	CompletedAt        time.Time `json:"completedAt,omitzero"`
+}
+
+// Error is the body of a failed request.
+type Error struct {
+	Error string `json:"error"`
+	AWS   string `json:"aws,omitempty"` // Operation and request ID of a failed AWS call
+}
+
+// Coverage is whether a stack resource is in a backup selection.
+type Coverage struct {
+	ResourceType  string   `json:"resourceType"`
+	ResourceARN   string   `json:"resourceArn"`
+	LogicalID     string   `json:"logicalId"`
+	Covered       bool     `json:"covered"`
+	Plan          string   `json:"plan,omitempty"`
+	Selection     string   `json:"selection,omitempty"`
+	TagSelections []string `json:"tagSelections,omitempty"` // "plan/selection" entries that may also cover it
+}
+
+// environment handles GET /v1/environment: the stack, vault, and region
+// the API works against.
+func (s *Server) environment(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"stack":     s.opts.Stack,
+		"vault":     s.opts.Vault,
+		"region":    s.opts.Region,
+		"simulated": s.opts.Client.Simulated(),
+	})
+}
+
+// listRecoveryPoints handles GET /v1/recovery-points[?type=RDS,EFS].
+func (s *Server) listRecoveryPoints(w http.ResponseWriter, r *http.Request) {
+	types, err := aws.ParseResourceTypes(r.URL.Query().Get("type"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid type: %w", err))
+		return
+	}
+	points, err := s.opts.Client.ListRecoveryPoints(r.Context(), s.opts.Vault, types...)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	out := make([]RecoveryPoint, len(points))
+	for i, p := range points {
+		out[i] = NewRecoveryPoint(p)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"recoveryPoints": out})
+}
+
+// describeRecoveryPoint handles GET /v1/recovery-point?arn=...: the
+// recovery point, its details, and what restoring it would create.
+func (s *Server) describeRecoveryPoint(w http.ResponseWriter, r *http.Request) {
+	rp, ok := s.findRecoveryPoint(w, r, r.URL.Query().Get("arn"))
+	if !ok {
+		return
+	}
+	details, err := s.opts.Client.DescribeRecoveryPointDetails(r.Context(), s.opts.Vault, rp.RecoveryPointARN)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	meta, err := s.opts.Client.GetRestoreMetadata(r.Context(), rp, s.opts.Stack)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"recoveryPoint": NewRecoveryPoint(rp),
+		"details": Details{
+			CreatedBy:        details.CreatedBy,
+			BackupRule:       details.BackupRule,
+			Encrypted:        details.Encrypted,
+			EncryptionKeyARN: details.EncryptionKeyARN,
+			StorageClass:     details.StorageClass,
+			IAMRoleARN:       details.IAMRoleARN,
+			Tags:             details.Tags,
+		},
+		"restore": restorePlan(meta),
+	})
+}
+
+// startRestore handles POST /v1/restores: it starts a restore of a
+// recovery point in the vault as the TUI's confirm screen would, and
+// returns the job ID to follow with GET /v1/jobs/{id}. Restores are
+// refused during the config's freeze windows.
+func (s *Server) startRestore(w http.ResponseWriter, r *http.Request) {
+	// A JSON body cannot be sent by a cross-site form, so a web page the
+	// operator visits cannot start a restore through the local API
+	if ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, errors.New("request body must be application/json"))
+		return
+	}
+	var req RestoreRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid restore request: %w", err))
+		return
+	}
+	if !s.opts.OverrideFreeze {
+		if f, until := s.opts.Config.ActiveFreeze(s.now()); f != nil {
+			writeError(w, http.StatusConflict, fmt.Errorf("restores are frozen by config window %s until %s; restart with -override-freeze to restore anyway",
+				f, until.UTC().Format(time.RFC3339)))
+			return
+		}
+	}
+	rp, ok := s.findRecoveryPoint(w, r, req.RecoveryPointARN)
+	if !ok {
+		return
+	}
+
+	opts := aws.RestoreOptions{KMSKeyID: req.KMSKeyID, SubnetGroup: req.SubnetGroup, SecurityGroupIDs: req.SecurityGroupIDs, ClusterSuffix: req.ClusterSuffix}
+	if err := aws.ValidateRestoreOptions(rp.ResourceType, opts); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var warnings []string
+	other, err := s.lockRestore(r.Context())
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("the restore lock is not kept, so other sessions will not see this restore: %v", err))
+	}
+	if other != nil {
+		warnings = append(warnings, fmt.Sprintf("a restore of %s is in progress in another session: %s (advisory lock)", s.opts.Stack, other))
+	}
+	jobID, err := s.opts.Client.StartRestoreJob(r.Context(), rp, s.opts.Stack, s.opts.Vault, opts)
+	if err != nil {
+		s.unlockRestore()
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	go s.holdLockUntilDone(jobID)
+	resp := map[string]any{"jobId": jobID, "recoveryPoint": NewRecoveryPoint(rp)}
+	// Saving is best effort, as in the TUI: the restore has started either way
+	if s.opts.HistoryPath != "" {
+		_, err := store.SaveJobs(s.opts.HistoryPath, store.TrackedJob{
+			JobID:            jobID,
+			Kind:             aws.JobKindRestore,
+			Region:           s.opts.Region,
+			Vault:            s.opts.Vault,
+			ResourceType:     rp.ResourceType,
+			ResourceID:       rp.ResourceID,
+			RecoveryPointARN: rp.RecoveryPointARN,
+			StartedAt:        s.now(),
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("restore is not saved to the job history: %v", err))
+		}
+	}
+	if len(warnings) > 0 {
+		resp["warning"] = strings.Join(warnings, "; ")
+	}
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+// stackLock returns the stack's restore lock.
+func (s *Server) stackLock() store.StackLock {
+	k := store.StackLock{Path: s.opts.LocksPath, Env: store.LockEnv(s.opts.Region, s.opts.Stack), Vault: s.opts.Vault, Stack: s.opts.Stack}
+	if s.opts.Config != nil && s.opts.Config.SharedRestoreLock {
+		k.Shared = s.opts.Client
+	}
+	return k
+}
+
+// lockRestore takes the stack's restore lock for a restore about to start,
+// or counts it among those holding it, and returns another session's lock
+// found on the stack. Like the TUI's, the lock refuses nothing.
+func (s *Server) lockRestore(ctx context.Context) (*store.RestoreLock, error) {
+	k := s.stackLock()
+	self := store.SessionLock(s.opts.Client.CallerARN())
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	if s.running++; s.running > 1 {
+		return k.Other(ctx, self)
+	}
+	held, other, err := k.Take(ctx, self)
+	s.stopLock = k.Hold(s.opts.Context, held, s.opts.OnLockError)
+	return other, err
+}
+
+// unlockRestore releases the restore lock once no restore the server
+// started holds it.
+func (s *Server) unlockRestore() {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	if s.running--; s.running == 0 {
+		s.stopLock()
+		s.stopLock = nil
+	}
+}
+
+// holdLockUntilDone polls the restore job until it finishes and then lets
+// go of the restore lock. When the server shuts down first, the lock is
+// left to expire, as the restore is still running.
+func (s *Server) holdLockUntilDone(jobID string) {
+	ticker := time.NewTicker(lockPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.opts.Context.Done():
+			return
+		case <-ticker.C:
+			st, err := s.opts.Client.GetJobStatus(s.opts.Context, aws.JobKindRestore, jobID)
+			if err == nil && st.IsTerminal {
+				s.unlockRestore()
+				return
+			}
+		}
+	}
+}
+
+// listJobs handles GET /v1/jobs[?since=RFC3339]: the vault's backup,
+// restore, and copy jobs created since then, 7 days by default.
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request) {
+	since := s.now().Add(-defaultJobsWindow)
+	if v := r.URL.Query().Get("since"); v != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, v); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid since %q: expected an RFC 3339 time, e.g. 2026-01-02T15:04:05Z", v))
+			return
+		}
+	}
+	records, err := s.opts.Client.ListJobs(r.Context(), s.opts.Vault, since)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if records == nil {
+		records = []aws.JobRecord{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"since": since, "jobs": records})
+}
+
+// jobStatus handles GET /v1/jobs/{id}[?kind=restore]: the status of a
+// restore job, or of another kind of job.
+func (s *Server) jobStatus(w http.ResponseWriter, r *http.Request) {
+	kind := r.URL.Query().Get("kind")
+	switch kind {
+	case "":
+		kind = aws.JobKindRestore
+	case aws.JobKindRestore, aws.JobKindBackup, aws.JobKindCopy, aws.JobKindExport, aws.JobKindClone:
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid kind %q: expected restore, backup, copy, export, or clone", kind))
+		return
+	}
+	st, err := s.opts.Client.GetJobStatus(r.Context(), kind, r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, NewJobStatus(kind, st))
+}
+
+// doctor handles GET /v1/doctor: whether each of the stack's RDS clusters
+// and EFS file systems is in a backup selection, as "backup-tui doctor"
+// checks it. Nothing is repaired.
+func (s *Server) doctor(w http.ResponseWriter, r *http.Request) {
+	results, err := s.opts.Client.CheckCoverage(r.Context(), s.opts.Stack)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	out := make([]Coverage, len(results))
+	uncovered := 0
+	for i, c := range results {
+		out[i] = Coverage{
+			ResourceType:  c.Resource.Type,
+			ResourceARN:   c.Resource.ARN,
+			LogicalID:     c.Resource.LogicalID,
+			Covered:       c.Covered,
+			Plan:          c.PlanName,
+			Selection:     c.SelectionName,
+			TagSelections: c.TagSelections,
+		}
+		if !c.Covered {
+			uncovered++
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"resources": out, "uncovered": uncovered})
+}
+
+// findRecoveryPoint looks up the recovery point with the given ARN in the
+// vault, writing an error response if there is none.
+func (s *Server) findRecoveryPoint(w http.ResponseWriter, r *http.Request, arn string) (aws.RecoveryPoint, bool) {
+	if arn == "" {
+		writeError(w, http.StatusBadRequest, errors.New("a recovery point ARN is required"))
+		return aws.RecoveryPoint{}, false
+	}
+	points, err := s.opts.Client.ListRecoveryPoints(r.Context(), s.opts.Vault)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return aws.RecoveryPoint{}, false
+	}
+	for _, p := range points {
+		if p.RecoveryPointARN == arn {
+			return p, true
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("recovery point %s is not in vault %s", arn, s.opts.Vault))
+	return aws.RecoveryPoint{}, false
+}
+
+// NewJobStatus converts the status of a job of the given kind for a
+// response.
+func NewJobStatus(kind string, st *aws.RestoreJobStatus) JobStatus {
+	return JobStatus{
+		JobID:              st.JobID,
+		Kind:               kind,
+		Status:             st.Status,
+		Finished:           st.IsTerminal,
+		PercentDone:        st.PercentDone,
+		StatusMessage:      st.StatusMessage,
+		ResourceType:       st.ResourceType,
+		ResourceID:         st.ResourceID,
+		RecoveryPointARN:   st.RecoveryPointARN,
+		CreatedResourceARN: st.CreatedResourceARN,
+		CreatedAt:          st.CreatedAt,
+		CompletedAt:        st.CompletedAt,
+	}
+}
+
+// NewRecoveryPoint converts an aws.RecoveryPoint for a response.
+func NewRecoveryPoint(p aws.RecoveryPoint) RecoveryPoint {
+	return RecoveryPoint{
+		ARN:          p.RecoveryPointARN,
+		ResourceType: p.ResourceType,
+		ResourceID:   p.ResourceID,
+		ResourceARN:  p.ResourceARN,
+		Status:       p.Status,
+		CreatedAt:    p.CreationDate,
+		SizeBytes:    p.BackupSizeInBytes,
+		MoveToColdAt: p.MoveToColdAt,
+		DeleteAt:     p.DeleteAt,
+	}
+}
+
+// restorePlan converts restore metadata for a response.
+func restorePlan(m *aws.RestoreMetadata) RestorePlan {
+	return RestorePlan{
+		ResourceType:   m.ResourceType,
+		ResourceID:     m.ResourceID,
+		ClusterID:      m.ClusterID,
+		SubnetGroup:    m.SubnetGroup,
+		SecurityGroups: m.SecurityGroups,
+		Encrypted:      m.Encrypted,
+		NewFileSystem:  m.NewFileSystem,
+		KMSKeyID:       m.KMSKeyID,
+	}
+}
+
+// writeJSON writes v as the JSON response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// writeError writes err as {"error": ...}.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, NewError(err))
+}
+
+// NewError converts err for a response, with the AWS operation and request
+// ID of a failed AWS call, which AWS support asks for.
+func NewError(err error) Error {
+	e := Error{Error: strings.TrimSpace(err.Error())}
+	if d := aws.DescribeError(err); !d.IsZero() {
+		e.AWS = d.String()
+	}
+	return e
+}
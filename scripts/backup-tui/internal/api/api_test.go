@@ -0,0 +1,256 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+func TestMain(m *testing.M) {
+	keyring.MockInit()
+	os.Exit(m.Run())
+}
+
+// testToken is the bearer token of the test servers.
+const testToken = "0123456789abcdef0123456789abcdef"
+
+// newTestServer returns a Server against the built-in simulated environment,
+// reached as httptest's example.com with testToken.
+func newTestServer(t *testing.T, opts Options) *Server {
+	t.Helper()
+	fx, err := aws.LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Client = aws.NewSimulatedBackupClient(fx)
+	opts.Stack, opts.Vault, opts.Region = fx.Stacks[0].Name, fx.Vaults[0], fx.Region
+	opts.Token, opts.Hosts = testToken, []string{"example.com:80"}
+	return New(opts)
+}
+
+// do sends a request to s and decodes the JSON response into out.
+func do(t *testing.T, s *Server, method, target, body string, out any) int {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: invalid JSON %q: %v", method, target, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestServer_RequiresTokenAndHost(t *testing.T) {
+	s := newTestServer(t, Options{})
+	for _, tc := range []struct {
+		name, host, auth string
+		want             int
+	}{
+		{"no token", "example.com", "", http.StatusUnauthorized},
+		{"wrong token", "example.com", "Bearer " + strings.Repeat("x", len(testToken)), http.StatusUnauthorized},
+		{"basic auth", "example.com", "Basic " + testToken, http.StatusUnauthorized},
+		{"rebound host", "attacker.example:80", "Bearer " + testToken, http.StatusMisdirectedRequest},
+		{"listen address", "EXAMPLE.com:80", "Bearer " + testToken, http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/environment", nil)
+		req.Host = tc.host
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: GET /v1/environment = %d, want %d", tc.name, rec.Code, tc.want)
+		}
+	}
+
+	// A server without a token refuses everything
+	s.opts.Token = ""
+	if code := do(t, s, http.MethodGet, "/v1/environment", "", nil); code != http.StatusUnauthorized {
+		t.Errorf("server without a token = %d, want 401", code)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	h := CheckHost([]string{"127.0.0.1:8099", "localhost:8099"}, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	for host, want := range map[string]int{
+		"127.0.0.1:8099":        http.StatusOK,
+		"LOCALHOST:8099":        http.StatusOK,
+		"attacker.example":      http.StatusMisdirectedRequest,
+		"attacker.example:8099": http.StatusMisdirectedRequest,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		req.Host = host
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("Host %s = %d, want %d", host, rec.Code, want)
+		}
+	}
+}
+
+func TestServer_ListAndDescribe(t *testing.T) {
+	s := newTestServer(t, Options{})
+
+	var list struct{ RecoveryPoints []RecoveryPoint }
+	if code := do(t, s, http.MethodGet, "/v1/recovery-points?type=RDS", "", &list); code != http.StatusOK || len(list.RecoveryPoints) == 0 {
+		t.Fatalf("GET /v1/recovery-points = %d, %+v", code, list)
+	}
+	for _, p := range list.RecoveryPoints {
+		if p.ResourceType != "RDS" {
+			t.Errorf("type=RDS should list only RDS recovery points, got %+v", p)
+		}
+	}
+
+	var described struct {
+		RecoveryPoint RecoveryPoint
+		Restore       RestorePlan
+	}
+	arn := list.RecoveryPoints[0].ARN
+	if code := do(t, s, http.MethodGet, "/v1/recovery-point?arn="+arn, "", &described); code != http.StatusOK {
+		t.Fatalf("GET /v1/recovery-point = %d", code)
+	}
+	if described.RecoveryPoint.ARN != arn || described.Restore.ClusterID == "" {
+		t.Errorf("describe should return the recovery point and the cluster a restore creates, got %+v", described)
+	}
+
+	var failure map[string]string
+	if code := do(t, s, http.MethodGet, "/v1/recovery-point?arn=missing", "", &failure); code != http.StatusNotFound || !strings.Contains(failure["error"], "not in vault") {
+		t.Errorf("unknown ARN = %d %v, want 404", code, failure)
+	}
+	if code := do(t, s, http.MethodGet, "/v1/recovery-points?type=Bogus", "", &failure); code != http.StatusBadRequest {
+		t.Errorf("unknown type = %d, want 400", code)
+	}
+}
+
+func TestServer_Restore(t *testing.T) {
+	history := filepath.Join(t.TempDir(), "history.json")
+	s := newTestServer(t, Options{HistoryPath: history})
+
+	var list struct{ RecoveryPoints []RecoveryPoint }
+	do(t, s, http.MethodGet, "/v1/recovery-points?type=RDS", "", &list)
+	body := `{"recoveryPointArn": "` + list.RecoveryPoints[0].ARN + `"}`
+
+	// A cross-site form cannot send JSON
+	req := httptest.NewRequest(http.MethodPost, "/v1/restores", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("form-encoded restore = %d, want 415", rec.Code)
+	}
+
+	var started struct{ JobID string }
+	if code := do(t, s, http.MethodPost, "/v1/restores", body, &started); code != http.StatusAccepted || started.JobID == "" {
+		t.Fatalf("POST /v1/restores = %d, %+v", code, started)
+	}
+	jobs, err := store.LoadHistory(history)
+	if err != nil || len(jobs) != 1 || jobs[0].JobID != started.JobID || jobs[0].Kind != aws.JobKindRestore {
+		t.Errorf("started restore should be saved for watch, got %+v (%v)", jobs, err)
+	}
+
+	var status JobStatus
+	if code := do(t, s, http.MethodGet, "/v1/jobs/"+started.JobID, "", &status); code != http.StatusOK || status.JobID != started.JobID || status.Kind != aws.JobKindRestore {
+		t.Errorf("GET /v1/jobs/{id} = %d, %+v", code, status)
+	}
+	if code := do(t, s, http.MethodGet, "/v1/jobs/"+started.JobID+"?kind=bogus", "", nil); code != http.StatusBadRequest {
+		t.Errorf("unknown kind = %d, want 400", code)
+	}
+
+	if code := do(t, s, http.MethodPost, "/v1/restores", `{"recoveryPointArn": "x", "extra": 1}`, nil); code != http.StatusBadRequest {
+		t.Errorf("unknown field = %d, want 400", code)
+	}
+}
+
+func TestServer_RestoreFrozen(t *testing.T) {
+	cfg := &config.Config{Freeze: []config.FreezeWindow{{Name: "always", Start: "00:00", End: "23:59", TimeZone: "UTC"}}}
+	s := newTestServer(t, Options{Config: cfg})
+	s.now = func() time.Time { return time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC) }
+
+	var failure map[string]string
+	code := do(t, s, http.MethodPost, "/v1/restores", `{"recoveryPointArn": "x"}`, &failure)
+	if code != http.StatusConflict || !strings.Contains(failure["error"], `"always"`) {
+		t.Errorf("restore during a freeze = %d %v, want 409", code, failure)
+	}
+
+	s.opts.OverrideFreeze = true
+	if code := do(t, s, http.MethodPost, "/v1/restores", `{"recoveryPointArn": "x"}`, nil); code != http.StatusNotFound {
+		t.Errorf("overridden freeze should go on to look up the recovery point, got %d", code)
+	}
+}
+
+func TestServer_RestoreKeepsOtherSessionsLock(t *testing.T) {
+	locks := filepath.Join(t.TempDir(), "locks.json")
+	s := newTestServer(t, Options{LocksPath: locks, Context: t.Context()})
+	env := store.LockEnv(s.opts.Region, s.opts.Stack)
+	now := time.Now()
+	theirs := store.RestoreLock{Holder: "arn:aws:iam::123456789012:user/bob", Host: "ops-2", PID: 200, Since: now, Updated: now}
+	if err := store.SaveLock(locks, env, theirs); err != nil {
+		t.Fatal(err)
+	}
+
+	var list struct{ RecoveryPoints []RecoveryPoint }
+	do(t, s, http.MethodGet, "/v1/recovery-points?type=RDS", "", &list)
+	var started struct{ JobID, Warning string }
+	code := do(t, s, http.MethodPost, "/v1/restores", `{"recoveryPointArn": "`+list.RecoveryPoints[0].ARN+`"}`, &started)
+	if code != http.StatusAccepted || !strings.Contains(started.Warning, "ops-2") {
+		t.Fatalf("restore of a locked stack = %d %+v, want it started with a warning", code, started)
+	}
+	held, err := store.LoadLocks(locks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := held[env]; !l.SameSession(theirs) {
+		t.Errorf("the other session's lock should be kept, got %+v", l)
+	}
+}
+
+func TestServer_DoctorAndJobs(t *testing.T) {
+	s := newTestServer(t, Options{})
+
+	var doctor struct {
+		Resources []Coverage
+		Uncovered int
+	}
+	if code := do(t, s, http.MethodGet, "/v1/doctor", "", &doctor); code != http.StatusOK || len(doctor.Resources) == 0 {
+		t.Fatalf("GET /v1/doctor = %d, %+v", code, doctor)
+	}
+	uncovered := 0
+	for _, r := range doctor.Resources {
+		if !r.Covered {
+			uncovered++
+		}
+	}
+	if doctor.Uncovered != uncovered {
+		t.Errorf("uncovered = %d, want %d", doctor.Uncovered, uncovered)
+	}
+
+	var jobs struct{ Jobs []aws.JobRecord }
+	if code := do(t, s, http.MethodGet, "/v1/jobs", "", &jobs); code != http.StatusOK || len(jobs.Jobs) == 0 {
+		t.Errorf("GET /v1/jobs = %d, %+v", code, jobs)
+	}
+	if code := do(t, s, http.MethodGet, "/v1/jobs?since=yesterday", "", nil); code != http.StatusBadRequest {
+		t.Errorf("invalid since = %d, want 400", code)
+	}
+	if code := do(t, s, http.MethodDelete, "/v1/doctor", "", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE /v1/doctor = %d, want 405", code)
+	}
+}
@@ -0,0 +1,252 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the vault activity view: the recovery points created
+// in, copied into, and deleted from the vault over the last day (or three,
+// or seven), newest first, with who did it, so "what changed since
+// yesterday" is one key press.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// activityWindows are the windows "w" cycles through; the first is the
+// default.
+var activityWindows = []time.Duration{24 * time.Hour, 3 * 24 * time.Hour, 7 * 24 * time.Hour}
+
+// activityView is the state of the vault activity view.
+type activityView struct {
+	window   int // Index into activityWindows
+	activity *aws.VaultActivity
+	err      error
+	loading  bool
+	cursor   int
+}
+
+// activityMsg is sent when the vault activity has been loaded.
+type activityMsg struct {
+	vault    string
+	activity *aws.VaultActivity
+	err      error
+}
+
+// openActivity opens the vault activity view and loads it.
+func (m *Model) openActivity() tea.Cmd {
+	m.state = stateActivity
+	m.activity.cursor = 0
+	return m.loadActivity()
+}
+
+// loadActivity returns a command that loads the vault's activity over the
+// selected window.
+func (m *Model) loadActivity() tea.Cmd {
+	if m.activity.loading {
+		return nil
+	}
+	m.activity.loading = true
+	client, vaultName := m.backupClient, m.vaultName
+	since := time.Now().Add(-activityWindows[m.activity.window])
+	return func() tea.Msg {
+		a, err := client.VaultActivity(m.ctx, vaultName, since)
+		return activityMsg{vault: vaultName, activity: a, err: err}
+	}
+}
+
+// handleActivity stores loaded vault activity. A CloudTrail failure leaves
+// the rest of the activity usable and is logged with its AWS details.
+func (m *Model) handleActivity(msg activityMsg) {
+	m.activity.loading = false
+	if msg.vault != m.vaultName {
+		return // The vault was switched while loading
+	}
+	m.activity.activity, m.activity.err = msg.activity, msg.err
+	if msg.err != nil {
+		m.logError("Vault activity not loaded", msg.err)
+		return
+	}
+	if msg.activity.DeletionsErr != nil {
+		m.logError("Deletions not read from CloudTrail", msg.activity.DeletionsErr)
+	}
+	if m.activity.cursor >= len(msg.activity.Events) {
+		m.activity.cursor = max(0, len(msg.activity.Events)-1)
+	}
+}
+
+// activityEvents returns the loaded events, newest first.
+func (m *Model) activityEvents() []aws.VaultEvent {
+	if m.activity.activity == nil {
+		return nil
+	}
+	return m.activity.activity.Events
+}
+
+// updateActivity handles key presses in the vault activity view. Enter on
+// a recovery point still in the vault opens its detail view.
+func (m *Model) updateActivity(msg tea.KeyPressMsg) tea.Cmd {
+	events := m.activityEvents()
+	switch msg.String() {
+	case "up", "k":
+		if m.activity.cursor > 0 {
+			m.activity.cursor--
+		}
+	case "down", "j":
+		if m.activity.cursor < len(events)-1 {
+			m.activity.cursor++
+		}
+	case "w":
+		m.activity.window = (m.activity.window + 1) % len(activityWindows)
+		m.activity.cursor = 0
+		return m.loadActivity()
+	case "r":
+		return m.loadActivity()
+	case "enter":
+		if m.activity.cursor >= len(events) {
+			return nil
+		}
+		idx := m.backupIndex(events[m.activity.cursor].RecoveryPointARN)
+		if idx < 0 {
+			m.notify(SeverityWarn, "That recovery point is not in the list (deleted, or hidden by the filter)")
+			return nil
+		}
+		m.selectedIdx = idx
+		m.listModel.SetCursor(idx)
+		return m.openDetail()
+	}
+	return nil
+}
+
+// backupIndex returns the index in m.backups of the recovery point with the
+// given ARN, or -1.
+func (m *Model) backupIndex(arn string) int {
+	for i, rp := range m.backups {
+		if rp.RecoveryPointARN == arn {
+			return i
+		}
+	}
+	return -1
+}
+
+// activitySummary counts the loaded events by kind, e.g. "2 created · 1
+// copied in · 1 deleted (1 refused)".
+func activitySummary(events []aws.VaultEvent) string {
+	var created, copied, deleted, refused int
+	for _, e := range events {
+		switch {
+		case e.Kind == aws.ActivityCreated:
+			created++
+		case e.Kind == aws.ActivityCopiedIn:
+			copied++
+		case e.ErrorCode != "":
+			refused++
+		default:
+			deleted++
+		}
+	}
+	s := fmt.Sprintf("%d created · %d copied in · %d deleted", created, copied, deleted)
+	if refused > 0 {
+		s += fmt.Sprintf(" (%d refused)", refused)
+	}
+	return s
+}
+
+// activityLine describes an event after its time and kind.
+func activityLine(e aws.VaultEvent) string {
+	switch e.Kind {
+	case aws.ActivityCreated:
+		by := "on demand"
+		if e.Actor != "" {
+			by = "by plan " + e.Actor
+		}
+		return fmt.Sprintf("%s %s  %s  %s", e.ResourceType, e.ResourceID, formatBytes(e.SizeBytes), by)
+	case aws.ActivityCopiedIn:
+		return fmt.Sprintf("%s %s  %s  from %s", e.ResourceType, e.ResourceID, formatBytes(e.SizeBytes), arnName(e.Actor))
+	}
+	line := fmt.Sprintf("%s  by %s", arnName(e.RecoveryPointARN), arnName(e.Actor))
+	if e.ErrorCode != "" {
+		line += "  refused: " + e.ErrorCode
+	}
+	return line
+}
+
+// renderActivity renders the vault activity view, grouped by day.
+func (m *Model) renderActivity() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	dayStyle := lipgloss.NewStyle().Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("248")})
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	failStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+	deleteStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+
+	window := activityWindows[m.activity.window]
+	title := "Vault activity — last 24 hours"
+	if window > 24*time.Hour {
+		title = fmt.Sprintf("Vault activity — last %d days", int(window.Hours()/24))
+	}
+	lines := []string{titleStyle.Render(title), dimStyle.Render("+ created  ⇄ copied in  ✗ deleted"), ""}
+
+	if m.activity.loading {
+		lines = append(lines, dimStyle.Render("Loading recovery points and CloudTrail events..."))
+	}
+	if m.activity.err != nil {
+		lines = append(lines, failStyle.Render("Unavailable: "+m.activity.err.Error()))
+	}
+	a := m.activity.activity
+	if a == nil {
+		return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+	}
+	if a.DeletionsErr != nil {
+		lines = append(lines, failStyle.Render("Deletions unknown: "+a.DeletionsErr.Error()))
+	}
+	lines = append(lines, infoStyle.Render(activitySummary(a.Events)), "")
+	if len(a.Events) == 0 && !m.activity.loading {
+		lines = append(lines, dimStyle.Render("Nothing changed in this window."))
+	}
+
+	icons := map[string]string{aws.ActivityCreated: "+", aws.ActivityCopiedIn: "⇄", aws.ActivityDeleted: "✗"}
+	day := ""
+	for i, e := range a.Events {
+		local := e.At.Local()
+		if d := local.Format("Mon 2006-01-02"); d != day {
+			if day != "" {
+				lines = append(lines, "")
+			}
+			day = d
+			lines = append(lines, dayStyle.Render(day))
+		}
+
+		line := fmt.Sprintf("%s  %s %-9s  %s", local.Format("15:04"), icons[e.Kind], e.Kind, activityLine(e))
+		style := infoStyle
+		switch {
+		case e.ErrorCode != "":
+			style = failStyle
+		case e.Kind == aws.ActivityDeleted:
+			style = deleteStyle
+		}
+		if i == m.activity.cursor {
+			lines = append(lines, focusStyle.Render("▸ "+line))
+		} else {
+			lines = append(lines, style.Render("  "+line))
+		}
+	}
+	lines = append(lines, "", dimStyle.Render("Deletions come from CloudTrail, which can lag by up to 15 minutes."))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,125 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the API calls panel and budget: "ctrl+d" shows the
+// session's AWS API calls per service with their retries, throttles, and
+// errors, and once an -api-budget is used up, background refresh (details
+// of the backups on screen and the restore prefetch) pauses so the TUI
+// stops adding quota pressure. Job polling and the operator's own actions
+// carry on.
+package app
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// apiCallsView is the session's API call budget and the API calls panel.
+type apiCallsView struct {
+	usage    func() aws.APIUsage // Nil until the client is created
+	budget   int                 // Calls after which background refresh pauses; 0 for no budget
+	initial  int                 // Budget given at startup, by which "+" raises it
+	paused   bool                // Whether the budget has been used up
+	returnTo state               // View to return to on esc/q
+}
+
+// backgroundPaused reports whether the API call budget has been used up,
+// warning and dropping the queued background lookups the first time it is.
+func (m *Model) backgroundPaused() bool {
+	a := &m.apiCalls
+	if a.budget <= 0 || a.usage == nil {
+		return false
+	}
+	if a.paused {
+		return true
+	}
+	if calls := a.usage().Total().Calls; calls < a.budget {
+		return false
+	}
+	a.paused = true
+	if m.enrich.enricher != nil {
+		m.enrich.enricher.Prioritize(m.vaultName, nil)
+		m.enrich.queued = ""
+	}
+	m.notify(SeverityWarn, fmt.Sprintf("API call budget of %d used up; background refresh paused (ctrl+d for details)", a.budget))
+	return true
+}
+
+// openAPICalls opens the API calls panel.
+func (m *Model) openAPICalls() {
+	m.apiCalls.returnTo = m.state
+	m.state = stateAPICalls
+}
+
+// updateAPICalls handles key presses in the API calls panel.
+func (m *Model) updateAPICalls(msg tea.KeyPressMsg) {
+	a := &m.apiCalls
+	if msg.String() != "+" || a.initial <= 0 {
+		return
+	}
+	a.budget += a.initial
+	if a.paused && a.usage().Total().Calls < a.budget {
+		a.paused = false
+		m.enrichVisible()
+		m.inform(fmt.Sprintf("API call budget raised to %d; background refresh resumed", a.budget))
+		return
+	}
+	m.inform(fmt.Sprintf("API call budget raised to %d", a.budget))
+}
+
+// renderAPICalls renders the API calls panel.
+func (m *Model) renderAPICalls() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+
+	lines := []string{titleStyle.Render("AWS API Calls"), ""}
+	if m.backupClient != nil && m.backupClient.Simulated() {
+		lines = append(lines, dimStyle.Render("Simulation mode makes no AWS API calls."))
+		return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+	}
+
+	var usage aws.APIUsage
+	if m.apiCalls.usage != nil {
+		usage = m.apiCalls.usage()
+	}
+	row := func(s aws.ServiceCalls) string {
+		return fmt.Sprintf("%-16s %7d %8d %10d %7d", s.Service, s.Calls, s.Retries, s.Throttles, s.Errors)
+	}
+	lines = append(lines, titleStyle.Render(fmt.Sprintf("%-16s %7s %8s %10s %7s", "Service", "Calls", "Retries", "Throttles", "Errors")))
+	for _, s := range usage.Services {
+		style := infoStyle
+		if s.Throttles > 0 {
+			style = warnStyle
+		}
+		lines = append(lines, style.Render(row(s)))
+	}
+	lines = append(lines, titleStyle.Render(row(usage.Total())), "")
+
+	if !usage.Since.IsZero() {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("Counted since %s (%s).", usage.Since.Local().Format("15:04:05"), relativeTime(usage.Since))))
+	}
+	switch a := m.apiCalls; {
+	case a.budget <= 0:
+		lines = append(lines, dimStyle.Render("No API call budget; start with -api-budget to pause background refresh after that many calls."))
+	case a.paused:
+		lines = append(lines, warnStyle.Render(fmt.Sprintf("Budget of %d calls used up: background refresh is paused. Press + to allow %d more.", a.budget, a.initial)))
+	default:
+		lines = append(lines, infoStyle.Render(fmt.Sprintf("Budget: %d of %d calls used.", usage.Total().Calls, a.budget)))
+	}
+	lines = append(lines, dimStyle.Render("Throttles pause the service's requests; see AWS API Rate Limiting in the README."))
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,190 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements cleaning up after restores and clones started in the
+// TUI: when one fails, or a later step of its restore chain fails or is
+// cancelled, the cluster or file system it created is left behind and
+// billed. "D" in the jobs view offers to delete it; the stack's own cluster
+// and file systems are never deleted.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// cleanupDoneMsg is sent when a job's leftover resource has been deleted,
+// or deleting it failed.
+type cleanupDoneMsg struct {
+	seq      int
+	resource aws.CreatedResource
+	deleted  []string // Deleted before any failure
+	err      error
+}
+
+// leftover returns the resource job created that was left behind because
+// job failed or its restore chain was abandoned, and false when there is
+// none. Imported jobs were not started here, and a restore whose created
+// resource is the backed-up one replaced it rather than creating a new one.
+func (m *Model) leftover(job *restoreJob) (aws.CreatedResource, bool) {
+	if job.cleaned || job.imported || (job.state != jobFailed && !m.chainAbandoned(job)) {
+		return aws.CreatedResource{}, false
+	}
+	switch job.kind {
+	case aws.JobKindClone:
+		if job.jobID != "" {
+			return aws.CreatedResource{ResourceType: "RDS", ID: job.jobID}, true
+		}
+	case aws.JobKindRestore:
+		if job.status == nil || job.status.CreatedResourceARN == "" || job.status.CreatedResourceARN == job.backup.ResourceARN {
+			break
+		}
+		r, ok := aws.CreatedResourceFromARN(job.status.CreatedResourceARN)
+		if ok && r.ID != job.backup.ResourceID {
+			return r, true
+		}
+	}
+	return aws.CreatedResource{}, false
+}
+
+// chainAbandoned reports whether a completed step's chain was abandoned: a
+// step chained after it failed, was skipped, or was cancelled.
+func (m *Model) chainAbandoned(job *restoreJob) bool {
+	if job.state != jobCompleted {
+		return false
+	}
+	for _, next := range m.jobs {
+		if next.state != jobFailed && next.state != jobSkipped && next.state != jobCancelled {
+			continue
+		}
+		for step := next.after; step != nil; step = step.after {
+			if step == job {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// openCleanupConfirm asks to confirm deleting what the selected job left
+// behind.
+func (m *Model) openCleanupConfirm() {
+	job := m.jobBySeq(m.jobsCursor + 1)
+	if job == nil {
+		return
+	}
+	if job.cleaning {
+		m.notify(SeverityWarn, fmt.Sprintf("%s #%d is already being cleaned up", job.noun(), job.seq))
+		return
+	}
+	if _, ok := m.leftover(job); !ok {
+		m.notify(SeverityWarn, fmt.Sprintf("%s #%d left nothing behind to clean up", job.noun(), job.seq))
+		return
+	}
+	m.cleanupSeq = job.seq
+	m.state = stateCleanup
+}
+
+// updateCleanupConfirm handles key presses on the cleanup confirmation.
+func (m *Model) updateCleanupConfirm(msg tea.KeyPressMsg) tea.Cmd {
+	switch msg.String() {
+	case "y", "Y":
+		m.state = stateJobs
+		job := m.jobBySeq(m.cleanupSeq)
+		if job == nil {
+			return nil
+		}
+		r, ok := m.leftover(job)
+		if !ok {
+			return nil
+		}
+		job.cleaning = true
+		m.inform(fmt.Sprintf("Deleting %s...", r))
+		seq, stackName := job.seq, m.stackName
+		client := m.backupClient
+		return func() tea.Msg {
+			deleted, err := client.DeleteCreatedResource(m.ctx, stackName, r)
+			return cleanupDoneMsg{seq: seq, resource: r, deleted: deleted, err: err}
+		}
+	case "n", "N", "backspace":
+		m.state = stateJobs
+	}
+	return nil
+}
+
+// handleCleanupDone records a job's leftover resource as deleted, or
+// reports why it was not.
+func (m *Model) handleCleanupDone(msg cleanupDoneMsg) {
+	job := m.jobBySeq(msg.seq)
+	if job == nil {
+		return
+	}
+	job.cleaning = false
+	if msg.err != nil {
+		text := fmt.Sprintf("Cleanup of #%d failed: %v", job.seq, msg.err)
+		if len(msg.deleted) > 0 {
+			text += fmt.Sprintf(" (deleted %s)", strings.Join(msg.deleted, ", "))
+		}
+		m.reportError(text, msg.err)
+		return
+	}
+	job.cleaned = true
+	job.note = "cleaned up: deleted " + msg.resource.String()
+	m.inform(fmt.Sprintf("Deleting %s; AWS finishes the deletion in the background", strings.Join(msg.deleted, ", ")))
+}
+
+// renderCleanupConfirm renders the cleanup confirmation.
+func (m *Model) renderCleanupConfirm() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorFail).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.ColorFail).
+		Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+
+	job := m.jobBySeq(m.cleanupSeq)
+	if job == nil {
+		return header
+	}
+	r, _ := m.leftover(job)
+	why := job.note
+	if job.state == jobCompleted {
+		why = "a later step of its restore chain did not complete"
+	}
+
+	lines := []string{
+		titleStyle.Render("Delete Leftover Resources"),
+		"",
+		infoStyle.Render(fmt.Sprintf("Job:       %s #%d, %s", job.noun(), job.seq, job.state)),
+		infoStyle.Render("Why:       " + why),
+		infoStyle.Render("Resource:  " + r.String()),
+		"",
+	}
+	if r.ResourceType == "EFS" {
+		lines = append(lines,
+			dimStyle.Render("The file system and all data restored to it are deleted."),
+			dimStyle.Render("It is not deleted while it has mount targets."))
+	} else {
+		lines = append(lines,
+			dimStyle.Render("The cluster's instances and then the cluster are deleted, without a final snapshot."),
+			dimStyle.Render("It is not deleted while deletion protection is on."))
+	}
+	lines = append(lines,
+		dimStyle.Render("The stack's own cluster and file systems are never deleted."),
+		"",
+		infoStyle.Render("Delete it? y / n"),
+	)
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,132 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the Aurora fast clone offered on the RDS restore
+// confirmation: "c" clones the stack's current cluster with copy-on-write
+// storage instead of restoring the backup, which takes minutes rather than
+// hours for a quick investigation. The clone is tracked in the jobs view
+// until it can be connected to.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// cloneStartedMsg is sent when the clone cluster and its instance have
+// been requested for a job.
+type cloneStartedMsg struct {
+	seq     int
+	cloneID string
+	err     error
+}
+
+// openCloneConfirm asks to confirm cloning the current cluster. Only RDS
+// backups offer a clone.
+func (m *Model) openCloneConfirm() {
+	if m.selectedIdx >= len(m.backups) || m.backups[m.selectedIdx].ResourceType != "RDS" {
+		m.notify(SeverityWarn, "Fast clones are only available for the Aurora database")
+		return
+	}
+	m.state = stateClone
+}
+
+// updateCloneConfirm handles key presses on the clone confirmation.
+func (m *Model) updateCloneConfirm(msg tea.KeyPressMsg) tea.Cmd {
+	switch msg.String() {
+	case "y", "Y":
+		source := m.cloneSource()
+		job := m.addJob(aws.RecoveryPoint{ResourceType: "RDS", ResourceID: source}, nil)
+		job.kind = aws.JobKindClone
+		m.jobsCursor = len(m.jobs) - 1
+		m.state = stateJobs
+		m.inform(fmt.Sprintf("Cloning %s...", source))
+		return m.startClone(job)
+	case "n", "N", "backspace":
+		m.state = stateConfirm
+	}
+	return nil
+}
+
+// cloneSource names the cluster being cloned, once the restore parameters
+// have been loaded.
+func (m *Model) cloneSource() string {
+	if m.restoreMetadata != nil && m.restoreMetadata.ClusterID != "" {
+		return m.restoreMetadata.ClusterID
+	}
+	return "current cluster"
+}
+
+// startClone returns a command that creates the clone for job.
+func (m *Model) startClone(job *restoreJob) tea.Cmd {
+	job.started = time.Now()
+	seq, stackName := job.seq, m.stackName
+	client := m.backupClient
+	return func() tea.Msg {
+		cloneID, err := client.CloneCluster(m.ctx, stackName)
+		return cloneStartedMsg{seq: seq, cloneID: cloneID, err: err}
+	}
+}
+
+// handleCloneStarted records a requested clone, saves it for resuming, and
+// polls it until it is available.
+func (m *Model) handleCloneStarted(msg cloneStartedMsg) tea.Cmd {
+	job := m.jobBySeq(msg.seq)
+	if job == nil {
+		return nil
+	}
+	if msg.cloneID != "" {
+		job.jobID = msg.cloneID
+		job.backup.ResourceID = msg.cloneID
+	}
+	if msg.err != nil {
+		// A cluster created before the failure is kept for cleaning up
+		job.state = jobFailed
+		job.note = msg.err.Error()
+		m.reportError(fmt.Sprintf("Clone #%d failed: %v", job.seq, msg.err), msg.err)
+		return m.notifyFinished(job)
+	}
+	job.state = jobActive
+	m.recordJob(job)
+	m.inform(fmt.Sprintf("Clone #%d is being created: %s", job.seq, msg.cloneID))
+	return tea.Batch(m.pollRestoreStatus(job.jobID), m.notifyStep(job, config.EventStarted))
+}
+
+// renderCloneConfirm renders the clone confirmation.
+func (m *Model) renderCloneConfirm() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorWarn).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.ColorWarn).
+		Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+
+	lines := []string{
+		titleStyle.Render("Fast Clone Instead of Restore"),
+		"",
+		infoStyle.Render("Source:   " + m.cloneSource()),
+		infoStyle.Render("Data:     the cluster's current data (latest restorable time), not the selected backup"),
+		infoStyle.Render("Storage:  copy-on-write, shared with the source until pages change"),
+		infoStyle.Render("Network:  same subnet group and security groups as the source"),
+		"",
+		dimStyle.Render("A clone is usually ready in minutes. Production is not changed."),
+		dimStyle.Render("The clone's instance is billed until you delete it; clones are tagged " + aws.CloneTagKey + "."),
+		dimStyle.Render("To investigate the data as of the backup, restore the backup instead."),
+		"",
+		infoStyle.Render("Create the clone? y / n"),
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,236 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the columns of the backup list: the config file's
+// listColumns chooses them and their order, and "C" in the list opens a
+// picker that chooses them for the environment instead, saved with the
+// view state (views.go), since different teams care about different
+// metadata.
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// listColumn is a column the backup list can show.
+type listColumn struct {
+	column ui.Column
+	about  string // Shown in the column picker
+	// cell returns the backup's cell; age is its age against the RPO
+	cell func(m *Model, rp aws.RecoveryPoint, age ui.Level, now time.Time) string
+}
+
+// listColumns are the columns of the backup list by their config name, one
+// for each of config.ListColumnNames.
+var listColumns = map[string]listColumn{
+	"type": {ui.Column{Title: "Type", Width: 10}, "resource type",
+		func(_ *Model, rp aws.RecoveryPoint, _ ui.Level, _ time.Time) string { return rp.ResourceType }},
+	"resource": {ui.Column{Title: "Resource ID", Width: 12, Flex: true}, "backed-up resource, as wide as the others leave",
+		func(_ *Model, rp aws.RecoveryPoint, _ ui.Level, _ time.Time) string { return rp.ResourceID }},
+	"created": {ui.Column{Title: "Created", Width: 30, Align: lipgloss.Right}, "creation time, colored by age against the RPO",
+		func(_ *Model, rp aws.RecoveryPoint, age ui.Level, _ time.Time) string {
+			return lipgloss.NewStyle().Foreground(age.Color()).Render(fmt.Sprintf("%s (%s)", rp.CreationDate.Format("2006-01-02 15:04:05"), relativeTime(rp.CreationDate)))
+		}},
+	"size": {ui.Column{Title: "Size", Width: 9, Align: lipgloss.Right}, "backup size",
+		func(_ *Model, rp aws.RecoveryPoint, _ ui.Level, _ time.Time) string {
+			return formatBytes(rp.BackupSizeInBytes)
+		}},
+	"created-by": {ui.Column{Title: "Created By", Width: 20, HideEmpty: true}, "backup plan, or on-demand; once details load",
+		func(m *Model, rp aws.RecoveryPoint, _ ui.Level, _ time.Time) string {
+			return m.backupCreator(rp.RecoveryPointARN)
+		}},
+	"status": {ui.Column{Title: "Status", Width: 9}, "recovery point status, e.g. PARTIAL",
+		func(_ *Model, rp aws.RecoveryPoint, _ ui.Level, _ time.Time) string { return rp.Status }},
+	"tier": {ui.Column{Title: "Tier", Width: 4}, "warm or cold storage",
+		func(_ *Model, rp aws.RecoveryPoint, _ ui.Level, now time.Time) string {
+			if rp.InColdStorage(now) {
+				return "cold"
+			}
+			return "warm"
+		}},
+	"vault": {ui.Column{Title: "Vault", Width: 24}, "vault the backup is in",
+		func(m *Model, _ aws.RecoveryPoint, _ ui.Level, _ time.Time) string { return m.vaultName }},
+	"rule": {ui.Column{Title: "Plan Rule", Width: 16, HideEmpty: true}, "backup plan rule that took it; once details load",
+		func(m *Model, rp aws.RecoveryPoint, _ ui.Level, _ time.Time) string {
+			if d := m.enrich.details[rp.RecoveryPointARN]; d != nil {
+				return d.BackupRule
+			}
+			return ""
+		}},
+	"tags": {ui.Column{Title: "Tags", Width: 24, HideEmpty: true}, "recovery point tags; once details load",
+		func(m *Model, rp aws.RecoveryPoint, _ ui.Level, _ time.Time) string {
+			if d := m.enrich.details[rp.RecoveryPointARN]; d != nil && len(d.Tags) > 0 {
+				return aws.TagEdit{Add: d.Tags}.String()
+			}
+			return ""
+		}},
+	"arn": {ui.Column{Title: "ARN", Width: 20}, "end of the recovery point ARN",
+		func(_ *Model, rp aws.RecoveryPoint, _ ui.Level, _ time.Time) string {
+			return arnSuffix(rp.RecoveryPointARN)
+		}},
+}
+
+// leadColumns lead every row of the backup list, before the chosen columns.
+var leadColumns = []ui.Column{
+	{Width: 1, HideEmpty: true}, // ✓ on marked backups, while any are marked
+	{Width: 1},                  // ● colored by age against the RPO
+}
+
+// backupColumns returns the columns of the backup list showing the named
+// columns, in the order formatBackupsForList fills them.
+func backupColumns(names []string) []ui.Column {
+	columns := slices.Clone(leadColumns)
+	for _, name := range names {
+		columns = append(columns, listColumns[name].column)
+	}
+	return columns
+}
+
+// arnSuffix returns the part of an ARN after its last colon, e.g. a
+// recovery point's ID.
+func arnSuffix(arn string) string {
+	return arn[strings.LastIndex(arn, ":")+1:]
+}
+
+// listColumnNames returns the columns the backup list shows: those picked
+// for the environment, or the config file's.
+func (m *Model) listColumnNames() []string {
+	if m.columns != nil {
+		return m.columns
+	}
+	return m.config.ListColumns()
+}
+
+// applyColumns lays the backup list out in its current columns.
+func (m *Model) applyColumns() {
+	m.listModel.SetColumns(backupColumns(m.listColumnNames())...)
+	m.listModel.SetItems(m.formatBackupsForList())
+}
+
+// columnPicker is the state of the column picker.
+type columnPicker struct {
+	order  []string        // Every column, the shown ones first in their order
+	shown  map[string]bool // Columns the list will show
+	cursor int
+}
+
+// openColumnPicker opens the column picker with the list's columns.
+func (m *Model) openColumnPicker() {
+	current := m.listColumnNames()
+	p := columnPicker{order: slices.Clone(current), shown: make(map[string]bool, len(current))}
+	for _, name := range current {
+		p.shown[name] = true
+	}
+	for _, name := range config.ListColumnNames {
+		if !p.shown[name] {
+			p.order = append(p.order, name)
+		}
+	}
+	m.columnPicker = p
+	m.state = stateColumns
+}
+
+// updateColumnPicker handles key presses in the column picker: space shows
+// or hides a column, shift with the arrows (or K and J) moves it, enter
+// applies the choice, and r goes back to the config file's columns.
+func (m *Model) updateColumnPicker(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	p := &m.columnPicker
+	switch msg.String() {
+	case "esc", "q", "backspace":
+		m.state = stateList
+	case "up", "k":
+		p.cursor = max(p.cursor-1, 0)
+	case "down", "j":
+		p.cursor = min(p.cursor+1, len(p.order)-1)
+	case "shift+up", "K":
+		if p.cursor > 0 {
+			p.order[p.cursor-1], p.order[p.cursor] = p.order[p.cursor], p.order[p.cursor-1]
+			p.cursor--
+		}
+	case "shift+down", "J":
+		if p.cursor < len(p.order)-1 {
+			p.order[p.cursor+1], p.order[p.cursor] = p.order[p.cursor], p.order[p.cursor+1]
+			p.cursor++
+		}
+	case "space":
+		name := p.order[p.cursor]
+		p.shown[name] = !p.shown[name]
+	case "r":
+		m.setColumns(nil)
+		m.state = stateList
+	case "enter":
+		var names []string
+		for _, name := range p.order {
+			if p.shown[name] {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			m.notify(SeverityWarn, "Show at least one column (space)")
+			return m, nil
+		}
+		m.setColumns(names)
+		m.state = stateList
+	}
+	return m, nil
+}
+
+// setColumns sets the columns picked for the environment; nil, or the
+// config file's columns, follows the config file.
+func (m *Model) setColumns(names []string) {
+	if slices.Equal(names, m.config.ListColumns()) {
+		names = nil
+	}
+	m.columns = names
+	m.applyColumns()
+	if names == nil {
+		m.inform("List shows the configured columns: " + strings.Join(m.listColumnNames(), ", "))
+		return
+	}
+	m.inform("List shows " + strings.Join(names, ", "))
+}
+
+// renderColumnPicker renders the column picker.
+func (m *Model) renderColumnPicker() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+
+	p := m.columnPicker
+	lines := []string{titleStyle.Render("List Columns"), ""}
+	for i, name := range p.order {
+		box := "[ ]"
+		if p.shown[name] {
+			box = "[x]"
+		}
+		entry := fmt.Sprintf("%s %-10s  %s", box, name, dimStyle.Render(listColumns[name].about))
+		if i == p.cursor {
+			lines = append(lines, focusStyle.Render("▸ "+entry))
+		} else {
+			lines = append(lines, infoStyle.Render("  "+entry))
+		}
+	}
+	lines = append(lines, "",
+		dimStyle.Render("Saved for this environment; r goes back to the config file's listColumns ("+strings.Join(m.config.ListColumns(), ", ")+")."))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,99 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the configuration diff on the RDS restore
+// confirmation: the live cluster side by side with the cluster the restore
+// creates, differences in red, so a backup taken before an engine upgrade
+// or a key rotation is noticed before it is submitted.
+package app
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// configDiffView is the configuration diff on the confirm screen.
+type configDiffView struct {
+	open     bool
+	arn      string             // Recovery point the recorded configuration belongs to
+	recorded *aws.ClusterConfig // Nil until looked up
+	err      error
+	loading  bool
+}
+
+// recordedConfigMsg is sent when the configuration recorded with a backup
+// has been looked up.
+type recordedConfigMsg struct {
+	arn    string
+	config *aws.ClusterConfig
+	err    error
+}
+
+// toggleConfigDiff shows or hides the configuration diff of the selected
+// RDS backup, looking up its recorded configuration the first time.
+func (m *Model) toggleConfigDiff() tea.Cmd {
+	if m.selectedIdx >= len(m.backups) || m.backups[m.selectedIdx].ResourceType != "RDS" {
+		m.notify(SeverityWarn, "The configuration diff applies to RDS restores")
+		return nil
+	}
+	m.configDiff.open = !m.configDiff.open
+	rp := m.backups[m.selectedIdx]
+	if !m.configDiff.open || m.configDiff.arn == rp.RecoveryPointARN {
+		return nil
+	}
+	m.configDiff = configDiffView{open: true, arn: rp.RecoveryPointARN, loading: true}
+	client, vault := m.backupClient, m.vaultName
+	return func() tea.Msg {
+		cfg, err := client.RecordedClusterConfig(m.ctx, vault, rp.RecoveryPointARN)
+		return recordedConfigMsg{arn: rp.RecoveryPointARN, config: cfg, err: err}
+	}
+}
+
+// handleRecordedConfig stores a looked-up configuration, unless another
+// backup has been selected since.
+func (m *Model) handleRecordedConfig(msg recordedConfigMsg) {
+	if msg.arn != m.configDiff.arn {
+		return
+	}
+	m.configDiff.loading = false
+	m.configDiff.recorded, m.configDiff.err = msg.config, msg.err
+	if msg.err != nil {
+		m.logError("Configuration recorded with the backup not read", msg.err)
+	}
+}
+
+// configDiffLines renders the configuration diff of restoring rp, or nil
+// when it is hidden.
+func (m *Model) configDiffLines(rp aws.RecoveryPoint, labelStyle, style lipgloss.Style) []string {
+	if !m.configDiff.open || rp.ResourceType != "RDS" {
+		return nil
+	}
+	lines := []string{"", labelStyle.Render("Live Cluster vs Restore:")}
+	switch {
+	case m.clusterErr != nil:
+		return append(lines, style.Render("  Unavailable: the live cluster could not be read"))
+	case m.configDiff.err != nil:
+		return append(lines, style.Render("  Unavailable: the backup's configuration could not be read"))
+	case m.cluster == nil || m.restoreMetadata == nil || m.configDiff.recorded == nil:
+		return append(lines, style.Render("  Loading..."))
+	}
+
+	differStyle := lipgloss.NewStyle().Foreground(ui.ColorFail).Bold(true)
+	rows := aws.RestoreConfigDiff(m.cluster, m.restoreMetadata, m.configDiff.recorded)
+	width := len("Live")
+	for _, r := range rows {
+		width = max(width, len(r.Live))
+	}
+	lines = append(lines, labelStyle.Render(fmt.Sprintf("  %-16s %-*s  %s", "", width, "Live", "Restore")))
+	for _, r := range rows {
+		row := fmt.Sprintf("  %-16s %-*s  %s", r.Setting, width, r.Live, r.Restore)
+		if r.Differs {
+			lines = append(lines, differStyle.Render(row))
+		} else {
+			lines = append(lines, style.Render(row))
+		}
+	}
+	return lines
+}
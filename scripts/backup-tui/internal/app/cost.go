@@ -0,0 +1,53 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the estimated cost shown on the RDS restore
+// confirmation, from the instances and storage of the live cluster the
+// restore mirrors, so staging refreshes do not surprise the budget.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// costLines renders the estimated cost of restoring rp, or nil for
+// backups other than RDS.
+func (m *Model) costLines(rp aws.RecoveryPoint, labelStyle, style lipgloss.Style) []string {
+	if rp.ResourceType != "RDS" {
+		return nil
+	}
+	lines := []string{"", labelStyle.Render("Estimated Cost:")}
+	switch {
+	case m.clusterErr != nil:
+		return append(lines, style.Render("  Unavailable: the live cluster could not be read"))
+	case m.cluster == nil:
+		return append(lines, style.Render("  Loading..."))
+	}
+
+	e := aws.EstimateRestoreCost(m.cluster, rp.BackupSizeInBytes)
+	for _, l := range e.Instances {
+		class := l.Class
+		if l.Class == "db.serverless" {
+			class = fmt.Sprintf("serverless %g-%g ACU", e.ServerlessMin, e.ServerlessMax)
+		}
+		price := "no list price"
+		if l.Priced() {
+			price = aws.FormatCostRange(l.HourlyMin, l.HourlyMax) + "/h"
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("  %-28s %-26s %s", l.Instance, class, price)))
+	}
+	if len(e.Instances) == 0 {
+		lines = append(lines, style.Render("  No instances: the cluster costs only its storage until one is added"))
+	}
+	hourlyLo, hourlyHi := e.Hourly()
+	monthlyLo, monthlyHi := e.Monthly()
+	lines = append(lines, style.Render(fmt.Sprintf("  Total %s/h · ~%s/month incl. %s storage",
+		aws.FormatCostRange(hourlyLo, hourlyHi), aws.FormatCostRange(monthlyLo, monthlyHi), aws.FormatCostRange(e.StorageMonthly, e.StorageMonthly))))
+	note := aws.PricingNote(m.region)
+	if unpriced := e.Unpriced(); len(unpriced) > 0 {
+		note += "; excludes " + strings.Join(unpriced, ", ")
+	}
+	return append(lines, style.Render("  ("+note+")"))
+}
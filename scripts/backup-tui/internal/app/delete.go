@@ -0,0 +1,430 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements deleting recovery points: "d" in the list deletes the
+// backups marked with space (or the one under the cursor), and in the
+// detail view the selected backup, e.g. failed or expired points that would
+// otherwise take the console to clean up. The operator types "delete" to
+// confirm. A confirmed deletion is queued for the config file's deleteGrace
+// (a minute by default), during which its backups leave the list and "u"
+// undoes it, so a fat-fingered deletion of patient-data backups can be
+// taken back. Backups the vault lock or a config rule still protects are
+// kept without asking AWS, and the ones AWS Backup refuses, e.g. under a
+// legal hold, are reported as kept rather than as failed. Quitting while
+// deletions are pending asks whether to send them now or discard them.
+package app
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// deleteConfirmWord is what the operator types to confirm a deletion.
+const deleteConfirmWord = "delete"
+
+// deleteConfirm is the state of the deletion confirmation.
+type deleteConfirm struct {
+	targets  []aws.RecoveryPoint
+	kept     []deleteResult // Known to be protected; not sent to AWS
+	input    string
+	err      error // Mistyped confirmation, shown in the dialog
+	returnTo state // View to return to
+}
+
+// deleteQueue holds confirmed deletions until their grace period ends.
+type deleteQueue struct {
+	pending      []pendingDelete // Oldest first
+	ticking      bool            // A deleteTickMsg is on its way
+	quitReturnTo state           // View to return to from the quit question
+}
+
+// pendingDelete is a confirmed deletion waiting out its grace period.
+type pendingDelete struct {
+	client  *aws.BackupClient // Client of the vault's region, should the region be switched
+	vault   string            // Vault the backups are in, should the vault be switched
+	targets []aws.RecoveryPoint
+	due     time.Time
+}
+
+// deleteTickMsg is sent every second while deletions are pending, to send
+// the due ones and count down the rest in the status bar.
+type deleteTickMsg struct{}
+
+// holds reports whether the backup with the given ARN is pending deletion.
+func (q *deleteQueue) holds(arn string) bool {
+	for _, p := range q.pending {
+		for _, rp := range p.targets {
+			if rp.RecoveryPointARN == arn {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// summary describes the pending deletions for the status bar, or returns
+// "" if there are none.
+func (q *deleteQueue) summary(now time.Time) string {
+	if len(q.pending) == 0 {
+		return ""
+	}
+	n := 0
+	for _, p := range q.pending {
+		n += len(p.targets)
+	}
+	secs := max(int(math.Ceil(q.pending[0].due.Sub(now).Seconds())), 0)
+	return fmt.Sprintf("🗑 %d backup(s) pending deletion, next in %ds, u to undo", n, secs)
+}
+
+// deleteResult is the outcome of deleting one backup.
+type deleteResult struct {
+	rp   aws.RecoveryPoint
+	err  error
+	kept bool // Refused as protected, rather than failed
+}
+
+// pointsDeletedMsg is sent when every target of a deletion has been
+// deleted or refused.
+type pointsDeletedMsg struct {
+	results []deleteResult
+}
+
+// openDelete asks to confirm deleting the marked backups, or the selected
+// one when none are marked or the detail view is open. Backups the vault
+// lock or a config rule is known to protect are listed as kept.
+func (m *Model) openDelete() {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return
+	}
+	d := deleteConfirm{returnTo: m.state}
+	now := time.Now()
+	for _, rp := range targets {
+		err := m.vaultInfo.CheckDelete(rp, now)
+		if rule := m.protectionRule(rp); err == nil && rule != nil {
+			err = fmt.Errorf("protected from deletion by config rule %s", rule)
+		}
+		if err != nil {
+			d.kept = append(d.kept, deleteResult{rp: rp, err: err})
+			continue
+		}
+		d.targets = append(d.targets, rp)
+	}
+	if len(d.targets) == 0 {
+		m.notify(SeverityWarn, fmt.Sprintf("Nothing to delete: %v", d.kept[0].err))
+		return
+	}
+	m.deleteConfirm = d
+	m.state = stateDelete
+}
+
+// updateDelete handles key presses on the deletion confirmation.
+func (m *Model) updateDelete(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	d := &m.deleteConfirm
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.state = d.returnTo
+	case "backspace":
+		if r := []rune(d.input); len(r) > 0 {
+			d.input = string(r[:len(r)-1])
+		}
+	case "enter":
+		if strings.TrimSpace(d.input) != deleteConfirmWord {
+			d.err = fmt.Errorf("type %q to confirm, or esc to keep the backups", deleteConfirmWord)
+			return m, nil
+		}
+		d.err = nil
+		m.state = d.returnTo
+		if m.state == stateDetail {
+			m.state = stateList
+		}
+		return m, m.queueDelete(d.targets)
+	default:
+		if msg.Text != "" {
+			d.input += msg.Text
+		}
+	}
+	return m, nil
+}
+
+// queueDelete queues deleting targets once the grace period ends, taking
+// them off the list in the meantime.
+func (m *Model) queueDelete(targets []aws.RecoveryPoint) tea.Cmd {
+	grace := m.config.DeletionGrace()
+	q := &m.deletes
+	q.pending = append(q.pending, pendingDelete{client: m.backupClient, vault: m.vaultName, targets: targets, due: time.Now().Add(grace)})
+	for _, rp := range targets {
+		delete(m.marked, rp.RecoveryPointARN)
+	}
+	m.refreshList()
+	m.inform(fmt.Sprintf("%d backup(s) will be deleted in %s; u to undo", len(targets), grace))
+	return m.scheduleDeleteTick()
+}
+
+// scheduleDeleteTick returns a command that ticks in a second while
+// deletions are pending, or nil when none are or a tick is on its way.
+func (m *Model) scheduleDeleteTick() tea.Cmd {
+	q := &m.deletes
+	if q.ticking || len(q.pending) == 0 {
+		return nil
+	}
+	q.ticking = true
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return deleteTickMsg{} })
+}
+
+// handleDeleteTick sends the deletions whose grace period has ended.
+func (m *Model) handleDeleteTick() tea.Cmd {
+	q := &m.deletes
+	q.ticking = false
+	now := time.Now()
+	var cmds []tea.Cmd
+	waiting := q.pending[:0]
+	for _, p := range q.pending {
+		if now.Before(p.due) {
+			waiting = append(waiting, p)
+			continue
+		}
+		cmds = append(cmds, m.deletePoints(p.client, p.vault, p.targets))
+	}
+	q.pending = waiting
+	return tea.Batch(append(cmds, m.scheduleDeleteTick())...)
+}
+
+// undoDelete cancels the most recently queued deletion, returning its
+// backups to the list.
+func (m *Model) undoDelete() {
+	q := &m.deletes
+	if len(q.pending) == 0 {
+		return
+	}
+	last := q.pending[len(q.pending)-1]
+	q.pending = q.pending[:len(q.pending)-1]
+	m.refreshList()
+	m.inform(fmt.Sprintf("Deletion of %d backup(s) undone", len(last.targets)))
+}
+
+// refreshList redraws the list after backups were added to or taken off
+// it, keeping the cursor on the selected backup.
+func (m *Model) refreshList() {
+	selected := m.selectedARN()
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+	m.selectARN(selected)
+	m.selectedIdx = m.listModel.SelectedIndex()
+}
+
+// deletePoints returns a command that deletes each of targets from the
+// vault in turn through client, the one of the region the deletion was
+// confirmed in, checking the config file's deletion protection again with
+// their tags.
+func (m *Model) deletePoints(client *aws.BackupClient, vaultName string, targets []aws.RecoveryPoint) tea.Cmd {
+	cfg, ctx := m.config, m.ctx
+	details := make(map[string]*aws.RecoveryPointDetails, len(targets))
+	for _, rp := range targets {
+		details[rp.RecoveryPointARN] = m.enrich.details[rp.RecoveryPointARN]
+	}
+	return func() tea.Msg {
+		results := make([]deleteResult, len(targets))
+		for i, rp := range targets {
+			if err := checkProtected(ctx, client, cfg, vaultName, rp, details[rp.RecoveryPointARN]); err != nil {
+				results[i] = deleteResult{rp: rp, err: err, kept: true}
+				continue
+			}
+			err := client.DeleteRecoveryPoint(ctx, vaultName, rp.RecoveryPointARN)
+			results[i] = deleteResult{rp: rp, err: err, kept: aws.DeletionRefused(err)}
+		}
+		return pointsDeletedMsg{results: results}
+	}
+}
+
+// handlePointsDeleted drops the deleted backups from the list and returns
+// the ones kept to it.
+func (m *Model) handlePointsDeleted(msg pointsDeletedMsg) {
+	deleted := make(map[string]bool)
+	var kept, failed []string
+	for _, r := range msg.results {
+		when := r.rp.ResourceID + " " + r.rp.CreationDate.Format("01-02 15:04")
+		switch {
+		case r.err == nil:
+			deleted[r.rp.RecoveryPointARN] = true
+			delete(m.marked, r.rp.RecoveryPointARN)
+		case r.kept:
+			kept = append(kept, when)
+			m.logError(fmt.Sprintf("Backup of %s (%s) kept as protected", r.rp.ResourceID, r.rp.CreationDate.Format("2006-01-02 15:04")), r.err)
+		default:
+			failed = append(failed, when)
+			m.logError(fmt.Sprintf("Backup of %s (%s) not deleted", r.rp.ResourceID, r.rp.CreationDate.Format("2006-01-02 15:04")), r.err)
+		}
+	}
+
+	m.allBackups = dropPoints(m.allBackups, deleted)
+	m.refreshList()
+
+	text := fmt.Sprintf("Deleted %d of %d backup(s)", len(deleted), len(msg.results))
+	switch {
+	case len(failed) > 0:
+		text += fmt.Sprintf("; not deleted: %s", strings.Join(failed, ", "))
+		if len(kept) > 0 {
+			text += fmt.Sprintf("; kept as protected: %s", strings.Join(kept, ", "))
+		}
+		m.notify(SeverityCritical, text+" (e for details)")
+	case len(kept) > 0:
+		m.notify(SeverityWarn, text+fmt.Sprintf("; kept %s: the vault lock, a legal hold, or a config rule protects them (e for details)", strings.Join(kept, ", ")))
+	default:
+		m.inform(text)
+	}
+}
+
+// dropPoints returns points without the ones whose ARN is in arns.
+func dropPoints(points []aws.RecoveryPoint, arns map[string]bool) []aws.RecoveryPoint {
+	kept := make([]aws.RecoveryPoint, 0, len(points))
+	for _, rp := range points {
+		if !arns[rp.RecoveryPointARN] {
+			kept = append(kept, rp)
+		}
+	}
+	return kept
+}
+
+// renderDelete renders the deletion confirmation.
+func (m *Model) renderDelete() string {
+	header := m.renderHeader()
+	d := m.deleteConfirm
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorFail).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ui.ColorFail)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+	errStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+
+	describe := func(rp aws.RecoveryPoint) string {
+		return fmt.Sprintf("  %s %s (%s, %s)", rp.ResourceType, rp.ResourceID, rp.CreationDate.Format("2006-01-02 15:04 MST"), rp.Status)
+	}
+	lines := []string{titleStyle.Render(fmt.Sprintf("Delete %d Backup(s)", len(d.targets))), ""}
+	// Long selections are summarized past the first few backups
+	const shown = 5
+	for i, rp := range d.targets {
+		if i == shown {
+			lines = append(lines, infoStyle.Render(fmt.Sprintf("  … and %d more", len(d.targets)-shown)))
+			break
+		}
+		lines = append(lines, infoStyle.Render(describe(rp)))
+	}
+	if len(d.kept) > 0 {
+		lines = append(lines, "", warnStyle.Render(fmt.Sprintf("Kept, %d protected:", len(d.kept))))
+		for _, k := range d.kept {
+			lines = append(lines, dimStyle.Render(describe(k.rp)+": "+k.err.Error()))
+		}
+	}
+	lines = append(lines,
+		"",
+		warnStyle.Render("Deleted recovery points cannot be recovered."),
+		dimStyle.Render(fmt.Sprintf("The deletion is sent to AWS in %s; u undoes it until then.", m.config.DeletionGrace())),
+		"",
+		fmt.Sprintf("Type %q to confirm:", deleteConfirmWord),
+		"> "+d.input+"█",
+	)
+	if d.err != nil {
+		lines = append(lines, "", errStyle.Render("✗ "+d.err.Error()))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
+
+// quit quits the TUI, or, while deletions are in their grace period, asks
+// whether to send them first or discard them, so quitting does not drop
+// confirmed deletions unannounced.
+func (m *Model) quit() tea.Cmd {
+	if len(m.deletes.pending) == 0 {
+		return tea.Quit
+	}
+	m.deletes.quitReturnTo = m.state
+	m.state = stateQuitDeletes
+	return nil
+}
+
+// updateQuitDeletes handles key presses on the quit question: s sends the
+// pending deletions and quits once AWS has answered, d (or ctrl+c again)
+// discards them and quits, and esc goes back.
+func (m *Model) updateQuitDeletes(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "s", "S":
+		return m, m.sendDeletesAndQuit()
+	case "d", "D", "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.state = m.deletes.quitReturnTo
+	}
+	return m, nil
+}
+
+// sendDeletesAndQuit sends every pending deletion without waiting out its
+// grace period, then quits.
+func (m *Model) sendDeletesAndQuit() tea.Cmd {
+	q := &m.deletes
+	cmds := make([]tea.Cmd, 0, len(q.pending)+1)
+	n := 0
+	for _, p := range q.pending {
+		cmds = append(cmds, m.deletePoints(p.client, p.vault, p.targets))
+		n += len(p.targets)
+	}
+	q.pending = nil
+	m.inform(fmt.Sprintf("Deleting %d backup(s) before quitting", n))
+	return tea.Sequence(append(cmds, tea.Quit)...)
+}
+
+// renderQuitDeletes renders the quit question, naming the backups whose
+// deletion is pending.
+func (m *Model) renderQuitDeletes() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorWarn).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ui.ColorWarn)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+
+	var lines []string
+	n := 0
+	now := time.Now()
+	// Long queues are summarized past the first few backups
+	const shown = 10
+	for _, p := range m.deletes.pending {
+		secs := max(int(math.Ceil(p.due.Sub(now).Seconds())), 0)
+		for _, rp := range p.targets {
+			if n++; n <= shown {
+				lines = append(lines, infoStyle.Render(fmt.Sprintf("  %s %s (%s) in %s, due in %ds",
+					rp.ResourceType, rp.ResourceID, rp.CreationDate.Format("2006-01-02 15:04 MST"), p.vault, secs)))
+			}
+		}
+	}
+	if n > shown {
+		lines = append(lines, infoStyle.Render(fmt.Sprintf("  … and %d more", n-shown)))
+	}
+	lines = append([]string{titleStyle.Render(fmt.Sprintf("⚠ %d Backup(s) Pending Deletion", n)), ""}, lines...)
+	lines = append(lines,
+		"",
+		"Quitting now would discard these deletions. Send them first?",
+		"",
+		dimStyle.Render("s send them now and quit  d discard them and quit  esc back"),
+	)
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
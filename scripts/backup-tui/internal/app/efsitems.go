@@ -0,0 +1,148 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements item-level EFS restores: "p" on the restore
+// confirmation of an EFS backup opens a form of the paths to restore, e.g.
+// one patient's documents directory, so recovering a few deleted files does
+// not mean restoring the whole file system. No paths restores all of it.
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// efsItemsForm is the state of the items form.
+type efsItemsForm struct {
+	items []string // Paths added so far
+	input string   // Path being typed
+	err   error    // Why the last path was refused
+}
+
+// openEFSItems opens the items form for the pending EFS restore, filled in
+// with the items already chosen.
+func (m *Model) openEFSItems() tea.Cmd {
+	if m.selectedIdx >= len(m.backups) || m.backups[m.selectedIdx].ResourceType != "EFS" {
+		m.notify(SeverityWarn, "Item-level restore applies to EFS backups only")
+		return nil
+	}
+	if m.restoreMetadata == nil {
+		m.notify(SeverityWarn, "The restore parameters are still loading")
+		return nil
+	}
+	m.efsItems = efsItemsForm{items: slices.Clone(m.restoreOpts.ItemsToRestore)}
+	m.state = stateEFSItems
+	return nil
+}
+
+// updateEFSItems handles key presses on the items form: enter adds the
+// typed path, and on an empty line saves the items; backspace on an empty
+// line removes the last item.
+func (m *Model) updateEFSItems(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	f := &m.efsItems
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.state = stateConfirm
+	case "enter":
+		if strings.TrimSpace(f.input) == "" {
+			m.setEFSItems(f.items)
+			m.state = stateConfirm
+			return m, nil
+		}
+		f.err = f.add(f.input)
+		if f.err == nil {
+			f.input = ""
+		}
+	case "backspace":
+		if r := []rune(f.input); len(r) > 0 {
+			f.input = string(r[:len(r)-1])
+		} else if len(f.items) > 0 {
+			f.items = f.items[:len(f.items)-1]
+		}
+		f.err = nil
+	default:
+		if msg.Text != "" {
+			f.input += msg.Text
+		}
+	}
+	return m, nil
+}
+
+// add adds the path item to the form, cleaned, unless it is invalid, a
+// duplicate, or one too many.
+func (f *efsItemsForm) add(item string) error {
+	clean, err := aws.CleanItemPath(item)
+	switch {
+	case err != nil:
+		return err
+	case slices.Contains(f.items, clean):
+		return fmt.Errorf("%s is already listed", clean)
+	case len(f.items) >= aws.MaxItemsToRestore:
+		return fmt.Errorf("an EFS restore takes at most %d items", aws.MaxItemsToRestore)
+	}
+	f.items = append(f.items, clean)
+	return nil
+}
+
+// setEFSItems sets the items of the pending EFS restore; none restores the
+// whole file system.
+func (m *Model) setEFSItems(items []string) {
+	m.restoreOpts.ItemsToRestore = items
+	m.restoreMetadata.ApplyOptions(m.restoreOpts)
+	if len(items) == 0 {
+		m.inform("Restore covers the whole file system")
+		return
+	}
+	m.inform(fmt.Sprintf("Restore covers %d item(s): %s", len(items), strings.Join(items, ", ")))
+}
+
+// itemsValue formats the items an EFS restore covers.
+func itemsValue(meta *aws.RestoreMetadata) string {
+	if len(meta.ItemsToRestore) == 0 {
+		return "whole file system"
+	}
+	return strings.Join(meta.ItemsToRestore, ", ")
+}
+
+// renderEFSItems renders the items form.
+func (m *Model) renderEFSItems() string {
+	header := m.renderHeader()
+	f := m.efsItems
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2)
+	labelStyle := lipgloss.NewStyle().Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	errStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+
+	lines := []string{labelStyle.Render("Restore individual files and directories"), ""}
+	if len(f.items) == 0 {
+		lines = append(lines, dimStyle.Render("No items: the whole file system is restored."))
+	}
+	for i, item := range f.items {
+		lines = append(lines, fmt.Sprintf("  %d. %s", i+1, item))
+	}
+	lines = append(lines,
+		"",
+		fmt.Sprintf("Path %d of up to %d:", len(f.items)+1, aws.MaxItemsToRestore),
+		"> "+f.input+"█",
+	)
+	if f.err != nil {
+		lines = append(lines, "", errStyle.Render("✗ "+f.err.Error()))
+	}
+	lines = append(lines,
+		"",
+		dimStyle.Render("Absolute from the file system's root, e.g. /sites/default/documents; a directory restores everything in it."),
+		dimStyle.Render("The items are written into a new aws-backup-restore_<timestamp> directory, not over the live files."),
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,60 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the EFS resource kind: the live file system in the
+// detail view, the file system a restore writes into, and the items it
+// restores.
+package app
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// efsKind restores an EFS backup in place, or into a new file system.
+var efsKind = resourceKind{
+	lookup: func(m *Model, rp aws.RecoveryPoint) tea.Cmd { return m.fetchFileSystem(rp.ResourceID) },
+	preflight: func(meta *aws.RestoreMetadata, _ *prefetchState) []ui.PreflightItem {
+		target := meta.ResourceID + " (restored in place)"
+		if meta.NewFileSystem {
+			target = "new file system"
+		}
+		return []ui.PreflightItem{{Label: "File System", Value: target}, {Label: "Items", Value: itemsValue(meta)}}
+	},
+	fields: func(meta *aws.RestoreMetadata) []restoreField {
+		return []restoreField{
+			{label: "File System", key: "file-system-id", value: meta.ResourceID},
+			{label: "New FS", key: "newFileSystem", value: fmt.Sprint(meta.NewFileSystem)},
+			{label: "Encrypted", key: "Encrypted", value: fmt.Sprint(meta.Encrypted)},
+			{label: "Items", key: "itemsToRestore", value: itemsValue(meta)},
+		}
+	},
+	help: map[string]fieldHelp{
+		"file-system-id": {
+			what: "EFS file system the backup is restored into. Files are written to a new " +
+				"aws-backup-restore_<timestamp> directory at the root, not over live files.",
+			ifChange: "Another file system receives the restored data instead; OpenEMR's sites directory is only " +
+				"affected when this is the stack's file system.",
+		},
+		"newFileSystem": {
+			what: "false restores into the existing file system (in-place); true creates a new file system.",
+			ifChange: "A new file system has no mount targets or access points, and OpenEMR will not use it " +
+				"until the ECS task definition is updated — recovery takes much longer.",
+		},
+		"Encrypted": {
+			what:     "Whether a newly created file system is encrypted at rest. Always true for this stack.",
+			ifChange: "false would store PHI unencrypted, which violates the stack's HIPAA safeguards.",
+		},
+		"itemsToRestore": {
+			what: "Files and directories restored on their own, by absolute path from the file system's root, " +
+				"e.g. /sites/default/documents; empty restores the whole file system. Press p on the " +
+				"confirmation screen to choose up to 5.",
+			ifChange: "Restoring only the lost files is much faster than the whole file system, but anything " +
+				"outside the listed paths is not restored.",
+		},
+	},
+	keys: []confirmKey{
+		{key: "p", hint: "items to restore", run: (*Model).openEFSItems},
+	},
+}
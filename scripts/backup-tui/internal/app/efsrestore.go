@@ -0,0 +1,68 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements reporting where a completed EFS restore put the
+// files: an in-place restore writes into an aws-backup-restore_<timestamp>
+// directory at the root of the live file system, whose path is shown in the
+// monitoring view and the jobs view and can be copied with "c", so nobody
+// has to mount the file system and hunt for it.
+package app
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// efsRestoreNote returns where a completed EFS restore put the files, or ""
+// for other jobs.
+func efsRestoreNote(job *restoreJob) string {
+	if job.backup.ResourceType != "EFS" || job.status == nil || job.status.Status != "COMPLETED" {
+		return ""
+	}
+	if dir := aws.EFSRestoreDirectory(job.backup, job.status); dir != "" {
+		return fmt.Sprintf("restored into %s at %s", job.backup.ResourceID, dir)
+	}
+	if job.status.ResourceID != "" {
+		return fmt.Sprintf("restored into new file system %s", job.status.ResourceID)
+	}
+	return ""
+}
+
+// monitoredRestoreDir returns the directory the monitored restore wrote
+// into, or "" unless it is a completed in-place EFS restore.
+func (m *Model) monitoredRestoreDir() string {
+	job := m.jobByID(m.restoreJobID)
+	if job == nil {
+		return ""
+	}
+	return aws.EFSRestoreDirectory(job.backup, job.status)
+}
+
+// efsRestoreLines renders where the monitored EFS restore put the files,
+// or nil until it has completed.
+func (m *Model) efsRestoreLines(labelStyle, style lipgloss.Style) []string {
+	job := m.jobByID(m.restoreJobID)
+	if job == nil {
+		return nil
+	}
+	note := efsRestoreNote(job)
+	if note == "" {
+		return nil
+	}
+	lines := []string{"", labelStyle.Render("Restored Files:"), style.Render("  " + note)}
+	if m.monitoredRestoreDir() != "" {
+		lines = append(lines, style.Render("  Press c to copy the path; mount the file system to move the files into place"))
+	}
+	return lines
+}
+
+// copyRestoreDir copies the monitored restore's directory to the clipboard.
+func (m *Model) copyRestoreDir() tea.Cmd {
+	dir := m.monitoredRestoreDir()
+	if dir == "" {
+		return nil
+	}
+	m.inform("Copied " + dir)
+	return tea.SetClipboard(dir)
+}
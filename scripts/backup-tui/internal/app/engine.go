@@ -0,0 +1,119 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the engine version check on the RDS restore
+// confirmation: when the region no longer offers the Aurora version a
+// backup was taken with, RDS upgrades the restored cluster, and the
+// confirmation says to which version and whether OpenEMR has been tested
+// against it.
+package app
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// engineCheckView is the engine version check of the backup being
+// confirmed.
+type engineCheckView struct {
+	arn   string                  // Recovery point checked
+	check *aws.EngineVersionCheck // Nil until looked up, or if the lookup failed
+}
+
+// engineCheckMsg is sent when the engine version of a backup has been
+// checked.
+type engineCheckMsg struct {
+	arn   string
+	check *aws.EngineVersionCheck
+	err   error
+}
+
+// checkEngineVersion returns a command checking whether the region offers
+// the engine version of the selected RDS backup, or nil if it is not an RDS
+// backup or was already checked.
+func (m *Model) checkEngineVersion() tea.Cmd {
+	if m.selectedIdx >= len(m.backups) {
+		return nil
+	}
+	rp := m.backups[m.selectedIdx]
+	if rp.ResourceType != "RDS" || m.engineCheck.arn == rp.RecoveryPointARN {
+		return nil
+	}
+	m.engineCheck = engineCheckView{arn: rp.RecoveryPointARN}
+	client, vault := m.backupClient, m.vaultName
+	return func() tea.Msg {
+		recorded, err := client.RecordedClusterConfig(m.ctx, vault, rp.RecoveryPointARN)
+		if err != nil || recorded.Engine == "" || recorded.EngineVersion == "" {
+			return engineCheckMsg{arn: rp.RecoveryPointARN, err: err}
+		}
+		check, err := client.CheckEngineVersion(m.ctx, recorded.Engine, recorded.EngineVersion)
+		return engineCheckMsg{arn: rp.RecoveryPointARN, check: check, err: err}
+	}
+}
+
+// handleEngineCheck stores a backup's engine version check, unless another
+// backup has been selected since, and warns when the restore upgrades the
+// engine to a version OpenEMR has not been tested against. A failed check
+// is logged; the restore itself is not affected.
+func (m *Model) handleEngineCheck(msg engineCheckMsg) {
+	if msg.arn != m.engineCheck.arn {
+		return
+	}
+	if msg.err != nil {
+		m.logError("Engine version of the backup not checked", msg.err)
+		return
+	}
+	m.engineCheck.check = msg.check
+	if msg.check != nil && msg.check.NeedsUpgrade() && !m.engineVersionTested(msg.check) {
+		m.notify(SeverityWarn, m.engineUpgradeWarning(msg.check))
+	}
+}
+
+// engineVersionTested reports whether OpenEMR has been tested against the
+// version the restored cluster runs.
+func (m *Model) engineVersionTested(check *aws.EngineVersionCheck) bool {
+	version := check.RestoreVersion()
+	if version == "" {
+		return false
+	}
+	var live string
+	if m.cluster != nil {
+		live = m.cluster.EngineVersion
+	}
+	return m.config.EngineVersionTested(version, live)
+}
+
+// engineUpgradeWarning describes the engine upgrade of restoring a backup
+// checked as check.
+func (m *Model) engineUpgradeWarning(check *aws.EngineVersionCheck) string {
+	offered := "is no longer offered"
+	if check.Status != "" {
+		offered = "is " + check.Status
+	}
+	msg := fmt.Sprintf("The backup's Aurora engine %s %s in %s", check.Recorded, offered, m.region)
+	switch {
+	case check.Upgrade == "":
+		return msg + "; RDS upgrades the restored cluster to a version that could not be determined"
+	case m.engineVersionTested(check):
+		return msg + fmt.Sprintf("; the restored cluster is upgraded to %s, which OpenEMR has been tested against", check.Upgrade)
+	default:
+		return msg + fmt.Sprintf("; the restored cluster is upgraded to %s, which OpenEMR has not been tested against", check.Upgrade)
+	}
+}
+
+// engineLines renders the engine upgrade of restoring rp for the restore
+// confirmation, or nil when the restore keeps the backup's version or it has
+// not been checked.
+func (m *Model) engineLines(rp aws.RecoveryPoint, warningStyle, style lipgloss.Style) []string {
+	check := m.engineCheck.check
+	if rp.ResourceType != "RDS" || m.engineCheck.arn != rp.RecoveryPointARN || check == nil || !check.NeedsUpgrade() {
+		return nil
+	}
+	title := "⚠  Engine version upgrade"
+	if !m.engineVersionTested(check) {
+		title = "⚠  Engine version upgrade not tested with OpenEMR"
+	}
+	return []string{"", warningStyle.Render(title),
+		style.Width(76).Render("  " + m.engineUpgradeWarning(check) + ".")}
+}
@@ -0,0 +1,106 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements enrichment of the backup list: who created each
+// recovery point, its encryption, and its tags are not returned by listing
+// the vault, so after every update the rows on screen (the selected one
+// first) are handed to a bounded worker pool, and their details fill in the
+// list and detail view as they arrive.
+package app
+
+import (
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// enrichState is the enrichment pipeline and the details it has fetched.
+type enrichState struct {
+	enricher *aws.Enricher                        // Nil disables enrichment
+	details  map[string]*aws.RecoveryPointDetails // By recovery point ARN; nil when the lookup failed
+	queued   string                               // ARNs last prioritized, to skip unchanged requests
+}
+
+// enrichedMsg delivers the details of a recovery point.
+type enrichedMsg aws.EnrichResult
+
+// waitForEnrichment returns a command that waits for the next enriched
+// recovery point.
+func (m *Model) waitForEnrichment() tea.Cmd {
+	if m.enrich.enricher == nil {
+		return nil
+	}
+	ctx, results := m.ctx, m.enrich.enricher.Results()
+	return func() tea.Msg {
+		select {
+		case r := <-results:
+			return enrichedMsg(r)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// handleEnriched caches a recovery point's details, shows them in the list
+// and detail view, and waits for the next one. Failed lookups are logged
+// and not retried.
+func (m *Model) handleEnriched(msg enrichedMsg) tea.Cmd {
+	if m.enrich.details == nil {
+		m.enrich.details = make(map[string]*aws.RecoveryPointDetails)
+	}
+	m.enrich.details[msg.RecoveryPointARN] = msg.Details
+	if msg.Err != nil {
+		m.logError("Failed to load recovery point details", msg.Err)
+	} else {
+		m.listModel.SetItems(m.formatBackupsForList())
+		m.detailModel.SetDetails(msg.Details)
+	}
+	return m.waitForEnrichment()
+}
+
+// enrichVisible asks the enricher for the details of the backups on screen
+// that are not yet known, the selected one first. Rows scrolled out of
+// view since the last call are dropped from its queue.
+func (m *Model) enrichVisible() {
+	if m.enrich.enricher == nil || (m.state != stateList && m.state != stateDetail) || m.backgroundPaused() {
+		return
+	}
+	var arns []string
+	add := func(i int) {
+		if i < 0 || i >= len(m.backups) {
+			return
+		}
+		arn := m.backups[i].RecoveryPointARN
+		if _, known := m.enrich.details[arn]; !known && !slices.Contains(arns, arn) {
+			arns = append(arns, arn)
+		}
+	}
+	if m.state == stateDetail {
+		add(m.selectedIdx)
+	} else {
+		add(m.listModel.SelectedIndex())
+	}
+	start, end := m.listModel.VisibleRange()
+	for i := start; i < end; i++ {
+		add(i)
+	}
+
+	if queued := strings.Join(arns, "\n"); queued != m.enrich.queued {
+		m.enrich.queued = queued
+		m.enrich.enricher.Prioritize(m.vaultName, arns)
+	}
+}
+
+// backupCreator returns who created a backup, "on-demand" or its backup
+// plan's name, or "" until its details are known.
+func (m *Model) backupCreator(arn string) string {
+	d := m.enrich.details[arn]
+	switch {
+	case d == nil:
+		return ""
+	case d.OnDemand():
+		return "on-demand"
+	default:
+		return d.CreatedBy
+	}
+}
@@ -0,0 +1,201 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the environment info panel: "i" lists every
+// identifier the TUI has discovered (account, region, identity, stack,
+// vault, restore role, and the stack's clusters and file systems) one per
+// line and unboxed, so they can be copied into tickets and CLI commands,
+// and "enter" copies the selected one to the clipboard. The last is a
+// backup-tui:// link reopening the view the panel was opened from.
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// envInfoView is the state of the environment info panel.
+type envInfoView struct {
+	returnTo  state
+	cursor    int
+	stack     string // Stack the ARN and resources were loaded for
+	stackARN  string
+	resources []aws.ProtectedResource
+	err       error // Error from loading the stack's identifiers
+	loading   bool
+}
+
+// envInfoMsg is sent when the stack's ARN and resources have been loaded.
+type envInfoMsg struct {
+	stack     string
+	stackARN  string
+	resources []aws.ProtectedResource
+	err       error
+}
+
+// envField is one identifier in the panel. An empty value is not known.
+type envField struct {
+	label string
+	value string
+}
+
+// openEnvInfo opens the environment info panel, loading the stack's
+// identifiers the first time, or again after they failed to load.
+func (m *Model) openEnvInfo() tea.Cmd {
+	m.envInfo.returnTo = m.state
+	m.envInfo.cursor = 0
+	m.state = stateEnvInfo
+	if m.stackName == "" || m.envInfo.loading || (m.envInfo.stack == m.stackName && m.envInfo.err == nil) {
+		return nil
+	}
+	m.envInfo.loading = true
+	client, stackName := m.backupClient, m.stackName
+	return func() tea.Msg {
+		msg := envInfoMsg{stack: stackName}
+		msg.stackARN, msg.err = client.StackARN(m.ctx, stackName)
+		if msg.err == nil {
+			msg.resources, msg.err = client.StackResources(m.ctx, stackName)
+		}
+		return msg
+	}
+}
+
+// handleEnvInfo records the loaded stack identifiers.
+func (m *Model) handleEnvInfo(msg envInfoMsg) {
+	v := &m.envInfo
+	v.loading = false
+	v.stack, v.stackARN, v.resources, v.err = msg.stack, msg.stackARN, msg.resources, msg.err
+}
+
+// envFields returns the identifiers the panel lists, in order.
+func (m *Model) envFields() []envField {
+	fields := []envField{{label: "Region", value: m.region}}
+	var vaultARN string
+	if m.vaultInfo != nil {
+		vaultARN = m.vaultInfo.ARN
+	}
+	if c := m.backupClient; c != nil {
+		fields = append(fields, envField{"Account", c.AccountID()}, envField{"Identity ARN", c.CallerARN()})
+		if vaultARN == "" && m.vaultName != "" {
+			vaultARN = c.VaultARN(m.vaultName)
+		}
+	}
+	if m.stackName != "" {
+		fields = append(fields, envField{"Stack", m.stackName}, envField{"Stack ARN", m.envInfo.stackARN})
+	}
+	fields = append(fields, envField{"Vault", m.vaultName}, envField{"Vault ARN", vaultARN})
+	if m.planRole != nil {
+		fields = append(fields, envField{"Restore role ARN", m.planRole.RoleARN})
+	}
+	for _, r := range m.envResources() {
+		if r.Type == "EFS" {
+			fields = append(fields, envField{"EFS ID", arnName(r.ARN)}, envField{"EFS ARN", r.ARN})
+		} else {
+			fields = append(fields, envField{r.Type + " cluster ARN", r.ARN})
+		}
+	}
+	return append(fields, envField{"Link", m.deepLink(m.envInfo.returnTo).String()})
+}
+
+// envResources returns the stack's clusters and file systems, or, without
+// a stack, those the vault's backups were taken from.
+func (m *Model) envResources() []aws.ProtectedResource {
+	if m.envInfo.stack == m.stackName && m.envInfo.stackARN != "" {
+		return m.envInfo.resources
+	}
+	var resources []aws.ProtectedResource
+	seen := make(map[string]bool)
+	for _, bp := range m.allBackups {
+		if bp.ResourceARN == "" || seen[bp.ResourceARN] {
+			continue
+		}
+		seen[bp.ResourceARN] = true
+		resources = append(resources, aws.ProtectedResource{Type: bp.ResourceType, ARN: bp.ResourceARN})
+	}
+	slices.SortFunc(resources, func(a, b aws.ProtectedResource) int {
+		return strings.Compare(a.Type+a.ARN, b.Type+b.ARN)
+	})
+	return resources
+}
+
+// updateEnvInfo handles key presses in the environment info panel.
+func (m *Model) updateEnvInfo(msg tea.KeyPressMsg) tea.Cmd {
+	fields := m.envFields()
+	switch msg.String() {
+	case "up", "k":
+		if m.envInfo.cursor > 0 {
+			m.envInfo.cursor--
+		}
+	case "down", "j":
+		if m.envInfo.cursor < len(fields)-1 {
+			m.envInfo.cursor++
+		}
+	case "enter", "c":
+		if m.envInfo.cursor < len(fields) {
+			if f := fields[m.envInfo.cursor]; f.value != "" {
+				m.inform("Copied " + f.label)
+				return tea.SetClipboard(f.value)
+			}
+		}
+	case "a":
+		var lines []string
+		for _, f := range fields {
+			if f.value != "" {
+				lines = append(lines, f.label+": "+f.value)
+			}
+		}
+		m.inform(fmt.Sprintf("Copied %d identifier(s)", len(lines)))
+		return tea.SetClipboard(strings.Join(lines, "\n"))
+	}
+	return nil
+}
+
+// renderEnvInfo renders the environment info panel. The identifiers are
+// not boxed, so a terminal selection copies them without border characters.
+func (m *Model) renderEnvInfo() string {
+	header := m.renderHeader()
+
+	titleStyle := lipgloss.NewStyle().Bold(true).MarginTop(1)
+	labelStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("248")})
+	selectedStyle := lipgloss.NewStyle().Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+
+	fields := m.envFields()
+	width := 0
+	for _, f := range fields {
+		width = max(width, len(f.label))
+	}
+
+	lines := []string{titleStyle.Render("Environment Info"), ""}
+	for i, f := range fields {
+		cursor, label := "  ", labelStyle.Render(fmt.Sprintf("%-*s", width, f.label))
+		if i == m.envInfo.cursor {
+			cursor, label = selectedStyle.Render("> "), selectedStyle.Render(fmt.Sprintf("%-*s", width, f.label))
+		}
+		value := f.value
+		if value == "" {
+			value = dimStyle.Render("unknown")
+		}
+		lines = append(lines, cursor+label+"  "+value)
+	}
+
+	switch v := m.envInfo; {
+	case v.loading:
+		lines = append(lines, "", dimStyle.Render("Loading the stack's ARN and resources..."))
+	case v.err != nil:
+		lines = append(lines, "", warnStyle.Render("Stack identifiers unavailable: "+v.err.Error()))
+		if len(m.envResources()) > 0 {
+			lines = append(lines, dimStyle.Render("Resources are those the vault's backups were taken from."))
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
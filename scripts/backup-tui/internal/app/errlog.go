@@ -0,0 +1,157 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the error log: errors and warnings shown in the
+// status bar are easy to miss when the next message replaces them, so the
+// most recent ones are kept with their time and AWS details (error code and
+// request ID, which AWS support asks for), and "e" opens them in a pane.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// errorLogSize is how many errors and warnings the log keeps.
+const errorLogSize = 50
+
+// errorEntry is an error or warning in the error log.
+type errorEntry struct {
+	at      time.Time
+	warning bool   // A job AWS reported as failed, rather than a failed call
+	message string // As shown in the status bar
+	err     string // Full error text; empty for warnings
+	details aws.ErrorDetails
+}
+
+// errorLog is the error log and the state of its pane.
+type errorLog struct {
+	entries  []errorEntry // Oldest first
+	unseen   int          // Entries added since the pane was last opened
+	cursor   int          // Selected entry, counted from the newest
+	expanded bool         // Whether the selected entry's details are shown
+	returnTo state        // View to return to on esc/q
+}
+
+// add appends e, dropping the oldest entry when the log is full.
+func (l *errorLog) add(e errorEntry) {
+	l.entries = append(l.entries, e)
+	if len(l.entries) > errorLogSize {
+		l.entries = l.entries[len(l.entries)-errorLogSize:]
+	}
+	l.unseen++
+}
+
+// reportError pushes status as a critical warning and records it with err
+// in the error log.
+func (m *Model) reportError(status string, err error) {
+	m.notify(SeverityCritical, status)
+	m.logError(status, err)
+}
+
+// logError records message and err in the error log.
+func (m *Model) logError(message string, err error) {
+	e := errorEntry{at: time.Now(), message: message}
+	if err != nil {
+		e.err = err.Error()
+		e.details = aws.DescribeError(err)
+		m.noteFailure(err)
+	}
+	m.errorLog.add(e)
+}
+
+// reportWarning pushes status as a warning and records it in the error
+// log.
+func (m *Model) reportWarning(status string) {
+	m.notify(SeverityWarn, status)
+	m.errorLog.add(errorEntry{at: time.Now(), warning: true, message: status})
+}
+
+// openErrorLog opens the error log pane on the newest entry.
+func (m *Model) openErrorLog() {
+	m.errorLog.returnTo = m.state
+	m.errorLog.cursor = 0
+	m.errorLog.expanded = false
+	m.errorLog.unseen = 0
+	m.state = stateErrorLog
+}
+
+// updateErrorLog handles key presses in the error log pane.
+func (m *Model) updateErrorLog(msg tea.KeyPressMsg) {
+	l := &m.errorLog
+	switch msg.String() {
+	case "up", "k":
+		if l.cursor > 0 {
+			l.cursor--
+		}
+	case "down", "j":
+		if l.cursor < len(l.entries)-1 {
+			l.cursor++
+		}
+	case "enter", "space":
+		l.expanded = !l.expanded
+	}
+}
+
+// renderErrorLog renders the error log pane, newest first.
+func (m *Model) renderErrorLog() string {
+	header := m.renderHeader()
+	l := m.errorLog
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	errStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+
+	lines := []string{titleStyle.Render(fmt.Sprintf("Errors and Warnings (last %d kept)", errorLogSize)), ""}
+	if len(l.entries) == 0 {
+		lines = append(lines, dimStyle.Render("No errors or warnings this session."))
+	}
+
+	for i := range l.entries {
+		e := l.entries[len(l.entries)-1-i]
+		icon := errStyle.Render("✗")
+		if e.warning {
+			icon = warnStyle.Render("!")
+		}
+		line := fmt.Sprintf("%s  %s", e.at.Format("15:04:05"), e.message)
+		if i == l.cursor {
+			lines = append(lines, icon+" "+focusStyle.Render("▸ "+line))
+		} else {
+			lines = append(lines, icon+" "+infoStyle.Render("  "+line))
+		}
+		if i != l.cursor || !l.expanded {
+			continue
+		}
+
+		detail := func(label, value string) {
+			if value != "" {
+				lines = append(lines, dimStyle.Render(fmt.Sprintf("      %-11s %s", label+":", value)))
+			}
+		}
+		if e.details.Service != "" {
+			detail("Operation", e.details.Service+" "+e.details.Operation)
+		}
+		detail("Error code", e.details.Code)
+		detail("Request ID", e.details.RequestID)
+		detail("Error", e.err)
+		detail("Time", e.at.Format(time.RFC3339))
+	}
+
+	lines = append(lines, "", dimStyle.Render("Include the request ID when opening an AWS support case."))
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,132 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements snapshot export from the detail view: "x" on an RDS
+// backup confirms and starts an S3 Parquet export of the recovery point to
+// the destination given with the -export-* flags, then tracks the export
+// task's progress in the jobs view like a restore.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// exportStartedMsg is sent when StartExportTask returns for a job.
+type exportStartedMsg struct {
+	seq    int
+	taskID string
+	err    error
+}
+
+// openExportConfirm asks to confirm exporting the selected backup, or
+// explains why it cannot be exported.
+func (m *Model) openExportConfirm() {
+	if m.selectedIdx >= len(m.backups) {
+		return
+	}
+	rp := m.backups[m.selectedIdx]
+	if !aws.CanExport(rp.ResourceType) {
+		m.notify(SeverityWarn, fmt.Sprintf("%s backups cannot be exported to S3; only Aurora snapshots can", rp.ResourceType))
+		return
+	}
+	if err := m.exportDest.Validate(); err != nil {
+		m.reportError(fmt.Sprintf("Snapshot export unavailable: %v (set -export-bucket, -export-role, and -export-kms-key)", err), err)
+		return
+	}
+	m.state = stateExport
+}
+
+// updateExportConfirm handles key presses on the export confirmation.
+func (m *Model) updateExportConfirm(msg tea.KeyPressMsg) tea.Cmd {
+	switch msg.String() {
+	case "y", "Y":
+		if m.selectedIdx >= len(m.backups) {
+			return nil
+		}
+		job := m.addJob(m.backups[m.selectedIdx], nil)
+		job.kind = aws.JobKindExport
+		m.jobsCursor = len(m.jobs) - 1
+		m.state = stateJobs
+		m.inform(fmt.Sprintf("Starting export #%d...", job.seq))
+		return m.startExport(job)
+	case "n", "N", "backspace":
+		m.state = stateDetail
+	}
+	return nil
+}
+
+// startExport returns a command that starts the export task for job.
+func (m *Model) startExport(job *restoreJob) tea.Cmd {
+	job.started = time.Now()
+	seq, backup, dest := job.seq, job.backup, m.exportDest
+	client := m.backupClient
+	return func() tea.Msg {
+		taskID, err := client.StartSnapshotExport(m.ctx, backup, dest)
+		return exportStartedMsg{seq: seq, taskID: taskID, err: err}
+	}
+}
+
+// handleExportStarted records a started export, saves it for resuming, and
+// starts polling its progress.
+func (m *Model) handleExportStarted(msg exportStartedMsg) tea.Cmd {
+	job := m.jobBySeq(msg.seq)
+	if job == nil {
+		return nil
+	}
+	if msg.err != nil {
+		job.state = jobFailed
+		job.note = msg.err.Error()
+		m.reportError(fmt.Sprintf("Export #%d failed to start: %v", job.seq, msg.err), msg.err)
+		return m.notifyFinished(job)
+	}
+	job.jobID = msg.taskID
+	job.state = jobActive
+	m.recordJob(job)
+	m.inform(fmt.Sprintf("Export #%d started: task %s", job.seq, msg.taskID))
+	return tea.Batch(m.pollRestoreStatus(job.jobID), m.notifyStep(job, config.EventStarted))
+}
+
+// renderExportConfirm renders the export confirmation.
+func (m *Model) renderExportConfirm() string {
+	header := m.renderHeader()
+	if m.selectedIdx >= len(m.backups) {
+		return lipgloss.JoinVertical(lipgloss.Left, header, "No backup selected")
+	}
+	rp := m.backups[m.selectedIdx]
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorWarn).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.ColorWarn).
+		Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+
+	lines := []string{
+		titleStyle.Render("Export Snapshot to S3"),
+		"",
+		infoStyle.Render(fmt.Sprintf("Backup:      %s (%s)", rp.ResourceID, rp.CreationDate.Format("2006-01-02 15:04:05 MST"))),
+		infoStyle.Render(fmt.Sprintf("Size:        %s", formatBytes(rp.BackupSizeInBytes))),
+		infoStyle.Render("Destination: " + m.exportDest.String()),
+		infoStyle.Render("Format:      Apache Parquet, one folder per table"),
+		infoStyle.Render("Encryption:  " + m.exportDest.KMSKeyID),
+		infoStyle.Render("Role:        " + m.exportDest.IAMRoleARN),
+		"",
+		dimStyle.Render("The export contains patient data: the bucket must have the same access controls as the database."),
+		dimStyle.Render("RDS charges per GB of snapshot exported; large databases take hours."),
+		"",
+		infoStyle.Render("Start the export? y / n"),
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,114 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements fatal error handling while restores are still
+// tracked: the terminal bell rings, the running job IDs are saved to the job
+// history file for "backup-tui watch", and quitting requires confirmation.
+// The operator can instead keep a minimal tracking mode (the jobs view)
+// running until the restores finish.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// fail shows a fatal error. If restores are still tracked, it saves their
+// job IDs and rings the terminal bell so an operator who has looked away
+// notices before the restores go unwatched.
+func (m *Model) fail(err error) tea.Cmd {
+	m.err = err
+	m.state = stateError
+	m.logError(err.Error(), err)
+	m.minimalTracking = false
+
+	running := m.runningJobs()
+	if len(running) == 0 {
+		return nil
+	}
+	m.saveRunningJobs(running)
+	return tea.Raw("\a")
+}
+
+// runningJobs returns the tracked restores that have not finished, including
+// queued chain steps.
+func (m *Model) runningJobs() []*restoreJob {
+	var running []*restoreJob
+	for _, j := range m.jobs {
+		if j.state.pending() {
+			running = append(running, j)
+		}
+	}
+	return running
+}
+
+// saveRunningJobs saves the started jobs among running to the job history
+// file again, in case saving them when they started failed. Queued steps
+// have no job ID yet and cannot be followed later.
+func (m *Model) saveRunningJobs(running []*restoreJob) {
+	if m.historyPath == "" {
+		return
+	}
+	var tracked []store.TrackedJob
+	for _, j := range running {
+		if j.jobID != "" {
+			tracked = append(tracked, m.trackedJob(j))
+		}
+	}
+	if len(tracked) == 0 {
+		m.savedJobs = ""
+		return
+	}
+	if _, err := store.SaveJobs(m.historyPath, tracked...); err != nil {
+		m.savedJobs = fmt.Sprintf("Could not save job IDs: %v", err)
+		return
+	}
+	m.savedJobs = fmt.Sprintf("Job IDs saved to %s — relaunching resumes tracking, or follow them with: backup-tui watch", m.historyPath)
+}
+
+// updateFatalPrompt handles key presses on the error screen while restores
+// are still tracked. Quitting needs Q (or ctrl+c); q and esc do nothing, so
+// the error cannot be dismissed by reflex.
+func (m *Model) updateFatalPrompt(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "k":
+		m.minimalTracking = true
+		m.state = stateJobs
+		m.notify(SeverityWarn, "Minimal tracking: restores are still polled; other views are unavailable after the error")
+	case "Q", "ctrl+c":
+		return m, m.quit()
+	}
+	return m, nil
+}
+
+// homeState is the view that esc/q returns to from the jobs and monitoring
+// views: the list, or the error screen in minimal tracking mode.
+func (m *Model) homeState() state {
+	if m.minimalTracking {
+		return stateError
+	}
+	return stateList
+}
+
+// renderRunningJobs renders the fatal error prompt listing the restores that
+// quitting would stop tracking.
+func (m *Model) renderRunningJobs(running []*restoreJob) string {
+	warnStyle := lipgloss.NewStyle().Bold(true).Foreground(ui.ColorWarn)
+
+	lines := []string{warnStyle.Render(fmt.Sprintf("⚠ %d job(s) still running — quitting stops tracking them:", len(running)))}
+	for _, j := range running {
+		detail := "job " + j.jobID
+		if j.state == jobQueued {
+			detail = "queued: will not start if you quit"
+		}
+		lines = append(lines, fmt.Sprintf("  #%d  %-3s  %-30s  %-12s  %s", j.seq, j.backup.ResourceType, j.backup.ResourceID, j.label(), detail))
+	}
+	if m.savedJobs != "" {
+		lines = append(lines, "", m.savedJobs)
+	}
+	lines = append(lines, "", "Press 'k' to keep tracking (minimal mode) or 'Q' to quit anyway")
+	return strings.Join(lines, "\n")
+}
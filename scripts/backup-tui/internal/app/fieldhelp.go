@@ -0,0 +1,58 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the restore parameter fields shown on the confirmation
+// screen and their contextual help, which explains what each AWS Backup
+// restore metadata key does and the consequences of changing it.
+package app
+
+import "github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+
+// restoreField is one restore parameter on the confirmation screen.
+type restoreField struct {
+	label string // Short label shown in the form
+	key   string // AWS Backup restore metadata key (or request field)
+	value string
+}
+
+// fieldHelp explains a restore metadata key.
+type fieldHelp struct {
+	what     string // What the key does
+	ifChange string // Consequences of a different value
+}
+
+// restoreFieldHelp documents the restore metadata keys the TUI sets for
+// every resource type, keyed by metadata key; each resource kind documents
+// its own.
+var restoreFieldHelp = map[string]fieldHelp{
+	"KmsKeyId": {
+		what: "KMS key the restored cluster or file system is encrypted with. By default the restore keeps " +
+			"the backup's key; press e on the confirmation screen to pick another key by alias.",
+		ifChange: "The restore role must be allowed to use the new key or the job fails. For EFS a different " +
+			"key restores into a new file system, which OpenEMR does not use until the stack is updated.",
+	},
+	"IamRoleArn": {
+		what: "IAM role AWS Backup assumes to perform the restore, taken from the vault's backup plan " +
+			"so restores run with the same trust and permissions as the backups.",
+		ifChange: "A role without restore permissions makes the job fail after it starts; a broader role " +
+			"widens what the restore job can modify.",
+	},
+}
+
+// restoreFields returns the restore parameters for the confirmation screen,
+// in display order. The IAM role is included once it has been resolved.
+func restoreFields(meta *aws.RestoreMetadata, planRole *aws.PlanRole) []restoreField {
+	var fields []restoreField
+	if meta != nil {
+		if kindFields := kindOf(meta.ResourceType).fields; kindFields != nil {
+			fields = kindFields(meta)
+		}
+		key := meta.KMSKeyID
+		if key == "" {
+			key = "(backup's key)"
+		}
+		fields = append(fields, restoreField{label: "KMS Key", key: "KmsKeyId", value: key})
+	}
+	if planRole != nil {
+		fields = append(fields, restoreField{label: "Role", key: "IamRoleArn", value: planRole.RoleARN})
+	}
+	return fields
+}
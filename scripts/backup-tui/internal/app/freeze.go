@@ -0,0 +1,52 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the config file's change freeze windows: while one
+// is in effect, restores are refused, including those chained after a
+// running restore, unless the session was started with --override-freeze.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+)
+
+// activeFreeze returns the freeze window refusing restores now, or nil if
+// none is in effect or the session overrides freezes.
+func (m *Model) activeFreeze() *config.FreezeWindow {
+	if m.overrideFreeze {
+		return nil
+	}
+	w, _ := m.config.ActiveFreeze(time.Now())
+	return w
+}
+
+// freezeRefusal returns why a restore cannot start now, or "" if it can.
+func (m *Model) freezeRefusal() string {
+	if m.overrideFreeze {
+		return ""
+	}
+	w, until := m.config.ActiveFreeze(time.Now())
+	if w == nil {
+		return ""
+	}
+	return fmt.Sprintf("Restores are frozen by config window %s until %s; restart with --override-freeze to restore anyway",
+		w, until.Local().Format("15:04"))
+}
+
+// freezeLines renders the freeze window in effect for the restore
+// confirmation, or nil if there is none.
+func (m *Model) freezeLines(warningStyle, style lipgloss.Style) []string {
+	w, until := m.config.ActiveFreeze(time.Now())
+	if w == nil {
+		return nil
+	}
+	if m.overrideFreeze {
+		return []string{"", warningStyle.Render("⚠  Change freeze overridden"),
+			style.Render(fmt.Sprintf("  %s is in effect; --override-freeze allows this restore", w))}
+	}
+	return []string{"", warningStyle.Render("⛔ Change freeze in effect"),
+		style.Render(fmt.Sprintf("  %s, until %s", w, until.Local().Format("15:04"))),
+		style.Render("  Restores are refused; restart with --override-freeze to restore anyway")}
+}
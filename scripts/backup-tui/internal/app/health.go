@@ -0,0 +1,69 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements service health awareness: when a call fails the way
+// a regional outage makes calls fail, the AWS status feeds of Backup, RDS,
+// and EFS in the region are checked in the background, and an ongoing
+// incident replaces the generic error in the status bar, e.g. "AWS Backup
+// is reporting an incident in us-west-2: Increased API error rates".
+package app
+
+import (
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// healthCheckInterval is the least time between health checks, so a burst
+// of failing calls reads the feeds once.
+const healthCheckInterval = 5 * time.Minute
+
+// healthState is the state of service health checks.
+type healthState struct {
+	checker   *aws.HealthChecker // Nil in simulation mode
+	checkedAt time.Time          // When the last check started
+	due       bool               // A regional failure is waiting for a check
+}
+
+// healthCheckedMsg is sent when the status feeds have been read.
+type healthCheckedMsg struct {
+	region    string
+	incidents []aws.ServiceIncident
+}
+
+// noteFailure schedules a health check if err is a regional failure and
+// none ran recently.
+func (m *Model) noteFailure(err error) {
+	if m.health.checker == nil || !aws.IsRegionalFailure(err) || time.Since(m.health.checkedAt) < healthCheckInterval {
+		return
+	}
+	m.health.due = true
+}
+
+// checkHealth returns the command reading the status feeds of the region
+// when a check is due, or nil.
+func (m *Model) checkHealth() tea.Cmd {
+	if !m.health.due {
+		return nil
+	}
+	m.health.due = false
+	m.health.checkedAt = time.Now()
+	checker, ctx, region := m.health.checker, m.ctx, m.region
+	return func() tea.Msg {
+		// A feed that cannot be read is no news; the error it would
+		// explain is already shown
+		incidents, _ := checker.Incidents(ctx, region)
+		return healthCheckedMsg{region: region, incidents: incidents}
+	}
+}
+
+// handleHealthChecked shows the incidents the status feeds report, and
+// records them in the error log next to the failures they explain.
+func (m *Model) handleHealthChecked(msg healthCheckedMsg) {
+	if msg.region != m.region {
+		return
+	}
+	for _, inc := range msg.incidents {
+		m.notify(SeverityCritical, inc.String())
+		m.logError(inc.String(), nil)
+	}
+}
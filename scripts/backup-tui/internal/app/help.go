@@ -0,0 +1,182 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the help overlay: "?" lists the bindings of the view
+// it was opened from first and the bindings that work everywhere second,
+// and "/" filters them, so help stays accurate as views gain keys.
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// helpStates are the views "?" opens help from. The restore confirmation
+// uses "?" to explain the focused parameter instead.
+var helpStates = map[state]bool{
+	stateList:         true,
+	stateDetail:       true,
+	stateJobs:         true,
+	stateTimeline:     true,
+	stateTaskDefs:     true,
+	stateLegalHolds:   true,
+	stateSelections:   true,
+	stateActivity:     true,
+	stateTeamActivity: true,
+	stateBackupJobs:   true,
+	stateRestoreJobs:  true,
+	stateErrorLog:     true,
+	stateAPICalls:     true,
+	stateEnvInfo:      true,
+}
+
+// navBinding is the cursor binding shared by most views.
+var navBinding = ui.HelpBinding{Key: "↑/↓, k/j", Desc: "Move the cursor"}
+
+// helpContext returns the bindings of view s, or nil for the backup list,
+// whose bindings the help component knows.
+func helpContext(s state) []ui.HelpSection {
+	switch s {
+	case stateDetail:
+		return []ui.HelpSection{
+			{Title: "Backup Details", Bindings: []ui.HelpBinding{
+				{Key: "Enter", Desc: "Restore this backup (opens the confirmation)"},
+				{Key: "x", Desc: "Export an RDS backup to S3 as Parquet"},
+				{Key: "l", Desc: "Change the backup's retention"},
+				{Key: "#", Desc: "Add or remove the backup's tags"},
+				{Key: "d", Desc: "Delete the backup (type \"delete\" to confirm)"},
+				{Key: "T", Desc: "OpenEMR task definition history around this backup"},
+				{Key: "i", Desc: "Environment info: identifiers to copy"},
+			}},
+			{Title: "Restore Confirmation", Bindings: []ui.HelpBinding{
+				{Key: "y", Desc: "Start the restore"},
+				{Key: "a", Desc: "Queue the restore to start after the current one completes"},
+				{Key: "e", Desc: "Choose the KMS key for the restore"},
+				{Key: "g", Desc: "Choose the security groups (RDS)"},
+				{Key: "s", Desc: "Choose the DB subnet group (RDS)"},
+				{Key: "c", Desc: "Fast-clone the current Aurora cluster instead (RDS)"},
+				{Key: "d", Desc: "Compare the live cluster with what an RDS restore creates"},
+				{Key: "t", Desc: "Copy the original resource's tags onto the restored one"},
+				{Key: "r", Desc: "Restore to a new cluster named with a suffix, alongside the live one (RDS)"},
+				{Key: "p", Desc: "Restore only some files and directories (EFS)"},
+				{Key: "↑/↓, ?", Desc: "Select a restore parameter and explain it"},
+				{Key: "n, Esc", Desc: "Cancel"},
+			}},
+		}
+	case stateJobs:
+		return []ui.HelpSection{
+			{Title: "Jobs", Bindings: []ui.HelpBinding{navBinding,
+				{Key: "Enter", Desc: "Monitor a restore, or track a job started elsewhere"},
+				{Key: "x", Desc: "Cancel a queued chain step"},
+				{Key: "D", Desc: "Delete what a failed job left behind"},
+				{Key: "i", Desc: "Import a restore or backup job ID"},
+				{Key: "V", Desc: "Verify a completed restore: row counts or file system size, against the config's checks"},
+				{Key: "r", Desc: "Refresh jobs started elsewhere"},
+			}},
+			{Title: "Restore Monitor", Bindings: []ui.HelpBinding{
+				{Key: "c", Desc: "Copy the directory a completed in-place EFS restore wrote into"},
+				{Key: "J", Desc: "Open the jobs view"},
+				{Key: "Esc", Desc: "Back to the list; the restore continues"},
+			}},
+		}
+	case stateTimeline:
+		return []ui.HelpSection{
+			{Title: "Timeline", Bindings: []ui.HelpBinding{navBinding,
+				{Key: "Enter", Desc: "Open the backup of the selected event"},
+				{Key: "r", Desc: "Refresh"},
+			}},
+		}
+	case stateTaskDefs:
+		return []ui.HelpSection{
+			{Title: "Task Definitions", Bindings: []ui.HelpBinding{navBinding,
+				{Key: "r", Desc: "Refresh"},
+			}},
+		}
+	case stateLegalHolds:
+		return []ui.HelpSection{
+			{Title: "Legal Holds", Bindings: []ui.HelpBinding{navBinding,
+				{Key: "n", Desc: "Place a hold on the marked backups"},
+				{Key: "x", Desc: "Release the selected hold"},
+				{Key: "r", Desc: "Refresh"},
+			}},
+		}
+	case stateSelections:
+		return []ui.HelpSection{
+			{Title: "Backup Selections", Bindings: []ui.HelpBinding{
+				{Key: "r", Desc: "Refresh"},
+			}},
+		}
+	case stateActivity:
+		return []ui.HelpSection{
+			{Title: "Vault Activity", Bindings: []ui.HelpBinding{navBinding,
+				{Key: "Enter", Desc: "Open the selected backup"},
+				{Key: "w", Desc: "Widen the window"},
+				{Key: "r", Desc: "Refresh"},
+			}},
+		}
+	case stateBackupJobs, stateRestoreJobs:
+		return []ui.HelpSection{
+			{Title: "Job Tabs", Bindings: []ui.HelpBinding{navBinding,
+				{Key: "Enter", Desc: "Open a backup job's backup, or track a restore job in the jobs view"},
+				{Key: "Tab/Shift+Tab", Desc: "Next/previous tab: Backups, Backup Jobs, Restore Jobs"},
+				{Key: "w", Desc: "Widen the window (7 or 30 days)"},
+				{Key: "r", Desc: "Refresh"},
+			}},
+		}
+	case stateTeamActivity:
+		return []ui.HelpSection{
+			{Title: "Team Activity", Bindings: []ui.HelpBinding{navBinding,
+				{Key: "w", Desc: "Widen the window"},
+				{Key: "r", Desc: "Refresh"},
+			}},
+		}
+	case stateErrorLog:
+		return []ui.HelpSection{
+			{Title: "Error Log", Bindings: []ui.HelpBinding{navBinding,
+				{Key: "Enter, Space", Desc: "Show or hide the entry's details"},
+			}},
+		}
+	case stateAPICalls:
+		return []ui.HelpSection{
+			{Title: "API Calls", Bindings: []ui.HelpBinding{
+				{Key: "+", Desc: "Raise the call budget and resume background refresh"},
+			}},
+		}
+	case stateEnvInfo:
+		return []ui.HelpSection{
+			{Title: "Environment Info", Bindings: []ui.HelpBinding{navBinding,
+				{Key: "Enter, c", Desc: "Copy the selected identifier to the clipboard"},
+				{Key: "a", Desc: "Copy every identifier, one \"label: value\" per line"},
+			}},
+		}
+	}
+	return nil
+}
+
+// openHelp opens the help overlay for the current view.
+func (m *Model) openHelp() {
+	m.helpReturnTo = m.state
+	m.helpModel.SetContext(helpContext(m.state))
+	m.state = stateHelp
+}
+
+// updateHelp handles key presses on the help overlay: typed into the search
+// while searching, otherwise "/" searches, esc, q, or "?" close it, and
+// ctrl+c quits.
+func (m *Model) updateHelp(msg tea.KeyPressMsg) tea.Cmd {
+	if m.helpModel.Searching() {
+		var cmd tea.Cmd
+		m.helpModel, cmd = m.helpModel.Update(msg)
+		return cmd
+	}
+	switch msg.String() {
+	case "/":
+		m.helpModel.Search()
+	case "ctrl+c":
+		return m.quit()
+	case "esc", "q", "?":
+		m.state = m.helpReturnTo
+		if m.state == stateLoading {
+			m.state = stateList
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the summary of filtered-out recovery points: when
+// the resource type filters (-type and the in-app "f" filter) or the status
+// filter hide every point the vault holds, the list says how many are
+// hidden and by what, e.g. "42 point(s) hidden by filters (RDS filter,
+// 3 DELETED)", instead of "No backups found", and "F" clears the filters.
+package app
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// hiddenSummary describes the recovery points the filters hide when they
+// hide all of them, or returns "" if the list shows any or the vault holds
+// none.
+func (m *Model) hiddenSummary() string {
+	if len(m.backups) > 0 {
+		return ""
+	}
+	hidden := m.listing.Hidden() + len(m.allBackups)
+	for _, rp := range m.allBackups {
+		if m.deletes.holds(rp.RecoveryPointARN) {
+			hidden-- // Pending deletion, not filtered out
+		}
+	}
+	if hidden == 0 {
+		return ""
+	}
+
+	var filters []string
+	if len(m.resourceTypes) > 0 && len(m.listing.HiddenByType) > 0 {
+		filters = append(filters, strings.Join(m.resourceTypes, "/")+" filter")
+	}
+	if m.activeFilter != filterAll && len(m.allBackups) > 0 {
+		filters = append(filters, m.activeFilter.String()+" filter")
+	}
+	for _, status := range slices.Sorted(maps.Keys(m.listing.HiddenByStatus)) {
+		filters = append(filters, fmt.Sprintf("%d %s", m.listing.HiddenByStatus[status], status))
+	}
+	return fmt.Sprintf("%d point(s) hidden by filters (%s)", hidden, strings.Join(filters, ", "))
+}
+
+// filtersClearable reports whether a resource type filter is set that "F"
+// can clear. Points hidden by status cannot be shown.
+func (m *Model) filtersClearable() bool {
+	return len(m.resourceTypes) > 0 || m.activeFilter != filterAll
+}
+
+// clearFilters clears the in-app filter and the -type filter. The vault is
+// listed again when the -type filter was set, since its points were never
+// loaded.
+func (m *Model) clearFilters() tea.Cmd {
+	if !m.filtersClearable() {
+		return nil
+	}
+	m.activeFilter = filterAll
+	if len(m.resourceTypes) > 0 {
+		m.resourceTypes = nil
+		m.state = stateLoading
+		m.inform("Filters cleared, reloading backups...")
+		return tea.Batch(m.loadBackups(), m.tickSpinner())
+	}
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+	m.inform("Filters cleared")
+	return nil
+}
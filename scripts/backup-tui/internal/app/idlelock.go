@@ -0,0 +1,172 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the idle lock: after the config file's idleLock (or
+// -lock-after) without a key press, the screen is replaced by a lock panel
+// so a healthcare system's backup inventory does not stay visible on an
+// unattended workstation. Resuming takes a key press and a confirmation,
+// and the AWS credentials are checked again first. Restores, polling, and
+// refreshes carry on while the screen is locked. Quitting from the lock
+// panel with deletions pending asks whether to send or discard them.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// idleLockState is the state of the idle lock.
+type idleLockState struct {
+	after     time.Duration // Idle time before locking (0 never locks)
+	lastInput time.Time
+	locked    bool
+	confirm   bool  // Asked to confirm resuming
+	quitting  bool  // Asked whether to send or discard pending deletions
+	checking  bool  // Credentials being checked
+	err       error // Why the last resume was refused
+}
+
+// idleTickMsg is sent when the session may have been idle long enough to
+// lock.
+type idleTickMsg struct{}
+
+// idleUnlockMsg is sent when the credentials have been checked for
+// resuming a locked session.
+type idleUnlockMsg struct {
+	err error
+}
+
+// scheduleIdleCheck returns a command that checks for idleness once the
+// lock could next be due, or nil when the lock is off.
+func (m *Model) scheduleIdleCheck() tea.Cmd {
+	v := &m.idle
+	if v.after <= 0 {
+		return nil
+	}
+	if v.lastInput.IsZero() {
+		v.lastInput = time.Now()
+	}
+	return tea.Tick(v.after-time.Since(v.lastInput), func(time.Time) tea.Msg { return idleTickMsg{} })
+}
+
+// handleIdleTick locks the screen when there has been no input for the
+// idle time, or checks again when it would be due.
+func (m *Model) handleIdleTick() tea.Cmd {
+	v := &m.idle
+	if v.locked {
+		return nil
+	}
+	if time.Since(v.lastInput) < v.after {
+		return m.scheduleIdleCheck()
+	}
+	v.locked, v.confirm, v.err = true, false, nil
+	return nil
+}
+
+// noteInput records operator input, which postpones the lock.
+func (m *Model) noteInput() {
+	m.idle.lastInput = time.Now()
+}
+
+// updateIdleLock handles key presses on the lock panel: any key asks to
+// confirm resuming, and y resumes once the credentials check out.
+func (m *Model) updateIdleLock(msg tea.KeyPressMsg) tea.Cmd {
+	v := &m.idle
+	key := msg.String()
+	switch {
+	case key == "ctrl+c" && len(m.deletes.pending) > 0 && !v.quitting:
+		v.quitting = true
+	case key == "ctrl+c":
+		return tea.Quit
+	case v.quitting:
+		switch key {
+		case "s", "S":
+			return m.sendDeletesAndQuit()
+		case "d", "D":
+			return tea.Quit
+		case "esc", "n", "N":
+			v.quitting = false
+		}
+	case v.checking:
+		return nil
+	case !v.confirm:
+		v.confirm, v.err = true, nil
+	case key == "y" || key == "Y":
+		v.checking = true
+		client, ctx := m.backupClient, m.ctx
+		return func() tea.Msg {
+			return idleUnlockMsg{err: client.CheckIdentity(ctx)}
+		}
+	case key == "n" || key == "N" || key == "esc":
+		v.confirm = false
+	}
+	return nil
+}
+
+// handleIdleUnlock resumes the session if the credentials checked out.
+func (m *Model) handleIdleUnlock(msg idleUnlockMsg) tea.Cmd {
+	v := &m.idle
+	v.checking, v.confirm = false, false
+	if msg.err != nil {
+		v.err = msg.err
+		m.logError("Locked session not resumed", msg.err)
+		return nil
+	}
+	v.locked, v.err = false, nil
+	m.noteInput()
+	return m.scheduleIdleCheck()
+}
+
+// renderIdleLock renders the lock panel, which shows nothing of the
+// session but who it belongs to.
+func (m *Model) renderIdleLock() string {
+	v := m.idle
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		Margin(1, 2)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	errStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+
+	identity := "this session's AWS identity"
+	if m.backupClient != nil && m.backupClient.CallerARN() != "" {
+		identity = m.backupClient.CallerARN()
+	}
+	lines := []string{
+		titleStyle.Render("🔒 Session Locked"),
+		"",
+		fmt.Sprintf("Locked after %s without input.", v.after),
+		dimStyle.Render("Restores and refreshes continue in the background."),
+		"",
+	}
+	switch {
+	case v.quitting:
+		// The backups stay unnamed, as the rest of the session while locked
+		n, vaults := 0, map[string]bool{}
+		for _, p := range m.deletes.pending {
+			n += len(p.targets)
+			vaults[p.vault] = true
+		}
+		lines = append(lines,
+			fmt.Sprintf("%d backup(s) in %d vault(s) are pending deletion; quitting now would discard them.", n, len(vaults)),
+			"",
+			dimStyle.Render("s send them now and quit  d discard them and quit  esc stay locked"))
+	case v.checking:
+		lines = append(lines, "Checking the AWS credentials...")
+	case v.confirm:
+		lines = append(lines, fmt.Sprintf("Resume as %s?", identity), "", dimStyle.Render("y resume  n stay locked  ctrl+c quit"))
+	default:
+		lines = append(lines, dimStyle.Render("Press any key to resume, or ctrl+c to quit."))
+	}
+	if v.err != nil {
+		lines = append(lines, "", errStyle.Render("✗ Not resumed: "+v.err.Error()))
+	}
+	return boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,130 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements importing jobs started elsewhere: a restore or backup
+// job ID from the console or CLI is entered (or pasted) in the jobs view and
+// tracked alongside the jobs started in-app, including resuming it after a
+// restart.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// jobImportedMsg is sent when an entered job ID has been looked up.
+type jobImportedMsg struct {
+	jobID  string
+	kind   string // aws.JobKindRestore or aws.JobKindBackup
+	status *aws.RestoreJobStatus
+	err    error
+}
+
+// startImportJob opens the job ID prompt.
+func (m *Model) startImportJob() {
+	m.importInput = ""
+	m.clearStatus()
+	m.state = stateImportJob
+}
+
+// updateImportJob handles key presses at the job ID prompt.
+func (m *Model) updateImportJob(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.state = stateJobs
+	case "enter":
+		jobID := strings.TrimSpace(m.importInput)
+		if jobID == "" {
+			return m, nil
+		}
+		m.state = stateJobs
+		if m.jobByID(jobID) != nil {
+			m.inform(fmt.Sprintf("Job %s is already tracked", jobID))
+			return m, nil
+		}
+		m.inform(fmt.Sprintf("Looking up job %s...", jobID))
+		return m, m.lookupJob(jobID)
+	case "backspace":
+		if r := []rune(m.importInput); len(r) > 0 {
+			m.importInput = string(r[:len(r)-1])
+		}
+	default:
+		if msg.Text != "" {
+			m.importInput += msg.Text
+		}
+	}
+	return m, nil
+}
+
+// lookupJob returns a command that finds a job by ID.
+func (m *Model) lookupJob(jobID string) tea.Cmd {
+	client := m.backupClient
+	return func() tea.Msg {
+		kind, status, err := client.LookupJob(m.ctx, jobID)
+		return jobImportedMsg{jobID: jobID, kind: kind, status: status, err: err}
+	}
+}
+
+// handleJobImported adds a looked-up job to the jobs view, polling it until
+// it finishes and saving it for resuming like a restore started in-app.
+func (m *Model) handleJobImported(msg jobImportedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.reportError(fmt.Sprintf("Import failed: %v", msg.err), msg.err)
+		return nil
+	}
+	if m.jobByID(msg.jobID) != nil {
+		return nil
+	}
+
+	st := msg.status
+	job := m.addJob(aws.RecoveryPoint{
+		RecoveryPointARN: st.RecoveryPointARN,
+		ResourceType:     st.ResourceType,
+		ResourceID:       st.ResourceID,
+	}, nil)
+	job.kind, job.jobID, job.status, job.started, job.imported = msg.kind, msg.jobID, st, st.CreatedAt, true
+	m.jobsCursor = len(m.jobs) - 1
+
+	if st.IsTerminal {
+		job.state = jobFailed
+		if st.Status == "COMPLETED" {
+			job.state = jobCompleted
+		} else {
+			job.note = st.StatusMessage
+		}
+		m.inform(fmt.Sprintf("Imported %s #%d: already %s", job.kind, job.seq, st.Status))
+		return nil
+	}
+
+	job.state = jobActive
+	m.recordJob(job)
+	m.inform(fmt.Sprintf("Imported %s #%d: tracking until it finishes", job.kind, job.seq))
+	return m.pollRestoreStatus(job.jobID)
+}
+
+// renderImportJob renders the job ID prompt.
+func (m *Model) renderImportJob() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2)
+	labelStyle := lipgloss.NewStyle().Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+
+	lines := []string{
+		labelStyle.Render("Import job"),
+		"",
+		"Restore or backup job ID started from the console or CLI:",
+		"> " + m.importInput + "█",
+		"",
+		dimStyle.Render("The job must be in " + m.region + ". Paste with your terminal's paste shortcut."),
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
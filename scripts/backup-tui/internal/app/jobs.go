@@ -0,0 +1,373 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the jobs view and restore chaining: a restore can be
+// queued to start automatically once the restore before it reaches
+// COMPLETED (e.g. restore EFS only after the database restore succeeds).
+// Queued steps can be cancelled until they start.
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// jobState is the lifecycle of a restore job tracked by the TUI.
+type jobState int
+
+const (
+	jobQueued    jobState = iota // Waiting for the previous step to complete
+	jobStarting                  // StartRestoreJob in flight
+	jobActive                    // Started on AWS; polled until terminal
+	jobCompleted                 // AWS reported COMPLETED
+	jobFailed                    // Failed to start, or AWS reported FAILED/ABORTED
+	jobCancelled                 // Queued step cancelled by the operator
+	jobSkipped                   // Queued step not started because the previous step failed
+)
+
+func (s jobState) String() string {
+	switch s {
+	case jobQueued:
+		return "QUEUED"
+	case jobStarting:
+		return "STARTING"
+	case jobActive:
+		return "ACTIVE"
+	case jobCompleted:
+		return "COMPLETED"
+	case jobFailed:
+		return "FAILED"
+	case jobCancelled:
+		return "CANCELLED"
+	default:
+		return "SKIPPED"
+	}
+}
+
+// pending reports whether the job has not reached a final state.
+func (s jobState) pending() bool {
+	return s == jobQueued || s == jobStarting || s == jobActive
+}
+
+// restoreJob is a job tracked in the jobs view: a restore started (or
+// queued) from this session, or a restore or backup job resumed from an
+// earlier session or imported by job ID.
+type restoreJob struct {
+	kind     string            // aws.JobKindRestore, JobKindExport, JobKindClone, or JobKindBackup for imported backups
+	seq      int               // 1-based number shown in the jobs view
+	backup   aws.RecoveryPoint // Recovery point being restored
+	options  aws.RestoreOptions
+	after    *restoreJob // Step that must complete first (nil for the first step)
+	jobID    string      // AWS Backup restore job ID, once started
+	state    jobState
+	status   *aws.RestoreJobStatus // Last polled status
+	note     string                // Why the job failed, was skipped, or was cancelled
+	progress string                // Step being performed while starting
+	started  time.Time             // When StartRestoreJob was called
+	resumed  bool                  // Started in an earlier session and resumed from the job history
+	imported bool                  // Started outside the TUI and imported by job ID
+	workflow string                // ID of the saved workflow of the job's chain ("" when not chained)
+	cleaning bool                  // Leftover resources being deleted
+	cleaned  bool                  // Leftover resources deleted
+}
+
+// noun names the job's kind in status messages, e.g. "Restore".
+func (j *restoreJob) noun() string {
+	switch j.kind {
+	case aws.JobKindBackup:
+		return "Backup"
+	case aws.JobKindExport:
+		return "Export"
+	case aws.JobKindClone:
+		return "Clone"
+	}
+	return "Restore"
+}
+
+// label is the state shown in the jobs view, using the AWS status once known.
+func (j *restoreJob) label() string {
+	if j.state == jobActive && j.status != nil {
+		if j.status.PercentDone != "" {
+			return fmt.Sprintf("%s %s%%", j.status.Status, j.status.PercentDone)
+		}
+		return j.status.Status
+	}
+	return j.state.String()
+}
+
+// depth is the number of steps before this one in its chain.
+func (j *restoreJob) depth() int {
+	d := 0
+	for p := j.after; p != nil; p = p.after {
+		d++
+	}
+	return d
+}
+
+// addJob records a new job for backup. A job with a predecessor is queued;
+// otherwise it is starting.
+func (m *Model) addJob(backup aws.RecoveryPoint, after *restoreJob) *restoreJob {
+	job := &restoreJob{kind: aws.JobKindRestore, seq: len(m.jobs) + 1, backup: backup, after: after, state: jobStarting}
+	if after != nil {
+		job.state = jobQueued
+	}
+	m.jobs = append(m.jobs, job)
+	return job
+}
+
+// jobBySeq returns the job with the given sequence number, or nil.
+func (m *Model) jobBySeq(seq int) *restoreJob {
+	if seq < 1 || seq > len(m.jobs) {
+		return nil
+	}
+	return m.jobs[seq-1]
+}
+
+// jobByID returns the started job with the given AWS job ID, or nil.
+func (m *Model) jobByID(jobID string) *restoreJob {
+	for _, j := range m.jobs {
+		if j.jobID != "" && j.jobID == jobID {
+			return j
+		}
+	}
+	return nil
+}
+
+// chainTail returns the most recent job that has not finished, which a new
+// restore is chained after, or nil when nothing is in progress. Exports and
+// clones run independently and are never chained after.
+func (m *Model) chainTail() *restoreJob {
+	for i := len(m.jobs) - 1; i >= 0; i-- {
+		if j := m.jobs[i]; j.state.pending() && j.kind != aws.JobKindExport && j.kind != aws.JobKindClone {
+			return m.jobs[i]
+		}
+	}
+	return nil
+}
+
+// queueRestore chains a restore of the selected backup after the most recent
+// unfinished restore and opens the jobs view.
+func (m *Model) queueRestore() {
+	tail := m.chainTail()
+	if tail == nil {
+		m.notify(SeverityWarn, "No restore in progress to chain after — press y to start now")
+		return
+	}
+	if m.selectedIdx >= len(m.backups) {
+		return
+	}
+	if refusal := m.freezeRefusal(); refusal != "" {
+		m.notify(SeverityWarn, refusal)
+		return
+	}
+	job := m.addJob(m.backups[m.selectedIdx], tail)
+	job.options = m.restoreOpts
+	m.chainWorkflow(job)
+	m.restoreMetadata = nil
+	m.jobsCursor = len(m.jobs) - 1
+	m.state = stateJobs
+	m.inform(fmt.Sprintf("Restore #%d queued: starts when #%d completes", job.seq, tail.seq))
+}
+
+// advanceChain starts or skips the steps queued after a job that reached a
+// terminal state.
+func (m *Model) advanceChain(done *restoreJob) []tea.Cmd {
+	var cmds []tea.Cmd
+	for _, next := range m.jobs {
+		if next.after != done || next.state != jobQueued {
+			continue
+		}
+		if w := m.activeFreeze(); done.state == jobCompleted && w != nil {
+			next.state = jobSkipped
+			next.note = "change freeze " + w.String()
+			m.recordWorkflow(next)
+			cmds = append(cmds, m.advanceChain(next)...)
+			continue
+		}
+		if done.state == jobCompleted {
+			next.state = jobStarting
+			cmds = append(cmds, m.initiateRestore(next))
+			continue
+		}
+		next.state = jobSkipped
+		next.note = fmt.Sprintf("#%d did not complete", done.seq)
+		m.recordWorkflow(next)
+		cmds = append(cmds, m.advanceChain(next)...)
+	}
+	return cmds
+}
+
+// cancelJob cancels a queued step and every step chained after it. Steps
+// that have already started cannot be cancelled from here.
+func (m *Model) cancelJob(job *restoreJob) {
+	if job.state != jobQueued {
+		m.notify(SeverityWarn, fmt.Sprintf("Restore #%d is %s; only queued steps can be cancelled", job.seq, job.state))
+		return
+	}
+	job.state = jobCancelled
+	job.note = "cancelled before start"
+	cancelled := 1
+	for _, next := range m.jobs {
+		if next.state == jobQueued && next.after != nil && next.after.state == jobCancelled {
+			next.state = jobCancelled
+			next.note = fmt.Sprintf("#%d was cancelled", next.after.seq)
+			cancelled++
+		}
+	}
+	m.recordWorkflow(job)
+	m.inform(fmt.Sprintf("Cancelled %d queued restore(s)", cancelled))
+}
+
+// updateJobs handles key presses in the jobs view.
+func (m *Model) updateJobs(msg tea.KeyPressMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if m.jobsCursor > 0 {
+			m.jobsCursor--
+		}
+	case "down", "j":
+		if m.jobsCursor < len(m.jobs)+len(m.otherJobs())-1 {
+			m.jobsCursor++
+		}
+	case "x":
+		if job := m.jobBySeq(m.jobsCursor + 1); job != nil {
+			m.cancelJob(job)
+		}
+	case "D":
+		m.openCleanupConfirm()
+	case "i":
+		m.startImportJob()
+	case "V":
+		return m.verifySelectedJob()
+	case "r":
+		return m.loadStackJobs()
+	case "enter":
+		if other := m.otherJobs(); m.jobsCursor >= len(m.jobs) && m.jobsCursor-len(m.jobs) < len(other) {
+			return m.trackOtherJob(other[m.jobsCursor-len(m.jobs)])
+		}
+		job := m.jobBySeq(m.jobsCursor + 1)
+		if job == nil || job.jobID == "" {
+			return nil
+		}
+		m.restoreJobID = job.jobID
+		m.restoreStart = job.started
+		m.restoreStatus = job.status
+		m.state = stateRestoring
+		return m.tickSpinner()
+	}
+	return nil
+}
+
+// renderJobs renders the restore jobs of this session, drawing chained steps
+// under the step they wait for.
+func (m *Model) renderJobs() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	failStyle := infoStyle.Foreground(ui.ColorFail)
+
+	lines := []string{titleStyle.Render("Restore Jobs"), ""}
+	if len(m.jobs) == 0 {
+		lines = append(lines, dimStyle.Render("No restores started this session."))
+	}
+
+	for i, job := range m.jobs {
+		prefix := ""
+		if d := job.depth(); d > 0 {
+			prefix = strings.Repeat("    ", d-1) + "└─▶ "
+		}
+		line := fmt.Sprintf("%s#%d  %-3s  %-30s  %s", prefix, job.seq, job.backup.ResourceType, job.backup.ResourceID, job.label())
+
+		stateStyle := infoStyle
+		switch job.state {
+		case jobCompleted:
+			stateStyle = stateStyle.Foreground(ui.ColorOK)
+		case jobFailed, jobSkipped:
+			stateStyle = stateStyle.Foreground(ui.ColorFail)
+		case jobStarting, jobActive:
+			stateStyle = stateStyle.Foreground(ui.ColorWarn)
+		}
+		if i == m.jobsCursor {
+			line = focusStyle.Render("▸ " + line)
+		} else {
+			line = stateStyle.Render("  " + line)
+		}
+
+		detail := ""
+		switch {
+		case job.state == jobQueued:
+			detail = fmt.Sprintf("starts when #%d completes", job.after.seq)
+		case job.state == jobStarting && job.progress != "":
+			detail = job.progress
+		case job.note != "":
+			detail = job.note
+		case job.jobID != "":
+			detail = "job " + job.jobID
+			if job.kind != aws.JobKindRestore {
+				detail = job.kind + " " + detail
+			}
+			if job.resumed {
+				detail += " (resumed from an earlier session)"
+			} else if job.imported {
+				detail += " (imported)"
+			}
+		}
+		if detail != "" {
+			line += dimStyle.Render("  " + detail)
+		}
+		if r, ok := m.leftover(job); ok {
+			line += failStyle.Render(fmt.Sprintf("  left behind %s (D to delete)", r))
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "", titleStyle.Render(fmt.Sprintf("Started elsewhere — last %d days", int(stackJobsWindow.Hours()/24))), "")
+	other := m.otherJobs()
+	switch {
+	case m.stackJobs.err != nil:
+		lines = append(lines, failStyle.Render(fmt.Sprintf("Could not list the stack's jobs: %v", m.stackJobs.err)))
+	case m.stackJobs.loading && len(other) == 0:
+		lines = append(lines, dimStyle.Render("Loading the stack's backup and restore jobs..."))
+	case len(other) == 0:
+		lines = append(lines, dimStyle.Render("No other backup or restore jobs of the stack's resources."))
+	}
+	for i, j := range other {
+		line := fmt.Sprintf("%-7s  %-3s  %-30s  %-9s  %s", j.Kind, j.ResourceType, arnName(jobResource(j)), j.State, j.CreatedAt.Local().Format("2006-01-02 15:04"))
+		stateStyle := infoStyle
+		switch {
+		case j.Succeeded():
+			stateStyle = stateStyle.Foreground(ui.ColorOK)
+		case j.Failed():
+			stateStyle = failStyle
+		case j.CompletedAt.IsZero():
+			stateStyle = stateStyle.Foreground(ui.ColorWarn)
+		}
+		if len(m.jobs)+i == m.jobsCursor {
+			line = focusStyle.Render("▸ " + line)
+		} else {
+			line = stateStyle.Render("  " + line)
+		}
+		if j.Failed() && j.StatusMessage != "" {
+			line += dimStyle.Render("  " + j.StatusMessage)
+		}
+		lines = append(lines, line)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,235 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the shared operation journal: with a journal table in
+// the config file, each transition of a restore, export, or clone started
+// from the TUI is recorded in it, and "O" opens the team activity view, the
+// journal of the environment across every operator's sessions, newest first,
+// so whoever joins an incident sees what the team has already started.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// teamView is the state of the team activity view. It uses the vault
+// activity view's windows.
+type teamView struct {
+	window  int // Index into activityWindows
+	entries []aws.JournalEntry
+	err     error
+	loading bool
+	cursor  int
+}
+
+// teamActivityMsg is sent when the journal has been read.
+type teamActivityMsg struct {
+	env     string
+	entries []aws.JournalEntry
+	err     error
+}
+
+// journalRecordedMsg is sent when a job's transition has been recorded.
+type journalRecordedMsg struct {
+	seq   int
+	event string
+	err   error
+}
+
+// journalStep returns a command recording job's transition to event in the
+// journal, or nil when there is no journal or the job was started outside
+// the TUI, where whoever started it is unknown.
+func (m *Model) journalStep(j *restoreJob, event string) tea.Cmd {
+	table := m.config.JournalTable()
+	if table == "" || j.imported {
+		return nil
+	}
+	client := m.backupClient
+	e := aws.JournalEntry{
+		Env:              m.viewEnv(),
+		At:               time.Now(),
+		Operator:         client.CallerARN(),
+		Host:             hostName(),
+		Kind:             j.kind,
+		Event:            event,
+		ResourceType:     j.backup.ResourceType,
+		ResourceID:       j.backup.ResourceID,
+		RecoveryPointARN: j.backup.RecoveryPointARN,
+		JobID:            j.jobID,
+	}
+	if j.state == jobFailed {
+		e.Message = j.note
+	}
+	seq := j.seq
+	return func() tea.Msg {
+		return journalRecordedMsg{seq: seq, event: event, err: client.RecordOperation(m.ctx, table, e)}
+	}
+}
+
+// handleJournalRecorded logs a transition the journal did not receive.
+// The job itself carries on.
+func (m *Model) handleJournalRecorded(msg journalRecordedMsg) {
+	if msg.err != nil {
+		m.logError(fmt.Sprintf("Journal not told that #%d %s", msg.seq, msg.event), msg.err)
+	}
+}
+
+// openTeamActivity opens the team activity view and loads it.
+func (m *Model) openTeamActivity() tea.Cmd {
+	m.state = stateTeamActivity
+	m.team.cursor = 0
+	return m.loadTeamActivity()
+}
+
+// loadTeamActivity returns a command that reads the environment's journal
+// over the selected window, or nil when there is no journal.
+func (m *Model) loadTeamActivity() tea.Cmd {
+	table := m.config.JournalTable()
+	if table == "" || m.team.loading {
+		return nil
+	}
+	m.team.loading = true
+	client, env := m.backupClient, m.viewEnv()
+	since := time.Now().Add(-activityWindows[m.team.window])
+	return func() tea.Msg {
+		entries, err := client.TeamActivity(m.ctx, table, env, since)
+		return teamActivityMsg{env: env, entries: entries, err: err}
+	}
+}
+
+// handleTeamActivity stores the journal entries read.
+func (m *Model) handleTeamActivity(msg teamActivityMsg) {
+	m.team.loading = false
+	if msg.env != m.viewEnv() {
+		return // The vault was switched while loading
+	}
+	m.team.entries, m.team.err = msg.entries, msg.err
+	if msg.err != nil {
+		m.logError("Team activity not loaded", msg.err)
+	}
+	if m.team.cursor >= len(msg.entries) {
+		m.team.cursor = max(0, len(msg.entries)-1)
+	}
+}
+
+// updateTeamActivity handles key presses in the team activity view.
+func (m *Model) updateTeamActivity(msg tea.KeyPressMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if m.team.cursor > 0 {
+			m.team.cursor--
+		}
+	case "down", "j":
+		if m.team.cursor < len(m.team.entries)-1 {
+			m.team.cursor++
+		}
+	case "w":
+		m.team.window = (m.team.window + 1) % len(activityWindows)
+		m.team.cursor = 0
+		return m.loadTeamActivity()
+	case "r":
+		return m.loadTeamActivity()
+	}
+	return nil
+}
+
+// journalLine describes an entry after its time, e.g. "restore started
+// RDS openemr-db  alice on ops-1  job-1".
+func journalLine(e aws.JournalEntry) string {
+	who := "unknown"
+	if e.Operator != "" {
+		who = arnName(e.Operator)
+	}
+	if e.Host != "" {
+		who += " on " + e.Host
+	}
+	line := fmt.Sprintf("%-7s %-9s  %s %s  %s", e.Kind, e.Event, e.ResourceType, e.ResourceID, who)
+	if e.JobID != "" {
+		line += "  " + e.JobID
+	}
+	if e.Message != "" {
+		line += "  " + e.Message
+	}
+	return line
+}
+
+// renderTeamActivity renders the team activity view, grouped by day.
+func (m *Model) renderTeamActivity() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	dayStyle := lipgloss.NewStyle().Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("248")})
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	failStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+	doneStyle := lipgloss.NewStyle().Foreground(ui.ColorOK)
+
+	window := activityWindows[m.team.window]
+	title := "Team activity — last 24 hours"
+	if window > 24*time.Hour {
+		title = fmt.Sprintf("Team activity — last %d days", int(window.Hours()/24))
+	}
+	lines := []string{titleStyle.Render(title), dimStyle.Render("Operations started from every operator's backup-tui on " + m.viewEnv()), ""}
+
+	table := m.config.JournalTable()
+	switch {
+	case table == "":
+		lines = append(lines, dimStyle.Render("No shared journal: set \"journal\": {\"table\": ...} in the config file."))
+		return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+	case m.team.loading:
+		lines = append(lines, dimStyle.Render("Reading journal "+table+"..."))
+	case m.team.err != nil:
+		lines = append(lines, failStyle.Render("Unavailable: "+m.team.err.Error()))
+	case len(m.team.entries) == 0:
+		lines = append(lines, dimStyle.Render("Nobody started anything in this window."))
+	}
+
+	icons := map[string]string{config.EventStarted: "▶", config.EventCompleted: "✓", config.EventFailed: "✗"}
+	day := ""
+	for i, e := range m.team.entries {
+		local := e.At.Local()
+		if d := local.Format("Mon 2006-01-02"); d != day {
+			if day != "" {
+				lines = append(lines, "")
+			}
+			day = d
+			lines = append(lines, dayStyle.Render(day))
+		}
+
+		icon := icons[e.Event]
+		if icon == "" {
+			icon = "·"
+		}
+		line := fmt.Sprintf("%s  %s %s", local.Format("15:04"), icon, journalLine(e))
+		style := infoStyle
+		switch e.Event {
+		case config.EventFailed:
+			style = failStyle
+		case config.EventCompleted:
+			style = doneStyle
+		}
+		if i == m.team.cursor {
+			lines = append(lines, focusStyle.Render("▸ "+line))
+		} else {
+			lines = append(lines, style.Render("  "+line))
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,159 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the restore encryption key picker: from the
+// confirmation screen, "e" lists the region's KMS keys by alias so the
+// restored cluster or file system can be encrypted with a different key
+// than the backup, e.g. after the original key was scheduled for deletion.
+package app
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// kmsPicker is the state of the encryption key picker.
+type kmsPicker struct {
+	keys    []aws.KMSKey
+	err     error // Error from the last load
+	loading bool
+	cursor  int // 0 keeps the backup's key; i > 0 selects keys[i-1]
+}
+
+// kmsKeysMsg is sent when the KMS key list load completes.
+type kmsKeysMsg struct {
+	keys []aws.KMSKey
+	err  error
+}
+
+// openKMSPicker opens the key picker for the selected backup and starts
+// loading the keys unless they were prefetched with the detail view.
+func (m *Model) openKMSPicker() tea.Cmd {
+	if m.selectedIdx >= len(m.backups) {
+		return nil
+	}
+	resourceType := m.backups[m.selectedIdx].ResourceType
+	m.kmsPicker = kmsPicker{loading: true}
+	m.state = stateKMSPicker
+	if p := m.prefetched(); p != nil && p.kms != nil && p.kms.err == nil {
+		m.handleKMSKeys(*p.kms)
+		return nil
+	}
+	return m.loadKMSKeys(resourceType)
+}
+
+// loadKMSKeys returns a command that lists the keys a restore of
+// resourceType can be encrypted with.
+func (m *Model) loadKMSKeys(resourceType string) tea.Cmd {
+	client := m.backupClient
+	return func() tea.Msg {
+		keys, err := client.ListKMSKeys(m.ctx, resourceType)
+		return kmsKeysMsg{keys: keys, err: err}
+	}
+}
+
+// handleKMSKeys records the loaded keys and puts the cursor on the key
+// currently chosen, if any.
+func (m *Model) handleKMSKeys(msg kmsKeysMsg) {
+	m.kmsPicker.loading = false
+	m.kmsPicker.keys, m.kmsPicker.err = msg.keys, msg.err
+	m.kmsPicker.cursor = 0
+	for i, k := range msg.keys {
+		if k.KeyRef() == m.restoreOpts.KMSKeyID {
+			m.kmsPicker.cursor = i + 1
+		}
+	}
+}
+
+// updateKMSPicker handles key presses in the key picker.
+func (m *Model) updateKMSPicker(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	p := &m.kmsPicker
+	switch msg.String() {
+	case "esc", "q", "backspace":
+		m.state = stateConfirm
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.keys) {
+			p.cursor++
+		}
+	case "enter":
+		if p.loading {
+			return m, nil
+		}
+		m.setRestoreKey(p.cursor)
+		m.state = stateConfirm
+	}
+	return m, nil
+}
+
+// setRestoreKey applies the picker entry at idx to the pending restore.
+func (m *Model) setRestoreKey(idx int) {
+	m.restoreOpts.KMSKeyID = ""
+	m.inform("Restore keeps the backup's encryption key")
+	if idx > 0 && idx <= len(m.kmsPicker.keys) {
+		key := m.kmsPicker.keys[idx-1]
+		m.restoreOpts.KMSKeyID = key.KeyRef()
+		m.inform("Restore will be encrypted with " + key.Alias)
+	}
+	if m.restoreMetadata != nil {
+		m.restoreMetadata.ApplyOptions(m.restoreOpts)
+	}
+}
+
+// renderKMSPicker renders the key picker.
+func (m *Model) renderKMSPicker() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+
+	p := m.kmsPicker
+	lines := []string{titleStyle.Render("Restore Encryption Key"), ""}
+	switch {
+	case p.loading:
+		lines = append(lines, dimStyle.Render("Loading KMS keys..."))
+	case p.err != nil:
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("Could not list KMS keys: %v", p.err)))
+	}
+
+	entries := []string{"Keep the backup's key"}
+	for _, k := range p.keys {
+		entry := k.Alias
+		switch {
+		case k.AWSManaged:
+			entry += "  (AWS managed)"
+		case k.KeyID != "":
+			entry += "  " + k.KeyID
+		}
+		entries = append(entries, entry)
+	}
+	for i, entry := range entries {
+		if i == p.cursor {
+			lines = append(lines, focusStyle.Render("▸ "+entry))
+		} else {
+			lines = append(lines, infoStyle.Render("  "+entry))
+		}
+	}
+
+	if m.selectedIdx < len(m.backups) && m.backups[m.selectedIdx].ResourceType == "EFS" {
+		lines = append(lines, "", dimStyle.Render("EFS can only change keys on a new file system: choosing a key restores into one."))
+	}
+	lines = append(lines, "", dimStyle.Render("The restore role must be allowed to use the key (kms:CreateGrant, kms:Decrypt, kms:GenerateDataKey*)."))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
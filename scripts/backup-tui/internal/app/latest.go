@@ -0,0 +1,137 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the latest restorable point banner above the backup
+// list: for each resource, the newest backup that is completed, in warm
+// storage, and not failed by a restore test, so that the answer to "what
+// would we restore right now?" is always on screen. Resource types with
+// RPO/RTO targets in the config file are colored against them (targets.go).
+// Below them, the stack's Aurora cluster shows its own point-in-time
+// restore window and the gaps between its backups that the window covers.
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// recentRestoresMsg is sent when the recent restore jobs, with their
+// restore test results, have been loaded.
+type recentRestoresMsg struct {
+	restores []aws.JobRecord
+	err      error
+}
+
+// loadRecentRestores returns a command that loads the recent restore jobs.
+func (m *Model) loadRecentRestores() tea.Cmd {
+	client := m.backupClient
+	return func() tea.Msg {
+		restores, err := client.RecentRestores(m.ctx)
+		return recentRestoresMsg{restores: restores, err: err}
+	}
+}
+
+// handleRecentRestores records the recent restore jobs. Without them the
+// banner still shows the latest restorable points, just without restore
+// test results, so errors are not surfaced.
+func (m *Model) handleRecentRestores(msg recentRestoresMsg) {
+	if msg.err == nil {
+		m.recentRestores = msg.restores
+	}
+}
+
+// fetchPITRWindow returns a command that looks up the stack's cluster, for
+// its point-in-time restore window, when the vault has RDS backups; nil
+// otherwise.
+func (m *Model) fetchPITRWindow() tea.Cmd {
+	if m.stackName == "" || !slices.ContainsFunc(m.allBackups, func(rp aws.RecoveryPoint) bool { return rp.ResourceType == "RDS" }) {
+		return nil
+	}
+	return m.fetchClusterHealth()
+}
+
+// renderLatestBanner renders the latest restorable point of each resource
+// in the vault, or nothing when the vault has no backups.
+func (m *Model) renderLatestBanner() string {
+	latest := aws.LatestRestorable(m.allBackups, m.recentRestores, time.Now())
+	if len(latest) == 0 {
+		return ""
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	okStyle := lipgloss.NewStyle().Foreground(ui.ColorOK)
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorFail).Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+
+	lines := []string{labelStyle.Render("Latest restorable")}
+	for _, p := range latest {
+		if !p.Found() {
+			lines = append(lines, fmt.Sprintf("  %s %s",
+				infoStyle.Render(fmt.Sprintf("%-4s %s", p.ResourceType, p.ResourceID)),
+				warnStyle.Render("none: no completed backup in warm storage")))
+			continue
+		}
+		target := m.config.Target(p.ResourceType)
+		dot, rpoLabel := freshnessIndicator(p.CreatedAt), ""
+		if target.RPO > 0 {
+			dot, rpoLabel = rpoCompliance(p.CreatedAt, time.Duration(target.RPO), time.Now())
+		}
+		line := fmt.Sprintf("  %s %s", dot,
+			infoStyle.Render(fmt.Sprintf("%-4s %s  %s (%s)", p.ResourceType, p.ResourceID, p.CreatedAt.Format("2006-01-02 15:04"), relativeTime(p.CreatedAt))))
+		if rpoLabel != "" {
+			line += "  " + rpoLabel
+		}
+		if target.RTO > 0 {
+			line += "  " + rtoCompliance(p.ResourceType, m.recentRestores, time.Duration(target.RTO))
+		}
+		if p.Validated {
+			line += "  " + okStyle.Render("✓ restore tested")
+		}
+		if p.Skipped > 0 {
+			line += "  " + dimStyle.Render(fmt.Sprintf("%d newer not restorable", p.Skipped))
+		}
+		lines = append(lines, line)
+	}
+	if line := m.renderPITRWindow(); line != "" {
+		lines = append(lines, line)
+	}
+	return lipgloss.NewStyle().MarginBottom(1).Render(strings.Join(lines, "\n"))
+}
+
+// renderPITRWindow renders the cluster's point-in-time restore window and
+// what it adds to the backups, or nothing until the cluster has been
+// looked up.
+func (m *Model) renderPITRWindow() string {
+	h := m.cluster
+	if h == nil || h.EarliestRestorable.IsZero() {
+		return ""
+	}
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+
+	line := fmt.Sprintf("  ↺ %s", infoStyle.Render(fmt.Sprintf("%-4s %s  point-in-time: any moment %s – %s (%s)", "RDS", h.ClusterID,
+		h.EarliestRestorable.Format("2006-01-02 15:04"), h.LatestRestorable.Format("2006-01-02 15:04"), relativeTime(h.LatestRestorable))))
+	c := h.PITRCoverage(m.allBackups)
+	var notes []string
+	if c.Newer > 0 {
+		notes = append(notes, formatElapsed(c.Newer)+" past the latest backup")
+	}
+	if c.Gaps > 0 {
+		notes = append(notes, fmt.Sprintf("covers the %d gap(s) between its backups, longest %s", c.Gaps, formatElapsed(c.Longest)))
+	}
+	if len(notes) > 0 {
+		line += "  " + dimStyle.Render(strings.Join(notes, ", "))
+	}
+	return line
+}
@@ -0,0 +1,409 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements legal holds: backups are marked in the list with
+// space, "H" opens the region's legal holds, and from there a hold is placed
+// on the marked backups ("n") or an active hold is released with a reason
+// ("x"). Held backups cannot be deleted, by their lifecycle or by hand,
+// until every hold on them is released.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// legalHoldView is the state of the legal holds view and its prompts.
+type legalHoldView struct {
+	holds   []aws.LegalHold
+	err     error // Error from the last load
+	loading bool
+	cursor  int
+
+	// New hold and release prompts
+	title       string
+	description string
+	reason      string
+	field       int // New hold: 0 is the title, 1 the description
+	saving      bool
+	formErr     error
+}
+
+// legalHoldsMsg is sent when the legal holds have been listed.
+type legalHoldsMsg struct {
+	holds []aws.LegalHold
+	err   error
+}
+
+// legalHoldSavedMsg is sent when a hold has been created or released.
+type legalHoldSavedMsg struct {
+	verb  string // "placed" or "released"
+	title string
+	err   error
+}
+
+// toggleMark marks or unmarks the backup under the list cursor for a legal
+// hold or a tag edit.
+func (m *Model) toggleMark() {
+	idx := m.listModel.SelectedIndex()
+	if idx < 0 || idx >= len(m.backups) {
+		return
+	}
+	arn := m.backups[idx].RecoveryPointARN
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	if m.marked[arn] {
+		delete(m.marked, arn)
+	} else {
+		m.marked[arn] = true
+	}
+	m.listModel.SetItems(m.formatBackupsForList())
+	m.inform(fmt.Sprintf("%d backup(s) marked; press H to place a legal hold on them, # to edit their tags, or d to delete them", len(m.marked)))
+}
+
+// markedBackups returns the marked backups, including any hidden by the
+// current filter.
+func (m *Model) markedBackups() []aws.RecoveryPoint {
+	var marked []aws.RecoveryPoint
+	for _, rp := range m.allBackups {
+		if m.marked[rp.RecoveryPointARN] {
+			marked = append(marked, rp)
+		}
+	}
+	return marked
+}
+
+// actionTargets returns the backups an action of the list or detail view
+// applies to: in the list the marked backups, or the one under the cursor
+// when none are marked, and in the detail view the selected backup.
+func (m *Model) actionTargets() []aws.RecoveryPoint {
+	var targets []aws.RecoveryPoint
+	idx := m.listModel.SelectedIndex()
+	if m.state == stateDetail {
+		idx = m.selectedIdx
+	} else {
+		targets = m.markedBackups()
+	}
+	if len(targets) == 0 && idx >= 0 && idx < len(m.backups) {
+		targets = []aws.RecoveryPoint{m.backups[idx]}
+	}
+	return targets
+}
+
+// openLegalHolds opens the legal holds view and loads the holds.
+func (m *Model) openLegalHolds() tea.Cmd {
+	m.state = stateLegalHolds
+	m.legalHolds.cursor = 0
+	return m.loadLegalHolds()
+}
+
+// loadLegalHolds returns a command that lists the legal holds.
+func (m *Model) loadLegalHolds() tea.Cmd {
+	if m.legalHolds.loading {
+		return nil
+	}
+	m.legalHolds.loading = true
+	client := m.backupClient
+	return func() tea.Msg {
+		holds, err := client.ListLegalHolds(m.ctx)
+		return legalHoldsMsg{holds: holds, err: err}
+	}
+}
+
+// handleLegalHolds records the listed holds.
+func (m *Model) handleLegalHolds(msg legalHoldsMsg) {
+	v := &m.legalHolds
+	v.loading = false
+	v.holds, v.err = msg.holds, msg.err
+	if v.cursor >= len(v.holds) {
+		v.cursor = max(len(v.holds)-1, 0)
+	}
+}
+
+// updateLegalHolds handles key presses in the legal holds view.
+func (m *Model) updateLegalHolds(msg tea.KeyPressMsg) tea.Cmd {
+	v := &m.legalHolds
+	switch msg.String() {
+	case "up", "k":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down", "j":
+		if v.cursor < len(v.holds)-1 {
+			v.cursor++
+		}
+	case "r":
+		return m.loadLegalHolds()
+	case "n":
+		if len(m.markedBackups()) == 0 {
+			m.notify(SeverityWarn, "Mark the backups to hold with space in the backup list first")
+			return nil
+		}
+		v.title, v.description, v.field, v.formErr = "", "", 0, nil
+		m.state = stateHoldNew
+	case "x":
+		if v.cursor >= len(v.holds) || !v.holds[v.cursor].Active() {
+			m.notify(SeverityWarn, "Select an active legal hold to release")
+			return nil
+		}
+		v.reason, v.formErr = "", nil
+		m.state = stateHoldRelease
+	}
+	return nil
+}
+
+// updateHoldNew handles key presses in the new hold prompt.
+func (m *Model) updateHoldNew(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	v := &m.legalHolds
+	if v.saving {
+		return m, nil
+	}
+	field := &v.title
+	if v.field == 1 {
+		field = &v.description
+	}
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.state = stateLegalHolds
+	case "tab", "up", "down":
+		v.field = 1 - v.field
+	case "backspace":
+		if r := []rune(*field); len(r) > 0 {
+			*field = string(r[:len(r)-1])
+		}
+	case "enter":
+		title, description := strings.TrimSpace(v.title), strings.TrimSpace(v.description)
+		if title == "" || description == "" {
+			v.formErr = fmt.Errorf("enter a title and a description (e.g. the matter and why the backups are held)")
+			return m, nil
+		}
+		v.saving, v.formErr = true, nil
+		client, vaultName, marked := m.backupClient, m.vaultName, m.markedBackups()
+		return m, func() tea.Msg {
+			_, err := client.CreateLegalHold(m.ctx, vaultName, title, description, marked)
+			return legalHoldSavedMsg{verb: "placed", title: title, err: err}
+		}
+	default:
+		if msg.Text != "" {
+			*field += msg.Text
+		}
+	}
+	return m, nil
+}
+
+// updateHoldRelease handles key presses in the release prompt.
+func (m *Model) updateHoldRelease(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	v := &m.legalHolds
+	if v.saving || v.cursor >= len(v.holds) {
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.state = stateLegalHolds
+	case "backspace":
+		if r := []rune(v.reason); len(r) > 0 {
+			v.reason = string(r[:len(r)-1])
+		}
+	case "enter":
+		reason := strings.TrimSpace(v.reason)
+		if reason == "" {
+			v.formErr = fmt.Errorf("enter why the hold is released (e.g. the matter was closed)")
+			return m, nil
+		}
+		v.saving, v.formErr = true, nil
+		client, hold := m.backupClient, v.holds[v.cursor]
+		return m, func() tea.Msg {
+			err := client.ReleaseLegalHold(m.ctx, hold.ID, reason)
+			return legalHoldSavedMsg{verb: "released", title: hold.Title, err: err}
+		}
+	default:
+		if msg.Text != "" {
+			v.reason += msg.Text
+		}
+	}
+	return m, nil
+}
+
+// handleLegalHoldSaved returns to the holds view and reloads it, or shows
+// the error in the prompt.
+func (m *Model) handleLegalHoldSaved(msg legalHoldSavedMsg) tea.Cmd {
+	v := &m.legalHolds
+	v.saving = false
+	if msg.err != nil {
+		v.formErr = msg.err
+		m.reportError(fmt.Sprintf("Legal hold not %s: %v", msg.verb, msg.err), msg.err)
+		return nil
+	}
+	if msg.verb == "placed" {
+		clear(m.marked)
+		m.listModel.SetItems(m.formatBackupsForList())
+	}
+	if m.state == stateHoldNew || m.state == stateHoldRelease {
+		m.state = stateLegalHolds
+	}
+	m.inform(fmt.Sprintf("Legal hold %q %s", msg.title, msg.verb))
+	return m.loadLegalHolds()
+}
+
+// renderLegalHolds renders the legal holds view.
+func (m *Model) renderLegalHolds() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	activeStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn).Bold(true)
+
+	v := m.legalHolds
+	lines := []string{titleStyle.Render(fmt.Sprintf("Legal Holds (%s)", m.region)), ""}
+	switch {
+	case v.loading && len(v.holds) == 0:
+		lines = append(lines, dimStyle.Render("Loading legal holds..."))
+	case v.err != nil:
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("Could not list legal holds: %v", v.err)))
+	case len(v.holds) == 0:
+		lines = append(lines, dimStyle.Render("No legal holds in this region."))
+	}
+
+	for i, h := range v.holds {
+		status := dimStyle.Render(fmt.Sprintf("%-9s", h.Status))
+		detail := fmt.Sprintf("created %s", h.Created.Format("2006-01-02"))
+		if h.Active() {
+			status = activeStyle.Render(fmt.Sprintf("%-9s", h.Status))
+			detail += fmt.Sprintf(", %d backup(s) held", len(h.RecoveryPoints))
+		} else if !h.Cancelled.IsZero() {
+			detail += ", released " + h.Cancelled.Format("2006-01-02")
+		}
+		row := fmt.Sprintf("%s  %-32.32s %s", status, h.Title, detail)
+		if i == v.cursor {
+			lines = append(lines, focusStyle.Render("▸ ")+row)
+			if h.Description != "" {
+				lines = append(lines, dimStyle.Render("    "+h.Description))
+			}
+		} else {
+			lines = append(lines, "  "+row)
+		}
+	}
+
+	marked := len(m.markedBackups())
+	lines = append(lines, "", dimStyle.Render(fmt.Sprintf("%d backup(s) marked in the list (space).", marked)))
+	lines = append(lines, dimStyle.Render("Held backups cannot be deleted by their lifecycle or by hand until every hold on them is released."))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
+
+// renderHoldNew renders the new hold prompt with the backups it will cover.
+func (m *Model) renderHoldNew() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorWarn).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.ColorWarn).
+		Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	errStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+
+	v := m.legalHolds
+	input := func(idx int, label, value string) string {
+		if idx == v.field {
+			return focusStyle.Render(fmt.Sprintf("▸ %-12s %s█", label, value))
+		}
+		return infoStyle.Render(fmt.Sprintf("  %-12s %s", label, value))
+	}
+
+	marked := m.markedBackups()
+	covered := aws.LegalHoldCoverage(m.allBackups, marked)
+	lines := []string{
+		titleStyle.Render("Place Legal Hold"),
+		"",
+		input(0, "Title:", v.title),
+		input(1, "Description:", v.description),
+		"",
+		infoStyle.Render(fmt.Sprintf("Covers %d backup(s) in %s:", len(covered), m.vaultName)),
+	}
+	for _, rp := range covered {
+		line := fmt.Sprintf("  %s %s | %s", rp.ResourceType, rp.ResourceID, rp.CreationDate.Format("2006-01-02 15:04:05"))
+		if !m.marked[rp.RecoveryPointARN] {
+			line += "  (not marked: same resource, between the marked dates)"
+		}
+		lines = append(lines, infoStyle.Render(line))
+	}
+	switch {
+	case v.saving:
+		lines = append(lines, "", dimStyle.Render("Placing hold..."))
+	case v.formErr != nil:
+		lines = append(lines, "", errStyle.Render(v.formErr.Error()))
+	}
+	lines = append(lines, "",
+		dimStyle.Render("Legal holds select by resource and date range, so every backup of the marked resources between"),
+		dimStyle.Render("the earliest and latest marked backup is held. The hold lasts until it is released."))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
+
+// renderHoldRelease renders the release prompt.
+func (m *Model) renderHoldRelease() string {
+	header := m.renderHeader()
+	v := m.legalHolds
+	if v.cursor >= len(v.holds) {
+		return lipgloss.JoinVertical(lipgloss.Left, header, "No legal hold selected")
+	}
+	h := v.holds[v.cursor]
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorFail).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.ColorFail).
+		Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	labelStyle := lipgloss.NewStyle().Bold(true)
+
+	lines := []string{
+		titleStyle.Render("Release Legal Hold"),
+		"",
+		infoStyle.Render("Hold:     " + h.Title),
+		infoStyle.Render(fmt.Sprintf("Backups:  %d held", len(h.RecoveryPoints))),
+		"",
+		labelStyle.Render("Reason for releasing (recorded with the hold):"),
+		"> " + v.reason + "█",
+	}
+	switch {
+	case v.saving:
+		lines = append(lines, "", dimStyle.Render("Releasing hold..."))
+	case v.formErr != nil:
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(ui.ColorFail).Render(v.formErr.Error()))
+	}
+	lines = append(lines, "",
+		dimStyle.Render("Once released, the backups' retention applies again: any past their deletion date are deleted."))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
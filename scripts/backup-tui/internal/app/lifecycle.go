@@ -0,0 +1,236 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the per-backup retention editor: "l" in the detail
+// view changes when AWS Backup deletes (and, for EFS, tiers to cold storage)
+// the selected recovery point, e.g. to keep a pre-incident backup beyond the
+// plan's deletion date.
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// lifecycleEditor is the state of the retention editor. Empty fields mean
+// never.
+type lifecycleEditor struct {
+	deleteDays string
+	coldDays   string
+	field      int // 0 is delete after, 1 is move to cold storage after
+	saving     bool
+	err        error // Validation or update error, shown in the editor
+}
+
+// lifecycleUpdatedMsg is sent when a recovery point's lifecycle update
+// completes.
+type lifecycleUpdatedMsg struct {
+	rp  aws.RecoveryPoint
+	err error
+}
+
+// openLifecycleEdit opens the retention editor for the selected backup,
+// filled in with its current lifecycle.
+func (m *Model) openLifecycleEdit() {
+	if m.selectedIdx >= len(m.backups) {
+		return
+	}
+	l := m.backups[m.selectedIdx].Lifecycle
+	m.lifecycleEdit = lifecycleEditor{deleteDays: daysField(l.DeleteAfterDays), coldDays: daysField(l.MoveToColdStorageAfterDays)}
+	m.state = stateLifecycle
+}
+
+// daysField formats days for an editor field: empty for never.
+func daysField(days int64) string {
+	if days <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(days, 10)
+}
+
+// updateLifecycleEdit handles key presses in the retention editor.
+func (m *Model) updateLifecycleEdit(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	e := &m.lifecycleEdit
+	if m.selectedIdx >= len(m.backups) {
+		m.state = stateDetail
+		return m, nil
+	}
+	if e.saving {
+		return m, nil
+	}
+	rp := m.backups[m.selectedIdx]
+	field := &e.deleteDays
+	if e.field == 1 {
+		field = &e.coldDays
+	}
+
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.state = stateDetail
+	case "tab", "up", "down":
+		if aws.SupportsColdStorage(rp.ResourceType) {
+			e.field = 1 - e.field
+		}
+	case "backspace":
+		if len(*field) > 0 {
+			*field = (*field)[:len(*field)-1]
+		}
+	case "enter":
+		l, err := e.lifecycle()
+		if err == nil {
+			err = m.vaultInfo.CheckRetention(l)
+		}
+		if e.err = err; err != nil {
+			return m, nil
+		}
+		e.saving = true
+		return m, m.updateLifecycle(rp, l)
+	default:
+		if len(msg.Text) == 1 && msg.Text[0] >= '0' && msg.Text[0] <= '9' && len(*field) < 5 {
+			*field += msg.Text
+		}
+	}
+	return m, nil
+}
+
+// lifecycle parses the editor fields.
+func (e lifecycleEditor) lifecycle() (aws.Lifecycle, error) {
+	var l aws.Lifecycle
+	var err error
+	if s := strings.TrimSpace(e.deleteDays); s != "" {
+		if l.DeleteAfterDays, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return l, fmt.Errorf("delete after: %w", err)
+		}
+	}
+	if s := strings.TrimSpace(e.coldDays); s != "" {
+		if l.MoveToColdStorageAfterDays, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return l, fmt.Errorf("move to cold storage after: %w", err)
+		}
+	}
+	return l, l.Validate()
+}
+
+// updateLifecycle returns a command that applies l to rp, unless it would
+// delete rp sooner and the config file protects it.
+func (m *Model) updateLifecycle(rp aws.RecoveryPoint, l aws.Lifecycle) tea.Cmd {
+	client, vaultName, cfg := m.backupClient, m.vaultName, m.config
+	details := m.enrich.details[rp.RecoveryPointARN]
+	return func() tea.Msg {
+		if schedulesDeletion(rp, l) {
+			if err := checkProtected(m.ctx, client, cfg, vaultName, rp, details); err != nil {
+				return lifecycleUpdatedMsg{rp: rp, err: err}
+			}
+		}
+		updated, err := client.UpdateLifecycle(m.ctx, vaultName, rp, l)
+		return lifecycleUpdatedMsg{rp: updated, err: err}
+	}
+}
+
+// handleLifecycleUpdated replaces the updated backup in the lists, or shows
+// the error in the editor.
+func (m *Model) handleLifecycleUpdated(msg lifecycleUpdatedMsg) {
+	m.lifecycleEdit.saving = false
+	if msg.err != nil {
+		m.lifecycleEdit.err = msg.err
+		if m.state != stateLifecycle {
+			m.reportError(fmt.Sprintf("Retention update failed: %v", msg.err), msg.err)
+		}
+		return
+	}
+	for _, list := range [][]aws.RecoveryPoint{m.allBackups, m.backups} {
+		for i := range list {
+			if list[i].RecoveryPointARN == msg.rp.RecoveryPointARN {
+				list[i] = msg.rp
+			}
+		}
+	}
+	if m.state == stateLifecycle {
+		m.state = stateDetail
+	}
+	m.inform(fmt.Sprintf("Retention of %s updated: %s", msg.rp.ResourceID, msg.rp.Lifecycle))
+}
+
+// renderLifecycleEdit renders the retention editor.
+func (m *Model) renderLifecycleEdit() string {
+	header := m.renderHeader()
+	if m.selectedIdx >= len(m.backups) {
+		return lipgloss.JoinVertical(lipgloss.Left, header, "No backup selected")
+	}
+	rp := m.backups[m.selectedIdx]
+	e := m.lifecycleEdit
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	errStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+
+	input := func(idx int, label, value string) string {
+		if idx == e.field {
+			return focusStyle.Render(fmt.Sprintf("▸ %-32s %s█ days", label, value))
+		}
+		if value == "" {
+			value = "never"
+		}
+		return infoStyle.Render(fmt.Sprintf("  %-32s %s days", label, value))
+	}
+
+	lines := []string{
+		titleStyle.Render("Change Retention"),
+		"",
+		infoStyle.Render(fmt.Sprintf("Backup:   %s (%s)", rp.ResourceID, rp.CreationDate.Format("2006-01-02 15:04:05 MST"))),
+		infoStyle.Render("Current:  " + rp.Lifecycle.String()),
+		"",
+		input(0, "Delete after (from creation)", e.deleteDays),
+	}
+	if aws.SupportsColdStorage(rp.ResourceType) {
+		lines = append(lines, input(1, "Move to cold storage after", e.coldDays))
+	}
+	protectedUntil := m.vaultInfo.ProtectedUntil(rp)
+	if l, err := e.lifecycle(); err == nil {
+		if deleteAt := l.DeleteAt(rp.CreationDate); deleteAt.Before(protectedUntil) && !deleteAt.IsZero() {
+			lines = append(lines, "", errStyle.Render(fmt.Sprintf("Deleted on %s: too soon, Vault Lock keeps this backup until %s",
+				deleteAt.Format("2006-01-02"), protectedUntil.Format("2006-01-02"))))
+		} else if !deleteAt.IsZero() {
+			lines = append(lines, "", dimStyle.Render("Deleted on "+deleteAt.Format("2006-01-02")))
+		} else {
+			lines = append(lines, "", dimStyle.Render("Kept until the retention is changed again or it is deleted by hand"))
+		}
+	}
+	if rule := m.protectionRule(rp); rule != nil {
+		lines = append(lines, "", errStyle.Render("Protected by config rule "+rule.String()+": retention can be extended, not shortened"))
+	}
+	switch {
+	case e.saving:
+		lines = append(lines, "", dimStyle.Render("Updating..."))
+	case e.err != nil:
+		lines = append(lines, "", errStyle.Render(e.err.Error()))
+	}
+	lines = append(lines, "",
+		dimStyle.Render("Leave a field empty for never. This changes only this backup; the plan's rule is unchanged."))
+	if v := m.vaultInfo; v != nil && v.Locked && (v.MinRetentionDays > 0 || v.MaxRetentionDays > 0) {
+		limits := []string{}
+		if v.MinRetentionDays > 0 {
+			limits = append(limits, fmt.Sprintf("at least %d days", v.MinRetentionDays))
+		}
+		if v.MaxRetentionDays > 0 {
+			limits = append(limits, fmt.Sprintf("at most %d days", v.MaxRetentionDays))
+		}
+		lines = append(lines, dimStyle.Render("Vault Lock requires retention of "+strings.Join(limits, " and ")+"."))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -5,7 +5,10 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,7 +16,10 @@ import (
 	"charm.land/lipgloss/v2"
 	"charm.land/lipgloss/v2/compat"
 	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
 	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/webhook"
 )
 
 // Model represents the main application state and implements the Bubbletea Model interface.
@@ -26,19 +32,22 @@ import (
 // - Messages are used to communicate results back to the model
 type Model struct {
 	// Configuration: User-provided or discovered configuration
-	ctx          context.Context // Context for cancellation and timeout control
-	stackName    string          // CloudFormation stack name (e.g., "OpenemrEcsStack")
-	vaultName    string          // Backup vault name (auto-discovered if not provided)
-	region       string          // AWS region (e.g., "us-west-2")
-	resourceType string          // Optional filter: "RDS", "EFS", or "" for all
+	ctx           context.Context // Context for cancellation and timeout control
+	stackName     string          // CloudFormation stack name (e.g., "OpenemrEcsStack")
+	vaultName     string          // Backup vault name (auto-discovered if not provided)
+	region        string          // AWS region (e.g., "us-west-2")
+	regionSource  string          // Where the region came from (flag, AWS_REGION, AWS_DEFAULT_REGION, shared config, prompt)
+	resourceTypes []string        // Optional AWS Backup resource type filter, e.g. ["RDS", "EFS"], or nil for all
 
 	// UI state: Current view and component state
-	state       state          // Current application state (loading, list, detail, confirm, help, error, restoring)
-	listModel   ui.ListModel   // List view component for displaying backups
-	detailModel ui.DetailModel // Detail view component for backup information
-	helpModel   ui.HelpModel   // Help screen component
-	statusMsg   string         // Status message displayed in status bar
-	err         error          // Error state (nil when no error)
+	state        state          // Current application state (loading, list, detail, confirm, help, error, restoring)
+	listModel    ui.ListModel   // List view component for displaying backups
+	detailModel  ui.DetailModel // Detail view component for backup information
+	helpModel    ui.HelpModel   // Help screen component
+	helpReturnTo state          // View the help overlay returns to
+	height       int            // Terminal height in lines (0 until the first resize)
+	warnings     warnings       // Messages for the operator; the current one is in the status bar
+	err          error          // Error state (nil when no error)
 
 	// Spinner state for loading animation
 	spinnerFrame int
@@ -47,21 +56,169 @@ type Model struct {
 	backupClient *aws.BackupClient // AWS Backup service client and related services
 
 	// Data: Application data and selections
-	backups         []aws.RecoveryPoint // Cached list of recovery points
-	allBackups      []aws.RecoveryPoint // Unfiltered list (before in-app filter)
-	selectedIdx     int                 // Index of currently selected backup in backups slice
-	vaultDiscovered bool                // Whether vault discovery has completed
+	backups         []aws.RecoveryPoint      // Cached list of recovery points
+	allBackups      []aws.RecoveryPoint      // Unfiltered list (before in-app filter)
+	listing         aws.RecoveryPointListing // Counts of the points the last listing left out
+	selectedIdx     int                      // Index of currently selected backup in backups slice
+	vaultDiscovered bool                     // Whether vault discovery has completed
 
-	// In-app filter state
-	activeFilter filterMode // Current in-app resource type filter
+	// In-app filter and sort state
+	activeFilter   filterMode // Current in-app resource type filter
+	activeSort     sortMode   // Current backup list sort order
+	supportedTypes []string   // Resource types AWS Backup supports in the region (nil until loaded)
+
+	// View state saved per environment ("" path disables saving), as last
+	// saved, and the saved tab to open once the backups load
+	viewsPath  string
+	savedView  store.ViewState
+	pendingTab string
+
+	// Backup whose detail view opens once the backups load (-resource, -arn)
+	startTarget startTarget
+
+	// Lock panel shown after the session sits idle
+	idle idleLockState
+
+	// Advisory lock on restores of the stack, shared with other sessions
+	restoreLock restoreLockState
+
+	// Vault switching: per-vault list context and the vault to return to
+	vaultSwitch vaultSwitchState
 
 	// Restore monitoring state
 	restoreJobID  string    // Active restore job ID being monitored
 	restoreStart  time.Time // When the restore was initiated
 	restoreStatus *aws.RestoreJobStatus
 
-	// Restore metadata preview
+	// Restore jobs started or queued this session, in start order
+	jobs        []*restoreJob
+	jobsCursor  int    // Selected job in the jobs view
+	cleanupSeq  int    // Job whose leftover resources the cleanup confirmation deletes
+	importInput string // Job ID typed at the import prompt
+
+	restoreAtInput string // Moment typed at the "@" prompt
+
+	// Backup and restore jobs of the stack's resources in the account,
+	// however they were started
+	stackJobs stackJobsView
+
+	// Restore metadata preview, and the restore prerequisites looked up
+	// when the detail view opened
 	restoreMetadata *aws.RestoreMetadata
+	prefetch        prefetchState
+	confirmField    int  // Focused restore parameter on the confirm screen
+	confirmHelp     bool // Whether help for the focused parameter is shown
+
+	// Overrides for the pending restore, the encryption key, security
+	// group, and subnet group pickers, and the new cluster's suffix typed
+	// at its prompt
+	restoreOpts        aws.RestoreOptions
+	kmsPicker          kmsPicker
+	sgPicker           sgPicker
+	subnetPicker       subnetPicker
+	clusterSuffixInput string
+	efsItems           efsItemsForm
+
+	// Columns of the backup list picked for the environment (nil for the
+	// config file's), and the picker choosing them
+	columns      []string
+	columnPicker columnPicker
+
+	// Stacks to choose from when launched without one among several
+	stackPicker stackPicker
+
+	// The stack's live cluster, which an RDS restore mirrors, for the
+	// estimated restore cost (nil until looked up with an RDS backup), and
+	// its configuration diff with the restore
+	cluster    *aws.ClusterHealth
+	clusterErr error
+	configDiff configDiffView
+
+	// Engine version check of the backup being confirmed (RDS only)
+	engineCheck engineCheckView
+
+	// Recent errors and warnings, and the error log pane
+	errorLog errorLog
+
+	// AWS status feed checks after regional failures
+	health healthState
+
+	// API calls of the session, their budget, and the API calls panel
+	apiCalls apiCallsView
+	envInfo  envInfoView
+
+	// Restore jobs of the last 30 days, for restore test results in the
+	// latest restorable banner
+	recentRestores []aws.JobRecord
+
+	// Retention editor for the selected backup
+	lifecycleEdit lifecycleEditor
+
+	// Backups marked in the list for a legal hold, a tag edit, or deletion, by
+	// recovery point ARN, and the legal holds view
+	marked     map[string]bool
+	legalHolds legalHoldView
+
+	// Tag editor for the marked or selected backups
+	tagEdit tagEditor
+
+	// Recovery point deletion
+	deleteConfirm deleteConfirm
+	deletes       deleteQueue // Confirmed deletions in their grace period
+
+	// Where snapshot exports are written (from the -export-* flags)
+	exportDest aws.ExportDestination
+
+	// Backup plan and IAM role resolved for restores from the vault
+	planRole *aws.PlanRole
+
+	// Type and lock settings of the current vault (nil until described)
+	vaultInfo *aws.VaultInfo
+
+	// OpenEMR task definition history, newest first, and the most recent
+	// image change in it (nil when unknown), used to warn about restoring
+	// pre-upgrade data
+	taskDefs   taskDefView
+	appUpgrade *aws.ImageChange
+
+	// Timeline of backups, jobs, and deployments
+	timeline timelineView
+
+	// Recovery points created, copied in, and deleted recently
+	activity activityView
+
+	// Backup jobs into the vault and restore jobs of its backups
+	vaultJobs vaultJobsView
+
+	// Operations every operator started, from the shared journal
+	team teamView
+
+	// Selections of the vault's backup plan
+	selections selectionsView
+
+	// Organization policies from the config file, e.g. RPO/RTO targets
+	config         *config.Config
+	overrideFreeze bool // Restores may start during the config's freeze windows
+
+	// Details of the backups on screen, fetched in the background
+	enrich enrichState
+
+	// Fatal error handling while restores are still tracked
+	historyPath     string // Job history file for "backup-tui watch" ("" disables saving)
+	savedJobs       string // Outcome of saving running job IDs, shown on the error screen
+	minimalTracking bool   // Keep polling restores in the jobs view after a fatal error
+
+	// Restore chains saved for resuming, and those from an earlier session
+	// offered for resuming
+	workflowsPath string // Workflow file ("" disables saving)
+	resume        resumePrompt
+
+	// Verified test restores, and those being verified
+	verify verifyState
+
+	renderer    Renderer          // Draws the list, detail, and jobs views (nil for the styled terminal views)
+	statusBoard *StatusBoard      // Published to after each update for -status-addr (nil when disabled)
+	notifier    *webhook.Notifier // Webhooks notified of job transitions (nil when none)
 }
 
 // state represents the current application view/state.
@@ -69,43 +226,104 @@ type Model struct {
 type state int
 
 const (
-	stateLoading   state = iota // Initial state: discovering vault and loading backups
-	stateList                   // Main state: displaying list of backups
-	stateDetail                 // Detail state: showing details of selected backup
-	stateConfirm                // Confirm state: confirming restore operation
-	stateHelp                   // Help state: displaying help screen
-	stateError                  // Error state: displaying error message
-	stateRestoring              // Restore monitoring: polling restore job status
+	stateLoading      state = iota // Initial state: discovering vault and loading backups
+	stateList                      // Main state: displaying list of backups
+	stateDetail                    // Detail state: showing details of selected backup
+	stateConfirm                   // Confirm state: confirming restore operation
+	stateHelp                      // Help state: displaying help screen
+	stateError                     // Error state: displaying error message
+	stateRestoring                 // Restore monitoring: polling restore job status
+	stateSwitchVault               // Vault switch: entering a vault (and optional region) to switch to
+	stateJobs                      // Jobs view: restores of this session and queued chain steps
+	stateTaskDefs                  // Task definition history: OpenEMR revisions, images, and env changes
+	stateTimeline                  // Timeline: backups, restores, copies, and deployments in order
+	stateImportJob                 // Import job: entering a restore or backup job ID started elsewhere
+	stateKMSPicker                 // Encryption key picker: choosing the KMS key for the pending restore
+	stateSGPicker                  // Security group picker: choosing the VPC security groups for the pending RDS restore
+	stateSubnetGroup               // Subnet group picker: choosing the DB subnet group for the pending RDS restore
+	stateExport                    // Export confirmation: exporting an RDS backup to S3
+	stateClone                     // Clone confirmation: fast-cloning the current Aurora cluster
+	stateCleanup                   // Cleanup confirmation: deleting what a failed or abandoned job left behind
+	stateLifecycle                 // Retention editor: changing the selected backup's lifecycle
+	stateLegalHolds                // Legal holds: the region's holds, placing and releasing them
+	stateHoldNew                   // New legal hold: title and description for a hold on the marked backups
+	stateHoldRelease               // Release legal hold: entering the reason for releasing it
+	stateErrorLog                  // Error log: recent errors and warnings with their AWS details
+	stateAPICalls                  // API calls: the session's AWS API calls per service and the call budget
+	stateSelections                // Backup selections: what the vault's plan backs up and why
+	stateActivity                  // Vault activity: recovery points created, copied in, and deleted recently
+	stateResume                    // Resume prompt: a restore chain interrupted in an earlier session
+	stateEnvInfo                   // Environment info: the discovered identifiers, for copying
+	stateTeamActivity              // Team activity: operations every operator started, from the shared journal
+	stateRestoreAt                 // Moment prompt: selecting the backups closest to but not after a moment
+	stateTagEdit                   // Tag editor: adding and removing tags of the marked or selected backups
+	stateNewCluster                // New cluster prompt: the suffix naming the cluster the pending RDS restore creates
+	stateDelete                    // Delete confirmation: typing "delete" to delete the marked or selected backups
+	stateBackupJobs                // Backup jobs tab: the vault's backup jobs, running and finished
+	stateRestoreJobs               // Restore jobs tab: the restore jobs of the vault's backups
+	stateEFSItems                  // Items form: the paths an item-level EFS restore covers
+	stateColumns                   // Column picker: the columns of the backup list and their order
+	stateStacks                    // Stack selector: choosing one of several OpenEMR stacks at launch
+	stateQuitDeletes               // Quit question: sending or discarding deletions still in their grace period
 )
 
-// filterMode represents the in-app resource type filter cycle.
-type filterMode int
+// filterMode is the in-app resource type filter: an AWS Backup resource
+// type, or filterAll.
+type filterMode string
+
+const filterAll filterMode = ""
+
+func (f filterMode) String() string {
+	if f == filterAll {
+		return "All"
+	}
+	return string(f)
+}
+
+// next returns the filter after f when cycling through All and then each
+// of types in turn.
+func (f filterMode) next(types []string) filterMode {
+	i := 0
+	if f != filterAll {
+		// A type no longer offered, e.g. after a vault switch, goes back to All
+		if i = slices.Index(types, string(f)) + 1; i == 0 {
+			return filterAll
+		}
+	}
+	if i < len(types) {
+		return filterMode(types[i])
+	}
+	return filterAll
+}
+
+// sortMode represents the backup list sort order cycle.
+type sortMode int
 
 const (
-	filterAll filterMode = iota
-	filterRDS
-	filterEFS
+	sortNewest  sortMode = iota // Newest first (default)
+	sortOldest                  // Oldest first
+	sortLargest                 // Largest backup first
 )
 
-func (f filterMode) String() string {
-	switch f {
-	case filterRDS:
-		return "RDS"
-	case filterEFS:
-		return "EFS"
+func (s sortMode) String() string {
+	switch s {
+	case sortOldest:
+		return "Oldest first"
+	case sortLargest:
+		return "Largest first"
 	default:
-		return "All"
+		return "Newest first"
 	}
 }
 
-func (f filterMode) next() filterMode {
-	switch f {
-	case filterAll:
-		return filterRDS
-	case filterRDS:
-		return filterEFS
+func (s sortMode) next() sortMode {
+	switch s {
+	case sortNewest:
+		return sortOldest
+	case sortOldest:
+		return sortLargest
 	default:
-		return filterAll
+		return sortNewest
 	}
 }
 
@@ -113,44 +331,152 @@ var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "
 
 type spinnerTickMsg time.Time
 
+// Options configures a new Model.
+type Options struct {
+	StackName         string   // CloudFormation stack name for vault discovery
+	VaultName         string   // Backup vault name (empty string triggers auto-discovery)
+	Region            string   // AWS region for API calls
+	RegionSource      string   // Where Region was resolved from, shown in the header
+	ResourceTypes     []string // Optional AWS Backup resource type filter, e.g. ["RDS", "EFS"] (nil for all)
+	HistoryPath       string   // Job history file for restores still running after a fatal error ("" disables saving)
+	ViewsPath         string   // View state file for the sort order, filter, and tab ("" disables saving)
+	WorkflowsPath     string   // Workflow file for resuming restore chains ("" disables saving)
+	LocksPath         string   // Restore lock file shared with other sessions ("" disables the local lock)
+	VerificationsPath string   // Verification history of test restores ("" disables saving)
+
+	// Stacks are offered to choose from when StackName is empty and
+	// discovery found several.
+	Stacks []aws.StackSummary
+
+	// Export is the S3 destination for snapshot exports; exports are
+	// unavailable until it is complete.
+	Export aws.ExportDestination
+
+	// Config holds the organization's policies, e.g. the RPO/RTO targets
+	// the latest restorable banner is colored against. Nil sets none.
+	Config *config.Config
+
+	// OverrideFreeze allows restores during the config's change freeze
+	// windows, which otherwise refuse them.
+	OverrideFreeze bool
+
+	// APIBudget pauses background refresh once the session has made this
+	// many AWS API calls (0 for no budget).
+	APIBudget int
+
+	// Status, if set, is kept up to date with the session's jobs and state
+	// for the -status-addr endpoint.
+	Status *StatusBoard
+
+	// Notifier posts restore, export, and clone transitions to the config
+	// file's webhooks (nil for none).
+	Notifier *webhook.Notifier
+
+	// Renderer draws the backup list, details, and jobs views, e.g. a
+	// PlainRenderer for -plain. Nil draws the styled terminal views.
+	Renderer Renderer
+
+	// OpenView is the view to launch into, one of StartViews, instead of
+	// the one saved for the environment ("" for the saved view).
+	OpenView string
+
+	// OpenResource and OpenARN launch into the detail view of a backup:
+	// the latest restorable one of a resource ID or ARN, or the recovery
+	// point with the given ARN.
+	OpenResource string
+	OpenARN      string
+
+	// IdleLock locks the screen after this long without a key press
+	// (0 for never).
+	IdleLock time.Duration
+
+	// OnWarning, if set, is called with each message pushed for the
+	// operator, e.g. to print them when running without the TUI.
+	OnWarning func(Warning)
+
+	// Client overrides the AWS-backed client, e.g. with a simulated client
+	// from aws.NewSimulatedBackupClient. Nil creates a real client for Region.
+	Client *aws.BackupClient
+
+	// Auth is where the credentials of a client created for Region come
+	// from (the zero value for the default credential chain).
+	Auth aws.AuthOptions
+}
+
 // NewModel creates and initializes a new application Model.
 // This function sets up the initial state, initializes AWS clients, and prepares
 // UI components for use.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout control (used for AWS API calls)
-//   - stackName: CloudFormation stack name for vault discovery
-//   - vaultName: Backup vault name (empty string triggers auto-discovery)
-//   - region: AWS region for API calls
-//   - resourceType: Optional resource type filter ("RDS", "EFS", or "")
+//   - opts: Stack, vault, region, and filter configuration
 //
 // Returns:
 //   - *Model: Initialized model (may be in error state if AWS client creation fails)
 //
 // Note: If AWS client initialization fails, the model is placed in stateError
 // with the error stored in m.err. The model can still be used (to display the error).
-func NewModel(ctx context.Context, stackName, vaultName, region, resourceType string) *Model {
+func NewModel(ctx context.Context, opts Options) *Model {
 	m := &Model{
-		ctx:          ctx,
-		stackName:    stackName,
-		vaultName:    vaultName,
-		region:       region,
-		resourceType: resourceType,
-		state:        stateLoading, // Start in loading state
-		selectedIdx:  0,
+		ctx:            ctx,
+		stackName:      opts.StackName,
+		vaultName:      opts.VaultName,
+		region:         opts.Region,
+		regionSource:   opts.RegionSource,
+		resourceTypes:  opts.ResourceTypes,
+		historyPath:    opts.HistoryPath,
+		viewsPath:      opts.ViewsPath,
+		workflowsPath:  opts.WorkflowsPath,
+		restoreLock:    restoreLockState{path: opts.LocksPath},
+		verify:         verifyState{path: opts.VerificationsPath},
+		exportDest:     opts.Export,
+		config:         opts.Config,
+		overrideFreeze: opts.OverrideFreeze,
+		statusBoard:    opts.Status,
+		notifier:       opts.Notifier,
+		renderer:       opts.Renderer,
+		warnings:       warnings{onPush: opts.OnWarning},
+		apiCalls:       apiCallsView{budget: opts.APIBudget, initial: opts.APIBudget},
+		idle:           idleLockState{after: opts.IdleLock},
+		state:          stateLoading, // Start in loading state
+		selectedIdx:    0,
+	}
+	defer m.publishStatus()
+	if m.stackName == "" && len(opts.Stacks) > 1 {
+		// The view state is per stack, so it is loaded once one is chosen
+		m.stackPicker = stackPicker{stacks: opts.Stacks}
+		m.state = stateStacks
+	} else {
+		m.loadView()
+	}
+	if opts.OpenView != "" {
+		m.pendingTab = opts.OpenView
+	}
+	if opts.OpenARN != "" || opts.OpenResource != "" {
+		m.pendingTab = ""
+		m.startTarget = startTarget{arn: opts.OpenARN, resource: opts.OpenResource}
 	}
+	m.loadVerifications()
 
 	// Initialize AWS clients (required for all operations)
 	var err error
-	m.backupClient, err = aws.NewBackupClient(ctx, region)
+	m.backupClient = opts.Client
+	if m.backupClient == nil {
+		m.backupClient, err = aws.NewBackupClient(ctx, opts.Region, opts.Auth)
+	}
 	if err != nil {
 		m.err = fmt.Errorf("failed to create backup client: %w", err)
 		m.state = stateError // Set error state immediately
 		return m
 	}
+	m.enrich.enricher = aws.NewEnricher(ctx, m.backupClient, aws.EnrichWorkers)
+	m.apiCalls.usage = m.backupClient.APIUsage
+	if !m.backupClient.Simulated() {
+		m.health.checker = aws.NewHealthChecker()
+	}
 
 	// Initialize UI components (these are stateless and don't need async setup)
-	m.listModel = ui.NewListModel()
+	m.listModel = ui.NewListModel(backupColumns(m.listColumnNames())...)
 	m.detailModel = ui.DetailModel{}
 	m.helpModel = ui.HelpModel{}
 
@@ -167,12 +493,22 @@ func NewModel(ctx context.Context, stackName, vaultName, region, resourceType st
 // Note: These commands run concurrently. The model will receive messages when
 // they complete, triggering state transitions.
 func (m *Model) Init() tea.Cmd {
-	cmds := []tea.Cmd{m.tickSpinner()}
+	if m.state == stateStacks {
+		return tea.Batch(m.tickSpinner(), m.scheduleIdleCheck())
+	}
+	return tea.Batch(m.tickSpinner(), m.startLoading(), m.scheduleIdleCheck())
+}
+
+// startLoading returns the commands that discover the vault and load the
+// backups and what goes with them, once the stack is known.
+func (m *Model) startLoading() tea.Cmd {
+	cmds := []tea.Cmd{m.fetchTaskDefHistory(), m.loadResumableJobs(), m.loadWorkflows()}
 	if m.vaultName == "" {
 		cmds = append(cmds, m.discoverVault())
 	} else {
-		cmds = append(cmds, m.loadBackups())
+		cmds = append(cmds, m.loadBackups(), m.resolvePlanRole(false), m.describeVault())
 	}
+	cmds = append(cmds, m.loadSupportedTypes(), m.loadStackJobs(), m.waitForEnrichment())
 	return tea.Batch(cmds...)
 }
 
@@ -201,6 +537,18 @@ func (m *Model) tickSpinner() tea.Cmd {
 //   - restoreInitiatedMsg: Restore job initiation completion
 //   - error: Generic error message
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	m.enrichVisible()
+	m.publishStatus()
+	m.saveView()
+	if check := m.checkHealth(); check != nil {
+		cmd = tea.Batch(cmd, check)
+	}
+	return model, cmd
+}
+
+// update implements Update.
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
@@ -211,65 +559,281 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, m.tickSpinner())
 		}
 
+	case idleTickMsg:
+		cmds = append(cmds, m.handleIdleTick())
+
+	case idleUnlockMsg:
+		cmds = append(cmds, m.handleIdleUnlock(msg))
+
+	case tea.WindowSizeMsg:
+		// The list's columns fit the window; its height is fitted when it
+		// is drawn, as the header and banner above it vary in height
+		m.listModel.SetWidth(msg.Width)
+		m.height = msg.Height
+
 	case tea.KeyPressMsg:
+		// The lock panel hides the session until it is resumed
+		if m.idle.locked {
+			return m, m.updateIdleLock(msg)
+		}
+		m.noteInput()
+
+		// The vault switch prompt takes text input, so it sees keys first
+		if m.state == stateSwitchVault {
+			return m.updateSwitchVault(msg)
+		}
+		if m.state == stateImportJob {
+			return m.updateImportJob(msg)
+		}
+		if m.state == stateRestoreAt {
+			return m.updateRestoreAt(msg)
+		}
+		if m.state == stateKMSPicker {
+			return m.updateKMSPicker(msg)
+		}
+		if m.state == stateSGPicker {
+			return m.updateSGPicker(msg)
+		}
+		if m.state == stateSubnetGroup {
+			return m.updateSubnetPicker(msg)
+		}
+		if m.state == stateNewCluster {
+			return m.updateClusterSuffix(msg)
+		}
+		if m.state == stateEFSItems {
+			return m.updateEFSItems(msg)
+		}
+		if m.state == stateColumns {
+			return m.updateColumnPicker(msg)
+		}
+		if m.state == stateStacks {
+			return m.updateStackPicker(msg)
+		}
+		if m.state == stateLifecycle {
+			return m.updateLifecycleEdit(msg)
+		}
+		if m.state == stateHoldNew {
+			return m.updateHoldNew(msg)
+		}
+		if m.state == stateHoldRelease {
+			return m.updateHoldRelease(msg)
+		}
+		if m.state == stateTagEdit {
+			return m.updateTagEdit(msg)
+		}
+		if m.state == stateDelete {
+			return m.updateDelete(msg)
+		}
+		if m.state == stateQuitDeletes {
+			return m.updateQuitDeletes(msg)
+		}
+		if m.state == stateResume {
+			return m, tea.Batch(m.updateResume(msg)...)
+		}
+		if m.state == stateHelp {
+			return m, m.updateHelp(msg)
+		}
+		// A fatal error with restores still tracked blocks quitting by reflex
+		if m.state == stateError && len(m.runningJobs()) > 0 {
+			return m.updateFatalPrompt(msg)
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
-			if m.state == stateHelp {
-				m.state = stateList
+			if m.state == stateConfirm || m.state == stateExport {
+				m.state = stateDetail
 				return m, nil
 			}
-			if m.state == stateConfirm {
-				m.state = stateDetail
+			if m.state == stateClone {
+				m.state = stateConfirm
 				return m, nil
 			}
-			if m.state == stateRestoring {
-				m.state = stateList
+			if m.state == stateCleanup {
+				m.state = stateJobs
 				return m, nil
 			}
-			return m, tea.Quit
-		case "esc":
-			if m.state == stateHelp {
-				m.state = stateList
+			if m.state == stateRestoring || m.state == stateJobs || m.state == stateTimeline || m.state == stateLegalHolds || m.state == stateSelections || m.state == stateActivity || m.state == stateTeamActivity || m.state == stateBackupJobs || m.state == stateRestoreJobs {
+				m.state = m.homeState()
 				return m, nil
 			}
-			if m.state == stateConfirm {
+			if m.state == stateTaskDefs {
+				m.state = m.taskDefs.returnTo
+				return m, nil
+			}
+			if m.state == stateErrorLog {
+				m.state = m.errorLog.returnTo
+				return m, nil
+			}
+			if m.state == stateAPICalls {
+				m.state = m.apiCalls.returnTo
+				return m, nil
+			}
+			if m.state == stateEnvInfo {
+				m.state = m.envInfo.returnTo
+				return m, nil
+			}
+			return m, m.quit()
+		case "esc":
+			if m.state == stateConfirm || m.state == stateExport {
 				m.state = stateDetail
 				return m, nil
 			}
-			if m.state == stateRestoring {
-				m.state = stateList
+			if m.state == stateClone {
+				m.state = stateConfirm
+				return m, nil
+			}
+			if m.state == stateCleanup {
+				m.state = stateJobs
+				return m, nil
+			}
+			if m.state == stateRestoring || m.state == stateJobs || m.state == stateTimeline || m.state == stateLegalHolds || m.state == stateSelections || m.state == stateActivity || m.state == stateTeamActivity || m.state == stateBackupJobs || m.state == stateRestoreJobs {
+				m.state = m.homeState()
+				return m, nil
+			}
+			if m.state == stateTaskDefs {
+				m.state = m.taskDefs.returnTo
+				return m, nil
+			}
+			if m.state == stateErrorLog {
+				m.state = m.errorLog.returnTo
+				return m, nil
+			}
+			if m.state == stateAPICalls {
+				m.state = m.apiCalls.returnTo
+				return m, nil
+			}
+			if m.state == stateEnvInfo {
+				m.state = m.envInfo.returnTo
 				return m, nil
 			}
 			if m.state == stateDetail {
 				m.state = stateList
 				return m, nil
 			}
-			return m, tea.Quit
+			return m, m.quit()
 		case "?":
-			if m.state == stateList || m.state == stateDetail {
-				m.state = stateHelp
+			if helpStates[m.state] {
+				m.openHelp()
 				return m, nil
 			}
 		case "r":
+			if m.state == stateTaskDefs {
+				return m, m.refreshTaskDefs()
+			}
 			if m.state == stateList {
 				m.state = stateLoading
-				cmds = append(cmds, m.loadBackups(), m.tickSpinner())
+				cmds = append(cmds, m.loadBackups(), m.resolvePlanRole(true), m.describeVault(), m.tickSpinner())
 			}
 		case "f":
 			if m.state == stateList {
 				m.cycleFilter()
 			}
+		case "F":
+			if m.state == stateList {
+				cmds = append(cmds, m.clearFilters())
+			}
+		case "s":
+			if m.state == stateList {
+				m.cycleSort()
+			}
+		case "v":
+			if m.state == stateList {
+				return m, m.startVaultSwitch()
+			}
+		case "-":
+			if m.state == stateList {
+				return m, m.switchToPrevious()
+			}
+		case "C":
+			if m.state == stateList {
+				m.openColumnPicker()
+				return m, nil
+			}
+		case "@":
+			if m.state == stateList {
+				m.startRestoreAt()
+				return m, nil
+			}
+		case "u":
+			if len(m.deletes.pending) > 0 {
+				m.undoDelete()
+				return m, nil
+			}
+		case "J":
+			if m.state == stateList || m.state == stateRestoring {
+				m.state = stateJobs
+				return m, m.loadStackJobs()
+			}
+		case "T":
+			if m.state == stateList || m.state == stateDetail {
+				return m, m.openTaskDefs()
+			}
+		case "t":
+			if m.state == stateList {
+				return m, m.openTimeline()
+			}
+		case "H":
+			if m.state == stateList {
+				return m, m.openLegalHolds()
+			}
+		case "#":
+			if m.state == stateList || m.state == stateDetail {
+				m.openTagEdit()
+				return m, nil
+			}
+		case "d":
+			if m.state == stateList || m.state == stateDetail {
+				m.openDelete()
+				return m, nil
+			}
+		case "i":
+			if m.state == stateList || m.state == stateDetail {
+				return m, m.openEnvInfo()
+			}
+		case "P":
+			if m.state == stateList {
+				return m, m.openSelections()
+			}
+		case "A":
+			if m.state == stateList {
+				return m, m.openActivity()
+			}
+		case "O":
+			if m.state == stateList {
+				return m, m.openTeamActivity()
+			}
+		case "tab", "shift+tab":
+			if tabIndex(m.state) >= 0 {
+				if msg.String() == "tab" {
+					return m, m.switchTab(1)
+				}
+				return m, m.switchTab(-1)
+			}
+		case "e":
+			// "e" on the confirm screen picks the encryption key instead
+			switch m.state {
+			case stateList, stateDetail, stateRestoring, stateJobs, stateTimeline, stateTaskDefs, stateLegalHolds, stateSelections, stateActivity, stateTeamActivity, stateBackupJobs, stateRestoreJobs:
+				m.openErrorLog()
+				return m, nil
+			}
+		case "ctrl+d":
+			switch m.state {
+			case stateList, stateDetail, stateRestoring, stateJobs, stateTimeline, stateTaskDefs, stateLegalHolds, stateSelections, stateActivity, stateTeamActivity, stateBackupJobs, stateRestoreJobs, stateErrorLog:
+				m.openAPICalls()
+				return m, nil
+			}
 		}
 
 		switch m.state {
 		case stateList:
-			if msg.String() == "enter" {
+			switch msg.String() {
+			case "enter":
 				if len(m.backups) > 0 && m.listModel.SelectedIndex() < len(m.backups) {
 					m.selectedIdx = m.listModel.SelectedIndex()
-					m.detailModel.SetRecoveryPoint(&m.backups[m.selectedIdx])
-					m.state = stateDetail
-					m.restoreMetadata = nil
+					cmds = append(cmds, m.openDetail())
 				}
+			case "space":
+				m.toggleMark()
 			}
 			m.listModel, cmd = m.listModel.Update(msg)
 			cmds = append(cmds, cmd)
@@ -282,9 +846,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.restoreMetadata = nil
 			case "enter":
 				m.state = stateConfirm
+				m.confirmField = 0
+				m.confirmHelp = false
+				m.restoreOpts = aws.RestoreOptions{}
 				if m.selectedIdx < len(m.backups) {
-					cmds = append(cmds, m.fetchRestoreMetadata())
+					cmds = append(cmds, m.restoreMetadataCmd(), m.checkEngineVersion(), m.checkRestoreLock())
 				}
+			case "x":
+				m.openExportConfirm()
+			case "l":
+				m.openLifecycleEdit()
 			}
 			m.detailModel, cmd = m.detailModel.Update(msg)
 			cmds = append(cmds, cmd)
@@ -292,17 +863,121 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case stateConfirm:
 			switch msg.String() {
 			case "y", "Y":
-				m.restoreStart = time.Now()
-				m.statusMsg = "Restoring..."
-				cmds = append(cmds, m.initiateRestore())
+				if refusal := m.freezeRefusal(); refusal != "" {
+					m.notify(SeverityWarn, refusal)
+				} else if m.selectedIdx < len(m.backups) {
+					m.restoreStart = time.Now()
+					m.inform("Restoring...")
+					job := m.addJob(m.backups[m.selectedIdx], nil)
+					job.options = m.restoreOpts
+					cmds = append(cmds, m.initiateRestore(job))
+				}
+			case "a", "A":
+				m.queueRestore()
+			case "e", "E":
+				cmds = append(cmds, m.openKMSPicker())
+			case "t", "T":
+				m.restoreOpts.CopyTags = !m.restoreOpts.CopyTags
 			case "n", "N", "backspace":
 				m.state = stateDetail
 				m.restoreMetadata = nil
+			case "up", "k":
+				if m.confirmField > 0 {
+					m.confirmField--
+				}
+			case "down", "j":
+				if m.confirmField < len(restoreFields(m.restoreMetadata, m.planRole))-1 {
+					m.confirmField++
+				}
+			case "?":
+				m.confirmHelp = !m.confirmHelp
+			default:
+				// Keys of the backup's resource kind, e.g. subnet groups for RDS
+				if cmd, ok := m.runConfirmKey(msg.String()); ok {
+					cmds = append(cmds, cmd)
+				}
 			}
 
-		case stateHelp:
-			m.helpModel, cmd = m.helpModel.Update(msg)
-			cmds = append(cmds, cmd)
+		case stateRestoring:
+			if msg.String() == "c" {
+				cmds = append(cmds, m.copyRestoreDir())
+			}
+
+		case stateExport:
+			cmds = append(cmds, m.updateExportConfirm(msg))
+
+		case stateClone:
+			cmds = append(cmds, m.updateCloneConfirm(msg))
+
+		case stateCleanup:
+			cmds = append(cmds, m.updateCleanupConfirm(msg))
+
+		case stateJobs:
+			cmds = append(cmds, m.updateJobs(msg))
+
+		case stateTaskDefs:
+			m.updateTaskDefs(msg)
+
+		case stateTimeline:
+			cmds = append(cmds, m.updateTimeline(msg))
+
+		case stateActivity:
+			cmds = append(cmds, m.updateActivity(msg))
+		case stateBackupJobs, stateRestoreJobs:
+			cmds = append(cmds, m.updateVaultJobs(msg))
+		case stateTeamActivity:
+			cmds = append(cmds, m.updateTeamActivity(msg))
+
+		case stateLegalHolds:
+			cmds = append(cmds, m.updateLegalHolds(msg))
+
+		case stateErrorLog:
+			m.updateErrorLog(msg)
+
+		case stateAPICalls:
+			m.updateAPICalls(msg)
+
+		case stateEnvInfo:
+			cmds = append(cmds, m.updateEnvInfo(msg))
+
+		case stateSelections:
+			if msg.String() == "r" {
+				cmds = append(cmds, m.loadSelections())
+			}
+		}
+
+	case tea.PasteMsg:
+		if m.idle.locked {
+			return m, nil
+		}
+		m.noteInput()
+		// Job IDs are usually pasted from the console or CLI output
+		if m.state == stateImportJob {
+			m.importInput += strings.TrimSpace(msg.Content)
+		}
+		// Moments are usually pasted from an incident timeline
+		if m.state == stateRestoreAt {
+			m.restoreAtInput += strings.TrimSpace(msg.Content)
+		}
+		// Case numbers and hold reasons may come from a ticket
+		if m.state == stateHoldNew {
+			if m.legalHolds.field == 0 {
+				m.legalHolds.title += strings.TrimSpace(msg.Content)
+			} else {
+				m.legalHolds.description += strings.TrimSpace(msg.Content)
+			}
+		}
+		if m.state == stateHoldRelease {
+			m.legalHolds.reason += strings.TrimSpace(msg.Content)
+		}
+		if m.state == stateTagEdit {
+			m.tagEdit.input += strings.TrimSpace(msg.Content)
+		}
+		if m.state == stateNewCluster {
+			m.clusterSuffixInput += strings.TrimSpace(msg.Content)
+		}
+		if m.state == stateEFSItems {
+			m.efsItems.input += strings.TrimSpace(msg.Content)
 		}
 
 	case vaultDiscoveredMsg:
@@ -310,57 +985,203 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.vaultName = msg.vaultName
 		m.vaultDiscovered = true
 		if !msg.success {
-			m.err = fmt.Errorf("failed to discover backup vault: %w", msg.err)
-			m.state = stateError
+			cmds = append(cmds, m.fail(fmt.Errorf("failed to discover backup vault: %w", msg.err)))
 		} else if msg.vaultName != "" {
 			// If vault was discovered successfully, now load backups
 			// The vault name is now set in m.vaultName, so loadBackups() will use it
-			cmds = append(cmds, m.loadBackups())
+			cmds = append(cmds, m.loadBackups(), m.resolvePlanRole(false), m.describeVault())
 		}
 
+	case vaultSwitchedMsg:
+		cmds = append(cmds, m.handleVaultSwitched(msg))
+
+	case vaultListMsg:
+		m.handleVaultList(msg)
+
+	case supportedTypesMsg:
+		m.handleSupportedTypes(msg)
+
 	case backupsLoadedMsg:
 		if msg.err != nil {
-			m.err = msg.err
-			m.state = stateError
+			if discover, ok := m.forgetRememberedVault(msg.err); ok {
+				cmds = append(cmds, discover)
+			} else {
+				cmds = append(cmds, m.fail(msg.err))
+			}
 		} else {
+			m.vaultSwitch.remembered = false
 			m.allBackups = msg.backups
+			m.listing = msg.listing
 			m.applyFilter()
 			m.state = stateList
 			m.listModel.SetItems(m.formatBackupsForList())
-			m.statusMsg = ""
+			m.clearStatus()
+			cmds = append(cmds, m.loadRecentRestores(), m.checkRestoreLock(), m.fetchPITRWindow())
+			if m.pendingTab != "" {
+				cmds = append(cmds, m.openPendingTab())
+			}
+			if m.startTarget != (startTarget{}) {
+				cmds = append(cmds, m.openStartTarget())
+			}
+			m.offerResume()
 		}
 
+	case recentRestoresMsg:
+		m.handleRecentRestores(msg)
+
 	case restoreInitiatedMsg:
-		if msg.err != nil {
-			m.err = msg.err
-			m.state = stateError
-		} else {
-			m.restoreJobID = msg.jobID
-			m.state = stateRestoring
-			m.statusMsg = fmt.Sprintf("Restore job started: %s", msg.jobID)
-			cmds = append(cmds, m.pollRestoreStatus(), m.tickSpinner())
-		}
+		cmds = append(cmds, m.handleRestoreInitiated(msg)...)
+		cmds = append(cmds, m.syncRestoreLock())
+
+	case restoreLockMsg:
+		m.handleRestoreLock(msg)
+
+	case restoreLockWrittenMsg:
+		m.handleRestoreLockWritten(msg)
+
+	case restoreProgressMsg:
+		cmds = append(cmds, m.handleRestoreProgress(msg))
+
+	case enrichedMsg:
+		cmds = append(cmds, m.handleEnriched(msg))
 
 	case restoreStatusMsg:
+		cmds = append(cmds, m.handleRestoreStatus(msg)...)
+		cmds = append(cmds, m.syncRestoreLock())
+
+	case restoredTaggedMsg:
 		if msg.err != nil {
-			m.statusMsg = fmt.Sprintf("Error checking restore: %v", msg.err)
-		} else {
-			m.restoreStatus = msg.status
-			if msg.status.IsTerminal {
-				m.statusMsg = fmt.Sprintf("Restore %s: %s", msg.status.Status, msg.status.StatusMessage)
-			} else if m.state == stateRestoring {
-				cmds = append(cmds, m.pollRestoreStatus())
-			}
+			m.logError(fmt.Sprintf("Restore #%d: resource not tagged with the operator's identity", msg.seq), msg.err)
+		}
+		if msg.copyErr != nil {
+			m.logError(fmt.Sprintf("Restore #%d: tags not copied from the original resource", msg.seq), msg.copyErr)
+		} else if len(msg.copied) > 0 {
+			m.inform(fmt.Sprintf("Restore #%d: copied %d tag(s) onto the restored resource", msg.seq, len(msg.copied)))
 		}
 
 	case restoreMetadataMsg:
 		if msg.err == nil {
 			m.restoreMetadata = msg.metadata
+			if m.restoreMetadata != nil {
+				m.restoreMetadata.ApplyOptions(m.restoreOpts)
+			}
+		}
+
+	case prefetchedMsg:
+		m.handlePrefetched(msg)
+
+	case recordedConfigMsg:
+		m.handleRecordedConfig(msg)
+
+	case engineCheckMsg:
+		m.handleEngineCheck(msg)
+
+	case kmsKeysMsg:
+		m.handleKMSKeys(msg)
+
+	case securityGroupsMsg:
+		m.handleSecurityGroups(msg)
+
+	case subnetGroupsMsg:
+		m.handleSubnetGroups(msg)
+
+	case exportStartedMsg:
+		cmds = append(cmds, m.handleExportStarted(msg))
+
+	case cloneStartedMsg:
+		cmds = append(cmds, m.handleCloneStarted(msg))
+
+	case cleanupDoneMsg:
+		m.handleCleanupDone(msg)
+
+	case lifecycleUpdatedMsg:
+		m.handleLifecycleUpdated(msg)
+
+	case tagsEditedMsg:
+		m.handleTagsEdited(msg)
+
+	case deleteTickMsg:
+		cmds = append(cmds, m.handleDeleteTick())
+
+	case pointsDeletedMsg:
+		m.handlePointsDeleted(msg)
+
+	case legalHoldsMsg:
+		m.handleLegalHolds(msg)
+
+	case legalHoldSavedMsg:
+		cmds = append(cmds, m.handleLegalHoldSaved(msg))
+
+	case fileSystemMsg:
+		// Ignore results for a backup that is no longer shown
+		if m.selectedIdx < len(m.backups) && m.backups[m.selectedIdx].ResourceID == msg.fileSystemID {
+			m.detailModel.SetFileSystem(msg.info, msg.err)
+		}
+
+	case taskDefHistoryMsg:
+		m.handleTaskDefHistory(msg)
+
+	case timelineMsg:
+		m.handleTimeline(msg)
+
+	case activityMsg:
+		m.handleActivity(msg)
+
+	case vaultJobsMsg:
+		m.handleVaultJobs(msg)
+
+	case teamActivityMsg:
+		m.handleTeamActivity(msg)
+
+	case journalRecordedMsg:
+		m.handleJournalRecorded(msg)
+
+	case verificationMsg:
+		m.handleVerification(msg)
+
+	case selectionsMsg:
+		m.handleSelections(msg)
+
+	case envInfoMsg:
+		m.handleEnvInfo(msg)
+
+	case stackJobsMsg:
+		m.handleStackJobs(msg)
+
+	case resumedJobsMsg:
+		cmds = append(cmds, m.handleResumedJobs(msg)...)
+
+	case workflowsLoadedMsg:
+		m.handleWorkflowsLoaded(msg)
+
+	case webhookSentMsg:
+		m.handleWebhookSent(msg)
+
+	case healthCheckedMsg:
+		m.handleHealthChecked(msg)
+
+	case jobImportedMsg:
+		cmds = append(cmds, m.handleJobImported(msg))
+
+	case clusterHealthMsg:
+		// The stack has one cluster, so any RDS backup shows the same result
+		m.cluster, m.clusterErr = msg.health, msg.err
+		if m.selectedIdx < len(m.backups) && m.backups[m.selectedIdx].ResourceType == "RDS" {
+			m.detailModel.SetClusterHealth(msg.health, msg.err)
+		}
+
+	case planRoleMsg:
+		// Role resolution failures are not fatal here; StartRestoreJob
+		// resolves again and reports the error if the restore is attempted.
+		if msg.err == nil {
+			m.planRole = msg.planRole
 		}
 
+	case vaultInfoMsg:
+		m.handleVaultInfo(msg)
+
 	case error:
-		m.err = msg
-		m.state = stateError
+		cmds = append(cmds, m.fail(msg))
 	}
 
 	// Execute all collected commands in parallel
@@ -376,24 +1197,88 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *Model) View() tea.View {
 	var content string
 
-	switch m.state {
-	case stateError:
+	switch {
+	case m.idle.locked:
+		content = m.renderIdleLock()
+	case m.state == stateError:
 		content = m.renderError()
-	case stateLoading:
+	case m.state == stateLoading:
 		content = m.renderLoading()
 	default:
 		var view string
 		switch m.state {
 		case stateList:
-			view = m.renderList()
+			view = m.viewRenderer().List(m.ListView())
 		case stateDetail:
-			view = m.renderDetail()
+			view = m.viewRenderer().Detail(m.DetailView())
 		case stateConfirm:
 			view = m.renderConfirm()
 		case stateHelp:
 			view = m.renderHelp()
 		case stateRestoring:
 			view = m.renderRestoring()
+		case stateSwitchVault:
+			view = m.renderSwitchVault()
+		case stateImportJob:
+			view = m.renderImportJob()
+		case stateRestoreAt:
+			view = m.renderRestoreAt()
+		case stateKMSPicker:
+			view = m.renderKMSPicker()
+		case stateSGPicker:
+			view = m.renderSGPicker()
+		case stateSubnetGroup:
+			view = m.renderSubnetPicker()
+		case stateExport:
+			view = m.renderExportConfirm()
+		case stateClone:
+			view = m.renderCloneConfirm()
+		case stateCleanup:
+			view = m.renderCleanupConfirm()
+		case stateLifecycle:
+			view = m.renderLifecycleEdit()
+		case stateLegalHolds:
+			view = m.renderLegalHolds()
+		case stateHoldNew:
+			view = m.renderHoldNew()
+		case stateHoldRelease:
+			view = m.renderHoldRelease()
+		case stateTagEdit:
+			view = m.renderTagEdit()
+		case stateNewCluster:
+			view = m.renderClusterSuffix()
+		case stateEFSItems:
+			view = m.renderEFSItems()
+		case stateColumns:
+			view = m.renderColumnPicker()
+		case stateStacks:
+			view = m.renderStackPicker()
+		case stateDelete:
+			view = m.renderDelete()
+		case stateQuitDeletes:
+			view = m.renderQuitDeletes()
+		case stateErrorLog:
+			view = m.renderErrorLog()
+		case stateAPICalls:
+			view = m.renderAPICalls()
+		case stateEnvInfo:
+			view = m.renderEnvInfo()
+		case stateJobs:
+			view = m.viewRenderer().Jobs(m.JobsView())
+		case stateTaskDefs:
+			view = m.renderTaskDefs()
+		case stateTimeline:
+			view = m.renderTimeline()
+		case stateActivity:
+			view = m.renderActivity()
+		case stateBackupJobs, stateRestoreJobs:
+			view = m.renderVaultJobs()
+		case stateTeamActivity:
+			view = m.renderTeamActivity()
+		case stateSelections:
+			view = m.renderSelections()
+		case stateResume:
+			view = m.renderResume()
 		default:
 			view = "Unknown state"
 		}
@@ -441,38 +1326,48 @@ func (m *Model) renderLoading() string {
 //   - string: Error message with red styling and quit instructions
 func (m *Model) renderError() string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("196")). // Red text
+		Foreground(ui.ColorFail). // Red text
 		Bold(true).
 		Padding(1, 2).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("196")). // Red border
+		BorderForeground(ui.ColorFail). // Red border
 		BorderTop(true).
 		BorderBottom(true).
 		BorderLeft(true).
 		BorderRight(true)
 
 	errorDetails := fmt.Sprintf("✗ Error: %v", m.err)
+	if d := aws.DescribeError(m.err); !d.IsZero() {
+		// AWS support asks for the operation and request ID
+		if d.Service != "" {
+			errorDetails += fmt.Sprintf("\n\n  Operation:  %s %s", d.Service, d.Operation)
+		}
+		if d.RequestID != "" {
+			errorDetails += "\n  Request ID: " + d.RequestID
+		}
+	}
 
 	// Add helpful context based on error type
 	hint := ""
 	errStr := m.err.Error()
+	var authErr *aws.AuthError
 	switch {
+	case errors.As(m.err, &authErr):
+		hint = "\n\n" + authErr.Hint()
 	case strings.Contains(errStr, "backup vault not found"):
 		hint = "\n\nTip: Ensure a backup vault exists for your stack.\n     You can also specify a vault name with the -vault flag."
 	case strings.Contains(errStr, "CloudFormation stack"):
 		hint = "\n\nTip: Verify your AWS credentials and region are correct.\n     You can specify a stack name with the -stack flag."
-	case strings.Contains(errStr, "credentials") || strings.Contains(errStr, "authentication") ||
-		strings.Contains(errStr, "NoCredentialProviders") || strings.Contains(errStr, "EC2RoleRequestError") ||
-		strings.Contains(errStr, "SharedCredsLoad"):
-		hint = "\n\nAWS credentials are required to use this application.\n" +
-			"Configure AWS credentials using one of:\n" +
-			"  - Environment variables: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY\n" +
-			"  - AWS credentials file: ~/.aws/credentials (run 'aws configure')\n" +
-			"  - IAM role: if running on EC2/ECS, ensure instance/task role has permissions"
 	case strings.Contains(errStr, "discover"):
 		hint = "\n\nTip: Check that your CloudFormation stack exists and has a backup vault.\n     You can specify the vault name directly with the -vault flag."
 	}
 
+	if running := m.runningJobs(); len(running) > 0 {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			errorStyle.Render(errorDetails+hint),
+			lipgloss.NewStyle().Padding(1, 2).Render(m.renderRunningJobs(running)))
+	}
+
 	msg := fmt.Sprintf("%s%s\n\nPress 'q' to quit", errorDetails, hint)
 	return errorStyle.Render(msg)
 }
@@ -484,8 +1379,34 @@ func (m *Model) renderError() string {
 //   - string: Rendered list view with header
 func (m *Model) renderList() string {
 	header := m.renderHeader()
+	tabs := m.renderTabs()
+	banner := m.renderLatestBanner()
+	m.fitListHeight(header, tabs, banner)
 	list := m.listModel.View()
-	return lipgloss.JoinVertical(lipgloss.Left, header, list)
+	if summary := m.hiddenSummary(); summary != "" {
+		list = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Padding(1).Render(summary)
+	}
+	if banner != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, header, tabs, banner, list)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, tabs, list)
+}
+
+// fitListHeight gives the backup list the terminal lines that the views
+// above it, the status bar, and the key hints leave, so the list scrolls
+// within the window and the cursor stays on screen however many backups
+// the vault holds.
+func (m *Model) fitListHeight(above ...string) {
+	if m.height <= 0 {
+		return
+	}
+	used := lipgloss.Height(m.renderStatusBar()) + lipgloss.Height(m.renderKeyHints())
+	for _, s := range above {
+		if s != "" {
+			used += lipgloss.Height(s)
+		}
+	}
+	m.listModel.SetHeight(m.height - used)
 }
 
 // renderDetail renders the detail view.
@@ -506,6 +1427,7 @@ func (m *Model) renderDetail() string {
 //   - string: Rendered help view with header
 func (m *Model) renderHelp() string {
 	header := m.renderHeader()
+	m.helpModel.SetResourceTypes(m.filterTypes())
 	help := m.helpModel.View()
 	return lipgloss.JoinVertical(lipgloss.Left, header, help)
 }
@@ -532,10 +1454,15 @@ func (m *Model) renderHeader() string {
 
 	// Info section: vault name, region, optional resource type filter
 	vaultInfo := fmt.Sprintf("Vault: %s", m.vaultName)
-	if !m.vaultDiscovered {
+	if m.state == stateStacks {
+		vaultInfo = "Choosing a stack..."
+	} else if !m.vaultDiscovered {
 		vaultInfo = "Discovering vault..."
 	}
 	regionInfo := fmt.Sprintf("Region: %s", m.region)
+	if m.regionSource != "" {
+		regionInfo = fmt.Sprintf("Region: %s (%s)", m.region, m.regionSource)
+	}
 
 	infoStyle := lipgloss.NewStyle().
 		Foreground(compat.AdaptiveColor{
@@ -544,17 +1471,30 @@ func (m *Model) renderHeader() string {
 		}).
 		MarginBottom(1)
 
+	// The region is highlighted: restoring from the wrong region is an
+	// easy mistake when several profiles are configured.
+	regionStyle := infoStyle.
+		Foreground(compat.AdaptiveColor{
+			Light: lipgloss.Color("130"),
+			Dark:  lipgloss.Color("214"),
+		}).
+		Bold(true)
+
 	infoSection := lipgloss.JoinHorizontal(
 		lipgloss.Left,
 		infoStyle.Render(vaultInfo),
 		"  ",
-		infoStyle.Render(regionInfo),
+		regionStyle.Render(regionInfo),
 	)
 
+	if badge := m.renderVaultBadge(); badge != "" {
+		infoSection = lipgloss.JoinHorizontal(lipgloss.Left, infoSection, "  ", badge)
+	}
+
 	// Show active filter (CLI flag or in-app toggle)
 	var filterLabel string
-	if m.resourceType != "" {
-		filterLabel = m.resourceType
+	if len(m.resourceTypes) > 0 {
+		filterLabel = strings.Join(m.resourceTypes, ", ")
 	}
 	if m.activeFilter != filterAll {
 		filterLabel = m.activeFilter.String()
@@ -569,6 +1509,23 @@ func (m *Model) renderHeader() string {
 		infoSection = lipgloss.JoinHorizontal(lipgloss.Left, infoSection, "  ", filter)
 	}
 
+	// Show non-default sort order
+	if m.activeSort != sortNewest {
+		infoSection = lipgloss.JoinHorizontal(lipgloss.Left, infoSection, "  ", infoStyle.Render("Sort: "+m.activeSort.String()))
+	}
+
+	// Simulation mode is always flagged so a training session is never
+	// mistaken for a real recovery (and vice versa).
+	if m.backupClient != nil && m.backupClient.Simulated() {
+		simStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("16")).
+			Background(ui.ColorWarn).
+			Padding(0, 1).
+			Bold(true)
+		sim := simStyle.Render("SIMULATION — no AWS changes")
+		infoSection = lipgloss.JoinHorizontal(lipgloss.Left, infoSection, "  ", sim)
+	}
+
 	// Combine title with info
 	header := lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -590,21 +1547,40 @@ func (m *Model) renderStatusBar() string {
 	var statusStyle lipgloss.Style
 
 	switch {
-	case m.statusMsg != "":
-		status = m.statusMsg
-		statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	case m.warnings.current != nil:
+		status = m.warnings.current.Severity.icon() + m.warnings.current.Message
+		statusStyle = m.warnings.current.Severity.style()
 	case len(m.backups) > 0:
 		if m.activeFilter != filterAll && len(m.allBackups) != len(m.backups) {
 			status = fmt.Sprintf("✓ %d of %d backup(s) shown (%s)", len(m.backups), len(m.allBackups), m.activeFilter)
 		} else {
 			status = fmt.Sprintf("✓ %d backup(s) found", len(m.backups))
 		}
-		statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+		if len(m.resourceTypes) > 0 {
+			status += " of type " + strings.Join(m.resourceTypes, ", ")
+		}
+		if n := len(m.runningJobs()); n > 0 {
+			status += fmt.Sprintf("  ·  %d restore(s) in progress (J)", n)
+		}
+		statusStyle = lipgloss.NewStyle().Foreground(ui.ColorOK)
+	case m.hiddenSummary() != "":
+		status = "○ " + m.hiddenSummary()
+		if m.filtersClearable() {
+			status += ", F to clear filters"
+		}
+		statusStyle = lipgloss.NewStyle().Foreground(compat.AdaptiveColor{
+			Light: lipgloss.Color("240"),
+			Dark:  lipgloss.Color("248"),
+		})
 	default:
+		kind := "backups"
+		if len(m.resourceTypes) > 0 {
+			kind = strings.Join(m.resourceTypes, " or ") + " backups"
+		}
 		if m.vaultDiscovered && m.vaultName != "" {
-			status = fmt.Sprintf("○ No backups found in vault: %s", m.vaultName)
+			status = fmt.Sprintf("○ No %s found in vault: %s", kind, m.vaultName)
 		} else {
-			status = "○ No backups found"
+			status = "○ No " + kind + " found"
 		}
 		statusStyle = lipgloss.NewStyle().Foreground(compat.AdaptiveColor{
 			Light: lipgloss.Color("240"),
@@ -612,6 +1588,13 @@ func (m *Model) renderStatusBar() string {
 		})
 	}
 
+	if n := m.errorLog.unseen; n > 0 {
+		status += fmt.Sprintf("  ·  %d new error(s), e to view", n)
+	}
+	if pending := m.deletes.summary(time.Now()); pending != "" {
+		status += "  ·  " + pending
+	}
+
 	return statusStyle.
 		Padding(0, 1).
 		Border(lipgloss.RoundedBorder()).
@@ -633,12 +1616,12 @@ func (m *Model) renderConfirm() string {
 	rp := m.backups[m.selectedIdx]
 
 	warningStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("214")).
+		Foreground(ui.ColorWarn).
 		Bold(true)
 
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("214")).
+		BorderForeground(ui.ColorWarn).
 		Padding(1, 2).
 		MarginTop(1)
 
@@ -652,13 +1635,13 @@ func (m *Model) renderConfirm() string {
 
 	yStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("114")).
+		Foreground(ui.ColorOK).
 		Background(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
 		Padding(0, 1)
 
 	nStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("196")).
+		Foreground(ui.ColorFail).
 		Background(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("238")}).
 		Padding(0, 1)
 
@@ -670,25 +1653,48 @@ func (m *Model) renderConfirm() string {
 		infoStyle.Render(fmt.Sprintf("Size:      %s", formatBytes(rp.BackupSizeInBytes))),
 	}
 
-	if m.restoreMetadata != nil {
-		meta := m.restoreMetadata
-		metaStyle := lipgloss.NewStyle().
-			Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("248")})
+	if warning := m.schemaWarning(rp); warning != "" {
+		sections = append(sections, "", warningStyle.Render("⚠  Older than the running OpenEMR version"),
+			infoStyle.Render(warning))
+	}
+	sections = append(sections, m.engineLines(rp, warningStyle, infoStyle)...)
 
-		sections = append(sections, "")
-		sections = append(sections, metaStyle.Render("Restore Parameters:"))
-		switch meta.ResourceType {
-		case "RDS":
-			sections = append(sections, infoStyle.Render(fmt.Sprintf("  Cluster:    %s", meta.ClusterID)))
-			sections = append(sections, infoStyle.Render(fmt.Sprintf("  Subnet:     %s", meta.SubnetGroup)))
-			sections = append(sections, infoStyle.Render(fmt.Sprintf("  Security:   %s", meta.SecurityGroups)))
-		case "EFS":
-			sections = append(sections, infoStyle.Render(fmt.Sprintf("  File System: %s", meta.ResourceID)))
-			sections = append(sections, infoStyle.Render(fmt.Sprintf("  Encrypted:   %v", meta.Encrypted)))
-			sections = append(sections, infoStyle.Render("  In-place:    true"))
+	// Restore parameters are focusable fields; "?" explains the focused one
+	fields := restoreFields(m.restoreMetadata, m.planRole)
+	metaStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("248")})
+
+	if m.restoreMetadata != nil {
+		sections = append(sections, "", metaStyle.Render("Restore Parameters:"))
+		for i, f := range fields {
+			if f.key != "IamRoleArn" {
+				sections = append(sections, m.renderField(i, f, infoStyle))
+			}
 		}
 	}
 
+	sections = append(sections, m.costLines(rp, metaStyle, infoStyle)...)
+	sections = append(sections, m.configDiffLines(rp, metaStyle, infoStyle)...)
+	sections = append(sections, m.vaultRestrictionLines(metaStyle, infoStyle)...)
+	sections = append(sections, m.freezeLines(warningStyle, infoStyle)...)
+	sections = append(sections, m.restoreLockLines(warningStyle, infoStyle)...)
+
+	tags := "not copied (t to copy the original's tags)"
+	if m.restoreOpts.CopyTags {
+		tags = "copy the original's tags + restore provenance (t to skip)"
+	}
+	sections = append(sections, "", infoStyle.Render("Tags:      "+tags))
+
+	if m.planRole != nil {
+		sections = append(sections, "", metaStyle.Render("Restore Role:"))
+		sections = append(sections, infoStyle.Render("  "+m.planLine()))
+		sections = append(sections, m.renderField(len(fields)-1, fields[len(fields)-1], infoStyle))
+	}
+
+	if m.confirmHelp && m.confirmField < len(fields) {
+		sections = append(sections, "", renderFieldHelp(rp.ResourceType, fields[m.confirmField].key))
+	}
+
 	sections = append(sections,
 		"",
 		promptStyle.Render("Are you sure you want to restore this backup?"),
@@ -700,6 +1706,9 @@ func (m *Model) renderConfirm() string {
 			"  Cancel",
 		),
 	)
+	if len(fields) > 0 {
+		sections = append(sections, "", metaStyle.Render("↑/↓ select a parameter · ? explain it"))
+	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
 
@@ -718,39 +1727,305 @@ func (m *Model) renderKeyHints() string {
 	switch m.state {
 	case stateList:
 		hints = fmt.Sprintf(
-			"%s navigate  %s select  %s filter  %s refresh  %s help  %s quit",
+			"%s navigate  %s select  %s at moment  %s mark  %s legal holds  %s tags  %s delete  %s selections  %s filter  %s sort  %s columns  %s vault  %s job tabs  %s jobs  %s timeline  %s activity  %s app versions  %s errors  %s refresh  %s help  %s quit",
 			keyStyle.Render("↑↓"),
 			keyStyle.Render("enter"),
+			keyStyle.Render("@"),
+			keyStyle.Render("space"),
+			keyStyle.Render("H"),
+			keyStyle.Render("#"),
+			keyStyle.Render("d"),
+			keyStyle.Render("P"),
 			keyStyle.Render("f"),
+			keyStyle.Render("s"),
+			keyStyle.Render("C"),
+			keyStyle.Render("v/-"),
+			keyStyle.Render("tab"),
+			keyStyle.Render("J"),
+			keyStyle.Render("t"),
+			keyStyle.Render("A"),
+			keyStyle.Render("T"),
+			keyStyle.Render("e"),
 			keyStyle.Render("r"),
 			keyStyle.Render("?"),
 			keyStyle.Render("q"),
 		)
 	case stateDetail:
 		hints = fmt.Sprintf(
-			"%s restore  %s back  %s help  %s quit",
+			"%s restore  %s export to S3  %s retention  %s tags  %s delete  %s app versions  %s back  %s help  %s quit",
 			keyStyle.Render("enter"),
+			keyStyle.Render("x"),
+			keyStyle.Render("l"),
+			keyStyle.Render("#"),
+			keyStyle.Render("d"),
+			keyStyle.Render("T"),
 			keyStyle.Render("b/←"),
 			keyStyle.Render("?"),
 			keyStyle.Render("q"),
 		)
 	case stateConfirm:
 		hints = fmt.Sprintf(
-			"%s confirm  %s cancel",
+			"%s confirm  %s run after current restore  %s encryption key  %s copy tags  %s cancel  %s field  %s explain field",
 			keyStyle.Render("y"),
+			keyStyle.Render("a"),
+			keyStyle.Render("e"),
+			keyStyle.Render("t"),
 			keyStyle.Render("n/esc"),
-		)
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("?"),
+		) + m.confirmKeyHints(keyStyle)
 	case stateHelp:
 		hints = fmt.Sprintf(
-			"%s close help  %s quit",
-			keyStyle.Render("esc/?"),
-			keyStyle.Render("q"),
+			"%s search  %s close help  %s quit",
+			keyStyle.Render("/"),
+			keyStyle.Render("esc/?/q"),
+			keyStyle.Render("ctrl+c"),
 		)
+		if m.helpModel.Searching() {
+			hints = fmt.Sprintf(
+				"%s keep filter  %s clear filter",
+				keyStyle.Render("enter"),
+				keyStyle.Render("esc"),
+			)
+		}
 	case stateRestoring:
 		hints = fmt.Sprintf(
-			"%s back to list (restore continues)",
+			"%s jobs  %s back to list (restore continues)",
+			keyStyle.Render("J"),
+			keyStyle.Render("esc/q"),
+		)
+		if m.monitoredRestoreDir() != "" {
+			hints = fmt.Sprintf("%s copy restored path  ", keyStyle.Render("c")) + hints
+		}
+	case stateJobs:
+		hints = fmt.Sprintf(
+			"%s navigate  %s monitor or track  %s cancel queued step  %s clean up  %s import job ID  %s verify restore  %s refresh  %s back",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("x"),
+			keyStyle.Render("D"),
+			keyStyle.Render("i"),
+			keyStyle.Render("V"),
+			keyStyle.Render("r"),
+			keyStyle.Render("esc/q"),
+		)
+	case stateTimeline:
+		hints = fmt.Sprintf(
+			"%s navigate  %s open backup  %s refresh  %s back",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("r"),
+			keyStyle.Render("esc/q"),
+		)
+	case stateActivity:
+		hints = fmt.Sprintf(
+			"%s navigate  %s open backup  %s window  %s refresh  %s back",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("w"),
+			keyStyle.Render("r"),
+			keyStyle.Render("esc/q"),
+		)
+	case stateBackupJobs, stateRestoreJobs:
+		hints = fmt.Sprintf(
+			"%s navigate  %s open  %s next tab  %s window  %s refresh  %s back",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("tab"),
+			keyStyle.Render("w"),
+			keyStyle.Render("r"),
+			keyStyle.Render("esc/q"),
+		)
+	case stateTeamActivity:
+		hints = fmt.Sprintf(
+			"%s navigate  %s window  %s refresh  %s back",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("w"),
+			keyStyle.Render("r"),
+			keyStyle.Render("esc/q"),
+		)
+	case stateSelections:
+		hints = fmt.Sprintf(
+			"%s refresh  %s back",
+			keyStyle.Render("r"),
+			keyStyle.Render("esc/q"),
+		)
+	case stateResume:
+		hints = fmt.Sprintf(
+			"%s resume  %s discard  %s decide next launch",
+			keyStyle.Render("y"),
+			keyStyle.Render("n"),
+			keyStyle.Render("esc"),
+		)
+	case stateTaskDefs:
+		hints = fmt.Sprintf(
+			"%s navigate  %s refresh  %s back",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("r"),
+			keyStyle.Render("esc/q"),
+		)
+	case stateSwitchVault:
+		hints = fmt.Sprintf(
+			"%s choose  %s switch  %s cancel",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc"),
+		)
+	case stateImportJob:
+		hints = fmt.Sprintf(
+			"%s import  %s cancel",
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc"),
+		)
+	case stateRestoreAt:
+		hints = fmt.Sprintf(
+			"%s select  %s cancel",
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc"),
+		)
+	case stateExport:
+		hints = fmt.Sprintf(
+			"%s start export  %s cancel",
+			keyStyle.Render("y"),
+			keyStyle.Render("n/esc"),
+		)
+	case stateClone:
+		hints = fmt.Sprintf(
+			"%s create clone  %s back",
+			keyStyle.Render("y"),
+			keyStyle.Render("n/esc"),
+		)
+	case stateCleanup:
+		hints = fmt.Sprintf(
+			"%s delete  %s back",
+			keyStyle.Render("y"),
+			keyStyle.Render("n/esc"),
+		)
+	case stateLegalHolds:
+		hints = fmt.Sprintf(
+			"%s navigate  %s hold marked backups  %s release  %s refresh  %s back",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("n"),
+			keyStyle.Render("x"),
+			keyStyle.Render("r"),
+			keyStyle.Render("esc/q"),
+		)
+	case stateHoldNew:
+		hints = fmt.Sprintf(
+			"%s field  %s place hold  %s cancel",
+			keyStyle.Render("tab"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc"),
+		)
+	case stateHoldRelease:
+		hints = fmt.Sprintf(
+			"%s release  %s cancel",
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc"),
+		)
+	case stateTagEdit:
+		hints = fmt.Sprintf(
+			"%s apply  %s cancel",
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc"),
+		)
+	case stateNewCluster:
+		hints = fmt.Sprintf(
+			"%s set  %s cancel",
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc"),
+		)
+	case stateEFSItems:
+		hints = fmt.Sprintf(
+			"%s add path / save on empty line  %s remove last  %s cancel",
+			keyStyle.Render("enter"),
+			keyStyle.Render("backspace"),
+			keyStyle.Render("esc"),
+		)
+	case stateColumns:
+		hints = fmt.Sprintf(
+			"%s navigate  %s show/hide  %s move  %s apply  %s configured columns  %s cancel",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("space"),
+			keyStyle.Render("shift+↑↓"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("r"),
+			keyStyle.Render("esc"),
+		)
+	case stateStacks:
+		hints = fmt.Sprintf(
+			"%s choose  %s open  %s quit",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc/q"),
+		)
+	case stateDelete:
+		hints = fmt.Sprintf(
+			"%s delete  %s keep",
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc"),
+		)
+	case stateQuitDeletes:
+		hints = fmt.Sprintf(
+			"%s send and quit  %s discard and quit  %s back",
+			keyStyle.Render("s"),
+			keyStyle.Render("d"),
+			keyStyle.Render("esc"),
+		)
+	case stateErrorLog:
+		hints = fmt.Sprintf(
+			"%s navigate  %s details  %s back",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc/q"),
+		)
+	case stateAPICalls:
+		hints = fmt.Sprintf(
+			"%s raise budget  %s back",
+			keyStyle.Render("+"),
 			keyStyle.Render("esc/q"),
 		)
+	case stateEnvInfo:
+		hints = fmt.Sprintf(
+			"%s select  %s copy value  %s copy all  %s back  %s help",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("enter/c"),
+			keyStyle.Render("a"),
+			keyStyle.Render("esc/q"),
+			keyStyle.Render("?"),
+		)
+	case stateLifecycle:
+		hints = fmt.Sprintf(
+			"%s days  %s field  %s save  %s cancel",
+			keyStyle.Render("0-9"),
+			keyStyle.Render("tab"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc"),
+		)
+	case stateKMSPicker:
+		hints = fmt.Sprintf(
+			"%s navigate  %s use key  %s back",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("esc"),
+		)
+	case stateSubnetGroup:
+		hints = fmt.Sprintf(
+			"%s navigate  %s use group  %s live cluster's group  %s back",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("r"),
+			keyStyle.Render("esc"),
+		)
+	case stateSGPicker:
+		hints = fmt.Sprintf(
+			"%s navigate  %s select  %s use selected  %s live cluster's groups  %s back",
+			keyStyle.Render("↑↓"),
+			keyStyle.Render("space"),
+			keyStyle.Render("enter"),
+			keyStyle.Render("r"),
+			keyStyle.Render("esc"),
+		)
 	default:
 		return ""
 	}
@@ -758,14 +2033,26 @@ func (m *Model) renderKeyHints() string {
 	return hintStyle.Render(" " + hints)
 }
 
-func (m *Model) formatBackupsForList() []string {
-	items := make([]string, len(m.backups))
+// formatBackupsForList returns the rows of the backup list, a cell per
+// column of backupColumns.
+func (m *Model) formatBackupsForList() []ui.Row {
+	items := make([]ui.Row, len(m.backups))
+	names := m.listColumnNames()
+	now := time.Now()
 	for i, backup := range m.backups {
-		date := backup.CreationDate.Format("2006-01-02 15:04:05")
-		relative := relativeTime(backup.CreationDate)
-		size := formatBytes(backup.BackupSizeInBytes)
-		dot := freshnessIndicator(backup.CreationDate)
-		items[i] = fmt.Sprintf("%s %s | %s | %s (%s) | %s", dot, backup.ResourceType, backup.ResourceID, date, relative, size)
+		// The dot and the Created column show the backup's age against the
+		// resource type's RPO
+		age := ageLevel(backup.CreationDate, time.Duration(m.config.Target(backup.ResourceType).RPO), now)
+		// Backups marked for a legal hold or another action get a check mark
+		var mark string
+		if m.marked[backup.RecoveryPointARN] {
+			mark = "✓"
+		}
+		row := ui.Row{mark, age.Dot()}
+		for _, name := range names {
+			row = append(row, listColumns[name].cell(m, backup, age, now))
+		}
+		items[i] = row
 	}
 	return items
 }
@@ -808,28 +2095,59 @@ type vaultDiscoveredMsg struct {
 
 // backupsLoadedMsg is sent when backup list loading completes.
 type backupsLoadedMsg struct {
-	backups []aws.RecoveryPoint // Loaded recovery points (empty slice if error)
-	err     error               // Error if loading failed (nil if success)
+	backups []aws.RecoveryPoint      // Loaded recovery points (empty slice if error)
+	listing aws.RecoveryPointListing // Counts of the points the filters left out
+	err     error                    // Error if loading failed (nil if success)
 }
 
 // restoreInitiatedMsg is sent when restore job initiation completes.
 type restoreInitiatedMsg struct {
+	seq   int    // Sequence number of the job in the jobs view
 	jobID string // Restore job ID if successful (empty if error)
 	err   error  // Error if initiation failed (nil if success)
 }
 
 // restoreStatusMsg is sent when a restore job status poll completes.
 type restoreStatusMsg struct {
+	jobID  string // Polled job ID (empty means the monitored job)
 	status *aws.RestoreJobStatus
 	err    error
 }
 
+// restoredTaggedMsg is sent when the resource a restore created has been
+// tagged with the operator's identity and, if asked, the original's tags.
+type restoredTaggedMsg struct {
+	seq     int
+	err     error
+	copied  map[string]string // Tags copied from the original, with provenance
+	copyErr error
+}
+
 // restoreMetadataMsg is sent when restore metadata lookup completes.
 type restoreMetadataMsg struct {
 	metadata *aws.RestoreMetadata
 	err      error
 }
 
+// fileSystemMsg is sent when the live EFS file system lookup completes.
+type fileSystemMsg struct {
+	fileSystemID string
+	info         *aws.FileSystemInfo
+	err          error
+}
+
+// clusterHealthMsg is sent when the live RDS cluster health lookup completes.
+type clusterHealthMsg struct {
+	health *aws.ClusterHealth
+	err    error
+}
+
+// planRoleMsg is sent when backup plan/IAM role resolution completes.
+type planRoleMsg struct {
+	planRole *aws.PlanRole
+	err      error
+}
+
 // Commands
 // These functions return Bubbletea commands that perform async operations.
 // Commands run in goroutines and send messages back to the model when complete.
@@ -859,7 +2177,7 @@ func (m *Model) discoverVault() tea.Cmd {
 
 // loadBackups returns a command that loads the backup list from AWS.
 // Requires vaultName to be set (should be set after vault discovery completes).
-// Filters backups by resourceType if specified.
+// Filters backups by resourceTypes if specified.
 //
 // This function accepts an optional vaultName parameter. If provided, it uses that
 // instead of checking the model state (useful when called right after vault discovery).
@@ -870,7 +2188,7 @@ func (m *Model) loadBackups() tea.Cmd {
 	// Capture the current vault name and resource type when the command is created
 	// This ensures we use the correct values even if the command executes asynchronously
 	vaultName := m.vaultName
-	resourceType := m.resourceType
+	resourceTypes := m.resourceTypes
 	return func() tea.Msg {
 		// Use the captured vault name, or fall back to checking model state
 		if vaultName == "" {
@@ -887,54 +2205,223 @@ func (m *Model) loadBackups() tea.Cmd {
 			}
 		}
 
-		// Use captured resource type or fall back to model state
-		if resourceType == "" {
-			resourceType = m.resourceType
-		}
-
 		// Load recovery points from the vault
 		// Note: Empty vault name should be caught above, but double-check for safety
 		if vaultName == "" {
 			return backupsLoadedMsg{err: fmt.Errorf("vault name is empty - cannot list recovery points")}
 		}
 
-		backups, err := m.backupClient.ListRecoveryPoints(m.ctx, vaultName, resourceType)
+		listing, err := m.backupClient.ListRecoveryPointsCounted(m.ctx, vaultName, resourceTypes...)
 		if err != nil {
 			return backupsLoadedMsg{err: fmt.Errorf("failed to list recovery points from vault %s: %w", vaultName, err)}
 		}
 
 		// Return backups (may be empty if no backups exist in the vault)
-		// If backups is empty but no error, the vault exists but has no recovery points
-		return backupsLoadedMsg{backups: backups}
+		// If backups is empty but no error, the vault exists but has no recovery points,
+		// or the filters hid them all
+		return backupsLoadedMsg{backups: listing.Points, listing: listing}
 	}
 }
 
-// initiateRestore returns a command that initiates a restore job.
-func (m *Model) initiateRestore() tea.Cmd {
-	return func() tea.Msg {
-		if m.selectedIdx >= len(m.backups) {
-			return restoreInitiatedMsg{err: fmt.Errorf("invalid backup selection")}
+// initiateRestore returns a command that starts the restore job for job,
+// and one that relays each lookup StartRestoreJob performs first as a
+// restoreProgressMsg.
+func (m *Model) initiateRestore(job *restoreJob) tea.Cmd {
+	job.started = time.Now()
+	seq, backup, opts := job.seq, job.backup, job.options
+	client, stackName, vaultName := m.backupClient, m.stackName, m.vaultName
+	steps := make(chan string, 8)
+	start := func() tea.Msg {
+		defer close(steps)
+		ctx := aws.WithProgress(m.ctx, func(step string) {
+			select {
+			case steps <- step:
+			default: // The view is behind; skip the step rather than block the restore
+			}
+		})
+		jobID, err := client.StartRestoreJob(ctx, backup, stackName, vaultName, opts)
+		if err != nil {
+			return restoreInitiatedMsg{seq: seq, err: err}
 		}
 
-		backup := m.backups[m.selectedIdx]
-		jobID, err := m.backupClient.StartRestoreJob(m.ctx, backup, m.stackName, m.vaultName)
-		if err != nil {
-			return restoreInitiatedMsg{err: err}
+		return restoreInitiatedMsg{seq: seq, jobID: jobID}
+	}
+	return tea.Batch(start, waitForRestoreProgress(seq, steps))
+}
+
+// restoreProgressMsg reports the step a starting restore is performing.
+type restoreProgressMsg struct {
+	seq   int
+	step  string
+	steps <-chan string // Further steps; closed when the restore has started or failed
+}
+
+// waitForRestoreProgress returns a command that waits for the next step
+// on steps, or returns nil once it is closed.
+func waitForRestoreProgress(seq int, steps <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		step, ok := <-steps
+		if !ok {
+			return nil
 		}
+		return restoreProgressMsg{seq: seq, step: step, steps: steps}
+	}
+}
 
-		return restoreInitiatedMsg{jobID: jobID}
+// handleRestoreProgress shows a starting restore's current step in the
+// jobs view and, for a restore started from the confirm screen, in the
+// status bar, and waits for the next one.
+func (m *Model) handleRestoreProgress(msg restoreProgressMsg) tea.Cmd {
+	if job := m.jobBySeq(msg.seq); job != nil && job.state == jobStarting {
+		job.progress = msg.step
+		if job.after == nil {
+			m.inform("Restoring: " + msg.step)
+		}
 	}
+	return waitForRestoreProgress(msg.seq, msg.steps)
 }
 
-// pollRestoreStatus returns a command that waits 5 seconds then checks restore job status.
-func (m *Model) pollRestoreStatus() tea.Cmd {
-	jobID := m.restoreJobID
+// pollRestoreStatus returns a command that waits 5 seconds then checks the
+// status of a restore job, or of an imported backup job.
+func (m *Model) pollRestoreStatus(jobID string) tea.Cmd {
+	client := m.backupClient
+	kind := aws.JobKindRestore
+	if job := m.jobByID(jobID); job != nil {
+		kind = job.kind
+	}
 	return tea.Tick(5*time.Second, func(_ time.Time) tea.Msg {
-		status, err := m.backupClient.GetRestoreJobStatus(m.ctx, jobID)
-		return restoreStatusMsg{status: status, err: err}
+		status, err := client.GetJobStatus(m.ctx, kind, jobID)
+		return restoreStatusMsg{jobID: jobID, status: status, err: err}
 	})
 }
 
+// handleRestoreInitiated records a started restore. A restore started from
+// the confirm screen opens the monitoring view; a chained step that starts
+// later takes over the monitoring view only if it is already open, and a
+// chained step that fails to start is recorded without leaving the current view.
+func (m *Model) handleRestoreInitiated(msg restoreInitiatedMsg) []tea.Cmd {
+	job := m.jobBySeq(msg.seq)
+	chained := job != nil && job.after != nil
+
+	if msg.err != nil {
+		if job != nil {
+			job.state = jobFailed
+			job.note = msg.err.Error()
+			m.recordWorkflow(job)
+		}
+		if chained {
+			m.reportError(fmt.Sprintf("Chained restore #%d failed to start: %v", job.seq, msg.err), msg.err)
+			return append(m.advanceChain(job), m.notifyFinished(job))
+		}
+		if job != nil {
+			return []tea.Cmd{m.fail(msg.err), m.notifyFinished(job)}
+		}
+		return []tea.Cmd{m.fail(msg.err)}
+	}
+
+	if job != nil {
+		job.jobID = msg.jobID
+		job.state = jobActive
+		m.recordJob(job)
+		m.recordWorkflow(job)
+	}
+	var notify tea.Cmd
+	if job != nil {
+		notify = m.notifyStep(job, config.EventStarted)
+	}
+	if chained {
+		m.inform(fmt.Sprintf("Chained restore #%d started: %s", job.seq, msg.jobID))
+		if m.state != stateRestoring {
+			return []tea.Cmd{m.pollRestoreStatus(msg.jobID), notify}
+		}
+		m.restoreStart = job.started
+	} else {
+		m.state = stateRestoring
+		m.inform(fmt.Sprintf("Restore job started: %s", msg.jobID))
+	}
+	m.restoreJobID = msg.jobID
+	m.restoreStatus = nil
+	return []tea.Cmd{m.pollRestoreStatus(msg.jobID), m.tickSpinner(), notify}
+}
+
+// handleRestoreStatus applies a polled restore status. Jobs in the jobs view
+// are polled until terminal whichever view is open, so queued steps start on
+// time; when a job completes, the next step in its chain is started.
+func (m *Model) handleRestoreStatus(msg restoreStatusMsg) []tea.Cmd {
+	jobID := msg.jobID
+	if jobID == "" {
+		jobID = m.restoreJobID
+	}
+	job := m.jobByID(jobID)
+	monitored := jobID == m.restoreJobID
+	tracked := job != nil && job.state == jobActive
+
+	if msg.err != nil {
+		m.reportError(fmt.Sprintf("Error checking restore: %v", msg.err), msg.err)
+		if tracked {
+			return []tea.Cmd{m.pollRestoreStatus(jobID)}
+		}
+		return nil
+	}
+
+	if monitored {
+		m.restoreStatus = msg.status
+	}
+	if job != nil {
+		job.status = msg.status
+	}
+	if !msg.status.IsTerminal {
+		if tracked || (monitored && m.state == stateRestoring) {
+			return []tea.Cmd{m.pollRestoreStatus(jobID)}
+		}
+		return nil
+	}
+
+	if job == nil {
+		status := fmt.Sprintf("Restore %s: %s", msg.status.Status, msg.status.StatusMessage)
+		if msg.status.Status == "COMPLETED" {
+			m.inform(status)
+		} else {
+			m.reportWarning(status)
+		}
+		return nil
+	}
+	job.state = jobFailed
+	if msg.status.Status == "COMPLETED" {
+		job.state = jobCompleted
+		job.note = efsRestoreNote(job)
+	} else {
+		job.note = msg.status.StatusMessage
+	}
+	m.recordJob(job)
+	m.recordWorkflow(job)
+	status := fmt.Sprintf("%s #%d %s: %s", job.noun(), job.seq, msg.status.Status, msg.status.StatusMessage)
+	if job.state == jobFailed {
+		m.reportWarning(status)
+	} else {
+		m.inform(status)
+	}
+	return append(m.advanceChain(job), m.tagRestored(job), m.notifyFinished(job))
+}
+
+// tagRestored returns a command tagging the resource a completed restore
+// created with the operator's identity, and with the original resource's
+// tags if the restore was confirmed with them copied, or nil if there is
+// nothing to tag: the job is not a restore, or was started outside the TUI.
+func (m *Model) tagRestored(job *restoreJob) tea.Cmd {
+	if job.kind != aws.JobKindRestore || job.imported || job.state != jobCompleted {
+		return nil
+	}
+	client, rp, status, copyTags := m.backupClient, job.backup, job.status, job.options.CopyTags
+	return func() tea.Msg {
+		msg := restoredTaggedMsg{seq: job.seq, err: client.TagRestoredResource(m.ctx, rp, status)}
+		if copyTags {
+			msg.copied, msg.copyErr = client.CopyTagsToRestored(m.ctx, rp, status)
+		}
+		return msg
+	}
+}
+
 // fetchRestoreMetadata returns a command that fetches restore parameters for preview.
 func (m *Model) fetchRestoreMetadata() tea.Cmd {
 	if m.selectedIdx >= len(m.backups) {
@@ -948,6 +2435,120 @@ func (m *Model) fetchRestoreMetadata() tea.Cmd {
 	}
 }
 
+// openDetail shows the selected backup in the detail view and starts the
+// live lookups of its restore target and the restore prefetch.
+func (m *Model) openDetail() tea.Cmd {
+	rp := m.backups[m.selectedIdx]
+	m.detailModel.SetRecoveryPoint(&m.backups[m.selectedIdx])
+	m.detailModel.SetDetails(m.enrich.details[rp.RecoveryPointARN])
+	m.setDetailProtection()
+	m.setDetailVerification()
+	m.state = stateDetail
+	m.restoreMetadata = nil
+	prefetch := m.prefetchRestore()
+	if lookup := kindOf(rp.ResourceType).lookup; lookup != nil {
+		return tea.Batch(lookup(m, rp), prefetch)
+	}
+	return prefetch
+}
+
+// fetchFileSystem returns a command that looks up the live EFS file system
+// an EFS recovery point would be restored into.
+func (m *Model) fetchFileSystem(fileSystemID string) tea.Cmd {
+	client := m.backupClient
+	return func() tea.Msg {
+		info, err := client.DescribeFileSystem(m.ctx, fileSystemID)
+		return fileSystemMsg{fileSystemID: fileSystemID, info: info, err: err}
+	}
+}
+
+// schemaWarning returns a warning when rp is a database backup taken before
+// the last OpenEMR upgrade, or "" otherwise. An upgrade migrates the schema
+// forward; restoring older data leaves the new code on the old schema.
+func (m *Model) schemaWarning(rp aws.RecoveryPoint) string {
+	up := m.appUpgrade
+	if up == nil || rp.ResourceType != "RDS" || !rp.CreationDate.Before(up.At) {
+		return ""
+	}
+	return fmt.Sprintf("This backup predates the OpenEMR upgrade from %s to %s\n"+
+		"(task definition revision %d, registered %s). Restoring it puts an\n"+
+		"older database schema under the running application; OpenEMR does not\n"+
+		"support schema downgrades. Plan to redeploy %s or upgrade the schema.",
+		up.From, up.To, up.Revision, up.At.Local().Format("2006-01-02 15:04 MST"), up.From)
+}
+
+// fetchClusterHealth returns a command that looks up the health of the
+// stack's current database cluster, which an RDS restore would replace.
+func (m *Model) fetchClusterHealth() tea.Cmd {
+	client, stackName := m.backupClient, m.stackName
+	return func() tea.Msg {
+		h, err := client.GetClusterHealth(m.ctx, stackName)
+		return clusterHealthMsg{health: h, err: err}
+	}
+}
+
+// resolvePlanRole returns a command that resolves the backup plan and IAM role
+// used for restores from the current vault. With refresh set, the client's
+// plan cache is re-read from AWS.
+func (m *Model) resolvePlanRole(refresh bool) tea.Cmd {
+	vaultName := m.vaultName
+	return func() tea.Msg {
+		if vaultName == "" {
+			return planRoleMsg{err: fmt.Errorf("vault name is empty")}
+		}
+		pr, err := m.backupClient.ResolvePlanRole(m.ctx, vaultName, refresh)
+		return planRoleMsg{planRole: pr, err: err}
+	}
+}
+
+// planLine describes the backup plan the restore role was taken from.
+func (m *Model) planLine() string {
+	pr := m.planRole
+	if pr.Fallback {
+		return "Plan:  none targets this vault"
+	}
+	if pr.ViaCopy {
+		return fmt.Sprintf("Plan:  %s (%s), copies into this vault", pr.PlanName, pr.PlanID)
+	}
+	return fmt.Sprintf("Plan:  %s (%s)", pr.PlanName, pr.PlanID)
+}
+
+// renderField renders restore parameter i, marking it when focused.
+func (m *Model) renderField(i int, f restoreField, style lipgloss.Style) string {
+	value := f.value
+	if f.key == "IamRoleArn" && m.planRole != nil && m.planRole.Fallback {
+		value += " (default service role)"
+	}
+	if i != m.confirmField {
+		return style.Render(fmt.Sprintf("  %-12s %s", f.label+":", value))
+	}
+	focusStyle := style.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	return focusStyle.Render(fmt.Sprintf("▸ %-12s %s  [%s]", f.label+":", value, f.key))
+}
+
+// renderFieldHelp renders the contextual help box for a restore metadata key.
+func renderFieldHelp(resourceType, key string) string {
+	help, ok := fieldHelpFor(resourceType, key)
+	if !ok {
+		return ""
+	}
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(0, 1).
+		Width(72)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+
+	return boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render(key),
+		help.what,
+		"",
+		warnStyle.Render("If changed: ")+help.ifChange,
+	))
+}
+
 // renderRestoring renders the restore monitoring view with live status.
 func (m *Model) renderRestoring() string {
 	header := m.renderHeader()
@@ -978,12 +2579,12 @@ func (m *Model) renderRestoring() string {
 
 	if m.restoreStatus != nil {
 		rs := m.restoreStatus
-		statusColor := lipgloss.Color("114") // green
+		statusColor := ui.ColorOK
 		switch rs.Status {
 		case "FAILED", "ABORTED":
-			statusColor = lipgloss.Color("196") // red
+			statusColor = ui.ColorFail
 		case "PENDING", "RUNNING":
-			statusColor = lipgloss.Color("214") // yellow/orange
+			statusColor = ui.ColorWarn
 		}
 		statusStyle := lipgloss.NewStyle().Foreground(statusColor).Bold(true)
 
@@ -1003,6 +2604,7 @@ func (m *Model) renderRestoring() string {
 			sections = append(sections, infoStyle.Render(fmt.Sprintf("Duration: %s", duration)))
 		}
 	}
+	sections = append(sections, m.efsRestoreLines(titleStyle, infoStyle)...)
 
 	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
 	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(content))
@@ -1010,27 +2612,74 @@ func (m *Model) renderRestoring() string {
 
 // cycleFilter advances the in-app filter and re-filters the backup list.
 func (m *Model) cycleFilter() {
-	m.activeFilter = m.activeFilter.next()
+	selected := m.selectedARN()
+	m.activeFilter = m.activeFilter.next(m.filterTypes())
 	m.applyFilter()
 	m.listModel.SetItems(m.formatBackupsForList())
+	m.selectARN(selected)
 }
 
-// applyFilter filters allBackups based on the active filter mode.
+// cycleSort advances the sort order and re-sorts the backup list, keeping
+// the selected backup selected.
+func (m *Model) cycleSort() {
+	selected := m.selectedARN()
+	m.activeSort = m.activeSort.next()
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+	m.selectARN(selected)
+}
+
+// applyFilter filters allBackups based on the active filter mode and sorts
+// the result by the active sort order.
 func (m *Model) applyFilter() {
-	if m.activeFilter == filterAll {
-		m.backups = m.allBackups
-		return
-	}
-	filterStr := m.activeFilter.String()
 	filtered := make([]aws.RecoveryPoint, 0, len(m.allBackups))
 	for _, bp := range m.allBackups {
-		if bp.ResourceType == filterStr {
+		if m.deletes.holds(bp.RecoveryPointARN) {
+			continue // Back on the list if the deletion is undone
+		}
+		if m.activeFilter == filterAll || bp.ResourceType == string(m.activeFilter) {
 			filtered = append(filtered, bp)
 		}
 	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		switch m.activeSort {
+		case sortOldest:
+			return filtered[i].CreationDate.Before(filtered[j].CreationDate)
+		case sortLargest:
+			return filtered[i].BackupSizeInBytes > filtered[j].BackupSizeInBytes
+		default:
+			return filtered[i].CreationDate.After(filtered[j].CreationDate)
+		}
+	})
 	m.backups = filtered
 }
 
+// selectedARN returns the ARN of the backup under the list cursor, or "".
+func (m *Model) selectedARN() string {
+	idx := m.listModel.SelectedIndex()
+	if idx >= 0 && idx < len(m.backups) {
+		return m.backups[idx].RecoveryPointARN
+	}
+	return ""
+}
+
+// selectARN moves the list cursor to the backup with the given ARN and
+// reports whether it was found.
+func (m *Model) selectARN(arn string) bool {
+	if arn == "" {
+		return false
+	}
+	for i, bp := range m.backups {
+		if bp.RecoveryPointARN == arn {
+			m.listModel.SetCursor(i)
+			m.selectedIdx = i
+			return true
+		}
+	}
+	return false
+}
+
 // relativeTime returns a human-readable relative time string (e.g., "2h ago", "3d ago").
 func relativeTime(t time.Time) string {
 	d := time.Since(t)
@@ -1055,19 +2704,10 @@ func relativeTime(t time.Time) string {
 	}
 }
 
-// freshnessIndicator returns a colored dot based on backup age.
-// Color numbers are ANSI 256 (Xterm) codes: 114=PaleGreen3, 214=Orange1, 196=Red1.
-// Full palette reference: https://www.ditig.com/256-colors-cheat-sheet
+// freshnessIndicator returns a dot based on backup age: a full, half, or
+// empty circle in the theme's OK, Warn, or Fail color (see ui.Themes).
 func freshnessIndicator(t time.Time) string {
-	age := time.Since(t)
-	switch {
-	case age < 24*time.Hour:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("114")).Render("●") // green
-	case age < 7*24*time.Hour:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("●") // yellow
-	default:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("●") // red
-	}
+	return ui.FreshnessLevel(t).Dot()
 }
 
 // RelativeTime is an exported wrapper for use by UI components.
@@ -2,15 +2,39 @@ package app
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/deeplink"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
 	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/webhook"
+	"github.com/zalando/go-keyring"
 )
 
+// TestMain keeps the job history's encryption key in memory rather than the
+// OS keyring.
+func TestMain(m *testing.M) {
+	keyring.MockInit()
+	os.Exit(m.Run())
+}
+
 func newTestModel() *Model {
 	m := &Model{
 		ctx:             context.Background(),
@@ -20,7 +44,7 @@ func newTestModel() *Model {
 		state:           stateList,
 		selectedIdx:     0,
 		vaultDiscovered: true,
-		listModel:       ui.NewListModel(),
+		listModel:       ui.NewListModel(backupColumns(config.DefaultListColumns)...),
 		detailModel:     ui.DetailModel{},
 		helpModel:       ui.HelpModel{},
 	}
@@ -291,17 +315,71 @@ func TestFormatBackupsForList(t *testing.T) {
 	if len(items) != 2 {
 		t.Fatalf("expected 2 items, got %d", len(items))
 	}
-	if !strings.Contains(items[0], "RDS") {
+	if !strings.Contains(items[0].String(), "RDS") {
 		t.Error("first item should contain RDS")
 	}
-	if !strings.Contains(items[0], "my-cluster") {
+	if !strings.Contains(items[0].String(), "my-cluster") {
 		t.Error("first item should contain my-cluster")
 	}
-	if !strings.Contains(items[1], "EFS") {
+	if !strings.Contains(items[1].String(), "EFS") {
 		t.Error("second item should contain EFS")
 	}
 }
 
+func TestModel_ListColumnsFitWindow(t *testing.T) {
+	m := newTestModel()
+	m.allBackups = sampleBackups()
+	m.allBackups[1].ResourceID = "fs-0123456789abcdef0-openemr-sites-and-documents"
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	view := m.View().Content
+	if !strings.Contains(view, "Resource ID") || !strings.Contains(view, "fs-0123456789abcdef0-") || strings.Contains(view, "documents") {
+		t.Errorf("a long resource ID should be truncated to fit the window:\n%s", view)
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if line = strings.TrimRight(line, " "); strings.Contains(line, "1.0 GB") && lipgloss.Width(line) > 100 {
+			t.Errorf("rows should fit the window, got %d cells: %q", lipgloss.Width(line), line)
+		}
+	}
+}
+
+func TestModel_ListFitsWindowHeight(t *testing.T) {
+	m := newTestModel()
+	now := time.Now()
+	for i := range 120 {
+		m.allBackups = append(m.allBackups, aws.RecoveryPoint{
+			RecoveryPointARN: fmt.Sprintf("rp-%03d", i+1),
+			ResourceType:     "RDS",
+			ResourceID:       fmt.Sprintf("cluster-%03d", i+1),
+			CreationDate:     now.Add(-time.Duration(i) * time.Hour),
+			Status:           "COMPLETED",
+		})
+	}
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	if lines := lipgloss.Height(m.View().Content); lines > 30 {
+		t.Errorf("the list view should fit 30 lines, got %d", lines)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'G', Text: "G"})
+	view := m.View().Content
+	if last := m.backups[len(m.backups)-1].ResourceID; !strings.Contains(view, last) || lipgloss.Height(view) > 30 {
+		t.Errorf("G should scroll %s into the window:\n%s", last, view)
+	}
+	if m.selectedIdx != len(m.backups)-1 {
+		t.Errorf("G should select the last backup, got index %d", m.selectedIdx)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'g', Text: "g"})
+	if view := m.View().Content; !strings.Contains(view, m.backups[0].ResourceID) || m.selectedIdx != 0 {
+		t.Errorf("g should scroll back to the first backup:\n%s", view)
+	}
+}
+
 func TestFormatBytes_Model(t *testing.T) {
 	tests := []struct {
 		input    int64
@@ -391,8 +469,8 @@ func TestModel_RestoreInitiatedMsg(t *testing.T) {
 	updated, _ := m.Update(msg)
 	model := updated.(*Model)
 
-	if !strings.Contains(model.statusMsg, "job-12345") {
-		t.Errorf("statusMsg should contain job ID, got %q", model.statusMsg)
+	if !strings.Contains(model.statusMessage(), "job-12345") {
+		t.Errorf("statusMsg should contain job ID, got %q", model.statusMessage())
 	}
 }
 
@@ -449,7 +527,6 @@ func TestModel_ErrorHints(t *testing.T) {
 	}{
 		{"backup vault not found", "vault"},
 		{"CloudFormation stack error", "credentials"},
-		{"NoCredentialProviders", "AWS credentials"},
 		{"discover error", "CloudFormation stack"},
 	}
 
@@ -613,7 +690,7 @@ func TestWorkflow_StatusBarContent(t *testing.T) {
 	}
 
 	// With status message
-	m.statusMsg = "Restore job started: job-xyz"
+	m.inform("Restore job started: job-xyz")
 	status = m.renderStatusBar()
 	if !strings.Contains(status, "job-xyz") {
 		t.Error("status bar should show status message when set")
@@ -658,23 +735,20 @@ func TestRelativeTime_Exported(t *testing.T) {
 func TestFreshnessIndicator(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
-		name     string
-		t        time.Time
-		notEmpty bool
+		name  string
+		t     time.Time
+		glyph string
 	}{
-		{"fresh (< 24h)", now.Add(-1 * time.Hour), true},
-		{"recent (1-7d)", now.Add(-3 * 24 * time.Hour), true},
-		{"stale (> 7d)", now.Add(-10 * 24 * time.Hour), true},
+		{"fresh (< 24h)", now.Add(-1 * time.Hour), "●"},
+		{"recent (1-7d)", now.Add(-3 * 24 * time.Hour), "◐"},
+		{"stale (> 7d)", now.Add(-10 * 24 * time.Hour), "○"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := freshnessIndicator(tt.t)
-			if tt.notEmpty && result == "" {
-				t.Error("freshnessIndicator() should not return empty string")
-			}
-			if !strings.Contains(result, "●") {
-				t.Errorf("freshnessIndicator() = %q, should contain dot character", result)
+			result := ui.StripANSI(freshnessIndicator(tt.t))
+			if result != tt.glyph {
+				t.Errorf("freshnessIndicator() = %q, want %q", result, tt.glyph)
 			}
 		})
 	}
@@ -688,30 +762,36 @@ func TestFilterMode_String(t *testing.T) {
 		want string
 	}{
 		{filterAll, "All"},
-		{filterRDS, "RDS"},
-		{filterEFS, "EFS"},
+		{"RDS", "RDS"},
+		{"EFS", "EFS"},
 	}
 	for _, tt := range tests {
 		if got := tt.mode.String(); got != tt.want {
-			t.Errorf("filterMode(%d).String() = %q, want %q", tt.mode, got, tt.want)
+			t.Errorf("filterMode(%q).String() = %q, want %q", string(tt.mode), got, tt.want)
 		}
 	}
 }
 
 func TestFilterMode_Next(t *testing.T) {
+	types := []string{"RDS", "EFS", "DynamoDB"}
 	tests := []struct {
 		mode filterMode
 		want filterMode
 	}{
-		{filterAll, filterRDS},
-		{filterRDS, filterEFS},
-		{filterEFS, filterAll},
+		{filterAll, "RDS"},
+		{"RDS", "EFS"},
+		{"EFS", "DynamoDB"},
+		{"DynamoDB", filterAll},
+		{"Aurora", filterAll}, // no longer in the vault
 	}
 	for _, tt := range tests {
-		if got := tt.mode.next(); got != tt.want {
-			t.Errorf("filterMode(%d).next() = %d, want %d", tt.mode, got, tt.want)
+		if got := tt.mode.next(types); got != tt.want {
+			t.Errorf("filterMode(%q).next() = %q, want %q", string(tt.mode), got, tt.want)
 		}
 	}
+	if got := filterAll.next(nil); got != filterAll {
+		t.Errorf("an empty vault has no types to filter by, got %q", got)
+	}
 }
 
 func TestModel_CycleFilter(t *testing.T) {
@@ -726,8 +806,8 @@ func TestModel_CycleFilter(t *testing.T) {
 
 	// Cycle to RDS
 	m.cycleFilter()
-	if m.activeFilter != filterRDS {
-		t.Errorf("expected filterRDS, got %v", m.activeFilter)
+	if m.activeFilter != "RDS" {
+		t.Errorf("expected RDS filter, got %v", m.activeFilter)
 	}
 	for _, bp := range m.backups {
 		if bp.ResourceType != "RDS" {
@@ -737,8 +817,8 @@ func TestModel_CycleFilter(t *testing.T) {
 
 	// Cycle to EFS
 	m.cycleFilter()
-	if m.activeFilter != filterEFS {
-		t.Errorf("expected filterEFS, got %v", m.activeFilter)
+	if m.activeFilter != "EFS" {
+		t.Errorf("expected EFS filter, got %v", m.activeFilter)
 	}
 	for _, bp := range m.backups {
 		if bp.ResourceType != "EFS" {
@@ -766,8 +846,8 @@ func TestModel_CycleFilter_ViaKeyPress(t *testing.T) {
 	result, _ := m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
 	model := result.(*Model)
 
-	if model.activeFilter != filterRDS {
-		t.Errorf("expected filterRDS after pressing f, got %v", model.activeFilter)
+	if model.activeFilter != "RDS" {
+		t.Errorf("expected RDS filter after pressing f, got %v", model.activeFilter)
 	}
 }
 
@@ -778,7 +858,7 @@ func TestModel_ApplyFilter_EmptyResult(t *testing.T) {
 	}
 	m.backups = m.allBackups
 
-	m.activeFilter = filterEFS
+	m.activeFilter = "EFS"
 	m.applyFilter()
 
 	if len(m.backups) != 0 {
@@ -840,8 +920,8 @@ func TestModel_RestoreStatusMsg_Completed(t *testing.T) {
 	result, _ := m.Update(msg)
 	model := result.(*Model)
 
-	if !strings.Contains(model.statusMsg, "COMPLETED") {
-		t.Errorf("expected statusMsg to contain COMPLETED, got %q", model.statusMsg)
+	if !strings.Contains(model.statusMessage(), "COMPLETED") {
+		t.Errorf("expected statusMsg to contain COMPLETED, got %q", model.statusMessage())
 	}
 }
 
@@ -853,8 +933,8 @@ func TestModel_RestoreStatusMsg_Error(t *testing.T) {
 	result, _ := m.Update(msg)
 	model := result.(*Model)
 
-	if !strings.Contains(model.statusMsg, "poll failed") {
-		t.Errorf("expected error in statusMsg, got %q", model.statusMsg)
+	if !strings.Contains(model.statusMessage(), "poll failed") {
+		t.Errorf("expected error in statusMsg, got %q", model.statusMessage())
 	}
 }
 
@@ -1066,7 +1146,7 @@ func TestFormatBackupsForList_IncludesRelativeTime(t *testing.T) {
 	if len(items) != 1 {
 		t.Fatalf("expected 1 item, got %d", len(items))
 	}
-	if !strings.Contains(items[0], "2h ago") {
+	if !strings.Contains(items[0].String(), "2h ago") {
 		t.Errorf("formatted item should contain relative time, got: %s", items[0])
 	}
 }
@@ -1078,7 +1158,7 @@ func TestModel_StatusBar_WithFilter(t *testing.T) {
 	m.state = stateList
 	m.allBackups = sampleBackups()
 	m.backups = []aws.RecoveryPoint{m.allBackups[0]} // Only RDS
-	m.activeFilter = filterRDS
+	m.activeFilter = "RDS"
 
 	status := m.renderStatusBar()
 	if !strings.Contains(status, "1 of 2") {
@@ -1102,7 +1182,7 @@ func TestWorkflow_FilterToggle(t *testing.T) {
 	// Press f to filter to RDS
 	result, _ := m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
 	m = result.(*Model)
-	if m.activeFilter != filterRDS {
+	if m.activeFilter != "RDS" {
 		t.Fatalf("expected RDS filter, got %v", m.activeFilter)
 	}
 	if len(m.backups) != 1 || m.backups[0].ResourceType != "RDS" {
@@ -1112,7 +1192,7 @@ func TestWorkflow_FilterToggle(t *testing.T) {
 	// Press f to filter to EFS
 	result, _ = m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
 	m = result.(*Model)
-	if m.activeFilter != filterEFS {
+	if m.activeFilter != "EFS" {
 		t.Fatalf("expected EFS filter, got %v", m.activeFilter)
 	}
 	if len(m.backups) != 1 || m.backups[0].ResourceType != "EFS" {
@@ -1174,8 +1254,8 @@ func TestWorkflow_RestoreMonitoring(t *testing.T) {
 	result, _ = m.Update(completeMsg)
 	m = result.(*Model)
 
-	if !strings.Contains(m.statusMsg, "COMPLETED") {
-		t.Errorf("expected COMPLETED in statusMsg, got %q", m.statusMsg)
+	if !strings.Contains(m.statusMessage(), "COMPLETED") {
+		t.Errorf("expected COMPLETED in statusMsg, got %q", m.statusMessage())
 	}
 
 	// Press esc to go back to list
@@ -1387,7 +1467,7 @@ func TestModel_View_LoadingBackups(t *testing.T) {
 
 func TestModel_RenderHeader_WithCLIResourceType(t *testing.T) {
 	m := newTestModel()
-	m.resourceType = "RDS"
+	m.resourceTypes = []string{"RDS"}
 
 	header := m.renderHeader()
 	if !strings.Contains(header, "Filter") || !strings.Contains(header, "RDS") {
@@ -1395,9 +1475,131 @@ func TestModel_RenderHeader_WithCLIResourceType(t *testing.T) {
 	}
 }
 
+func TestModel_MultiTypeFilter(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName, m.vaultDiscovered = fx.Vaults[0], true
+	m.resourceTypes = []string{"RDS", "EFS"}
+
+	if header := m.renderHeader(); !strings.Contains(header, "Filter: RDS, EFS") {
+		t.Errorf("header should show both CLI types, got: %s", header)
+	}
+	m.Update(m.loadBackups()())
+	if len(m.allBackups) == 0 {
+		t.Fatal("the fixture vault should have RDS and EFS backups")
+	}
+	for _, bp := range m.allBackups {
+		if bp.ResourceType != "RDS" && bp.ResourceType != "EFS" {
+			t.Errorf("only RDS and EFS backups should be listed, got %s", bp.ResourceType)
+		}
+	}
+	if status := m.renderStatusBar(); !strings.Contains(status, "of type RDS, EFS") {
+		t.Errorf("status bar should name the types listed, got: %s", status)
+	}
+
+	m.resourceTypes = []string{"Aurora", "DynamoDB"}
+	m.Update(m.loadBackups()())
+	if status := m.renderStatusBar(); !strings.Contains(status, "hidden by filters (Aurora/DynamoDB filter)") {
+		t.Errorf("an empty filtered list should name the types hiding the vault's backups, got: %s", status)
+	}
+}
+
+func TestModel_EnvInfoPanel(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName, m.vaultName, m.region = fx.Stacks[0].Name, fx.Vaults[0], "us-west-2"
+	m.planRole = &aws.PlanRole{RoleARN: "arn:aws:iam::123456789012:role/backup-role"}
+	m.state = stateList
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'i', Text: "i"})
+	if m.state != stateEnvInfo || cmd == nil {
+		t.Fatal("i should open the environment info panel and load the stack's identifiers")
+	}
+	m.Update(cmd())
+	view := m.View().Content
+	for _, want := range []string{
+		"Account", "123456789012",
+		"arn:aws:cloudformation:us-west-2:123456789012:stack/" + fx.Stacks[0].Name + "/",
+		"arn:aws:backup:us-west-2:123456789012:backup-vault:" + fx.Vaults[0],
+		"arn:aws:iam::123456789012:role/backup-role",
+		"arn:aws:rds:us-west-2:123456789012:cluster:openemr-training-cluster",
+		"EFS ID", "fs-0sim0001",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("panel should list %q:\n%s", want, view)
+		}
+	}
+
+	fields := m.envFields()
+	if fields[0].label != "Region" {
+		t.Fatalf("unexpected first field %+v", fields[0])
+	}
+	link, err := deeplink.Parse(fields[len(fields)-1].value)
+	if err != nil || link.View != tabList || link.Stack != m.stackName || link.Vault != m.vaultName || link.Region != m.region {
+		t.Errorf("last field should link to the list of this environment, got %+v (%v)", link, err)
+	}
+	m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil || m.warnings.current == nil || m.warnings.current.Message != "Copied "+fields[1].label {
+		t.Errorf("enter should copy the selected identifier, got %v", m.warnings.current)
+	}
+	if _, cmd = m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"}); cmd == nil {
+		t.Error("a should copy every identifier")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.state != stateList {
+		t.Errorf("esc should return to the list, got state %d", m.state)
+	}
+}
+
+func TestModel_HiddenByFiltersSummary(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName, m.vaultDiscovered = fx.Vaults[0], true
+	m.resourceTypes = []string{"DynamoDB"}
+
+	m.Update(m.loadBackups()())
+	total := m.listing.Seen
+	if total == 0 || len(m.backups) != 0 {
+		t.Fatalf("the fixture vault should have only other types, got %d of %d", len(m.backups), total)
+	}
+	want := fmt.Sprintf("%d point(s) hidden by filters (DynamoDB filter)", total)
+	if view := m.View().Content; !strings.Contains(view, want) || !strings.Contains(view, "F to clear filters") || strings.Contains(view, "No backups found") {
+		t.Errorf("the list should say %q and how to clear the filters:\n%s", want, view)
+	}
+
+	m.listing.HiddenByStatus = map[string]int{"DELETED": 3}
+	if got := m.hiddenSummary(); !strings.HasSuffix(got, "(DynamoDB filter, 3 DELETED)") {
+		t.Errorf("points hidden by status should be counted, got %q", got)
+	}
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'F', Text: "F"})
+	if m.resourceTypes != nil || m.state != stateLoading || cmd == nil {
+		t.Fatal("F should clear the -type filter and reload the vault")
+	}
+	m.Update(m.loadBackups()())
+	if len(m.backups) != total || m.hiddenSummary() != "" {
+		t.Errorf("every backup should be listed once the filters are cleared, got %d of %d", len(m.backups), total)
+	}
+
+	m.activeFilter = "DynamoDB"
+	m.applyFilter()
+	if got := m.hiddenSummary(); !strings.Contains(got, "(DynamoDB filter)") {
+		t.Errorf("the in-app filter should be named, got %q", got)
+	}
+	m.Update(tea.KeyPressMsg{Code: 'F', Text: "F"})
+	if m.activeFilter != filterAll || len(m.backups) != total {
+		t.Error("F should clear the in-app filter without reloading")
+	}
+}
+
 func TestModel_RenderHeader_WithActiveFilter(t *testing.T) {
 	m := newTestModel()
-	m.activeFilter = filterEFS
+	m.activeFilter = "EFS"
 
 	header := m.renderHeader()
 	if !strings.Contains(header, "EFS") {
@@ -1418,8 +1620,8 @@ func TestModel_RenderHeader_DiscoveringVault(t *testing.T) {
 
 func TestModel_RenderHeader_InAppFilterOverridesCLI(t *testing.T) {
 	m := newTestModel()
-	m.resourceType = "RDS"
-	m.activeFilter = filterEFS
+	m.resourceTypes = []string{"RDS"}
+	m.activeFilter = "EFS"
 
 	header := m.renderHeader()
 	if !strings.Contains(header, "EFS") {
@@ -1507,17 +1709,17 @@ func TestFreshnessIndicator_Boundaries(t *testing.T) {
 
 	fresh := freshnessIndicator(now.Add(-23*time.Hour - 59*time.Minute))
 	if !strings.Contains(fresh, "●") {
-		t.Error("just under 24h should have dot")
+		t.Error("just under 24h should have a full dot")
 	}
 
 	midAge := freshnessIndicator(now.Add(-24*time.Hour - 1*time.Minute))
-	if !strings.Contains(midAge, "●") {
-		t.Error("just over 24h should have dot")
+	if !strings.Contains(midAge, "◐") {
+		t.Error("just over 24h should have a half dot")
 	}
 
 	weekOld := freshnessIndicator(now.Add(-7*24*time.Hour + 1*time.Hour))
-	if !strings.Contains(weekOld, "●") {
-		t.Error("just under 7d should have dot")
+	if !strings.Contains(weekOld, "◐") {
+		t.Error("just under 7d should have a half dot")
 	}
 }
 
@@ -1648,13 +1850,13 @@ func TestModel_ApplyFilter_AllSameType(t *testing.T) {
 		{ResourceType: "RDS", ResourceID: "c2", CreationDate: time.Now()},
 	}
 
-	m.activeFilter = filterRDS
+	m.activeFilter = "RDS"
 	m.applyFilter()
 	if len(m.backups) != 2 {
 		t.Errorf("expected 2 RDS backups, got %d", len(m.backups))
 	}
 
-	m.activeFilter = filterEFS
+	m.activeFilter = "EFS"
 	m.applyFilter()
 	if len(m.backups) != 0 {
 		t.Errorf("expected 0 EFS backups, got %d", len(m.backups))
@@ -1713,7 +1915,7 @@ func TestWorkflow_FilterThenSelect(t *testing.T) {
 	result, _ = m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
 	m = result.(*Model)
 
-	if m.activeFilter != filterEFS {
+	if m.activeFilter != "EFS" {
 		t.Fatalf("expected EFS filter, got %v", m.activeFilter)
 	}
 	if len(m.backups) != 1 {
@@ -1792,43 +1994,41 @@ func TestWorkflow_RefreshPreservesFilter(t *testing.T) {
 	m = result.(*Model)
 
 	// Filter should still be RDS even after refresh
-	if m.activeFilter != filterRDS {
+	if m.activeFilter != "RDS" {
 		t.Errorf("filter should be preserved after refresh, got %v", m.activeFilter)
 	}
 }
 
 // --- Unit Tests: Error hints additional cases ---
 
-func TestModel_ErrorHints_Authentication(t *testing.T) {
-	m := newTestModel()
-	m.state = stateError
-	m.err = errTestError("authentication failed")
-
-	rendered := m.renderError()
-	if !strings.Contains(rendered, "credentials") {
-		t.Error("authentication error should suggest credentials")
+func TestModel_ErrorHints_AuthProviders(t *testing.T) {
+	tests := []struct {
+		err      *aws.AuthError
+		contains string
+	}{
+		{&aws.AuthError{Provider: aws.AuthAuto, Err: errTestError("no EC2 IMDS role found")}, "IAM role"},
+		{&aws.AuthError{Provider: aws.AuthProfile, Profile: "ops", Err: errTestError("failed to get shared config profile")}, "aws configure --profile ops"},
+		{&aws.AuthError{Provider: aws.AuthSSO, Profile: "ops", Err: errTestError("the SSO session has expired")}, "aws sso login --profile ops"},
+		{&aws.AuthError{Provider: aws.AuthWebIdentity, Err: errTestError("InvalidIdentityToken")}, "eks.amazonaws.com/role-arn"},
 	}
-}
-
-func TestModel_ErrorHints_EC2RoleRequestError(t *testing.T) {
-	m := newTestModel()
-	m.state = stateError
-	m.err = errTestError("EC2RoleRequestError: something")
+	for _, tt := range tests {
+		m := newTestModel()
+		m.state = stateError
+		// The hint is found through the wrapping of a failed discovery
+		m.err = fmt.Errorf("failed to auto-discover CloudFormation stack: %w", tt.err)
 
-	rendered := m.renderError()
-	if !strings.Contains(rendered, "IAM role") {
-		t.Error("EC2 role error should mention IAM role")
+		rendered := m.renderError()
+		if !strings.Contains(rendered, tt.contains) {
+			t.Errorf("error hint for %s credentials should contain %q", tt.err.Provider, tt.contains)
+		}
 	}
-}
 
-func TestModel_ErrorHints_SharedCredsLoad(t *testing.T) {
+	// Error text alone no longer selects the credentials hint
 	m := newTestModel()
 	m.state = stateError
-	m.err = errTestError("SharedCredsLoad: cannot load")
-
-	rendered := m.renderError()
-	if !strings.Contains(rendered, "aws configure") {
-		t.Error("shared creds error should mention 'aws configure'")
+	m.err = errTestError("authentication failed: NoCredentialProviders")
+	if strings.Contains(m.renderError(), "aws configure") {
+		t.Error("only an AuthError should get the credentials hint")
 	}
 }
 
@@ -1905,3 +2105,3474 @@ func TestFormatBytes_LargeValue(t *testing.T) {
 type errTestError string
 
 func (e errTestError) Error() string { return string(e) }
+
+// --- Unit Tests: Backup Plan Role ---
+
+func TestModel_PlanRoleMsg(t *testing.T) {
+	m := newTestModel()
+	pr := &aws.PlanRole{VaultName: "test-vault", PlanID: "plan-123", PlanName: "daily", RoleARN: "arn:aws:iam::123456789012:role/backup"}
+
+	updated, _ := m.Update(planRoleMsg{planRole: pr})
+	model := updated.(*Model)
+
+	if model.planRole == nil || model.planRole.RoleARN != pr.RoleARN {
+		t.Errorf("planRole should be stored, got %+v", model.planRole)
+	}
+}
+
+func TestModel_PlanRoleMsg_ErrorIgnored(t *testing.T) {
+	m := newTestModel()
+
+	updated, _ := m.Update(planRoleMsg{err: errTestError("access denied")})
+	model := updated.(*Model)
+
+	if model.planRole != nil {
+		t.Error("planRole should stay nil on error")
+	}
+	if model.state != stateList {
+		t.Errorf("role resolution failure should not change state, got %d", model.state)
+	}
+}
+
+func TestModel_View_ConfirmShowsPlanRole(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.state = stateConfirm
+	m.planRole = &aws.PlanRole{PlanID: "plan-123", PlanName: "daily", RoleARN: "arn:aws:iam::123456789012:role/backup"}
+
+	content := m.View().Content
+	for _, want := range []string{"Restore Role", "daily", "plan-123", "role/backup"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("confirm view should contain %q", want)
+		}
+	}
+}
+
+func TestModel_View_ConfirmShowsFallbackRole(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.state = stateConfirm
+	m.planRole = &aws.PlanRole{Fallback: true, RoleARN: "arn:aws:iam::123456789012:role/service-role/AWSBackupDefaultServiceRole"}
+
+	content := m.View().Content
+	if !strings.Contains(content, "default service role") {
+		t.Error("confirm view should flag the fallback role")
+	}
+}
+
+func TestModel_Refresh_AlsoRefreshesPlanRole(t *testing.T) {
+	m := newTestModel()
+	m.state = stateList
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	if cmd == nil {
+		t.Fatal("refresh should return commands")
+	}
+}
+
+func TestModel_RenderHeader_ShowsRegionSource(t *testing.T) {
+	m := newTestModel()
+	m.regionSource = "AWS_REGION"
+
+	header := m.renderHeader()
+	if !strings.Contains(header, "us-west-2 (AWS_REGION)") {
+		t.Errorf("header should show region with its source, got: %s", header)
+	}
+}
+
+func TestModel_RenderHeader_RegionWithoutSource(t *testing.T) {
+	m := newTestModel()
+
+	header := m.renderHeader()
+	if !strings.Contains(header, "Region: us-west-2") {
+		t.Errorf("header should show region, got: %s", header)
+	}
+}
+
+func TestNewModel_UsesInjectedClient(t *testing.T) {
+	fx, err := aws.LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := aws.NewSimulatedBackupClient(fx)
+
+	m := NewModel(context.Background(), Options{StackName: "OpenemrEcsStack", Region: fx.Region, Client: client})
+	if m.state == stateError {
+		t.Fatalf("injected client should not fail initialization: %v", m.err)
+	}
+	if m.backupClient != client {
+		t.Error("model should use the injected client")
+	}
+}
+
+func TestModel_StartTarget(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	client := aws.NewSimulatedBackupClient(fx)
+	backups := append(sampleBackups(), aws.RecoveryPoint{
+		RecoveryPointARN: "arn:aws:backup:us-west-2:123456789012:recovery-point:rp-3",
+		CreationDate:     time.Date(2026, 2, 16, 10, 0, 0, 0, time.UTC),
+		Status:           "PARTIAL",
+		ResourceType:     "RDS",
+		ResourceID:       "my-cluster",
+	})
+	open := func(opts Options) *Model {
+		opts.Region, opts.Client = fx.Region, client
+		return NewModel(context.Background(), opts)
+	}
+
+	m := open(Options{OpenResource: "my-cluster"})
+	m.Update(backupsLoadedMsg{backups: backups})
+	if m.state != stateDetail || m.backups[m.selectedIdx].RecoveryPointARN != backups[0].RecoveryPointARN {
+		t.Errorf("-resource should open the latest restorable backup, not the newer partial one: state %d, %+v", m.state, m.backups[m.selectedIdx])
+	}
+
+	m = open(Options{OpenARN: backups[1].RecoveryPointARN})
+	m.activeFilter = "RDS"
+	m.Update(backupsLoadedMsg{backups: backups})
+	if m.state != stateDetail || m.backups[m.selectedIdx].RecoveryPointARN != backups[1].RecoveryPointARN || m.activeFilter != filterAll {
+		t.Errorf("-arn should open a backup the filter hides, showing all types: state %d, filter %q", m.state, m.activeFilter)
+	}
+
+	m = open(Options{OpenARN: "arn:aws:backup:us-west-2:123456789012:recovery-point:gone"})
+	m.Update(backupsLoadedMsg{backups: backups})
+	if m.state != stateList || !strings.Contains(m.statusMessage(), "No backup of recovery point") {
+		t.Errorf("a missing recovery point should leave the list open with a warning, got state %d, %q", m.state, m.statusMessage())
+	}
+
+	m = open(Options{OpenView: tabJobs})
+	m.Update(backupsLoadedMsg{backups: backups})
+	if m.state != stateJobs {
+		t.Errorf("-open jobs should open the jobs view, got state %d", m.state)
+	}
+	if err := ValidateStartView("detail"); err == nil {
+		t.Error("an unknown view should be refused")
+	}
+}
+
+func TestModel_StackPicker(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	client := aws.NewSimulatedBackupClient(fx)
+	path := filepath.Join(t.TempDir(), "views.json")
+	if err := store.SaveView(path, fx.Region+"/OpenemrEcsStackDev", store.ViewState{Sort: "oldest"}); err != nil {
+		t.Fatal(err)
+	}
+	stacks := []aws.StackSummary{
+		{Name: "OpenemrEcsStack", Status: "UPDATE_COMPLETE", Created: time.Now().Add(-400 * 24 * time.Hour), Updated: time.Now().Add(-time.Hour)},
+		{Name: "OpenemrEcsStackDev", Status: "UPDATE_ROLLBACK_COMPLETE", Created: time.Now().Add(-30 * 24 * time.Hour)},
+	}
+
+	m := NewModel(context.Background(), Options{Region: fx.Region, Client: client, Stacks: stacks, ViewsPath: path, OpenView: tabJobs})
+	if m.state != stateStacks || m.Init() == nil {
+		t.Fatalf("several stacks and no -stack should open the stack selector, got state %d", m.state)
+	}
+	view := ui.StripANSI(m.View().Content)
+	for _, want := range []string{"2 OpenEMR stacks", "OpenemrEcsStackDev", "UPDATE_ROLLBACK_COMPLETE", "created", "updated"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("the selector should show %q:\n%s", want, view)
+		}
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	if views, _ := store.LoadViews(path); len(views) != 1 {
+		t.Errorf("nothing should be saved before a stack is chosen, got %v", views)
+	}
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.stackName != "OpenemrEcsStackDev" || m.state != stateLoading || cmd == nil {
+		t.Fatalf("enter should open the highlighted stack, got %q in state %d", m.stackName, m.state)
+	}
+	if m.activeSort != sortOldest || m.pendingTab != tabJobs {
+		t.Errorf("the stack's saved view should apply, with -open taking precedence: sort %s, tab %q", m.activeSort, m.pendingTab)
+	}
+
+	m = NewModel(context.Background(), Options{Region: fx.Region, Client: client, Stacks: stacks})
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("esc in the stack selector should quit")
+	}
+}
+
+func TestModel_IdleLock(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.allBackups = sampleBackups()
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+	m.idle.after = 15 * time.Minute
+
+	m.noteInput()
+	if m.Update(idleTickMsg{}); m.idle.locked {
+		t.Fatal("a session with recent input should not lock")
+	}
+	m.idle.lastInput = time.Now().Add(-20 * time.Minute)
+	m.Update(idleTickMsg{})
+	if view := m.View().Content; !m.idle.locked || strings.Contains(view, "my-cluster") || !strings.Contains(view, "Session Locked") {
+		t.Fatalf("an idle session should lock and hide the backups:\n%s", view)
+	}
+
+	// Background updates do not unlock it, and keys do not reach the list
+	m.Update(backupsLoadedMsg{backups: sampleBackups()})
+	m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	if !m.idle.locked || !m.idle.confirm || m.listModel.SelectedIndex() != 0 || strings.Contains(m.View().Content, "my-cluster") {
+		t.Fatalf("a key on the lock panel should only ask to confirm resuming: %+v", m.idle)
+	}
+	m.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	if !m.idle.locked || m.idle.confirm {
+		t.Fatal("n should stay locked")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if cmd == nil || !m.idle.checking {
+		t.Fatal("y should check the credentials before resuming")
+	}
+	if _, cmd = m.Update(cmd()); m.idle.locked || cmd == nil || !strings.Contains(m.View().Content, "my-cluster") {
+		t.Errorf("checked credentials should resume the session and schedule the next check: %+v", m.idle)
+	}
+
+	m.idle.locked = true
+	m.Update(idleUnlockMsg{err: errTestError("ExpiredToken")})
+	if !m.idle.locked || !strings.Contains(m.View().Content, "ExpiredToken") {
+		t.Error("expired credentials should keep the session locked and say why")
+	}
+}
+
+func TestModel_RenderHeader_SimulationBadge(t *testing.T) {
+	m := newTestModel()
+	if strings.Contains(m.renderHeader(), "SIMULATION") {
+		t.Error("badge should not show without a simulated client")
+	}
+
+	fx, _ := aws.LoadFixtures("")
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	if !strings.Contains(m.renderHeader(), "SIMULATION") {
+		t.Error("header should flag simulation mode")
+	}
+}
+
+// --- Sorting and vault switching ---
+
+func TestModel_CycleSort_KeepsSelection(t *testing.T) {
+	m := newTestModel()
+	m.allBackups = sampleBackups()
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+	m.listModel.SetCursor(1) // EFS backup (older, smaller)
+
+	m.Update(tea.KeyPressMsg{Code: 's', Text: "s"})
+	if m.activeSort != sortOldest {
+		t.Fatalf("activeSort = %v, want oldest first", m.activeSort)
+	}
+	if m.backups[0].ResourceType != "EFS" {
+		t.Error("oldest backup should be listed first")
+	}
+	if got := m.backups[m.listModel.SelectedIndex()].ResourceType; got != "EFS" {
+		t.Errorf("selection should follow the backup across re-sorting, got %s", got)
+	}
+	if !strings.Contains(m.renderHeader(), "Sort: Oldest first") {
+		t.Error("header should show a non-default sort order")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 's', Text: "s"})
+	if m.activeSort != sortLargest || m.backups[0].ResourceType != "RDS" {
+		t.Error("largest-first sort should list the 1 GB RDS backup first")
+	}
+}
+
+// newSwitchTestModel returns a model on the simulated prod vault with a
+// second "dr-vault" holding copies of the same resources.
+func newSwitchTestModel(t *testing.T) (*Model, string) {
+	t.Helper()
+	fx, err := aws.LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prod := fx.Vaults[0]
+	var copies []aws.FixtureRecoveryPoint
+	for i, rp := range fx.RecoveryPoints[prod] {
+		rp.RecoveryPointARN = fmt.Sprintf("arn:aws:backup:us-west-2:123456789012:recovery-point:dr-%d", i)
+		rp.AgeHours += 1
+		copies = append(copies, rp)
+	}
+	fx.Vaults = append(fx.Vaults, "dr-vault")
+	fx.RecoveryPoints["dr-vault"] = copies
+
+	m := newTestModel()
+	m.vaultName = prod
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	backups, err := m.backupClient.ListRecoveryPoints(context.Background(), prod, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Update(backupsLoadedMsg{backups: backups})
+	return m, prod
+}
+
+// doSwitch runs a vault switch command synchronously.
+func doSwitch(m *Model, region, vault string) {
+	msg := m.switchVault(vaultLocation{region: region, vault: vault})()
+	m.Update(msg)
+}
+
+func TestModel_SwitchVault_PreservesFilterSortAndResource(t *testing.T) {
+	m, _ := newSwitchTestModel(t)
+	m.cycleFilter() // RDS only
+	m.cycleSort()   // Oldest first
+	m.listModel.SetCursor(1)
+	wantResource := resourceKey(m.backups[1])
+
+	doSwitch(m, "us-west-2", "dr-vault")
+
+	if m.vaultName != "dr-vault" {
+		t.Fatalf("vaultName = %q", m.vaultName)
+	}
+	if m.activeFilter != "RDS" || m.activeSort != sortOldest {
+		t.Errorf("filter/sort should carry over to a new vault, got %v/%v", m.activeFilter, m.activeSort)
+	}
+	if got := resourceKey(m.backups[m.listModel.SelectedIndex()]); got != wantResource {
+		t.Errorf("selected resource = %s, want %s", got, wantResource)
+	}
+	if !strings.Contains(m.statusMessage(), "Switched to us-west-2/dr-vault") {
+		t.Errorf("statusMsg = %q", m.statusMessage())
+	}
+}
+
+func TestModel_SwitchVault_RestoresContextOnReturn(t *testing.T) {
+	m, prod := newSwitchTestModel(t)
+	m.listModel.SetCursor(3)
+	wantARN := m.selectedARN()
+
+	doSwitch(m, "us-west-2", "dr-vault")
+	m.cycleFilter() // change filter in the DR vault only
+	m.listModel.SetCursor(0)
+
+	m.Update(tea.KeyPressMsg{Code: '-', Text: "-"})
+	// "-" returns a batch; run the switch directly for determinism
+	doSwitch(m, "us-west-2", prod)
+
+	if m.vaultName != prod {
+		t.Fatalf("vaultName = %q, want %q", m.vaultName, prod)
+	}
+	if m.activeFilter != filterAll {
+		t.Errorf("prod vault's own filter should be restored, got %v", m.activeFilter)
+	}
+	if m.selectedARN() != wantARN {
+		t.Errorf("cursor should return to %s, got %s", wantARN, m.selectedARN())
+	}
+
+	doSwitch(m, "us-west-2", "dr-vault")
+	if m.activeFilter != "RDS" {
+		t.Errorf("DR vault's filter should be remembered, got %v", m.activeFilter)
+	}
+}
+
+func TestModel_AirGappedVault_BadgeAndRestrictions(t *testing.T) {
+	fx, err := aws.LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prod := fx.Vaults[0]
+	fx.Vaults = append(fx.Vaults, "dr-vault")
+	fx.RecoveryPoints["dr-vault"] = fx.RecoveryPoints[prod]
+	fx.VaultTypes = map[string]string{"dr-vault": aws.VaultTypeAirGapped}
+	fx.Plans[0].CopyTo = []string{"dr-vault"}
+
+	m := newTestModel()
+	m.vaultName = "dr-vault"
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.Update(m.describeVault()())
+	m.Update(m.resolvePlanRole(false)())
+	if !strings.Contains(m.renderHeader(), "AIR-GAPPED") {
+		t.Error("header should flag an air-gapped vault")
+	}
+
+	backups, _ := m.backupClient.ListRecoveryPoints(context.Background(), "dr-vault", "")
+	m.Update(backupsLoadedMsg{backups: backups})
+	m.state = stateConfirm
+	view := m.renderConfirm()
+	for _, want := range []string{"Air-gapped Vault:", "cannot be deleted", "copies into this vault"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("confirm screen missing %q:\n%s", want, view)
+		}
+	}
+
+	doSwitch(m, "us-west-2", prod)
+	if m.vaultInfo != nil || strings.Contains(m.renderHeader(), "AIR-GAPPED") {
+		t.Error("switching vaults should clear the air-gapped badge until the new vault is described")
+	}
+	m.Update(m.describeVault()())
+	if m.vaultInfo == nil || m.vaultInfo.AirGapped() {
+		t.Errorf("prod vault should be described as standard, got %+v", m.vaultInfo)
+	}
+}
+
+func TestModel_SwitchVault_FailureKeepsCurrentVault(t *testing.T) {
+	m, prod := newSwitchTestModel(t)
+	count := len(m.backups)
+
+	doSwitch(m, "us-west-2", "no-such-vault")
+
+	if m.vaultName != prod || len(m.backups) != count {
+		t.Error("failed switch should keep the current vault and backups")
+	}
+	if m.state != stateList || !strings.Contains(m.statusMessage(), "Could not switch") {
+		t.Errorf("expected list state with error status, got state %d, %q", m.state, m.statusMessage())
+	}
+	if m.vaultSwitch.previous != nil {
+		t.Error("failed switch should not record a previous vault")
+	}
+}
+
+func TestModel_SwitchVault_SimulationRejectsOtherRegion(t *testing.T) {
+	m, prod := newSwitchTestModel(t)
+
+	doSwitch(m, "eu-west-1", prod)
+
+	if m.region != "us-west-2" || !strings.Contains(m.statusMessage(), "simulation") {
+		t.Errorf("simulated client cannot switch regions, got region %s, status %q", m.region, m.statusMessage())
+	}
+}
+
+func TestModel_SwitchVaultPrompt(t *testing.T) {
+	m, _ := newSwitchTestModel(t)
+
+	m.Update(tea.KeyPressMsg{Code: 'v', Text: "v"})
+	if m.state != stateSwitchVault {
+		t.Fatalf("v should open the switch prompt, state = %d", m.state)
+	}
+	for _, r := range "dr-vaultx" {
+		m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	if m.vaultSwitch.input != "dr-vault" {
+		t.Errorf("input = %q, want dr-vault", m.vaultSwitch.input)
+	}
+	if m.state != stateSwitchVault {
+		t.Error("typed keys such as q and r must not leave the prompt")
+	}
+	if !strings.Contains(m.View().Content, "dr-vault") {
+		t.Error("prompt should render the typed input")
+	}
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateLoading || cmd == nil {
+		t.Error("enter should start loading the target vault")
+	}
+}
+
+func TestModel_SwitchVaultPicker(t *testing.T) {
+	m, prod := newSwitchTestModel(t)
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'v', Text: "v"})
+	if cmd == nil || !strings.Contains(m.View().Content, "Listing the vaults") {
+		t.Fatal("v should list the region's vaults")
+	}
+	m.Update(cmd())
+	view := m.View().Content
+	for _, want := range []string{"Vaults in us-west-2:", "dr-vault", prod, "(current)", "recovery points"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("picker should show %q:\n%s", want, view)
+		}
+	}
+	if vault, _ := m.highlightedVault(); vault.Name != prod {
+		t.Errorf("the first vault by name should be highlighted, got %q", vault.Name)
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	if vault, _ := m.highlightedVault(); vault.Name != "dr-vault" {
+		t.Errorf("down should highlight the next vault, got %q", vault.Name)
+	}
+
+	for _, r := range "DR" {
+		m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	if matches := m.matchingVaults(); len(matches) != 1 || matches[0].Name != "dr-vault" {
+		t.Fatalf("typing should narrow the vaults, ignoring case, got %+v", matches)
+	}
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateLoading || cmd == nil {
+		t.Fatal("enter should switch to the highlighted vault")
+	}
+	m.Update(m.switchVault(vaultLocation{region: m.region, vault: "dr-vault"})())
+	if m.vaultName != "dr-vault" {
+		t.Errorf("vaultName = %q, want dr-vault", m.vaultName)
+	}
+}
+
+func TestModel_RemembersVaultPerStack(t *testing.T) {
+	m, prod := newSwitchTestModel(t)
+	path := filepath.Join(t.TempDir(), "views.json")
+	m.stackName, m.viewsPath = "openemr", path
+	doSwitch(m, "us-west-2", "dr-vault")
+
+	next := newTestModel()
+	next.stackName, next.vaultName, next.viewsPath = "openemr", "", path
+	next.backupClient = m.backupClient
+	next.loadView()
+	if next.vaultName != "dr-vault" {
+		t.Fatalf("the vault last shown should reopen, got %q", next.vaultName)
+	}
+
+	_, cmd := next.Update(backupsLoadedMsg{err: errors.New("ResourceNotFoundException: vault dr-vault")})
+	if next.state == stateError || cmd == nil || !strings.Contains(next.statusMessage(), "finding the stack's vault") {
+		t.Fatalf("a remembered vault that fails to load should fall back to discovery, got state %d, %q", next.state, next.statusMessage())
+	}
+	if next.vaultName != "" {
+		t.Errorf("the remembered vault should be forgotten, got %q", next.vaultName)
+	}
+
+	named := newTestModel()
+	named.stackName, named.vaultName, named.viewsPath = "openemr", prod, path
+	named.loadView()
+	if named.vaultName != prod {
+		t.Errorf("-vault should win over the remembered vault, got %q", named.vaultName)
+	}
+}
+
+func TestModel_SwitchVaultPrompt_Escape(t *testing.T) {
+	m, prod := newSwitchTestModel(t)
+	m.Update(tea.KeyPressMsg{Code: 'v', Text: "v"})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.state != stateList || m.vaultName != prod {
+		t.Error("esc should cancel the switch")
+	}
+}
+
+func TestModel_ParseVaultLocation(t *testing.T) {
+	m := newTestModel()
+	loc, err := m.parseVaultLocation(" us-east-1/dr-vault ")
+	if err != nil || loc.region != "us-east-1" || loc.vault != "dr-vault" {
+		t.Errorf("got %+v, %v", loc, err)
+	}
+	loc, err = m.parseVaultLocation("other-vault")
+	if err != nil || loc.region != "us-west-2" || loc.vault != "other-vault" {
+		t.Errorf("plain vault should use current region, got %+v, %v", loc, err)
+	}
+	if _, err := m.parseVaultLocation("bogus/vault"); err == nil {
+		t.Error("expected invalid region error")
+	}
+	if _, err := m.parseVaultLocation("  "); err == nil {
+		t.Error("expected empty vault error")
+	}
+}
+
+func TestModel_SwitchToPrevious_None(t *testing.T) {
+	m := newTestModel()
+	if cmd := m.switchToPrevious(); cmd != nil {
+		t.Error("no previous vault should not start a switch")
+	}
+	if !strings.Contains(m.statusMessage(), "No previous vault") {
+		t.Errorf("statusMsg = %q", m.statusMessage())
+	}
+}
+
+// --- Restore parameter help ---
+
+func newConfirmTestModel() *Model {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.state = stateConfirm
+	m.restoreMetadata = &aws.RestoreMetadata{
+		ResourceType:   "RDS",
+		ClusterID:      "my-cluster",
+		SubnetGroup:    "subnet-group-1",
+		SecurityGroups: "sg-abc123",
+	}
+	m.planRole = &aws.PlanRole{PlanID: "plan-123", PlanName: "daily", RoleARN: "arn:aws:iam::123456789012:role/backup"}
+	return m
+}
+
+func TestModel_Confirm_NewCluster(t *testing.T) {
+	m := newConfirmTestModel()
+
+	m.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	if m.state != stateNewCluster {
+		t.Fatal("r on the confirm screen of an RDS restore should open the new cluster prompt")
+	}
+	m.Update(tea.PasteMsg{Content: "bad_name"})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateNewCluster || m.restoreOpts.ClusterSuffix != "" {
+		t.Fatal("an invalid suffix should be refused at the prompt")
+	}
+	m.clusterSuffixInput = ""
+	m.Update(tea.PasteMsg{Content: "Verify"})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateConfirm || m.restoreOpts.ClusterSuffix != "verify" || m.restoreMetadata.ClusterID != "my-cluster-verify" {
+		t.Fatalf("the suffix should be set and previewed, got %+v, %+v", m.restoreOpts, m.restoreMetadata)
+	}
+	if !strings.Contains(m.View().Content, "my-cluster-verify (new, alongside my-cluster)") {
+		t.Error("the confirmation should show the new cluster beside the live one")
+	}
+
+	// An empty suffix goes back to the live cluster's name
+	m.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	m.clusterSuffixInput = ""
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.restoreOpts.ClusterSuffix != "" || m.restoreMetadata.ClusterID != "my-cluster" {
+		t.Errorf("clearing the suffix should restore under the live name: %+v", m.restoreMetadata)
+	}
+}
+
+func TestModel_Confirm_EFSItems(t *testing.T) {
+	m := newConfirmTestModel()
+	m.Update(tea.KeyPressMsg{Code: 'p', Text: "p"})
+	if m.state != stateConfirm || !strings.Contains(m.statusMessage(), "EFS backups only") {
+		t.Fatal("p on an RDS restore should explain that items apply to EFS only")
+	}
+
+	m.selectedIdx = 1
+	m.restoreMetadata = &aws.RestoreMetadata{ResourceType: "EFS", ResourceID: "fs-12345", Encrypted: true}
+	if !strings.Contains(m.View().Content, "whole file system") {
+		t.Fatal("an EFS restore without items should show the whole file system")
+	}
+	m.Update(tea.KeyPressMsg{Code: 'p', Text: "p"})
+	if m.state != stateEFSItems {
+		t.Fatal("p on the confirm screen of an EFS restore should open the items form")
+	}
+	for _, item := range []string{"sites/default/documents", "/sites/default/documents/", "/sites/default/documents"} {
+		m.efsItems.input = ""
+		m.Update(tea.PasteMsg{Content: item})
+		m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	}
+	if len(m.efsItems.items) != 1 || m.efsItems.items[0] != "/sites/default/documents" || !strings.Contains(m.View().Content, "already listed") {
+		t.Fatalf("a relative path and a duplicate should be refused, got %q", m.efsItems.items)
+	}
+	m.efsItems.input = ""
+	m.Update(tea.PasteMsg{Content: "/sites/default/edi"})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateConfirm || len(m.restoreOpts.ItemsToRestore) != 2 {
+		t.Fatalf("an empty line should save the items, got state %v, %q", m.state, m.restoreOpts.ItemsToRestore)
+	}
+	if !strings.Contains(m.View().Content, "/sites/default/documents, /sites/default/edi") {
+		t.Error("the confirmation should show the items restored")
+	}
+
+	// Backspace on an empty line removes the last item; esc keeps the saved ones
+	m.Update(tea.KeyPressMsg{Code: 'p', Text: "p"})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if len(m.restoreOpts.ItemsToRestore) != 2 {
+		t.Error("esc should keep the saved items")
+	}
+	m.Update(tea.KeyPressMsg{Code: 'p', Text: "p"})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if len(m.restoreOpts.ItemsToRestore) != 0 || !strings.Contains(m.statusMessage(), "whole file system") {
+		t.Errorf("removing every item should restore the whole file system, got %q", m.restoreOpts.ItemsToRestore)
+	}
+}
+
+func TestModel_Confirm_FieldHelpToggle(t *testing.T) {
+	m := newConfirmTestModel()
+
+	if strings.Contains(m.View().Content, "If changed") {
+		t.Fatal("help should be hidden until requested")
+	}
+	m.Update(tea.KeyPressMsg{Code: '?', Text: "?"})
+	content := m.View().Content
+	if m.state != stateConfirm {
+		t.Fatal("? on the confirm screen should not open the global help")
+	}
+	if !strings.Contains(content, "DBClusterIdentifier") || !strings.Contains(content, "If changed") {
+		t.Error("help for the focused DBClusterIdentifier field should be shown")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: '?', Text: "?"})
+	if strings.Contains(m.View().Content, "If changed") {
+		t.Error("second ? should hide the help")
+	}
+}
+
+func TestModel_Confirm_FieldNavigation(t *testing.T) {
+	m := newConfirmTestModel()
+	m.Update(tea.KeyPressMsg{Code: '?', Text: "?"})
+
+	for i := 0; i < 10; i++ {
+		m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	}
+	if m.confirmField != 4 {
+		t.Fatalf("cursor should stop at the last field (role), got %d", m.confirmField)
+	}
+	if !strings.Contains(m.View().Content, "IAM role AWS Backup assumes") {
+		t.Error("help should follow the focused field")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'k', Text: "k"})
+	m.Update(tea.KeyPressMsg{Code: 'k', Text: "k"})
+	if !strings.Contains(m.View().Content, "security groups attached") {
+		t.Error("moving up should focus VpcSecurityGroupIds")
+	}
+}
+
+func TestModel_Confirm_FieldStateResetOnEntry(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.state = stateDetail
+	m.confirmField = 2
+	m.confirmHelp = true
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.confirmField != 0 || m.confirmHelp {
+		t.Error("entering the confirm screen should reset field focus and help")
+	}
+}
+
+func TestModel_KMSPicker_SelectsRestoreKey(t *testing.T) {
+	m := newConfirmTestModel()
+	fx, _ := aws.LoadFixtures("")
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'e', Text: "e"})
+	if m.state != stateKMSPicker || cmd == nil {
+		t.Fatal("e on the confirm screen should open the key picker and load keys")
+	}
+	m.Update(cmd())
+	content := m.View().Content
+	if !strings.Contains(content, "Keep the backup's key") || !strings.Contains(content, "alias/OpenemrEcsStack-dr") {
+		t.Fatalf("picker should list the fixture keys:\n%s", content)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateConfirm {
+		t.Fatal("enter should return to the confirm screen")
+	}
+	if !strings.HasSuffix(m.restoreOpts.KMSKeyID, ":alias/OpenemrEcsStack-dr") || m.restoreMetadata.KMSKeyID != m.restoreOpts.KMSKeyID {
+		t.Errorf("first customer key should be chosen and previewed: %+v", m.restoreOpts)
+	}
+	if !strings.Contains(m.View().Content, "alias/OpenemrEcsStack-dr") {
+		t.Error("confirm screen should show the chosen key")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if job := m.jobs[len(m.jobs)-1]; job.options.KMSKeyID != m.restoreOpts.KMSKeyID {
+		t.Errorf("restore should be started with the chosen key: %+v", job.options)
+	}
+}
+
+func TestModel_SGPicker_SelectsRestoreGroups(t *testing.T) {
+	m := newConfirmTestModel()
+	fx, _ := aws.LoadFixtures("")
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName = fx.Stacks[0].Name
+	m.restoreMetadata.SecurityGroups = "sg-0sim0001,sg-0sim0002"
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'g', Text: "g"})
+	if m.state != stateSGPicker || cmd == nil {
+		t.Fatal("g on the confirm screen should open the security group picker and load groups")
+	}
+	m.Update(cmd())
+	content := m.View().Content
+	if !strings.Contains(content, "vpc-0sim0001") || !strings.Contains(content, "OpenemrEcsStack-restore-isolated") ||
+		strings.Contains(content, "other-app-database") {
+		t.Fatalf("picker should list the groups of the stack's VPC only:\n%s", content)
+	}
+	if !m.sgPicker.selected["sg-0sim0001"] || !m.sgPicker.selected["sg-0sim0002"] {
+		t.Errorf("the live cluster's groups should start selected: %v", m.sgPicker.selected)
+	}
+
+	// Sorted by name: backup-proxy, database, restore-isolated, staging
+	space := tea.KeyPressMsg{Code: tea.KeySpace, Text: " "}
+	m.Update(space)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(space)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateSGPicker || !strings.Contains(m.statusMessage(), "at least one") {
+		t.Fatal("enter with nothing selected should stay in the picker")
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(space)
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateConfirm {
+		t.Fatal("enter should return to the confirm screen")
+	}
+	if len(m.restoreOpts.SecurityGroupIDs) != 1 || m.restoreOpts.SecurityGroupIDs[0] != "sg-0sim0003" ||
+		m.restoreMetadata.SecurityGroups != "sg-0sim0003" {
+		t.Errorf("the isolated group should be chosen and previewed: %+v %q", m.restoreOpts, m.restoreMetadata.SecurityGroups)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if job := m.jobs[len(m.jobs)-1]; len(job.options.SecurityGroupIDs) != 1 {
+		t.Errorf("restore should be started with the chosen groups: %+v", job.options)
+	}
+
+	m.state = stateConfirm
+	_, cmd = m.Update(tea.KeyPressMsg{Code: 'g', Text: "g"})
+	m.Update(cmd())
+	m.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	if m.restoreOpts.SecurityGroupIDs != nil || m.restoreMetadata.SecurityGroups != "sg-0sim0001,sg-0sim0002" {
+		t.Errorf("r should go back to the live cluster's groups: %+v %q", m.restoreOpts, m.restoreMetadata.SecurityGroups)
+	}
+}
+
+func TestModel_SGPicker_RDSOnly(t *testing.T) {
+	m := newConfirmTestModel()
+	m.selectedIdx = 1
+	m.Update(tea.KeyPressMsg{Code: 'g', Text: "g"})
+	if m.state != stateConfirm || !strings.Contains(m.statusMessage(), "RDS") {
+		t.Errorf("g should not open the picker for an EFS restore: state %d %q", m.state, m.statusMessage())
+	}
+}
+
+func TestModel_SubnetPicker_ValidatesAZCoverage(t *testing.T) {
+	m := newConfirmTestModel()
+	fx, _ := aws.LoadFixtures("")
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName = fx.Stacks[0].Name
+	m.restoreMetadata.SubnetGroup = "openemr-training-subnets"
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 's', Text: "s"})
+	if m.state != stateSubnetGroup || cmd == nil {
+		t.Fatal("s on the confirm screen should open the subnet group picker and load groups")
+	}
+	m.Update(cmd())
+	content := m.View().Content
+	if !strings.Contains(content, "us-west-2a, us-west-2b, us-west-2c") || strings.Contains(content, "other-app-subnets") {
+		t.Fatalf("picker should list the stack VPC's groups with their AZs:\n%s", content)
+	}
+	if m.subnetPicker.cursor != 2 {
+		t.Errorf("cursor should start on the current group, got %d", m.subnetPicker.cursor)
+	}
+
+	// Sorted by name: restore-isolated, staging-single-az, training-subnets
+	m.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateSubnetGroup || !strings.Contains(m.statusMessage(), "Availability Zone") {
+		t.Fatalf("a single-AZ group should be refused, got state %d %q", m.state, m.statusMessage())
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateConfirm || m.restoreOpts.SubnetGroup != "openemr-restore-isolated" ||
+		m.restoreMetadata.SubnetGroup != "openemr-restore-isolated" {
+		t.Errorf("the isolated group should be chosen and previewed: %+v", m.restoreOpts)
+	}
+
+	_, cmd = m.Update(tea.KeyPressMsg{Code: 's', Text: "s"})
+	m.Update(cmd())
+	m.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	if m.restoreOpts.SubnetGroup != "" || m.restoreMetadata.SubnetGroup != "openemr-training-subnets" {
+		t.Errorf("r should go back to the live cluster's group: %+v", m.restoreOpts)
+	}
+}
+
+func TestModel_FilterCyclesVaultResourceTypes(t *testing.T) {
+	m := newTestModel()
+	m.allBackups = append(sampleBackups(),
+		aws.RecoveryPoint{RecoveryPointARN: "rp-3", ResourceType: "DynamoDB", ResourceID: "sessions"},
+		aws.RecoveryPoint{RecoveryPointARN: "rp-4", ResourceType: "Legacy", ResourceID: "old"},
+	)
+	m.backups = m.allBackups
+	m.listModel.SetItems(m.formatBackupsForList())
+
+	m.handleSupportedTypes(supportedTypesMsg{types: []string{"Aurora", "DynamoDB", "EFS", "RDS"}})
+	var cycle []string
+	for range 4 {
+		m.cycleFilter()
+		cycle = append(cycle, m.activeFilter.String())
+	}
+	// Types AWS Backup no longer supports in the region are not offered
+	if want := []string{"RDS", "EFS", "DynamoDB", "All"}; !slices.Equal(cycle, want) {
+		t.Errorf("filter cycle = %v, want %v", cycle, want)
+	}
+
+	m.cycleFilter()
+	m.cycleFilter()
+	m.cycleFilter()
+	if len(m.backups) != 1 || m.backups[0].ResourceType != "DynamoDB" {
+		t.Errorf("DynamoDB filter should show only the DynamoDB backup, got %+v", m.backups)
+	}
+
+	m.state = stateHelp
+	if view := m.View().Content; !strings.Contains(view, "All → RDS → EFS → DynamoDB") {
+		t.Errorf("help should list the vault's resource types:\n%s", view)
+	}
+}
+
+func TestModel_SelectionsView(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName = fx.Stacks[0].Name
+	m.vaultName = fx.Plans[0].Vaults[0]
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'P', Text: "P"})
+	if m.state != stateSelections || cmd == nil {
+		t.Fatalf("P should open the selections view and load it, got state %v", m.state)
+	}
+	m.Update(cmd())
+
+	view := m.View().Content
+	for _, want := range []string{
+		"Plan " + fx.Plans[0].Name,
+		"Schedule: Daily at 05:00 UTC",
+		"✓ RDS  openemr-training-cluster  included by OpenemrEcsStack-selection",
+		"excluded by OpenemrEcsStack-tagged-resources",
+		"Tag (any): backup = daily",
+		"IAM role:  arn:aws:iam::123456789012:role/OpenemrEcsStack-BackupRole",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("selections view should show %q:\n%s", want, view)
+		}
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.state != stateList {
+		t.Errorf("esc should return to the list, got state %v", m.state)
+	}
+}
+
+func TestModel_Jobs_ListsJobsStartedElsewhere(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName = fx.Stacks[0].Name
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'J', Text: "J"})
+	if m.state != stateJobs || cmd == nil {
+		t.Fatalf("J should open the jobs view and list the stack's jobs, got state %v", m.state)
+	}
+	m.Update(cmd())
+	other := m.otherJobs()
+	if len(other) == 0 {
+		t.Fatal("backup and restore jobs of the stack's resources should be listed")
+	}
+	view := m.View().Content
+	if !strings.Contains(view, "Started elsewhere") || !strings.Contains(view, "openemr-training-cluster") {
+		t.Errorf("jobs view should show the jobs started elsewhere:\n%s", view)
+	}
+
+	// Enter tracks the job; it then moves from the other jobs to the tracked ones
+	m.jobsCursor = 0
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter on a job started elsewhere should look it up")
+	}
+	m.Update(cmd())
+	if len(m.jobs) != 1 || m.jobs[0].jobID != other[0].JobID || !m.jobs[0].imported {
+		t.Fatalf("job %s should be tracked as imported, got %+v", other[0].JobID, m.jobs)
+	}
+	if len(m.otherJobs()) != len(other)-1 {
+		t.Error("a tracked job should no longer be listed as started elsewhere")
+	}
+}
+
+func TestModel_LatestBannerColorsAgainstTargets(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName = fx.Vaults[0]
+	m.config = &config.Config{Targets: map[string]config.Target{
+		"RDS": {RPO: config.Duration(time.Minute), RTO: config.Duration(1000 * time.Hour)},
+	}}
+	backups, _ := m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "")
+
+	m.Update(backupsLoadedMsg{backups: backups})
+	view := m.View().Content
+	if !strings.Contains(view, "outside RPO 1m") {
+		t.Errorf("database backups older than the 1m RPO target should be outside it:\n%s", view)
+	}
+	if !strings.Contains(view, "RTO 1000h00m untested") {
+		t.Errorf("RTO should be untested before restores load:\n%s", view)
+	}
+	if strings.Count(view, "RPO") != 1 {
+		t.Errorf("only the database, which has a target, should be checked against an RPO:\n%s", view)
+	}
+}
+
+func TestAgeLevel(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		age  time.Duration
+		rpo  time.Duration
+		want ui.Level
+	}{
+		{23 * time.Hour, 0, ui.LevelOK},
+		{25 * time.Hour, 0, ui.LevelWarn},
+		{71 * time.Hour, 0, ui.LevelWarn},
+		{73 * time.Hour, 0, ui.LevelFail},
+		{2 * time.Hour, time.Hour, ui.LevelWarn},
+		{4 * time.Hour, time.Hour, ui.LevelFail},
+		{5 * 24 * time.Hour, 7 * 24 * time.Hour, ui.LevelOK},
+	}
+	for _, tt := range tests {
+		if got := ageLevel(now.Add(-tt.age), tt.rpo, now); got != tt.want {
+			t.Errorf("ageLevel(age %v, RPO %v) = %v, want %v", tt.age, tt.rpo, got, tt.want)
+		}
+	}
+}
+
+func TestModel_StatusGlyphsWithoutColor(t *testing.T) {
+	defer func() { _ = ui.SetTheme("default") }()
+	for _, theme := range ui.ThemeNames() {
+		if err := ui.SetTheme(theme); err != nil {
+			t.Fatal(err)
+		}
+		m := newTestModel()
+		now := time.Now()
+		m.backups = []aws.RecoveryPoint{
+			{RecoveryPointARN: "rp-fresh", ResourceType: "RDS", ResourceID: "fresh", CreationDate: now.Add(-time.Hour), Status: "COMPLETED"},
+			{RecoveryPointARN: "rp-aging", ResourceType: "RDS", ResourceID: "aging", CreationDate: now.Add(-48 * time.Hour), Status: "COMPLETED"},
+			{RecoveryPointARN: "rp-stale", ResourceType: "RDS", ResourceID: "stale", CreationDate: now.Add(-10 * 24 * time.Hour), Status: "COMPLETED"},
+		}
+		m.allBackups = m.backups
+		rows := m.formatBackupsForList()
+		for i, want := range []string{"●", "◐", "○"} {
+			if got := ui.StripANSI(rows[i][1]); got != want {
+				t.Errorf("%s theme: backup of %s has mark %q, want %q", theme, m.backups[i].ResourceID, got, want)
+			}
+		}
+
+		m.selectedIdx = 2
+		m.openDetail()
+		if view := ui.StripANSI(m.View().Content); !strings.Contains(view, "○ "+m.backups[2].CreationDate.Format("2006-01-02")) {
+			t.Errorf("%s theme: the detail view should mark a stale backup's date with ○:\n%s", theme, view)
+		}
+	}
+}
+
+// --- Recovery point enrichment ---
+
+func TestModel_EnrichesVisibleBackups(t *testing.T) {
+	fx, err := aws.LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := aws.NewSimulatedBackupClient(fx)
+	m := newTestModel()
+	m.ctx = ctx
+	m.backupClient = client
+	m.vaultName = fx.Vaults[0]
+	m.enrich.enricher = aws.NewEnricher(ctx, client, aws.EnrichWorkers)
+	m.allBackups, err = client.ListRecoveryPoints(ctx, m.vaultName, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+
+	m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	wait := m.waitForEnrichment()
+	for range len(m.backups) {
+		msg, ok := wait().(enrichedMsg)
+		if !ok || msg.Err != nil {
+			t.Fatalf("expected enriched recovery point, got %+v", msg)
+		}
+		_, cmd := m.Update(msg)
+		wait = cmd
+	}
+
+	onDemand := 0
+	for _, item := range m.formatBackupsForList() {
+		if createdBy := item[len(item)-1]; createdBy != "on-demand" && createdBy != "OpenemrEcsStack-backup-plan" {
+			t.Errorf("list item should show who created it: %q", item)
+		}
+		if slices.Contains(item, "on-demand") {
+			onDemand++
+		}
+	}
+	if onDemand != 1 {
+		t.Errorf("fixtures have one on-demand backup, list shows %d", onDemand)
+	}
+
+	// Enriched backups are not requested again
+	m.enrich.queued = "stale"
+	m.enrichVisible()
+	if m.enrich.queued != "" || m.enrich.enricher.Pending() != 0 {
+		t.Error("known backups should not be enriched again")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if !strings.Contains(m.View().Content, "Created By:") {
+		t.Error("detail view should show cached details immediately")
+	}
+}
+
+func TestModel_EnrichmentFailureIsLogged(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	arn := m.backups[0].RecoveryPointARN
+	m.handleEnriched(enrichedMsg{RecoveryPointARN: arn, Err: fmt.Errorf("AccessDenied")})
+	if len(m.errorLog.entries) != 1 {
+		t.Error("failed lookups should be logged")
+	}
+	if _, known := m.enrich.details[arn]; !known {
+		t.Error("failed lookups should not be retried")
+	}
+	if m.waitForEnrichment() != nil {
+		t.Error("no enricher should mean nothing to wait for")
+	}
+}
+
+func TestModel_DeletionProtection(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName = fx.Vaults[0]
+	m.allBackups, _ = m.backupClient.ListRecoveryPoints(context.Background(), m.vaultName, "")
+	m.applyFilter()
+	m.config = &config.Config{Protect: []config.ProtectRule{{Name: "training", Tags: map[string]string{"Environment": "training"}}}}
+	rp := m.backups[0]
+
+	// Shortening retention is refused, with tags looked up as needed
+	msg := m.updateLifecycle(rp, aws.Lifecycle{DeleteAfterDays: 2})()
+	if u := msg.(lifecycleUpdatedMsg); u.err == nil || !strings.Contains(u.err.Error(), `"training"`) {
+		t.Errorf("shortening a protected backup's retention should be refused, got %v", u.err)
+	}
+
+	// Extending it is not
+	msg = m.updateLifecycle(rp, aws.Lifecycle{DeleteAfterDays: 3650})()
+	if u := msg.(lifecycleUpdatedMsg); u.err != nil {
+		t.Errorf("extending a protected backup's retention should be allowed, got %v", u.err)
+	}
+
+	// Once the tags are known, the editor warns before saving
+	m.selectedIdx = 0
+	m.enrich.details = map[string]*aws.RecoveryPointDetails{rp.RecoveryPointARN: {Tags: map[string]string{"Environment": "training"}}}
+	m.openLifecycleEdit()
+	if !strings.Contains(m.View().Content, "Protected by config rule") {
+		t.Error("retention editor should show the protecting rule")
+	}
+}
+
+func TestModel_RestorePrefetch(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName, m.vaultName = fx.Stacks[0].Name, fx.Vaults[0]
+	m.backups, _ = m.backupClient.ListRecoveryPoints(context.Background(), m.vaultName, "RDS")
+	m.selectedIdx = 0
+
+	cmd := m.openDetail()
+	if view := m.View().Content; !strings.Contains(view, "Restore Preflight") || !strings.Contains(view, "Loading...") {
+		t.Fatal("detail view should show the preflight while it loads")
+	}
+	// Run the lookups started with the detail view, as Bubbletea would
+	var run func(tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				run(c)
+			}
+			return
+		}
+		m.Update(msg)
+	}
+	run(cmd)
+	if view := m.View().Content; strings.Contains(view, "Loading...") || !strings.Contains(view, "Subnet Group:") {
+		t.Errorf("preflight should be complete once the lookups return:\n%s", view)
+	}
+
+	// Confirming and the pickers use the prefetch instead of waiting on AWS
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateConfirm || m.restoreMetadata == nil {
+		t.Fatal("restore parameters should be shown as soon as the confirmation opens")
+	}
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 's', Text: "s"}); cmd != nil || m.subnetPicker.loading || len(m.subnetPicker.groups) == 0 {
+		t.Error("subnet group picker should open with the prefetched groups")
+	}
+
+	// Results for a backup no longer shown are dropped
+	m.handlePrefetched(prefetchedMsg{arn: "other", msg: kmsKeysMsg{}})
+	if m.prefetch.kms == nil || len(m.prefetch.kms.keys) == 0 {
+		t.Error("a stale result should not replace the prefetch")
+	}
+}
+
+func TestModel_FreezeWindowRefusesRestore(t *testing.T) {
+	now := time.Now().UTC()
+	cfg := &config.Config{Freeze: []config.FreezeWindow{{Name: "clinic hours",
+		Start: now.Add(-time.Hour).Format("15:04"), End: now.Add(time.Hour).Format("15:04"), TimeZone: "UTC"}}}
+	m := newTestModel()
+	m.config = cfg
+	m.backups = sampleBackups()
+	m.state = stateConfirm
+
+	if view := m.View().Content; !strings.Contains(view, "Change freeze in effect") || !strings.Contains(view, "clinic hours") {
+		t.Errorf("confirmation should show the freeze:\n%s", view)
+	}
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if cmd != nil || len(m.jobs) != 0 || !strings.Contains(m.statusMessage(), "--override-freeze") {
+		t.Fatalf("restore should be refused during a freeze, status %q", m.statusMessage())
+	}
+
+	m.overrideFreeze = true
+	if view := m.View().Content; !strings.Contains(view, "Change freeze overridden") {
+		t.Errorf("confirmation should show the override:\n%s", view)
+	}
+	m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if len(m.jobs) != 1 {
+		t.Error("--override-freeze should allow the restore")
+	}
+}
+
+func TestModel_FreezeWindowSkipsChainedRestore(t *testing.T) {
+	m := newChainTestModel()
+	m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+	now := time.Now().UTC()
+	m.config = &config.Config{Freeze: []config.FreezeWindow{{
+		Start: now.Add(-time.Hour).Format("15:04"), End: now.Add(time.Hour).Format("15:04"), TimeZone: "UTC"}}}
+
+	m.Update(restoreStatusMsg{jobID: "job-rds", status: &aws.RestoreJobStatus{
+		JobID: "job-rds", Status: "COMPLETED", IsTerminal: true,
+	}})
+	if m.jobs[1].state != jobSkipped || !strings.Contains(m.jobs[1].note, "change freeze") {
+		t.Errorf("a chained restore should not start during a freeze: %v %q", m.jobs[1].state, m.jobs[1].note)
+	}
+}
+
+func TestModel_TagsRestoredResource(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.backups = sampleBackups()
+	job := m.addJob(m.backups[0], nil)
+	job.state, job.jobID = jobActive, "job-rds"
+
+	cmds := m.handleRestoreStatus(restoreStatusMsg{jobID: "job-rds", status: &aws.RestoreJobStatus{
+		JobID: "job-rds", Status: "COMPLETED", IsTerminal: true,
+		CreatedResourceARN: "arn:aws:rds:us-west-2:123456789012:cluster:openemr-restored",
+	}})
+	var tagged bool
+	for _, cmd := range cmds {
+		if cmd == nil {
+			continue
+		}
+		if msg, ok := cmd().(restoredTaggedMsg); ok {
+			tagged = true
+			if msg.err != nil {
+				t.Errorf("tagging failed: %v", msg.err)
+			}
+		}
+	}
+	if !tagged {
+		t.Error("a completed restore should tag the cluster it created")
+	}
+
+	job.imported = true
+	if m.tagRestored(job) != nil {
+		t.Error("imported restores were started elsewhere and should not be tagged")
+	}
+}
+
+func TestModel_CopiesTagsToRestoredResource(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	points, _ := m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	m.backups = points[:1]
+	m.state = stateConfirm
+
+	m.Update(tea.KeyPressMsg{Code: 't', Text: "t"})
+	if !m.restoreOpts.CopyTags || !strings.Contains(m.View().Content, "copy the original's tags") {
+		t.Fatal("t should choose to copy the original's tags")
+	}
+
+	job := m.addJob(m.backups[0], nil)
+	job.options = m.restoreOpts
+	job.state, job.jobID = jobCompleted, "job-rds"
+	job.status = &aws.RestoreJobStatus{
+		JobID: "job-rds", Status: "COMPLETED", IsTerminal: true,
+		CreatedResourceARN: "arn:aws:rds:us-west-2:123456789012:cluster:openemr-restored",
+	}
+	msg, ok := m.tagRestored(job)().(restoredTaggedMsg)
+	if !ok || msg.copyErr != nil {
+		t.Fatalf("copying tags failed: %+v", msg)
+	}
+	if msg.copied["cost-center"] != "clinical-it" || msg.copied[aws.RestoreJobTagKey] != "job-rds" {
+		t.Errorf("expected the original's tags and provenance, got %v", msg.copied)
+	}
+	m.Update(msg)
+	if m.warnings.current == nil || !strings.Contains(m.warnings.current.Message, "tag(s) onto the restored resource") {
+		t.Error("the operator should be told the tags were copied")
+	}
+
+	job.options.CopyTags = false
+	if msg := m.tagRestored(job)().(restoredTaggedMsg); msg.copied != nil {
+		t.Error("tags should only be copied when chosen on the confirmation")
+	}
+}
+
+func TestModel_ConfirmShowsRestoreCost(t *testing.T) {
+	m := newTestModel()
+	m.region = "us-west-2"
+	m.backups = []aws.RecoveryPoint{{RecoveryPointARN: "rp-1", ResourceType: "RDS", ResourceID: "openemr-db", BackupSizeInBytes: 50 << 30, CreationDate: time.Now()}}
+	m.state = stateConfirm
+
+	if view := m.View().Content; !strings.Contains(view, "Estimated Cost:") || !strings.Contains(view, "Loading...") {
+		t.Errorf("cost should load with the live cluster:\n%s", view)
+	}
+	m.Update(clusterHealthMsg{health: &aws.ClusterHealth{
+		StorageType: "aurora", ServerlessMinACU: 0.5, ServerlessMaxACU: 8,
+		Instances: []aws.ClusterInstance{{ID: "openemr-db-1", Class: "db.serverless", Writer: true}},
+	}})
+	view := m.View().Content
+	for _, want := range []string{"serverless 0.5-8 ACU", "$0.06-$0.96/h", "$5.00 storage", "us-west-2 may differ"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("confirmation should contain %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestModel_ConfirmConfigDiff(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	ctx := context.Background()
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName = fx.Vaults[0]
+	points, _ := m.backupClient.ListRecoveryPoints(ctx, fx.Vaults[0], "RDS")
+	for _, rp := range points {
+		if strings.HasSuffix(rp.RecoveryPointARN, "job-sim-rds-0003") {
+			m.backups = []aws.RecoveryPoint{rp}
+		}
+	}
+	if len(m.backups) == 0 {
+		t.Fatal("fixture backup with an older engine version not found")
+	}
+	m.state = stateConfirm
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	if cmd == nil || !strings.Contains(m.View().Content, "Live Cluster vs Restore:") {
+		t.Fatal("d should open the diff and look up the backup's configuration")
+	}
+	m.Update(cmd())
+	health, err := m.backupClient.GetClusterHealth(ctx, fx.Stacks[0].Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Update(clusterHealthMsg{health: health})
+	meta, _ := m.backupClient.GetRestoreMetadata(ctx, m.backups[0], fx.Stacks[0].Name)
+	m.Update(restoreMetadataMsg{metadata: meta})
+
+	view := m.View().Content
+	for _, want := range []string{"Engine version", "3.07.1", health.EngineVersion, "Subnet group", "KMS key"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("diff should contain %q:\n%s", want, view)
+		}
+	}
+
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"}); cmd != nil || strings.Contains(m.View().Content, "Live Cluster vs Restore:") {
+		t.Error("d again should hide the diff without another lookup")
+	}
+}
+
+func TestModel_ConfirmEngineUpgrade(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	ctx := context.Background()
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName = fx.Vaults[0]
+	m.config = &config.Config{TestedEngineVersions: []string{"8.0.mysql_aurora.3.12.0"}}
+	points, _ := m.backupClient.ListRecoveryPoints(ctx, fx.Vaults[0], "RDS")
+	for _, rp := range points {
+		if strings.HasSuffix(rp.RecoveryPointARN, "job-sim-rds-0003") {
+			m.backups = []aws.RecoveryPoint{rp}
+		}
+	}
+	if len(m.backups) == 0 {
+		t.Fatal("fixture backup with a deprecated engine version not found")
+	}
+	m.state = stateDetail
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateConfirm || cmd == nil {
+		t.Fatal("enter should open the confirmation and check the engine version")
+	}
+	if m.engineCheck.arn != m.backups[0].RecoveryPointARN {
+		t.Fatal("enter should start the engine version check of the selected backup")
+	}
+	m.engineCheck = engineCheckView{} // Run the check again to get its message
+	m.Update(m.checkEngineVersion()())
+	view := m.View().Content
+	for _, want := range []string{"Engine version upgrade not tested with OpenEMR", "3.07.1 is deprecated"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("confirmation should contain %q:\n%s", want, view)
+		}
+	}
+	if w := m.warnings.current; w == nil || w.Severity != SeverityWarn || !strings.Contains(w.Message, "not been tested") {
+		t.Errorf("an untested upgrade should be pushed as a warning, got %+v", w)
+	}
+
+	// Without tested versions in the config, the live cluster's version is tested
+	m.config = nil
+	health, _ := m.backupClient.GetClusterHealth(ctx, fx.Stacks[0].Name)
+	m.Update(clusterHealthMsg{health: health})
+	if view := m.View().Content; !strings.Contains(view, "Engine version upgrade") || strings.Contains(view, "not tested with OpenEMR") {
+		t.Errorf("an upgrade to the live cluster's version should be tested:\n%s", view)
+	}
+
+	if cmd := m.checkEngineVersion(); cmd != nil {
+		t.Error("a checked backup should not be checked again")
+	}
+}
+
+func TestModel_RemembersViewPerEnvironment(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	path := filepath.Join(t.TempDir(), "views.json")
+	newModel := func(stack string) *Model {
+		m := newTestModel()
+		m.stackName = stack
+		m.backupClient = aws.NewSimulatedBackupClient(fx)
+		m.viewsPath = path
+		m.loadView()
+		return m
+	}
+	backups := []aws.RecoveryPoint{
+		{RecoveryPointARN: "rp-1", ResourceType: "RDS", CreationDate: time.Now().Add(-time.Hour)},
+		{RecoveryPointARN: "rp-2", ResourceType: "EFS", CreationDate: time.Now()},
+	}
+
+	m := newModel("openemr")
+	m.Update(backupsLoadedMsg{backups: backups})
+	m.Update(tea.KeyPressMsg{Code: 's', Text: "s"})
+	m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
+	filter := m.activeFilter
+	m.Update(tea.KeyPressMsg{Code: 'J', Text: "J"})
+
+	m = newModel("openemr")
+	if m.activeSort != sortOldest || m.activeFilter != filter || m.state != stateList {
+		t.Fatalf("sort and filter should be restored, got %s / %s", m.activeSort, m.activeFilter)
+	}
+	m.Update(backupsLoadedMsg{backups: backups})
+	if m.state != stateJobs {
+		t.Errorf("the jobs view should reopen once the backups load, got state %d", m.state)
+	}
+	if len(m.backups) != 1 || m.backups[0].ResourceType != string(filter) {
+		t.Errorf("the restored filter should apply, got %+v", m.backups)
+	}
+
+	if other := newModel("openemr-staging"); other.activeSort != sortNewest || other.activeFilter != filterAll {
+		t.Errorf("another environment should open with the defaults, got %s / %s", other.activeSort, other.activeFilter)
+	}
+}
+
+func TestModel_ListColumns(t *testing.T) {
+	for _, name := range config.ListColumnNames {
+		if listColumns[name].cell == nil {
+			t.Errorf("column %q has no cell", name)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "views.json")
+	newModel := func() *Model {
+		m := newTestModel()
+		m.stackName, m.viewsPath = "openemr", path
+		m.config = &config.Config{Columns: []string{"type", "created", "status"}}
+		m.loadView()
+		m.applyColumns()
+		m.Update(backupsLoadedMsg{backups: sampleBackups()})
+		return m
+	}
+
+	m := newModel()
+	if view := ui.StripANSI(m.listModel.View()); !strings.Contains(view, "Status") || strings.Contains(view, "Size") {
+		t.Fatalf("the list should show the configured columns:\n%s", view)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'C', Text: "C"})
+	if m.state != stateColumns {
+		t.Fatalf("C should open the column picker, got state %d", m.state)
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "}) // Hide type
+	m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	m.Update(tea.KeyPressMsg{Code: 'K', Text: "K"}) // Status before created
+	// Past resource, size and created-by to tier
+	for range 5 {
+		m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	want := []string{"status", "created", "tier"}
+	if m.state != stateList || !slices.Equal(m.columns, want) {
+		t.Fatalf("enter should apply the picked columns %v, got %v", want, m.columns)
+	}
+	view := ui.StripANSI(m.listModel.View())
+	if strings.Contains(view, "Type") || strings.Index(view, "Status") > strings.Index(view, "Created") {
+		t.Errorf("the list should show the picked columns in order:\n%s", view)
+	}
+
+	if reopened := newModel(); !slices.Equal(reopened.columns, want) {
+		t.Errorf("the picked columns should be restored for the environment, got %v", reopened.columns)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'C', Text: "C"})
+	for range len(want) {
+		m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+		m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateColumns || !strings.Contains(m.statusMessage(), "at least one column") {
+		t.Errorf("hiding every column should be refused, got state %d, %q", m.state, m.statusMessage())
+	}
+	m.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	if m.state != stateList || m.columns != nil || !slices.Equal(m.listColumnNames(), m.config.Columns) {
+		t.Errorf("r should go back to the configured columns, got %v", m.listColumnNames())
+	}
+}
+
+func TestModel_ReportsEFSRestoreDirectory(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	rp := aws.RecoveryPoint{RecoveryPointARN: "rp-efs", ResourceType: "EFS", ResourceID: "fs-1", CreationDate: time.Now()}
+	job := m.addJob(rp, nil)
+	job.state, job.jobID = jobActive, "job-efs"
+	m.restoreJobID, m.state = "job-efs", stateRestoring
+
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 'c', Text: "c"}); cmd != nil {
+		t.Error("nothing should be copied before the restore completes")
+	}
+	m.Update(restoreStatusMsg{jobID: "job-efs", status: &aws.RestoreJobStatus{
+		JobID: "job-efs", Status: "COMPLETED", IsTerminal: true, ResourceID: "fs-1",
+		CreatedAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}})
+	const dir = "/aws-backup-restore_2026-03-01T10-00-00"
+	if view := m.View().Content; !strings.Contains(view, dir) {
+		t.Errorf("monitor should show the restored directory:\n%s", view)
+	}
+	if !strings.Contains(job.note, dir) {
+		t.Errorf("jobs view note should name the directory, got %q", job.note)
+	}
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 'c', Text: "c"}); cmd == nil || !strings.Contains(m.statusMessage(), dir) {
+		t.Errorf("c should copy the directory, got %q", m.statusMessage())
+	}
+}
+
+func TestModel_VaultActivity(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName = fx.Vaults[0]
+	m.allBackups, _ = m.backupClient.ListRecoveryPoints(m.ctx, m.vaultName, "")
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'A', Text: "A"})
+	if m.state != stateActivity || cmd == nil {
+		t.Fatalf("A should open and load the vault activity, got state %v", m.state)
+	}
+	m.Update(cmd())
+	content := m.View().Content
+	for _, want := range []string{"last 24 hours", "2 created · 0 copied in · 1 deleted", "by dba-oncall", "by plan"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("activity view should contain %q", want)
+		}
+	}
+
+	// The newest event is a recovery point still in the vault
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateDetail || m.backups[m.selectedIdx].RecoveryPointARN != m.activity.activity.Events[0].RecoveryPointARN {
+		t.Fatalf("enter should open the event's backup, got state %v", m.state)
+	}
+
+	m.state = stateActivity
+	_, cmd = m.Update(tea.KeyPressMsg{Code: 'w', Text: "w"})
+	if cmd == nil {
+		t.Fatal("w should reload the activity over a wider window")
+	}
+	m.Update(cmd())
+	if !strings.Contains(m.View().Content, "last 3 days") || !strings.Contains(m.View().Content, "5 created") {
+		t.Error("the 3-day window should include every fixture backup")
+	}
+
+	// The deleted recovery point cannot be opened
+	m.activity.cursor = len(m.activity.activity.Events) - 1
+	for i, e := range m.activity.activity.Events {
+		if e.Kind == aws.ActivityDeleted {
+			m.activity.cursor = i
+		}
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateActivity || !strings.Contains(m.statusMessage(), "not in the list") {
+		t.Errorf("enter on a deleted backup should explain, got state %v status %q", m.state, m.statusMessage())
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.state != stateList {
+		t.Errorf("esc should return to the list, got state %v", m.state)
+	}
+}
+
+func TestModel_ExportSnapshot(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.backups, _ = m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	m.state = stateDetail
+
+	m.Update(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	if m.state != stateDetail || !strings.Contains(m.statusMessage(), "-export-bucket") {
+		t.Fatalf("export without a destination should explain the flags, got state %d %q", m.state, m.statusMessage())
+	}
+
+	m.exportDest = aws.ExportDestination{Bucket: "openemr-exports", IAMRoleARN: "arn:aws:iam::123456789012:role/export", KMSKeyID: "alias/exports"}
+	m.Update(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	if m.state != stateExport || !strings.Contains(m.View().Content, "s3://openemr-exports") {
+		t.Fatal("x should confirm the export and show its destination")
+	}
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if m.state != stateJobs || cmd == nil {
+		t.Fatal("confirming should open the jobs view and start the export")
+	}
+	m.Update(cmd())
+	job := m.jobs[0]
+	if job.kind != aws.JobKindExport || job.state != jobActive || !strings.HasPrefix(job.jobID, "backup-tui-") {
+		t.Errorf("export should be tracked as an active job: %+v", job)
+	}
+	if !strings.Contains(m.View().Content, "export job "+job.jobID) {
+		t.Error("jobs view should show the export task")
+	}
+}
+
+func TestModel_ExportSnapshot_RejectsEFS(t *testing.T) {
+	m := newTestModel()
+	m.backups = []aws.RecoveryPoint{{ResourceType: "EFS", ResourceID: "fs-1"}}
+	m.exportDest = aws.ExportDestination{Bucket: "b", IAMRoleARN: "r", KMSKeyID: "k"}
+	m.state = stateDetail
+
+	m.Update(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	if m.state != stateDetail || !strings.Contains(m.statusMessage(), "cannot be exported") {
+		t.Errorf("EFS backups should not be exportable, got %q", m.statusMessage())
+	}
+}
+
+func TestModel_FastClone(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName = fx.Stacks[0].Name
+	m.backups, _ = m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	m.state = stateConfirm
+
+	m.Update(tea.KeyPressMsg{Code: 'c', Text: "c"})
+	if m.state != stateClone || !strings.Contains(m.View().Content, "not the selected backup") {
+		t.Fatal("c should confirm the clone and say it is of the current data")
+	}
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if m.state != stateJobs || cmd == nil {
+		t.Fatal("confirming should open the jobs view and start the clone")
+	}
+	m.Update(cmd())
+	job := m.jobs[0]
+	if job.kind != aws.JobKindClone || job.state != jobActive || !strings.Contains(job.jobID, "-clone-") {
+		t.Errorf("clone should be tracked as an active job: %+v", job)
+	}
+	if m.chainTail() != nil {
+		t.Error("restores should not be chained after a clone")
+	}
+}
+
+func TestModel_CleanupLeftovers(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName = fx.Stacks[0].Name
+	cloneID, err := m.backupClient.CloneCluster(context.Background(), m.stackName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A clone whose instance failed leaves its cluster behind
+	failed := m.addJob(aws.RecoveryPoint{ResourceType: "RDS"}, nil)
+	failed.kind = aws.JobKindClone
+	m.handleCloneStarted(cloneStartedMsg{seq: failed.seq, cloneID: cloneID, err: fmt.Errorf("cluster was created, but not its instance")})
+	// A completed restore whose chained step failed leaves its file system behind
+	restored := m.addJob(aws.RecoveryPoint{ResourceType: "EFS", ResourceID: "fs-0sim0001"}, nil)
+	restored.state = jobCompleted
+	restored.status = &aws.RestoreJobStatus{CreatedResourceARN: "arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-restored"}
+	next := m.addJob(aws.RecoveryPoint{ResourceType: "RDS"}, restored)
+	next.state = jobFailed
+	// A completed restore onto the backed-up resource leaves nothing behind
+	inPlace := m.addJob(aws.RecoveryPoint{ResourceType: "EFS", ResourceID: "fs-0sim0001"}, nil)
+	inPlace.state = jobCompleted
+	inPlace.status = &aws.RestoreJobStatus{CreatedResourceARN: "arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-0sim0001"}
+
+	if r, ok := m.leftover(failed); !ok || r.ID != cloneID {
+		t.Errorf("failed clone should leave %s behind, got %+v", cloneID, r)
+	}
+	if r, ok := m.leftover(restored); !ok || r.ID != "fs-restored" {
+		t.Errorf("abandoned chain should leave fs-restored behind, got %+v", r)
+	}
+	if _, ok := m.leftover(inPlace); ok {
+		t.Error("an in-place restore should not be offered for cleanup")
+	}
+	m.state = stateJobs
+	if !strings.Contains(m.View().Content, "left behind Aurora cluster "+cloneID) {
+		t.Error("jobs view should mark the leftover cluster")
+	}
+
+	m.jobsCursor = inPlace.seq - 1
+	m.Update(tea.KeyPressMsg{Code: 'D', Text: "D"})
+	if m.state != stateJobs || !strings.Contains(m.statusMessage(), "nothing behind") {
+		t.Errorf("D on a job without leftovers should warn, got %q", m.statusMessage())
+	}
+
+	m.jobsCursor = failed.seq - 1
+	m.Update(tea.KeyPressMsg{Code: 'D', Text: "D"})
+	if m.state != stateCleanup || !strings.Contains(m.View().Content, "without a final snapshot") {
+		t.Fatal("D should confirm deleting the leftover cluster")
+	}
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if m.state != stateJobs || cmd == nil || !failed.cleaning {
+		t.Fatal("confirming should return to the jobs view and delete the cluster")
+	}
+	m.Update(cmd())
+	if _, ok := m.leftover(failed); ok || !strings.Contains(failed.note, "cleaned up") {
+		t.Errorf("deleted cluster should no longer be offered: %+v", failed)
+	}
+	if _, err := m.backupClient.GetJobStatus(context.Background(), aws.JobKindClone, cloneID); err == nil {
+		t.Error("the clone should have been deleted")
+	}
+}
+
+func TestModel_FastClone_OnlyRDS(t *testing.T) {
+	m := newTestModel()
+	m.backups = []aws.RecoveryPoint{{ResourceType: "EFS", ResourceID: "fs-1"}}
+	m.state = stateConfirm
+
+	m.Update(tea.KeyPressMsg{Code: 'c', Text: "c"})
+	if m.state != stateConfirm || !strings.Contains(m.statusMessage(), "only available") {
+		t.Errorf("EFS restores should not offer a clone, got %q", m.statusMessage())
+	}
+}
+
+func TestModel_LifecycleEdit(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName = fx.Vaults[0]
+	m.allBackups, _ = m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	m.applyFilter()
+	m.state = stateDetail
+
+	m.Update(tea.KeyPressMsg{Code: 'l', Text: "l"})
+	if m.state != stateLifecycle || !strings.Contains(m.View().Content, "Change Retention") {
+		t.Fatal("l should open the retention editor")
+	}
+	for range 5 {
+		m.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	}
+	for _, r := range "400" {
+		m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+
+	m.vaultInfo = &aws.VaultInfo{Name: "locked", Locked: true, MaxRetentionDays: 365}
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd != nil || !strings.Contains(m.View().Content, "maximum retention of 365 days") {
+		t.Fatal("retention beyond the Vault Lock maximum should be refused in the editor")
+	}
+
+	m.vaultInfo = nil
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should update the lifecycle")
+	}
+	m.Update(cmd())
+	if m.state != stateDetail || m.backups[m.selectedIdx].Lifecycle.DeleteAfterDays != 400 {
+		t.Errorf("updated lifecycle should be shown in the detail view, got state %d %+v", m.state, m.backups[m.selectedIdx].Lifecycle)
+	}
+	for _, rp := range m.allBackups {
+		if rp.RecoveryPointARN == m.backups[m.selectedIdx].RecoveryPointARN && rp.Lifecycle.DeleteAfterDays != 400 {
+			t.Error("unfiltered list should carry the new lifecycle too")
+		}
+	}
+}
+
+func TestModel_VaultLockProtectsRecentBackups(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName = fx.Vaults[0]
+	m.allBackups, _ = m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	m.applyFilter()
+	m.vaultInfo = &aws.VaultInfo{Name: fx.Vaults[0], Locked: true, MinRetentionDays: 3650}
+
+	m.openDetail()
+	if !strings.Contains(m.View().Content, "cannot be deleted before") {
+		t.Fatal("the detail view should show that Vault Lock protects the backup")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'l', Text: "l"})
+	for range 5 {
+		m.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	}
+	m.Update(tea.KeyPressMsg{Code: '9', Text: "9"})
+	if !strings.Contains(m.View().Content, "too soon, Vault Lock keeps this backup until") {
+		t.Error("a deletion date inside the protected window should be flagged while typing")
+	}
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter}); cmd != nil || m.state != stateLifecycle {
+		t.Error("a deletion date inside the protected window should not be saved")
+	}
+
+	m.vaultInfo = nil
+	m.state = stateDetail
+	m.openDetail()
+	if strings.Contains(m.View().Content, "cannot be deleted before") {
+		t.Error("an unlocked vault should not show protection")
+	}
+}
+
+func TestModel_LegalHold(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName = fx.Vaults[0]
+	m.allBackups, _ = m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "")
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	if len(m.marked) != 1 || !strings.Contains(m.View().Content, "✓") {
+		t.Fatal("space should mark the backup under the cursor")
+	}
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'H', Text: "H"})
+	if m.state != stateLegalHolds || cmd == nil {
+		t.Fatal("H should open the legal holds view")
+	}
+	m.Update(cmd())
+	m.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	if m.state != stateHoldNew {
+		t.Fatal("n should open the new hold prompt")
+	}
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd != nil || !strings.Contains(m.View().Content, "enter a title") {
+		t.Fatal("a hold without a title should be refused")
+	}
+	m.Update(tea.PasteMsg{Content: "Case 2026-17"})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	m.Update(tea.PasteMsg{Content: "Subpoena"})
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should place the hold")
+	}
+	_, cmd = m.Update(cmd())
+	m.Update(cmd())
+	if m.state != stateLegalHolds || len(m.marked) != 0 || len(m.legalHolds.holds) != 1 || !m.legalHolds.holds[0].Active() {
+		t.Fatalf("hold should be listed as active and the marks cleared, got %+v", m.legalHolds.holds)
+	}
+	if !strings.Contains(m.View().Content, "Case 2026-17") {
+		t.Error("holds view should show the new hold")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	for _, r := range "Settled" {
+		m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	_, cmd = m.Update(cmd())
+	m.Update(cmd())
+	if m.legalHolds.holds[0].Active() {
+		t.Error("released hold should no longer be active")
+	}
+}
+
+func TestModel_TagEdit(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName = fx.Vaults[0]
+	m.config = &config.Config{Protect: []config.ProtectRule{{Name: "legal", Tags: map[string]string{"hold": "legal"}}}}
+	m.allBackups, _ = m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "")
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+	ctx := context.Background()
+
+	// Mark the first two backups
+	m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	m.Update(tea.KeyPressMsg{Code: '#', Text: "#"})
+	if m.state != stateTagEdit || len(m.tagEdit.targets) != 2 {
+		t.Fatalf("# should open the tag editor on the marked backups, got state %d, %d target(s)", m.state, len(m.tagEdit.targets))
+	}
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter}); cmd != nil || !strings.Contains(m.View().Content, "enter tags") {
+		t.Fatal("an empty edit should be refused")
+	}
+	m.Update(tea.PasteMsg{Content: "verified=true hold=legal"})
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should apply the edit")
+	}
+	m.Update(cmd())
+	if m.state != stateList || len(m.marked) != 0 || !strings.Contains(m.statusMessage(), "Tags edited on 2 backup(s)") {
+		t.Fatalf("the edit should be reported and the marks cleared, got state %d, %q", m.state, m.statusMessage())
+	}
+	for _, rp := range m.backups[:2] {
+		d, _ := m.backupClient.DescribeRecoveryPointDetails(ctx, m.vaultName, rp.RecoveryPointARN)
+		if d.Tags["verified"] != "true" || d.Tags["hold"] != "legal" {
+			t.Errorf("%s should be tagged, got %v", rp.RecoveryPointARN, d.Tags)
+		}
+	}
+
+	// Removing the tag that protects the first backup is refused
+	m.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	m.Update(tea.KeyPressMsg{Code: '#', Text: "#"})
+	m.Update(tea.PasteMsg{Content: "-hold"})
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m.Update(cmd())
+	if m.state != stateTagEdit || !strings.Contains(m.View().Content, "deletion protection") {
+		t.Fatal("an edit lifting deletion protection should be refused in the editor")
+	}
+	d, _ := m.backupClient.DescribeRecoveryPointDetails(ctx, m.vaultName, m.backups[0].RecoveryPointARN)
+	if d.Tags["hold"] != "legal" {
+		t.Errorf("the protecting tag should be kept, got %v", d.Tags)
+	}
+}
+
+func TestModel_DeleteRecoveryPoints(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName = fx.Vaults[0]
+	m.allBackups, _ = m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "")
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+	total := len(m.allBackups)
+	typeWord := func(word string) {
+		for _, r := range word {
+			m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+		}
+	}
+	// endGrace ends the grace period of the pending deletions and sends them
+	endGrace := func() {
+		t.Helper()
+		for i := range m.deletes.pending {
+			m.deletes.pending[i].due = time.Now()
+		}
+		_, cmd := m.Update(deleteTickMsg{})
+		if cmd == nil {
+			t.Fatal("a due deletion should be sent")
+		}
+		m.Update(cmd())
+	}
+
+	// Mark the first two backups
+	m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	if m.state != stateDelete || len(m.deleteConfirm.targets) != 2 {
+		t.Fatalf("d should confirm deleting the marked backups, got state %d, %d target(s)", m.state, len(m.deleteConfirm.targets))
+	}
+	typeWord("yes")
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter}); cmd != nil || !strings.Contains(m.View().Content, `type "delete"`) {
+		t.Fatal("anything but the confirmation word should be refused")
+	}
+	for range 3 {
+		m.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	}
+	typeWord("delete")
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("the confirmation word should queue the deletion")
+	}
+	if m.state != stateList || len(m.backups) != total-2 || len(m.allBackups) != total || len(m.marked) != 0 {
+		t.Fatalf("queued backups should leave the list but not the vault, got state %d, %d shown, %d in the vault", m.state, len(m.backups), len(m.allBackups))
+	}
+	if view := m.View().Content; !strings.Contains(view, "2 backup(s) pending deletion") || !strings.Contains(view, "u to undo") {
+		t.Errorf("the status bar should show the pending deletion:\n%s", view)
+	}
+	m.Update(deleteTickMsg{})
+	if len(m.deletes.pending) != 1 {
+		t.Fatal("a deletion should wait out its grace period")
+	}
+
+	// u undoes it within the grace period
+	m.Update(tea.KeyPressMsg{Code: 'u', Text: "u"})
+	if len(m.deletes.pending) != 0 || len(m.backups) != total || !strings.Contains(m.statusMessage(), "undone") {
+		t.Fatalf("u should return the backups to the list, got %d of %d, %q", len(m.backups), total, m.statusMessage())
+	}
+
+	// Left alone, it is sent once the grace period ends
+	m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	typeWord("delete")
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	endGrace()
+	if len(m.deletes.pending) != 0 || len(m.allBackups) != total-2 || !strings.Contains(m.statusMessage(), "Deleted 2 of 2") {
+		t.Fatalf("the deleted backups should leave the vault, got %d of %d, %q", len(m.allBackups), total, m.statusMessage())
+	}
+
+	// AWS Backup refusing under a vault lock keeps the backup, back on the list
+	fx.VaultLocks = map[string]aws.FixtureVaultLock{m.vaultName: {MinRetentionDays: 36500}}
+	m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	typeWord("delete")
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	endGrace()
+	if len(m.allBackups) != total-2 || len(m.backups) != total-2 || !strings.Contains(m.statusMessage(), "kept") {
+		t.Errorf("a refused deletion should be reported as kept, got %d backups, %q", len(m.backups), m.statusMessage())
+	}
+
+	// Known to the vault lock: not even offered
+	m.vaultInfo = &aws.VaultInfo{Name: m.vaultName, Locked: true, MinRetentionDays: 36500}
+	m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	if m.state != stateList || !strings.Contains(m.statusMessage(), "Nothing to delete") {
+		t.Errorf("a backup the vault lock retains should not be offered, got state %d, %q", m.state, m.statusMessage())
+	}
+}
+
+func TestModel_DeleteAfterRegionSwitch(t *testing.T) {
+	ctx := context.Background()
+	fx, _ := aws.LoadFixtures("")
+	other, _ := aws.LoadFixtures("")
+	original, switched := aws.NewSimulatedBackupClient(fx), aws.NewSimulatedBackupClient(other)
+	m := newTestModel()
+	m.backupClient, m.vaultName = original, fx.Vaults[0]
+	m.allBackups, _ = original.ListRecoveryPoints(ctx, m.vaultName, "")
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+	total := len(m.allBackups)
+	target := m.backups[0]
+
+	m.queueDelete([]aws.RecoveryPoint{target})
+	m.Update(vaultSwitchedMsg{to: vaultLocation{region: "us-east-1", vault: fx.Vaults[0]}, client: switched})
+	if m.backupClient != switched {
+		t.Fatal("the switch should replace the client")
+	}
+	m.deletes.pending[0].due = time.Now()
+	_, cmd := m.Update(deleteTickMsg{})
+	if cmd == nil {
+		t.Fatal("a due deletion should be sent")
+	}
+	m.Update(cmd())
+
+	if left, _ := original.ListRecoveryPoints(ctx, fx.Vaults[0], ""); len(left) != total-1 {
+		t.Errorf("the deletion should go through the client of the region it was confirmed in, %d of %d left", len(left), total)
+	}
+	if left, _ := switched.ListRecoveryPoints(ctx, fx.Vaults[0], ""); len(left) != total {
+		t.Errorf("the switched-to region's vault should be untouched, %d of %d left", len(left), total)
+	}
+}
+
+func TestModel_QuitWithPendingDeletions(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.deletes.pending = []pendingDelete{{vault: m.vaultName, targets: m.backups[:1], due: time.Now().Add(time.Minute)}}
+	quits := func(cmd tea.Cmd) bool {
+		if cmd == nil {
+			return false
+		}
+		_, ok := cmd().(tea.QuitMsg)
+		return ok
+	}
+
+	// q asks instead of quitting, naming the pending deletions
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 'q', Text: "q"}); cmd != nil || m.state != stateQuitDeletes {
+		t.Fatalf("q with deletions pending should ask first, got state %d", m.state)
+	}
+	if view := m.View().Content; !strings.Contains(view, "RDS my-cluster") || !strings.Contains(view, "discard") {
+		t.Errorf("the question should name the pending deletion:\n%s", view)
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.state != stateList || len(m.deletes.pending) != 1 {
+		t.Fatalf("esc should go back with the deletion still pending, got state %d", m.state)
+	}
+
+	// d discards them; ctrl+c asks as q does
+	m.Update(tea.KeyPressMsg{Code: 'c', Mod: tea.ModCtrl})
+	if m.state != stateQuitDeletes {
+		t.Fatalf("ctrl+c with deletions pending should ask first, got state %d", m.state)
+	}
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"}); !quits(cmd) {
+		t.Error("d should quit, discarding the deletions")
+	}
+
+	// s sends them before quitting
+	m.state = stateList
+	m.Update(tea.KeyPressMsg{Code: 'q', Text: "q"})
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 's', Text: "s"}); cmd == nil || len(m.deletes.pending) != 0 {
+		t.Errorf("s should send the pending deletions, %d still pending", len(m.deletes.pending))
+	}
+
+	// The lock panel asks too, without naming the backups
+	m.deletes.pending = []pendingDelete{{vault: m.vaultName, targets: m.backups[:1], due: time.Now().Add(time.Minute)}}
+	m.state, m.idle.locked = stateList, true
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 'c', Mod: tea.ModCtrl}); cmd != nil || !m.idle.quitting {
+		t.Fatal("ctrl+c on the lock panel with deletions pending should ask first")
+	}
+	if view := m.View().Content; !strings.Contains(view, "1 backup(s) in 1 vault(s) are pending deletion") || strings.Contains(view, "my-cluster") {
+		t.Errorf("the lock panel should count the pending deletions without naming them:\n%s", view)
+	}
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 'c', Mod: tea.ModCtrl}); !quits(cmd) {
+		t.Error("ctrl+c again should quit, discarding the deletions")
+	}
+}
+
+func TestModel_LatestRestorableBanner(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName, m.vaultName = fx.Stacks[0].Name, fx.Vaults[0]
+	backups, _ := m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "")
+
+	_, cmd := m.Update(backupsLoadedMsg{backups: backups})
+	view := m.View().Content
+	if !strings.Contains(view, "Latest restorable") || strings.Contains(view, "restore tested") || strings.Contains(view, "point-in-time") {
+		t.Fatalf("list should show the latest restorable banner before restore tests and the cluster load:\n%s", view)
+	}
+	if cmd == nil {
+		t.Fatal("loading backups should load recent restores")
+	}
+	var run func(tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				run(c)
+			}
+			return
+		}
+		m.Update(msg)
+	}
+	run(cmd)
+	view = m.View().Content
+	if !strings.Contains(view, "✓ restore tested") {
+		t.Error("banner should show the fixture's passed restore test")
+	}
+	// The fixture's completed RDS backups are 3h and 27h old, inside the
+	// simulated cluster's week of point-in-time restore
+	for _, want := range []string{"point-in-time: any moment", "2h55m past the latest backup", "covers the 1 gap(s) between its backups, longest 24h00m"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("banner should show the cluster's point-in-time window, want %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestModel_RestoreProgress(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	job := m.addJob(m.backups[0], nil)
+	steps := make(chan string, 1)
+	steps <- "Resolving cluster…"
+	close(steps)
+
+	cmd := waitForRestoreProgress(job.seq, steps)
+	_, cmd = m.Update(cmd())
+	if job.progress != "Resolving cluster…" || m.statusMessage() != "Restoring: Resolving cluster…" {
+		t.Errorf("progress should be shown in the status bar, got %q", m.statusMessage())
+	}
+	m.state = stateJobs
+	if !strings.Contains(m.View().Content, "Resolving cluster…") {
+		t.Error("jobs view should show the starting restore's step")
+	}
+	if msg := cmd(); msg != nil {
+		t.Errorf("closed progress channel should end the updates, got %T", msg)
+	}
+}
+
+func TestModel_APICallBudget(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	calls := 40
+	m.apiCalls = apiCallsView{budget: 100, initial: 100, usage: func() aws.APIUsage {
+		return aws.APIUsage{Since: time.Now(), Services: []aws.ServiceCalls{
+			{Service: "Backup", Calls: calls, Retries: 2, Throttles: 1},
+			{Service: "RDS", Calls: 10},
+		}}
+	}}
+
+	m.openDetail()
+	if m.apiCalls.paused || m.prefetch.arn != m.backups[0].RecoveryPointARN {
+		t.Fatal("the restore should be prefetched within the budget")
+	}
+
+	calls = 95
+	m.state = stateList
+	m.selectedIdx = 1
+	m.openDetail()
+	if !m.apiCalls.paused || m.prefetch.arn == m.backups[1].RecoveryPointARN || !strings.Contains(m.statusMessage(), "budget of 100 used up") {
+		t.Fatalf("the prefetch should pause once the budget is used up, got %q", m.statusMessage())
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'd', Mod: tea.ModCtrl})
+	view := m.View().Content
+	if m.state != stateAPICalls || !strings.Contains(view, "Backup") || !strings.Contains(view, "background refresh is paused") {
+		t.Fatalf("ctrl+d should show the calls and the paused budget:\n%s", view)
+	}
+	m.Update(tea.KeyPressMsg{Code: '+', Text: "+"})
+	if m.apiCalls.paused || m.apiCalls.budget != 200 || !strings.Contains(m.statusMessage(), "resumed") {
+		t.Errorf("+ should raise the budget and resume, got %+v, %q", m.apiCalls, m.statusMessage())
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.state != stateDetail {
+		t.Errorf("esc should return to the detail view, got %v", m.state)
+	}
+}
+
+func TestModel_ErrorLog(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.allBackups = m.backups
+
+	m.Update(lifecycleUpdatedMsg{err: fmt.Errorf("access denied")})
+	m.Update(restoreStatusMsg{jobID: "job-1", status: &aws.RestoreJobStatus{Status: "FAILED", StatusMessage: "subnet full", IsTerminal: true}})
+	if len(m.errorLog.entries) != 2 || !strings.Contains(m.View().Content, "2 new error(s)") {
+		t.Fatalf("errors and failed jobs should be logged and counted in the status bar, got %+v", m.errorLog.entries)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'e', Text: "e"})
+	if m.state != stateErrorLog || m.errorLog.unseen != 0 {
+		t.Fatal("e should open the error log")
+	}
+	view := m.View().Content
+	if strings.Index(view, "subnet full") > strings.Index(view, "access denied") {
+		t.Error("newest entries should be listed first")
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if !strings.Contains(m.View().Content, "Error:") {
+		t.Error("enter should expand the selected entry")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.state != stateList {
+		t.Errorf("esc should return to the list, got state %d", m.state)
+	}
+
+	for i := 0; i < errorLogSize+5; i++ {
+		m.reportError("failed", nil)
+	}
+	if len(m.errorLog.entries) != errorLogSize {
+		t.Errorf("log should keep the last %d entries, got %d", errorLogSize, len(m.errorLog.entries))
+	}
+}
+
+func TestModel_RegionalFailureChecksServiceHealth(t *testing.T) {
+	m := newTestModel()
+	m.health.checker = aws.NewHealthChecker() // Never called: the check command is not run
+	serverFault := &smithy.GenericAPIError{Code: "ServiceUnavailableException", Fault: smithy.FaultServer}
+
+	m.reportError("access denied", fmt.Errorf("access denied"))
+	if m.health.due {
+		t.Fatal("client errors should not check service health")
+	}
+	if _, cmd := m.Update(lifecycleUpdatedMsg{err: serverFault}); cmd == nil || m.health.due || m.health.checkedAt.IsZero() {
+		t.Fatal("a server fault should start a health check")
+	}
+	m.reportError("failed", serverFault)
+	if m.health.due {
+		t.Error("health should be checked at most once per interval")
+	}
+
+	incident := aws.ServiceIncident{Service: "AWS Backup", Region: "us-west-2", Title: "Increased API error rates"}
+	m.Update(healthCheckedMsg{region: "eu-west-1", incidents: []aws.ServiceIncident{incident}})
+	if strings.Contains(m.statusMessage(), "incident") {
+		t.Error("incidents of a region switched away from should be dropped")
+	}
+	m.Update(healthCheckedMsg{region: "us-west-2", incidents: []aws.ServiceIncident{incident}})
+	if got := m.statusMessage(); got != "AWS Backup is reporting an incident in us-west-2: Increased API error rates" {
+		t.Errorf("the incident should replace the generic error, got %q", got)
+	}
+}
+
+func TestModel_ErrorViewShowsRequestID(t *testing.T) {
+	m := newTestModel()
+	m.err = fmt.Errorf("failed to list recovery points: %w", &smithy.OperationError{
+		ServiceID:     "Backup",
+		OperationName: "ListRecoveryPointsByBackupVault",
+		Err: &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: 403}},
+				Err:      &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized"},
+			},
+			RequestID: "req-1234",
+		},
+	})
+	m.state = stateError
+
+	view := m.View().Content
+	for _, want := range []string{"Operation:  Backup ListRecoveryPointsByBackupVault", "Request ID: req-1234"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("error view should show %q, got:\n%s", want, view)
+		}
+	}
+}
+
+func TestModel_StatusBoard(t *testing.T) {
+	board := NewStatusBoard()
+	m := newTestModel()
+	m.statusBoard = board
+	m.backups = sampleBackups()
+	m.allBackups = m.backups
+
+	job := m.addJob(m.backups[0], nil)
+	m.addJob(m.backups[0], job)
+	m.Update(restoreProgressMsg{seq: job.seq, step: "Resolving IAM role…"})
+
+	s := board.Status()
+	if s.Stack != "TestStack" || s.Running != 2 || len(s.Jobs) != 2 {
+		t.Fatalf("status should list both jobs as running, got %+v", s)
+	}
+	if s.Jobs[0].State != "STARTING" || s.Jobs[0].Progress != "Resolving IAM role…" || s.Jobs[1].State != "QUEUED" || s.Jobs[1].After != 1 {
+		t.Errorf("unexpected jobs %+v", s.Jobs)
+	}
+
+	rec := httptest.NewRecorder()
+	board.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"state": "QUEUED"`) {
+		t.Errorf("GET /status = %d %s", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	board.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/status", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /status = %d, want 405", rec.Code)
+	}
+}
+
+func TestRestoreFieldHelp_CoversAllFields(t *testing.T) {
+	role := &aws.PlanRole{RoleARN: "arn"}
+	for _, rt := range []string{"RDS", "EFS"} {
+		for _, f := range restoreFields(&aws.RestoreMetadata{ResourceType: rt}, role) {
+			if h, ok := fieldHelpFor(rt, f.key); !ok || h.what == "" || h.ifChange == "" {
+				t.Errorf("%s field %s has no help", rt, f.key)
+			}
+		}
+	}
+}
+
+func TestModel_ResourceKinds(t *testing.T) {
+	m := newConfirmTestModel()
+	m.state = stateConfirm
+	if hints := m.renderKeyHints(); !strings.Contains(hints, "subnet group") || !strings.Contains(hints, "new cluster") {
+		t.Errorf("RDS confirm hints should offer the RDS keys: %q", hints)
+	}
+	m.selectedIdx = 1
+	if hints := m.renderKeyHints(); strings.Contains(hints, "subnet group") || !strings.Contains(hints, "encryption key") {
+		t.Errorf("EFS confirm hints should offer only the common keys: %q", hints)
+	}
+
+	fields := restoreFields(&aws.RestoreMetadata{ResourceType: "DynamoDB"}, &aws.PlanRole{RoleARN: "arn"})
+	if len(fields) != 2 || fields[0].key != "KmsKeyId" || fields[1].key != "IamRoleArn" {
+		t.Errorf("a type without a kind should show the common fields only: %+v", fields)
+	}
+}
+
+// --- Restore chaining ---
+
+// newChainTestModel returns a model with restore #1 (RDS) active and the EFS
+// backup selected on the confirm screen.
+func newChainTestModel() *Model {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	first := m.addJob(m.backups[0], nil)
+	m.Update(restoreInitiatedMsg{seq: first.seq, jobID: "job-rds"})
+	m.selectedIdx = 1
+	m.state = stateConfirm
+	return m
+}
+
+func TestModel_Chain_QueueAfterActiveRestore(t *testing.T) {
+	m := newChainTestModel()
+
+	m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+	if m.state != stateJobs {
+		t.Fatalf("queuing should open the jobs view, got %d", m.state)
+	}
+	if len(m.jobs) != 2 || m.jobs[1].state != jobQueued || m.jobs[1].after != m.jobs[0] {
+		t.Fatalf("expected #2 queued after #1, got %+v", m.jobs)
+	}
+	content := m.View().Content
+	if !strings.Contains(content, "└─▶ #2") || !strings.Contains(content, "starts when #1 completes") {
+		t.Errorf("jobs view should draw the chain, got:\n%s", content)
+	}
+}
+
+func TestModel_Chain_NothingToChainAfter(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.state = stateConfirm
+
+	m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+	if m.state != stateConfirm || len(m.jobs) != 0 {
+		t.Error("a should not queue without an unfinished restore")
+	}
+	if !strings.Contains(m.statusMessage(), "No restore in progress") {
+		t.Errorf("unexpected status %q", m.statusMessage())
+	}
+}
+
+func TestModel_Chain_StartsNextOnCompleted(t *testing.T) {
+	m := newChainTestModel()
+	m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+
+	_, cmd := m.Update(restoreStatusMsg{jobID: "job-rds", status: &aws.RestoreJobStatus{
+		JobID: "job-rds", Status: "COMPLETED", IsTerminal: true,
+	}})
+	if m.jobs[0].state != jobCompleted || m.jobs[1].state != jobStarting || cmd == nil {
+		t.Fatalf("#2 should start once #1 completes: %v / %v", m.jobs[0].state, m.jobs[1].state)
+	}
+
+	m.Update(restoreInitiatedMsg{seq: 2, jobID: "job-efs"})
+	if m.jobs[1].state != jobActive || m.jobs[1].jobID != "job-efs" {
+		t.Errorf("#2 should be active, got %v", m.jobs[1].state)
+	}
+	if m.state != stateJobs {
+		t.Error("a chained step should not leave the jobs view")
+	}
+}
+
+func TestModel_Chain_SkipsNextOnFailure(t *testing.T) {
+	m := newChainTestModel()
+	m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+
+	m.Update(restoreStatusMsg{jobID: "job-rds", status: &aws.RestoreJobStatus{
+		JobID: "job-rds", Status: "FAILED", StatusMessage: "snapshot unavailable", IsTerminal: true,
+	}})
+	if m.jobs[0].state != jobFailed || m.jobs[1].state != jobSkipped {
+		t.Fatalf("#2 should be skipped when #1 fails: %v / %v", m.jobs[0].state, m.jobs[1].state)
+	}
+}
+
+func TestModel_Chain_CancelQueuedStep(t *testing.T) {
+	m := newChainTestModel()
+	m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+	m.state = stateConfirm
+	m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+	if m.jobs[2].after != m.jobs[1] {
+		t.Fatal("#3 should be chained after #2")
+	}
+
+	m.jobsCursor = 1
+	m.Update(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	if m.jobs[1].state != jobCancelled || m.jobs[2].state != jobCancelled {
+		t.Fatalf("cancelling #2 should cancel #3 too: %v / %v", m.jobs[1].state, m.jobs[2].state)
+	}
+
+	m.Update(restoreStatusMsg{jobID: "job-rds", status: &aws.RestoreJobStatus{
+		JobID: "job-rds", Status: "COMPLETED", IsTerminal: true,
+	}})
+	if m.jobs[1].state != jobCancelled {
+		t.Error("a cancelled step must not start")
+	}
+
+	m.jobsCursor = 0
+	m.Update(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	if !strings.Contains(m.statusMessage(), "only queued steps") {
+		t.Errorf("started steps should not be cancellable, got %q", m.statusMessage())
+	}
+}
+
+func TestModel_Chain_PollsOutsideMonitoringView(t *testing.T) {
+	m := newChainTestModel()
+	m.state = stateList
+
+	_, cmd := m.Update(restoreStatusMsg{jobID: "job-rds", status: &aws.RestoreJobStatus{
+		JobID: "job-rds", Status: "RUNNING",
+	}})
+	if cmd == nil {
+		t.Error("active jobs should keep polling from any view")
+	}
+}
+
+func TestModel_Jobs_OpenAndMonitor(t *testing.T) {
+	m := newChainTestModel()
+	m.state = stateList
+
+	m.Update(tea.KeyPressMsg{Code: 'J', Text: "J"})
+	if m.state != stateJobs {
+		t.Fatalf("J should open the jobs view, got %d", m.state)
+	}
+	m.jobsCursor = 0
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateRestoring || m.restoreJobID != "job-rds" {
+		t.Errorf("enter should monitor the selected job, got state %d job %q", m.state, m.restoreJobID)
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.state != stateList {
+		t.Error("esc should return to the list")
+	}
+}
+
+func TestModel_FileSystemMsg_OnlyForSelectedBackup(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.selectedIdx = 1 // EFS
+	m.detailModel.SetRecoveryPoint(&m.backups[1])
+	m.state = stateDetail
+
+	m.Update(fileSystemMsg{fileSystemID: "fs-other", info: &aws.FileSystemInfo{FileSystemID: "fs-other"}})
+	if strings.Contains(m.View().Content, "fs-other") {
+		t.Error("details for another file system should be ignored")
+	}
+
+	fsID := m.backups[1].ResourceID
+	m.Update(fileSystemMsg{fileSystemID: fsID, info: &aws.FileSystemInfo{FileSystemID: fsID, ThroughputMode: "elastic"}})
+	if !strings.Contains(m.View().Content, "elastic") {
+		t.Error("details for the selected file system should be shown")
+	}
+}
+
+func TestModel_ClusterHealthMsg_OnlyForRDSBackup(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.selectedIdx = 1 // EFS
+	m.detailModel.SetRecoveryPoint(&m.backups[1])
+	m.state = stateDetail
+
+	health := &aws.ClusterHealth{ClusterID: "my-cluster", Status: "available", EngineVersion: "8.0.mysql_aurora.3.08.0"}
+	m.Update(clusterHealthMsg{health: health})
+	if strings.Contains(m.View().Content, "8.0.mysql_aurora") {
+		t.Error("cluster health should not be shown for an EFS backup")
+	}
+
+	m.selectedIdx = 0 // RDS
+	m.detailModel.SetRecoveryPoint(&m.backups[0])
+	m.Update(clusterHealthMsg{health: health})
+	if !strings.Contains(m.View().Content, "8.0.mysql_aurora") {
+		t.Error("cluster health should be shown for an RDS backup")
+	}
+}
+
+func TestModel_View_ConfirmSchemaWarning(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.state = stateConfirm
+	m.selectedIdx = 0 // RDS, created 2026-02-15
+
+	m.Update(taskDefHistoryMsg{history: sampleTaskDefs()})
+	if content := m.View().Content; !strings.Contains(content, "predates the OpenEMR upgrade") || !strings.Contains(content, "openemr/openemr:7.0.2") {
+		t.Error("restoring a database backup from before the upgrade should warn")
+	}
+
+	m.selectedIdx = 1 // EFS holds no schema
+	if strings.Contains(m.View().Content, "predates the OpenEMR upgrade") {
+		t.Error("EFS backups should not get the schema warning")
+	}
+
+	m.selectedIdx = 0
+	m.appUpgrade.At = time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	if strings.Contains(m.View().Content, "predates the OpenEMR upgrade") {
+		t.Error("backups taken after the upgrade should not warn")
+	}
+}
+
+// sampleTaskDefs returns a history where revision 12 upgraded OpenEMR on
+// 2026-02-16, after both sample backups were taken.
+func sampleTaskDefs() []aws.TaskDefinitionRevision {
+	rev := func(n int32, registered time.Time, image string, env map[string]string) aws.TaskDefinitionRevision {
+		return aws.TaskDefinitionRevision{
+			Family:       "openemr",
+			Revision:     n,
+			RegisteredAt: registered,
+			Containers:   []aws.ContainerDefinition{{Name: "openemr", Image: image, Environment: env}},
+		}
+	}
+	return []aws.TaskDefinitionRevision{
+		rev(13, time.Date(2026, 2, 17, 0, 0, 0, 0, time.UTC), "openemr/openemr:7.0.3", map[string]string{"MYSQL_PORT": "3306", "SWARM_MODE": "yes"}),
+		rev(12, time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC), "openemr/openemr:7.0.3", map[string]string{"MYSQL_PORT": "3306"}),
+		rev(11, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), "openemr/openemr:7.0.2", map[string]string{"MYSQL_PORT": "3306"}),
+	}
+}
+
+func TestModel_TaskDefs_OpenFromList(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.listModel.SetItems(m.formatBackupsForList())
+	m.state = stateList
+	m.handleTaskDefHistory(taskDefHistoryMsg{history: sampleTaskDefs()})
+
+	m.Update(tea.KeyPressMsg{Code: 'T', Text: "T"})
+	if m.state != stateTaskDefs {
+		t.Fatalf("T should open the task definition history, got state %v", m.state)
+	}
+	content := m.View().Content
+	for _, want := range []string{"rev 13", "+ SWARM_MODE=yes", "image: openemr/openemr:7.0.2 → openemr/openemr:7.0.3", "running when backup was taken"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("history view should contain %q", want)
+		}
+	}
+	// The RDS sample backup (2026-02-15) was taken while revision 11 was current
+	if m.taskDefs.cursor != 2 {
+		t.Errorf("cursor should start on the revision running at backup time, got %d", m.taskDefs.cursor)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.state != stateList {
+		t.Errorf("esc should return to the list, got state %v", m.state)
+	}
+}
+
+func TestModel_TaskDefs_ReturnsToDetail(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.selectedIdx = 0
+	m.detailModel.SetRecoveryPoint(&m.backups[0])
+	m.state = stateDetail
+	m.taskDefs.loaded = true
+
+	m.Update(tea.KeyPressMsg{Code: 'T', Text: "T"})
+	if m.state != stateTaskDefs {
+		t.Fatal("T should open the task definition history from the detail view")
+	}
+	m.Update(tea.KeyPressMsg{Code: 'q', Text: "q"})
+	if m.state != stateDetail {
+		t.Errorf("q should return to the detail view, got state %v", m.state)
+	}
+}
+
+func TestModel_TaskDefs_LoadError(t *testing.T) {
+	m := newTestModel()
+	m.state = stateTaskDefs
+	m.handleTaskDefHistory(taskDefHistoryMsg{err: fmt.Errorf("access denied")})
+	if !strings.Contains(m.View().Content, "Unavailable: access denied") {
+		t.Error("load errors should be shown in the history view")
+	}
+	if m.appUpgrade != nil {
+		t.Error("no image change should be known without history")
+	}
+}
+
+func TestEnvChanges(t *testing.T) {
+	got := envChanges(
+		map[string]string{"A": "1", "B": "2", "C": "3"},
+		map[string]string{"A": "1", "B": "20", "D": "4"},
+	)
+	want := []string{"~ B: 2 → 20", "- C", "+ D=4"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// recentTimeline sets up a model with two recent backups, a failed restore,
+// a successful backup job, and a rolled-back deployment.
+func recentTimeline() *Model {
+	m := newTestModel()
+	now := time.Now()
+	m.backups = sampleBackups()
+	m.backups[0].CreationDate = now.Add(-2 * time.Hour)
+	m.backups[1].CreationDate = now.Add(-30 * time.Hour)
+	m.listModel.SetItems(m.formatBackupsForList())
+	m.state = stateList
+	m.handleTimeline(timelineMsg{
+		vault: m.vaultName,
+		jobs: []aws.JobRecord{
+			{Kind: aws.JobKindBackup, ResourceType: "RDS", State: "COMPLETED", CreatedAt: now.Add(-2 * time.Hour)},
+			{Kind: aws.JobKindRestore, ResourceType: "RDS", ResourceARN: "arn:aws:rds:us-west-2:123456789012:cluster:my-cluster",
+				State: "FAILED", StatusMessage: "access denied", CreatedAt: now.Add(-1 * time.Hour)},
+		},
+		deployments: []aws.Deployment{{
+			Service:        "openemr",
+			Status:         "ROLLBACK_SUCCESSFUL",
+			CreatedAt:      now.Add(-3 * time.Hour),
+			TaskDefinition: "arn:aws:ecs:us-west-2:123456789012:task-definition/openemr:12",
+			Image:          "openemr/openemr:7.0.3",
+		}},
+	})
+	return m
+}
+
+func TestModel_VaultJobTabs(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.vaultName, m.vaultDiscovered = fx.Vaults[0], true
+	m.Update(m.loadBackups()())
+	m.state = stateList
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	if m.state != stateBackupJobs || cmd == nil {
+		t.Fatalf("tab should open the backup jobs tab and load it, got state %v", m.state)
+	}
+	m.Update(cmd())
+	if len(m.vaultJobs.backups) == 0 {
+		t.Fatal("the fixture vault should have backup jobs")
+	}
+	view := ui.StripANSI(m.View().Content)
+	for _, want := range []string{"▸ Backup Jobs", "✗ FAILED", "file system was being modified", "✓ COMPLETED"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("the backup jobs tab should show %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "sim-copy") || strings.Contains(view, "Access denied to destination vault") {
+		t.Error("copy jobs belong to neither tab")
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	if m.state != stateRestoreJobs {
+		t.Fatalf("tab should move on to the restore jobs tab, got state %v", m.state)
+	}
+	if len(m.vaultJobs.restores) != 2 {
+		t.Errorf("both restores of the vault's cluster should be listed, got %d", len(m.vaultJobs.restores))
+	}
+	if view := ui.StripANSI(m.View().Content); !strings.Contains(view, "openemr-training-cluster → openemr-training-restore-test") {
+		t.Errorf("a restore job should name its source and restored resource:\n%s", view)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyTab, Mod: tea.ModShift})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyTab, Mod: tea.ModShift})
+	if m.state != stateList {
+		t.Errorf("shift+tab twice should return to the list, got state %v", m.state)
+	}
+	if tabOf(stateRestoreJobs) != "restore-jobs" || ValidateStartView("backup-jobs") != nil {
+		t.Error("the job tabs should be remembered and openable with -open")
+	}
+}
+
+func TestVaultRestores_KeepsTheVaultsResources(t *testing.T) {
+	points := []aws.RecoveryPoint{{RecoveryPointARN: "rp-1", ResourceARN: "arn:db"}}
+	jobs := []aws.JobRecord{
+		{JobID: "by-point", RecoveryPointARN: "rp-1"},
+		{JobID: "by-resource", RecoveryPointARN: "rp-expired", SourceResourceARN: "arn:db"},
+		{JobID: "elsewhere", RecoveryPointARN: "rp-other", SourceResourceARN: "arn:other"},
+	}
+	var ids []string
+	for _, j := range vaultRestores(jobs, points) {
+		ids = append(ids, j.JobID)
+	}
+	if strings.Join(ids, ",") != "by-point,by-resource" {
+		t.Errorf("got restores %v, want the vault's backups and resources only", ids)
+	}
+}
+
+func TestModel_Timeline_MergesNewestFirst(t *testing.T) {
+	m := recentTimeline()
+	events := m.timelineEvents()
+	kinds := make([]string, len(events))
+	for i, e := range events {
+		kinds[i] = e.kind
+	}
+	// The successful backup job duplicates the recovery point and is skipped
+	want := []string{eventRestore, eventBackup, eventDeploy, eventBackup}
+	if strings.Join(kinds, ",") != strings.Join(want, ",") {
+		t.Fatalf("got events %v, want %v", kinds, want)
+	}
+	if !events[0].failed || !events[2].failed {
+		t.Error("the failed restore and rolled-back deployment should be marked failed")
+	}
+	if !strings.Contains(events[2].summary, "openemr/openemr:7.0.3 (rev 12)") {
+		t.Errorf("deployment summary should name the image and revision, got %q", events[2].summary)
+	}
+}
+
+func TestModel_Timeline_OpenAndEnterBackup(t *testing.T) {
+	m := recentTimeline()
+	m.Update(tea.KeyPressMsg{Code: 't', Text: "t"})
+	if m.state != stateTimeline {
+		t.Fatalf("t should open the timeline, got state %v", m.state)
+	}
+	m.timeline.loading = false
+	if content := m.View().Content; !strings.Contains(content, "my-cluster  FAILED") || !strings.Contains(content, "ROLLBACK_SUCCESSFUL") {
+		t.Error("timeline should show the restore and deployment")
+	}
+
+	// Enter on a non-backup event does nothing
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateTimeline {
+		t.Errorf("enter on a restore should stay in the timeline, got state %v", m.state)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateDetail || m.selectedIdx != 1 {
+		t.Errorf("enter on a backup should open its detail view, got state %v index %d", m.state, m.selectedIdx)
+	}
+}
+
+func TestModel_Timeline_SourceErrors(t *testing.T) {
+	m := recentTimeline()
+	m.state = stateTimeline
+	m.handleTimeline(timelineMsg{vault: m.vaultName, deploymentsErr: fmt.Errorf("ECS client not configured")})
+	content := m.View().Content
+	if !strings.Contains(content, "Unavailable: deployments: ECS client not configured") {
+		t.Error("a failed source should be reported")
+	}
+	if !strings.Contains(content, "my-cluster") {
+		t.Error("backups should still be shown when a source fails")
+	}
+}
+
+func TestModel_Timeline_IgnoresOtherVault(t *testing.T) {
+	m := recentTimeline()
+	m.handleTimeline(timelineMsg{vault: "other-vault"})
+	if len(m.timeline.jobs) != 2 || len(m.timeline.deployments) != 1 {
+		t.Error("results for another vault should be ignored")
+	}
+}
+
+// withRunningRestore adds an active restore of the first sample backup.
+func withRunningRestore(m *Model) *restoreJob {
+	m.backups = sampleBackups()
+	job := m.addJob(m.backups[0], nil)
+	job.jobID, job.state, job.started = "restore-job-1", jobActive, time.Now()
+	return job
+}
+
+func TestModel_Fatal_SavesJobsAndBlocksQuit(t *testing.T) {
+	m := newTestModel()
+	m.historyPath = filepath.Join(t.TempDir(), "history.json")
+	withRunningRestore(m)
+	m.state = stateList
+
+	_, cmd := m.Update(backupsLoadedMsg{err: errTestError("api error")})
+	if m.state != stateError || cmd == nil {
+		t.Fatalf("a fatal error with a running restore should ring the bell, got state %v cmd %v", m.state, cmd)
+	}
+	saved, err := store.LoadHistory(m.historyPath)
+	if err != nil || len(saved) != 1 || saved[0].JobID != "restore-job-1" || saved[0].Region != "us-west-2" {
+		t.Errorf("running job IDs should be saved to the history file, got %+v, %v", saved, err)
+	}
+	content := m.View().Content
+	for _, want := range []string{"1 job(s) still running", "restore-job-1", "backup-tui watch", "'Q' to quit anyway"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("error screen should contain %q", want)
+		}
+	}
+
+	for _, key := range []tea.KeyPressMsg{{Code: 'q', Text: "q"}, {Code: tea.KeyEscape}} {
+		if _, cmd := m.Update(key); cmd != nil {
+			t.Errorf("%s should not quit while a restore is tracked", key.String())
+		}
+	}
+	_, cmd = m.Update(tea.KeyPressMsg{Code: 'Q', Text: "Q"})
+	if cmd == nil {
+		t.Fatal("Q should quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Q should quit")
+	}
+}
+
+func TestModel_Fatal_MinimalTracking(t *testing.T) {
+	m := newTestModel()
+	job := withRunningRestore(m)
+	m.Update(fmt.Errorf("session expired"))
+
+	m.Update(tea.KeyPressMsg{Code: 'k', Text: "k"})
+	if m.state != stateJobs || !m.minimalTracking {
+		t.Fatalf("k should keep tracking in the jobs view, got state %v", m.state)
+	}
+	m.Update(restoreStatusMsg{jobID: job.jobID, status: &aws.RestoreJobStatus{Status: "RUNNING", PercentDone: "40"}})
+	if job.label() != "RUNNING 40%" {
+		t.Errorf("restores should still be polled in minimal tracking, got %q", job.label())
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.state != stateError {
+		t.Errorf("esc should return to the error screen in minimal tracking, got state %v", m.state)
+	}
+
+	m.Update(restoreStatusMsg{jobID: job.jobID, status: &aws.RestoreJobStatus{Status: "COMPLETED", IsTerminal: true}})
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 'q', Text: "q"}); cmd == nil {
+		t.Error("q should quit once no restores are tracked")
+	}
+}
+
+func TestModel_Fatal_NoRunningJobs(t *testing.T) {
+	m := newTestModel()
+	if cmd := m.fail(fmt.Errorf("boom")); cmd != nil {
+		t.Error("no bell without tracked restores")
+	}
+	if !strings.Contains(m.View().Content, "Press 'q' to quit") {
+		t.Error("the error screen should offer q when nothing is tracked")
+	}
+}
+
+func TestModel_TrackedJobs_SavedOnStartAndFinish(t *testing.T) {
+	m := newTestModel()
+	m.historyPath = filepath.Join(t.TempDir(), "history.json")
+	m.backups = sampleBackups()
+	job := m.addJob(m.backups[0], nil)
+	job.started = time.Now()
+
+	m.Update(restoreInitiatedMsg{seq: job.seq, jobID: "restore-job-1"})
+	saved, err := store.LoadHistory(m.historyPath)
+	if err != nil || len(saved) != 1 || saved[0].JobID != "restore-job-1" || saved[0].Finished() {
+		t.Fatalf("a started restore should be saved as in flight, got %+v, %v", saved, err)
+	}
+	if saved[0].RecoveryPointARN != m.backups[0].RecoveryPointARN || saved[0].Vault != "test-vault" {
+		t.Errorf("the saved job should identify its backup, got %+v", saved[0])
+	}
+
+	m.Update(restoreStatusMsg{jobID: "restore-job-1", status: &aws.RestoreJobStatus{Status: "COMPLETED", IsTerminal: true}})
+	saved, _ = store.LoadHistory(m.historyPath)
+	if len(saved) != 1 || saved[0].State != "COMPLETED" {
+		t.Errorf("a finished restore should be saved with its final state, got %+v", saved)
+	}
+}
+
+func TestModel_ResumeJobs(t *testing.T) {
+	m := newTestModel()
+	m.historyPath = filepath.Join(t.TempDir(), "history.json")
+	recent := time.Now().Add(-time.Hour)
+	if _, err := store.SaveJobs(m.historyPath,
+		store.TrackedJob{JobID: "running", Kind: aws.JobKindRestore, Region: "us-west-2", ResourceType: "RDS", ResourceID: "my-cluster", StartedAt: recent},
+		store.TrackedJob{JobID: "finished", Kind: aws.JobKindRestore, Region: "us-west-2", StartedAt: recent, State: "COMPLETED"},
+		store.TrackedJob{JobID: "other-region", Kind: aws.JobKindRestore, Region: "us-east-1", StartedAt: recent},
+		store.TrackedJob{JobID: "stale", Kind: aws.JobKindRestore, Region: "us-west-2", StartedAt: time.Now().Add(-10 * 24 * time.Hour)},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := m.loadResumableJobs()()
+	_, cmd := m.Update(msg)
+	if len(m.jobs) != 1 || m.jobs[0].jobID != "running" || m.jobs[0].state != jobActive || cmd == nil {
+		t.Fatalf("only the recent unfinished restore in this region should be resumed and polled, got %d job(s)", len(m.jobs))
+	}
+
+	// Loading again (e.g. after a vault switch) does not duplicate it
+	m.Update(msg)
+	if len(m.jobs) != 1 {
+		t.Errorf("resumed jobs should not be added twice, got %d", len(m.jobs))
+	}
+
+	m.state = stateJobs
+	if content := m.View().Content; !strings.Contains(content, "my-cluster") || !strings.Contains(content, "resumed from an earlier session") {
+		t.Error("the jobs view should show the resumed restore")
+	}
+}
+
+func TestModel_ResumeJobs_Disabled(t *testing.T) {
+	m := newTestModel()
+	if m.loadResumableJobs() != nil {
+		t.Error("without a history file nothing should be loaded")
+	}
+}
+
+func TestModel_Chain_SavedForResuming(t *testing.T) {
+	m := newChainTestModel()
+	m.workflowsPath = filepath.Join(t.TempDir(), "workflows.json")
+	m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+
+	saved, err := store.LoadWorkflows(m.workflowsPath)
+	if err != nil || len(saved) != 1 || len(saved[0].Steps) != 2 {
+		t.Fatalf("a queued step should save its chain, got %+v, %v", saved, err)
+	}
+	w := saved[0]
+	if w.Kind != store.WorkflowRestoreChain || w.Vault != "test-vault" || w.Steps[0].JobID != "job-rds" ||
+		w.Steps[1].State != store.StepPending || w.Steps[1].ResourceID != m.backups[1].ResourceID || w.Closed {
+		t.Errorf("the saved chain should hold the started and queued steps, got %+v", w)
+	}
+
+	m.Update(restoreStatusMsg{jobID: "job-rds", status: &aws.RestoreJobStatus{Status: "COMPLETED", IsTerminal: true}})
+	m.Update(restoreInitiatedMsg{seq: 2, jobID: "job-efs"})
+	m.Update(restoreStatusMsg{jobID: "job-efs", status: &aws.RestoreJobStatus{Status: "COMPLETED", IsTerminal: true}})
+	saved, _ = store.LoadWorkflows(m.workflowsPath)
+	if len(saved) != 1 || saved[0].Steps[1].JobID != "job-efs" || !saved[0].Closed {
+		t.Errorf("a finished chain should be saved closed, got %+v", saved)
+	}
+}
+
+// savedChain saves a chain whose first step completed and whose second was
+// still queued when the session ended.
+func savedChain(t *testing.T, path string) store.Workflow {
+	t.Helper()
+	backups := sampleBackups()
+	w := store.Workflow{
+		ID: "wf-1", Kind: store.WorkflowRestoreChain, Region: "us-west-2", Vault: "test-vault", StartedAt: time.Now().Add(-time.Hour),
+		Steps: []store.WorkflowStep{
+			{Kind: aws.JobKindRestore, ResourceType: "RDS", ResourceID: backups[0].ResourceID, RecoveryPointARN: backups[0].RecoveryPointARN, JobID: "job-rds", State: "COMPLETED"},
+			{Kind: aws.JobKindRestore, ResourceType: "EFS", ResourceID: backups[1].ResourceID, RecoveryPointARN: backups[1].RecoveryPointARN, KMSKeyID: "alias/restore", State: store.StepPending},
+		},
+	}
+	if err := store.SaveWorkflow(path, w); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestModel_ResumeChain(t *testing.T) {
+	m := newTestModel()
+	m.workflowsPath = filepath.Join(t.TempDir(), "workflows.json")
+	savedChain(t, m.workflowsPath)
+
+	m.Update(m.loadWorkflows()())
+	if m.state != stateResume {
+		t.Fatalf("an interrupted chain should be offered for resuming, got state %v", m.state)
+	}
+	if content := m.View().Content; !strings.Contains(content, "Resume Interrupted Restore Chain") || !strings.Contains(content, "job job-rds") {
+		t.Errorf("the prompt should list the chain's steps, got:\n%s", content)
+	}
+
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if m.state != stateJobs || len(m.jobs) != 2 || cmd == nil {
+		t.Fatalf("resuming should rebuild the chain in the jobs view, got state %v with %d job(s)", m.state, len(m.jobs))
+	}
+	if m.jobs[0].state != jobCompleted || m.jobs[0].jobID != "job-rds" {
+		t.Errorf("the completed step should keep its job ID, got %+v", m.jobs[0])
+	}
+	if next := m.jobs[1]; next.state != jobStarting || next.after != m.jobs[0] || next.options.KMSKeyID != "alias/restore" {
+		t.Errorf("the queued step should start with its saved options once its predecessor completed, got %+v", next)
+	}
+}
+
+func TestModel_ResumeChain_Discard(t *testing.T) {
+	m := newTestModel()
+	m.workflowsPath = filepath.Join(t.TempDir(), "workflows.json")
+	savedChain(t, m.workflowsPath)
+	m.Update(m.loadWorkflows()())
+
+	m.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	if m.state != stateList || len(m.jobs) != 0 {
+		t.Fatalf("discarding should return to the list without starting anything, got state %v", m.state)
+	}
+	if saved, _ := store.LoadWorkflows(m.workflowsPath); len(saved) != 1 || !saved[0].Closed {
+		t.Errorf("a discarded chain should be saved closed, got %+v", saved)
+	}
+
+	m.Update(m.loadWorkflows()())
+	if m.state != stateList {
+		t.Error("a discarded chain should not be offered again")
+	}
+}
+
+func TestModel_ResumeChain_OtherVault(t *testing.T) {
+	m := newTestModel()
+	m.vaultName = "other-vault"
+	m.workflowsPath = filepath.Join(t.TempDir(), "workflows.json")
+	savedChain(t, m.workflowsPath)
+
+	m.Update(m.loadWorkflows()())
+	if m.state != stateList {
+		t.Error("a chain of another vault should wait until that vault is opened")
+	}
+}
+
+func TestModel_Webhook_ChainStepEvents(t *testing.T) {
+	var got []webhook.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var e webhook.Event
+		_ = json.NewDecoder(r.Body).Decode(&e)
+		got = append(got, e)
+	}))
+	defer srv.Close()
+
+	m := newChainTestModel()
+	var err error
+	if m.notifier, err = webhook.New([]config.Webhook{{URL: config.Secret{Value: srv.URL}}}); err != nil {
+		t.Fatal(err)
+	}
+	m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+	m.Update(restoreStatusMsg{jobID: "job-rds", status: &aws.RestoreJobStatus{Status: "COMPLETED", IsTerminal: true}})
+	m.Update(restoreInitiatedMsg{seq: 2, err: fmt.Errorf("no role")})
+
+	for _, cmd := range []tea.Cmd{m.notifyFinished(m.jobs[0]), m.notifyFinished(m.jobs[1])} {
+		if msg := cmd(); msg.(webhookSentMsg).err != nil {
+			t.Fatal(msg.(webhookSentMsg).err)
+		}
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	done, failed := got[0], got[1]
+	if done.Event != config.EventCompleted || done.Workflow != store.WorkflowRestoreChain || done.Step.Index != 1 || done.Step.Total != 2 ||
+		done.Step.JobID != "job-rds" || done.Step.State != "COMPLETED" || done.Vault != "test-vault" {
+		t.Errorf("unexpected completed event %+v", done)
+	}
+	if failed.Event != config.EventFailed || failed.Step.Index != 2 || failed.Step.Message != "no role" || failed.WorkflowID != done.WorkflowID {
+		t.Errorf("unexpected failed event %+v", failed)
+	}
+}
+
+func TestModel_Webhook_NoneConfigured(t *testing.T) {
+	m := newChainTestModel()
+	if m.notifyStep(m.jobs[0], config.EventStarted) != nil {
+		t.Error("nothing should be posted without webhooks")
+	}
+}
+
+func TestModel_ImportJob_PromptAndLookup(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.state = stateJobs
+
+	m.Update(tea.KeyPressMsg{Code: 'i', Text: "i"})
+	if m.state != stateImportJob {
+		t.Fatalf("i should open the import prompt, got state %v", m.state)
+	}
+	m.Update(tea.PasteMsg{Content: " sim-backup-0002\n"})
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateJobs || cmd == nil {
+		t.Fatalf("enter should look up the pasted job ID, got state %v", m.state)
+	}
+	m.Update(cmd())
+
+	if len(m.jobs) != 1 {
+		t.Fatalf("the job should be imported, got %d job(s)", len(m.jobs))
+	}
+	job := m.jobs[0]
+	if job.kind != aws.JobKindBackup || job.state != jobCompleted || job.backup.ResourceID != "fs-0sim0001" {
+		t.Errorf("a finished backup job should be imported as completed, got %+v", job)
+	}
+	if content := m.View().Content; !strings.Contains(content, "backup job sim-backup-0002 (imported)") {
+		t.Error("the jobs view should mark the imported backup job")
+	}
+}
+
+func TestModel_ImportJob_RunningIsTrackedAndSaved(t *testing.T) {
+	m := newTestModel()
+	m.historyPath = filepath.Join(t.TempDir(), "history.json")
+	m.state = stateJobs
+	cmd := m.handleJobImported(jobImportedMsg{
+		jobID:  "console-restore-1",
+		kind:   aws.JobKindRestore,
+		status: &aws.RestoreJobStatus{JobID: "console-restore-1", Status: "RUNNING", ResourceType: "Aurora", CreatedAt: time.Now()},
+	})
+	if cmd == nil || len(m.jobs) != 1 || m.jobs[0].state != jobActive {
+		t.Fatal("a running job should be tracked and polled")
+	}
+	saved, _ := store.LoadHistory(m.historyPath)
+	if len(saved) != 1 || saved[0].JobID != "console-restore-1" {
+		t.Errorf("an imported running job should be saved for resuming, got %+v", saved)
+	}
+
+	// Importing the same job again is a no-op
+	m.startImportJob()
+	m.importInput = "console-restore-1"
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter}); cmd != nil || !strings.Contains(m.statusMessage(), "already tracked") {
+		t.Errorf("an already tracked job should not be looked up again, got %q", m.statusMessage())
+	}
+}
+
+func TestModel_ImportJob_NotFound(t *testing.T) {
+	m := newTestModel()
+	m.state = stateJobs
+	m.Update(jobImportedMsg{jobID: "nope", err: fmt.Errorf("no restore or backup job nope found")})
+	if len(m.jobs) != 0 || !strings.Contains(m.statusMessage(), "Import failed") {
+		t.Errorf("lookup failures should be reported, got %q", m.statusMessage())
+	}
+}
+
+func TestModel_PlainRenderer(t *testing.T) {
+	m := newTestModel()
+	m.renderer = PlainRenderer{}
+	m.backups = sampleBackups()
+	m.allBackups = m.backups
+	m.selectedIdx = 1
+	m.listModel.SetItems(m.formatBackupsForList())
+
+	view := m.View().Content
+	if !strings.Contains(view, "stack TestStack, vault test-vault") || !strings.Contains(view, "> EFS") || strings.Contains(view, "> RDS") {
+		t.Errorf("plain list should mark the selected backup:\n%s", view)
+	}
+	if strings.Contains(m.renderer.List(m.ListView()), "\x1b[") {
+		t.Error("plain list should not be styled")
+	}
+
+	m.selectedIdx = 0
+	m.state = stateDetail
+	if view := m.View().Content; !strings.Contains(view, "Resource: my-cluster") || !strings.Contains(view, "Size: 1.0 GB") {
+		t.Errorf("plain detail should list the selected backup:\n%s", view)
+	}
+
+	job := m.addJob(m.backups[0], nil)
+	m.addJob(m.backups[1], job)
+	m.state = stateJobs
+	if view := m.View().Content; !strings.Contains(view, "2 job(s), 2 running") || !strings.Contains(view, "starts when #1 completes") {
+		t.Errorf("plain jobs should list the chain:\n%s", view)
+	}
+}
+
+func TestModel_Snapshots(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.allBackups = m.backups
+	m.activeFilter = "RDS"
+	m.marked = map[string]bool{m.backups[0].RecoveryPointARN: true}
+	m.enrich.details = map[string]*aws.RecoveryPointDetails{m.backups[0].RecoveryPointARN: {CreatedBy: "daily"}}
+
+	v := m.ListView()
+	if v.Filter != "RDS" || v.Sort != "Newest first" || len(v.Backups) != len(m.backups) {
+		t.Fatalf("unexpected list view %+v", v)
+	}
+	if b := v.Backups[0]; !b.Marked || b.CreatedBy != "daily" || v.Backups[1].Marked {
+		t.Errorf("list view should carry marks and creators, got %+v", v.Backups)
+	}
+	if d := m.DetailView(); d.Backup.ResourceID != "my-cluster" || d.Details == nil || d.Details.CreatedBy != "daily" {
+		t.Errorf("unexpected detail view %+v", d)
+	}
+	m.selectedIdx = len(m.backups)
+	if d := m.DetailView(); d.Backup.RecoveryPointARN != "" {
+		t.Errorf("detail view without a selection should be empty, got %+v", d)
+	}
+}
+
+func TestModel_Warnings(t *testing.T) {
+	m := newTestModel()
+	var pushed []Warning
+	m.warnings.onPush = func(w Warning) { pushed = append(pushed, w) }
+
+	m.inform("Restore job started: job-1")
+	if got := m.Status(); got.StatusMsg != "Restore job started: job-1" || got.Severity != "info" {
+		t.Errorf("info message not reported, got %q (%s)", got.StatusMsg, got.Severity)
+	}
+
+	m.reportError("Failed to load backups", fmt.Errorf("throttled"))
+	if status := m.renderStatusBar(); !strings.Contains(status, "✗ Failed to load backups") {
+		t.Errorf("status bar should mark a critical message, got %q", status)
+	}
+	if len(m.errorLog.entries) != 1 {
+		t.Errorf("a critical message should still be logged, got %d entries", len(m.errorLog.entries))
+	}
+
+	m.reportWarning("Restore #1 FAILED: access denied")
+	if len(pushed) != 3 || pushed[1].Severity != SeverityCritical || pushed[2].Severity != SeverityWarn {
+		t.Fatalf("OnWarning should receive every message with its severity, got %+v", pushed)
+	}
+	if !strings.HasSuffix(pushed[2].String(), "[warn] Restore #1 FAILED: access denied") {
+		t.Errorf("String() = %q", pushed[2].String())
+	}
+
+	m.clearStatus()
+	status := m.Status()
+	if status.StatusMsg != "" || status.Severity != "" || len(status.Warnings) != 3 {
+		t.Errorf("clearing the status bar should keep recent warnings, got %+v", status)
+	}
+	for i := range recentWarnings {
+		m.inform(fmt.Sprint("step ", i))
+	}
+	if w := m.Status().Warnings; len(w) != recentWarnings || w[0].Message != "step 0" {
+		t.Errorf("recent warnings should be capped at %d, got %d", recentWarnings, len(w))
+	}
+}
+
+func TestModel_HelpPerView(t *testing.T) {
+	m := newTestModel()
+	m.backups = sampleBackups()
+	m.state = stateJobs
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: '?', Text: "?"})
+	m = result.(*Model)
+	if m.state != stateHelp {
+		t.Fatalf("expected help from the jobs view, got %d", m.state)
+	}
+	view := m.View().Content
+	if !strings.Contains(view, "Jobs:") || !strings.Contains(view, "left behind") || strings.Contains(view, "Navigation:") {
+		t.Error("help should list the jobs view's bindings instead of the backup list's")
+	}
+
+	// Typing while searching filters instead of closing help
+	for _, k := range []tea.KeyPressMsg{{Code: '/', Text: "/"}, {Code: 'q', Text: "q"}, {Code: 'u', Text: "u"}} {
+		result, _ = m.Update(k)
+		m = result.(*Model)
+	}
+	if m.state != stateHelp || m.helpModel.Query() != "qu" {
+		t.Fatalf("expected the filter %q in help, got %q in state %d", "qu", m.helpModel.Query(), m.state)
+	}
+	if view = m.View().Content; !strings.Contains(view, "Quit") || strings.Contains(view, "Import") {
+		t.Error("help should be filtered to the matching bindings")
+	}
+
+	// esc clears the filter, then closes help back to the jobs view
+	for range 2 {
+		result, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+		m = result.(*Model)
+	}
+	if m.state != stateJobs {
+		t.Fatalf("expected the jobs view after closing help, got %d", m.state)
+	}
+
+	// Reopening starts with no filter
+	result, _ = m.Update(tea.KeyPressMsg{Code: '?', Text: "?"})
+	m = result.(*Model)
+	if m.helpModel.Query() != "" {
+		t.Error("help should reopen unfiltered")
+	}
+}
+
+func TestModel_RestoreLock(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName, m.vaultName, m.region = fx.Stacks[0].Name, fx.Vaults[0], fx.Region
+	m.config = &config.Config{SharedRestoreLock: true}
+	m.restoreLock.path = filepath.Join(t.TempDir(), "locks.json")
+	points, _ := m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	m.backups = points[:1]
+	ctx := context.Background()
+
+	// Another session on another machine is restoring the stack
+	now := time.Now()
+	theirs := store.RestoreLock{Holder: "arn:aws:iam::123456789012:user/bob", Host: "ops-2", PID: 7, Since: now, Updated: now}
+	if err := m.backupClient.SetSharedRestoreLock(ctx, m.vaultName, m.stackName, theirs.TagValue()); err != nil {
+		t.Fatal(err)
+	}
+	m.Update(m.checkRestoreLock()())
+	if m.warnings.current == nil || !strings.Contains(m.warnings.current.Message, "in progress in another session: bob on ops-2") {
+		t.Fatalf("the operator should be warned of the other session's restore, got %+v", m.warnings.current)
+	}
+	m.state = stateConfirm
+	if view := m.View().Content; !strings.Contains(view, "Restore in progress in another session") {
+		t.Errorf("the confirmation should warn of the other session's restore:\n%s", view)
+	}
+	m.warnings.current = nil
+	m.Update(m.checkRestoreLock()())
+	if m.warnings.current != nil {
+		t.Error("the same lock should be warned about once")
+	}
+	if err := m.backupClient.ClearSharedRestoreLock(ctx, m.vaultName, m.stackName); err != nil {
+		t.Fatal(err)
+	}
+
+	// This session's restore takes the lock, and releases it when done
+	job := m.addJob(m.backups[0], nil)
+	job.state, job.jobID = jobActive, "job-rds"
+	m.Update(m.syncRestoreLock()())
+	v, _ := m.backupClient.SharedRestoreLock(ctx, m.vaultName, m.stackName)
+	if held, err := store.ParseLockTag(v); err != nil || !held.SameSession(m.lockSession()) {
+		t.Fatalf("the shared lock should name this session, got %q, %v", v, err)
+	}
+	if locks, _ := store.LoadLocks(m.restoreLock.path); !locks[m.viewEnv()].SameSession(m.lockSession()) {
+		t.Errorf("the local lock should name this session, got %+v", locks)
+	}
+	if msg := m.checkRestoreLock()().(restoreLockMsg); msg.lock != nil {
+		t.Errorf("a session's own lock is not another's, got %+v", msg.lock)
+	}
+
+	job.state = jobCompleted
+	m.Update(m.syncRestoreLock()())
+	if v, _ := m.backupClient.SharedRestoreLock(ctx, m.vaultName, m.stackName); v != "" {
+		t.Errorf("the shared lock should be released, got %q", v)
+	}
+	if locks, _ := store.LoadLocks(m.restoreLock.path); len(locks) != 0 {
+		t.Errorf("the local lock should be released, got %+v", locks)
+	}
+	if m.syncRestoreLock() != nil {
+		t.Error("nothing is left to release")
+	}
+}
+
+func TestModel_TeamActivity(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName, m.vaultName, m.region = fx.Stacks[0].Name, fx.Vaults[0], fx.Region
+	points, _ := m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	m.backups = points[:1]
+	job := m.addJob(m.backups[0], nil)
+	job.state, job.jobID = jobActive, "job-rds"
+
+	// Without a journal nothing is recorded, and the view says how to set one up
+	if m.journalStep(job, config.EventStarted) != nil {
+		t.Error("nothing should be recorded without a journal")
+	}
+	m.Update(tea.KeyPressMsg{Code: 'O', Text: "O"})
+	if m.state != stateTeamActivity {
+		t.Fatalf("O should open the team activity view, got state %v", m.state)
+	}
+	if view := m.View().Content; !strings.Contains(view, "No shared journal") {
+		t.Errorf("the view should explain there is no journal:\n%s", view)
+	}
+
+	// With one, the job's transitions are recorded and shown
+	m.state = stateList
+	m.config = &config.Config{Journal: &config.Journal{Table: "backup-tui-journal"}}
+	m.Update(m.journalStep(job, config.EventStarted)())
+	if len(m.errorLog.entries) != 0 {
+		t.Fatalf("the transition should be recorded, got %+v", m.errorLog.entries)
+	}
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'O', Text: "O"})
+	if cmd == nil {
+		t.Fatal("opening the view should read the journal")
+	}
+	m.Update(cmd())
+	if len(m.team.entries) != 1 || m.team.entries[0].JobID != "job-rds" || m.team.entries[0].Event != config.EventStarted {
+		t.Fatalf("the recorded transition should be read back, got %+v", m.team.entries)
+	}
+	if view := m.View().Content; !strings.Contains(view, "restore started") || !strings.Contains(view, "job-rds") {
+		t.Errorf("the view should list the restore:\n%s", view)
+	}
+
+	job.imported = true
+	if m.journalStep(job, config.EventCompleted) != nil {
+		t.Error("jobs started outside the TUI should not be recorded")
+	}
+}
+
+func TestModel_VerifyRestore(t *testing.T) {
+	fx, _ := aws.LoadFixtures("")
+	m := newTestModel()
+	m.backupClient = aws.NewSimulatedBackupClient(fx)
+	m.stackName, m.vaultName, m.region = fx.Stacks[0].Name, fx.Vaults[0], fx.Region
+	m.verify.path = filepath.Join(t.TempDir(), "verifications.json")
+	points, _ := m.backupClient.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	m.backups = points[:1]
+	m.state = stateJobs
+
+	// Only completed restores can be verified
+	job := m.addJob(m.backups[0], nil)
+	job.state, job.jobID = jobActive, "sim-restore-test-0001"
+	if _, cmd := m.Update(tea.KeyPressMsg{Code: 'V', Text: "V"}); cmd != nil || m.warnings.current == nil || m.warnings.current.Severity != SeverityWarn {
+		t.Fatalf("a running restore should not be verified, got %+v", m.warnings.current)
+	}
+
+	// The fixture restore test's tables are counted with the configured secret
+	job.state = jobCompleted
+	m.config = &config.Config{Verify: &config.Verification{DatabaseSecret: "arn:aws:secretsmanager:us-west-2:123456789012:secret:openemr-db"}}
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'V', Text: "V"})
+	if cmd == nil || !m.verify.running["sim-restore-test-0001"] {
+		t.Fatal("V should verify the completed restore")
+	}
+	m.Update(cmd())
+	if len(m.errorLog.entries) != 0 {
+		t.Fatalf("the restore should be verified, got %+v", m.errorLog.entries)
+	}
+	v, ok := m.verify.latest[m.backups[0].RecoveryPointARN]
+	if !ok || v.Result != store.VerifyPassed || len(v.Checks) != len(config.DefaultVerifyTables) {
+		t.Fatalf("the restore should pass every table check, got %+v", v)
+	}
+	if c, _ := v.Check("rows patient_data"); c.Value != 1842 {
+		t.Errorf("unexpected patient_data count %+v", c)
+	}
+	if saved, _ := store.LoadVerifications(m.verify.path); len(saved) != 1 {
+		t.Errorf("the verification should be saved, got %+v", saved)
+	}
+
+	// The detail view shows the backup's last result
+	m.openDetail()
+	if view := m.View().Content; !strings.Contains(view, "Verified:") || !strings.Contains(view, "PASSED") {
+		t.Errorf("the detail view should show the verification:\n%s", view)
+	}
+}
+
+func TestModel_RestoreAt_SelectsClosestBefore(t *testing.T) {
+	m := newTestModel()
+	m.state = stateList
+	now := time.Now().UTC().Truncate(time.Minute)
+	m.backups = []aws.RecoveryPoint{
+		{RecoveryPointARN: "arn:rds-new", ResourceARN: "arn:db", ResourceType: "RDS", ResourceID: "db", Status: "COMPLETED", CreationDate: now.Add(-time.Hour)},
+		{RecoveryPointARN: "arn:efs-old", ResourceARN: "arn:fs", ResourceType: "EFS", ResourceID: "fs", Status: "COMPLETED", CreationDate: now.Add(-26 * time.Hour)},
+		{RecoveryPointARN: "arn:rds-old", ResourceARN: "arn:db", ResourceType: "RDS", ResourceID: "db", Status: "COMPLETED", CreationDate: now.Add(-25 * time.Hour)},
+	}
+	m.allBackups = m.backups
+	m.listModel.SetItems(m.formatBackupsForList())
+
+	m.Update(tea.KeyPressMsg{Code: '@', Text: "@"})
+	if m.state != stateRestoreAt {
+		t.Fatalf("@ should open the moment prompt, got state %v", m.state)
+	}
+	m.Update(tea.PasteMsg{Content: "not a time"})
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateRestoreAt || !strings.Contains(m.statusMessage(), "invalid moment") {
+		t.Fatalf("an invalid moment should keep the prompt open, got state %v, %q", m.state, m.statusMessage())
+	}
+
+	m.restoreAtInput = now.Add(-24 * time.Hour).Format("2006-01-02T15:04Z")
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.state != stateList {
+		t.Fatalf("enter should return to the list, got state %v", m.state)
+	}
+	if m.selectedIdx != 1 {
+		t.Errorf("the first resource's closest backup before the moment should be selected, got index %d", m.selectedIdx)
+	}
+	if msg := m.statusMessage(); !strings.Contains(msg, "EFS fs") || !strings.Contains(msg, "(2h0m before)") || !strings.Contains(msg, "RDS db") {
+		t.Errorf("every resource's pick should be reported, got %q", msg)
+	}
+}
@@ -0,0 +1,101 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements restoring to a new RDS cluster: "r" on the restore
+// confirmation asks for a suffix, and the backup is restored to a cluster
+// named after the live one with it, e.g. openemr-db-verify, so the data can
+// be verified before production is cut over.
+package app
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// startClusterSuffix opens the suffix prompt for the pending RDS restore,
+// filled in with the current suffix.
+func (m *Model) startClusterSuffix() {
+	if m.selectedIdx >= len(m.backups) || m.backups[m.selectedIdx].ResourceType != "RDS" {
+		return
+	}
+	if m.restoreMetadata == nil {
+		m.notify(SeverityWarn, "The restore parameters are still loading")
+		return
+	}
+	m.clusterSuffixInput = m.restoreOpts.ClusterSuffix
+	m.state = stateNewCluster
+}
+
+// updateClusterSuffix handles key presses at the suffix prompt. An empty
+// suffix restores under the live cluster's name again.
+func (m *Model) updateClusterSuffix(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.state = stateConfirm
+	case "enter":
+		suffix := strings.ToLower(strings.TrimSpace(m.clusterSuffixInput))
+		if suffix != "" {
+			if _, err := aws.RestoredClusterID(m.restoreMetadata.LiveClusterID(), suffix); err != nil {
+				m.notify(SeverityWarn, err.Error())
+				return m, nil
+			}
+		}
+		m.setClusterSuffix(suffix)
+		m.state = stateConfirm
+	case "backspace":
+		if r := []rune(m.clusterSuffixInput); len(r) > 0 {
+			m.clusterSuffixInput = string(r[:len(r)-1])
+		}
+	default:
+		if msg.Text != "" {
+			m.clusterSuffixInput += msg.Text
+		}
+	}
+	return m, nil
+}
+
+// setClusterSuffix sets the suffix of the pending restore's new cluster;
+// "" restores under the live cluster's name.
+func (m *Model) setClusterSuffix(suffix string) {
+	m.restoreOpts.ClusterSuffix = suffix
+	m.restoreMetadata.ApplyOptions(m.restoreOpts)
+	if suffix == "" {
+		m.inform("Restore uses the live cluster's name " + m.restoreMetadata.ClusterID)
+		return
+	}
+	m.inform("Restore will create the new cluster " + m.restoreMetadata.ClusterID + " alongside " + m.restoreMetadata.LiveClusterID())
+}
+
+// renderClusterSuffix renders the suffix prompt.
+func (m *Model) renderClusterSuffix() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2)
+	labelStyle := lipgloss.NewStyle().Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+
+	live := m.restoreMetadata.LiveClusterID()
+	name := live + "-" + strings.ToLower(strings.TrimSpace(m.clusterSuffixInput))
+	if strings.TrimSpace(m.clusterSuffixInput) == "" {
+		name = live + " (the live cluster's name)"
+	}
+	lines := []string{
+		labelStyle.Render("Restore to a new cluster"),
+		"",
+		"Suffix for the restored cluster's name:",
+		"> " + m.clusterSuffixInput + "█",
+		"",
+		"Cluster: " + name,
+		"",
+		dimStyle.Render("e.g. verify or pre-incident; letters, digits, and hyphens. Leave empty for the live cluster's name."),
+		dimStyle.Render("OpenEMR keeps using " + live + " until the stack's database endpoint is updated."),
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,210 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the restore prefetch: when a backup's detail view
+// opens, its restore parameters, the restore role, the KMS keys, and the
+// stack VPC's security and subnet groups are looked up in the background,
+// shown as a preflight in the detail view, and reused by the confirmation
+// screen and its pickers, so starting a restore does not wait on AWS.
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// prefetchState holds the restore prerequisites looked up for one backup.
+// Each result is nil until its lookup completes.
+type prefetchState struct {
+	arn            string // Backup the lookups are for
+	metadata       *restoreMetadataMsg
+	roleErr        error // Error resolving the restore role, which is kept in Model.planRole
+	kms            *kmsKeysMsg
+	securityGroups *securityGroupsMsg
+	subnetGroups   *subnetGroupsMsg
+}
+
+// prefetchedMsg delivers a lookup's result for the backup with the given ARN.
+type prefetchedMsg struct {
+	arn string
+	msg tea.Msg
+}
+
+// prefetchRestore starts the restore lookups for the selected backup,
+// unless the API call budget has paused background refresh; the
+// confirmation screen then looks up what it needs.
+func (m *Model) prefetchRestore() tea.Cmd {
+	if m.backgroundPaused() {
+		m.detailModel.SetPreflight(nil)
+		return nil
+	}
+	rp := m.backups[m.selectedIdx]
+	m.prefetch = prefetchState{arn: rp.RecoveryPointARN}
+	wrap := func(cmd tea.Cmd) tea.Cmd {
+		return func() tea.Msg {
+			return prefetchedMsg{arn: rp.RecoveryPointARN, msg: cmd()}
+		}
+	}
+	cmds := []tea.Cmd{wrap(m.fetchRestoreMetadata()), wrap(m.loadKMSKeys(rp.ResourceType))}
+	if m.planRole == nil {
+		cmds = append(cmds, wrap(m.resolvePlanRole(false)))
+	}
+	if prefetch := kindOf(rp.ResourceType).prefetch; prefetch != nil {
+		for _, cmd := range prefetch(m) {
+			cmds = append(cmds, wrap(cmd))
+		}
+	}
+	m.detailModel.SetPreflight(m.preflightItems())
+	return tea.Batch(cmds...)
+}
+
+// prefetched returns the prefetch of the selected backup, or nil if the
+// prefetch is for another one.
+func (m *Model) prefetched() *prefetchState {
+	if m.selectedIdx >= len(m.backups) || m.backups[m.selectedIdx].RecoveryPointARN != m.prefetch.arn {
+		return nil
+	}
+	return &m.prefetch
+}
+
+// handlePrefetched records a lookup's result and updates the preflight.
+// Results for a backup no longer shown are dropped.
+func (m *Model) handlePrefetched(msg prefetchedMsg) {
+	p := m.prefetched()
+	if p == nil || msg.arn != p.arn {
+		return
+	}
+	switch r := msg.msg.(type) {
+	case restoreMetadataMsg:
+		p.metadata = &r
+		// The confirmation screen opened before the lookup completed
+		if m.state == stateConfirm && m.restoreMetadata == nil {
+			m.usePrefetchedMetadata()
+		}
+	case planRoleMsg:
+		p.roleErr = r.err
+		if r.err == nil {
+			m.planRole = r.planRole
+		}
+	case kmsKeysMsg:
+		p.kms = &r
+	case securityGroupsMsg:
+		p.securityGroups = &r
+	case subnetGroupsMsg:
+		p.subnetGroups = &r
+	}
+	m.detailModel.SetPreflight(m.preflightItems())
+}
+
+// restoreMetadataCmd shows the selected backup's prefetched restore
+// parameters on the confirmation screen. It returns a command fetching them
+// only if the prefetch failed or was for another backup; one still running
+// fills them in when it completes.
+func (m *Model) restoreMetadataCmd() tea.Cmd {
+	p := m.prefetched()
+	switch {
+	case p == nil || (p.metadata != nil && p.metadata.err != nil):
+		return m.fetchRestoreMetadata()
+	case p.metadata == nil:
+		return nil
+	}
+	m.usePrefetchedMetadata()
+	return nil
+}
+
+// usePrefetchedMetadata copies the prefetched restore parameters into the
+// pending restore, so overrides chosen on the confirmation screen do not
+// change the prefetch.
+func (m *Model) usePrefetchedMetadata() {
+	if m.prefetch.metadata.err != nil || m.prefetch.metadata.metadata == nil {
+		return
+	}
+	meta := *m.prefetch.metadata.metadata
+	meta.ApplyOptions(m.restoreOpts)
+	m.restoreMetadata = &meta
+}
+
+// preflightItems describes the restore prerequisites of the selected backup
+// as far as they are known.
+func (m *Model) preflightItems() []ui.PreflightItem {
+	p := m.prefetched()
+	if p == nil {
+		return nil
+	}
+	var items []ui.PreflightItem
+	switch {
+	case p.metadata == nil:
+		items = append(items, ui.PreflightItem{Label: "Parameters", Loading: true})
+	case p.metadata.err != nil:
+		items = append(items, ui.PreflightItem{Label: "Parameters", Warning: p.metadata.err.Error()})
+	case p.metadata.metadata != nil:
+		meta := p.metadata.metadata
+		if preflight := kindOf(meta.ResourceType).preflight; preflight != nil {
+			items = append(items, preflight(meta, p)...)
+		}
+	}
+
+	switch {
+	case p.roleErr != nil:
+		items = append(items, ui.PreflightItem{Label: "Role", Warning: p.roleErr.Error()})
+	case m.planRole == nil:
+		items = append(items, ui.PreflightItem{Label: "Role", Loading: true})
+	case m.planRole.Fallback:
+		items = append(items, ui.PreflightItem{Label: "Role", Value: m.planRole.RoleARN,
+			Warning: "no backup plan targets this vault; the default service role is used"})
+	default:
+		items = append(items, ui.PreflightItem{Label: "Role", Value: m.planRole.RoleARN})
+	}
+
+	switch {
+	case p.kms == nil:
+		items = append(items, ui.PreflightItem{Label: "KMS Keys", Loading: true})
+	case p.kms.err != nil:
+		items = append(items, ui.PreflightItem{Label: "KMS Keys", Warning: p.kms.err.Error()})
+	default:
+		items = append(items, ui.PreflightItem{Label: "KMS Keys",
+			Value: fmt.Sprintf("backup's key, or %d other key(s) with e on the confirmation screen", len(p.kms.keys))})
+	}
+	return items
+}
+
+// subnetGroupItem checks that the restore's subnet group is in the stack's VPC.
+func subnetGroupItem(name string, loaded *subnetGroupsMsg) ui.PreflightItem {
+	item := ui.PreflightItem{Label: "Subnet Group", Value: name}
+	switch {
+	case loaded == nil:
+		item.Loading = true
+	case loaded.err != nil:
+		item.Warning = "cannot list subnet groups: " + loaded.err.Error()
+	case !slices.ContainsFunc(loaded.groups, func(g aws.DBSubnetGroup) bool { return g.Name == name }):
+		item.Warning = "not found in the stack's VPC " + loaded.vpcID
+	}
+	return item
+}
+
+// securityGroupsItem checks that the restore's security groups are in the
+// stack's VPC.
+func securityGroupsItem(ids string, loaded *securityGroupsMsg) ui.PreflightItem {
+	item := ui.PreflightItem{Label: "Security Groups", Value: ids}
+	switch {
+	case loaded == nil:
+		item.Loading = true
+	case loaded.err != nil:
+		item.Warning = "cannot list security groups: " + loaded.err.Error()
+	default:
+		var missing []string
+		for _, id := range strings.Split(ids, ",") {
+			id = strings.TrimSpace(id)
+			if id != "" && !slices.ContainsFunc(loaded.groups, func(g aws.SecurityGroup) bool { return g.ID == id }) {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			item.Warning = strings.Join(missing, ", ") + " not found in the stack's VPC " + loaded.vpcID
+		}
+	}
+	return item
+}
@@ -0,0 +1,56 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the config file's deletion protection: a change that
+// would have AWS Backup delete a protected recovery point sooner is refused
+// before it is sent, whatever the operator confirmed.
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+)
+
+// schedulesDeletion reports whether applying l to rp would have AWS Backup
+// delete it, or delete it sooner than it does now.
+func schedulesDeletion(rp aws.RecoveryPoint, l aws.Lifecycle) bool {
+	deleteAt := l.DeleteAt(rp.CreationDate)
+	return !deleteAt.IsZero() && (rp.DeleteAt.IsZero() || deleteAt.Before(rp.DeleteAt))
+}
+
+// checkProtected returns an error if a rule of cfg protects rp from
+// deletion. details are rp's known details, or nil; when a rule matches on
+// tags and they are unknown, they are looked up, and a failed lookup
+// refuses the deletion rather than risk it.
+func checkProtected(ctx context.Context, client *aws.BackupClient, cfg *config.Config, vaultName string, rp aws.RecoveryPoint, details *aws.RecoveryPointDetails) error {
+	if cfg == nil || len(cfg.Protect) == 0 {
+		return nil
+	}
+	if details == nil && cfg.ProtectsByTag() {
+		var err error
+		if details, err = client.DescribeRecoveryPointDetails(ctx, vaultName, rp.RecoveryPointARN); err != nil {
+			return fmt.Errorf("cannot check the backup's tags against deletion protection, so it is kept: %w", err)
+		}
+	}
+	if rule := cfg.Protection(protectedPoint(rp, details), time.Now()); rule != nil {
+		return fmt.Errorf("this backup is protected from deletion by config rule %s", rule)
+	}
+	return nil
+}
+
+// protectedPoint returns what protection rules are matched against for rp.
+func protectedPoint(rp aws.RecoveryPoint, details *aws.RecoveryPointDetails) config.ProtectedPoint {
+	p := config.ProtectedPoint{ARN: rp.RecoveryPointARN, ResourceType: rp.ResourceType, Created: rp.CreationDate}
+	if details != nil {
+		p.Tags = details.Tags
+	}
+	return p
+}
+
+// protectionRule returns the rule protecting the selected backup as far as
+// is known without looking up its tags, or nil.
+func (m *Model) protectionRule(rp aws.RecoveryPoint) *config.ProtectRule {
+	return m.config.Protection(protectedPoint(rp, m.enrich.details[rp.RecoveryPointARN]), time.Now())
+}
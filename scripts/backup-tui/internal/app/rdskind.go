@@ -0,0 +1,74 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the RDS resource kind: the live cluster's health in
+// the detail view, the cluster, subnet group, and security groups a restore
+// creates, and the confirmation keys that change them.
+package app
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// rdsKind restores an RDS backup as an Aurora cluster.
+var rdsKind = resourceKind{
+	lookup: func(m *Model, _ aws.RecoveryPoint) tea.Cmd { return m.fetchClusterHealth() },
+	prefetch: func(m *Model) []tea.Cmd {
+		return []tea.Cmd{m.loadSecurityGroups(), m.loadSubnetGroups()}
+	},
+	preflight: func(meta *aws.RestoreMetadata, p *prefetchState) []ui.PreflightItem {
+		return []ui.PreflightItem{
+			{Label: "Cluster", Value: meta.ClusterID},
+			subnetGroupItem(meta.SubnetGroup, p.subnetGroups),
+			securityGroupsItem(meta.SecurityGroups, p.securityGroups),
+		}
+	},
+	fields: func(meta *aws.RestoreMetadata) []restoreField {
+		return []restoreField{
+			{label: "Cluster", key: "DBClusterIdentifier", value: clusterValue(meta)},
+			{label: "Subnet", key: "DBSubnetGroupName", value: meta.SubnetGroup},
+			{label: "Security", key: "VpcSecurityGroupIds", value: meta.SecurityGroups},
+		}
+	},
+	help: map[string]fieldHelp{
+		"DBClusterIdentifier": {
+			what: "Name of the Aurora cluster the snapshot is restored to. AWS Backup always creates a new " +
+				"cluster with this identifier; the restore job fails if a cluster with this name already exists.",
+			ifChange: "A different name restores alongside production instead of replacing it, e.g. to verify " +
+				"the data before cutting over; press r on the confirmation screen to name it with a suffix. " +
+				"OpenEMR keeps using the old endpoint until the stack's database endpoint is updated.",
+		},
+		"DBSubnetGroupName": {
+			what: "DB subnet group (VPC and subnets) the restored cluster is placed in. Copied from the current " +
+				"cluster so the restore lands in the same network as the ECS tasks; press s on the confirmation " +
+				"screen to pick another group of the stack's VPC. Aurora needs subnets in at least two AZs.",
+			ifChange: "A subnet group in another VPC leaves the cluster unreachable from OpenEMR; public subnets " +
+				"can expose the database outside the VPC.",
+		},
+		"VpcSecurityGroupIds": {
+			what: "Comma-separated security groups attached to the restored cluster. They decide which clients " +
+				"can connect on the MySQL port. Copied from the current cluster; press g on the confirmation " +
+				"screen to pick groups of the stack's VPC instead, e.g. for an isolated or staging network.",
+			ifChange: "Omitting the application's group blocks OpenEMR from the database; adding broad groups " +
+				"can expose patient data to other workloads.",
+		},
+	},
+	keys: []confirmKey{
+		{key: "g", hint: "security groups", run: (*Model).openSGPicker},
+		{key: "s", hint: "subnet group", run: (*Model).openSubnetPicker},
+		{key: "d", hint: "diff live config", run: (*Model).toggleConfigDiff},
+		{key: "r", hint: "new cluster", run: func(m *Model) tea.Cmd { m.startClusterSuffix(); return nil }},
+		{key: "c", hint: "fast clone instead", run: func(m *Model) tea.Cmd { m.openCloneConfirm(); return nil }},
+	},
+}
+
+// clusterValue formats the cluster an RDS restore creates, noting when it
+// is a new one alongside the live cluster.
+func clusterValue(meta *aws.RestoreMetadata) string {
+	if live := meta.LiveClusterID(); meta.ClusterID != live {
+		return fmt.Sprintf("%s (new, alongside %s)", meta.ClusterID, live)
+	}
+	return meta.ClusterID
+}
@@ -0,0 +1,231 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements pluggable renderers: the backup list, the selected
+// backup's details, and the jobs view are drawn by a Renderer from plain
+// snapshots of the model (ListView, DetailView, JobsView), so alternate
+// frontends such as unstyled text for screen readers or a future read-only
+// web dashboard reuse the same application model.
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+// Renderer draws the model's main views. Each method is given a snapshot of
+// the model and returns the view's text, without the status bar and key
+// hints.
+type Renderer interface {
+	List(v ListView) string
+	Detail(v DetailView) string
+	Jobs(v JobsView) string
+}
+
+// ListView is a snapshot of the backup list.
+type ListView struct {
+	Stack    string
+	Vault    string
+	Region   string
+	Filter   string // Resource type filter, or "" for all
+	Sort     string
+	Backups  []ListItem
+	Selected int // Index in Backups of the selected backup
+}
+
+// ListItem is a backup in the list.
+type ListItem struct {
+	aws.RecoveryPoint
+	CreatedBy string // Backup plan, "on-demand", or "" until its details load
+	Marked    bool   // Marked for a legal hold
+}
+
+// DetailView is a snapshot of the selected backup's details.
+type DetailView struct {
+	Stack   string
+	Vault   string
+	Region  string
+	Backup  aws.RecoveryPoint
+	Details *aws.RecoveryPointDetails // Nil until loaded
+	// ProtectedUntil is when the vault lock allows the backup to be deleted
+	// (zero when it does not protect it).
+	ProtectedUntil time.Time
+	// Verification is the backup's last verified test restore (nil if it
+	// was never verified).
+	Verification *store.Verification
+}
+
+// JobsView is a snapshot of the jobs view.
+type JobsView struct {
+	Status
+	Selected int // Index in Jobs of the selected job
+}
+
+// ListView returns a snapshot of the backup list.
+func (m *Model) ListView() ListView {
+	v := ListView{
+		Stack:    m.stackName,
+		Vault:    m.vaultName,
+		Region:   m.region,
+		Filter:   strings.Join(m.resourceTypes, ", "),
+		Sort:     m.activeSort.String(),
+		Backups:  make([]ListItem, len(m.backups)),
+		Selected: m.selectedIdx,
+	}
+	if m.activeFilter != filterAll {
+		v.Filter = m.activeFilter.String()
+	}
+	for i, b := range m.backups {
+		v.Backups[i] = ListItem{RecoveryPoint: b, CreatedBy: m.backupCreator(b.RecoveryPointARN), Marked: m.marked[b.RecoveryPointARN]}
+	}
+	return v
+}
+
+// DetailView returns a snapshot of the selected backup's details.
+func (m *Model) DetailView() DetailView {
+	v := DetailView{Stack: m.stackName, Vault: m.vaultName, Region: m.region}
+	if m.selectedIdx < len(m.backups) {
+		v.Backup = m.backups[m.selectedIdx]
+		v.Details = m.enrich.details[v.Backup.RecoveryPointARN]
+		if m.vaultInfo != nil {
+			v.ProtectedUntil = m.vaultInfo.ProtectedUntil(v.Backup)
+		}
+		if verified, ok := m.verify.latest[v.Backup.RecoveryPointARN]; ok {
+			v.Verification = &verified
+		}
+	}
+	return v
+}
+
+// JobsView returns a snapshot of the jobs view.
+func (m *Model) JobsView() JobsView {
+	return JobsView{Status: m.Status(), Selected: m.jobsCursor}
+}
+
+// terminalRenderer is the default renderer: the styled views drawn by the
+// model's scrolling list and detail components, which hold the same data
+// as the snapshots it is given.
+type terminalRenderer struct {
+	m *Model
+}
+
+func (r terminalRenderer) List(ListView) string     { return r.m.renderList() }
+func (r terminalRenderer) Detail(DetailView) string { return r.m.renderDetail() }
+func (r terminalRenderer) Jobs(JobsView) string     { return r.m.renderJobs() }
+
+// viewRenderer returns the model's renderer, or the terminal renderer if
+// none was set.
+func (m *Model) viewRenderer() Renderer {
+	if m.renderer == nil {
+		return terminalRenderer{m}
+	}
+	return m.renderer
+}
+
+// plainRows is how many backups the plain renderer lists around the
+// selected one.
+const plainRows = 20
+
+// PlainRenderer draws the views as unstyled text, one fact per line, for
+// screen readers and terminals without color.
+type PlainRenderer struct{}
+
+// List renders the backup list, marking the selected backup with ">".
+func (PlainRenderer) List(v ListView) string {
+	lines := []string{plainHeader(v.Stack, v.Vault, v.Region)}
+	if v.Filter != "" {
+		lines = append(lines, "Filter: "+v.Filter)
+	}
+	lines = append(lines, "Sort: "+v.Sort, fmt.Sprintf("%d backup(s)", len(v.Backups)), "")
+
+	start := max(0, min(v.Selected-plainRows/2, len(v.Backups)-plainRows))
+	for i := start; i < len(v.Backups) && i < start+plainRows; i++ {
+		b := v.Backups[i]
+		cursor := " "
+		if i == v.Selected {
+			cursor = ">"
+		}
+		line := fmt.Sprintf("%s %s %s, %s, %s", cursor, b.ResourceType, b.ResourceID,
+			b.CreationDate.Format("2006-01-02 15:04"), formatBytes(b.BackupSizeInBytes))
+		if b.CreatedBy != "" {
+			line += ", " + b.CreatedBy
+		}
+		if b.Marked {
+			line += ", marked"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Detail renders the selected backup's details.
+func (PlainRenderer) Detail(v DetailView) string {
+	b := v.Backup
+	lines := []string{
+		plainHeader(v.Stack, v.Vault, v.Region),
+		"",
+		"Resource type: " + b.ResourceType,
+		"Resource: " + b.ResourceID,
+		"Created: " + b.CreationDate.Format("2006-01-02 15:04:05 MST"),
+		"Status: " + b.Status,
+		"Size: " + formatBytes(b.BackupSizeInBytes),
+		"Recovery point: " + b.RecoveryPointARN,
+	}
+	if !b.DeleteAt.IsZero() {
+		lines = append(lines, "Deleted: "+b.DeleteAt.Format("2006-01-02"))
+	}
+	if !v.ProtectedUntil.IsZero() {
+		lines = append(lines, "Protected by the vault lock until: "+v.ProtectedUntil.Format("2006-01-02"))
+	}
+	if vf := v.Verification; vf != nil {
+		lines = append(lines, "Verified: "+vf.Result+" "+vf.VerifiedAt.Format("2006-01-02 15:04"))
+		for _, c := range vf.Checks {
+			if c.Result == store.VerifyFailed {
+				lines = append(lines, "Failed check: "+c.Name+": "+c.Detail)
+			}
+		}
+	}
+	if d := v.Details; d != nil {
+		createdBy := d.CreatedBy
+		if d.OnDemand() {
+			createdBy = "on-demand"
+		}
+		lines = append(lines, "Created by: "+createdBy, "Storage class: "+d.StorageClass)
+		if d.Encrypted {
+			lines = append(lines, "Encryption key: "+d.EncryptionKeyARN)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Jobs renders the session's jobs, marking the selected job with ">".
+func (PlainRenderer) Jobs(v JobsView) string {
+	lines := []string{plainHeader(v.Stack, v.Vault, v.Region), "", fmt.Sprintf("%d job(s), %d running", len(v.Jobs), v.Running)}
+	for i, j := range v.Jobs {
+		cursor := " "
+		if i == v.Selected {
+			cursor = ">"
+		}
+		line := fmt.Sprintf("%s #%d %s %s %s, %s", cursor, j.Seq, j.Kind, j.ResourceType, j.ResourceID, j.State)
+		switch {
+		case j.State == jobQueued.String():
+			line += fmt.Sprintf(", starts when #%d completes", j.After)
+		case j.Note != "":
+			line += ", " + j.Note
+		case j.PercentDone != "":
+			line += ", " + j.PercentDone + "% done"
+		}
+		if j.Leftover != "" {
+			line += ", left behind " + j.Leftover
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// plainHeader is the first line of every plain view.
+func plainHeader(stack, vault, region string) string {
+	return fmt.Sprintf("OpenEMR Backup Manager: stack %s, vault %s, region %s", stack, vault, region)
+}
@@ -0,0 +1,110 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the resource kind registry: each resource type with
+// views of its own contributes the live lookups of its detail view, its
+// restore prefetch and preflight, its restore parameter fields and their
+// help, and its keys on the restore confirmation screen, so supporting a new
+// type does not mean branching on it throughout the model.
+package app
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// resourceKind is what a resource type contributes to the TUI. Every field
+// is optional; types without a kind are listed and restored with AWS
+// Backup's defaults.
+type resourceKind struct {
+	// lookup returns a command that looks up the live resource a restore
+	// of rp targets, for the detail view.
+	lookup func(m *Model, rp aws.RecoveryPoint) tea.Cmd
+
+	// prefetch returns the type's own restore lookups, started with the
+	// detail view alongside the parameters, role, and KMS keys.
+	prefetch func(m *Model) []tea.Cmd
+
+	// preflight describes the prefetched restore parameters of meta.
+	preflight func(meta *aws.RestoreMetadata, p *prefetchState) []ui.PreflightItem
+
+	// fields returns the restore parameters of meta for the confirmation
+	// screen, in display order.
+	fields func(meta *aws.RestoreMetadata) []restoreField
+
+	// help explains the restore metadata keys of fields.
+	help map[string]fieldHelp
+
+	// keys are the type's own keys on the restore confirmation screen.
+	keys []confirmKey
+}
+
+// confirmKey is a key a resource kind handles on the restore confirmation
+// screen.
+type confirmKey struct {
+	key  string // Lowercase key; its uppercase form works too
+	hint string // Key hint, e.g. "subnet group"
+	run  func(m *Model) tea.Cmd
+}
+
+// resourceKinds are the resource types with views of their own, keyed as
+// AWS Backup spells them.
+var resourceKinds = map[string]resourceKind{
+	"RDS": rdsKind,
+	"EFS": efsKind,
+}
+
+// kindOf returns what resourceType contributes to the TUI.
+func kindOf(resourceType string) resourceKind {
+	return resourceKinds[resourceType]
+}
+
+// selectedKind returns the kind of the selected backup.
+func (m *Model) selectedKind() resourceKind {
+	if m.selectedIdx >= len(m.backups) {
+		return resourceKind{}
+	}
+	return kindOf(m.backups[m.selectedIdx].ResourceType)
+}
+
+// runConfirmKey runs the handler of key on the confirmation screen. It
+// reports whether any kind handles key: a key of another kind than the
+// selected backup's runs that kind's handler, which tells the operator why
+// it does not apply.
+func (m *Model) runConfirmKey(key string) (tea.Cmd, bool) {
+	kinds := []resourceKind{m.selectedKind()}
+	for _, t := range slices.Sorted(maps.Keys(resourceKinds)) {
+		kinds = append(kinds, resourceKinds[t])
+	}
+	for _, kind := range kinds {
+		for _, k := range kind.keys {
+			if key == k.key || key == strings.ToUpper(k.key) {
+				return k.run(m), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// confirmKeyHints renders the selected backup's confirmation keys.
+func (m *Model) confirmKeyHints(keyStyle lipgloss.Style) string {
+	var hints string
+	for _, k := range m.selectedKind().keys {
+		hints += fmt.Sprintf("  %s %s", keyStyle.Render(k.key), k.hint)
+	}
+	return hints
+}
+
+// fieldHelpFor returns the help of a restore metadata key of resourceType.
+func fieldHelpFor(resourceType, key string) (fieldHelp, bool) {
+	if h, ok := kindOf(resourceType).help[key]; ok {
+		return h, true
+	}
+	h, ok := restoreFieldHelp[key]
+	return h, ok
+}
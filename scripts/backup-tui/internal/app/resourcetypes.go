@@ -0,0 +1,41 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the dynamic resource type filter: "f" cycles through
+// the resource types the vault actually holds that AWS Backup supports in
+// the region, so stacks extended beyond RDS and EFS can be filtered too.
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// supportedTypesMsg is sent when the region's supported resource types have
+// been loaded.
+type supportedTypesMsg struct {
+	types []string
+	err   error
+}
+
+// loadSupportedTypes returns a command that asks AWS Backup which resource
+// types it supports in the region.
+func (m *Model) loadSupportedTypes() tea.Cmd {
+	client := m.backupClient
+	return func() tea.Msg {
+		types, err := client.SupportedResourceTypes(m.ctx)
+		return supportedTypesMsg{types: types, err: err}
+	}
+}
+
+// handleSupportedTypes records the supported resource types. Failures are
+// not fatal: the filter then offers every type found in the vault.
+func (m *Model) handleSupportedTypes(msg supportedTypesMsg) {
+	if msg.err != nil {
+		return
+	}
+	m.supportedTypes = msg.types
+}
+
+// filterTypes returns the resource types the in-app filter cycles through.
+func (m *Model) filterTypes() []string {
+	return aws.VaultResourceTypes(m.allBackups, m.supportedTypes)
+}
@@ -0,0 +1,108 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements selecting backups by moment: "@" in the list takes a
+// moment of an incident timeline, e.g. 2026-01-15T03:00Z, and selects the
+// restorable backup closest to but not after it, listing the pick of every
+// resource in the status bar along with whether a point-in-time restore of
+// the live cluster reaches the moment exactly.
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// startRestoreAt opens the moment prompt.
+func (m *Model) startRestoreAt() {
+	m.restoreAtInput = ""
+	m.clearStatus()
+	m.state = stateRestoreAt
+}
+
+// updateRestoreAt handles key presses at the moment prompt.
+func (m *Model) updateRestoreAt(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.state = stateList
+	case "enter":
+		at, err := aws.ParseMoment(strings.TrimSpace(m.restoreAtInput))
+		if err != nil {
+			m.notify(SeverityWarn, err.Error())
+			return m, nil
+		}
+		m.state = stateList
+		m.selectRestorableAt(at)
+	case "backspace":
+		if r := []rune(m.restoreAtInput); len(r) > 0 {
+			m.restoreAtInput = string(r[:len(r)-1])
+		}
+	default:
+		if msg.Text != "" {
+			m.restoreAtInput += msg.Text
+		}
+	}
+	return m, nil
+}
+
+// selectRestorableAt moves the list cursor to the first resource's
+// restorable backup closest to but not after at that the list shows, and
+// reports every resource's pick.
+func (m *Model) selectRestorableAt(at time.Time) {
+	picks := aws.RestorableAt(m.allBackups, m.recentRestores, time.Now(), at)
+	if len(picks) == 0 {
+		m.notify(SeverityWarn, "No backups in the vault")
+		return
+	}
+
+	selected := false
+	parts := make([]string, 0, len(picks))
+	for _, p := range picks {
+		if !p.Found() {
+			parts = append(parts, fmt.Sprintf("%s %s none", p.ResourceType, p.ResourceID))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s (%s before)", p.ResourceType, p.ResourceID,
+			p.CreatedAt.UTC().Format("01-02 15:04"), strings.TrimSuffix(at.Sub(p.CreatedAt).Round(time.Minute).String(), "0s")))
+		if !selected {
+			selected = m.selectARN(p.RecoveryPointARN)
+		}
+	}
+	message := "At " + at.UTC().Format("2006-01-02 15:04 MST") + ": " + strings.Join(parts, ", ")
+	if m.cluster != nil && m.cluster.CoversPITR(at) {
+		message += "; point-in-time restore of " + m.cluster.ClusterID + " also reaches it"
+	}
+	if !selected {
+		m.notify(SeverityWarn, message+"; no restorable backup in the list (hidden by the filter?)")
+		return
+	}
+	m.inform(message)
+}
+
+// renderRestoreAt renders the moment prompt.
+func (m *Model) renderRestoreAt() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2)
+	labelStyle := lipgloss.NewStyle().Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+
+	lines := []string{
+		labelStyle.Render("Select backups by moment"),
+		"",
+		"Restore to the state at (closest backup not after it):",
+		"> " + m.restoreAtInput + "█",
+		"",
+		dimStyle.Render("e.g. 2026-01-15T03:00Z, 2026-01-15T03:00-05:00, or 2026-01-15 03:00 (UTC)"),
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
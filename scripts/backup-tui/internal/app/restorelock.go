@@ -0,0 +1,184 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the advisory restore lock: while this session has
+// restores of the stack running, it holds a lock on the stack in the local
+// lock file and, with sharedRestoreLock in the config, as a tag on the
+// stack's vault. Sessions that find another's lock when the backups load
+// or a restore is confirmed warn that a restore is already in progress, so
+// two on-call engineers do not restore the same stack at once. Nothing is
+// refused: the lock only informs. The lock itself is store.StackLock, which
+// the restore subcommands and the JSON API take too.
+package app
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+// restoreLockState is this session's restore lock, and the lock of another
+// session last found on the stack.
+type restoreLockState struct {
+	path   string             // Local lock file ("" disables it)
+	held   *store.RestoreLock // Lock this session holds (nil when none)
+	other  *store.RestoreLock // Another session's live lock (nil when none)
+	warned *store.RestoreLock // Other lock last warned about, so each is warned once
+}
+
+// restoreLockMsg is sent when the stack's locks have been read.
+type restoreLockMsg struct {
+	lock *store.RestoreLock // Another session's live lock (nil when none)
+	err  error
+}
+
+// restoreLockWrittenMsg is sent when the session's lock has been written
+// or released.
+type restoreLockWrittenMsg struct {
+	released bool
+	err      error
+}
+
+// restoreLocking reports whether restores of the stack are locked: there
+// is a stack, and a local lock file or the shared lock.
+func (m *Model) restoreLocking() bool {
+	return m.stackName != "" && (m.restoreLock.path != "" || m.sharedRestoreLock())
+}
+
+// sharedRestoreLock reports whether the lock is also kept as a vault tag.
+func (m *Model) sharedRestoreLock() bool {
+	return m.config != nil && m.config.SharedRestoreLock && m.vaultName != ""
+}
+
+// stackLock returns the stack's restore lock.
+func (m *Model) stackLock() store.StackLock {
+	k := store.StackLock{Path: m.restoreLock.path, Env: store.LockEnv(m.region, m.stackName), Vault: m.vaultName, Stack: m.stackName}
+	if m.sharedRestoreLock() {
+		k.Shared = m.backupClient
+	}
+	return k
+}
+
+// checkRestoreLock returns a command reading the stack's locks for another
+// session's, or nil if restores are not locked.
+func (m *Model) checkRestoreLock() tea.Cmd {
+	if !m.restoreLocking() {
+		return nil
+	}
+	k, self := m.stackLock(), m.lockSession()
+	return func() tea.Msg {
+		lock, err := k.Other(m.ctx, self)
+		return restoreLockMsg{lock: lock, err: err}
+	}
+}
+
+// handleRestoreLock records another session's lock, warning about it the
+// first time it is found.
+func (m *Model) handleRestoreLock(msg restoreLockMsg) {
+	if msg.err != nil {
+		m.logError("Restore lock of "+m.stackName+" not checked", msg.err)
+	}
+	m.restoreLock.other = msg.lock
+	if msg.lock == nil {
+		return
+	}
+	if w := m.restoreLock.warned; w != nil && w.SameSession(*msg.lock) && w.Since.Equal(msg.lock.Since) {
+		return
+	}
+	m.restoreLock.warned = msg.lock
+	m.notify(SeverityWarn, fmt.Sprintf("Restore of %s in progress in another session: %s (advisory lock)",
+		m.stackName, msg.lock.String()))
+}
+
+// lockSession returns a lock identifying this session, without times.
+func (m *Model) lockSession() store.RestoreLock {
+	var holder string
+	if m.backupClient != nil {
+		holder = m.backupClient.CallerARN()
+	}
+	return store.SessionLock(holder)
+}
+
+// hostName returns the machine's host name, or "unknown".
+func hostName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return host
+}
+
+// syncRestoreLock takes the lock when the session's first restore of the
+// stack has started, refreshes it as the restores are polled, and releases
+// it once none is running. It returns the command writing the change, or
+// nil if there is none.
+func (m *Model) syncRestoreLock() tea.Cmd {
+	if !m.restoreLocking() {
+		return nil
+	}
+	restoring := slices.ContainsFunc(m.runningJobs(), func(j *restoreJob) bool {
+		return j.kind == aws.JobKindRestore && !j.imported && j.jobID != ""
+	})
+	held := m.restoreLock.held
+	now := time.Now()
+	switch {
+	case restoring && held == nil:
+		l := m.lockSession()
+		l.Since, l.Updated = now, now
+		m.restoreLock.held = &l
+		return m.writeRestoreLock(l)
+	case restoring && now.Sub(held.Updated) > store.LockTTL/3:
+		held.Updated = now
+		return m.writeRestoreLock(*held)
+	case !restoring && held != nil:
+		m.restoreLock.held = nil
+		return m.releaseRestoreLock(*held)
+	}
+	return nil
+}
+
+// writeRestoreLock returns a command writing l to the local lock file and
+// the shared lock.
+func (m *Model) writeRestoreLock(l store.RestoreLock) tea.Cmd {
+	k := m.stackLock()
+	return func() tea.Msg {
+		return restoreLockWrittenMsg{err: k.Write(m.ctx, l)}
+	}
+}
+
+// releaseRestoreLock returns a command removing l from the local lock file
+// and the shared lock. A lock another session has since taken is kept.
+func (m *Model) releaseRestoreLock(l store.RestoreLock) tea.Cmd {
+	k := m.stackLock()
+	return func() tea.Msg {
+		return restoreLockWrittenMsg{released: true, err: k.Release(m.ctx, l)}
+	}
+}
+
+// handleRestoreLockWritten logs a lock that could not be written. Other
+// sessions then do not see the restore in progress.
+func (m *Model) handleRestoreLockWritten(msg restoreLockWrittenMsg) {
+	switch {
+	case msg.err == nil:
+	case msg.released:
+		m.logError("Restore lock of "+m.stackName+" not released; it expires on its own", msg.err)
+	default:
+		m.logError("Restore lock of "+m.stackName+" not written; other sessions will not see this restore", msg.err)
+	}
+}
+
+// restoreLockLines returns the confirm screen's warning that another
+// session is restoring the stack, or nil if none is known to be.
+func (m *Model) restoreLockLines(warningStyle, style lipgloss.Style) []string {
+	l := m.restoreLock.other
+	if l == nil || l.Stale(time.Now()) {
+		return nil
+	}
+	return []string{"", warningStyle.Render("⚠  Restore in progress in another session"),
+		style.Render("  " + l.String()),
+		style.Render("  Coordinate with them before restoring " + m.stackName + " again")}
+}
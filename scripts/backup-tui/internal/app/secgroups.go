@@ -0,0 +1,191 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the restore security group picker: from the
+// confirmation screen of an RDS restore, "g" lists the security groups of
+// the stack's VPC so the restored cluster can be attached to the groups of
+// an isolated or staging network instead of the live cluster's, without
+// looking up sg-IDs.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// sgPicker is the state of the security group picker.
+type sgPicker struct {
+	vpcID    string
+	groups   []aws.SecurityGroup
+	err      error // Error from the last load
+	loading  bool
+	cursor   int
+	selected map[string]bool // Group IDs that will be attached
+}
+
+// securityGroupsMsg is sent when the stack's VPC and its security groups
+// have been loaded.
+type securityGroupsMsg struct {
+	vpcID  string
+	groups []aws.SecurityGroup
+	err    error
+}
+
+// openSGPicker opens the security group picker for the pending RDS restore,
+// with the groups it currently uses selected, and starts loading the VPC's
+// groups unless they were prefetched with the detail view.
+func (m *Model) openSGPicker() tea.Cmd {
+	if m.selectedIdx >= len(m.backups) {
+		return nil
+	}
+	if m.backups[m.selectedIdx].ResourceType != "RDS" {
+		m.notify(SeverityWarn, "Security groups can only be chosen for RDS restores")
+		return nil
+	}
+	m.sgPicker = sgPicker{loading: true, selected: map[string]bool{}}
+	if m.restoreMetadata != nil {
+		for _, id := range strings.Split(m.restoreMetadata.SecurityGroups, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				m.sgPicker.selected[id] = true
+			}
+		}
+	}
+	m.state = stateSGPicker
+	if p := m.prefetched(); p != nil && p.securityGroups != nil && p.securityGroups.err == nil {
+		m.handleSecurityGroups(*p.securityGroups)
+		return nil
+	}
+	return m.loadSecurityGroups()
+}
+
+// loadSecurityGroups returns a command that lists the security groups of
+// the stack's VPC.
+func (m *Model) loadSecurityGroups() tea.Cmd {
+	client, stack := m.backupClient, m.stackName
+	return func() tea.Msg {
+		vpcID, err := client.StackVPC(m.ctx, stack)
+		if err != nil {
+			return securityGroupsMsg{err: err}
+		}
+		groups, err := client.ListSecurityGroups(m.ctx, vpcID)
+		return securityGroupsMsg{vpcID: vpcID, groups: groups, err: err}
+	}
+}
+
+// handleSecurityGroups records the loaded security groups.
+func (m *Model) handleSecurityGroups(msg securityGroupsMsg) {
+	p := &m.sgPicker
+	p.loading = false
+	p.vpcID, p.groups, p.err = msg.vpcID, msg.groups, msg.err
+	p.cursor = 0
+}
+
+// updateSGPicker handles key presses in the security group picker.
+func (m *Model) updateSGPicker(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	p := &m.sgPicker
+	switch msg.String() {
+	case "esc", "q", "backspace":
+		m.state = stateConfirm
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.groups)-1 {
+			p.cursor++
+		}
+	case "space":
+		if p.cursor < len(p.groups) {
+			id := p.groups[p.cursor].ID
+			p.selected[id] = !p.selected[id]
+		}
+	case "r":
+		m.setRestoreSecurityGroups(nil)
+		m.state = stateConfirm
+	case "enter":
+		if p.loading || len(p.groups) == 0 {
+			return m, nil
+		}
+		var ids []string
+		for _, g := range p.groups {
+			if p.selected[g.ID] {
+				ids = append(ids, g.ID)
+			}
+		}
+		if len(ids) == 0 {
+			m.notify(SeverityWarn, "Select at least one security group (space), or r to use the live cluster's")
+			return m, nil
+		}
+		m.setRestoreSecurityGroups(ids)
+		m.state = stateConfirm
+	}
+	return m, nil
+}
+
+// setRestoreSecurityGroups sets the security groups of the pending restore;
+// nil restores with the live cluster's groups.
+func (m *Model) setRestoreSecurityGroups(ids []string) {
+	m.restoreOpts.SecurityGroupIDs = ids
+	m.inform("Restore uses the live cluster's security groups")
+	if len(ids) > 0 {
+		m.inform("Restore will use security groups " + strings.Join(ids, ", "))
+	}
+	if m.restoreMetadata != nil {
+		m.restoreMetadata.ApplyOptions(m.restoreOpts)
+	}
+}
+
+// renderSGPicker renders the security group picker.
+func (m *Model) renderSGPicker() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+
+	p := m.sgPicker
+	lines := []string{titleStyle.Render("Restore Security Groups"), ""}
+	switch {
+	case p.loading:
+		lines = append(lines, dimStyle.Render("Loading the stack's VPC security groups..."))
+	case p.err != nil:
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("Could not list security groups: %v", p.err)))
+	case len(p.groups) == 0:
+		lines = append(lines, dimStyle.Render("No security groups in "+p.vpcID))
+	default:
+		lines = append(lines, dimStyle.Render("VPC "+p.vpcID), "")
+	}
+
+	for i, g := range p.groups {
+		box := "[ ]"
+		if p.selected[g.ID] {
+			box = "[x]"
+		}
+		entry := fmt.Sprintf("%s %s  %s", box, g.Name, g.ID)
+		if g.Description != "" {
+			entry += "  " + dimStyle.Render(g.Description)
+		}
+		if i == p.cursor {
+			lines = append(lines, focusStyle.Render("▸ "+entry))
+		} else {
+			lines = append(lines, infoStyle.Render("  "+entry))
+		}
+	}
+
+	lines = append(lines, "",
+		dimStyle.Render("OpenEMR can only reach the restored cluster through a group that allows its tasks on the MySQL port."))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,184 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the backup selections view: "P" shows the selections
+// of the plan that backs up to the vault and when its rules run, with the
+// resource ARNs and tag conditions each uses and its IAM role, and for each
+// of the stack's resources which selection includes it or why none does.
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// selectionsView is the state of the backup selections view.
+type selectionsView struct {
+	plan       *aws.PlanRole
+	selections []aws.BackupSelection
+	resources  []aws.ProtectedResource
+	err        error // Error from the last load
+	loading    bool
+}
+
+// selectionsMsg is sent when the plan's selections and the stack's
+// resources have been loaded.
+type selectionsMsg struct {
+	vault      string
+	plan       *aws.PlanRole
+	selections []aws.BackupSelection
+	resources  []aws.ProtectedResource
+	err        error
+}
+
+// openSelections opens the backup selections view and loads it.
+func (m *Model) openSelections() tea.Cmd {
+	m.state = stateSelections
+	return m.loadSelections()
+}
+
+// loadSelections returns a command that reads the selections of the
+// vault's backup plan and the stack's resources.
+func (m *Model) loadSelections() tea.Cmd {
+	if m.selections.loading {
+		return nil
+	}
+	m.selections.loading = true
+	client, stackName, vaultName := m.backupClient, m.stackName, m.vaultName
+	return func() tea.Msg {
+		msg := selectionsMsg{vault: vaultName}
+		msg.plan, msg.selections, msg.err = client.PlanSelections(m.ctx, vaultName)
+		if msg.err == nil {
+			msg.resources, msg.err = client.StackResources(m.ctx, stackName)
+		}
+		return msg
+	}
+}
+
+// handleSelections records the loaded selections.
+func (m *Model) handleSelections(msg selectionsMsg) {
+	m.selections.loading = false
+	if msg.vault != m.vaultName {
+		return // The vault was switched while loading
+	}
+	v := &m.selections
+	v.plan, v.selections, v.resources, v.err = msg.plan, msg.selections, msg.resources, msg.err
+}
+
+// selectionVerdict explains whether the plan backs up the resource with the
+// given ARN: which selections include it, or why none does.
+func selectionVerdict(arn string, selections []aws.BackupSelection) (aws.SelectionMatch, string) {
+	var included, excluded, byTags []string
+	for _, s := range selections {
+		switch s.Match(arn) {
+		case aws.SelectionIncluded:
+			included = append(included, s.Name)
+		case aws.SelectionExcluded:
+			excluded = append(excluded, s.Name)
+		case aws.SelectionByTags:
+			byTags = append(byTags, s.Name)
+		}
+	}
+	switch {
+	case len(included) > 0:
+		return aws.SelectionIncluded, "included by " + strings.Join(included, ", ")
+	case len(byTags) > 0:
+		return aws.SelectionByTags, "included only if its tags match " + strings.Join(byTags, ", ")
+	case len(excluded) > 0:
+		return aws.SelectionExcluded, "not backed up: excluded by " + strings.Join(excluded, ", ") + " and listed by no other selection"
+	}
+	return aws.SelectionNoMatch, "not backed up: no selection lists its ARN or selects by tags"
+}
+
+// renderSelections renders the backup selections view.
+func (m *Model) renderSelections() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	sectionStyle := lipgloss.NewStyle().Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("248")})
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+	failStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+
+	v := m.selections
+	lines := []string{titleStyle.Render("Backup Selections"), ""}
+	switch {
+	case v.loading && v.plan == nil:
+		lines = append(lines, dimStyle.Render("Loading the vault's backup plan and selections..."))
+	case v.err != nil:
+		lines = append(lines, failStyle.Render(fmt.Sprintf("Could not read backup selections: %v", v.err)))
+	case v.plan == nil:
+	case v.plan.Fallback:
+		lines = append(lines, warnStyle.Render("No backup plan targets vault "+m.vaultName+"; nothing backs up to it on a schedule."))
+	default:
+		via := "backs up to"
+		if v.plan.ViaCopy {
+			via = "copies to"
+		}
+		lines = append(lines, infoStyle.Render(fmt.Sprintf("Plan %s (%s) %s %s", v.plan.PlanName, v.plan.PlanID, via, m.vaultName)))
+		now := time.Now()
+		for _, sch := range v.plan.Schedules {
+			if sch.Expression == "" {
+				continue // A rule only run on demand
+			}
+			lines = append(lines, infoStyle.Render(fmt.Sprintf("  Schedule: %s  (rule %s, %s)", sch.Describe(now, time.Local), sch.RuleName, sch.Expression)))
+		}
+
+		lines = append(lines, "", sectionStyle.Render("Stack resources"))
+		if len(v.resources) == 0 {
+			lines = append(lines, dimStyle.Render("  The stack has no RDS clusters or EFS file systems."))
+		}
+		for _, r := range v.resources {
+			match, why := selectionVerdict(r.ARN, v.selections)
+			line := fmt.Sprintf("%-4s %s  %s", r.Type, arnName(r.ARN), why)
+			switch match {
+			case aws.SelectionIncluded:
+				lines = append(lines, okStyle.Render("  ✓ "+line))
+			case aws.SelectionByTags:
+				lines = append(lines, warnStyle.Render("  ? "+line))
+			default:
+				lines = append(lines, failStyle.Render("  ✗ "+line))
+			}
+		}
+
+		if len(v.selections) == 0 {
+			lines = append(lines, "", dimStyle.Render("The plan has no selections, so it backs up nothing."))
+		}
+		for _, s := range v.selections {
+			lines = append(lines, "", sectionStyle.Render("Selection "+s.Name))
+			lines = append(lines, infoStyle.Render("  IAM role:  "+s.RoleARN))
+			for _, r := range s.Resources {
+				lines = append(lines, infoStyle.Render("  Includes:  "+r))
+			}
+			for _, r := range s.NotResources {
+				lines = append(lines, infoStyle.Render("  Excludes:  "+r))
+			}
+			for _, t := range s.Tags {
+				lines = append(lines, infoStyle.Render("  Tag (any): "+t.String()))
+			}
+			for _, c := range s.Conditions {
+				lines = append(lines, infoStyle.Render("  Tag (all): "+c.String()))
+			}
+		}
+	}
+
+	lines = append(lines, "",
+		dimStyle.Render("Resource tags are not read here: check a resource marked ? against its selection's tags."))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
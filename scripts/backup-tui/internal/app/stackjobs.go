@@ -0,0 +1,91 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the account-wide jobs of the stack: backup and
+// restore jobs of the stack's resources started outside this session (by
+// backup plans, the console, or the CLI) are listed in the jobs view below
+// the tracked ones, and enter on one tracks it like an imported job.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// stackJobsWindow is how far back the jobs view lists jobs started
+// elsewhere.
+const stackJobsWindow = 7 * 24 * time.Hour
+
+// stackJobsView is the state of the jobs started outside this session.
+type stackJobsView struct {
+	jobs    []aws.JobRecord // Newest first, including jobs tracked in m.jobs
+	err     error           // Error from the last load
+	loading bool
+}
+
+// stackJobsMsg is sent when the stack's jobs have been listed.
+type stackJobsMsg struct {
+	stack string
+	jobs  []aws.JobRecord
+	err   error
+}
+
+// loadStackJobs returns a command that lists the backup and restore jobs of
+// the stack's resources in the account and region.
+func (m *Model) loadStackJobs() tea.Cmd {
+	if m.stackJobs.loading {
+		return nil
+	}
+	m.stackJobs.loading = true
+	client, stackName := m.backupClient, m.stackName
+	return func() tea.Msg {
+		jobs, err := client.ListStackJobs(m.ctx, stackName, time.Now().Add(-stackJobsWindow))
+		return stackJobsMsg{stack: stackName, jobs: jobs, err: err}
+	}
+}
+
+// handleStackJobs records the listed jobs. A failure is shown in the jobs
+// view; the tracked jobs are unaffected.
+func (m *Model) handleStackJobs(msg stackJobsMsg) {
+	m.stackJobs.loading = false
+	if msg.stack != m.stackName {
+		return
+	}
+	m.stackJobs.err = msg.err
+	if msg.err == nil {
+		m.stackJobs.jobs = msg.jobs
+	}
+	if total := len(m.jobs) + len(m.otherJobs()); m.jobsCursor >= total {
+		m.jobsCursor = max(total-1, 0)
+	}
+}
+
+// otherJobs returns the stack's jobs that are not tracked in the jobs view,
+// newest first.
+func (m *Model) otherJobs() []aws.JobRecord {
+	var other []aws.JobRecord
+	for _, j := range m.stackJobs.jobs {
+		if m.jobByID(j.JobID) == nil {
+			other = append(other, j)
+		}
+	}
+	return other
+}
+
+// trackOtherJob looks up a job started elsewhere so it is tracked, and
+// polled while running, like an imported job.
+func (m *Model) trackOtherJob(j aws.JobRecord) tea.Cmd {
+	m.inform(fmt.Sprintf("Looking up job %s...", j.JobID))
+	return m.lookupJob(j.JobID)
+}
+
+// jobResource returns the ARN of the stack resource a job is for: the
+// resource backed up, or the resource a restored recovery point was taken
+// from.
+func jobResource(j aws.JobRecord) string {
+	if j.Kind == aws.JobKindRestore && j.SourceResourceARN != "" {
+		return j.SourceResourceARN
+	}
+	return j.ResourceARN
+}
@@ -0,0 +1,110 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the stack selector: launched without -stack in a
+// region with several OpenEMR stacks, e.g. dev, staging, and prod in one
+// account, the TUI lists them to choose from instead of failing, and loads
+// the chosen stack's vault and view state.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// stackPicker is the state of the stack selector.
+type stackPicker struct {
+	stacks []aws.StackSummary
+	cursor int
+}
+
+// updateStackPicker handles key presses in the stack selector. There is no
+// view behind it, so esc and q quit.
+func (m *Model) updateStackPicker(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	p := &m.stackPicker
+	switch msg.String() {
+	case "esc", "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		p.cursor = max(p.cursor-1, 0)
+	case "down", "j":
+		p.cursor = min(p.cursor+1, len(p.stacks)-1)
+	case "enter":
+		return m, m.chooseStack(p.stacks[p.cursor].Name)
+	}
+	return m, nil
+}
+
+// chooseStack opens the stack name: its saved view state is applied, as
+// NewModel does for a stack given with -stack, and its vault and backups
+// start loading. A view or backup named with -open, -resource, or -arn
+// still takes precedence over the saved tab.
+func (m *Model) chooseStack(name string) tea.Cmd {
+	m.stackName = name
+	opened := m.pendingTab
+	m.loadView()
+	if opened != "" || m.startTarget != (startTarget{}) {
+		m.pendingTab = opened
+	}
+	m.applyColumns()
+	m.state = stateLoading
+	m.inform("Opening stack " + name)
+	return m.startLoading()
+}
+
+// renderStackPicker renders the stack selector.
+func (m *Model) renderStackPicker() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2)
+	labelStyle := lipgloss.NewStyle().Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	selectedStyle := lipgloss.NewStyle().Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")})
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+
+	p := m.stackPicker
+	lines := []string{
+		labelStyle.Render(fmt.Sprintf("Choose a stack: %d OpenEMR stacks in %s", len(p.stacks), m.region)),
+		"",
+	}
+	nameWidth, statusWidth := 0, 0
+	for _, st := range p.stacks {
+		nameWidth = max(nameWidth, len(st.Name))
+		statusWidth = max(statusWidth, len(st.Status))
+	}
+	for i, st := range p.stacks {
+		status := fmt.Sprintf("%-*s", statusWidth, st.Status)
+		if st.Status == "UPDATE_ROLLBACK_COMPLETE" && i != p.cursor {
+			status = warnStyle.Render(status) // Its last update failed
+		}
+		line := fmt.Sprintf("%-*s  %s  created %s", nameWidth, st.Name, status, stackTime(st.Created))
+		if !st.Updated.IsZero() {
+			line += ", updated " + stackTime(st.Updated)
+		}
+		if i == p.cursor {
+			lines = append(lines, selectedStyle.Render("▶ "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, "", dimStyle.Render("-stack opens one directly, e.g. backup-tui -stack "+p.stacks[p.cursor].Name))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
+
+// stackTime formats when a stack was created or updated, or "unknown".
+func stackTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s (%s)", t.Format("2006-01-02 15:04"), relativeTime(t))
+}
@@ -0,0 +1,108 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the startup target: -open launches into a view, and
+// -resource or -arn into the detail view of a backup, so a link in a
+// runbook drops the operator where the procedure starts. The target is
+// opened once, after the backups first load, in place of the saved view.
+// The environment info panel shows the current context as a deep link.
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/deeplink"
+)
+
+// StartViews are the views -open launches into.
+var StartViews = []string{tabList, tabJobs, tabTimeline, tabLegalHolds, tabSelections, tabActivity, tabTeam, tabBackupJobs, tabRestoreJobs}
+
+// ValidateStartView returns an error if view is not one of StartViews.
+func ValidateStartView(view string) error {
+	if !slices.Contains(StartViews, view) {
+		return fmt.Errorf("unknown view %q (want one of %s)", view, strings.Join(StartViews, ", "))
+	}
+	return nil
+}
+
+// startTarget is the backup the TUI launches into the detail view of.
+type startTarget struct {
+	arn      string // Recovery point ARN
+	resource string // Resource ID or ARN, whose latest restorable backup is opened
+}
+
+func (t startTarget) String() string {
+	if t.arn != "" {
+		return "recovery point " + t.arn
+	}
+	return "resource " + t.resource
+}
+
+// find returns the backup of points t names. For a resource, that is its
+// latest restorable backup, or its newest one when none is restorable.
+func (t startTarget) find(points []aws.RecoveryPoint) (aws.RecoveryPoint, bool) {
+	if t.arn != "" {
+		i := slices.IndexFunc(points, func(rp aws.RecoveryPoint) bool { return rp.RecoveryPointARN == t.arn })
+		if i < 0 {
+			return aws.RecoveryPoint{}, false
+		}
+		return points[i], true
+	}
+
+	var matching []aws.RecoveryPoint
+	for _, rp := range points {
+		if rp.ResourceID == t.resource || rp.ResourceARN == t.resource {
+			matching = append(matching, rp)
+		}
+	}
+	if len(matching) == 0 {
+		return aws.RecoveryPoint{}, false
+	}
+	arn := ""
+	if latest := aws.LatestRestorable(matching, nil, time.Now()); len(latest) > 0 {
+		arn = latest[0].RecoveryPointARN
+	}
+	newest := matching[0]
+	for _, rp := range matching {
+		if rp.RecoveryPointARN == arn {
+			return rp, true
+		}
+		if rp.CreationDate.After(newest.CreationDate) {
+			newest = rp
+		}
+	}
+	return newest, true
+}
+
+// openStartTarget opens the detail view of the startup target, once, after
+// the backups first load. A target the saved filter hides shows all types;
+// one not in the vault leaves the list open with a warning.
+func (m *Model) openStartTarget() tea.Cmd {
+	t := m.startTarget
+	m.startTarget = startTarget{}
+	rp, ok := t.find(m.allBackups)
+	if !ok {
+		m.notify(SeverityWarn, fmt.Sprintf("No backup of %s in vault %s", t, m.vaultName))
+		return nil
+	}
+	if !m.selectARN(rp.RecoveryPointARN) {
+		m.activeFilter = filterAll
+		m.applyFilter()
+		m.listModel.SetItems(m.formatBackupsForList())
+		m.selectARN(rp.RecoveryPointARN)
+	}
+	return m.openDetail()
+}
+
+// deepLink returns a link to the view s in the current environment, or to
+// the selected backup's details when s is the detail view.
+func (m *Model) deepLink(s state) deeplink.Link {
+	l := deeplink.Link{Region: m.region, Stack: m.stackName, Vault: m.vaultName, View: tabOf(s)}
+	if s == stateDetail && m.selectedIdx < len(m.backups) {
+		l.ARN = m.backups[m.selectedIdx].RecoveryPointARN
+	}
+	return l
+}
@@ -0,0 +1,148 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the status endpoint: with -status-addr, a small local
+// HTTP server returns the session's jobs and state as JSON, so other
+// terminals or dashboards can follow a long DR workflow without the TUI.
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// StatusJob is a job in the jobs view, as reported by the status endpoint.
+type StatusJob struct {
+	Seq           int       `json:"seq"`
+	Kind          string    `json:"kind"`
+	ResourceType  string    `json:"resourceType"`
+	ResourceID    string    `json:"resourceId"`
+	JobID         string    `json:"jobId,omitempty"` // Empty until started
+	State         string    `json:"state"`           // QUEUED, STARTING, ACTIVE, COMPLETED, FAILED, CANCELLED, or SKIPPED
+	Status        string    `json:"status,omitempty"`
+	PercentDone   string    `json:"percentDone,omitempty"`
+	StatusMessage string    `json:"statusMessage,omitempty"`
+	Note          string    `json:"note,omitempty"`     // Why the job failed, was skipped, or was cancelled
+	Progress      string    `json:"progress,omitempty"` // Step being performed while starting
+	After         int       `json:"after,omitempty"`    // Seq of the step that must complete first
+	Leftover      string    `json:"leftover,omitempty"` // Resource left behind by a failed or abandoned job, until deleted
+	StartedAt     time.Time `json:"startedAt,omitzero"`
+}
+
+// Status is the session state returned by the status endpoint.
+type Status struct {
+	Stack     string      `json:"stack"`
+	Vault     string      `json:"vault"`
+	Region    string      `json:"region"`
+	Loading   bool        `json:"loading"`
+	Error     string      `json:"error,omitempty"`          // Fatal error, if any
+	StatusMsg string      `json:"statusMessage,omitempty"`  // Message in the status bar
+	Severity  string      `json:"statusSeverity,omitempty"` // Its severity: info, warn, or critical
+	Warnings  []Warning   `json:"warnings,omitempty"`       // Recent messages, oldest first
+	Running   int         `json:"running"`                  // Jobs that have not finished
+	Jobs      []StatusJob `json:"jobs"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
+
+// StatusBoard holds the latest Status of a Model for the status endpoint.
+// The Model publishes to it after each update; it is safe to read from
+// other goroutines and serves the status as JSON on GET.
+type StatusBoard struct {
+	mu     sync.RWMutex
+	status Status
+}
+
+// NewStatusBoard creates an empty StatusBoard.
+func NewStatusBoard() *StatusBoard {
+	return &StatusBoard{status: Status{Jobs: []StatusJob{}}}
+}
+
+// Status returns the latest published status.
+func (b *StatusBoard) Status() Status {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.status
+}
+
+func (b *StatusBoard) publish(s Status) {
+	b.mu.Lock()
+	b.status = s
+	b.mu.Unlock()
+}
+
+// ServeHTTP writes the latest status as JSON.
+func (b *StatusBoard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" && r.URL.Path != "/status" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(b.Status())
+}
+
+// publishStatus publishes the model's current state to its status board,
+// if it has one.
+func (m *Model) publishStatus() {
+	if m.statusBoard == nil {
+		return
+	}
+	m.statusBoard.publish(m.Status())
+}
+
+// Status returns the session's jobs and state.
+func (m *Model) Status() Status {
+	s := Status{
+		Stack:     m.stackName,
+		Vault:     m.vaultName,
+		Region:    m.region,
+		Loading:   m.state == stateLoading,
+		StatusMsg: m.statusMessage(),
+		Warnings:  slices.Clone(m.warnings.recent),
+		Jobs:      make([]StatusJob, 0, len(m.jobs)),
+		UpdatedAt: time.Now(),
+	}
+	if m.warnings.current != nil {
+		s.Severity = m.warnings.current.Severity.String()
+	}
+	if m.err != nil {
+		s.Error = m.err.Error()
+	}
+	for _, j := range m.jobs {
+		sj := StatusJob{
+			Seq:          j.seq,
+			Kind:         j.kind,
+			ResourceType: j.backup.ResourceType,
+			ResourceID:   j.backup.ResourceID,
+			JobID:        j.jobID,
+			State:        j.state.String(),
+			Note:         j.note,
+			StartedAt:    j.started,
+		}
+		if j.state == jobStarting {
+			sj.Progress = j.progress
+		}
+		if j.after != nil {
+			sj.After = j.after.seq
+		}
+		if r, ok := m.leftover(j); ok {
+			sj.Leftover = r.String()
+		}
+		if j.status != nil {
+			sj.Status, sj.PercentDone, sj.StatusMessage = j.status.Status, j.status.PercentDone, j.status.StatusMessage
+		}
+		if j.state.pending() {
+			s.Running++
+		}
+		s.Jobs = append(s.Jobs, sj)
+	}
+	return s
+}
@@ -0,0 +1,191 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the restore subnet group picker: from the
+// confirmation screen of an RDS restore, "s" lists the DB subnet groups of
+// the stack's VPC with the Availability Zones each covers, and refuses
+// groups a cluster cannot be created in, so the restore does not fail
+// after the job has started.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// subnetPicker is the state of the subnet group picker.
+type subnetPicker struct {
+	vpcID   string
+	groups  []aws.DBSubnetGroup
+	err     error // Error from the last load
+	loading bool
+	cursor  int
+}
+
+// subnetGroupsMsg is sent when the stack's VPC and its DB subnet groups
+// have been loaded.
+type subnetGroupsMsg struct {
+	vpcID  string
+	groups []aws.DBSubnetGroup
+	err    error
+}
+
+// openSubnetPicker opens the subnet group picker for the pending RDS
+// restore and starts loading the VPC's subnet groups unless they were
+// prefetched with the detail view.
+func (m *Model) openSubnetPicker() tea.Cmd {
+	if m.selectedIdx >= len(m.backups) {
+		return nil
+	}
+	if m.backups[m.selectedIdx].ResourceType != "RDS" {
+		m.notify(SeverityWarn, "Subnet groups can only be chosen for RDS restores")
+		return nil
+	}
+	m.subnetPicker = subnetPicker{loading: true}
+	m.state = stateSubnetGroup
+	if p := m.prefetched(); p != nil && p.subnetGroups != nil && p.subnetGroups.err == nil {
+		m.handleSubnetGroups(*p.subnetGroups)
+		return nil
+	}
+	return m.loadSubnetGroups()
+}
+
+// loadSubnetGroups returns a command that lists the DB subnet groups of the
+// stack's VPC.
+func (m *Model) loadSubnetGroups() tea.Cmd {
+	client, stack := m.backupClient, m.stackName
+	return func() tea.Msg {
+		vpcID, err := client.StackVPC(m.ctx, stack)
+		if err != nil {
+			return subnetGroupsMsg{err: err}
+		}
+		groups, err := client.ListDBSubnetGroups(m.ctx, vpcID)
+		return subnetGroupsMsg{vpcID: vpcID, groups: groups, err: err}
+	}
+}
+
+// handleSubnetGroups records the loaded subnet groups and puts the cursor
+// on the group the restore currently uses.
+func (m *Model) handleSubnetGroups(msg subnetGroupsMsg) {
+	p := &m.subnetPicker
+	p.loading = false
+	p.vpcID, p.groups, p.err = msg.vpcID, msg.groups, msg.err
+	p.cursor = 0
+	for i, g := range msg.groups {
+		if m.restoreMetadata != nil && g.Name == m.restoreMetadata.SubnetGroup {
+			p.cursor = i
+		}
+	}
+}
+
+// updateSubnetPicker handles key presses in the subnet group picker.
+func (m *Model) updateSubnetPicker(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	p := &m.subnetPicker
+	switch msg.String() {
+	case "esc", "q", "backspace":
+		m.state = stateConfirm
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.groups)-1 {
+			p.cursor++
+		}
+	case "r":
+		m.setRestoreSubnetGroup("")
+		m.state = stateConfirm
+	case "enter":
+		if p.loading || p.cursor >= len(p.groups) {
+			return m, nil
+		}
+		g := p.groups[p.cursor]
+		if err := g.Validate(p.vpcID); err != nil {
+			m.notify(SeverityWarn, "Cannot restore into "+g.Name+": "+err.Error())
+			return m, nil
+		}
+		m.setRestoreSubnetGroup(g.Name)
+		m.state = stateConfirm
+	}
+	return m, nil
+}
+
+// setRestoreSubnetGroup sets the subnet group of the pending restore; ""
+// restores into the live cluster's.
+func (m *Model) setRestoreSubnetGroup(name string) {
+	m.restoreOpts.SubnetGroup = name
+	m.inform("Restore uses the live cluster's subnet group")
+	if name != "" {
+		m.inform("Restore will use subnet group " + name)
+	}
+	if m.restoreMetadata != nil {
+		m.restoreMetadata.ApplyOptions(m.restoreOpts)
+	}
+}
+
+// renderSubnetPicker renders the subnet group picker.
+func (m *Model) renderSubnetPicker() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+
+	p := m.subnetPicker
+	lines := []string{titleStyle.Render("Restore Subnet Group"), ""}
+	switch {
+	case p.loading:
+		lines = append(lines, dimStyle.Render("Loading the stack's VPC subnet groups..."))
+	case p.err != nil:
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("Could not list DB subnet groups: %v", p.err)))
+	case len(p.groups) == 0:
+		lines = append(lines, dimStyle.Render("No DB subnet groups in "+p.vpcID))
+	default:
+		lines = append(lines, dimStyle.Render("VPC "+p.vpcID), "")
+	}
+
+	current := ""
+	if m.restoreMetadata != nil {
+		current = m.restoreMetadata.SubnetGroup
+	}
+	for i, g := range p.groups {
+		entry := g.Name
+		if g.Name == current {
+			entry += " (current)"
+		}
+		azs := g.AZs()
+		coverage := fmt.Sprintf("%d AZs: %s", len(azs), strings.Join(azs, ", "))
+		if len(azs) == 0 {
+			coverage = "no active subnets"
+		}
+		if i == p.cursor {
+			lines = append(lines, focusStyle.Render("▸ "+entry))
+		} else {
+			lines = append(lines, infoStyle.Render("  "+entry))
+		}
+		if err := g.Validate(p.vpcID); err != nil {
+			lines = append(lines, warnStyle.Render("    ✗ "+coverage+fmt.Sprintf(" (needs %d)", aws.MinSubnetGroupAZs)))
+		} else {
+			lines = append(lines, dimStyle.Render("    ✓ "+coverage))
+		}
+	}
+
+	lines = append(lines, "",
+		dimStyle.Render(fmt.Sprintf("Aurora needs subnets in at least %d Availability Zones; groups covering fewer cannot be chosen.", aws.MinSubnetGroupAZs)))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,389 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements switching between vaults and regions at runtime. The
+// switch prompt lists the region's vaults with their recovery point counts,
+// narrowed as a name is typed. The list context (filter, sort order,
+// selected backup, cursor) is remembered per vault, so flipping between e.g.
+// a production and a DR vault returns to exactly where the operator left
+// off, and the vault last shown is reopened for the stack on the next launch
+// (views.go).
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// regionSourceSwitched is shown as the region source after an in-app switch.
+const regionSourceSwitched = "switched in app"
+
+// vaultLocation identifies a vault in a region.
+type vaultLocation struct {
+	region string
+	vault  string
+}
+
+func (l vaultLocation) String() string {
+	return l.region + "/" + l.vault
+}
+
+// listContext is the list view state remembered for a vault.
+type listContext struct {
+	filter      filterMode
+	sort        sortMode
+	selectedARN string // Recovery point under the cursor
+	resourceKey string // Type and ID of the selected resource, for matching across vaults
+	cursor      int    // Cursor index, used when the selected backup no longer exists
+}
+
+// vaultSwitchState holds vault switching state on the Model.
+type vaultSwitchState struct {
+	input      string                        // Text typed at the switch prompt
+	vaults     []aws.VaultInfo               // Vaults of the current region, listed at the prompt
+	vaultsErr  error                         // Why the vaults could not be listed
+	loading    bool                          // Vaults being listed
+	cursor     int                           // Highlighted vault among those matching the input
+	contexts   map[vaultLocation]listContext // Remembered list context per vault
+	clients    map[string]*aws.BackupClient  // Clients per region, reused across switches
+	previous   *vaultLocation                // Vault to return to with "-"
+	remembered bool                          // Vault reopened from the saved view, not yet loaded
+}
+
+// vaultListMsg is sent when the region's vaults have been listed for the
+// switch prompt.
+type vaultListMsg struct {
+	region string
+	vaults []aws.VaultInfo
+	err    error
+}
+
+// vaultSwitchedMsg is sent when a switch has loaded the target vault's backups.
+type vaultSwitchedMsg struct {
+	to      vaultLocation
+	client  *aws.BackupClient
+	backups []aws.RecoveryPoint
+	listing aws.RecoveryPointListing
+	err     error
+}
+
+// resourceKey identifies the resource a recovery point was taken from.
+// Copies in a DR vault share the key with their source.
+func resourceKey(rp aws.RecoveryPoint) string {
+	return rp.ResourceType + "/" + rp.ResourceID
+}
+
+// currentLocation returns the vault and region currently shown.
+func (m *Model) currentLocation() vaultLocation {
+	return vaultLocation{region: m.region, vault: m.vaultName}
+}
+
+// captureContext snapshots the list view state.
+func (m *Model) captureContext() listContext {
+	lc := listContext{
+		filter: m.activeFilter,
+		sort:   m.activeSort,
+		cursor: m.listModel.SelectedIndex(),
+	}
+	if idx := m.listModel.SelectedIndex(); idx >= 0 && idx < len(m.backups) {
+		lc.selectedARN = m.backups[idx].RecoveryPointARN
+		lc.resourceKey = resourceKey(m.backups[idx])
+	}
+	return lc
+}
+
+// restoreCursor positions the list cursor from a remembered context: on the
+// same recovery point if it still exists, else on the newest backup of the
+// same resource (as sorted), else at the remembered index.
+func (m *Model) restoreCursor(lc listContext) {
+	if m.selectARN(lc.selectedARN) {
+		return
+	}
+	if lc.resourceKey != "" {
+		for i, bp := range m.backups {
+			if resourceKey(bp) == lc.resourceKey {
+				m.listModel.SetCursor(i)
+				m.selectedIdx = i
+				return
+			}
+		}
+	}
+	m.listModel.SetCursor(lc.cursor)
+	m.selectedIdx = m.listModel.SelectedIndex()
+}
+
+// parseVaultLocation parses "vault" or "region/vault" relative to the
+// current region.
+func (m *Model) parseVaultLocation(input string) (vaultLocation, error) {
+	input = strings.TrimSpace(input)
+	loc := vaultLocation{region: m.region, vault: input}
+	if region, vault, ok := strings.Cut(input, "/"); ok {
+		loc = vaultLocation{region: strings.TrimSpace(region), vault: strings.TrimSpace(vault)}
+		if !aws.ValidRegion(loc.region) {
+			return loc, fmt.Errorf("%q is not a valid AWS region name", loc.region)
+		}
+	}
+	if loc.vault == "" {
+		return loc, fmt.Errorf("vault name cannot be empty")
+	}
+	return loc, nil
+}
+
+// switchVault returns a command that loads the target vault's backups,
+// creating a client for the target region if needed. The current vault is
+// kept until the load succeeds, so a mistyped name does not lose the view.
+func (m *Model) switchVault(to vaultLocation) tea.Cmd {
+	client, base := m.backupClient, m.backupClient
+	if to.region != m.region {
+		if m.backupClient != nil && m.backupClient.Simulated() {
+			return func() tea.Msg {
+				return vaultSwitchedMsg{to: to, err: fmt.Errorf("simulation fixtures cover only %s", m.region)}
+			}
+		}
+		client = m.vaultSwitch.clients[to.region]
+	}
+	resourceTypes := m.resourceTypes
+	ctx := m.ctx
+
+	return func() tea.Msg {
+		if client == nil {
+			var err error
+			client, err = base.InRegion(ctx, to.region)
+			if err != nil {
+				return vaultSwitchedMsg{to: to, err: fmt.Errorf("failed to create client for %s: %w", to.region, err)}
+			}
+		}
+		listing, err := client.ListRecoveryPointsCounted(ctx, to.vault, resourceTypes...)
+		if err != nil {
+			return vaultSwitchedMsg{to: to, err: err}
+		}
+		return vaultSwitchedMsg{to: to, client: client, backups: listing.Points, listing: listing}
+	}
+}
+
+// handleVaultSwitched applies a completed switch: the current vault's list
+// context is remembered, and the target's remembered context is restored.
+// A vault visited for the first time keeps the current filter and sort and
+// selects the same resource where possible.
+func (m *Model) handleVaultSwitched(msg vaultSwitchedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.state = stateList
+		m.reportError(fmt.Sprintf("Could not switch to %s: %v", msg.to, msg.err), msg.err)
+		return nil
+	}
+
+	from := m.currentLocation()
+	current := m.captureContext()
+	if m.vaultSwitch.contexts == nil {
+		m.vaultSwitch.contexts = make(map[vaultLocation]listContext)
+	}
+	if m.vaultSwitch.clients == nil {
+		m.vaultSwitch.clients = make(map[string]*aws.BackupClient)
+	}
+	m.vaultSwitch.contexts[from] = current
+	m.vaultSwitch.clients[m.region] = m.backupClient
+	m.vaultSwitch.previous = &from
+
+	target, remembered := m.vaultSwitch.contexts[msg.to]
+	if !remembered {
+		target = current
+		target.selectedARN = ""
+		target.cursor = 0
+	}
+
+	if msg.to.region != m.region {
+		m.region = msg.to.region
+		m.regionSource = regionSourceSwitched
+	}
+	m.vaultName = msg.to.vault
+	m.backupClient = msg.client
+	m.planRole = nil
+	m.vaultInfo = nil
+	m.activeFilter = target.filter
+	m.activeSort = target.sort
+	m.allBackups = msg.backups
+	m.listing = msg.listing
+	m.applyFilter()
+	m.listModel.SetItems(m.formatBackupsForList())
+	m.restoreCursor(target)
+	m.state = stateList
+	m.inform(fmt.Sprintf("Switched to %s", msg.to))
+
+	return tea.Batch(m.resolvePlanRole(false), m.describeVault())
+}
+
+// updateSwitchVault handles key presses at the vault switch prompt: typing
+// narrows the listed vaults, the arrows move between them, and enter
+// switches to the highlighted one, or to the typed name when none matches
+// or a region is given.
+func (m *Model) updateSwitchVault(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	v := &m.vaultSwitch
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.state = stateList
+	case "up":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down":
+		if v.cursor < len(m.matchingVaults())-1 {
+			v.cursor++
+		}
+	case "enter":
+		input := v.input
+		if vault, ok := m.highlightedVault(); ok {
+			input = vault.Name
+		}
+		to, err := m.parseVaultLocation(input)
+		if err != nil {
+			m.notify(SeverityWarn, err.Error())
+			return m, nil
+		}
+		if to == m.currentLocation() {
+			m.state = stateList
+			return m, nil
+		}
+		m.state = stateLoading
+		return m, tea.Batch(m.switchVault(to), m.tickSpinner())
+	case "backspace":
+		if r := []rune(v.input); len(r) > 0 {
+			v.input = string(r[:len(r)-1])
+			v.cursor = 0
+		}
+	default:
+		if msg.Text != "" {
+			v.input += msg.Text
+			v.cursor = 0
+		}
+	}
+	return m, nil
+}
+
+// startVaultSwitch opens the vault switch prompt and lists the region's
+// vaults for it.
+func (m *Model) startVaultSwitch() tea.Cmd {
+	v := &m.vaultSwitch
+	v.input, v.cursor = "", 0
+	v.vaults, v.vaultsErr, v.loading = nil, nil, true
+	m.clearStatus()
+	m.state = stateSwitchVault
+	client, ctx, region := m.backupClient, m.ctx, m.region
+	return func() tea.Msg {
+		vaults, err := client.ListVaults(ctx)
+		return vaultListMsg{region: region, vaults: vaults, err: err}
+	}
+}
+
+// handleVaultList records the listed vaults, unless the region has changed
+// since. Without them the prompt still takes a typed name.
+func (m *Model) handleVaultList(msg vaultListMsg) {
+	if msg.region != m.region {
+		return
+	}
+	v := &m.vaultSwitch
+	v.vaults, v.vaultsErr, v.loading = msg.vaults, msg.err, false
+	v.cursor = 0
+}
+
+// matchingVaults returns the listed vaults whose names contain the typed
+// input, ignoring case. A typed region/vault is for another region, so
+// nothing listed matches it.
+func (m *Model) matchingVaults() []aws.VaultInfo {
+	input := strings.ToLower(strings.TrimSpace(m.vaultSwitch.input))
+	if strings.Contains(input, "/") {
+		return nil
+	}
+	var matches []aws.VaultInfo
+	for _, vault := range m.vaultSwitch.vaults {
+		if strings.Contains(strings.ToLower(vault.Name), input) {
+			matches = append(matches, vault)
+		}
+	}
+	return matches
+}
+
+// highlightedVault returns the vault enter switches to, if any matches.
+func (m *Model) highlightedVault() (aws.VaultInfo, bool) {
+	matches := m.matchingVaults()
+	if len(matches) == 0 {
+		return aws.VaultInfo{}, false
+	}
+	return matches[min(m.vaultSwitch.cursor, len(matches)-1)], true
+}
+
+// switchToPrevious returns to the previously shown vault, if any.
+func (m *Model) switchToPrevious() tea.Cmd {
+	if m.vaultSwitch.previous == nil {
+		m.notify(SeverityWarn, "No previous vault to return to")
+		return nil
+	}
+	m.state = stateLoading
+	return tea.Batch(m.switchVault(*m.vaultSwitch.previous), m.tickSpinner())
+}
+
+// renderSwitchVault renders the vault switch prompt with the region's
+// vaults and the previously visited one.
+func (m *Model) renderSwitchVault() string {
+	header := m.renderHeader()
+	v := m.vaultSwitch
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2)
+	labelStyle := lipgloss.NewStyle().Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	selectedStyle := lipgloss.NewStyle().Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")})
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+
+	lines := []string{
+		labelStyle.Render("Switch vault"),
+		"",
+		"Vault name, or region/vault for another region:",
+		"> " + v.input + "█",
+		"",
+	}
+	matches := m.matchingVaults()
+	highlighted, _ := m.highlightedVault()
+	switch {
+	case v.loading:
+		lines = append(lines, dimStyle.Render("Listing the vaults in "+m.region+"..."))
+	case v.vaultsErr != nil:
+		lines = append(lines, warnStyle.Render("Vaults not listed: "+v.vaultsErr.Error()), dimStyle.Render("Type the vault's name instead."))
+	case len(matches) == 0 && strings.Contains(v.input, "/"):
+		lines = append(lines, dimStyle.Render("Enter switches to the typed region and vault."))
+	case len(matches) == 0:
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("No vault in %s matches; enter tries the typed name.", m.region)))
+	default:
+		lines = append(lines, labelStyle.Render(fmt.Sprintf("Vaults in %s:", m.region)))
+		width := 0
+		for _, vault := range matches {
+			width = max(width, len(vault.Name))
+		}
+		for _, vault := range matches {
+			line := fmt.Sprintf("%-*s  %6d recovery points", width, vault.Name, vault.RecoveryPoints)
+			if vault.AirGapped() {
+				line += "  AIR-GAPPED"
+			}
+			if vault.Name == m.vaultName {
+				line += "  (current)"
+			}
+			if vault.Name == highlighted.Name {
+				lines = append(lines, selectedStyle.Render("▶ "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+	}
+	if v.previous != nil {
+		lines = append(lines, "", dimStyle.Render("Previous: "+v.previous.String()+"  (press - in the list to return)"))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,225 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the tag editor: "#" in the list edits the tags of the
+// backups marked with space (or of the one under the cursor), and in the
+// detail view those of the selected backup, so classification such as
+// verified=true or hold=legal-2026-01 is applied without the CLI. An edit
+// that would lift the config file's deletion protection from a backup is
+// refused for that backup.
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// tagEditor is the state of the tag editor.
+type tagEditor struct {
+	targets  []aws.RecoveryPoint
+	input    string
+	saving   bool
+	err      error // Validation error, shown in the editor
+	returnTo state // View to return to
+}
+
+// tagResult is the outcome of a tag edit on one backup.
+type tagResult struct {
+	rp  aws.RecoveryPoint
+	err error
+}
+
+// tagsEditedMsg is sent when a tag edit has been applied to every target.
+type tagsEditedMsg struct {
+	edit    aws.TagEdit
+	results []tagResult
+}
+
+// openTagEdit opens the tag editor on the marked backups, or the selected
+// one when none are marked or the detail view is open.
+func (m *Model) openTagEdit() {
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return
+	}
+	m.tagEdit = tagEditor{targets: targets, returnTo: m.state}
+	m.state = stateTagEdit
+}
+
+// updateTagEdit handles key presses in the tag editor.
+func (m *Model) updateTagEdit(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	e := &m.tagEdit
+	if e.saving {
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.state = e.returnTo
+	case "backspace":
+		if r := []rune(e.input); len(r) > 0 {
+			e.input = string(r[:len(r)-1])
+		}
+	case "enter":
+		edit, err := aws.ParseTagEdit(e.input)
+		if e.err = err; err != nil {
+			return m, nil
+		}
+		e.saving = true
+		return m, m.editTags(e.targets, edit)
+	default:
+		if msg.Text != "" {
+			e.input += msg.Text
+		}
+	}
+	return m, nil
+}
+
+// editTags returns a command that applies edit to each of targets in turn.
+func (m *Model) editTags(targets []aws.RecoveryPoint, edit aws.TagEdit) tea.Cmd {
+	client, vaultName, cfg, ctx := m.backupClient, m.vaultName, m.config, m.ctx
+	details := make(map[string]*aws.RecoveryPointDetails, len(targets))
+	for _, rp := range targets {
+		details[rp.RecoveryPointARN] = m.enrich.details[rp.RecoveryPointARN]
+	}
+	return func() tea.Msg {
+		results := make([]tagResult, len(targets))
+		for i, rp := range targets {
+			err := checkUnprotects(ctx, client, cfg, vaultName, rp, details[rp.RecoveryPointARN], edit)
+			if err == nil {
+				err = client.EditRecoveryPointTags(ctx, rp.RecoveryPointARN, edit)
+			}
+			results[i] = tagResult{rp: rp, err: err}
+		}
+		return tagsEditedMsg{edit: edit, results: results}
+	}
+}
+
+// checkUnprotects returns an error if a tag rule of cfg protects rp from
+// deletion and would no longer once edit is applied. details are rp's
+// known details, or nil; unknown tags are looked up, and a failed lookup
+// refuses the edit rather than risk it.
+func checkUnprotects(ctx context.Context, client *aws.BackupClient, cfg *config.Config, vaultName string, rp aws.RecoveryPoint, details *aws.RecoveryPointDetails, edit aws.TagEdit) error {
+	if cfg == nil || !cfg.ProtectsByTag() || len(edit.Remove) == 0 && len(edit.Add) == 0 {
+		return nil
+	}
+	if details == nil {
+		var err error
+		if details, err = client.DescribeRecoveryPointDetails(ctx, vaultName, rp.RecoveryPointARN); err != nil {
+			return fmt.Errorf("cannot check the backup's tags against deletion protection, so they are kept: %w", err)
+		}
+	}
+	now := time.Now()
+	p := protectedPoint(rp, details)
+	rule := cfg.Protection(p, now)
+	if rule == nil {
+		return nil
+	}
+	p.Tags = edit.Apply(p.Tags)
+	if cfg.Protection(p, now) == nil {
+		return fmt.Errorf("the edit would lift the deletion protection of config rule %s", rule)
+	}
+	return nil
+}
+
+// handleTagsEdited updates the known tags of the edited backups and
+// reports the ones that failed.
+func (m *Model) handleTagsEdited(msg tagsEditedMsg) {
+	m.tagEdit.saving = false
+	var failed []string
+	var lastErr error
+	for _, r := range msg.results {
+		if r.err != nil {
+			failed = append(failed, r.rp.ResourceID+" "+r.rp.CreationDate.Format("01-02 15:04"))
+			lastErr = r.err
+			m.logError(fmt.Sprintf("Tags of %s (%s) not edited", r.rp.ResourceID, r.rp.CreationDate.Format("2006-01-02 15:04")), r.err)
+			continue
+		}
+		if d := m.enrich.details[r.rp.RecoveryPointARN]; d != nil {
+			edited := *d
+			edited.Tags = msg.edit.Apply(d.Tags)
+			m.enrich.details[r.rp.RecoveryPointARN] = &edited
+			if m.selectedIdx < len(m.backups) && m.backups[m.selectedIdx].RecoveryPointARN == r.rp.RecoveryPointARN {
+				m.detailModel.SetDetails(&edited)
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		m.tagEdit.err = lastErr
+		m.notify(SeverityCritical, fmt.Sprintf("Tags not edited on %d of %d backup(s): %s (e for details)",
+			len(failed), len(msg.results), strings.Join(failed, ", ")))
+		return
+	}
+	if m.tagEdit.returnTo == stateList {
+		clear(m.marked)
+	}
+	m.listModel.SetItems(m.formatBackupsForList())
+	if m.state == stateTagEdit {
+		m.state = m.tagEdit.returnTo
+	}
+	m.inform(fmt.Sprintf("Tags edited on %d backup(s): %s", len(msg.results), msg.edit))
+}
+
+// renderTagEdit renders the tag editor.
+func (m *Model) renderTagEdit() string {
+	header := m.renderHeader()
+	e := m.tagEdit
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	errStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+
+	lines := []string{titleStyle.Render("Edit Tags"), ""}
+	// Long selections are summarized past the first few backups
+	const shown = 5
+	for i, rp := range e.targets {
+		if i == shown {
+			lines = append(lines, infoStyle.Render(fmt.Sprintf("  … and %d more", len(e.targets)-shown)))
+			break
+		}
+		line := fmt.Sprintf("  %s %s (%s)", rp.ResourceType, rp.ResourceID, rp.CreationDate.Format("2006-01-02 15:04 MST"))
+		if d := m.enrich.details[rp.RecoveryPointARN]; d != nil {
+			line += "  " + formatTags(d.Tags)
+		}
+		lines = append(lines, infoStyle.Render(line))
+	}
+	lines = append(lines,
+		"",
+		"Tags to add (key=value) or remove (-key):",
+		"> "+e.input+"█",
+		"",
+		dimStyle.Render("e.g. verified=true hold=legal-2026-01 -stale; an existing key is overwritten"),
+	)
+	if e.saving {
+		lines = append(lines, "", infoStyle.Render(fmt.Sprintf("Editing the tags of %d backup(s)...", len(e.targets))))
+	}
+	if e.err != nil {
+		lines = append(lines, "", errStyle.Render("✗ "+e.err.Error()))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
+
+// formatTags formats tags on one line, e.g. "env=prod verified=true", or
+// "no tags".
+func formatTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return "no tags"
+	}
+	return aws.TagEdit{Add: tags}.String()
+}
@@ -0,0 +1,87 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the recovery objective coloring of the latest
+// restorable banner: when the config file sets an RPO or RTO target for a
+// resource type, its latest point is colored against the RPO and its
+// slowest recent restore against the RTO, instead of by fixed ages. The
+// backup list's Created column is colored by age against the same RPO.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// rpoWarnFraction is the share of the RPO after which a backup is shown as
+// nearing it.
+const rpoWarnFraction = 0.75
+
+// defaultListRPO is the RPO the list's Created column is colored against
+// for resource types without an RPO target.
+const defaultListRPO = 24 * time.Hour
+
+// staleRPOFactor is the multiple of the RPO after which a backup is shown
+// as stale in the list.
+const staleRPOFactor = 3
+
+// ageLevel returns the level of a backup's age in the list: OK within the
+// RPO, Warn within staleRPOFactor times it, and Fail when older. With no
+// RPO target, that is OK under 24h, Warn under 72h, Fail older.
+func ageLevel(created time.Time, rpo time.Duration, now time.Time) ui.Level {
+	if rpo <= 0 {
+		rpo = defaultListRPO
+	}
+	switch age := now.Sub(created); {
+	case age < rpo:
+		return ui.LevelOK
+	case age < staleRPOFactor*rpo:
+		return ui.LevelWarn
+	default:
+		return ui.LevelFail
+	}
+}
+
+// rpoCompliance renders the age of a backup against an RPO target: the
+// level's dot and a label, e.g. "within RPO 26h00m".
+func rpoCompliance(created time.Time, rpo time.Duration, now time.Time) (dot, label string) {
+	okStyle := lipgloss.NewStyle().Foreground(ui.ColorOK)
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+	failStyle := lipgloss.NewStyle().Foreground(ui.ColorFail).Bold(true)
+
+	target := formatElapsed(rpo)
+	switch age := now.Sub(created); {
+	case age > rpo:
+		return failStyle.Render(ui.LevelFail.Glyph()), failStyle.Render("outside RPO " + target)
+	case float64(age) > rpoWarnFraction*float64(rpo):
+		return ui.LevelWarn.Dot(), warnStyle.Render("nearing RPO " + target)
+	default:
+		return ui.LevelOK.Dot(), okStyle.Render("within RPO " + target)
+	}
+}
+
+// rtoCompliance renders the slowest successful restore of a resource type
+// among restores against an RTO target, or that no restore has tested it.
+func rtoCompliance(resourceType string, restores []aws.JobRecord, rto time.Duration) string {
+	okStyle := lipgloss.NewStyle().Foreground(ui.ColorOK)
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+	failStyle := lipgloss.NewStyle().Foreground(ui.ColorFail).Bold(true)
+
+	var slowest time.Duration
+	for _, j := range restores {
+		if j.ResourceType == resourceType && j.Succeeded() {
+			slowest = max(slowest, j.Duration())
+		}
+	}
+	target := formatElapsed(rto)
+	switch {
+	case slowest == 0:
+		return warnStyle.Render("RTO " + target + " untested")
+	case slowest > rto:
+		return failStyle.Render(fmt.Sprintf("restore took %s, over RTO %s", formatElapsed(slowest), target))
+	default:
+		return okStyle.Render(fmt.Sprintf("restore took %s, within RTO %s", formatElapsed(slowest), target))
+	}
+}
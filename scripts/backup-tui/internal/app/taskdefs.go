@@ -0,0 +1,247 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the task definition history view: recent revisions of
+// the OpenEMR ECS task definition with their images, environment changes, and
+// registration times, marking the revision that was running when the selected
+// backup was taken.
+package app
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// taskDefHistoryDepth is how many task definition revisions are loaded, both
+// for the history view and for finding the last OpenEMR image change.
+const taskDefHistoryDepth = 25
+
+// taskDefView is the state of the task definition history view.
+type taskDefView struct {
+	history  []aws.TaskDefinitionRevision // Newest first
+	err      error                        // Error from the last load
+	loading  bool
+	loaded   bool
+	cursor   int
+	backup   *aws.RecoveryPoint // Backup to correlate with (nil when opened without one)
+	returnTo state              // View to return to on esc/q
+}
+
+// taskDefHistoryMsg is sent when the task definition history load completes.
+type taskDefHistoryMsg struct {
+	history []aws.TaskDefinitionRevision
+	err     error
+}
+
+// fetchTaskDefHistory returns a command that loads the OpenEMR task
+// definition history.
+func (m *Model) fetchTaskDefHistory() tea.Cmd {
+	client, stackName := m.backupClient, m.stackName
+	m.taskDefs.loading = true
+	return func() tea.Msg {
+		history, err := client.TaskDefinitionHistory(m.ctx, stackName, taskDefHistoryDepth)
+		return taskDefHistoryMsg{history: history, err: err}
+	}
+}
+
+// handleTaskDefHistory stores a loaded history and the image change in it.
+// Without history (e.g. no ECS permissions) there is simply no schema
+// warning; it is advisory, not a restore blocker.
+func (m *Model) handleTaskDefHistory(msg taskDefHistoryMsg) {
+	m.taskDefs.loading = false
+	m.taskDefs.loaded = true
+	m.taskDefs.err = msg.err
+	if msg.err != nil {
+		return
+	}
+	m.taskDefs.history = msg.history
+	m.appUpgrade = aws.LastImageChange(msg.history)
+	if m.taskDefs.cursor >= len(msg.history) {
+		m.taskDefs.cursor = 0
+	}
+	if i := m.runningRevision(); i >= 0 && m.state == stateTaskDefs {
+		m.taskDefs.cursor = i
+	}
+}
+
+// openTaskDefs opens the history view for the selected backup, positioning
+// the cursor on the revision that was running when it was taken.
+func (m *Model) openTaskDefs() tea.Cmd {
+	m.taskDefs.returnTo = m.state
+	m.taskDefs.backup = nil
+	idx := m.selectedIdx
+	if m.state == stateList {
+		idx = m.listModel.SelectedIndex()
+	}
+	if idx < len(m.backups) {
+		rp := m.backups[idx]
+		m.taskDefs.backup = &rp
+	}
+	m.state = stateTaskDefs
+	if i := m.runningRevision(); i >= 0 {
+		m.taskDefs.cursor = i
+	}
+	if !m.taskDefs.loaded && !m.taskDefs.loading {
+		return m.fetchTaskDefHistory()
+	}
+	return nil
+}
+
+// refreshTaskDefs reloads the history.
+func (m *Model) refreshTaskDefs() tea.Cmd {
+	if m.taskDefs.loading {
+		return nil
+	}
+	return m.fetchTaskDefHistory()
+}
+
+// updateTaskDefs handles key presses in the history view.
+func (m *Model) updateTaskDefs(msg tea.KeyPressMsg) {
+	switch msg.String() {
+	case "up", "k":
+		if m.taskDefs.cursor > 0 {
+			m.taskDefs.cursor--
+		}
+	case "down", "j":
+		if m.taskDefs.cursor < len(m.taskDefs.history)-1 {
+			m.taskDefs.cursor++
+		}
+	}
+}
+
+// runningRevision returns the index of the revision that was the newest
+// registered when the correlated backup was taken, or -1 if unknown.
+func (m *Model) runningRevision() int {
+	if m.taskDefs.backup == nil {
+		return -1
+	}
+	for i, rev := range m.taskDefs.history {
+		if !rev.RegisteredAt.After(m.taskDefs.backup.CreationDate) {
+			return i
+		}
+	}
+	return -1
+}
+
+// envChanges describes how a container's environment changed between two
+// revisions, one line per variable: "+ NAME=value", "- NAME", or
+// "~ NAME: old → new".
+func envChanges(prev, cur map[string]string) []string {
+	names := make([]string, 0, len(prev)+len(cur))
+	for name := range cur {
+		names = append(names, name)
+	}
+	for name := range prev {
+		if _, ok := cur[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []string
+	for _, name := range names {
+		old, had := prev[name]
+		value, has := cur[name]
+		switch {
+		case !had:
+			changes = append(changes, fmt.Sprintf("+ %s=%s", name, truncateValue(value)))
+		case !has:
+			changes = append(changes, "- "+name)
+		case old != value:
+			changes = append(changes, fmt.Sprintf("~ %s: %s → %s", name, truncateValue(old), truncateValue(value)))
+		}
+	}
+	return changes
+}
+
+// truncateValue shortens long environment values such as endpoints.
+func truncateValue(s string) string {
+	const maxLen = 48
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
+
+// renderTaskDefs renders the task definition history.
+func (m *Model) renderTaskDefs() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	changeStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+	markStyle := lipgloss.NewStyle().Bold(true).Foreground(ui.ColorOK)
+
+	lines := []string{titleStyle.Render("OpenEMR Task Definition History")}
+	tv := m.taskDefs
+	if tv.backup != nil {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("Backup: %s %s taken %s",
+			tv.backup.ResourceType, tv.backup.ResourceID, tv.backup.CreationDate.Format("2006-01-02 15:04:05 MST"))))
+	}
+	lines = append(lines, "")
+
+	switch {
+	case tv.loading && len(tv.history) == 0:
+		lines = append(lines, dimStyle.Render("Loading task definition history..."))
+	case tv.err != nil:
+		lines = append(lines, changeStyle.Render("Unavailable: "+tv.err.Error()))
+	case len(tv.history) == 0:
+		lines = append(lines, dimStyle.Render("No task definition revisions found."))
+	}
+
+	running := m.runningRevision()
+	for i, rev := range tv.history {
+		app := rev.AppContainer()
+		line := fmt.Sprintf("rev %-4d %s (%s)  %s", rev.Revision,
+			rev.RegisteredAt.Local().Format("2006-01-02 15:04"), relativeTime(rev.RegisteredAt), app.Image)
+		if i == tv.cursor {
+			line = focusStyle.Render("▸ " + line)
+		} else {
+			line = infoStyle.Render("  " + line)
+		}
+		if i == running {
+			line += markStyle.Render("  ◀ running when backup was taken")
+		}
+		lines = append(lines, line)
+
+		// Changes from the next older revision; the oldest loaded has no baseline
+		if i+1 >= len(tv.history) {
+			lines = append(lines, dimStyle.Render("      (oldest loaded revision)"))
+			continue
+		}
+		prev := tv.history[i+1].AppContainer()
+		if prev.Image != app.Image {
+			lines = append(lines, changeStyle.Render(fmt.Sprintf("      image: %s → %s", prev.Image, app.Image)))
+		}
+		changes := envChanges(prev.Environment, app.Environment)
+		for _, change := range changes {
+			lines = append(lines, changeStyle.Render("      "+change))
+		}
+		if prev.Image == app.Image && len(changes) == 0 {
+			lines = append(lines, dimStyle.Render("      no image or environment changes"))
+		}
+	}
+
+	if tv.backup != nil && running < 0 && len(tv.history) > 0 {
+		oldest := tv.history[len(tv.history)-1]
+		lines = append(lines, "", dimStyle.Render(fmt.Sprintf("The backup predates the oldest loaded revision (%d, %s).",
+			oldest.Revision, oldest.RegisteredAt.Local().Format(time.DateOnly))))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,266 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the timeline view: backups, restore and copy jobs, and
+// ECS deployments merged into one chronological list, so incident responders
+// can see what changed and when relative to the available backups.
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// timelineWindow is how far back the timeline reaches.
+const timelineWindow = 14 * 24 * time.Hour
+
+// Timeline event kinds.
+const (
+	eventBackup  = "backup"
+	eventRestore = "restore"
+	eventCopy    = "copy"
+	eventDeploy  = "deploy"
+)
+
+// timelineEvent is one entry in the timeline.
+type timelineEvent struct {
+	at        time.Time
+	kind      string // eventBackup, eventRestore, eventCopy, or eventDeploy
+	summary   string
+	failed    bool
+	backupIdx int // Index into m.backups for recovery points, -1 otherwise
+}
+
+// timelineView is the state of the timeline view.
+type timelineView struct {
+	vault       string // Vault the jobs were loaded for
+	jobs        []aws.JobRecord
+	deployments []aws.Deployment
+	errs        []string // Sources that could not be loaded
+	loading     bool
+	cursor      int
+}
+
+// timelineMsg is sent when the timeline's job and deployment lookups complete.
+type timelineMsg struct {
+	vault          string
+	jobs           []aws.JobRecord
+	jobsErr        error
+	deployments    []aws.Deployment
+	deploymentsErr error
+}
+
+// openTimeline opens the timeline and loads jobs and deployments for it.
+func (m *Model) openTimeline() tea.Cmd {
+	m.state = stateTimeline
+	m.timeline.cursor = 0
+	return m.loadTimeline()
+}
+
+// loadTimeline returns a command that loads the timeline's restore and copy
+// jobs and ECS deployments. Recovery points come from the loaded list.
+func (m *Model) loadTimeline() tea.Cmd {
+	if m.timeline.loading {
+		return nil
+	}
+	m.timeline.loading = true
+	client, stackName, vaultName := m.backupClient, m.stackName, m.vaultName
+	return func() tea.Msg {
+		since := time.Now().Add(-timelineWindow)
+		msg := timelineMsg{vault: vaultName}
+		msg.jobs, msg.jobsErr = client.ListJobs(m.ctx, vaultName, since)
+		msg.deployments, msg.deploymentsErr = client.ServiceDeployments(m.ctx, stackName, since)
+		return msg
+	}
+}
+
+// handleTimeline stores loaded timeline sources. Either source may fail on
+// its own (e.g. no ECS permissions); the rest of the timeline is still shown.
+func (m *Model) handleTimeline(msg timelineMsg) {
+	m.timeline.loading = false
+	if msg.vault != m.vaultName {
+		return // The vault was switched while loading
+	}
+	m.timeline.vault = msg.vault
+	m.timeline.jobs, m.timeline.deployments, m.timeline.errs = msg.jobs, msg.deployments, nil
+	if msg.jobsErr != nil {
+		m.timeline.errs = append(m.timeline.errs, "jobs: "+msg.jobsErr.Error())
+	}
+	if msg.deploymentsErr != nil {
+		m.timeline.errs = append(m.timeline.errs, "deployments: "+msg.deploymentsErr.Error())
+	}
+}
+
+// timelineEvents merges the window's backups, jobs, and deployments, newest
+// first.
+func (m *Model) timelineEvents() []timelineEvent {
+	since := time.Now().Add(-timelineWindow)
+	var events []timelineEvent
+
+	for i, rp := range m.backups {
+		if rp.CreationDate.Before(since) {
+			continue
+		}
+		events = append(events, timelineEvent{
+			at:        rp.CreationDate,
+			kind:      eventBackup,
+			summary:   fmt.Sprintf("%s %s  %s  %s", rp.ResourceType, rp.ResourceID, formatBytes(rp.BackupSizeInBytes), rp.Status),
+			failed:    rp.Status != "COMPLETED",
+			backupIdx: i,
+		})
+	}
+
+	for _, j := range m.timeline.jobs {
+		// Successful backup jobs are already listed as recovery points
+		if j.Kind == aws.JobKindBackup && !j.Failed() {
+			continue
+		}
+		summary := fmt.Sprintf("%s %s  %s", j.ResourceType, arnName(j.ResourceARN), j.State)
+		if d := j.Duration(); d > 0 {
+			summary += "  " + formatElapsed(d)
+		}
+		if j.Failed() && j.StatusMessage != "" {
+			summary += "  " + j.StatusMessage
+		}
+		events = append(events, timelineEvent{at: j.CreatedAt, kind: j.Kind, summary: summary, failed: j.Failed(), backupIdx: -1})
+	}
+
+	for _, d := range m.timeline.deployments {
+		target := d.Image
+		if _, rev, ok := strings.Cut(arnName(d.TaskDefinition), ":"); ok {
+			target += " (rev " + rev + ")"
+		}
+		summary := fmt.Sprintf("%s  %s  %s", d.Service, target, d.Status)
+		if !d.FinishedAt.IsZero() {
+			summary += "  " + formatElapsed(d.FinishedAt.Sub(d.CreatedAt))
+		}
+		if d.Failed() && d.StatusReason != "" {
+			summary += "  " + d.StatusReason
+		}
+		events = append(events, timelineEvent{at: d.CreatedAt, kind: eventDeploy, summary: summary, failed: d.Failed(), backupIdx: -1})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.After(events[j].at) })
+	return events
+}
+
+// updateTimeline handles key presses in the timeline view. Enter on a backup
+// opens its detail view.
+func (m *Model) updateTimeline(msg tea.KeyPressMsg) tea.Cmd {
+	events := m.timelineEvents()
+	switch msg.String() {
+	case "up", "k":
+		if m.timeline.cursor > 0 {
+			m.timeline.cursor--
+		}
+	case "down", "j":
+		if m.timeline.cursor < len(events)-1 {
+			m.timeline.cursor++
+		}
+	case "r":
+		return m.loadTimeline()
+	case "enter":
+		if m.timeline.cursor >= len(events) || events[m.timeline.cursor].backupIdx < 0 {
+			return nil
+		}
+		m.selectedIdx = events[m.timeline.cursor].backupIdx
+		m.listModel.SetCursor(m.selectedIdx)
+		return m.openDetail()
+	}
+	return nil
+}
+
+// arnName returns the last segment of an ARN (cluster name, file system ID,
+// task definition family:revision).
+func arnName(arn string) string {
+	if i := strings.LastIndex(arn, "/"); i >= 0 {
+		return arn[i+1:]
+	}
+	if i := strings.LastIndex(arn, ":"); i >= 0 {
+		return arn[i+1:]
+	}
+	return arn
+}
+
+// formatElapsed formats a job or deployment duration, e.g. "42m" or "1h05m".
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// renderTimeline renders the timeline, grouped by day.
+func (m *Model) renderTimeline() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	dayStyle := lipgloss.NewStyle().Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("248")})
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	failStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+	deployStyle := lipgloss.NewStyle().Foreground(ui.ColorWarn)
+
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("Timeline — last %d days", int(timelineWindow.Hours()/24))),
+		dimStyle.Render("● backup  ↺ restore  ⇄ copy  ◆ deploy"),
+		"",
+	}
+	if m.timeline.loading {
+		lines = append(lines, dimStyle.Render("Loading jobs and deployments..."))
+	}
+	for _, e := range m.timeline.errs {
+		lines = append(lines, failStyle.Render("Unavailable: "+e))
+	}
+
+	events := m.timelineEvents()
+	if len(events) == 0 && !m.timeline.loading {
+		lines = append(lines, dimStyle.Render("Nothing happened in this window."))
+	}
+
+	icons := map[string]string{eventBackup: "●", eventRestore: "↺", eventCopy: "⇄", eventDeploy: "◆"}
+	day := ""
+	for i, e := range events {
+		local := e.at.Local()
+		if d := local.Format("Mon 2006-01-02"); d != day {
+			if day != "" {
+				lines = append(lines, "")
+			}
+			day = d
+			lines = append(lines, dayStyle.Render(day))
+		}
+
+		line := fmt.Sprintf("%s  %s %-7s  %s", local.Format("15:04"), icons[e.kind], e.kind, e.summary)
+		style := infoStyle
+		switch {
+		case e.failed:
+			style = failStyle
+		case e.kind == eventDeploy:
+			style = deployStyle
+		}
+		if i == m.timeline.cursor {
+			lines = append(lines, focusStyle.Render("▸ "+line))
+		} else {
+			lines = append(lines, style.Render("  "+line))
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
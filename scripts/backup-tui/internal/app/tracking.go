@@ -0,0 +1,108 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements persistent job tracking: restores (and imported jobs)
+// are saved to the job history file when they start and updated when they
+// finish, and jobs still in flight from an earlier session are tracked again
+// in the jobs view on launch, so closing the terminal does not lose them.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+// resumeWindow is how old an unfinished saved job may be and still be
+// resumed. Restores take hours, not days; older entries are jobs whose end
+// was never seen and are left to "backup-tui watch".
+const resumeWindow = 3 * 24 * time.Hour
+
+// resumedJobsMsg is sent when the saved jobs from earlier sessions are loaded.
+type resumedJobsMsg struct {
+	jobs []store.TrackedJob
+	err  error
+}
+
+// trackedJob returns the job history entry for a started job.
+func (m *Model) trackedJob(j *restoreJob) store.TrackedJob {
+	t := store.TrackedJob{
+		JobID:            j.jobID,
+		Kind:             j.kind,
+		Region:           m.region,
+		Vault:            m.vaultName,
+		ResourceType:     j.backup.ResourceType,
+		ResourceID:       j.backup.ResourceID,
+		RecoveryPointARN: j.backup.RecoveryPointARN,
+		StartedAt:        j.started,
+	}
+	if !j.state.pending() && j.status != nil {
+		t.State = j.status.Status
+	}
+	return t
+}
+
+// recordJob saves a started or finished job to the job history file.
+// Saving is best effort: a failure is reported in the status bar but never
+// interrupts the restore.
+func (m *Model) recordJob(j *restoreJob) {
+	if m.historyPath == "" || j.jobID == "" {
+		return
+	}
+	if _, err := store.SaveJobs(m.historyPath, m.trackedJob(j)); err != nil {
+		m.reportError(fmt.Sprintf("%s %s is not saved for resuming: %v", j.noun(), j.jobID, err), err)
+	}
+}
+
+// loadResumableJobs returns a command that reads the job history file for
+// jobs to resume.
+func (m *Model) loadResumableJobs() tea.Cmd {
+	if m.historyPath == "" {
+		return nil
+	}
+	path := m.historyPath
+	return func() tea.Msg {
+		jobs, err := store.LoadHistory(path)
+		return resumedJobsMsg{jobs: jobs, err: err}
+	}
+}
+
+// trackableKind reports whether jobs of kind can be resumed and polled.
+func trackableKind(kind string) bool {
+	switch kind {
+	case aws.JobKindRestore, aws.JobKindBackup, aws.JobKindExport, aws.JobKindClone:
+		return true
+	}
+	return false
+}
+
+// handleResumedJobs adds the unfinished restore, backup, export, and clone
+// jobs in the job history that were started in this region recently to the jobs view
+// and polls them.
+func (m *Model) handleResumedJobs(msg resumedJobsMsg) []tea.Cmd {
+	if msg.err != nil {
+		m.reportError(fmt.Sprintf("Could not resume earlier restores: %v", msg.err), msg.err)
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	cutoff := time.Now().Add(-resumeWindow)
+	for _, t := range msg.jobs {
+		if t.Finished() || !trackableKind(t.Kind) || t.Region != m.region ||
+			t.StartedAt.Before(cutoff) || m.jobByID(t.JobID) != nil {
+			continue
+		}
+		job := m.addJob(aws.RecoveryPoint{
+			RecoveryPointARN: t.RecoveryPointARN,
+			ResourceType:     t.ResourceType,
+			ResourceID:       t.ResourceID,
+		}, nil)
+		job.kind, job.jobID, job.state, job.started, job.resumed = t.Kind, t.JobID, jobActive, t.StartedAt, true
+		cmds = append(cmds, m.pollRestoreStatus(t.JobID))
+	}
+	if len(cmds) > 0 {
+		m.inform(fmt.Sprintf("Resumed tracking %d job(s) from an earlier session — press J to view", len(cmds)))
+	}
+	return cmds
+}
@@ -0,0 +1,82 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements vault type detection: logically air-gapped vaults are
+// flagged in the header, and their restrictions (no deletion, copy-only
+// ingestion, restores using the copying plan's role) are shown before a
+// restore so operators are not surprised by what the vault refuses. Backups
+// still inside a Vault Lock's minimum retention are shown as protected.
+package app
+
+import (
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// vaultInfoMsg is sent when the current vault has been described.
+type vaultInfoMsg struct {
+	vault string
+	info  *aws.VaultInfo
+	err   error
+}
+
+// describeVault returns a command that reads the current vault's type and
+// lock settings.
+func (m *Model) describeVault() tea.Cmd {
+	vaultName := m.vaultName
+	client := m.backupClient
+	return func() tea.Msg {
+		info, err := client.DescribeVault(m.ctx, vaultName)
+		return vaultInfoMsg{vault: vaultName, info: info, err: err}
+	}
+}
+
+// handleVaultInfo records the vault's details. Failures are not fatal: the
+// vault is then treated as a standard vault, and AWS still refuses anything
+// an air-gapped vault does not allow.
+func (m *Model) handleVaultInfo(msg vaultInfoMsg) {
+	if msg.err != nil || msg.vault != m.vaultName {
+		return
+	}
+	m.vaultInfo = msg.info
+	if m.state == stateDetail {
+		m.setDetailProtection()
+	}
+}
+
+// setDetailProtection shows in the detail view whether the vault's Vault
+// Lock keeps the selected backup from being deleted yet.
+func (m *Model) setDetailProtection() {
+	if m.selectedIdx >= len(m.backups) || m.vaultInfo == nil {
+		return
+	}
+	m.detailModel.SetDeleteProtection(m.vaultInfo.ProtectedUntil(m.backups[m.selectedIdx]), m.vaultInfo.MinRetentionDays)
+}
+
+// renderVaultBadge renders the header badge for an air-gapped vault, or ""
+// for a standard vault.
+func (m *Model) renderVaultBadge() string {
+	if !m.vaultInfo.AirGapped() {
+		return ""
+	}
+	badgeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("255")).
+		Background(compat.AdaptiveColor{Light: lipgloss.Color("25"), Dark: lipgloss.Color("31")}).
+		Padding(0, 1).
+		Bold(true)
+	return badgeStyle.Render("AIR-GAPPED")
+}
+
+// vaultRestrictionLines renders the vault's restrictions for the restore
+// confirmation, or nil for a standard vault.
+func (m *Model) vaultRestrictionLines(labelStyle, style lipgloss.Style) []string {
+	restrictions := m.vaultInfo.Restrictions()
+	if len(restrictions) == 0 {
+		return nil
+	}
+	lines := []string{"", labelStyle.Render("Air-gapped Vault:")}
+	for _, r := range restrictions {
+		lines = append(lines, style.Render("  • "+r))
+	}
+	return lines
+}
@@ -0,0 +1,340 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the job tabs: tab cycles the backup list, the
+// vault's backup jobs, and the restore jobs of its backups, so in-flight
+// and failed jobs (with AWS Backup's status message) are one key press
+// from the list, whoever started them.
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// jobTab is a view tab cycles through.
+type jobTab struct {
+	state state
+	title string
+}
+
+// jobTabs are the views tab cycles through, in order.
+var jobTabs = []jobTab{
+	{stateList, "Backups"},
+	{stateBackupJobs, "Backup Jobs"},
+	{stateRestoreJobs, "Restore Jobs"},
+}
+
+// vaultJobsWindows are the windows "w" cycles through; the first is the
+// default. AWS Backup keeps job history for 30 days.
+var vaultJobsWindows = []time.Duration{7 * 24 * time.Hour, 30 * 24 * time.Hour}
+
+// vaultJobsView is the state of the job tabs.
+type vaultJobsView struct {
+	window   int             // Index into vaultJobsWindows
+	backups  []aws.JobRecord // Backup jobs into the vault, newest first
+	restores []aws.JobRecord // Restore jobs of the vault's backups, newest first
+	err      error
+	loading  bool
+	cursor   int
+}
+
+// vaultJobsMsg is sent when the vault's jobs have been listed.
+type vaultJobsMsg struct {
+	vault    string
+	backups  []aws.JobRecord
+	restores []aws.JobRecord
+	err      error
+}
+
+// tabIndex returns the index in jobTabs of the view s, or -1.
+func tabIndex(s state) int {
+	return slices.IndexFunc(jobTabs, func(t jobTab) bool { return t.state == s })
+}
+
+// switchTab moves delta tabs along jobTabs, wrapping around. The jobs are
+// loaded on leaving the list, not between the two job tabs.
+func (m *Model) switchTab(delta int) tea.Cmd {
+	i := tabIndex(m.state)
+	next := jobTabs[(i+delta+len(jobTabs))%len(jobTabs)].state
+	from := m.state
+	m.state = next
+	m.vaultJobs.cursor = 0
+	if from == stateList && next != stateList {
+		return m.loadVaultJobs()
+	}
+	return nil
+}
+
+// openVaultJobs opens a job tab and loads the jobs.
+func (m *Model) openVaultJobs(s state) tea.Cmd {
+	m.state = s
+	m.vaultJobs.cursor = 0
+	return m.loadVaultJobs()
+}
+
+// loadVaultJobs returns a command that lists the vault's backup jobs and
+// the account's restore jobs over the selected window.
+func (m *Model) loadVaultJobs() tea.Cmd {
+	if m.vaultJobs.loading {
+		return nil
+	}
+	m.vaultJobs.loading = true
+	client, ctx, vaultName := m.backupClient, m.ctx, m.vaultName
+	since := time.Now().Add(-vaultJobsWindows[m.vaultJobs.window])
+	return func() tea.Msg {
+		backups, err := client.ListBackupJobs(ctx, vaultName, since)
+		if err != nil {
+			return vaultJobsMsg{vault: vaultName, err: err}
+		}
+		restores, err := client.ListRestoreJobs(ctx, since)
+		return vaultJobsMsg{vault: vaultName, backups: backups, restores: restores, err: err}
+	}
+}
+
+// handleVaultJobs stores the listed jobs, keeping the restore jobs of the
+// vault's backups.
+func (m *Model) handleVaultJobs(msg vaultJobsMsg) {
+	v := &m.vaultJobs
+	v.loading = false
+	if msg.vault != m.vaultName {
+		return // The vault was switched while loading
+	}
+	v.err = msg.err
+	if msg.err != nil {
+		m.logError("Vault jobs not loaded", msg.err)
+		return
+	}
+	v.backups = newestFirst(msg.backups)
+	v.restores = newestFirst(vaultRestores(msg.restores, m.allBackups))
+	if n := len(m.tabJobs()); v.cursor >= n {
+		v.cursor = max(n-1, 0)
+	}
+}
+
+// vaultRestores returns the restore jobs of points, or of the resources
+// they were taken from, e.g. of a backup since expired: AWS Backup cannot
+// list restore jobs by vault.
+func vaultRestores(jobs []aws.JobRecord, points []aws.RecoveryPoint) []aws.JobRecord {
+	arns := make(map[string]bool, 2*len(points))
+	for _, rp := range points {
+		arns[rp.RecoveryPointARN] = true
+		if rp.ResourceARN != "" {
+			arns[rp.ResourceARN] = true
+		}
+	}
+	var kept []aws.JobRecord
+	for _, j := range jobs {
+		if arns[j.RecoveryPointARN] || arns[j.SourceResourceARN] {
+			kept = append(kept, j)
+		}
+	}
+	return kept
+}
+
+// newestFirst sorts jobs by creation time, newest first.
+func newestFirst(jobs []aws.JobRecord) []aws.JobRecord {
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	return jobs
+}
+
+// tabJobs returns the jobs of the open job tab.
+func (m *Model) tabJobs() []aws.JobRecord {
+	if m.state == stateRestoreJobs {
+		return m.vaultJobs.restores
+	}
+	return m.vaultJobs.backups
+}
+
+// updateVaultJobs handles key presses in the job tabs. Enter on a backup
+// job opens the backup it created; on a restore job, it tracks the job in
+// the jobs view.
+func (m *Model) updateVaultJobs(msg tea.KeyPressMsg) tea.Cmd {
+	v := &m.vaultJobs
+	jobs := m.tabJobs()
+	switch msg.String() {
+	case "up", "k":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down", "j":
+		if v.cursor < len(jobs)-1 {
+			v.cursor++
+		}
+	case "w":
+		v.window = (v.window + 1) % len(vaultJobsWindows)
+		v.cursor = 0
+		return m.loadVaultJobs()
+	case "r":
+		return m.loadVaultJobs()
+	case "enter":
+		if v.cursor >= len(jobs) {
+			return nil
+		}
+		j := jobs[v.cursor]
+		if j.Kind == aws.JobKindRestore {
+			if m.jobByID(j.JobID) != nil {
+				m.state = stateJobs
+				return nil
+			}
+			return m.trackOtherJob(j)
+		}
+		idx := m.backupIndex(j.RecoveryPointARN)
+		if idx < 0 {
+			m.notify(SeverityWarn, "That job's backup is not in the list (not created, deleted, or hidden by the filter)")
+			return nil
+		}
+		m.selectedIdx = idx
+		m.listModel.SetCursor(idx)
+		return m.openDetail()
+	}
+	return nil
+}
+
+// jobLevel returns the level of a job's state: OK when it succeeded, Fail
+// when it failed, and Warn while it runs.
+func jobLevel(j aws.JobRecord) ui.Level {
+	switch {
+	case j.Succeeded():
+		return ui.LevelOK
+	case j.Failed():
+		return ui.LevelFail
+	default:
+		return ui.LevelWarn
+	}
+}
+
+// jobMark returns the mark of a job's state, so it reads without color.
+func jobMark(j aws.JobRecord) string {
+	switch jobLevel(j) {
+	case ui.LevelOK:
+		return "✓"
+	case ui.LevelFail:
+		return "✗"
+	default:
+		return "◐"
+	}
+}
+
+// jobsSummary counts jobs by outcome, e.g. "12 completed · 1 failed · 1
+// running".
+func jobsSummary(jobs []aws.JobRecord) string {
+	var done, failed, running int
+	for _, j := range jobs {
+		switch jobLevel(j) {
+		case ui.LevelOK:
+			done++
+		case ui.LevelFail:
+			failed++
+		default:
+			running++
+		}
+	}
+	return fmt.Sprintf("%d completed · %d failed · %d running", done, failed, running)
+}
+
+// vaultJobLine describes a job after its time and mark.
+func vaultJobLine(j aws.JobRecord) string {
+	name := arnName(j.ResourceARN)
+	if j.Kind == aws.JobKindRestore {
+		name = arnName(j.SourceResourceARN) + " → " + arnName(j.ResourceARN)
+	}
+	line := fmt.Sprintf("%-9s  %-3s  %s", j.State, j.ResourceType, name)
+	switch {
+	case j.Duration() > 0:
+		line += "  " + formatElapsed(j.Duration())
+	case j.PercentDone != "":
+		line += fmt.Sprintf("  %s%%", strings.TrimSuffix(j.PercentDone, ".0"))
+	case j.CompletedAt.IsZero():
+		line += "  " + formatElapsed(time.Since(j.CreatedAt).Truncate(time.Minute)) + " so far"
+	}
+	return line
+}
+
+// renderTabs renders the tab bar above the list and the job tabs.
+func (m *Model) renderTabs() string {
+	activeStyle := lipgloss.NewStyle().Bold(true).Underline(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+
+	titles := make([]string, len(jobTabs))
+	for i, t := range jobTabs {
+		if t.state == m.state {
+			titles[i] = activeStyle.Render("▸ " + t.title)
+		} else {
+			titles[i] = dimStyle.Render("  " + t.title)
+		}
+	}
+	return strings.Join(titles, dimStyle.Render("  │")) + dimStyle.Render("   (tab)")
+}
+
+// renderVaultJobs renders the open job tab, grouped by day.
+func (m *Model) renderVaultJobs() string {
+	header := m.renderHeader()
+	v := m.vaultJobs
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("63")}).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	dayStyle := lipgloss.NewStyle().Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("248")})
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+	focusStyle := infoStyle.Bold(true).
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("62"), Dark: lipgloss.Color("141")})
+	failStyle := lipgloss.NewStyle().Foreground(ui.ColorFail)
+
+	jobs := m.tabJobs()
+	title := "Backup jobs into " + m.vaultName
+	if m.state == stateRestoreJobs {
+		title = "Restore jobs of " + m.vaultName + "'s backups"
+	}
+	lines := []string{titleStyle.Render(fmt.Sprintf("%s — last %d days", title, int(vaultJobsWindows[v.window].Hours()/24))), ""}
+	switch {
+	case v.loading && jobs == nil:
+		lines = append(lines, infoStyle.Render("Loading jobs..."))
+	case v.err != nil:
+		lines = append(lines, failStyle.Render("Unavailable: "+v.err.Error()))
+	case len(jobs) == 0:
+		lines = append(lines, dimStyle.Render("No jobs in this window."))
+	default:
+		lines = append(lines, infoStyle.Render(jobsSummary(jobs)))
+	}
+
+	var day string
+	for i, j := range jobs {
+		local := j.CreatedAt.Local()
+		if d := local.Format("Mon 2006-01-02"); d != day {
+			lines = append(lines, "", dayStyle.Render(d))
+			day = d
+		}
+
+		line := fmt.Sprintf("%s  %s %s", local.Format("15:04"), jobMark(j), vaultJobLine(j))
+		if i == v.cursor {
+			lines = append(lines, focusStyle.Render("▸ "+line))
+		} else {
+			lines = append(lines, lipgloss.NewStyle().Foreground(jobLevel(j).Color()).Render("  "+line))
+		}
+		if j.Failed() && j.StatusMessage != "" {
+			lines = append(lines, failStyle.Render("           "+j.StatusMessage))
+		}
+	}
+	if m.state == stateRestoreJobs {
+		lines = append(lines, "", dimStyle.Render("AWS Backup lists restore jobs per account; those of the vault's backups and resources are shown."))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, m.renderTabs(), boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,160 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements verifying test restores from the jobs view: V on a
+// completed restore measures the restored cluster or file system against
+// the config file's checks in the background, records the result in the
+// verification history, and shows each backup's last result in the detail
+// view.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/report"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// verifyState is the verification history and the restores being verified.
+type verifyState struct {
+	path    string                        // Verification file ("" disables saving)
+	history []store.Verification          // Oldest first
+	latest  map[string]store.Verification // Newest verification per recovery point ARN
+	running map[string]bool               // Restore job IDs being verified
+}
+
+// verificationMsg is sent when a test restore has been verified.
+type verificationMsg struct {
+	jobID        string
+	verification store.Verification
+	err          error
+}
+
+// loadVerifications reads the verification history.
+func (m *Model) loadVerifications() {
+	if m.verify.path == "" {
+		return
+	}
+	vs, err := store.LoadVerifications(m.verify.path)
+	if err != nil {
+		m.logError("Verification history not loaded", err)
+		m.verify.path = "" // Never overwrite a file that cannot be read
+		return
+	}
+	m.verify.history = vs
+	m.verify.latest = store.LatestVerifications(vs)
+}
+
+// verifySelectedJob starts verifying the restore under the cursor in the
+// jobs view, one started in this session or elsewhere.
+func (m *Model) verifySelectedJob() tea.Cmd {
+	jobID := ""
+	if job := m.jobBySeq(m.jobsCursor + 1); job != nil {
+		if job.kind == aws.JobKindRestore && job.state == jobCompleted {
+			jobID = job.jobID
+		}
+	} else if other := m.otherJobs(); m.jobsCursor-len(m.jobs) < len(other) {
+		if j := other[m.jobsCursor-len(m.jobs)]; j.Kind == aws.JobKindRestore && j.Succeeded() {
+			jobID = j.JobID
+		}
+	}
+	switch {
+	case jobID == "":
+		m.notify(SeverityWarn, "Only completed restores can be verified")
+		return nil
+	case m.verify.running[jobID]:
+		m.inform(fmt.Sprintf("Restore job %s is already being verified", jobID))
+		return nil
+	}
+	if m.verify.running == nil {
+		m.verify.running = map[string]bool{}
+	}
+	m.verify.running[jobID] = true
+	m.inform(fmt.Sprintf("Verifying restore job %s...", jobID))
+	return m.verifyRestore(jobID)
+}
+
+// verifyRestore returns a command that measures the restore job jobID and
+// judges it against the config file's checks and the resource's last
+// verified backup.
+func (m *Model) verifyRestore(jobID string) tea.Cmd {
+	client, vaultName, checks := m.backupClient, m.vaultName, m.config.VerifyChecks()
+	history := m.verify.history
+	return func() tea.Msg {
+		status, rp, err := client.LookupTestRestore(m.ctx, vaultName, jobID)
+		if err != nil {
+			return verificationMsg{jobID: jobID, err: err}
+		}
+		target := report.VerifyTarget{RecoveryPoint: rp, RestoreJobID: jobID, RestoredARN: status.CreatedResourceARN, RestoredAt: status.CompletedAt}
+		measured := client.MeasureRestore(m.ctx, aws.RestoreToMeasure{
+			ResourceType: rp.ResourceType,
+			RestoredARN:  target.RestoredARN,
+			InPlace:      target.InPlace(),
+			SecretARN:    checks.DatabaseSecret,
+			Database:     checks.Database,
+			Tables:       checks.TableNames(),
+		})
+		baseline := store.Baseline(history, rp.ResourceType, rp.ResourceID, rp.CreationDate)
+		return verificationMsg{jobID: jobID, verification: report.BuildVerification(target, measured, nil, checks, baseline, time.Now().UTC())}
+	}
+}
+
+// handleVerification records a verified test restore and reports its
+// result.
+func (m *Model) handleVerification(msg verificationMsg) {
+	delete(m.verify.running, msg.jobID)
+	if msg.err != nil {
+		m.logError(fmt.Sprintf("Restore job %s not verified", msg.jobID), msg.err)
+		return
+	}
+	v := msg.verification
+	m.verify.history = append(m.verify.history, v)
+	if m.verify.latest == nil {
+		m.verify.latest = map[string]store.Verification{}
+	}
+	m.verify.latest[v.RecoveryPointARN] = v
+	if m.verify.path != "" {
+		if err := store.SaveVerification(m.verify.path, v); err != nil {
+			m.logError("Verification not saved", err)
+		}
+	}
+
+	summary := fmt.Sprintf("Restore job %s of %s %s: %s", msg.jobID, v.ResourceType, v.ResourceID, v.Result)
+	switch v.Result {
+	case store.VerifyFailed:
+		for _, c := range v.Checks {
+			if c.Result == store.VerifyFailed {
+				summary += fmt.Sprintf(" (%s: %s)", c.Name, c.Detail)
+				break
+			}
+		}
+		m.notify(SeverityWarn, summary)
+	case store.VerifyInconclusive:
+		m.notify(SeverityWarn, summary+": no check could be run; see the verify block of the config file")
+	default:
+		m.inform(summary)
+	}
+	m.setDetailVerification()
+}
+
+// setDetailVerification shows the selected backup's last verification in
+// the detail view.
+func (m *Model) setDetailVerification() {
+	if m.selectedIdx >= len(m.backups) {
+		return
+	}
+	v, ok := m.verify.latest[m.backups[m.selectedIdx].RecoveryPointARN]
+	if !ok {
+		m.detailModel.SetVerification(nil)
+		return
+	}
+	summary := &ui.Verification{Result: v.Result, VerifiedAt: v.VerifiedAt}
+	for _, c := range v.Checks {
+		if c.Result == store.VerifyFailed {
+			summary.Failed = append(summary.Failed, c.Name+": "+c.Detail)
+		}
+	}
+	m.detailModel.SetVerification(summary)
+}
@@ -0,0 +1,170 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements view persistence: the sort order, in-app filter,
+// last active view, vault, and list columns are saved per environment (region and stack)
+// whenever they change, and restored on launch, so the TUI opens the way the
+// operator left it for each deployment.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+// Views saved as the last active tab.
+const (
+	tabList        = "list"
+	tabJobs        = "jobs"
+	tabTimeline    = "timeline"
+	tabLegalHolds  = "legal-holds"
+	tabSelections  = "selections"
+	tabActivity    = "activity"
+	tabTeam        = "team"
+	tabBackupJobs  = "backup-jobs"
+	tabRestoreJobs = "restore-jobs"
+)
+
+// sortKeys are the saved names of the sort orders.
+var sortKeys = map[sortMode]string{sortNewest: "newest", sortOldest: "oldest", sortLargest: "largest"}
+
+// viewEnv returns the environment the view state is saved under.
+func (m *Model) viewEnv() string {
+	if m.stackName != "" {
+		return m.region + "/" + m.stackName
+	}
+	return m.region + "/vault:" + m.vaultName
+}
+
+// loadView applies the view state saved for the environment. The saved tab
+// is opened once the backups have loaded. The saved vault is opened in
+// place of discovering the stack's, unless one was named with -vault.
+func (m *Model) loadView() {
+	if m.viewsPath == "" {
+		return
+	}
+	views, err := store.LoadViews(m.viewsPath)
+	if err != nil {
+		m.logError("Saved sort order and filters not restored", err)
+		m.viewsPath = "" // Never overwrite a file that cannot be read
+		return
+	}
+	v := views[m.viewEnv()]
+	for mode, key := range sortKeys {
+		if key == v.Sort {
+			m.activeSort = mode
+		}
+	}
+	m.activeFilter = filterMode(v.Filter)
+	m.pendingTab = v.Tab
+	if v.Columns != "" {
+		columns := strings.Split(v.Columns, ",")
+		if err := config.ValidateListColumns(columns); err != nil {
+			m.logError("Saved list columns not restored", err)
+		} else {
+			m.columns = columns
+		}
+	}
+	if m.vaultName == "" && m.stackName != "" && v.Vault != "" {
+		m.vaultName = v.Vault
+		m.vaultSwitch.remembered = true
+	}
+	m.savedView = v
+}
+
+// currentView returns the view state to save for the model.
+func (m *Model) currentView() store.ViewState {
+	v := store.ViewState{Sort: sortKeys[m.activeSort], Filter: string(m.activeFilter), Tab: m.savedView.Tab, Columns: strings.Join(m.columns, ",")}
+	if m.stackName != "" {
+		v.Vault = m.vaultName
+	}
+	if tab := tabOf(m.state); tab != "" {
+		v.Tab = tab
+	}
+	return v
+}
+
+// tabOf returns the saved name of the view s, or "" for states that are
+// not views of their own, e.g. dialogs.
+func tabOf(s state) string {
+	switch s {
+	case stateList:
+		return tabList
+	case stateJobs:
+		return tabJobs
+	case stateTimeline:
+		return tabTimeline
+	case stateLegalHolds:
+		return tabLegalHolds
+	case stateSelections:
+		return tabSelections
+	case stateActivity:
+		return tabActivity
+	case stateTeamActivity:
+		return tabTeam
+	case stateBackupJobs:
+		return tabBackupJobs
+	case stateRestoreJobs:
+		return tabRestoreJobs
+	}
+	return ""
+}
+
+// saveView saves the view state when it has changed. Saving is best
+// effort: a failure is logged once and retried at the next change.
+func (m *Model) saveView() {
+	// Until a stack is chosen there is no environment to save under
+	if m.viewsPath == "" || m.pendingTab != "" || m.state == stateStacks {
+		return
+	}
+	v := m.currentView()
+	if v == m.savedView {
+		return
+	}
+	m.savedView = v
+	if err := store.SaveView(m.viewsPath, m.viewEnv(), v); err != nil {
+		m.logError(fmt.Sprintf("View state for %s not saved", m.viewEnv()), err)
+	}
+}
+
+// forgetRememberedVault falls back to discovering the stack's vault when
+// the vault saved for it could not be loaded, e.g. because it was deleted,
+// and reports whether it did.
+func (m *Model) forgetRememberedVault(err error) (tea.Cmd, bool) {
+	if !m.vaultSwitch.remembered {
+		return nil, false
+	}
+	m.vaultSwitch.remembered = false
+	m.notify(SeverityWarn, fmt.Sprintf("Vault %s, last used for %s, not loaded (%v); finding the stack's vault", m.vaultName, m.stackName, err))
+	m.vaultName = ""
+	return m.discoverVault(), true
+}
+
+// openPendingTab opens the tab saved for the environment, once, after the
+// backups first load.
+func (m *Model) openPendingTab() tea.Cmd {
+	tab := m.pendingTab
+	m.pendingTab = ""
+	switch tab {
+	case tabJobs:
+		m.state = stateJobs
+		return m.loadStackJobs()
+	case tabTimeline:
+		return m.openTimeline()
+	case tabLegalHolds:
+		return m.openLegalHolds()
+	case tabSelections:
+		return m.openSelections()
+	case tabActivity:
+		return m.openActivity()
+	case tabTeam:
+		return m.openTeamActivity()
+	case tabBackupJobs:
+		return m.openVaultJobs(stateBackupJobs)
+	case tabRestoreJobs:
+		return m.openVaultJobs(stateRestoreJobs)
+	}
+	return nil
+}
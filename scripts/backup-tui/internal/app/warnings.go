@@ -0,0 +1,121 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements the warnings channel: components push messages for
+// the operator with a severity (info, warn, or critical) instead of setting
+// status bar text, and the status bar, the status endpoint, and the
+// Options.OnWarning hook (e.g. a headless runner printing them) all consume
+// the same warnings.
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// recentWarnings is how many warnings the channel keeps for the status
+// endpoint.
+const recentWarnings = 20
+
+// Severity is how much a warning needs the operator's attention.
+type Severity int
+
+const (
+	SeverityInfo     Severity = iota // Progress and confirmations, e.g. a restore started
+	SeverityWarn                     // Something was refused or did not go as asked, e.g. a job failed
+	SeverityCritical                 // An operation failed with an error
+)
+
+// String returns "info", "warn", or "critical".
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarn:
+		return "warn"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "info"
+	}
+}
+
+// MarshalJSON writes the severity as its name.
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// icon returns the status bar's mark for the severity.
+func (s Severity) icon() string {
+	switch s {
+	case SeverityWarn:
+		return "⚠ "
+	case SeverityCritical:
+		return "✗ "
+	default:
+		return ""
+	}
+}
+
+// style returns the status bar's style for the severity.
+func (s Severity) style() lipgloss.Style {
+	switch s {
+	case SeverityWarn:
+		return lipgloss.NewStyle().Foreground(ui.ColorWarn)
+	case SeverityCritical:
+		return lipgloss.NewStyle().Foreground(ui.ColorCritical).Bold(true)
+	default:
+		return lipgloss.NewStyle().Foreground(ui.ColorOK)
+	}
+}
+
+// Warning is a message for the operator.
+type Warning struct {
+	At       time.Time `json:"at"`
+	Severity Severity  `json:"severity"`
+	Message  string    `json:"message"`
+}
+
+// String formats the warning as a line of text, e.g. for headless output.
+func (w Warning) String() string {
+	return fmt.Sprintf("%s [%s] %s", w.At.Format(time.TimeOnly), w.Severity, w.Message)
+}
+
+// warnings is the model's warnings channel.
+type warnings struct {
+	current *Warning      // Shown in the status bar until cleared or replaced
+	recent  []Warning     // Oldest first
+	onPush  func(Warning) // Options.OnWarning, if set
+}
+
+// notify pushes message with severity: it replaces the status bar message
+// and is passed to the OnWarning hook.
+func (m *Model) notify(severity Severity, message string) {
+	w := Warning{At: time.Now(), Severity: severity, Message: message}
+	m.warnings.current = &w
+	m.warnings.recent = append(m.warnings.recent, w)
+	if len(m.warnings.recent) > recentWarnings {
+		m.warnings.recent = m.warnings.recent[len(m.warnings.recent)-recentWarnings:]
+	}
+	if m.warnings.onPush != nil {
+		m.warnings.onPush(w)
+	}
+}
+
+// inform pushes an info message.
+func (m *Model) inform(message string) {
+	m.notify(SeverityInfo, message)
+}
+
+// clearStatus removes the current message from the status bar.
+func (m *Model) clearStatus() {
+	m.warnings.current = nil
+}
+
+// statusMessage returns the message in the status bar, if any.
+func (m *Model) statusMessage() string {
+	if m.warnings.current == nil {
+		return ""
+	}
+	return m.warnings.current.Message
+}
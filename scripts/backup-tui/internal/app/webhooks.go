@@ -0,0 +1,97 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements webhook notifications: each time a restore, export,
+// or clone started from the TUI (including each step of a restore chain)
+// starts, completes, or fails, the transition is posted to the config file's
+// webhooks in the background.
+package app
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/webhook"
+)
+
+// webhookSentMsg is sent when a step transition has been posted.
+type webhookSentMsg struct {
+	seq   int
+	event string
+	err   error
+}
+
+// stepEvent returns the webhook event of job's transition to event.
+func (m *Model) stepEvent(j *restoreJob, event string) webhook.Event {
+	e := webhook.Event{
+		Event:      event,
+		Workflow:   j.kind,
+		WorkflowID: j.workflow,
+		Stack:      m.stackName,
+		Region:     m.region,
+		Vault:      m.vaultName,
+		Step: webhook.Step{
+			Index:            1,
+			Total:            1,
+			Kind:             j.kind,
+			ResourceType:     j.backup.ResourceType,
+			ResourceID:       j.backup.ResourceID,
+			RecoveryPointARN: j.backup.RecoveryPointARN,
+			JobID:            j.jobID,
+			State:            stepState(j),
+		},
+	}
+	if j.state == jobFailed {
+		e.Step.Message = j.note
+	}
+	if j.workflow != "" {
+		e.Workflow = store.WorkflowRestoreChain
+		e.Step.Total = 0
+		for _, other := range m.jobs {
+			if other.workflow != j.workflow {
+				continue
+			}
+			e.Step.Total++
+			if other == j {
+				e.Step.Index = e.Step.Total
+			}
+		}
+	}
+	return e
+}
+
+// notifyStep returns a command posting job's transition to event to the
+// webhooks and recording it in the team journal, or nil when neither is
+// configured.
+func (m *Model) notifyStep(j *restoreJob, event string) tea.Cmd {
+	return tea.Batch(m.postStep(j, event), m.journalStep(j, event))
+}
+
+// postStep returns a command posting job's transition to event to the
+// webhooks, or nil when none are configured.
+func (m *Model) postStep(j *restoreJob, event string) tea.Cmd {
+	if m.notifier == nil {
+		return nil
+	}
+	notifier, ctx, e, seq := m.notifier, m.ctx, m.stepEvent(j, event), j.seq
+	return func() tea.Msg {
+		return webhookSentMsg{seq: seq, event: event, err: notifier.Send(ctx, e)}
+	}
+}
+
+// notifyFinished returns a command posting a finished job as completed or
+// failed.
+func (m *Model) notifyFinished(j *restoreJob) tea.Cmd {
+	if j.state == jobCompleted {
+		return m.notifyStep(j, config.EventCompleted)
+	}
+	return m.notifyStep(j, config.EventFailed)
+}
+
+// handleWebhookSent reports an event the webhooks did not receive. The
+// workflow itself carries on.
+func (m *Model) handleWebhookSent(msg webhookSentMsg) {
+	if msg.err != nil {
+		m.reportError(fmt.Sprintf("Webhook not notified that #%d %s: %v", msg.seq, msg.event, msg.err), msg.err)
+	}
+}
@@ -0,0 +1,323 @@
+// Package app provides the main application model and business logic for the backup TUI.
+// This file implements resumable restore chains: a chain is saved to the
+// workflow file as its steps are queued, start, and finish, and a chain
+// interrupted by a closed terminal or a crash is offered for resuming on the
+// next launch, tracking started steps again by their job IDs and starting
+// the next queued step once the step before it has completed.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+)
+
+// resumePrompt holds the restore chains from earlier sessions not yet
+// resumed or discarded.
+type resumePrompt struct {
+	pending []store.Workflow
+}
+
+// workflowsLoadedMsg is sent when the saved workflows are loaded.
+type workflowsLoadedMsg struct {
+	workflows []store.Workflow
+	err       error
+}
+
+// loadWorkflows returns a command that reads the workflow file for restore
+// chains to resume.
+func (m *Model) loadWorkflows() tea.Cmd {
+	if m.workflowsPath == "" {
+		return nil
+	}
+	path := m.workflowsPath
+	return func() tea.Msg {
+		workflows, err := store.LoadWorkflows(path)
+		return workflowsLoadedMsg{workflows: workflows, err: err}
+	}
+}
+
+// handleWorkflowsLoaded keeps the interrupted restore chains of this region
+// and offers the first one for resuming once the backup list is shown.
+func (m *Model) handleWorkflowsLoaded(msg workflowsLoadedMsg) {
+	if msg.err != nil {
+		m.reportError(fmt.Sprintf("Could not read interrupted restore chains: %v", msg.err), msg.err)
+		return
+	}
+	now := time.Now()
+	for _, w := range msg.workflows {
+		if w.Kind == store.WorkflowRestoreChain && w.Region == m.region && w.Resumable(now, resumeWindow) {
+			m.resume.pending = append(m.resume.pending, w)
+		}
+	}
+	if m.state == stateList {
+		m.offerResume()
+	}
+}
+
+// resumable returns the first pending chain of the current vault, or nil.
+// Chains of other vaults are offered when their vault is opened.
+func (m *Model) resumable() *store.Workflow {
+	for i, w := range m.resume.pending {
+		if w.Vault == m.vaultName {
+			return &m.resume.pending[i]
+		}
+	}
+	return nil
+}
+
+// offerResume opens the resume prompt if a chain of the current vault is
+// waiting to be resumed.
+func (m *Model) offerResume() {
+	if m.resumable() != nil {
+		m.state = stateResume
+	}
+}
+
+// dropResumable removes the chain with the given ID from the pending ones.
+func (m *Model) dropResumable(id string) {
+	kept := m.resume.pending[:0]
+	for _, w := range m.resume.pending {
+		if w.ID != id {
+			kept = append(kept, w)
+		}
+	}
+	m.resume.pending = kept
+}
+
+// updateResume handles key presses in the resume prompt: y resumes the
+// chain, n discards it, and esc leaves it to be offered on the next launch.
+func (m *Model) updateResume(msg tea.KeyPressMsg) []tea.Cmd {
+	w := m.resumable()
+	if w == nil {
+		m.state = stateList
+		return nil
+	}
+	var cmds []tea.Cmd
+	switch msg.String() {
+	case "y", "Y":
+		chain := *w
+		m.dropResumable(chain.ID)
+		cmds = m.resumeWorkflow(chain)
+	case "n", "N":
+		chain := *w
+		m.dropResumable(chain.ID)
+		chain.Closed = true
+		if err := store.SaveWorkflow(m.workflowsPath, chain); err != nil {
+			m.reportError(fmt.Sprintf("Could not discard the interrupted restore chain: %v", err), err)
+		} else {
+			m.inform("Discarded the interrupted restore chain")
+		}
+		m.state = stateList
+	case "esc", "q":
+		m.dropResumable(w.ID)
+		m.inform("Interrupted restore chain kept; it is offered again on the next launch")
+		m.state = stateList
+	case "ctrl+c":
+		return []tea.Cmd{tea.Quit}
+	default:
+		return nil
+	}
+	// Offer the next chain of the vault, if any
+	if m.state == stateList {
+		m.offerResume()
+	}
+	return cmds
+}
+
+// resumeWorkflow rebuilds an interrupted chain in the jobs view. Started
+// steps keep their job IDs and unfinished ones are polled again; queued
+// steps start once the step before them has completed, as they would have.
+func (m *Model) resumeWorkflow(w store.Workflow) []tea.Cmd {
+	var (
+		cmds  []tea.Cmd
+		chain []*restoreJob
+		prev  *restoreJob
+	)
+	for _, step := range w.Steps {
+		job := m.jobByID(step.JobID)
+		if job == nil {
+			job = m.addJob(aws.RecoveryPoint{
+				RecoveryPointARN: step.RecoveryPointARN,
+				ResourceType:     step.ResourceType,
+				ResourceID:       step.ResourceID,
+			}, prev)
+			job.kind = step.Kind
+			job.options = aws.RestoreOptions{KMSKeyID: step.KMSKeyID, SubnetGroup: step.SubnetGroup, SecurityGroupIDs: step.SecurityGroupIDs, CopyTags: step.CopyTags, ClusterSuffix: step.ClusterSuffix, ItemsToRestore: step.ItemsToRestore}
+			job.jobID, job.started, job.resumed = step.JobID, step.StartedAt, step.Started()
+			switch {
+			case step.Completed():
+				job.state = jobCompleted
+			case step.State == store.StepCancelled:
+				job.state, job.note = jobCancelled, "cancelled before start"
+			case step.State == store.StepSkipped:
+				job.state, job.note = jobSkipped, "not started in an earlier session"
+			case step.Finished():
+				job.state, job.note = jobFailed, step.State
+			case step.Started():
+				job.state = jobActive
+				cmds = append(cmds, m.pollRestoreStatus(step.JobID))
+			default:
+				job.state = jobQueued
+			}
+		}
+		job.after, job.workflow = prev, w.ID
+		chain = append(chain, job)
+		prev = job
+	}
+
+	for _, job := range chain {
+		if job.state != jobQueued {
+			continue
+		}
+		switch {
+		case job.after == nil:
+			// Interrupted while starting; whether it started is unknown,
+			// so it is started again
+			if f := m.activeFreeze(); f != nil {
+				job.state, job.note = jobSkipped, "change freeze "+f.String()
+				cmds = append(cmds, m.advanceChain(job)...)
+				continue
+			}
+			job.state = jobStarting
+			cmds = append(cmds, m.initiateRestore(job))
+		case !job.after.state.pending():
+			cmds = append(cmds, m.advanceChain(job.after)...)
+		}
+	}
+	if len(chain) > 0 {
+		m.recordWorkflow(chain[0])
+	}
+
+	m.jobsCursor = len(m.jobs) - 1
+	m.state = stateJobs
+	m.inform(fmt.Sprintf("Resumed restore chain with %d unfinished step(s)", w.Unfinished()))
+	return cmds
+}
+
+// chainWorkflow puts job in the saved workflow of the step it is chained
+// after, starting a workflow for the chain if it has none yet.
+func (m *Model) chainWorkflow(job *restoreJob) {
+	if job.after.workflow == "" {
+		id := store.NewWorkflowID()
+		for p := job.after; p != nil; p = p.after {
+			p.workflow = id
+		}
+	}
+	job.workflow = job.after.workflow
+	m.recordWorkflow(job)
+}
+
+// stepState is the saved state of a job in a workflow.
+func stepState(j *restoreJob) string {
+	switch j.state {
+	case jobActive:
+		if j.status != nil && j.status.Status != "" {
+			return j.status.Status
+		}
+		return "RUNNING"
+	case jobCompleted:
+		return "COMPLETED"
+	case jobFailed:
+		if j.status != nil && j.status.IsTerminal {
+			return j.status.Status
+		}
+		return "FAILED"
+	case jobCancelled:
+		return store.StepCancelled
+	case jobSkipped:
+		return store.StepSkipped
+	}
+	return store.StepPending
+}
+
+// recordWorkflow saves the chain j belongs to, closing it once every step
+// has finished. Like recordJob, saving is best effort.
+func (m *Model) recordWorkflow(j *restoreJob) {
+	if m.workflowsPath == "" || j.workflow == "" {
+		return
+	}
+	w := store.Workflow{ID: j.workflow, Kind: store.WorkflowRestoreChain, Region: m.region, Vault: m.vaultName, Closed: true}
+	for _, job := range m.jobs {
+		if job.workflow != j.workflow {
+			continue
+		}
+		if w.StartedAt.IsZero() || (!job.started.IsZero() && job.started.Before(w.StartedAt)) {
+			w.StartedAt = job.started
+		}
+		step := store.WorkflowStep{
+			Kind:             job.kind,
+			ResourceType:     job.backup.ResourceType,
+			ResourceID:       job.backup.ResourceID,
+			RecoveryPointARN: job.backup.RecoveryPointARN,
+			KMSKeyID:         job.options.KMSKeyID,
+			SubnetGroup:      job.options.SubnetGroup,
+			SecurityGroupIDs: job.options.SecurityGroupIDs,
+			CopyTags:         job.options.CopyTags,
+			ClusterSuffix:    job.options.ClusterSuffix,
+			ItemsToRestore:   job.options.ItemsToRestore,
+			JobID:            job.jobID,
+			StartedAt:        job.started,
+			State:            stepState(job),
+		}
+		if !step.Finished() {
+			w.Closed = false
+		}
+		w.Steps = append(w.Steps, step)
+	}
+	if w.StartedAt.IsZero() {
+		w.StartedAt = time.Now()
+	}
+	if err := store.SaveWorkflow(m.workflowsPath, w); err != nil {
+		m.reportError(fmt.Sprintf("Restore chain is not saved for resuming: %v", err), err)
+	}
+}
+
+// renderResume renders the prompt for resuming a restore chain interrupted
+// in an earlier session.
+func (m *Model) renderResume() string {
+	header := m.renderHeader()
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorWarn).
+		Padding(1, 2).
+		MarginTop(1)
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	infoStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("240"), Dark: lipgloss.Color("252")})
+	dimStyle := lipgloss.NewStyle().
+		Foreground(compat.AdaptiveColor{Light: lipgloss.Color("245"), Dark: lipgloss.Color("242")})
+
+	w := m.resumable()
+	if w == nil {
+		return header
+	}
+	lines := []string{
+		titleStyle.Render("Resume Interrupted Restore Chain"),
+		"",
+		infoStyle.Render(fmt.Sprintf("A restore chain in vault %s was interrupted, last saved %s.",
+			w.Vault, w.UpdatedAt.Local().Format("2006-01-02 15:04"))),
+		"",
+	}
+	for i, step := range w.Steps {
+		line := fmt.Sprintf("  #%d  %-3s  %-30s  %s", i+1, step.ResourceType, step.ResourceID, step.State)
+		if step.JobID != "" {
+			line += dimStyle.Render("  job " + step.JobID)
+		}
+		lines = append(lines, infoStyle.Render(line))
+	}
+	lines = append(lines, "",
+		dimStyle.Render("Resuming tracks started steps again by their job IDs and starts the next"),
+		dimStyle.Render("queued step once the step before it has completed. Discarding leaves"),
+		dimStyle.Render("running jobs alone but does not start the queued steps."),
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
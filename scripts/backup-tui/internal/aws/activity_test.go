@@ -0,0 +1,124 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+)
+
+type mockCloudTrail struct {
+	err error
+}
+
+func (m *mockCloudTrail) LookupEvents(_ context.Context, _ *cloudtrail.LookupEventsInput, _ ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+	return nil, m.err
+}
+
+func TestVaultActivity(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	vault := fx.Vaults[0]
+	fx.RecoveryPoints[vault] = append(fx.RecoveryPoints[vault], FixtureRecoveryPoint{
+		RecoveryPointARN: "arn:aws:backup:us-west-2:123456789012:recovery-point:copied-in",
+		ResourceARN:      "arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-0sim0001",
+		ResourceType:     "EFS",
+		Status:           "COMPLETED",
+		AgeHours:         1,
+		SourceVaultARN:   "arn:aws:backup:us-east-1:123456789012:backup-vault:dr-vault",
+	})
+	fx.TrailEvents = append(fx.TrailEvents,
+		FixtureTrailEvent{EventName: "DeleteRecoveryPoint", Username: "old", Vault: vault, RecoveryPointARN: "too-old", AgeHours: 48},
+		FixtureTrailEvent{EventName: "DeleteRecoveryPoint", Username: "elsewhere", Vault: "other-vault", RecoveryPointARN: "other", AgeHours: 2},
+		FixtureTrailEvent{EventName: "UpdateRecoveryPointLifecycle", Username: "ops", Vault: vault, RecoveryPointARN: "lifecycle", AgeHours: 2},
+	)
+	c := NewSimulatedBackupClient(fx)
+
+	a, err := c.VaultActivity(context.Background(), vault, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.DeletionsErr != nil {
+		t.Fatalf("unexpected deletions error: %v", a.DeletionsErr)
+	}
+	kinds := map[string]int{}
+	for _, e := range a.Events {
+		kinds[e.Kind]++
+	}
+	// sim-rds-0001 and sim-efs-0001 are 3 and 4 hours old; the rest are older
+	if kinds[ActivityCreated] != 2 || kinds[ActivityCopiedIn] != 1 || kinds[ActivityDeleted] != 1 {
+		t.Errorf("kinds = %v, want 2 created, 1 copied in, 1 deleted (events %+v)", kinds, a.Events)
+	}
+	for i := 1; i < len(a.Events); i++ {
+		if a.Events[i].At.After(a.Events[i-1].At) {
+			t.Errorf("events not newest first: %v after %v", a.Events[i].At, a.Events[i-1].At)
+		}
+	}
+	first := a.Events[0]
+	if first.Kind != ActivityCopiedIn || first.Actor != "arn:aws:backup:us-east-1:123456789012:backup-vault:dr-vault" || first.ResourceID != "fs-0sim0001" {
+		t.Errorf("newest event = %+v, want the copy from dr-vault", first)
+	}
+	for _, e := range a.Events {
+		if e.Kind == ActivityDeleted && (e.Actor != "dba-oncall" || e.ErrorCode != "") {
+			t.Errorf("deletion = %+v, want a successful one by dba-oncall", e)
+		}
+		if e.Kind == ActivityCreated && e.Actor == "" {
+			t.Errorf("scheduled backup %s should name its plan", e.RecoveryPointARN)
+		}
+	}
+}
+
+func TestVaultActivity_RecordsPrunes(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	vault := fx.Vaults[0]
+	fx.VaultLocks = map[string]FixtureVaultLock{vault: {MinRetentionDays: 2}}
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	points, _ := c.ListRecoveryPoints(ctx, vault, "")
+
+	_ = c.DeleteRecoveryPoint(ctx, vault, points[0].RecoveryPointARN)             // 3 hours old: refused by the lock
+	_ = c.DeleteRecoveryPoint(ctx, vault, points[len(points)-1].RecoveryPointARN) // 51 hours old
+
+	a, err := c.VaultActivity(ctx, vault, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	refused, deleted := 0, 0
+	for _, e := range a.Events {
+		switch {
+		case e.Kind != ActivityDeleted:
+		case e.ErrorCode == "InvalidRequestException":
+			refused++
+		case e.ErrorCode == "":
+			deleted++
+		}
+	}
+	if refused != 1 || deleted != 1 {
+		t.Errorf("refused = %d, deleted = %d, want 1 each (events %+v)", refused, deleted, a.Events)
+	}
+}
+
+func TestVaultActivity_CloudTrailUnavailable(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	c.trail = &mockCloudTrail{err: errors.New("AccessDeniedException")}
+
+	a, err := c.VaultActivity(context.Background(), fx.Vaults[0], time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("CloudTrail errors should not fail the activity: %v", err)
+	}
+	if a.DeletionsErr == nil {
+		t.Error("DeletionsErr should be set")
+	}
+	if len(a.Events) != 2 {
+		t.Errorf("got %d events, want the 2 new recovery points", len(a.Events))
+	}
+}
+
+func TestVaultActivity_EmptyVault(t *testing.T) {
+	c := &BackupClient{}
+	if _, err := c.VaultActivity(context.Background(), "", time.Now()); err == nil {
+		t.Error("expected an error for an empty vault name")
+	}
+}
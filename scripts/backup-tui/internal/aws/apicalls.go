@@ -0,0 +1,147 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the session's API call counter: like the rate
+// limiter, it is attached to every SDK client through the middleware stack
+// and counts each service's calls, retries, throttles, and errors, so slow
+// screens and quota pressure can be diagnosed from the TUI.
+package aws
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// ServiceCalls counts the API calls made to one AWS service.
+type ServiceCalls struct {
+	Service   string // SDK service ID, e.g. "Backup"
+	Calls     int    // Operations called, however many attempts each took
+	Retries   int    // Attempts after an operation's first
+	Throttles int    // Attempts AWS throttled
+	Errors    int    // Operations that failed after their last attempt
+}
+
+// APIUsage is the API calls made since the counter was created.
+type APIUsage struct {
+	Since    time.Time
+	Services []ServiceCalls // Most called first
+}
+
+// Total sums the calls to every service.
+func (u APIUsage) Total() ServiceCalls {
+	total := ServiceCalls{Service: "Total"}
+	for _, s := range u.Services {
+		total.Calls += s.Calls
+		total.Retries += s.Retries
+		total.Throttles += s.Throttles
+		total.Errors += s.Errors
+	}
+	return total
+}
+
+// APICounter counts API calls per service. It is safe for concurrent use
+// and is shared by all clients created from the same config.
+type APICounter struct {
+	mu       sync.Mutex
+	since    time.Time
+	services map[string]*ServiceCalls
+}
+
+// NewAPICounter creates an APICounter with no calls counted.
+func NewAPICounter() *APICounter {
+	return &APICounter{since: time.Now(), services: make(map[string]*ServiceCalls)}
+}
+
+// Usage returns a snapshot of the calls counted so far.
+func (c *APICounter) Usage() APIUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	u := APIUsage{Since: c.since}
+	for _, s := range c.services {
+		u.Services = append(u.Services, *s)
+	}
+	sort.Slice(u.Services, func(i, j int) bool {
+		if u.Services[i].Calls != u.Services[j].Calls {
+			return u.Services[i].Calls > u.Services[j].Calls
+		}
+		return u.Services[i].Service < u.Services[j].Service
+	})
+	return u
+}
+
+// update applies fn to the counts of service.
+func (c *APICounter) update(service string, fn func(*ServiceCalls)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.services[service]
+	if !ok {
+		s = &ServiceCalls{Service: service}
+		c.services[service] = s
+	}
+	fn(s)
+}
+
+// Middleware IDs of the counter in the SDK middleware stack.
+const (
+	apiCallsMiddlewareID    = "BackupTUIAPICalls"
+	apiAttemptsMiddlewareID = "BackupTUIAPIAttempts"
+)
+
+// attemptsKey holds the attempts made so far by an operation.
+type attemptsKey struct{}
+
+// AddToStack registers the counter on an SDK middleware stack: once in the
+// initialize step for each operation, and once after the retry middleware
+// for each of its attempts.
+func (c *APICounter) AddToStack(stack *middleware.Stack) error {
+	calls := middleware.InitializeMiddlewareFunc(apiCallsMiddlewareID, func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		service := awsmiddleware.GetServiceID(ctx)
+		c.update(service, func(s *ServiceCalls) { s.Calls++ })
+		out, md, err := next.HandleInitialize(middleware.WithStackValue(ctx, attemptsKey{}, new(int)), in)
+		if err != nil {
+			c.update(service, func(s *ServiceCalls) { s.Errors++ })
+		}
+		return out, md, err
+	})
+	if err := stack.Initialize.Add(calls, middleware.After); err != nil {
+		return err
+	}
+
+	attempts := middleware.FinalizeMiddlewareFunc(apiAttemptsMiddlewareID, func(
+		ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+	) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		service := awsmiddleware.GetServiceID(ctx)
+		if n, ok := middleware.GetStackValue(ctx, attemptsKey{}).(*int); ok {
+			if *n++; *n > 1 {
+				c.update(service, func(s *ServiceCalls) { s.Retries++ })
+			}
+		}
+		out, md, err := next.HandleFinalize(ctx, in)
+		if err != nil && isThrottle(err) {
+			c.update(service, func(s *ServiceCalls) { s.Throttles++ })
+		}
+		return out, md, err
+	})
+	if _, ok := stack.Finalize.Get("Retry"); ok {
+		return stack.Finalize.Insert(attempts, "Retry", middleware.After)
+	}
+	return stack.Finalize.Add(attempts, middleware.After)
+}
+
+// defaultAPICounter counts the calls of every client created via
+// loadAWSConfig, like defaultRateLimiter.
+var defaultAPICounter = NewAPICounter()
+
+// APIUsage returns the API calls made by the process's AWS clients. A
+// simulated client makes no API calls and reports none.
+func (c *BackupClient) APIUsage() APIUsage {
+	if c.simulated {
+		return APIUsage{}
+	}
+	return defaultAPICounter.Usage()
+}
@@ -0,0 +1,86 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// runCountedOperation runs an operation through a stack with c attached and
+// a stand-in retry middleware that retries once after a failed attempt.
+// Each attempt returns the next of errs.
+func runCountedOperation(t *testing.T, c *APICounter, service string, errs ...error) error {
+	t.Helper()
+	stack := middleware.NewStack("test", func() interface{} { return nil })
+	retry := middleware.FinalizeMiddlewareFunc("Retry", func(
+		ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+	) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		out, md, err := next.HandleFinalize(ctx, in)
+		if err != nil {
+			out, md, err = next.HandleFinalize(ctx, in)
+		}
+		return out, md, err
+	})
+	if err := stack.Finalize.Add(retry, middleware.After); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddToStack(stack); err != nil {
+		t.Fatalf("AddToStack: %v", err)
+	}
+
+	attempt := 0
+	handler := middleware.HandlerFunc(func(context.Context, interface{}) (interface{}, middleware.Metadata, error) {
+		var err error
+		if attempt < len(errs) {
+			err = errs[attempt]
+		}
+		attempt++
+		return nil, middleware.Metadata{}, err
+	})
+	ctx := awsmiddleware.SetServiceID(context.Background(), service)
+	_, _, err := middleware.DecorateHandler(handler, stack).Handle(ctx, nil)
+	return err
+}
+
+func TestAPICounter(t *testing.T) {
+	c := NewAPICounter()
+	throttle := &smithy.GenericAPIError{Code: "ThrottlingException"}
+
+	if err := runCountedOperation(t, c, "Backup"); err != nil {
+		t.Fatal(err)
+	}
+	if err := runCountedOperation(t, c, "Backup", throttle); err != nil {
+		t.Fatal(err) // Retried and succeeded
+	}
+	if err := runCountedOperation(t, c, "RDS", errors.New("boom"), errors.New("boom")); err == nil {
+		t.Fatal("expected the second attempt's error")
+	}
+
+	u := c.Usage()
+	want := []ServiceCalls{
+		{Service: "Backup", Calls: 2, Retries: 1, Throttles: 1},
+		{Service: "RDS", Calls: 1, Retries: 1, Errors: 1},
+	}
+	if len(u.Services) != len(want) {
+		t.Fatalf("got %+v", u.Services)
+	}
+	for i := range want {
+		if u.Services[i] != want[i] {
+			t.Errorf("service %d = %+v, want %+v", i, u.Services[i], want[i])
+		}
+	}
+	if total := u.Total(); total.Calls != 3 || total.Retries != 2 || total.Throttles != 1 || total.Errors != 1 {
+		t.Errorf("unexpected total %+v", total)
+	}
+}
+
+func TestSimulatedClient_APIUsage(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	if u := NewSimulatedBackupClient(fx).APIUsage(); len(u.Services) != 0 {
+		t.Errorf("a simulated client makes no API calls, got %+v", u)
+	}
+}
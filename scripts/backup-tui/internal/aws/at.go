@@ -0,0 +1,60 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements recovery point selection by moment: incident
+// timelines say "the database was fine at 03:00", so for each resource the
+// restorable recovery point closest to, but not after, that moment is the
+// one to restore.
+package aws
+
+import (
+	"fmt"
+	"time"
+)
+
+// momentLayouts are the layouts ParseMoment accepts, tried in order.
+// Layouts without a zone are read as UTC.
+var momentLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseMoment parses a moment of an incident timeline, e.g.
+// "2026-01-15T03:00Z", "2026-01-15T03:00:00-05:00", or "2026-01-15 03:00"
+// (UTC).
+func ParseMoment(s string) (time.Time, error) {
+	for _, layout := range momentLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid moment %q: expected e.g. 2026-01-15T03:00Z or 2026-01-15 03:00 (UTC)", s)
+}
+
+// RestorableAt returns, for each resource with recovery points in points,
+// its restorable recovery point closest to but not after at, by the rules
+// of LatestRestorable: Skipped counts the points between it and at that
+// are not restorable. Resources backed up only after at have none.
+func RestorableAt(points []RecoveryPoint, restores []JobRecord, now, at time.Time) []LatestPoint {
+	var before []RecoveryPoint
+	later := make(map[string]RecoveryPoint)
+	for _, rp := range points {
+		if rp.CreationDate.After(at) {
+			later[rp.ResourceARN] = rp
+			continue
+		}
+		before = append(before, rp)
+	}
+	latest := LatestRestorable(before, restores, now)
+	// Keep the resources without a point before at, with none found
+	for _, p := range latest {
+		delete(later, p.ResourceARN)
+	}
+	for _, rp := range later {
+		latest = append(latest, LatestPoint{ResourceType: rp.ResourceType, ResourceID: rp.ResourceID, ResourceARN: rp.ResourceARN})
+	}
+	return sortLatest(latest)
+}
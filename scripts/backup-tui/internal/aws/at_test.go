@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMoment(t *testing.T) {
+	want := time.Date(2026, 1, 15, 3, 0, 0, 0, time.UTC)
+	for _, s := range []string{"2026-01-15T03:00Z", "2026-01-15T03:00:00Z", "2026-01-15T04:00+01:00", "2026-01-15 03:00", "2026-01-15T03:00"} {
+		got, err := ParseMoment(s)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseMoment(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if got, err := ParseMoment("2026-01-15"); err != nil || !got.Equal(want.Add(-3*time.Hour)) {
+		t.Errorf("a date is its midnight UTC, got %v, %v", got, err)
+	}
+	for _, s := range []string{"", "yesterday", "15/01/2026 03:00"} {
+		if _, err := ParseMoment(s); err == nil {
+			t.Errorf("ParseMoment(%q) should fail", s)
+		}
+	}
+}
+
+func TestRestorableAt(t *testing.T) {
+	now := time.Date(2026, 1, 16, 12, 0, 0, 0, time.UTC)
+	at := time.Date(2026, 1, 15, 3, 0, 0, 0, time.UTC)
+	points := []RecoveryPoint{
+		{RecoveryPointARN: "db-1", ResourceARN: "arn:db", ResourceType: "RDS", ResourceID: "db", Status: "COMPLETED", CreationDate: at.Add(-25 * time.Hour)},
+		{RecoveryPointARN: "db-2", ResourceARN: "arn:db", ResourceType: "RDS", ResourceID: "db", Status: "COMPLETED", CreationDate: at.Add(-time.Hour)},
+		{RecoveryPointARN: "db-3", ResourceARN: "arn:db", ResourceType: "RDS", ResourceID: "db", Status: "PARTIAL", CreationDate: at.Add(-time.Minute)},
+		{RecoveryPointARN: "db-4", ResourceARN: "arn:db", ResourceType: "RDS", ResourceID: "db", Status: "COMPLETED", CreationDate: at.Add(time.Minute)},
+		{RecoveryPointARN: "fs-1", ResourceARN: "arn:fs", ResourceType: "EFS", ResourceID: "fs", Status: "COMPLETED", CreationDate: at},
+		{RecoveryPointARN: "new-1", ResourceARN: "arn:new", ResourceType: "EFS", ResourceID: "new", Status: "COMPLETED", CreationDate: at.Add(time.Hour)},
+	}
+
+	got := RestorableAt(points, nil, now, at)
+	if len(got) != 3 {
+		t.Fatalf("expected one entry per resource, got %+v", got)
+	}
+	if fs := got[0]; fs.ResourceID != "fs" || fs.RecoveryPointARN != "fs-1" {
+		t.Errorf("a point taken exactly at the moment is not after it: %+v", fs)
+	}
+	if n := got[1]; n.ResourceID != "new" || n.Found() {
+		t.Errorf("a resource backed up only after the moment has no point: %+v", n)
+	}
+	if db := got[2]; db.RecoveryPointARN != "db-2" || db.Skipped != 1 {
+		t.Errorf("the closest completed point before the moment should be picked, skipping the partial one: %+v", db)
+	}
+}
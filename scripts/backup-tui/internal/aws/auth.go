@@ -0,0 +1,271 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the auth providers: where the AWS credentials come
+// from (the SDK's default chain, environment variables, a shared config
+// profile, IAM Identity Center, or an EKS web identity token) and, when
+// they cannot be obtained, an AuthError that says how to fix that provider.
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+)
+
+// AuthProvider selects where the AWS credentials come from.
+type AuthProvider string
+
+// Auth providers.
+const (
+	AuthAuto        AuthProvider = "auto"         // The SDK's default credential chain
+	AuthEnv         AuthProvider = "env"          // AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	AuthProfile     AuthProvider = "profile"      // A shared config profile (AWS_PROFILE, or default)
+	AuthSSO         AuthProvider = "sso"          // A shared config profile signed in with IAM Identity Center
+	AuthWebIdentity AuthProvider = "web-identity" // AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN, e.g. EKS IRSA
+)
+
+// AuthOptions selects the AWS credentials: their provider and, for the
+// profile and SSO providers, the shared config profile.
+type AuthOptions struct {
+	Provider AuthProvider // "" for AuthAuto
+	Profile  string       // Shared config profile; "" for AWS_PROFILE, or default
+}
+
+// AuthProviders lists the auth providers in the order they are documented.
+var AuthProviders = []AuthProvider{AuthAuto, AuthEnv, AuthProfile, AuthSSO, AuthWebIdentity}
+
+// ParseAuthProvider parses an -auth value. Empty is AuthAuto.
+func ParseAuthProvider(s string) (AuthProvider, error) {
+	if s == "" {
+		return AuthAuto, nil
+	}
+	names := make([]string, len(AuthProviders))
+	for i, p := range AuthProviders {
+		if strings.EqualFold(s, string(p)) {
+			return p, nil
+		}
+		names[i] = string(p)
+	}
+	return "", fmt.Errorf("unknown auth provider %q (want %s)", s, strings.Join(names, ", "))
+}
+
+// AuthError is a failure to obtain or use the credentials of an auth
+// provider. Hint says how to fix it.
+type AuthError struct {
+	Provider AuthProvider
+	Profile  string // Shared config profile, for AuthProfile and AuthSSO
+	Err      error
+
+	retrieving bool // Raised retrieving or using the credentials, not loading the config
+}
+
+// Error implements the error interface.
+func (e *AuthError) Error() string {
+	if e.Profile != "" {
+		return fmt.Sprintf("%s credentials (profile %s): %v", e.Provider, e.Profile, e.Err)
+	}
+	return fmt.Sprintf("%s credentials: %v", e.Provider, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// NeedsSSOLogin reports whether signing in to IAM Identity Center again
+// ('aws sso login') may fix the error: the profile is set up for SSO, but
+// its credentials could not be retrieved or were rejected, e.g. because the
+// cached SSO token is missing or expired.
+func (e *AuthError) NeedsSSOLogin() bool {
+	return e.Provider == AuthSSO && e.retrieving
+}
+
+// Hint returns guidance on configuring the provider's credentials.
+func (e *AuthError) Hint() string {
+	switch e.Provider {
+	case AuthEnv:
+		return "Set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (and AWS_SESSION_TOKEN for temporary\n" +
+			"credentials), or choose another provider with -auth."
+	case AuthProfile:
+		return fmt.Sprintf("Check profile %q in ~/.aws/config and ~/.aws/credentials\n"+
+			"(run 'aws configure --profile %s'), or choose another profile with -profile.", e.Profile, e.Profile)
+	case AuthSSO:
+		return fmt.Sprintf("Sign in with 'aws sso login --profile %s'; the cached SSO token may have expired.\n"+
+			"The profile needs sso_session (or sso_start_url), sso_account_id, and sso_role_name\n"+
+			"(run 'aws configure sso' to set them up).", e.Profile)
+	case AuthWebIdentity:
+		return "Set AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN. On EKS (IRSA), annotate the pod's\n" +
+			"service account with eks.amazonaws.com/role-arn so they are injected, and check the\n" +
+			"role's trust policy allows the cluster's OIDC provider and service account."
+	}
+	return "AWS credentials are required to use this application.\n" +
+		"Configure AWS credentials using one of:\n" +
+		"  - Environment variables: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (-auth env)\n" +
+		"  - AWS credentials file: ~/.aws/credentials (run 'aws configure'; -auth profile)\n" +
+		"  - IAM Identity Center: run 'aws sso login' (-auth sso)\n" +
+		"  - EKS web identity (IRSA): AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN (-auth web-identity)\n" +
+		"  - IAM role: if running on EC2/ECS, ensure instance/task role has permissions"
+}
+
+// authCredentials reports a provider's failures to retrieve credentials,
+// wherever they surface, as an AuthError.
+type authCredentials struct {
+	provider AuthProvider
+	profile  string
+	inner    aws.CredentialsProvider
+}
+
+// Retrieve implements aws.CredentialsProvider.
+func (c *authCredentials) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	creds, err := c.inner.Retrieve(ctx)
+	if err != nil {
+		return creds, c.fail(err)
+	}
+	return creds, nil
+}
+
+// Invalidate invalidates the cached credentials, if they are cached.
+func (c *authCredentials) Invalidate() {
+	if i, ok := c.inner.(interface{ Invalidate() }); ok {
+		i.Invalidate()
+	}
+}
+
+// IsCredentialsProvider reports whether the wrapped provider is target.
+func (c *authCredentials) IsCredentialsProvider(target aws.CredentialsProvider) bool {
+	return aws.IsCredentialsProvider(c.inner, target)
+}
+
+// fail wraps err as an AuthError of the provider.
+func (c *authCredentials) fail(err error) error {
+	return &AuthError{Provider: c.provider, Profile: c.profile, Err: err, retrieving: true}
+}
+
+// rejectedCredentials reports whether AWS rejected the credentials of a
+// call: they were retrieved, but are invalid or expired.
+func rejectedCredentials(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "InvalidClientTokenId", "ExpiredToken", "ExpiredTokenException", "SignatureDoesNotMatch", "UnrecognizedClientException":
+		return true
+	}
+	return false
+}
+
+// authConfig returns the config options that select auth's credentials,
+// the provider they come from, and their shared config profile, if any.
+// Naming a profile with AuthAuto selects it like AuthProfile, or AuthSSO
+// for an IAM Identity Center profile; without one, failures of an SSO
+// profile in AWS_PROFILE that the default chain uses are reported as
+// AuthSSO. It fails early, before any AWS call, when the provider is
+// plainly not configured.
+func authConfig(ctx context.Context, auth AuthOptions) ([]func(*awsconfig.LoadOptions) error, AuthProvider, string, error) {
+	provider := auth.Provider
+	if provider == "" {
+		provider = AuthAuto
+	}
+	env, err := awsconfig.NewEnvConfig()
+	if err != nil {
+		return nil, provider, "", err
+	}
+	profile := auth.Profile
+	if profile == "" {
+		profile = env.SharedConfigProfile
+	}
+	if profile == "" {
+		profile = awsconfig.DefaultSharedConfigProfile
+	}
+
+	switch provider {
+	case AuthAuto:
+		if auth.Profile != "" {
+			provider = AuthProfile
+			if shared, err := loadSharedProfile(ctx, env, profile); err == nil && isSSOProfile(shared) {
+				provider = AuthSSO
+			}
+			break
+		}
+		// The default chain prefers access keys and web identity to AWS_PROFILE
+		if env.SharedConfigProfile != "" && !env.Credentials.HasKeys() && env.WebIdentityTokenFilePath == "" {
+			if shared, err := loadSharedProfile(ctx, env, profile); err == nil && isSSOProfile(shared) {
+				return nil, AuthSSO, profile, nil
+			}
+		}
+		return nil, provider, "", nil
+	case AuthEnv, AuthWebIdentity:
+		if auth.Profile != "" {
+			return nil, provider, "", fmt.Errorf("a profile cannot be used with -auth %s", provider)
+		}
+	}
+
+	switch provider {
+	case AuthEnv:
+		if !env.Credentials.HasKeys() {
+			return nil, provider, "", errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are not set")
+		}
+		return []func(*awsconfig.LoadOptions) error{
+			awsconfig.WithCredentialsProvider(credentials.StaticCredentialsProvider{Value: env.Credentials}),
+		}, provider, "", nil
+	case AuthProfile, AuthSSO:
+		shared, err := loadSharedProfile(ctx, env, profile)
+		if err != nil {
+			return nil, provider, profile, err
+		}
+		if provider == AuthSSO && !isSSOProfile(shared) {
+			return nil, provider, profile, errors.New("the profile has no sso_session or sso_start_url")
+		}
+		// Naming the profile takes it over credentials in the environment
+		return []func(*awsconfig.LoadOptions) error{awsconfig.WithSharedConfigProfile(profile)}, provider, profile, nil
+	case AuthWebIdentity:
+		if env.WebIdentityTokenFilePath == "" || env.RoleARN == "" {
+			return nil, provider, "", errors.New("AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN are not set")
+		}
+		if _, err := os.Stat(env.WebIdentityTokenFilePath); err != nil {
+			return nil, provider, "", fmt.Errorf("web identity token: %w", err)
+		}
+	}
+	return nil, provider, "", nil
+}
+
+// loadSharedProfile reads a profile from the shared config files
+// LoadDefaultConfig reads.
+func loadSharedProfile(ctx context.Context, env awsconfig.EnvConfig, profile string) (awsconfig.SharedConfig, error) {
+	return awsconfig.LoadSharedConfigProfile(ctx, profile, func(o *awsconfig.LoadSharedConfigOptions) {
+		if env.SharedConfigFile != "" {
+			o.ConfigFiles = []string{env.SharedConfigFile}
+		}
+		if env.SharedCredentialsFile != "" {
+			o.CredentialsFiles = []string{env.SharedCredentialsFile}
+		}
+	})
+}
+
+// isSSOProfile reports whether a profile signs in with IAM Identity Center.
+func isSSOProfile(shared awsconfig.SharedConfig) bool {
+	return shared.SSOSession != nil || shared.SSOStartURL != ""
+}
+
+// webIdentityCredentials returns credentials for the role in AWS_ROLE_ARN,
+// assumed with the token in AWS_WEB_IDENTITY_TOKEN_FILE. Unlike the default
+// chain, it is used even when the environment also has access keys.
+func webIdentityCredentials(cfg aws.Config) aws.CredentialsProvider {
+	env, _ := awsconfig.NewEnvConfig()
+	return aws.NewCredentialsCache(stscreds.NewWebIdentityRoleProvider(sts.NewFromConfig(cfg), env.RoleARN,
+		stscreds.IdentityTokenFile(env.WebIdentityTokenFilePath),
+		func(o *stscreds.WebIdentityRoleOptions) {
+			if env.RoleSessionName != "" {
+				o.RoleSessionName = env.RoleSessionName
+			}
+		}))
+}
@@ -0,0 +1,206 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+)
+
+// isolateAWSEnv points the shared config files at dir and clears the
+// credential environment variables.
+func isolateAWSEnv(t *testing.T, dir string) {
+	t.Helper()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	for _, v := range []string{"AWS_PROFILE", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN",
+		"AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_ARN", "AWS_ROLE_SESSION_NAME"} {
+		t.Setenv(v, "")
+	}
+}
+
+func TestParseAuthProvider(t *testing.T) {
+	for in, want := range map[string]AuthProvider{"": AuthAuto, "auto": AuthAuto, "SSO": AuthSSO, "web-identity": AuthWebIdentity} {
+		if got, err := ParseAuthProvider(in); err != nil || got != want {
+			t.Errorf("ParseAuthProvider(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseAuthProvider("irsa"); err == nil || !strings.Contains(err.Error(), "web-identity") {
+		t.Errorf("expected an error listing the providers, got %v", err)
+	}
+}
+
+func TestLoadAWSConfig_AuthProviders(t *testing.T) {
+	dir := t.TempDir()
+	isolateAWSEnv(t, dir)
+	files := map[string]string{
+		"config":      "[profile sso-dev]\nsso_session = corp\nsso_account_id = 123456789012\nsso_role_name = Ops\n\n[sso-session corp]\nsso_start_url = https://corp.awsapps.com/start\nsso_region = us-east-1\n\n[profile plain]\nregion = us-east-1\n",
+		"credentials": "[plain]\naws_access_key_id = AKIDPROFILE\naws_secret_access_key = secret\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	authErr := func(t *testing.T, err error, provider AuthProvider, hint string) {
+		t.Helper()
+		var ae *AuthError
+		if !errors.As(err, &ae) || ae.Provider != provider {
+			t.Fatalf("expected a %s AuthError, got %v", provider, err)
+		}
+		if !strings.Contains(ae.Hint(), hint) {
+			t.Errorf("hint should mention %q, got %q", hint, ae.Hint())
+		}
+	}
+	accessKey := func(t *testing.T, cfg aws.Config) string {
+		t.Helper()
+		creds, err := cfg.Credentials.Retrieve(ctx)
+		if err != nil {
+			t.Fatalf("Retrieve: %v", err)
+		}
+		return creds.AccessKeyID
+	}
+
+	_, err := loadAWSConfig(ctx, "us-east-1", AuthOptions{Provider: AuthEnv})
+	authErr(t, err, AuthEnv, "AWS_ACCESS_KEY_ID")
+
+	_, err = loadAWSConfig(ctx, "us-east-1", AuthOptions{Provider: AuthWebIdentity})
+	authErr(t, err, AuthWebIdentity, "eks.amazonaws.com/role-arn")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", filepath.Join(dir, "missing-token"))
+	t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/irsa")
+	_, err = loadAWSConfig(ctx, "us-east-1", AuthOptions{Provider: AuthWebIdentity})
+	authErr(t, err, AuthWebIdentity, "AWS_WEB_IDENTITY_TOKEN_FILE")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+	t.Setenv("AWS_ROLE_ARN", "")
+
+	t.Setenv("AWS_PROFILE", "missing")
+	_, err = loadAWSConfig(ctx, "us-east-1", AuthOptions{Provider: AuthProfile})
+	authErr(t, err, AuthProfile, `profile "missing"`)
+
+	t.Setenv("AWS_PROFILE", "plain")
+	_, err = loadAWSConfig(ctx, "us-east-1", AuthOptions{Provider: AuthSSO})
+	authErr(t, err, AuthSSO, "aws sso login --profile plain")
+
+	// With access keys in the environment, -auth profile still uses the profile
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	cfg, err := loadAWSConfig(ctx, "us-east-1", AuthOptions{Provider: AuthProfile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := accessKey(t, cfg); key != "AKIDPROFILE" {
+		t.Errorf("profile credentials: got %s", key)
+	}
+	cfg, err = loadAWSConfig(ctx, "us-east-1", AuthOptions{Provider: AuthEnv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := accessKey(t, cfg); key != "AKIDENV" {
+		t.Errorf("env credentials: got %s", key)
+	}
+
+	t.Setenv("AWS_PROFILE", "sso-dev")
+	if _, err := loadAWSConfig(ctx, "us-east-1", AuthOptions{Provider: AuthSSO}); err != nil {
+		t.Errorf("an SSO profile should load before signing in: %v", err)
+	}
+}
+
+func TestLoadAWSConfig_Profile(t *testing.T) {
+	dir := t.TempDir()
+	isolateAWSEnv(t, dir)
+	t.Setenv("HOME", dir) // No cached SSO token
+	files := map[string]string{
+		"config":      "[profile sso-dev]\nsso_session = corp\nsso_account_id = 123456789012\nsso_role_name = Ops\n\n[sso-session corp]\nsso_start_url = https://corp.awsapps.com/start\nsso_region = us-east-1\n",
+		"credentials": "[plain]\naws_access_key_id = AKIDPROFILE\naws_secret_access_key = secret\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	// A named profile takes over access keys in the environment
+	cfg, err := loadAWSConfig(ctx, "us-east-1", AuthOptions{Profile: "plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds, err := cfg.Credentials.Retrieve(ctx); err != nil || creds.AccessKeyID != "AKIDPROFILE" {
+		t.Errorf("expected the profile's credentials, got %s, %v", creds.AccessKeyID, err)
+	}
+	if _, err := loadAWSConfig(ctx, "us-east-1", AuthOptions{Provider: AuthEnv, Profile: "plain"}); err == nil {
+		t.Error("a profile with -auth env should be refused")
+	}
+
+	// A named SSO profile without a sign-in asks for one
+	cfg, err = loadAWSConfig(ctx, "us-east-1", AuthOptions{Profile: "sso-dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cfg.Credentials.Retrieve(ctx)
+	var ae *AuthError
+	if !errors.As(err, &ae) || ae.Provider != AuthSSO || ae.Profile != "sso-dev" || !ae.NeedsSSOLogin() {
+		t.Fatalf("expected an SSO AuthError that needs a sign-in, got %v", err)
+	}
+
+	// So does an SSO profile in AWS_PROFILE that the default chain uses
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_PROFILE", "sso-dev")
+	cfg, err = loadAWSConfig(ctx, "us-east-1", AuthOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cfg.Credentials.Retrieve(ctx); !errors.As(err, &ae) || !ae.NeedsSSOLogin() {
+		t.Errorf("expected an SSO AuthError from the default chain, got %v", err)
+	}
+
+	// A profile that cannot be loaded is not fixed by signing in
+	if _, err := loadAWSConfig(ctx, "us-east-1", AuthOptions{Profile: "missing"}); !errors.As(err, &ae) || ae.Provider != AuthProfile || ae.NeedsSSOLogin() {
+		t.Errorf("expected a profile AuthError, got %v", err)
+	}
+}
+
+// failingCredentials fails every retrieval.
+type failingCredentials struct{}
+
+func (failingCredentials) Retrieve(context.Context) (aws.Credentials, error) {
+	return aws.Credentials{}, errors.New("the SSO session has expired")
+}
+
+func TestAuthCredentials_ReportRetrieveFailures(t *testing.T) {
+	c := &authCredentials{provider: AuthSSO, profile: "prod", inner: failingCredentials{}}
+	_, err := c.Retrieve(context.Background())
+
+	// The SDK wraps the failure in the operation's error
+	err = fmt.Errorf("operation error STS: GetCallerIdentity, get identity: %w", err)
+	var ae *AuthError
+	if !errors.As(err, &ae) || ae.Profile != "prod" {
+		t.Fatalf("expected an AuthError for profile prod, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "sso credentials (profile prod): the SSO session has expired") {
+		t.Errorf("unexpected message %q", err)
+	}
+}
+
+func TestRejectedCredentials(t *testing.T) {
+	if !rejectedCredentials(fmt.Errorf("call: %w", &smithy.GenericAPIError{Code: "ExpiredToken"})) {
+		t.Error("an expired token is rejected credentials")
+	}
+	if rejectedCredentials(&smithy.GenericAPIError{Code: "AccessDeniedException"}) {
+		t.Error("access denied is a permissions problem, not a credentials one")
+	}
+	if (&AuthError{Provider: AuthAuto}).Hint() == "" || !strings.Contains((&AuthError{Provider: AuthAuto}).Hint(), "-auth web-identity") {
+		t.Error("the default chain's hint should list every provider")
+	}
+}
@@ -1,11 +1,13 @@
 // Package aws provides AWS service clients for backup operations.
 // This file implements the BackupClient, which handles interactions with
-// AWS Backup, RDS, CloudFormation, and STS services.
+// AWS Backup, RDS, EFS, CloudFormation, and STS services.
 package aws
 
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,7 +15,16 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/backup"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
@@ -27,9 +38,23 @@ type BackupClient struct {
 	client    BackupAPI         // AWS Backup service client
 	cfn       CloudFormationAPI // CloudFormation service client for stack queries
 	rds       RDSAPI            // RDS service client for cluster details
+	efs       EFSAPI            // EFS service client for file system details
+	ecs       ECSAPI            // ECS service client for task definition history
+	kms       KMSAPI            // KMS service client for the restore key picker
+	ec2       EC2API            // EC2 service client for the restore security group picker
+	ses       SESAPI            // SES service client for cron summary emails
+	sns       SNSAPI            // SNS service client for cron summary notifications
+	trail     CloudTrailAPI     // CloudTrail client for recovery point deletions in the vault activity
+	ddb       DynamoDBAPI       // DynamoDB client for the shared operation journal
+	rdsData   RDSDataAPI        // RDS Data API client for verifying restored clusters
 	sts       *sts.Client       // STS service client for account ID
 	region    string            // AWS region
 	accountID string            // Cached AWS account ID
+	callerARN string            // Cached ARN of the caller, stamped on created resources
+	auth      AuthOptions       // Where the credentials come from, for clients in other regions
+
+	planCache planRoleCache // Cached vault → backup plan → IAM role mapping
+	simulated bool          // True when backed by simulation fixtures
 }
 
 // NewBackupClient creates a new BackupClient with AWS service clients
@@ -43,42 +68,125 @@ type BackupClient struct {
 // Parameters:
 //   - ctx: Context for cancellation and timeout
 //   - region: AWS region name (e.g., "us-west-2")
+//   - auth: Where the credentials come from (the zero value for AuthAuto)
 //
 // Returns:
 //   - *BackupClient: Initialized backup client
-//   - error: Error if initialization fails (credentials, network, etc.)
+//   - error: Error if initialization fails (*AuthError for credentials, network, etc.)
 //
 // Example:
 //
-//	client, err := NewBackupClient(ctx, "us-west-2")
+//	client, err := NewBackupClient(ctx, "us-west-2", AuthOptions{Profile: "prod"})
 //	if err != nil {
 //	    return fmt.Errorf("failed to create backup client: %w", err)
 //	}
-func NewBackupClient(ctx context.Context, region string) (*BackupClient, error) {
-	cfg, err := loadAWSConfig(ctx, region)
+func NewBackupClient(ctx context.Context, region string, auth AuthOptions) (*BackupClient, error) {
+	cfg, err := loadAWSConfig(ctx, region, auth)
 	if err != nil {
 		return nil, err
 	}
+	return newBackupClientFromConfig(ctx, cfg, region, auth)
+}
 
+// newBackupClientFromConfig creates a BackupClient with the credentials of
+// cfg and caches their account ID. auth is where cfg's base credentials
+// come from.
+func newBackupClientFromConfig(ctx context.Context, cfg aws.Config, region string, auth AuthOptions) (*BackupClient, error) {
 	stsClient := sts.NewFromConfig(cfg)
 
 	// Get account ID - required for constructing IAM role ARNs
 	identity, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
 	if err != nil {
+		// Credentials AWS rejects are as much the provider's problem as
+		// credentials that could not be retrieved
+		if c, ok := cfg.Credentials.(*authCredentials); ok && rejectedCredentials(err) {
+			err = c.fail(err)
+		}
 		return nil, fmt.Errorf("failed to get caller identity: %w", err)
 	}
 	accountID := aws.ToString(identity.Account)
+	callerARN := aws.ToString(identity.Arn)
 
 	return &BackupClient{
 		client:    backup.NewFromConfig(cfg),
 		cfn:       cloudformation.NewFromConfig(cfg),
 		rds:       rds.NewFromConfig(cfg),
+		efs:       efs.NewFromConfig(cfg),
+		ecs:       ecs.NewFromConfig(cfg),
+		kms:       kms.NewFromConfig(cfg),
+		ec2:       ec2.NewFromConfig(cfg),
+		ses:       sesv2.NewFromConfig(cfg),
+		sns:       sns.NewFromConfig(cfg),
+		trail:     cloudtrail.NewFromConfig(cfg),
+		ddb:       dynamodb.NewFromConfig(cfg),
+		rdsData:   rdsdata.NewFromConfig(cfg),
 		sts:       stsClient,
 		region:    region,
 		accountID: accountID,
+		callerARN: callerARN,
+		auth:      auth,
 	}, nil
 }
 
+// StackSummary is an OpenEMR CloudFormation stack in the region.
+type StackSummary struct {
+	Name    string
+	Status  string // e.g. "UPDATE_COMPLETE"
+	Created time.Time
+	Updated time.Time // Zero when never updated
+}
+
+// MultipleStacksError is returned by DiscoverStackName when more than one
+// stack matches, so that callers able to ask the operator can offer Stacks
+// to choose from.
+type MultipleStacksError struct {
+	Stacks []StackSummary
+}
+
+// Error implements the error interface.
+func (e *MultipleStacksError) Error() string {
+	names := make([]string, len(e.Stacks))
+	for i, st := range e.Stacks {
+		names[i] = st.Name
+	}
+	return fmt.Sprintf("multiple CloudFormation stacks found matching pattern 'OpenemrEcs*': %v. Please specify stack name with -stack flag", names)
+}
+
+// ListOpenEMRStacks lists the region's deployed stacks that match the
+// OpenEMR pattern (starting with "OpenemrEcs"), sorted by name.
+func (c *BackupClient) ListOpenEMRStacks(ctx context.Context) ([]StackSummary, error) {
+	input := &cloudformation.ListStacksInput{
+		StackStatusFilter: []types.StackStatus{
+			types.StackStatusCreateComplete,
+			types.StackStatusUpdateComplete,
+			types.StackStatusUpdateRollbackComplete,
+		},
+	}
+
+	var stacks []StackSummary
+	pages := cloudformation.NewListStacksPaginator(c.cfn, input)
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list CloudFormation stacks: %w", err)
+		}
+		for _, summary := range page.StackSummaries {
+			stackName := aws.ToString(summary.StackName)
+			// Match stacks that start with "OpenemrEcs" (case-sensitive)
+			if strings.HasPrefix(stackName, "OpenemrEcs") {
+				stacks = append(stacks, StackSummary{
+					Name:    stackName,
+					Status:  string(summary.StackStatus),
+					Created: aws.ToTime(summary.CreationTime),
+					Updated: aws.ToTime(summary.LastUpdatedTime),
+				})
+			}
+		}
+	}
+	slices.SortFunc(stacks, func(a, b StackSummary) int { return strings.Compare(a.Name, b.Name) })
+	return stacks, nil
+}
+
 // DiscoverStackName discovers the CloudFormation stack name by listing
 // stacks and finding one that matches the OpenEMR pattern (starts with "OpenemrEcs").
 //
@@ -90,33 +198,17 @@ func NewBackupClient(ctx context.Context, region string) (*BackupClient, error)
 //
 // Returns:
 //   - string: Stack name if found (empty string if multiple or none found)
-//   - error: Error if API call fails or multiple stacks found
+//   - error: Error if API call fails or no stack is found, or a
+//     *MultipleStacksError listing them if several are found
 //
 // Example:
 //
 //	stackName, err := client.DiscoverStackName(ctx)
 //	// Returns: "OpenemrEcsStack", nil
 func (c *BackupClient) DiscoverStackName(ctx context.Context) (string, error) {
-	input := &cloudformation.ListStacksInput{
-		StackStatusFilter: []types.StackStatus{
-			types.StackStatusCreateComplete,
-			types.StackStatusUpdateComplete,
-			types.StackStatusUpdateRollbackComplete,
-		},
-	}
-
-	result, err := c.cfn.ListStacks(ctx, input)
+	matchingStacks, err := c.ListOpenEMRStacks(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to list CloudFormation stacks: %w", err)
-	}
-
-	var matchingStacks []string
-	for _, summary := range result.StackSummaries {
-		stackName := aws.ToString(summary.StackName)
-		// Match stacks that start with "OpenemrEcs" (case-sensitive)
-		if strings.HasPrefix(stackName, "OpenemrEcs") {
-			matchingStacks = append(matchingStacks, stackName)
-		}
+		return "", err
 	}
 
 	if len(matchingStacks) == 0 {
@@ -124,10 +216,10 @@ func (c *BackupClient) DiscoverStackName(ctx context.Context) (string, error) {
 	}
 
 	if len(matchingStacks) > 1 {
-		return "", fmt.Errorf("multiple CloudFormation stacks found matching pattern 'OpenemrEcs*': %v. Please specify stack name with -stack flag", matchingStacks)
+		return "", &MultipleStacksError{Stacks: matchingStacks}
 	}
 
-	return matchingStacks[0], nil
+	return matchingStacks[0].Name, nil
 }
 
 // DiscoverVaultByStack discovers a backup vault by searching for vaults
@@ -176,7 +268,7 @@ func (c *BackupClient) DiscoverVaultByStack(ctx context.Context, stackName strin
 // Parameters:
 //   - ctx: Context for cancellation and timeout
 //   - vaultName: Name of the backup vault to query
-//   - resourceType: Optional filter by resource type (empty string = all types)
+//   - resourceTypes: Optional filter by resource types (none or "" = all types)
 //
 // Returns:
 //   - []RecoveryPoint: List of recovery points with metadata
@@ -184,11 +276,46 @@ func (c *BackupClient) DiscoverVaultByStack(ctx context.Context, stackName strin
 //
 // Example:
 //
-//	points, err := client.ListRecoveryPoints(ctx, "my-vault", "RDS")
-//	// Returns only RDS recovery points
-func (c *BackupClient) ListRecoveryPoints(ctx context.Context, vaultName, resourceType string) ([]RecoveryPoint, error) {
+//	points, err := client.ListRecoveryPoints(ctx, "my-vault", "RDS", "EFS")
+//	// Returns only RDS and EFS recovery points
+func (c *BackupClient) ListRecoveryPoints(ctx context.Context, vaultName string, resourceTypes ...string) ([]RecoveryPoint, error) {
+	listing, err := c.ListRecoveryPointsCounted(ctx, vaultName, resourceTypes...)
+	return listing.Points, err
+}
+
+// RecoveryPointListing is the result of listing a vault's recovery points:
+// the points kept, and how many the vault held that were left out.
+type RecoveryPointListing struct {
+	Points         []RecoveryPoint
+	Seen           int            // Recovery points in the vault, before filtering
+	HiddenByType   map[string]int // Points left out by the resource type filter, per type
+	HiddenByStatus map[string]int // Points left out by status (DELETED), per status
+}
+
+// Hidden returns how many recovery points were left out.
+func (l RecoveryPointListing) Hidden() int {
+	n := 0
+	for _, c := range l.HiddenByType {
+		n += c
+	}
+	for _, c := range l.HiddenByStatus {
+		n += c
+	}
+	return n
+}
+
+// ListRecoveryPointsCounted lists recovery points like ListRecoveryPoints,
+// and counts the points the filters left out, so an empty list can say
+// what it is hiding.
+func (c *BackupClient) ListRecoveryPointsCounted(ctx context.Context, vaultName string, resourceTypes ...string) (RecoveryPointListing, error) {
 	if vaultName == "" {
-		return nil, fmt.Errorf("vault name cannot be empty")
+		return RecoveryPointListing{}, fmt.Errorf("vault name cannot be empty")
+	}
+	var only []string
+	for _, t := range resourceTypes {
+		if t != "" {
+			only = append(only, t)
+		}
 	}
 
 	input := &backup.ListRecoveryPointsByBackupVaultInput{
@@ -196,30 +323,30 @@ func (c *BackupClient) ListRecoveryPoints(ctx context.Context, vaultName, resour
 		// Don't set MaxResults - let paginator handle it automatically
 	}
 
-	var allPoints []RecoveryPoint
+	listing := RecoveryPointListing{HiddenByType: map[string]int{}, HiddenByStatus: map[string]int{}}
 	paginator := backup.NewListRecoveryPointsByBackupVaultPaginator(c.client, input)
 
 	// Iterate through all pages of results
 	// Note: If the vault exists but has no recovery points, this loop will
 	// execute once (empty page) and return an empty slice, which is correct.
-	var totalPointsSeen int
 	var pagesProcessed int
 	for paginator.HasMorePages() {
 		pagesProcessed++
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list recovery points from vault %s (after %d pages, %d points): %w", vaultName, pagesProcessed, totalPointsSeen, err)
+			return RecoveryPointListing{}, fmt.Errorf("failed to list recovery points from vault %s (after %d pages, %d points): %w", vaultName, pagesProcessed, listing.Seen, err)
 		}
 
-		// Track total points seen before filtering (for debugging)
-		totalPointsSeen += len(page.RecoveryPoints)
+		// Track total points seen before filtering
+		listing.Seen += len(page.RecoveryPoints)
 
 		// Process each recovery point in the current page
 		// If page.RecoveryPoints is empty, no backups exist in this page
 		for _, point := range page.RecoveryPoints {
 			// Filter by resource type if specified
 			pointResourceType := aws.ToString(point.ResourceType)
-			if resourceType != "" && pointResourceType != resourceType {
+			if len(only) > 0 && !slices.Contains(only, pointResourceType) {
+				listing.HiddenByType[pointResourceType]++
 				continue
 			}
 
@@ -230,6 +357,7 @@ func (c *BackupClient) ListRecoveryPoints(ctx context.Context, vaultName, resour
 			pointStatus := string(point.Status)
 			if pointStatus == "DELETED" {
 				// Skip deleted points (though API shouldn't return these)
+				listing.HiddenByStatus[pointStatus]++
 				continue
 			}
 
@@ -240,21 +368,23 @@ func (c *BackupClient) ListRecoveryPoints(ctx context.Context, vaultName, resour
 				Status:           pointStatus,
 				ResourceType:     pointResourceType,
 				ResourceID:       extractResourceID(aws.ToString(point.ResourceArn)),
+				ResourceARN:      aws.ToString(point.ResourceArn),
+				Lifecycle:        lifecycleFromAPI(point.Lifecycle),
 			}
 
 			if point.BackupSizeInBytes != nil {
 				rp.BackupSizeInBytes = *point.BackupSizeInBytes
 			}
+			if point.CalculatedLifecycle != nil {
+				rp.MoveToColdAt = aws.ToTime(point.CalculatedLifecycle.MoveToColdStorageAt)
+				rp.DeleteAt = aws.ToTime(point.CalculatedLifecycle.DeleteAt)
+			}
 
-			allPoints = append(allPoints, rp)
+			listing.Points = append(listing.Points, rp)
 		}
 	}
 
-	// If we saw recovery points but filtered them all out, that's unusual
-	// This can help diagnose filtering issues (all points DELETING/EXPIRED or filtered by resource type)
-	_ = totalPointsSeen // Tracked for potential future debugging/logging
-
-	return allPoints, nil
+	return listing, nil
 }
 
 // StartRestoreJob initiates a restore job from a recovery point.
@@ -269,19 +399,26 @@ func (c *BackupClient) ListRecoveryPoints(ctx context.Context, vaultName, resour
 //   - rp: Recovery point to restore from
 //   - stackName: CloudFormation stack name (used for RDS metadata lookup)
 //   - vaultName: Backup vault name (used to discover the IAM role from the backup plan)
+//   - opts: Operator overrides, e.g. a different KMS key (zero value for the defaults)
 //
 // Returns:
 //   - string: Restore job ID if successful
 //   - error: Error if restore job cannot be started
 //
 // Note: The restore job runs asynchronously. Use AWS Backup APIs to monitor
-// the job status after this function returns.
+// the job status after this function returns. Each lookup before the job is
+// requested is reported to the ProgressFunc of a context from WithProgress.
 //
 // Example:
 //
-//	jobID, err := client.StartRestoreJob(ctx, recoveryPoint, "OpenemrEcsStack", "my-vault")
-func (c *BackupClient) StartRestoreJob(ctx context.Context, rp RecoveryPoint, stackName, vaultName string) (string, error) {
+//	jobID, err := client.StartRestoreJob(ctx, recoveryPoint, "OpenemrEcsStack", "my-vault", RestoreOptions{})
+func (c *BackupClient) StartRestoreJob(ctx context.Context, rp RecoveryPoint, stackName, vaultName string, opts RestoreOptions) (string, error) {
+	if err := ValidateRestoreOptions(rp.ResourceType, opts); err != nil {
+		return "", err
+	}
+
 	// Discover the IAM role from the backup plan that uses this vault
+	reportProgress(ctx, "Resolving IAM role…")
 	roleArn, err := c.getBackupPlanRoleArn(ctx, vaultName)
 	if err != nil {
 		return "", fmt.Errorf("failed to get backup plan role ARN: %w", err)
@@ -294,37 +431,21 @@ func (c *BackupClient) StartRestoreJob(ctx context.Context, rp RecoveryPoint, st
 	}
 
 	// Add metadata based on resource type
-	switch rp.ResourceType {
-	case "RDS":
-		// For RDS, we need to get cluster details from stack outputs and RDS API
-		dbClusterID, err := c.getRDSClusterIDFromStack(ctx, stackName)
-		if err != nil {
-			return "", fmt.Errorf("failed to get RDS cluster ID from stack: %w", err)
-		}
-
-		// Get subnet group and security groups from RDS cluster
-		subnetGroup, securityGroups, err := c.getRDSClusterDetails(ctx, dbClusterID)
-		if err != nil {
-			return "", fmt.Errorf("failed to get RDS cluster details: %w", err)
+	rt := restoreTypeFor(rp.ResourceType)
+	meta := &RestoreMetadata{ResourceType: rp.ResourceType, ResourceID: rp.ResourceID}
+	if rt.resolve != nil {
+		if err := rt.resolve(ctx, c, rp, stackName, meta); err != nil {
+			return "", err
 		}
-
-		// RDS restore metadata requires:
-		// - DBClusterIdentifier: The target cluster identifier
-		// - DBSubnetGroupName: The subnet group to use for the restored cluster
-		// - VpcSecurityGroupIds: Comma-separated list of security group IDs
-		input.Metadata["DBClusterIdentifier"] = dbClusterID
-		input.Metadata["DBSubnetGroupName"] = subnetGroup
-		input.Metadata["VpcSecurityGroupIds"] = securityGroups
-	case "EFS":
-		// EFS restore metadata:
-		// - file-system-id: The target file system ID (restores in-place)
-		// - newFileSystem: "false" to restore to existing file system
-		// - Encrypted: "true" to maintain encryption
-		input.Metadata["file-system-id"] = rp.ResourceID
-		input.Metadata["newFileSystem"] = "false"
-		input.Metadata["Encrypted"] = "true"
+	}
+	if rt.metadata != nil {
+		maps.Copy(input.Metadata, rt.metadata(meta))
+	}
+	if err := applyRestoreOptions(input.Metadata, rp.ResourceType, opts); err != nil {
+		return "", err
 	}
 
+	reportProgress(ctx, "Starting restore job…")
 	result, err := c.client.StartRestoreJob(ctx, input)
 	if err != nil {
 		return "", fmt.Errorf("failed to start restore job: %w", err)
@@ -333,16 +454,20 @@ func (c *BackupClient) StartRestoreJob(ctx context.Context, rp RecoveryPoint, st
 	return aws.ToString(result.RestoreJobId), nil
 }
 
-// RestoreJobStatus represents the current status of a restore job.
+// RestoreJobStatus represents the current status of a restore job. Backup
+// jobs are reported in the same shape by GetBackupJobStatus.
 type RestoreJobStatus struct {
-	JobID         string
-	Status        string // PENDING, RUNNING, COMPLETED, ABORTED, FAILED
-	CreatedAt     time.Time
-	CompletedAt   time.Time
-	ResourceType  string
-	PercentDone   string
-	StatusMessage string
-	IsTerminal    bool
+	JobID              string
+	Status             string // PENDING, RUNNING, COMPLETED, ABORTED, FAILED
+	CreatedAt          time.Time
+	CompletedAt        time.Time
+	ResourceType       string
+	ResourceID         string // Resource backed up, or created by a completed restore
+	CreatedResourceARN string // Resource created by a completed restore
+	RecoveryPointARN   string // Recovery point restored from, created by a backup, or copied to
+	PercentDone        string
+	StatusMessage      string
+	IsTerminal         bool
 }
 
 // RestoreMetadata contains the parameters that will be used for a restore operation.
@@ -354,6 +479,76 @@ type RestoreMetadata struct {
 	SecurityGroups string
 	Encrypted      bool
 	NewFileSystem  bool
+	KMSKeyID       string   // Empty when the restore keeps the backup's key
+	ItemsToRestore []string // EFS paths restored on their own; empty for the whole file system
+
+	liveClusterID      string // The live cluster's identifier, kept while a suffix is set
+	liveSubnetGroup    string // The live cluster's subnet group, kept while overridden
+	liveSecurityGroups string // The live cluster's groups, kept while overridden
+}
+
+// RestoreOptions are operator overrides of the restore parameters derived
+// from the stack and the recovery point. The zero value restores with the
+// defaults.
+type RestoreOptions struct {
+	// KMSKeyID encrypts the restored cluster or file system with a different
+	// key (key ARN, alias ARN, or alias name) instead of the backup's key.
+	// EFS can only use a different key for a new file system, so setting it
+	// for an EFS restore creates one.
+	KMSKeyID string
+
+	// SubnetGroup creates a restored RDS cluster in this DB subnet group
+	// instead of the live cluster's. Ignored for EFS.
+	SubnetGroup string
+
+	// SecurityGroupIDs attaches a restored RDS cluster to these VPC
+	// security groups instead of the live cluster's, e.g. to restore into
+	// an isolated or staging network. Ignored for EFS.
+	SecurityGroupIDs []string
+
+	// CopyTags copies the original resource's tags, with provenance tags,
+	// onto the restored cluster or file system once the restore completes
+	// (see CopyTagsToRestored). AWS Backup cannot tag it as the job starts.
+	CopyTags bool
+
+	// ClusterSuffix restores an RDS backup to a new cluster named after the
+	// live one with this suffix (see RestoredClusterID), e.g. to verify the
+	// data before cutting production over. Ignored for EFS.
+	ClusterSuffix string
+
+	// ItemsToRestore restores only these files and directories of an EFS
+	// backup, by absolute path from the file system's root (see
+	// CleanItemPath), instead of the whole file system. EFS only.
+	ItemsToRestore []string
+}
+
+// LiveClusterID returns the identifier of the live cluster an RDS restore
+// is based on, whatever name ApplyOptions previews.
+func (m *RestoreMetadata) LiveClusterID() string {
+	if m.liveClusterID != "" {
+		return m.liveClusterID
+	}
+	return m.ClusterID
+}
+
+// ApplyOptions updates the previewed parameters for opts, matching what
+// StartRestoreJob sends.
+func (m *RestoreMetadata) ApplyOptions(opts RestoreOptions) {
+	m.KMSKeyID = opts.KMSKeyID
+	if preview := restoreTypeFor(m.ResourceType).preview; preview != nil {
+		preview(m, opts)
+	}
+}
+
+// applyRestoreOptions adds the restore metadata for opts.
+func applyRestoreOptions(metadata map[string]string, resourceType string, opts RestoreOptions) error {
+	if opts.KMSKeyID != "" {
+		metadata["KmsKeyId"] = opts.KMSKeyID
+	}
+	if options := restoreTypeFor(resourceType).options; options != nil {
+		return options(metadata, opts)
+	}
+	return nil
 }
 
 // GetRestoreJobStatus queries the current status of a restore job.
@@ -366,11 +561,16 @@ func (c *BackupClient) GetRestoreJobStatus(ctx context.Context, jobID string) (*
 	}
 
 	status := &RestoreJobStatus{
-		JobID:         aws.ToString(result.RestoreJobId),
-		Status:        string(result.Status),
-		ResourceType:  aws.ToString(result.ResourceType),
-		PercentDone:   aws.ToString(result.PercentDone),
-		StatusMessage: aws.ToString(result.StatusMessage),
+		JobID:            aws.ToString(result.RestoreJobId),
+		Status:           string(result.Status),
+		ResourceType:     aws.ToString(result.ResourceType),
+		RecoveryPointARN: aws.ToString(result.RecoveryPointArn),
+		PercentDone:      aws.ToString(result.PercentDone),
+		StatusMessage:    aws.ToString(result.StatusMessage),
+	}
+	if arn := aws.ToString(result.CreatedResourceArn); arn != "" {
+		status.ResourceID = resourceName(arn)
+		status.CreatedResourceARN = arn
 	}
 
 	if result.CreationDate != nil {
@@ -396,24 +596,10 @@ func (c *BackupClient) GetRestoreMetadata(ctx context.Context, rp RecoveryPoint,
 		ResourceID:   rp.ResourceID,
 	}
 
-	switch rp.ResourceType {
-	case "RDS":
-		dbClusterID, err := c.getRDSClusterIDFromStack(ctx, stackName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get RDS cluster ID: %w", err)
-		}
-
-		subnetGroup, securityGroups, err := c.getRDSClusterDetails(ctx, dbClusterID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get RDS cluster details: %w", err)
+	if resolve := restoreTypeFor(rp.ResourceType).resolve; resolve != nil {
+		if err := resolve(ctx, c, rp, stackName, meta); err != nil {
+			return nil, err
 		}
-
-		meta.ClusterID = dbClusterID
-		meta.SubnetGroup = subnetGroup
-		meta.SecurityGroups = securityGroups
-	case "EFS":
-		meta.Encrypted = true
-		meta.NewFileSystem = false
 	}
 
 	return meta, nil
@@ -428,7 +614,11 @@ type RecoveryPoint struct {
 	Status            string    // Recovery point status (COMPLETED, AVAILABLE, etc.)
 	ResourceType      string    // Type of resource (RDS, EFS, etc.)
 	ResourceID        string    // ID of the backed-up resource (extracted from ARN)
+	ResourceARN       string    // ARN of the backed-up resource
 	BackupSizeInBytes int64     // Size of the backup in bytes
+	Lifecycle         Lifecycle // Current retention setting; zero values mean never
+	MoveToColdAt      time.Time // When it moves to cold storage; zero if never
+	DeleteAt          time.Time // When AWS Backup deletes it; zero if kept indefinitely
 }
 
 // getRDSClusterIDFromStack retrieves the RDS cluster identifier from
@@ -537,6 +727,8 @@ func (c *BackupClient) getRDSClusterDetails(ctx context.Context, clusterID strin
 // correct role with proper permissions, rather than the default service role
 // which may not have the necessary trust relationship.
 //
+// Resolution is cached per vault; see ResolvePlanRole.
+//
 // Parameters:
 //   - ctx: Context for cancellation and timeout
 //   - vaultName: Name of the backup vault
@@ -545,62 +737,11 @@ func (c *BackupClient) getRDSClusterDetails(ctx context.Context, clusterID strin
 //   - string: IAM role ARN from the backup plan
 //   - error: Error if the role cannot be discovered
 func (c *BackupClient) getBackupPlanRoleArn(ctx context.Context, vaultName string) (string, error) {
-	if vaultName == "" {
-		return "", fmt.Errorf("vault name cannot be empty")
-	}
-
-	// List all backup plans
-	listPlansInput := &backup.ListBackupPlansInput{}
-	plansPaginator := backup.NewListBackupPlansPaginator(c.client, listPlansInput)
-
-	for plansPaginator.HasMorePages() {
-		plansPage, err := plansPaginator.NextPage(ctx)
-		if err != nil {
-			return "", fmt.Errorf("failed to list backup plans: %w", err)
-		}
-
-		// Check each plan to see if it uses our vault
-		for _, plan := range plansPage.BackupPlansList {
-			// Get the plan details to check which vault it uses
-			getPlanInput := &backup.GetBackupPlanInput{
-				BackupPlanId: plan.BackupPlanId,
-			}
-			planDetails, err := c.client.GetBackupPlan(ctx, getPlanInput)
-			if err != nil {
-				// Skip this plan if we can't get details
-				continue
-			}
-
-			// Check if any rule in this plan targets our vault
-			for _, rule := range planDetails.BackupPlan.Rules {
-				if rule.TargetBackupVaultName != nil && *rule.TargetBackupVaultName == vaultName {
-					// Found the plan that uses our vault, get its IAM role from backup selections
-					listSelectionsInput := &backup.ListBackupSelectionsInput{
-						BackupPlanId: plan.BackupPlanId,
-					}
-					selectionsPaginator := backup.NewListBackupSelectionsPaginator(c.client, listSelectionsInput)
-
-					for selectionsPaginator.HasMorePages() {
-						selectionsPage, err := selectionsPaginator.NextPage(ctx)
-						if err != nil {
-							continue
-						}
-
-						// Get the first selection's IAM role
-						for _, selection := range selectionsPage.BackupSelectionsList {
-							if selection.IamRoleArn != nil && *selection.IamRoleArn != "" {
-								return *selection.IamRoleArn, nil
-							}
-						}
-					}
-				}
-			}
-		}
+	pr, err := c.ResolvePlanRole(ctx, vaultName, false)
+	if err != nil {
+		return "", err
 	}
-
-	// Fallback to default service role if plan role not found
-	// This should not happen in practice, but provides a fallback
-	return fmt.Sprintf("arn:aws:iam::%s:role/service-role/AWSBackupDefaultServiceRole", c.accountID), nil
+	return pr.RoleARN, nil
 }
 
 // extractResourceID extracts the resource ID from an AWS resource ARN.
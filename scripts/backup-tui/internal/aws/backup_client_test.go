@@ -2,7 +2,9 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,6 +26,8 @@ type mockCFN struct {
 	listStacksErr       error
 	describeStackOutput *cloudformation.DescribeStacksOutput
 	describeStackErr    error
+	listResourcesOutput *cloudformation.ListStackResourcesOutput
+	listResourcesErr    error
 }
 
 func (m *mockCFN) ListStacks(_ context.Context, _ *cloudformation.ListStacksInput, _ ...func(*cloudformation.Options)) (*cloudformation.ListStacksOutput, error) {
@@ -34,31 +38,84 @@ func (m *mockCFN) DescribeStacks(_ context.Context, _ *cloudformation.DescribeSt
 	return m.describeStackOutput, m.describeStackErr
 }
 
+func (m *mockCFN) ListStackResources(_ context.Context, _ *cloudformation.ListStackResourcesInput, _ ...func(*cloudformation.Options)) (*cloudformation.ListStackResourcesOutput, error) {
+	return m.listResourcesOutput, m.listResourcesErr
+}
+
 type mockBackup struct {
 	listVaultsOutput      *backup.ListBackupVaultsOutput
 	listVaultsErr         error
+	describeVaultOutput   *backup.DescribeBackupVaultOutput
+	describeVaultErr      error
+	supportedTypesOut     *backup.GetSupportedResourceTypesOutput
+	supportedTypesErr     error
 	listRPOutput          *backup.ListRecoveryPointsByBackupVaultOutput
 	listRPErr             error
+	startBackupOutput     *backup.StartBackupJobOutput
+	startBackupErr        error
 	startRestoreOutput    *backup.StartRestoreJobOutput
 	startRestoreErr       error
 	describeRestoreOutput *backup.DescribeRestoreJobOutput
 	describeRestoreErr    error
+	describeBackupOutput  *backup.DescribeBackupJobOutput
+	describeBackupErr     error
 	listPlansOutput       *backup.ListBackupPlansOutput
 	listPlansErr          error
 	getPlanOutput         *backup.GetBackupPlanOutput
 	getPlanErr            error
 	listSelectionsOut     *backup.ListBackupSelectionsOutput
 	listSelectionsErr     error
+	getSelectionOut       *backup.GetBackupSelectionOutput
+	getSelectionErr       error
+	createSelectionOut    *backup.CreateBackupSelectionOutput
+	createSelectionErr    error
+	deleteSelectionErr    error
+	listBackupJobsOut     *backup.ListBackupJobsOutput
+	listRestoreJobsOut    *backup.ListRestoreJobsOutput
+	listCopyJobsOut       *backup.ListCopyJobsOutput
+	listJobsErr           error
+	updateLifecycleInput  *backup.UpdateRecoveryPointLifecycleInput
+	updateLifecycleOut    *backup.UpdateRecoveryPointLifecycleOutput
+	updateLifecycleErr    error
+	createHoldInput       *backup.CreateLegalHoldInput
+	createHoldErr         error
+	listHoldsOut          *backup.ListLegalHoldsOutput
+	holdPointsOut         map[string]*backup.ListRecoveryPointsByLegalHoldOutput
+	cancelHoldInput       *backup.CancelLegalHoldInput
+	describeRPOut         *backup.DescribeRecoveryPointOutput
+	describeRPErr         error
+	listTagsOut           []*backup.ListTagsOutput // Pages, in order
+	listTagsInputs        []*backup.ListTagsInput
+	startCopyInput        *backup.StartCopyJobInput
+	startCopyErr          error
+	describeCopyOut       *backup.DescribeCopyJobOutput
+	restoreMetadataOut    *backup.GetRecoveryPointRestoreMetadataOutput
+	tagResourceInput      *backup.TagResourceInput
+	untagResourceInput    *backup.UntagResourceInput
+	deleteRPInputs        []*backup.DeleteRecoveryPointInput
+	deleteRPErr           error
 }
 
 func (m *mockBackup) ListBackupVaults(_ context.Context, _ *backup.ListBackupVaultsInput, _ ...func(*backup.Options)) (*backup.ListBackupVaultsOutput, error) {
 	return m.listVaultsOutput, m.listVaultsErr
 }
 
+func (m *mockBackup) DescribeBackupVault(_ context.Context, _ *backup.DescribeBackupVaultInput, _ ...func(*backup.Options)) (*backup.DescribeBackupVaultOutput, error) {
+	return m.describeVaultOutput, m.describeVaultErr
+}
+
+func (m *mockBackup) GetSupportedResourceTypes(_ context.Context, _ *backup.GetSupportedResourceTypesInput, _ ...func(*backup.Options)) (*backup.GetSupportedResourceTypesOutput, error) {
+	return m.supportedTypesOut, m.supportedTypesErr
+}
+
 func (m *mockBackup) ListRecoveryPointsByBackupVault(_ context.Context, _ *backup.ListRecoveryPointsByBackupVaultInput, _ ...func(*backup.Options)) (*backup.ListRecoveryPointsByBackupVaultOutput, error) {
 	return m.listRPOutput, m.listRPErr
 }
 
+func (m *mockBackup) StartBackupJob(_ context.Context, _ *backup.StartBackupJobInput, _ ...func(*backup.Options)) (*backup.StartBackupJobOutput, error) {
+	return m.startBackupOutput, m.startBackupErr
+}
+
 func (m *mockBackup) StartRestoreJob(_ context.Context, _ *backup.StartRestoreJobInput, _ ...func(*backup.Options)) (*backup.StartRestoreJobOutput, error) {
 	return m.startRestoreOutput, m.startRestoreErr
 }
@@ -67,6 +124,10 @@ func (m *mockBackup) DescribeRestoreJob(_ context.Context, _ *backup.DescribeRes
 	return m.describeRestoreOutput, m.describeRestoreErr
 }
 
+func (m *mockBackup) DescribeBackupJob(_ context.Context, _ *backup.DescribeBackupJobInput, _ ...func(*backup.Options)) (*backup.DescribeBackupJobOutput, error) {
+	return m.describeBackupOutput, m.describeBackupErr
+}
+
 func (m *mockBackup) ListBackupPlans(_ context.Context, _ *backup.ListBackupPlansInput, _ ...func(*backup.Options)) (*backup.ListBackupPlansOutput, error) {
 	return m.listPlansOutput, m.listPlansErr
 }
@@ -79,15 +140,227 @@ func (m *mockBackup) ListBackupSelections(_ context.Context, _ *backup.ListBacku
 	return m.listSelectionsOut, m.listSelectionsErr
 }
 
+func (m *mockBackup) GetBackupSelection(_ context.Context, _ *backup.GetBackupSelectionInput, _ ...func(*backup.Options)) (*backup.GetBackupSelectionOutput, error) {
+	return m.getSelectionOut, m.getSelectionErr
+}
+
+func (m *mockBackup) CreateBackupSelection(_ context.Context, _ *backup.CreateBackupSelectionInput, _ ...func(*backup.Options)) (*backup.CreateBackupSelectionOutput, error) {
+	return m.createSelectionOut, m.createSelectionErr
+}
+
+func (m *mockBackup) DeleteBackupSelection(_ context.Context, _ *backup.DeleteBackupSelectionInput, _ ...func(*backup.Options)) (*backup.DeleteBackupSelectionOutput, error) {
+	return &backup.DeleteBackupSelectionOutput{}, m.deleteSelectionErr
+}
+
+func (m *mockBackup) ListBackupJobs(_ context.Context, _ *backup.ListBackupJobsInput, _ ...func(*backup.Options)) (*backup.ListBackupJobsOutput, error) {
+	if m.listBackupJobsOut == nil {
+		return &backup.ListBackupJobsOutput{}, m.listJobsErr
+	}
+	return m.listBackupJobsOut, m.listJobsErr
+}
+
+func (m *mockBackup) ListRestoreJobs(_ context.Context, _ *backup.ListRestoreJobsInput, _ ...func(*backup.Options)) (*backup.ListRestoreJobsOutput, error) {
+	if m.listRestoreJobsOut == nil {
+		return &backup.ListRestoreJobsOutput{}, m.listJobsErr
+	}
+	return m.listRestoreJobsOut, m.listJobsErr
+}
+
+func (m *mockBackup) ListCopyJobs(_ context.Context, _ *backup.ListCopyJobsInput, _ ...func(*backup.Options)) (*backup.ListCopyJobsOutput, error) {
+	if m.listCopyJobsOut == nil {
+		return &backup.ListCopyJobsOutput{}, m.listJobsErr
+	}
+	return m.listCopyJobsOut, m.listJobsErr
+}
+
+func (m *mockBackup) CreateLegalHold(_ context.Context, in *backup.CreateLegalHoldInput, _ ...func(*backup.Options)) (*backup.CreateLegalHoldOutput, error) {
+	m.createHoldInput = in
+	if m.createHoldErr != nil {
+		return nil, m.createHoldErr
+	}
+	return &backup.CreateLegalHoldOutput{LegalHoldId: aws.String("hold-1"), Title: in.Title, Description: in.Description, Status: backuptypes.LegalHoldStatusCreating}, nil
+}
+
+func (m *mockBackup) ListLegalHolds(_ context.Context, _ *backup.ListLegalHoldsInput, _ ...func(*backup.Options)) (*backup.ListLegalHoldsOutput, error) {
+	if m.listHoldsOut == nil {
+		return &backup.ListLegalHoldsOutput{}, nil
+	}
+	return m.listHoldsOut, nil
+}
+
+func (m *mockBackup) ListRecoveryPointsByLegalHold(_ context.Context, in *backup.ListRecoveryPointsByLegalHoldInput, _ ...func(*backup.Options)) (*backup.ListRecoveryPointsByLegalHoldOutput, error) {
+	if out, ok := m.holdPointsOut[aws.ToString(in.LegalHoldId)]; ok {
+		return out, nil
+	}
+	return &backup.ListRecoveryPointsByLegalHoldOutput{}, nil
+}
+
+func (m *mockBackup) CancelLegalHold(_ context.Context, in *backup.CancelLegalHoldInput, _ ...func(*backup.Options)) (*backup.CancelLegalHoldOutput, error) {
+	m.cancelHoldInput = in
+	return &backup.CancelLegalHoldOutput{}, nil
+}
+
+func (m *mockBackup) UpdateRecoveryPointLifecycle(_ context.Context, in *backup.UpdateRecoveryPointLifecycleInput, _ ...func(*backup.Options)) (*backup.UpdateRecoveryPointLifecycleOutput, error) {
+	m.updateLifecycleInput = in
+	if m.updateLifecycleOut == nil {
+		return &backup.UpdateRecoveryPointLifecycleOutput{Lifecycle: in.Lifecycle}, m.updateLifecycleErr
+	}
+	return m.updateLifecycleOut, m.updateLifecycleErr
+}
+
+func (m *mockBackup) DescribeRecoveryPoint(_ context.Context, _ *backup.DescribeRecoveryPointInput, _ ...func(*backup.Options)) (*backup.DescribeRecoveryPointOutput, error) {
+	return m.describeRPOut, m.describeRPErr
+}
+
+func (m *mockBackup) ListTags(_ context.Context, in *backup.ListTagsInput, _ ...func(*backup.Options)) (*backup.ListTagsOutput, error) {
+	m.listTagsInputs = append(m.listTagsInputs, in)
+	if page := len(m.listTagsInputs) - 1; page < len(m.listTagsOut) {
+		return m.listTagsOut[page], nil
+	}
+	return &backup.ListTagsOutput{}, nil
+}
+
+func (m *mockBackup) StartCopyJob(_ context.Context, in *backup.StartCopyJobInput, _ ...func(*backup.Options)) (*backup.StartCopyJobOutput, error) {
+	m.startCopyInput = in
+	if m.startCopyErr != nil {
+		return nil, m.startCopyErr
+	}
+	return &backup.StartCopyJobOutput{CopyJobId: aws.String("copy-1")}, nil
+}
+
+func (m *mockBackup) DescribeCopyJob(_ context.Context, _ *backup.DescribeCopyJobInput, _ ...func(*backup.Options)) (*backup.DescribeCopyJobOutput, error) {
+	if m.describeCopyOut == nil {
+		return &backup.DescribeCopyJobOutput{}, nil
+	}
+	return m.describeCopyOut, nil
+}
+
+func (m *mockBackup) TagResource(_ context.Context, in *backup.TagResourceInput, _ ...func(*backup.Options)) (*backup.TagResourceOutput, error) {
+	m.tagResourceInput = in
+	return &backup.TagResourceOutput{}, nil
+}
+
+func (m *mockBackup) UntagResource(_ context.Context, in *backup.UntagResourceInput, _ ...func(*backup.Options)) (*backup.UntagResourceOutput, error) {
+	m.untagResourceInput = in
+	return &backup.UntagResourceOutput{}, nil
+}
+
+func (m *mockBackup) DeleteRecoveryPoint(_ context.Context, in *backup.DeleteRecoveryPointInput, _ ...func(*backup.Options)) (*backup.DeleteRecoveryPointOutput, error) {
+	m.deleteRPInputs = append(m.deleteRPInputs, in)
+	return &backup.DeleteRecoveryPointOutput{}, m.deleteRPErr
+}
+
+func (m *mockBackup) GetRecoveryPointRestoreMetadata(_ context.Context, _ *backup.GetRecoveryPointRestoreMetadataInput, _ ...func(*backup.Options)) (*backup.GetRecoveryPointRestoreMetadataOutput, error) {
+	if m.restoreMetadataOut == nil {
+		return &backup.GetRecoveryPointRestoreMetadataOutput{}, nil
+	}
+	return m.restoreMetadataOut, nil
+}
+
 type mockRDS struct {
-	describeClustersOutput *rds.DescribeDBClustersOutput
-	describeClustersErr    error
+	describeClustersOutput  *rds.DescribeDBClustersOutput
+	describeClustersErr     error
+	describeInstancesOutput *rds.DescribeDBInstancesOutput
+	describeInstancesErr    error
+	describeEventsOutput    *rds.DescribeEventsOutput
+	describeEventsErr       error
+	describeEventsInput     *rds.DescribeEventsInput
+	startExportInput        *rds.StartExportTaskInput
+	startExportErr          error
+	describeExportOutput    *rds.DescribeExportTasksOutput
+	describeExportErr       error
+	cloneInput              *rds.RestoreDBClusterToPointInTimeInput
+	cloneErr                error
+	createInstanceInput     *rds.CreateDBInstanceInput
+	createInstanceErr       error
+	subnetGroupsOutput      *rds.DescribeDBSubnetGroupsOutput
+	subnetGroupsErr         error
+	addTagsInput            *rds.AddTagsToResourceInput
+	engineVersions          []rdstypes.DBEngineVersion // Deprecated ones included
+	defaultEngineVersion    string
+	deletedInstances        []string
+	deletedCluster          *rds.DeleteDBClusterInput
+}
+
+func (m *mockRDS) DeleteDBInstance(_ context.Context, in *rds.DeleteDBInstanceInput, _ ...func(*rds.Options)) (*rds.DeleteDBInstanceOutput, error) {
+	m.deletedInstances = append(m.deletedInstances, aws.ToString(in.DBInstanceIdentifier))
+	return &rds.DeleteDBInstanceOutput{}, nil
+}
+
+func (m *mockRDS) EnableHttpEndpoint(_ context.Context, in *rds.EnableHttpEndpointInput, _ ...func(*rds.Options)) (*rds.EnableHttpEndpointOutput, error) {
+	return &rds.EnableHttpEndpointOutput{ResourceArn: in.ResourceArn, HttpEndpointEnabled: aws.Bool(true)}, nil
+}
+
+func (m *mockRDS) DeleteDBCluster(_ context.Context, in *rds.DeleteDBClusterInput, _ ...func(*rds.Options)) (*rds.DeleteDBClusterOutput, error) {
+	m.deletedCluster = in
+	return &rds.DeleteDBClusterOutput{}, nil
+}
+
+func (m *mockRDS) DescribeDBEngineVersions(_ context.Context, in *rds.DescribeDBEngineVersionsInput, _ ...func(*rds.Options)) (*rds.DescribeDBEngineVersionsOutput, error) {
+	out := &rds.DescribeDBEngineVersionsOutput{}
+	for _, v := range m.engineVersions {
+		version := aws.ToString(v.EngineVersion)
+		if (in.EngineVersion == nil || aws.ToString(in.EngineVersion) == version) &&
+			(!aws.ToBool(in.DefaultOnly) || version == m.defaultEngineVersion) {
+			out.DBEngineVersions = append(out.DBEngineVersions, v)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockRDS) AddTagsToResource(_ context.Context, in *rds.AddTagsToResourceInput, _ ...func(*rds.Options)) (*rds.AddTagsToResourceOutput, error) {
+	m.addTagsInput = in
+	return &rds.AddTagsToResourceOutput{}, nil
+}
+
+func (m *mockRDS) RestoreDBClusterToPointInTime(_ context.Context, in *rds.RestoreDBClusterToPointInTimeInput, _ ...func(*rds.Options)) (*rds.RestoreDBClusterToPointInTimeOutput, error) {
+	m.cloneInput = in
+	return &rds.RestoreDBClusterToPointInTimeOutput{}, m.cloneErr
+}
+
+func (m *mockRDS) CreateDBInstance(_ context.Context, in *rds.CreateDBInstanceInput, _ ...func(*rds.Options)) (*rds.CreateDBInstanceOutput, error) {
+	m.createInstanceInput = in
+	return &rds.CreateDBInstanceOutput{}, m.createInstanceErr
+}
+
+func (m *mockRDS) StartExportTask(_ context.Context, in *rds.StartExportTaskInput, _ ...func(*rds.Options)) (*rds.StartExportTaskOutput, error) {
+	m.startExportInput = in
+	if m.startExportErr != nil {
+		return nil, m.startExportErr
+	}
+	return &rds.StartExportTaskOutput{ExportTaskIdentifier: in.ExportTaskIdentifier}, nil
+}
+
+func (m *mockRDS) DescribeExportTasks(_ context.Context, _ *rds.DescribeExportTasksInput, _ ...func(*rds.Options)) (*rds.DescribeExportTasksOutput, error) {
+	return m.describeExportOutput, m.describeExportErr
 }
 
 func (m *mockRDS) DescribeDBClusters(_ context.Context, _ *rds.DescribeDBClustersInput, _ ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
 	return m.describeClustersOutput, m.describeClustersErr
 }
 
+func (m *mockRDS) DescribeDBSubnetGroups(_ context.Context, _ *rds.DescribeDBSubnetGroupsInput, _ ...func(*rds.Options)) (*rds.DescribeDBSubnetGroupsOutput, error) {
+	if m.subnetGroupsOutput == nil && m.subnetGroupsErr == nil {
+		return &rds.DescribeDBSubnetGroupsOutput{}, nil
+	}
+	return m.subnetGroupsOutput, m.subnetGroupsErr
+}
+
+func (m *mockRDS) DescribeDBInstances(_ context.Context, _ *rds.DescribeDBInstancesInput, _ ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	if m.describeInstancesOutput == nil && m.describeInstancesErr == nil {
+		return &rds.DescribeDBInstancesOutput{}, nil
+	}
+	return m.describeInstancesOutput, m.describeInstancesErr
+}
+
+func (m *mockRDS) DescribeEvents(_ context.Context, in *rds.DescribeEventsInput, _ ...func(*rds.Options)) (*rds.DescribeEventsOutput, error) {
+	m.describeEventsInput = in
+	if m.describeEventsOutput == nil && m.describeEventsErr == nil {
+		return &rds.DescribeEventsOutput{}, nil
+	}
+	return m.describeEventsOutput, m.describeEventsErr
+}
+
 func newTestClient(cfnMock *mockCFN, backupMock *mockBackup, rdsMock *mockRDS) *BackupClient {
 	return &BackupClient{
 		client:    backupMock,
@@ -139,19 +412,31 @@ func TestDiscoverStackName_NoMatch(t *testing.T) {
 }
 
 func TestDiscoverStackName_MultipleMatches(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
 	cfnMock := &mockCFN{
 		listStacksOutput: &cloudformation.ListStacksOutput{
 			StackSummaries: []cfntypes.StackSummary{
-				{StackName: aws.String("OpenemrEcsStack")},
-				{StackName: aws.String("OpenemrEcsStackDev")},
+				{StackName: aws.String("OpenemrEcsStackDev"), StackStatus: cfntypes.StackStatusCreateComplete, CreationTime: aws.Time(created)},
+				{StackName: aws.String("OtherStack")},
+				{StackName: aws.String("OpenemrEcsStack"), StackStatus: cfntypes.StackStatusUpdateComplete},
 			},
 		},
 	}
 	c := newTestClient(cfnMock, &mockBackup{}, &mockRDS{})
 
 	_, err := c.DiscoverStackName(context.Background())
-	if err == nil {
-		t.Fatal("expected error for multiple matches")
+	var multiple *MultipleStacksError
+	if !errors.As(err, &multiple) {
+		t.Fatalf("expected a MultipleStacksError, got %v", err)
+	}
+	if len(multiple.Stacks) != 2 || multiple.Stacks[0].Name != "OpenemrEcsStack" || multiple.Stacks[1].Name != "OpenemrEcsStackDev" {
+		t.Fatalf("the matching stacks should be listed by name, got %+v", multiple.Stacks)
+	}
+	if dev := multiple.Stacks[1]; dev.Status != "CREATE_COMPLETE" || !dev.Created.Equal(created) {
+		t.Errorf("the stack's status and creation time should be kept, got %+v", dev)
+	}
+	if !strings.Contains(err.Error(), "-stack") {
+		t.Errorf("the error should say how to pick one, got %q", err)
 	}
 }
 
@@ -274,6 +559,15 @@ func TestListRecoveryPoints_FiltersResourceType(t *testing.T) {
 	if points[0].ResourceType != "RDS" {
 		t.Errorf("expected RDS, got %s", points[0].ResourceType)
 	}
+
+	points, err = c.ListRecoveryPoints(context.Background(), "my-vault", "RDS", "EFS")
+	if err != nil || len(points) != 2 {
+		t.Errorf("expected the RDS and EFS points with both types, got %d, %v", len(points), err)
+	}
+	points, _ = c.ListRecoveryPoints(context.Background(), "my-vault", "Aurora", "DynamoDB")
+	if len(points) != 0 {
+		t.Errorf("expected no points of other types, got %d", len(points))
+	}
 }
 
 func TestListRecoveryPoints_AllTypes(t *testing.T) {
@@ -335,6 +629,38 @@ func TestListRecoveryPoints_SkipsDeleted(t *testing.T) {
 	}
 }
 
+func TestListRecoveryPointsCounted_CountsHidden(t *testing.T) {
+	now := time.Now()
+	point := func(arn, resourceType string, status backuptypes.RecoveryPointStatus) backuptypes.RecoveryPointByBackupVault {
+		return backuptypes.RecoveryPointByBackupVault{
+			RecoveryPointArn: aws.String(arn), ResourceType: aws.String(resourceType),
+			ResourceArn: aws.String("arn:aws:rds:us-west-2:123:cluster:c"), CreationDate: &now, Status: status,
+		}
+	}
+	backupMock := &mockBackup{
+		listRPOutput: &backup.ListRecoveryPointsByBackupVaultOutput{
+			RecoveryPoints: []backuptypes.RecoveryPointByBackupVault{
+				point("arn:1", "RDS", backuptypes.RecoveryPointStatusCompleted),
+				point("arn:2", "EFS", backuptypes.RecoveryPointStatusCompleted),
+				point("arn:3", "EFS", backuptypes.RecoveryPointStatusCompleted),
+				point("arn:4", "DynamoDB", backuptypes.RecoveryPointStatus("DELETED")),
+			},
+		},
+	}
+	c := newTestClient(&mockCFN{}, backupMock, &mockRDS{})
+
+	listing, err := c.ListRecoveryPointsCounted(context.Background(), "my-vault", "DynamoDB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listing.Points) != 0 || listing.Seen != 4 || listing.Hidden() != 4 {
+		t.Fatalf("expected all 4 points hidden, got %+v", listing)
+	}
+	if listing.HiddenByType["EFS"] != 2 || listing.HiddenByType["RDS"] != 1 || listing.HiddenByStatus["DELETED"] != 1 {
+		t.Errorf("unexpected hidden counts: %v by type, %v by status", listing.HiddenByType, listing.HiddenByStatus)
+	}
+}
+
 func TestListRecoveryPoints_EmptyVaultName(t *testing.T) {
 	c := newTestClient(&mockCFN{}, &mockBackup{}, &mockRDS{})
 
@@ -0,0 +1,115 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements deleting the resources a restore or clone left
+// behind when it failed or its restore chain was abandoned: a half-created
+// Aurora cluster and its instances, or a new EFS file system, which are
+// otherwise billed until someone notices them. The stack's own cluster and
+// file systems are never deleted.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// CreatedResource is a resource a restore or clone created.
+type CreatedResource struct {
+	ResourceType string // "RDS" for an Aurora cluster and its instances, or "EFS" for a file system
+	ID           string // Cluster identifier or file system ID
+}
+
+// CreatedResourceFromARN returns the cluster or file system arn names, as
+// AWS Backup reports a restore's created resource, and false for other
+// resources.
+func CreatedResourceFromARN(arn string) (CreatedResource, bool) {
+	switch {
+	case strings.Contains(arn, ":rds:") && strings.Contains(arn, ":cluster:"):
+		return CreatedResource{ResourceType: "RDS", ID: resourceName(arn)}, true
+	case strings.Contains(arn, ":elasticfilesystem:") && strings.Contains(arn, ":file-system/"):
+		return CreatedResource{ResourceType: "EFS", ID: resourceName(arn)}, true
+	}
+	return CreatedResource{}, false
+}
+
+// String describes the resource, e.g. "Aurora cluster openemr-clone-1".
+func (r CreatedResource) String() string {
+	if r.ResourceType == "EFS" {
+		return "EFS file system " + r.ID
+	}
+	return "Aurora cluster " + r.ID
+}
+
+// DeleteCreatedResource deletes r: an Aurora cluster with its instances,
+// without a final snapshot, or a file system without mount targets. It
+// refuses to delete a cluster or file system of stackName, a cluster with
+// deletion protection, and a file system that is mounted. It returns what
+// was deleted; RDS and EFS finish the deletion in the background.
+func (c *BackupClient) DeleteCreatedResource(ctx context.Context, stackName string, r CreatedResource) ([]string, error) {
+	stack, err := c.stackProtectedResources(ctx, stackName)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range stack {
+		if p.Type == r.ResourceType && resourceName(p.ARN) == r.ID {
+			return nil, fmt.Errorf("%s is the stack's %s (%s); refusing to delete it", r, p.LogicalID, stackName)
+		}
+	}
+
+	switch r.ResourceType {
+	case "RDS":
+		return c.deleteCluster(ctx, r.ID)
+	case "EFS":
+		return c.deleteFileSystem(ctx, r.ID)
+	}
+	return nil, fmt.Errorf("cannot delete a %s resource", r.ResourceType)
+}
+
+// deleteCluster deletes a cluster's instances and then the cluster.
+func (c *BackupClient) deleteCluster(ctx context.Context, clusterID string) ([]string, error) {
+	out, err := c.rds.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(clusterID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe DB cluster: %w", err)
+	}
+	if len(out.DBClusters) == 0 {
+		return nil, fmt.Errorf("DB cluster not found: %s", clusterID)
+	}
+	cl := out.DBClusters[0]
+	if aws.ToBool(cl.DeletionProtection) {
+		return nil, fmt.Errorf("DB cluster %s has deletion protection; turn it off to delete it", clusterID)
+	}
+
+	var deleted []string
+	for _, member := range cl.DBClusterMembers {
+		id := aws.ToString(member.DBInstanceIdentifier)
+		if _, err := c.rds.DeleteDBInstance(ctx, &rds.DeleteDBInstanceInput{DBInstanceIdentifier: aws.String(id)}); err != nil {
+			return deleted, fmt.Errorf("failed to delete DB instance %s: %w", id, err)
+		}
+		deleted = append(deleted, "DB instance "+id)
+	}
+	if _, err := c.rds.DeleteDBCluster(ctx, &rds.DeleteDBClusterInput{
+		DBClusterIdentifier: aws.String(clusterID),
+		SkipFinalSnapshot:   aws.Bool(true),
+	}); err != nil {
+		return deleted, fmt.Errorf("failed to delete DB cluster %s: %w", clusterID, err)
+	}
+	return append(deleted, "Aurora cluster "+clusterID), nil
+}
+
+// deleteFileSystem deletes a file system that has no mount targets.
+func (c *BackupClient) deleteFileSystem(ctx context.Context, fileSystemID string) ([]string, error) {
+	mts, err := c.efs.DescribeMountTargets(ctx, &efs.DescribeMountTargetsInput{FileSystemId: aws.String(fileSystemID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe mount targets: %w", err)
+	}
+	if n := len(mts.MountTargets); n > 0 {
+		return nil, fmt.Errorf("EFS file system %s has %d mount target(s), so it may be in use; delete them to delete it", fileSystemID, n)
+	}
+	if _, err := c.efs.DeleteFileSystem(ctx, &efs.DeleteFileSystemInput{FileSystemId: aws.String(fileSystemID)}); err != nil {
+		return nil, fmt.Errorf("failed to delete EFS file system %s: %w", fileSystemID, err)
+	}
+	return []string{"EFS file system " + fileSystemID}, nil
+}
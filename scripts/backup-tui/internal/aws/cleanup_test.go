@@ -0,0 +1,105 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	efstypes "github.com/aws/aws-sdk-go-v2/service/efs/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+func TestCreatedResourceFromARN(t *testing.T) {
+	tests := []struct {
+		arn  string
+		want CreatedResource
+		ok   bool
+	}{
+		{"arn:aws:rds:us-west-2:123456789012:cluster:restored-1", CreatedResource{"RDS", "restored-1"}, true},
+		{"arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-123", CreatedResource{"EFS", "fs-123"}, true},
+		{"arn:aws:rds:us-west-2:123456789012:db:instance-1", CreatedResource{}, false},
+		{"", CreatedResource{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := CreatedResourceFromARN(tt.arn)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("CreatedResourceFromARN(%q) = %+v, %v, want %+v, %v", tt.arn, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDeleteCreatedResource(t *testing.T) {
+	cfnMock := &mockCFN{listResourcesOutput: &cloudformation.ListStackResourcesOutput{
+		StackResourceSummaries: []cfntypes.StackResourceSummary{
+			{ResourceType: aws.String("AWS::RDS::DBCluster"), LogicalResourceId: aws.String("DatabaseCluster"), PhysicalResourceId: aws.String("my-cluster")},
+			{ResourceType: aws.String("AWS::EFS::FileSystem"), LogicalResourceId: aws.String("SitesFileSystem"), PhysicalResourceId: aws.String("fs-stack")},
+		},
+	}}
+	rdsMock := &mockRDS{describeClustersOutput: &rds.DescribeDBClustersOutput{DBClusters: []rdstypes.DBCluster{{
+		DBClusterIdentifier: aws.String("my-cluster-clone"),
+		DBClusterMembers:    []rdstypes.DBClusterMember{{DBInstanceIdentifier: aws.String("my-cluster-clone-1")}},
+	}}}}
+	efsMock := &mockEFS{mountTargetsOut: &efs.DescribeMountTargetsOutput{}}
+	c := newTestClient(cfnMock, &mockBackup{}, rdsMock)
+	c.efs = efsMock
+	ctx := context.Background()
+
+	for _, r := range []CreatedResource{{"RDS", "my-cluster"}, {"EFS", "fs-stack"}} {
+		if _, err := c.DeleteCreatedResource(ctx, "TestStack", r); err == nil || !strings.Contains(err.Error(), "refusing") {
+			t.Errorf("the stack's %s should not be deleted, got %v", r, err)
+		}
+	}
+	if rdsMock.deletedCluster != nil || efsMock.deleted != "" {
+		t.Fatal("nothing should have been deleted")
+	}
+
+	deleted, err := c.DeleteCreatedResource(ctx, "TestStack", CreatedResource{"RDS", "my-cluster-clone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 2 || len(rdsMock.deletedInstances) != 1 || rdsMock.deletedInstances[0] != "my-cluster-clone-1" ||
+		aws.ToString(rdsMock.deletedCluster.DBClusterIdentifier) != "my-cluster-clone" || !aws.ToBool(rdsMock.deletedCluster.SkipFinalSnapshot) {
+		t.Errorf("expected the instance and then the cluster deleted, got %v, %+v", deleted, rdsMock.deletedCluster)
+	}
+
+	rdsMock.deletedCluster = nil
+	rdsMock.describeClustersOutput.DBClusters[0].DeletionProtection = aws.Bool(true)
+	if _, err := c.DeleteCreatedResource(ctx, "TestStack", CreatedResource{"RDS", "my-cluster-clone"}); err == nil || rdsMock.deletedCluster != nil {
+		t.Errorf("a cluster with deletion protection should not be deleted, got %v", err)
+	}
+
+	efsMock.mountTargetsOut.MountTargets = []efstypes.MountTargetDescription{{MountTargetId: aws.String("fsmt-1")}}
+	if _, err := c.DeleteCreatedResource(ctx, "TestStack", CreatedResource{"EFS", "fs-restored"}); err == nil || efsMock.deleted != "" {
+		t.Errorf("a mounted file system should not be deleted, got %v", err)
+	}
+	efsMock.mountTargetsOut.MountTargets = nil
+	if _, err := c.DeleteCreatedResource(ctx, "TestStack", CreatedResource{"EFS", "fs-restored"}); err != nil || efsMock.deleted != "fs-restored" {
+		t.Errorf("expected fs-restored deleted, got %q, %v", efsMock.deleted, err)
+	}
+}
+
+func TestSimulatedClient_DeleteCreatedResource(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	stack := fx.Stacks[0].Name
+
+	cloneID, err := c.CloneCluster(ctx, stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.DeleteCreatedResource(ctx, stack, CreatedResource{"RDS", cloneID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetJobStatus(ctx, JobKindClone, cloneID); err == nil {
+		t.Error("a deleted clone should no longer be found")
+	}
+	if _, err := c.DeleteCreatedResource(ctx, stack, CreatedResource{"RDS", "openemr-training-cluster"}); err == nil {
+		t.Error("the training stack's cluster should not be deleted")
+	}
+}
@@ -0,0 +1,158 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements Aurora fast clones of the stack's current cluster:
+// a copy-on-write clone (RestoreDBClusterToPointInTime) shares storage with
+// the source until pages change, so it is ready in minutes rather than the
+// hours a full restore of a large database takes. Clones are tracked like
+// jobs, as JobKindClone, until the cluster and its instance are available.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// CloneTagKey tags clones with the cluster they were cloned from, so they
+// can be found and deleted after the investigation.
+const CloneTagKey = "backup-tui:clone-of"
+
+// cloneClusterID returns the identifier for a clone of source: at most 63
+// letters, digits, and hyphens, with no consecutive or trailing hyphens.
+func cloneClusterID(source string, now time.Time) string {
+	suffix := "-clone-" + now.UTC().Format("20060102-1504")
+	if limit := 63 - len(suffix); len(source) > limit {
+		source = source[:limit]
+	}
+	return strings.TrimRight(source, "-") + suffix
+}
+
+// CloneCluster starts a copy-on-write clone of the stack's current Aurora
+// cluster at its latest restorable time, in the same subnet group and
+// security groups, and adds a writer instance of the source writer's class.
+// Both are tagged with the source cluster and the identity tags. It returns the clone's cluster identifier; follow it with GetCloneStatus.
+func (c *BackupClient) CloneCluster(ctx context.Context, stackName string) (string, error) {
+	sourceID, err := c.getRDSClusterIDFromStack(ctx, stackName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get RDS cluster ID: %w", err)
+	}
+	out, err := c.rds.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(sourceID)})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe DB cluster: %w", err)
+	}
+	if len(out.DBClusters) == 0 {
+		return "", fmt.Errorf("DB cluster not found: %s", sourceID)
+	}
+	source := out.DBClusters[0]
+
+	instanceClass := "db.serverless"
+	instances, err := c.rds.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		Filters: []rdstypes.Filter{{Name: aws.String("db-cluster-id"), Values: []string{sourceID}}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe DB instances: %w", err)
+	}
+	writers := make(map[string]bool, len(source.DBClusterMembers))
+	for _, member := range source.DBClusterMembers {
+		writers[aws.ToString(member.DBInstanceIdentifier)] = aws.ToBool(member.IsClusterWriter)
+	}
+	for _, inst := range instances.DBInstances {
+		if writers[aws.ToString(inst.DBInstanceIdentifier)] {
+			instanceClass = aws.ToString(inst.DBInstanceClass)
+		}
+	}
+
+	cloneID := cloneClusterID(sourceID, time.Now())
+	input := &rds.RestoreDBClusterToPointInTimeInput{
+		DBClusterIdentifier:       aws.String(cloneID),
+		SourceDBClusterIdentifier: aws.String(sourceID),
+		RestoreType:               aws.String("copy-on-write"),
+		UseLatestRestorableTime:   aws.Bool(true),
+		DBSubnetGroupName:         source.DBSubnetGroup,
+		DeletionProtection:        aws.Bool(false),
+		Tags:                      c.rdsIdentityTags(rdstypes.Tag{Key: aws.String(CloneTagKey), Value: aws.String(sourceID)}),
+	}
+	for _, sg := range source.VpcSecurityGroups {
+		input.VpcSecurityGroupIds = append(input.VpcSecurityGroupIds, aws.ToString(sg.VpcSecurityGroupId))
+	}
+	if sc := source.ServerlessV2ScalingConfiguration; sc != nil {
+		input.ServerlessV2ScalingConfiguration = &rdstypes.ServerlessV2ScalingConfiguration{
+			MinCapacity: sc.MinCapacity,
+			MaxCapacity: sc.MaxCapacity,
+		}
+	}
+	if _, err := c.rds.RestoreDBClusterToPointInTime(ctx, input); err != nil {
+		return "", fmt.Errorf("failed to clone DB cluster %s: %w", sourceID, err)
+	}
+
+	// A cluster without instances has no endpoint to connect to
+	_, err = c.rds.CreateDBInstance(ctx, &rds.CreateDBInstanceInput{
+		DBInstanceIdentifier: aws.String(cloneID + "-1"),
+		DBClusterIdentifier:  aws.String(cloneID),
+		DBInstanceClass:      aws.String(instanceClass),
+		Engine:               source.Engine,
+		Tags:                 c.rdsIdentityTags(rdstypes.Tag{Key: aws.String(CloneTagKey), Value: aws.String(sourceID)}),
+	})
+	if err != nil {
+		return cloneID, fmt.Errorf("clone cluster %s was created, but adding its instance failed (add one or delete the cluster): %w", cloneID, err)
+	}
+	return cloneID, nil
+}
+
+// GetCloneStatus reports a clone's progress as a job status: COMPLETED once
+// the cluster and all its instances are available, with the endpoint to
+// connect to in the status message.
+func (c *BackupClient) GetCloneStatus(ctx context.Context, cloneID string) (*RestoreJobStatus, error) {
+	out, err := c.rds.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(cloneID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe DB cluster: %w", err)
+	}
+	if len(out.DBClusters) == 0 {
+		return nil, fmt.Errorf("DB cluster not found: %s", cloneID)
+	}
+	cl := out.DBClusters[0]
+	status := &RestoreJobStatus{
+		JobID:        cloneID,
+		Status:       aws.ToString(cl.Status),
+		ResourceType: "RDS",
+		ResourceID:   cloneID,
+		CreatedAt:    aws.ToTime(cl.ClusterCreateTime),
+	}
+
+	switch status.Status {
+	case "failed", "inaccessible-encryption-credentials", "incompatible-network", "incompatible-parameters", "incompatible-restore":
+		status.Status = "FAILED"
+		status.StatusMessage = "cluster is " + aws.ToString(cl.Status)
+		status.IsTerminal = true
+		return status, nil
+	case "available":
+	default:
+		return status, nil
+	}
+
+	instances, err := c.rds.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		Filters: []rdstypes.Filter{{Name: aws.String("db-cluster-id"), Values: []string{cloneID}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe DB instances: %w", err)
+	}
+	if len(instances.DBInstances) == 0 {
+		status.Status = "no instance"
+		status.StatusMessage = "the clone has no DB instance to connect to"
+		return status, nil
+	}
+	for _, inst := range instances.DBInstances {
+		if s := aws.ToString(inst.DBInstanceStatus); s != "available" {
+			status.Status = "instance " + s
+			return status, nil
+		}
+	}
+	status.Status = "COMPLETED"
+	status.StatusMessage = "endpoint " + aws.ToString(cl.Endpoint)
+	status.IsTerminal = true
+	return status, nil
+}
@@ -0,0 +1,124 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+func TestCloneClusterID(t *testing.T) {
+	now := time.Date(2026, 3, 31, 9, 5, 0, 0, time.UTC)
+	if got := cloneClusterID("my-cluster", now); got != "my-cluster-clone-20260331-0905" {
+		t.Errorf("cloneClusterID = %q", got)
+	}
+	long := strings.Repeat("a", 45) + "-" + strings.Repeat("b", 20)
+	if got := cloneClusterID(long, now); len(got) > 63 || strings.Contains(got, "--") {
+		t.Errorf("long IDs should be truncated without double hyphens, got %q", got)
+	}
+}
+
+func TestCloneCluster(t *testing.T) {
+	rdsMock := &mockRDS{
+		describeClustersOutput: &rds.DescribeDBClustersOutput{DBClusters: []rdstypes.DBCluster{{
+			DBClusterIdentifier: aws.String("my-cluster"),
+			DBSubnetGroup:       aws.String("db-subnets"),
+			Engine:              aws.String("aurora-mysql"),
+			VpcSecurityGroups:   []rdstypes.VpcSecurityGroupMembership{{VpcSecurityGroupId: aws.String("sg-1")}},
+			ServerlessV2ScalingConfiguration: &rdstypes.ServerlessV2ScalingConfigurationInfo{
+				MinCapacity: aws.Float64(0.5), MaxCapacity: aws.Float64(16),
+			},
+			DBClusterMembers: []rdstypes.DBClusterMember{
+				{DBInstanceIdentifier: aws.String("reader"), IsClusterWriter: aws.Bool(false)},
+				{DBInstanceIdentifier: aws.String("writer"), IsClusterWriter: aws.Bool(true)},
+			},
+		}}},
+		describeInstancesOutput: &rds.DescribeDBInstancesOutput{DBInstances: []rdstypes.DBInstance{
+			{DBInstanceIdentifier: aws.String("reader"), DBInstanceClass: aws.String("db.r6g.large")},
+			{DBInstanceIdentifier: aws.String("writer"), DBInstanceClass: aws.String("db.r6g.xlarge")},
+		}},
+	}
+	c := newTestClient(clusterStackMock(), &mockBackup{}, rdsMock)
+
+	cloneID, err := c.CloneCluster(context.Background(), "TestStack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := rdsMock.cloneInput
+	if !strings.HasPrefix(cloneID, "my-cluster-clone-") || aws.ToString(in.RestoreType) != "copy-on-write" ||
+		!aws.ToBool(in.UseLatestRestorableTime) || aws.ToString(in.DBSubnetGroupName) != "db-subnets" ||
+		len(in.VpcSecurityGroupIds) != 1 || aws.ToFloat64(in.ServerlessV2ScalingConfiguration.MaxCapacity) != 16 {
+		t.Errorf("unexpected clone input: %+v", in)
+	}
+	if inst := rdsMock.createInstanceInput; aws.ToString(inst.DBClusterIdentifier) != cloneID || aws.ToString(inst.DBInstanceClass) != "db.r6g.xlarge" {
+		t.Errorf("clone instance should use the source writer's class: %+v", inst)
+	}
+
+	rdsMock.createInstanceErr = errors.New("quota exceeded")
+	cloneID, err = c.CloneCluster(context.Background(), "TestStack")
+	if err == nil || cloneID == "" || !strings.Contains(err.Error(), "was created") {
+		t.Errorf("a failed instance should report the created cluster, got %q, %v", cloneID, err)
+	}
+}
+
+func TestGetJobStatus_Clone(t *testing.T) {
+	rdsMock := &mockRDS{
+		describeClustersOutput: &rds.DescribeDBClustersOutput{DBClusters: []rdstypes.DBCluster{{
+			Status:   aws.String("available"),
+			Endpoint: aws.String("clone.cluster-x.us-west-2.rds.amazonaws.com"),
+		}}},
+		describeInstancesOutput: &rds.DescribeDBInstancesOutput{DBInstances: []rdstypes.DBInstance{
+			{DBInstanceStatus: aws.String("creating")},
+		}},
+	}
+	c := newTestClient(&mockCFN{}, &mockBackup{}, rdsMock)
+
+	status, err := c.GetJobStatus(context.Background(), JobKindClone, "clone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.IsTerminal || status.Status != "instance creating" {
+		t.Errorf("clone is not usable until its instance is available: %+v", status)
+	}
+
+	rdsMock.describeInstancesOutput.DBInstances[0].DBInstanceStatus = aws.String("available")
+	status, _ = c.GetJobStatus(context.Background(), JobKindClone, "clone")
+	if !status.IsTerminal || status.Status != "COMPLETED" || !strings.Contains(status.StatusMessage, "clone.cluster-x") {
+		t.Errorf("available clone should complete with its endpoint: %+v", status)
+	}
+
+	rdsMock.describeClustersOutput.DBClusters[0].Status = aws.String("incompatible-restore")
+	status, _ = c.GetJobStatus(context.Background(), JobKindClone, "clone")
+	if !status.IsTerminal || status.Status != "FAILED" {
+		t.Errorf("incompatible-restore should fail the clone: %+v", status)
+	}
+}
+
+func TestSimulatedClone(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	fx.Restore = FixtureRestore{DurationSeconds: 100, Outcome: "COMPLETED"}
+	c := NewSimulatedBackupClient(fx)
+	sim := c.rds.(*simulatedAWS)
+	start := time.Now()
+	sim.now = func() time.Time { return start }
+
+	cloneID, err := c.CloneCluster(context.Background(), fx.Stacks[0].Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := c.GetJobStatus(context.Background(), JobKindClone, cloneID)
+	if err != nil || status.IsTerminal {
+		t.Fatalf("new clone should be creating: %+v, %v", status, err)
+	}
+
+	sim.now = func() time.Time { return start.Add(2 * time.Minute) }
+	status, _ = c.GetJobStatus(context.Background(), JobKindClone, cloneID)
+	if status.Status != "COMPLETED" {
+		t.Errorf("clone should be usable after the restore duration: %+v", status)
+	}
+}
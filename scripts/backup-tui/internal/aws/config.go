@@ -7,29 +7,52 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/smithy-go/middleware"
 )
 
-// loadAWSConfig loads AWS configuration for the specified region.
-// This function uses the default credential chain, which checks:
+// loadAWSConfig loads AWS configuration for the specified region, with the
+// credentials of the auth provider. AuthAuto uses the default credential
+// chain, which checks:
 // 1. Environment variables (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, etc.)
-// 2. AWS credentials file (~/.aws/credentials)
-// 3. IAM role credentials (if running on EC2/ECS/Lambda)
-// 4. AWS SSO credentials
+// 2. Web identity (AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN, e.g. EKS IRSA)
+// 3. AWS credentials file (~/.aws/credentials) and SSO profiles
+// 4. IAM role credentials (if running on EC2/ECS/Lambda)
+// The other providers use only their own source (see auth.go).
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout
 //   - region: AWS region name (e.g., "us-west-2")
+//   - auth: Where the credentials come from (the zero value for AuthAuto
+//     with AWS_PROFILE)
 //
 // Returns:
 //   - aws.Config: Configured AWS config with the specified region
-//   - error: Error if configuration fails
+//   - error: *AuthError if the provider's credentials cannot be loaded
 //
 // Note: This function should be called once per application startup to
 // create a shared config that can be used for all AWS service clients.
-func loadAWSConfig(ctx context.Context, region string) (aws.Config, error) {
-	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+// Every client built from the returned config shares the process-wide
+// per-service rate limiter (see ratelimit.go) and API call counter (see
+// apicalls.go). Failures to retrieve its credentials, which surface on the
+// first call, are reported as an *AuthError.
+func loadAWSConfig(ctx context.Context, region string, auth AuthOptions) (aws.Config, error) {
+	opts, provider, profile, err := authConfig(ctx, auth)
 	if err != nil {
-		return aws.Config{}, err
+		return aws.Config{}, &AuthError{Provider: provider, Profile: profile, Err: err}
+	}
+	opts = append(opts,
+		awsconfig.WithRegion(region),
+		awsconfig.WithAPIOptions([]func(*middleware.Stack) error{defaultRateLimiter.AddToStack, defaultAPICounter.AddToStack}),
+	)
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, &AuthError{Provider: provider, Profile: profile, Err: err}
+	}
+	if provider == AuthWebIdentity {
+		cfg.Credentials = webIdentityCredentials(cfg)
+	}
+	if cfg.Credentials != nil {
+		cfg.Credentials = &authCredentials{provider: provider, profile: profile, inner: cfg.Credentials}
 	}
 	return cfg, nil
 }
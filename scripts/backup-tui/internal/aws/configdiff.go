@@ -0,0 +1,112 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the comparison of the live Aurora cluster with the
+// cluster an RDS restore creates: engine version, network placement,
+// encryption key, and Serverless v2 scaling, so drift since the backup was
+// taken is visible before a restore is submitted.
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// ClusterConfig is the cluster configuration AWS Backup recorded with an
+// RDS recovery point, which a restore recreates unless overridden.
+type ClusterConfig struct {
+	Engine           string // e.g. "aurora-mysql"
+	EngineVersion    string
+	KMSKeyID         string
+	ServerlessMinACU float64 // Zero when the backed-up cluster had no Serverless v2 scaling
+	ServerlessMaxACU float64
+}
+
+// RecordedClusterConfig returns the cluster configuration recorded with the
+// recovery point in vaultName.
+func (c *BackupClient) RecordedClusterConfig(ctx context.Context, vaultName, recoveryPointARN string) (*ClusterConfig, error) {
+	out, err := c.client.GetRecoveryPointRestoreMetadata(ctx, &backup.GetRecoveryPointRestoreMetadataInput{
+		BackupVaultName:  aws.String(vaultName),
+		RecoveryPointArn: aws.String(recoveryPointARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restore metadata: %w", err)
+	}
+	md := out.RestoreMetadata
+	cfg := &ClusterConfig{Engine: md["Engine"], EngineVersion: md["EngineVersion"], KMSKeyID: md["KmsKeyId"]}
+	if raw := md["ServerlessV2ScalingConfiguration"]; raw != "" {
+		var sc struct{ MinCapacity, MaxCapacity float64 }
+		if err := json.Unmarshal([]byte(raw), &sc); err != nil {
+			return nil, fmt.Errorf("invalid Serverless v2 scaling in restore metadata: %w", err)
+		}
+		cfg.ServerlessMinACU, cfg.ServerlessMaxACU = sc.MinCapacity, sc.MaxCapacity
+	}
+	return cfg, nil
+}
+
+// ConfigDiffRow compares one setting of the live cluster with the restored
+// one.
+type ConfigDiffRow struct {
+	Setting string
+	Live    string
+	Restore string
+	Differs bool
+}
+
+// RestoreConfigDiff compares live, the stack's running cluster, with the
+// cluster restoring with meta creates from a backup recorded as recorded.
+// The restore's subnet group, security groups, and key override what was
+// recorded; the engine version and scaling are the backup's.
+func RestoreConfigDiff(live *ClusterHealth, meta *RestoreMetadata, recorded *ClusterConfig) []ConfigDiffRow {
+	restoreKey := meta.KMSKeyID
+	if restoreKey == "" {
+		restoreKey = recorded.KMSKeyID
+	}
+	liveGroups := slices.Sorted(slices.Values(live.SecurityGroups))
+	restoreGroups := splitGroups(meta.SecurityGroups)
+
+	rows := []ConfigDiffRow{
+		{Setting: "Engine version", Live: live.EngineVersion, Restore: recorded.EngineVersion},
+		{Setting: "Subnet group", Live: live.SubnetGroup, Restore: meta.SubnetGroup},
+		{Setting: "Security groups", Live: strings.Join(liveGroups, ", "), Restore: strings.Join(restoreGroups, ", ")},
+		{Setting: "KMS key", Live: live.KMSKeyID, Restore: restoreKey},
+		{Setting: "Scaling", Live: formatScaling(live.ServerlessMinACU, live.ServerlessMaxACU),
+			Restore: formatScaling(recorded.ServerlessMinACU, recorded.ServerlessMaxACU)},
+	}
+	for i := range rows {
+		r := &rows[i]
+		r.Differs = r.Live != r.Restore
+		if r.Live == "" {
+			r.Live = "-"
+		}
+		if r.Restore == "" {
+			r.Restore = "-"
+		}
+	}
+	return rows
+}
+
+// splitGroups parses security group IDs listed comma-separated or as a JSON
+// array, as restore metadata records them, and sorts them.
+func splitGroups(s string) []string {
+	var groups []string
+	for _, g := range strings.Split(strings.Trim(s, "[]"), ",") {
+		if g = strings.Trim(strings.TrimSpace(g), `"`); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	slices.Sort(groups)
+	return groups
+}
+
+// formatScaling formats a Serverless v2 ACU range, e.g. "0.5-16 ACU".
+func formatScaling(lo, hi float64) string {
+	if hi == 0 {
+		return "not set"
+	}
+	return fmt.Sprintf("%g-%g ACU", lo, hi)
+}
@@ -0,0 +1,63 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+func TestRecordedClusterConfig(t *testing.T) {
+	b := &mockBackup{restoreMetadataOut: &backup.GetRecoveryPointRestoreMetadataOutput{RestoreMetadata: map[string]string{
+		"Engine":                           "aurora-mysql",
+		"EngineVersion":                    "8.0.mysql_aurora.3.07.1",
+		"KmsKeyId":                         "arn:aws:kms:us-west-2:123456789012:key/k1",
+		"ServerlessV2ScalingConfiguration": `{"MinCapacity":0.5,"MaxCapacity":16}`,
+	}}}
+	c := &BackupClient{client: b}
+	cfg, err := c.RecordedClusterConfig(context.Background(), "vault", "arn:rp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ClusterConfig{Engine: "aurora-mysql", EngineVersion: "8.0.mysql_aurora.3.07.1", KMSKeyID: "arn:aws:kms:us-west-2:123456789012:key/k1",
+		ServerlessMinACU: 0.5, ServerlessMaxACU: 16}
+	if *cfg != want {
+		t.Errorf("got %+v, want %+v", *cfg, want)
+	}
+
+	b.restoreMetadataOut.RestoreMetadata["ServerlessV2ScalingConfiguration"] = "not json"
+	if _, err := c.RecordedClusterConfig(context.Background(), "vault", "arn:rp"); err == nil {
+		t.Error("expected an error for malformed scaling")
+	}
+}
+
+func TestRestoreConfigDiff(t *testing.T) {
+	live := &ClusterHealth{EngineVersion: "8.0.mysql_aurora.3.08.0", SubnetGroup: "db-subnets",
+		SecurityGroups: []string{"sg-2", "sg-1"}, KMSKeyID: "key-live", ServerlessMinACU: 0.5, ServerlessMaxACU: 16}
+	meta := &RestoreMetadata{ResourceType: "RDS", SubnetGroup: "db-subnets", SecurityGroups: "sg-1,sg-2"}
+	recorded := &ClusterConfig{EngineVersion: "8.0.mysql_aurora.3.07.1", KMSKeyID: "key-live", ServerlessMinACU: 0.5, ServerlessMaxACU: 16}
+
+	differs := func(rows []ConfigDiffRow) map[string]bool {
+		d := make(map[string]bool)
+		for _, r := range rows {
+			d[r.Setting] = r.Differs
+		}
+		return d
+	}
+	d := differs(RestoreConfigDiff(live, meta, recorded))
+	if !d["Engine version"] || d["Subnet group"] || d["Security groups"] || d["KMS key"] || d["Scaling"] {
+		t.Errorf("only the engine version should differ, got %v", d)
+	}
+
+	meta.KMSKeyID = "alias/restore"
+	meta.SecurityGroups = `["sg-9"]`
+	recorded.ServerlessMaxACU = 0
+	rows := RestoreConfigDiff(live, meta, recorded)
+	d = differs(rows)
+	if !d["KMS key"] || !d["Security groups"] || !d["Scaling"] {
+		t.Errorf("overridden key and groups and missing scaling should differ, got %v", d)
+	}
+	if rows[4].Restore != "not set" || rows[2].Restore != "sg-9" {
+		t.Errorf("unexpected restore values: %+v", rows)
+	}
+}
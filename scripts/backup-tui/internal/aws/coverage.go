@@ -0,0 +1,419 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements backup coverage checks for the stack's RDS cluster and
+// EFS file systems, and a guided repair that creates or updates a backup
+// selection for any resource that no selection covers.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+)
+
+// protectedResourceTypes maps CloudFormation resource types that must be
+// backed up to the short resource type used throughout the TUI.
+var protectedResourceTypes = map[string]string{
+	"AWS::RDS::DBCluster":  "RDS",
+	"AWS::EFS::FileSystem": "EFS",
+}
+
+// coverageSelectionSuffix names the selection created by ApplyCoverageFix.
+const coverageSelectionSuffix = "-coverage-repair"
+
+// ProtectedResource is a stack resource that should be in a backup selection.
+type ProtectedResource struct {
+	Type      string // "RDS" or "EFS"
+	LogicalID string // CloudFormation logical ID
+	ARN       string // Resource ARN as used in backup selections
+}
+
+// CoverageResult reports whether a protected resource is covered by a
+// backup selection.
+type CoverageResult struct {
+	Resource      ProtectedResource
+	Covered       bool     // True when a selection lists the resource by ARN or wildcard
+	PlanName      string   // Plan of the covering selection
+	SelectionName string   // Covering selection
+	TagSelections []string // "plan/selection" entries chosen by tags, which may also cover it
+}
+
+// backupSelectionInfo is a backup selection and its resource filters.
+type backupSelectionInfo struct {
+	planID       string
+	planName     string
+	id           string
+	name         string
+	roleARN      string
+	resources    []string
+	notResources []string
+	tags         []SelectionCondition // ListOfTags: any one selects a resource
+	conditions   []SelectionCondition // Conditions: a resource must match all of them
+	tagBased     bool                 // Selects by tags or conditions, which cannot be evaluated here
+}
+
+// CheckCoverage lists the stack's RDS clusters and EFS file systems and
+// reports which are included in a backup selection of any plan.
+//
+// Selections that choose resources by tag or condition are not evaluated
+// (that would require reading every resource's tags); they are reported in
+// TagSelections so the operator can check them by hand.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout
+//   - stackName: CloudFormation stack name
+//
+// Returns:
+//   - []CoverageResult: One result per protected resource, sorted by type then ARN
+//   - error: Error if the stack or backup selections cannot be read
+func (c *BackupClient) CheckCoverage(ctx context.Context, stackName string) ([]CoverageResult, error) {
+	resources, err := c.stackProtectedResources(ctx, stackName)
+	if err != nil {
+		return nil, err
+	}
+	selections, err := c.listSelections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]CoverageResult, 0, len(resources))
+	for _, res := range resources {
+		result := CoverageResult{Resource: res}
+		for _, sel := range selections {
+			if sel.tagBased {
+				result.TagSelections = append(result.TagSelections, sel.planName+"/"+sel.name)
+				continue
+			}
+			if !result.Covered && sel.covers(res.ARN) {
+				result.Covered = true
+				result.PlanName = sel.planName
+				result.SelectionName = sel.name
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// StackResources returns the stack's RDS clusters and EFS file systems,
+// sorted by type then ARN.
+func (c *BackupClient) StackResources(ctx context.Context, stackName string) ([]ProtectedResource, error) {
+	return c.stackProtectedResources(ctx, stackName)
+}
+
+// StackARN returns the ARN (stack ID) of stackName.
+func (c *BackupClient) StackARN(ctx context.Context, stackName string) (string, error) {
+	out, err := c.cfn.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe stack: %w", err)
+	}
+	if len(out.Stacks) == 0 {
+		return "", fmt.Errorf("stack not found: %s", stackName)
+	}
+	return aws.ToString(out.Stacks[0].StackId), nil
+}
+
+// stackProtectedResources returns the stack's RDS clusters and EFS file systems.
+func (c *BackupClient) stackProtectedResources(ctx context.Context, stackName string) ([]ProtectedResource, error) {
+	var resources []ProtectedResource
+	paginator := cloudformation.NewListStackResourcesPaginator(c.cfn, &cloudformation.ListStackResourcesInput{
+		StackName: aws.String(stackName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stack resources: %w", err)
+		}
+		for _, r := range page.StackResourceSummaries {
+			short, ok := protectedResourceTypes[aws.ToString(r.ResourceType)]
+			physicalID := aws.ToString(r.PhysicalResourceId)
+			if !ok || physicalID == "" {
+				continue
+			}
+			resources = append(resources, ProtectedResource{
+				Type:      short,
+				LogicalID: aws.ToString(r.LogicalResourceId),
+				ARN:       c.resourceARN(short, physicalID),
+			})
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Type != resources[j].Type {
+			return resources[i].Type > resources[j].Type // RDS before EFS
+		}
+		return resources[i].ARN < resources[j].ARN
+	})
+	return resources, nil
+}
+
+// resourceARN builds the ARN AWS Backup uses for a cluster or file system.
+func (c *BackupClient) resourceARN(resourceType, physicalID string) string {
+	switch resourceType {
+	case "RDS":
+		return fmt.Sprintf("arn:%s:rds:%s:%s:cluster:%s", partition(c.region), c.region, c.accountID, physicalID)
+	case "EFS":
+		return fmt.Sprintf("arn:%s:elasticfilesystem:%s:%s:file-system/%s", partition(c.region), c.region, c.accountID, physicalID)
+	}
+	return physicalID
+}
+
+// partition returns the AWS partition for a region.
+func partition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	}
+	return "aws"
+}
+
+// listSelections reads every backup selection of every plan.
+func (c *BackupClient) listSelections(ctx context.Context) ([]backupSelectionInfo, error) {
+	var selections []backupSelectionInfo
+	plans := backup.NewListBackupPlansPaginator(c.client, &backup.ListBackupPlansInput{})
+	for plans.HasMorePages() {
+		page, err := plans.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list backup plans: %w", err)
+		}
+		for _, plan := range page.BackupPlansList {
+			planSelections, err := c.listPlanSelections(ctx, aws.ToString(plan.BackupPlanId), aws.ToString(plan.BackupPlanName))
+			if err != nil {
+				return nil, err
+			}
+			selections = append(selections, planSelections...)
+		}
+	}
+	return selections, nil
+}
+
+// listPlanSelections reads the selections of one backup plan.
+func (c *BackupClient) listPlanSelections(ctx context.Context, planID, planName string) ([]backupSelectionInfo, error) {
+	var selections []backupSelectionInfo
+	paginator := backup.NewListBackupSelectionsPaginator(c.client, &backup.ListBackupSelectionsInput{
+		BackupPlanId: aws.String(planID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list selections of plan %s: %w", planName, err)
+		}
+		for _, s := range page.BackupSelectionsList {
+			details, err := c.client.GetBackupSelection(ctx, &backup.GetBackupSelectionInput{
+				BackupPlanId: aws.String(planID),
+				SelectionId:  s.SelectionId,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to read selection %s: %w", aws.ToString(s.SelectionName), err)
+			}
+			info := backupSelectionInfo{
+				planID:   planID,
+				planName: planName,
+				id:       aws.ToString(s.SelectionId),
+				name:     aws.ToString(s.SelectionName),
+				roleARN:  aws.ToString(s.IamRoleArn),
+			}
+			if sel := details.BackupSelection; sel != nil {
+				info.resources = sel.Resources
+				info.notResources = sel.NotResources
+				info.tags = tagConditions(sel.ListOfTags)
+				info.conditions = selectionConditions(sel.Conditions)
+				info.tagBased = len(sel.ListOfTags) > 0 || hasConditions(sel.Conditions)
+			}
+			selections = append(selections, info)
+		}
+	}
+	return selections, nil
+}
+
+// hasConditions reports whether a selection uses tag conditions.
+func hasConditions(c *backuptypes.Conditions) bool {
+	return c != nil && (len(c.StringEquals) > 0 || len(c.StringLike) > 0 ||
+		len(c.StringNotEquals) > 0 || len(c.StringNotLike) > 0)
+}
+
+// covers reports whether the selection's resource list includes arn and its
+// exclusions do not.
+func (s backupSelectionInfo) covers(arn string) bool {
+	if s.excludes(arn) {
+		return false
+	}
+	for _, pattern := range s.resources {
+		if arnMatches(pattern, arn) {
+			return true
+		}
+	}
+	return false
+}
+
+// arnMatches matches an ARN against a selection pattern, where "*" matches
+// any run of characters (as AWS Backup selections allow).
+func arnMatches(pattern, arn string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == arn
+	}
+	re := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	ok, err := regexp.MatchString(re, arn)
+	return err == nil && ok
+}
+
+// SelectionSpec describes a backup selection's name, role, and resources.
+type SelectionSpec struct {
+	Name      string
+	RoleARN   string
+	Resources []string
+}
+
+// CoverageFix is a proposed change to a backup plan that brings uncovered
+// resources into a selection. Existing is nil when a new selection is created.
+type CoverageFix struct {
+	PlanID     string
+	PlanName   string
+	Existing   *SelectionSpec // Current repair selection, replaced by Proposed
+	existingID string
+	Proposed   SelectionSpec
+}
+
+// Diff describes the change as lines prefixed with "+" (added), "-" (removed),
+// or " " (unchanged), suitable for showing before confirmation.
+func (f *CoverageFix) Diff() []string {
+	action := "create"
+	if f.Existing != nil {
+		action = "replace"
+	}
+	lines := []string{
+		fmt.Sprintf("Backup plan %s (%s): %s selection %s", f.PlanName, f.PlanID, action, f.Proposed.Name),
+	}
+
+	existing := map[string]bool{}
+	if f.Existing != nil {
+		if f.Existing.RoleARN != f.Proposed.RoleARN {
+			lines = append(lines, "- IamRoleArn: "+f.Existing.RoleARN)
+			lines = append(lines, "+ IamRoleArn: "+f.Proposed.RoleARN)
+		} else {
+			lines = append(lines, "  IamRoleArn: "+f.Proposed.RoleARN)
+		}
+		for _, r := range f.Existing.Resources {
+			existing[r] = true
+		}
+	} else {
+		lines = append(lines, "+ IamRoleArn: "+f.Proposed.RoleARN)
+	}
+
+	for _, r := range f.Proposed.Resources {
+		if existing[r] {
+			lines = append(lines, "  Resource: "+r)
+		} else {
+			lines = append(lines, "+ Resource: "+r)
+		}
+	}
+	return lines
+}
+
+// PlanCoverageFix proposes a backup selection that covers every uncovered
+// resource in results. The selection is added to the backup plan that
+// targets vaultName (see ResolvePlanRole) and uses that plan's IAM role. If
+// the plan already has a repair selection from an earlier fix, the proposal
+// replaces it with the union of its resources and the missing ones.
+//
+// Returns nil without error when every resource is covered, and an error
+// when no backup plan targets the vault (there is nothing safe to attach to).
+func (c *BackupClient) PlanCoverageFix(ctx context.Context, stackName, vaultName string, results []CoverageResult) (*CoverageFix, error) {
+	var missing []string
+	for _, r := range results {
+		if !r.Covered {
+			missing = append(missing, r.Resource.ARN)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	pr, err := c.ResolvePlanRole(ctx, vaultName, true)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Fallback {
+		return nil, fmt.Errorf("no backup plan targets vault %s; add one to the stack before repairing coverage", vaultName)
+	}
+
+	fix := &CoverageFix{
+		PlanID:   pr.PlanID,
+		PlanName: pr.PlanName,
+		Proposed: SelectionSpec{Name: coverageSelectionName(stackName), RoleARN: pr.RoleARN},
+	}
+
+	selections, err := c.listPlanSelections(ctx, pr.PlanID, pr.PlanName)
+	if err != nil {
+		return nil, err
+	}
+	resources := map[string]bool{}
+	for _, sel := range selections {
+		if sel.name == fix.Proposed.Name && !sel.tagBased {
+			fix.Existing = &SelectionSpec{Name: sel.name, RoleARN: sel.roleARN, Resources: sel.resources}
+			fix.existingID = sel.id
+			for _, r := range sel.resources {
+				resources[r] = true
+			}
+			break
+		}
+	}
+	for _, arn := range missing {
+		resources[arn] = true
+	}
+	for r := range resources {
+		fix.Proposed.Resources = append(fix.Proposed.Resources, r)
+	}
+	sort.Strings(fix.Proposed.Resources)
+	return fix, nil
+}
+
+// coverageSelectionName derives the repair selection name from the stack,
+// within AWS Backup's 50-character limit.
+func coverageSelectionName(stackName string) string {
+	maxStack := 50 - len(coverageSelectionSuffix)
+	if len(stackName) > maxStack {
+		stackName = stackName[:maxStack]
+	}
+	return stackName + coverageSelectionSuffix
+}
+
+// ApplyCoverageFix creates the proposed backup selection. When the fix
+// replaces an existing repair selection, the new selection is created first
+// and the old one deleted afterwards, so coverage never lapses. (AWS Backup
+// selections cannot be updated in place.)
+func (c *BackupClient) ApplyCoverageFix(ctx context.Context, fix *CoverageFix) error {
+	if fix == nil {
+		return nil
+	}
+	_, err := c.client.CreateBackupSelection(ctx, &backup.CreateBackupSelectionInput{
+		BackupPlanId: aws.String(fix.PlanID),
+		BackupSelection: &backuptypes.BackupSelection{
+			SelectionName: aws.String(fix.Proposed.Name),
+			IamRoleArn:    aws.String(fix.Proposed.RoleARN),
+			Resources:     fix.Proposed.Resources,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create backup selection: %w", err)
+	}
+
+	if fix.existingID != "" {
+		_, err := c.client.DeleteBackupSelection(ctx, &backup.DeleteBackupSelectionInput{
+			BackupPlanId: aws.String(fix.PlanID),
+			SelectionId:  aws.String(fix.existingID),
+		})
+		if err != nil {
+			return fmt.Errorf("created new selection but failed to delete the old one (%s): %w", fix.existingID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const (
+	testClusterARN = "arn:aws:rds:us-west-2:123456789012:cluster:openemr-training-cluster"
+	testEFSARN     = "arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-0sim0001"
+)
+
+// newCoverageClient returns a simulated client whose default fixtures cover
+// the RDS cluster but not the EFS file system.
+func newCoverageClient(t *testing.T) (*BackupClient, *Fixtures) {
+	t.Helper()
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewSimulatedBackupClient(fx), fx
+}
+
+func TestCheckCoverage_ReportsMissingResource(t *testing.T) {
+	c, fx := newCoverageClient(t)
+
+	results, err := c.CheckCoverage(context.Background(), fx.Stacks[0].Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 protected resources, got %d", len(results))
+	}
+	if results[0].Resource.ARN != testClusterARN || !results[0].Covered {
+		t.Errorf("RDS cluster should be listed first and covered: %+v", results[0])
+	}
+	if results[0].SelectionName != "OpenemrEcsStack-selection" {
+		t.Errorf("SelectionName = %q", results[0].SelectionName)
+	}
+	if results[1].Resource.ARN != testEFSARN || results[1].Covered {
+		t.Errorf("EFS file system should be uncovered: %+v", results[1])
+	}
+}
+
+func TestCheckCoverage_WildcardsAndExclusions(t *testing.T) {
+	c, fx := newCoverageClient(t)
+	fx.Plans[0].Selections[0].Resources = []string{"arn:aws:elasticfilesystem:*:*:file-system/*", "arn:aws:rds:*"}
+	fx.Plans[0].Selections[0].NotResources = []string{testClusterARN}
+
+	results, err := c.CheckCoverage(context.Background(), fx.Stacks[0].Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Covered {
+		t.Error("NotResources should exclude the cluster")
+	}
+	if !results[1].Covered {
+		t.Error("wildcard should cover the file system")
+	}
+}
+
+func TestArnMatches(t *testing.T) {
+	tests := []struct {
+		pattern, arn string
+		want         bool
+	}{
+		{testEFSARN, testEFSARN, true},
+		{"*", testEFSARN, true},
+		{"arn:aws:elasticfilesystem:*", testEFSARN, true},
+		{"arn:aws:rds:*", testEFSARN, false},
+		{"arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-0sim0001.", testEFSARN, false},
+	}
+	for _, tt := range tests {
+		if got := arnMatches(tt.pattern, tt.arn); got != tt.want {
+			t.Errorf("arnMatches(%q, %q) = %v, want %v", tt.pattern, tt.arn, got, tt.want)
+		}
+	}
+}
+
+func TestPlanCoverageFix_CreatesSelection(t *testing.T) {
+	c, fx := newCoverageClient(t)
+	ctx := context.Background()
+	stack, vault := fx.Stacks[0].Name, fx.Vaults[0]
+
+	results, _ := c.CheckCoverage(ctx, stack)
+	fix, err := c.PlanCoverageFix(ctx, stack, vault, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fix == nil || fix.Existing != nil {
+		t.Fatalf("expected a new selection, got %+v", fix)
+	}
+	if fix.Proposed.Name != "OpenemrEcsStack-coverage-repair" {
+		t.Errorf("Name = %q", fix.Proposed.Name)
+	}
+	if fix.Proposed.RoleARN != "arn:aws:iam::123456789012:role/OpenemrEcsStack-BackupRole" {
+		t.Errorf("fix should use the plan's role, got %q", fix.Proposed.RoleARN)
+	}
+	diff := strings.Join(fix.Diff(), "\n")
+	if !strings.Contains(diff, "create selection") || !strings.Contains(diff, "+ Resource: "+testEFSARN) {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+
+	if err := c.ApplyCoverageFix(ctx, fix); err != nil {
+		t.Fatal(err)
+	}
+	results, _ = c.CheckCoverage(ctx, stack)
+	for _, r := range results {
+		if !r.Covered {
+			t.Errorf("%s should be covered after the fix", r.Resource.ARN)
+		}
+	}
+}
+
+func TestPlanCoverageFix_ReplacesExistingRepairSelection(t *testing.T) {
+	c, fx := newCoverageClient(t)
+	ctx := context.Background()
+	stack, vault := fx.Stacks[0].Name, fx.Vaults[0]
+	fx.Plans[0].Selections = append(fx.Plans[0].Selections, FixtureSelection{
+		ID:         "old-repair",
+		Name:       "OpenemrEcsStack-coverage-repair",
+		IAMRoleARN: "arn:aws:iam::123456789012:role/OpenemrEcsStack-BackupRole",
+		Resources:  []string{"arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-old"},
+	})
+
+	results, _ := c.CheckCoverage(ctx, stack)
+	fix, err := c.PlanCoverageFix(ctx, stack, vault, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fix.Existing == nil {
+		t.Fatal("expected the existing repair selection to be replaced")
+	}
+	if len(fix.Proposed.Resources) != 2 {
+		t.Errorf("proposal should keep existing resources, got %v", fix.Proposed.Resources)
+	}
+	diff := strings.Join(fix.Diff(), "\n")
+	if !strings.Contains(diff, "replace selection") || !strings.Contains(diff, "  Resource: arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-old") {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+
+	if err := c.ApplyCoverageFix(ctx, fix); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for _, sel := range fx.Plans[0].Selections {
+		if sel.Name == fix.Proposed.Name {
+			count++
+			if sel.ID == "old-repair" {
+				t.Error("old repair selection should be deleted")
+			}
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected exactly one repair selection, got %d", count)
+	}
+}
+
+func TestPlanCoverageFix_NothingMissing(t *testing.T) {
+	c, fx := newCoverageClient(t)
+	fx.Plans[0].Selections[0].Resources = []string{"*"}
+	ctx := context.Background()
+
+	results, _ := c.CheckCoverage(ctx, fx.Stacks[0].Name)
+	fix, err := c.PlanCoverageFix(ctx, fx.Stacks[0].Name, fx.Vaults[0], results)
+	if err != nil || fix != nil {
+		t.Errorf("expected no fix, got %+v, %v", fix, err)
+	}
+}
+
+func TestPlanCoverageFix_NoPlanForVault(t *testing.T) {
+	c, fx := newCoverageClient(t)
+	fx.Plans[0].Vaults = []string{"other-vault"}
+	ctx := context.Background()
+
+	results, _ := c.CheckCoverage(ctx, fx.Stacks[0].Name)
+	if _, err := c.PlanCoverageFix(ctx, fx.Stacks[0].Name, fx.Vaults[0], results); err == nil {
+		t.Error("expected error when no plan targets the vault")
+	}
+}
+
+func TestCoverageSelectionName_Truncates(t *testing.T) {
+	name := coverageSelectionName(strings.Repeat("S", 60))
+	if len(name) != 50 || !strings.HasSuffix(name, coverageSelectionSuffix) {
+		t.Errorf("name %q should be truncated to 50 characters", name)
+	}
+}
+
+func TestStackARN(t *testing.T) {
+	c, fx := newCoverageClient(t)
+	ctx := context.Background()
+
+	arn, err := c.StackARN(ctx, fx.Stacks[0].Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(arn, "arn:aws:cloudformation:us-west-2:123456789012:stack/"+fx.Stacks[0].Name+"/") {
+		t.Errorf("unexpected stack ARN %q", arn)
+	}
+	if _, err := c.StackARN(ctx, "missing-stack"); err == nil {
+		t.Error("expected an error for a stack that does not exist")
+	}
+}
@@ -0,0 +1,173 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements cross-account recovery: copying recovery points to a
+// vault in a separate recovery account, assuming a role there, and
+// restoring the copies as new resources, for when the stack's own account
+// is compromised or lost.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// crossAccountSession is the session name of the assumed recovery role,
+// as shown in the recovery account's CloudTrail.
+const crossAccountSession = "backup-tui-dr"
+
+// CrossAccountTarget is the recovery account and vault.
+type CrossAccountTarget struct {
+	AccountID  string
+	Region     string
+	VaultName  string
+	RoleARN    string // Role assumed in the recovery account
+	ExternalID string // Required by the role's trust policy, if set
+}
+
+// VaultARN returns the ARN of the recovery vault.
+func (t CrossAccountTarget) VaultARN() string {
+	return fmt.Sprintf("arn:aws:backup:%s:%s:backup-vault:%s", t.Region, t.AccountID, t.VaultName)
+}
+
+// StartCrossAccountCopy starts copying the recovery point with the given
+// ARN from vaultName to the recovery vault, with roleARN as the IAM role AWS
+// Backup copies with, and returns the copy job ID.
+func (c *BackupClient) StartCrossAccountCopy(ctx context.Context, vaultName, recoveryPointARN, roleARN string, target CrossAccountTarget) (string, error) {
+	out, err := c.client.StartCopyJob(ctx, &backup.StartCopyJobInput{
+		SourceBackupVaultName:     aws.String(vaultName),
+		RecoveryPointArn:          aws.String(recoveryPointARN),
+		DestinationBackupVaultArn: aws.String(target.VaultARN()),
+		IamRoleArn:                aws.String(roleARN),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start copy job: %w", err)
+	}
+	return aws.ToString(out.CopyJobId), nil
+}
+
+// GetCopyJobStatus queries the current status of a copy job. The recovery
+// point ARN is the copy's, in the destination vault, once it completes.
+func (c *BackupClient) GetCopyJobStatus(ctx context.Context, jobID string) (*RestoreJobStatus, error) {
+	out, err := c.client.DescribeCopyJob(ctx, &backup.DescribeCopyJobInput{CopyJobId: aws.String(jobID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe copy job: %w", err)
+	}
+	job := out.CopyJob
+	if job == nil {
+		return nil, fmt.Errorf("copy job %s not found", jobID)
+	}
+	status := &RestoreJobStatus{
+		JobID:            aws.ToString(job.CopyJobId),
+		Status:           string(job.State),
+		ResourceType:     aws.ToString(job.ResourceType),
+		ResourceID:       resourceName(aws.ToString(job.ResourceArn)),
+		RecoveryPointARN: aws.ToString(job.DestinationRecoveryPointArn),
+		StatusMessage:    aws.ToString(job.StatusMessage),
+		CreatedAt:        aws.ToTime(job.CreationDate),
+		CompletedAt:      aws.ToTime(job.CompletionDate),
+	}
+	switch status.Status {
+	case "COMPLETED", "FAILED", "ABORTED", "PARTIAL":
+		status.IsTerminal = true
+	}
+	return status, nil
+}
+
+// AssumeRecoveryRole returns a client for the recovery account, with the
+// credentials of its role, and checks they are for that account. In
+// simulation mode it is a simulated account with only the recovery vault.
+func (c *BackupClient) AssumeRecoveryRole(ctx context.Context, target CrossAccountTarget) (*BackupClient, error) {
+	if c.simulated {
+		return c.simulatedRecoveryClient(target), nil
+	}
+	cfg, err := loadAWSConfig(ctx, target.Region, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), target.RoleARN,
+		func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = crossAccountSession
+			if target.ExternalID != "" {
+				o.ExternalID = aws.String(target.ExternalID)
+			}
+		}))
+	recovery, err := newBackupClientFromConfig(ctx, cfg, target.Region, c.auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume %s: %w", target.RoleARN, err)
+	}
+	if recovery.accountID != target.AccountID {
+		return nil, fmt.Errorf("role %s is in account %s, not the recovery account %s", target.RoleARN, recovery.accountID, target.AccountID)
+	}
+	return recovery, nil
+}
+
+// StartSandboxRestore restores rp, a recovery point in this client's
+// account, as new resources alongside whatever exists there: an Aurora
+// cluster named after the restore time, or a new EFS file system. The
+// restore metadata AWS Backup recorded with the recovery point is the base,
+// so nothing of the stack's own account is referenced; opts can place an
+// Aurora cluster in a subnet group and security groups. roleARN is the IAM
+// role AWS Backup restores with, the account's AWSBackupDefaultServiceRole
+// when empty. It returns the restore job ID.
+func (c *BackupClient) StartSandboxRestore(ctx context.Context, vaultName string, rp RecoveryPoint, roleARN string, opts RestoreOptions) (string, error) {
+	if roleARN == "" {
+		roleARN = fmt.Sprintf("arn:aws:iam::%s:role/service-role/AWSBackupDefaultServiceRole", c.accountID)
+	}
+	out, err := c.client.GetRecoveryPointRestoreMetadata(ctx, &backup.GetRecoveryPointRestoreMetadataInput{
+		BackupVaultName:  aws.String(vaultName),
+		RecoveryPointArn: aws.String(rp.RecoveryPointARN),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get restore metadata: %w", err)
+	}
+	metadata := sandboxRestoreMetadata(out.RestoreMetadata, rp.ResourceType, time.Now())
+	if err := applyRestoreOptions(metadata, rp.ResourceType, opts); err != nil {
+		return "", err
+	}
+
+	result, err := c.client.StartRestoreJob(ctx, &backup.StartRestoreJobInput{
+		RecoveryPointArn: aws.String(rp.RecoveryPointARN),
+		IamRoleArn:       aws.String(roleARN),
+		Metadata:         metadata,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start restore job: %w", err)
+	}
+	return aws.ToString(result.RestoreJobId), nil
+}
+
+// sandboxRestoreMetadata adapts the metadata recorded with a recovery point
+// to restore it as new resources: a new cluster identifier, or a new file
+// system rather than the original one, which does not exist in the
+// recovery account.
+func sandboxRestoreMetadata(recorded map[string]string, resourceType string, now time.Time) map[string]string {
+	metadata := make(map[string]string, len(recorded))
+	for k, v := range recorded {
+		metadata[k] = v
+	}
+	// Keys and Availability Zones of the stack's account mean nothing here;
+	// the copy's own encryption applies unless a key is given
+	delete(metadata, "KmsKeyId")
+	delete(metadata, "AvailabilityZones")
+	stamp := now.UTC().Format("20060102-150405")
+	switch resourceType {
+	case "RDS", "Aurora":
+		metadata["DBClusterIdentifier"] = "openemr-dr-" + stamp
+		// The original's network does not exist here; the account's
+		// default VPC is used unless a subnet group is given
+		delete(metadata, "DBSubnetGroupName")
+		delete(metadata, "VpcSecurityGroupIds")
+	case "EFS":
+		delete(metadata, "file-system-id")
+		metadata["newFileSystem"] = "true"
+		metadata["Encrypted"] = "true"
+		metadata["PerformanceMode"] = "generalPurpose"
+		metadata["CreationToken"] = "backup-tui-dr-" + stamp
+	}
+	return metadata
+}
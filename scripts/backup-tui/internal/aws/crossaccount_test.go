@@ -0,0 +1,128 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+)
+
+var testTarget = CrossAccountTarget{
+	AccountID: "210987654321",
+	Region:    "us-east-1",
+	VaultName: "openemr-dr",
+	RoleARN:   "arn:aws:iam::210987654321:role/backup-tui-dr",
+}
+
+func TestStartCrossAccountCopy(t *testing.T) {
+	m := &mockBackup{}
+	c := &BackupClient{client: m}
+
+	id, err := c.StartCrossAccountCopy(context.Background(), "vault", "rp-1", "arn:aws:iam::123456789012:role/BackupRole", testTarget)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "copy-1" {
+		t.Errorf("id = %q, want copy-1", id)
+	}
+	in := m.startCopyInput
+	if got, want := aws.ToString(in.DestinationBackupVaultArn), "arn:aws:backup:us-east-1:210987654321:backup-vault:openemr-dr"; got != want {
+		t.Errorf("destination = %q, want %q", got, want)
+	}
+	if aws.ToString(in.SourceBackupVaultName) != "vault" || aws.ToString(in.RecoveryPointArn) != "rp-1" || aws.ToString(in.IamRoleArn) == "" {
+		t.Errorf("unexpected copy input %+v", in)
+	}
+}
+
+func TestGetCopyJobStatus(t *testing.T) {
+	c := &BackupClient{client: &mockBackup{describeCopyOut: &backup.DescribeCopyJobOutput{CopyJob: &backuptypes.CopyJob{
+		CopyJobId:                   aws.String("copy-1"),
+		State:                       backuptypes.CopyJobStateCompleted,
+		ResourceType:                aws.String("EFS"),
+		ResourceArn:                 aws.String("arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-1"),
+		DestinationRecoveryPointArn: aws.String("arn:aws:backup:us-east-1:210987654321:recovery-point:rp-2"),
+	}}}}
+
+	status, err := c.GetJobStatus(context.Background(), JobKindCopy, "copy-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.IsTerminal || status.ResourceID != "fs-1" || !strings.HasSuffix(status.RecoveryPointARN, "rp-2") {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestSandboxRestoreMetadata(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rds := sandboxRestoreMetadata(map[string]string{
+		"DBClusterIdentifier": "openemr-db",
+		"Engine":              "aurora-mysql",
+		"DBSubnetGroupName":   "live-subnets",
+		"VpcSecurityGroupIds": `["sg-live"]`,
+		"KmsKeyId":            "arn:aws:kms:us-west-2:123456789012:key/k1",
+	}, "RDS", now)
+	if rds["DBClusterIdentifier"] != "openemr-dr-20260301-120000" || rds["Engine"] != "aurora-mysql" {
+		t.Errorf("cluster should be new and keep its engine: %v", rds)
+	}
+	for _, k := range []string{"DBSubnetGroupName", "VpcSecurityGroupIds", "KmsKeyId"} {
+		if _, ok := rds[k]; ok {
+			t.Errorf("%s of the stack's account should be dropped", k)
+		}
+	}
+
+	efs := sandboxRestoreMetadata(map[string]string{"file-system-id": "fs-1", "newFileSystem": "false"}, "EFS", now)
+	if _, ok := efs["file-system-id"]; ok || efs["newFileSystem"] != "true" || efs["CreationToken"] == "" {
+		t.Errorf("EFS should restore to a new file system: %v", efs)
+	}
+}
+
+func TestSimulatedClient_CrossAccountRecovery(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	sim := c.client.(*simulatedAWS)
+	start := time.Now()
+	sim.now = func() time.Time { return start }
+	ctx := context.Background()
+	target := testTarget
+	target.Region = fx.Region
+	source := fx.RecoveryPoints[fx.Vaults[0]][0]
+
+	if _, err := c.StartCrossAccountCopy(ctx, fx.Vaults[0], source.RecoveryPointARN, "role", target); err == nil {
+		t.Error("copy should be refused before the recovery account grants access")
+	}
+
+	recovery, err := c.AssumeRecoveryRole(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recovery.DescribeVault(ctx, target.VaultName); err != nil {
+		t.Fatalf("recovery vault should exist: %v", err)
+	}
+	id, err := c.StartCrossAccountCopy(ctx, fx.Vaults[0], source.RecoveryPointARN, "role", target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, _ := c.GetJobStatus(ctx, JobKindCopy, id)
+	if status.IsTerminal {
+		t.Fatalf("new copy should be running: %+v", status)
+	}
+
+	sim.now = func() time.Time { return start.Add(2 * time.Minute) }
+	status, _ = c.GetJobStatus(ctx, JobKindCopy, id)
+	if status.Status != "COMPLETED" || !strings.Contains(status.RecoveryPointARN, target.AccountID) {
+		t.Fatalf("copy should complete into the recovery account: %+v", status)
+	}
+	points, err := recovery.ListRecoveryPoints(ctx, target.VaultName, "")
+	if err != nil || len(points) != 1 || points[0].RecoveryPointARN != status.RecoveryPointARN {
+		t.Fatalf("copy should be listed in the recovery vault: %+v, %v", points, err)
+	}
+
+	rp := RecoveryPoint{RecoveryPointARN: status.RecoveryPointARN, ResourceType: source.ResourceType}
+	if _, err := recovery.StartSandboxRestore(ctx, target.VaultName, rp, "", RestoreOptions{}); err != nil {
+		t.Errorf("copy should be restorable in the recovery account: %v", err)
+	}
+}
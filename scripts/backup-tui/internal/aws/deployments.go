@@ -0,0 +1,176 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the ECS service deployment history of the stack's
+// services, merged with backups and restores in the TUI's timeline view.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// maxServiceRevisionsPerCall is the DescribeServiceRevisions batch limit.
+const maxServiceRevisionsPerCall = 20
+
+// Deployment is an ECS service deployment.
+type Deployment struct {
+	Service        string // Service name
+	ARN            string
+	Status         string // e.g. "SUCCESSFUL", "ROLLBACK_SUCCESSFUL", "IN_PROGRESS"
+	StatusReason   string
+	CreatedAt      time.Time
+	FinishedAt     time.Time // Zero while in progress
+	TaskDefinition string    // Task definition ARN deployed, when known
+	Image          string    // OpenEMR image deployed, when known
+
+	serviceARN string
+}
+
+// Failed reports whether the deployment did not roll out its target.
+func (d Deployment) Failed() bool {
+	return d.Status == "STOPPED" || strings.HasPrefix(d.Status, "ROLLBACK")
+}
+
+// ServiceDeployments returns the deployments of the stack's ECS services
+// created since the given time, oldest first.
+func (c *BackupClient) ServiceDeployments(ctx context.Context, stackName string, since time.Time) ([]Deployment, error) {
+	if c.ecs == nil {
+		return nil, fmt.Errorf("ECS client not configured")
+	}
+
+	services, err := c.stackServices(ctx, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	var deployments []Deployment
+	var targets []string // Target service revision ARN of each deployment
+	for _, serviceARN := range services {
+		cluster, name := splitServiceARN(serviceARN)
+		input := &ecs.ListServiceDeploymentsInput{
+			Service:   aws.String(name),
+			CreatedAt: &ecstypes.CreatedAt{After: aws.Time(since)},
+		}
+		if cluster != "" {
+			input.Cluster = aws.String(cluster)
+		}
+		for {
+			page, err := c.ecs.ListServiceDeployments(ctx, input)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list deployments of %s: %w", name, err)
+			}
+			for _, d := range page.ServiceDeployments {
+				deployments = append(deployments, Deployment{
+					Service:      name,
+					serviceARN:   serviceARN,
+					ARN:          aws.ToString(d.ServiceDeploymentArn),
+					Status:       string(d.Status),
+					StatusReason: aws.ToString(d.StatusReason),
+					CreatedAt:    aws.ToTime(d.CreatedAt),
+					FinishedAt:   aws.ToTime(d.FinishedAt),
+				})
+				targets = append(targets, aws.ToString(d.TargetServiceRevisionArn))
+			}
+			if page.NextToken == nil {
+				break
+			}
+			input.NextToken = page.NextToken
+		}
+	}
+
+	// Resolve each target revision to the task definition and image it ran
+	revisions, err := c.describeServiceRevisions(ctx, targets)
+	if err != nil {
+		return nil, err
+	}
+	for i, arn := range targets {
+		if rev, ok := revisions[arn]; ok {
+			deployments[i].TaskDefinition, deployments[i].Image = rev.taskDefinition, rev.image
+		}
+	}
+
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].CreatedAt.Before(deployments[j].CreatedAt) })
+	return deployments, nil
+}
+
+// stackServices returns the ARNs of the stack's ECS services.
+func (c *BackupClient) stackServices(ctx context.Context, stackName string) ([]string, error) {
+	var services []string
+	paginator := cloudformation.NewListStackResourcesPaginator(c.cfn, &cloudformation.ListStackResourcesInput{
+		StackName: aws.String(stackName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stack resources: %w", err)
+		}
+		for _, r := range page.StackResourceSummaries {
+			if aws.ToString(r.ResourceType) == "AWS::ECS::Service" && aws.ToString(r.PhysicalResourceId) != "" {
+				services = append(services, aws.ToString(r.PhysicalResourceId))
+			}
+		}
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no ECS service found in stack %s", stackName)
+	}
+	return services, nil
+}
+
+// serviceRevision is what a service revision deploys.
+type serviceRevision struct {
+	taskDefinition string
+	image          string // OpenEMR image
+}
+
+// describeServiceRevisions looks up the given service revisions, skipping
+// empty and repeated ARNs.
+func (c *BackupClient) describeServiceRevisions(ctx context.Context, arns []string) (map[string]serviceRevision, error) {
+	var unique []string
+	seen := map[string]bool{"": true}
+	for _, arn := range arns {
+		if !seen[arn] {
+			seen[arn] = true
+			unique = append(unique, arn)
+		}
+	}
+
+	revisions := make(map[string]serviceRevision, len(unique))
+	for start := 0; start < len(unique); start += maxServiceRevisionsPerCall {
+		batch := unique[start:min(start+maxServiceRevisionsPerCall, len(unique))]
+		out, err := c.ecs.DescribeServiceRevisions(ctx, &ecs.DescribeServiceRevisionsInput{ServiceRevisionArns: batch})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe service revisions: %w", err)
+		}
+		for _, r := range out.ServiceRevisions {
+			rev := serviceRevision{taskDefinition: aws.ToString(r.TaskDefinition)}
+			for _, img := range r.ContainerImages {
+				if rev.image == "" || strings.Contains(strings.ToLower(aws.ToString(img.Image)), "openemr") {
+					rev.image = aws.ToString(img.Image)
+				}
+			}
+			revisions[aws.ToString(r.ServiceRevisionArn)] = rev
+		}
+	}
+	return revisions, nil
+}
+
+// splitServiceARN returns the cluster and service name from a service ARN
+// ("arn:aws:ecs:region:account:service/cluster/name"). Old-format ARNs
+// without a cluster return an empty cluster (the default cluster).
+func splitServiceARN(arn string) (cluster, name string) {
+	_, resource, _ := strings.Cut(arn, ":service/")
+	if resource == "" {
+		return "", arn
+	}
+	if c, n, ok := strings.Cut(resource, "/"); ok {
+		return c, n
+	}
+	return "", resource
+}
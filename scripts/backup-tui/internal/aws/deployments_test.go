@@ -0,0 +1,81 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+func TestServiceDeployments(t *testing.T) {
+	now := time.Now()
+	cfnMock := &mockCFN{listResourcesOutput: &cloudformation.ListStackResourcesOutput{
+		StackResourceSummaries: []cfntypes.StackResourceSummary{{
+			ResourceType:       aws.String("AWS::ECS::Service"),
+			PhysicalResourceId: aws.String("arn:aws:ecs:us-west-2:123456789012:service/my-cluster/openemr"),
+		}},
+	}}
+	c := newTestClient(cfnMock, &mockBackup{}, &mockRDS{})
+	ecsMock := &mockECS{
+		deployments: map[string][]ecstypes.ServiceDeploymentBrief{"openemr": {
+			{ServiceDeploymentArn: aws.String("dep-2"), Status: ecstypes.ServiceDeploymentStatusSuccessful,
+				CreatedAt: aws.Time(now.Add(-time.Hour)), FinishedAt: aws.Time(now), TargetServiceRevisionArn: aws.String("rev-2")},
+			{ServiceDeploymentArn: aws.String("dep-1"), Status: ecstypes.ServiceDeploymentStatusRollbackSuccessful,
+				CreatedAt: aws.Time(now.Add(-2 * time.Hour)), TargetServiceRevisionArn: aws.String("rev-2")},
+		}},
+		revisions: []ecstypes.ServiceRevision{{
+			ServiceRevisionArn: aws.String("rev-2"),
+			TaskDefinition:     aws.String("arn:aws:ecs:us-west-2:123456789012:task-definition/app:2"),
+			ContainerImages: []ecstypes.ContainerImage{
+				{Image: aws.String("nginx:1.27")},
+				{Image: aws.String("openemr/openemr:7.0.3")},
+			},
+		}},
+	}
+	c.ecs = ecsMock
+
+	deployments, err := c.ServiceDeployments(context.Background(), "TestStack", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deployments) != 2 || deployments[0].ARN != "dep-1" {
+		t.Fatalf("deployments should be oldest first: %+v", deployments)
+	}
+	if !deployments[0].Failed() || deployments[1].Failed() {
+		t.Error("only the rolled back deployment should count as failed")
+	}
+	if d := deployments[1]; d.Service != "openemr" || d.Image != "openemr/openemr:7.0.3" || d.TaskDefinition == "" {
+		t.Errorf("unexpected deployment: %+v", d)
+	}
+	if len(ecsMock.revisionsIn) != 1 || len(ecsMock.revisionsIn[0]) != 1 {
+		t.Errorf("repeated service revisions should be described once: %v", ecsMock.revisionsIn)
+	}
+}
+
+func TestServiceDeployments_NoService(t *testing.T) {
+	c := newTestClient(&mockCFN{listResourcesOutput: &cloudformation.ListStackResourcesOutput{}}, &mockBackup{}, &mockRDS{})
+	if _, err := c.ServiceDeployments(context.Background(), "TestStack", time.Now()); err == nil {
+		t.Error("expected error without an ECS client")
+	}
+	c.ecs = &mockECS{}
+	if _, err := c.ServiceDeployments(context.Background(), "TestStack", time.Now()); err == nil {
+		t.Error("expected error for a stack without services")
+	}
+}
+
+func TestSplitServiceARN(t *testing.T) {
+	tests := []struct{ arn, cluster, name string }{
+		{"arn:aws:ecs:us-west-2:123456789012:service/my-cluster/openemr", "my-cluster", "openemr"},
+		{"arn:aws:ecs:us-west-2:123456789012:service/openemr", "", "openemr"},
+		{"openemr", "", "openemr"},
+	}
+	for _, tt := range tests {
+		if cluster, name := splitServiceARN(tt.arn); cluster != tt.cluster || name != tt.name {
+			t.Errorf("splitServiceARN(%q) = %q, %q", tt.arn, cluster, name)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements recovery point details that listing a vault does not
+// return: who created the recovery point, how it is encrypted, its storage
+// class, the IAM role it was taken with, and its tags.
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// RecoveryPointDetails are the details of a recovery point from
+// DescribeRecoveryPoint and ListTags.
+type RecoveryPointDetails struct {
+	RecoveryPointARN string
+	ResourceType     string
+	CreatedBy        string // Backup plan name, or empty for on-demand backups
+	BackupRule       string // Rule of CreatedBy that took it
+	EncryptionKeyARN string
+	Encrypted        bool
+	StorageClass     string // WARM, COLD, or DELETED
+	IAMRoleARN       string
+	Tags             map[string]string
+}
+
+// OnDemand reports whether the recovery point was taken outside a backup
+// plan, e.g. by "backup-tui backup" or the console.
+func (d RecoveryPointDetails) OnDemand() bool {
+	return d.CreatedBy == ""
+}
+
+// DescribeRecoveryPointDetails returns the details of the recovery point
+// with the given ARN in vaultName.
+func (c *BackupClient) DescribeRecoveryPointDetails(ctx context.Context, vaultName, arn string) (*RecoveryPointDetails, error) {
+	out, err := c.client.DescribeRecoveryPoint(ctx, &backup.DescribeRecoveryPointInput{
+		BackupVaultName:  aws.String(vaultName),
+		RecoveryPointArn: aws.String(arn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe recovery point: %w", err)
+	}
+	d := &RecoveryPointDetails{
+		RecoveryPointARN: arn,
+		ResourceType:     aws.ToString(out.ResourceType),
+		EncryptionKeyARN: aws.ToString(out.EncryptionKeyArn),
+		Encrypted:        out.IsEncrypted,
+		StorageClass:     string(out.StorageClass),
+		IAMRoleARN:       aws.ToString(out.IamRoleArn),
+	}
+	if by := out.CreatedBy; by != nil {
+		d.CreatedBy = aws.ToString(by.BackupPlanName)
+		if d.CreatedBy == "" {
+			d.CreatedBy = aws.ToString(by.BackupPlanId)
+		}
+		d.BackupRule = aws.ToString(by.BackupRuleName)
+	}
+
+	if d.Tags, err = c.recoveryPointTags(ctx, arn); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// recoveryPointTags returns the tags of the recovery point with the given ARN.
+func (c *BackupClient) recoveryPointTags(ctx context.Context, arn string) (map[string]string, error) {
+	tags := map[string]string{}
+	var next *string
+	for {
+		out, err := c.client.ListTags(ctx, &backup.ListTagsInput{ResourceArn: aws.String(arn), NextToken: next})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list recovery point tags: %w", err)
+		}
+		for k, v := range out.Tags {
+			tags[k] = v
+		}
+		if next = out.NextToken; next == nil {
+			break
+		}
+	}
+	return tags, nil
+}
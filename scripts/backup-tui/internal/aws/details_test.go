@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+)
+
+func TestDescribeRecoveryPointDetails(t *testing.T) {
+	m := &mockBackup{
+		describeRPOut: &backup.DescribeRecoveryPointOutput{
+			EncryptionKeyArn: aws.String("arn:aws:kms:us-west-2:123456789012:key/k1"),
+			IsEncrypted:      true,
+			StorageClass:     backuptypes.StorageClassWarm,
+			IamRoleArn:       aws.String("arn:aws:iam::123456789012:role/BackupRole"),
+			CreatedBy:        &backuptypes.RecoveryPointCreator{BackupPlanName: aws.String("daily-plan"), BackupRuleName: aws.String("nightly")},
+		},
+		listTagsOut: []*backup.ListTagsOutput{
+			{Tags: map[string]string{"Environment": "prod"}, NextToken: aws.String("p2")},
+			{Tags: map[string]string{"Owner": "ops"}},
+		},
+	}
+	c := &BackupClient{client: m}
+
+	d, err := c.DescribeRecoveryPointDetails(context.Background(), "vault", "rp-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.CreatedBy != "daily-plan" || d.BackupRule != "nightly" || d.OnDemand() {
+		t.Errorf("created by = %q/%q, want daily-plan/nightly", d.CreatedBy, d.BackupRule)
+	}
+	if !d.Encrypted || d.StorageClass != "WARM" || d.IAMRoleARN == "" || d.EncryptionKeyARN == "" {
+		t.Errorf("unexpected details %+v", d)
+	}
+	if len(d.Tags) != 2 || d.Tags["Owner"] != "ops" {
+		t.Errorf("tags should be read from every page, got %v", d.Tags)
+	}
+	if len(m.listTagsInputs) != 2 || aws.ToString(m.listTagsInputs[1].NextToken) != "p2" {
+		t.Error("second tags page should be requested with the first page's token")
+	}
+}
+
+func TestDescribeRecoveryPointDetails_OnDemandAndErrors(t *testing.T) {
+	c := &BackupClient{client: &mockBackup{describeRPOut: &backup.DescribeRecoveryPointOutput{}}}
+	d, err := c.DescribeRecoveryPointDetails(context.Background(), "vault", "rp-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.OnDemand() {
+		t.Error("a recovery point without a creating plan should be on demand")
+	}
+
+	c = &BackupClient{client: &mockBackup{describeRPErr: errors.New("throttled")}}
+	if _, err := c.DescribeRecoveryPointDetails(context.Background(), "vault", "rp-1"); err == nil {
+		t.Error("expected describe error")
+	}
+}
@@ -0,0 +1,172 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the DR region scan: the recovery points that
+// cross-region copy rules copied from the stack's vault into other regions
+// of the account, per source resource, so a copy rule that stopped working
+// is noticed before the copies are needed.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// RegionCopies are the copies of a vault's recovery points found in one
+// region, per source resource.
+type RegionCopies struct {
+	Region    string         `json:"region"`
+	Vaults    []string       `json:"vaults,omitempty"` // Vaults holding copies
+	Resources []CopiedSource `json:"resources"`        // Stack resources first, then orphans
+	Err       error          `json:"-"`                // The region could not be scanned
+	Error     string         `json:"error,omitempty"`
+}
+
+// CopiedSource is a resource whose recovery points were copied into a region.
+type CopiedSource struct {
+	ResourceType string    `json:"resourceType"`
+	ResourceID   string    `json:"resourceId"`
+	ResourceARN  string    `json:"resourceArn"`
+	Copies       int       `json:"copies"`
+	Newest       time.Time `json:"newest,omitzero"` // Zero when there are no copies
+	Oldest       time.Time `json:"oldest,omitzero"`
+	// Orphaned is set for copies of a resource that is no longer in the
+	// stack, e.g. a replaced cluster; they are kept until their lifecycle
+	// deletes them.
+	Orphaned bool `json:"orphaned,omitempty"`
+}
+
+// Age returns how old the newest copy is at now, or zero without copies.
+func (s CopiedSource) Age(now time.Time) time.Duration {
+	if s.Newest.IsZero() {
+		return 0
+	}
+	return now.Sub(s.Newest)
+}
+
+// InRegion returns a client for another region of the same account and
+// credentials. In simulation mode it is served from the fixtures' region.
+func (c *BackupClient) InRegion(ctx context.Context, region string) (*BackupClient, error) {
+	if c.simulated {
+		return newSimulatedClient(c.client.(*simulatedAWS).otherRegion(region)), nil
+	}
+	cfg, err := loadAWSConfig(ctx, region, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return newBackupClientFromConfig(ctx, cfg, region, c.auth)
+}
+
+// ScanRegionCopies looks in each region, concurrently, for completed
+// recovery points copied from the vault with sourceVaultARN, and groups them
+// by source resource. Every resource in stack is listed, with no copies if
+// none were found; copies of other resources are listed as orphaned. A
+// region that cannot be scanned has Err set; the others are still scanned.
+func (c *BackupClient) ScanRegionCopies(ctx context.Context, sourceVaultARN string, stack []ProtectedResource, regions []string) []RegionCopies {
+	results := make([]RegionCopies, len(regions))
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.scanRegion(ctx, sourceVaultARN, stack, region)
+			if results[i].Err != nil {
+				results[i].Error = results[i].Err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// scanRegion scans every vault of one region for copies from sourceVaultARN.
+func (c *BackupClient) scanRegion(ctx context.Context, sourceVaultARN string, stack []ProtectedResource, region string) RegionCopies {
+	rc := RegionCopies{Region: region}
+	client, err := c.InRegion(ctx, region)
+	if err != nil {
+		rc.Err = err
+		return rc
+	}
+
+	bySource := make(map[string]*CopiedSource)
+	for _, r := range stack {
+		bySource[r.ARN] = &CopiedSource{ResourceType: r.Type, ResourceID: resourceName(r.ARN), ResourceARN: r.ARN}
+	}
+	vaults := backup.NewListBackupVaultsPaginator(client.client, &backup.ListBackupVaultsInput{})
+	for vaults.HasMorePages() {
+		page, err := vaults.NextPage(ctx)
+		if err != nil {
+			rc.Err = fmt.Errorf("failed to list backup vaults in %s: %w", region, err)
+			return rc
+		}
+		for _, v := range page.BackupVaultList {
+			name := aws.ToString(v.BackupVaultName)
+			found, err := client.copiesInVault(ctx, name, sourceVaultARN, bySource)
+			if err != nil {
+				rc.Err = fmt.Errorf("failed to list recovery points of %s in %s: %w", name, region, err)
+				return rc
+			}
+			if found {
+				rc.Vaults = append(rc.Vaults, name)
+			}
+		}
+	}
+
+	for _, r := range stack {
+		rc.Resources = append(rc.Resources, *bySource[r.ARN])
+		delete(bySource, r.ARN)
+	}
+	var orphans []CopiedSource
+	for _, s := range bySource {
+		s.Orphaned = true
+		orphans = append(orphans, *s)
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].ResourceARN < orphans[j].ResourceARN })
+	rc.Resources = append(rc.Resources, orphans...)
+	return rc
+}
+
+// copiesInVault adds the completed copies from sourceVaultARN in vaultName
+// to bySource and reports whether there were any.
+func (c *BackupClient) copiesInVault(ctx context.Context, vaultName, sourceVaultARN string, bySource map[string]*CopiedSource) (bool, error) {
+	found := false
+	points := backup.NewListRecoveryPointsByBackupVaultPaginator(c.client, &backup.ListRecoveryPointsByBackupVaultInput{
+		BackupVaultName: aws.String(vaultName),
+	})
+	for points.HasMorePages() {
+		page, err := points.NextPage(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, rp := range page.RecoveryPoints {
+			if aws.ToString(rp.SourceBackupVaultArn) != sourceVaultARN || string(rp.Status) != "COMPLETED" {
+				continue
+			}
+			found = true
+			arn := aws.ToString(rp.ResourceArn)
+			s, ok := bySource[arn]
+			if !ok {
+				s = &CopiedSource{ResourceType: aws.ToString(rp.ResourceType), ResourceID: resourceName(arn), ResourceARN: arn}
+				bySource[arn] = s
+			}
+			created := aws.ToTime(rp.CreationDate)
+			s.Copies++
+			if created.After(s.Newest) {
+				s.Newest = created
+			}
+			if s.Oldest.IsZero() || created.Before(s.Oldest) {
+				s.Oldest = created
+			}
+		}
+	}
+	return found, nil
+}
+
+// VaultARN returns the ARN of vaultName in the client's account and region.
+func (c *BackupClient) VaultARN(vaultName string) string {
+	return fmt.Sprintf("arn:%s:backup:%s:%s:backup-vault:%s", partition(c.region), c.region, c.accountID, vaultName)
+}
@@ -0,0 +1,60 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScanRegionCopies(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	stack, err := c.StackResources(ctx, fx.Stacks[0].Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := c.ScanRegionCopies(ctx, c.VaultARN(fx.Vaults[0]), stack, []string{"us-east-1", "eu-west-1"})
+	if len(results) != 2 || results[0].Region != "us-east-1" || results[1].Region != "eu-west-1" {
+		t.Fatalf("results should be in region order, got %+v", results)
+	}
+
+	dr := results[0]
+	if dr.Err != nil || len(dr.Vaults) != 1 || dr.Vaults[0] != "OpenemrEcsStack-dr-vault" {
+		t.Fatalf("us-east-1 = %+v, want copies in the DR vault", dr)
+	}
+	if len(dr.Resources) != 3 {
+		t.Fatalf("got %d resources, want the cluster, the file system, and one orphan: %+v", len(dr.Resources), dr.Resources)
+	}
+	rds, efs, orphan := dr.Resources[0], dr.Resources[1], dr.Resources[2]
+	if rds.ResourceType != "RDS" || rds.Copies != 2 || rds.Orphaned {
+		t.Errorf("cluster = %+v, want 2 copies", rds)
+	}
+	if age := rds.Age(time.Now()); age < 4*time.Hour || age > 6*time.Hour {
+		t.Errorf("newest cluster copy is %v old, want about 5h", age)
+	}
+	if efs.ResourceID != "fs-0sim0001" || efs.Copies != 1 {
+		t.Errorf("file system = %+v, want 1 copy", efs)
+	}
+	if !orphan.Orphaned || orphan.ResourceID != "openemr-training-cluster-old" {
+		t.Errorf("copies of a resource no longer in the stack should be orphaned, got %+v", orphan)
+	}
+
+	empty := results[1]
+	if empty.Err != nil || len(empty.Vaults) != 0 || len(empty.Resources) != 2 {
+		t.Fatalf("eu-west-1 = %+v, want both stack resources without copies", empty)
+	}
+	for _, r := range empty.Resources {
+		if r.Copies != 0 || !r.Newest.IsZero() || r.Age(time.Now()) != 0 {
+			t.Errorf("%s should have no copies, got %+v", r.ResourceID, r)
+		}
+	}
+}
+
+func TestVaultARN(t *testing.T) {
+	c := &BackupClient{region: "us-gov-west-1", accountID: "123456789012"}
+	if got, want := c.VaultARN("v"), "arn:aws-us-gov:backup:us-gov-west-1:123456789012:backup-vault:v"; got != want {
+		t.Errorf("VaultARN = %q, want %q", got, want)
+	}
+}
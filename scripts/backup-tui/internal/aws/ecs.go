@@ -0,0 +1,198 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the OpenEMR ECS task definition history: the stack's
+// task definition revisions with their container images and environment,
+// used to tell which application version was running when a backup was taken.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// TaskDefinitionRevision is a revision of an ECS task definition.
+type TaskDefinitionRevision struct {
+	ARN          string
+	Family       string
+	Revision     int32
+	RegisteredAt time.Time
+	Containers   []ContainerDefinition
+}
+
+// ContainerDefinition is a container in a task definition revision.
+type ContainerDefinition struct {
+	Name        string
+	Image       string            // e.g. "openemr/openemr:7.0.3"
+	Environment map[string]string // Plain environment variables (not secrets)
+}
+
+// AppContainer returns the OpenEMR container: the first container whose image
+// mentions openemr, or the first container if none does.
+func (r TaskDefinitionRevision) AppContainer() ContainerDefinition {
+	for _, c := range r.Containers {
+		if strings.Contains(strings.ToLower(c.Image), "openemr") {
+			return c
+		}
+	}
+	if len(r.Containers) > 0 {
+		return r.Containers[0]
+	}
+	return ContainerDefinition{}
+}
+
+// ImageChange is an OpenEMR image change between task definition revisions.
+type ImageChange struct {
+	At       time.Time // Registration time of the first revision with the new image
+	From, To string    // Images before and after
+	Revision int32     // First revision with the new image
+}
+
+// LastImageChange returns the most recent OpenEMR image change in history
+// (newest first), or nil if every revision in history uses the same image.
+//
+// Registration time stands in for deployment time: revisions are registered
+// by the CDK deployment that rolls them out, moments before the service
+// starts using them.
+func LastImageChange(history []TaskDefinitionRevision) *ImageChange {
+	if len(history) == 0 {
+		return nil
+	}
+	current := history[0].AppContainer().Image
+	for i := 1; i < len(history); i++ {
+		if prev := history[i].AppContainer().Image; prev != current {
+			return &ImageChange{
+				At:       history[i-1].RegisteredAt,
+				From:     prev,
+				To:       current,
+				Revision: history[i-1].Revision,
+			}
+		}
+	}
+	return nil
+}
+
+// TaskDefinitionHistory returns up to limit of the most recent revisions of
+// the stack's OpenEMR task definition family, newest first.
+func (c *BackupClient) TaskDefinitionHistory(ctx context.Context, stackName string, limit int) ([]TaskDefinitionRevision, error) {
+	if c.ecs == nil {
+		return nil, fmt.Errorf("ECS client not configured")
+	}
+
+	family, err := c.appTaskFamily(ctx, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	var arns []string
+	paginator := ecs.NewListTaskDefinitionsPaginator(c.ecs, &ecs.ListTaskDefinitionsInput{
+		FamilyPrefix: aws.String(family),
+		Sort:         ecstypes.SortOrderDesc,
+	})
+	for paginator.HasMorePages() && len(arns) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list task definitions: %w", err)
+		}
+		for _, arn := range page.TaskDefinitionArns {
+			// FamilyPrefix also matches longer family names
+			if f, _ := splitTaskDefinitionARN(arn); f == family && len(arns) < limit {
+				arns = append(arns, arn)
+			}
+		}
+	}
+
+	history := make([]TaskDefinitionRevision, 0, len(arns))
+	for _, arn := range arns {
+		rev, err := c.describeTaskDefinition(ctx, arn)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, *rev)
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Revision > history[j].Revision })
+	return history, nil
+}
+
+// appTaskFamily returns the task definition family of the stack's OpenEMR
+// service: of the stack's task definitions, the one running an openemr image.
+func (c *BackupClient) appTaskFamily(ctx context.Context, stackName string) (string, error) {
+	var candidates []string
+	paginator := cloudformation.NewListStackResourcesPaginator(c.cfn, &cloudformation.ListStackResourcesInput{
+		StackName: aws.String(stackName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list stack resources: %w", err)
+		}
+		for _, r := range page.StackResourceSummaries {
+			if aws.ToString(r.ResourceType) == "AWS::ECS::TaskDefinition" && aws.ToString(r.PhysicalResourceId) != "" {
+				candidates = append(candidates, aws.ToString(r.PhysicalResourceId))
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no ECS task definition found in stack %s", stackName)
+	}
+
+	for _, arn := range candidates {
+		rev, err := c.describeTaskDefinition(ctx, arn)
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(strings.ToLower(rev.AppContainer().Image), "openemr") {
+			return rev.Family, nil
+		}
+	}
+	family, _ := splitTaskDefinitionARN(candidates[0])
+	return family, nil
+}
+
+// describeTaskDefinition returns one task definition revision.
+func (c *BackupClient) describeTaskDefinition(ctx context.Context, arn string) (*TaskDefinitionRevision, error) {
+	out, err := c.ecs.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(arn)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe task definition %s: %w", arn, err)
+	}
+	td := out.TaskDefinition
+	if td == nil {
+		return nil, fmt.Errorf("task definition %s not found", arn)
+	}
+
+	rev := &TaskDefinitionRevision{
+		ARN:          aws.ToString(td.TaskDefinitionArn),
+		Family:       aws.ToString(td.Family),
+		Revision:     td.Revision,
+		RegisteredAt: aws.ToTime(td.RegisteredAt),
+	}
+	for _, cd := range td.ContainerDefinitions {
+		container := ContainerDefinition{
+			Name:        aws.ToString(cd.Name),
+			Image:       aws.ToString(cd.Image),
+			Environment: make(map[string]string, len(cd.Environment)),
+		}
+		for _, kv := range cd.Environment {
+			container.Environment[aws.ToString(kv.Name)] = aws.ToString(kv.Value)
+		}
+		rev.Containers = append(rev.Containers, container)
+	}
+	return rev, nil
+}
+
+// splitTaskDefinitionARN splits a task definition ARN (or "family:revision")
+// into its family and revision.
+func splitTaskDefinitionARN(arn string) (family, revision string) {
+	s := arn
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		s = s[i+1:]
+	}
+	family, revision, _ = strings.Cut(s, ":")
+	return family, revision
+}
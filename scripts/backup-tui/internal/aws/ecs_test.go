@@ -0,0 +1,142 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+type mockECS struct {
+	listArns    []string
+	defs        map[string]*ecstypes.TaskDefinition // Keyed by ARN
+	listErr     error
+	deployments map[string][]ecstypes.ServiceDeploymentBrief // Keyed by service name
+	revisions   []ecstypes.ServiceRevision
+	revisionsIn [][]string // ServiceRevisionArns of each DescribeServiceRevisions call
+}
+
+func (m *mockECS) ListTaskDefinitions(_ context.Context, _ *ecs.ListTaskDefinitionsInput, _ ...func(*ecs.Options)) (*ecs.ListTaskDefinitionsOutput, error) {
+	return &ecs.ListTaskDefinitionsOutput{TaskDefinitionArns: m.listArns}, m.listErr
+}
+
+func (m *mockECS) DescribeTaskDefinition(_ context.Context, in *ecs.DescribeTaskDefinitionInput, _ ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	td, ok := m.defs[aws.ToString(in.TaskDefinition)]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: td}, nil
+}
+
+func (m *mockECS) ListServiceDeployments(_ context.Context, in *ecs.ListServiceDeploymentsInput, _ ...func(*ecs.Options)) (*ecs.ListServiceDeploymentsOutput, error) {
+	return &ecs.ListServiceDeploymentsOutput{ServiceDeployments: m.deployments[aws.ToString(in.Service)]}, nil
+}
+
+func (m *mockECS) DescribeServiceRevisions(_ context.Context, in *ecs.DescribeServiceRevisionsInput, _ ...func(*ecs.Options)) (*ecs.DescribeServiceRevisionsOutput, error) {
+	m.revisionsIn = append(m.revisionsIn, in.ServiceRevisionArns)
+	out := &ecs.DescribeServiceRevisionsOutput{}
+	for _, r := range m.revisions {
+		for _, arn := range in.ServiceRevisionArns {
+			if aws.ToString(r.ServiceRevisionArn) == arn {
+				out.ServiceRevisions = append(out.ServiceRevisions, r)
+			}
+		}
+	}
+	return out, nil
+}
+
+func testTaskDef(family string, rev int32, image string, registered time.Time) *ecstypes.TaskDefinition {
+	return &ecstypes.TaskDefinition{
+		TaskDefinitionArn: aws.String(taskDefARN(family, rev)),
+		Family:            aws.String(family),
+		Revision:          rev,
+		RegisteredAt:      aws.Time(registered),
+		ContainerDefinitions: []ecstypes.ContainerDefinition{
+			{Name: aws.String("proxy"), Image: aws.String("nginx:1.27")},
+			{Name: aws.String("openemr"), Image: aws.String(image), Environment: []ecstypes.KeyValuePair{
+				{Name: aws.String("MYSQL_PORT"), Value: aws.String("3306")},
+			}},
+		},
+	}
+}
+
+func taskDefARN(family string, rev int32) string {
+	return fmt.Sprintf("arn:aws:ecs:us-west-2:123456789012:task-definition/%s:%d", family, rev)
+}
+
+func TestTaskDefinitionHistory(t *testing.T) {
+	now := time.Now()
+	defs := map[string]*ecstypes.TaskDefinition{}
+	for _, td := range []*ecstypes.TaskDefinition{
+		testTaskDef("app", 3, "openemr/openemr:7.0.3", now),
+		testTaskDef("app", 2, "openemr/openemr:7.0.3", now.Add(-time.Hour)),
+		testTaskDef("app", 1, "openemr/openemr:7.0.2", now.Add(-2*time.Hour)),
+		testTaskDef("app-worker", 1, "openemr/openemr:7.0.3", now),
+	} {
+		defs[aws.ToString(td.TaskDefinitionArn)] = td
+	}
+	cfnMock := &mockCFN{listResourcesOutput: &cloudformation.ListStackResourcesOutput{
+		StackResourceSummaries: []cfntypes.StackResourceSummary{{
+			ResourceType:       aws.String("AWS::ECS::TaskDefinition"),
+			PhysicalResourceId: aws.String(taskDefARN("app", 3)),
+		}},
+	}}
+	c := newTestClient(cfnMock, &mockBackup{}, &mockRDS{})
+	c.ecs = &mockECS{
+		listArns: []string{taskDefARN("app-worker", 1), taskDefARN("app", 3), taskDefARN("app", 2), taskDefARN("app", 1)},
+		defs:     defs,
+	}
+
+	history, err := c.TaskDefinitionHistory(context.Background(), "TestStack", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 || history[0].Revision != 3 {
+		t.Fatalf("expected app revisions 3..1 only, got %+v", history)
+	}
+	if app := history[0].AppContainer(); app.Name != "openemr" || app.Environment["MYSQL_PORT"] != "3306" {
+		t.Errorf("unexpected app container: %+v", app)
+	}
+
+	change := LastImageChange(history)
+	if change == nil || change.Revision != 2 || change.From != "openemr/openemr:7.0.2" || !change.At.Equal(now.Add(-time.Hour)) {
+		t.Errorf("unexpected image change: %+v", change)
+	}
+
+	limited, err := c.TaskDefinitionHistory(context.Background(), "TestStack", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limited) != 2 || LastImageChange(limited) != nil {
+		t.Errorf("limit should cap history and hide the older change: %+v", limited)
+	}
+}
+
+func TestTaskDefinitionHistory_Errors(t *testing.T) {
+	c := newTestClient(&mockCFN{listResourcesOutput: &cloudformation.ListStackResourcesOutput{}}, &mockBackup{}, &mockRDS{})
+	if _, err := c.TaskDefinitionHistory(context.Background(), "TestStack", 10); err == nil {
+		t.Error("expected error without an ECS client")
+	}
+
+	c.ecs = &mockECS{}
+	if _, err := c.TaskDefinitionHistory(context.Background(), "TestStack", 10); err == nil {
+		t.Error("expected error for a stack without task definitions")
+	}
+}
+
+func TestSplitTaskDefinitionARN(t *testing.T) {
+	family, rev := splitTaskDefinitionARN("arn:aws:ecs:us-west-2:123456789012:task-definition/app:12")
+	if family != "app" || rev != "12" {
+		t.Errorf("got %q %q", family, rev)
+	}
+	if family, rev := splitTaskDefinitionARN("app"); family != "app" || rev != "" {
+		t.Errorf("got %q %q", family, rev)
+	}
+}
@@ -0,0 +1,125 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the live EFS file system lookup shown in the detail
+// view for EFS recovery points: size, lifecycle policy, mount targets, and
+// throughput mode of the file system a restore would write into.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+)
+
+// FileSystemInfo describes a live EFS file system.
+type FileSystemInfo struct {
+	FileSystemID     string
+	Name             string
+	LifeCycleState   string // e.g. "available"
+	SizeBytes        int64  // Metered size (last hourly measurement)
+	SizeMeasuredAt   time.Time
+	PerformanceMode  string // "generalPurpose" or "maxIO"
+	ThroughputMode   string // "bursting", "provisioned", or "elastic"
+	ProvisionedMiBps float64
+	Encrypted        bool
+	Lifecycle        []string // Lifecycle rules, e.g. "TransitionToIA: AFTER_30_DAYS"
+	MountTargets     []MountTarget
+}
+
+// MountTarget is an EFS mount target.
+type MountTarget struct {
+	ID               string
+	AvailabilityZone string
+	SubnetID         string
+	IPAddress        string
+	State            string
+}
+
+// DescribeFileSystem returns the current configuration of an EFS file
+// system, including its lifecycle policy and mount targets.
+func (c *BackupClient) DescribeFileSystem(ctx context.Context, fileSystemID string) (*FileSystemInfo, error) {
+	if c.efs == nil {
+		return nil, fmt.Errorf("EFS client not configured")
+	}
+
+	out, err := c.efs.DescribeFileSystems(ctx, &efs.DescribeFileSystemsInput{FileSystemId: aws.String(fileSystemID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe file system %s: %w", fileSystemID, err)
+	}
+	if len(out.FileSystems) == 0 {
+		return nil, fmt.Errorf("file system %s not found", fileSystemID)
+	}
+	fs := out.FileSystems[0]
+
+	info := &FileSystemInfo{
+		FileSystemID:     aws.ToString(fs.FileSystemId),
+		Name:             aws.ToString(fs.Name),
+		LifeCycleState:   string(fs.LifeCycleState),
+		PerformanceMode:  string(fs.PerformanceMode),
+		ThroughputMode:   string(fs.ThroughputMode),
+		ProvisionedMiBps: aws.ToFloat64(fs.ProvisionedThroughputInMibps),
+		Encrypted:        aws.ToBool(fs.Encrypted),
+	}
+	if fs.SizeInBytes != nil {
+		info.SizeBytes = fs.SizeInBytes.Value
+		info.SizeMeasuredAt = aws.ToTime(fs.SizeInBytes.Timestamp)
+	}
+
+	lc, err := c.efs.DescribeLifecycleConfiguration(ctx, &efs.DescribeLifecycleConfigurationInput{FileSystemId: aws.String(fileSystemID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe lifecycle configuration: %w", err)
+	}
+	for _, p := range lc.LifecyclePolicies {
+		switch {
+		case p.TransitionToIA != "":
+			info.Lifecycle = append(info.Lifecycle, "TransitionToIA: "+string(p.TransitionToIA))
+		case p.TransitionToArchive != "":
+			info.Lifecycle = append(info.Lifecycle, "TransitionToArchive: "+string(p.TransitionToArchive))
+		case p.TransitionToPrimaryStorageClass != "":
+			info.Lifecycle = append(info.Lifecycle, "TransitionToPrimaryStorageClass: "+string(p.TransitionToPrimaryStorageClass))
+		}
+	}
+
+	paginator := efs.NewDescribeMountTargetsPaginator(c.efs, &efs.DescribeMountTargetsInput{FileSystemId: aws.String(fileSystemID)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe mount targets: %w", err)
+		}
+		for _, mt := range page.MountTargets {
+			info.MountTargets = append(info.MountTargets, MountTarget{
+				ID:               aws.ToString(mt.MountTargetId),
+				AvailabilityZone: aws.ToString(mt.AvailabilityZoneName),
+				SubnetID:         aws.ToString(mt.SubnetId),
+				IPAddress:        aws.ToString(mt.IpAddress),
+				State:            string(mt.LifeCycleState),
+			})
+		}
+	}
+	sort.Slice(info.MountTargets, func(i, j int) bool {
+		return info.MountTargets[i].AvailabilityZone < info.MountTargets[j].AvailabilityZone
+	})
+
+	return info, nil
+}
+
+// efsRestoreDirPrefix starts the name of the directory an in-place EFS
+// restore writes into.
+const efsRestoreDirPrefix = "aws-backup-restore_"
+
+// EFSRestoreDirectory returns the directory a completed in-place restore of
+// rp wrote into, at the root of the file system, e.g.
+// "/aws-backup-restore_2026-03-01T10-00-00". AWS Backup names it after the
+// time the restore job was created, in UTC. It returns "" for restores into
+// a new file system, whose root holds the restored files, and for restores
+// that have not completed.
+func EFSRestoreDirectory(rp RecoveryPoint, status *RestoreJobStatus) string {
+	if rp.ResourceType != "EFS" || status == nil || status.Status != "COMPLETED" ||
+		status.CreatedAt.IsZero() || (status.ResourceID != "" && status.ResourceID != rp.ResourceID) {
+		return ""
+	}
+	return "/" + efsRestoreDirPrefix + status.CreatedAt.UTC().Format("2006-01-02T15-04-05")
+}
@@ -0,0 +1,149 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	efstypes "github.com/aws/aws-sdk-go-v2/service/efs/types"
+)
+
+type mockEFS struct {
+	describeOut     *efs.DescribeFileSystemsOutput
+	describeErr     error
+	lifecycleOut    *efs.DescribeLifecycleConfigurationOutput
+	mountTargetsOut *efs.DescribeMountTargetsOutput
+	tagInput        *efs.TagResourceInput
+	deleted         string
+	deletedTargets  []string
+}
+
+func (m *mockEFS) DeleteMountTarget(_ context.Context, in *efs.DeleteMountTargetInput, _ ...func(*efs.Options)) (*efs.DeleteMountTargetOutput, error) {
+	m.deletedTargets = append(m.deletedTargets, aws.ToString(in.MountTargetId))
+	return &efs.DeleteMountTargetOutput{}, nil
+}
+
+func (m *mockEFS) DeleteFileSystem(_ context.Context, in *efs.DeleteFileSystemInput, _ ...func(*efs.Options)) (*efs.DeleteFileSystemOutput, error) {
+	m.deleted = aws.ToString(in.FileSystemId)
+	return &efs.DeleteFileSystemOutput{}, nil
+}
+
+func (m *mockEFS) TagResource(_ context.Context, in *efs.TagResourceInput, _ ...func(*efs.Options)) (*efs.TagResourceOutput, error) {
+	m.tagInput = in
+	return &efs.TagResourceOutput{}, nil
+}
+
+func (m *mockEFS) DescribeFileSystems(_ context.Context, _ *efs.DescribeFileSystemsInput, _ ...func(*efs.Options)) (*efs.DescribeFileSystemsOutput, error) {
+	return m.describeOut, m.describeErr
+}
+
+func (m *mockEFS) DescribeLifecycleConfiguration(_ context.Context, _ *efs.DescribeLifecycleConfigurationInput, _ ...func(*efs.Options)) (*efs.DescribeLifecycleConfigurationOutput, error) {
+	return m.lifecycleOut, nil
+}
+
+// DescribeMountTargets returns the mount targets not deleted since.
+func (m *mockEFS) DescribeMountTargets(_ context.Context, _ *efs.DescribeMountTargetsInput, _ ...func(*efs.Options)) (*efs.DescribeMountTargetsOutput, error) {
+	if m.mountTargetsOut == nil || len(m.deletedTargets) == 0 {
+		return m.mountTargetsOut, nil
+	}
+	out := &efs.DescribeMountTargetsOutput{}
+	for _, mt := range m.mountTargetsOut.MountTargets {
+		if !slices.Contains(m.deletedTargets, aws.ToString(mt.MountTargetId)) {
+			out.MountTargets = append(out.MountTargets, mt)
+		}
+	}
+	return out, nil
+}
+
+func TestDescribeFileSystem(t *testing.T) {
+	c := newTestClient(&mockCFN{}, &mockBackup{}, &mockRDS{})
+	c.efs = &mockEFS{
+		describeOut: &efs.DescribeFileSystemsOutput{FileSystems: []efstypes.FileSystemDescription{{
+			FileSystemId:                 aws.String("fs-1"),
+			LifeCycleState:               efstypes.LifeCycleStateAvailable,
+			SizeInBytes:                  &efstypes.FileSystemSize{Value: 4096},
+			ThroughputMode:               efstypes.ThroughputModeProvisioned,
+			ProvisionedThroughputInMibps: aws.Float64(128),
+			Encrypted:                    aws.Bool(true),
+		}}},
+		lifecycleOut: &efs.DescribeLifecycleConfigurationOutput{LifecyclePolicies: []efstypes.LifecyclePolicy{
+			{TransitionToIA: efstypes.TransitionToIARulesAfter30Days},
+			{TransitionToPrimaryStorageClass: efstypes.TransitionToPrimaryStorageClassRulesAfter1Access},
+		}},
+		mountTargetsOut: &efs.DescribeMountTargetsOutput{MountTargets: []efstypes.MountTargetDescription{
+			{MountTargetId: aws.String("fsmt-b"), AvailabilityZoneName: aws.String("us-west-2b")},
+			{MountTargetId: aws.String("fsmt-a"), AvailabilityZoneName: aws.String("us-west-2a")},
+		}},
+	}
+
+	info, err := c.DescribeFileSystem(context.Background(), "fs-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.SizeBytes != 4096 || info.ProvisionedMiBps != 128 || !info.Encrypted || info.ThroughputMode != "provisioned" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+	if len(info.Lifecycle) != 2 || info.Lifecycle[0] != "TransitionToIA: AFTER_30_DAYS" {
+		t.Errorf("unexpected lifecycle: %v", info.Lifecycle)
+	}
+	if len(info.MountTargets) != 2 || info.MountTargets[0].ID != "fsmt-a" {
+		t.Errorf("mount targets should be sorted by AZ: %+v", info.MountTargets)
+	}
+}
+
+func TestDescribeFileSystem_Errors(t *testing.T) {
+	c := newTestClient(&mockCFN{}, &mockBackup{}, &mockRDS{})
+	if _, err := c.DescribeFileSystem(context.Background(), "fs-1"); err == nil {
+		t.Error("expected error without an EFS client")
+	}
+
+	c.efs = &mockEFS{describeErr: errors.New("access denied")}
+	if _, err := c.DescribeFileSystem(context.Background(), "fs-1"); err == nil {
+		t.Error("expected API error to be returned")
+	}
+
+	c.efs = &mockEFS{describeOut: &efs.DescribeFileSystemsOutput{}}
+	if _, err := c.DescribeFileSystem(context.Background(), "fs-1"); err == nil {
+		t.Error("expected not found error")
+	}
+}
+
+func TestDescribeFileSystem_Simulated(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := NewSimulatedBackupClient(fx).DescribeFileSystem(context.Background(), "fs-0sim0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.MountTargets) != 2 || len(info.Lifecycle) != 2 || info.ThroughputMode != "elastic" {
+		t.Errorf("unexpected simulated file system: %+v", info)
+	}
+}
+
+func TestEFSRestoreDirectory(t *testing.T) {
+	rp := RecoveryPoint{ResourceType: "EFS", ResourceID: "fs-1"}
+	created := time.Date(2026, 3, 1, 5, 4, 3, 0, time.FixedZone("PST", -8*3600))
+	done := &RestoreJobStatus{Status: "COMPLETED", ResourceID: "fs-1", CreatedAt: created}
+
+	if got := EFSRestoreDirectory(rp, done); got != "/aws-backup-restore_2026-03-01T13-04-03" {
+		t.Errorf("in-place restore directory = %q", got)
+	}
+	for name, status := range map[string]*RestoreJobStatus{
+		"running":         {Status: "RUNNING", ResourceID: "fs-1", CreatedAt: created},
+		"new file system": {Status: "COMPLETED", ResourceID: "fs-2", CreatedAt: created},
+		"no status":       nil,
+	} {
+		if got := EFSRestoreDirectory(rp, status); got != "" {
+			t.Errorf("%s: expected no directory, got %q", name, got)
+		}
+	}
+	if got := EFSRestoreDirectory(RecoveryPoint{ResourceType: "RDS"}, done); got != "" {
+		t.Errorf("RDS restores have no directory, got %q", got)
+	}
+}
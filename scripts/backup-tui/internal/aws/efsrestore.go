@@ -0,0 +1,117 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements restoring EFS backups: in place into the live file
+// system, or into a new encrypted one when a different KMS key is chosen,
+// either whole or only the files and directories the operator names.
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxItemsToRestore is the most paths an EFS item-level restore takes.
+const MaxItemsToRestore = 5
+
+// efsRestore restores an EFS backup into its file system, or a new one.
+var efsRestore = restoreType{
+	resolve:  resolveEFSRestore,
+	metadata: efsRestoreMetadata,
+	options:  efsRestoreOptions,
+	preview:  previewEFSRestore,
+	validate: validateEFSOptions,
+}
+
+// resolveEFSRestore restores in place, encrypted. The file system is the
+// backed-up one, so nothing needs looking up.
+func resolveEFSRestore(_ context.Context, _ *BackupClient, _ RecoveryPoint, _ string, meta *RestoreMetadata) error {
+	meta.Encrypted = true
+	meta.NewFileSystem = false
+	return nil
+}
+
+// efsRestoreMetadata returns the EFS restore metadata:
+//   - file-system-id: The target file system ID (restores in-place)
+//   - newFileSystem: "false" to restore to existing file system
+//   - Encrypted: "true" to maintain encryption
+func efsRestoreMetadata(meta *RestoreMetadata) map[string]string {
+	return map[string]string{
+		"file-system-id": meta.ResourceID,
+		"newFileSystem":  "false",
+		"Encrypted":      "true",
+	}
+}
+
+// efsRestoreOptions restores only the items opts name, and into a new file
+// system when opts choose a different KMS key, as EFS cannot re-encrypt
+// one in place.
+func efsRestoreOptions(metadata map[string]string, opts RestoreOptions) error {
+	if len(opts.ItemsToRestore) > 0 {
+		items, err := json.Marshal(opts.ItemsToRestore)
+		if err != nil {
+			return err
+		}
+		metadata["itemsToRestore"] = string(items)
+	}
+	if opts.KMSKeyID == "" {
+		return nil
+	}
+	metadata["newFileSystem"] = "true"
+	metadata["PerformanceMode"] = "generalPurpose"
+	metadata["CreationToken"] = fmt.Sprintf("backup-tui-restore-%d", time.Now().Unix())
+	return nil
+}
+
+// previewEFSRestore shows a new file system when opts choose a KMS key, and
+// the items opts restore.
+func previewEFSRestore(m *RestoreMetadata, opts RestoreOptions) {
+	m.NewFileSystem = opts.KMSKeyID != ""
+	m.ItemsToRestore = opts.ItemsToRestore
+}
+
+// validateEFSOptions refuses the RDS cluster options and items that are
+// not clean, distinct paths, or more of them than AWS Backup takes.
+func validateEFSOptions(resourceType string, opts RestoreOptions) error {
+	if err := refuseClusterOptions(resourceType, opts); err != nil {
+		return err
+	}
+	if len(opts.ItemsToRestore) > MaxItemsToRestore {
+		return fmt.Errorf("an EFS restore takes at most %d items, not %d", MaxItemsToRestore, len(opts.ItemsToRestore))
+	}
+	for i, item := range opts.ItemsToRestore {
+		if clean, err := CleanItemPath(item); err != nil {
+			return err
+		} else if clean != item {
+			return fmt.Errorf("item %q is not a clean path; use %s", item, clean)
+		}
+		if slices.Contains(opts.ItemsToRestore[:i], item) {
+			return fmt.Errorf("item %s is listed twice", item)
+		}
+	}
+	return nil
+}
+
+// CleanItemPath returns an EFS item-level restore path in the form AWS
+// Backup takes: absolute from the file system's root, e.g.
+// /sites/default/documents, without a trailing slash. The root itself is
+// refused, as that is the whole file system.
+func CleanItemPath(item string) (string, error) {
+	item = strings.TrimSpace(item)
+	switch {
+	case item == "":
+		return "", fmt.Errorf("enter a path to restore, e.g. /sites/default/documents")
+	case !strings.HasPrefix(item, "/"):
+		return "", fmt.Errorf("item %q must be an absolute path from the file system's root, e.g. /%s", item, item)
+	case slices.Contains(strings.Split(item, "/"), ".."):
+		return "", fmt.Errorf("item %q must not contain ..", item)
+	}
+	clean := path.Clean(item)
+	if clean == "/" {
+		return "", fmt.Errorf("item / is the whole file system; restore without items instead")
+	}
+	return clean, nil
+}
@@ -0,0 +1,89 @@
+package aws
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCleanItemPath(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"/sites/default/documents", "/sites/default/documents"},
+		{" /sites/default/documents/ ", "/sites/default/documents"},
+		{"/sites//default/./edi", "/sites/default/edi"},
+	}
+	for _, tt := range tests {
+		if got, err := CleanItemPath(tt.in); err != nil || got != tt.want {
+			t.Errorf("CleanItemPath(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "sites/default", "/", "//", "/sites/../etc"} {
+		if _, err := CleanItemPath(in); err == nil {
+			t.Errorf("CleanItemPath(%q) should fail", in)
+		}
+	}
+}
+
+func TestValidateRestoreOptions_Items(t *testing.T) {
+	items := []string{"/sites/default/documents", "/sites/default/edi"}
+	if err := ValidateRestoreOptions("EFS", RestoreOptions{ItemsToRestore: items}); err != nil {
+		t.Errorf("EFS should take items: %v", err)
+	}
+	for _, rt := range []string{"RDS", "DynamoDB"} {
+		if err := ValidateRestoreOptions(rt, RestoreOptions{ItemsToRestore: items}); err == nil {
+			t.Errorf("%s should refuse items", rt)
+		}
+	}
+	for _, bad := range [][]string{
+		{"/a", "/b", "/c", "/d", "/e", "/f"},
+		{"/a", "/a"},
+		{"/a/"},
+		{"relative"},
+	} {
+		if err := ValidateRestoreOptions("EFS", RestoreOptions{ItemsToRestore: bad}); err == nil {
+			t.Errorf("EFS should refuse items %q", bad)
+		}
+	}
+}
+
+func TestApplyRestoreOptions_Items(t *testing.T) {
+	metadata := efsRestoreMetadata(&RestoreMetadata{ResourceID: "fs-1"})
+	opts := RestoreOptions{ItemsToRestore: []string{"/sites/default/documents", "/sites/default/edi"}}
+	if err := applyRestoreOptions(metadata, "EFS", opts); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := metadata["itemsToRestore"], `["/sites/default/documents","/sites/default/edi"]`; got != want {
+		t.Errorf("itemsToRestore = %s, want %s", got, want)
+	}
+	if metadata["newFileSystem"] != "false" || metadata["file-system-id"] != "fs-1" {
+		t.Errorf("items should still restore in place: %v", metadata)
+	}
+
+	whole := efsRestoreMetadata(&RestoreMetadata{ResourceID: "fs-1"})
+	_ = applyRestoreOptions(whole, "EFS", RestoreOptions{})
+	if _, ok := whole["itemsToRestore"]; ok {
+		t.Error("a restore without items should restore the whole file system")
+	}
+
+	preview := RestoreMetadata{ResourceType: "EFS", ResourceID: "fs-1"}
+	preview.ApplyOptions(opts)
+	if len(preview.ItemsToRestore) != 2 {
+		t.Errorf("the preview should show the items: %+v", preview)
+	}
+}
+
+func TestStartRestoreJob_Items(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	points, _ := c.ListRecoveryPoints(ctx, fx.Vaults[0], "EFS")
+	if len(points) == 0 {
+		t.Fatal("no EFS recovery points in the fixtures")
+	}
+
+	if _, err := c.StartRestoreJob(ctx, points[0], fx.Stacks[0].Name, fx.Vaults[0], RestoreOptions{ItemsToRestore: []string{"/sites/default/documents"}}); err != nil {
+		t.Fatalf("item-level restore: %v", err)
+	}
+	if _, err := c.StartRestoreJob(ctx, points[0], fx.Stacks[0].Name, fx.Vaults[0], RestoreOptions{ItemsToRestore: []string{"documents"}}); err == nil {
+		t.Error("a relative item should refuse the restore")
+	}
+}
@@ -0,0 +1,77 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the engine version check before an RDS restore:
+// whether the region still offers the engine version recorded with the
+// recovery point, and if not, the version RDS upgrades the restored cluster
+// to, so an upgrade OpenEMR was not tested against is noticed before the
+// restore is submitted.
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// engineVersionAvailable is the RDS status of an engine version new
+// clusters can be created with.
+const engineVersionAvailable = "available"
+
+// EngineVersionCheck is the availability in the region of the engine
+// version an RDS recovery point was taken with.
+type EngineVersionCheck struct {
+	Engine   string // e.g. "aurora-mysql"
+	Recorded string // Engine version recorded with the recovery point
+	Status   string // "available" or "deprecated"; empty when the region does not offer the version
+	Upgrade  string // Default version RDS restores at instead, when Recorded is not available; empty if unknown
+}
+
+// NeedsUpgrade reports whether the restored cluster cannot run the recorded
+// version.
+func (e *EngineVersionCheck) NeedsUpgrade() bool {
+	return e.Status != engineVersionAvailable
+}
+
+// RestoreVersion returns the engine version the restored cluster runs, or
+// "" when it needs an upgrade to an unknown version.
+func (e *EngineVersionCheck) RestoreVersion() string {
+	if e.NeedsUpgrade() {
+		return e.Upgrade
+	}
+	return e.Recorded
+}
+
+// CheckEngineVersion looks up whether the region offers version of engine
+// for new clusters and, if not, the engine's default version.
+func (c *BackupClient) CheckEngineVersion(ctx context.Context, engine, version string) (*EngineVersionCheck, error) {
+	check := &EngineVersionCheck{Engine: engine, Recorded: version}
+	out, err := c.rds.DescribeDBEngineVersions(ctx, &rds.DescribeDBEngineVersionsInput{
+		Engine:        aws.String(engine),
+		EngineVersion: aws.String(version),
+		IncludeAll:    aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe engine version %s: %w", version, err)
+	}
+	for _, v := range out.DBEngineVersions {
+		if aws.ToString(v.EngineVersion) == version {
+			check.Status = aws.ToString(v.Status)
+		}
+	}
+	if !check.NeedsUpgrade() {
+		return check, nil
+	}
+
+	def, err := c.rds.DescribeDBEngineVersions(ctx, &rds.DescribeDBEngineVersionsInput{
+		Engine:      aws.String(engine),
+		DefaultOnly: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the default %s version: %w", engine, err)
+	}
+	if len(def.DBEngineVersions) > 0 {
+		check.Upgrade = aws.ToString(def.DBEngineVersions[0].EngineVersion)
+	}
+	return check, nil
+}
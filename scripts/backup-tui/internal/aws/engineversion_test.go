@@ -0,0 +1,68 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+func TestCheckEngineVersion(t *testing.T) {
+	m := &mockRDS{
+		engineVersions: []rdstypes.DBEngineVersion{
+			{EngineVersion: aws.String("8.0.mysql_aurora.3.05.2"), Status: aws.String("deprecated")},
+			{EngineVersion: aws.String("8.0.mysql_aurora.3.08.0"), Status: aws.String("available")},
+		},
+		defaultEngineVersion: "8.0.mysql_aurora.3.08.0",
+	}
+	c := &BackupClient{rds: m}
+	ctx := context.Background()
+
+	tests := []struct {
+		version     string
+		wantUpgrade bool
+		wantRestore string
+	}{
+		{"8.0.mysql_aurora.3.08.0", false, "8.0.mysql_aurora.3.08.0"},
+		{"8.0.mysql_aurora.3.05.2", true, "8.0.mysql_aurora.3.08.0"},
+		{"5.7.mysql_aurora.2.11.2", true, "8.0.mysql_aurora.3.08.0"}, // No longer offered at all
+	}
+	for _, tt := range tests {
+		check, err := c.CheckEngineVersion(ctx, "aurora-mysql", tt.version)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.version, err)
+		}
+		if check.NeedsUpgrade() != tt.wantUpgrade || check.RestoreVersion() != tt.wantRestore {
+			t.Errorf("%s: upgrade %v to %q, want %v to %q", tt.version, check.NeedsUpgrade(), check.RestoreVersion(), tt.wantUpgrade, tt.wantRestore)
+		}
+	}
+
+	// Without a default version the upgrade target is unknown
+	m.defaultEngineVersion = ""
+	check, err := c.CheckEngineVersion(ctx, "aurora-mysql", "8.0.mysql_aurora.3.05.2")
+	if err != nil || !check.NeedsUpgrade() || check.RestoreVersion() != "" {
+		t.Errorf("expected an upgrade to an unknown version, got %+v, %v", check, err)
+	}
+}
+
+func TestSimulatedClient_CheckEngineVersion(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewSimulatedBackupClient(fx)
+	check, err := c.CheckEngineVersion(context.Background(), "aurora-mysql", "8.0.mysql_aurora.3.07.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Status != "deprecated" || check.Upgrade != "8.0.mysql_aurora.3.08.0" {
+		t.Errorf("the training backup's version should need an upgrade to the default, got %+v", check)
+	}
+
+	// Engines without fixture versions offer every version
+	check, err = c.CheckEngineVersion(context.Background(), "aurora-postgresql", "16.4")
+	if err != nil || check.NeedsUpgrade() {
+		t.Errorf("expected an available version, got %+v, %v", check, err)
+	}
+}
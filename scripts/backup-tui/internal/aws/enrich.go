@@ -0,0 +1,142 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the enrichment pipeline: recovery point details for
+// the rows on screen are fetched by a fixed pool of workers, in the order
+// the rows were asked for, and asking again replaces the queue, so scrolling
+// a large vault fetches what is shown instead of firing a call per row.
+package aws
+
+import (
+	"context"
+	"sync"
+)
+
+// EnrichWorkers is the number of recovery points enriched at once. Each
+// takes two AWS Backup calls, so this stays within the Backup rate limit.
+const EnrichWorkers = 4
+
+// EnrichResult is the outcome of enriching one recovery point.
+type EnrichResult struct {
+	Vault            string
+	RecoveryPointARN string
+	Details          *RecoveryPointDetails
+	Err              error
+}
+
+// enrichRequest is a queued recovery point.
+type enrichRequest struct {
+	vault string
+	arn   string
+}
+
+// Enricher fetches RecoveryPointDetails with a bounded number of workers.
+type Enricher struct {
+	ctx     context.Context
+	fetch   func(ctx context.Context, vault, arn string) (*RecoveryPointDetails, error)
+	results chan EnrichResult
+	wake    chan struct{}
+
+	mu       sync.Mutex
+	queue    []enrichRequest
+	inFlight map[string]bool // Recovery point ARNs being fetched
+}
+
+// NewEnricher starts workers that enrich recovery points with client until
+// ctx is done.
+func NewEnricher(ctx context.Context, client *BackupClient, workers int) *Enricher {
+	return newEnricher(ctx, client.DescribeRecoveryPointDetails, workers)
+}
+
+// newEnricher starts workers that enrich recovery points with fetch.
+func newEnricher(ctx context.Context, fetch func(context.Context, string, string) (*RecoveryPointDetails, error), workers int) *Enricher {
+	e := &Enricher{
+		ctx:      ctx,
+		fetch:    fetch,
+		results:  make(chan EnrichResult, workers),
+		wake:     make(chan struct{}, 1),
+		inFlight: make(map[string]bool),
+	}
+	for range max(workers, 1) {
+		go e.work()
+	}
+	return e
+}
+
+// Results delivers each enriched recovery point.
+func (e *Enricher) Results() <-chan EnrichResult {
+	return e.results
+}
+
+// Prioritize replaces the queue with the recovery points in vault with the
+// given ARNs, fetched first to last. Points being fetched are not fetched
+// again, and queued points not in arns are dropped: callers pass what is on
+// screen, most important first, and pass it again when it changes.
+func (e *Enricher) Prioritize(vault string, arns []string) {
+	e.mu.Lock()
+	e.queue = e.queue[:0]
+	for _, arn := range arns {
+		if !e.inFlight[arn] {
+			e.queue = append(e.queue, enrichRequest{vault: vault, arn: arn})
+		}
+	}
+	queued := len(e.queue)
+	e.mu.Unlock()
+	if queued > 0 {
+		e.signal()
+	}
+}
+
+// Pending returns the number of recovery points queued or being fetched.
+func (e *Enricher) Pending() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.queue) + len(e.inFlight)
+}
+
+// signal wakes a worker if none is already being woken.
+func (e *Enricher) signal() {
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next takes the first queued recovery point, and wakes another worker if
+// more are queued.
+func (e *Enricher) next() (enrichRequest, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.queue) == 0 {
+		return enrichRequest{}, false
+	}
+	req := e.queue[0]
+	e.queue = e.queue[1:]
+	e.inFlight[req.arn] = true
+	if len(e.queue) > 0 {
+		e.signal()
+	}
+	return req, true
+}
+
+// work fetches queued recovery points until the context is done.
+func (e *Enricher) work() {
+	for {
+		req, ok := e.next()
+		if !ok {
+			select {
+			case <-e.ctx.Done():
+				return
+			case <-e.wake:
+				continue
+			}
+		}
+		details, err := e.fetch(e.ctx, req.vault, req.arn)
+		e.mu.Lock()
+		delete(e.inFlight, req.arn)
+		e.mu.Unlock()
+		select {
+		case e.results <- EnrichResult{Vault: req.vault, RecoveryPointARN: req.arn, Details: details, Err: err}:
+		case <-e.ctx.Done():
+			return
+		}
+	}
+}
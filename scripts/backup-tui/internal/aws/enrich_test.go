@@ -0,0 +1,111 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingFetch records the recovery points fetched and the most fetched at
+// once, and holds each fetch until release is closed.
+type blockingFetch struct {
+	release chan struct{}
+
+	mu      sync.Mutex
+	running int
+	peak    int
+	fetched []string
+}
+
+func (f *blockingFetch) fetch(ctx context.Context, _, arn string) (*RecoveryPointDetails, error) {
+	f.mu.Lock()
+	f.running++
+	f.peak = max(f.peak, f.running)
+	f.fetched = append(f.fetched, arn)
+	f.mu.Unlock()
+	select {
+	case <-f.release:
+	case <-ctx.Done():
+	}
+	f.mu.Lock()
+	f.running--
+	f.mu.Unlock()
+	return &RecoveryPointDetails{RecoveryPointARN: arn}, nil
+}
+
+func arnRange(from, to int) []string {
+	var arns []string
+	for i := from; i < to; i++ {
+		arns = append(arns, fmt.Sprintf("rp-%03d", i))
+	}
+	return arns
+}
+
+func TestEnricher_BoundsConcurrency(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := &blockingFetch{release: make(chan struct{})}
+	e := newEnricher(ctx, f.fetch, 3)
+
+	e.Prioritize("vault", arnRange(0, 50))
+	time.Sleep(50 * time.Millisecond)
+	f.mu.Lock()
+	started := len(f.fetched)
+	f.mu.Unlock()
+	if started != 3 {
+		t.Errorf("3 workers should start 3 fetches, started %d", started)
+	}
+
+	close(f.release)
+	for range 50 {
+		select {
+		case r := <-e.Results():
+			if r.Err != nil || r.Details == nil {
+				t.Fatalf("unexpected result %+v", r)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for results")
+		}
+	}
+	if f.peak > 3 {
+		t.Errorf("at most 3 fetches should run at once, ran %d", f.peak)
+	}
+	if e.Pending() != 0 {
+		t.Errorf("nothing should be pending, got %d", e.Pending())
+	}
+}
+
+func TestEnricher_PrioritizeReplacesQueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := &blockingFetch{release: make(chan struct{})}
+	e := newEnricher(ctx, f.fetch, 1)
+
+	// The first page starts fetching rp-000; the rest of it is scrolled away
+	e.Prioritize("vault", arnRange(0, 20))
+	time.Sleep(20 * time.Millisecond)
+	e.Prioritize("vault", append([]string{"rp-000"}, arnRange(100, 102)...))
+	close(f.release)
+
+	got := map[string]bool{}
+	for range 3 {
+		select {
+		case r := <-e.Results():
+			got[r.RecoveryPointARN] = true
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for results")
+		}
+	}
+	for _, arn := range []string{"rp-000", "rp-100", "rp-101"} {
+		if !got[arn] {
+			t.Errorf("expected %s to be enriched, got %v", arn, got)
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.fetched) != 3 {
+		t.Errorf("rows scrolled away should not be fetched, and rp-000 only once: fetched %v", f.fetched)
+	}
+}
@@ -0,0 +1,63 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements extraction of the details AWS support asks for from
+// an SDK error: the service and operation, the error code, and the request
+// ID.
+package aws
+
+import (
+	"errors"
+	"strings"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+)
+
+// ErrorDetails are the AWS details of an error returned by an SDK call.
+// Fields are empty when the error did not come from (or carry) them, e.g.
+// a network error has no request ID.
+type ErrorDetails struct {
+	Service   string // SDK service ID, e.g. "Backup"
+	Operation string // API operation, e.g. "StartRestoreJob"
+	Code      string // AWS error code, e.g. "AccessDeniedException"
+	RequestID string // AWS request ID
+}
+
+// DescribeError returns the AWS details of err, which may wrap an SDK error.
+func DescribeError(err error) ErrorDetails {
+	var d ErrorDetails
+	var opErr *smithy.OperationError
+	if errors.As(err, &opErr) {
+		d.Service, d.Operation = opErr.Service(), opErr.Operation()
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		d.Code = apiErr.ErrorCode()
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		d.RequestID = respErr.ServiceRequestID()
+	}
+	return d
+}
+
+// IsZero reports whether no AWS details were found.
+func (d ErrorDetails) IsZero() bool {
+	return d == ErrorDetails{}
+}
+
+// String formats d on one line for error output and logs, e.g. "Backup
+// StartRestoreJob, AccessDeniedException, request ID 1a2b3c". Empty fields
+// are left out.
+func (d ErrorDetails) String() string {
+	var parts []string
+	if op := strings.TrimSpace(d.Service + " " + d.Operation); op != "" {
+		parts = append(parts, op)
+	}
+	if d.Code != "" {
+		parts = append(parts, d.Code)
+	}
+	if d.RequestID != "" {
+		parts = append(parts, "request ID "+d.RequestID)
+	}
+	return strings.Join(parts, ", ")
+}
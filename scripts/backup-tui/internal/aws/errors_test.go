@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+func TestDescribeError(t *testing.T) {
+	sdkErr := &smithy.OperationError{
+		ServiceID:     "Backup",
+		OperationName: "StartRestoreJob",
+		Err: &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: 400}},
+				Err:      &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized"},
+			},
+			RequestID: "b1a2c3d4-0000-1111-2222-333344445555",
+		},
+	}
+	d := DescribeError(fmt.Errorf("failed to start restore job: %w", sdkErr))
+	want := ErrorDetails{Service: "Backup", Operation: "StartRestoreJob", Code: "AccessDeniedException", RequestID: "b1a2c3d4-0000-1111-2222-333344445555"}
+	if d != want {
+		t.Errorf("DescribeError = %+v, want %+v", d, want)
+	}
+	if got := d.String(); got != "Backup StartRestoreJob, AccessDeniedException, request ID b1a2c3d4-0000-1111-2222-333344445555" {
+		t.Errorf("String = %q", got)
+	}
+	if got := (ErrorDetails{Service: "Backup", Operation: "ListRecoveryPointsByBackupVault"}).String(); got != "Backup ListRecoveryPointsByBackupVault" {
+		t.Errorf("String without code or request ID = %q", got)
+	}
+
+	if d := DescribeError(errors.New("vault name cannot be empty")); !d.IsZero() {
+		t.Errorf("non-AWS errors have no details, got %+v", d)
+	}
+}
@@ -0,0 +1,159 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements snapshot exports: an Aurora recovery point (a cluster
+// snapshot) is exported to S3 as Apache Parquet with RDS StartExportTask,
+// for analytics and long-term archival of OpenEMR data without restoring a
+// live cluster. Export tasks are tracked like jobs, as JobKindExport.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// ExportDestination is where snapshot exports are written.
+type ExportDestination struct {
+	Bucket     string // S3 bucket name
+	Prefix     string // Optional key prefix within the bucket
+	IAMRoleARN string // Role RDS assumes to write to the bucket
+	KMSKeyID   string // Customer managed key that encrypts the exported data
+}
+
+// Configured reports whether any destination setting was given.
+func (d ExportDestination) Configured() bool {
+	return d != (ExportDestination{})
+}
+
+// Validate checks that every setting StartExportTask requires is present.
+func (d ExportDestination) Validate() error {
+	var missing []string
+	if d.Bucket == "" {
+		missing = append(missing, "bucket")
+	}
+	if d.IAMRoleARN == "" {
+		missing = append(missing, "IAM role")
+	}
+	if d.KMSKeyID == "" {
+		missing = append(missing, "KMS key")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("export destination is missing the %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// String describes the destination, e.g. "s3://bucket/prefix".
+func (d ExportDestination) String() string {
+	if d.Prefix == "" {
+		return "s3://" + d.Bucket
+	}
+	return "s3://" + d.Bucket + "/" + strings.Trim(d.Prefix, "/")
+}
+
+// CanExport reports whether recovery points of resourceType can be exported
+// to S3. Only Aurora snapshots can; EFS backups have no export.
+func CanExport(resourceType string) bool {
+	return resourceType == "RDS" || resourceType == "Aurora"
+}
+
+// exportTaskID returns an export task identifier for a recovery point: it
+// must start with a letter, contain only letters, digits, and hyphens, and
+// be at most 60 characters.
+func exportTaskID(resourceID string, now time.Time) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(resourceID) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('-')
+		}
+	}
+	suffix := fmt.Sprintf("-%d", now.Unix())
+	name := strings.Trim(b.String(), "-")
+	if limit := 60 - len("backup-tui-") - len(suffix); len(name) > limit {
+		name = strings.TrimRight(name[:limit], "-")
+	}
+	return "backup-tui-" + name + suffix
+}
+
+// StartSnapshotExport exports an Aurora recovery point to dest as Parquet
+// and returns the export task identifier. The export runs in the background;
+// follow it with GetExportTaskStatus.
+func (c *BackupClient) StartSnapshotExport(ctx context.Context, rp RecoveryPoint, dest ExportDestination) (string, error) {
+	if !CanExport(rp.ResourceType) {
+		return "", fmt.Errorf("%s recovery points cannot be exported to S3", rp.ResourceType)
+	}
+	if err := dest.Validate(); err != nil {
+		return "", err
+	}
+
+	input := &rds.StartExportTaskInput{
+		ExportTaskIdentifier: aws.String(exportTaskID(rp.ResourceID, time.Now())),
+		SourceArn:            aws.String(rp.RecoveryPointARN),
+		S3BucketName:         aws.String(dest.Bucket),
+		IamRoleArn:           aws.String(dest.IAMRoleARN),
+		KmsKeyId:             aws.String(dest.KMSKeyID),
+	}
+	if dest.Prefix != "" {
+		input.S3Prefix = aws.String(strings.Trim(dest.Prefix, "/"))
+	}
+
+	out, err := c.rds.StartExportTask(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to start snapshot export: %w", err)
+	}
+	return aws.ToString(out.ExportTaskIdentifier), nil
+}
+
+// GetExportTaskStatus queries the progress of a snapshot export. RDS
+// reports COMPLETE for a finished export; it is returned as COMPLETED like
+// the other job kinds.
+func (c *BackupClient) GetExportTaskStatus(ctx context.Context, taskID string) (*RestoreJobStatus, error) {
+	out, err := c.rds.DescribeExportTasks(ctx, &rds.DescribeExportTasksInput{ExportTaskIdentifier: aws.String(taskID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe export task: %w", err)
+	}
+	if len(out.ExportTasks) == 0 {
+		return nil, fmt.Errorf("export task %s not found", taskID)
+	}
+	return exportTaskStatus(out.ExportTasks[0]), nil
+}
+
+// exportTaskStatus converts an RDS export task to a job status.
+func exportTaskStatus(t rdstypes.ExportTask) *RestoreJobStatus {
+	status := &RestoreJobStatus{
+		JobID:            aws.ToString(t.ExportTaskIdentifier),
+		Status:           aws.ToString(t.Status),
+		ResourceType:     "RDS",
+		ResourceID:       resourceName(aws.ToString(t.SourceArn)),
+		RecoveryPointARN: aws.ToString(t.SourceArn),
+		StatusMessage:    aws.ToString(t.FailureCause),
+		CreatedAt:        aws.ToTime(t.TaskStartTime),
+		CompletedAt:      aws.ToTime(t.TaskEndTime),
+	}
+	if t.PercentProgress != nil {
+		status.PercentDone = fmt.Sprint(*t.PercentProgress)
+	}
+	if status.StatusMessage == "" {
+		status.StatusMessage = aws.ToString(t.WarningMessage)
+	}
+
+	switch status.Status {
+	case "COMPLETE":
+		status.Status = "COMPLETED"
+		status.IsTerminal = true
+		if status.StatusMessage == "" {
+			dest := ExportDestination{Bucket: aws.ToString(t.S3Bucket), Prefix: aws.ToString(t.S3Prefix)}
+			status.StatusMessage = fmt.Sprintf("exported %d GB to %s", aws.ToInt32(t.TotalExtractedDataInGB), dest)
+		}
+	case "FAILED", "CANCELED":
+		status.IsTerminal = true
+	}
+	return status
+}
@@ -0,0 +1,127 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+var testDestination = ExportDestination{
+	Bucket:     "openemr-exports",
+	Prefix:     "/snapshots/",
+	IAMRoleARN: "arn:aws:iam::123456789012:role/export",
+	KMSKeyID:   "alias/exports",
+}
+
+func TestExportDestination_Validate(t *testing.T) {
+	if err := testDestination.Validate(); err != nil {
+		t.Errorf("complete destination should be valid: %v", err)
+	}
+	err := ExportDestination{Bucket: "b"}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "IAM role, KMS key") {
+		t.Errorf("error should name the missing settings, got %v", err)
+	}
+	if testDestination.String() != "s3://openemr-exports/snapshots" {
+		t.Errorf("String() = %q", testDestination.String())
+	}
+}
+
+func TestExportTaskID(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	if got := exportTaskID("OpenemrEcsStack_db.cluster", now); got != "backup-tui-openemrecsstack-db-cluster-1700000000" {
+		t.Errorf("exportTaskID = %q", got)
+	}
+	if got := exportTaskID(strings.Repeat("a", 80), now); len(got) > 60 || !strings.HasSuffix(got, "-1700000000") {
+		t.Errorf("long IDs should be truncated to 60 characters, got %q", got)
+	}
+}
+
+func TestStartSnapshotExport(t *testing.T) {
+	rdsMock := &mockRDS{}
+	c := newTestClient(&mockCFN{}, &mockBackup{}, rdsMock)
+	rp := RecoveryPoint{RecoveryPointARN: "arn:aws:rds:us-west-2:1:cluster-snapshot:awsbackup:job-1", ResourceType: "RDS", ResourceID: "db"}
+
+	id, err := c.StartSnapshotExport(context.Background(), rp, testDestination)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := rdsMock.startExportInput
+	if !strings.HasPrefix(id, "backup-tui-db-") || aws.ToString(in.SourceArn) != rp.RecoveryPointARN ||
+		aws.ToString(in.S3Prefix) != "snapshots" || aws.ToString(in.KmsKeyId) != "alias/exports" {
+		t.Errorf("unexpected export input: id=%s %+v", id, in)
+	}
+
+	if _, err := c.StartSnapshotExport(context.Background(), RecoveryPoint{ResourceType: "EFS"}, testDestination); err == nil {
+		t.Error("EFS recovery points should not be exportable")
+	}
+	if _, err := c.StartSnapshotExport(context.Background(), rp, ExportDestination{}); err == nil {
+		t.Error("an incomplete destination should be rejected before calling RDS")
+	}
+	rdsMock.startExportErr = errors.New("access denied")
+	if _, err := c.StartSnapshotExport(context.Background(), rp, testDestination); err == nil {
+		t.Error("expected API error to be returned")
+	}
+}
+
+func TestGetJobStatus_Export(t *testing.T) {
+	rdsMock := &mockRDS{describeExportOutput: &rds.DescribeExportTasksOutput{ExportTasks: []rdstypes.ExportTask{{
+		ExportTaskIdentifier:   aws.String("backup-tui-db-1"),
+		SourceArn:              aws.String("arn:aws:rds:us-west-2:1:cluster-snapshot:awsbackup:job-1"),
+		Status:                 aws.String("COMPLETE"),
+		PercentProgress:        aws.Int32(100),
+		TotalExtractedDataInGB: aws.Int32(12),
+		S3Bucket:               aws.String("openemr-exports"),
+	}}}}
+	c := newTestClient(&mockCFN{}, &mockBackup{}, rdsMock)
+
+	status, err := c.GetJobStatus(context.Background(), JobKindExport, "backup-tui-db-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "COMPLETED" || !status.IsTerminal || status.PercentDone != "100" ||
+		status.StatusMessage != "exported 12 GB to s3://openemr-exports" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	rdsMock.describeExportOutput = &rds.DescribeExportTasksOutput{ExportTasks: []rdstypes.ExportTask{{Status: aws.String("IN_PROGRESS")}}}
+	if status, _ := c.GetJobStatus(context.Background(), JobKindExport, "x"); status.IsTerminal {
+		t.Error("IN_PROGRESS should not be terminal")
+	}
+	rdsMock.describeExportOutput = &rds.DescribeExportTasksOutput{}
+	if _, err := c.GetJobStatus(context.Background(), JobKindExport, "x"); err == nil {
+		t.Error("expected error for an unknown export task")
+	}
+}
+
+func TestSimulatedSnapshotExport(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	fx.Restore = FixtureRestore{DurationSeconds: 100, Outcome: "COMPLETED"}
+	c := NewSimulatedBackupClient(fx)
+	sim := c.rds.(*simulatedAWS)
+	start := time.Now()
+	sim.now = func() time.Time { return start }
+
+	points, _ := c.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	id, err := c.StartSnapshotExport(context.Background(), points[0], testDestination)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim.now = func() time.Time { return start.Add(50 * time.Second) }
+	status, err := c.GetJobStatus(context.Background(), JobKindExport, id)
+	if err != nil || status.Status != "IN_PROGRESS" || status.PercentDone != "50" {
+		t.Fatalf("halfway status = %+v, %v", status, err)
+	}
+
+	sim.now = func() time.Time { return start.Add(2 * time.Minute) }
+	status, _ = c.GetJobStatus(context.Background(), JobKindExport, id)
+	if status.Status != "COMPLETED" || !strings.Contains(status.StatusMessage, "s3://openemr-exports/snapshots") {
+		t.Errorf("final status = %+v", status)
+	}
+}
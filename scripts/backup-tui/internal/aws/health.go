@@ -0,0 +1,183 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements AWS service health awareness: when calls fail the
+// way a regional outage makes them fail (server faults, 5xx responses,
+// network errors), the public AWS status feeds of Backup, RDS, and EFS in
+// the region are checked, so an operator reads "AWS Backup is reporting an
+// incident in us-west-2" rather than a generic error mid-incident.
+package aws
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+)
+
+// statusFeedURL is the public AWS status site, which serves an RSS feed per
+// service and region and needs no credentials.
+const statusFeedURL = "https://status.aws.amazon.com/rss"
+
+// healthTimeout bounds a health check, so it never holds up the error it
+// explains.
+const healthTimeout = 5 * time.Second
+
+// incidentWindow is how recent a feed's newest post must be for an
+// unresolved incident to count as ongoing.
+const incidentWindow = 24 * time.Hour
+
+// healthServices are the services whose feeds are checked: the feed name
+// and how incidents name the service.
+var healthServices = []struct{ feed, name string }{
+	{"backup", "AWS Backup"},
+	{"rds", "Amazon RDS"},
+	{"elasticfilesystem", "Amazon EFS"},
+}
+
+// ServiceIncident is an ongoing incident a service reports in a region.
+type ServiceIncident struct {
+	Service   string    // e.g. "AWS Backup"
+	Region    string    // e.g. "us-west-2"
+	Title     string    // The newest post's title, e.g. "Increased API error rates"
+	Published time.Time // When the newest post was published
+}
+
+// String formats i for the status bar and error output, e.g. "AWS Backup
+// is reporting an incident in us-west-2: Increased API error rates".
+func (i ServiceIncident) String() string {
+	s := fmt.Sprintf("%s is reporting an incident in %s", i.Service, i.Region)
+	if i.Title != "" {
+		s += ": " + i.Title
+	}
+	return s
+}
+
+// HealthChecker reads the AWS status feeds.
+type HealthChecker struct {
+	baseURL string
+	client  *http.Client
+	now     func() time.Time
+}
+
+// NewHealthChecker returns a HealthChecker for the public AWS status site.
+func NewHealthChecker() *HealthChecker {
+	return &HealthChecker{baseURL: statusFeedURL, client: &http.Client{Timeout: healthTimeout}, now: time.Now}
+}
+
+// Incidents returns the ongoing incidents of Backup, RDS, and EFS in region,
+// in that order. A service without a feed in the region has none; feeds
+// that cannot be read are reported in the error alongside the incidents of
+// the others.
+func (h *HealthChecker) Incidents(ctx context.Context, region string) ([]ServiceIncident, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	found := make([]*ServiceIncident, len(healthServices))
+	errs := make([]error, len(healthServices))
+	var wg sync.WaitGroup
+	for i, svc := range healthServices {
+		wg.Go(func() {
+			found[i], errs[i] = h.incident(ctx, svc.feed, svc.name, region)
+		})
+	}
+	wg.Wait()
+
+	var incidents []ServiceIncident
+	for _, inc := range found {
+		if inc != nil {
+			incidents = append(incidents, *inc)
+		}
+	}
+	return incidents, errors.Join(errs...)
+}
+
+// statusFeed is the part of a status RSS feed that is read.
+type statusFeed struct {
+	Items []struct {
+		Title   string `xml:"title"`
+		PubDate string `xml:"pubDate"`
+	} `xml:"channel>item"`
+}
+
+// incident reads the feed of a service in region and returns its ongoing
+// incident, or nil.
+func (h *HealthChecker) incident(ctx context.Context, feed, service, region string) (*ServiceIncident, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s-%s.rss", h.baseURL, feed, region), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "backup-tui")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s status feed: %w", service, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s status feed: HTTP %d", service, resp.StatusCode)
+	}
+
+	var f statusFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&f); err != nil {
+		return nil, fmt.Errorf("%s status feed: %w", service, err)
+	}
+	var newest *ServiceIncident
+	for _, item := range f.Items {
+		published, ok := parsePubDate(item.PubDate)
+		if !ok || (newest != nil && !published.After(newest.Published)) {
+			continue
+		}
+		newest = &ServiceIncident{Service: service, Region: region, Title: strings.TrimSpace(item.Title), Published: published}
+	}
+	if newest == nil || h.now().Sub(newest.Published) > incidentWindow || resolved(newest.Title) {
+		return nil, nil
+	}
+	return newest, nil
+}
+
+// parsePubDate parses an RSS publication date, e.g. "Tue, 14 Jan 2026
+// 10:32:00 PST".
+func parsePubDate(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123} {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// resolved reports whether a status post's title closes its incident, e.g.
+// "Service is operating normally: [RESOLVED] Increased API error rates".
+func resolved(title string) bool {
+	upper := strings.ToUpper(title)
+	return strings.Contains(upper, "RESOLVED") || strings.Contains(upper, "OPERATING NORMALLY")
+}
+
+// IsRegionalFailure reports whether err is how calls fail when a service
+// has trouble in the region: a server fault, a 5xx response, or a network
+// error (including retries running out on any of these). Client errors
+// such as access denied or a missing resource are not.
+func IsRegionalFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() >= 500 {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorFault() == smithy.FaultServer
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,108 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// statusRSS returns a status feed with a post per title, published at
+// the matching time.
+func statusRSS(titles []string, published []time.Time) string {
+	items := ""
+	for i, title := range titles {
+		items += fmt.Sprintf("<item><title>%s</title><pubDate>%s</pubDate></item>", title, published[i].Format(time.RFC1123Z))
+	}
+	return `<?xml version="1.0"?><rss version="2.0"><channel><title>status</title>` + items + `</channel></rss>`
+}
+
+func TestHealthChecker_Incidents(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	feeds := map[string]string{
+		// Newest post first is not assumed: the ongoing one is last here
+		"/backup-us-west-2.rss": statusRSS(
+			[]string{"Increased API error rates", "Service is operating normally: [RESOLVED] Earlier issue"},
+			[]time.Time{now.Add(-time.Hour), now.Add(-72 * time.Hour)}),
+		"/rds-us-west-2.rss": statusRSS(
+			[]string{"Increased API error rates", "Service is operating normally: [RESOLVED] Increased API error rates"},
+			[]time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour)}),
+		// No feed for EFS: a 404
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := feeds[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	h := &HealthChecker{baseURL: srv.URL, client: srv.Client(), now: func() time.Time { return now }}
+	got, err := h.Incidents(context.Background(), "us-west-2")
+	if err != nil {
+		t.Fatalf("Incidents: %v", err)
+	}
+	if len(got) != 1 || got[0].Service != "AWS Backup" || got[0].Title != "Increased API error rates" {
+		t.Fatalf("only Backup's unresolved incident is ongoing, got %+v", got)
+	}
+	if s := got[0].String(); s != "AWS Backup is reporting an incident in us-west-2: Increased API error rates" {
+		t.Errorf("String = %q", s)
+	}
+
+	// A day later the post is too old to be ongoing
+	h.now = func() time.Time { return now.Add(25 * time.Hour) }
+	if got, err := h.Incidents(context.Background(), "us-west-2"); err != nil || len(got) != 0 {
+		t.Errorf("stale posts are not incidents, got %+v, %v", got, err)
+	}
+}
+
+func TestHealthChecker_Incidents_FeedError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	h := &HealthChecker{baseURL: srv.URL, client: srv.Client(), now: time.Now}
+	if got, err := h.Incidents(context.Background(), "us-west-2"); err == nil || len(got) != 0 {
+		t.Errorf("unreadable feeds should be reported, got %+v, %v", got, err)
+	}
+}
+
+func TestIsRegionalFailure(t *testing.T) {
+	respErr := func(status int, apiErr error) error {
+		return &smithy.OperationError{ServiceID: "Backup", OperationName: "ListBackupVaults", Err: &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+				Err:      apiErr,
+			},
+		}}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"server fault", &smithy.GenericAPIError{Code: "ServiceUnavailableException", Fault: smithy.FaultServer}, true},
+		{"5xx response", respErr(503, &smithy.GenericAPIError{Code: "ServiceUnavailable"}), true},
+		{"network error", fmt.Errorf("failed to list: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), true},
+		{"access denied", respErr(400, &smithy.GenericAPIError{Code: "AccessDeniedException", Fault: smithy.FaultClient}), false},
+		{"canceled", fmt.Errorf("failed to list: %w", context.Canceled), false},
+		{"other", errors.New("vault name cannot be empty"), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsRegionalFailure(tt.err); got != tt.want {
+			t.Errorf("%s: IsRegionalFailure = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
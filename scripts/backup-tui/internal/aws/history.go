@@ -0,0 +1,244 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements job history: backup, restore, and copy jobs created
+// in a time window, normalized into a single record type for reporting.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// Job kinds reported by ListJobs.
+const (
+	JobKindBackup  = "backup"
+	JobKindRestore = "restore"
+	JobKindCopy    = "copy"
+	JobKindExport  = "export" // RDS snapshot export to S3; tracked, not listed by ListJobs
+	JobKindClone   = "clone"  // Aurora fast clone; tracked, not listed by ListJobs
+)
+
+// JobRecord is a backup, restore, or copy job from AWS Backup's job history.
+type JobRecord struct {
+	Kind          string    `json:"kind"` // JobKindBackup, JobKindRestore, or JobKindCopy
+	JobID         string    `json:"jobId"`
+	ResourceType  string    `json:"resourceType"`
+	ResourceARN   string    `json:"resourceArn,omitempty"`
+	State         string    `json:"state"` // AWS job state, e.g. COMPLETED, FAILED
+	StatusMessage string    `json:"statusMessage,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	CompletedAt   time.Time `json:"completedAt,omitzero"` // Zero while the job is running
+
+	// PercentDone is a running backup job's progress, e.g. "42.0" (ListBackupJobs only).
+	PercentDone string `json:"percentDone,omitempty"`
+
+	// Restore jobs: the recovery point restored, the resource it was taken
+	// from, and, for restore tests, the result of validating the restored
+	// resource. Backup jobs from ListBackupJobs: the recovery point created.
+	RecoveryPointARN  string `json:"recoveryPointArn,omitempty"`
+	SourceResourceARN string `json:"sourceResourceArn,omitempty"`
+	ValidationStatus  string `json:"validationStatus,omitempty"` // SUCCESSFUL, FAILED, TIMED_OUT, or VALIDATING
+}
+
+// Duration returns how long a finished job took, or zero if it has not finished.
+func (j JobRecord) Duration() time.Duration {
+	if j.CompletedAt.IsZero() || j.CompletedAt.Before(j.CreatedAt) {
+		return 0
+	}
+	return j.CompletedAt.Sub(j.CreatedAt)
+}
+
+// Succeeded reports whether the job finished successfully.
+func (j JobRecord) Succeeded() bool {
+	return j.State == "COMPLETED"
+}
+
+// Failed reports whether the job finished unsuccessfully. PARTIAL and
+// EXPIRED jobs count as failures: the data was not fully protected.
+func (j JobRecord) Failed() bool {
+	switch j.State {
+	case "FAILED", "ABORTED", "PARTIAL", "EXPIRED":
+		return true
+	}
+	return false
+}
+
+// ListJobs returns backup, restore, and copy jobs created at or after since.
+// Backup jobs are limited to the vault and copy jobs to those copying from
+// or to it. AWS Backup cannot filter restore jobs by vault, so all restore
+// jobs in the account and region are included.
+func (c *BackupClient) ListJobs(ctx context.Context, vaultName string, since time.Time) ([]JobRecord, error) {
+	if vaultName == "" {
+		return nil, fmt.Errorf("vault name cannot be empty")
+	}
+
+	records, err := c.ListBackupJobs(ctx, vaultName, since)
+	if err != nil {
+		return nil, err
+	}
+
+	restores, err := c.ListRestoreJobs(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	records = append(records, restores...)
+
+	copies := backup.NewListCopyJobsPaginator(c.client, &backup.ListCopyJobsInput{
+		ByCreatedAfter: aws.Time(since),
+	})
+	for copies.HasMorePages() {
+		page, err := copies.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list copy jobs: %w", err)
+		}
+		for _, j := range page.CopyJobs {
+			if !vaultARNNamed(aws.ToString(j.SourceBackupVaultArn), vaultName) &&
+				!vaultARNNamed(aws.ToString(j.DestinationBackupVaultArn), vaultName) {
+				continue
+			}
+			records = append(records, JobRecord{
+				Kind:          JobKindCopy,
+				JobID:         aws.ToString(j.CopyJobId),
+				ResourceType:  aws.ToString(j.ResourceType),
+				ResourceARN:   aws.ToString(j.ResourceArn),
+				State:         string(j.State),
+				StatusMessage: aws.ToString(j.StatusMessage),
+				CreatedAt:     aws.ToTime(j.CreationDate),
+				CompletedAt:   aws.ToTime(j.CompletionDate),
+			})
+		}
+	}
+
+	return records, nil
+}
+
+// ListBackupJobs returns the backup jobs into the vault created at or after
+// since, running ones included.
+func (c *BackupClient) ListBackupJobs(ctx context.Context, vaultName string, since time.Time) ([]JobRecord, error) {
+	if vaultName == "" {
+		return nil, fmt.Errorf("vault name cannot be empty")
+	}
+
+	var records []JobRecord
+	backups := backup.NewListBackupJobsPaginator(c.client, &backup.ListBackupJobsInput{
+		ByBackupVaultName: aws.String(vaultName),
+		ByCreatedAfter:    aws.Time(since),
+	})
+	for backups.HasMorePages() {
+		page, err := backups.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list backup jobs: %w", err)
+		}
+		for _, j := range page.BackupJobs {
+			records = append(records, JobRecord{
+				Kind:             JobKindBackup,
+				JobID:            aws.ToString(j.BackupJobId),
+				ResourceType:     aws.ToString(j.ResourceType),
+				ResourceARN:      aws.ToString(j.ResourceArn),
+				State:            string(j.State),
+				StatusMessage:    aws.ToString(j.StatusMessage),
+				CreatedAt:        aws.ToTime(j.CreationDate),
+				CompletedAt:      aws.ToTime(j.CompletionDate),
+				RecoveryPointARN: aws.ToString(j.RecoveryPointArn),
+				PercentDone:      aws.ToString(j.PercentDone),
+			})
+		}
+	}
+	return records, nil
+}
+
+// ListRestoreJobs returns the restore jobs in the account and region created
+// at or after since. AWS Backup cannot filter them by vault.
+func (c *BackupClient) ListRestoreJobs(ctx context.Context, since time.Time) ([]JobRecord, error) {
+	var records []JobRecord
+	restores := backup.NewListRestoreJobsPaginator(c.client, &backup.ListRestoreJobsInput{
+		ByCreatedAfter: aws.Time(since),
+	})
+	for restores.HasMorePages() {
+		page, err := restores.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list restore jobs: %w", err)
+		}
+		for _, j := range page.RestoreJobs {
+			records = append(records, JobRecord{
+				Kind:              JobKindRestore,
+				JobID:             aws.ToString(j.RestoreJobId),
+				ResourceType:      aws.ToString(j.ResourceType),
+				ResourceARN:       aws.ToString(j.CreatedResourceArn),
+				State:             string(j.Status),
+				StatusMessage:     aws.ToString(j.StatusMessage),
+				CreatedAt:         aws.ToTime(j.CreationDate),
+				CompletedAt:       aws.ToTime(j.CompletionDate),
+				RecoveryPointARN:  aws.ToString(j.RecoveryPointArn),
+				SourceResourceARN: aws.ToString(j.SourceResourceArn),
+				ValidationStatus:  string(j.ValidationStatus),
+			})
+		}
+	}
+	return records, nil
+}
+
+// ListStackJobs returns the backup and restore jobs of the stack's RDS
+// clusters and EFS file systems created at or after since, newest first:
+// backups to any vault and restores from any of their recovery points,
+// whether started by a backup plan, the console, the CLI, or this tool.
+// Restore jobs are matched by the resource their recovery point was taken
+// from.
+func (c *BackupClient) ListStackJobs(ctx context.Context, stackName string, since time.Time) ([]JobRecord, error) {
+	resources, err := c.stackProtectedResources(ctx, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []JobRecord
+	stackARNs := make(map[string]bool, len(resources))
+	for _, r := range resources {
+		stackARNs[r.ARN] = true
+		backups := backup.NewListBackupJobsPaginator(c.client, &backup.ListBackupJobsInput{
+			ByResourceArn:  aws.String(r.ARN),
+			ByCreatedAfter: aws.Time(since),
+		})
+		for backups.HasMorePages() {
+			page, err := backups.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list backup jobs: %w", err)
+			}
+			for _, j := range page.BackupJobs {
+				records = append(records, JobRecord{
+					Kind:          JobKindBackup,
+					JobID:         aws.ToString(j.BackupJobId),
+					ResourceType:  aws.ToString(j.ResourceType),
+					ResourceARN:   aws.ToString(j.ResourceArn),
+					State:         string(j.State),
+					StatusMessage: aws.ToString(j.StatusMessage),
+					CreatedAt:     aws.ToTime(j.CreationDate),
+					CompletedAt:   aws.ToTime(j.CompletionDate),
+				})
+			}
+		}
+	}
+
+	restores, err := c.ListRestoreJobs(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range restores {
+		if stackARNs[j.SourceResourceARN] {
+			records = append(records, j)
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].CreatedAt.After(records[j].CreatedAt) })
+	return records, nil
+}
+
+// vaultARNNamed reports whether a backup vault ARN
+// (arn:aws:backup:region:account:backup-vault:name) names vaultName.
+func vaultARNNamed(arn, vaultName string) bool {
+	return strings.HasSuffix(arn, ":backup-vault:"+vaultName)
+}
@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+)
+
+func TestListJobs_NormalizesAndFiltersCopies(t *testing.T) {
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock := &mockBackup{
+		listBackupJobsOut: &backup.ListBackupJobsOutput{BackupJobs: []backuptypes.BackupJob{{
+			BackupJobId:    aws.String("b-1"),
+			ResourceType:   aws.String("RDS"),
+			State:          backuptypes.BackupJobStateCompleted,
+			CreationDate:   aws.Time(created),
+			CompletionDate: aws.Time(created.Add(20 * time.Minute)),
+		}}},
+		listRestoreJobsOut: &backup.ListRestoreJobsOutput{RestoreJobs: []backuptypes.RestoreJobsListMember{{
+			RestoreJobId: aws.String("r-1"),
+			Status:       backuptypes.RestoreJobStatusRunning,
+			CreationDate: aws.Time(created),
+		}}},
+		listCopyJobsOut: &backup.ListCopyJobsOutput{CopyJobs: []backuptypes.CopyJob{
+			{CopyJobId: aws.String("c-1"), SourceBackupVaultArn: aws.String("arn:aws:backup:us-west-2:1:backup-vault:my-vault"), State: backuptypes.CopyJobStateFailed},
+			{CopyJobId: aws.String("c-2"), DestinationBackupVaultArn: aws.String("arn:aws:backup:us-east-1:1:backup-vault:my-vault"), State: backuptypes.CopyJobStateCompleted},
+			{CopyJobId: aws.String("c-3"), SourceBackupVaultArn: aws.String("arn:aws:backup:us-west-2:1:backup-vault:my-vault-2"), State: backuptypes.CopyJobStateCompleted},
+		}},
+	}
+	c := newTestClient(&mockCFN{}, mock, &mockRDS{})
+
+	jobs, err := c.ListJobs(context.Background(), "my-vault", created.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 4 {
+		t.Fatalf("expected backup, restore, and 2 matching copy jobs, got %+v", jobs)
+	}
+	if jobs[0].Kind != JobKindBackup || jobs[0].Duration() != 20*time.Minute || !jobs[0].Succeeded() {
+		t.Errorf("unexpected backup record: %+v", jobs[0])
+	}
+	if jobs[1].Kind != JobKindRestore || jobs[1].Duration() != 0 || jobs[1].Succeeded() || jobs[1].Failed() {
+		t.Errorf("running restore should be neither succeeded nor failed: %+v", jobs[1])
+	}
+	if jobs[2].JobID != "c-1" || !jobs[2].Failed() || jobs[3].JobID != "c-2" {
+		t.Errorf("copy jobs should be filtered to the vault: %+v", jobs[2:])
+	}
+}
+
+func TestListJobs_Errors(t *testing.T) {
+	c := newTestClient(&mockCFN{}, &mockBackup{listJobsErr: errors.New("throttled")}, &mockRDS{})
+	if _, err := c.ListJobs(context.Background(), "v", time.Now()); err == nil {
+		t.Error("expected API error to be returned")
+	}
+	if _, err := c.ListJobs(context.Background(), "", time.Now()); err == nil {
+		t.Error("expected error for empty vault name")
+	}
+}
+
+func TestListJobs_Simulated(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	rp := fx.RecoveryPoints[fx.Vaults[0]][0]
+	if _, err := c.client.StartRestoreJob(ctx, &backup.StartRestoreJobInput{RecoveryPointArn: aws.String(rp.RecoveryPointARN)}); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := c.ListJobs(ctx, fx.Vaults[0], time.Now().Add(-48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int{}
+	for _, j := range jobs {
+		counts[j.Kind]++
+	}
+	if counts[JobKindBackup] != 4 || counts[JobKindCopy] != 2 || counts[JobKindRestore] != 2 {
+		t.Errorf("unexpected simulated job counts within 48h: %v", counts)
+	}
+}
+
+func TestListStackJobs_Simulated(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	fx.Jobs = append(fx.Jobs,
+		FixtureJob{Kind: JobKindBackup, ID: "other-app-backup", Vault: "other-vault", ResourceARN: "arn:aws:rds:us-west-2:123456789012:cluster:other-app", ResourceType: "RDS", State: "COMPLETED", AgeHours: 1},
+		FixtureJob{Kind: JobKindRestore, ID: "other-app-restore", SourceARN: "arn:aws:rds:us-west-2:123456789012:cluster:other-app", ResourceType: "RDS", State: "RUNNING", AgeHours: 1},
+	)
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	rp := fx.RecoveryPoints[fx.Vaults[0]][0]
+	if _, err := c.client.StartRestoreJob(ctx, &backup.StartRestoreJobInput{RecoveryPointArn: aws.String(rp.RecoveryPointARN)}); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := c.ListStackJobs(ctx, fx.Stacks[0].Name, time.Now().Add(-48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int{}
+	for i, j := range jobs {
+		counts[j.Kind]++
+		if j.JobID == "other-app-backup" || j.JobID == "other-app-restore" {
+			t.Errorf("jobs of another application's resources should not be listed: %+v", j)
+		}
+		if i > 0 && jobs[i-1].CreatedAt.Before(j.CreatedAt) {
+			t.Errorf("jobs should be newest first: %s before %s", jobs[i-1].JobID, j.JobID)
+		}
+	}
+	// Backups from any vault, the restore test, and the restore just started
+	if counts[JobKindBackup] != 4 || counts[JobKindRestore] != 2 || counts[JobKindCopy] != 0 {
+		t.Errorf("unexpected stack job counts within 48h: %v", counts)
+	}
+}
@@ -0,0 +1,150 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements operator identity stamping: resources the tool
+// creates (restored clusters and file systems, on-demand backups, copies,
+// and clones) are tagged with the caller's ARN and the tool's name, so
+// CloudTrail and billing can attribute them.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	efstypes "github.com/aws/aws-sdk-go-v2/service/efs/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Tags stamped on every resource the tool creates.
+const (
+	CreatedByTagKey  = "created-by"  // ARN of the IAM identity that ran the tool
+	CreatedViaTagKey = "created-via" // Always CreatedVia
+	CreatedVia       = "backup-tui"
+)
+
+// CallerARN returns the ARN of the IAM identity the client's credentials
+// belong to, e.g. an assumed SSO role session.
+func (c *BackupClient) CallerARN() string {
+	return c.callerARN
+}
+
+// CheckIdentity confirms that the client's credentials still work and
+// belong to the identity they did when the client was created, e.g. before
+// a locked session is resumed. Simulated clients always pass.
+func (c *BackupClient) CheckIdentity(ctx context.Context) error {
+	if c.simulated || c.sts == nil {
+		return nil
+	}
+	identity, err := c.sts.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	if arn := aws.ToString(identity.Arn); arn != c.callerARN {
+		return fmt.Errorf("credentials now belong to %s, not %s", arn, c.callerARN)
+	}
+	return nil
+}
+
+// AccountID returns the ID of the AWS account the client's credentials
+// belong to.
+func (c *BackupClient) AccountID() string {
+	return c.accountID
+}
+
+// IdentityTags returns the tags stamped on resources the client creates.
+func (c *BackupClient) IdentityTags() map[string]string {
+	return map[string]string{CreatedByTagKey: c.callerARN, CreatedViaTagKey: CreatedVia}
+}
+
+// withIdentityTags returns tags with the identity tags added. Tags the
+// caller sets are kept, except the identity tags themselves.
+func (c *BackupClient) withIdentityTags(tags map[string]string) map[string]string {
+	merged := maps.Clone(tags)
+	if merged == nil {
+		merged = make(map[string]string, 2)
+	}
+	maps.Copy(merged, c.IdentityTags())
+	return merged
+}
+
+// rdsIdentityTags returns the identity tags as RDS tags, after extra.
+func (c *BackupClient) rdsIdentityTags(extra ...rdstypes.Tag) []rdstypes.Tag {
+	return append(extra,
+		rdstypes.Tag{Key: aws.String(CreatedByTagKey), Value: aws.String(c.callerARN)},
+		rdstypes.Tag{Key: aws.String(CreatedViaTagKey), Value: aws.String(CreatedVia)})
+}
+
+// TagCreatedResource stamps the identity tags of tagger, the client of the
+// operator who started the job, on the resource with the given ARN, which c
+// can reach: a restored Aurora cluster, an EFS file system, or a recovery
+// point. tagger is c unless the resource is in another account, e.g. a copy
+// in the recovery vault.
+func (c *BackupClient) TagCreatedResource(ctx context.Context, tagger *BackupClient, arn string) error {
+	return c.tagResource(ctx, arn, tagger.IdentityTags())
+}
+
+// tagResource adds tags to the resource with the given ARN: an Aurora
+// cluster, an EFS file system, or a recovery point.
+func (c *BackupClient) tagResource(ctx context.Context, arn string, tags map[string]string) error {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return fmt.Errorf("cannot tag %q: not an ARN", arn)
+	}
+	keys := slices.Sorted(maps.Keys(tags))
+	var err error
+	switch parts[2] {
+	case "rds":
+		rdsTags := make([]rdstypes.Tag, 0, len(keys))
+		for _, k := range keys {
+			rdsTags = append(rdsTags, rdstypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+		}
+		_, err = c.rds.AddTagsToResource(ctx, &rds.AddTagsToResourceInput{
+			ResourceName: aws.String(arn),
+			Tags:         rdsTags,
+		})
+	case "elasticfilesystem":
+		efsTags := make([]efstypes.Tag, 0, len(keys))
+		for _, k := range keys {
+			efsTags = append(efsTags, efstypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+		}
+		_, err = c.efs.TagResource(ctx, &efs.TagResourceInput{ResourceId: aws.String(resourceName(arn)), Tags: efsTags})
+	case "backup":
+		_, err = c.client.TagResource(ctx, &backup.TagResourceInput{ResourceArn: aws.String(arn), Tags: tags})
+	default:
+		return fmt.Errorf("cannot tag %s: unsupported service %s", arn, parts[2])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to tag %s: %w", resourceName(arn), err)
+	}
+	return nil
+}
+
+// restoredResourceARN returns the ARN of the resource a completed restore
+// of rp created, or "" if it created none: it has not completed, or was an
+// EFS restore into the existing file system.
+func restoredResourceARN(rp RecoveryPoint, status *RestoreJobStatus) string {
+	if status == nil || status.Status != "COMPLETED" || status.CreatedResourceARN == "" {
+		return ""
+	}
+	if rp.ResourceType == "EFS" && status.ResourceID == rp.ResourceID {
+		return ""
+	}
+	return status.CreatedResourceARN
+}
+
+// TagRestoredResource stamps the identity tags on the resource a completed
+// restore of rp created. An EFS restore into the existing file system
+// creates nothing, so nothing is tagged.
+func (c *BackupClient) TagRestoredResource(ctx context.Context, rp RecoveryPoint, status *RestoreJobStatus) error {
+	arn := restoredResourceARN(rp, status)
+	if arn == "" {
+		return nil
+	}
+	return c.TagCreatedResource(ctx, c, arn)
+}
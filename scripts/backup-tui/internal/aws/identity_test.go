@@ -0,0 +1,132 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const testCaller = "arn:aws:sts::123456789012:assumed-role/Operator/jane"
+
+func TestTagCreatedResource(t *testing.T) {
+	b, r, e := &mockBackup{}, &mockRDS{}, &mockEFS{}
+	c := &BackupClient{client: b, rds: r, efs: e, callerARN: testCaller}
+	ctx := context.Background()
+
+	if err := c.TagCreatedResource(ctx, c, "arn:aws:rds:us-west-2:123456789012:cluster:openemr-dr"); err != nil {
+		t.Fatal(err)
+	}
+	if r.addTagsInput == nil || len(r.addTagsInput.Tags) != 2 || aws.ToString(r.addTagsInput.Tags[0].Value) != testCaller {
+		t.Errorf("cluster should be tagged with the caller, got %+v", r.addTagsInput)
+	}
+
+	if err := c.TagCreatedResource(ctx, c, "arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-new"); err != nil {
+		t.Fatal(err)
+	}
+	if e.tagInput == nil || aws.ToString(e.tagInput.ResourceId) != "fs-new" || len(e.tagInput.Tags) != 2 {
+		t.Errorf("file system should be tagged by ID, got %+v", e.tagInput)
+	}
+
+	operator := &BackupClient{callerARN: "arn:aws:iam::123456789012:user/ops"}
+	if err := c.TagCreatedResource(ctx, operator, "arn:aws:backup:us-east-1:210987654321:recovery-point:rp-2"); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.tagResourceInput.Tags; got[CreatedByTagKey] != operator.callerARN || got[CreatedViaTagKey] != CreatedVia {
+		t.Errorf("copy should carry the operator's identity, got %v", got)
+	}
+
+	for _, arn := range []string{"fs-1", "arn:aws:s3:::bucket/key"} {
+		if err := c.TagCreatedResource(ctx, c, arn); err == nil {
+			t.Errorf("%s: expected an error", arn)
+		}
+	}
+}
+
+func TestTagRestoredResource_SkipsInPlaceEFS(t *testing.T) {
+	e := &mockEFS{}
+	c := &BackupClient{efs: e, callerARN: testCaller}
+	rp := RecoveryPoint{ResourceType: "EFS", ResourceID: "fs-1"}
+	status := &RestoreJobStatus{Status: "COMPLETED", ResourceID: "fs-1",
+		CreatedResourceARN: "arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-1"}
+
+	if err := c.TagRestoredResource(context.Background(), rp, status); err != nil || e.tagInput != nil {
+		t.Errorf("an in-place EFS restore should not tag the live file system: %v", err)
+	}
+	status.ResourceID = "fs-2"
+	status.CreatedResourceARN = "arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-2"
+	if err := c.TagRestoredResource(context.Background(), rp, status); err != nil || e.tagInput == nil {
+		t.Errorf("a new file system should be tagged: %v", err)
+	}
+}
+
+func TestSimulatedClient_IdentityTags(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	sim := c.client.(*simulatedAWS)
+	start := time.Now()
+	sim.now = func() time.Time { return start }
+	ctx := context.Background()
+
+	id, err := c.StartOnDemandBackup(ctx, fx.Vaults[0], "role", fx.RecoveryPoints[fx.Vaults[0]][0].ResourceARN, map[string]string{"Reason": "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := sim.backups[id].in.RecoveryPointTags; tags[CreatedViaTagKey] != CreatedVia || tags[CreatedByTagKey] != c.CallerARN() || tags["Reason"] != "test" {
+		t.Errorf("backup should carry the identity and its own tags, got %v", tags)
+	}
+
+	points, _ := c.ListRecoveryPoints(ctx, fx.Vaults[0], "RDS")
+	rds := points[0]
+	jobID, err := c.StartRestoreJob(ctx, rds, fx.Stacks[0].Name, fx.Vaults[0], RestoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.now = func() time.Time { return start.Add(time.Duration(fx.Restore.DurationSeconds+1) * time.Second) }
+	status, err := c.GetRestoreJobStatus(ctx, jobID)
+	if err != nil || status.CreatedResourceARN == "" {
+		t.Fatalf("completed restore should name its cluster: %+v, %v", status, err)
+	}
+	if err := c.TagRestoredResource(ctx, rds, status); err != nil {
+		t.Fatal(err)
+	}
+	if got := sim.tags[status.CreatedResourceARN][CreatedByTagKey]; got != c.CallerARN() {
+		t.Errorf("restored cluster created-by = %q, want %q", got, c.CallerARN())
+	}
+}
+
+func TestRecoveryPointDetails_ToolCreated(t *testing.T) {
+	for name, tc := range map[string]struct {
+		d    RecoveryPointDetails
+		want bool
+	}{
+		"identity tags":  {RecoveryPointDetails{Tags: map[string]string{CreatedViaTagKey: CreatedVia}}, true},
+		"batch tag only": {RecoveryPointDetails{Tags: map[string]string{BatchTagKey: "20260301T100000Z"}}, true},
+		"console":        {RecoveryPointDetails{Tags: map[string]string{}}, false},
+		"backup plan":    {RecoveryPointDetails{CreatedBy: "daily", Tags: map[string]string{CreatedViaTagKey: CreatedVia}}, false},
+	} {
+		if got := tc.d.ToolCreated(); got != tc.want {
+			t.Errorf("%s: ToolCreated() = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestSimulatedClient_DeleteRecoveryPoint(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	vault := fx.Vaults[0]
+	points, _ := c.ListRecoveryPoints(ctx, vault, "")
+
+	if err := c.DeleteRecoveryPoint(ctx, vault, points[0].RecoveryPointARN); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := c.ListRecoveryPoints(ctx, vault, "")
+	if len(after) != len(points)-1 {
+		t.Errorf("recovery point should be gone, %d left of %d", len(after), len(points))
+	}
+	if err := c.DeleteRecoveryPoint(ctx, vault, points[0].RecoveryPointARN); err == nil {
+		t.Error("deleting it again should fail")
+	}
+}
@@ -0,0 +1,89 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements status lookups for jobs started outside the TUI (from
+// the console or CLI), which may be restore or backup jobs, so they can be
+// tracked alongside restores started in-app.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// GetBackupJobStatus queries the current status of a backup job.
+func (c *BackupClient) GetBackupJobStatus(ctx context.Context, jobID string) (*RestoreJobStatus, error) {
+	result, err := c.client.DescribeBackupJob(ctx, &backup.DescribeBackupJobInput{
+		BackupJobId: aws.String(jobID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe backup job: %w", err)
+	}
+
+	status := &RestoreJobStatus{
+		JobID:            aws.ToString(result.BackupJobId),
+		Status:           string(result.State),
+		ResourceType:     aws.ToString(result.ResourceType),
+		ResourceID:       resourceName(aws.ToString(result.ResourceArn)),
+		RecoveryPointARN: aws.ToString(result.RecoveryPointArn),
+		PercentDone:      aws.ToString(result.PercentDone),
+		StatusMessage:    aws.ToString(result.StatusMessage),
+		CreatedAt:        aws.ToTime(result.CreationDate),
+		CompletedAt:      aws.ToTime(result.CompletionDate),
+	}
+
+	switch status.Status {
+	case "COMPLETED", "FAILED", "ABORTED", "EXPIRED", "PARTIAL":
+		status.IsTerminal = true
+	}
+
+	return status, nil
+}
+
+// resourceName returns the last segment of a resource ARN: the cluster name
+// of "...:cluster:name" or the file system ID of ".../fs-123".
+func resourceName(arn string) string {
+	if i := strings.LastIndexAny(arn, "/:"); i >= 0 {
+		return arn[i+1:]
+	}
+	return arn
+}
+
+// GetJobStatus queries the current status of a job of the given kind
+// (JobKindRestore, JobKindBackup, JobKindCopy, JobKindExport, or JobKindClone).
+func (c *BackupClient) GetJobStatus(ctx context.Context, kind, jobID string) (*RestoreJobStatus, error) {
+	switch kind {
+	case JobKindRestore:
+		return c.GetRestoreJobStatus(ctx, jobID)
+	case JobKindBackup:
+		return c.GetBackupJobStatus(ctx, jobID)
+	case JobKindCopy:
+		return c.GetCopyJobStatus(ctx, jobID)
+	case JobKindExport:
+		return c.GetExportTaskStatus(ctx, jobID)
+	case JobKindClone:
+		return c.GetCloneStatus(ctx, jobID)
+	default:
+		return nil, fmt.Errorf("cannot track %s jobs", kind)
+	}
+}
+
+// LookupJob finds a job by ID without knowing its kind: restore jobs are
+// tried first, then backup jobs. It returns the job's kind and status.
+func (c *BackupClient) LookupJob(ctx context.Context, jobID string) (string, *RestoreJobStatus, error) {
+	jobID = strings.TrimSpace(jobID)
+	if jobID == "" {
+		return "", nil, fmt.Errorf("job ID is empty")
+	}
+	status, restoreErr := c.GetRestoreJobStatus(ctx, jobID)
+	if restoreErr == nil {
+		return JobKindRestore, status, nil
+	}
+	status, backupErr := c.GetBackupJobStatus(ctx, jobID)
+	if backupErr == nil {
+		return JobKindBackup, status, nil
+	}
+	return "", nil, fmt.Errorf("no restore or backup job %s found (%v; %v)", jobID, restoreErr, backupErr)
+}
@@ -0,0 +1,70 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+func TestGetBackupJobStatus(t *testing.T) {
+	now := time.Now()
+	backupMock := &mockBackup{
+		describeBackupOutput: &backup.DescribeBackupJobOutput{
+			BackupJobId:      aws.String("backup-1"),
+			State:            "PARTIAL",
+			ResourceArn:      aws.String("arn:aws:rds:us-west-2:123456789012:cluster:my-cluster"),
+			ResourceType:     aws.String("Aurora"),
+			RecoveryPointArn: aws.String("arn:aws:backup:us-west-2:123456789012:recovery-point:rp-9"),
+			CreationDate:     &now,
+		},
+	}
+	c := newTestClient(&mockCFN{}, backupMock, &mockRDS{})
+
+	status, err := c.GetBackupJobStatus(context.Background(), "backup-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ResourceID != "my-cluster" || !status.IsTerminal || status.RecoveryPointARN == "" {
+		t.Errorf("unexpected backup job status: %+v", status)
+	}
+}
+
+func TestLookupJob(t *testing.T) {
+	backupMock := &mockBackup{
+		describeRestoreErr:   fmt.Errorf("restore job not found"),
+		describeBackupOutput: &backup.DescribeBackupJobOutput{BackupJobId: aws.String("backup-1"), State: "RUNNING"},
+	}
+	c := newTestClient(&mockCFN{}, backupMock, &mockRDS{})
+
+	kind, status, err := c.LookupJob(context.Background(), " backup-1 ")
+	if err != nil || kind != JobKindBackup || status.IsTerminal {
+		t.Errorf("a backup job ID should be found as a running backup job, got %q %+v %v", kind, status, err)
+	}
+
+	backupMock.describeRestoreErr = nil
+	backupMock.describeRestoreOutput = &backup.DescribeRestoreJobOutput{RestoreJobId: aws.String("restore-1"), Status: "RUNNING"}
+	if kind, _, _ := c.LookupJob(context.Background(), "restore-1"); kind != JobKindRestore {
+		t.Errorf("restore jobs should be tried first, got %q", kind)
+	}
+
+	backupMock.describeRestoreErr = fmt.Errorf("restore job not found")
+	backupMock.describeBackupErr = fmt.Errorf("backup job not found")
+	if _, _, err := c.LookupJob(context.Background(), "nope"); err == nil || !strings.Contains(err.Error(), "no restore or backup job nope") {
+		t.Errorf("unknown job IDs should be reported, got %v", err)
+	}
+	if _, _, err := c.LookupJob(context.Background(), "  "); err == nil {
+		t.Error("an empty job ID should be rejected")
+	}
+}
+
+func TestGetJobStatus_UnsupportedKind(t *testing.T) {
+	c := newTestClient(&mockCFN{}, &mockBackup{}, &mockRDS{})
+	if _, err := c.GetJobStatus(context.Background(), "report", "report-1"); err == nil {
+		t.Error("unknown job kinds cannot be tracked")
+	}
+}
@@ -0,0 +1,133 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the shared operation journal: an optional DynamoDB
+// table in which every operator's session records the restores, exports,
+// clones, and backups it starts and how they end, so each operator's TUI can
+// show what the team is doing to a stack during an incident.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Journal table attributes. The table's partition key is "env" and its sort
+// key "at", both strings; "expires" suits the table's time to live setting.
+const (
+	journalEnvKey     = "env"
+	journalAtKey      = "at"
+	journalExpiresKey = "expires"
+)
+
+// JournalRetention is how long entries are kept when the table's time to
+// live is enabled on the "expires" attribute.
+const JournalRetention = 30 * 24 * time.Hour
+
+// journalTimeFormat is the sort key's time format: fixed width, so keys
+// sort by time.
+const journalTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// maxJournalEntries caps the entries TeamActivity returns.
+const maxJournalEntries = 200
+
+// JournalEntry is a transition of an operation started from the tool.
+type JournalEntry struct {
+	Env              string // Region and stack, e.g. "us-west-2/openemr"
+	At               time.Time
+	Operator         string // ARN of the identity that started the operation
+	Host             string // Machine the session ran on
+	Kind             string // JobKindRestore, JobKindExport, JobKindClone, or JobKindBackup
+	Event            string // e.g. "started", "completed", or "failed"
+	ResourceType     string
+	ResourceID       string
+	RecoveryPointARN string
+	JobID            string
+	Message          string // Why the operation failed
+}
+
+// RecordOperation adds e to the journal table.
+func (c *BackupClient) RecordOperation(ctx context.Context, table string, e JournalEntry) error {
+	at := e.At.UTC()
+	item := map[string]ddbtypes.AttributeValue{
+		journalEnvKey: &ddbtypes.AttributeValueMemberS{Value: e.Env},
+		// The host and job ID keep entries recorded in the same millisecond apart
+		journalAtKey:      &ddbtypes.AttributeValueMemberS{Value: at.Format(journalTimeFormat) + "#" + e.Host + "#" + e.JobID},
+		journalExpiresKey: &ddbtypes.AttributeValueMemberN{Value: strconv.FormatInt(at.Add(JournalRetention).Unix(), 10)},
+	}
+	for name, value := range map[string]string{
+		"operator": e.Operator, "host": e.Host, "kind": e.Kind, "event": e.Event,
+		"resourceType": e.ResourceType, "resourceId": e.ResourceID,
+		"recoveryPointArn": e.RecoveryPointARN, "jobId": e.JobID, "message": e.Message,
+	} {
+		if value != "" {
+			item[name] = &ddbtypes.AttributeValueMemberS{Value: value}
+		}
+	}
+	if _, err := c.ddb.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(table), Item: item}); err != nil {
+		return fmt.Errorf("failed to record %s %s in journal %s: %w", e.Kind, e.Event, table, err)
+	}
+	return nil
+}
+
+// TeamActivity returns the journal entries of env recorded since the given
+// time, newest first, up to 200.
+func (c *BackupClient) TeamActivity(ctx context.Context, table, env string, since time.Time) ([]JournalEntry, error) {
+	in := &dynamodb.QueryInput{
+		TableName:                aws.String(table),
+		KeyConditionExpression:   aws.String("#env = :env AND #at >= :since"),
+		ExpressionAttributeNames: map[string]string{"#env": journalEnvKey, "#at": journalAtKey},
+		ExpressionAttributeValues: map[string]ddbtypes.AttributeValue{
+			":env":   &ddbtypes.AttributeValueMemberS{Value: env},
+			":since": &ddbtypes.AttributeValueMemberS{Value: since.UTC().Format(journalTimeFormat)},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+	var entries []JournalEntry
+	for {
+		out, err := c.ddb.Query(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read journal %s: %w", table, err)
+		}
+		for _, item := range out.Items {
+			entries = append(entries, journalEntry(item))
+			if len(entries) == maxJournalEntries {
+				return entries, nil
+			}
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return entries, nil
+		}
+		in.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+// journalEntry decodes a journal table item.
+func journalEntry(item map[string]ddbtypes.AttributeValue) JournalEntry {
+	str := func(name string) string {
+		if v, ok := item[name].(*ddbtypes.AttributeValueMemberS); ok {
+			return v.Value
+		}
+		return ""
+	}
+	e := JournalEntry{
+		Env:              str(journalEnvKey),
+		Operator:         str("operator"),
+		Host:             str("host"),
+		Kind:             str("kind"),
+		Event:            str("event"),
+		ResourceType:     str("resourceType"),
+		ResourceID:       str("resourceId"),
+		RecoveryPointARN: str("recoveryPointArn"),
+		JobID:            str("jobId"),
+		Message:          str("message"),
+	}
+	if at := str(journalAtKey); len(at) >= len(journalTimeFormat) {
+		e.At, _ = time.Parse(journalTimeFormat, at[:len(journalTimeFormat)])
+	}
+	return e
+}
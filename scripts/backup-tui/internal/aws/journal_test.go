@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestSimulatedClient_Journal(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	start := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	record := []JournalEntry{
+		{Env: "us-west-2/openemr", At: start.Add(-2 * time.Hour), Kind: JobKindRestore, Event: "started", JobID: "old"},
+		{Env: "us-west-2/openemr", At: start, Operator: "arn:aws:iam::123456789012:user/alice", Host: "ops-1",
+			Kind: JobKindRestore, Event: "started", ResourceType: "RDS", ResourceID: "openemr-db", JobID: "job-1"},
+		{Env: "us-west-2/openemr", At: start.Add(time.Minute), Host: "ops-2", Kind: JobKindBackup, Event: "failed", JobID: "job-2", Message: "Access denied"},
+		{Env: "us-west-2/openemr-staging", At: start, Kind: JobKindRestore, Event: "started", JobID: "job-3"},
+	}
+	for _, e := range record {
+		if err := c.RecordOperation(ctx, "backup-tui-journal", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := c.TeamActivity(ctx, "backup-tui-journal", "us-west-2/openemr", start.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].JobID != "job-2" || got[1].JobID != "job-1" {
+		t.Fatalf("expected the environment's entries since the hour, newest first, got %+v", got)
+	}
+	if want := record[1]; got[1] != want {
+		t.Errorf("entry = %+v, want %+v", got[1], want)
+	}
+	if got[0].Message != "Access denied" {
+		t.Errorf("the failure should be recorded, got %+v", got[0])
+	}
+
+	sim := c.client.(*simulatedAWS)
+	expires, ok := sim.journal["backup-tui-journal"][0][journalExpiresKey].(*ddbtypes.AttributeValueMemberN)
+	if !ok || expires.Value != "1794812400" {
+		t.Errorf("entries should expire after JournalRetention, got %+v", sim.journal["backup-tui-journal"][0][journalExpiresKey])
+	}
+}
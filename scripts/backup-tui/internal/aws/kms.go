@@ -0,0 +1,88 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the KMS key lookup behind the restore key picker:
+// the customer managed keys (by alias) that a restored RDS cluster or EFS
+// file system can be encrypted with instead of the backup's key.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// awsManagedKeyAliases are the AWS managed key aliases for the restorable
+// resource types.
+var awsManagedKeyAliases = map[string]string{
+	"RDS":    "alias/aws/rds",
+	"Aurora": "alias/aws/rds",
+	"EFS":    "alias/aws/elasticfilesystem",
+}
+
+// KMSKey is a KMS key a restore can encrypt with, identified by its alias.
+type KMSKey struct {
+	Alias      string // e.g. "alias/openemr-restore"
+	AliasARN   string
+	KeyID      string // Target key ID; empty for an AWS managed key not created yet
+	AWSManaged bool
+}
+
+// ListKMSKeys returns the keys a restore of resourceType can be encrypted
+// with: every customer managed key alias in the region, sorted by alias,
+// followed by the AWS managed key for the resource type. Aliases not
+// pointing at a key are skipped.
+func (c *BackupClient) ListKMSKeys(ctx context.Context, resourceType string) ([]KMSKey, error) {
+	if c.kms == nil {
+		return nil, fmt.Errorf("KMS client not configured")
+	}
+
+	managedAlias := awsManagedKeyAliases[resourceType]
+	var keys []KMSKey
+	var managed *KMSKey
+	paginator := kms.NewListAliasesPaginator(c.kms, &kms.ListAliasesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list KMS aliases: %w", err)
+		}
+		for _, a := range page.Aliases {
+			key := KMSKey{
+				Alias:    aws.ToString(a.AliasName),
+				AliasARN: aws.ToString(a.AliasArn),
+				KeyID:    aws.ToString(a.TargetKeyId),
+			}
+			switch {
+			case key.Alias == managedAlias:
+				key.AWSManaged = true
+				managed = &key
+			case strings.HasPrefix(key.Alias, "alias/aws/"), key.KeyID == "":
+				continue
+			default:
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Alias < keys[j].Alias })
+	if managed == nil && managedAlias != "" {
+		// AWS creates its managed key on first use, so the alias may not
+		// exist yet; restoring with it still works.
+		managed = &KMSKey{Alias: managedAlias, AWSManaged: true}
+	}
+	if managed != nil {
+		keys = append(keys, *managed)
+	}
+	return keys, nil
+}
+
+// KeyRef returns the identifier to pass to the restore: the alias ARN when
+// known, otherwise the alias name.
+func (k KMSKey) KeyRef() string {
+	if k.AliasARN != "" {
+		return k.AliasARN
+	}
+	return k.Alias
+}
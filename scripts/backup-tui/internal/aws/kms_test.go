@@ -0,0 +1,95 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+type mockKMS struct {
+	aliases []kmstypes.AliasListEntry
+	err     error
+}
+
+func (m *mockKMS) ListAliases(_ context.Context, _ *kms.ListAliasesInput, _ ...func(*kms.Options)) (*kms.ListAliasesOutput, error) {
+	return &kms.ListAliasesOutput{Aliases: m.aliases}, m.err
+}
+
+func alias(name, keyID string) kmstypes.AliasListEntry {
+	entry := kmstypes.AliasListEntry{AliasName: aws.String(name), AliasArn: aws.String("arn:aws:kms:us-west-2:1:" + name)}
+	if keyID != "" {
+		entry.TargetKeyId = aws.String(keyID)
+	}
+	return entry
+}
+
+func TestListKMSKeys(t *testing.T) {
+	c := newTestClient(&mockCFN{}, &mockBackup{}, &mockRDS{})
+	c.kms = &mockKMS{aliases: []kmstypes.AliasListEntry{
+		alias("alias/zeta", "key-z"),
+		alias("alias/aws/rds", "key-rds"),
+		alias("alias/aws/ebs", "key-ebs"),
+		alias("alias/unused", ""),
+		alias("alias/alpha", "key-a"),
+	}}
+
+	keys, err := c.ListKMSKeys(context.Background(), "RDS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 || keys[0].Alias != "alias/alpha" || keys[1].Alias != "alias/zeta" {
+		t.Fatalf("customer managed keys should be sorted, other AWS managed and unused aliases skipped: %+v", keys)
+	}
+	if last := keys[2]; last.Alias != "alias/aws/rds" || !last.AWSManaged || last.KeyID != "key-rds" {
+		t.Errorf("the resource type's AWS managed key should come last: %+v", last)
+	}
+
+	keys, err = c.ListKMSKeys(context.Background(), "EFS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := keys[len(keys)-1]; last.Alias != "alias/aws/elasticfilesystem" || last.KeyRef() != "alias/aws/elasticfilesystem" {
+		t.Errorf("a not yet created AWS managed key should be offered by alias name: %+v", last)
+	}
+}
+
+func TestListKMSKeys_Errors(t *testing.T) {
+	c := newTestClient(&mockCFN{}, &mockBackup{}, &mockRDS{})
+	if _, err := c.ListKMSKeys(context.Background(), "RDS"); err == nil {
+		t.Error("expected error without a KMS client")
+	}
+
+	c.kms = &mockKMS{err: errors.New("access denied")}
+	if _, err := c.ListKMSKeys(context.Background(), "RDS"); err == nil {
+		t.Error("expected API error to be returned")
+	}
+}
+
+func TestApplyRestoreOptions(t *testing.T) {
+	metadata := map[string]string{"newFileSystem": "false"}
+	applyRestoreOptions(metadata, "EFS", RestoreOptions{})
+	if len(metadata) != 1 {
+		t.Errorf("zero options should not change the metadata: %v", metadata)
+	}
+
+	applyRestoreOptions(metadata, "EFS", RestoreOptions{KMSKeyID: "alias/restore"})
+	if metadata["KmsKeyId"] != "alias/restore" || metadata["newFileSystem"] != "true" || metadata["CreationToken"] == "" {
+		t.Errorf("a different key should restore EFS into a new file system: %v", metadata)
+	}
+
+	metadata = map[string]string{}
+	applyRestoreOptions(metadata, "RDS", RestoreOptions{KMSKeyID: "alias/restore"})
+	if metadata["KmsKeyId"] != "alias/restore" || metadata["newFileSystem"] != "" {
+		t.Errorf("unexpected RDS metadata: %v", metadata)
+	}
+
+	preview := RestoreMetadata{ResourceType: "EFS"}
+	preview.ApplyOptions(RestoreOptions{KMSKeyID: "alias/restore"})
+	if !preview.NewFileSystem || preview.KMSKeyID != "alias/restore" {
+		t.Errorf("preview should match the restore: %+v", preview)
+	}
+}
@@ -0,0 +1,124 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the latest restorable point per resource: the
+// newest recovery point that can be relied on for a restore right now,
+// i.e. completed, in warm storage, and not failed by a restore test.
+package aws
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+// restoreTestHistory is how far back restore jobs are read for restore
+// test validation results.
+const restoreTestHistory = 30 * 24 * time.Hour
+
+// LatestPoint is the latest restorable recovery point of a resource.
+type LatestPoint struct {
+	ResourceType     string    `json:"resourceType"`
+	ResourceID       string    `json:"resourceId"`
+	ResourceARN      string    `json:"resourceArn"`
+	RecoveryPointARN string    `json:"recoveryPointArn,omitempty"` // Empty when no recovery point qualifies
+	CreatedAt        time.Time `json:"createdAt,omitzero"`
+	SizeBytes        int64     `json:"sizeBytes"`
+	Validated        bool      `json:"validated"` // A restore test of the point passed validation
+	Skipped          int       `json:"skipped"`   // Newer recovery points that did not qualify
+}
+
+// Found reports whether the resource has a restorable recovery point.
+func (p LatestPoint) Found() bool {
+	return p.RecoveryPointARN != ""
+}
+
+// LatestRestorable returns, for each resource with recovery points in
+// points, its newest COMPLETED recovery point that is not in cold storage
+// at now and whose latest restore test in restores did not fail
+// validation. Resources are ordered by type and ID.
+func LatestRestorable(points []RecoveryPoint, restores []JobRecord, now time.Time) []LatestPoint {
+	// The newest validation result of each recovery point
+	validation := make(map[string]JobRecord)
+	for _, j := range restores {
+		if j.Kind != JobKindRestore || j.RecoveryPointARN == "" || j.ValidationStatus == "" {
+			continue
+		}
+		if prev, ok := validation[j.RecoveryPointARN]; !ok || j.CreatedAt.After(prev.CreatedAt) {
+			validation[j.RecoveryPointARN] = j
+		}
+	}
+
+	newestFirst := slices.Clone(points)
+	slices.SortStableFunc(newestFirst, func(a, b RecoveryPoint) int { return b.CreationDate.Compare(a.CreationDate) })
+
+	byResource := make(map[string]*LatestPoint)
+	var latest []*LatestPoint
+	for _, rp := range newestFirst {
+		p, ok := byResource[rp.ResourceARN]
+		if !ok {
+			p = &LatestPoint{ResourceType: rp.ResourceType, ResourceID: rp.ResourceID, ResourceARN: rp.ResourceARN}
+			byResource[rp.ResourceARN] = p
+			latest = append(latest, p)
+		}
+		if p.Found() {
+			continue
+		}
+		status := validation[rp.RecoveryPointARN].ValidationStatus
+		if rp.Status != "COMPLETED" || rp.InColdStorage(now) || status == "FAILED" || status == "TIMED_OUT" {
+			p.Skipped++
+			continue
+		}
+		p.RecoveryPointARN = rp.RecoveryPointARN
+		p.CreatedAt = rp.CreationDate
+		p.SizeBytes = rp.BackupSizeInBytes
+		p.Validated = status == "SUCCESSFUL"
+	}
+
+	out := make([]LatestPoint, 0, len(latest))
+	for _, p := range latest {
+		out = append(out, *p)
+	}
+	return sortLatest(out)
+}
+
+// sortLatest orders latest points by resource type and ID.
+func sortLatest(latest []LatestPoint) []LatestPoint {
+	slices.SortStableFunc(latest, func(a, b LatestPoint) int {
+		return cmp.Or(cmp.Compare(a.ResourceType, b.ResourceType), cmp.Compare(a.ResourceID, b.ResourceID))
+	})
+	return latest
+}
+
+// RecentRestores returns the restore jobs of the last 30 days, whose
+// restore test results LatestRestorable takes into account.
+func (c *BackupClient) RecentRestores(ctx context.Context) ([]JobRecord, error) {
+	return c.ListRestoreJobs(ctx, time.Now().Add(-restoreTestHistory))
+}
+
+// LatestRestorablePoints returns the latest restorable point of each
+// resource backed up to vaultName.
+func (c *BackupClient) LatestRestorablePoints(ctx context.Context, vaultName string) ([]LatestPoint, error) {
+	points, err := c.ListRecoveryPoints(ctx, vaultName, "")
+	if err != nil {
+		return nil, err
+	}
+	restores, err := c.RecentRestores(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return LatestRestorable(points, restores, time.Now()), nil
+}
+
+// RestorablePointsAt returns the restorable point of each resource backed
+// up to vaultName closest to but not after at.
+func (c *BackupClient) RestorablePointsAt(ctx context.Context, vaultName string, at time.Time) ([]LatestPoint, error) {
+	points, err := c.ListRecoveryPoints(ctx, vaultName, "")
+	if err != nil {
+		return nil, err
+	}
+	restores, err := c.RecentRestores(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return RestorableAt(points, restores, time.Now(), at), nil
+}
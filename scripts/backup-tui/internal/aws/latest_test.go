@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLatestRestorable(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	points := []RecoveryPoint{
+		{RecoveryPointARN: "db-1", ResourceARN: "arn:db", ResourceType: "RDS", ResourceID: "db", Status: "COMPLETED", CreationDate: now.Add(-50 * time.Hour)},
+		{RecoveryPointARN: "db-2", ResourceARN: "arn:db", ResourceType: "RDS", ResourceID: "db", Status: "COMPLETED", CreationDate: now.Add(-26 * time.Hour)},
+		{RecoveryPointARN: "db-3", ResourceARN: "arn:db", ResourceType: "RDS", ResourceID: "db", Status: "PARTIAL", CreationDate: now.Add(-2 * time.Hour)},
+		{RecoveryPointARN: "fs-1", ResourceARN: "arn:fs", ResourceType: "EFS", ResourceID: "fs", Status: "COMPLETED", CreationDate: now.Add(-30 * 24 * time.Hour), MoveToColdAt: now.Add(-time.Hour)},
+	}
+	restores := []JobRecord{
+		{Kind: JobKindRestore, RecoveryPointARN: "db-2", ValidationStatus: "SUCCESSFUL", CreatedAt: now.Add(-20 * time.Hour)},
+		{Kind: JobKindRestore, RecoveryPointARN: "db-2", ValidationStatus: "FAILED", CreatedAt: now.Add(-10 * time.Hour)},
+		{Kind: JobKindRestore, RecoveryPointARN: "db-1", ValidationStatus: "SUCCESSFUL", CreatedAt: now.Add(-40 * time.Hour)},
+	}
+
+	latest := LatestRestorable(points, restores, now)
+	if len(latest) != 2 || latest[0].ResourceType != "EFS" || latest[1].ResourceType != "RDS" {
+		t.Fatalf("expected one entry per resource ordered by type, got %+v", latest)
+	}
+	if latest[0].Found() || latest[0].Skipped != 1 {
+		t.Errorf("a backup in cold storage is not restorable right away: %+v", latest[0])
+	}
+	if db := latest[1]; db.RecoveryPointARN != "db-1" || !db.Validated || db.Skipped != 2 {
+		t.Errorf("partial and most recently failed restore tests should be skipped: %+v", db)
+	}
+
+	if latest := LatestRestorable(points[:2], nil, now); latest[0].RecoveryPointARN != "db-2" || latest[0].Validated {
+		t.Errorf("without restore tests the newest completed point is restorable but not validated: %+v", latest[0])
+	}
+}
+
+func TestSimulatedClient_LatestRestorablePoints(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	latest, err := c.LatestRestorablePoints(context.Background(), fx.Vaults[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range latest {
+		if !p.Found() {
+			t.Errorf("every fixture resource has a completed backup: %+v", p)
+		}
+	}
+}
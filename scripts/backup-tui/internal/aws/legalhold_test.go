@@ -0,0 +1,115 @@
+package aws
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+)
+
+func legalHoldPoints() []RecoveryPoint {
+	base := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	return []RecoveryPoint{
+		{RecoveryPointARN: "rp-db-1", ResourceARN: "arn:db", CreationDate: base},
+		{RecoveryPointARN: "rp-db-2", ResourceARN: "arn:db", CreationDate: base.AddDate(0, 0, 1)},
+		{RecoveryPointARN: "rp-db-3", ResourceARN: "arn:db", CreationDate: base.AddDate(0, 0, 2)},
+		{RecoveryPointARN: "rp-fs-2", ResourceARN: "arn:fs", CreationDate: base.AddDate(0, 0, 1)},
+		{RecoveryPointARN: "rp-db-4", ResourceARN: "arn:db", CreationDate: base.AddDate(0, 0, 3)},
+	}
+}
+
+func TestLegalHoldCoverage(t *testing.T) {
+	all := legalHoldPoints()
+	covered := LegalHoldCoverage(all, []RecoveryPoint{all[0], all[2]})
+	var arns []string
+	for _, rp := range covered {
+		arns = append(arns, rp.RecoveryPointARN)
+	}
+	if !slices.Equal(arns, []string{"rp-db-1", "rp-db-2", "rp-db-3"}) {
+		t.Errorf("hold should cover the selected resource between the selected dates, got %v", arns)
+	}
+	if LegalHoldCoverage(all, nil) != nil {
+		t.Error("nothing selected should cover nothing")
+	}
+}
+
+func TestCreateLegalHold(t *testing.T) {
+	backupMock := &mockBackup{}
+	c := newTestClient(&mockCFN{}, backupMock, &mockRDS{})
+	all := legalHoldPoints()
+
+	if _, err := c.CreateLegalHold(context.Background(), "vault", "", "", all[:1]); err == nil {
+		t.Error("a hold without a title and description should be refused")
+	}
+
+	hold, err := c.CreateLegalHold(context.Background(), "vault", "Case 2026-17", "Subpoena for March records", []RecoveryPoint{all[1], all[3]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sel := backupMock.createHoldInput.RecoveryPointSelection
+	if hold.ID != "hold-1" || !slices.Equal(sel.VaultNames, []string{"vault"}) || len(sel.ResourceIdentifiers) != 2 {
+		t.Errorf("unexpected hold %+v with selection %+v", hold, sel)
+	}
+	if from := aws.ToTime(sel.DateRange.FromDate); from.After(all[1].CreationDate) {
+		t.Errorf("date range should start at the earliest selected point, got %v", from)
+	}
+}
+
+func TestListLegalHolds(t *testing.T) {
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	backupMock := &mockBackup{
+		listHoldsOut: &backup.ListLegalHoldsOutput{LegalHolds: []backuptypes.LegalHold{
+			{LegalHoldId: aws.String("old"), Status: backuptypes.LegalHoldStatusCanceled, CreationDate: aws.Time(older)},
+			{LegalHoldId: aws.String("new"), Status: backuptypes.LegalHoldStatusActive, CreationDate: aws.Time(older.AddDate(0, 1, 0))},
+		}},
+		holdPointsOut: map[string]*backup.ListRecoveryPointsByLegalHoldOutput{
+			"new": {RecoveryPoints: []backuptypes.RecoveryPointMember{{RecoveryPointArn: aws.String("rp-db-1")}}},
+		},
+	}
+	c := newTestClient(&mockCFN{}, backupMock, &mockRDS{})
+
+	holds, err := c.ListLegalHolds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holds) != 2 || holds[0].ID != "new" || !holds[0].Holds("rp-db-1") || holds[1].Active() {
+		t.Errorf("unexpected holds: %+v", holds)
+	}
+
+	if err := c.ReleaseLegalHold(context.Background(), "new", ""); err == nil {
+		t.Error("releasing without a reason should be refused")
+	}
+	if err := c.ReleaseLegalHold(context.Background(), "new", "Case closed"); err != nil || aws.ToString(backupMock.cancelHoldInput.CancelDescription) != "Case closed" {
+		t.Errorf("release should record the reason: %v", err)
+	}
+}
+
+func TestSimulatedClient_LegalHold(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	points, _ := c.ListRecoveryPoints(context.Background(), fx.Vaults[0], "")
+	if len(points) == 0 {
+		t.Fatal("no recovery points in the fixtures")
+	}
+
+	hold, err := c.CreateLegalHold(context.Background(), fx.Vaults[0], "Case", "Litigation", points[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	holds, err := c.ListLegalHolds(context.Background())
+	if err != nil || len(holds) != 1 || !holds[0].Holds(points[0].RecoveryPointARN) {
+		t.Fatalf("new hold should hold the selected point: %+v, %v", holds, err)
+	}
+
+	if err := c.ReleaseLegalHold(context.Background(), hold.ID, "Settled"); err != nil {
+		t.Fatal(err)
+	}
+	holds, _ = c.ListLegalHolds(context.Background())
+	if holds[0].Active() || holds[0].Cancelled.IsZero() {
+		t.Errorf("released hold should be cancelled: %+v", holds[0])
+	}
+}
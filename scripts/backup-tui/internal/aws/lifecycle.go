@@ -0,0 +1,166 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements recovery point lifecycles: when AWS Backup moves a
+// recovery point to cold storage and when it deletes it, and changing them
+// for a single recovery point.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+)
+
+// minColdStorageDays is how long AWS Backup requires a recovery point to
+// stay in cold storage before it may be deleted.
+const minColdStorageDays = 90
+
+// Lifecycle is a recovery point retention setting, in days after the
+// recovery point was created. Zero means never.
+type Lifecycle struct {
+	MoveToColdStorageAfterDays int64 `json:"moveToColdStorageAfterDays,omitempty"`
+	DeleteAfterDays            int64 `json:"deleteAfterDays,omitempty"`
+}
+
+// Validate checks the lifecycle against the rules AWS Backup enforces:
+// days are not negative, and a recovery point moved to cold storage stays
+// there at least 90 days before it is deleted.
+func (l Lifecycle) Validate() error {
+	if l.MoveToColdStorageAfterDays < 0 || l.DeleteAfterDays < 0 {
+		return fmt.Errorf("lifecycle days cannot be negative")
+	}
+	if l.MoveToColdStorageAfterDays > 0 && l.DeleteAfterDays > 0 &&
+		l.DeleteAfterDays < l.MoveToColdStorageAfterDays+minColdStorageDays {
+		return fmt.Errorf("delete after %d days is too soon: recovery points moved to cold storage after %d days must stay there at least %d days (delete after %d days or more)",
+			l.DeleteAfterDays, l.MoveToColdStorageAfterDays, minColdStorageDays, l.MoveToColdStorageAfterDays+minColdStorageDays)
+	}
+	return nil
+}
+
+// String describes the lifecycle, e.g. "delete after 35 days, never move to
+// cold storage".
+func (l Lifecycle) String() string {
+	del := "never delete"
+	if l.DeleteAfterDays > 0 {
+		del = fmt.Sprintf("delete after %d days", l.DeleteAfterDays)
+	}
+	cold := "never move to cold storage"
+	if l.MoveToColdStorageAfterDays > 0 {
+		cold = fmt.Sprintf("move to cold storage after %d days", l.MoveToColdStorageAfterDays)
+	}
+	return del + ", " + cold
+}
+
+// DeleteAt returns when a recovery point created at created is deleted
+// under the lifecycle, or the zero time if it is kept indefinitely.
+func (l Lifecycle) DeleteAt(created time.Time) time.Time {
+	if l.DeleteAfterDays <= 0 {
+		return time.Time{}
+	}
+	return created.AddDate(0, 0, int(l.DeleteAfterDays))
+}
+
+// MoveToColdAt returns when a recovery point created at created moves to
+// cold storage under the lifecycle, or the zero time if it never does.
+func (l Lifecycle) MoveToColdAt(created time.Time) time.Time {
+	if l.MoveToColdStorageAfterDays <= 0 {
+		return time.Time{}
+	}
+	return created.AddDate(0, 0, int(l.MoveToColdStorageAfterDays))
+}
+
+// SupportsColdStorage reports whether AWS Backup can move recovery points
+// of resourceType to cold storage. RDS and Aurora snapshots cannot be; a
+// cold storage setting is ignored for them.
+func SupportsColdStorage(resourceType string) bool {
+	switch resourceType {
+	case "EFS", "DynamoDB", "Timestream", "SAP HANA on Amazon EC2", "VirtualMachine":
+		return true
+	}
+	return false
+}
+
+// UpdateLifecycle changes the retention of a single recovery point, e.g. to
+// keep a pre-incident backup beyond the plan's deletion date. A zero
+// lifecycle keeps it indefinitely. It returns rp with the new lifecycle and
+// the dates AWS Backup calculated from it.
+func (c *BackupClient) UpdateLifecycle(ctx context.Context, vaultName string, rp RecoveryPoint, l Lifecycle) (RecoveryPoint, error) {
+	if err := l.Validate(); err != nil {
+		return rp, err
+	}
+	if l.MoveToColdStorageAfterDays > 0 && !SupportsColdStorage(rp.ResourceType) {
+		return rp, fmt.Errorf("%s recovery points cannot be moved to cold storage", rp.ResourceType)
+	}
+	now := time.Now()
+	if rp.InColdStorage(now) && l.MoveToColdStorageAfterDays != rp.Lifecycle.MoveToColdStorageAfterDays {
+		return rp, fmt.Errorf("the recovery point is already in cold storage; its cold storage setting cannot be changed")
+	}
+	if deleteAt := l.DeleteAt(rp.CreationDate); !deleteAt.IsZero() && !deleteAt.After(now) {
+		return rp, fmt.Errorf("delete after %d days is already past (created %s): AWS Backup would delete the recovery point now",
+			l.DeleteAfterDays, rp.CreationDate.Format("2006-01-02"))
+	}
+
+	lc := l.toAPI()
+	if lc == nil {
+		// An empty lifecycle, unlike none, removes the retention
+		lc = &backuptypes.Lifecycle{}
+	}
+	out, err := c.client.UpdateRecoveryPointLifecycle(ctx, &backup.UpdateRecoveryPointLifecycleInput{
+		BackupVaultName:  aws.String(vaultName),
+		RecoveryPointArn: aws.String(rp.RecoveryPointARN),
+		Lifecycle:        lc,
+	})
+	if err != nil {
+		return rp, fmt.Errorf("failed to update recovery point lifecycle: %w", err)
+	}
+	rp.Lifecycle = lifecycleFromAPI(out.Lifecycle)
+	rp.MoveToColdAt, rp.DeleteAt = rp.Lifecycle.MoveToColdAt(rp.CreationDate), rp.Lifecycle.DeleteAt(rp.CreationDate)
+	if out.CalculatedLifecycle != nil {
+		rp.MoveToColdAt = aws.ToTime(out.CalculatedLifecycle.MoveToColdStorageAt)
+		rp.DeleteAt = aws.ToTime(out.CalculatedLifecycle.DeleteAt)
+	}
+	return rp, nil
+}
+
+// lifecycleFromAPI converts an AWS Backup lifecycle.
+func lifecycleFromAPI(l *backuptypes.Lifecycle) Lifecycle {
+	if l == nil {
+		return Lifecycle{}
+	}
+	return Lifecycle{
+		MoveToColdStorageAfterDays: aws.ToInt64(l.MoveToColdStorageAfterDays),
+		DeleteAfterDays:            aws.ToInt64(l.DeleteAfterDays),
+	}
+}
+
+// toAPI converts the lifecycle for AWS Backup, or nil if it is unset.
+func (l Lifecycle) toAPI() *backuptypes.Lifecycle {
+	if l == (Lifecycle{}) {
+		return nil
+	}
+	out := &backuptypes.Lifecycle{}
+	if l.MoveToColdStorageAfterDays > 0 {
+		out.MoveToColdStorageAfterDays = aws.Int64(l.MoveToColdStorageAfterDays)
+	}
+	if l.DeleteAfterDays > 0 {
+		out.DeleteAfterDays = aws.Int64(l.DeleteAfterDays)
+	}
+	return out
+}
+
+// timeOrNil returns a pointer to t, or nil for the zero time.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// InColdStorage reports whether the recovery point has moved to cold
+// storage by now.
+func (rp RecoveryPoint) InColdStorage(now time.Time) bool {
+	return !rp.MoveToColdAt.IsZero() && !rp.MoveToColdAt.After(now)
+}
@@ -0,0 +1,105 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLifecycle_Validate(t *testing.T) {
+	tests := []struct {
+		lifecycle Lifecycle
+		wantErr   string
+	}{
+		{Lifecycle{DeleteAfterDays: 35}, ""},
+		{Lifecycle{MoveToColdStorageAfterDays: 30, DeleteAfterDays: 120}, ""},
+		{Lifecycle{MoveToColdStorageAfterDays: 30, DeleteAfterDays: 119}, "delete after 120 days or more"},
+		{Lifecycle{DeleteAfterDays: -1}, "negative"},
+	}
+	for _, tt := range tests {
+		err := tt.lifecycle.Validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%v: unexpected error %v", tt.lifecycle, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%v: error %v, want %q", tt.lifecycle, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSimulatedClient_RecoveryPointLifecycle(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewSimulatedBackupClient(fx)
+	points, err := c.ListRecoveryPoints(context.Background(), fx.Vaults[0], "EFS")
+	if err != nil || len(points) == 0 {
+		t.Fatalf("ListRecoveryPoints: %v, %d points", err, len(points))
+	}
+	rp := points[0]
+	if rp.Lifecycle.MoveToColdStorageAfterDays != 30 || !rp.DeleteAt.Equal(rp.CreationDate.AddDate(0, 0, 365)) {
+		t.Errorf("fixture lifecycle not reported: %+v", rp)
+	}
+	if rp.InColdStorage(time.Now()) || !rp.InColdStorage(rp.CreationDate.AddDate(0, 0, 31)) {
+		t.Error("a 30-day cold storage setting should move the point after 30 days")
+	}
+}
+
+func TestUpdateLifecycle(t *testing.T) {
+	backupMock := &mockBackup{}
+	c := newTestClient(&mockCFN{}, backupMock, &mockRDS{})
+	rp := RecoveryPoint{
+		RecoveryPointARN: "arn:aws:backup:us-west-2:123456789012:recovery-point:rds-1",
+		ResourceType:     "RDS",
+		CreationDate:     time.Now().AddDate(0, 0, -30),
+		Lifecycle:        Lifecycle{DeleteAfterDays: 35},
+	}
+
+	got, err := c.UpdateLifecycle(context.Background(), "vault", rp, Lifecycle{DeleteAfterDays: 365})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := backupMock.updateLifecycleInput
+	if *in.BackupVaultName != "vault" || *in.RecoveryPointArn != rp.RecoveryPointARN || *in.Lifecycle.DeleteAfterDays != 365 {
+		t.Errorf("unexpected input: %+v", in)
+	}
+	if got.Lifecycle.DeleteAfterDays != 365 || !got.DeleteAt.Equal(rp.CreationDate.AddDate(0, 0, 365)) {
+		t.Errorf("recovery point should carry the new lifecycle: %+v", got)
+	}
+
+	got, err = c.UpdateLifecycle(context.Background(), "vault", rp, Lifecycle{})
+	if err != nil || backupMock.updateLifecycleInput.Lifecycle == nil || !got.DeleteAt.IsZero() {
+		t.Errorf("keeping indefinitely should send an empty lifecycle: %+v, %v", backupMock.updateLifecycleInput.Lifecycle, err)
+	}
+
+	for _, tt := range []struct {
+		lifecycle Lifecycle
+		wantErr   string
+	}{
+		{Lifecycle{DeleteAfterDays: 7}, "already past"},
+		{Lifecycle{MoveToColdStorageAfterDays: 30, DeleteAfterDays: 365}, "cannot be moved to cold storage"},
+	} {
+		if _, err := c.UpdateLifecycle(context.Background(), "vault", rp, tt.lifecycle); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%v: error %v, want %q", tt.lifecycle, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSimulatedClient_UpdateLifecycle(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	points, _ := c.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	if len(points) == 0 {
+		t.Fatal("no RDS recovery points in the fixtures")
+	}
+
+	if _, err := c.UpdateLifecycle(context.Background(), fx.Vaults[0], points[0], Lifecycle{}); err != nil {
+		t.Fatal(err)
+	}
+	points, _ = c.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	if points[0].Lifecycle != (Lifecycle{}) || !points[0].DeleteAt.IsZero() {
+		t.Errorf("relisted recovery point should be kept indefinitely: %+v", points[0])
+	}
+}
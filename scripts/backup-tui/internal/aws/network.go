@@ -0,0 +1,194 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the network lookups behind the restore security
+// group and subnet group pickers: the stack's VPC and the security groups
+// and DB subnet groups in it, so a restored cluster can be placed in an
+// isolated or staging network instead of the live cluster's.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// MinSubnetGroupAZs is how many Availability Zones a DB subnet group must
+// cover before RDS creates a cluster in it: Aurora spreads a cluster over
+// at least two, and a group covering fewer fails the restore after the
+// job has started.
+const MinSubnetGroupAZs = 2
+
+// SecurityGroup is a VPC security group a restored cluster can use.
+type SecurityGroup struct {
+	ID          string // e.g. "sg-0123456789abcdef0"
+	Name        string
+	Description string
+	VPCID       string
+}
+
+// StackVPC returns the ID of the stack's VPC: its AWS::EC2::VPC resource,
+// or, for stacks deployed into an existing VPC, the VPC of the live
+// cluster's security groups.
+func (c *BackupClient) StackVPC(ctx context.Context, stackName string) (string, error) {
+	paginator := cloudformation.NewListStackResourcesPaginator(c.cfn, &cloudformation.ListStackResourcesInput{
+		StackName: aws.String(stackName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list stack resources: %w", err)
+		}
+		for _, r := range page.StackResourceSummaries {
+			if aws.ToString(r.ResourceType) == "AWS::EC2::VPC" && aws.ToString(r.PhysicalResourceId) != "" {
+				return aws.ToString(r.PhysicalResourceId), nil
+			}
+		}
+	}
+
+	clusterID, err := c.getRDSClusterIDFromStack(ctx, stackName)
+	if err != nil {
+		return "", fmt.Errorf("stack %s has no VPC resource and no database to find it from: %w", stackName, err)
+	}
+	_, securityGroups, err := c.getRDSClusterDetails(ctx, clusterID)
+	if err != nil {
+		return "", err
+	}
+	groups, err := c.describeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{GroupIds: splitIDs(securityGroups)})
+	if err != nil {
+		return "", err
+	}
+	if len(groups) == 0 || groups[0].VPCID == "" {
+		return "", fmt.Errorf("could not determine the VPC of stack %s", stackName)
+	}
+	return groups[0].VPCID, nil
+}
+
+// ListSecurityGroups returns the security groups in vpcID, sorted by name.
+func (c *BackupClient) ListSecurityGroups(ctx context.Context, vpcID string) ([]SecurityGroup, error) {
+	if vpcID == "" {
+		return nil, fmt.Errorf("VPC ID cannot be empty")
+	}
+	groups, err := c.describeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("vpc-id"), Values: []string{vpcID}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+// describeSecurityGroups pages through DescribeSecurityGroups.
+func (c *BackupClient) describeSecurityGroups(ctx context.Context, in *ec2.DescribeSecurityGroupsInput) ([]SecurityGroup, error) {
+	if c.ec2 == nil {
+		return nil, fmt.Errorf("EC2 client not configured")
+	}
+	var groups []SecurityGroup
+	paginator := ec2.NewDescribeSecurityGroupsPaginator(c.ec2, in)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe security groups: %w", err)
+		}
+		for _, g := range page.SecurityGroups {
+			groups = append(groups, SecurityGroup{
+				ID:          aws.ToString(g.GroupId),
+				Name:        aws.ToString(g.GroupName),
+				Description: aws.ToString(g.Description),
+				VPCID:       aws.ToString(g.VpcId),
+			})
+		}
+	}
+	return groups, nil
+}
+
+// DBSubnetGroup is a DB subnet group a restored cluster can be created in.
+type DBSubnetGroup struct {
+	Name        string
+	Description string
+	VPCID       string
+	Subnets     []Subnet
+}
+
+// Subnet is a subnet of a DB subnet group.
+type Subnet struct {
+	ID     string
+	AZ     string
+	Status string // "Active" unless the subnet has been deleted or is failing
+}
+
+// AZs returns the sorted Availability Zones of the group's active subnets.
+func (g DBSubnetGroup) AZs() []string {
+	var azs []string
+	for _, sn := range g.Subnets {
+		if sn.Status == "Active" && sn.AZ != "" && !slices.Contains(azs, sn.AZ) {
+			azs = append(azs, sn.AZ)
+		}
+	}
+	sort.Strings(azs)
+	return azs
+}
+
+// Validate reports why a cluster restored into the group would fail: it
+// must be in vpcID, when set, for the restore's security groups to apply,
+// and cover MinSubnetGroupAZs Availability Zones.
+func (g DBSubnetGroup) Validate(vpcID string) error {
+	if vpcID != "" && g.VPCID != vpcID {
+		return fmt.Errorf("subnet group %s is in %s, not the stack's VPC %s", g.Name, g.VPCID, vpcID)
+	}
+	if azs := g.AZs(); len(azs) < MinSubnetGroupAZs {
+		return fmt.Errorf("subnet group %s covers %d Availability Zone(s), Aurora needs at least %d", g.Name, len(azs), MinSubnetGroupAZs)
+	}
+	return nil
+}
+
+// ListDBSubnetGroups returns the DB subnet groups in vpcID, or in the
+// region when vpcID is empty, sorted by name.
+func (c *BackupClient) ListDBSubnetGroups(ctx context.Context, vpcID string) ([]DBSubnetGroup, error) {
+	var groups []DBSubnetGroup
+	paginator := rds.NewDescribeDBSubnetGroupsPaginator(c.rds, &rds.DescribeDBSubnetGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DB subnet groups: %w", err)
+		}
+		for _, g := range page.DBSubnetGroups {
+			if vpcID != "" && aws.ToString(g.VpcId) != vpcID {
+				continue
+			}
+			group := DBSubnetGroup{
+				Name:        aws.ToString(g.DBSubnetGroupName),
+				Description: aws.ToString(g.DBSubnetGroupDescription),
+				VPCID:       aws.ToString(g.VpcId),
+			}
+			for _, sn := range g.Subnets {
+				subnet := Subnet{ID: aws.ToString(sn.SubnetIdentifier), Status: aws.ToString(sn.SubnetStatus)}
+				if sn.SubnetAvailabilityZone != nil {
+					subnet.AZ = aws.ToString(sn.SubnetAvailabilityZone.Name)
+				}
+				group.Subnets = append(group.Subnets, subnet)
+			}
+			groups = append(groups, group)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+// splitIDs splits a comma-separated ID list, as in restore metadata.
+func splitIDs(s string) []string {
+	var ids []string
+	for id := range strings.SplitSeq(s, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
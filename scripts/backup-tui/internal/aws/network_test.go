@@ -0,0 +1,147 @@
+package aws
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestStackVPC_Simulated(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	stack := fx.Stacks[0].Name
+
+	vpcID, err := c.StackVPC(context.Background(), stack)
+	if err != nil || vpcID != "vpc-0sim0001" {
+		t.Fatalf("StackVPC = %q, %v; want the stack's VPC resource", vpcID, err)
+	}
+
+	// Stacks deployed into an existing VPC have no VPC resource
+	fx.Stacks[0].Resources = slices.DeleteFunc(fx.Stacks[0].Resources, func(r FixtureStackResource) bool {
+		return r.Type == "AWS::EC2::VPC"
+	})
+	c = NewSimulatedBackupClient(fx)
+	vpcID, err = c.StackVPC(context.Background(), stack)
+	if err != nil || vpcID != "vpc-0sim0001" {
+		t.Errorf("StackVPC = %q, %v; want the VPC of the live cluster's groups", vpcID, err)
+	}
+}
+
+func TestListSecurityGroups_Simulated(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+
+	groups, err := c.ListSecurityGroups(context.Background(), "vpc-0sim0001")
+	if err != nil {
+		t.Fatalf("ListSecurityGroups: %v", err)
+	}
+	if len(groups) != 4 {
+		t.Fatalf("expected the 4 groups of the VPC, got %+v", groups)
+	}
+	for i, g := range groups {
+		if g.VPCID != "vpc-0sim0001" {
+			t.Errorf("group %s is from another VPC", g.ID)
+		}
+		if i > 0 && groups[i-1].Name > g.Name {
+			t.Errorf("groups should be sorted by name: %s before %s", groups[i-1].Name, g.Name)
+		}
+	}
+
+	if _, err := c.ListSecurityGroups(context.Background(), ""); err == nil {
+		t.Error("expected error for an empty VPC ID")
+	}
+}
+
+func TestListSecurityGroups_NoClient(t *testing.T) {
+	c := newTestClient(&mockCFN{}, &mockBackup{}, &mockRDS{})
+	if _, err := c.ListSecurityGroups(context.Background(), "vpc-1"); err == nil {
+		t.Error("expected error without an EC2 client")
+	}
+}
+
+func TestApplyRestoreOptions_SecurityGroups(t *testing.T) {
+	opts := RestoreOptions{SecurityGroupIDs: []string{"sg-3", "sg-4"}}
+
+	metadata := map[string]string{"VpcSecurityGroupIds": "sg-1,sg-2"}
+	applyRestoreOptions(metadata, "RDS", opts)
+	if metadata["VpcSecurityGroupIds"] != "sg-3,sg-4" {
+		t.Errorf("chosen groups should replace the live cluster's: %v", metadata)
+	}
+
+	metadata = map[string]string{}
+	applyRestoreOptions(metadata, "EFS", opts)
+	if len(metadata) != 0 {
+		t.Errorf("security groups do not apply to EFS: %v", metadata)
+	}
+
+	preview := RestoreMetadata{ResourceType: "RDS", SecurityGroups: "sg-1,sg-2"}
+	preview.ApplyOptions(opts)
+	if preview.SecurityGroups != "sg-3,sg-4" {
+		t.Errorf("preview should show the chosen groups: %+v", preview)
+	}
+	preview.ApplyOptions(RestoreOptions{})
+	if preview.SecurityGroups != "sg-1,sg-2" {
+		t.Errorf("clearing the choice should preview the live cluster's groups again: %+v", preview)
+	}
+}
+
+func TestListDBSubnetGroups_Simulated(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+
+	groups, err := c.ListDBSubnetGroups(context.Background(), "vpc-0sim0001")
+	if err != nil {
+		t.Fatalf("ListDBSubnetGroups: %v", err)
+	}
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	want := []string{"openemr-restore-isolated", "openemr-staging-single-az", "openemr-training-subnets"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("groups = %v, want %v", names, want)
+	}
+
+	if azs := groups[2].AZs(); len(azs) != 3 || groups[2].Validate("vpc-0sim0001") != nil {
+		t.Errorf("the stack's group covers 3 AZs and is valid, got %v", azs)
+	}
+	// The deleted subnet does not count towards coverage
+	if azs := groups[1].AZs(); len(azs) != 1 || groups[1].Validate("vpc-0sim0001") == nil {
+		t.Errorf("a group covering one AZ should be refused, got %v", azs)
+	}
+}
+
+func TestDBSubnetGroup_Validate(t *testing.T) {
+	g := DBSubnetGroup{Name: "g", VPCID: "vpc-1", Subnets: []Subnet{
+		{ID: "subnet-1", AZ: "us-west-2a", Status: "Active"},
+		{ID: "subnet-2", AZ: "us-west-2a", Status: "Active"},
+	}}
+	if err := g.Validate(""); err == nil {
+		t.Error("two subnets in one AZ should not be enough")
+	}
+	g.Subnets = append(g.Subnets, Subnet{ID: "subnet-3", AZ: "us-west-2b", Status: "Active"})
+	if err := g.Validate(""); err != nil {
+		t.Errorf("two AZs should be enough: %v", err)
+	}
+	if err := g.Validate("vpc-2"); err == nil {
+		t.Error("a group in another VPC should be refused")
+	}
+}
+
+func TestApplyRestoreOptions_SubnetGroup(t *testing.T) {
+	metadata := map[string]string{"DBSubnetGroupName": "live"}
+	applyRestoreOptions(metadata, "RDS", RestoreOptions{SubnetGroup: "isolated"})
+	if metadata["DBSubnetGroupName"] != "isolated" {
+		t.Errorf("chosen group should replace the live cluster's: %v", metadata)
+	}
+
+	preview := RestoreMetadata{ResourceType: "RDS", SubnetGroup: "live"}
+	preview.ApplyOptions(RestoreOptions{SubnetGroup: "isolated"})
+	if preview.SubnetGroup != "isolated" {
+		t.Errorf("preview should show the chosen group: %+v", preview)
+	}
+	preview.ApplyOptions(RestoreOptions{})
+	if preview.SubnetGroup != "live" {
+		t.Errorf("clearing the choice should preview the live cluster's group again: %+v", preview)
+	}
+}
@@ -0,0 +1,39 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements naming RDS restores to a new cluster: the restored
+// cluster is named after the live one with an operator's suffix, e.g.
+// openemr-db-verify, so the data can be checked alongside production
+// before cutting over.
+package aws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxClusterIDLength is the longest Aurora cluster identifier RDS accepts.
+const maxClusterIDLength = 63
+
+// RestoredClusterID returns the identifier of a cluster restored alongside
+// live with suffix, e.g. "openemr-db-verify" for live "openemr-db" and
+// suffix "verify". The suffix is lowercased, as RDS stores identifiers;
+// it may hold letters, digits, and single hyphens, and the identifier must
+// fit RDS's 63 characters.
+func RestoredClusterID(live, suffix string) (string, error) {
+	suffix = strings.ToLower(strings.TrimSpace(suffix))
+	switch {
+	case suffix == "":
+		return "", fmt.Errorf("enter a suffix for the new cluster's name, e.g. verify")
+	case strings.HasPrefix(suffix, "-") || strings.HasSuffix(suffix, "-") || strings.Contains(suffix, "--"):
+		return "", fmt.Errorf("invalid cluster suffix %q: hyphens cannot start, end, or repeat", suffix)
+	}
+	for _, r := range suffix {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return "", fmt.Errorf("invalid cluster suffix %q: use letters, digits, and hyphens", suffix)
+		}
+	}
+	id := strings.TrimRight(live, "-") + "-" + suffix
+	if len(id) > maxClusterIDLength {
+		return "", fmt.Errorf("cluster identifier %s is longer than %d characters: use a shorter suffix", id, maxClusterIDLength)
+	}
+	return id, nil
+}
@@ -0,0 +1,57 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRestoredClusterID(t *testing.T) {
+	tests := []struct {
+		live, suffix, want string
+	}{
+		{"openemr-db", "verify", "openemr-db-verify"},
+		{"openemr-db", " Verify-2026 ", "openemr-db-verify-2026"},
+		{"openemr-db-", "v1", "openemr-db-v1"},
+	}
+	for _, tt := range tests {
+		if got, err := RestoredClusterID(tt.live, tt.suffix); err != nil || got != tt.want {
+			t.Errorf("RestoredClusterID(%q, %q) = %q, %v; want %q", tt.live, tt.suffix, got, err, tt.want)
+		}
+	}
+
+	for _, suffix := range []string{"", "-verify", "verify-", "a--b", "v_1", "vérify", strings.Repeat("x", 60)} {
+		if _, err := RestoredClusterID("openemr-db", suffix); err == nil {
+			t.Errorf("RestoredClusterID(%q) should fail", suffix)
+		}
+	}
+}
+
+func TestApplyOptions_ClusterSuffix(t *testing.T) {
+	preview := RestoreMetadata{ResourceType: "RDS", ClusterID: "openemr-db"}
+	preview.ApplyOptions(RestoreOptions{ClusterSuffix: "verify"})
+	if preview.ClusterID != "openemr-db-verify" {
+		t.Errorf("preview should show the new cluster: %+v", preview)
+	}
+	preview.ApplyOptions(RestoreOptions{})
+	if preview.ClusterID != "openemr-db" {
+		t.Errorf("clearing the suffix should preview the live cluster again: %+v", preview)
+	}
+}
+
+func TestStartRestoreJob_ClusterSuffix(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	points, _ := c.ListRecoveryPoints(ctx, fx.Vaults[0], "RDS")
+	if len(points) == 0 {
+		t.Fatal("no RDS recovery points in the fixtures")
+	}
+
+	if _, err := c.StartRestoreJob(ctx, points[0], fx.Stacks[0].Name, fx.Vaults[0], RestoreOptions{ClusterSuffix: "verify"}); err != nil {
+		t.Fatalf("restore to a new cluster: %v", err)
+	}
+	if _, err := c.StartRestoreJob(ctx, points[0], fx.Stacks[0].Name, fx.Vaults[0], RestoreOptions{ClusterSuffix: "bad_suffix"}); err == nil {
+		t.Error("an invalid suffix should refuse the restore")
+	}
+}
@@ -0,0 +1,88 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements delivery of the cron subcommand's summary: as a
+// plain-text email through SES, or as a message to an SNS topic (which can
+// fan out to email, chat, or a pager).
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	sestypes "github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+// SNS limits on a message's subject and body.
+const (
+	snsMaxSubject = 100
+	snsMaxMessage = 256 * 1024
+)
+
+// SummaryMessage is a summary to deliver by email or SNS.
+type SummaryMessage struct {
+	Subject string
+	Body    string
+}
+
+// SendSummaryEmail emails msg as plain text from from (an SES-verified
+// identity) to each address in to.
+func (c *BackupClient) SendSummaryEmail(ctx context.Context, from string, to []string, msg SummaryMessage) error {
+	switch {
+	case from == "":
+		return fmt.Errorf("a sender address is required to send email")
+	case len(to) == 0:
+		return fmt.Errorf("at least one recipient is required to send email")
+	}
+	_, err := c.ses.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(from),
+		Destination:      &sestypes.Destination{ToAddresses: to},
+		Content: &sestypes.EmailContent{Simple: &sestypes.Message{
+			Subject: &sestypes.Content{Data: aws.String(msg.Subject), Charset: aws.String("UTF-8")},
+			Body:    &sestypes.Body{Text: &sestypes.Content{Data: aws.String(msg.Body), Charset: aws.String("UTF-8")}},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send summary email: %w", err)
+	}
+	return nil
+}
+
+// PublishSummary publishes msg to the SNS topic topicARN. SNS subjects are
+// limited to one line of 100 characters and messages to 256 KB, so longer
+// ones are truncated.
+func (c *BackupClient) PublishSummary(ctx context.Context, topicARN string, msg SummaryMessage) error {
+	if topicARN == "" {
+		return fmt.Errorf("topic ARN cannot be empty")
+	}
+	_, err := c.sns.Publish(ctx, &sns.PublishInput{
+		TopicArn: aws.String(topicARN),
+		Subject:  aws.String(snsSubject(msg.Subject)),
+		Message:  aws.String(truncate(msg.Body, snsMaxMessage)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish summary to %s: %w", topicARN, err)
+	}
+	return nil
+}
+
+// snsSubject makes subject a valid SNS subject: its first line, cut to
+// snsMaxSubject characters.
+func snsSubject(subject string) string {
+	subject, _, _ = strings.Cut(subject, "\n")
+	return truncate(strings.TrimSpace(subject), snsMaxSubject)
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
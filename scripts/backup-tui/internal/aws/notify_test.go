@@ -0,0 +1,67 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+type mockNotify struct {
+	emailInput   *sesv2.SendEmailInput
+	publishInput *sns.PublishInput
+}
+
+func (m *mockNotify) SendEmail(_ context.Context, in *sesv2.SendEmailInput, _ ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error) {
+	m.emailInput = in
+	return &sesv2.SendEmailOutput{}, nil
+}
+
+func (m *mockNotify) Publish(_ context.Context, in *sns.PublishInput, _ ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	m.publishInput = in
+	return &sns.PublishOutput{}, nil
+}
+
+func TestSendSummaryEmail(t *testing.T) {
+	m := &mockNotify{}
+	c := &BackupClient{ses: m}
+	msg := SummaryMessage{Subject: "backup-tui Stack: OK", Body: "# Backup Summary"}
+
+	if err := c.SendSummaryEmail(context.Background(), "ops@example.org", nil, msg); err == nil {
+		t.Error("email without recipients should be refused")
+	}
+	if err := c.SendSummaryEmail(context.Background(), "ops@example.org", []string{"a@example.org"}, msg); err != nil {
+		t.Fatal(err)
+	}
+	if got := aws.ToString(m.emailInput.Content.Simple.Subject.Data); got != msg.Subject {
+		t.Errorf("subject = %q", got)
+	}
+}
+
+func TestPublishSummary_TruncatesSubject(t *testing.T) {
+	m := &mockNotify{}
+	c := &BackupClient{sns: m}
+	subject := strings.Repeat("é", 60) + "\nsecond line"
+
+	if err := c.PublishSummary(context.Background(), "arn:aws:sns:us-west-2:1:ops", SummaryMessage{Subject: subject, Body: "body"}); err != nil {
+		t.Fatal(err)
+	}
+	got := aws.ToString(m.publishInput.Subject)
+	if len(got) > snsMaxSubject || strings.Contains(got, "\n") || got != strings.Repeat("é", 50) {
+		t.Errorf("subject should be one line of at most %d bytes, got %q", snsMaxSubject, got)
+	}
+}
+
+func TestSimulatedClient_SummaryIsNotDelivered(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	if err := c.PublishSummary(context.Background(), "arn:aws:sns:us-west-2:1:ops", SummaryMessage{Subject: "s", Body: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if sent := c.sns.(*simulatedAWS).sent; len(sent) != 1 || sent[0].Subject != "s" {
+		t.Errorf("simulated publish should be recorded, got %+v", sent)
+	}
+}
@@ -0,0 +1,151 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements on-demand backups of several resources at once, e.g.
+// before an upgrade or a DR drill: the backups run concurrently with a
+// bounded number of workers, every resulting recovery point gets the same
+// tags, and the outcome of each is collected for one summary.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// maxPollFailures is how many polls of a backup job in a row may fail
+// before it is given up on.
+const maxPollFailures = 5
+
+// OnDemandBackup is the outcome of an on-demand backup of one resource.
+type OnDemandBackup struct {
+	Resource ProtectedResource
+	JobID    string            // Empty if the job could not be started
+	Status   *RestoreJobStatus // Last polled status (nil until polled)
+	Err      error             // Why the job could not be started or followed
+}
+
+// Completed reports whether the backup completed.
+func (b OnDemandBackup) Completed() bool {
+	return b.Err == nil && b.Status != nil && b.Status.Status == "COMPLETED"
+}
+
+// OnDemandOptions configures RunOnDemandBackups.
+type OnDemandOptions struct {
+	VaultName    string
+	IAMRoleARN   string            // Role AWS Backup assumes to take the backups
+	Tags         map[string]string // Applied to every resulting recovery point
+	Workers      int               // Backups run at the same time (at least 1)
+	PollInterval time.Duration     // How often running jobs are polled
+
+	// OnUpdate, if set, is called from the worker goroutines whenever a
+	// backup starts or its status changes.
+	OnUpdate func(OnDemandBackup)
+}
+
+// StartOnDemandBackup starts a backup of resourceARN into vaultName, with
+// tags and the identity tags applied to the resulting recovery point, and
+// returns the job ID.
+func (c *BackupClient) StartOnDemandBackup(ctx context.Context, vaultName, roleARN, resourceARN string, tags map[string]string) (string, error) {
+	switch {
+	case vaultName == "":
+		return "", fmt.Errorf("vault name cannot be empty")
+	case roleARN == "":
+		return "", fmt.Errorf("an IAM role is required to start a backup")
+	}
+	result, err := c.client.StartBackupJob(ctx, &backup.StartBackupJobInput{
+		BackupVaultName:   aws.String(vaultName),
+		IamRoleArn:        aws.String(roleARN),
+		ResourceArn:       aws.String(resourceARN),
+		RecoveryPointTags: c.withIdentityTags(tags),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start backup of %s: %w", resourceName(resourceARN), err)
+	}
+	return aws.ToString(result.BackupJobId), nil
+}
+
+// RunOnDemandBackups backs up each of resources, at most opts.Workers at a
+// time, and waits for every started job to finish. It returns one outcome
+// per resource, in the order of resources. If ctx is cancelled, jobs not
+// yet started are not started and running jobs are left running in AWS;
+// their outcome has the last polled status and ctx's error.
+func (c *BackupClient) RunOnDemandBackups(ctx context.Context, resources []ProtectedResource, opts OnDemandOptions) []OnDemandBackup {
+	workers := max(opts.Workers, 1)
+	poll := opts.PollInterval
+	if poll <= 0 {
+		poll = 30 * time.Second
+	}
+	update := func(b OnDemandBackup) {
+		if opts.OnUpdate != nil {
+			opts.OnUpdate(b)
+		}
+	}
+
+	results := make([]OnDemandBackup, len(resources))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(resources)) {
+		wg.Go(func() {
+			for i := range next {
+				results[i] = c.runOnDemandBackup(ctx, resources[i], opts, poll, update)
+			}
+		})
+	}
+	for i := range resources {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			results[i] = OnDemandBackup{Resource: resources[i], Err: ctx.Err()}
+		}
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// runOnDemandBackup starts a backup of res and polls it until it finishes.
+func (c *BackupClient) runOnDemandBackup(ctx context.Context, res ProtectedResource, opts OnDemandOptions, poll time.Duration,
+	update func(OnDemandBackup)) OnDemandBackup {
+	b := OnDemandBackup{Resource: res}
+	b.JobID, b.Err = c.StartOnDemandBackup(ctx, opts.VaultName, opts.IAMRoleARN, res.ARN, opts.Tags)
+	update(b)
+	if b.Err != nil {
+		return b
+	}
+
+	failures := 0
+	for {
+		status, err := c.GetBackupJobStatus(ctx, b.JobID)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			b.Err = ctx.Err()
+			return b
+		case err != nil:
+			// Transient errors are retried on the next poll
+			if failures++; failures >= maxPollFailures {
+				b.Err = err
+				update(b)
+				return b
+			}
+		default:
+			failures = 0
+			if b.Status == nil || status.Status != b.Status.Status || status.PercentDone != b.Status.PercentDone {
+				b.Status = status
+				update(b)
+			}
+			if status.IsTerminal {
+				return b
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			b.Err = ctx.Err()
+			return b
+		case <-time.After(poll):
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunOnDemandBackups_Simulated(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	fx.Restore = FixtureRestore{DurationSeconds: 100, Outcome: "COMPLETED"}
+	c := NewSimulatedBackupClient(fx)
+	sim := c.client.(*simulatedAWS)
+	// Each call to now is a minute later, so jobs finish within a few polls
+	start := time.Now()
+	var calls atomic.Int64
+	sim.now = func() time.Time { return start.Add(time.Duration(calls.Add(1)) * time.Minute) }
+
+	resources, err := c.StackResources(context.Background(), fx.Stacks[0].Name)
+	if err != nil || len(resources) != 2 {
+		t.Fatalf("StackResources = %v, %v", resources, err)
+	}
+
+	var mu sync.Mutex
+	updates := 0
+	tags := map[string]string{"Reason": "pre-upgrade"}
+	results := c.RunOnDemandBackups(context.Background(), resources, OnDemandOptions{
+		VaultName:    fx.Vaults[0],
+		IAMRoleARN:   "arn:aws:iam::123456789012:role/backup",
+		Tags:         tags,
+		Workers:      2,
+		PollInterval: time.Millisecond,
+		OnUpdate: func(OnDemandBackup) {
+			mu.Lock()
+			updates++
+			mu.Unlock()
+		},
+	})
+
+	if len(results) != 2 {
+		t.Fatalf("expected one result per resource, got %d", len(results))
+	}
+	for i, r := range results {
+		if r.Resource != resources[i] {
+			t.Errorf("result %d is for %s, want %s", i, r.Resource.ARN, resources[i].ARN)
+		}
+		if !r.Completed() || r.Status.RecoveryPointARN == "" {
+			t.Errorf("backup of %s should complete with a recovery point: %+v %v", r.Resource.ARN, r.Status, r.Err)
+		}
+		if got := sim.backups[r.JobID].in.RecoveryPointTags["Reason"]; got != "pre-upgrade" {
+			t.Errorf("tags not propagated to %s, got %q", r.JobID, got)
+		}
+	}
+	if updates < 4 {
+		t.Errorf("expected start and status updates for each backup, got %d", updates)
+	}
+}
+
+func TestRunOnDemandBackups_StartFailure(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	resources, _ := c.StackResources(context.Background(), fx.Stacks[0].Name)
+
+	results := c.RunOnDemandBackups(context.Background(), resources, OnDemandOptions{VaultName: "no-such-vault", IAMRoleARN: "arn:role", Workers: 4})
+	for _, r := range results {
+		if r.Err == nil || r.JobID != "" || r.Completed() {
+			t.Errorf("backup into a missing vault should fail to start: %+v", r)
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, r := range c.RunOnDemandBackups(cancelled, resources, OnDemandOptions{VaultName: fx.Vaults[0], IAMRoleARN: "arn:role", Workers: 1}) {
+		if r.Completed() {
+			t.Errorf("no backup should run once the context is cancelled: %+v", r)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements backup plan and IAM role discovery for restores,
+// caching the vault → plan → role mapping so that repeated restores do not
+// re-walk every plan and selection in the account.
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// PlanRole describes the backup plan and IAM role resolved for a vault.
+// Restores use this role so they run with the same permissions as the
+// backups that produced the recovery points.
+type PlanRole struct {
+	VaultName     string         // Vault the resolution applies to
+	PlanID        string         // Backup plan ID (empty when Fallback is true)
+	PlanName      string         // Backup plan name (empty when Fallback is true)
+	PlanVersionID string         // Plan version the resolution was made against
+	SelectionName string         // Backup selection the role was taken from
+	RoleARN       string         // IAM role ARN used for restore jobs
+	Fallback      bool           // True when no plan targets the vault and the default service role is used
+	ViaCopy       bool           // True when the plan copies into the vault rather than backing up to it (e.g. air-gapped vaults)
+	Schedules     []PlanSchedule // When the plan's rules back up (or copy) to the vault
+	ResolvedAt    time.Time      // When the mapping was last resolved
+}
+
+// cachedPlan holds the parts of a backup plan needed for role resolution.
+// Rule targets are keyed by plan version, so unchanged plans are not
+// re-fetched on refresh. Roles come from backup selections, which do not
+// bump the plan version, so they are only re-read on an explicit refresh.
+type cachedPlan struct {
+	id            string
+	name          string
+	versionID     string
+	vaults        map[string]bool // Target vaults of the plan's rules
+	copyVaults    map[string]bool // Vaults the plan's rules copy to, by name
+	rules         []planRule
+	roleLoaded    bool // Whether selections have been read without error
+	roleARN       string
+	selectionName string
+}
+
+// planRule is when a plan's rule runs and the vaults it writes to.
+type planRule struct {
+	schedule   PlanSchedule
+	vault      string
+	copyVaults map[string]bool
+}
+
+// schedules returns the schedules of the rules that back up to vaultName,
+// or with viaCopy, that copy to it.
+func (p *cachedPlan) schedules(vaultName string, viaCopy bool) []PlanSchedule {
+	var schedules []PlanSchedule
+	for _, r := range p.rules {
+		if (!viaCopy && r.vault == vaultName) || (viaCopy && r.copyVaults[vaultName]) {
+			schedules = append(schedules, r.schedule)
+		}
+	}
+	return schedules
+}
+
+// planRoleCache caches backup plans and per-vault resolutions.
+// The zero value is ready to use.
+type planRoleCache struct {
+	mu         sync.Mutex
+	plans      map[string]*cachedPlan // Keyed by plan ID
+	unreadable map[string]error       // Plans whose details could not be read, by ID
+	vaults     map[string]*PlanRole   // Keyed by vault name
+	listed     bool                   // Whether the plan list has been loaded
+}
+
+// ResolvePlanRole returns the backup plan and IAM role used for restores
+// from the given vault.
+//
+// The first call lists all backup plans once (one GetBackupPlan per plan)
+// and caches which vaults each plan targets; selections are only read for
+// plans that target the requested vault. Subsequent calls are served from
+// the cache. Pass refresh=true to re-list plans: plans whose version ID is
+// unchanged keep their cached rules, while roles are always re-read.
+//
+// If no plan targets the vault, the account's default AWS Backup service
+// role is returned with Fallback set. It is not cached: the next call lists
+// the plans again, as one may target the vault by then. If a plan could not
+// be read, or the selections of one targeting the vault could not be
+// listed, the plan's role may be the right one, so an error is returned
+// rather than the default.
+func (c *BackupClient) ResolvePlanRole(ctx context.Context, vaultName string, refresh bool) (*PlanRole, error) {
+	if vaultName == "" {
+		return nil, fmt.Errorf("vault name cannot be empty")
+	}
+
+	pc := &c.planCache
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if !refresh {
+		if pr, ok := pc.vaults[vaultName]; ok {
+			cp := *pr
+			return &cp, nil
+		}
+	}
+
+	if refresh || !pc.listed {
+		if err := c.refreshPlansLocked(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	pr, err := c.resolveVaultLocked(ctx, vaultName)
+	if err != nil || pr.Fallback {
+		pc.listed = false
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pr.Fallback {
+		cp := *pr
+		return &cp, nil
+	}
+	if pc.vaults == nil {
+		pc.vaults = make(map[string]*PlanRole)
+	}
+	pc.vaults[vaultName] = pr
+	cp := *pr
+	return &cp, nil
+}
+
+// refreshPlansLocked re-lists backup plans, fetching details only for new
+// plans or plans whose version changed. Callers must hold planCache.mu.
+func (c *BackupClient) refreshPlansLocked(ctx context.Context) error {
+	pc := &c.planCache
+	previous := pc.plans
+	current := make(map[string]*cachedPlan)
+	unreadable := make(map[string]error)
+
+	paginator := backup.NewListBackupPlansPaginator(c.client, &backup.ListBackupPlansInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list backup plans: %w", err)
+		}
+
+		for _, plan := range page.BackupPlansList {
+			id := aws.ToString(plan.BackupPlanId)
+			version := aws.ToString(plan.VersionId)
+
+			if old, ok := previous[id]; ok && version != "" && old.versionID == version {
+				// Rules are unchanged; selections may not be, so re-read the role lazily.
+				old.roleLoaded = false
+				old.roleARN = ""
+				old.selectionName = ""
+				current[id] = old
+				continue
+			}
+
+			details, err := c.client.GetBackupPlan(ctx, &backup.GetBackupPlanInput{
+				BackupPlanId: plan.BackupPlanId,
+			})
+			if err != nil {
+				// It cannot be matched to a vault, but may target this one
+				unreadable[id] = fmt.Errorf("failed to get backup plan %s: %w", aws.ToString(plan.BackupPlanName), err)
+				continue
+			}
+			if details.BackupPlan == nil {
+				continue
+			}
+
+			cp := &cachedPlan{
+				id:         id,
+				name:       aws.ToString(plan.BackupPlanName),
+				versionID:  version,
+				vaults:     make(map[string]bool),
+				copyVaults: make(map[string]bool),
+			}
+			for _, rule := range details.BackupPlan.Rules {
+				r := planRule{
+					schedule: PlanSchedule{
+						RuleName:   aws.ToString(rule.RuleName),
+						Expression: aws.ToString(rule.ScheduleExpression),
+						Timezone:   aws.ToString(rule.ScheduleExpressionTimezone),
+					},
+					vault:      aws.ToString(rule.TargetBackupVaultName),
+					copyVaults: make(map[string]bool),
+				}
+				if r.vault != "" {
+					cp.vaults[r.vault] = true
+				}
+				for _, action := range rule.CopyActions {
+					if v := vaultNameFromARN(aws.ToString(action.DestinationBackupVaultArn)); v != "" {
+						cp.copyVaults[v] = true
+						r.copyVaults[v] = true
+					}
+				}
+				cp.rules = append(cp.rules, r)
+			}
+			current[id] = cp
+		}
+	}
+
+	pc.plans = current
+	pc.unreadable = unreadable
+	pc.vaults = nil
+	pc.listed = true
+	return nil
+}
+
+// resolveVaultLocked finds the first plan (by name, then ID) targeting the
+// vault that has a selection with an IAM role. No plan targets an
+// air-gapped vault, so plans whose rules copy into the vault are tried
+// next. Callers must hold planCache.mu.
+func (c *BackupClient) resolveVaultLocked(ctx context.Context, vaultName string) (*PlanRole, error) {
+	pc := &c.planCache
+	var errs []error
+
+	for _, viaCopy := range []bool{false, true} {
+		candidates := make([]*cachedPlan, 0)
+		for _, p := range pc.plans {
+			if (!viaCopy && p.vaults[vaultName]) || (viaCopy && p.copyVaults[vaultName]) {
+				candidates = append(candidates, p)
+			}
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].name != candidates[j].name {
+				return candidates[i].name < candidates[j].name
+			}
+			return candidates[i].id < candidates[j].id
+		})
+
+		for _, p := range candidates {
+			if !p.roleLoaded {
+				if err := c.loadPlanRole(ctx, p); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+			}
+			if p.roleARN != "" {
+				return &PlanRole{
+					VaultName:     vaultName,
+					PlanID:        p.id,
+					PlanName:      p.name,
+					PlanVersionID: p.versionID,
+					SelectionName: p.selectionName,
+					RoleARN:       p.roleARN,
+					ViaCopy:       viaCopy,
+					Schedules:     p.schedules(vaultName, viaCopy),
+					ResolvedAt:    time.Now(),
+				}, nil
+			}
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(pc.unreadable)) {
+		errs = append(errs, pc.unreadable[id])
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("cannot tell which backup plan's role restores from vault %s: %w", vaultName, errors.Join(errs...))
+	}
+
+	// Fallback to the default service role if no plan role was found.
+	// This should not happen in practice, but keeps restores possible.
+	return &PlanRole{
+		VaultName:  vaultName,
+		RoleARN:    fmt.Sprintf("arn:aws:iam::%s:role/service-role/AWSBackupDefaultServiceRole", c.accountID),
+		Fallback:   true,
+		ResolvedAt: time.Now(),
+	}, nil
+}
+
+// loadPlanRole reads a plan's backup selections and records the first IAM
+// role found. The plan is marked loaded only once they have been read, so
+// an error is retried by the next resolution.
+func (c *BackupClient) loadPlanRole(ctx context.Context, p *cachedPlan) error {
+	paginator := backup.NewListBackupSelectionsPaginator(c.client, &backup.ListBackupSelectionsInput{
+		BackupPlanId: aws.String(p.id),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list the backup selections of plan %s: %w", p.name, err)
+		}
+		for _, selection := range page.BackupSelectionsList {
+			if role := aws.ToString(selection.IamRoleArn); role != "" {
+				p.roleARN = role
+				p.selectionName = aws.ToString(selection.SelectionName)
+				p.roleLoaded = true
+				return nil
+			}
+		}
+	}
+	p.roleLoaded = true
+	return nil
+}
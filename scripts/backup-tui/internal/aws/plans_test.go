@@ -0,0 +1,371 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+)
+
+// testPlan describes a backup plan served by planMock.
+type testPlan struct {
+	id, name, version string
+	vaults            []string
+	copyTo            []string
+	role              string
+	schedule          string
+	getErr            error // Returned by GetBackupPlan
+	selectionsErr     error // Returned by ListBackupSelections
+}
+
+// planMock serves per-plan responses and counts calls so caching can be
+// verified. Other BackupAPI methods fall through to mockBackup.
+type planMock struct {
+	*mockBackup
+	plans          []testPlan
+	listPlansErr   error
+	listPlansCalls int
+	getPlanCalls   map[string]int
+	selectionCalls map[string]int
+}
+
+func newPlanMock(plans ...testPlan) *planMock {
+	return &planMock{
+		mockBackup:     &mockBackup{},
+		plans:          plans,
+		getPlanCalls:   make(map[string]int),
+		selectionCalls: make(map[string]int),
+	}
+}
+
+func (m *planMock) ListBackupPlans(_ context.Context, _ *backup.ListBackupPlansInput, _ ...func(*backup.Options)) (*backup.ListBackupPlansOutput, error) {
+	m.listPlansCalls++
+	if m.listPlansErr != nil {
+		return nil, m.listPlansErr
+	}
+	out := &backup.ListBackupPlansOutput{}
+	for _, p := range m.plans {
+		out.BackupPlansList = append(out.BackupPlansList, backuptypes.BackupPlansListMember{
+			BackupPlanId:   aws.String(p.id),
+			BackupPlanName: aws.String(p.name),
+			VersionId:      aws.String(p.version),
+		})
+	}
+	return out, nil
+}
+
+func (m *planMock) find(id string) *testPlan {
+	for i := range m.plans {
+		if m.plans[i].id == id {
+			return &m.plans[i]
+		}
+	}
+	return nil
+}
+
+func (m *planMock) GetBackupPlan(_ context.Context, in *backup.GetBackupPlanInput, _ ...func(*backup.Options)) (*backup.GetBackupPlanOutput, error) {
+	id := aws.ToString(in.BackupPlanId)
+	m.getPlanCalls[id]++
+	p := m.find(id)
+	if p == nil {
+		return nil, fmt.Errorf("plan %s not found", id)
+	}
+	if p.getErr != nil {
+		return nil, p.getErr
+	}
+	plan := &backuptypes.BackupPlan{BackupPlanName: aws.String(p.name)}
+	for _, v := range p.vaults {
+		rule := backuptypes.BackupRule{TargetBackupVaultName: aws.String(v), RuleName: aws.String("rule-" + v), ScheduleExpression: aws.String(p.schedule)}
+		for _, dest := range p.copyTo {
+			rule.CopyActions = append(rule.CopyActions, backuptypes.CopyAction{
+				DestinationBackupVaultArn: aws.String("arn:aws:backup:us-west-2:123456789012:backup-vault:" + dest),
+			})
+		}
+		plan.Rules = append(plan.Rules, rule)
+	}
+	return &backup.GetBackupPlanOutput{BackupPlan: plan, VersionId: aws.String(p.version)}, nil
+}
+
+func (m *planMock) ListBackupSelections(_ context.Context, in *backup.ListBackupSelectionsInput, _ ...func(*backup.Options)) (*backup.ListBackupSelectionsOutput, error) {
+	id := aws.ToString(in.BackupPlanId)
+	m.selectionCalls[id]++
+	out := &backup.ListBackupSelectionsOutput{}
+	if p := m.find(id); p != nil && p.selectionsErr != nil {
+		return nil, p.selectionsErr
+	}
+	if p := m.find(id); p != nil && p.role != "" {
+		out.BackupSelectionsList = []backuptypes.BackupSelectionsListMember{
+			{SelectionName: aws.String(p.name + "-selection"), IamRoleArn: aws.String(p.role)},
+		}
+	}
+	return out, nil
+}
+
+func newPlanTestClient(m *planMock) *BackupClient {
+	return &BackupClient{client: m, region: "us-west-2", accountID: "123456789012"}
+}
+
+func TestResolvePlanRole_FindsRoleFromMatchingPlan(t *testing.T) {
+	m := newPlanMock(
+		testPlan{id: "p-other", name: "other", version: "v1", vaults: []string{"other-vault"}, role: "arn:aws:iam::1:role/other"},
+		testPlan{id: "p-main", name: "main", version: "v1", vaults: []string{"my-vault"}, role: "arn:aws:iam::1:role/backup"},
+	)
+	c := newPlanTestClient(m)
+
+	pr, err := c.ResolvePlanRole(context.Background(), "my-vault", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.RoleARN != "arn:aws:iam::1:role/backup" {
+		t.Errorf("RoleARN = %q", pr.RoleARN)
+	}
+	if pr.PlanID != "p-main" || pr.PlanName != "main" || pr.PlanVersionID != "v1" {
+		t.Errorf("unexpected plan: %+v", pr)
+	}
+	if pr.SelectionName != "main-selection" {
+		t.Errorf("SelectionName = %q", pr.SelectionName)
+	}
+	if pr.Fallback {
+		t.Error("Fallback should be false when a plan role is found")
+	}
+	if m.selectionCalls["p-other"] != 0 {
+		t.Error("selections should only be read for plans targeting the vault")
+	}
+}
+
+func TestResolvePlanRole_AirGappedVaultUsesCopyingPlan(t *testing.T) {
+	m := newPlanMock(
+		testPlan{id: "p-main", name: "main", version: "v1", vaults: []string{"my-vault"}, copyTo: []string{"lag-vault"}, role: "arn:aws:iam::1:role/backup"},
+	)
+	c := newPlanTestClient(m)
+
+	pr, err := c.ResolvePlanRole(context.Background(), "lag-vault", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Fallback || !pr.ViaCopy || pr.PlanID != "p-main" || pr.RoleARN != "arn:aws:iam::1:role/backup" {
+		t.Errorf("expected the role of the plan copying into the vault, got %+v", pr)
+	}
+
+	pr, _ = c.ResolvePlanRole(context.Background(), "my-vault", false)
+	if pr.ViaCopy {
+		t.Error("a plan targeting the vault directly is not a copy")
+	}
+}
+
+func TestResolvePlanRole_Schedules(t *testing.T) {
+	m := newPlanMock(
+		testPlan{id: "p-main", name: "main", version: "v1", vaults: []string{"my-vault", "other-vault"}, copyTo: []string{"lag-vault"},
+			role: "arn:aws:iam::1:role/backup", schedule: "cron(0 5 ? * * *)"},
+	)
+	c := newPlanTestClient(m)
+
+	for _, vault := range []string{"my-vault", "lag-vault"} {
+		pr, err := c.ResolvePlanRole(context.Background(), vault, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := 1
+		if vault == "lag-vault" {
+			want = 2 // Both rules copy to it
+		}
+		if len(pr.Schedules) != want || pr.Schedules[0].Expression != "cron(0 5 ? * * *)" {
+			t.Errorf("%s: expected %d schedule(s) of the plan's rules, got %+v", vault, want, pr.Schedules)
+		}
+	}
+	pr, _ := c.ResolvePlanRole(context.Background(), "my-vault", false)
+	if pr.Schedules[0].RuleName != "rule-my-vault" {
+		t.Errorf("only the rule targeting the vault should be listed, got %+v", pr.Schedules)
+	}
+}
+
+func TestResolvePlanRole_CachedAcrossCalls(t *testing.T) {
+	m := newPlanMock(testPlan{id: "p1", name: "plan", version: "v1", vaults: []string{"my-vault"}, role: "arn:role"})
+	c := newPlanTestClient(m)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.ResolvePlanRole(context.Background(), "my-vault", false); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if m.listPlansCalls != 1 || m.getPlanCalls["p1"] != 1 || m.selectionCalls["p1"] != 1 {
+		t.Errorf("expected one call each, got list=%d get=%d selections=%d",
+			m.listPlansCalls, m.getPlanCalls["p1"], m.selectionCalls["p1"])
+	}
+}
+
+func TestResolvePlanRole_RefreshSkipsUnchangedPlans(t *testing.T) {
+	m := newPlanMock(testPlan{id: "p1", name: "plan", version: "v1", vaults: []string{"my-vault"}, role: "arn:role-a"})
+	c := newPlanTestClient(m)
+
+	if _, err := c.ResolvePlanRole(context.Background(), "my-vault", false); err != nil {
+		t.Fatal(err)
+	}
+	m.plans[0].role = "arn:role-b"
+
+	pr, err := c.ResolvePlanRole(context.Background(), "my-vault", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.getPlanCalls["p1"] != 1 {
+		t.Errorf("unchanged plan version should not be re-fetched, GetBackupPlan calls = %d", m.getPlanCalls["p1"])
+	}
+	if pr.RoleARN != "arn:role-b" {
+		t.Errorf("refresh should re-read selections, got role %q", pr.RoleARN)
+	}
+}
+
+func TestResolvePlanRole_RefreshRefetchesChangedPlans(t *testing.T) {
+	m := newPlanMock(testPlan{id: "p1", name: "plan", version: "v1", vaults: []string{"old-vault"}, role: "arn:role"})
+	c := newPlanTestClient(m)
+
+	pr, err := c.ResolvePlanRole(context.Background(), "new-vault", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pr.Fallback {
+		t.Fatal("vault not targeted by any plan should fall back")
+	}
+
+	m.plans[0].version = "v2"
+	m.plans[0].vaults = []string{"new-vault"}
+
+	pr, err = c.ResolvePlanRole(context.Background(), "new-vault", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.getPlanCalls["p1"] != 2 {
+		t.Errorf("changed plan version should be re-fetched, calls = %d", m.getPlanCalls["p1"])
+	}
+	if pr.Fallback || pr.RoleARN != "arn:role" {
+		t.Errorf("expected plan role after refresh, got %+v", pr)
+	}
+}
+
+func TestResolvePlanRole_FallbackIsNotCached(t *testing.T) {
+	m := newPlanMock()
+	c := newPlanTestClient(m)
+
+	pr, err := c.ResolvePlanRole(context.Background(), "my-vault", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "arn:aws:iam::123456789012:role/service-role/AWSBackupDefaultServiceRole"
+	if !pr.Fallback || pr.RoleARN != want {
+		t.Errorf("expected fallback role, got %+v", pr)
+	}
+
+	// A plan created since is found on the next call
+	m.plans = append(m.plans, testPlan{id: "p1", name: "daily", version: "v1", vaults: []string{"my-vault"}, role: "arn:role-1"})
+	pr, err = c.ResolvePlanRole(context.Background(), "my-vault", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Fallback || pr.RoleARN != "arn:role-1" {
+		t.Errorf("expected the new plan's role, got %+v", pr)
+	}
+	if m.listPlansCalls != 2 {
+		t.Errorf("a fallback should not be cached, ListBackupPlans calls = %d", m.listPlansCalls)
+	}
+}
+
+func TestResolvePlanRole_SelectionErrorIsRetried(t *testing.T) {
+	m := newPlanMock(testPlan{id: "p1", name: "daily", version: "v1", vaults: []string{"my-vault"}, role: "arn:role-1",
+		selectionsErr: fmt.Errorf("throttled")})
+	c := newPlanTestClient(m)
+
+	if pr, err := c.ResolvePlanRole(context.Background(), "my-vault", false); err == nil {
+		t.Fatalf("unreadable selections should be an error rather than the default role, got %+v", pr)
+	}
+	m.plans[0].selectionsErr = nil
+	pr, err := c.ResolvePlanRole(context.Background(), "my-vault", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.RoleARN != "arn:role-1" {
+		t.Errorf("selections should be read again after an error, got %+v", pr)
+	}
+}
+
+func TestResolvePlanRole_UnreadablePlan(t *testing.T) {
+	m := newPlanMock(testPlan{id: "p1", name: "daily", version: "v1", vaults: []string{"my-vault"}, role: "arn:role-1",
+		getErr: fmt.Errorf("access denied")})
+	c := newPlanTestClient(m)
+
+	if pr, err := c.ResolvePlanRole(context.Background(), "my-vault", false); err == nil {
+		t.Fatalf("an unreadable plan should be an error rather than the default role, got %+v", pr)
+	}
+	m.plans[0].getErr = nil
+	pr, err := c.ResolvePlanRole(context.Background(), "my-vault", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.RoleARN != "arn:role-1" {
+		t.Errorf("the plan should be read again after an error, got %+v", pr)
+	}
+}
+
+func TestResolvePlanRole_DeterministicPlanOrder(t *testing.T) {
+	m := newPlanMock(
+		testPlan{id: "p-z", name: "zeta", version: "v1", vaults: []string{"my-vault"}, role: "arn:role-z"},
+		testPlan{id: "p-a", name: "alpha", version: "v1", vaults: []string{"my-vault"}, role: "arn:role-a"},
+	)
+	c := newPlanTestClient(m)
+
+	pr, err := c.ResolvePlanRole(context.Background(), "my-vault", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.PlanName != "alpha" {
+		t.Errorf("plans should be considered in name order, got %q", pr.PlanName)
+	}
+}
+
+func TestResolvePlanRole_SkipsPlansWithoutRole(t *testing.T) {
+	m := newPlanMock(
+		testPlan{id: "p-a", name: "alpha", version: "v1", vaults: []string{"my-vault"}},
+		testPlan{id: "p-b", name: "beta", version: "v1", vaults: []string{"my-vault"}, role: "arn:role-b"},
+	)
+	c := newPlanTestClient(m)
+
+	pr, err := c.ResolvePlanRole(context.Background(), "my-vault", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.RoleARN != "arn:role-b" {
+		t.Errorf("expected role from plan with a selection, got %q", pr.RoleARN)
+	}
+}
+
+func TestResolvePlanRole_ListError(t *testing.T) {
+	m := newPlanMock()
+	m.listPlansErr = fmt.Errorf("access denied")
+	c := newPlanTestClient(m)
+
+	if _, err := c.ResolvePlanRole(context.Background(), "my-vault", false); err == nil {
+		t.Fatal("expected error when plans cannot be listed")
+	}
+}
+
+func TestResolvePlanRole_EmptyVault(t *testing.T) {
+	c := newPlanTestClient(newPlanMock())
+	if _, err := c.ResolvePlanRole(context.Background(), "", false); err == nil {
+		t.Fatal("expected error for empty vault name")
+	}
+}
+
+func TestResolvePlanRole_ReturnsCopy(t *testing.T) {
+	m := newPlanMock(testPlan{id: "p1", name: "plan", version: "v1", vaults: []string{"my-vault"}, role: "arn:role"})
+	c := newPlanTestClient(m)
+
+	pr, _ := c.ResolvePlanRole(context.Background(), "my-vault", false)
+	pr.RoleARN = "mutated"
+
+	again, _ := c.ResolvePlanRole(context.Background(), "my-vault", false)
+	if again.RoleARN != "arn:role" {
+		t.Error("callers must not be able to mutate the cached resolution")
+	}
+}
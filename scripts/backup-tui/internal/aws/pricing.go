@@ -0,0 +1,143 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the cost estimate shown before an RDS restore: the
+// hourly and monthly cost of the Aurora instances or Serverless v2 capacity
+// the restored cluster runs, and of its storage, from a static table of
+// on-demand list prices.
+package aws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Aurora MySQL on-demand list prices in us-east-1, in USD. Other regions
+// differ, typically by up to a fifth.
+const (
+	PricingRegion = "us-east-1"
+
+	hoursPerMonth        = 730
+	serverlessACUHour    = 0.12  // Per ACU-hour, Aurora Standard
+	storageGiBMonth      = 0.10  // Per GiB-month, Aurora Standard
+	storageIOOptGiBMonth = 0.225 // Per GiB-month, Aurora I/O-Optimized
+	ioOptimizedPremium   = 1.3   // I/O-Optimized instances and ACUs cost 30% more
+)
+
+// instanceHourly are hourly prices of provisioned instance classes.
+var instanceHourly = map[string]float64{
+	"db.t3.medium":   0.082,
+	"db.t3.large":    0.164,
+	"db.t4g.medium":  0.073,
+	"db.t4g.large":   0.146,
+	"db.r5.large":    0.29,
+	"db.r5.xlarge":   0.58,
+	"db.r5.2xlarge":  1.16,
+	"db.r5.4xlarge":  2.32,
+	"db.r6g.large":   0.26,
+	"db.r6g.xlarge":  0.519,
+	"db.r6g.2xlarge": 1.038,
+	"db.r6g.4xlarge": 2.076,
+	"db.r6i.large":   0.29,
+	"db.r6i.xlarge":  0.58,
+	"db.r6i.2xlarge": 1.16,
+	"db.r6i.4xlarge": 2.32,
+	"db.r7g.large":   0.276,
+	"db.r7g.xlarge":  0.552,
+	"db.r7g.2xlarge": 1.104,
+	"db.r7g.4xlarge": 2.208,
+}
+
+// CostLine is the estimated compute cost of one instance. Minimum and
+// maximum differ for Serverless v2, which scales within its ACU range.
+type CostLine struct {
+	Instance  string
+	Class     string
+	HourlyMin float64
+	HourlyMax float64
+}
+
+// Priced reports whether the instance's class has a known price.
+func (l CostLine) Priced() bool {
+	return l.HourlyMax > 0 || l.Class == "db.serverless"
+}
+
+// CostEstimate is the estimated cost of running a restored cluster.
+type CostEstimate struct {
+	Instances      []CostLine
+	StorageMonthly float64 // Zero when the backup's size is unknown
+	ServerlessMin  float64 // Serverless v2 scaling range, zero when not configured
+	ServerlessMax  float64
+}
+
+// EstimateRestoreCost estimates the cost of a cluster restored from a backup
+// of backupBytes with the instances and storage configuration of h, the
+// live cluster it mirrors.
+func EstimateRestoreCost(h *ClusterHealth, backupBytes int64) CostEstimate {
+	ioOptimized := h.StorageType == "aurora-iopt1"
+	premium := 1.0
+	if ioOptimized {
+		premium = ioOptimizedPremium
+	}
+	e := CostEstimate{ServerlessMin: h.ServerlessMinACU, ServerlessMax: h.ServerlessMaxACU}
+	for _, inst := range h.Instances {
+		line := CostLine{Instance: inst.ID, Class: inst.Class}
+		if inst.Class == "db.serverless" {
+			line.HourlyMin = h.ServerlessMinACU * serverlessACUHour * premium
+			line.HourlyMax = h.ServerlessMaxACU * serverlessACUHour * premium
+		} else if price, ok := instanceHourly[inst.Class]; ok {
+			line.HourlyMin = price * premium
+			line.HourlyMax = line.HourlyMin
+		}
+		e.Instances = append(e.Instances, line)
+	}
+	gib := float64(backupBytes) / (1 << 30)
+	if ioOptimized {
+		e.StorageMonthly = gib * storageIOOptGiBMonth
+	} else {
+		e.StorageMonthly = gib * storageGiBMonth
+	}
+	return e
+}
+
+// Hourly returns the estimated compute cost per hour of the priced instances.
+func (e CostEstimate) Hourly() (lo, hi float64) {
+	for _, l := range e.Instances {
+		lo += l.HourlyMin
+		hi += l.HourlyMax
+	}
+	return lo, hi
+}
+
+// Monthly returns the estimated cost per month, compute and storage.
+func (e CostEstimate) Monthly() (lo, hi float64) {
+	lo, hi = e.Hourly()
+	return lo*hoursPerMonth + e.StorageMonthly, hi*hoursPerMonth + e.StorageMonthly
+}
+
+// Unpriced returns the instance classes without a known price.
+func (e CostEstimate) Unpriced() []string {
+	var classes []string
+	for _, l := range e.Instances {
+		if !l.Priced() {
+			classes = append(classes, l.Class)
+		}
+	}
+	return classes
+}
+
+// FormatCostRange formats a cost, or a range when lo and hi differ, e.g.
+// "$0.06-$1.92".
+func FormatCostRange(lo, hi float64) string {
+	if fmt.Sprintf("%.2f", lo) == fmt.Sprintf("%.2f", hi) {
+		return fmt.Sprintf("$%.2f", lo)
+	}
+	return fmt.Sprintf("$%.2f-$%.2f", lo, hi)
+}
+
+// PricingNote qualifies the estimate for region.
+func PricingNote(region string) string {
+	note := "on-demand list prices"
+	if !strings.EqualFold(region, PricingRegion) {
+		note += " in " + PricingRegion + "; " + region + " may differ"
+	}
+	return note
+}
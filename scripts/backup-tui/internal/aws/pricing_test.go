@@ -0,0 +1,45 @@
+package aws
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEstimateRestoreCost(t *testing.T) {
+	near := func(a, b float64) bool { return math.Abs(a-b) < 0.001 }
+
+	serverless := EstimateRestoreCost(&ClusterHealth{
+		StorageType: "aurora", ServerlessMinACU: 0.5, ServerlessMaxACU: 16,
+		Instances: []ClusterInstance{{ID: "w", Class: "db.serverless"}, {ID: "r", Class: "db.serverless"}},
+	}, 100<<30)
+	if lo, hi := serverless.Hourly(); !near(lo, 0.12) || !near(hi, 3.84) {
+		t.Errorf("serverless hourly = %v-%v, want 0.12-3.84", lo, hi)
+	}
+	if !near(serverless.StorageMonthly, 10) {
+		t.Errorf("100 GiB of standard storage = %v/month, want 10", serverless.StorageMonthly)
+	}
+	if lo, _ := serverless.Monthly(); !near(lo, 0.12*730+10) {
+		t.Errorf("monthly minimum = %v", lo)
+	}
+
+	provisioned := EstimateRestoreCost(&ClusterHealth{
+		StorageType: "aurora-iopt1",
+		Instances:   []ClusterInstance{{ID: "w", Class: "db.r6g.large"}, {ID: "r", Class: "db.x9.huge"}},
+	}, 0)
+	if lo, hi := provisioned.Hourly(); !near(lo, 0.26*1.3) || lo != hi {
+		t.Errorf("I/O-Optimized r6g.large = %v-%v, want %v", lo, hi, 0.26*1.3)
+	}
+	if got := provisioned.Unpriced(); !slices.Equal(got, []string{"db.x9.huge"}) {
+		t.Errorf("unpriced = %v", got)
+	}
+}
+
+func TestFormatCostRange(t *testing.T) {
+	if got := FormatCostRange(0.12, 3.84); got != "$0.12-$3.84" {
+		t.Errorf("range = %q", got)
+	}
+	if got := FormatCostRange(0.26, 0.2600001); got != "$0.26" {
+		t.Errorf("equal = %q", got)
+	}
+}
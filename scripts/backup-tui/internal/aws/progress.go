@@ -0,0 +1,25 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements progress reporting for multi-step operations such
+// as starting a restore, which resolves the stack's cluster, network, and
+// IAM role before the restore job itself is requested.
+package aws
+
+import "context"
+
+// ProgressFunc receives a short description of the step an operation is
+// about to perform, e.g. "Resolving IAM role…".
+type ProgressFunc func(step string)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose operations report their steps to fn.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress reports step to the context's ProgressFunc, if any.
+func reportProgress(ctx context.Context, step string) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(step)
+	}
+}
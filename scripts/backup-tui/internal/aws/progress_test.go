@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestStartRestoreJob_ReportsProgress(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	points, _ := c.ListRecoveryPoints(context.Background(), fx.Vaults[0], "RDS")
+	if len(points) == 0 {
+		t.Fatal("no RDS recovery points in the fixtures")
+	}
+
+	var steps []string
+	ctx := WithProgress(context.Background(), func(step string) { steps = append(steps, step) })
+	if _, err := c.StartRestoreJob(ctx, points[0], fx.Stacks[0].Name, fx.Vaults[0], RestoreOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Resolving IAM role…", "Resolving cluster…", "Fetching subnet group and security groups…", "Starting restore job…"}
+	if !slices.Equal(steps, want) {
+		t.Errorf("steps = %q, want %q", steps, want)
+	}
+
+	// Without a ProgressFunc nothing is reported, and nothing breaks
+	if _, err := c.StartRestoreJob(context.Background(), points[0], fx.Stacks[0].Name, fx.Vaults[0], RestoreOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,50 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements deleting recovery points: the on-demand backups the
+// tool created, for "backup-tui prune", as they are outside any backup plan
+// and no plan lifecycle ever deletes them, and backups deleted from the TUI.
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// BatchTagKey is the recovery point tag "backup-tui backup" groups the
+// backups of one run with. Backups taken before the identity tags were
+// stamped carry only this tag.
+const BatchTagKey = "backup-tui:batch"
+
+// ToolCreated reports whether the recovery point is an on-demand backup the
+// tool took, by its identity or batch tag.
+func (d RecoveryPointDetails) ToolCreated() bool {
+	if !d.OnDemand() {
+		return false
+	}
+	_, batch := d.Tags[BatchTagKey]
+	return d.Tags[CreatedViaTagKey] == CreatedVia || batch
+}
+
+// DeleteRecoveryPoint deletes the recovery point with the given ARN from
+// vaultName. AWS Backup refuses recovery points under a legal hold or
+// within a locked vault's minimum retention.
+func (c *BackupClient) DeleteRecoveryPoint(ctx context.Context, vaultName, arn string) error {
+	_, err := c.client.DeleteRecoveryPoint(ctx, &backup.DeleteRecoveryPointInput{
+		BackupVaultName:  aws.String(vaultName),
+		RecoveryPointArn: aws.String(arn),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery point %s: %w", arn, err)
+	}
+	return nil
+}
+
+// DeletionRefused reports whether err is AWS Backup refusing to delete a
+// recovery point, as it does with an InvalidRequestException within a locked
+// vault's minimum retention, under a legal hold, or in an air-gapped vault
+// before its retention ends, rather than failing to.
+func DeletionRefused(err error) bool {
+	return err != nil && DescribeError(err).Code == "InvalidRequestException"
+}
@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDeleteRecoveryPoint_VaultLockRefusal(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	vault := fx.Vaults[0]
+	fx.VaultLocks = map[string]FixtureVaultLock{vault: {MinRetentionDays: 36500}}
+	c := NewSimulatedBackupClient(fx)
+	arn := fx.RecoveryPoints[vault][0].RecoveryPointARN
+
+	err := c.DeleteRecoveryPoint(context.Background(), vault, arn)
+	if err == nil || !DeletionRefused(err) {
+		t.Fatalf("a locked vault should refuse the deletion, got %v", err)
+	}
+	if DeletionRefused(errors.New("connection reset")) || DeletionRefused(nil) {
+		t.Error("only AWS Backup's refusals count")
+	}
+
+	delete(fx.VaultLocks, vault)
+	if err := c.DeleteRecoveryPoint(context.Background(), vault, arn); err != nil {
+		t.Fatalf("unlocked deletion: %v", err)
+	}
+	if _, err := c.DescribeRecoveryPointDetails(context.Background(), vault, arn); err == nil {
+		t.Error("the deleted recovery point should be gone")
+	}
+}
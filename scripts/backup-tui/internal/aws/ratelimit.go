@@ -0,0 +1,246 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements a client-side token bucket rate limiter per service
+// and region, attached to every SDK client through the middleware stack.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// ServiceLimit describes the sustained request rate and burst size allowed
+// for a single AWS service.
+type ServiceLimit struct {
+	Rate  float64 // Sustained requests per second
+	Burst int     // Maximum number of requests that may be issued back-to-back
+}
+
+// defaultServiceLimits holds conservative per-service limits. AWS Backup has
+// comparatively low control-plane quotas, so it gets the tightest bucket;
+// listing large vaults and walking every backup plan can otherwise exhaust
+// the account-wide quota during an incident.
+//
+// Keys are SDK service IDs as reported by awsmiddleware.GetServiceID. AWS
+// applies its quotas per region, so each region has its own bucket.
+var defaultServiceLimits = map[string]ServiceLimit{
+	"Backup":         {Rate: 4, Burst: 8},
+	"CloudFormation": {Rate: 5, Burst: 10},
+	"CloudTrail":     {Rate: 2, Burst: 2}, // LookupEvents is limited to 2 calls per second
+	"DynamoDB":       {Rate: 10, Burst: 20},
+	"EC2":            {Rate: 10, Burst: 20},
+	"ECS":            {Rate: 5, Burst: 10},
+	"EFS":            {Rate: 5, Burst: 10},
+	"KMS":            {Rate: 5, Burst: 10},
+	"RDS":            {Rate: 5, Burst: 10},
+	"RDS Data":       {Rate: 5, Burst: 10},
+	"SESv2":          {Rate: 1, Burst: 5},
+	"SNS":            {Rate: 5, Burst: 10},
+	"STS":            {Rate: 10, Burst: 10},
+}
+
+// fallbackServiceLimit applies to services without an explicit entry.
+var fallbackServiceLimit = ServiceLimit{Rate: 10, Burst: 20}
+
+// Throttle penalty bounds. After a throttling error the service's bucket is
+// drained and paused for the current penalty, which doubles on consecutive
+// throttles and resets after the next successful call.
+const (
+	minThrottlePenalty = 500 * time.Millisecond
+	maxThrottlePenalty = 8 * time.Second
+)
+
+// tokenBucket is a classic token bucket: tokens refill continuously at rate
+// per second up to burst, and each request consumes one token.
+type tokenBucket struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time     // No tokens are handed out before this instant
+	penalty     time.Duration // Next pause to apply on throttle
+	now         func() time.Time
+}
+
+func newTokenBucket(limit ServiceLimit) *tokenBucket {
+	if limit.Rate <= 0 {
+		limit.Rate = fallbackServiceLimit.Rate
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+	b := &tokenBucket{
+		rate:    limit.Rate,
+		burst:   float64(limit.Burst),
+		tokens:  float64(limit.Burst),
+		penalty: minThrottlePenalty,
+		now:     time.Now,
+	}
+	b.last = b.now()
+	return b
+}
+
+// reserve takes a token if one is available and otherwise returns how long
+// the caller must wait before trying again.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+	b.tokens = min(b.burst, b.tokens+elapsed*b.rate)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	missing := 1 - b.tokens
+	return time.Duration(missing / b.rate * float64(time.Second))
+}
+
+// Wait blocks until a token is available or the context is cancelled.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	for {
+		delay := b.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// throttled drains the bucket and pauses it for the current penalty.
+func (b *tokenBucket) throttled() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens = 0
+	b.last = now
+	b.pausedUntil = now.Add(b.penalty)
+	b.penalty = min(b.penalty*2, maxThrottlePenalty)
+}
+
+// succeeded resets the throttle penalty after a successful call.
+func (b *tokenBucket) succeeded() {
+	b.mu.Lock()
+	b.penalty = minThrottlePenalty
+	b.mu.Unlock()
+}
+
+// RateLimiter maintains one token bucket per AWS service and region. It is
+// safe for concurrent use and is shared by all clients created from the same
+// config.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limits  map[string]ServiceLimit
+	buckets map[bucketKey]*tokenBucket
+}
+
+// bucketKey identifies a token bucket. Region is "" for requests without
+// one.
+type bucketKey struct {
+	service string
+	region  string
+}
+
+// NewRateLimiter creates a RateLimiter. Entries in limits override the
+// defaults for the corresponding service; pass nil to use the defaults.
+func NewRateLimiter(limits map[string]ServiceLimit) *RateLimiter {
+	merged := make(map[string]ServiceLimit, len(defaultServiceLimits)+len(limits))
+	for svc, l := range defaultServiceLimits {
+		merged[svc] = l
+	}
+	for svc, l := range limits {
+		merged[svc] = l
+	}
+	return &RateLimiter{
+		limits:  merged,
+		buckets: make(map[bucketKey]*tokenBucket),
+	}
+}
+
+// bucket returns the token bucket for a service in a region, creating it
+// on first use.
+func (r *RateLimiter) bucket(service, region string) *tokenBucket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := bucketKey{service: service, region: region}
+	b, ok := r.buckets[key]
+	if !ok {
+		limit, found := r.limits[service]
+		if !found {
+			limit = fallbackServiceLimit
+		}
+		b = newTokenBucket(limit)
+		r.buckets[key] = b
+	}
+	return b
+}
+
+// Wait blocks until a request to the given service in region is allowed.
+func (r *RateLimiter) Wait(ctx context.Context, service, region string) error {
+	return r.bucket(service, region).Wait(ctx)
+}
+
+// isThrottle reports whether err is an AWS throttling error
+// (ThrottlingException, TooManyRequestsException, etc.).
+func isThrottle(err error) bool {
+	return retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary
+}
+
+// rateLimitMiddlewareID identifies the limiter in the SDK middleware stack.
+const rateLimitMiddlewareID = "BackupTUIRateLimit"
+
+// AddToStack registers the limiter on an SDK middleware stack. It is added
+// to the finalize step after the retry middleware so that every attempt,
+// including retries, consumes a token.
+func (r *RateLimiter) AddToStack(stack *middleware.Stack) error {
+	mw := middleware.FinalizeMiddlewareFunc(rateLimitMiddlewareID, func(
+		ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+	) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		service, region := awsmiddleware.GetServiceID(ctx), awsmiddleware.GetRegion(ctx)
+		b := r.bucket(service, region)
+		if err := b.Wait(ctx); err != nil {
+			return middleware.FinalizeOutput{}, middleware.Metadata{},
+				fmt.Errorf("rate limiter wait for %s in %s: %w", service, region, err)
+		}
+
+		out, md, err := next.HandleFinalize(ctx, in)
+		switch {
+		case err == nil:
+			b.succeeded()
+		case isThrottle(err):
+			b.throttled()
+		}
+		return out, md, err
+	})
+
+	if _, ok := stack.Finalize.Get("Retry"); ok {
+		return stack.Finalize.Insert(mw, "Retry", middleware.After)
+	}
+	return stack.Finalize.Add(mw, middleware.After)
+}
+
+// defaultRateLimiter is shared by every client created via loadAWSConfig so
+// that all BackupClient instances in the process draw from the same quota.
+var defaultRateLimiter = NewRateLimiter(nil)
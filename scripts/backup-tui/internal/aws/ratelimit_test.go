@@ -0,0 +1,233 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// fakeClock is a manually advanced clock for token bucket tests.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBucket(limit ServiceLimit) (*tokenBucket, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := newTokenBucket(limit)
+	b.now = clock.now
+	b.last = clock.now()
+	return b, clock
+}
+
+func TestTokenBucket_BurstThenWait(t *testing.T) {
+	b, _ := newTestBucket(ServiceLimit{Rate: 2, Burst: 3})
+
+	for i := 0; i < 3; i++ {
+		if d := b.reserve(); d != 0 {
+			t.Fatalf("request %d within burst should not wait, got %v", i, d)
+		}
+	}
+	if d := b.reserve(); d != 500*time.Millisecond {
+		t.Errorf("request beyond burst should wait 1/rate, got %v", d)
+	}
+}
+
+func TestTokenBucket_Refill(t *testing.T) {
+	b, clock := newTestBucket(ServiceLimit{Rate: 2, Burst: 1})
+
+	if d := b.reserve(); d != 0 {
+		t.Fatalf("first request should not wait, got %v", d)
+	}
+	clock.advance(500 * time.Millisecond)
+	if d := b.reserve(); d != 0 {
+		t.Errorf("token should have refilled after 1/rate, got wait %v", d)
+	}
+}
+
+func TestTokenBucket_RefillCappedAtBurst(t *testing.T) {
+	b, clock := newTestBucket(ServiceLimit{Rate: 10, Burst: 2})
+
+	clock.advance(time.Hour)
+	for i := 0; i < 2; i++ {
+		if d := b.reserve(); d != 0 {
+			t.Fatalf("request %d should not wait, got %v", i, d)
+		}
+	}
+	if d := b.reserve(); d == 0 {
+		t.Error("tokens should be capped at burst after a long idle period")
+	}
+}
+
+func TestTokenBucket_ThrottlePausesAndBacksOff(t *testing.T) {
+	b, clock := newTestBucket(ServiceLimit{Rate: 100, Burst: 10})
+
+	b.throttled()
+	if d := b.reserve(); d != minThrottlePenalty {
+		t.Errorf("after throttle, wait = %v, want %v", d, minThrottlePenalty)
+	}
+
+	clock.advance(minThrottlePenalty)
+	b.throttled()
+	if d := b.reserve(); d != 2*minThrottlePenalty {
+		t.Errorf("consecutive throttle should double the penalty, got %v", d)
+	}
+
+	b.succeeded()
+	if b.penalty != minThrottlePenalty {
+		t.Errorf("success should reset penalty, got %v", b.penalty)
+	}
+}
+
+func TestTokenBucket_PenaltyCapped(t *testing.T) {
+	b, _ := newTestBucket(ServiceLimit{Rate: 1, Burst: 1})
+	for i := 0; i < 20; i++ {
+		b.throttled()
+	}
+	if b.penalty != maxThrottlePenalty {
+		t.Errorf("penalty = %v, want cap %v", b.penalty, maxThrottlePenalty)
+	}
+}
+
+func TestTokenBucket_WaitHonorsContext(t *testing.T) {
+	b := newTokenBucket(ServiceLimit{Rate: 0.01, Burst: 1})
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("first wait should succeed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTokenBucket_InvalidLimitUsesSafeDefaults(t *testing.T) {
+	b := newTokenBucket(ServiceLimit{})
+	if b.rate <= 0 || b.burst < 1 {
+		t.Errorf("invalid limit should fall back to positive rate/burst, got rate=%v burst=%v", b.rate, b.burst)
+	}
+}
+
+func TestRateLimiter_PerServiceBuckets(t *testing.T) {
+	r := NewRateLimiter(map[string]ServiceLimit{"Backup": {Rate: 1, Burst: 1}})
+
+	if r.bucket("Backup", "us-west-2") != r.bucket("Backup", "us-west-2") {
+		t.Error("same service should reuse its bucket")
+	}
+	if r.bucket("Backup", "us-west-2") == r.bucket("RDS", "us-west-2") {
+		t.Error("different services should have independent buckets")
+	}
+	if r.bucket("Backup", "us-west-2") == r.bucket("Backup", "us-east-1") {
+		t.Error("different regions should have independent buckets")
+	}
+	if got := r.bucket("Backup", "us-east-1").burst; got != 1 {
+		t.Errorf("override should replace default burst, got %v", got)
+	}
+	if got := r.bucket("Unknown", "us-west-2").rate; got != fallbackServiceLimit.Rate {
+		t.Errorf("unknown service should use fallback rate, got %v", got)
+	}
+}
+
+func TestRateLimiter_DefaultsIncludeBackup(t *testing.T) {
+	r := NewRateLimiter(nil)
+	if _, ok := r.limits["Backup"]; !ok {
+		t.Error("default limits should include AWS Backup")
+	}
+}
+
+func TestIsThrottle(t *testing.T) {
+	throttle := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+	if !isThrottle(throttle) {
+		t.Error("ThrottlingException should be detected as a throttle")
+	}
+	if isThrottle(&smithy.GenericAPIError{Code: "AccessDeniedException"}) {
+		t.Error("AccessDeniedException is not a throttle")
+	}
+	if isThrottle(errors.New("boom")) {
+		t.Error("plain errors are not throttles")
+	}
+}
+
+// runLimiterMiddleware executes the limiter's finalize middleware against a
+// stub handler returning handlerErr.
+func runLimiterMiddleware(t *testing.T, r *RateLimiter, service, region string, handlerErr error) error {
+	t.Helper()
+	stack := middleware.NewStack("test", func() interface{} { return nil })
+	if err := r.AddToStack(stack); err != nil {
+		t.Fatalf("AddToStack: %v", err)
+	}
+	mw, ok := stack.Finalize.Get(rateLimitMiddlewareID)
+	if !ok {
+		t.Fatal("limiter middleware not registered")
+	}
+
+	ctx := serviceContext(service, region)
+	next := middleware.FinalizeHandlerFunc(func(context.Context, middleware.FinalizeInput) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, handlerErr
+	})
+	_, _, err := mw.HandleFinalize(ctx, middleware.FinalizeInput{}, next)
+	return err
+}
+
+// serviceContext returns a context naming the service and region of a
+// request, as the SDK's middleware stack sets them.
+func serviceContext(service, region string) context.Context {
+	var ctx context.Context
+	_, _, _ = awsmiddleware.RegisterServiceMetadata{ServiceID: service, Region: region}.HandleInitialize(context.Background(), middleware.InitializeInput{},
+		middleware.InitializeHandlerFunc(func(c context.Context, _ middleware.InitializeInput) (middleware.InitializeOutput, middleware.Metadata, error) {
+			ctx = c
+			return middleware.InitializeOutput{}, middleware.Metadata{}, nil
+		}))
+	return ctx
+}
+
+func TestRateLimiter_MiddlewarePenalizesThrottle(t *testing.T) {
+	r := NewRateLimiter(nil)
+	throttle := &smithy.GenericAPIError{Code: "ThrottlingException"}
+
+	if err := runLimiterMiddleware(t, r, "Backup", "us-west-2", throttle); !errors.Is(err, throttle) {
+		t.Fatalf("middleware should pass through handler error, got %v", err)
+	}
+	if d := r.bucket("Backup", "us-west-2").reserve(); d <= 0 {
+		t.Error("bucket should be paused after a throttling error")
+	}
+	if d := r.bucket("RDS", "us-west-2").reserve(); d != 0 {
+		t.Error("throttling one service should not affect others")
+	}
+}
+
+func TestRateLimiter_RegionsDoNotBlockEachOther(t *testing.T) {
+	r := NewRateLimiter(map[string]ServiceLimit{"Backup": {Rate: 0.001, Burst: 1}})
+
+	// The primary region is throttled, which pauses its bucket
+	if err := runLimiterMiddleware(t, r, "Backup", "us-west-2", &smithy.GenericAPIError{Code: "ThrottlingException"}); err == nil {
+		t.Fatal("expected the throttling error")
+	}
+
+	// The DR region's calls go ahead at once
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := r.Wait(ctx, "Backup", "us-east-1"); err != nil {
+		t.Errorf("a call in another region should not wait for the throttled one: %v", err)
+	}
+	if d := r.bucket("Backup", "us-west-2").reserve(); d <= 0 {
+		t.Error("the throttled region should still be paused")
+	}
+}
+
+func TestRateLimiter_MiddlewareSuccess(t *testing.T) {
+	r := NewRateLimiter(nil)
+	if err := runLimiterMiddleware(t, r, "RDS", "us-west-2", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b := r.bucket("RDS", "us-west-2")
+	if b.tokens != b.burst-1 {
+		t.Errorf("successful call should consume one token, tokens=%v burst=%v", b.tokens, b.burst)
+	}
+}
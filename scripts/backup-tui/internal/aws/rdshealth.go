@@ -0,0 +1,204 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the health summary of the stack's current Aurora
+// cluster — the restore target — shown before an RDS restore: status,
+// engine version, instances, storage, recent failovers, and the window its
+// own automated backups can restore it to any moment of.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// failoverLookback is how far back ClusterHealth looks for failovers.
+const failoverLookback = 7 * 24 * time.Hour
+
+// ClusterHealth summarizes an Aurora cluster's current state.
+type ClusterHealth struct {
+	ClusterID        string
+	Status           string // e.g. "available", "backing-up", "failing-over"
+	Engine           string
+	EngineVersion    string
+	StorageType      string // "aurora" or "aurora-iopt1"
+	StorageEncrypted bool
+	AllocatedGiB     int32   // Only meaningful for non-Aurora storage; Aurora grows automatically
+	ServerlessMinACU float64 // Serverless v2 scaling range, zero when not configured
+	ServerlessMaxACU float64
+	SubnetGroup      string
+	SecurityGroups   []string
+	KMSKeyID         string // Key ARN encrypting the cluster, empty when unencrypted
+	Instances        []ClusterInstance
+	Failovers        []ClusterEvent // Failovers in the last 7 days, newest first
+
+	// The window Aurora's automated backups can restore the cluster to any
+	// moment of (point-in-time restore), zero when unknown
+	EarliestRestorable time.Time
+	LatestRestorable   time.Time
+}
+
+// CoversPITR reports whether the cluster can be restored to exactly t by a
+// point-in-time restore from its automated backups.
+func (h *ClusterHealth) CoversPITR(t time.Time) bool {
+	return !h.EarliestRestorable.IsZero() && !t.Before(h.EarliestRestorable) && !t.After(h.LatestRestorable)
+}
+
+// PITRCoverage is what a cluster's point-in-time restore window adds to its
+// AWS Backup recovery points, which only restore to when they were taken.
+type PITRCoverage struct {
+	Backups int           // Completed backups of the cluster inside the window
+	Gaps    int           // Gaps between consecutive backups inside the window
+	Longest time.Duration // Longest of those gaps
+	Newer   time.Duration // How far past the newest backup the window reaches
+}
+
+// PITRCoverage reports which gaps between the cluster's completed backups
+// in points its point-in-time restore window covers, including the time
+// since the newest one. It is zero when the window is unknown.
+func (h *ClusterHealth) PITRCoverage(points []RecoveryPoint) PITRCoverage {
+	var c PITRCoverage
+	if h.EarliestRestorable.IsZero() {
+		return c
+	}
+	var times []time.Time
+	for _, rp := range points {
+		ofCluster := rp.ResourceID == h.ClusterID || strings.HasSuffix(rp.ResourceARN, ":cluster:"+h.ClusterID)
+		if rp.ResourceType == "RDS" && ofCluster && rp.Status == "COMPLETED" && h.CoversPITR(rp.CreationDate) {
+			times = append(times, rp.CreationDate)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	c.Backups = len(times)
+	for i := 1; i < len(times); i++ {
+		c.Gaps++
+		c.Longest = max(c.Longest, times[i].Sub(times[i-1]))
+	}
+	if len(times) > 0 {
+		c.Newer = h.LatestRestorable.Sub(times[len(times)-1])
+	}
+	return c
+}
+
+// ClusterInstance is a DB instance in a cluster.
+type ClusterInstance struct {
+	ID               string
+	Class            string // e.g. "db.r6g.large" or "db.serverless"
+	Writer           bool
+	AvailabilityZone string
+	Status           string
+}
+
+// ClusterEvent is an RDS event for a cluster.
+type ClusterEvent struct {
+	Time    time.Time
+	Message string
+}
+
+// Healthy reports whether the cluster and all its instances are available.
+func (h *ClusterHealth) Healthy() bool {
+	if h.Status != "available" {
+		return false
+	}
+	for _, inst := range h.Instances {
+		if inst.Status != "available" {
+			return false
+		}
+	}
+	return true
+}
+
+// GetClusterHealth returns the health of the stack's current database
+// cluster, which an RDS restore would replace.
+func (c *BackupClient) GetClusterHealth(ctx context.Context, stackName string) (*ClusterHealth, error) {
+	clusterID, err := c.getRDSClusterIDFromStack(ctx, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := c.rds.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(clusterID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe DB cluster: %w", err)
+	}
+	if len(out.DBClusters) == 0 {
+		return nil, fmt.Errorf("DB cluster not found: %s", clusterID)
+	}
+	cl := out.DBClusters[0]
+
+	h := &ClusterHealth{
+		ClusterID:        clusterID,
+		Status:           aws.ToString(cl.Status),
+		Engine:           aws.ToString(cl.Engine),
+		EngineVersion:    aws.ToString(cl.EngineVersion),
+		StorageType:      aws.ToString(cl.StorageType),
+		StorageEncrypted: aws.ToBool(cl.StorageEncrypted),
+		AllocatedGiB:     aws.ToInt32(cl.AllocatedStorage),
+		SubnetGroup:      aws.ToString(cl.DBSubnetGroup),
+		KMSKeyID:         aws.ToString(cl.KmsKeyId),
+
+		EarliestRestorable: aws.ToTime(cl.EarliestRestorableTime),
+		LatestRestorable:   aws.ToTime(cl.LatestRestorableTime),
+	}
+	for _, sg := range cl.VpcSecurityGroups {
+		h.SecurityGroups = append(h.SecurityGroups, aws.ToString(sg.VpcSecurityGroupId))
+	}
+	if h.StorageType == "" {
+		h.StorageType = "aurora" // Omitted by the API for standard Aurora storage
+	}
+	if sc := cl.ServerlessV2ScalingConfiguration; sc != nil {
+		h.ServerlessMinACU = aws.ToFloat64(sc.MinCapacity)
+		h.ServerlessMaxACU = aws.ToFloat64(sc.MaxCapacity)
+	}
+
+	writers := make(map[string]bool, len(cl.DBClusterMembers))
+	for _, member := range cl.DBClusterMembers {
+		writers[aws.ToString(member.DBInstanceIdentifier)] = aws.ToBool(member.IsClusterWriter)
+	}
+	instances := rds.NewDescribeDBInstancesPaginator(c.rds, &rds.DescribeDBInstancesInput{
+		Filters: []rdstypes.Filter{{Name: aws.String("db-cluster-id"), Values: []string{clusterID}}},
+	})
+	for instances.HasMorePages() {
+		page, err := instances.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DB instances: %w", err)
+		}
+		for _, inst := range page.DBInstances {
+			id := aws.ToString(inst.DBInstanceIdentifier)
+			h.Instances = append(h.Instances, ClusterInstance{
+				ID:               id,
+				Class:            aws.ToString(inst.DBInstanceClass),
+				Writer:           writers[id],
+				AvailabilityZone: aws.ToString(inst.AvailabilityZone),
+				Status:           aws.ToString(inst.DBInstanceStatus),
+			})
+		}
+	}
+	// Writer first, then readers by ID
+	sort.Slice(h.Instances, func(i, j int) bool {
+		if h.Instances[i].Writer != h.Instances[j].Writer {
+			return h.Instances[i].Writer
+		}
+		return h.Instances[i].ID < h.Instances[j].ID
+	})
+
+	events, err := c.rds.DescribeEvents(ctx, &rds.DescribeEventsInput{
+		SourceIdentifier: aws.String(clusterID),
+		SourceType:       rdstypes.SourceTypeDbCluster,
+		Duration:         aws.Int32(int32(failoverLookback / time.Minute)),
+		EventCategories:  []string{"failover"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster events: %w", err)
+	}
+	for _, e := range events.Events {
+		h.Failovers = append(h.Failovers, ClusterEvent{Time: aws.ToTime(e.Date), Message: aws.ToString(e.Message)})
+	}
+	sort.Slice(h.Failovers, func(i, j int) bool { return h.Failovers[i].Time.After(h.Failovers[j].Time) })
+
+	return h, nil
+}
@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+func clusterStackMock() *mockCFN {
+	return &mockCFN{
+		describeStackOutput: &cloudformation.DescribeStacksOutput{
+			Stacks: []cfntypes.Stack{{Outputs: []cfntypes.Output{{
+				OutputKey:   aws.String("DatabaseEndpoint"),
+				OutputValue: aws.String("my-cluster.xxx.us-west-2.rds.amazonaws.com"),
+			}}}},
+		},
+	}
+}
+
+func TestGetClusterHealth(t *testing.T) {
+	now := time.Now()
+	rdsMock := &mockRDS{
+		describeClustersOutput: &rds.DescribeDBClustersOutput{DBClusters: []rdstypes.DBCluster{{
+			DBClusterIdentifier: aws.String("my-cluster"),
+			Status:              aws.String("available"),
+			Engine:              aws.String("aurora-mysql"),
+			EngineVersion:       aws.String("8.0.mysql_aurora.3.08.0"),
+			StorageEncrypted:    aws.Bool(true),
+			ServerlessV2ScalingConfiguration: &rdstypes.ServerlessV2ScalingConfigurationInfo{
+				MinCapacity: aws.Float64(0.5), MaxCapacity: aws.Float64(16),
+			},
+			DBClusterMembers: []rdstypes.DBClusterMember{
+				{DBInstanceIdentifier: aws.String("inst-a"), IsClusterWriter: aws.Bool(false)},
+				{DBInstanceIdentifier: aws.String("inst-b"), IsClusterWriter: aws.Bool(true)},
+			},
+		}}},
+		describeInstancesOutput: &rds.DescribeDBInstancesOutput{DBInstances: []rdstypes.DBInstance{
+			{DBInstanceIdentifier: aws.String("inst-a"), DBInstanceClass: aws.String("db.serverless"), DBInstanceStatus: aws.String("available")},
+			{DBInstanceIdentifier: aws.String("inst-b"), DBInstanceClass: aws.String("db.serverless"), DBInstanceStatus: aws.String("available")},
+		}},
+		describeEventsOutput: &rds.DescribeEventsOutput{Events: []rdstypes.Event{
+			{Date: aws.Time(now.Add(-48 * time.Hour)), Message: aws.String("older")},
+			{Date: aws.Time(now.Add(-time.Hour)), Message: aws.String("newer")},
+		}},
+	}
+	c := newTestClient(clusterStackMock(), &mockBackup{}, rdsMock)
+
+	h, err := c.GetClusterHealth(context.Background(), "TestStack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.ClusterID != "my-cluster" || h.StorageType != "aurora" || h.ServerlessMaxACU != 16 || !h.Healthy() {
+		t.Errorf("unexpected health: %+v", h)
+	}
+	if len(h.Instances) != 2 || h.Instances[0].ID != "inst-b" || !h.Instances[0].Writer {
+		t.Errorf("writer should be listed first: %+v", h.Instances)
+	}
+	if len(h.Failovers) != 2 || h.Failovers[0].Message != "newer" {
+		t.Errorf("failovers should be newest first: %+v", h.Failovers)
+	}
+	if in := rdsMock.describeEventsInput; in == nil || aws.ToInt32(in.Duration) != 7*24*60 || in.SourceType != rdstypes.SourceTypeDbCluster {
+		t.Errorf("unexpected events query: %+v", in)
+	}
+}
+
+func TestGetClusterHealth_Errors(t *testing.T) {
+	c := newTestClient(clusterStackMock(), &mockBackup{}, &mockRDS{describeClustersErr: errors.New("access denied")})
+	if _, err := c.GetClusterHealth(context.Background(), "TestStack"); err == nil {
+		t.Error("expected cluster lookup error")
+	}
+
+	c = newTestClient(clusterStackMock(), &mockBackup{}, &mockRDS{describeClustersOutput: &rds.DescribeDBClustersOutput{}})
+	if _, err := c.GetClusterHealth(context.Background(), "TestStack"); err == nil {
+		t.Error("expected error for missing cluster")
+	}
+}
+
+func TestClusterHealth_Healthy(t *testing.T) {
+	h := &ClusterHealth{Status: "available", Instances: []ClusterInstance{{Status: "available"}, {Status: "rebooting"}}}
+	if h.Healthy() {
+		t.Error("a rebooting instance should make the cluster unhealthy")
+	}
+}
+
+func TestClusterHealth_PITRCoverage(t *testing.T) {
+	now := time.Now()
+	h := &ClusterHealth{ClusterID: "db", EarliestRestorable: now.Add(-7 * 24 * time.Hour), LatestRestorable: now.Add(-5 * time.Minute)}
+	point := func(id, status string, age time.Duration) RecoveryPoint {
+		return RecoveryPoint{ResourceType: "RDS", ResourceID: id, Status: status, CreationDate: now.Add(-age)}
+	}
+	points := []RecoveryPoint{
+		point("db", "COMPLETED", 3*time.Hour),
+		point("db", "COMPLETED", 51*time.Hour),
+		{ResourceType: "RDS", ResourceID: "cluster", ResourceARN: "arn:aws:rds:us-west-2:1:cluster:db", Status: "COMPLETED", CreationDate: now.Add(-27 * time.Hour)},
+		point("db", "PARTIAL", 10*time.Hour),
+		point("other", "COMPLETED", time.Hour),
+		point("db", "COMPLETED", 10*24*time.Hour), // Before the window
+	}
+
+	c := h.PITRCoverage(points)
+	if c.Backups != 3 || c.Gaps != 2 {
+		t.Errorf("got %d backups and %d gaps, want 3 and 2", c.Backups, c.Gaps)
+	}
+	if c.Longest != 24*time.Hour {
+		t.Errorf("longest gap = %v, want 24h", c.Longest)
+	}
+	if c.Newer != 3*time.Hour-5*time.Minute {
+		t.Errorf("newer = %v, want 2h55m", c.Newer)
+	}
+
+	if got := (&ClusterHealth{ClusterID: "db"}).PITRCoverage(points); got != (PITRCoverage{}) {
+		t.Errorf("an unknown window should cover nothing, got %+v", got)
+	}
+}
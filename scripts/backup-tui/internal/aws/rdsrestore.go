@@ -0,0 +1,98 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements restoring RDS backups: the restored Aurora cluster
+// takes the live cluster's identifier, subnet group, and security groups
+// from the stack, each of which the operator can override.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// rdsRestore restores an RDS backup as an Aurora cluster in the live
+// cluster's network.
+var rdsRestore = restoreType{
+	resolve:  resolveRDSRestore,
+	metadata: rdsRestoreMetadata,
+	options:  rdsRestoreOptions,
+	preview:  previewRDSRestore,
+	validate: refuseItems,
+}
+
+// resolveRDSRestore looks up the stack's live cluster and its network.
+func resolveRDSRestore(ctx context.Context, c *BackupClient, _ RecoveryPoint, stackName string, meta *RestoreMetadata) error {
+	reportProgress(ctx, "Resolving cluster…")
+	dbClusterID, err := c.getRDSClusterIDFromStack(ctx, stackName)
+	if err != nil {
+		return fmt.Errorf("failed to get RDS cluster ID from stack: %w", err)
+	}
+
+	reportProgress(ctx, "Fetching subnet group and security groups…")
+	subnetGroup, securityGroups, err := c.getRDSClusterDetails(ctx, dbClusterID)
+	if err != nil {
+		return fmt.Errorf("failed to get RDS cluster details: %w", err)
+	}
+
+	meta.ClusterID = dbClusterID
+	meta.SubnetGroup = subnetGroup
+	meta.SecurityGroups = securityGroups
+	return nil
+}
+
+// rdsRestoreMetadata returns the RDS restore metadata:
+//   - DBClusterIdentifier: The target cluster identifier
+//   - DBSubnetGroupName: The subnet group to use for the restored cluster
+//   - VpcSecurityGroupIds: Comma-separated list of security group IDs
+func rdsRestoreMetadata(meta *RestoreMetadata) map[string]string {
+	return map[string]string{
+		"DBClusterIdentifier": meta.ClusterID,
+		"DBSubnetGroupName":   meta.SubnetGroup,
+		"VpcSecurityGroupIds": meta.SecurityGroups,
+	}
+}
+
+// rdsRestoreOptions names the cluster with opts' suffix and places it in
+// the chosen subnet group and security groups.
+func rdsRestoreOptions(metadata map[string]string, opts RestoreOptions) error {
+	if opts.ClusterSuffix != "" {
+		id, err := RestoredClusterID(metadata["DBClusterIdentifier"], opts.ClusterSuffix)
+		if err != nil {
+			return err
+		}
+		metadata["DBClusterIdentifier"] = id
+	}
+	if opts.SubnetGroup != "" {
+		metadata["DBSubnetGroupName"] = opts.SubnetGroup
+	}
+	if len(opts.SecurityGroupIDs) > 0 {
+		metadata["VpcSecurityGroupIds"] = strings.Join(opts.SecurityGroupIDs, ",")
+	}
+	return nil
+}
+
+// previewRDSRestore shows the cluster name, subnet group, and security
+// groups opts choose, or the live cluster's where they choose none.
+func previewRDSRestore(m *RestoreMetadata, opts RestoreOptions) {
+	if m.liveClusterID == "" {
+		m.liveClusterID = m.ClusterID
+	}
+	m.ClusterID = m.liveClusterID
+	if id, err := RestoredClusterID(m.liveClusterID, opts.ClusterSuffix); err == nil && opts.ClusterSuffix != "" {
+		m.ClusterID = id
+	}
+	if m.liveSubnetGroup == "" {
+		m.liveSubnetGroup = m.SubnetGroup
+	}
+	m.SubnetGroup = m.liveSubnetGroup
+	if opts.SubnetGroup != "" {
+		m.SubnetGroup = opts.SubnetGroup
+	}
+	if m.liveSecurityGroups == "" {
+		m.liveSecurityGroups = m.SecurityGroups
+	}
+	m.SecurityGroups = m.liveSecurityGroups
+	if len(opts.SecurityGroupIDs) > 0 {
+		m.SecurityGroups = strings.Join(opts.SecurityGroupIDs, ",")
+	}
+}
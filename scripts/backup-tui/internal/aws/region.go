@@ -0,0 +1,116 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements AWS region resolution for the TUI, so that the region
+// actually used is explicit and never silently defaulted.
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// Region sources, in resolution order.
+const (
+	RegionSourceFlag         = "-region flag"
+	RegionSourceEnv          = "AWS_REGION"
+	RegionSourceDefaultEnv   = "AWS_DEFAULT_REGION" // The AWS CLI's variable, which the SDK ignores
+	RegionSourceSharedConfig = "shared config"
+	RegionSourcePrompt       = "prompt"
+
+	// RegionSourceSimulation marks the region taken from -simulate fixtures.
+	RegionSourceSimulation = "simulation fixtures"
+)
+
+// RegionResolution records the resolved region and where it came from.
+type RegionResolution struct {
+	Region string // AWS region name (e.g., "us-east-1")
+	Source string // One of the RegionSource* constants
+	Detail string // Extra context, e.g. the shared config profile name
+}
+
+// String returns a human-readable description such as
+// "us-east-1 (from shared config, profile prod)".
+func (r RegionResolution) String() string {
+	if r.Detail != "" {
+		return fmt.Sprintf("%s (from %s, %s)", r.Region, r.Source, r.Detail)
+	}
+	return fmt.Sprintf("%s (from %s)", r.Region, r.Source)
+}
+
+// ErrRegionUnresolved is returned by ResolveRegion when no region is set by
+// flag, environment, or shared config. Interactive callers should prompt.
+var ErrRegionUnresolved = errors.New("AWS region could not be determined")
+
+// ErrInvalidRegion is returned by ResolveRegion when the -region flag is not
+// an AWS region name. Callers should treat it as a usage error.
+var ErrInvalidRegion = errors.New("not an AWS region name such as us-west-2")
+
+// regionPattern matches AWS region names such as "us-west-2" or "us-gov-east-1".
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
+// ValidRegion reports whether s looks like an AWS region name.
+func ValidRegion(s string) bool {
+	return regionPattern.MatchString(s)
+}
+
+// ResolveRegion determines the AWS region using, in order:
+//  1. The -region flag (flagRegion, if non-empty)
+//  2. The AWS_REGION environment variable
+//  3. The AWS_DEFAULT_REGION environment variable, which the AWS CLI reads
+//     and the SDK does not, so a shell set up for the CLI works here too
+//  4. The region of the shared config profile: profile if non-empty, else
+//     AWS_PROFILE or "default"
+//
+// A flag value that is not a region name is refused with ErrInvalidRegion,
+// as the prompt refuses it, rather than failing later as an endpoint lookup.
+// If none of these yields a region, ErrRegionUnresolved is returned so the
+// caller can prompt the operator. There is deliberately no hardcoded default:
+// restoring from the wrong region is worse than asking.
+func ResolveRegion(ctx context.Context, flagRegion, profile string) (RegionResolution, error) {
+	if flagRegion != "" {
+		if !ValidRegion(flagRegion) {
+			return RegionResolution{}, fmt.Errorf("invalid -region %q: %w", flagRegion, ErrInvalidRegion)
+		}
+		return RegionResolution{Region: flagRegion, Source: RegionSourceFlag}, nil
+	}
+
+	if r := os.Getenv("AWS_REGION"); r != "" {
+		return RegionResolution{Region: r, Source: RegionSourceEnv}, nil
+	}
+	if r := os.Getenv("AWS_DEFAULT_REGION"); r != "" {
+		return RegionResolution{Region: r, Source: RegionSourceDefaultEnv}, nil
+	}
+
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	if r := loadSharedConfigRegion(ctx, profile); r != "" {
+		return RegionResolution{Region: r, Source: RegionSourceSharedConfig, Detail: "profile " + profile}, nil
+	}
+
+	return RegionResolution{}, ErrRegionUnresolved
+}
+
+// loadSharedConfigRegion returns the region configured for a shared config
+// profile, or "" if the profile or files do not exist.
+func loadSharedConfigRegion(ctx context.Context, profile string) string {
+	cfg, err := awsconfig.LoadSharedConfigProfile(ctx, profile, func(o *awsconfig.LoadSharedConfigOptions) {
+		if f := os.Getenv("AWS_CONFIG_FILE"); f != "" {
+			o.ConfigFiles = []string{f}
+		}
+		if f := os.Getenv("AWS_SHARED_CREDENTIALS_FILE"); f != "" {
+			o.CredentialsFiles = []string{f}
+		}
+	})
+	if err != nil {
+		return ""
+	}
+	return cfg.Region
+}
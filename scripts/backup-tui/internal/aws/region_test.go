@@ -0,0 +1,158 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// isolateRegionEnv clears region-related environment variables and points
+// the shared config at a temporary file with the given contents.
+func isolateRegionEnv(t *testing.T, configContents string) {
+	t.Helper()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(configPath, []byte(configContents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", configPath)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_PROFILE", "")
+}
+
+func TestResolveRegion_FlagWins(t *testing.T) {
+	isolateRegionEnv(t, "[default]\nregion = eu-west-1\n")
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	res, err := ResolveRegion(context.Background(), "ap-southeast-2", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Region != "ap-southeast-2" || res.Source != RegionSourceFlag {
+		t.Errorf("got %+v, want flag region", res)
+	}
+}
+
+func TestResolveRegion_InvalidFlag(t *testing.T) {
+	isolateRegionEnv(t, "[default]\nregion = eu-west-1\n")
+
+	for _, flagRegion := range []string{"us-west2", "US-WEST-2", "us-west-2 "} {
+		if _, err := ResolveRegion(context.Background(), flagRegion, ""); !errors.Is(err, ErrInvalidRegion) {
+			t.Errorf("ResolveRegion(%q) = %v, want ErrInvalidRegion", flagRegion, err)
+		}
+	}
+}
+
+func TestResolveRegion_EnvBeforeSharedConfig(t *testing.T) {
+	isolateRegionEnv(t, "[default]\nregion = eu-west-1\n")
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	res, err := ResolveRegion(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Region != "us-east-1" || res.Source != RegionSourceEnv {
+		t.Errorf("got %+v, want AWS_REGION", res)
+	}
+}
+
+func TestResolveRegion_DefaultRegionEnv(t *testing.T) {
+	isolateRegionEnv(t, "[default]\nregion = eu-west-1\n")
+	t.Setenv("AWS_DEFAULT_REGION", "ca-central-1")
+
+	res, err := ResolveRegion(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Region != "ca-central-1" || res.Source != RegionSourceDefaultEnv {
+		t.Errorf("got %+v, want AWS_DEFAULT_REGION before shared config", res)
+	}
+
+	t.Setenv("AWS_REGION", "us-east-1")
+	if res, _ := ResolveRegion(context.Background(), "", ""); res.Source != RegionSourceEnv {
+		t.Errorf("got %+v, want AWS_REGION before AWS_DEFAULT_REGION", res)
+	}
+}
+
+func TestResolveRegion_SharedConfigDefaultProfile(t *testing.T) {
+	isolateRegionEnv(t, "[default]\nregion = eu-west-1\n")
+
+	res, err := ResolveRegion(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Region != "eu-west-1" || res.Source != RegionSourceSharedConfig || res.Detail != "profile default" {
+		t.Errorf("got %+v, want shared config default profile", res)
+	}
+}
+
+func TestResolveRegion_SharedConfigNamedProfile(t *testing.T) {
+	isolateRegionEnv(t, "[default]\nregion = eu-west-1\n\n[profile prod]\nregion = us-east-2\n")
+	t.Setenv("AWS_PROFILE", "prod")
+
+	res, err := ResolveRegion(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Region != "us-east-2" || res.Detail != "profile prod" {
+		t.Errorf("got %+v, want prod profile region", res)
+	}
+
+	// -profile takes precedence over AWS_PROFILE
+	if res, _ := ResolveRegion(context.Background(), "", "default"); res.Region != "eu-west-1" || res.Detail != "profile default" {
+		t.Errorf("got %+v, want the named profile's region", res)
+	}
+}
+
+func TestResolveRegion_Unresolved(t *testing.T) {
+	isolateRegionEnv(t, "[default]\noutput = json\n")
+
+	_, err := ResolveRegion(context.Background(), "", "")
+	if !errors.Is(err, ErrRegionUnresolved) {
+		t.Errorf("expected ErrRegionUnresolved, got %v", err)
+	}
+}
+
+func TestResolveRegion_MissingProfileIsUnresolved(t *testing.T) {
+	isolateRegionEnv(t, "[default]\nregion = eu-west-1\n")
+	t.Setenv("AWS_PROFILE", "does-not-exist")
+
+	_, err := ResolveRegion(context.Background(), "", "")
+	if !errors.Is(err, ErrRegionUnresolved) {
+		t.Errorf("expected ErrRegionUnresolved for missing profile, got %v", err)
+	}
+}
+
+func TestRegionResolution_String(t *testing.T) {
+	tests := []struct {
+		res  RegionResolution
+		want string
+	}{
+		{RegionResolution{Region: "us-east-1", Source: RegionSourceEnv}, "us-east-1 (from AWS_REGION)"},
+		{RegionResolution{Region: "eu-west-1", Source: RegionSourceSharedConfig, Detail: "profile prod"}, "eu-west-1 (from shared config, profile prod)"},
+	}
+	for _, tt := range tests {
+		if got := tt.res.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestValidRegion(t *testing.T) {
+	valid := []string{"us-west-2", "eu-central-1", "us-gov-east-1", "ap-southeast-4"}
+	invalid := []string{"", "uswest2", "us-west", "US-WEST-2", "us-west-2a"}
+	for _, r := range valid {
+		if !ValidRegion(r) {
+			t.Errorf("ValidRegion(%q) = false, want true", r)
+		}
+	}
+	for _, r := range invalid {
+		if ValidRegion(r) {
+			t.Errorf("ValidRegion(%q) = true, want false", r)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements resource type discovery: the resource types AWS
+// Backup supports in the region, and which of them a vault actually holds,
+// so filters offer what is there instead of assuming only RDS and EFS.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// stackResourceTypes are the resource types this stack backs up. They are
+// listed before any others a vault holds.
+var stackResourceTypes = []string{"RDS", "EFS"}
+
+// KnownResourceTypes are the resource types AWS Backup names, as spelled in
+// its API. Not every region supports all of them.
+var KnownResourceTypes = []string{
+	"Aurora", "CloudFormation", "DocumentDB", "DynamoDB", "EBS", "EC2", "EFS", "FSx", "Neptune", "RDS",
+	"Redshift", "Redshift Serverless", "S3", "SAP HANA on Amazon EC2", "Storage Gateway", "Timestream", "VirtualMachine",
+}
+
+// ParseResourceTypes parses a comma-separated list of resource types, e.g.
+// "RDS,EFS", as the -type flags take it. Types are matched against
+// KnownResourceTypes without regard to case and returned as AWS Backup
+// spells them, without duplicates. An empty list means all types.
+func ParseResourceTypes(s string) ([]string, error) {
+	var types []string
+	for item := range strings.SplitSeq(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := slices.IndexFunc(KnownResourceTypes, func(t string) bool { return strings.EqualFold(t, item) })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown resource type %q: use one or more of %s", item, strings.Join(KnownResourceTypes, ", "))
+		}
+		if !slices.Contains(types, KnownResourceTypes[i]) {
+			types = append(types, KnownResourceTypes[i])
+		}
+	}
+	return types, nil
+}
+
+// SupportedResourceTypes returns the resource types AWS Backup supports in
+// the client's region, e.g. "Aurora", "DynamoDB", "EFS", "RDS".
+func (c *BackupClient) SupportedResourceTypes(ctx context.Context) ([]string, error) {
+	out, err := c.client.GetSupportedResourceTypes(ctx, &backup.GetSupportedResourceTypesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supported resource types: %w", err)
+	}
+	return out.ResourceTypes, nil
+}
+
+// VaultResourceTypes returns the resource types of points, limited to
+// supported when it is not nil: the stack's own types first, then the rest
+// in alphabetical order.
+func VaultResourceTypes(points []RecoveryPoint, supported []string) []string {
+	var types []string
+	for _, rp := range points {
+		t := rp.ResourceType
+		if t == "" || slices.Contains(types, t) || (supported != nil && !slices.Contains(supported, t)) {
+			continue
+		}
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		ri, rj := stackTypeRank(types[i]), stackTypeRank(types[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return types[i] < types[j]
+	})
+	return types
+}
+
+// stackTypeRank orders the stack's resource types before all others.
+func stackTypeRank(t string) int {
+	if i := slices.Index(stackResourceTypes, t); i >= 0 {
+		return i
+	}
+	return len(stackResourceTypes)
+}
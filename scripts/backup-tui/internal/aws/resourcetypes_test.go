@@ -0,0 +1,84 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+func TestVaultResourceTypes(t *testing.T) {
+	points := []RecoveryPoint{
+		{ResourceType: "DynamoDB"},
+		{ResourceType: "EFS"},
+		{ResourceType: "Aurora"},
+		{ResourceType: "RDS"},
+		{ResourceType: "EFS"},
+		{ResourceType: ""},
+	}
+
+	got := VaultResourceTypes(points, nil)
+	if want := []string{"RDS", "EFS", "Aurora", "DynamoDB"}; !slices.Equal(got, want) {
+		t.Errorf("VaultResourceTypes = %v, want the stack's types first, then the rest sorted: %v", got, want)
+	}
+
+	got = VaultResourceTypes(points, []string{"EFS", "DynamoDB"})
+	if want := []string{"EFS", "DynamoDB"}; !slices.Equal(got, want) {
+		t.Errorf("VaultResourceTypes = %v, want only supported types %v", got, want)
+	}
+
+	if got := VaultResourceTypes(nil, nil); len(got) != 0 {
+		t.Errorf("an empty vault has no types, got %v", got)
+	}
+}
+
+func TestParseResourceTypes(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"RDS", []string{"RDS"}, false},
+		{"rds, efs", []string{"RDS", "EFS"}, false},
+		{"EFS,RDS,efs,", []string{"EFS", "RDS"}, false},
+		{"sap hana on amazon ec2", []string{"SAP HANA on Amazon EC2"}, false},
+		{"RDS,Postgres", nil, true},
+		{"RDSS", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseResourceTypes(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseResourceTypes(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseResourceTypes(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSupportedResourceTypes(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	types, err := NewSimulatedBackupClient(fx).SupportedResourceTypes(context.Background())
+	if err != nil {
+		t.Fatalf("SupportedResourceTypes: %v", err)
+	}
+	for _, want := range []string{"RDS", "Aurora", "EFS"} {
+		if !slices.Contains(types, want) {
+			t.Errorf("supported types %v should include %s", types, want)
+		}
+	}
+
+	c := newTestClient(&mockCFN{}, &mockBackup{supportedTypesErr: errors.New("access denied")}, &mockRDS{})
+	if _, err := c.SupportedResourceTypes(context.Background()); err == nil {
+		t.Error("expected error when GetSupportedResourceTypes fails")
+	}
+
+	c = newTestClient(&mockCFN{}, &mockBackup{supportedTypesOut: &backup.GetSupportedResourceTypesOutput{ResourceTypes: []string{"EFS"}}}, &mockRDS{})
+	if types, err := c.SupportedResourceTypes(context.Background()); err != nil || !slices.Equal(types, []string{"EFS"}) {
+		t.Errorf("SupportedResourceTypes = %v, %v", types, err)
+	}
+}
@@ -0,0 +1,73 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the shared restore lock: a tag on the stack's backup
+// vault that sessions on other machines read to learn that a restore of the
+// stack is in progress. A tag needs no infrastructure beyond the vault every
+// session already reads; a DynamoDB table, like the optional operation
+// journal's, would have to be created in each account before any session
+// could lock.
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// restoreLockTagPrefix prefixes the stack name in the lock's tag key.
+const restoreLockTagPrefix = "backup-tui:restore-lock:"
+
+// maxTagKey is the longest key AWS allows for a tag.
+const maxTagKey = 128
+
+// RestoreLockTagKey returns the vault tag key of the restore lock of the
+// given stack.
+func RestoreLockTagKey(stack string) string {
+	key := restoreLockTagPrefix + stack
+	if len(key) > maxTagKey {
+		key = key[:maxTagKey]
+	}
+	return key
+}
+
+// SharedRestoreLock returns the value of the restore lock tag of stack on
+// the vault, or "" if no session holds it.
+func (c *BackupClient) SharedRestoreLock(ctx context.Context, vaultName, stack string) (string, error) {
+	key := RestoreLockTagKey(stack)
+	var next *string
+	for {
+		out, err := c.client.ListTags(ctx, &backup.ListTagsInput{ResourceArn: aws.String(c.VaultARN(vaultName)), NextToken: next})
+		if err != nil {
+			return "", fmt.Errorf("failed to read the restore lock of %s: %w", stack, err)
+		}
+		if v, ok := out.Tags[key]; ok {
+			return v, nil
+		}
+		if next = out.NextToken; next == nil {
+			return "", nil
+		}
+	}
+}
+
+// SetSharedRestoreLock tags the vault with the restore lock of stack,
+// replacing whichever value it had.
+func (c *BackupClient) SetSharedRestoreLock(ctx context.Context, vaultName, stack, value string) error {
+	if err := c.tagResource(ctx, c.VaultARN(vaultName), map[string]string{RestoreLockTagKey(stack): value}); err != nil {
+		return fmt.Errorf("failed to write the restore lock of %s: %w", stack, err)
+	}
+	return nil
+}
+
+// ClearSharedRestoreLock removes the restore lock tag of stack from the
+// vault.
+func (c *BackupClient) ClearSharedRestoreLock(ctx context.Context, vaultName, stack string) error {
+	_, err := c.client.UntagResource(ctx, &backup.UntagResourceInput{
+		ResourceArn: aws.String(c.VaultARN(vaultName)),
+		TagKeyList:  []string{RestoreLockTagKey(stack)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release the restore lock of %s: %w", stack, err)
+	}
+	return nil
+}
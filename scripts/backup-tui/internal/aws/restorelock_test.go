@@ -0,0 +1,42 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSimulatedClient_SharedRestoreLock(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	vault, stack := fx.Vaults[0], fx.Stacks[0].Name
+
+	if v, err := c.SharedRestoreLock(ctx, vault, stack); err != nil || v != "" {
+		t.Fatalf("no lock should be held yet, got %q, %v", v, err)
+	}
+	if err := c.SetSharedRestoreLock(ctx, vault, stack, "host=ops-1 pid=1"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := c.SharedRestoreLock(ctx, vault, stack); err != nil || v != "host=ops-1 pid=1" {
+		t.Fatalf("SharedRestoreLock() = %q, %v", v, err)
+	}
+	if v, _ := c.SharedRestoreLock(ctx, vault, "other-stack"); v != "" {
+		t.Errorf("locks are per stack, got %q", v)
+	}
+	if err := c.ClearSharedRestoreLock(ctx, vault, stack); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.SharedRestoreLock(ctx, vault, stack); v != "" {
+		t.Errorf("the lock should be released, got %q", v)
+	}
+}
+
+func TestRestoreLockTagKey_FitsTag(t *testing.T) {
+	if got := RestoreLockTagKey("openemr"); got != "backup-tui:restore-lock:openemr" {
+		t.Errorf("RestoreLockTagKey() = %q", got)
+	}
+	if got := RestoreLockTagKey(strings.Repeat("s", 200)); len(got) > maxTagKey {
+		t.Errorf("key of %d characters does not fit a tag", len(got))
+	}
+}
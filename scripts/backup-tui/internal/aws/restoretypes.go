@@ -0,0 +1,98 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the restore type registry: each resource type the
+// TUI can restore contributes how its restore parameters are resolved, the
+// AWS Backup restore metadata they become, the options it accepts, and how
+// those options change the preview, so a new type is restored by adding it
+// here instead of branching in StartRestoreJob and GetRestoreMetadata.
+package aws
+
+import (
+	"context"
+	"fmt"
+)
+
+// restoreType is how recovery points of one resource type are restored.
+// Every function is optional.
+type restoreType struct {
+	// resolve fills in the parameters meta derives from the stack's live
+	// resource, e.g. the cluster an RDS backup is restored as.
+	resolve func(ctx context.Context, c *BackupClient, rp RecoveryPoint, stackName string, meta *RestoreMetadata) error
+
+	// metadata returns the StartRestoreJob metadata for meta as resolved,
+	// before any options are applied.
+	metadata func(meta *RestoreMetadata) map[string]string
+
+	// options changes metadata for opts, beyond the KMS key every type
+	// takes, whether it came from metadata or was recorded with the backup.
+	options func(metadata map[string]string, opts RestoreOptions) error
+
+	// preview updates meta for opts, matching what options sends.
+	preview func(meta *RestoreMetadata, opts RestoreOptions)
+
+	// validate refuses options the type cannot be restored with. Types
+	// without it accept only the KMS key and tag copy.
+	validate func(resourceType string, opts RestoreOptions) error
+}
+
+// restoreTypes are the resource types with restore parameters of their
+// own, keyed as AWS Backup spells them. Other types are restored with the
+// recovery point's defaults.
+var restoreTypes = map[string]restoreType{
+	"RDS": rdsRestore,
+	"EFS": efsRestore,
+}
+
+// restoreTypeFor returns how recovery points of resourceType are restored.
+func restoreTypeFor(resourceType string) restoreType {
+	return restoreTypes[resourceType]
+}
+
+// ValidateRestoreOptions returns an error if a backup of resourceType
+// cannot be restored with opts, e.g. a new cluster for an EFS backup.
+func ValidateRestoreOptions(resourceType string, opts RestoreOptions) error {
+	if v := restoreTypeFor(resourceType).validate; v != nil {
+		return v(resourceType, opts)
+	}
+	if err := refuseClusterOptions(resourceType, opts); err != nil {
+		return err
+	}
+	return refuseItems(resourceType, opts)
+}
+
+// refuseItems returns an error if opts restore individual items, which
+// only EFS restores can.
+func refuseItems(resourceType string, opts RestoreOptions) error {
+	if len(opts.ItemsToRestore) > 0 {
+		return fmt.Errorf("item-level restore applies to EFS restores only, not %s", resourceType)
+	}
+	return nil
+}
+
+// refuseClusterOptions returns an error if opts set any of the options that
+// place a restored RDS cluster, which resourceType has none of.
+func refuseClusterOptions(resourceType string, opts RestoreOptions) error {
+	switch {
+	case opts.ClusterSuffix != "":
+		return fmt.Errorf("a new cluster applies to RDS restores only, not %s", resourceType)
+	case opts.SubnetGroup != "":
+		return fmt.Errorf("a subnet group applies to RDS restores only, not %s", resourceType)
+	case len(opts.SecurityGroupIDs) > 0:
+		return fmt.Errorf("security groups apply to RDS restores only, not %s", resourceType)
+	}
+	return nil
+}
+
+// ValidateOptions returns an error if the restore m describes, as resolved
+// and before ApplyOptions, cannot be started with opts: options its type
+// does not take, or ones its parameters rule out, e.g. a cluster suffix
+// that makes the identifier too long.
+func (m *RestoreMetadata) ValidateOptions(opts RestoreOptions) error {
+	if err := ValidateRestoreOptions(m.ResourceType, opts); err != nil {
+		return err
+	}
+	rt := restoreTypeFor(m.ResourceType)
+	if rt.metadata == nil || rt.options == nil {
+		return nil
+	}
+	return rt.options(rt.metadata(m), opts)
+}
@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValidateRestoreOptions(t *testing.T) {
+	rds := RestoreOptions{ClusterSuffix: "verify", SubnetGroup: "isolated", SecurityGroupIDs: []string{"sg-1"}, KMSKeyID: "alias/k"}
+	if err := ValidateRestoreOptions("RDS", rds); err != nil {
+		t.Errorf("RDS should take every option: %v", err)
+	}
+	for _, rt := range []string{"EFS", "DynamoDB"} {
+		if err := ValidateRestoreOptions(rt, RestoreOptions{KMSKeyID: "alias/k", CopyTags: true}); err != nil {
+			t.Errorf("%s should take a KMS key and tag copy: %v", rt, err)
+		}
+		for _, opts := range []RestoreOptions{{ClusterSuffix: "verify"}, {SubnetGroup: "isolated"}, {SecurityGroupIDs: []string{"sg-1"}}} {
+			if err := ValidateRestoreOptions(rt, opts); err == nil {
+				t.Errorf("%s should refuse %+v", rt, opts)
+			}
+		}
+	}
+}
+
+func TestRestoreMetadata_ValidateOptions(t *testing.T) {
+	meta := RestoreMetadata{ResourceType: "RDS", ClusterID: "openemr-db"}
+	if err := meta.ValidateOptions(RestoreOptions{ClusterSuffix: "verify"}); err != nil {
+		t.Errorf("valid suffix refused: %v", err)
+	}
+	long := RestoreMetadata{ResourceType: "RDS", ClusterID: "openemr-db-with-a-rather-long-cluster-identifier-x"}
+	if err := long.ValidateOptions(RestoreOptions{ClusterSuffix: "verify-restore"}); err == nil {
+		t.Error("a suffix making the identifier too long should be refused")
+	}
+}
+
+func TestStartRestoreJob_UnregisteredType(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	rp := RecoveryPoint{RecoveryPointARN: "arn:aws:backup:us-west-2:123456789012:recovery-point:ddb-1", ResourceType: "DynamoDB", ResourceID: "sessions"}
+
+	meta, err := c.GetRestoreMetadata(context.Background(), rp, fx.Stacks[0].Name)
+	if err != nil {
+		t.Fatalf("GetRestoreMetadata: %v", err)
+	}
+	if meta.ClusterID != "" || meta.Encrypted {
+		t.Errorf("a type without restore parameters should preview none: %+v", meta)
+	}
+	if _, err := c.StartRestoreJob(context.Background(), rp, fx.Stacks[0].Name, fx.Vaults[0], RestoreOptions{SubnetGroup: "isolated"}); err == nil {
+		t.Error("an RDS option on a DynamoDB restore should be refused")
+	}
+}
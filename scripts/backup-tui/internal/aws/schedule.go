@@ -0,0 +1,201 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements human-readable backup rule schedules: AWS Backup's
+// cron(...) and rate(...) expressions rendered as e.g. "Daily at 05:00 UTC
+// / 22:00 PDT", in the rule's timezone and the operator's.
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// PlanSchedule is when a backup rule runs.
+type PlanSchedule struct {
+	RuleName   string
+	Expression string // e.g. "cron(0 5 ? * * *)" or "rate(12 hours)"
+	Timezone   string // IANA name the expression is evaluated in; empty means UTC
+}
+
+// cronParser parses the first five fields of an AWS cron expression, which
+// has a sixth (year) field and numbers weekdays from 1 (Sunday).
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.DowOptional)
+
+// rateExpr matches an AWS rate expression, e.g. "rate(12 hours)".
+var rateExpr = regexp.MustCompile(`^rate\((\d+) (minutes?|hours?|days?)\)$`)
+
+// digits matches the numbers in a cron field.
+var digits = regexp.MustCompile(`\d+`)
+
+// starBit is set by the cron parser in a field that was "*" or "?".
+const starBit = 1 << 63
+
+var (
+	weekdayNames = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	monthNames   = []string{"", "Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+)
+
+// Describe renders the schedule as text, e.g. "Daily at 05:00 UTC / 22:00
+// PDT". The time of the next run at or after now is also given in local
+// when that zone differs from the rule's. Expressions that do not read well
+// as text (year restrictions, L, W, #) are returned as they are, with the
+// rule's timezone.
+func (s PlanSchedule) Describe(now time.Time, local *time.Location) string {
+	tz := s.Timezone
+	if tz == "" {
+		tz = "UTC"
+	}
+	raw := s.Expression + " " + tz
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return raw
+	}
+
+	if m := rateExpr.FindStringSubmatch(s.Expression); m != nil {
+		n, unit := m[1], strings.TrimSuffix(m[2], "s")
+		if n == "1" {
+			return "Every " + unit
+		}
+		return "Every " + n + " " + unit + "s"
+	}
+
+	inner, ok := strings.CutPrefix(s.Expression, "cron(")
+	if !ok || !strings.HasSuffix(inner, ")") {
+		return raw
+	}
+	fields := strings.Fields(strings.TrimSuffix(inner, ")"))
+	if len(fields) != 6 || (fields[5] != "*" && fields[5] != "?") || strings.ContainsAny(fields[2]+fields[4], "LW#") {
+		return raw
+	}
+	dow, ok := cronWeekdays(fields[4])
+	if !ok {
+		return raw
+	}
+	spec := strings.Join([]string{fields[0], fields[1], strings.ReplaceAll(fields[2], "?", "*"), fields[3], dow}, " ")
+	parsed, err := cronParser.Parse(spec)
+	if err != nil {
+		return raw
+	}
+	sched, ok := parsed.(*cron.SpecSchedule)
+	if !ok {
+		return raw
+	}
+	sched.Location = loc
+
+	days, ok := scheduleDays(sched)
+	if !ok {
+		return raw
+	}
+	minutes, hours := setBits(sched.Minute, 0, 59), setBits(sched.Hour, 0, 23)
+	switch {
+	case len(minutes) == 1 && len(hours) == 1:
+		next := sched.Next(now.In(loc))
+		text := fmt.Sprintf("%s at %s", days, next.Format("15:04 MST"))
+		if local != nil {
+			there := next.In(local)
+			if there.Format("MST -0700") != next.Format("MST -0700") {
+				if days != "Daily" && there.Weekday() != next.Weekday() {
+					text += " / " + there.Format("Mon 15:04 MST")
+				} else {
+					text += " / " + there.Format("15:04 MST")
+				}
+			}
+		}
+		return text
+	case len(minutes) == 1 && sched.Hour&starBit != 0:
+		if days == "Daily" {
+			return fmt.Sprintf("Hourly at :%02d", minutes[0])
+		}
+		return fmt.Sprintf("%s, hourly at :%02d", days, minutes[0])
+	case len(minutes)*len(hours) <= 4:
+		var times []string
+		for _, h := range hours {
+			for _, m := range minutes {
+				times = append(times, fmt.Sprintf("%02d:%02d", h, m))
+			}
+		}
+		return fmt.Sprintf("%s at %s %s", days, strings.Join(times, ", "), sched.Next(now.In(loc)).Format("MST"))
+	}
+	return raw
+}
+
+// cronWeekdays converts an AWS day-of-week field, numbered 1 (Sunday) to 7,
+// to the parser's numbering from 0. Names are left as they are.
+func cronWeekdays(field string) (string, bool) {
+	if field == "?" || field == "*" {
+		return "*", true
+	}
+	if strings.Contains(field, "/") {
+		return "", false
+	}
+	ok := true
+	converted := digits.ReplaceAllStringFunc(field, func(d string) string {
+		n, err := strconv.Atoi(d)
+		if err != nil || n < 1 || n > 7 {
+			ok = false
+			return d
+		}
+		return strconv.Itoa(n - 1)
+	})
+	return converted, ok
+}
+
+// scheduleDays describes the days a schedule runs on, e.g. "Daily",
+// "Weekdays", "Weekly on Sun", or "Monthly on day 1 in Jan, Jul". It
+// reports false when both the day of month and day of week are restricted,
+// which cron reads as either.
+func scheduleDays(s *cron.SpecSchedule) (string, bool) {
+	anyDom, anyDow := s.Dom&starBit != 0, s.Dow&starBit != 0
+	var days string
+	switch {
+	case anyDom && anyDow:
+		days = "Daily"
+	case anyDom:
+		dows := setBits(s.Dow, 0, 6)
+		if fmt.Sprint(dows) == "[1 2 3 4 5]" {
+			days = "Weekdays"
+			break
+		}
+		names := make([]string, len(dows))
+		for i, d := range dows {
+			names[i] = weekdayNames[d]
+		}
+		days = "Weekly on " + strings.Join(names, ", ")
+	case anyDow:
+		doms := setBits(s.Dom, 1, 31)
+		nums := make([]string, len(doms))
+		for i, d := range doms {
+			nums[i] = strconv.Itoa(d)
+		}
+		days = "Monthly on day " + strings.Join(nums, ", ")
+	default:
+		return "", false
+	}
+	if s.Month&starBit == 0 {
+		months := setBits(s.Month, 1, 12)
+		names := make([]string, len(months))
+		for i, mo := range months {
+			names[i] = monthNames[mo]
+		}
+		if days == "Daily" {
+			days = "Every day"
+		}
+		days += " in " + strings.Join(names, ", ")
+	}
+	return days, true
+}
+
+// setBits returns the values from lo to hi whose bits are set in mask.
+func setBits(mask uint64, lo, hi int) []int {
+	var values []int
+	for v := lo; v <= hi; v++ {
+		if mask&(1<<uint(v)) != 0 {
+			values = append(values, v)
+		}
+	}
+	return values
+}
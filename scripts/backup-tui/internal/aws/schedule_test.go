@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanScheduleDescribe(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("no timezone database: %v", err)
+	}
+	summer := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr, tz string
+		local    *time.Location
+		want     string
+	}{
+		{"cron(0 5 ? * * *)", "", la, "Daily at 05:00 UTC / 22:00 PDT"},
+		{"cron(0 5 ? * * *)", "UTC", time.UTC, "Daily at 05:00 UTC"},
+		{"cron(30 1 ? * 2 *)", "", la, "Weekly on Mon at 01:30 UTC / Sun 18:30 PDT"},
+		{"cron(0 3 ? * MON-FRI *)", "America/Los_Angeles", la, "Weekdays at 03:00 PDT"},
+		{"cron(0 5 1 * ? *)", "", nil, "Monthly on day 1 at 05:00 UTC"},
+		{"cron(0 0,12 * * ? *)", "", nil, "Daily at 00:00, 12:00 UTC"},
+		{"cron(15 * * * ? *)", "", nil, "Hourly at :15"},
+		{"cron(0 5 ? 1,7 * *)", "", nil, "Every day in Jan, Jul at 05:00 UTC"},
+		{"rate(12 hours)", "", la, "Every 12 hours"},
+		{"rate(1 day)", "", la, "Every day"},
+		// Left as they are
+		{"cron(0 5 L * ? *)", "", la, "cron(0 5 L * ? *) UTC"},
+		{"cron(0 5 ? * * 2027)", "Europe/Berlin", la, "cron(0 5 ? * * 2027) Europe/Berlin"},
+		{"cron(0 5 ? * * *)", "Not/AZone", la, "cron(0 5 ? * * *) Not/AZone"},
+	}
+	for _, tt := range tests {
+		s := PlanSchedule{Expression: tt.expr, Timezone: tt.tz}
+		if got := s.Describe(summer, tt.local); got != tt.want {
+			t.Errorf("Describe(%q, %q) = %q, want %q", tt.expr, tt.tz, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements the backup selection details of the stack's plan:
+// the resource ARNs each selection includes and excludes, its tag
+// conditions, and the IAM role it backs up with, so it is clear why a
+// resource is or is not being backed up.
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+)
+
+// SelectionCondition is a tag condition of a backup selection.
+type SelectionCondition struct {
+	Type  string // "StringEquals", "StringNotEquals", "StringLike", or "StringNotLike"
+	Key   string // Tag key, e.g. "backup" or "aws:ResourceTag/backup"
+	Value string
+}
+
+// String formats the condition, e.g. "backup = daily" or "env like prod*".
+func (c SelectionCondition) String() string {
+	op := map[string]string{
+		"StringEquals":    "=",
+		"StringNotEquals": "≠",
+		"StringLike":      "like",
+		"StringNotLike":   "not like",
+	}[c.Type]
+	if op == "" {
+		op = c.Type
+	}
+	return fmt.Sprintf("%s %s %s", c.Key, op, c.Value)
+}
+
+// BackupSelection is a backup selection of a plan: the resources it backs
+// up and the IAM role AWS Backup uses for them.
+type BackupSelection struct {
+	PlanID       string
+	PlanName     string
+	ID           string
+	Name         string
+	RoleARN      string
+	Resources    []string             // ARNs, "*" wildcards allowed
+	NotResources []string             // ARNs excluded from Resources and tags
+	Tags         []SelectionCondition // ListOfTags: a resource matching any one is selected
+	Conditions   []SelectionCondition // Conditions: a resource must match all of them
+}
+
+// SelectionMatch is how a backup selection treats a resource.
+type SelectionMatch int
+
+const (
+	SelectionNoMatch  SelectionMatch = iota // Neither listed nor excluded
+	SelectionIncluded                       // Listed in Resources
+	SelectionExcluded                       // Listed in NotResources
+	SelectionByTags                         // May be selected by its tags, which are not read here
+)
+
+// Match reports how the selection treats the resource with the given ARN.
+func (s BackupSelection) Match(arn string) SelectionMatch {
+	info := backupSelectionInfo{resources: s.Resources, notResources: s.NotResources}
+	switch {
+	case info.covers(arn):
+		return SelectionIncluded
+	case info.excludes(arn):
+		return SelectionExcluded
+	case len(s.Tags) > 0 || len(s.Conditions) > 0:
+		return SelectionByTags
+	}
+	return SelectionNoMatch
+}
+
+// PlanSelections returns the backup plan that backs up to vaultName (see
+// ResolvePlanRole) and its selections. When no plan targets the vault, the
+// fallback PlanRole is returned with no selections.
+func (c *BackupClient) PlanSelections(ctx context.Context, vaultName string) (*PlanRole, []BackupSelection, error) {
+	pr, err := c.ResolvePlanRole(ctx, vaultName, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pr.Fallback {
+		return pr, nil, nil
+	}
+	infos, err := c.listPlanSelections(ctx, pr.PlanID, pr.PlanName)
+	if err != nil {
+		return nil, nil, err
+	}
+	selections := make([]BackupSelection, 0, len(infos))
+	for _, info := range infos {
+		selections = append(selections, BackupSelection{
+			PlanID:       info.planID,
+			PlanName:     info.planName,
+			ID:           info.id,
+			Name:         info.name,
+			RoleARN:      info.roleARN,
+			Resources:    info.resources,
+			NotResources: info.notResources,
+			Tags:         info.tags,
+			Conditions:   info.conditions,
+		})
+	}
+	return pr, selections, nil
+}
+
+// excludes reports whether the selection's exclusions match arn.
+func (s backupSelectionInfo) excludes(arn string) bool {
+	for _, pattern := range s.notResources {
+		if arnMatches(pattern, arn) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagConditions converts a selection's ListOfTags.
+func tagConditions(tags []backuptypes.Condition) []SelectionCondition {
+	var conditions []SelectionCondition
+	for _, t := range tags {
+		conditions = append(conditions, SelectionCondition{
+			Type:  "StringEquals", // The only type ListOfTags supports
+			Key:   aws.ToString(t.ConditionKey),
+			Value: aws.ToString(t.ConditionValue),
+		})
+	}
+	return conditions
+}
+
+// selectionConditions converts a selection's Conditions.
+func selectionConditions(c *backuptypes.Conditions) []SelectionCondition {
+	if c == nil {
+		return nil
+	}
+	var conditions []SelectionCondition
+	for _, group := range []struct {
+		conditionType string
+		params        []backuptypes.ConditionParameter
+	}{
+		{"StringEquals", c.StringEquals},
+		{"StringNotEquals", c.StringNotEquals},
+		{"StringLike", c.StringLike},
+		{"StringNotLike", c.StringNotLike},
+	} {
+		for _, p := range group.params {
+			conditions = append(conditions, SelectionCondition{
+				Type:  group.conditionType,
+				Key:   aws.ToString(p.ConditionKey),
+				Value: aws.ToString(p.ConditionValue),
+			})
+		}
+	}
+	return conditions
+}
@@ -0,0 +1,66 @@
+package aws
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPlanSelections_Simulated(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	fx.Plans[0].Selections = append(fx.Plans[0].Selections, FixtureSelection{
+		Name:       "conditions",
+		IAMRoleARN: "arn:aws:iam::123456789012:role/other",
+		Resources:  []string{"*"},
+		Conditions: []FixtureCondition{
+			{Key: "aws:ResourceTag/app", Value: "openemr"},
+			{Type: "StringNotLike", Key: "aws:ResourceTag/env", Value: "dev*"},
+		},
+	})
+	c := NewSimulatedBackupClient(fx)
+
+	plan, selections, err := c.PlanSelections(context.Background(), fx.Plans[0].Vaults[0])
+	if err != nil {
+		t.Fatalf("PlanSelections: %v", err)
+	}
+	if plan.PlanName != fx.Plans[0].Name || len(selections) != 3 {
+		t.Fatalf("got plan %+v with %d selections", plan, len(selections))
+	}
+
+	tagged := selections[1]
+	if len(tagged.Tags) != 1 || tagged.Tags[0].String() != "backup = daily" {
+		t.Errorf("tagged selection should select by backup=daily, got %+v", tagged.Tags)
+	}
+	conds := selections[2].Conditions
+	if len(conds) != 2 || conds[0].String() != "aws:ResourceTag/app = openemr" || conds[1].String() != "aws:ResourceTag/env not like dev*" {
+		t.Errorf("conditions = %+v", conds)
+	}
+
+	plan, selections, err = c.PlanSelections(context.Background(), "no-plan-vault")
+	if err != nil || !plan.Fallback || selections != nil {
+		t.Errorf("a vault without a plan should report the fallback and no selections, got %+v, %v, %v", plan, selections, err)
+	}
+}
+
+func TestBackupSelection_Match(t *testing.T) {
+	cluster := "arn:aws:rds:us-west-2:123456789012:cluster:db"
+	fs := "arn:aws:elasticfilesystem:us-west-2:123456789012:file-system/fs-1"
+
+	s := BackupSelection{Resources: []string{"arn:aws:rds:*"}}
+	if got := s.Match(cluster); got != SelectionIncluded {
+		t.Errorf("wildcard should include the cluster, got %v", got)
+	}
+	if got := s.Match(fs); got != SelectionNoMatch {
+		t.Errorf("file system is not listed, got %v", got)
+	}
+
+	s = BackupSelection{
+		Tags:         []SelectionCondition{{Type: "StringEquals", Key: "backup", Value: "daily"}},
+		NotResources: []string{"arn:aws:elasticfilesystem:*"},
+	}
+	if got := s.Match(fs); got != SelectionExcluded {
+		t.Errorf("excluded file system, got %v", got)
+	}
+	if got := s.Match(cluster); got != SelectionByTags {
+		t.Errorf("cluster may be selected by tags, got %v", got)
+	}
+}
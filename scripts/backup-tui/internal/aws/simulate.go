@@ -0,0 +1,2948 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements simulation mode: a BackupClient backed by recorded
+// JSON fixtures instead of AWS, so teams can rehearse the full restore
+// workflow (discovery, confirmation, job progress) during DR training
+// without touching a real account.
+package aws
+
+import (
+	"context"
+	_ "embed" // Default simulation fixtures are embedded in the binary
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cttypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	efstypes "github.com/aws/aws-sdk-go-v2/service/efs/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	rdsdatatypes "github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/smithy-go"
+)
+
+//go:embed fixtures/default.json
+var defaultFixtures []byte
+
+// fixtureJobHistory is how much job history RecordFixtures captures.
+const fixtureJobHistory = 30 * 24 * time.Hour
+
+// recordedStackResourceTypes are stack resources RecordFixtures records in
+// addition to the protected resources.
+var recordedStackResourceTypes = map[string]bool{
+	"AWS::EC2::VPC":            true,
+	"AWS::ECS::TaskDefinition": true,
+	"AWS::ECS::Service":        true,
+}
+
+// fixtureTaskDefinitions is how many task definition revisions RecordFixtures
+// captures.
+const fixtureTaskDefinitions = 20
+
+// Fixtures is the on-disk format for simulation mode. It captures the subset
+// of AWS state the TUI reads: stacks, vaults, recovery points, backup plans,
+// RDS clusters, EFS file systems, and how simulated restore jobs should behave.
+type Fixtures struct {
+	AccountID       string                            `json:"accountId"`
+	Region          string                            `json:"region"`
+	Stacks          []FixtureStack                    `json:"stacks"`
+	Vaults          []string                          `json:"vaults"`
+	VaultTypes      map[string]string                 `json:"vaultTypes,omitempty"` // Keyed by vault name; BACKUP_VAULT when absent
+	VaultLocks      map[string]FixtureVaultLock       `json:"vaultLocks,omitempty"` // Keyed by vault name; unlocked when absent
+	RecoveryPoints  map[string][]FixtureRecoveryPoint `json:"recoveryPoints"`       // Keyed by vault name
+	Plans           []FixturePlan                     `json:"plans"`
+	Clusters        []FixtureCluster                  `json:"clusters"`
+	FileSystems     []FixtureFileSystem               `json:"fileSystems,omitempty"`
+	Restore         FixtureRestore                    `json:"restore"`
+	Jobs            []FixtureJob                      `json:"jobs,omitempty"` // Job history for reports
+	TaskDefinitions []FixtureTaskDefinition           `json:"taskDefinitions,omitempty"`
+	Deployments     []FixtureDeployment               `json:"deployments,omitempty"`
+	KMSAliases      map[string]string                 `json:"kmsAliases,omitempty"` // Alias name to target key ID
+	SecurityGroups  []FixtureSecurityGroup            `json:"securityGroups,omitempty"`
+	SubnetGroups    []FixtureSubnetGroup              `json:"subnetGroups,omitempty"`
+	TrailEvents     []FixtureTrailEvent               `json:"trailEvents,omitempty"` // CloudTrail history of AWS Backup calls
+	Regions         map[string]FixtureRegion          `json:"regions,omitempty"`     // Other regions of the account, e.g. DR regions
+	EngineVersions  []FixtureEngineVersion            `json:"engineVersions,omitempty"`
+}
+
+// FixtureRegion is the vaults and recovery points of another region of the
+// account, e.g. copies made there by a cross-region copy rule.
+type FixtureRegion struct {
+	Vaults         []string                          `json:"vaults"`
+	RecoveryPoints map[string][]FixtureRecoveryPoint `json:"recoveryPoints"` // Keyed by vault name
+}
+
+// FixtureSecurityGroup is a VPC security group.
+type FixtureSecurityGroup struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	VPCID       string `json:"vpcId"`
+}
+
+// FixtureVaultLock is the Vault Lock retention range of a vault.
+type FixtureVaultLock struct {
+	MinRetentionDays int64 `json:"minRetentionDays,omitempty"`
+	MaxRetentionDays int64 `json:"maxRetentionDays,omitempty"`
+}
+
+// FixtureSubnetGroup is a DB subnet group and the Availability Zones of
+// its subnets.
+type FixtureSubnetGroup struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	VPCID       string          `json:"vpcId"`
+	Subnets     []FixtureSubnet `json:"subnets"`
+}
+
+// FixtureSubnet is a subnet of a DB subnet group.
+type FixtureSubnet struct {
+	ID     string `json:"id"`
+	AZ     string `json:"az"`
+	Status string `json:"status,omitempty"` // Defaults to "Active"
+}
+
+// FixtureStack is a CloudFormation stack, its outputs, and the resources
+// that should be backed up.
+type FixtureStack struct {
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`
+	Created   *time.Time             `json:"created,omitempty"`
+	Updated   *time.Time             `json:"updated,omitempty"`
+	Outputs   map[string]string      `json:"outputs"`
+	Resources []FixtureStackResource `json:"resources,omitempty"`
+}
+
+// FixtureStackResource is a stack resource, e.g. an RDS cluster or EFS file system.
+type FixtureStackResource struct {
+	Type       string `json:"type"` // CloudFormation type, e.g. "AWS::EFS::FileSystem"
+	LogicalID  string `json:"logicalId"`
+	PhysicalID string `json:"physicalId"`
+}
+
+// FixtureRecoveryPoint is a recovery point in a vault. Either CreationDate or
+// AgeHours may be set; AgeHours is relative to when the fixtures are loaded,
+// which keeps shared training fixtures looking fresh.
+type FixtureRecoveryPoint struct {
+	RecoveryPointARN string            `json:"recoveryPointArn"`
+	ResourceARN      string            `json:"resourceArn"`
+	ResourceType     string            `json:"resourceType"`
+	Status           string            `json:"status"`
+	CreationDate     *time.Time        `json:"creationDate,omitempty"`
+	AgeHours         float64           `json:"ageHours,omitempty"`
+	BackupSizeBytes  int64             `json:"backupSizeBytes"`
+	Lifecycle        Lifecycle         `json:"lifecycle,omitzero"`
+	OnDemand         bool              `json:"onDemand,omitempty"` // Created outside the vault's backup plan
+	Tags             map[string]string `json:"tags,omitempty"`
+	EngineVersion    string            `json:"engineVersion,omitempty"`  // Of an RDS backup; the cluster's when empty
+	SourceVaultARN   string            `json:"sourceVaultArn,omitempty"` // Vault a copied-in recovery point was copied from
+}
+
+// FixtureTrailEvent is a CloudTrail record of an AWS Backup call on a
+// recovery point. EventTime or AgeHours may be set as for recovery points.
+type FixtureTrailEvent struct {
+	EventName        string     `json:"eventName"` // e.g. "DeleteRecoveryPoint"
+	Username         string     `json:"username"`
+	Vault            string     `json:"vault"`
+	RecoveryPointARN string     `json:"recoveryPointArn"`
+	ErrorCode        string     `json:"errorCode,omitempty"` // Set for a refused call
+	EventTime        *time.Time `json:"eventTime,omitempty"`
+	AgeHours         float64    `json:"ageHours,omitempty"`
+}
+
+// FixturePlan is a backup plan, the vaults its rules target and copy to,
+// and its selections.
+type FixturePlan struct {
+	ID         string             `json:"id"`
+	Name       string             `json:"name"`
+	VersionID  string             `json:"versionId"`
+	Vaults     []string           `json:"vaults"`
+	CopyTo     []string           `json:"copyTo,omitempty"`   // Vault names, e.g. an air-gapped vault
+	Schedule   string             `json:"schedule,omitempty"` // Rule schedule expression, e.g. "cron(0 5 ? * * *)"
+	Timezone   string             `json:"timezone,omitempty"` // Rule schedule timezone; empty means UTC
+	Selections []FixtureSelection `json:"selections"`
+}
+
+// FixtureSelection is a backup selection, the IAM role it uses, the
+// resource ARNs (wildcards allowed) it includes and excludes, and the tag
+// conditions it selects resources by.
+type FixtureSelection struct {
+	ID           string             `json:"id,omitempty"` // Defaults to Name
+	Name         string             `json:"name"`
+	IAMRoleARN   string             `json:"iamRoleArn"`
+	Resources    []string           `json:"resources,omitempty"`
+	NotResources []string           `json:"notResources,omitempty"`
+	Tags         []FixtureCondition `json:"tags,omitempty"`       // ListOfTags (StringEquals only)
+	Conditions   []FixtureCondition `json:"conditions,omitempty"` // Conditions
+}
+
+// FixtureCondition is a tag condition of a backup selection.
+type FixtureCondition struct {
+	Type  string `json:"type,omitempty"` // "StringEquals" (default), "StringNotEquals", "StringLike", or "StringNotLike"
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// FixtureJob is a backup, restore, or copy job in the job history. Vault is
+// the backup vault (the source vault for copies); CreationDate or AgeHours
+// may be set as for recovery points.
+type FixtureJob struct {
+	Kind             string     `json:"kind"` // "backup", "restore", or "copy"
+	ID               string     `json:"id"`
+	Vault            string     `json:"vault,omitempty"`
+	DestinationVault string     `json:"destinationVault,omitempty"` // Copy jobs only
+	ResourceARN      string     `json:"resourceArn"`
+	ResourceType     string     `json:"resourceType"`
+	State            string     `json:"state"`
+	StatusMessage    string     `json:"statusMessage,omitempty"`
+	CreationDate     *time.Time `json:"creationDate,omitempty"`
+	AgeHours         float64    `json:"ageHours,omitempty"`
+	DurationMinutes  float64    `json:"durationMinutes,omitempty"`   // Zero while running
+	RecoveryPointARN string     `json:"recoveryPointArn,omitempty"`  // Restore jobs only
+	SourceARN        string     `json:"sourceResourceArn,omitempty"` // Restore jobs only: resource the recovery point was taken from
+	ValidationStatus string     `json:"validationStatus,omitempty"`  // Restore tests only
+}
+
+// FixtureTaskDefinition is an ECS task definition revision. RegisteredAt or
+// AgeHours may be set as for recovery points.
+type FixtureTaskDefinition struct {
+	Family       string             `json:"family"`
+	Revision     int32              `json:"revision"`
+	RegisteredAt *time.Time         `json:"registeredAt,omitempty"`
+	AgeHours     float64            `json:"ageHours,omitempty"`
+	Containers   []FixtureContainer `json:"containers"`
+}
+
+// FixtureContainer is a container in a fixture task definition.
+type FixtureContainer struct {
+	Name        string            `json:"name"`
+	Image       string            `json:"image"`
+	Environment map[string]string `json:"environment,omitempty"`
+}
+
+// FixtureDeployment is an ECS service deployment of a task definition
+// revision. Service is the service ARN, as in the stack's AWS::ECS::Service
+// resource; CreatedAt or AgeHours may be set as for recovery points.
+type FixtureDeployment struct {
+	Service         string     `json:"service"`
+	Family          string     `json:"family"`
+	Revision        int32      `json:"revision"`
+	Status          string     `json:"status"`
+	StatusReason    string     `json:"statusReason,omitempty"`
+	CreatedAt       *time.Time `json:"createdAt,omitempty"`
+	AgeHours        float64    `json:"ageHours,omitempty"`
+	DurationMinutes float64    `json:"durationMinutes,omitempty"` // Zero while in progress
+}
+
+// FixtureCluster is an RDS cluster's network configuration and health.
+// Status defaults to "available" when empty.
+type FixtureCluster struct {
+	ID               string            `json:"id"`
+	SubnetGroup      string            `json:"subnetGroup"`
+	SecurityGroups   []string          `json:"securityGroups"`
+	Status           string            `json:"status,omitempty"`
+	Engine           string            `json:"engine,omitempty"`
+	EngineVersion    string            `json:"engineVersion,omitempty"`
+	StorageType      string            `json:"storageType,omitempty"`
+	StorageEncrypted bool              `json:"storageEncrypted,omitempty"`
+	ServerlessMinACU float64           `json:"serverlessMinAcu,omitempty"`
+	ServerlessMaxACU float64           `json:"serverlessMaxAcu,omitempty"`
+	KMSKeyID         string            `json:"kmsKeyId,omitempty"`
+	Instances        []FixtureInstance `json:"instances,omitempty"`
+	Failovers        []FixtureEvent    `json:"failovers,omitempty"`
+	Tags             map[string]string `json:"tags,omitempty"`
+}
+
+// FixtureEngineVersion is a database engine version RDS offers in the
+// region. Versions of an engine without any fixture versions are all
+// available.
+type FixtureEngineVersion struct {
+	Engine  string `json:"engine"`
+	Version string `json:"version"`
+	Status  string `json:"status,omitempty"`  // "available" (default) or "deprecated"
+	Default bool   `json:"default,omitempty"` // The version new clusters get when none is specified
+}
+
+// FixtureInstance is a DB instance in a fixture cluster. Status defaults to
+// "available" when empty.
+type FixtureInstance struct {
+	ID               string `json:"id"`
+	Class            string `json:"class"`
+	Writer           bool   `json:"writer,omitempty"`
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+	Status           string `json:"status,omitempty"`
+}
+
+// FixtureEvent is an RDS cluster event. Date or AgeHours may be set as for
+// recovery points.
+type FixtureEvent struct {
+	Date     *time.Time `json:"date,omitempty"`
+	AgeHours float64    `json:"ageHours,omitempty"`
+	Message  string     `json:"message"`
+}
+
+// FixtureFileSystem is an EFS file system's configuration. LifeCycleState
+// defaults to "available" when empty.
+type FixtureFileSystem struct {
+	ID               string               `json:"id"`
+	Name             string               `json:"name,omitempty"`
+	SizeBytes        int64                `json:"sizeBytes"`
+	PerformanceMode  string               `json:"performanceMode,omitempty"`
+	ThroughputMode   string               `json:"throughputMode,omitempty"`
+	ProvisionedMiBps float64              `json:"provisionedMiBps,omitempty"`
+	Encrypted        bool                 `json:"encrypted"`
+	Lifecycle        map[string]string    `json:"lifecycle,omitempty"` // e.g. {"TransitionToIA": "AFTER_30_DAYS"}
+	MountTargets     []FixtureMountTarget `json:"mountTargets,omitempty"`
+	Tags             map[string]string    `json:"tags,omitempty"`
+	LifeCycleState   string               `json:"lifeCycleState,omitempty"`
+}
+
+// FixtureMountTarget is an EFS mount target. LifeCycleState defaults to
+// "available" when empty.
+type FixtureMountTarget struct {
+	ID               string `json:"id"`
+	AvailabilityZone string `json:"availabilityZone"`
+	SubnetID         string `json:"subnetId"`
+	IPAddress        string `json:"ipAddress"`
+	LifeCycleState   string `json:"lifeCycleState,omitempty"`
+}
+
+// FixtureRestore controls simulated restore jobs: how long they take and
+// whether they end COMPLETED, FAILED, or ABORTED.
+type FixtureRestore struct {
+	DurationSeconds int    `json:"durationSeconds"`
+	Outcome         string `json:"outcome"`
+	StatusMessage   string `json:"statusMessage"`
+	// TableRows are the rows the simulated Data API counts in each table
+	// of a restored cluster, by table name.
+	TableRows map[string]int64 `json:"tableRows,omitempty"`
+}
+
+// LoadFixtures reads simulation fixtures from path. An empty path loads the
+// built-in sample environment.
+func LoadFixtures(path string) (*Fixtures, error) {
+	data := defaultFixtures
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path) //nolint:gosec // path is an operator-supplied fixture file
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixtures: %w", err)
+		}
+	}
+
+	var fx Fixtures
+	if err := json.Unmarshal(data, &fx); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+	if fx.AccountID == "" {
+		fx.AccountID = "123456789012"
+	}
+	if fx.Region == "" {
+		fx.Region = "us-west-2"
+	}
+	if fx.Restore.DurationSeconds <= 0 {
+		fx.Restore.DurationSeconds = 60
+	}
+	if fx.Restore.Outcome == "" {
+		fx.Restore.Outcome = "COMPLETED"
+	}
+	return &fx, nil
+}
+
+// NewSimulatedBackupClient creates a BackupClient whose AWS service clients
+// are served from fixtures. No AWS credentials or network access are used.
+func NewSimulatedBackupClient(fx *Fixtures) *BackupClient {
+	return newSimulatedClient(newSimulatedAWS(fx))
+}
+
+// newSimulatedClient creates a BackupClient served by sim.
+func newSimulatedClient(sim *simulatedAWS) *BackupClient {
+	return &BackupClient{
+		client:    sim,
+		cfn:       sim,
+		rds:       sim,
+		efs:       simulatedEFS{sim},
+		ecs:       sim,
+		kms:       sim,
+		ec2:       sim,
+		ses:       sim,
+		sns:       sim,
+		trail:     sim,
+		ddb:       sim,
+		rdsData:   sim,
+		region:    sim.fx.Region,
+		accountID: sim.fx.AccountID,
+		callerARN: fmt.Sprintf("arn:aws:iam::%s:user/simulated-operator", sim.fx.AccountID),
+		simulated: true,
+	}
+}
+
+// simulatedRecoveryClient returns a client for the simulated recovery
+// account of target, as if its role had been assumed.
+func (c *BackupClient) simulatedRecoveryClient(target CrossAccountTarget) *BackupClient {
+	sim := c.client.(*simulatedAWS)
+	recovery := newSimulatedClient(sim.recoveryAccount(target.AccountID, target.Region, target.VaultName))
+	recovery.callerARN = fmt.Sprintf("arn:aws:sts::%s:assumed-role/%s/%s", target.AccountID, resourceName(target.RoleARN), crossAccountSession)
+	return recovery
+}
+
+// Simulated reports whether the client is backed by simulation fixtures.
+func (c *BackupClient) Simulated() bool {
+	return c.simulated
+}
+
+// simulatedAWS implements BackupAPI, CloudFormationAPI, RDSAPI, EFSAPI, ECSAPI, KMSAPI, EC2API, SESAPI, SNSAPI, CloudTrailAPI, DynamoDBAPI, and RDSDataAPI from fixtures.
+type simulatedAWS struct {
+	fx       *Fixtures
+	loadedAt time.Time
+	now      func() time.Time
+
+	mu      sync.Mutex
+	jobs    map[string]*simulatedJob
+	backups map[string]*simulatedBackup
+	exports map[string]*simulatedExport
+	clones  map[string]*simulatedClone
+	holds   []*simulatedLegalHold
+	sent    []SummaryMessage // Summaries "sent" by email or SNS; nothing is delivered
+	copies  map[string]*simulatedCopy
+	tags    map[string]map[string]string                    // Tags added to resources other than recovery points, by ARN or ID
+	deleted map[string]bool                                 // File systems created by restores and since deleted, by ID
+	journal map[string][]map[string]ddbtypes.AttributeValue // Journal items by table, in the order recorded
+	dataAPI map[string]bool                                 // Clusters with the Data API enabled, by ARN
+	nextID  int
+
+	recovery map[string]*simulatedAWS // Recovery accounts by ID, created when a role is assumed
+	regions  map[string]*simulatedAWS // Other regions of the account by name, created on first use
+}
+
+// simulatedLegalHold is a legal hold created in simulation mode.
+type simulatedLegalHold struct {
+	hold      backuptypes.LegalHold
+	selection *backuptypes.RecoveryPointSelection
+}
+
+// simulatedClone is an Aurora clone created in simulation mode.
+type simulatedClone struct {
+	source    FixtureCluster
+	instances map[string]string // Instance ID to class
+	started   time.Time
+}
+
+// simulatedExport is a snapshot export task started in simulation mode.
+type simulatedExport struct {
+	in      *rds.StartExportTaskInput
+	started time.Time
+}
+
+// simulatedBackup is an on-demand backup job started in simulation mode.
+type simulatedBackup struct {
+	in      *backup.StartBackupJobInput
+	started time.Time
+}
+
+// simulatedCopy is a copy job started in simulation mode.
+type simulatedCopy struct {
+	in        *backup.StartCopyJobInput
+	source    FixtureRecoveryPoint
+	dest      *simulatedAWS // Account of the destination vault
+	vault     string        // Destination vault name
+	started   time.Time
+	delivered bool // The copy has been added to the destination vault
+}
+
+// simulatedJob is a restore job started in simulation mode.
+type simulatedJob struct {
+	id               string
+	resourceType     string
+	recoveryPointARN string
+	sourceARN        string // Resource the recovery point was taken from
+	createdARN       string // Resource the restore creates, or restores into
+	started          time.Time
+}
+
+func newSimulatedAWS(fx *Fixtures) *simulatedAWS {
+	return &simulatedAWS{
+		fx:       fx,
+		loadedAt: time.Now(),
+		now:      time.Now,
+		jobs:     make(map[string]*simulatedJob),
+		backups:  make(map[string]*simulatedBackup),
+		exports:  make(map[string]*simulatedExport),
+		clones:   make(map[string]*simulatedClone),
+		copies:   make(map[string]*simulatedCopy),
+		tags:     make(map[string]map[string]string),
+		deleted:  make(map[string]bool),
+		journal:  make(map[string][]map[string]ddbtypes.AttributeValue),
+		dataAPI:  make(map[string]bool),
+		recovery: make(map[string]*simulatedAWS),
+		regions:  make(map[string]*simulatedAWS),
+	}
+}
+
+// notFound returns an API error shaped like the real service's.
+func notFound(format string, args ...any) error {
+	return &smithy.GenericAPIError{Code: "ResourceNotFoundException", Message: fmt.Sprintf(format, args...)}
+}
+
+// orDefault returns s, or def when s is empty.
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// --- CloudFormationAPI ---
+
+func (s *simulatedAWS) ListStacks(_ context.Context, in *cloudformation.ListStacksInput, _ ...func(*cloudformation.Options)) (*cloudformation.ListStacksOutput, error) {
+	out := &cloudformation.ListStacksOutput{}
+	for _, st := range s.fx.Stacks {
+		if len(in.StackStatusFilter) > 0 && !slices.Contains(in.StackStatusFilter, cfntypes.StackStatus(st.Status)) {
+			continue
+		}
+		out.StackSummaries = append(out.StackSummaries, cfntypes.StackSummary{
+			StackName:       aws.String(st.Name),
+			StackStatus:     cfntypes.StackStatus(st.Status),
+			CreationTime:    st.Created,
+			LastUpdatedTime: st.Updated,
+		})
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) DescribeStacks(_ context.Context, in *cloudformation.DescribeStacksInput, _ ...func(*cloudformation.Options)) (*cloudformation.DescribeStacksOutput, error) {
+	name := aws.ToString(in.StackName)
+	for _, st := range s.fx.Stacks {
+		if st.Name != name {
+			continue
+		}
+		stack := cfntypes.Stack{
+			StackId:     aws.String(fmt.Sprintf("arn:%s:cloudformation:%s:%s:stack/%s/sim-%s", partition(s.fx.Region), s.fx.Region, s.fx.AccountID, st.Name, strings.ToLower(st.Name))),
+			StackName:   aws.String(st.Name),
+			StackStatus: cfntypes.StackStatus(st.Status),
+		}
+		for k, v := range st.Outputs {
+			stack.Outputs = append(stack.Outputs, cfntypes.Output{OutputKey: aws.String(k), OutputValue: aws.String(v)})
+		}
+		return &cloudformation.DescribeStacksOutput{Stacks: []cfntypes.Stack{stack}}, nil
+	}
+	return nil, &smithy.GenericAPIError{Code: "ValidationError", Message: fmt.Sprintf("Stack with id %s does not exist", name)}
+}
+
+func (s *simulatedAWS) ListStackResources(_ context.Context, in *cloudformation.ListStackResourcesInput, _ ...func(*cloudformation.Options)) (*cloudformation.ListStackResourcesOutput, error) {
+	name := aws.ToString(in.StackName)
+	for _, st := range s.fx.Stacks {
+		if st.Name != name {
+			continue
+		}
+		out := &cloudformation.ListStackResourcesOutput{}
+		for _, r := range st.Resources {
+			out.StackResourceSummaries = append(out.StackResourceSummaries, cfntypes.StackResourceSummary{
+				ResourceType:       aws.String(r.Type),
+				LogicalResourceId:  aws.String(r.LogicalID),
+				PhysicalResourceId: aws.String(r.PhysicalID),
+			})
+		}
+		return out, nil
+	}
+	return nil, &smithy.GenericAPIError{Code: "ValidationError", Message: fmt.Sprintf("Stack with id %s does not exist", name)}
+}
+
+// --- ECSAPI ---
+
+// taskDefinitionARN returns the ARN of a fixture task definition revision.
+func (s *simulatedAWS) taskDefinitionARN(td FixtureTaskDefinition) string {
+	return fmt.Sprintf("arn:%s:ecs:%s:%s:task-definition/%s:%d", partition(s.fx.Region), s.fx.Region, s.fx.AccountID, td.Family, td.Revision)
+}
+
+func (s *simulatedAWS) ListTaskDefinitions(_ context.Context, in *ecs.ListTaskDefinitionsInput, _ ...func(*ecs.Options)) (*ecs.ListTaskDefinitionsOutput, error) {
+	defs := slices.Clone(s.fx.TaskDefinitions)
+	slices.SortFunc(defs, func(a, b FixtureTaskDefinition) int {
+		if a.Family != b.Family {
+			return strings.Compare(a.Family, b.Family)
+		}
+		if in.Sort == ecstypes.SortOrderDesc {
+			return int(b.Revision - a.Revision)
+		}
+		return int(a.Revision - b.Revision)
+	})
+	out := &ecs.ListTaskDefinitionsOutput{}
+	for _, td := range defs {
+		if strings.HasPrefix(td.Family, aws.ToString(in.FamilyPrefix)) {
+			out.TaskDefinitionArns = append(out.TaskDefinitionArns, s.taskDefinitionARN(td))
+		}
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) DescribeTaskDefinition(_ context.Context, in *ecs.DescribeTaskDefinitionInput, _ ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	family, revision := splitTaskDefinitionARN(aws.ToString(in.TaskDefinition))
+	var found *FixtureTaskDefinition
+	for i, td := range s.fx.TaskDefinitions {
+		// Without a revision, the latest revision of the family is returned
+		if td.Family == family && (revision == "" || fmt.Sprint(td.Revision) == revision) &&
+			(found == nil || td.Revision > found.Revision) {
+			found = &s.fx.TaskDefinitions[i]
+		}
+	}
+	if found == nil {
+		return nil, &smithy.GenericAPIError{Code: "ClientException", Message: "Unable to describe task definition."}
+	}
+
+	registered := s.loadedAt.Add(-time.Duration(found.AgeHours * float64(time.Hour)))
+	if found.RegisteredAt != nil {
+		registered = *found.RegisteredAt
+	}
+	td := &ecstypes.TaskDefinition{
+		TaskDefinitionArn: aws.String(s.taskDefinitionARN(*found)),
+		Family:            aws.String(found.Family),
+		Revision:          found.Revision,
+		RegisteredAt:      aws.Time(registered),
+		Status:            ecstypes.TaskDefinitionStatusActive,
+	}
+	for _, c := range found.Containers {
+		cd := ecstypes.ContainerDefinition{Name: aws.String(c.Name), Image: aws.String(c.Image)}
+		names := make([]string, 0, len(c.Environment))
+		for name := range c.Environment {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			cd.Environment = append(cd.Environment, ecstypes.KeyValuePair{Name: aws.String(name), Value: aws.String(c.Environment[name])})
+		}
+		td.ContainerDefinitions = append(td.ContainerDefinitions, cd)
+	}
+	return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: td}, nil
+}
+
+func (s *simulatedAWS) ListServiceDeployments(_ context.Context, in *ecs.ListServiceDeploymentsInput, _ ...func(*ecs.Options)) (*ecs.ListServiceDeploymentsOutput, error) {
+	out := &ecs.ListServiceDeploymentsOutput{}
+	for i, d := range s.fx.Deployments {
+		cluster, name := splitServiceARN(d.Service)
+		if name != aws.ToString(in.Service) || (in.Cluster != nil && cluster != aws.ToString(in.Cluster)) {
+			continue
+		}
+		created := s.loadedAt.Add(-time.Duration(d.AgeHours * float64(time.Hour)))
+		if d.CreatedAt != nil {
+			created = *d.CreatedAt
+		}
+		if in.CreatedAt != nil && in.CreatedAt.After != nil && created.Before(*in.CreatedAt.After) {
+			continue
+		}
+		brief := ecstypes.ServiceDeploymentBrief{
+			ServiceArn:               aws.String(d.Service),
+			ServiceDeploymentArn:     aws.String(fmt.Sprintf("arn:%s:ecs:%s:%s:service-deployment/%s/%s/sim-%04d", partition(s.fx.Region), s.fx.Region, s.fx.AccountID, cluster, name, i+1)),
+			TargetServiceRevisionArn: aws.String(s.serviceRevisionARN(i)),
+			Status:                   ecstypes.ServiceDeploymentStatus(d.Status),
+			CreatedAt:                aws.Time(created),
+			StartedAt:                aws.Time(created),
+		}
+		if d.StatusReason != "" {
+			brief.StatusReason = aws.String(d.StatusReason)
+		}
+		if d.DurationMinutes > 0 {
+			brief.FinishedAt = aws.Time(created.Add(time.Duration(d.DurationMinutes * float64(time.Minute))))
+		}
+		out.ServiceDeployments = append(out.ServiceDeployments, brief)
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) DescribeServiceRevisions(_ context.Context, in *ecs.DescribeServiceRevisionsInput, _ ...func(*ecs.Options)) (*ecs.DescribeServiceRevisionsOutput, error) {
+	out := &ecs.DescribeServiceRevisionsOutput{}
+	for _, arn := range in.ServiceRevisionArns {
+		for i, d := range s.fx.Deployments {
+			if s.serviceRevisionARN(i) != arn {
+				continue
+			}
+			rev := ecstypes.ServiceRevision{
+				ServiceRevisionArn: aws.String(arn),
+				ServiceArn:         aws.String(d.Service),
+				TaskDefinition:     aws.String(s.taskDefinitionARN(FixtureTaskDefinition{Family: d.Family, Revision: d.Revision})),
+			}
+			for _, td := range s.fx.TaskDefinitions {
+				if td.Family != d.Family || td.Revision != d.Revision {
+					continue
+				}
+				for _, c := range td.Containers {
+					rev.ContainerImages = append(rev.ContainerImages, ecstypes.ContainerImage{ContainerName: aws.String(c.Name), Image: aws.String(c.Image)})
+				}
+			}
+			out.ServiceRevisions = append(out.ServiceRevisions, rev)
+		}
+	}
+	return out, nil
+}
+
+// serviceRevisionARN returns the service revision ARN fixture deployment i
+// targets; each deployment gets its own revision.
+func (s *simulatedAWS) serviceRevisionARN(i int) string {
+	cluster, name := splitServiceARN(s.fx.Deployments[i].Service)
+	return fmt.Sprintf("arn:%s:ecs:%s:%s:service-revision/%s/%s/sim-%04d", partition(s.fx.Region), s.fx.Region, s.fx.AccountID, cluster, name, i+1)
+}
+
+// --- RDSAPI ---
+
+// DescribeDBClusters describes the fixture cluster or simulated clone with
+// the given identifier, or all of them without one, with the tags added
+// since.
+func (s *simulatedAWS) DescribeDBClusters(_ context.Context, in *rds.DescribeDBClustersInput, _ ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	id := aws.ToString(in.DBClusterIdentifier)
+	if id != "" {
+		cl, err := s.describeDBCluster(id)
+		if err != nil {
+			return nil, err
+		}
+		return &rds.DescribeDBClustersOutput{DBClusters: []rdstypes.DBCluster{cl}}, nil
+	}
+	ids := make([]string, 0, len(s.fx.Clusters))
+	for _, cl := range s.fx.Clusters {
+		ids = append(ids, cl.ID)
+	}
+	s.mu.Lock()
+	ids = append(ids, slices.Sorted(maps.Keys(s.clones))...)
+	s.mu.Unlock()
+	out := &rds.DescribeDBClustersOutput{}
+	for _, id := range ids {
+		cl, err := s.describeDBCluster(id)
+		if err != nil {
+			return nil, err
+		}
+		out.DBClusters = append(out.DBClusters, cl)
+	}
+	return out, nil
+}
+
+// describeDBCluster describes a fixture cluster or simulated clone.
+func (s *simulatedAWS) describeDBCluster(id string) (rdstypes.DBCluster, error) {
+	arn := fmt.Sprintf("arn:aws:rds:%s:%s:cluster:%s", s.fx.Region, s.fx.AccountID, id)
+	if clone, ok := s.clone(id); ok {
+		status := "creating"
+		if s.now().Sub(clone.started) >= s.cloneDuration()/2 {
+			status = "available"
+		}
+		return rdstypes.DBCluster{
+			DBClusterIdentifier: aws.String(id),
+			DBClusterArn:        aws.String(arn),
+			DBSubnetGroup:       aws.String(clone.source.SubnetGroup),
+			Status:              aws.String(status),
+			Engine:              aws.String(clone.source.Engine),
+			EngineVersion:       aws.String(clone.source.EngineVersion),
+			Endpoint:            aws.String(fmt.Sprintf("%s.cluster-sim.%s.rds.amazonaws.com", id, s.fx.Region)),
+			ClusterCreateTime:   aws.Time(clone.started),
+			DBClusterMembers:    cloneMembers(clone),
+			TagList:             s.rdsTagList(arn, nil),
+		}, nil
+	}
+	for _, cl := range s.fx.Clusters {
+		if cl.ID != id {
+			continue
+		}
+		cluster := rdstypes.DBCluster{
+			DBClusterIdentifier: aws.String(cl.ID),
+			DBClusterArn:        aws.String(arn),
+			DBSubnetGroup:       aws.String(cl.SubnetGroup),
+			Status:              aws.String(orDefault(cl.Status, "available")),
+			Engine:              aws.String(cl.Engine),
+			EngineVersion:       aws.String(cl.EngineVersion),
+			StorageEncrypted:    aws.Bool(cl.StorageEncrypted),
+			// Aurora's default week of automated backups, current to the
+			// last five minutes
+			EarliestRestorableTime: aws.Time(s.now().Add(-7 * 24 * time.Hour)),
+			LatestRestorableTime:   aws.Time(s.now().Add(-5 * time.Minute)),
+		}
+		if cl.KMSKeyID != "" {
+			cluster.KmsKeyId = aws.String(cl.KMSKeyID)
+		}
+		if cl.StorageType != "" {
+			cluster.StorageType = aws.String(cl.StorageType)
+		}
+		if cl.ServerlessMaxACU > 0 {
+			cluster.ServerlessV2ScalingConfiguration = &rdstypes.ServerlessV2ScalingConfigurationInfo{
+				MinCapacity: aws.Float64(cl.ServerlessMinACU),
+				MaxCapacity: aws.Float64(cl.ServerlessMaxACU),
+			}
+		}
+		for _, sg := range cl.SecurityGroups {
+			cluster.VpcSecurityGroups = append(cluster.VpcSecurityGroups, rdstypes.VpcSecurityGroupMembership{VpcSecurityGroupId: aws.String(sg)})
+		}
+		for _, inst := range cl.Instances {
+			cluster.DBClusterMembers = append(cluster.DBClusterMembers, rdstypes.DBClusterMember{
+				DBInstanceIdentifier: aws.String(inst.ID),
+				IsClusterWriter:      aws.Bool(inst.Writer),
+			})
+		}
+		cluster.TagList = s.rdsTagList(arn, cl.Tags)
+		return cluster, nil
+	}
+	return rdstypes.DBCluster{}, &smithy.GenericAPIError{Code: "DBClusterNotFoundFault", Message: fmt.Sprintf("DBCluster %s not found", id)}
+}
+
+// rdsTagList returns a resource's fixture tags with the tags added to its
+// ARN since, sorted by key.
+func (s *simulatedAWS) rdsTagList(arn string, fixture map[string]string) []rdstypes.Tag {
+	tags := s.addedTags(arn, fixture)
+	var list []rdstypes.Tag
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		list = append(list, rdstypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return list
+}
+
+// addedTags returns fixture tags with the tags added to resource since.
+func (s *simulatedAWS) addedTags(resource string, fixture map[string]string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tags := maps.Clone(fixture)
+	if tags == nil {
+		tags = make(map[string]string, len(s.tags[resource]))
+	}
+	maps.Copy(tags, s.tags[resource])
+	return tags
+}
+
+// DescribeDBEngineVersions returns the fixture engine versions of the
+// engine, filtered by version and to the default as asked; deprecated ones
+// only with IncludeAll. A version of an engine without fixture versions is
+// returned as available.
+func (s *simulatedAWS) DescribeDBEngineVersions(_ context.Context, in *rds.DescribeDBEngineVersionsInput, _ ...func(*rds.Options)) (*rds.DescribeDBEngineVersionsOutput, error) {
+	engine, version := aws.ToString(in.Engine), aws.ToString(in.EngineVersion)
+	out := &rds.DescribeDBEngineVersionsOutput{}
+	known := false
+	for _, v := range s.fx.EngineVersions {
+		if v.Engine != engine {
+			continue
+		}
+		known = true
+		status := orDefault(v.Status, engineVersionAvailable)
+		if (version != "" && v.Version != version) || (aws.ToBool(in.DefaultOnly) && !v.Default) ||
+			(status != engineVersionAvailable && !aws.ToBool(in.IncludeAll)) {
+			continue
+		}
+		out.DBEngineVersions = append(out.DBEngineVersions, rdstypes.DBEngineVersion{
+			Engine:        aws.String(engine),
+			EngineVersion: aws.String(v.Version),
+			Status:        aws.String(status),
+		})
+	}
+	if !known && version != "" {
+		out.DBEngineVersions = append(out.DBEngineVersions, rdstypes.DBEngineVersion{
+			Engine:        aws.String(engine),
+			EngineVersion: aws.String(version),
+			Status:        aws.String(engineVersionAvailable),
+		})
+	}
+	return out, nil
+}
+
+// DescribeDBSubnetGroups returns the fixture subnet groups, or the named one.
+func (s *simulatedAWS) DescribeDBSubnetGroups(_ context.Context, in *rds.DescribeDBSubnetGroupsInput, _ ...func(*rds.Options)) (*rds.DescribeDBSubnetGroupsOutput, error) {
+	name := aws.ToString(in.DBSubnetGroupName)
+	out := &rds.DescribeDBSubnetGroupsOutput{}
+	for _, g := range s.fx.SubnetGroups {
+		if name != "" && g.Name != name {
+			continue
+		}
+		group := rdstypes.DBSubnetGroup{
+			DBSubnetGroupName:        aws.String(g.Name),
+			DBSubnetGroupDescription: aws.String(g.Description),
+			VpcId:                    aws.String(g.VPCID),
+			SubnetGroupStatus:        aws.String("Complete"),
+		}
+		for _, sn := range g.Subnets {
+			status := sn.Status
+			if status == "" {
+				status = "Active"
+			}
+			group.Subnets = append(group.Subnets, rdstypes.Subnet{
+				SubnetIdentifier:       aws.String(sn.ID),
+				SubnetAvailabilityZone: &rdstypes.AvailabilityZone{Name: aws.String(sn.AZ)},
+				SubnetStatus:           aws.String(status),
+			})
+		}
+		out.DBSubnetGroups = append(out.DBSubnetGroups, group)
+	}
+	if name != "" && len(out.DBSubnetGroups) == 0 {
+		return nil, &smithy.GenericAPIError{Code: "DBSubnetGroupNotFoundFault", Message: fmt.Sprintf("DBSubnetGroup %s not found", name)}
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) DescribeDBInstances(_ context.Context, in *rds.DescribeDBInstancesInput, _ ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	var clusterID string
+	for _, f := range in.Filters {
+		if aws.ToString(f.Name) == "db-cluster-id" && len(f.Values) > 0 {
+			clusterID = f.Values[0]
+		}
+	}
+	out := &rds.DescribeDBInstancesOutput{}
+	if clone, ok := s.clone(clusterID); ok {
+		status := "creating"
+		if s.now().Sub(clone.started) >= s.cloneDuration() {
+			status = "available"
+		}
+		for _, id := range slices.Sorted(maps.Keys(clone.instances)) {
+			out.DBInstances = append(out.DBInstances, rdstypes.DBInstance{
+				DBInstanceIdentifier: aws.String(id),
+				DBClusterIdentifier:  aws.String(clusterID),
+				DBInstanceClass:      aws.String(clone.instances[id]),
+				DBInstanceStatus:     aws.String(status),
+			})
+		}
+		return out, nil
+	}
+	for _, cl := range s.fx.Clusters {
+		if clusterID != "" && cl.ID != clusterID {
+			continue
+		}
+		for _, inst := range cl.Instances {
+			out.DBInstances = append(out.DBInstances, rdstypes.DBInstance{
+				DBInstanceIdentifier: aws.String(inst.ID),
+				DBClusterIdentifier:  aws.String(cl.ID),
+				DBInstanceClass:      aws.String(inst.Class),
+				AvailabilityZone:     aws.String(inst.AvailabilityZone),
+				DBInstanceStatus:     aws.String(orDefault(inst.Status, "available")),
+			})
+		}
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) DescribeEvents(_ context.Context, in *rds.DescribeEventsInput, _ ...func(*rds.Options)) (*rds.DescribeEventsOutput, error) {
+	id := aws.ToString(in.SourceIdentifier)
+	cutoff := s.loadedAt.Add(-time.Duration(aws.ToInt32(in.Duration)) * time.Minute)
+	out := &rds.DescribeEventsOutput{}
+	for _, cl := range s.fx.Clusters {
+		if cl.ID != id {
+			continue
+		}
+		for _, e := range cl.Failovers {
+			date := s.loadedAt.Add(-time.Duration(e.AgeHours * float64(time.Hour)))
+			if e.Date != nil {
+				date = *e.Date
+			}
+			if in.Duration != nil && date.Before(cutoff) {
+				continue
+			}
+			out.Events = append(out.Events, rdstypes.Event{
+				SourceIdentifier: aws.String(cl.ID),
+				SourceType:       rdstypes.SourceTypeDbCluster,
+				Date:             aws.Time(date),
+				Message:          aws.String(e.Message),
+				EventCategories:  []string{"failover"},
+			})
+		}
+	}
+	return out, nil
+}
+
+// RestoreDBClusterToPointInTime creates a simulated clone of a fixture
+// cluster. It becomes available after half the configured restore
+// duration, and its instances after the full duration.
+func (s *simulatedAWS) RestoreDBClusterToPointInTime(_ context.Context, in *rds.RestoreDBClusterToPointInTimeInput, _ ...func(*rds.Options)) (*rds.RestoreDBClusterToPointInTimeOutput, error) {
+	sourceID := aws.ToString(in.SourceDBClusterIdentifier)
+	idx := slices.IndexFunc(s.fx.Clusters, func(cl FixtureCluster) bool { return cl.ID == sourceID })
+	if idx < 0 {
+		return nil, &smithy.GenericAPIError{Code: "DBClusterNotFoundFault", Message: fmt.Sprintf("DBCluster %s not found", sourceID)}
+	}
+	id := aws.ToString(in.DBClusterIdentifier)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.clones[id]; exists {
+		return nil, &smithy.GenericAPIError{Code: "DBClusterAlreadyExistsFault", Message: "DB cluster already exists"}
+	}
+	s.clones[id] = &simulatedClone{source: s.fx.Clusters[idx], instances: map[string]string{}, started: s.now()}
+	return &rds.RestoreDBClusterToPointInTimeOutput{DBCluster: &rdstypes.DBCluster{DBClusterIdentifier: aws.String(id), Status: aws.String("creating")}}, nil
+}
+
+// CreateDBInstance adds an instance to a simulated clone. Instances cannot
+// be added to fixture clusters.
+func (s *simulatedAWS) CreateDBInstance(_ context.Context, in *rds.CreateDBInstanceInput, _ ...func(*rds.Options)) (*rds.CreateDBInstanceOutput, error) {
+	clusterID := aws.ToString(in.DBClusterIdentifier)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clone, ok := s.clones[clusterID]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "DBClusterNotFoundFault", Message: fmt.Sprintf("DBCluster %s not found", clusterID)}
+	}
+	clone.instances[aws.ToString(in.DBInstanceIdentifier)] = aws.ToString(in.DBInstanceClass)
+	return &rds.CreateDBInstanceOutput{DBInstance: &rdstypes.DBInstance{DBInstanceIdentifier: in.DBInstanceIdentifier, DBInstanceStatus: aws.String("creating")}}, nil
+}
+
+// cloneMembers returns a clone's instances as cluster members, the first
+// one the writer.
+func cloneMembers(clone simulatedClone) []rdstypes.DBClusterMember {
+	var members []rdstypes.DBClusterMember
+	for i, id := range slices.Sorted(maps.Keys(clone.instances)) {
+		members = append(members, rdstypes.DBClusterMember{DBInstanceIdentifier: aws.String(id), IsClusterWriter: aws.Bool(i == 0)})
+	}
+	return members
+}
+
+// DeleteDBInstance deletes an instance of a simulated clone. Instances of
+// fixture clusters cannot be deleted.
+func (s *simulatedAWS) DeleteDBInstance(_ context.Context, in *rds.DeleteDBInstanceInput, _ ...func(*rds.Options)) (*rds.DeleteDBInstanceOutput, error) {
+	id := aws.ToString(in.DBInstanceIdentifier)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, clone := range s.clones {
+		if _, ok := clone.instances[id]; ok {
+			delete(clone.instances, id)
+			return &rds.DeleteDBInstanceOutput{DBInstance: &rdstypes.DBInstance{DBInstanceIdentifier: aws.String(id), DBInstanceStatus: aws.String("deleting")}}, nil
+		}
+	}
+	return nil, &smithy.GenericAPIError{Code: "DBInstanceNotFound", Message: fmt.Sprintf("DBInstance %s not found", id)}
+}
+
+// DeleteDBCluster deletes a simulated clone once its instances have been
+// deleted. Fixture clusters cannot be deleted.
+func (s *simulatedAWS) DeleteDBCluster(_ context.Context, in *rds.DeleteDBClusterInput, _ ...func(*rds.Options)) (*rds.DeleteDBClusterOutput, error) {
+	id := aws.ToString(in.DBClusterIdentifier)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clone, ok := s.clones[id]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "DBClusterNotFoundFault", Message: fmt.Sprintf("DBCluster %s not found", id)}
+	}
+	if len(clone.instances) > 0 {
+		return nil, &smithy.GenericAPIError{Code: "InvalidDBClusterStateFault", Message: "Cluster cannot be deleted, it still contains DB instances in non-deleting state."}
+	}
+	delete(s.clones, id)
+	return &rds.DeleteDBClusterOutput{DBCluster: &rdstypes.DBCluster{DBClusterIdentifier: aws.String(id), Status: aws.String("deleting")}}, nil
+}
+
+// EnableHttpEndpoint enables the Data API on a cluster a restore created.
+func (s *simulatedAWS) EnableHttpEndpoint(_ context.Context, in *rds.EnableHttpEndpointInput, _ ...func(*rds.Options)) (*rds.EnableHttpEndpointOutput, error) {
+	arn := aws.ToString(in.ResourceArn)
+	if !s.restoredCluster(arn) {
+		return nil, &smithy.GenericAPIError{Code: "DBClusterNotFoundFault", Message: fmt.Sprintf("DBCluster %s not found", resourceName(arn))}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dataAPI[arn] = true
+	return &rds.EnableHttpEndpointOutput{ResourceArn: in.ResourceArn, HttpEndpointEnabled: aws.Bool(true)}, nil
+}
+
+// restoredCluster reports whether arn is a cluster a simulated or fixture
+// restore job created.
+func (s *simulatedAWS) restoredCluster(arn string) bool {
+	if !strings.Contains(arn, ":cluster:") {
+		return false
+	}
+	for _, j := range s.fx.Jobs {
+		if j.Kind == JobKindRestore && j.ResourceARN == arn {
+			return true
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		if job.createdARN == arn {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a copy of the simulated clone with the given cluster ID.
+func (s *simulatedAWS) clone(id string) (simulatedClone, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clone, ok := s.clones[id]
+	if !ok {
+		return simulatedClone{}, false
+	}
+	c := *clone
+	c.instances = maps.Clone(clone.instances)
+	return c, true
+}
+
+// cloneDuration is how long a simulated clone takes to become usable.
+func (s *simulatedAWS) cloneDuration() time.Duration {
+	return time.Duration(s.fx.Restore.DurationSeconds) * time.Second
+}
+
+// StartExportTask starts a simulated export of a recovery point in the
+// fixtures; it progresses like a restore job.
+func (s *simulatedAWS) StartExportTask(_ context.Context, in *rds.StartExportTaskInput, _ ...func(*rds.Options)) (*rds.StartExportTaskOutput, error) {
+	arn := aws.ToString(in.SourceArn)
+	found := false
+	for _, points := range s.fx.RecoveryPoints {
+		for _, rp := range points {
+			found = found || rp.RecoveryPointARN == arn
+		}
+	}
+	if !found {
+		return nil, notFound("Snapshot %s does not exist", arn)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := aws.ToString(in.ExportTaskIdentifier)
+	if _, exists := s.exports[id]; exists {
+		return nil, &smithy.GenericAPIError{Code: "ExportTaskAlreadyExistsFault", Message: "Export task " + id + " already exists"}
+	}
+	s.exports[id] = &simulatedExport{in: in, started: s.now()}
+	return &rds.StartExportTaskOutput{ExportTaskIdentifier: in.ExportTaskIdentifier, SourceArn: in.SourceArn, Status: aws.String("STARTING")}, nil
+}
+
+// DescribeExportTasks derives an export's progress from elapsed time over
+// the configured restore duration. A FAILED restore outcome fails exports too.
+func (s *simulatedAWS) DescribeExportTasks(_ context.Context, in *rds.DescribeExportTasksInput, _ ...func(*rds.Options)) (*rds.DescribeExportTasksOutput, error) {
+	id := aws.ToString(in.ExportTaskIdentifier)
+	s.mu.Lock()
+	export, ok := s.exports[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, notFound("Export task %s does not exist", id)
+	}
+
+	duration := time.Duration(s.fx.Restore.DurationSeconds) * time.Second
+	elapsed := s.now().Sub(export.started)
+	task := rdstypes.ExportTask{
+		ExportTaskIdentifier: aws.String(id),
+		SourceArn:            export.in.SourceArn,
+		S3Bucket:             export.in.S3BucketName,
+		S3Prefix:             export.in.S3Prefix,
+		IamRoleArn:           export.in.IamRoleArn,
+		KmsKeyId:             export.in.KmsKeyId,
+		TaskStartTime:        aws.Time(export.started),
+		PercentProgress:      aws.Int32(0),
+	}
+	switch {
+	case elapsed < duration/10:
+		task.Status = aws.String("STARTING")
+	case elapsed < duration:
+		task.Status = aws.String("IN_PROGRESS")
+		task.PercentProgress = aws.Int32(int32(100 * elapsed / duration))
+	case s.fx.Restore.Outcome == "COMPLETED":
+		task.Status = aws.String("COMPLETE")
+		task.PercentProgress = aws.Int32(100)
+		task.TotalExtractedDataInGB = aws.Int32(4)
+		task.TaskEndTime = aws.Time(export.started.Add(duration))
+	default:
+		task.Status = aws.String("FAILED")
+		task.FailureCause = aws.String(s.fx.Restore.StatusMessage)
+		task.TaskEndTime = aws.Time(export.started.Add(duration))
+	}
+	return &rds.DescribeExportTasksOutput{ExportTasks: []rdstypes.ExportTask{task}}, nil
+}
+
+// --- EFSAPI ---
+
+// findFileSystem returns the fixture file system with the given ID.
+func (s *simulatedAWS) findFileSystem(id string) (*FixtureFileSystem, error) {
+	for i := range s.fx.FileSystems {
+		if s.fx.FileSystems[i].ID == id {
+			return &s.fx.FileSystems[i], nil
+		}
+	}
+	return nil, &smithy.GenericAPIError{Code: "FileSystemNotFound", Message: fmt.Sprintf("File system '%s' does not exist.", id)}
+}
+
+// DescribeFileSystems describes the fixture file system or one a simulated
+// restore created with the given ID, or all of them without one, with the
+// tags added since.
+func (s *simulatedAWS) DescribeFileSystems(_ context.Context, in *efs.DescribeFileSystemsInput, _ ...func(*efs.Options)) (*efs.DescribeFileSystemsOutput, error) {
+	id := aws.ToString(in.FileSystemId)
+	if id != "" {
+		desc, err := s.describeFileSystem(id)
+		if err != nil {
+			return nil, err
+		}
+		return &efs.DescribeFileSystemsOutput{FileSystems: []efstypes.FileSystemDescription{desc}}, nil
+	}
+	var ids []string
+	for _, fs := range s.fx.FileSystems {
+		ids = append(ids, fs.ID)
+	}
+	s.mu.Lock()
+	for _, job := range s.jobs {
+		if i := strings.Index(job.createdARN, ":file-system/"); i >= 0 && !s.deleted[job.createdARN[i+len(":file-system/"):]] {
+			ids = append(ids, job.createdARN[i+len(":file-system/"):])
+		}
+	}
+	s.mu.Unlock()
+	slices.Sort(ids)
+	out := &efs.DescribeFileSystemsOutput{}
+	for _, id := range slices.Compact(ids) {
+		desc, err := s.describeFileSystem(id)
+		if err != nil {
+			return nil, err
+		}
+		out.FileSystems = append(out.FileSystems, desc)
+	}
+	return out, nil
+}
+
+// describeFileSystem describes a fixture file system or one a simulated
+// restore created.
+func (s *simulatedAWS) describeFileSystem(id string) (efstypes.FileSystemDescription, error) {
+	if out, ok := s.describeRestoredFileSystem(id); ok {
+		desc := out.FileSystems[0]
+		desc.Tags = s.efsTagList(id, nil)
+		return desc, nil
+	}
+	fs, err := s.findFileSystem(id)
+	if err != nil {
+		return efstypes.FileSystemDescription{}, err
+	}
+	desc := efstypes.FileSystemDescription{
+		FileSystemId:         aws.String(fs.ID),
+		Name:                 aws.String(fs.Name),
+		LifeCycleState:       efstypes.LifeCycleState(orDefault(fs.LifeCycleState, string(efstypes.LifeCycleStateAvailable))),
+		SizeInBytes:          &efstypes.FileSystemSize{Value: fs.SizeBytes},
+		PerformanceMode:      efstypes.PerformanceMode(fs.PerformanceMode),
+		ThroughputMode:       efstypes.ThroughputMode(fs.ThroughputMode),
+		Encrypted:            aws.Bool(fs.Encrypted),
+		NumberOfMountTargets: int32(len(fs.MountTargets)), //nolint:gosec // fixture mount targets are few
+	}
+	if fs.ProvisionedMiBps > 0 {
+		desc.ProvisionedThroughputInMibps = aws.Float64(fs.ProvisionedMiBps)
+	}
+	desc.Tags = s.efsTagList(fs.ID, fs.Tags)
+	return desc, nil
+}
+
+// efsTagList returns a file system's fixture tags with the tags added to it
+// since, sorted by key.
+func (s *simulatedAWS) efsTagList(id string, fixture map[string]string) []efstypes.Tag {
+	tags := s.addedTags(id, fixture)
+	var list []efstypes.Tag
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		list = append(list, efstypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return list
+}
+
+func (s *simulatedAWS) DescribeLifecycleConfiguration(_ context.Context, in *efs.DescribeLifecycleConfigurationInput, _ ...func(*efs.Options)) (*efs.DescribeLifecycleConfigurationOutput, error) {
+	if s.restoredFileSystem(aws.ToString(in.FileSystemId)) {
+		return &efs.DescribeLifecycleConfigurationOutput{}, nil // Restores create file systems without lifecycle policies
+	}
+	fs, err := s.findFileSystem(aws.ToString(in.FileSystemId))
+	if err != nil {
+		return nil, err
+	}
+	out := &efs.DescribeLifecycleConfigurationOutput{}
+	for _, rule := range []string{"TransitionToIA", "TransitionToArchive", "TransitionToPrimaryStorageClass"} {
+		value, ok := fs.Lifecycle[rule]
+		if !ok {
+			continue
+		}
+		var p efstypes.LifecyclePolicy
+		switch rule {
+		case "TransitionToIA":
+			p.TransitionToIA = efstypes.TransitionToIARules(value)
+		case "TransitionToArchive":
+			p.TransitionToArchive = efstypes.TransitionToArchiveRules(value)
+		default:
+			p.TransitionToPrimaryStorageClass = efstypes.TransitionToPrimaryStorageClassRules(value)
+		}
+		out.LifecyclePolicies = append(out.LifecyclePolicies, p)
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) DescribeMountTargets(_ context.Context, in *efs.DescribeMountTargetsInput, _ ...func(*efs.Options)) (*efs.DescribeMountTargetsOutput, error) {
+	if s.restoredFileSystem(aws.ToString(in.FileSystemId)) {
+		return &efs.DescribeMountTargetsOutput{}, nil // Restores create file systems without mount targets
+	}
+	fs, err := s.findFileSystem(aws.ToString(in.FileSystemId))
+	if err != nil {
+		return nil, err
+	}
+	out := &efs.DescribeMountTargetsOutput{}
+	for _, mt := range fs.MountTargets {
+		out.MountTargets = append(out.MountTargets, efstypes.MountTargetDescription{
+			MountTargetId:        aws.String(mt.ID),
+			FileSystemId:         aws.String(fs.ID),
+			AvailabilityZoneName: aws.String(mt.AvailabilityZone),
+			SubnetId:             aws.String(mt.SubnetID),
+			IpAddress:            aws.String(mt.IPAddress),
+			LifeCycleState:       efstypes.LifeCycleState(orDefault(mt.LifeCycleState, string(efstypes.LifeCycleStateAvailable))),
+		})
+	}
+	return out, nil
+}
+
+// restoredFileSystem reports whether id is a file system a simulated
+// restore created and that has not been deleted.
+func (s *simulatedAWS) restoredFileSystem(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deleted[id] {
+		return false
+	}
+	for _, job := range s.jobs {
+		if strings.HasSuffix(job.createdARN, ":file-system/"+id) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeRestoredFileSystem describes a file system a simulated restore
+// created, as large as the recovery point it was restored from, metered
+// when the restore completed.
+func (s *simulatedAWS) describeRestoredFileSystem(id string) (*efs.DescribeFileSystemsOutput, bool) {
+	if !s.restoredFileSystem(id) {
+		return nil, false
+	}
+	s.mu.Lock()
+	var job *simulatedJob
+	for _, j := range s.jobs {
+		if strings.HasSuffix(j.createdARN, ":file-system/"+id) {
+			job = j
+		}
+	}
+	s.mu.Unlock()
+	var size int64
+	for _, points := range s.fx.RecoveryPoints {
+		for _, rp := range points {
+			if rp.RecoveryPointARN == job.recoveryPointARN {
+				size = rp.BackupSizeBytes
+			}
+		}
+	}
+	measured := job.started.Add(time.Duration(s.fx.Restore.DurationSeconds) * time.Second)
+	return &efs.DescribeFileSystemsOutput{FileSystems: []efstypes.FileSystemDescription{{
+		FileSystemId:   aws.String(id),
+		LifeCycleState: efstypes.LifeCycleStateAvailable,
+		SizeInBytes:    &efstypes.FileSystemSize{Value: size, Timestamp: aws.Time(measured)},
+		Encrypted:      aws.Bool(true),
+	}}}, true
+}
+
+// DeleteMountTarget refuses to delete a mount target: file systems created
+// by simulated restores have none, and fixture file systems are in use.
+func (s *simulatedAWS) DeleteMountTarget(_ context.Context, in *efs.DeleteMountTargetInput, _ ...func(*efs.Options)) (*efs.DeleteMountTargetOutput, error) {
+	id := aws.ToString(in.MountTargetId)
+	for _, fs := range s.fx.FileSystems {
+		for _, mt := range fs.MountTargets {
+			if mt.ID == id {
+				return nil, &smithy.GenericAPIError{Code: "AccessDeniedException", Message: fmt.Sprintf("Mount target '%s' of file system '%s' is in use.", id, fs.ID)}
+			}
+		}
+	}
+	return nil, &smithy.GenericAPIError{Code: "MountTargetNotFound", Message: fmt.Sprintf("Mount target '%s' does not exist.", id)}
+}
+
+// DeleteFileSystem deletes a file system a simulated restore created.
+// Fixture file systems are in use and cannot be deleted.
+func (s *simulatedAWS) DeleteFileSystem(_ context.Context, in *efs.DeleteFileSystemInput, _ ...func(*efs.Options)) (*efs.DeleteFileSystemOutput, error) {
+	id := aws.ToString(in.FileSystemId)
+	if s.restoredFileSystem(id) {
+		s.mu.Lock()
+		s.deleted[id] = true
+		s.mu.Unlock()
+		return &efs.DeleteFileSystemOutput{}, nil
+	}
+	if _, err := s.findFileSystem(id); err != nil {
+		return nil, err
+	}
+	return nil, &smithy.GenericAPIError{Code: "FileSystemInUse", Message: fmt.Sprintf("File system '%s' is in use.", id)}
+}
+
+// --- EC2API ---
+
+// DescribeSecurityGroups returns the fixture security groups matching the
+// requested group IDs and vpc-id filter.
+func (s *simulatedAWS) DescribeSecurityGroups(_ context.Context, in *ec2.DescribeSecurityGroupsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	var vpcs []string
+	for _, f := range in.Filters {
+		if aws.ToString(f.Name) == "vpc-id" {
+			vpcs = f.Values
+		}
+	}
+	out := &ec2.DescribeSecurityGroupsOutput{}
+	for _, g := range s.fx.SecurityGroups {
+		if (len(in.GroupIds) > 0 && !slices.Contains(in.GroupIds, g.ID)) || (vpcs != nil && !slices.Contains(vpcs, g.VPCID)) {
+			continue
+		}
+		out.SecurityGroups = append(out.SecurityGroups, ec2types.SecurityGroup{
+			GroupId:     aws.String(g.ID),
+			GroupName:   aws.String(g.Name),
+			Description: aws.String(g.Description),
+			VpcId:       aws.String(g.VPCID),
+		})
+	}
+	for _, id := range in.GroupIds {
+		if !slices.ContainsFunc(out.SecurityGroups, func(g ec2types.SecurityGroup) bool { return aws.ToString(g.GroupId) == id }) {
+			return nil, &smithy.GenericAPIError{Code: "InvalidGroup.NotFound", Message: fmt.Sprintf("The security group '%s' does not exist", id)}
+		}
+	}
+	return out, nil
+}
+
+// --- KMSAPI ---
+
+func (s *simulatedAWS) ListAliases(_ context.Context, _ *kms.ListAliasesInput, _ ...func(*kms.Options)) (*kms.ListAliasesOutput, error) {
+	out := &kms.ListAliasesOutput{}
+	for _, name := range slices.Sorted(maps.Keys(s.fx.KMSAliases)) {
+		out.Aliases = append(out.Aliases, kmstypes.AliasListEntry{
+			AliasName:   aws.String(name),
+			AliasArn:    aws.String(fmt.Sprintf("arn:%s:kms:%s:%s:%s", partition(s.fx.Region), s.fx.Region, s.fx.AccountID, name)),
+			TargetKeyId: aws.String(s.fx.KMSAliases[name]),
+		})
+	}
+	return out, nil
+}
+
+// --- SESAPI and SNSAPI ---
+
+func (s *simulatedAWS) SendEmail(_ context.Context, in *sesv2.SendEmailInput, _ ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	msg := in.Content.Simple
+	s.sent = append(s.sent, SummaryMessage{Subject: aws.ToString(msg.Subject.Data), Body: aws.ToString(msg.Body.Text.Data)})
+	return &sesv2.SendEmailOutput{MessageId: aws.String(fmt.Sprintf("sim-email-%d", s.nextID))}, nil
+}
+
+func (s *simulatedAWS) Publish(_ context.Context, in *sns.PublishInput, _ ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	s.sent = append(s.sent, SummaryMessage{Subject: aws.ToString(in.Subject), Body: aws.ToString(in.Message)})
+	return &sns.PublishOutput{MessageId: aws.String(fmt.Sprintf("sim-message-%d", s.nextID))}, nil
+}
+
+// --- DynamoDBAPI ---
+
+// PutItem records an item in a journal table, which exists as soon as it
+// is written to.
+func (s *simulatedAWS) PutItem(_ context.Context, in *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	table := aws.ToString(in.TableName)
+	s.journal[table] = append(s.journal[table], maps.Clone(in.Item))
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+// Query answers the journal's query, the only one the tool makes: the items
+// of one environment from a time on, in one page.
+func (s *simulatedAWS) Query(_ context.Context, in *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	str := func(v ddbtypes.AttributeValue) string {
+		if sv, ok := v.(*ddbtypes.AttributeValueMemberS); ok {
+			return sv.Value
+		}
+		return ""
+	}
+	env, since := str(in.ExpressionAttributeValues[":env"]), str(in.ExpressionAttributeValues[":since"])
+	out := &dynamodb.QueryOutput{}
+	for _, item := range s.journal[aws.ToString(in.TableName)] {
+		if str(item[journalEnvKey]) == env && str(item[journalAtKey]) >= since {
+			out.Items = append(out.Items, maps.Clone(item))
+		}
+	}
+	slices.SortStableFunc(out.Items, func(a, b map[string]ddbtypes.AttributeValue) int {
+		return strings.Compare(str(a[journalAtKey]), str(b[journalAtKey]))
+	})
+	if !aws.ToBool(in.ScanIndexForward) {
+		slices.Reverse(out.Items)
+	}
+	out.Count = int32(len(out.Items))
+	return out, nil
+}
+
+// --- RDSDataAPI ---
+
+// ExecuteStatement answers the only statement the tool runs, counting a
+// table's rows, from the fixture's table rows.
+func (s *simulatedAWS) ExecuteStatement(_ context.Context, in *rdsdata.ExecuteStatementInput, _ ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error) {
+	arn := aws.ToString(in.ResourceArn)
+	s.mu.Lock()
+	enabled := s.dataAPI[arn]
+	s.mu.Unlock()
+	if !enabled {
+		return nil, &smithy.GenericAPIError{Code: "BadRequestException", Message: fmt.Sprintf("HttpEndpoint is not enabled for resource %s", arn)}
+	}
+	table, ok := strings.CutPrefix(aws.ToString(in.Sql), "SELECT COUNT(*) FROM `")
+	table, _ = strings.CutSuffix(table, "`")
+	rows, found := s.fx.Restore.TableRows[table]
+	if !ok || !found {
+		return nil, &smithy.GenericAPIError{Code: "BadRequestException", Message: fmt.Sprintf("Table '%s.%s' doesn't exist", aws.ToString(in.Database), table)}
+	}
+	return &rdsdata.ExecuteStatementOutput{Records: [][]rdsdatatypes.Field{{&rdsdatatypes.FieldMemberLongValue{Value: rows}}}}, nil
+}
+
+// --- BackupAPI ---
+
+func (s *simulatedAWS) ListBackupVaults(_ context.Context, _ *backup.ListBackupVaultsInput, _ ...func(*backup.Options)) (*backup.ListBackupVaultsOutput, error) {
+	out := &backup.ListBackupVaultsOutput{}
+	for _, v := range s.fx.Vaults {
+		out.BackupVaultList = append(out.BackupVaultList, backuptypes.BackupVaultListMember{
+			BackupVaultName:        aws.String(v),
+			VaultType:              backuptypes.VaultType(s.vaultType(v)),
+			NumberOfRecoveryPoints: int64(len(s.fx.RecoveryPoints[v])),
+		})
+	}
+	return out, nil
+}
+
+// simulatedResourceTypes are the resource types the simulated region
+// supports, as GetSupportedResourceTypes reports them.
+var simulatedResourceTypes = []string{
+	"Aurora", "CloudFormation", "DocumentDB", "DynamoDB", "EBS", "EC2", "EFS", "FSx",
+	"Neptune", "RDS", "Redshift", "S3", "SAP HANA on Amazon EC2", "Storage Gateway", "Timestream", "VirtualMachine",
+}
+
+func (s *simulatedAWS) GetSupportedResourceTypes(_ context.Context, _ *backup.GetSupportedResourceTypesInput, _ ...func(*backup.Options)) (*backup.GetSupportedResourceTypesOutput, error) {
+	return &backup.GetSupportedResourceTypesOutput{ResourceTypes: slices.Clone(simulatedResourceTypes)}, nil
+}
+
+// vaultType returns the fixture type of a vault.
+func (s *simulatedAWS) vaultType(vault string) string {
+	if t := s.fx.VaultTypes[vault]; t != "" {
+		return t
+	}
+	return VaultTypeStandard
+}
+
+func (s *simulatedAWS) DescribeBackupVault(_ context.Context, in *backup.DescribeBackupVaultInput, _ ...func(*backup.Options)) (*backup.DescribeBackupVaultOutput, error) {
+	vault := aws.ToString(in.BackupVaultName)
+	if !slices.Contains(s.fx.Vaults, vault) {
+		return nil, notFound("Backup vault %s does not exist", vault)
+	}
+	out := &backup.DescribeBackupVaultOutput{
+		BackupVaultName:        aws.String(vault),
+		BackupVaultArn:         aws.String(s.vaultARN(vault)),
+		VaultType:              backuptypes.VaultType(s.vaultType(vault)),
+		NumberOfRecoveryPoints: int64(len(s.fx.RecoveryPoints[vault])),
+	}
+	if out.VaultType == VaultTypeAirGapped {
+		// Air-gapped vaults always have a retention range
+		out.VaultState = backuptypes.VaultStateAvailable
+		out.Locked = aws.Bool(true)
+		out.MinRetentionDays = aws.Int64(7)
+		out.MaxRetentionDays = aws.Int64(365)
+	}
+	if lock, ok := s.fx.VaultLocks[vault]; ok {
+		out.Locked = aws.Bool(true)
+		if lock.MinRetentionDays > 0 {
+			out.MinRetentionDays = aws.Int64(lock.MinRetentionDays)
+		}
+		if lock.MaxRetentionDays > 0 {
+			out.MaxRetentionDays = aws.Int64(lock.MaxRetentionDays)
+		}
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) ListRecoveryPointsByBackupVault(_ context.Context, in *backup.ListRecoveryPointsByBackupVaultInput, _ ...func(*backup.Options)) (*backup.ListRecoveryPointsByBackupVaultOutput, error) {
+	vault := aws.ToString(in.BackupVaultName)
+	if !slices.Contains(s.fx.Vaults, vault) {
+		return nil, notFound("Backup vault %s does not exist", vault)
+	}
+	out := &backup.ListRecoveryPointsByBackupVaultOutput{}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rp := range s.fx.RecoveryPoints[vault] {
+		created := s.recoveryPointCreated(rp)
+		if in.ByCreatedAfter != nil && created.Before(*in.ByCreatedAfter) {
+			continue
+		}
+		out.RecoveryPoints = append(out.RecoveryPoints, backuptypes.RecoveryPointByBackupVault{
+			RecoveryPointArn:  aws.String(rp.RecoveryPointARN),
+			ResourceArn:       aws.String(rp.ResourceARN),
+			ResourceType:      aws.String(rp.ResourceType),
+			Status:            backuptypes.RecoveryPointStatus(rp.Status),
+			CreationDate:      aws.Time(created),
+			BackupSizeInBytes: aws.Int64(rp.BackupSizeBytes),
+			BackupVaultName:   aws.String(vault),
+			Lifecycle:         rp.Lifecycle.toAPI(),
+			CalculatedLifecycle: &backuptypes.CalculatedLifecycle{
+				MoveToColdStorageAt: timeOrNil(rp.Lifecycle.MoveToColdAt(created)),
+				DeleteAt:            timeOrNil(rp.Lifecycle.DeleteAt(created)),
+			},
+			CreatedBy:            s.recoveryPointCreator(vault, rp),
+			SourceBackupVaultArn: stringOrNil(rp.SourceVaultARN),
+		})
+	}
+	return out, nil
+}
+
+// recoveryPointPlan returns the plan that created a fixture recovery point:
+// the first plan that targets its vault, or nil for on-demand and copied-in
+// recovery points.
+func (s *simulatedAWS) recoveryPointPlan(vault string, rp FixtureRecoveryPoint) *FixturePlan {
+	if rp.OnDemand || rp.SourceVaultARN != "" {
+		return nil
+	}
+	for i, p := range s.fx.Plans {
+		if slices.Contains(p.Vaults, vault) {
+			return &s.fx.Plans[i]
+		}
+	}
+	return nil
+}
+
+// recoveryPointCreator returns the creator of a fixture recovery point.
+func (s *simulatedAWS) recoveryPointCreator(vault string, rp FixtureRecoveryPoint) *backuptypes.RecoveryPointCreator {
+	p := s.recoveryPointPlan(vault, rp)
+	if p == nil {
+		return &backuptypes.RecoveryPointCreator{}
+	}
+	return &backuptypes.RecoveryPointCreator{
+		BackupPlanId:      aws.String(p.ID),
+		BackupPlanName:    aws.String(p.Name),
+		BackupPlanVersion: aws.String(p.VersionID),
+	}
+}
+
+// DescribeRecoveryPoint returns a fixture recovery point, created by the
+// first plan that targets its vault unless it is on demand, encrypted with
+// the AWS managed backup key.
+func (s *simulatedAWS) DescribeRecoveryPoint(_ context.Context, in *backup.DescribeRecoveryPointInput, _ ...func(*backup.Options)) (*backup.DescribeRecoveryPointOutput, error) {
+	vault, arn := aws.ToString(in.BackupVaultName), aws.ToString(in.RecoveryPointArn)
+	rp, ok := s.recoveryPoint(vault, arn)
+	if !ok {
+		return nil, notFound("Recovery point %s does not exist in vault %s", arn, vault)
+	}
+	created := s.recoveryPointCreated(rp)
+	out := &backup.DescribeRecoveryPointOutput{
+		RecoveryPointArn:     aws.String(arn),
+		BackupVaultName:      aws.String(vault),
+		BackupVaultArn:       aws.String(s.vaultARN(vault)),
+		ResourceArn:          aws.String(rp.ResourceARN),
+		ResourceType:         aws.String(rp.ResourceType),
+		Status:               backuptypes.RecoveryPointStatus(rp.Status),
+		CreationDate:         aws.Time(created),
+		BackupSizeInBytes:    aws.Int64(rp.BackupSizeBytes),
+		Lifecycle:            rp.Lifecycle.toAPI(),
+		StorageClass:         backuptypes.StorageClassWarm,
+		IsEncrypted:          true,
+		EncryptionKeyArn:     aws.String(fmt.Sprintf("arn:aws:kms:%s:%s:key/%s", s.fx.Region, s.fx.AccountID, orDefault(s.fx.KMSAliases["alias/aws/backup"], "sim-aws-backup-key"))),
+		SourceBackupVaultArn: stringOrNil(rp.SourceVaultARN),
+	}
+	if at := rp.Lifecycle.MoveToColdAt(created); !at.IsZero() && !s.now().Before(at) {
+		out.StorageClass = backuptypes.StorageClassCold
+	}
+	out.CreatedBy = s.recoveryPointCreator(vault, rp)
+	if plan := s.recoveryPointPlan(vault, rp); plan != nil && len(plan.Selections) > 0 {
+		out.IamRoleArn = aws.String(plan.Selections[0].IAMRoleARN)
+	}
+	return out, nil
+}
+
+// ListTags returns the tags of a fixture recovery point or vault in one page.
+func (s *simulatedAWS) ListTags(_ context.Context, in *backup.ListTagsInput, _ ...func(*backup.Options)) (*backup.ListTagsOutput, error) {
+	arn := aws.ToString(in.ResourceArn)
+	if s.isVaultARN(arn) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return &backup.ListTagsOutput{Tags: maps.Clone(s.tags[arn])}, nil
+	}
+	for vault := range s.fx.RecoveryPoints {
+		if rp, ok := s.recoveryPoint(vault, arn); ok {
+			return &backup.ListTagsOutput{Tags: maps.Clone(rp.Tags)}, nil
+		}
+	}
+	return nil, notFound("Resource %s does not exist", arn)
+}
+
+// TagResource adds tags to a fixture recovery point or vault.
+func (s *simulatedAWS) TagResource(_ context.Context, in *backup.TagResourceInput, _ ...func(*backup.Options)) (*backup.TagResourceOutput, error) {
+	arn := aws.ToString(in.ResourceArn)
+	if s.isVaultARN(arn) {
+		s.addTags(arn, in.Tags)
+		return &backup.TagResourceOutput{}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, points := range s.fx.RecoveryPoints {
+		for i := range points {
+			if points[i].RecoveryPointARN == arn {
+				if points[i].Tags == nil {
+					points[i].Tags = make(map[string]string, len(in.Tags))
+				}
+				maps.Copy(points[i].Tags, in.Tags)
+				return &backup.TagResourceOutput{}, nil
+			}
+		}
+	}
+	return nil, notFound("Resource %s does not exist", arn)
+}
+
+// UntagResource removes tags from a fixture recovery point or vault.
+func (s *simulatedAWS) UntagResource(_ context.Context, in *backup.UntagResourceInput, _ ...func(*backup.Options)) (*backup.UntagResourceOutput, error) {
+	arn := aws.ToString(in.ResourceArn)
+	vault := s.isVaultARN(arn)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if vault {
+		for _, k := range in.TagKeyList {
+			delete(s.tags[arn], k)
+		}
+		return &backup.UntagResourceOutput{}, nil
+	}
+	for _, points := range s.fx.RecoveryPoints {
+		for i := range points {
+			if points[i].RecoveryPointARN == arn {
+				for _, k := range in.TagKeyList {
+					delete(points[i].Tags, k)
+				}
+				return &backup.UntagResourceOutput{}, nil
+			}
+		}
+	}
+	return nil, notFound("Resource %s does not exist", arn)
+}
+
+// isVaultARN reports whether arn is the ARN of a fixture vault.
+func (s *simulatedAWS) isVaultARN(arn string) bool {
+	return slices.ContainsFunc(s.fx.Vaults, func(v string) bool { return s.vaultARN(v) == arn })
+}
+
+// addTags records tags added to a resource that is not a recovery point.
+func (s *simulatedAWS) addTags(resource string, tags map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tags[resource] == nil {
+		s.tags[resource] = make(map[string]string, len(tags))
+	}
+	maps.Copy(s.tags[resource], tags)
+}
+
+// AddTagsToResource records tags added to an RDS resource.
+func (s *simulatedAWS) AddTagsToResource(_ context.Context, in *rds.AddTagsToResourceInput, _ ...func(*rds.Options)) (*rds.AddTagsToResourceOutput, error) {
+	tags := make(map[string]string, len(in.Tags))
+	for _, t := range in.Tags {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	s.addTags(aws.ToString(in.ResourceName), tags)
+	return &rds.AddTagsToResourceOutput{}, nil
+}
+
+// simulatedEFS serves the EFS API from a simulatedAWS, whose TagResource
+// is AWS Backup's.
+type simulatedEFS struct {
+	*simulatedAWS
+}
+
+// TagResource records tags added to an EFS file system.
+func (s simulatedEFS) TagResource(_ context.Context, in *efs.TagResourceInput, _ ...func(*efs.Options)) (*efs.TagResourceOutput, error) {
+	tags := make(map[string]string, len(in.Tags))
+	for _, t := range in.Tags {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	s.addTags(aws.ToString(in.ResourceId), tags)
+	return &efs.TagResourceOutput{}, nil
+}
+
+// recoveryPoint returns the fixture recovery point with the given ARN in vault.
+func (s *simulatedAWS) recoveryPoint(vault, arn string) (FixtureRecoveryPoint, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rp := range s.fx.RecoveryPoints[vault] {
+		if rp.RecoveryPointARN == arn {
+			return rp, true
+		}
+	}
+	return FixtureRecoveryPoint{}, false
+}
+
+func (s *simulatedAWS) ListBackupPlans(_ context.Context, _ *backup.ListBackupPlansInput, _ ...func(*backup.Options)) (*backup.ListBackupPlansOutput, error) {
+	out := &backup.ListBackupPlansOutput{}
+	for _, p := range s.fx.Plans {
+		out.BackupPlansList = append(out.BackupPlansList, backuptypes.BackupPlansListMember{
+			BackupPlanId:   aws.String(p.ID),
+			BackupPlanName: aws.String(p.Name),
+			VersionId:      aws.String(p.VersionID),
+		})
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) findPlan(id string) *FixturePlan {
+	for i := range s.fx.Plans {
+		if s.fx.Plans[i].ID == id {
+			return &s.fx.Plans[i]
+		}
+	}
+	return nil
+}
+
+func (s *simulatedAWS) GetBackupPlan(_ context.Context, in *backup.GetBackupPlanInput, _ ...func(*backup.Options)) (*backup.GetBackupPlanOutput, error) {
+	p := s.findPlan(aws.ToString(in.BackupPlanId))
+	if p == nil {
+		return nil, notFound("Backup plan %s does not exist", aws.ToString(in.BackupPlanId))
+	}
+	plan := &backuptypes.BackupPlan{BackupPlanName: aws.String(p.Name)}
+	for _, v := range p.Vaults {
+		rule := backuptypes.BackupRule{
+			RuleName:                   aws.String("rule-" + v),
+			TargetBackupVaultName:      aws.String(v),
+			ScheduleExpression:         stringOrNil(p.Schedule),
+			ScheduleExpressionTimezone: stringOrNil(p.Timezone),
+		}
+		for _, dest := range p.CopyTo {
+			rule.CopyActions = append(rule.CopyActions, backuptypes.CopyAction{DestinationBackupVaultArn: aws.String(s.vaultARN(dest))})
+		}
+		plan.Rules = append(plan.Rules, rule)
+	}
+	return &backup.GetBackupPlanOutput{
+		BackupPlan:   plan,
+		BackupPlanId: aws.String(p.ID),
+		VersionId:    aws.String(p.VersionID),
+	}, nil
+}
+
+func (s *simulatedAWS) ListBackupSelections(_ context.Context, in *backup.ListBackupSelectionsInput, _ ...func(*backup.Options)) (*backup.ListBackupSelectionsOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.findPlan(aws.ToString(in.BackupPlanId))
+	if p == nil {
+		return nil, notFound("Backup plan %s does not exist", aws.ToString(in.BackupPlanId))
+	}
+	out := &backup.ListBackupSelectionsOutput{}
+	for _, sel := range p.Selections {
+		out.BackupSelectionsList = append(out.BackupSelectionsList, backuptypes.BackupSelectionsListMember{
+			BackupPlanId:  aws.String(p.ID),
+			SelectionId:   aws.String(selectionID(sel)),
+			SelectionName: aws.String(sel.Name),
+			IamRoleArn:    aws.String(sel.IAMRoleARN),
+		})
+	}
+	return out, nil
+}
+
+// selectionID returns a fixture selection's ID, defaulting to its name.
+func selectionID(sel FixtureSelection) string {
+	if sel.ID != "" {
+		return sel.ID
+	}
+	return sel.Name
+}
+
+func (s *simulatedAWS) GetBackupSelection(_ context.Context, in *backup.GetBackupSelectionInput, _ ...func(*backup.Options)) (*backup.GetBackupSelectionOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.findPlan(aws.ToString(in.BackupPlanId))
+	if p == nil {
+		return nil, notFound("Backup plan %s does not exist", aws.ToString(in.BackupPlanId))
+	}
+	for _, sel := range p.Selections {
+		if selectionID(sel) == aws.ToString(in.SelectionId) {
+			return &backup.GetBackupSelectionOutput{
+				BackupPlanId: aws.String(p.ID),
+				SelectionId:  aws.String(selectionID(sel)),
+				BackupSelection: &backuptypes.BackupSelection{
+					SelectionName: aws.String(sel.Name),
+					IamRoleArn:    aws.String(sel.IAMRoleARN),
+					Resources:     sel.Resources,
+					NotResources:  sel.NotResources,
+					ListOfTags:    fixtureTags(sel.Tags),
+					Conditions:    fixtureConditions(sel.Conditions),
+				},
+			}, nil
+		}
+	}
+	return nil, notFound("Backup selection %s does not exist", aws.ToString(in.SelectionId))
+}
+
+// fixtureTags converts fixture tag conditions to a selection's ListOfTags.
+func fixtureTags(tags []FixtureCondition) []backuptypes.Condition {
+	var out []backuptypes.Condition
+	for _, t := range tags {
+		out = append(out, backuptypes.Condition{
+			ConditionKey:   aws.String(t.Key),
+			ConditionType:  backuptypes.ConditionTypeStringequals,
+			ConditionValue: aws.String(t.Value),
+		})
+	}
+	return out
+}
+
+// fixtureConditions converts fixture conditions to a selection's
+// Conditions, or nil when there are none.
+func fixtureConditions(conditions []FixtureCondition) *backuptypes.Conditions {
+	if len(conditions) == 0 {
+		return nil
+	}
+	out := &backuptypes.Conditions{}
+	for _, c := range conditions {
+		p := backuptypes.ConditionParameter{ConditionKey: aws.String(c.Key), ConditionValue: aws.String(c.Value)}
+		switch c.Type {
+		case "StringNotEquals":
+			out.StringNotEquals = append(out.StringNotEquals, p)
+		case "StringLike":
+			out.StringLike = append(out.StringLike, p)
+		case "StringNotLike":
+			out.StringNotLike = append(out.StringNotLike, p)
+		default:
+			out.StringEquals = append(out.StringEquals, p)
+		}
+	}
+	return out
+}
+
+// CreateBackupSelection records the selection in memory only; fixture files
+// are never modified.
+func (s *simulatedAWS) CreateBackupSelection(_ context.Context, in *backup.CreateBackupSelectionInput, _ ...func(*backup.Options)) (*backup.CreateBackupSelectionOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.findPlan(aws.ToString(in.BackupPlanId))
+	if p == nil {
+		return nil, notFound("Backup plan %s does not exist", aws.ToString(in.BackupPlanId))
+	}
+	if in.BackupSelection == nil {
+		return nil, &smithy.GenericAPIError{Code: "InvalidParameterValueException", Message: "BackupSelection is required"}
+	}
+	s.nextID++
+	sel := FixtureSelection{
+		ID:         fmt.Sprintf("sim-selection-%04d", s.nextID),
+		Name:       aws.ToString(in.BackupSelection.SelectionName),
+		IAMRoleARN: aws.ToString(in.BackupSelection.IamRoleArn),
+		Resources:  in.BackupSelection.Resources,
+	}
+	p.Selections = append(p.Selections, sel)
+	return &backup.CreateBackupSelectionOutput{
+		BackupPlanId: aws.String(p.ID),
+		SelectionId:  aws.String(sel.ID),
+	}, nil
+}
+
+func (s *simulatedAWS) DeleteBackupSelection(_ context.Context, in *backup.DeleteBackupSelectionInput, _ ...func(*backup.Options)) (*backup.DeleteBackupSelectionOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.findPlan(aws.ToString(in.BackupPlanId))
+	if p == nil {
+		return nil, notFound("Backup plan %s does not exist", aws.ToString(in.BackupPlanId))
+	}
+	for i, sel := range p.Selections {
+		if selectionID(sel) == aws.ToString(in.SelectionId) {
+			p.Selections = append(p.Selections[:i], p.Selections[i+1:]...)
+			return &backup.DeleteBackupSelectionOutput{}, nil
+		}
+	}
+	return nil, notFound("Backup selection %s does not exist", aws.ToString(in.SelectionId))
+}
+
+// StartBackupJob starts a simulated on-demand backup of a stack resource; it
+// progresses like a restore job. No recovery point is added to the vault.
+func (s *simulatedAWS) StartBackupJob(_ context.Context, in *backup.StartBackupJobInput, _ ...func(*backup.Options)) (*backup.StartBackupJobOutput, error) {
+	if !slices.Contains(s.fx.Vaults, aws.ToString(in.BackupVaultName)) {
+		return nil, notFound("Backup vault %s does not exist", aws.ToString(in.BackupVaultName))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	id := fmt.Sprintf("sim-backup-%04d", s.nextID)
+	s.backups[id] = &simulatedBackup{in: in, started: s.now()}
+	return &backup.StartBackupJobOutput{BackupJobId: aws.String(id), CreationDate: aws.Time(s.now())}, nil
+}
+
+func (s *simulatedAWS) StartRestoreJob(_ context.Context, in *backup.StartRestoreJobInput, _ ...func(*backup.Options)) (*backup.StartRestoreJobOutput, error) {
+	arn := aws.ToString(in.RecoveryPointArn)
+	resourceType, sourceARN := "", ""
+	for _, points := range s.fx.RecoveryPoints {
+		for _, rp := range points {
+			if rp.RecoveryPointARN == arn {
+				resourceType, sourceARN = rp.ResourceType, rp.ResourceARN
+			}
+		}
+	}
+	if resourceType == "" {
+		return nil, notFound("Recovery point %s does not exist", arn)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	id := fmt.Sprintf("sim-restore-%04d", s.nextID)
+	s.jobs[id] = &simulatedJob{id: id, resourceType: resourceType, recoveryPointARN: arn, sourceARN: sourceARN,
+		createdARN: s.restoreTarget(id, sourceARN, in.Metadata), started: s.now()}
+	return &backup.StartRestoreJobOutput{RestoreJobId: aws.String(id)}, nil
+}
+
+// restoreTarget returns the ARN of the resource a restore with the given
+// metadata creates: the named cluster, a new file system, or for an EFS
+// restore in place the original file system.
+func (s *simulatedAWS) restoreTarget(jobID, sourceARN string, metadata map[string]string) string {
+	switch {
+	case metadata["DBClusterIdentifier"] != "":
+		return fmt.Sprintf("arn:aws:rds:%s:%s:cluster:%s", s.fx.Region, s.fx.AccountID, metadata["DBClusterIdentifier"])
+	case metadata["newFileSystem"] == "true":
+		return fmt.Sprintf("arn:aws:elasticfilesystem:%s:%s:file-system/fs-%s", s.fx.Region, s.fx.AccountID, strings.TrimPrefix(jobID, "sim-restore-"))
+	}
+	return sourceARN
+}
+
+// DescribeRestoreJob derives a job's progress from elapsed time: PENDING for
+// the first tenth of the configured duration, then RUNNING with a rising
+// percentage, then the configured outcome.
+func (s *simulatedAWS) DescribeRestoreJob(_ context.Context, in *backup.DescribeRestoreJobInput, _ ...func(*backup.Options)) (*backup.DescribeRestoreJobOutput, error) {
+	id := aws.ToString(in.RestoreJobId)
+	s.mu.Lock()
+	job, ok := s.jobs[id]
+	s.mu.Unlock()
+	if !ok {
+		// Restores from the job history can be looked up too, e.g. to import them
+		if j, found := s.fixtureJob(JobKindRestore, id); found {
+			created, completed := s.jobTimes(j)
+			return &backup.DescribeRestoreJobOutput{
+				RestoreJobId:       aws.String(j.ID),
+				CreatedResourceArn: aws.String(j.ResourceARN),
+				ResourceType:       aws.String(j.ResourceType),
+				Status:             backuptypes.RestoreJobStatus(j.State),
+				StatusMessage:      aws.String(j.StatusMessage),
+				CreationDate:       created,
+				CompletionDate:     completed,
+				RecoveryPointArn:   aws.String(j.RecoveryPointARN),
+				ValidationStatus:   backuptypes.RestoreValidationStatus(j.ValidationStatus),
+			}, nil
+		}
+		return nil, notFound("Restore job %s does not exist", id)
+	}
+
+	duration := time.Duration(s.fx.Restore.DurationSeconds) * time.Second
+	elapsed := s.now().Sub(job.started)
+	out := &backup.DescribeRestoreJobOutput{
+		RestoreJobId:     aws.String(job.id),
+		ResourceType:     aws.String(job.resourceType),
+		CreationDate:     aws.Time(job.started),
+		RecoveryPointArn: aws.String(job.recoveryPointARN),
+	}
+
+	switch {
+	case elapsed < duration/10:
+		out.Status = backuptypes.RestoreJobStatusPending
+		out.PercentDone = aws.String("0.00")
+	case elapsed < duration:
+		out.Status = backuptypes.RestoreJobStatusRunning
+		out.PercentDone = aws.String(fmt.Sprintf("%.2f", 100*elapsed.Seconds()/duration.Seconds()))
+	default:
+		out.Status = backuptypes.RestoreJobStatus(s.fx.Restore.Outcome)
+		out.CompletionDate = aws.Time(job.started.Add(duration))
+		out.StatusMessage = aws.String(s.fx.Restore.StatusMessage)
+		if out.Status == backuptypes.RestoreJobStatusCompleted {
+			out.PercentDone = aws.String("100.00")
+			out.CreatedResourceArn = aws.String(job.createdARN)
+		}
+	}
+	return out, nil
+}
+
+// recoveryAccount returns the simulated account a recovery role is assumed
+// into, with the given vault, creating it on first use. Copies to its vaults
+// land there, and restores from them run there.
+func (s *simulatedAWS) recoveryAccount(accountID, region, vault string) *simulatedAWS {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.recovery[accountID]
+	if !ok {
+		rec = newSimulatedAWS(&Fixtures{
+			AccountID:      accountID,
+			Region:         region,
+			RecoveryPoints: make(map[string][]FixtureRecoveryPoint),
+			Restore:        s.fx.Restore,
+		})
+		rec.now = func() time.Time { return s.now() } // One clock for both accounts
+		s.recovery[accountID] = rec
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if !slices.Contains(rec.fx.Vaults, vault) {
+		rec.fx.Vaults = append(rec.fx.Vaults, vault)
+	}
+	return rec
+}
+
+// otherRegion returns the simulated region of the account with the given
+// name, creating it from the fixtures' regions on first use. A region
+// without fixtures has no vaults.
+func (s *simulatedAWS) otherRegion(region string) *simulatedAWS {
+	if region == s.fx.Region {
+		return s
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	other, ok := s.regions[region]
+	if !ok {
+		fr := s.fx.Regions[region]
+		other = newSimulatedAWS(&Fixtures{
+			AccountID:      s.fx.AccountID,
+			Region:         region,
+			Vaults:         slices.Clone(fr.Vaults),
+			RecoveryPoints: make(map[string][]FixtureRecoveryPoint),
+			Restore:        s.fx.Restore,
+		})
+		for vault, points := range fr.RecoveryPoints {
+			other.fx.RecoveryPoints[vault] = slices.Clone(points)
+		}
+		other.loadedAt = s.loadedAt // Ages are relative to the same load time
+		other.now = func() time.Time { return s.now() }
+		s.regions[region] = other
+	}
+	return other
+}
+
+// copyDestination returns the simulated account and name of a copy's
+// destination vault. Vaults in other accounts are reachable once a recovery
+// role has been assumed into them.
+func (s *simulatedAWS) copyDestination(vaultARN string) (*simulatedAWS, string, error) {
+	parts := strings.Split(vaultARN, ":")
+	if len(parts) != 7 || parts[5] != "backup-vault" {
+		return nil, "", &smithy.GenericAPIError{Code: "InvalidParameterValueException", Message: "Invalid destination backup vault ARN " + vaultARN}
+	}
+	account, vault := parts[4], parts[6]
+	dest := s
+	if account != s.fx.AccountID {
+		s.mu.Lock()
+		dest = s.recovery[account]
+		s.mu.Unlock()
+		if dest == nil {
+			return nil, "", &smithy.GenericAPIError{Code: "AccessDeniedException",
+				Message: fmt.Sprintf("Account %s has not granted access to backup vault %s", account, vault)}
+		}
+	}
+	if !slices.Contains(dest.fx.Vaults, vault) {
+		return nil, "", notFound("Backup vault %s does not exist", vault)
+	}
+	return dest, vault, nil
+}
+
+func (s *simulatedAWS) StartCopyJob(_ context.Context, in *backup.StartCopyJobInput, _ ...func(*backup.Options)) (*backup.StartCopyJobOutput, error) {
+	vault, arn := aws.ToString(in.SourceBackupVaultName), aws.ToString(in.RecoveryPointArn)
+	rp, ok := s.recoveryPoint(vault, arn)
+	if !ok {
+		return nil, notFound("Recovery point %s does not exist in vault %s", arn, vault)
+	}
+	dest, destVault, err := s.copyDestination(aws.ToString(in.DestinationBackupVaultArn))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	id := fmt.Sprintf("sim-copy-%04d", s.nextID)
+	s.copies[id] = &simulatedCopy{in: in, source: rp, dest: dest, vault: destVault, started: s.now()}
+	return &backup.StartCopyJobOutput{CopyJobId: aws.String(id), CreationDate: aws.Time(s.now())}, nil
+}
+
+// DescribeCopyJob derives a copy's progress from elapsed time, like
+// DescribeRestoreJob. It always completes, and the copy then appears in
+// the destination vault.
+func (s *simulatedAWS) DescribeCopyJob(_ context.Context, in *backup.DescribeCopyJobInput, _ ...func(*backup.Options)) (*backup.DescribeCopyJobOutput, error) {
+	id := aws.ToString(in.CopyJobId)
+	s.mu.Lock()
+	cp, ok := s.copies[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, notFound("Copy job %s does not exist", id)
+	}
+
+	duration := time.Duration(s.fx.Restore.DurationSeconds) * time.Second
+	elapsed := s.now().Sub(cp.started)
+	job := &backuptypes.CopyJob{
+		CopyJobId:                 aws.String(id),
+		SourceBackupVaultArn:      aws.String(s.vaultARN(aws.ToString(cp.in.SourceBackupVaultName))),
+		SourceRecoveryPointArn:    cp.in.RecoveryPointArn,
+		DestinationBackupVaultArn: cp.in.DestinationBackupVaultArn,
+		IamRoleArn:                cp.in.IamRoleArn,
+		ResourceArn:               aws.String(cp.source.ResourceARN),
+		ResourceType:              aws.String(cp.source.ResourceType),
+		BackupSizeInBytes:         aws.Int64(cp.source.BackupSizeBytes),
+		CreationDate:              aws.Time(cp.started),
+	}
+	switch {
+	case elapsed < duration/10:
+		job.State = backuptypes.CopyJobStateCreated
+	case elapsed < duration:
+		job.State = backuptypes.CopyJobStateRunning
+	default:
+		job.State = backuptypes.CopyJobStateCompleted
+		job.CompletionDate = aws.Time(cp.started.Add(duration))
+		job.DestinationRecoveryPointArn = aws.String(s.deliverCopy(id, cp))
+	}
+	return &backup.DescribeCopyJobOutput{CopyJob: job}, nil
+}
+
+// deliverCopy adds a completed copy to its destination vault, once, and
+// returns its ARN there.
+func (s *simulatedAWS) deliverCopy(id string, cp *simulatedCopy) string {
+	arn := fmt.Sprintf("arn:aws:backup:%s:%s:recovery-point:%s", cp.dest.fx.Region, cp.dest.fx.AccountID, id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cp.delivered {
+		return arn
+	}
+	cp.delivered = true
+	point := cp.source
+	point.RecoveryPointARN = arn
+	point.CreationDate = aws.Time(s.recoveryPointCreated(cp.source))
+	point.Lifecycle = Lifecycle{}
+	if cp.dest != s {
+		cp.dest.mu.Lock()
+		defer cp.dest.mu.Unlock()
+	}
+	cp.dest.fx.RecoveryPoints[cp.vault] = append(cp.dest.fx.RecoveryPoints[cp.vault], point)
+	return arn
+}
+
+// GetRecoveryPointRestoreMetadata returns what AWS Backup records of the
+// original resource: its cluster and network, or its file system.
+func (s *simulatedAWS) GetRecoveryPointRestoreMetadata(_ context.Context, in *backup.GetRecoveryPointRestoreMetadataInput, _ ...func(*backup.Options)) (*backup.GetRecoveryPointRestoreMetadataOutput, error) {
+	vault, arn := aws.ToString(in.BackupVaultName), aws.ToString(in.RecoveryPointArn)
+	rp, ok := s.recoveryPoint(vault, arn)
+	if !ok {
+		return nil, notFound("Recovery point %s does not exist in vault %s", arn, vault)
+	}
+	metadata := make(map[string]string)
+	name := resourceName(rp.ResourceARN)
+	switch rp.ResourceType {
+	case "RDS", "Aurora":
+		metadata["DBClusterIdentifier"] = name
+		metadata["Engine"] = "aurora-mysql"
+		for _, c := range s.fx.Clusters {
+			if c.ID == name {
+				metadata["Engine"] = orDefault(c.Engine, "aurora-mysql")
+				metadata["EngineVersion"] = orDefault(rp.EngineVersion, c.EngineVersion)
+				metadata["DBSubnetGroupName"] = c.SubnetGroup
+				metadata["VpcSecurityGroupIds"] = `["` + strings.Join(c.SecurityGroups, `","`) + `"]`
+				if c.KMSKeyID != "" {
+					metadata["KmsKeyId"] = c.KMSKeyID
+				}
+				if c.ServerlessMaxACU > 0 {
+					metadata["ServerlessV2ScalingConfiguration"] = fmt.Sprintf(`{"MinCapacity":%g,"MaxCapacity":%g}`, c.ServerlessMinACU, c.ServerlessMaxACU)
+				}
+			}
+		}
+	case "EFS":
+		metadata["file-system-id"] = name
+		metadata["Encrypted"] = "true"
+		metadata["PerformanceMode"] = "generalPurpose"
+		metadata["newFileSystem"] = "false"
+	}
+	return &backup.GetRecoveryPointRestoreMetadataOutput{
+		BackupVaultArn:   aws.String(s.vaultARN(vault)),
+		RecoveryPointArn: aws.String(arn),
+		ResourceType:     aws.String(rp.ResourceType),
+		RestoreMetadata:  metadata,
+	}, nil
+}
+
+// jobTimes returns a fixture job's creation and (if finished) completion times.
+func (s *simulatedAWS) jobTimes(j FixtureJob) (*time.Time, *time.Time) {
+	created := s.loadedAt.Add(-time.Duration(j.AgeHours * float64(time.Hour)))
+	if j.CreationDate != nil {
+		created = *j.CreationDate
+	}
+	if j.DurationMinutes <= 0 {
+		return aws.Time(created), nil
+	}
+	return aws.Time(created), aws.Time(created.Add(time.Duration(j.DurationMinutes * float64(time.Minute))))
+}
+
+// fixtureJobs returns the fixture jobs of a kind created at or after since.
+func (s *simulatedAWS) fixtureJobs(kind string, since *time.Time) []FixtureJob {
+	var jobs []FixtureJob
+	for _, j := range s.fx.Jobs {
+		if created, _ := s.jobTimes(j); j.Kind == kind && (since == nil || !created.Before(*since)) {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+// fixtureJob returns the fixture job of a kind with the given ID.
+func (s *simulatedAWS) fixtureJob(kind, id string) (FixtureJob, bool) {
+	for _, j := range s.fx.Jobs {
+		if j.Kind == kind && j.ID == id {
+			return j, true
+		}
+	}
+	return FixtureJob{}, false
+}
+
+// DescribeBackupJob returns a backup job from the fixture job history.
+func (s *simulatedAWS) DescribeBackupJob(_ context.Context, in *backup.DescribeBackupJobInput, _ ...func(*backup.Options)) (*backup.DescribeBackupJobOutput, error) {
+	s.mu.Lock()
+	started, ok := s.backups[aws.ToString(in.BackupJobId)]
+	s.mu.Unlock()
+	if ok {
+		return s.describeStartedBackup(aws.ToString(in.BackupJobId), started), nil
+	}
+
+	j, ok := s.fixtureJob(JobKindBackup, aws.ToString(in.BackupJobId))
+	if !ok {
+		return nil, notFound("Backup job %s does not exist", aws.ToString(in.BackupJobId))
+	}
+	created, completed := s.jobTimes(j)
+	return &backup.DescribeBackupJobOutput{
+		BackupJobId:     aws.String(j.ID),
+		BackupVaultName: aws.String(j.Vault),
+		ResourceArn:     aws.String(j.ResourceARN),
+		ResourceType:    aws.String(j.ResourceType),
+		State:           backuptypes.BackupJobState(j.State),
+		StatusMessage:   aws.String(j.StatusMessage),
+		CreationDate:    created,
+		CompletionDate:  completed,
+	}, nil
+}
+
+// describeStartedBackup derives the progress of a backup started in this
+// session from elapsed time, like DescribeRestoreJob. It always completes.
+func (s *simulatedAWS) describeStartedBackup(id string, b *simulatedBackup) *backup.DescribeBackupJobOutput {
+	duration := time.Duration(s.fx.Restore.DurationSeconds) * time.Second
+	elapsed := s.now().Sub(b.started)
+	out := &backup.DescribeBackupJobOutput{
+		BackupJobId:     aws.String(id),
+		BackupVaultName: b.in.BackupVaultName,
+		ResourceArn:     b.in.ResourceArn,
+		ResourceType:    aws.String(simulatedResourceType(aws.ToString(b.in.ResourceArn))),
+		CreationDate:    aws.Time(b.started),
+	}
+	switch {
+	case elapsed < duration/10:
+		out.State = backuptypes.BackupJobStateCreated
+		out.PercentDone = aws.String("0.00")
+	case elapsed < duration:
+		out.State = backuptypes.BackupJobStateRunning
+		out.PercentDone = aws.String(fmt.Sprintf("%.2f", 100*elapsed.Seconds()/duration.Seconds()))
+	default:
+		out.State = backuptypes.BackupJobStateCompleted
+		out.PercentDone = aws.String("100.00")
+		out.CompletionDate = aws.Time(b.started.Add(duration))
+		out.RecoveryPointArn = aws.String(fmt.Sprintf("arn:aws:backup:%s:%s:recovery-point:%s", s.fx.Region, s.fx.AccountID, id))
+	}
+	return out
+}
+
+// simulatedResourceType returns the fixture resource type ("RDS" or "EFS")
+// of a resource ARN.
+func simulatedResourceType(arn string) string {
+	if strings.Contains(arn, ":rds:") {
+		return "RDS"
+	}
+	return "EFS"
+}
+
+// recoveryPointCreated returns when a fixture recovery point was created.
+func (s *simulatedAWS) recoveryPointCreated(rp FixtureRecoveryPoint) time.Time {
+	if rp.CreationDate != nil {
+		return *rp.CreationDate
+	}
+	return s.loadedAt.Add(-time.Duration(rp.AgeHours * float64(time.Hour)))
+}
+
+// stringOrNil returns nil for an empty fixture string, as AWS omits unset
+// fields.
+func stringOrNil(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+// vaultARN returns the ARN of a vault in the simulated account.
+func (s *simulatedAWS) vaultARN(name string) string {
+	return fmt.Sprintf("arn:aws:backup:%s:%s:backup-vault:%s", s.fx.Region, s.fx.AccountID, name)
+}
+
+func (s *simulatedAWS) ListBackupJobs(_ context.Context, in *backup.ListBackupJobsInput, _ ...func(*backup.Options)) (*backup.ListBackupJobsOutput, error) {
+	out := &backup.ListBackupJobsOutput{}
+	for _, j := range s.fixtureJobs(JobKindBackup, in.ByCreatedAfter) {
+		if in.ByBackupVaultName != nil && j.Vault != aws.ToString(in.ByBackupVaultName) {
+			continue
+		}
+		if in.ByResourceArn != nil && j.ResourceARN != aws.ToString(in.ByResourceArn) {
+			continue
+		}
+		created, completed := s.jobTimes(j)
+		out.BackupJobs = append(out.BackupJobs, backuptypes.BackupJob{
+			BackupJobId:     aws.String(j.ID),
+			BackupVaultName: aws.String(j.Vault),
+			ResourceArn:     aws.String(j.ResourceARN),
+			ResourceType:    aws.String(j.ResourceType),
+			State:           backuptypes.BackupJobState(j.State),
+			StatusMessage:   aws.String(j.StatusMessage),
+			CreationDate:    created,
+			CompletionDate:  completed,
+		})
+	}
+	return out, nil
+}
+
+// ListRestoreJobs returns fixture restore jobs plus the restores started in
+// this simulation session.
+func (s *simulatedAWS) ListRestoreJobs(ctx context.Context, in *backup.ListRestoreJobsInput, _ ...func(*backup.Options)) (*backup.ListRestoreJobsOutput, error) {
+	out := &backup.ListRestoreJobsOutput{}
+	for _, j := range s.fixtureJobs(JobKindRestore, in.ByCreatedAfter) {
+		created, completed := s.jobTimes(j)
+		out.RestoreJobs = append(out.RestoreJobs, backuptypes.RestoreJobsListMember{
+			RestoreJobId:       aws.String(j.ID),
+			SourceResourceArn:  aws.String(j.SourceARN),
+			CreatedResourceArn: aws.String(j.ResourceARN),
+			ResourceType:       aws.String(j.ResourceType),
+			Status:             backuptypes.RestoreJobStatus(j.State),
+			StatusMessage:      aws.String(j.StatusMessage),
+			CreationDate:       created,
+			CompletionDate:     completed,
+			RecoveryPointArn:   aws.String(j.RecoveryPointARN),
+			ValidationStatus:   backuptypes.RestoreValidationStatus(j.ValidationStatus),
+		})
+	}
+
+	s.mu.Lock()
+	jobs := make([]simulatedJob, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, *j)
+	}
+	s.mu.Unlock()
+	slices.SortFunc(jobs, func(a, b simulatedJob) int { return strings.Compare(a.id, b.id) })
+	for _, j := range jobs {
+		d, err := s.DescribeRestoreJob(ctx, &backup.DescribeRestoreJobInput{RestoreJobId: aws.String(j.id)})
+		if err != nil || (in.ByCreatedAfter != nil && d.CreationDate.Before(*in.ByCreatedAfter)) {
+			continue
+		}
+		out.RestoreJobs = append(out.RestoreJobs, backuptypes.RestoreJobsListMember{
+			RestoreJobId:      d.RestoreJobId,
+			RecoveryPointArn:  aws.String(j.recoveryPointARN),
+			SourceResourceArn: aws.String(j.sourceARN),
+			ResourceType:      d.ResourceType,
+			Status:            d.Status,
+			StatusMessage:     d.StatusMessage,
+			PercentDone:       d.PercentDone,
+			CreationDate:      d.CreationDate,
+			CompletionDate:    d.CompletionDate,
+		})
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) ListCopyJobs(_ context.Context, in *backup.ListCopyJobsInput, _ ...func(*backup.Options)) (*backup.ListCopyJobsOutput, error) {
+	out := &backup.ListCopyJobsOutput{}
+	for _, j := range s.fixtureJobs(JobKindCopy, in.ByCreatedAfter) {
+		created, completed := s.jobTimes(j)
+		out.CopyJobs = append(out.CopyJobs, backuptypes.CopyJob{
+			CopyJobId:                 aws.String(j.ID),
+			SourceBackupVaultArn:      aws.String(s.vaultARN(j.Vault)),
+			DestinationBackupVaultArn: aws.String(s.vaultARN(j.DestinationVault)),
+			ResourceArn:               aws.String(j.ResourceARN),
+			ResourceType:              aws.String(j.ResourceType),
+			State:                     backuptypes.CopyJobState(j.State),
+			StatusMessage:             aws.String(j.StatusMessage),
+			CreationDate:              created,
+			CompletionDate:            completed,
+		})
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) UpdateRecoveryPointLifecycle(_ context.Context, in *backup.UpdateRecoveryPointLifecycleInput, _ ...func(*backup.Options)) (*backup.UpdateRecoveryPointLifecycleOutput, error) {
+	vault, arn := aws.ToString(in.BackupVaultName), aws.ToString(in.RecoveryPointArn)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, rp := range s.fx.RecoveryPoints[vault] {
+		if rp.RecoveryPointARN != arn {
+			continue
+		}
+		created := s.recoveryPointCreated(rp)
+		l := lifecycleFromAPI(in.Lifecycle)
+		if lock, ok := s.fx.VaultLocks[vault]; ok && lock.MinRetentionDays > 0 && l.DeleteAfterDays > 0 && l.DeleteAfterDays < lock.MinRetentionDays {
+			return nil, &smithy.GenericAPIError{Code: "InvalidParameterValueException",
+				Message: fmt.Sprintf("DeleteAfterDays must be at least the vault lock minimum retention of %d days", lock.MinRetentionDays)}
+		}
+		s.fx.RecoveryPoints[vault][i].Lifecycle = l
+		return &backup.UpdateRecoveryPointLifecycleOutput{
+			BackupVaultArn:   aws.String(s.vaultARN(vault)),
+			RecoveryPointArn: aws.String(arn),
+			Lifecycle:        l.toAPI(),
+			CalculatedLifecycle: &backuptypes.CalculatedLifecycle{
+				MoveToColdStorageAt: timeOrNil(l.MoveToColdAt(created)),
+				DeleteAt:            timeOrNil(l.DeleteAt(created)),
+			},
+		}, nil
+	}
+	return nil, notFound("Recovery point %s does not exist in vault %s", arn, vault)
+}
+
+// DeleteRecoveryPoint removes a fixture recovery point, unless the vault's
+// lock still retains it.
+func (s *simulatedAWS) DeleteRecoveryPoint(_ context.Context, in *backup.DeleteRecoveryPointInput, _ ...func(*backup.Options)) (*backup.DeleteRecoveryPointOutput, error) {
+	vault, arn := aws.ToString(in.BackupVaultName), aws.ToString(in.RecoveryPointArn)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, rp := range s.fx.RecoveryPoints[vault] {
+		if rp.RecoveryPointARN != arn {
+			continue
+		}
+		trail := FixtureTrailEvent{EventName: "DeleteRecoveryPoint", Username: "simulated-operator", Vault: vault,
+			RecoveryPointARN: arn, EventTime: aws.Time(s.now())}
+		if lock, ok := s.fx.VaultLocks[vault]; ok && lock.MinRetentionDays > 0 &&
+			s.now().Sub(s.recoveryPointCreated(rp)) < time.Duration(lock.MinRetentionDays)*24*time.Hour {
+			trail.ErrorCode = "InvalidRequestException"
+			s.fx.TrailEvents = append(s.fx.TrailEvents, trail)
+			return nil, &smithy.GenericAPIError{Code: trail.ErrorCode,
+				Message: fmt.Sprintf("Recovery point %s is retained by the vault lock for %d days", arn, lock.MinRetentionDays)}
+		}
+		s.fx.RecoveryPoints[vault] = slices.Delete(s.fx.RecoveryPoints[vault], i, i+1)
+		s.fx.TrailEvents = append(s.fx.TrailEvents, trail)
+		return &backup.DeleteRecoveryPointOutput{}, nil
+	}
+	return nil, notFound("Recovery point %s does not exist in vault %s", arn, vault)
+}
+
+func (s *simulatedAWS) CreateLegalHold(_ context.Context, in *backup.CreateLegalHoldInput, _ ...func(*backup.Options)) (*backup.CreateLegalHoldOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	id := fmt.Sprintf("sim-legal-hold-%04d", s.nextID)
+	h := &simulatedLegalHold{
+		hold: backuptypes.LegalHold{
+			LegalHoldId:  aws.String(id),
+			LegalHoldArn: aws.String(fmt.Sprintf("arn:aws:backup:%s:%s:legal-hold:%s", s.fx.Region, s.fx.AccountID, id)),
+			Title:        in.Title,
+			Description:  in.Description,
+			Status:       backuptypes.LegalHoldStatusActive,
+			CreationDate: aws.Time(s.now()),
+		},
+		selection: in.RecoveryPointSelection,
+	}
+	s.holds = append(s.holds, h)
+	return &backup.CreateLegalHoldOutput{
+		LegalHoldId:            h.hold.LegalHoldId,
+		LegalHoldArn:           h.hold.LegalHoldArn,
+		Title:                  in.Title,
+		Description:            in.Description,
+		Status:                 backuptypes.LegalHoldStatusCreating,
+		CreationDate:           h.hold.CreationDate,
+		RecoveryPointSelection: in.RecoveryPointSelection,
+	}, nil
+}
+
+func (s *simulatedAWS) ListLegalHolds(_ context.Context, _ *backup.ListLegalHoldsInput, _ ...func(*backup.Options)) (*backup.ListLegalHoldsOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := &backup.ListLegalHoldsOutput{}
+	for _, h := range s.holds {
+		out.LegalHolds = append(out.LegalHolds, h.hold)
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) ListRecoveryPointsByLegalHold(_ context.Context, in *backup.ListRecoveryPointsByLegalHoldInput, _ ...func(*backup.Options)) (*backup.ListRecoveryPointsByLegalHoldOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := s.legalHold(aws.ToString(in.LegalHoldId))
+	if h == nil {
+		return nil, notFound("Legal hold %s does not exist", aws.ToString(in.LegalHoldId))
+	}
+	out := &backup.ListRecoveryPointsByLegalHoldOutput{}
+	sel := h.selection
+	for _, vault := range sel.VaultNames {
+		for _, rp := range s.fx.RecoveryPoints[vault] {
+			created := s.recoveryPointCreated(rp)
+			if len(sel.ResourceIdentifiers) > 0 && !slices.Contains(sel.ResourceIdentifiers, rp.ResourceARN) {
+				continue
+			}
+			if r := sel.DateRange; r != nil && (created.Before(aws.ToTime(r.FromDate)) || created.After(aws.ToTime(r.ToDate))) {
+				continue
+			}
+			out.RecoveryPoints = append(out.RecoveryPoints, backuptypes.RecoveryPointMember{
+				RecoveryPointArn: aws.String(rp.RecoveryPointARN),
+				ResourceArn:      aws.String(rp.ResourceARN),
+				ResourceType:     aws.String(rp.ResourceType),
+				BackupVaultName:  aws.String(vault),
+			})
+		}
+	}
+	return out, nil
+}
+
+func (s *simulatedAWS) CancelLegalHold(_ context.Context, in *backup.CancelLegalHoldInput, _ ...func(*backup.Options)) (*backup.CancelLegalHoldOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := s.legalHold(aws.ToString(in.LegalHoldId))
+	if h == nil {
+		return nil, notFound("Legal hold %s does not exist", aws.ToString(in.LegalHoldId))
+	}
+	h.hold.Status = backuptypes.LegalHoldStatusCanceled
+	h.hold.CancellationDate = aws.Time(s.now())
+	return &backup.CancelLegalHoldOutput{}, nil
+}
+
+// legalHold returns the simulated legal hold with id, or nil. The caller
+// holds s.mu.
+func (s *simulatedAWS) legalHold(id string) *simulatedLegalHold {
+	for _, h := range s.holds {
+		if aws.ToString(h.hold.LegalHoldId) == id {
+			return h
+		}
+	}
+	return nil
+}
+
+// RecordFixtures captures the current AWS state for a stack and vault as
+// simulation fixtures, so realistic DR exercises can be replayed later with
+// -simulate. Only read-only API calls are made.
+func (c *BackupClient) RecordFixtures(ctx context.Context, stackName, vaultName string) (*Fixtures, error) {
+	fx := &Fixtures{
+		AccountID:      c.accountID,
+		Region:         c.region,
+		Vaults:         []string{vaultName},
+		RecoveryPoints: map[string][]FixtureRecoveryPoint{},
+		Restore:        FixtureRestore{DurationSeconds: 60, Outcome: "COMPLETED"},
+	}
+	if info, err := c.DescribeVault(ctx, vaultName); err == nil {
+		if info.AirGapped() {
+			fx.VaultTypes = map[string]string{vaultName: VaultTypeAirGapped}
+		} else if info.Locked {
+			fx.VaultLocks = map[string]FixtureVaultLock{vaultName: {MinRetentionDays: info.MinRetentionDays, MaxRetentionDays: info.MaxRetentionDays}}
+		}
+	}
+
+	stacks, err := c.cfn.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe stack: %w", err)
+	}
+	for _, st := range stacks.Stacks {
+		fs := FixtureStack{Name: aws.ToString(st.StackName), Status: string(st.StackStatus), Created: st.CreationTime, Updated: st.LastUpdatedTime, Outputs: map[string]string{}}
+		for _, o := range st.Outputs {
+			fs.Outputs[aws.ToString(o.OutputKey)] = aws.ToString(o.OutputValue)
+		}
+		resources := cloudformation.NewListStackResourcesPaginator(c.cfn, &cloudformation.ListStackResourcesInput{StackName: st.StackName})
+		for resources.HasMorePages() {
+			page, err := resources.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list stack resources: %w", err)
+			}
+			for _, r := range page.StackResourceSummaries {
+				if _, ok := protectedResourceTypes[aws.ToString(r.ResourceType)]; ok || recordedStackResourceTypes[aws.ToString(r.ResourceType)] {
+					fs.Resources = append(fs.Resources, FixtureStackResource{
+						Type:       aws.ToString(r.ResourceType),
+						LogicalID:  aws.ToString(r.LogicalResourceId),
+						PhysicalID: aws.ToString(r.PhysicalResourceId),
+					})
+				}
+			}
+		}
+		fx.Stacks = append(fx.Stacks, fs)
+	}
+
+	// File systems are optional, like the cluster below.
+	for _, st := range fx.Stacks {
+		for _, r := range st.Resources {
+			if r.Type != "AWS::EFS::FileSystem" {
+				continue
+			}
+			info, err := c.DescribeFileSystem(ctx, r.PhysicalID)
+			if err != nil {
+				continue
+			}
+			ffs := FixtureFileSystem{
+				ID:               info.FileSystemID,
+				Name:             info.Name,
+				SizeBytes:        info.SizeBytes,
+				PerformanceMode:  info.PerformanceMode,
+				ThroughputMode:   info.ThroughputMode,
+				ProvisionedMiBps: info.ProvisionedMiBps,
+				Encrypted:        info.Encrypted,
+				Lifecycle:        map[string]string{},
+			}
+			for _, rule := range info.Lifecycle {
+				if name, value, ok := strings.Cut(rule, ": "); ok {
+					ffs.Lifecycle[name] = value
+				}
+			}
+			for _, mt := range info.MountTargets {
+				ffs.MountTargets = append(ffs.MountTargets, FixtureMountTarget{
+					ID:               mt.ID,
+					AvailabilityZone: mt.AvailabilityZone,
+					SubnetID:         mt.SubnetID,
+					IPAddress:        mt.IPAddress,
+				})
+			}
+			fx.FileSystems = append(fx.FileSystems, ffs)
+		}
+	}
+
+	paginator := backup.NewListRecoveryPointsByBackupVaultPaginator(c.client, &backup.ListRecoveryPointsByBackupVaultInput{
+		BackupVaultName: aws.String(vaultName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list recovery points: %w", err)
+		}
+		for _, rp := range page.RecoveryPoints {
+			fx.RecoveryPoints[vaultName] = append(fx.RecoveryPoints[vaultName], FixtureRecoveryPoint{
+				RecoveryPointARN: aws.ToString(rp.RecoveryPointArn),
+				ResourceARN:      aws.ToString(rp.ResourceArn),
+				ResourceType:     aws.ToString(rp.ResourceType),
+				Status:           string(rp.Status),
+				CreationDate:     rp.CreationDate,
+				BackupSizeBytes:  aws.ToInt64(rp.BackupSizeInBytes),
+				Lifecycle:        lifecycleFromAPI(rp.Lifecycle),
+			})
+		}
+	}
+
+	plans := backup.NewListBackupPlansPaginator(c.client, &backup.ListBackupPlansInput{})
+	for plans.HasMorePages() {
+		page, err := plans.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list backup plans: %w", err)
+		}
+		for _, p := range page.BackupPlansList {
+			details, err := c.client.GetBackupPlan(ctx, &backup.GetBackupPlanInput{BackupPlanId: p.BackupPlanId})
+			if err != nil || details.BackupPlan == nil {
+				continue
+			}
+			fp := FixturePlan{ID: aws.ToString(p.BackupPlanId), Name: aws.ToString(p.BackupPlanName), VersionID: aws.ToString(p.VersionId)}
+			for _, rule := range details.BackupPlan.Rules {
+				fp.Vaults = append(fp.Vaults, aws.ToString(rule.TargetBackupVaultName))
+				for _, action := range rule.CopyActions {
+					if v := vaultNameFromARN(aws.ToString(action.DestinationBackupVaultArn)); v != "" && !slices.Contains(fp.CopyTo, v) {
+						fp.CopyTo = append(fp.CopyTo, v)
+					}
+				}
+			}
+			sels, err := c.listPlanSelections(ctx, fp.ID, fp.Name)
+			if err == nil {
+				for _, s := range sels {
+					fs := FixtureSelection{
+						ID:           s.id,
+						Name:         s.name,
+						IAMRoleARN:   s.roleARN,
+						Resources:    s.resources,
+						NotResources: s.notResources,
+					}
+					for _, t := range s.tags {
+						fs.Tags = append(fs.Tags, FixtureCondition{Key: t.Key, Value: t.Value})
+					}
+					for _, c := range s.conditions {
+						fs.Conditions = append(fs.Conditions, FixtureCondition(c))
+					}
+					fp.Selections = append(fp.Selections, fs)
+				}
+			}
+			fx.Plans = append(fx.Plans, fp)
+		}
+	}
+
+	// Job history is optional: recording still succeeds without permission
+	// to list jobs, the fixtures just have no history to report on.
+	if jobs, err := c.ListJobs(ctx, vaultName, time.Now().Add(-fixtureJobHistory)); err == nil {
+		for _, j := range jobs {
+			fj := FixtureJob{
+				Kind:             j.Kind,
+				ID:               j.JobID,
+				ResourceARN:      j.ResourceARN,
+				ResourceType:     j.ResourceType,
+				State:            j.State,
+				StatusMessage:    j.StatusMessage,
+				CreationDate:     aws.Time(j.CreatedAt),
+				RecoveryPointARN: j.RecoveryPointARN,
+				SourceARN:        j.SourceResourceARN,
+				ValidationStatus: j.ValidationStatus,
+			}
+			if j.Kind != JobKindRestore {
+				fj.Vault = vaultName
+			}
+			if d := j.Duration(); d > 0 {
+				fj.DurationMinutes = d.Minutes()
+			}
+			fx.Jobs = append(fx.Jobs, fj)
+		}
+	}
+
+	// Task definition history is optional, like the cluster below.
+	if history, err := c.TaskDefinitionHistory(ctx, stackName, fixtureTaskDefinitions); err == nil {
+		for _, rev := range history {
+			ftd := FixtureTaskDefinition{Family: rev.Family, Revision: rev.Revision, RegisteredAt: aws.Time(rev.RegisteredAt)}
+			for _, c := range rev.Containers {
+				ftd.Containers = append(ftd.Containers, FixtureContainer(c))
+			}
+			fx.TaskDefinitions = append(fx.TaskDefinitions, ftd)
+		}
+	}
+
+	// Deployments are optional too, and are recorded for the job history window.
+	if deployments, err := c.ServiceDeployments(ctx, stackName, time.Now().Add(-fixtureJobHistory)); err == nil {
+		for _, d := range deployments {
+			family, revision := splitTaskDefinitionARN(d.TaskDefinition)
+			rev, _ := strconv.Atoi(revision)
+			fd := FixtureDeployment{
+				Service:      d.serviceARN,
+				Family:       family,
+				Revision:     int32(rev), //nolint:gosec // Task definition revisions are small
+				Status:       d.Status,
+				StatusReason: d.StatusReason,
+				CreatedAt:    aws.Time(d.CreatedAt),
+			}
+			if !d.FinishedAt.IsZero() {
+				fd.DurationMinutes = d.FinishedAt.Sub(d.CreatedAt).Minutes()
+			}
+			fx.Deployments = append(fx.Deployments, fd)
+		}
+	}
+
+	// Security and subnet groups are optional too; they feed the restore
+	// network pickers.
+	if vpcID, err := c.StackVPC(ctx, stackName); err == nil {
+		if groups, err := c.ListSecurityGroups(ctx, vpcID); err == nil {
+			for _, g := range groups {
+				fx.SecurityGroups = append(fx.SecurityGroups, FixtureSecurityGroup(g))
+			}
+		}
+		if groups, err := c.ListDBSubnetGroups(ctx, vpcID); err == nil {
+			for _, g := range groups {
+				fg := FixtureSubnetGroup{Name: g.Name, Description: g.Description, VPCID: g.VPCID}
+				for _, sn := range g.Subnets {
+					fg.Subnets = append(fg.Subnets, FixtureSubnet(sn))
+				}
+				fx.SubnetGroups = append(fx.SubnetGroups, fg)
+			}
+		}
+	}
+
+	// The cluster is optional: stacks without a database output still record.
+	if clusterID, err := c.getRDSClusterIDFromStack(ctx, stackName); err == nil {
+		if subnetGroup, sgs, err := c.getRDSClusterDetails(ctx, clusterID); err == nil {
+			fc := FixtureCluster{ID: clusterID, SubnetGroup: subnetGroup}
+			if sgs != "" {
+				fc.SecurityGroups = strings.Split(sgs, ",")
+			}
+			if h, err := c.GetClusterHealth(ctx, stackName); err == nil {
+				fc.Status, fc.Engine, fc.EngineVersion = h.Status, h.Engine, h.EngineVersion
+				fc.StorageType, fc.StorageEncrypted = h.StorageType, h.StorageEncrypted
+				fc.ServerlessMinACU, fc.ServerlessMaxACU = h.ServerlessMinACU, h.ServerlessMaxACU
+				fc.KMSKeyID = h.KMSKeyID
+				for _, inst := range h.Instances {
+					fc.Instances = append(fc.Instances, FixtureInstance(inst))
+				}
+				for _, e := range h.Failovers {
+					fc.Failovers = append(fc.Failovers, FixtureEvent{Date: aws.Time(e.Time), Message: e.Message})
+				}
+			}
+			fx.Clusters = append(fx.Clusters, fc)
+			if fc.Engine != "" {
+				fx.EngineVersions = c.recordEngineVersions(ctx, fc.Engine)
+			}
+		}
+	}
+
+	return fx, nil
+}
+
+// recordEngineVersions returns the versions of engine RDS offers in the
+// region, deprecated ones included, for the engine version check. They are
+// optional: nil is returned when they cannot be listed.
+func (c *BackupClient) recordEngineVersions(ctx context.Context, engine string) []FixtureEngineVersion {
+	def, err := c.rds.DescribeDBEngineVersions(ctx, &rds.DescribeDBEngineVersionsInput{Engine: aws.String(engine), DefaultOnly: aws.Bool(true)})
+	if err != nil {
+		return nil
+	}
+	var defaultVersion string
+	if len(def.DBEngineVersions) > 0 {
+		defaultVersion = aws.ToString(def.DBEngineVersions[0].EngineVersion)
+	}
+	var versions []FixtureEngineVersion
+	pages := rds.NewDescribeDBEngineVersionsPaginator(c.rds, &rds.DescribeDBEngineVersionsInput{Engine: aws.String(engine), IncludeAll: aws.Bool(true)})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil
+		}
+		for _, v := range page.DBEngineVersions {
+			version := aws.ToString(v.EngineVersion)
+			versions = append(versions, FixtureEngineVersion{
+				Engine:  engine,
+				Version: version,
+				Status:  aws.ToString(v.Status),
+				Default: version == defaultVersion,
+			})
+		}
+	}
+	return versions
+}
+
+// WriteFixtures writes fixtures to path as indented JSON.
+func WriteFixtures(fx *Fixtures, path string) error {
+	data, err := json.MarshalIndent(fx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixtures: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write fixtures: %w", err)
+	}
+	return nil
+}
+
+// --- CloudTrailAPI ---
+
+// LookupEvents returns the fixture trail events at or after the start time,
+// newest first in one page, as CloudTrail records of AWS Backup calls. Only
+// the event source lookup attribute is supported.
+func (s *simulatedAWS) LookupEvents(_ context.Context, in *cloudtrail.LookupEventsInput, _ ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+	for _, attr := range in.LookupAttributes {
+		if attr.AttributeKey != cttypes.LookupAttributeKeyEventSource || aws.ToString(attr.AttributeValue) != backupEventSource {
+			return &cloudtrail.LookupEventsOutput{}, nil
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := &cloudtrail.LookupEventsOutput{}
+	for i, e := range s.fx.TrailEvents {
+		at := s.loadedAt.Add(-time.Duration(e.AgeHours * float64(time.Hour)))
+		if e.EventTime != nil {
+			at = *e.EventTime
+		}
+		if in.StartTime != nil && at.Before(*in.StartTime) {
+			continue
+		}
+		record := map[string]any{
+			"eventSource":       backupEventSource,
+			"eventName":         e.EventName,
+			"userIdentity":      map[string]string{"arn": fmt.Sprintf("arn:aws:iam::%s:user/%s", s.fx.AccountID, e.Username)},
+			"requestParameters": map[string]string{"backupVaultName": e.Vault, "recoveryPointArn": e.RecoveryPointARN},
+		}
+		if e.ErrorCode != "" {
+			record["errorCode"] = e.ErrorCode
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		out.Events = append(out.Events, cttypes.Event{
+			EventId:         aws.String(fmt.Sprintf("sim-trail-%04d", i+1)),
+			EventName:       aws.String(e.EventName),
+			EventSource:     aws.String(backupEventSource),
+			EventTime:       aws.Time(at),
+			Username:        aws.String(e.Username),
+			CloudTrailEvent: aws.String(string(data)),
+		})
+	}
+	sort.SliceStable(out.Events, func(i, j int) bool { return out.Events[i].EventTime.After(*out.Events[j].EventTime) })
+	return out, nil
+}
@@ -0,0 +1,262 @@
+package aws
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadFixtures_Default(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatalf("default fixtures should load: %v", err)
+	}
+	if len(fx.Stacks) == 0 || len(fx.Vaults) == 0 || len(fx.RecoveryPoints[fx.Vaults[0]]) == 0 {
+		t.Errorf("default fixtures should describe a usable environment: %+v", fx)
+	}
+}
+
+func TestLoadFixtures_AppliesDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fx.json")
+	if err := os.WriteFile(path, []byte(`{"vaults":["v"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fx, err := LoadFixtures(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fx.Region == "" || fx.AccountID == "" || fx.Restore.DurationSeconds <= 0 || fx.Restore.Outcome != "COMPLETED" {
+		t.Errorf("expected defaults to be filled in, got %+v", fx)
+	}
+}
+
+func TestLoadFixtures_Errors(t *testing.T) {
+	if _, err := LoadFixtures(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+	path := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(path, []byte(`{`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFixtures(path); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestSimulatedClient_RestoreWorkflow(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fx.Restore = FixtureRestore{DurationSeconds: 100, Outcome: "COMPLETED"}
+	c := NewSimulatedBackupClient(fx)
+	if !c.Simulated() {
+		t.Fatal("simulated client should report Simulated()")
+	}
+
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.client.(*simulatedAWS).now = func() time.Time { return clock }
+
+	ctx := context.Background()
+	stack, err := c.DiscoverStackName(ctx)
+	if err != nil {
+		t.Fatalf("DiscoverStackName: %v", err)
+	}
+	vault, err := c.DiscoverVaultByStack(ctx, stack)
+	if err != nil {
+		t.Fatalf("DiscoverVaultByStack: %v", err)
+	}
+	points, err := c.ListRecoveryPoints(ctx, vault, "RDS")
+	if err != nil || len(points) == 0 {
+		t.Fatalf("ListRecoveryPoints: %v (%d points)", err, len(points))
+	}
+
+	meta, err := c.GetRestoreMetadata(ctx, points[0], stack)
+	if err != nil {
+		t.Fatalf("GetRestoreMetadata: %v", err)
+	}
+	if meta.ClusterID == "" || meta.SubnetGroup == "" || meta.SecurityGroups == "" {
+		t.Errorf("expected cluster network details, got %+v", meta)
+	}
+
+	jobID, err := c.StartRestoreJob(ctx, points[0], stack, vault, RestoreOptions{})
+	if err != nil {
+		t.Fatalf("StartRestoreJob: %v", err)
+	}
+
+	steps := []struct {
+		elapsed time.Duration
+		status  string
+	}{
+		{0, "PENDING"},
+		{50 * time.Second, "RUNNING"},
+		{100 * time.Second, "COMPLETED"},
+	}
+	start := clock
+	for _, step := range steps {
+		clock = start.Add(step.elapsed)
+		st, err := c.GetRestoreJobStatus(ctx, jobID)
+		if err != nil {
+			t.Fatalf("GetRestoreJobStatus: %v", err)
+		}
+		if st.Status != step.status {
+			t.Errorf("after %s: status = %s, want %s", step.elapsed, st.Status, step.status)
+		}
+		if step.status == "RUNNING" && st.PercentDone != "50.00" {
+			t.Errorf("PercentDone = %q, want 50.00", st.PercentDone)
+		}
+		if st.IsTerminal != (step.status == "COMPLETED") {
+			t.Errorf("IsTerminal = %v at %s", st.IsTerminal, step.status)
+		}
+	}
+}
+
+func TestSimulatedClient_FailedOutcome(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	fx.Restore = FixtureRestore{DurationSeconds: 10, Outcome: "FAILED", StatusMessage: "drill: access denied"}
+	c := NewSimulatedBackupClient(fx)
+	clock := time.Now()
+	c.client.(*simulatedAWS).now = func() time.Time { return clock }
+
+	ctx := context.Background()
+	points, _ := c.ListRecoveryPoints(ctx, fx.Vaults[0], "EFS")
+	jobID, err := c.StartRestoreJob(ctx, points[0], fx.Stacks[0].Name, fx.Vaults[0], RestoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock = clock.Add(time.Minute)
+	st, err := c.GetRestoreJobStatus(ctx, jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Status != "FAILED" || st.StatusMessage != "drill: access denied" || !st.IsTerminal {
+		t.Errorf("unexpected status: %+v", st)
+	}
+}
+
+func TestSimulatedClient_UnknownVault(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	if _, err := c.ListRecoveryPoints(context.Background(), "no-such-vault", ""); err == nil {
+		t.Error("expected error for a vault not in the fixtures")
+	}
+}
+
+func TestRecordFixtures_RoundTrip(t *testing.T) {
+	src, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(src)
+	ctx := context.Background()
+
+	fx, err := c.RecordFixtures(ctx, src.Stacks[0].Name, src.Vaults[0])
+	if err != nil {
+		t.Fatalf("RecordFixtures: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "recorded.json")
+	if err := WriteFixtures(fx, path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadFixtures(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(loaded.RecoveryPoints[src.Vaults[0]]), len(src.RecoveryPoints[src.Vaults[0]]); got != want {
+		t.Errorf("recorded %d recovery points, want %d", got, want)
+	}
+	if len(loaded.Plans) != len(src.Plans) || len(loaded.Clusters) != 1 || len(loaded.EngineVersions) != len(src.EngineVersions) {
+		t.Errorf("expected plans, cluster, and engine versions to be recorded, got %+v", loaded)
+	}
+
+	replay := NewSimulatedBackupClient(loaded)
+	pr, err := replay.ResolvePlanRole(ctx, src.Vaults[0], false)
+	if err != nil || pr.Fallback {
+		t.Errorf("replayed fixtures should resolve the plan role, got %+v, %v", pr, err)
+	}
+}
+
+func TestSimulatedClient_ClusterHealth(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewSimulatedBackupClient(fx)
+	h, err := c.GetClusterHealth(context.Background(), "OpenemrEcsStack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.Healthy() || len(h.Instances) != 2 || !h.Instances[0].Writer || len(h.Failovers) != 1 {
+		t.Errorf("unexpected simulated cluster health: %+v", h)
+	}
+}
+
+func TestSimulatedClient_TaskDefinitionHistory(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewSimulatedBackupClient(fx)
+	history, err := c.TaskDefinitionHistory(context.Background(), "OpenemrEcsStack", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 4 || history[0].Revision != 13 {
+		t.Fatalf("unexpected simulated history: %+v", history)
+	}
+	change := LastImageChange(history)
+	if change == nil || change.Revision != 12 || change.To != "openemr/openemr:7.0.3" {
+		t.Errorf("unexpected image change: %+v", change)
+	}
+}
+
+func TestSimulatedClient_ServiceDeployments(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewSimulatedBackupClient(fx)
+	deployments, err := c.ServiceDeployments(context.Background(), "OpenemrEcsStack", time.Now().Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deployments) != 4 || deployments[3].Image != "openemr/openemr:7.0.3" || !deployments[1].Failed() {
+		t.Errorf("unexpected simulated deployments: %+v", deployments)
+	}
+}
+
+func TestSimulatedClient_LookupFixtureJobs(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewSimulatedBackupClient(fx)
+	kind, status, err := c.LookupJob(context.Background(), "sim-backup-0002")
+	if err != nil || kind != JobKindBackup || status.ResourceID != "fs-0sim0001" || !status.IsTerminal {
+		t.Errorf("fixture backup jobs should be found, got %q %+v %v", kind, status, err)
+	}
+	if kind, _, err := c.LookupJob(context.Background(), "sim-restore-drill-0001"); err != nil || kind != JobKindRestore {
+		t.Errorf("fixture restore jobs should be found, got %q %v", kind, err)
+	}
+}
+
+func TestSimulatedClient_RecoveryPointDetails(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewSimulatedBackupClient(fx)
+	vault := fx.Vaults[0]
+	for _, rp := range fx.RecoveryPoints[vault] {
+		d, err := c.DescribeRecoveryPointDetails(context.Background(), vault, rp.RecoveryPointARN)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.OnDemand() != rp.OnDemand || !d.Encrypted || len(d.Tags) != len(rp.Tags) {
+			t.Errorf("details of %s do not match the fixture: %+v", rp.RecoveryPointARN, d)
+		}
+	}
+	if _, err := c.DescribeRecoveryPointDetails(context.Background(), vault, "arn:missing"); err == nil {
+		t.Error("expected error for an unknown recovery point")
+	}
+}
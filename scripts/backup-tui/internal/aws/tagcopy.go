@@ -0,0 +1,146 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements restore-time tag copying: a restored cluster or file
+// system starts with none of the original's tags (Name, environment,
+// cost-center), so tag-based cost and ownership reports miss it. When asked,
+// the original resource's tags are copied onto it once the restore
+// completes, with provenance tags naming the backup it came from.
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/smithy-go"
+)
+
+// Provenance tags stamped on a restored resource along with the tags copied
+// from the original.
+const (
+	RestoredFromTagKey         = "restored-from"          // ARN of the recovery point restored
+	RestoredFromResourceTagKey = "restored-from-resource" // ID of the backed-up cluster or file system
+	RestoreJobTagKey           = "restore-job-id"         // AWS Backup restore job ID
+	RestoredAtTagKey           = "restored-at"            // When the restore completed, RFC 3339 in UTC
+)
+
+// SourceTags returns the tags of the resource rp backed up that can be
+// copied to what a restore of it creates: tags AWS reserves ("aws:"), the
+// tool's own tags, and provenance tags are left out. If the resource no
+// longer exists, the recovery point's tags are used instead, which AWS
+// Backup copies from the resource when the backup is taken.
+func (c *BackupClient) SourceTags(ctx context.Context, rp RecoveryPoint) (map[string]string, error) {
+	tags, err := c.liveResourceTags(ctx, rp)
+	if isResourceNotFound(err) {
+		tags, err = c.recoveryPointTags(ctx, rp.RecoveryPointARN)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the tags of %s: %w", sourceID(rp), err)
+	}
+	copyable := make(map[string]string, len(tags))
+	for k, v := range tags {
+		switch k {
+		case CreatedByTagKey, CreatedViaTagKey, BatchTagKey,
+			RestoredFromTagKey, RestoredFromResourceTagKey, RestoreJobTagKey, RestoredAtTagKey:
+			continue
+		}
+		if !strings.HasPrefix(k, "aws:") {
+			copyable[k] = v
+		}
+	}
+	return copyable, nil
+}
+
+// sourceID returns the ID of the cluster or file system rp backed up.
+func sourceID(rp RecoveryPoint) string {
+	if rp.ResourceARN != "" {
+		return resourceName(rp.ResourceARN)
+	}
+	return rp.ResourceID
+}
+
+// liveResourceTags returns the tags of the Aurora cluster or EFS file
+// system rp backed up.
+func (c *BackupClient) liveResourceTags(ctx context.Context, rp RecoveryPoint) (map[string]string, error) {
+	tags := map[string]string{}
+	switch rp.ResourceType {
+	case "RDS", "Aurora":
+		out, err := c.rds.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(sourceID(rp))})
+		if err != nil {
+			return nil, err
+		}
+		for _, cl := range out.DBClusters {
+			for _, t := range cl.TagList {
+				tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+			}
+		}
+	case "EFS":
+		out, err := c.efs.DescribeFileSystems(ctx, &efs.DescribeFileSystemsInput{FileSystemId: aws.String(sourceID(rp))})
+		if err != nil {
+			return nil, err
+		}
+		for _, fs := range out.FileSystems {
+			for _, t := range fs.Tags {
+				tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("copying tags of %s resources is not supported", rp.ResourceType)
+	}
+	return tags, nil
+}
+
+// isResourceNotFound reports whether err says the cluster or file system
+// does not exist.
+func isResourceNotFound(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "DBClusterNotFoundFault", "FileSystemNotFound":
+		return true
+	}
+	return false
+}
+
+// RestoreTags returns the tags copied onto the resource a restore of rp
+// created: the original's tags, then the provenance tags.
+func RestoreTags(source map[string]string, rp RecoveryPoint, status *RestoreJobStatus) map[string]string {
+	tags := make(map[string]string, len(source)+4)
+	maps.Copy(tags, source)
+	tags[RestoredFromTagKey] = rp.RecoveryPointARN
+	tags[RestoredFromResourceTagKey] = sourceID(rp)
+	if status.JobID != "" {
+		tags[RestoreJobTagKey] = status.JobID
+	}
+	if !status.CompletedAt.IsZero() {
+		tags[RestoredAtTagKey] = status.CompletedAt.UTC().Format(time.RFC3339)
+	}
+	return tags
+}
+
+// CopyTagsToRestored copies the tags of the resource rp backed up, with the
+// provenance tags, onto the resource a completed restore of it created, and
+// returns the tags applied. Nothing is copied when the restore created
+// nothing, e.g. an EFS restore into the existing file system.
+func (c *BackupClient) CopyTagsToRestored(ctx context.Context, rp RecoveryPoint, status *RestoreJobStatus) (map[string]string, error) {
+	arn := restoredResourceARN(rp, status)
+	if arn == "" {
+		return nil, nil
+	}
+	source, err := c.SourceTags(ctx, rp)
+	if err != nil {
+		return nil, err
+	}
+	tags := RestoreTags(source, rp, status)
+	if err := c.tagResource(ctx, arn, tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
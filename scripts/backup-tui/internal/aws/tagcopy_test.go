@@ -0,0 +1,85 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/smithy-go"
+)
+
+func TestSimulatedClient_CopyTagsToRestored(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	sim := c.client.(*simulatedAWS)
+	start := time.Now()
+	sim.now = func() time.Time { return start }
+	ctx := context.Background()
+
+	points, _ := c.ListRecoveryPoints(ctx, fx.Vaults[0], "RDS")
+	rp := points[0]
+	jobID, err := c.StartRestoreJob(ctx, rp, fx.Stacks[0].Name, fx.Vaults[0], RestoreOptions{CopyTags: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags, err := c.CopyTagsToRestored(ctx, rp, &RestoreJobStatus{JobID: jobID, Status: "RUNNING"}); err != nil || tags != nil {
+		t.Fatalf("a running restore has created nothing to tag, got %v, %v", tags, err)
+	}
+
+	sim.now = func() time.Time { return start.Add(time.Duration(fx.Restore.DurationSeconds+1) * time.Second) }
+	status, err := c.GetRestoreJobStatus(ctx, jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CopyTagsToRestored(ctx, rp, status); err != nil {
+		t.Fatal(err)
+	}
+	got := sim.tags[status.CreatedResourceARN]
+	want := map[string]string{
+		"Name":                     "openemr-training-cluster",
+		"environment":              "training",
+		"cost-center":              "clinical-it",
+		RestoredFromTagKey:         rp.RecoveryPointARN,
+		RestoredFromResourceTagKey: "openemr-training-cluster",
+		RestoreJobTagKey:           jobID,
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("tag %s = %q, want %q", k, got[k], v)
+		}
+	}
+	if _, ok := got["aws:cloudformation:stack-name"]; ok {
+		t.Error("tags AWS reserves cannot be copied")
+	}
+	if got[RestoredAtTagKey] == "" {
+		t.Error("the restore's completion time should be stamped")
+	}
+}
+
+func TestSourceTags_FallsBackToRecoveryPoint(t *testing.T) {
+	rdsMock := &mockRDS{describeClustersErr: &smithy.GenericAPIError{Code: "DBClusterNotFoundFault"}}
+	backupMock := &mockBackup{listTagsOut: []*backup.ListTagsOutput{{Tags: map[string]string{
+		"cost-center":    "clinical-it",
+		CreatedViaTagKey: CreatedVia,
+	}}}}
+	c := newTestClient(&mockCFN{}, backupMock, rdsMock)
+
+	rp := RecoveryPoint{RecoveryPointARN: "arn:aws:backup:us-west-2:123456789012:recovery-point:rp-1", ResourceType: "RDS", ResourceID: "gone-cluster"}
+	tags, err := c.SourceTags(context.Background(), rp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags["cost-center"] != "clinical-it" {
+		t.Errorf("a deleted cluster's tags should come from its recovery point, without the tool's own: %v", tags)
+	}
+	if len(backupMock.listTagsInputs) != 1 || aws.ToString(backupMock.listTagsInputs[0].ResourceArn) != rp.RecoveryPointARN {
+		t.Errorf("expected the recovery point's tags to be listed, got %+v", backupMock.listTagsInputs)
+	}
+
+	rdsMock.describeClustersErr = &smithy.GenericAPIError{Code: "AccessDenied"}
+	if _, err := c.SourceTags(context.Background(), rp); err == nil {
+		t.Error("other errors should not fall back to the recovery point")
+	}
+}
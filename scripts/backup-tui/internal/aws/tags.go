@@ -0,0 +1,137 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements recovery point tag edits: classification such as
+// verified=true or hold=legal-2026-01 is added to and removed from recovery
+// points with TagResource and UntagResource.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// Tag limits AWS Backup enforces.
+const (
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// TagEdit is a change to recovery point tags: tags to add (or overwrite)
+// and tag keys to remove.
+type TagEdit struct {
+	Add    map[string]string
+	Remove []string
+}
+
+// ParseTagEdit parses a tag edit written as "key=value" to add a tag and
+// "-key" to remove one, separated by spaces or commas, e.g. "verified=true
+// hold=legal-2026-01 -stale". Values cannot contain spaces or commas.
+func ParseTagEdit(s string) (TagEdit, error) {
+	var e TagEdit
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	if len(fields) == 0 {
+		return e, fmt.Errorf("enter tags to add as key=value or to remove as -key")
+	}
+	for _, f := range fields {
+		if key, ok := strings.CutPrefix(f, "-"); ok {
+			if err := checkTagKey(key); err != nil {
+				return e, err
+			}
+			if _, added := e.Add[key]; added {
+				return e, fmt.Errorf("tag %s is both added and removed", key)
+			}
+			if !slices.Contains(e.Remove, key) {
+				e.Remove = append(e.Remove, key)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(f, "=")
+		if !ok {
+			return e, fmt.Errorf("invalid tag %q: expected key=value to add or -key to remove", f)
+		}
+		if err := checkTagKey(key); err != nil {
+			return e, err
+		}
+		if len(value) > maxTagValueLength {
+			return e, fmt.Errorf("the value of tag %s is longer than %d characters", key, maxTagValueLength)
+		}
+		if slices.Contains(e.Remove, key) {
+			return e, fmt.Errorf("tag %s is both added and removed", key)
+		}
+		if e.Add == nil {
+			e.Add = make(map[string]string)
+		}
+		e.Add[key] = value
+	}
+	return e, nil
+}
+
+// checkTagKey reports a tag key AWS would refuse.
+func checkTagKey(key string) error {
+	switch {
+	case key == "":
+		return fmt.Errorf("tag keys cannot be empty")
+	case len(key) > maxTagKeyLength:
+		return fmt.Errorf("tag key %s is longer than %d characters", key, maxTagKeyLength)
+	case strings.HasPrefix(strings.ToLower(key), "aws:"):
+		return fmt.Errorf("tag key %s uses the reserved aws: prefix", key)
+	}
+	return nil
+}
+
+// Apply returns tags with the edit applied, leaving tags unchanged.
+func (e TagEdit) Apply(tags map[string]string) map[string]string {
+	out := maps.Clone(tags)
+	if out == nil {
+		out = make(map[string]string, len(e.Add))
+	}
+	for _, k := range e.Remove {
+		delete(out, k)
+	}
+	maps.Copy(out, e.Add)
+	return out
+}
+
+// Changes reports whether the edit removes or overwrites any of keys.
+func (e TagEdit) Changes(keys []string) bool {
+	for _, k := range keys {
+		if _, ok := e.Add[k]; ok || slices.Contains(e.Remove, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the edit as ParseTagEdit reads it, added tags first, e.g.
+// "verified=true -stale".
+func (e TagEdit) String() string {
+	parts := make([]string, 0, len(e.Add)+len(e.Remove))
+	for _, k := range slices.Sorted(maps.Keys(e.Add)) {
+		parts = append(parts, k+"="+e.Add[k])
+	}
+	for _, k := range e.Remove {
+		parts = append(parts, "-"+k)
+	}
+	return strings.Join(parts, " ")
+}
+
+// EditRecoveryPointTags applies e to the recovery point with the given ARN:
+// tags are added first, then keys removed.
+func (c *BackupClient) EditRecoveryPointTags(ctx context.Context, arn string, e TagEdit) error {
+	if len(e.Add) > 0 {
+		if _, err := c.client.TagResource(ctx, &backup.TagResourceInput{ResourceArn: aws.String(arn), Tags: e.Add}); err != nil {
+			return fmt.Errorf("failed to tag recovery point: %w", err)
+		}
+	}
+	if len(e.Remove) > 0 {
+		if _, err := c.client.UntagResource(ctx, &backup.UntagResourceInput{ResourceArn: aws.String(arn), TagKeyList: e.Remove}); err != nil {
+			return fmt.Errorf("failed to untag recovery point: %w", err)
+		}
+	}
+	return nil
+}
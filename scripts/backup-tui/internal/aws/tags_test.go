@@ -0,0 +1,72 @@
+package aws
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestParseTagEdit(t *testing.T) {
+	e, err := ParseTagEdit("verified=true, hold=legal-2026-01 -stale note=")
+	if err != nil {
+		t.Fatalf("ParseTagEdit: %v", err)
+	}
+	wantAdd := map[string]string{"verified": "true", "hold": "legal-2026-01", "note": ""}
+	if !maps.Equal(e.Add, wantAdd) || !slices.Equal(e.Remove, []string{"stale"}) {
+		t.Errorf("ParseTagEdit = %+v", e)
+	}
+	if got := e.String(); got != "hold=legal-2026-01 note= verified=true -stale" {
+		t.Errorf("String = %q", got)
+	}
+
+	for _, s := range []string{"", "verified", "=true", "-", "aws:backup=x", "a=1 -a", "-a a=1"} {
+		if _, err := ParseTagEdit(s); err == nil {
+			t.Errorf("ParseTagEdit(%q) should fail", s)
+		}
+	}
+}
+
+func TestTagEdit_Apply(t *testing.T) {
+	e := TagEdit{Add: map[string]string{"verified": "true"}, Remove: []string{"stale"}}
+	tags := map[string]string{"stale": "yes", "env": "prod"}
+	got := e.Apply(tags)
+	if !maps.Equal(got, map[string]string{"env": "prod", "verified": "true"}) {
+		t.Errorf("Apply = %v", got)
+	}
+	if len(tags) != 2 || tags["stale"] != "yes" {
+		t.Errorf("Apply should not change its argument, got %v", tags)
+	}
+	if !e.Changes([]string{"stale"}) || !e.Changes([]string{"verified"}) || e.Changes([]string{"env"}) {
+		t.Error("Changes should report removed and overwritten keys only")
+	}
+}
+
+func TestBackupClient_EditRecoveryPointTags(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	vault := fx.Vaults[0]
+	arn := fx.RecoveryPoints[vault][0].RecoveryPointARN
+
+	if err := c.EditRecoveryPointTags(ctx, arn, TagEdit{Add: map[string]string{"verified": "true", "stale": "yes"}}); err != nil {
+		t.Fatalf("tagging: %v", err)
+	}
+	if err := c.EditRecoveryPointTags(ctx, arn, TagEdit{Remove: []string{"stale"}}); err != nil {
+		t.Fatalf("untagging: %v", err)
+	}
+	d, err := c.DescribeRecoveryPointDetails(ctx, vault, arn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Tags["verified"] != "true" {
+		t.Errorf("added tag missing: %v", d.Tags)
+	}
+	if _, ok := d.Tags["stale"]; ok {
+		t.Errorf("removed tag still present: %v", d.Tags)
+	}
+
+	if err := c.EditRecoveryPointTags(ctx, "arn:aws:backup:missing", TagEdit{Remove: []string{"x"}}); err == nil {
+		t.Error("editing the tags of an unknown recovery point should fail")
+	}
+}
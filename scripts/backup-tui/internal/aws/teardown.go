@@ -0,0 +1,318 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements tearing down what a restore drill or staging
+// restore left behind: every Aurora cluster, DB instance, and EFS file
+// system tagged with the drill's label, with the instances of its clusters
+// and the mount targets of its file systems, deleted in the order AWS
+// requires. The stack's own cluster and file systems are never deleted.
+package aws
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	efstypes "github.com/aws/aws-sdk-go-v2/service/efs/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+)
+
+// LabelTagKey is the tag naming the drill or staging restore a resource
+// was created for, e.g. "drill-2026-01", which "backup-tui teardown" deletes
+// by.
+const LabelTagKey = "backup-tui:label"
+
+// Kinds of resources a teardown deletes, in the order they are deleted.
+const (
+	TeardownDBInstance  = "DB instance"
+	TeardownCluster     = "Aurora cluster"
+	TeardownMountTarget = "EFS mount target"
+	TeardownFileSystem  = "EFS file system"
+)
+
+// teardownOrder is the order resources are deleted in: a cluster's
+// instances before the cluster, and a file system's mount targets before
+// the file system.
+var teardownOrder = []string{TeardownDBInstance, TeardownCluster, TeardownMountTarget, TeardownFileSystem}
+
+// skipDeleting is why a resource an earlier teardown deleted, which RDS or
+// EFS is still deleting, is left.
+const skipDeleting = "already deleting"
+
+// TeardownStep is a resource a teardown deletes, or leaves when Skip says
+// why.
+type TeardownStep struct {
+	Kind   string // One of the Teardown kinds
+	ID     string // Instance or cluster identifier, mount target or file system ID
+	Parent string // Cluster of an instance, or file system of a mount target
+	Skip   string // Why the resource is not deleted, or "" to delete it
+}
+
+// String describes the step's resource, e.g. "DB instance drill-1 (in
+// drill-cluster)".
+func (s TeardownStep) String() string {
+	if s.Parent == "" {
+		return s.Kind + " " + s.ID
+	}
+	return fmt.Sprintf("%s %s (in %s)", s.Kind, s.ID, s.Parent)
+}
+
+// TeardownPlan is what tearing down a label deletes, in order.
+type TeardownPlan struct {
+	Label string
+	Steps []TeardownStep
+}
+
+// Deletes returns the number of resources the plan deletes.
+func (p *TeardownPlan) Deletes() int {
+	n := 0
+	for _, s := range p.Steps {
+		if s.Skip == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// LabelRestoredResource tags the resource a completed restore of rp
+// created with label, for a teardown to find it, and returns its ARN. An
+// EFS restore into the existing file system creates nothing, so nothing is
+// tagged and the ARN is "".
+func (c *BackupClient) LabelRestoredResource(ctx context.Context, rp RecoveryPoint, status *RestoreJobStatus, label string) (string, error) {
+	arn := restoredResourceARN(rp, status)
+	if arn == "" {
+		return "", nil
+	}
+	return arn, c.tagResource(ctx, arn, map[string]string{LabelTagKey: label})
+}
+
+// PlanTeardown finds the resources tagged with label: Aurora clusters with
+// their instances, DB instances, and EFS file systems with their mount
+// targets. Resources of stackName and clusters with deletion protection are
+// listed as skipped, with the instances in them, and so are resources an
+// earlier teardown deleted that are still being deleted, so that it can be
+// rerun.
+func (c *BackupClient) PlanTeardown(ctx context.Context, stackName, label string) (*TeardownPlan, error) {
+	if label == "" {
+		return nil, fmt.Errorf("a label is required")
+	}
+	stack, err := c.stackProtectedResources(ctx, stackName)
+	if err != nil {
+		return nil, err
+	}
+	stackSkip := func(resourceType, id string) string {
+		for _, p := range stack {
+			if p.Type == resourceType && resourceName(p.ARN) == id {
+				return fmt.Sprintf("the stack's %s (%s)", p.LogicalID, stackName)
+			}
+		}
+		return ""
+	}
+
+	plan := &TeardownPlan{Label: label}
+	// Instances are deleted with their cluster, or by their own label
+	clusterSkip := map[string]string{}
+	instances := map[string]TeardownStep{}
+	clusters := rds.NewDescribeDBClustersPaginator(c.rds, &rds.DescribeDBClustersInput{})
+	for clusters.HasMorePages() {
+		page, err := clusters.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list DB clusters: %w", err)
+		}
+		for _, cl := range page.DBClusters {
+			id := aws.ToString(cl.DBClusterIdentifier)
+			if !hasLabel(rdsTags(cl.TagList), label) {
+				continue
+			}
+			step := TeardownStep{Kind: TeardownCluster, ID: id, Skip: stackSkip("RDS", id)}
+			switch {
+			case step.Skip != "":
+			case aws.ToString(cl.Status) == "deleting":
+				step.Skip = skipDeleting
+			case aws.ToBool(cl.DeletionProtection):
+				step.Skip = "deletion protection is on"
+			}
+			plan.Steps = append(plan.Steps, step)
+			clusterSkip[id] = step.Skip
+			for _, m := range cl.DBClusterMembers {
+				instances[aws.ToString(m.DBInstanceIdentifier)] = TeardownStep{Kind: TeardownDBInstance, ID: aws.ToString(m.DBInstanceIdentifier), Parent: id}
+			}
+		}
+	}
+	deleting := map[string]bool{}
+	dbInstances := rds.NewDescribeDBInstancesPaginator(c.rds, &rds.DescribeDBInstancesInput{})
+	for dbInstances.HasMorePages() {
+		page, err := dbInstances.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list DB instances: %w", err)
+		}
+		for _, inst := range page.DBInstances {
+			id := aws.ToString(inst.DBInstanceIdentifier)
+			deleting[id] = aws.ToString(inst.DBInstanceStatus) == "deleting"
+			if _, ok := instances[id]; ok || !hasLabel(rdsTags(inst.TagList), label) {
+				continue
+			}
+			instances[id] = TeardownStep{Kind: TeardownDBInstance, ID: id, Parent: aws.ToString(inst.DBClusterIdentifier)}
+		}
+	}
+	for _, step := range instances {
+		if skip := stackSkip("RDS", step.Parent); skip != "" {
+			step.Skip = "in " + skip
+		} else if deleting[step.ID] {
+			step.Skip = skipDeleting
+		} else if skip := clusterSkip[step.Parent]; skip != "" && skip != skipDeleting {
+			step.Skip = "its cluster is not deleted"
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+
+	fileSystems := efs.NewDescribeFileSystemsPaginator(c.efs, &efs.DescribeFileSystemsInput{})
+	for fileSystems.HasMorePages() {
+		page, err := fileSystems.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list EFS file systems: %w", err)
+		}
+		for _, fs := range page.FileSystems {
+			id := aws.ToString(fs.FileSystemId)
+			tags := make(map[string]string, len(fs.Tags))
+			for _, t := range fs.Tags {
+				tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+			}
+			if !hasLabel(tags, label) {
+				continue
+			}
+			step := TeardownStep{Kind: TeardownFileSystem, ID: id, Skip: stackSkip("EFS", id)}
+			if step.Skip == "" && efsDeleting(fs.LifeCycleState) {
+				step.Skip = skipDeleting
+			}
+			plan.Steps = append(plan.Steps, step)
+			if step.Skip != "" {
+				continue
+			}
+			mts, err := c.efs.DescribeMountTargets(ctx, &efs.DescribeMountTargetsInput{FileSystemId: aws.String(id)})
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe mount targets of %s: %w", id, err)
+			}
+			for _, mt := range mts.MountTargets {
+				step := TeardownStep{Kind: TeardownMountTarget, ID: aws.ToString(mt.MountTargetId), Parent: id}
+				if efsDeleting(mt.LifeCycleState) {
+					step.Skip = skipDeleting
+				}
+				plan.Steps = append(plan.Steps, step)
+			}
+		}
+	}
+
+	slices.SortFunc(plan.Steps, func(a, b TeardownStep) int {
+		return cmp.Or(cmp.Compare(slices.Index(teardownOrder, a.Kind), slices.Index(teardownOrder, b.Kind)),
+			cmp.Compare(a.Parent, b.Parent), cmp.Compare(a.ID, b.ID))
+	})
+	return plan, nil
+}
+
+// Teardown deletes the resources of plan in order, reporting each step to
+// progress with its error. A file system is deleted once its mount targets
+// are gone, which is checked every poll. A resource that no longer exists,
+// e.g. because an earlier run deleted it since the plan, counts as deleted.
+// It stops at the first error, since what follows may depend on it, and
+// returns it; RDS and EFS finish the deletions in the background.
+func (c *BackupClient) Teardown(ctx context.Context, plan *TeardownPlan, poll time.Duration, progress func(TeardownStep, error)) error {
+	for _, step := range plan.Steps {
+		if step.Skip != "" {
+			continue
+		}
+		err := c.teardownStep(ctx, step, poll)
+		progress(step, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// teardownStep deletes one resource of a teardown.
+func (c *BackupClient) teardownStep(ctx context.Context, step TeardownStep, poll time.Duration) error {
+	var err error
+	switch step.Kind {
+	case TeardownDBInstance:
+		// An instance in the deleting state no longer keeps its cluster
+		// from being deleted
+		_, err = c.rds.DeleteDBInstance(ctx, &rds.DeleteDBInstanceInput{DBInstanceIdentifier: aws.String(step.ID)})
+	case TeardownCluster:
+		_, err = c.rds.DeleteDBCluster(ctx, &rds.DeleteDBClusterInput{
+			DBClusterIdentifier: aws.String(step.ID),
+			SkipFinalSnapshot:   aws.Bool(true),
+		})
+	case TeardownMountTarget:
+		_, err = c.efs.DeleteMountTarget(ctx, &efs.DeleteMountTargetInput{MountTargetId: aws.String(step.ID)})
+	case TeardownFileSystem:
+		if err = c.waitForNoMountTargets(ctx, step.ID, poll); err == nil {
+			_, err = c.efs.DeleteFileSystem(ctx, &efs.DeleteFileSystemInput{FileSystemId: aws.String(step.ID)})
+		}
+	default:
+		return fmt.Errorf("cannot delete a %s", step.Kind)
+	}
+	if err != nil && !isAlreadyDeleted(err) {
+		return fmt.Errorf("failed to delete %s: %w", step, err)
+	}
+	return nil
+}
+
+// waitForNoMountTargets waits until a file system's mount targets, which
+// EFS deletes in the background, are gone.
+func (c *BackupClient) waitForNoMountTargets(ctx context.Context, fileSystemID string, poll time.Duration) error {
+	for {
+		mts, err := c.efs.DescribeMountTargets(ctx, &efs.DescribeMountTargetsInput{FileSystemId: aws.String(fileSystemID)})
+		if err != nil {
+			return fmt.Errorf("failed to describe mount targets: %w", err)
+		}
+		if len(mts.MountTargets) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(poll):
+		}
+	}
+}
+
+// efsDeleting reports whether an EFS file system or mount target in state
+// is being, or has been, deleted.
+func efsDeleting(state efstypes.LifeCycleState) bool {
+	return state == efstypes.LifeCycleStateDeleting || state == efstypes.LifeCycleStateDeleted
+}
+
+// isAlreadyDeleted reports whether err says the resource a teardown
+// deletes no longer exists.
+func isAlreadyDeleted(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "DBInstanceNotFound", "DBClusterNotFoundFault", "MountTargetNotFound", "FileSystemNotFound":
+		return true
+	}
+	return false
+}
+
+// rdsTags returns RDS tags as a map.
+func rdsTags(list []rdstypes.Tag) map[string]string {
+	tags := make(map[string]string, len(list))
+	for _, t := range list {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return tags
+}
+
+// hasLabel reports whether tags carry the teardown label.
+func hasLabel(tags map[string]string, label string) bool {
+	v, ok := tags[LabelTagKey]
+	return ok && v == label
+}
@@ -0,0 +1,217 @@
+package aws
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	efstypes "github.com/aws/aws-sdk-go-v2/service/efs/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+func TestPlanTeardown(t *testing.T) {
+	label := []rdstypes.Tag{{Key: aws.String(LabelTagKey), Value: aws.String("drill-2026-01")}}
+	cfnMock := &mockCFN{listResourcesOutput: &cloudformation.ListStackResourcesOutput{
+		StackResourceSummaries: []cfntypes.StackResourceSummary{
+			{ResourceType: aws.String("AWS::RDS::DBCluster"), LogicalResourceId: aws.String("DatabaseCluster"), PhysicalResourceId: aws.String("live")},
+			{ResourceType: aws.String("AWS::EFS::FileSystem"), LogicalResourceId: aws.String("SitesFileSystem"), PhysicalResourceId: aws.String("fs-live")},
+		},
+	}}
+	rdsMock := &mockRDS{
+		describeClustersOutput: &rds.DescribeDBClustersOutput{DBClusters: []rdstypes.DBCluster{
+			{DBClusterIdentifier: aws.String("drill"), TagList: label,
+				DBClusterMembers: []rdstypes.DBClusterMember{{DBInstanceIdentifier: aws.String("drill-1")}}},
+			{DBClusterIdentifier: aws.String("protected"), TagList: label, DeletionProtection: aws.Bool(true),
+				DBClusterMembers: []rdstypes.DBClusterMember{{DBInstanceIdentifier: aws.String("protected-1")}}},
+			{DBClusterIdentifier: aws.String("live"), TagList: label},
+			{DBClusterIdentifier: aws.String("other")},
+		}},
+		describeInstancesOutput: &rds.DescribeDBInstancesOutput{DBInstances: []rdstypes.DBInstance{
+			{DBInstanceIdentifier: aws.String("drill-1"), DBClusterIdentifier: aws.String("drill")},
+			{DBInstanceIdentifier: aws.String("live-reader"), DBClusterIdentifier: aws.String("live"), TagList: label},
+			{DBInstanceIdentifier: aws.String("other-reader"), DBClusterIdentifier: aws.String("other"), TagList: label},
+			{DBInstanceIdentifier: aws.String("other-writer"), DBClusterIdentifier: aws.String("other")},
+		}},
+	}
+	efsLabel := []efstypes.Tag{{Key: aws.String(LabelTagKey), Value: aws.String("drill-2026-01")}}
+	efsMock := &mockEFS{
+		describeOut: &efs.DescribeFileSystemsOutput{FileSystems: []efstypes.FileSystemDescription{
+			{FileSystemId: aws.String("fs-drill"), Tags: efsLabel},
+			{FileSystemId: aws.String("fs-live"), Tags: efsLabel},
+			{FileSystemId: aws.String("fs-other"), Tags: []efstypes.Tag{{Key: aws.String(LabelTagKey), Value: aws.String("drill-2025-12")}}},
+		}},
+		mountTargetsOut: &efs.DescribeMountTargetsOutput{MountTargets: []efstypes.MountTargetDescription{{MountTargetId: aws.String("fsmt-1")}}},
+	}
+	c := newTestClient(cfnMock, &mockBackup{}, rdsMock)
+	c.efs = efsMock
+	ctx := context.Background()
+
+	plan, err := c.PlanTeardown(ctx, "TestStack", "drill-2026-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, s := range plan.Steps {
+		line := s.String()
+		if s.Skip != "" {
+			line += ": " + s.Skip
+		}
+		got = append(got, line)
+	}
+	want := []string{
+		"DB instance drill-1 (in drill)",
+		"DB instance live-reader (in live): in the stack's DatabaseCluster (TestStack)",
+		"DB instance other-reader (in other)",
+		"DB instance protected-1 (in protected): its cluster is not deleted",
+		"Aurora cluster drill",
+		"Aurora cluster live: the stack's DatabaseCluster (TestStack)",
+		"Aurora cluster protected: deletion protection is on",
+		"EFS mount target fsmt-1 (in fs-drill)",
+		"EFS file system fs-drill",
+		"EFS file system fs-live: the stack's SitesFileSystem (TestStack)",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("plan =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if plan.Deletes() != 5 {
+		t.Errorf("Deletes = %d, want 5", plan.Deletes())
+	}
+
+	var deleted []string
+	err = c.Teardown(ctx, plan, time.Millisecond, func(s TeardownStep, err error) {
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+		}
+		deleted = append(deleted, s.ID)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"drill-1", "other-reader", "drill", "fsmt-1", "fs-drill"}; !slices.Equal(deleted, want) {
+		t.Errorf("deleted %v, want %v", deleted, want)
+	}
+	if !slices.Equal(rdsMock.deletedInstances, []string{"drill-1", "other-reader"}) || aws.ToString(rdsMock.deletedCluster.DBClusterIdentifier) != "drill" ||
+		!aws.ToBool(rdsMock.deletedCluster.SkipFinalSnapshot) || efsMock.deleted != "fs-drill" {
+		t.Errorf("unexpected deletions: instances %v, cluster %+v, file system %q", rdsMock.deletedInstances, rdsMock.deletedCluster, efsMock.deleted)
+	}
+
+	if _, err := c.PlanTeardown(ctx, "TestStack", ""); err == nil {
+		t.Error("a teardown without a label should be refused")
+	}
+}
+
+func TestSimulatedClient_Teardown(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	stack := fx.Stacks[0].Name
+	sim := c.client.(*simulatedAWS)
+	start := time.Now()
+	sim.now = func() time.Time { return start }
+
+	// A drill restores the file system to a new one and clones the cluster
+	points, err := c.ListRecoveryPoints(ctx, fx.Vaults[0], "EFS")
+	if err != nil || len(points) == 0 {
+		t.Fatalf("expected EFS recovery points, got %v", err)
+	}
+	jobID, err := c.StartRestoreJob(ctx, points[0], stack, fx.Vaults[0], RestoreOptions{KMSKeyID: "alias/drill"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cloneID, err := c.CloneCluster(ctx, stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.now = func() time.Time { return start.Add(time.Hour) }
+	status, err := c.GetRestoreJobStatus(ctx, jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arn, err := c.LabelRestoredResource(ctx, points[0], status, "drill")
+	if err != nil || arn != status.CreatedResourceARN {
+		t.Fatalf("LabelRestoredResource = %q, %v", arn, err)
+	}
+	if err := c.tagResource(ctx, "arn:aws:rds:"+fx.Region+":"+fx.AccountID+":cluster:"+cloneID, map[string]string{LabelTagKey: "drill"}); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := c.PlanTeardown(ctx, stack, "drill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Deletes() < 3 {
+		t.Fatalf("expected the clone, its instance, and the file system, got %+v", plan.Steps)
+	}
+	if err := c.Teardown(ctx, plan, time.Millisecond, func(TeardownStep, error) {}); err != nil {
+		t.Fatal(err)
+	}
+	if plan, err := c.PlanTeardown(ctx, stack, "drill"); err != nil || len(plan.Steps) != 0 {
+		t.Errorf("nothing labelled should be left, got %+v, %v", plan, err)
+	}
+}
+
+func TestSimulatedClient_TeardownRerun(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	// What an earlier teardown deleted, which RDS and EFS are still deleting
+	label := map[string]string{LabelTagKey: "drill"}
+	fx.Clusters = append(fx.Clusters, FixtureCluster{ID: "drill-old", Status: "deleting", Tags: label,
+		Instances: []FixtureInstance{{ID: "drill-old-1", Status: "deleting"}}})
+	fx.FileSystems = append(fx.FileSystems, FixtureFileSystem{ID: "fs-drill-old", LifeCycleState: "deleting", Tags: label})
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+	stack := fx.Stacks[0].Name
+	cloneID, err := c.CloneCluster(ctx, stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.tagResource(ctx, "arn:aws:rds:"+fx.Region+":"+fx.AccountID+":cluster:"+cloneID, label); err != nil {
+		t.Fatal(err)
+	}
+
+	skipped := func(plan *TeardownPlan) []string {
+		var ids []string
+		for _, s := range plan.Steps {
+			if s.Skip == "already deleting" {
+				ids = append(ids, s.ID)
+			}
+		}
+		return ids
+	}
+	wantSkipped := []string{"drill-old-1", "drill-old", "fs-drill-old"}
+	plan, err := c.PlanTeardown(ctx, stack, "drill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := skipped(plan); !slices.Equal(got, wantSkipped) {
+		t.Errorf("first plan leaves %v as already deleting, want %v", got, wantSkipped)
+	}
+	if plan.Deletes() != 2 {
+		t.Fatalf("expected the clone and its instance to be deleted, got %+v", plan.Steps)
+	}
+	for range 2 {
+		// A rerun of a plan whose resources are gone counts them as deleted
+		err := c.Teardown(ctx, plan, time.Millisecond, func(s TeardownStep, err error) {
+			if err != nil {
+				t.Errorf("%s: %v", s, err)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plan, err = c.PlanTeardown(ctx, stack, "drill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := skipped(plan); plan.Deletes() != 0 || !slices.Equal(got, wantSkipped) {
+		t.Errorf("second plan = %+v, want only %v left as already deleting", plan.Steps, wantSkipped)
+	}
+}
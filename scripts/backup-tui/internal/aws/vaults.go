@@ -0,0 +1,158 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements backup vault details, including logically
+// air-gapped vaults: vaults that only receive recovery points by copy, whose
+// recovery points cannot be deleted before their retention ends, and that no
+// backup plan rule targets directly.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+)
+
+// Backup vault types reported by AWS Backup.
+const (
+	VaultTypeStandard  = "BACKUP_VAULT"
+	VaultTypeAirGapped = "LOGICALLY_AIR_GAPPED_BACKUP_VAULT"
+)
+
+// VaultInfo describes a backup vault.
+type VaultInfo struct {
+	Name             string
+	ARN              string
+	Type             string // VaultTypeStandard or VaultTypeAirGapped
+	State            string // e.g. AVAILABLE, CREATING, FAILED (air-gapped vaults only)
+	Locked           bool   // Vault Lock is applied
+	MinRetentionDays int64  // Vault Lock minimum retention; 0 when unset
+	MaxRetentionDays int64  // Vault Lock maximum retention; 0 when unset
+	RecoveryPoints   int64
+}
+
+// AirGapped reports whether the vault is logically air-gapped.
+func (v *VaultInfo) AirGapped() bool {
+	return v != nil && v.Type == VaultTypeAirGapped
+}
+
+// Restrictions describes what the vault does not allow, for display before
+// operations that would be refused. Standard vaults have none.
+func (v *VaultInfo) Restrictions() []string {
+	if !v.AirGapped() {
+		return nil
+	}
+	retention := "until their retention ends"
+	if v.MinRetentionDays > 0 {
+		retention = fmt.Sprintf("until their retention ends (at least %d days)", v.MinRetentionDays)
+	}
+	return []string{
+		"Recovery points cannot be deleted " + retention,
+		"Backups arrive only by copy jobs from another vault; no backup plan rule or on-demand backup targets it",
+		"Restores use the IAM role of the backup plan that copies into this vault",
+	}
+}
+
+// CheckRetention reports whether the vault's Vault Lock allows recovery
+// points to be kept for l: no shorter than its minimum retention and no
+// longer than its maximum, which also rules out keeping them indefinitely.
+func (v *VaultInfo) CheckRetention(l Lifecycle) error {
+	if v == nil || !v.Locked {
+		return nil
+	}
+	if v.MinRetentionDays > 0 && l.DeleteAfterDays > 0 && l.DeleteAfterDays < v.MinRetentionDays {
+		return fmt.Errorf("vault %s is locked with a minimum retention of %d days", v.Name, v.MinRetentionDays)
+	}
+	if v.MaxRetentionDays > 0 && (l.DeleteAfterDays == 0 || l.DeleteAfterDays > v.MaxRetentionDays) {
+		return fmt.Errorf("vault %s is locked with a maximum retention of %d days", v.Name, v.MaxRetentionDays)
+	}
+	return nil
+}
+
+// ProtectedUntil returns when the vault's Vault Lock minimum retention
+// stops protecting rp from deletion, or the zero time if it never does.
+func (v *VaultInfo) ProtectedUntil(rp RecoveryPoint) time.Time {
+	if v == nil || !v.Locked || v.MinRetentionDays <= 0 {
+		return time.Time{}
+	}
+	return rp.CreationDate.AddDate(0, 0, int(v.MinRetentionDays))
+}
+
+// CheckDelete reports whether rp can be deleted at now: the vault's Vault
+// Lock refuses to delete recovery points younger than its minimum
+// retention, and the error says until when.
+func (v *VaultInfo) CheckDelete(rp RecoveryPoint, now time.Time) error {
+	if until := v.ProtectedUntil(rp); now.Before(until) {
+		return fmt.Errorf("vault %s is locked with a minimum retention of %d days: this backup cannot be deleted before %s",
+			v.Name, v.MinRetentionDays, until.Format("2006-01-02 15:04 MST"))
+	}
+	return nil
+}
+
+// DescribeVault returns the type, lock settings, and size of a backup vault.
+func (c *BackupClient) DescribeVault(ctx context.Context, vaultName string) (*VaultInfo, error) {
+	if vaultName == "" {
+		return nil, fmt.Errorf("vault name cannot be empty")
+	}
+	out, err := c.client.DescribeBackupVault(ctx, &backup.DescribeBackupVaultInput{BackupVaultName: aws.String(vaultName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe backup vault %s: %w", vaultName, err)
+	}
+	info := &VaultInfo{
+		Name:             aws.ToString(out.BackupVaultName),
+		ARN:              aws.ToString(out.BackupVaultArn),
+		Type:             string(out.VaultType),
+		State:            string(out.VaultState),
+		Locked:           aws.ToBool(out.Locked),
+		MinRetentionDays: aws.ToInt64(out.MinRetentionDays),
+		MaxRetentionDays: aws.ToInt64(out.MaxRetentionDays),
+		RecoveryPoints:   out.NumberOfRecoveryPoints,
+	}
+	if info.Type == "" {
+		info.Type = VaultTypeStandard
+	}
+	return info, nil
+}
+
+// ListVaults returns the backup vaults of the client's region, sorted by
+// name, with their type, lock settings, and recovery point counts.
+func (c *BackupClient) ListVaults(ctx context.Context) ([]VaultInfo, error) {
+	var vaults []VaultInfo
+	pages := backup.NewListBackupVaultsPaginator(c.client, &backup.ListBackupVaultsInput{})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list backup vaults: %w", err)
+		}
+		for _, v := range page.BackupVaultList {
+			info := VaultInfo{
+				Name:             aws.ToString(v.BackupVaultName),
+				ARN:              aws.ToString(v.BackupVaultArn),
+				Type:             string(v.VaultType),
+				State:            string(v.VaultState),
+				Locked:           aws.ToBool(v.Locked),
+				MinRetentionDays: aws.ToInt64(v.MinRetentionDays),
+				MaxRetentionDays: aws.ToInt64(v.MaxRetentionDays),
+				RecoveryPoints:   v.NumberOfRecoveryPoints,
+			}
+			if info.Type == "" {
+				info.Type = VaultTypeStandard
+			}
+			vaults = append(vaults, info)
+		}
+	}
+	sort.Slice(vaults, func(i, j int) bool { return vaults[i].Name < vaults[j].Name })
+	return vaults, nil
+}
+
+// vaultNameFromARN returns the vault name in a backup vault ARN
+// (arn:aws:backup:region:account:backup-vault:name).
+func vaultNameFromARN(arn string) string {
+	if _, name, ok := strings.Cut(arn, ":backup-vault:"); ok {
+		return name
+	}
+	return ""
+}
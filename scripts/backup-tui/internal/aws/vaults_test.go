@@ -0,0 +1,182 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+)
+
+func TestDescribeVault_AirGapped(t *testing.T) {
+	backupMock := &mockBackup{
+		describeVaultOutput: &backup.DescribeBackupVaultOutput{
+			BackupVaultName:  aws.String("dr-vault"),
+			VaultType:        backuptypes.VaultTypeLogicallyAirGappedBackupVault,
+			Locked:           aws.Bool(true),
+			MinRetentionDays: aws.Int64(30),
+		},
+	}
+	c := newTestClient(&mockCFN{}, backupMock, &mockRDS{})
+
+	info, err := c.DescribeVault(context.Background(), "dr-vault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.AirGapped() || !info.Locked {
+		t.Fatalf("unexpected vault info: %+v", info)
+	}
+	restrictions := info.Restrictions()
+	if len(restrictions) != 3 || !strings.Contains(restrictions[0], "at least 30 days") {
+		t.Errorf("unexpected restrictions: %q", restrictions)
+	}
+}
+
+func TestDescribeVault_StandardHasNoRestrictions(t *testing.T) {
+	c := newTestClient(&mockCFN{}, &mockBackup{describeVaultOutput: &backup.DescribeBackupVaultOutput{BackupVaultName: aws.String("v")}}, &mockRDS{})
+	info, err := c.DescribeVault(context.Background(), "v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Type != VaultTypeStandard || info.AirGapped() || info.Restrictions() != nil {
+		t.Errorf("a vault without a type is standard, got %+v", info)
+	}
+	if _, err := c.DescribeVault(context.Background(), ""); err == nil {
+		t.Error("expected error for an empty vault name")
+	}
+}
+
+func TestSimulatedClient_AirGappedVault(t *testing.T) {
+	fx, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fx.Vaults = append(fx.Vaults, "dr-vault")
+	fx.VaultTypes = map[string]string{"dr-vault": VaultTypeAirGapped}
+	fx.Plans[0].CopyTo = []string{"dr-vault"}
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+
+	info, err := c.DescribeVault(ctx, "dr-vault")
+	if err != nil || !info.AirGapped() || info.MinRetentionDays == 0 {
+		t.Fatalf("DescribeVault: %+v, %v", info, err)
+	}
+	pr, err := c.ResolvePlanRole(ctx, "dr-vault", false)
+	if err != nil || pr.Fallback || !pr.ViaCopy {
+		t.Errorf("restores from the air-gapped vault should use the copying plan's role, got %+v, %v", pr, err)
+	}
+
+	recorded, err := c.RecordFixtures(ctx, fx.Stacks[0].Name, "dr-vault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded.VaultTypes["dr-vault"] != VaultTypeAirGapped || len(recorded.Plans[0].CopyTo) != 1 {
+		t.Errorf("vault type and copy rules should be recorded, got %v, %+v", recorded.VaultTypes, recorded.Plans[0])
+	}
+}
+
+func TestVaultInfo_CheckRetention(t *testing.T) {
+	locked := &VaultInfo{Name: "locked", Locked: true, MinRetentionDays: 7, MaxRetentionDays: 365}
+	tests := []struct {
+		vault     *VaultInfo
+		lifecycle Lifecycle
+		wantErr   string
+	}{
+		{nil, Lifecycle{}, ""},
+		{&VaultInfo{Name: "standard"}, Lifecycle{}, ""},
+		{locked, Lifecycle{DeleteAfterDays: 30}, ""},
+		{locked, Lifecycle{DeleteAfterDays: 3}, "minimum retention of 7 days"},
+		{locked, Lifecycle{DeleteAfterDays: 400}, "maximum retention of 365 days"},
+		{locked, Lifecycle{}, "maximum retention"},
+	}
+	for _, tt := range tests {
+		err := tt.vault.CheckRetention(tt.lifecycle)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%v: unexpected error %v", tt.lifecycle, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%v: error %v, want %q", tt.lifecycle, err, tt.wantErr)
+		}
+	}
+}
+
+func TestVaultInfo_CheckDelete(t *testing.T) {
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rp := RecoveryPoint{CreationDate: created}
+	locked := &VaultInfo{Name: "locked", Locked: true, MinRetentionDays: 30}
+
+	if until := locked.ProtectedUntil(rp); !until.Equal(created.AddDate(0, 0, 30)) {
+		t.Errorf("ProtectedUntil = %v, want 30 days after creation", until)
+	}
+	if err := locked.CheckDelete(rp, created.AddDate(0, 0, 10)); err == nil || !strings.Contains(err.Error(), "2026-03-31") {
+		t.Errorf("a backup inside the minimum retention should not be deletable, got %v", err)
+	}
+	if err := locked.CheckDelete(rp, created.AddDate(0, 0, 31)); err != nil {
+		t.Errorf("a backup past the minimum retention should be deletable, got %v", err)
+	}
+
+	for _, v := range []*VaultInfo{nil, {Name: "standard"}, {Name: "max-only", Locked: true, MaxRetentionDays: 365}} {
+		if !v.ProtectedUntil(rp).IsZero() || v.CheckDelete(rp, created) != nil {
+			t.Errorf("%+v has no minimum retention", v)
+		}
+	}
+}
+
+func TestSimulatedClient_VaultLock(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	vault := fx.Vaults[0]
+	fx.VaultLocks = map[string]FixtureVaultLock{vault: {MinRetentionDays: 30}}
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+
+	info, err := c.DescribeVault(ctx, vault)
+	if err != nil || !info.Locked || info.MinRetentionDays != 30 || info.AirGapped() {
+		t.Fatalf("DescribeVault: %+v, %v", info, err)
+	}
+	points, _ := c.ListRecoveryPoints(ctx, vault, "")
+	if _, err := c.UpdateLifecycle(ctx, vault, points[0], Lifecycle{DeleteAfterDays: 7}); err == nil {
+		t.Error("a lifecycle shorter than the minimum retention should be refused")
+	}
+	if _, err := c.UpdateLifecycle(ctx, vault, points[0], Lifecycle{DeleteAfterDays: 60}); err != nil {
+		t.Errorf("UpdateLifecycle: %v", err)
+	}
+
+	recorded, err := c.RecordFixtures(ctx, fx.Stacks[0].Name, vault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded.VaultLocks[vault].MinRetentionDays != 30 {
+		t.Errorf("the vault lock should be recorded, got %+v", recorded.VaultLocks)
+	}
+}
+
+func TestListVaults(t *testing.T) {
+	backupMock := &mockBackup{listVaultsOutput: &backup.ListBackupVaultsOutput{BackupVaultList: []backuptypes.BackupVaultListMember{
+		{BackupVaultName: aws.String("prod-vault"), NumberOfRecoveryPoints: 42},
+		{BackupVaultName: aws.String("dr-vault"), VaultType: backuptypes.VaultTypeLogicallyAirGappedBackupVault, Locked: aws.Bool(true), NumberOfRecoveryPoints: 7},
+	}}}
+	c := newTestClient(&mockCFN{}, backupMock, &mockRDS{})
+
+	vaults, err := c.ListVaults(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vaults) != 2 || vaults[0].Name != "dr-vault" || vaults[1].Name != "prod-vault" {
+		t.Fatalf("vaults should be sorted by name, got %+v", vaults)
+	}
+	if !vaults[0].AirGapped() || !vaults[0].Locked || vaults[0].RecoveryPoints != 7 {
+		t.Errorf("unexpected air-gapped vault: %+v", vaults[0])
+	}
+	if vaults[1].Type != VaultTypeStandard || vaults[1].RecoveryPoints != 42 {
+		t.Errorf("a vault without a type should be standard, got %+v", vaults[1])
+	}
+
+	backupMock.listVaultsErr = errors.New("AccessDenied")
+	if _, err := c.ListVaults(context.Background()); err == nil || !strings.Contains(err.Error(), "failed to list backup vaults") {
+		t.Errorf("expected a wrapped error, got %v", err)
+	}
+}
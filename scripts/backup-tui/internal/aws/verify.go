@@ -0,0 +1,123 @@
+// Package aws provides AWS service clients for backup operations.
+// This file implements measuring a test restore for verification: the row
+// counts of tables in a restored Aurora cluster, read through the RDS Data
+// API, and the metered size of a restored EFS file system. Judging the
+// measurements is left to the caller.
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	rdsdatatypes "github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+)
+
+// TableCount is the number of rows in a table of a restored cluster, or
+// why they could not be counted.
+type TableCount struct {
+	Table string
+	Rows  int64
+	Err   error
+}
+
+// CountRows enables the RDS Data API on the restored cluster clusterARN and
+// counts the rows of each table in database, signed in with the database
+// credentials in the Secrets Manager secret secretARN. Tables are counted
+// one at a time; one that cannot be counted, e.g. because it is missing
+// from the restore, does not stop the others. Table names must be plain
+// identifiers. The cluster needs an available instance to answer.
+func (c *BackupClient) CountRows(ctx context.Context, clusterARN, secretARN, database string, tables []string) ([]TableCount, error) {
+	// Enabling an already enabled endpoint changes nothing
+	if _, err := c.rds.EnableHttpEndpoint(ctx, &rds.EnableHttpEndpointInput{ResourceArn: aws.String(clusterARN)}); err != nil {
+		return nil, fmt.Errorf("failed to enable the Data API on %s: %w", resourceName(clusterARN), err)
+	}
+	counts := make([]TableCount, 0, len(tables))
+	for _, table := range tables {
+		tc := TableCount{Table: table}
+		out, err := c.rdsData.ExecuteStatement(ctx, &rdsdata.ExecuteStatementInput{
+			ResourceArn: aws.String(clusterARN),
+			SecretArn:   aws.String(secretARN),
+			Database:    aws.String(database),
+			Sql:         aws.String("SELECT COUNT(*) FROM `" + table + "`"),
+		})
+		switch {
+		case err != nil:
+			tc.Err = fmt.Errorf("failed to count rows of %s: %w", table, err)
+		case len(out.Records) != 1 || len(out.Records[0]) != 1:
+			tc.Err = fmt.Errorf("unexpected result counting rows of %s", table)
+		default:
+			if v, ok := out.Records[0][0].(*rdsdatatypes.FieldMemberLongValue); ok {
+				tc.Rows = v.Value
+			} else {
+				tc.Err = fmt.Errorf("unexpected result counting rows of %s", table)
+			}
+		}
+		counts = append(counts, tc)
+	}
+	return counts, nil
+}
+
+// LookupTestRestore returns the completed restore job jobID and the
+// recovery point in vaultName it restored, for verifying the restore.
+func (c *BackupClient) LookupTestRestore(ctx context.Context, vaultName, jobID string) (*RestoreJobStatus, RecoveryPoint, error) {
+	status, err := c.GetRestoreJobStatus(ctx, jobID)
+	if err != nil {
+		return nil, RecoveryPoint{}, err
+	}
+	switch {
+	case status.Status != "COMPLETED":
+		return nil, RecoveryPoint{}, fmt.Errorf("restore job %s is %s; only completed restores can be verified", jobID, status.Status)
+	case status.CreatedResourceARN == "":
+		return nil, RecoveryPoint{}, fmt.Errorf("restore job %s did not report the resource it restored", jobID)
+	}
+	points, err := c.ListRecoveryPoints(ctx, vaultName)
+	if err != nil {
+		return nil, RecoveryPoint{}, err
+	}
+	for _, rp := range points {
+		if rp.RecoveryPointARN == status.RecoveryPointARN {
+			return status, rp, nil
+		}
+	}
+	return nil, RecoveryPoint{}, fmt.Errorf("restore job %s restored %s, which is not in vault %s", jobID, status.RecoveryPointARN, vaultName)
+}
+
+// RestoreToMeasure is a completed test restore and what to measure in it.
+type RestoreToMeasure struct {
+	ResourceType string // "RDS" or "EFS"
+	RestoredARN  string // Cluster or file system the restore created
+	InPlace      bool   // An EFS restore into the original file system
+	SecretARN    string // Database credentials for the row counts ("" skips them)
+	Database     string
+	Tables       []string
+}
+
+// RestoreMeasurement is what was measured in a test restore.
+type RestoreMeasurement struct {
+	Rows          []TableCount    // Tables counted; nil when none were
+	RowsErr       error           // Why no table could be counted
+	FileSystem    *FileSystemInfo // The restored file system; nil when not described
+	FileSystemErr error
+}
+
+// MeasureRestore measures r: the rows of its tables for an RDS restore
+// with database credentials, and the restored file system for an EFS
+// restore to a new file system. Errors are recorded in the measurement
+// for the checks they fail.
+func (c *BackupClient) MeasureRestore(ctx context.Context, r RestoreToMeasure) RestoreMeasurement {
+	var m RestoreMeasurement
+	switch r.ResourceType {
+	case "RDS":
+		if r.SecretARN != "" {
+			m.Rows, m.RowsErr = c.CountRows(ctx, r.RestoredARN, r.SecretARN, r.Database, r.Tables)
+		}
+	case "EFS":
+		if !r.InPlace {
+			m.FileSystem, m.FileSystemErr = c.DescribeFileSystem(ctx, resourceName(r.RestoredARN))
+		}
+	}
+	return m
+}
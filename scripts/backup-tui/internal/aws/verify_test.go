@@ -0,0 +1,64 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimulatedClient_MeasureRestore(t *testing.T) {
+	fx, _ := LoadFixtures("")
+	c := NewSimulatedBackupClient(fx)
+	ctx := context.Background()
+
+	// The fixture restore test created a cluster; its tables are counted
+	restored := "arn:aws:rds:us-west-2:123456789012:cluster:openemr-training-restore-test"
+	m := c.MeasureRestore(ctx, RestoreToMeasure{ResourceType: "RDS", RestoredARN: restored,
+		SecretARN: "arn:aws:secretsmanager:us-west-2:123456789012:secret:openemr-db", Database: "openemr",
+		Tables: []string{"patient_data", "users", "no_such_table"}})
+	if m.RowsErr != nil || len(m.Rows) != 3 {
+		t.Fatalf("expected three counts, got %+v", m)
+	}
+	if m.Rows[0].Rows != 1842 || m.Rows[1].Rows != 37 || m.Rows[0].Err != nil {
+		t.Errorf("unexpected counts %+v", m.Rows)
+	}
+	if m.Rows[2].Err == nil || !strings.Contains(m.Rows[2].Err.Error(), "doesn't exist") {
+		t.Errorf("a missing table should fail on its own, got %+v", m.Rows[2])
+	}
+	if m := c.MeasureRestore(ctx, RestoreToMeasure{ResourceType: "RDS", RestoredARN: restored}); m.Rows != nil || m.RowsErr != nil {
+		t.Errorf("without credentials nothing is counted, got %+v", m)
+	}
+	if _, err := c.CountRows(ctx, "arn:aws:rds:us-west-2:123456789012:cluster:elsewhere", "arn:secret", "openemr", []string{"users"}); err == nil {
+		t.Error("the Data API cannot be enabled on an unknown cluster")
+	}
+
+	// A restore to a new file system is as large as its backup
+	points, err := c.ListRecoveryPoints(ctx, fx.Vaults[0], "EFS")
+	if err != nil || len(points) == 0 {
+		t.Fatalf("expected EFS recovery points, got %v", err)
+	}
+	efsPoint := points[0]
+	start := time.Now()
+	sim := c.client.(*simulatedAWS)
+	sim.now = func() time.Time { return start }
+	jobID, err := c.StartRestoreJob(ctx, efsPoint, fx.Stacks[0].Name, fx.Vaults[0], RestoreOptions{KMSKeyID: "alias/restore-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.LookupTestRestore(ctx, fx.Vaults[0], jobID); err == nil || !strings.Contains(err.Error(), "only completed restores") {
+		t.Errorf("a running restore cannot be verified, got %v", err)
+	}
+	sim.now = func() time.Time { return start.Add(time.Hour) }
+	if _, rp, err := c.LookupTestRestore(ctx, fx.Vaults[0], jobID); err != nil || rp.RecoveryPointARN != efsPoint.RecoveryPointARN {
+		t.Errorf("expected the restored recovery point, got %+v, %v", rp, err)
+	}
+	status, err := c.GetRestoreJobStatus(ctx, jobID)
+	if err != nil || status.CreatedResourceARN == "" {
+		t.Fatalf("the restore should have completed, got %+v, %v", status, err)
+	}
+	m = c.MeasureRestore(ctx, RestoreToMeasure{ResourceType: "EFS", RestoredARN: status.CreatedResourceARN})
+	if m.FileSystemErr != nil || m.FileSystem.SizeBytes != efsPoint.BackupSizeInBytes || m.FileSystem.SizeMeasuredAt.IsZero() {
+		t.Errorf("unexpected file system %+v, %v", m.FileSystem, m.FileSystemErr)
+	}
+}
@@ -0,0 +1,27 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements the bearer token the JSON API requires.
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// minAPITokenLength is the shortest API token accepted, in bytes.
+const minAPITokenLength = 32
+
+// BearerToken returns the token every request to the JSON API must present.
+func (c *Config) BearerToken() (string, error) {
+	if c.APIToken.IsZero() {
+		return "", errors.New(`no "apiToken" in the config file; generate one with "openssl rand -hex 32 | backup-tui config set-secret api-token" and set "apiToken": {"keyring": "api-token"}`)
+	}
+	token, err := c.APIToken.Reveal()
+	if err != nil {
+		return "", err
+	}
+	if len(token) < minAPITokenLength {
+		return "", fmt.Errorf("apiToken must be at least %d characters, got %d", minAPITokenLength, len(token))
+	}
+	return token, nil
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfig_BearerToken(t *testing.T) {
+	if _, err := (&Config{}).BearerToken(); err == nil || !strings.Contains(err.Error(), "apiToken") {
+		t.Errorf("a missing token should explain how to set one, got %v", err)
+	}
+	if _, err := (&Config{APIToken: Secret{Value: "short"}}).BearerToken(); err == nil {
+		t.Error("a short token should be rejected")
+	}
+
+	token := strings.Repeat("t", 64)
+	if err := SetSecret("api-token", token); err != nil {
+		t.Fatal(err)
+	}
+	got, err := (&Config{APIToken: Secret{Keyring: "api-token"}}).BearerToken()
+	if err != nil || got != token {
+		t.Errorf("BearerToken() = %q, %v; want the keyring value", got, err)
+	}
+}
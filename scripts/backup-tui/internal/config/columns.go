@@ -0,0 +1,44 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements the columns of the backup list: which the list
+// shows, and in what order.
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ListColumnNames are the columns the backup list can show, after the mark
+// and the age dot that always lead it.
+var ListColumnNames = []string{"type", "resource", "created", "size", "created-by", "status", "tier", "vault", "rule", "tags", "arn"}
+
+// DefaultListColumns are the columns the backup list shows without
+// listColumns in the config.
+var DefaultListColumns = []string{"type", "resource", "created", "size", "created-by"}
+
+// ListColumns returns the columns the backup list shows, in order.
+func (c *Config) ListColumns() []string {
+	if c == nil || len(c.Columns) == 0 {
+		return DefaultListColumns
+	}
+	return c.Columns
+}
+
+// ValidateListColumns reports a column that is not one of ListColumnNames
+// or is listed twice, or an empty list.
+func ValidateListColumns(columns []string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("at least one column is needed")
+	}
+	for i, col := range columns {
+		if !slices.Contains(ListColumnNames, col) {
+			return fmt.Errorf("unknown column %q (want some of %s)", col, strings.Join(ListColumnNames, ", "))
+		}
+		if slices.Contains(columns[:i], col) {
+			return fmt.Errorf("column %q is listed twice", col)
+		}
+	}
+	return nil
+}
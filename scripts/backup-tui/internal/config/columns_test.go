@@ -0,0 +1,31 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestConfig_ListColumns(t *testing.T) {
+	if got := (*Config)(nil).ListColumns(); !slices.Equal(got, DefaultListColumns) {
+		t.Errorf("a nil config should show the default columns, got %v", got)
+	}
+	c := &Config{Columns: []string{"type", "created", "tier"}}
+	if got := c.ListColumns(); !slices.Equal(got, c.Columns) {
+		t.Errorf("listColumns should replace the defaults, got %v", got)
+	}
+}
+
+func TestValidateListColumns(t *testing.T) {
+	if err := ValidateListColumns(ListColumnNames); err != nil {
+		t.Errorf("every column should be valid: %v", err)
+	}
+	for name, columns := range map[string][]string{
+		"empty":   nil,
+		"unknown": {"type", "owner"},
+		"twice":   {"type", "size", "type"},
+	} {
+		if err := ValidateListColumns(columns); err == nil {
+			t.Errorf("%s: expected an error for %v", name, columns)
+		}
+	}
+}
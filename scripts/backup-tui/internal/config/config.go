@@ -0,0 +1,241 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements loading the file and the per-resource-type recovery
+// objectives: the target recovery point objective (RPO), the maximum age of
+// a resource's newest backup, and recovery time objective (RTO), the
+// maximum time a restore may take.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// configFile is the name of the configuration file in the config directory.
+const configFile = "config.json"
+
+// Config is the contents of the configuration file. The zero Config sets no
+// policies. Sensitive values are Secrets, kept in the OS keyring.
+type Config struct {
+	// Targets are the recovery objectives per AWS Backup resource type,
+	// e.g. "RDS" or "EFS".
+	Targets map[string]Target `json:"targets,omitempty"`
+
+	// Protect lists recovery points the tool refuses to delete.
+	Protect []ProtectRule `json:"protect,omitempty"`
+
+	// DeleteGrace is how long a confirmed deletion waits before it is sent
+	// to AWS, during which it can be undone (1m when unset).
+	DeleteGrace Duration `json:"deleteGrace,omitempty"`
+
+	// CrossAccount is where "backup-tui dr" recovers to.
+	CrossAccount *CrossAccount `json:"crossAccount,omitempty"`
+
+	// Freeze lists change freeze windows during which restores are refused
+	// unless overridden.
+	Freeze []FreezeWindow `json:"freeze,omitempty"`
+
+	// Prune is the policy "backup-tui prune" applies to the on-demand
+	// backups the tool created, unless overridden with flags.
+	Prune *PrunePolicy `json:"prune,omitempty"`
+
+	// DRRegions are the regions the vault's cross-region copy rules copy
+	// to, which "backup-tui dr scan" checks, e.g. ["us-east-1"].
+	DRRegions []string `json:"drRegions,omitempty"`
+
+	// Webhooks are notified when workflow steps start, complete, or fail.
+	Webhooks []Webhook `json:"webhooks,omitempty"`
+
+	// PlanSigningKey signs the restore plans "backup-tui plan" writes, so
+	// "backup-tui apply" only executes plans that were not edited after
+	// review. Everyone who plans or applies restores needs the same key.
+	PlanSigningKey Secret `json:"planSigningKey,omitzero"`
+
+	// APIToken is the bearer token every request to the JSON API
+	// (backup-tui -api) must present; the API does not start without one.
+	APIToken Secret `json:"apiToken,omitzero"`
+
+	// TestedEngineVersions are the Aurora engine versions OpenEMR has been
+	// tested against, e.g. ["8.0.mysql_aurora.3.12.0"]. A restore that
+	// upgrades the engine to another version is flagged.
+	TestedEngineVersions []string `json:"testedEngineVersions,omitempty"`
+
+	// SharedRestoreLock also keeps the advisory restore lock as a tag on
+	// the stack's vault, so sessions on other machines see a restore in
+	// progress, not only those sharing this machine's config directory.
+	SharedRestoreLock bool `json:"sharedRestoreLock,omitempty"`
+
+	// Journal is the shared table every operator's session records the
+	// restores, exports, clones, and backups it starts in (nil for none).
+	Journal *Journal `json:"journal,omitempty"`
+
+	// Verify is the checks run against test restores ("backup-tui verify",
+	// V in the jobs view); nil runs the defaults.
+	Verify *Verification `json:"verify,omitempty"`
+
+	// IdleLock locks the TUI after this long without a key press, so the
+	// backup inventory does not stay visible on an unattended workstation
+	// (0 for never). -lock-after can shorten it but not lengthen it.
+	IdleLock Duration `json:"idleLock,omitempty"`
+
+	// Theme is the TUI's status colors, e.g. "colorblind" (empty for the
+	// default). -theme takes precedence.
+	Theme string `json:"theme,omitempty"`
+
+	// Columns are the columns of the backup list, in order, e.g.
+	// ["type", "resource", "created", "status", "tier"] (empty for the
+	// defaults). C in the list picks them per environment instead.
+	Columns []string `json:"listColumns,omitempty"`
+}
+
+// Target is the recovery objectives of a resource type. A zero objective is
+// not checked.
+type Target struct {
+	RPO Duration `json:"rpo,omitempty"`
+	RTO Duration `json:"rto,omitempty"`
+}
+
+// Duration is a duration written in the configuration file as a number of
+// days ("2d") or a Go duration ("26h", "90m").
+type Duration time.Duration
+
+// UnmarshalJSON parses the duration.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("expected a duration such as \"26h\" or \"2d\", got %s", data)
+	}
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON formats the duration as a Go duration.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// ParseDuration parses a positive number of days ("2d") or Go duration
+// ("26h").
+func ParseDuration(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: expected e.g. 2d or 26h", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid duration %q: expected e.g. 2d or 26h", s)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %q", s)
+	}
+	return d, nil
+}
+
+// DefaultPath returns the configuration file in the user's config
+// directory, e.g. ~/.config/backup-tui/config.json on Linux.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "backup-tui", configFile), nil
+}
+
+// Load reads the configuration file. A missing file is the zero Config.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := c.validateProtect(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err := c.validateFreeze(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if c.Prune != nil {
+		if err := c.Prune.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+	if err := ValidateRegions(c.DRRegions); err != nil {
+		return nil, fmt.Errorf("invalid config %s: drRegions: %w", path, err)
+	}
+	if err := c.validateWebhooks(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if c.Journal != nil {
+		if err := c.Journal.validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+	if c.Verify != nil {
+		if err := c.Verify.validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+	if c.Columns != nil {
+		if err := ValidateListColumns(c.Columns); err != nil {
+			return nil, fmt.Errorf("invalid config %s: listColumns: %w", path, err)
+		}
+	}
+	return &c, nil
+}
+
+// ValidateRegions reports a region name that aws.ValidRegion refuses, as
+// the -region flag is checked, or one that is listed twice.
+func ValidateRegions(regions []string) error {
+	seen := make(map[string]bool, len(regions))
+	for _, r := range regions {
+		if !aws.ValidRegion(r) {
+			return fmt.Errorf("%q is not a region name such as \"us-east-1\"", r)
+		}
+		if seen[r] {
+			return fmt.Errorf("region %s is listed twice", r)
+		}
+		seen[r] = true
+	}
+	return nil
+}
+
+// Target returns the recovery objectives of resourceType, matched without
+// regard to case. Types without a target return the zero Target.
+func (c *Config) Target(resourceType string) Target {
+	if c == nil {
+		return Target{}
+	}
+	if t, ok := c.Targets[resourceType]; ok {
+		return t
+	}
+	for name, t := range c.Targets {
+		if strings.EqualFold(name, resourceType) {
+			return t
+		}
+	}
+	return Target{}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad_Missing(t *testing.T) {
+	c, err := Load(filepath.Join(t.TempDir(), "none", configFile))
+	if err != nil || c == nil || len(c.Targets) != 0 {
+		t.Errorf("a missing config file should be empty, got %+v, %v", c, err)
+	}
+}
+
+func TestLoad_Targets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFile)
+	data := `{"targets": {"RDS": {"rpo": "26h", "rto": "2h"}, "EFS": {"rpo": "2d"}}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Target("RDS"); time.Duration(got.RPO) != 26*time.Hour || time.Duration(got.RTO) != 2*time.Hour {
+		t.Errorf("RDS target = %+v", got)
+	}
+	if got := c.Target("efs"); time.Duration(got.RPO) != 48*time.Hour || got.RTO != 0 {
+		t.Errorf("resource types should match without regard to case, got %+v", got)
+	}
+	if got := c.Target("DynamoDB"); got != (Target{}) {
+		t.Errorf("a type without a target should have none, got %+v", got)
+	}
+	if got := (*Config)(nil).Target("RDS"); got != (Target{}) {
+		t.Errorf("a nil config should set no targets, got %+v", got)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"corrupt":  "{not json",
+		"number":   `{"targets": {"RDS": {"rpo": 26}}}`,
+		"unit":     `{"targets": {"RDS": {"rpo": "26 hours"}}}`,
+		"negative": `{"targets": {"RDS": {"rto": "-1h"}}}`,
+		"region":   `{"drRegions": ["US-EAST-1"]}`,
+		"twice":    `{"drRegions": ["us-east-1", "us-east-1"]}`,
+		"column":   `{"listColumns": ["type", "owner"]}`,
+		"columns":  `{"listColumns": []}`,
+	} {
+		path := filepath.Join(t.TempDir(), configFile)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s: expected an error loading %s", name, data)
+		}
+	}
+}
+
+func TestValidateRegions(t *testing.T) {
+	if err := ValidateRegions([]string{"us-east-1", "us-gov-west-1", "ap-southeast-2"}); err != nil {
+		t.Errorf("valid regions rejected: %v", err)
+	}
+	for _, r := range []string{"", "us-east", "us-east-", "useast1", "us-east-1a"} {
+		if err := ValidateRegions([]string{r}); err == nil {
+			t.Errorf("%q should be rejected", r)
+		}
+	}
+}
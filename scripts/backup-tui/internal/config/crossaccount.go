@@ -0,0 +1,44 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements the cross-account recovery target: a separate AWS
+// account that recovery points are copied to and restored in when the
+// stack's own account is compromised or lost.
+package config
+
+import "fmt"
+
+// CrossAccount is the account "backup-tui dr" copies recovery points to and
+// restores them in.
+type CrossAccount struct {
+	// AccountID is the 12-digit ID of the recovery account.
+	AccountID string `json:"accountId"`
+	// Region of the recovery vault; the stack's region when empty.
+	Region string `json:"region,omitempty"`
+	// Vault is the backup vault in the recovery account that copies go to.
+	// Its access policy must allow backup:CopyIntoBackupVault from the
+	// stack's account.
+	Vault string `json:"vault"`
+	// RoleARN is the role in the recovery account the tool assumes to
+	// check the vault and restore there.
+	RoleARN string `json:"roleArn"`
+	// ExternalID is the external ID the role's trust policy requires.
+	ExternalID Secret `json:"externalId,omitzero"`
+	// RestoreRoleARN is the IAM role AWS Backup restores with in the
+	// recovery account; its AWSBackupDefaultServiceRole when empty.
+	RestoreRoleARN string `json:"restoreRoleArn,omitempty"`
+}
+
+// Validate reports a missing or malformed required setting.
+func (c *CrossAccount) Validate() error {
+	switch {
+	case c == nil:
+		return fmt.Errorf(`no "crossAccount" section in the config file`)
+	case len(c.AccountID) != 12:
+		return fmt.Errorf("crossAccount.accountId must be a 12-digit account ID, got %q", c.AccountID)
+	case c.Vault == "":
+		return fmt.Errorf("crossAccount.vault is required")
+	case c.RoleARN == "":
+		return fmt.Errorf("crossAccount.roleArn is required")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoad_CrossAccount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFile)
+	data := `{"crossAccount": {"accountId": "210987654321", "vault": "openemr-dr",
+		"roleArn": "arn:aws:iam::210987654321:role/backup-tui-dr", "externalId": "ext-123"}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CrossAccount.Validate(); err != nil {
+		t.Errorf("complete section should be valid: %v", err)
+	}
+	if v, _ := c.CrossAccount.ExternalID.Reveal(); v != "ext-123" {
+		t.Errorf("external ID = %q, want ext-123", v)
+	}
+	if names := c.PlaintextSecrets(); !slices.Contains(names, "crossAccount.externalId") {
+		t.Errorf("a plaintext external ID should be reported, got %v", names)
+	}
+}
+
+func TestCrossAccount_Validate(t *testing.T) {
+	valid := CrossAccount{AccountID: "210987654321", Vault: "openemr-dr", RoleARN: "arn:aws:iam::210987654321:role/dr"}
+	for name, ca := range map[string]*CrossAccount{
+		"missing":    nil,
+		"account ID": {AccountID: "2109", Vault: valid.Vault, RoleARN: valid.RoleARN},
+		"vault":      {AccountID: valid.AccountID, RoleARN: valid.RoleARN},
+		"role":       {AccountID: valid.AccountID, Vault: valid.Vault},
+	} {
+		if err := ca.Validate(); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
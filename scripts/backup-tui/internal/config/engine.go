@@ -0,0 +1,18 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements the Aurora engine versions OpenEMR has been tested
+// against.
+package config
+
+import "slices"
+
+// EngineVersionTested reports whether OpenEMR has been tested against the
+// Aurora engine version. Without testedEngineVersions in the config, the
+// version the stack's cluster runs (live) is the tested one; when that is
+// unknown too, every version is taken as tested.
+func (c *Config) EngineVersionTested(version, live string) bool {
+	if c != nil && len(c.TestedEngineVersions) > 0 {
+		return slices.Contains(c.TestedEngineVersions, version)
+	}
+	return live == "" || version == live
+}
@@ -0,0 +1,19 @@
+package config
+
+import "testing"
+
+func TestConfig_EngineVersionTested(t *testing.T) {
+	const live, next = "8.0.mysql_aurora.3.08.0", "8.0.mysql_aurora.3.09.0"
+	var none *Config
+	if !none.EngineVersionTested(live, live) || none.EngineVersionTested(next, live) {
+		t.Error("without a config, only the live cluster's version should be tested")
+	}
+	if !none.EngineVersionTested(next, "") {
+		t.Error("without a config or live cluster, every version should be taken as tested")
+	}
+
+	c := &Config{TestedEngineVersions: []string{next}}
+	if !c.EngineVersionTested(next, live) || c.EngineVersionTested(live, live) {
+		t.Error("testedEngineVersions should replace the live cluster's version")
+	}
+}
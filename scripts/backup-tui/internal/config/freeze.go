@@ -0,0 +1,148 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements change freeze windows: recurring times, such as a
+// clinic's business hours, during which restores are refused unless the
+// operator explicitly overrides the freeze.
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FreezeWindow is a daily period during which restores are frozen. A window
+// whose end is before its start runs past midnight, e.g. 22:00 to 06:00.
+type FreezeWindow struct {
+	// Name identifies the window when a restore is refused.
+	Name string `json:"name,omitempty"`
+	// Days the window starts on, e.g. ["Mon", "Tue"]; every day if empty.
+	Days []string `json:"days,omitempty"`
+	// Start and End are the local times the window starts and ends, "15:04".
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// TimeZone is an IANA time zone, e.g. "America/New_York"; the system
+	// time zone if empty.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// weekdays are the day names accepted in FreezeWindow.Days.
+var weekdays = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// String describes the window, e.g. `"clinic hours" (Mon, Tue 08:00-18:00 America/New_York)`.
+func (w FreezeWindow) String() string {
+	desc := "daily"
+	if len(w.Days) > 0 {
+		desc = strings.Join(w.Days, ", ")
+	}
+	desc += fmt.Sprintf(" %s-%s", w.Start, w.End)
+	if w.TimeZone != "" {
+		desc += " " + w.TimeZone
+	}
+	if w.Name != "" {
+		return fmt.Sprintf("%q (%s)", w.Name, desc)
+	}
+	return desc
+}
+
+// location returns the window's time zone.
+func (w FreezeWindow) location() (*time.Location, error) {
+	if w.TimeZone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", w.TimeZone)
+	}
+	return loc, nil
+}
+
+// clock parses a "15:04" time of day as an offset from midnight.
+func clock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: expected e.g. 08:00", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// validate checks the window's times, days, and time zone.
+func (w FreezeWindow) validate() error {
+	start, err := clock(w.Start)
+	if err != nil {
+		return err
+	}
+	end, err := clock(w.End)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return fmt.Errorf("start and end are both %s", w.Start)
+	}
+	for _, d := range w.Days {
+		if !slices.Contains(weekdays, d) {
+			return fmt.Errorf("invalid day %q: expected one of %s", d, strings.Join(weekdays, ", "))
+		}
+	}
+	_, err = w.location()
+	return err
+}
+
+// activeUntil returns when the window's current period ends, or the zero
+// time if now is outside the window.
+func (w FreezeWindow) activeUntil(now time.Time) time.Time {
+	loc, err := w.location()
+	if err != nil {
+		return time.Time{}
+	}
+	start, err := clock(w.Start)
+	if err != nil {
+		return time.Time{}
+	}
+	end, err := clock(w.End)
+	if err != nil {
+		return time.Time{}
+	}
+	length := end - start
+	if length < 0 {
+		length += 24 * time.Hour
+	}
+	now = now.In(loc)
+	// A period that runs past midnight may have started the day before
+	for _, back := range []int{0, -1} {
+		day := time.Date(now.Year(), now.Month(), now.Day()+back, 0, 0, 0, 0, loc)
+		if len(w.Days) > 0 && !slices.Contains(w.Days, weekdays[day.Weekday()]) {
+			continue
+		}
+		from := day.Add(start)
+		if until := from.Add(length); !now.Before(from) && now.Before(until) {
+			return until
+		}
+	}
+	return time.Time{}
+}
+
+// ActiveFreeze returns the first freeze window in effect at now and when
+// its current period ends, or nil if restores are not frozen.
+func (c *Config) ActiveFreeze(now time.Time) (*FreezeWindow, time.Time) {
+	if c == nil {
+		return nil, time.Time{}
+	}
+	for i := range c.Freeze {
+		if until := c.Freeze[i].activeUntil(now); !until.IsZero() {
+			return &c.Freeze[i], until
+		}
+	}
+	return nil, time.Time{}
+}
+
+// validateFreeze rejects windows that cannot be matched.
+func (c *Config) validateFreeze() error {
+	for i, w := range c.Freeze {
+		if err := w.validate(); err != nil {
+			return fmt.Errorf("freeze window %d: %w", i, err)
+		}
+	}
+	return nil
+}
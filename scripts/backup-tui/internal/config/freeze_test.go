@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestActiveFreeze(t *testing.T) {
+	c := &Config{Freeze: []FreezeWindow{
+		{Name: "clinic hours", Days: []string{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "08:00", End: "18:00", TimeZone: "UTC"},
+		{Name: "overnight batch", Days: []string{"Sat"}, Start: "22:00", End: "02:00", TimeZone: "UTC"},
+	}}
+	// 2026-06-01 is a Monday
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 6, day, hour, minute, 0, 0, time.UTC) }
+	for _, tc := range []struct {
+		name  string
+		now   time.Time
+		want  string
+		until time.Time
+	}{
+		{"weekday business hours", at(1, 9, 30), "clinic hours", at(1, 18, 0)},
+		{"at the start", at(1, 8, 0), "clinic hours", at(1, 18, 0)},
+		{"at the end", at(1, 18, 0), "", time.Time{}},
+		{"weekday evening", at(2, 20, 0), "", time.Time{}},
+		{"Saturday daytime", at(6, 9, 30), "", time.Time{}},
+		{"Saturday night", at(6, 23, 0), "overnight batch", at(7, 2, 0)},
+		{"after midnight into Sunday", at(7, 1, 0), "overnight batch", at(7, 2, 0)},
+		{"after midnight into Monday", at(8, 1, 0), "", time.Time{}},
+	} {
+		w, until := c.ActiveFreeze(tc.now)
+		switch {
+		case tc.want == "" && w != nil:
+			t.Errorf("%s: should not be frozen, got %s", tc.name, w)
+		case tc.want != "" && (w == nil || w.Name != tc.want || !until.Equal(tc.until)):
+			t.Errorf("%s: should be frozen by %q until %v, got %v until %v", tc.name, tc.want, tc.until, w, until)
+		}
+	}
+	if w, _ := (*Config)(nil).ActiveFreeze(at(1, 9, 0)); w != nil {
+		t.Error("a nil config should freeze nothing")
+	}
+}
+
+func TestActiveFreeze_TimeZone(t *testing.T) {
+	c := &Config{Freeze: []FreezeWindow{{Start: "08:00", End: "18:00", TimeZone: "America/New_York"}}}
+	// 13:00 UTC is 09:00 in New York in June
+	if w, _ := c.ActiveFreeze(time.Date(2026, 6, 1, 13, 0, 0, 0, time.UTC)); w == nil {
+		t.Error("09:00 New York time should be frozen")
+	}
+	if w, _ := c.ActiveFreeze(time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)); w != nil {
+		t.Error("05:00 New York time should not be frozen")
+	}
+}
+
+func TestLoad_RejectsInvalidFreeze(t *testing.T) {
+	for name, data := range map[string]string{
+		"time":      `{"freeze": [{"start": "8am", "end": "18:00"}]}`,
+		"same":      `{"freeze": [{"start": "08:00", "end": "08:00"}]}`,
+		"day":       `{"freeze": [{"days": ["Monday"], "start": "08:00", "end": "18:00"}]}`,
+		"time zone": `{"freeze": [{"start": "08:00", "end": "18:00", "timeZone": "Mars/Olympus"}]}`,
+	} {
+		path := filepath.Join(t.TempDir(), configFile)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements the shared operation journal: the DynamoDB table in
+// which every operator's session records the operations it starts.
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Journal is the shared operation journal.
+type Journal struct {
+	// Table is the name or ARN of the DynamoDB table, with a string
+	// partition key "env" and a string sort key "at".
+	Table string `json:"table"`
+}
+
+// JournalTable returns the journal's table, or "" when none is set.
+func (c *Config) JournalTable() string {
+	if c == nil || c.Journal == nil {
+		return ""
+	}
+	return c.Journal.Table
+}
+
+// validate reports a missing or malformed table name.
+func (j *Journal) validate() error {
+	if strings.HasPrefix(j.Table, "arn:") {
+		return nil
+	}
+	if len(j.Table) < 3 || len(j.Table) > 255 {
+		return fmt.Errorf("journal.table must be a DynamoDB table name of 3 to 255 characters, got %q", j.Table)
+	}
+	for _, r := range j.Table {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.') {
+			return fmt.Errorf("journal.table %q may only hold letters, digits, \"_\", \"-\", and \".\"", j.Table)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad_Journal(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		json  string
+		table string
+		ok    bool
+	}{
+		{`{}`, "", true},
+		{`{"journal": {"table": "backup-tui-journal"}}`, "backup-tui-journal", true},
+		{`{"journal": {"table": "arn:aws:dynamodb:us-west-2:123456789012:table/ops"}}`, "arn:aws:dynamodb:us-west-2:123456789012:table/ops", true},
+		{`{"journal": {}}`, "", false},
+		{`{"journal": {"table": "ops journal"}}`, "", false},
+	} {
+		path := filepath.Join(dir, "config.json")
+		if err := os.WriteFile(path, []byte(tc.json), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		c, err := Load(path)
+		if (err == nil) != tc.ok {
+			t.Errorf("Load(%s) error = %v, want ok %v", tc.json, err, tc.ok)
+			continue
+		}
+		if err == nil && c.JournalTable() != tc.table {
+			t.Errorf("Load(%s).JournalTable() = %q, want %q", tc.json, c.JournalTable(), tc.table)
+		}
+	}
+	var none *Config
+	if none.JournalTable() != "" {
+		t.Error("without a config there is no journal")
+	}
+}
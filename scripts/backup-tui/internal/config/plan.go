@@ -0,0 +1,27 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements the key restore plans are signed with.
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// minPlanKeyLength is the shortest plan signing key accepted, in bytes.
+const minPlanKeyLength = 32
+
+// PlanKey returns the restore plan signing key.
+func (c *Config) PlanKey() ([]byte, error) {
+	if c.PlanSigningKey.IsZero() {
+		return nil, errors.New(`no "planSigningKey" in the config file; generate one with "openssl rand -hex 32 | backup-tui config set-secret plan-signing-key" and set "planSigningKey": {"keyring": "plan-signing-key"}`)
+	}
+	key, err := c.PlanSigningKey.Reveal()
+	if err != nil {
+		return nil, err
+	}
+	if len(key) < minPlanKeyLength {
+		return nil, fmt.Errorf("planSigningKey must be at least %d characters, got %d", minPlanKeyLength, len(key))
+	}
+	return []byte(key), nil
+}
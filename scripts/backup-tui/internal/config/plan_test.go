@@ -0,0 +1,24 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfig_PlanKey(t *testing.T) {
+	if _, err := (&Config{}).PlanKey(); err == nil || !strings.Contains(err.Error(), "planSigningKey") {
+		t.Errorf("a missing key should explain how to set one, got %v", err)
+	}
+	if _, err := (&Config{PlanSigningKey: Secret{Value: "short"}}).PlanKey(); err == nil {
+		t.Error("a short key should be rejected")
+	}
+
+	key := strings.Repeat("k", 64)
+	if err := SetSecret("plan-signing-key", key); err != nil {
+		t.Fatal(err)
+	}
+	got, err := (&Config{PlanSigningKey: Secret{Keyring: "plan-signing-key"}}).PlanKey()
+	if err != nil || string(got) != key {
+		t.Errorf("PlanKey() = %q, %v; want the keyring value", got, err)
+	}
+}
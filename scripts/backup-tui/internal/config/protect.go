@@ -0,0 +1,134 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements deletion protection: rules matching recovery points
+// by tag, resource type, age, or ARN that the tool refuses to delete or
+// shorten the retention of, whatever the operator confirms, and the grace
+// period in which a confirmed deletion can still be undone.
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ProtectRule matches recovery points the tool must not delete. A recovery
+// point matches when it meets every condition the rule sets.
+type ProtectRule struct {
+	// Name identifies the rule when a deletion is refused.
+	Name string `json:"name,omitempty"`
+	// Tags the recovery point must have, e.g. {"hold": "true"}.
+	Tags map[string]string `json:"tags,omitempty"`
+	// ResourceType is the AWS Backup resource type, e.g. "RDS".
+	ResourceType string `json:"resourceType,omitempty"`
+	// OlderThan matches recovery points created at least this long ago.
+	OlderThan Duration `json:"olderThan,omitempty"`
+	// RecoveryPoints pins recovery points by ARN.
+	RecoveryPoints []string `json:"recoveryPoints,omitempty"`
+}
+
+// ProtectedPoint is what protection rules are matched against.
+type ProtectedPoint struct {
+	ARN          string
+	ResourceType string
+	Created      time.Time
+	Tags         map[string]string // Nil when unknown
+}
+
+// String describes the rule, e.g. `"legal" (tag hold=true)`.
+func (r ProtectRule) String() string {
+	var conds []string
+	for _, k := range slices.Sorted(maps.Keys(r.Tags)) {
+		conds = append(conds, fmt.Sprintf("tag %s=%s", k, r.Tags[k]))
+	}
+	if r.ResourceType != "" {
+		conds = append(conds, r.ResourceType)
+	}
+	if r.OlderThan > 0 {
+		conds = append(conds, "older than "+time.Duration(r.OlderThan).String())
+	}
+	if len(r.RecoveryPoints) > 0 {
+		conds = append(conds, "pinned")
+	}
+	desc := strings.Join(conds, ", ")
+	if r.Name != "" {
+		return fmt.Sprintf("%q (%s)", r.Name, desc)
+	}
+	return desc
+}
+
+// empty reports whether the rule sets no condition.
+func (r ProtectRule) empty() bool {
+	return len(r.Tags) == 0 && r.ResourceType == "" && r.OlderThan == 0 && len(r.RecoveryPoints) == 0
+}
+
+// matches reports whether p meets every condition of the rule at now.
+func (r ProtectRule) matches(p ProtectedPoint, now time.Time) bool {
+	if r.empty() {
+		return false
+	}
+	for k, v := range r.Tags {
+		if got, ok := p.Tags[k]; !ok || got != v {
+			return false
+		}
+	}
+	if r.ResourceType != "" && !strings.EqualFold(r.ResourceType, p.ResourceType) {
+		return false
+	}
+	if r.OlderThan > 0 && now.Sub(p.Created) < time.Duration(r.OlderThan) {
+		return false
+	}
+	if len(r.RecoveryPoints) > 0 && !slices.Contains(r.RecoveryPoints, p.ARN) {
+		return false
+	}
+	return true
+}
+
+// Protection returns the first rule protecting p from deletion at now, or
+// nil if none does.
+func (c *Config) Protection(p ProtectedPoint, now time.Time) *ProtectRule {
+	if c == nil {
+		return nil
+	}
+	for i := range c.Protect {
+		if c.Protect[i].matches(p, now) {
+			return &c.Protect[i]
+		}
+	}
+	return nil
+}
+
+// ProtectsByTag reports whether any protection rule matches on tags, so
+// a recovery point's tags must be known before it can be deleted.
+func (c *Config) ProtectsByTag() bool {
+	if c == nil {
+		return false
+	}
+	return slices.ContainsFunc(c.Protect, func(r ProtectRule) bool { return len(r.Tags) > 0 })
+}
+
+// DefaultDeleteGrace is the grace period of deletions when the config file
+// sets none.
+const DefaultDeleteGrace = time.Minute
+
+// DeletionGrace returns how long a confirmed deletion waits before it is
+// sent to AWS.
+func (c *Config) DeletionGrace() time.Duration {
+	if c == nil || c.DeleteGrace <= 0 {
+		return DefaultDeleteGrace
+	}
+	return time.Duration(c.DeleteGrace)
+}
+
+// validateProtect rejects rules without conditions, which are most likely a
+// mistake.
+func (c *Config) validateProtect() error {
+	for i, r := range c.Protect {
+		if r.empty() {
+			return fmt.Errorf("protect rule %d sets no condition: add tags, resourceType, olderThan, or recoveryPoints", i)
+		}
+	}
+	return nil
+}
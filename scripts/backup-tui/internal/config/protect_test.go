@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProtection(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	c := &Config{Protect: []ProtectRule{
+		{Name: "legal", Tags: map[string]string{"hold": "true"}},
+		{ResourceType: "RDS", OlderThan: Duration(90 * 24 * time.Hour)},
+		{RecoveryPoints: []string{"rp-pinned"}},
+	}}
+	recent := now.Add(-time.Hour)
+	for _, tc := range []struct {
+		name string
+		p    ProtectedPoint
+		want string
+	}{
+		{"tagged", ProtectedPoint{ARN: "rp-1", ResourceType: "EFS", Created: recent, Tags: map[string]string{"hold": "true", "x": "y"}}, "legal"},
+		{"tag value differs", ProtectedPoint{ARN: "rp-1", ResourceType: "EFS", Created: recent, Tags: map[string]string{"hold": "false"}}, ""},
+		{"tags unknown", ProtectedPoint{ARN: "rp-1", ResourceType: "EFS", Created: recent}, ""},
+		{"old database", ProtectedPoint{ARN: "rp-2", ResourceType: "rds", Created: now.AddDate(0, 0, -91)}, "RDS, older than 2160h0m0s"},
+		{"recent database", ProtectedPoint{ARN: "rp-2", ResourceType: "RDS", Created: recent}, ""},
+		{"old file system", ProtectedPoint{ARN: "rp-3", ResourceType: "EFS", Created: now.AddDate(0, 0, -91)}, ""},
+		{"pinned", ProtectedPoint{ARN: "rp-pinned", ResourceType: "EFS", Created: recent}, "pinned"},
+	} {
+		rule := c.Protection(tc.p, now)
+		switch {
+		case tc.want == "" && rule != nil:
+			t.Errorf("%s: should not be protected, got %s", tc.name, rule)
+		case tc.want != "" && (rule == nil || !strings.Contains(rule.String(), tc.want)):
+			t.Errorf("%s: should be protected by %q, got %v", tc.name, tc.want, rule)
+		}
+	}
+	if (*Config)(nil).Protection(ProtectedPoint{ARN: "rp-pinned"}, now) != nil || (*Config)(nil).ProtectsByTag() {
+		t.Error("a nil config should protect nothing")
+	}
+	if !c.ProtectsByTag() {
+		t.Error("config has a tag rule")
+	}
+}
+
+func TestLoad_ProtectRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFile)
+	data := `{"protect": [{"name": "legal", "tags": {"hold": "true"}}, {"olderThan": "365d"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Protect) != 2 || time.Duration(c.Protect[1].OlderThan) != 365*24*time.Hour {
+		t.Errorf("unexpected rules %+v", c.Protect)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"protect": [{"name": "everything"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "no condition") {
+		t.Errorf("a rule without conditions should be rejected, got %v", err)
+	}
+}
+
+func TestDeletionGrace(t *testing.T) {
+	var unset *Config
+	if got := unset.DeletionGrace(); got != DefaultDeleteGrace {
+		t.Errorf("no config: grace %v, want %v", got, DefaultDeleteGrace)
+	}
+	if got := (&Config{}).DeletionGrace(); got != DefaultDeleteGrace {
+		t.Errorf("unset: grace %v, want %v", got, DefaultDeleteGrace)
+	}
+	c := &Config{DeleteGrace: Duration(5 * time.Minute)}
+	if got := c.DeletionGrace(); got != 5*time.Minute {
+		t.Errorf("grace %v, want 5m", got)
+	}
+}
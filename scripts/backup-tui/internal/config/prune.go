@@ -0,0 +1,51 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements the prune policy: how many of the on-demand backups
+// the tool created "backup-tui prune" keeps per resource, by recency and by
+// week and month, in the manner of restic's forget policies.
+package config
+
+import "fmt"
+
+// PrunePolicy keeps, for each resource, the newest KeepLast backups, the
+// newest backup of each of the KeepWeekly most recent ISO weeks with one,
+// and likewise of the KeepMonthly most recent months. A backup kept by any
+// rule is kept; zero disables a rule.
+type PrunePolicy struct {
+	KeepLast    int `json:"keepLast,omitempty"`
+	KeepWeekly  int `json:"keepWeekly,omitempty"`
+	KeepMonthly int `json:"keepMonthly,omitempty"`
+}
+
+// IsZero reports whether the policy keeps nothing, which would prune every
+// backup and is never applied.
+func (p PrunePolicy) IsZero() bool {
+	return p == PrunePolicy{}
+}
+
+// String describes the policy, e.g. "keep last 3, weekly 4, monthly 6".
+func (p PrunePolicy) String() string {
+	if p.IsZero() {
+		return "keep nothing"
+	}
+	s := "keep"
+	sep := " "
+	for _, r := range []struct {
+		name string
+		n    int
+	}{{"last", p.KeepLast}, {"weekly", p.KeepWeekly}, {"monthly", p.KeepMonthly}} {
+		if r.n > 0 {
+			s += fmt.Sprintf("%s%s %d", sep, r.name, r.n)
+			sep = ", "
+		}
+	}
+	return s
+}
+
+// Validate reports a negative count.
+func (p PrunePolicy) Validate() error {
+	if p.KeepLast < 0 || p.KeepWeekly < 0 || p.KeepMonthly < 0 {
+		return fmt.Errorf("prune policy counts must not be negative, got %+v", p)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad_PrunePolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFile)
+	if err := os.WriteFile(path, []byte(`{"prune": {"keepLast": 3, "keepMonthly": 6}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Prune == nil || *c.Prune != (PrunePolicy{KeepLast: 3, KeepMonthly: 6}) {
+		t.Fatalf("Prune = %+v", c.Prune)
+	}
+	if got := c.Prune.String(); got != "keep last 3, monthly 6" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"prune": {"keepWeekly": -1}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("a negative count should be rejected")
+	}
+}
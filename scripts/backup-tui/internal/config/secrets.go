@@ -0,0 +1,205 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements sensitive config values such as webhook URLs, role
+// external IDs, and tokens: the config file holds a reference to an OS
+// keyring entry instead of the value, and MigrateSecrets moves plaintext
+// values written in existing config files into the keyring.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the OS keyring service config secrets are stored under.
+const keyringService = "backup-tui"
+
+// Secret is a sensitive config value. In the config file it is either the
+// plaintext value, which MigrateSecrets moves to the keyring, or a keyring
+// reference: {"keyring": "name"}.
+type Secret struct {
+	Value   string // Plaintext value from the config file
+	Keyring string // Name of the OS keyring entry holding the value
+}
+
+// IsZero reports whether the secret is unset.
+func (s Secret) IsZero() bool {
+	return s.Value == "" && s.Keyring == ""
+}
+
+// Plaintext reports whether the value is written in the config file.
+func (s Secret) Plaintext() bool {
+	return s.Value != "" && s.Keyring == ""
+}
+
+// Reveal returns the secret's value, reading it from the keyring if the
+// config references it there.
+func (s Secret) Reveal() (string, error) {
+	if s.Keyring == "" {
+		return s.Value, nil
+	}
+	value, err := keyring.Get(keyringService, keyringUser(s.Keyring))
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %q from the OS keyring: %w", s.Keyring, err)
+	}
+	return value, nil
+}
+
+// UnmarshalJSON reads a plaintext value or a keyring reference.
+func (s *Secret) UnmarshalJSON(data []byte) error {
+	var ref struct {
+		Keyring string `json:"keyring"`
+	}
+	if err := json.Unmarshal(data, &ref); err == nil {
+		if ref.Keyring == "" {
+			return fmt.Errorf(`a keyring reference needs a name, e.g. {"keyring": "ops-webhook"}`)
+		}
+		*s = Secret{Keyring: ref.Keyring}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf(`expected a string or {"keyring": "name"}, got %s`, data)
+	}
+	*s = Secret{Value: value}
+	return nil
+}
+
+// MarshalJSON writes a keyring reference, or the plaintext value if the
+// secret is not in the keyring.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	if s.Keyring != "" {
+		return json.Marshal(map[string]string{"keyring": s.Keyring})
+	}
+	return json.Marshal(s.Value)
+}
+
+// SetSecret stores value in the OS keyring as name, for reference from the
+// config file as {"keyring": "name"}.
+func SetSecret(name, value string) error {
+	if name == "" {
+		return fmt.Errorf("a secret needs a name")
+	}
+	if err := keyring.Set(keyringService, keyringUser(name), value); err != nil {
+		return fmt.Errorf("failed to store secret %q in the OS keyring: %w", name, err)
+	}
+	return nil
+}
+
+// keyringUser is the keyring entry of the config secret name.
+func keyringUser(name string) string {
+	return "config/" + name
+}
+
+// PlaintextSecrets returns the names of the config's secrets that are
+// written in plaintext, e.g. "webhooks.0.url".
+func (c *Config) PlaintextSecrets() []string {
+	var names []string
+	walkSecrets(reflect.ValueOf(c), "", func(name string, s *Secret) {
+		if s.Plaintext() {
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+// MigrateSecrets moves the plaintext secrets of the config file at path to
+// the OS keyring, each under its name in the config, and rewrites the file
+// with references to them. It returns the names of the migrated secrets.
+// The file is only rewritten once every secret is in the keyring.
+func MigrateSecrets(path string) ([]string, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	names, err := migrateSecrets(c)
+	if err != nil || len(names) == 0 {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	return names, nil
+}
+
+// migrateSecrets stores the plaintext secrets in v in the keyring and
+// replaces them with references.
+func migrateSecrets(v any) ([]string, error) {
+	var names []string
+	var firstErr error
+	walkSecrets(reflect.ValueOf(v), "", func(name string, s *Secret) {
+		if firstErr != nil || !s.Plaintext() {
+			return
+		}
+		if err := SetSecret(name, s.Value); err != nil {
+			firstErr = err
+			return
+		}
+		*s = Secret{Keyring: name}
+		names = append(names, name)
+	})
+	return names, firstErr
+}
+
+// walkSecrets calls fn for every Secret reachable from v, named by its
+// path of JSON field names, map keys, and slice indexes.
+func walkSecrets(v reflect.Value, name string, fn func(string, *Secret)) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkSecrets(v.Elem(), name, fn)
+		}
+	case reflect.Struct:
+		if s, ok := v.Addr().Interface().(*Secret); ok {
+			fn(name, s)
+			return
+		}
+		for i := range v.NumField() {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			field, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if field == "-" {
+				continue
+			}
+			if field == "" {
+				field = f.Name
+			}
+			walkSecrets(v.Field(i), joinName(name, field), fn)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			walkSecrets(v.Index(i), joinName(name, fmt.Sprint(i)), fn)
+		}
+	case reflect.Map:
+		// Map values are not addressable: walk a copy and store it back
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			walkSecrets(elem, joinName(name, fmt.Sprint(key.Interface())), fn)
+			v.SetMapIndex(key, elem)
+		}
+	}
+}
+
+// joinName appends part to a dotted secret name.
+func joinName(name, part string) string {
+	if name == "" {
+		return part
+	}
+	return name + "." + part
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+// TestMain keeps secrets in memory rather than the OS keyring.
+func TestMain(m *testing.M) {
+	keyring.MockInit()
+	os.Exit(m.Run())
+}
+
+// webhookConfig has secrets in a slice, a map, and a nested struct, as
+// config sections holding webhook URLs and external IDs do.
+type webhookConfig struct {
+	Webhooks []struct {
+		URL  Secret `json:"url"`
+		Name string `json:"name"`
+	} `json:"webhooks"`
+	Accounts map[string]struct {
+		ExternalID Secret `json:"externalId"`
+	} `json:"accounts"`
+	Token  Secret `json:"token,omitempty"`
+	hidden Secret
+}
+
+func TestSecret_JSON(t *testing.T) {
+	var s Secret
+	if err := json.Unmarshal([]byte(`"https://hooks.example.org/x"`), &s); err != nil || !s.Plaintext() {
+		t.Errorf("a string should be a plaintext secret, got %+v, %v", s, err)
+	}
+	if err := json.Unmarshal([]byte(`{"keyring": "ops-webhook"}`), &s); err != nil || s.Keyring != "ops-webhook" || s.Plaintext() {
+		t.Errorf("a reference should name a keyring entry, got %+v, %v", s, err)
+	}
+	if data, _ := json.Marshal(s); string(data) != `{"keyring":"ops-webhook"}` {
+		t.Errorf("a keyring secret should be written as a reference, got %s", data)
+	}
+	for _, bad := range []string{`{}`, `42`, `{"keyring": ""}`} {
+		if err := json.Unmarshal([]byte(bad), &s); err == nil {
+			t.Errorf("%s should not be a secret", bad)
+		}
+	}
+}
+
+func TestSecret_Reveal(t *testing.T) {
+	if v, err := (Secret{Value: "plain"}).Reveal(); err != nil || v != "plain" {
+		t.Errorf("Reveal = %q, %v", v, err)
+	}
+	if err := SetSecret("ops-webhook", "https://hooks.example.org/x"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := (Secret{Keyring: "ops-webhook"}).Reveal(); err != nil || v != "https://hooks.example.org/x" {
+		t.Errorf("Reveal = %q, %v", v, err)
+	}
+	if _, err := (Secret{Keyring: "missing"}).Reveal(); err == nil {
+		t.Error("a reference to a missing keyring entry should fail")
+	}
+}
+
+func TestMigrateSecrets_Walk(t *testing.T) {
+	var c webhookConfig
+	data := `{
+		"webhooks": [{"url": "https://hooks.example.org/a", "name": "ops"}, {"url": {"keyring": "kept"}}],
+		"accounts": {"dr": {"externalId": "ext-123"}},
+		"token": "t0k3n"
+	}`
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		t.Fatal(err)
+	}
+	c.hidden = Secret{Value: "unexported fields are not config"}
+
+	names, err := migrateSecrets(&c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(names)
+	if want := []string{"accounts.dr.externalId", "token", "webhooks.0.url"}; !slices.Equal(names, want) {
+		t.Errorf("migrated %v, want %v", names, want)
+	}
+	if c.Webhooks[0].URL.Keyring != "webhooks.0.url" || c.Accounts["dr"].ExternalID.Keyring != "accounts.dr.externalId" {
+		t.Errorf("migrated secrets should become references, got %+v", c)
+	}
+	if v, _ := c.Accounts["dr"].ExternalID.Reveal(); v != "ext-123" {
+		t.Errorf("migrated external ID should be in the keyring, got %q", v)
+	}
+
+	out, _ := json.Marshal(c)
+	for _, leaked := range []string{"hooks.example.org", "ext-123", "t0k3n"} {
+		if strings.Contains(string(out), leaked) {
+			t.Errorf("migrated config should not hold %q: %s", leaked, out)
+		}
+	}
+}
+
+func TestMigrateSecrets_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFile)
+	data := `{"targets": {"RDS": {"rpo": "26h"}}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	names, err := MigrateSecrets(path)
+	if err != nil || len(names) != 0 {
+		t.Errorf("a config without secrets has nothing to migrate, got %v, %v", names, err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != data {
+		t.Errorf("a config without plaintext secrets should not be rewritten, got %s", got)
+	}
+}
+
+func TestMigrateSecrets_KeyringUnavailable(t *testing.T) {
+	keyring.MockInitWithError(errors.New("no secret service"))
+	t.Cleanup(keyring.MockInit)
+
+	c := webhookConfig{Token: Secret{Value: "t0k3n"}}
+	if _, err := migrateSecrets(&c); err == nil {
+		t.Error("migration should fail without a keyring")
+	}
+	if !c.Token.Plaintext() {
+		t.Error("a secret that could not be stored should be left as it was")
+	}
+}
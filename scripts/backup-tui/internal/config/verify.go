@@ -0,0 +1,113 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements the verification checks run against a test restore:
+// which OpenEMR tables to count rows of, what the counts must reach, and
+// how much a restored file system may fall short of its backup.
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultVerifyTables are the OpenEMR tables counted when the config names
+// none: patients, encounters, forms, users, and documents.
+var DefaultVerifyTables = []TableCheck{
+	{Name: "patient_data"},
+	{Name: "form_encounter"},
+	{Name: "forms"},
+	{Name: "users", MinRows: 1},
+	{Name: "documents"},
+}
+
+// DefaultVerifyDatabase is the schema the tables are counted in when the
+// config names none.
+const DefaultVerifyDatabase = "openemr"
+
+// DefaultEFSMinSizePercent is how much of its backup's size a restored
+// file system must hold when the config does not say.
+const DefaultEFSMinSizePercent = 90
+
+// Verification is the checks run against a test restore.
+type Verification struct {
+	// DatabaseSecret is the ARN of the Secrets Manager secret holding the
+	// database credentials the RDS Data API signs in with. Without it, row
+	// counts are skipped.
+	DatabaseSecret string `json:"databaseSecret,omitempty"`
+	// Database is the schema the tables are in (DefaultVerifyDatabase if
+	// empty).
+	Database string `json:"database,omitempty"`
+	// Tables are the tables whose rows are counted (DefaultVerifyTables if
+	// empty).
+	Tables []TableCheck `json:"tables,omitempty"`
+	// MaxShrinkPercent is how far a count may fall below the one of the
+	// resource's last verified backup, in percent. Zero allows no shrinking.
+	MaxShrinkPercent float64 `json:"maxShrinkPercent,omitempty"`
+	// EFSMinSizePercent is how much of its backup's size a restored file
+	// system must hold, in percent (DefaultEFSMinSizePercent if zero).
+	EFSMinSizePercent float64 `json:"efsMinSizePercent,omitempty"`
+}
+
+// TableCheck is a table whose rows are counted.
+type TableCheck struct {
+	Name    string `json:"name"`
+	MinRows int64  `json:"minRows,omitempty"` // Fewer rows fail the check
+}
+
+// VerifyChecks returns the config's verification checks with the defaults
+// filled in. A config without a "verify" section gets the defaults.
+func (c *Config) VerifyChecks() Verification {
+	var v Verification
+	if c != nil && c.Verify != nil {
+		v = *c.Verify
+	}
+	if v.Database == "" {
+		v.Database = DefaultVerifyDatabase
+	}
+	if len(v.Tables) == 0 {
+		v.Tables = DefaultVerifyTables
+	}
+	if v.EFSMinSizePercent == 0 {
+		v.EFSMinSizePercent = DefaultEFSMinSizePercent
+	}
+	return v
+}
+
+// TableNames returns the names of the tables counted.
+func (v Verification) TableNames() []string {
+	names := make([]string, len(v.Tables))
+	for i, t := range v.Tables {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// validate reports table names that are not plain identifiers, which the
+// row counts could not quote safely, and percentages out of range.
+func (v *Verification) validate() error {
+	if v.DatabaseSecret != "" && !strings.HasPrefix(v.DatabaseSecret, "arn:") {
+		return fmt.Errorf("verify.databaseSecret must be a Secrets Manager secret ARN, got %q", v.DatabaseSecret)
+	}
+	for _, name := range append([]string{v.Database}, v.TableNames()...) {
+		for _, r := range name {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '$') {
+				return fmt.Errorf("verify: %q is not a table or database name of letters, digits, \"_\", and \"$\"", name)
+			}
+		}
+	}
+	for _, t := range v.Tables {
+		if t.Name == "" {
+			return fmt.Errorf("verify.tables: every table needs a name")
+		}
+		if t.MinRows < 0 {
+			return fmt.Errorf("verify.tables: minRows of %s must not be negative", t.Name)
+		}
+	}
+	if v.MaxShrinkPercent < 0 || v.MaxShrinkPercent > 100 {
+		return fmt.Errorf("verify.maxShrinkPercent must be between 0 and 100, got %g", v.MaxShrinkPercent)
+	}
+	if v.EFSMinSizePercent < 0 || v.EFSMinSizePercent > 100 {
+		return fmt.Errorf("verify.efsMinSizePercent must be between 0 and 100, got %g", v.EFSMinSizePercent)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoad_Verify(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		json string
+		ok   bool
+	}{
+		{`{"verify": {"databaseSecret": "arn:aws:secretsmanager:us-west-2:123456789012:secret:openemr-db", "tables": [{"name": "patient_data", "minRows": 100}], "maxShrinkPercent": 5}}`, true},
+		{`{"verify": {"databaseSecret": "openemr-db"}}`, false},
+		{`{"verify": {"tables": [{"name": "patient_data; DROP TABLE users"}]}}`, false},
+		{`{"verify": {"tables": [{"name": ""}]}}`, false},
+		{`{"verify": {"tables": [{"name": "users", "minRows": -1}]}}`, false},
+		{`{"verify": {"maxShrinkPercent": 120}}`, false},
+	} {
+		path := filepath.Join(dir, "config.json")
+		if err := os.WriteFile(path, []byte(tc.json), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); (err == nil) != tc.ok {
+			t.Errorf("Load(%s) error = %v, want ok %v", tc.json, err, tc.ok)
+		}
+	}
+}
+
+func TestConfig_VerifyChecks(t *testing.T) {
+	var none *Config
+	v := none.VerifyChecks()
+	if v.Database != "openemr" || v.EFSMinSizePercent != DefaultEFSMinSizePercent || !slices.Equal(v.TableNames(), []string{"patient_data", "form_encounter", "forms", "users", "documents"}) {
+		t.Errorf("without a config the defaults should apply, got %+v", v)
+	}
+
+	c := &Config{Verify: &Verification{Database: "emr", Tables: []TableCheck{{Name: "patient_data", MinRows: 10}}, EFSMinSizePercent: 50}}
+	v = c.VerifyChecks()
+	if v.Database != "emr" || v.EFSMinSizePercent != 50 || len(v.Tables) != 1 || v.Tables[0].MinRows != 10 {
+		t.Errorf("the config's checks should be kept, got %+v", v)
+	}
+}
@@ -0,0 +1,71 @@
+// Package config reads the backup TUI's configuration file, in which an
+// organization records the policies the tool checks the stack against.
+// This file implements webhooks: URLs that receive a JSON document each time
+// a workflow step (a restore, export, clone, or "dr copy" copy or restore)
+// starts, completes, or fails, e.g. an incident tool's events endpoint.
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+// Webhook events.
+const (
+	EventStarted   = "started"
+	EventCompleted = "completed"
+	EventFailed    = "failed"
+)
+
+// WebhookEvents are the step transitions a webhook can receive.
+var WebhookEvents = []string{EventStarted, EventCompleted, EventFailed}
+
+// Webhook is an endpoint notified of workflow step transitions.
+type Webhook struct {
+	// Name identifies the webhook in error messages; its index if empty.
+	Name string `json:"name,omitempty"`
+	// URL receives a POST per event. It usually embeds a routing key or
+	// token, so it is a Secret.
+	URL Secret `json:"url"`
+	// Headers are sent with each POST, e.g. "Authorization".
+	Headers map[string]Secret `json:"headers,omitempty"`
+	// Events the webhook receives; all of WebhookEvents if empty.
+	Events []string `json:"events,omitempty"`
+}
+
+// Wants reports whether the webhook receives event.
+func (w Webhook) Wants(event string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+// validate reports a missing URL, a plaintext URL that is not HTTP(S), or
+// an unknown event.
+func (w Webhook) validate() error {
+	if w.URL.IsZero() {
+		return fmt.Errorf("url is required")
+	}
+	if w.URL.Plaintext() {
+		if u, err := url.Parse(w.URL.Value); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("url must be an http or https URL")
+		}
+	}
+	for _, e := range w.Events {
+		if !slices.Contains(WebhookEvents, e) {
+			return fmt.Errorf("unknown event %q: use %v", e, WebhookEvents)
+		}
+	}
+	return nil
+}
+
+// validateWebhooks reports the first invalid webhook.
+func (c *Config) validateWebhooks() error {
+	for i, w := range c.Webhooks {
+		if err := w.validate(); err != nil {
+			return fmt.Errorf("webhook %s: %w", cmp.Or(w.Name, strconv.Itoa(i)), err)
+		}
+	}
+	return nil
+}
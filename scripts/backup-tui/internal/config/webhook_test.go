@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad_Webhooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFile)
+	data := `{"webhooks": [
+		{"name": "pager", "url": {"keyring": "pager-url"}, "events": ["failed"]},
+		{"url": "https://hooks.example.org/dr", "headers": {"Authorization": {"keyring": "dr-token"}}}
+	]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Webhooks) != 2 || c.Webhooks[0].URL.Keyring != "pager-url" || c.Webhooks[1].Headers["Authorization"].Keyring != "dr-token" {
+		t.Fatalf("Webhooks = %+v", c.Webhooks)
+	}
+	if c.Webhooks[0].Wants(EventStarted) || !c.Webhooks[0].Wants(EventFailed) || !c.Webhooks[1].Wants(EventStarted) {
+		t.Error("a webhook should receive its events, or all of them when none are listed")
+	}
+
+	for name, data := range map[string]string{
+		"no url":  `{"webhooks": [{"name": "x"}]}`,
+		"scheme":  `{"webhooks": [{"url": "ftp://hooks.example.org"}]}`,
+		"event":   `{"webhooks": [{"url": "https://hooks.example.org", "events": ["skipped"]}]}`,
+		"no host": `{"webhooks": [{"url": "https://"}]}`,
+	} {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s: expected an error loading %s", name, data)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Package deeplink implements backup-tui:// links: a region, stack, vault,
+// and view or backup encoded as one string, which reports and the TUI emit
+// and "backup-tui <link>" (or -link) opens, so a runbook or report can
+// reconstruct the exact context on another operator's machine, e.g.
+//
+//	backup-tui://open?region=us-west-2&stack=OpenemrEcsStack&view=jobs
+package deeplink
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Scheme is the URI scheme of deep links.
+const Scheme = "backup-tui"
+
+// action is the only host deep links have: opening the TUI.
+const action = "open"
+
+// params are the query parameters a link may carry, in the order they are
+// documented.
+var params = []string{"region", "stack", "vault", "view", "resource", "arn"}
+
+// Link is the context a deep link opens. Empty fields are left to
+// auto-discovery and the remembered view.
+type Link struct {
+	Region   string
+	Stack    string
+	Vault    string
+	View     string // View to launch into, e.g. "jobs"
+	Resource string // Resource ID or ARN whose latest restorable backup is opened
+	ARN      string // Recovery point ARN whose details are opened
+}
+
+// IsLink reports whether s is a deep link rather than a subcommand or file.
+func IsLink(s string) bool {
+	return strings.HasPrefix(s, Scheme+"://")
+}
+
+// String returns l as a deep link. Parameters are in alphabetical order,
+// so the same context is always the same link.
+func (l Link) String() string {
+	q := url.Values{}
+	for name, value := range l.values() {
+		if value != "" {
+			q.Set(name, value)
+		}
+	}
+	u := url.URL{Scheme: Scheme, Host: action, RawQuery: q.Encode()}
+	return u.String()
+}
+
+// values returns the fields of l by parameter name.
+func (l Link) values() map[string]string {
+	return map[string]string{
+		"region":   l.Region,
+		"stack":    l.Stack,
+		"vault":    l.Vault,
+		"view":     l.View,
+		"resource": l.Resource,
+		"arn":      l.ARN,
+	}
+}
+
+// Parse parses a deep link. A link with an unknown action or parameter,
+// e.g. from a newer version, is refused rather than opened partially.
+func Parse(s string) (Link, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return Link{}, fmt.Errorf("invalid link: %w", err)
+	}
+	if u.Scheme != Scheme {
+		return Link{}, fmt.Errorf("invalid link: scheme %q is not %s://", u.Scheme, Scheme)
+	}
+	if u.Host != action || strings.Trim(u.Path, "/") != "" {
+		return Link{}, fmt.Errorf("invalid link: unknown action %q (want %s://%s?...)", strings.Trim(u.Host+u.Path, "/"), Scheme, action)
+	}
+	q, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return Link{}, fmt.Errorf("invalid link: %w", err)
+	}
+	for name, values := range q {
+		if !slices.Contains(params, name) {
+			return Link{}, fmt.Errorf("invalid link: unknown parameter %q (want %s)", name, strings.Join(params, ", "))
+		}
+		if len(values) > 1 {
+			return Link{}, fmt.Errorf("invalid link: %s given %d times", name, len(values))
+		}
+	}
+	l := Link{
+		Region:   q.Get("region"),
+		Stack:    q.Get("stack"),
+		Vault:    q.Get("vault"),
+		View:     q.Get("view"),
+		Resource: q.Get("resource"),
+		ARN:      q.Get("arn"),
+	}
+	if l == (Link{}) {
+		return Link{}, errors.New("invalid link: it names no region, stack, vault, view, or backup")
+	}
+	return l, nil
+}
@@ -0,0 +1,50 @@
+package deeplink
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLink_RoundTrip(t *testing.T) {
+	l := Link{
+		Region: "us-west-2",
+		Stack:  "OpenemrEcsStack",
+		Vault:  "openemr-vault",
+		ARN:    "arn:aws:rds:us-west-2:123456789012:cluster-snapshot:awsbackup:job-1a2b",
+	}
+	s := l.String()
+	if !IsLink(s) || !strings.HasPrefix(s, "backup-tui://open?arn=") {
+		t.Fatalf("unexpected link %q", s)
+	}
+	got, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", s, err)
+	}
+	if got != l {
+		t.Errorf("round trip changed the link: got %+v, want %+v", got, l)
+	}
+	if s != (Link{ARN: l.ARN, Vault: l.Vault, Stack: l.Stack, Region: l.Region}).String() {
+		t.Error("the same context should always give the same link")
+	}
+}
+
+func TestParse_Refused(t *testing.T) {
+	for _, s := range []string{
+		"https://open?region=us-west-2",
+		"backup-tui://restore?arn=x",
+		"backup-tui://open/extra?region=us-west-2",
+		"backup-tui://open?region=us-west-2&color=red",
+		"backup-tui://open?view=jobs&view=timeline",
+		"backup-tui://open",
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) should fail", s)
+		}
+	}
+}
+
+func TestIsLink(t *testing.T) {
+	if IsLink("list") || IsLink("-region") || !IsLink("backup-tui://open?view=jobs") {
+		t.Error("IsLink should only match backup-tui:// links")
+	}
+}
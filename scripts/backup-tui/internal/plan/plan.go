@@ -0,0 +1,292 @@
+// Package plan implements restore plans: "backup-tui plan" records exactly
+// what a restore of a recovery point will create or modify in a signed plan
+// file, which is reviewed and approved like an infrastructure change before
+// "backup-tui apply" executes it. The signature is an HMAC of the plan under
+// the config's planSigningKey, so a plan edited after review is refused.
+package plan
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// Version is the plan file format version.
+const Version = 1
+
+// Plan is a restore plan file.
+type Plan struct {
+	Version   int       `json:"version"`
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedBy string    `json:"createdBy,omitempty"` // ARN of the IAM identity that planned the restore
+
+	Stack  string `json:"stack"`
+	Vault  string `json:"vault"`
+	Region string `json:"region"`
+
+	RecoveryPoint RecoveryPoint `json:"recoveryPoint"`
+	Options       Options       `json:"options"` // Operator overrides the parameters were derived with
+	Restore       Restore       `json:"restore"` // Parameters the restore job is started with
+	Changes       []Change      `json:"changes"` // What the restore creates or modifies
+
+	Signature string `json:"signature,omitempty"` // Hex HMAC-SHA256 of the plan without the signature
+}
+
+// RecoveryPoint is the recovery point a plan restores.
+type RecoveryPoint struct {
+	ARN          string    `json:"arn"`
+	ResourceType string    `json:"resourceType"`
+	ResourceID   string    `json:"resourceId"`
+	ResourceARN  string    `json:"resourceArn"`
+	CreatedAt    time.Time `json:"createdAt"`
+	SizeBytes    int64     `json:"sizeBytes,omitempty"`
+}
+
+// Options are the operator overrides of a planned restore, as
+// aws.RestoreOptions.
+type Options struct {
+	KMSKeyID         string   `json:"kmsKeyId,omitempty"`
+	SubnetGroup      string   `json:"subnetGroup,omitempty"`
+	SecurityGroupIDs []string `json:"securityGroupIds,omitempty"`
+	ClusterSuffix    string   `json:"clusterSuffix,omitempty"`
+}
+
+// Restore is the parameters a restore job is started with.
+type Restore struct {
+	ClusterID      string `json:"clusterId,omitempty"`
+	SubnetGroup    string `json:"subnetGroup,omitempty"`
+	SecurityGroups string `json:"securityGroups,omitempty"`
+	FileSystemID   string `json:"fileSystemId,omitempty"`
+	NewFileSystem  bool   `json:"newFileSystem,omitempty"`
+	Encrypted      bool   `json:"encrypted,omitempty"`
+	KMSKeyID       string `json:"kmsKeyId,omitempty"` // Empty when the restore keeps the backup's key
+	RoleARN        string `json:"roleArn"`
+}
+
+// Change is a resource a restore creates ("create") or modifies ("modify").
+type Change struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// New returns an unsigned plan to restore rp with the parameters meta, with
+// opts applied, and the IAM role roleARN. The caller sets the environment,
+// times, and author.
+func New(rp aws.RecoveryPoint, opts aws.RestoreOptions, meta *aws.RestoreMetadata, roleARN string) *Plan {
+	restore := RestoreFor(meta, roleARN)
+	return &Plan{
+		Version: Version,
+		ID:      newID(),
+		RecoveryPoint: RecoveryPoint{
+			ARN:          rp.RecoveryPointARN,
+			ResourceType: rp.ResourceType,
+			ResourceID:   rp.ResourceID,
+			ResourceARN:  rp.ResourceARN,
+			CreatedAt:    rp.CreationDate.UTC(),
+			SizeBytes:    rp.BackupSizeInBytes,
+		},
+		Options: Options{KMSKeyID: opts.KMSKeyID, SubnetGroup: opts.SubnetGroup, SecurityGroupIDs: opts.SecurityGroupIDs, ClusterSuffix: opts.ClusterSuffix},
+		Restore: restore,
+		Changes: changes(rp.ResourceType, restore),
+	}
+}
+
+// RestoreFor returns the restore parameters of meta and roleARN, as
+// StartRestoreJob sends them.
+func RestoreFor(meta *aws.RestoreMetadata, roleARN string) Restore {
+	r := Restore{KMSKeyID: meta.KMSKeyID, RoleARN: roleARN}
+	switch meta.ResourceType {
+	case "RDS":
+		r.ClusterID, r.SubnetGroup, r.SecurityGroups = meta.ClusterID, meta.SubnetGroup, meta.SecurityGroups
+	case "EFS":
+		r.FileSystemID, r.NewFileSystem, r.Encrypted = meta.ResourceID, meta.NewFileSystem, meta.Encrypted
+	}
+	return r
+}
+
+// changes describes what a restore with r creates or modifies.
+func changes(resourceType string, r Restore) []Change {
+	key := r.KMSKeyID
+	if key == "" {
+		key = "the backup's key"
+	}
+	switch {
+	case resourceType == "RDS":
+		return []Change{{Action: "create", Resource: "Aurora cluster " + r.ClusterID,
+			Detail: fmt.Sprintf("in subnet group %s with security groups %s, encrypted with %s", r.SubnetGroup, r.SecurityGroups, key)}}
+	case r.NewFileSystem:
+		return []Change{{Action: "create", Resource: "EFS file system",
+			Detail: fmt.Sprintf("restored from the backup of %s, encrypted with %s", r.FileSystemID, key)}}
+	default:
+		return []Change{{Action: "modify", Resource: "EFS file system " + r.FileSystemID,
+			Detail: "the backup is restored in place into a new aws-backup-restore_ directory at its root"}}
+	}
+}
+
+// RestoreOptions returns the plan's overrides for StartRestoreJob.
+func (p *Plan) RestoreOptions() aws.RestoreOptions {
+	return aws.RestoreOptions{KMSKeyID: p.Options.KMSKeyID, SubnetGroup: p.Options.SubnetGroup, SecurityGroupIDs: p.Options.SecurityGroupIDs, ClusterSuffix: p.Options.ClusterSuffix}
+}
+
+// Drift compares the planned restore parameters with current, the ones a
+// restore would be started with now, and describes each difference.
+func (p *Plan) Drift(current Restore) []string {
+	var drift []string
+	diff := func(name, planned, now string) {
+		if planned != now {
+			drift = append(drift, fmt.Sprintf("%s: planned %s, now %s", name, orNone(planned), orNone(now)))
+		}
+	}
+	diff("cluster", p.Restore.ClusterID, current.ClusterID)
+	diff("subnet group", p.Restore.SubnetGroup, current.SubnetGroup)
+	diff("security groups", p.Restore.SecurityGroups, current.SecurityGroups)
+	diff("file system", p.Restore.FileSystemID, current.FileSystemID)
+	diff("new file system", fmt.Sprint(p.Restore.NewFileSystem), fmt.Sprint(current.NewFileSystem))
+	diff("encrypted", fmt.Sprint(p.Restore.Encrypted), fmt.Sprint(current.Encrypted))
+	diff("KMS key", p.Restore.KMSKeyID, current.KMSKeyID)
+	diff("IAM role", p.Restore.RoleARN, current.RoleARN)
+	return drift
+}
+
+// orNone returns s, or "(none)" when it is empty.
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// Expired reports whether the plan may no longer be applied at now.
+func (p *Plan) Expired(now time.Time) bool {
+	return !now.Before(p.ExpiresAt)
+}
+
+// Sign sets the plan's signature under key.
+func (p *Plan) Sign(key []byte) error {
+	mac, err := p.mac(key)
+	if err != nil {
+		return err
+	}
+	p.Signature = hex.EncodeToString(mac)
+	return nil
+}
+
+// Verify reports whether the plan is signed under key and unchanged since.
+func (p *Plan) Verify(key []byte) error {
+	if p.Signature == "" {
+		return errors.New("the plan is not signed")
+	}
+	signature, err := hex.DecodeString(p.Signature)
+	if err != nil {
+		return errors.New("the plan's signature is malformed")
+	}
+	mac, err := p.mac(key)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(signature, mac) {
+		return errors.New("the plan's signature does not match: it was changed after it was planned, or signed with a different planSigningKey")
+	}
+	return nil
+}
+
+// mac returns the HMAC of the plan's JSON without the signature.
+func (p *Plan) mac(key []byte) ([]byte, error) {
+	unsigned := *p
+	unsigned.Signature = ""
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plan: %w", err)
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// Write saves the plan to path as indented JSON for review.
+func (p *Plan) Write(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return nil
+}
+
+// Load reads a plan file. It does not verify the signature.
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+	var p Plan
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	if p.Version != Version {
+		return nil, fmt.Errorf("plan %s has version %d; this backup-tui reads version %d", path, p.Version, Version)
+	}
+	return &p, nil
+}
+
+// Text describes the plan for review.
+func (p *Plan) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Restore plan %s\n", p.ID)
+	fmt.Fprintf(&b, "  Stack %s, vault %s (%s)\n", p.Stack, p.Vault, p.Region)
+	fmt.Fprintf(&b, "  Planned %s", p.CreatedAt.UTC().Format("2006-01-02 15:04 MST"))
+	if p.CreatedBy != "" {
+		fmt.Fprintf(&b, " by %s", p.CreatedBy)
+	}
+	fmt.Fprintf(&b, ", expires %s\n\n", p.ExpiresAt.UTC().Format("2006-01-02 15:04 MST"))
+
+	rp := p.RecoveryPoint
+	fmt.Fprintf(&b, "Recovery point %s\n", rp.ARN)
+	fmt.Fprintf(&b, "  %s %s, backed up %s\n\n", rp.ResourceType, resourceName(rp), rp.CreatedAt.UTC().Format("2006-01-02 15:04 MST"))
+
+	b.WriteString("Applying this plan will:\n")
+	for _, c := range p.Changes {
+		symbol := "~"
+		if c.Action == "create" {
+			symbol = "+"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", symbol, c.Action, c.Resource)
+		if c.Detail != "" {
+			fmt.Fprintf(&b, "      %s\n", c.Detail)
+		}
+	}
+	fmt.Fprintf(&b, "  The restore job runs as %s\n", p.Restore.RoleARN)
+	return b.String()
+}
+
+// resourceName returns the name of rp's resource, the last part of its ARN.
+func resourceName(rp RecoveryPoint) string {
+	if i := strings.LastIndexAny(rp.ResourceARN, ":/"); i >= 0 {
+		return rp.ResourceARN[i+1:]
+	}
+	return rp.ResourceID
+}
+
+// newID returns a random plan ID.
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,122 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+var key = []byte(strings.Repeat("k", 32))
+
+// samplePlan returns a plan to restore an RDS recovery point.
+func samplePlan() *Plan {
+	rp := aws.RecoveryPoint{
+		RecoveryPointARN: "arn:aws:backup:us-west-2:123456789012:recovery-point:rp-1",
+		ResourceType:     "RDS",
+		ResourceID:       "openemr-cluster",
+		CreationDate:     time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC),
+	}
+	meta := &aws.RestoreMetadata{ResourceType: "RDS", ResourceID: "openemr-cluster", ClusterID: "openemr-cluster", SubnetGroup: "db-subnets", SecurityGroups: "sg-1"}
+	p := New(rp, aws.RestoreOptions{}, meta, "arn:aws:iam::123456789012:role/backup")
+	p.Stack, p.Vault, p.Region = "OpenemrEcsStack", "openemr-vault", "us-west-2"
+	p.CreatedAt = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	p.ExpiresAt = p.CreatedAt.Add(24 * time.Hour)
+	return p
+}
+
+func TestPlan_SignWriteLoadVerify(t *testing.T) {
+	p := samplePlan()
+	if err := p.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "restore.plan.json")
+	if err := p.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Verify(key); err != nil {
+		t.Errorf("an unchanged plan should verify, got %v", err)
+	}
+	if err := loaded.Verify([]byte(strings.Repeat("x", 32))); err == nil {
+		t.Error("a plan should not verify under another key")
+	}
+
+	// A reviewer's plan cannot be edited to restore somewhere else
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), "db-subnets", "public-subnets", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	edited, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := edited.Verify(key); err == nil || !strings.Contains(err.Error(), "changed after it was planned") {
+		t.Errorf("an edited plan should not verify, got %v", err)
+	}
+
+	edited.Signature = ""
+	if err := edited.Verify(key); err == nil {
+		t.Error("an unsigned plan should not verify")
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"version": `{"version": 2}`,
+		"unknown": `{"version": 1, "approvedBy": "me"}`,
+		"corrupt": `{`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s: Load should fail", name)
+		}
+	}
+}
+
+func TestPlan_Drift(t *testing.T) {
+	p := samplePlan()
+	if drift := p.Drift(p.Restore); len(drift) != 0 {
+		t.Errorf("unchanged parameters should not drift, got %v", drift)
+	}
+	current := p.Restore
+	current.SecurityGroups = "sg-1,sg-2"
+	drift := p.Drift(current)
+	if len(drift) != 1 || drift[0] != "security groups: planned sg-1, now sg-1,sg-2" {
+		t.Errorf("Drift() = %v", drift)
+	}
+}
+
+func TestPlan_Changes(t *testing.T) {
+	p := samplePlan()
+	if len(p.Changes) != 1 || p.Changes[0].Action != "create" || !strings.Contains(p.Changes[0].Resource, "openemr-cluster") {
+		t.Errorf("an RDS restore should create a cluster, got %+v", p.Changes)
+	}
+
+	rp := aws.RecoveryPoint{ResourceType: "EFS", ResourceID: "fs-1"}
+	inPlace := New(rp, aws.RestoreOptions{}, &aws.RestoreMetadata{ResourceType: "EFS", ResourceID: "fs-1", Encrypted: true}, "role")
+	if inPlace.Changes[0].Action != "modify" || !strings.Contains(inPlace.Text(), "~ modify EFS file system fs-1") {
+		t.Errorf("an in-place EFS restore should modify the file system, got %+v", inPlace.Changes)
+	}
+	newFS := New(rp, aws.RestoreOptions{KMSKeyID: "alias/k"}, &aws.RestoreMetadata{ResourceType: "EFS", ResourceID: "fs-1", NewFileSystem: true, KMSKeyID: "alias/k"}, "role")
+	if newFS.Changes[0].Action != "create" || newFS.RestoreOptions().KMSKeyID != "alias/k" {
+		t.Errorf("an EFS restore with another key should create a file system, got %+v", newFS.Changes)
+	}
+}
+
+func TestPlan_Expired(t *testing.T) {
+	p := samplePlan()
+	if p.Expired(p.ExpiresAt.Add(-time.Minute)) || !p.Expired(p.ExpiresAt) {
+		t.Error("a plan should expire at ExpiresAt")
+	}
+}
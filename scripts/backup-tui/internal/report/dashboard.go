@@ -0,0 +1,201 @@
+// Package report builds operational reports from AWS Backup job history.
+// This file implements the read-only web dashboard served by "backup-tui
+// serve" for NOC wall displays: the vault's inventory per resource, the
+// scheduled summary's RPO/RTO checks, and the most recent jobs, rendered as
+// an HTML page that reloads itself, or as JSON.
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// dashboardJobs is how many of the most recent jobs the dashboard lists.
+const dashboardJobs = 25
+
+// InventoryItem is a backed-up resource and its recovery points in the vault.
+type InventoryItem struct {
+	ResourceType   string    `json:"resourceType"`
+	ResourceID     string    `json:"resourceId"`
+	ResourceARN    string    `json:"resourceArn"`
+	RecoveryPoints int       `json:"recoveryPoints"`
+	Newest         time.Time `json:"newest"`
+	Oldest         time.Time `json:"oldest"`
+	TotalBytes     int64     `json:"totalBytes"`
+}
+
+// Dashboard is the web dashboard's content.
+type Dashboard struct {
+	Stack       string          `json:"stack"`
+	Vault       string          `json:"vault"`
+	Region      string          `json:"region"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Inventory   []InventoryItem `json:"inventory"`
+	Summary     *Summary        `json:"summary"`    // Coverage, RPO/RTO checks, and job success rates
+	RecentJobs  []aws.JobRecord `json:"recentJobs"` // Newest first
+
+	// Refresh is how often the HTML page reloads itself (zero for never).
+	Refresh time.Duration `json:"-"`
+}
+
+// BuildDashboard builds the dashboard of summary's vault from its recovery
+// points and job records.
+func BuildDashboard(summary *Summary, points []aws.RecoveryPoint, records []aws.JobRecord) *Dashboard {
+	d := &Dashboard{
+		Stack:       summary.Stack,
+		Vault:       summary.Vault,
+		Region:      summary.Region,
+		GeneratedAt: summary.GeneratedAt,
+		Inventory:   []InventoryItem{},
+		Summary:     summary,
+	}
+
+	byResource := make(map[string]*InventoryItem)
+	for _, p := range points {
+		item, ok := byResource[p.ResourceARN]
+		if !ok {
+			item = &InventoryItem{ResourceType: p.ResourceType, ResourceID: p.ResourceID, ResourceARN: p.ResourceARN, Newest: p.CreationDate, Oldest: p.CreationDate}
+			byResource[p.ResourceARN] = item
+		}
+		item.RecoveryPoints++
+		item.TotalBytes += p.BackupSizeInBytes
+		if p.CreationDate.After(item.Newest) {
+			item.Newest = p.CreationDate
+		}
+		if p.CreationDate.Before(item.Oldest) {
+			item.Oldest = p.CreationDate
+		}
+	}
+	for _, item := range byResource {
+		d.Inventory = append(d.Inventory, *item)
+	}
+	sort.Slice(d.Inventory, func(a, b int) bool {
+		if d.Inventory[a].ResourceType != d.Inventory[b].ResourceType {
+			return d.Inventory[a].ResourceType < d.Inventory[b].ResourceType
+		}
+		return d.Inventory[a].ResourceID < d.Inventory[b].ResourceID
+	})
+
+	d.RecentJobs = append([]aws.JobRecord{}, records...)
+	sort.SliceStable(d.RecentJobs, func(a, b int) bool {
+		return d.RecentJobs[a].CreatedAt.After(d.RecentJobs[b].CreatedAt)
+	})
+	if len(d.RecentJobs) > dashboardJobs {
+		d.RecentJobs = d.RecentJobs[:dashboardJobs]
+	}
+	return d
+}
+
+// JSON renders the dashboard as indented JSON.
+func (d *Dashboard) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dashboard: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// HTML renders the dashboard as a standalone HTML page.
+func (d *Dashboard) HTML() ([]byte, error) {
+	var b bytes.Buffer
+	if err := dashboardPage.Execute(&b, d); err != nil {
+		return nil, fmt.Errorf("failed to render dashboard: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// dashboardPage is the dashboard's HTML page. It needs no scripts or
+// external assets, so it works on locked-down wall display browsers.
+var dashboardPage = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "none"
+		}
+		return t.UTC().Format("2006-01-02 15:04")
+	},
+	"duration": func(s Seconds) string { return formatDuration(time.Duration(s)) },
+	"bytes":    formatBytes,
+	"name":     resourceName,
+	"seconds":  func(d time.Duration) int { return int(d.Seconds()) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+{{- if .Refresh}}
+<meta http-equiv="refresh" content="{{seconds .Refresh}}">
+{{- end}}
+<title>Backups: {{.Stack}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; background: #111; color: #eee; }
+h1 { margin-bottom: 0.2em; }
+h2 { margin-top: 1.5em; }
+.meta { color: #aaa; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 0.3em 0.8em; border-bottom: 1px solid #333; }
+td.num { text-align: right; }
+.ok { color: #6c6; }
+.bad { color: #f55; font-weight: bold; }
+.banner { font-size: 1.4em; padding: 0.5em 0.8em; margin-top: 1em; }
+.banner.ok { background: #143; }
+.banner.bad { background: #411; }
+</style>
+</head>
+<body>
+<h1>Backups: {{.Stack}}</h1>
+<div class="meta">Vault {{.Vault}} ({{.Region}}), updated {{date .GeneratedAt}} UTC</div>
+{{with .Summary}}
+{{- if .Violations}}
+<div class="banner bad">✗ {{.Violations}} violation(s)</div>
+{{- else}}
+<div class="banner ok">✓ All resources within their recovery objectives</div>
+{{- end}}
+{{- if .Uncovered}}
+<h2>Not in any backup selection</h2>
+<ul>
+{{- range .Uncovered}}
+<li class="bad">{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+<h2>Recovery Point Objective</h2>
+<table>
+<tr><th></th><th>Resource</th><th>Latest backup (UTC)</th><th>Age</th><th>RPO</th></tr>
+{{- range .Freshness}}
+<tr>
+{{- if .Violation}}<td class="bad">✗</td>{{else}}<td class="ok">✓</td>{{end}}
+<td>{{.ResourceType}} {{name .ResourceARN}}</td><td>{{date .LatestBackup}}</td>
+<td class="num">{{if .LatestBackup.IsZero}}n/a{{else}}{{duration .Age}}{{end}}</td><td class="num">{{duration .RPO}}</td>
+</tr>
+{{- else}}
+<tr><td colspan="5">No RDS clusters or EFS file systems found in the stack.</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Inventory</h2>
+<table>
+<tr><th>Type</th><th>Resource</th><th>Recovery points</th><th>Newest (UTC)</th><th>Oldest (UTC)</th><th>Total size</th></tr>
+{{- range .Inventory}}
+<tr><td>{{.ResourceType}}</td><td>{{name .ResourceARN}}</td><td class="num">{{.RecoveryPoints}}</td><td>{{date .Newest}}</td><td>{{date .Oldest}}</td><td class="num">{{bytes .TotalBytes}}</td></tr>
+{{- else}}
+<tr><td colspan="6">The vault has no recovery points.</td></tr>
+{{- end}}
+</table>
+<h2>Recent Jobs</h2>
+<table>
+<tr><th>Created (UTC)</th><th>Kind</th><th>Type</th><th>Resource</th><th>State</th><th>Message</th></tr>
+{{- range .RecentJobs}}
+<tr><td>{{date .CreatedAt}}</td><td>{{.Kind}}</td><td>{{.ResourceType}}</td><td>{{name .ResourceARN}}</td>
+<td{{if .Failed}} class="bad"{{else if .Succeeded}} class="ok"{{end}}>{{.State}}</td><td>{{.StatusMessage}}</td></tr>
+{{- else}}
+<tr><td colspan="6">No jobs in the window.</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
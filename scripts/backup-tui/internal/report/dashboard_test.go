@@ -0,0 +1,65 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+func TestBuildDashboard(t *testing.T) {
+	db := "arn:aws:rds:us-west-2:1:cluster:db"
+	points := []aws.RecoveryPoint{
+		{ResourceType: "RDS", ResourceID: "db", ResourceARN: db, Status: "COMPLETED", CreationDate: testUntil.Add(-30 * time.Hour), BackupSizeInBytes: 1 << 30},
+		{ResourceType: "RDS", ResourceID: "db", ResourceARN: db, Status: "COMPLETED", CreationDate: testUntil.Add(-6 * time.Hour), BackupSizeInBytes: 1 << 30},
+		{ResourceType: "EFS", ResourceID: "fs-1", ResourceARN: "arn:aws:elasticfilesystem:us-west-2:1:file-system/fs-1", Status: "COMPLETED", CreationDate: testUntil.Add(-2 * time.Hour)},
+	}
+	var records []aws.JobRecord
+	for i := range dashboardJobs + 5 {
+		records = append(records, job(aws.JobKindBackup, "COMPLETED", float64(i), 10))
+	}
+	records = append(records, job(aws.JobKindRestore, "FAILED", 160, 5))
+
+	s := BuildSummary(nil, points, BuildJobs(records, testSince, testUntil), 26*time.Hour, nil, testUntil)
+	s.Stack, s.Vault, s.Region = "Stack", "vault", "us-west-2"
+	d := BuildDashboard(s, points, records)
+
+	if len(d.Inventory) != 2 || d.Inventory[0].ResourceType != "EFS" {
+		t.Fatalf("inventory should list each resource once, by type, got %+v", d.Inventory)
+	}
+	if rds := d.Inventory[1]; rds.RecoveryPoints != 2 || rds.TotalBytes != 2<<30 || !rds.Newest.Equal(points[1].CreationDate) || !rds.Oldest.Equal(points[0].CreationDate) {
+		t.Errorf("unexpected RDS inventory %+v", rds)
+	}
+	if len(d.RecentJobs) != dashboardJobs || d.RecentJobs[0].State != "FAILED" {
+		t.Errorf("recent jobs should be the newest %d, newest first, got %d starting %+v", dashboardJobs, len(d.RecentJobs), d.RecentJobs[0])
+	}
+
+	d.Refresh = 5 * time.Minute
+	html, err := d.HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(html)
+	for _, want := range []string{`content="300"`, "Backups: Stack", "All resources within their recovery objectives", "<td>fs-1</td>", "2.0 GB", `class="bad">FAILED`} {
+		if !strings.Contains(page, want) {
+			t.Errorf("dashboard page should contain %q:\n%s", want, page)
+		}
+	}
+
+	if _, err := d.JSON(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDashboard_HTMLEscapes(t *testing.T) {
+	s := BuildSummary(nil, nil, nil, 26*time.Hour, nil, testUntil)
+	s.Stack = "<script>"
+	html, err := BuildDashboard(s, nil, nil).HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(html), "<script>") || strings.Contains(string(html), "http-equiv") {
+		t.Errorf("stack name should be escaped and the page should not reload without a refresh:\n%s", html)
+	}
+}
@@ -0,0 +1,340 @@
+// Package report builds operational reports from AWS Backup job history.
+// This file implements inventory reconciliation: the vault's recovery points
+// are exported to CSV, and a later run compares the vault with a previous
+// export, listing the recovery points created since, those that expired or
+// were deleted, and those whose size changed, as markdown (with a sign-off
+// section for monthly compliance attestations) or JSON.
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/deeplink"
+)
+
+// inventoryColumns are the columns of an inventory CSV export, in order.
+var inventoryColumns = []string{
+	"recovery_point_arn", "resource_type", "resource_id", "resource_arn", "status",
+	"created_at", "size_bytes", "move_to_cold_at", "delete_at",
+}
+
+// InventoryRecord is a recovery point as exported to CSV.
+type InventoryRecord struct {
+	RecoveryPointARN string     `json:"recoveryPointArn"`
+	ResourceType     string     `json:"resourceType"`
+	ResourceID       string     `json:"resourceId"`
+	ResourceARN      string     `json:"resourceArn"`
+	Status           string     `json:"status"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	SizeBytes        int64      `json:"sizeBytes"`
+	MoveToColdAt     *time.Time `json:"moveToColdAt,omitempty"` // Unset when never moved
+	DeleteAt         *time.Time `json:"deleteAt,omitempty"`     // Unset when kept indefinitely
+}
+
+// inventoryRecord converts a recovery point for export.
+func inventoryRecord(p aws.RecoveryPoint) InventoryRecord {
+	return InventoryRecord{
+		RecoveryPointARN: p.RecoveryPointARN,
+		ResourceType:     p.ResourceType,
+		ResourceID:       p.ResourceID,
+		ResourceARN:      p.ResourceARN,
+		Status:           p.Status,
+		CreatedAt:        p.CreationDate.UTC(),
+		SizeBytes:        p.BackupSizeInBytes,
+		MoveToColdAt:     timePtr(p.MoveToColdAt.UTC()),
+		DeleteAt:         timePtr(p.DeleteAt.UTC()),
+	}
+}
+
+// WriteInventoryCSV writes points as CSV with a header row, ordered by
+// resource and then creation date so that exports diff cleanly.
+func WriteInventoryCSV(w io.Writer, points []aws.RecoveryPoint) error {
+	records := make([]InventoryRecord, 0, len(points))
+	for _, p := range points {
+		records = append(records, inventoryRecord(p))
+	}
+	sortRecords(records)
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write(inventoryColumns)
+	for _, r := range records {
+		_ = cw.Write([]string{
+			r.RecoveryPointARN, r.ResourceType, r.ResourceID, r.ResourceARN, r.Status,
+			r.CreatedAt.Format(time.RFC3339), strconv.FormatInt(r.SizeBytes, 10),
+			csvTime(r.MoveToColdAt), csvTime(r.DeleteAt),
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write inventory: %w", err)
+	}
+	return nil
+}
+
+// ReadInventoryCSV reads an inventory written by WriteInventoryCSV.
+// Columns are matched by their header, so columns added in a spreadsheet,
+// e.g. reviewer notes, are ignored.
+func ReadInventoryCSV(r io.Reader) ([]InventoryRecord, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the inventory is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheets may save the file with a byte order mark
+		index[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, name := range inventoryColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("the inventory has no %s column; export one with 'backup-tui inventory export'", name)
+		}
+	}
+
+	var records []InventoryRecord
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read inventory: %w", err)
+		}
+		field := func(name string) string {
+			if i := index[name]; i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		rec := InventoryRecord{
+			RecoveryPointARN: field("recovery_point_arn"),
+			ResourceType:     field("resource_type"),
+			ResourceID:       field("resource_id"),
+			ResourceARN:      field("resource_arn"),
+			Status:           field("status"),
+		}
+		if rec.RecoveryPointARN == "" {
+			return nil, fmt.Errorf("inventory line %d: no recovery_point_arn", line)
+		}
+		if rec.CreatedAt, err = time.Parse(time.RFC3339, field("created_at")); err != nil {
+			return nil, fmt.Errorf("inventory line %d: invalid created_at %q", line, field("created_at"))
+		}
+		if rec.SizeBytes, err = strconv.ParseInt(field("size_bytes"), 10, 64); err != nil {
+			return nil, fmt.Errorf("inventory line %d: invalid size_bytes %q", line, field("size_bytes"))
+		}
+		if rec.MoveToColdAt, err = parseCSVTime(field("move_to_cold_at")); err != nil {
+			return nil, fmt.Errorf("inventory line %d: invalid move_to_cold_at: %w", line, err)
+		}
+		if rec.DeleteAt, err = parseCSVTime(field("delete_at")); err != nil {
+			return nil, fmt.Errorf("inventory line %d: invalid delete_at: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+}
+
+// csvTime formats an optional time for CSV, empty when unset.
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseCSVTime parses an optional CSV time.
+func parseCSVTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not an RFC 3339 time", s)
+	}
+	return &t, nil
+}
+
+// sortRecords orders records by resource, then creation date.
+func sortRecords(records []InventoryRecord) {
+	sort.SliceStable(records, func(a, b int) bool {
+		ra, rb := records[a], records[b]
+		if ra.ResourceType != rb.ResourceType {
+			return ra.ResourceType < rb.ResourceType
+		}
+		if ra.ResourceID != rb.ResourceID {
+			return ra.ResourceID < rb.ResourceID
+		}
+		return ra.CreatedAt.Before(rb.CreatedAt)
+	})
+}
+
+// RemovedPoint is a recovery point in the previous export that is no
+// longer in the vault.
+type RemovedPoint struct {
+	InventoryRecord
+	// BeforeSchedule is set when the recovery point was deleted before its
+	// lifecycle's delete date, or had none, so its deletion needs an
+	// explanation in an attestation.
+	BeforeSchedule bool `json:"beforeSchedule"`
+}
+
+// SizeChange is a recovery point whose size differs from the previous
+// export.
+type SizeChange struct {
+	InventoryRecord
+	PreviousSizeBytes int64 `json:"previousSizeBytes"`
+}
+
+// Reconciliation compares a vault's recovery points with a previous
+// inventory export.
+type Reconciliation struct {
+	Stack       string    `json:"stack"`
+	Vault       string    `json:"vault"`
+	Region      string    `json:"region"`
+	Previous    string    `json:"previous"` // Name of the previous export
+	GeneratedAt time.Time `json:"generatedAt"`
+
+	PreviousCount int `json:"previousCount"`
+	CurrentCount  int `json:"currentCount"`
+
+	New       []InventoryRecord `json:"new"`       // Created since the previous export
+	Removed   []RemovedPoint    `json:"removed"`   // Expired or deleted since
+	Resized   []SizeChange      `json:"resized"`   // In both, with a different size
+	Unchanged int               `json:"unchanged"` // In both, with the same size
+}
+
+// Reconcile compares points, the vault's recovery points at now, with
+// previous, an earlier export.
+func Reconcile(previous []InventoryRecord, points []aws.RecoveryPoint, now time.Time) *Reconciliation {
+	r := &Reconciliation{
+		GeneratedAt:   now.UTC(),
+		PreviousCount: len(previous),
+		CurrentCount:  len(points),
+		New:           []InventoryRecord{},
+		Removed:       []RemovedPoint{},
+		Resized:       []SizeChange{},
+	}
+	before := make(map[string]InventoryRecord, len(previous))
+	for _, rec := range previous {
+		before[rec.RecoveryPointARN] = rec
+	}
+	for _, p := range points {
+		current := inventoryRecord(p)
+		prev, ok := before[p.RecoveryPointARN]
+		delete(before, p.RecoveryPointARN)
+		switch {
+		case !ok:
+			r.New = append(r.New, current)
+		case prev.SizeBytes != current.SizeBytes:
+			r.Resized = append(r.Resized, SizeChange{InventoryRecord: current, PreviousSizeBytes: prev.SizeBytes})
+		default:
+			r.Unchanged++
+		}
+	}
+	var removed []InventoryRecord
+	for _, rec := range before {
+		removed = append(removed, rec)
+	}
+	sortRecords(removed)
+	for _, rec := range removed {
+		r.Removed = append(r.Removed, RemovedPoint{InventoryRecord: rec, BeforeSchedule: rec.DeleteAt == nil || now.Before(*rec.DeleteAt)})
+	}
+	sortRecords(r.New)
+	sort.SliceStable(r.Resized, func(a, b int) bool { return r.Resized[a].CreatedAt.Before(r.Resized[b].CreatedAt) })
+	return r
+}
+
+// JSON renders the reconciliation as indented JSON.
+func (r *Reconciliation) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode reconciliation: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Markdown renders the reconciliation as a markdown document ending in a
+// sign-off table for the attestation.
+func (r *Reconciliation) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Backup Inventory Reconciliation: %s\n\n", r.Stack)
+	fmt.Fprintf(&b, "- **Vault:** %s (%s)\n", r.Vault, r.Region)
+	fmt.Fprintf(&b, "- **Open in backup-tui:** `%s`\n", deeplink.Link{Region: r.Region, Stack: r.Stack, Vault: r.Vault})
+	fmt.Fprintf(&b, "- **Compared with:** %s\n", r.Previous)
+	fmt.Fprintf(&b, "- **Generated:** %s\n\n", r.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"))
+
+	early := 0
+	for _, p := range r.Removed {
+		if p.BeforeSchedule {
+			early++
+		}
+	}
+	b.WriteString("| Change | Recovery points |\n")
+	b.WriteString("|--------|----------------:|\n")
+	fmt.Fprintf(&b, "| In the previous export | %d |\n", r.PreviousCount)
+	fmt.Fprintf(&b, "| New | %d |\n", len(r.New))
+	fmt.Fprintf(&b, "| Expired or deleted | %d |\n", len(r.Removed))
+	fmt.Fprintf(&b, "| Deleted before their scheduled date | %d |\n", early)
+	fmt.Fprintf(&b, "| Size changed | %d |\n", len(r.Resized))
+	fmt.Fprintf(&b, "| Unchanged | %d |\n", r.Unchanged)
+	fmt.Fprintf(&b, "| In the vault now | %d |\n", r.CurrentCount)
+
+	b.WriteString("\n## New\n\n")
+	if len(r.New) == 0 {
+		b.WriteString("No recovery points were created since the previous export.\n")
+	} else {
+		b.WriteString("| Created (UTC) | Resource | Status | Size | Recovery point |\n")
+		b.WriteString("|---------------|----------|--------|-----:|----------------|\n")
+		for _, p := range r.New {
+			fmt.Fprintf(&b, "| %s | %s %s | %s | %s | %s |\n", formatDate(&p.CreatedAt), p.ResourceType, p.ResourceID,
+				p.Status, formatBytes(p.SizeBytes), p.RecoveryPointARN)
+		}
+	}
+
+	b.WriteString("\n## Expired or Deleted\n\n")
+	if len(r.Removed) == 0 {
+		b.WriteString("Every recovery point in the previous export is still in the vault.\n")
+	} else {
+		b.WriteString("| Created (UTC) | Resource | Scheduled deletion | | Recovery point |\n")
+		b.WriteString("|---------------|----------|--------------------|-|----------------|\n")
+		for _, p := range r.Removed {
+			note := "expired"
+			if p.BeforeSchedule {
+				note = "**deleted early**"
+			}
+			fmt.Fprintf(&b, "| %s | %s %s | %s | %s | %s |\n", formatDate(&p.CreatedAt), p.ResourceType, p.ResourceID,
+				formatDate(p.DeleteAt), note, p.RecoveryPointARN)
+		}
+	}
+
+	b.WriteString("\n## Size Changed\n\n")
+	if len(r.Resized) == 0 {
+		b.WriteString("No recovery point changed size.\n")
+	} else {
+		b.WriteString("| Created (UTC) | Resource | Previous size | Size | Recovery point |\n")
+		b.WriteString("|---------------|----------|--------------:|-----:|----------------|\n")
+		for _, p := range r.Resized {
+			fmt.Fprintf(&b, "| %s | %s %s | %s | %s | %s |\n", formatDate(&p.CreatedAt), p.ResourceType, p.ResourceID,
+				formatBytes(p.PreviousSizeBytes), formatBytes(p.SizeBytes), p.RecoveryPointARN)
+		}
+	}
+
+	b.WriteString("\n## Sign-off\n\n")
+	b.WriteString("| Role | Name | Date | Signature |\n")
+	b.WriteString("|------|------|------|-----------|\n")
+	b.WriteString("| Prepared by | | | |\n")
+	b.WriteString("| Reviewed by | | | |\n")
+	b.WriteString("| Attested by | | | |\n")
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+func inventoryPoints() []aws.RecoveryPoint {
+	created := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	return []aws.RecoveryPoint{
+		{RecoveryPointARN: "rp-efs", ResourceType: "EFS", ResourceID: "fs-1", Status: "COMPLETED", CreationDate: created, BackupSizeInBytes: 2048},
+		{RecoveryPointARN: "rp-rds", ResourceType: "RDS", ResourceID: "openemr", Status: "COMPLETED", CreationDate: created,
+			BackupSizeInBytes: 1024, DeleteAt: created.AddDate(0, 0, 35), MoveToColdAt: created.AddDate(0, 0, 7)},
+	}
+}
+
+func TestInventoryCSV_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteInventoryCSV(&buf, inventoryPoints()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "recovery_point_arn,resource_type,") {
+		t.Errorf("export should start with the header, got %q", buf.String())
+	}
+
+	records, err := ReadInventoryCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1].RecoveryPointARN != "rp-rds" || records[1].DeleteAt == nil || records[0].DeleteAt != nil {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	if !records[1].CreatedAt.Equal(time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)) || records[1].SizeBytes != 1024 {
+		t.Errorf("record not read back: %+v", records[1])
+	}
+}
+
+func TestReadInventoryCSV_ReorderedAndInvalid(t *testing.T) {
+	// A spreadsheet may reorder columns, add notes, and save a byte order mark
+	csv := "\ufeffnotes,size_bytes,recovery_point_arn,resource_type,resource_id,resource_arn,status,created_at,move_to_cold_at,delete_at\n" +
+		"checked,10,rp-1,RDS,openemr,,COMPLETED,2026-03-01T03:00:00Z,,\n"
+	records, err := ReadInventoryCSV(strings.NewReader(csv))
+	if err != nil || len(records) != 1 || records[0].SizeBytes != 10 {
+		t.Errorf("reordered columns should be read, got %+v, %v", records, err)
+	}
+
+	for name, content := range map[string]string{
+		"empty":          "",
+		"missing column": "recovery_point_arn,resource_type\nrp-1,RDS\n",
+		"bad date":       strings.Join(inventoryColumns, ",") + "\nrp-1,RDS,openemr,,COMPLETED,yesterday,10,,\n",
+	} {
+		if _, err := ReadInventoryCSV(strings.NewReader(content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	expiredAt := now.Add(-time.Hour)
+	keptUntil := now.AddDate(1, 0, 0)
+	previous := []InventoryRecord{
+		{RecoveryPointARN: "rp-rds", ResourceType: "RDS", SizeBytes: 512},
+		{RecoveryPointARN: "rp-expired", ResourceType: "RDS", DeleteAt: &expiredAt},
+		{RecoveryPointARN: "rp-deleted", ResourceType: "RDS", DeleteAt: &keptUntil},
+	}
+	r := Reconcile(previous, inventoryPoints(), now)
+
+	if len(r.New) != 1 || r.New[0].RecoveryPointARN != "rp-efs" {
+		t.Errorf("New = %+v", r.New)
+	}
+	if len(r.Resized) != 1 || r.Resized[0].PreviousSizeBytes != 512 || r.Resized[0].SizeBytes != 1024 {
+		t.Errorf("Resized = %+v", r.Resized)
+	}
+	if len(r.Removed) != 2 {
+		t.Fatalf("Removed = %+v", r.Removed)
+	}
+	for _, p := range r.Removed {
+		if want := p.RecoveryPointARN == "rp-deleted"; p.BeforeSchedule != want {
+			t.Errorf("%s: BeforeSchedule = %v, want %v", p.RecoveryPointARN, p.BeforeSchedule, want)
+		}
+	}
+	if r.PreviousCount != 3 || r.CurrentCount != 2 || r.Unchanged != 0 {
+		t.Errorf("counts = %d previous, %d current, %d unchanged", r.PreviousCount, r.CurrentCount, r.Unchanged)
+	}
+
+	md := r.Markdown()
+	for _, want := range []string{"| Deleted before their scheduled date | 1 |", "**deleted early**", "| Attested by |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown should contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Package report builds operational reports from AWS Backup job history.
+// This file implements the prune preview: which of the on-demand backups
+// the tool created a prune policy keeps, and why, and which it deletes,
+// rendered as markdown or JSON before "backup-tui prune -apply" deletes
+// anything.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/deeplink"
+)
+
+// PruneEntry is a recovery point considered by a prune policy.
+type PruneEntry struct {
+	RecoveryPointARN string    `json:"recoveryPointArn"`
+	ResourceType     string    `json:"resourceType"`
+	ResourceID       string    `json:"resourceId"`
+	CreatedAt        time.Time `json:"createdAt"`
+	SizeBytes        int64     `json:"sizeBytes"`
+	// Reasons are why the policy keeps it, e.g. "last 1", "weekly
+	// 2026-W09", "monthly 2026-02", or the protection rule that keeps it.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// PrunePlan is the preview of applying a prune policy to the on-demand
+// backups the tool created in a vault.
+type PrunePlan struct {
+	Stack        string             `json:"stack"`
+	Vault        string             `json:"vault"`
+	Region       string             `json:"region"`
+	ResourceType string             `json:"resourceType,omitempty"` // Empty for all types
+	GeneratedAt  time.Time          `json:"generatedAt"`
+	Policy       config.PrunePolicy `json:"policy"`
+
+	// Delete are deleted when the plan is applied, oldest first.
+	Delete []PruneEntry `json:"delete"`
+	// Keep are kept by the policy, newest first.
+	Keep []PruneEntry `json:"keep"`
+	// Protected would be deleted but a deletion protection rule of the
+	// config file, or the vault's Vault Lock minimum retention, keeps them.
+	Protected []PruneEntry `json:"protected"`
+
+	DeleteBytes int64 `json:"deleteBytes"`
+
+	// Confirming words the markdown for the plan "prune -apply" prints
+	// before asking to delete, rather than for a preview.
+	Confirming bool `json:"-"`
+}
+
+// BuildPrunePlan applies policy to points, the on-demand backups the tool
+// created, per resource. protection returns the config rule protecting a
+// recovery point from deletion, or "". Recovery points the Vault Lock of
+// vault, which may be nil, does not yet allow to be deleted are protected
+// too, as AWS Backup would refuse to delete them.
+func BuildPrunePlan(points []aws.RecoveryPoint, policy config.PrunePolicy, protection func(aws.RecoveryPoint) string, vault *aws.VaultInfo, now time.Time) *PrunePlan {
+	p := &PrunePlan{GeneratedAt: now, Policy: policy, Delete: []PruneEntry{}, Keep: []PruneEntry{}, Protected: []PruneEntry{}}
+
+	byResource := make(map[string][]aws.RecoveryPoint)
+	var resources []string
+	for _, rp := range points {
+		key := rp.ResourceARN
+		if key == "" {
+			key = rp.ResourceID
+		}
+		if _, ok := byResource[key]; !ok {
+			resources = append(resources, key)
+		}
+		byResource[key] = append(byResource[key], rp)
+	}
+	sort.Strings(resources)
+
+	for _, key := range resources {
+		group := byResource[key]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].CreationDate.After(group[j].CreationDate) })
+		weeks, months := map[string]bool{}, map[string]bool{}
+		for i, rp := range group {
+			e := PruneEntry{RecoveryPointARN: rp.RecoveryPointARN, ResourceType: rp.ResourceType, ResourceID: rp.ResourceID,
+				CreatedAt: rp.CreationDate, SizeBytes: rp.BackupSizeInBytes}
+			if i < policy.KeepLast {
+				e.Reasons = append(e.Reasons, fmt.Sprintf("last %d", i+1))
+			}
+			year, week := rp.CreationDate.UTC().ISOWeek()
+			if w := fmt.Sprintf("%d-W%02d", year, week); !weeks[w] && len(weeks) < policy.KeepWeekly {
+				weeks[w] = true
+				e.Reasons = append(e.Reasons, "weekly "+w)
+			}
+			if mo := rp.CreationDate.UTC().Format("2006-01"); !months[mo] && len(months) < policy.KeepMonthly {
+				months[mo] = true
+				e.Reasons = append(e.Reasons, "monthly "+mo)
+			}
+
+			if len(e.Reasons) > 0 {
+				p.Keep = append(p.Keep, e)
+				continue
+			}
+			if protection != nil {
+				if rule := protection(rp); rule != "" {
+					e.Reasons = []string{"protected by " + rule}
+					p.Protected = append(p.Protected, e)
+					continue
+				}
+			}
+			if until := vault.ProtectedUntil(rp); now.Before(until) {
+				e.Reasons = []string{fmt.Sprintf("Vault Lock minimum retention of %d days, until %s", vault.MinRetentionDays, until.UTC().Format("2006-01-02"))}
+				p.Protected = append(p.Protected, e)
+				continue
+			}
+			p.Delete = append(p.Delete, e)
+			p.DeleteBytes += rp.BackupSizeInBytes
+		}
+	}
+
+	sort.SliceStable(p.Delete, func(a, b int) bool { return p.Delete[a].CreatedAt.Before(p.Delete[b].CreatedAt) })
+	sort.SliceStable(p.Keep, func(a, b int) bool { return p.Keep[a].CreatedAt.After(p.Keep[b].CreatedAt) })
+	return p
+}
+
+// JSON renders the plan as indented JSON.
+func (p *PrunePlan) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode prune plan: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Markdown renders the plan as a markdown document.
+func (p *PrunePlan) Markdown() string {
+	var b strings.Builder
+	title := "Prune Preview"
+	if p.Confirming {
+		title = "Prune Plan"
+	}
+	fmt.Fprintf(&b, "# %s: %s\n\n", title, p.Stack)
+	fmt.Fprintf(&b, "- **Vault:** %s (%s)\n", p.Vault, p.Region)
+	fmt.Fprintf(&b, "- **Open in backup-tui:** `%s`\n", deeplink.Link{Region: p.Region, Stack: p.Stack, Vault: p.Vault})
+	if p.ResourceType != "" {
+		fmt.Fprintf(&b, "- **Resource type:** %s\n", p.ResourceType)
+	}
+	fmt.Fprintf(&b, "- **Policy:** %s\n", p.Policy)
+	fmt.Fprintf(&b, "- **Generated:** %s\n\n", p.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"))
+	if p.Confirming {
+		b.WriteString("Only on-demand backups created by backup-tui are considered. Nothing has been\n")
+		b.WriteString("deleted yet: the backups listed below are deleted once confirmed.\n\n")
+	} else {
+		b.WriteString("Only on-demand backups created by backup-tui are considered. This is a preview:\n")
+		b.WriteString("nothing has been deleted. Run with -apply to delete the backups listed below.\n\n")
+	}
+
+	b.WriteString("| Outcome | Recovery points | Size |\n")
+	b.WriteString("|---------|----------------:|-----:|\n")
+	fmt.Fprintf(&b, "| Deleted | %d | %s |\n", len(p.Delete), formatBytes(p.DeleteBytes))
+	fmt.Fprintf(&b, "| Kept by the policy | %d | %s |\n", len(p.Keep), formatBytes(pruneBytes(p.Keep)))
+	fmt.Fprintf(&b, "| Kept by deletion protection | %d | %s |\n", len(p.Protected), formatBytes(pruneBytes(p.Protected)))
+
+	b.WriteString("\n## Deleted\n\n")
+	if len(p.Delete) == 0 {
+		b.WriteString("The policy keeps every backup.\n")
+	} else {
+		b.WriteString("| Created (UTC) | Resource | Size | Recovery point |\n")
+		b.WriteString("|---------------|----------|-----:|----------------|\n")
+		for _, e := range p.Delete {
+			fmt.Fprintf(&b, "| %s | %s %s | %s | %s |\n", formatDate(&e.CreatedAt), e.ResourceType, e.ResourceID,
+				formatBytes(e.SizeBytes), e.RecoveryPointARN)
+		}
+	}
+
+	for _, section := range []struct {
+		title   string
+		entries []PruneEntry
+		none    string
+	}{
+		{"Kept", p.Keep, "The policy keeps no backups."},
+		{"Protected", p.Protected, "No backup the policy deletes is protected."},
+	} {
+		fmt.Fprintf(&b, "\n## %s\n\n", section.title)
+		if len(section.entries) == 0 {
+			b.WriteString(section.none + "\n")
+			continue
+		}
+		b.WriteString("| Created (UTC) | Resource | Why | Recovery point |\n")
+		b.WriteString("|---------------|----------|-----|----------------|\n")
+		for _, e := range section.entries {
+			fmt.Fprintf(&b, "| %s | %s %s | %s | %s |\n", formatDate(&e.CreatedAt), e.ResourceType, e.ResourceID,
+				strings.Join(e.Reasons, ", "), e.RecoveryPointARN)
+		}
+	}
+	return b.String()
+}
+
+// pruneBytes sums the sizes of entries.
+func pruneBytes(entries []PruneEntry) int64 {
+	var total int64
+	for _, e := range entries {
+		total += e.SizeBytes
+	}
+	return total
+}
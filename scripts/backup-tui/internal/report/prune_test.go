@@ -0,0 +1,125 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+)
+
+func TestBuildPrunePlan(t *testing.T) {
+	// Daily on-demand backups of one cluster over the last 60 days
+	var points []aws.RecoveryPoint
+	for age := range 60 {
+		rp := point("db-"+string(rune('a'+age%26))+string(rune('a'+age/26)), "RDS", age, aws.Lifecycle{})
+		rp.ResourceARN = "arn:aws:rds:us-west-2:1:cluster:openemr"
+		points = append(points, rp)
+	}
+	policy := config.PrunePolicy{KeepLast: 3, KeepWeekly: 2, KeepMonthly: 3}
+	protectedARN := points[40].RecoveryPointARN
+	protection := func(rp aws.RecoveryPoint) string {
+		if rp.RecoveryPointARN == protectedARN {
+			return "audit"
+		}
+		return ""
+	}
+
+	p := BuildPrunePlan(points, policy, protection, nil, testNow)
+	// testNow is Tuesday 2026-03-31: the newest 3, Sunday 03-29 closing
+	// the previous week, and the newest of March, February, and January
+	kept := map[int]string{}
+	for _, e := range p.Keep {
+		kept[int(testNow.Sub(e.CreatedAt).Hours()/24)] = strings.Join(e.Reasons, ", ")
+	}
+	want := map[int]string{
+		0:  "last 1, weekly 2026-W14, monthly 2026-03",
+		1:  "last 2",
+		2:  "last 3, weekly 2026-W13",
+		31: "monthly 2026-02",
+		59: "monthly 2026-01",
+	}
+	if len(kept) != len(want) {
+		t.Errorf("kept %v, want %v", kept, want)
+	}
+	for age, reasons := range want {
+		if kept[age] != reasons {
+			t.Errorf("backup %d days old: kept for %q, want %q", age, kept[age], reasons)
+		}
+	}
+	if len(p.Protected) != 1 || p.Protected[0].RecoveryPointARN != protectedARN || p.Protected[0].Reasons[0] != "protected by audit" {
+		t.Errorf("protected backup should be kept, got %+v", p.Protected)
+	}
+	if len(p.Delete) != 60-len(want)-1 || !p.Delete[0].CreatedAt.Before(p.Delete[1].CreatedAt) {
+		t.Errorf("the rest should be deleted oldest first, got %d", len(p.Delete))
+	}
+	if p.DeleteBytes != int64(len(p.Delete))<<30 {
+		t.Errorf("DeleteBytes = %d", p.DeleteBytes)
+	}
+}
+
+func TestBuildPrunePlan_PerResource(t *testing.T) {
+	db, fs := point("db-1", "RDS", 1, aws.Lifecycle{}), point("fs-1", "EFS", 2, aws.Lifecycle{})
+	older := point("db-0", "RDS", 3, aws.Lifecycle{})
+	db.ResourceARN, older.ResourceARN, fs.ResourceARN = "cluster", "cluster", "fs"
+
+	p := BuildPrunePlan([]aws.RecoveryPoint{older, db, fs}, config.PrunePolicy{KeepLast: 1}, nil, nil, testNow)
+	if len(p.Keep) != 2 || len(p.Delete) != 1 || p.Delete[0].ResourceID != "db-0" {
+		t.Errorf("the newest backup of each resource should be kept, got keep %+v delete %+v", p.Keep, p.Delete)
+	}
+}
+
+func TestBuildPrunePlan_VaultLock(t *testing.T) {
+	fx, err := aws.LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vaultName := fx.Vaults[0]
+	fx.VaultLocks = map[string]aws.FixtureVaultLock{vaultName: {MinRetentionDays: 7}}
+	vault, err := aws.NewSimulatedBackupClient(fx).DescribeVault(context.Background(), vaultName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newest, locked, old := point("db-2", "RDS", 1, aws.Lifecycle{}), point("db-1", "RDS", 3, aws.Lifecycle{}), point("db-0", "RDS", 10, aws.Lifecycle{})
+	newest.ResourceARN, locked.ResourceARN, old.ResourceARN = "cluster", "cluster", "cluster"
+
+	p := BuildPrunePlan([]aws.RecoveryPoint{newest, locked, old}, config.PrunePolicy{KeepLast: 1}, nil, vault, testNow)
+	if len(p.Delete) != 1 || p.Delete[0].ResourceID != "db-0" {
+		t.Errorf("only the backup older than the minimum retention should be deleted, got %+v", p.Delete)
+	}
+	if len(p.Protected) != 1 || p.Protected[0].ResourceID != "db-1" ||
+		p.Protected[0].Reasons[0] != "Vault Lock minimum retention of 7 days, until 2026-04-04" {
+		t.Errorf("the backup inside the minimum retention should be protected by the Vault Lock, got %+v", p.Protected)
+	}
+	if md := p.Markdown(); !strings.Contains(md, "Vault Lock minimum retention of 7 days") {
+		t.Errorf("the plan should say why the backup is kept:\n%s", md)
+	}
+}
+
+func TestPrunePlan_Render(t *testing.T) {
+	newer, older := point("db-1", "RDS", 1, aws.Lifecycle{}), point("db-0", "RDS", 3, aws.Lifecycle{})
+	newer.ResourceARN, older.ResourceARN = "cluster", "cluster"
+	p := BuildPrunePlan([]aws.RecoveryPoint{newer, older}, config.PrunePolicy{KeepLast: 1}, nil, nil, testNow)
+	p.Stack, p.Vault, p.Region = "OpenemrEcsStack", "vault", "us-west-2"
+
+	md := p.Markdown()
+	for _, want := range []string{"# Prune Preview: OpenemrEcsStack", "keep last 1", "| Deleted | 1 | 1.0 GB |", older.RecoveryPointARN, "-apply"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown should contain %q:\n%s", want, md)
+		}
+	}
+	p.Confirming = true
+	if md := p.Markdown(); strings.Contains(md, "-apply") || !strings.Contains(md, "# Prune Plan: OpenemrEcsStack") {
+		t.Errorf("the plan printed before confirming should not say to run -apply:\n%s", md)
+	}
+	data, err := p.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded PrunePlan
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Delete) != 1 || decoded.Policy.KeepLast != 1 {
+		t.Errorf("JSON should round-trip, got %+v, %v", decoded, err)
+	}
+}
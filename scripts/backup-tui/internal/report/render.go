@@ -0,0 +1,66 @@
+// Package report builds operational reports from AWS Backup job history.
+// This file implements report rendering: every report is a Document that a
+// Renderer for the chosen output format turns into bytes, so the report
+// subcommands share one -format flag and new formats need no changes to
+// the reports.
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Document is a report that can be rendered in every output format.
+type Document interface {
+	JSON() ([]byte, error)
+	Markdown() string
+}
+
+// Renderer renders documents in one output format.
+type Renderer interface {
+	Render(doc Document) ([]byte, error)
+}
+
+// MarkdownRenderer renders documents as markdown.
+type MarkdownRenderer struct{}
+
+// Render renders doc as markdown.
+func (MarkdownRenderer) Render(doc Document) ([]byte, error) {
+	return []byte(doc.Markdown()), nil
+}
+
+// JSONRenderer renders documents as indented JSON.
+type JSONRenderer struct{}
+
+// Render renders doc as indented JSON.
+func (JSONRenderer) Render(doc Document) ([]byte, error) {
+	return doc.JSON()
+}
+
+// formats are the output formats for -format, the default first.
+var formats = []struct {
+	name     string
+	renderer Renderer
+}{
+	{"markdown", MarkdownRenderer{}},
+	{"json", JSONRenderer{}},
+}
+
+// Formats returns the names of the output formats, the default first.
+func Formats() []string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.name
+	}
+	return names
+}
+
+// NewRenderer returns the renderer for the named output format.
+func NewRenderer(format string) (Renderer, error) {
+	for _, f := range formats {
+		if f.name == format {
+			return f.renderer, nil
+		}
+	}
+	return nil, fmt.Errorf("-format must be %s, got %q", strings.Join(Formats(), " or "), format)
+}
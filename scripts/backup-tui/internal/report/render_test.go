@@ -0,0 +1,39 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewRenderer(t *testing.T) {
+	s := BuildSummary(nil, nil, nil, 26*time.Hour, nil, testUntil)
+	s.Stack = "Stack"
+
+	md, err := NewRenderer("markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := md.Render(s)
+	if err != nil || !strings.HasPrefix(string(data), "# Backup Summary: Stack") {
+		t.Errorf("markdown renderer should render the markdown document, got %q (%v)", data, err)
+	}
+
+	js, err := NewRenderer("json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = js.Render(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Summary
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Stack != "Stack" {
+		t.Errorf("json renderer should render the document as JSON, got %q (%v)", data, err)
+	}
+
+	if _, err := NewRenderer("html"); err == nil || !strings.Contains(err.Error(), "markdown or json") {
+		t.Errorf("unknown format should list the formats, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Package report builds operational reports from AWS Backup job history.
+// This file implements the job success-rate report: per job kind (backup,
+// restore, copy) success rates and average durations over a time window,
+// plus the failed jobs, rendered as markdown or JSON for weekly ops reviews.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// kinds is the order job kinds are reported in.
+var kinds = []string{aws.JobKindBackup, aws.JobKindRestore, aws.JobKindCopy}
+
+// KindSummary summarizes the jobs of one kind.
+type KindSummary struct {
+	Kind        string  `json:"kind"`
+	Total       int     `json:"total"`
+	Succeeded   int     `json:"succeeded"`
+	Failed      int     `json:"failed"`
+	InProgress  int     `json:"inProgress"`
+	SuccessRate float64 `json:"successRate"` // Succeeded / finished jobs, 0–100; -1 when none finished
+	AvgDuration Seconds `json:"avgDurationSeconds"`
+}
+
+// Seconds is a duration encoded in JSON as whole seconds.
+type Seconds time.Duration
+
+// MarshalJSON encodes the duration as whole seconds.
+func (s Seconds) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(time.Duration(s).Round(time.Second) / time.Second))
+}
+
+// JobsReport is the job success-rate report for a vault over a window.
+type JobsReport struct {
+	Stack    string          `json:"stack"`
+	Vault    string          `json:"vault"`
+	Region   string          `json:"region"`
+	Since    time.Time       `json:"since"`
+	Until    time.Time       `json:"until"`
+	Kinds    []KindSummary   `json:"kinds"`
+	Failures []aws.JobRecord `json:"failures"` // Failed jobs, newest first
+
+	restores []aws.JobRecord // Successful restore jobs, for RTO checks
+}
+
+// BuildJobs summarizes job records created in [since, until).
+func BuildJobs(records []aws.JobRecord, since, until time.Time) *JobsReport {
+	r := &JobsReport{Since: since, Until: until, Failures: []aws.JobRecord{}}
+	byKind := make(map[string]*KindSummary, len(kinds))
+	for _, k := range kinds {
+		byKind[k] = &KindSummary{Kind: k}
+	}
+	durations := make(map[string]time.Duration)
+
+	for _, j := range records {
+		if j.CreatedAt.Before(since) || !j.CreatedAt.Before(until) {
+			continue
+		}
+		s, ok := byKind[j.Kind]
+		if !ok {
+			continue
+		}
+		s.Total++
+		switch {
+		case j.Succeeded():
+			s.Succeeded++
+			durations[j.Kind] += j.Duration()
+			if j.Kind == aws.JobKindRestore {
+				r.restores = append(r.restores, j)
+			}
+		case j.Failed():
+			s.Failed++
+			r.Failures = append(r.Failures, j)
+		default:
+			s.InProgress++
+		}
+	}
+
+	for _, k := range kinds {
+		s := byKind[k]
+		s.SuccessRate = -1
+		if finished := s.Succeeded + s.Failed; finished > 0 {
+			s.SuccessRate = 100 * float64(s.Succeeded) / float64(finished)
+		}
+		// Average duration is over successful jobs: failures often end
+		// early and would make jobs look faster than they are.
+		if s.Succeeded > 0 {
+			s.AvgDuration = Seconds(durations[k] / time.Duration(s.Succeeded))
+		}
+		r.Kinds = append(r.Kinds, *s)
+	}
+
+	sort.SliceStable(r.Failures, func(a, b int) bool {
+		return r.Failures[a].CreatedAt.After(r.Failures[b].CreatedAt)
+	})
+	return r
+}
+
+// JSON renders the report as indented JSON.
+func (r *JobsReport) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Markdown renders the report as a markdown document.
+func (r *JobsReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Backup Job Report: %s\n\n", r.Stack)
+	fmt.Fprintf(&b, "- **Vault:** %s (%s)\n", r.Vault, r.Region)
+	fmt.Fprintf(&b, "- **Window:** %s to %s\n\n", r.Since.UTC().Format("2006-01-02 15:04 MST"), r.Until.UTC().Format("2006-01-02 15:04 MST"))
+
+	b.WriteString("| Jobs | Total | Succeeded | Failed | In progress | Success rate | Avg duration |\n")
+	b.WriteString("|------|------:|----------:|-------:|------------:|-------------:|-------------:|\n")
+	for _, s := range r.Kinds {
+		rate, avg := "n/a", "n/a"
+		if s.SuccessRate >= 0 {
+			rate = fmt.Sprintf("%.1f%%", s.SuccessRate)
+		}
+		if s.AvgDuration > 0 {
+			avg = formatDuration(time.Duration(s.AvgDuration))
+		}
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %s | %s |\n", s.Kind, s.Total, s.Succeeded, s.Failed, s.InProgress, rate, avg)
+	}
+
+	b.WriteString("\n## Failures\n\n")
+	if len(r.Failures) == 0 {
+		b.WriteString("No failed jobs in this window.\n")
+		return b.String()
+	}
+	b.WriteString("| Created (UTC) | Kind | Resource | State | Message |\n")
+	b.WriteString("|---------------|------|----------|-------|---------|\n")
+	for _, j := range r.Failures {
+		fmt.Fprintf(&b, "| %s | %s | %s %s | %s | %s |\n",
+			j.CreatedAt.UTC().Format("2006-01-02 15:04"), j.Kind, j.ResourceType, resourceName(j.ResourceARN),
+			j.State, strings.ReplaceAll(j.StatusMessage, "|", `\|`))
+	}
+	return b.String()
+}
+
+// formatDuration formats a duration as e.g. "42m", "1h05m", or "35s".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// resourceName returns the last segment of a resource ARN (cluster name,
+// file system ID), which is what operators recognize.
+func resourceName(arn string) string {
+	if i := strings.LastIndexAny(arn, ":/"); i >= 0 {
+		return arn[i+1:]
+	}
+	return arn
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+var (
+	testSince = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	testUntil = testSince.Add(7 * 24 * time.Hour)
+)
+
+func job(kind, state string, createdHours, minutes float64) aws.JobRecord {
+	created := testSince.Add(time.Duration(createdHours * float64(time.Hour)))
+	j := aws.JobRecord{Kind: kind, JobID: kind + "-" + state, ResourceType: "RDS", ResourceARN: "arn:aws:rds:us-west-2:1:cluster:db", State: state, CreatedAt: created}
+	if minutes > 0 {
+		j.CompletedAt = created.Add(time.Duration(minutes * float64(time.Minute)))
+	}
+	return j
+}
+
+func TestBuildJobs_RatesAndDurations(t *testing.T) {
+	r := BuildJobs([]aws.JobRecord{
+		job(aws.JobKindBackup, "COMPLETED", 1, 20),
+		job(aws.JobKindBackup, "COMPLETED", 25, 40),
+		job(aws.JobKindBackup, "FAILED", 49, 2),
+		job(aws.JobKindBackup, "RUNNING", 160, 0),
+		job(aws.JobKindCopy, "PARTIAL", 10, 5),
+	}, testSince, testUntil)
+
+	backup := r.Kinds[0]
+	if backup.Total != 4 || backup.Succeeded != 2 || backup.Failed != 1 || backup.InProgress != 1 {
+		t.Errorf("unexpected backup counts: %+v", backup)
+	}
+	if int(backup.SuccessRate+0.5) != 67 {
+		t.Errorf("success rate should exclude running jobs, got %.1f", backup.SuccessRate)
+	}
+	if time.Duration(backup.AvgDuration) != 30*time.Minute {
+		t.Errorf("average should cover successful jobs only, got %v", time.Duration(backup.AvgDuration))
+	}
+	if r.Kinds[1].SuccessRate != -1 {
+		t.Error("restore rate should be -1 with no finished jobs")
+	}
+	if len(r.Failures) != 2 || r.Failures[0].State != "FAILED" {
+		t.Errorf("failures should be newest first and include PARTIAL, got %+v", r.Failures)
+	}
+}
+
+func TestBuildJobs_Window(t *testing.T) {
+	r := BuildJobs([]aws.JobRecord{
+		job(aws.JobKindBackup, "COMPLETED", -1, 10),
+		job(aws.JobKindBackup, "COMPLETED", 7*24, 10),
+		job("unknown", "COMPLETED", 1, 10),
+	}, testSince, testUntil)
+	for _, s := range r.Kinds {
+		if s.Total != 0 {
+			t.Errorf("%s: jobs outside the window or of unknown kind should be ignored", s.Kind)
+		}
+	}
+}
+
+func TestJobsReport_Markdown(t *testing.T) {
+	r := BuildJobs([]aws.JobRecord{
+		job(aws.JobKindBackup, "COMPLETED", 1, 65),
+		job(aws.JobKindRestore, "FAILED", 2, 1),
+	}, testSince, testUntil)
+	r.Stack, r.Vault, r.Region = "Stack", "vault", "us-west-2"
+	r.Failures[0].StatusMessage = "bad | pipe"
+
+	md := r.Markdown()
+	for _, want := range []string{
+		"# Backup Job Report: Stack",
+		"| backup | 1 | 1 | 0 | 0 | 100.0% | 1h05m |",
+		"| copy | 0 | 0 | 0 | 0 | n/a | n/a |",
+		"| 2026-03-01 02:00 | restore | RDS db | FAILED | bad \\| pipe |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestJobsReport_MarkdownNoFailures(t *testing.T) {
+	r := BuildJobs(nil, testSince, testUntil)
+	if !strings.Contains(r.Markdown(), "No failed jobs") {
+		t.Error("expected a no-failures note")
+	}
+}
+
+func TestJobsReport_JSON(t *testing.T) {
+	r := BuildJobs([]aws.JobRecord{job(aws.JobKindBackup, "COMPLETED", 1, 30)}, testSince, testUntil)
+	data, err := r.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Kinds []struct {
+			Kind        string  `json:"kind"`
+			SuccessRate float64 `json:"successRate"`
+			AvgDuration int64   `json:"avgDurationSeconds"`
+		} `json:"kinds"`
+		Failures []any `json:"failures"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Kinds[0].AvgDuration != 1800 || decoded.Kinds[0].SuccessRate != 100 {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+	if decoded.Failures == nil {
+		t.Error("failures should encode as an empty array, not null")
+	}
+}
@@ -0,0 +1,227 @@
+// Package report builds operational reports from AWS Backup job history.
+// This file implements the retention dry run: before a lifecycle change is
+// applied to a vault's recovery points, it lists exactly which recovery
+// points would be deleted or moved to cold storage, rendered as markdown
+// (with a sign-off section) or JSON.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/deeplink"
+)
+
+// RetentionChange is a recovery point affected by a proposed lifecycle.
+type RetentionChange struct {
+	RecoveryPointARN string     `json:"recoveryPointArn"`
+	ResourceType     string     `json:"resourceType"`
+	ResourceID       string     `json:"resourceId"`
+	Status           string     `json:"status"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	SizeBytes        int64      `json:"sizeBytes"`
+	CurrentDeleteAt  *time.Time `json:"currentDeleteAt,omitempty"` // Unset when kept indefinitely
+	NewDeleteAt      *time.Time `json:"newDeleteAt,omitempty"`
+	CurrentColdAt    *time.Time `json:"currentMoveToColdAt,omitempty"` // Unset when never moved
+	NewColdAt        *time.Time `json:"newMoveToColdAt,omitempty"`
+}
+
+// RetentionPlan is the dry run of applying a lifecycle to a vault's
+// recovery points.
+type RetentionPlan struct {
+	Stack        string        `json:"stack"`
+	Vault        string        `json:"vault"`
+	Region       string        `json:"region"`
+	ResourceType string        `json:"resourceType,omitempty"` // Empty for all types
+	GeneratedAt  time.Time     `json:"generatedAt"`
+	Proposed     aws.Lifecycle `json:"proposed"`
+
+	// Deleted are past their new delete date: AWS Backup deletes them as
+	// soon as the lifecycle is applied.
+	Deleted []RetentionChange `json:"deleted"`
+	// ExpireSooner are kept for now but deleted earlier than today.
+	ExpireSooner []RetentionChange `json:"expireSooner"`
+	// MovedToCold move to cold storage earlier than today, or for the
+	// first time. Recovery points already in cold storage are not listed.
+	MovedToCold []RetentionChange `json:"movedToCold"`
+	// Unchanged counts the recovery points kept at least as long and not
+	// moved to cold storage any sooner.
+	Unchanged int `json:"unchanged"`
+
+	DeletedBytes int64 `json:"deletedBytes"`
+}
+
+// BuildRetentionPlan classifies points by what applying proposed to them
+// at now would do. Cold storage settings only affect resource types that
+// support cold storage; AWS Backup ignores them for the rest.
+func BuildRetentionPlan(points []aws.RecoveryPoint, proposed aws.Lifecycle, now time.Time) *RetentionPlan {
+	p := &RetentionPlan{
+		GeneratedAt:  now,
+		Proposed:     proposed,
+		Deleted:      []RetentionChange{},
+		ExpireSooner: []RetentionChange{},
+		MovedToCold:  []RetentionChange{},
+	}
+	for _, rp := range points {
+		c := RetentionChange{
+			RecoveryPointARN: rp.RecoveryPointARN,
+			ResourceType:     rp.ResourceType,
+			ResourceID:       rp.ResourceID,
+			Status:           rp.Status,
+			CreatedAt:        rp.CreationDate,
+			SizeBytes:        rp.BackupSizeInBytes,
+			CurrentDeleteAt:  timePtr(rp.DeleteAt),
+			CurrentColdAt:    timePtr(rp.MoveToColdAt),
+		}
+
+		newDelete := proposed.DeleteAt(rp.CreationDate)
+		c.NewDeleteAt = timePtr(newDelete)
+		if !newDelete.IsZero() && !newDelete.After(now) {
+			p.Deleted = append(p.Deleted, c)
+			p.DeletedBytes += rp.BackupSizeInBytes
+			continue
+		}
+
+		changed := false
+		if !newDelete.IsZero() && (rp.DeleteAt.IsZero() || newDelete.Before(rp.DeleteAt)) {
+			p.ExpireSooner = append(p.ExpireSooner, c)
+			changed = true
+		}
+		if newCold := proposed.MoveToColdAt(rp.CreationDate); !newCold.IsZero() && aws.SupportsColdStorage(rp.ResourceType) &&
+			!rp.InColdStorage(now) && (rp.MoveToColdAt.IsZero() || newCold.Before(rp.MoveToColdAt)) {
+			c.NewColdAt = timePtr(newCold)
+			p.MovedToCold = append(p.MovedToCold, c)
+			changed = true
+		}
+		if !changed {
+			p.Unchanged++
+		}
+	}
+
+	for _, list := range [][]RetentionChange{p.Deleted, p.ExpireSooner, p.MovedToCold} {
+		sort.SliceStable(list, func(a, b int) bool { return list[a].CreatedAt.Before(list[b].CreatedAt) })
+	}
+	return p
+}
+
+// JSON renders the plan as indented JSON.
+func (p *RetentionPlan) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode retention plan: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Markdown renders the plan as a markdown document ending in a sign-off
+// table for the reviewers who approve the change.
+func (p *RetentionPlan) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Retention Dry Run: %s\n\n", p.Stack)
+	fmt.Fprintf(&b, "- **Vault:** %s (%s)\n", p.Vault, p.Region)
+	fmt.Fprintf(&b, "- **Open in backup-tui:** `%s`\n", deeplink.Link{Region: p.Region, Stack: p.Stack, Vault: p.Vault})
+	if p.ResourceType != "" {
+		fmt.Fprintf(&b, "- **Resource type:** %s\n", p.ResourceType)
+	}
+	fmt.Fprintf(&b, "- **Proposed lifecycle:** %s\n", p.Proposed)
+	fmt.Fprintf(&b, "- **Generated:** %s\n\n", p.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"))
+	b.WriteString("This is a dry run: no recovery point has been changed.\n\n")
+
+	b.WriteString("| Change | Recovery points | Size |\n")
+	b.WriteString("|--------|----------------:|-----:|\n")
+	fmt.Fprintf(&b, "| Deleted when applied | %d | %s |\n", len(p.Deleted), formatBytes(p.DeletedBytes))
+	fmt.Fprintf(&b, "| Deleted sooner than today | %d | %s |\n", len(p.ExpireSooner), formatBytes(totalBytes(p.ExpireSooner)))
+	fmt.Fprintf(&b, "| Moved to cold storage sooner | %d | %s |\n", len(p.MovedToCold), formatBytes(totalBytes(p.MovedToCold)))
+	fmt.Fprintf(&b, "| Unchanged | %d | |\n", p.Unchanged)
+
+	b.WriteString("\n## Deleted When Applied\n\n")
+	if len(p.Deleted) == 0 {
+		b.WriteString("No recovery points are past the proposed delete date.\n")
+	} else {
+		b.WriteString("| Created (UTC) | Resource | Status | Size | Currently deleted | Recovery point |\n")
+		b.WriteString("|---------------|----------|--------|-----:|-------------------|----------------|\n")
+		for _, c := range p.Deleted {
+			fmt.Fprintf(&b, "| %s | %s %s | %s | %s | %s | %s |\n", formatDate(&c.CreatedAt), c.ResourceType, c.ResourceID,
+				c.Status, formatBytes(c.SizeBytes), formatDate(c.CurrentDeleteAt), c.RecoveryPointARN)
+		}
+	}
+
+	b.WriteString("\n## Deleted Sooner\n\n")
+	if len(p.ExpireSooner) == 0 {
+		b.WriteString("No recovery points are deleted earlier than today.\n")
+	} else {
+		b.WriteString("| Created (UTC) | Resource | Currently deleted | Deleted after change | Recovery point |\n")
+		b.WriteString("|---------------|----------|-------------------|----------------------|----------------|\n")
+		for _, c := range p.ExpireSooner {
+			fmt.Fprintf(&b, "| %s | %s %s | %s | %s | %s |\n", formatDate(&c.CreatedAt), c.ResourceType, c.ResourceID,
+				formatDate(c.CurrentDeleteAt), formatDate(c.NewDeleteAt), c.RecoveryPointARN)
+		}
+	}
+
+	b.WriteString("\n## Moved to Cold Storage\n\n")
+	if len(p.MovedToCold) == 0 {
+		b.WriteString("No recovery points move to cold storage earlier than today.\n")
+	} else {
+		b.WriteString("| Created (UTC) | Resource | Currently moved | Moved after change | Recovery point |\n")
+		b.WriteString("|---------------|----------|-----------------|--------------------|----------------|\n")
+		for _, c := range p.MovedToCold {
+			moved := formatDate(c.NewColdAt)
+			if !c.NewColdAt.After(p.GeneratedAt) {
+				moved = "when applied"
+			}
+			fmt.Fprintf(&b, "| %s | %s %s | %s | %s | %s |\n", formatDate(&c.CreatedAt), c.ResourceType, c.ResourceID,
+				formatDate(c.CurrentColdAt), moved, c.RecoveryPointARN)
+		}
+	}
+
+	b.WriteString("\n## Sign-off\n\n")
+	b.WriteString("| Role | Name | Date | Signature |\n")
+	b.WriteString("|------|------|------|-----------|\n")
+	b.WriteString("| Prepared by | | | |\n")
+	b.WriteString("| Reviewed by | | | |\n")
+	b.WriteString("| Approved by | | | |\n")
+	return b.String()
+}
+
+// totalBytes sums the sizes of changes.
+func totalBytes(changes []RetentionChange) int64 {
+	var total int64
+	for _, c := range changes {
+		total += c.SizeBytes
+	}
+	return total
+}
+
+// timePtr returns a pointer to t, or nil for the zero time.
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// formatDate formats a date for the report, or "never" when unset.
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.UTC().Format("2006-01-02 15:04")
+}
+
+// formatBytes formats a size in bytes with a binary unit, e.g. "1.5 GB".
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+var testNow = time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+func point(id, resourceType string, ageDays int, current aws.Lifecycle) aws.RecoveryPoint {
+	created := testNow.AddDate(0, 0, -ageDays)
+	return aws.RecoveryPoint{
+		RecoveryPointARN:  "arn:aws:backup:us-west-2:1:recovery-point:" + id,
+		CreationDate:      created,
+		Status:            "COMPLETED",
+		ResourceType:      resourceType,
+		ResourceID:        id,
+		BackupSizeInBytes: 1 << 30,
+		Lifecycle:         current,
+		DeleteAt:          current.DeleteAt(created),
+		MoveToColdAt:      current.MoveToColdAt(created),
+	}
+}
+
+func TestBuildRetentionPlan_Deletions(t *testing.T) {
+	keep35 := aws.Lifecycle{DeleteAfterDays: 35}
+	p := BuildRetentionPlan([]aws.RecoveryPoint{
+		point("old", "RDS", 20, keep35),
+		point("older", "RDS", 30, keep35),
+		point("recent", "RDS", 5, keep35),
+		point("forever", "RDS", 2, aws.Lifecycle{}),
+		point("new", "RDS", 1, aws.Lifecycle{DeleteAfterDays: 7}),
+	}, aws.Lifecycle{DeleteAfterDays: 14}, testNow)
+
+	if len(p.Deleted) != 2 || p.Deleted[0].ResourceID != "older" || p.Deleted[1].ResourceID != "old" {
+		t.Errorf("points past 14 days should be deleted, oldest first: %+v", p.Deleted)
+	}
+	if p.DeletedBytes != 2<<30 {
+		t.Errorf("deleted bytes = %d", p.DeletedBytes)
+	}
+	if len(p.ExpireSooner) != 2 || p.ExpireSooner[0].ResourceID != "recent" || p.ExpireSooner[1].CurrentDeleteAt != nil {
+		t.Errorf("shortened and previously indefinite points should expire sooner: %+v", p.ExpireSooner)
+	}
+	if p.Unchanged != 1 {
+		t.Errorf("a point already deleted sooner is unchanged, got %d", p.Unchanged)
+	}
+}
+
+func TestBuildRetentionPlan_ColdStorage(t *testing.T) {
+	p := BuildRetentionPlan([]aws.RecoveryPoint{
+		point("efs-warm", "EFS", 40, aws.Lifecycle{}),
+		point("efs-cold", "EFS", 40, aws.Lifecycle{MoveToColdStorageAfterDays: 30}),
+		point("efs-new", "EFS", 1, aws.Lifecycle{MoveToColdStorageAfterDays: 60}),
+		point("rds", "RDS", 40, aws.Lifecycle{}),
+	}, aws.Lifecycle{MoveToColdStorageAfterDays: 7}, testNow)
+
+	if len(p.MovedToCold) != 2 || p.MovedToCold[0].ResourceID != "efs-warm" || p.MovedToCold[1].ResourceID != "efs-new" {
+		t.Fatalf("warm EFS points should move sooner, cold points and RDS should not: %+v", p.MovedToCold)
+	}
+	if p.Unchanged != 2 || len(p.Deleted) != 0 {
+		t.Errorf("unchanged = %d, deleted = %d", p.Unchanged, len(p.Deleted))
+	}
+
+	md := p.Markdown()
+	if !strings.Contains(md, "| EFS efs-warm | never | when applied |") {
+		t.Errorf("overdue transition should read 'when applied':\n%s", md)
+	}
+}
+
+func TestRetentionPlan_Markdown(t *testing.T) {
+	p := BuildRetentionPlan([]aws.RecoveryPoint{point("old", "RDS", 20, aws.Lifecycle{})}, aws.Lifecycle{DeleteAfterDays: 14}, testNow)
+	p.Stack, p.Vault, p.Region = "OpenemrEcsStack", "vault", "us-west-2"
+	md := p.Markdown()
+	for _, want := range []string{
+		"# Retention Dry Run: OpenemrEcsStack",
+		"**Open in backup-tui:** `backup-tui://open?region=us-west-2&stack=OpenemrEcsStack&vault=vault`",
+		"**Proposed lifecycle:** delete after 14 days, never move to cold storage",
+		"| Deleted when applied | 1 | 1.0 GB |",
+		"| 2026-03-11 12:00 | RDS old | COMPLETED | 1.0 GB | never |",
+		"No recovery points are deleted earlier than today.",
+		"## Sign-off",
+		"| Approved by | | | |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestRetentionPlan_JSON(t *testing.T) {
+	p := BuildRetentionPlan(nil, aws.Lifecycle{DeleteAfterDays: 14}, testNow)
+	data, err := p.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if _, ok := decoded["deleted"].([]any); !ok {
+		t.Errorf("empty lists should encode as [], got %s", data)
+	}
+}
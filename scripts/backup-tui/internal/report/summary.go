@@ -0,0 +1,233 @@
+// Package report builds operational reports from AWS Backup job history.
+// This file implements the scheduled summary sent by the cron subcommand:
+// backup selection coverage, recovery point objective (RPO) checks on the
+// age of each resource's newest backup, recovery time objective (RTO) checks
+// on the duration of restores, and the job report, with a count of
+// violations that fail the run. RPO and RTO targets per resource type come
+// from the config file; resource types without an RPO target use the cron
+// subcommand's -rpo.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+)
+
+// Freshness is the age of a protected resource's newest completed backup,
+// checked against the RPO.
+type Freshness struct {
+	ResourceType string    `json:"resourceType"`
+	ResourceARN  string    `json:"resourceArn"`
+	LatestBackup time.Time `json:"latestBackup,omitzero"` // Zero when the vault has no completed backup
+	Age          Seconds   `json:"ageSeconds"`
+	RPO          Seconds   `json:"rpoSeconds"` // The resource type's RPO target, or the default RPO
+	Violation    bool      `json:"violation"`  // No backup, or older than the RPO
+}
+
+// Compliance is a resource type's standing against its recovery objectives.
+type Compliance struct {
+	ResourceType   string  `json:"resourceType"`
+	RPO            Seconds `json:"rpoSeconds"`
+	StalestBackup  Seconds `json:"stalestBackupSeconds"`  // Age of the oldest newest backup among the type's resources
+	RPOViolation   bool    `json:"rpoViolation"`          // A resource of the type has no backup within the RPO
+	RTO            Seconds `json:"rtoSeconds"`            // Zero when the config sets no RTO target
+	SlowestRestore Seconds `json:"slowestRestoreSeconds"` // Zero when no restore of the type completed in the window
+	RTOViolation   bool    `json:"rtoViolation"`          // A restore took longer than the RTO
+}
+
+// Summary is the scheduled health summary of a stack's backups.
+type Summary struct {
+	Stack       string       `json:"stack"`
+	Vault       string       `json:"vault"`
+	Region      string       `json:"region"`
+	GeneratedAt time.Time    `json:"generatedAt"`
+	RPO         Seconds      `json:"rpoSeconds"` // Default RPO for resource types without a target
+	Uncovered   []string     `json:"uncovered"`  // ARNs of resources not in any backup selection
+	Freshness   []Freshness  `json:"freshness"`
+	Compliance  []Compliance `json:"compliance"`
+	Jobs        *JobsReport  `json:"jobs"`
+}
+
+// BuildSummary checks coverage and, for each protected resource, the age
+// at now of its newest completed recovery point in points against its
+// type's RPO target in cfg, or rpo when cfg sets none. Each resource type
+// is then checked against its RTO target using the restores in jobs.
+// Failed jobs are reported but are not violations: a failed backup only
+// matters once it leaves the resource outside its RPO.
+func BuildSummary(coverage []aws.CoverageResult, points []aws.RecoveryPoint, jobs *JobsReport, rpo time.Duration, cfg *config.Config, now time.Time) *Summary {
+	s := &Summary{GeneratedAt: now, RPO: Seconds(rpo), Uncovered: []string{}, Freshness: []Freshness{}, Compliance: []Compliance{}, Jobs: jobs}
+	for _, r := range coverage {
+		if !r.Covered {
+			s.Uncovered = append(s.Uncovered, r.Resource.ARN)
+		}
+
+		f := Freshness{ResourceType: r.Resource.Type, ResourceARN: r.Resource.ARN, RPO: Seconds(rpo)}
+		if target := cfg.Target(r.Resource.Type); target.RPO > 0 {
+			f.RPO = Seconds(target.RPO)
+		}
+		for _, rp := range points {
+			if rp.ResourceARN == r.Resource.ARN && rp.Status == "COMPLETED" && rp.CreationDate.After(f.LatestBackup) {
+				f.LatestBackup = rp.CreationDate
+			}
+		}
+		if !f.LatestBackup.IsZero() {
+			f.Age = Seconds(now.Sub(f.LatestBackup))
+		}
+		f.Violation = f.LatestBackup.IsZero() || f.Age > f.RPO
+		s.Freshness = append(s.Freshness, f)
+	}
+	s.Compliance = buildCompliance(s.Freshness, jobs, cfg)
+	return s
+}
+
+// buildCompliance rolls the freshness checks up per resource type, in the
+// order the types first appear, and checks the slowest successful restore
+// of each type against its RTO target.
+func buildCompliance(freshness []Freshness, jobs *JobsReport, cfg *config.Config) []Compliance {
+	compliance := []Compliance{}
+	index := make(map[string]int)
+	for _, f := range freshness {
+		i, ok := index[f.ResourceType]
+		if !ok {
+			i = len(compliance)
+			index[f.ResourceType] = i
+			compliance = append(compliance, Compliance{ResourceType: f.ResourceType, RPO: f.RPO, RTO: Seconds(cfg.Target(f.ResourceType).RTO)})
+		}
+		c := &compliance[i]
+		c.RPOViolation = c.RPOViolation || f.Violation
+		c.StalestBackup = max(c.StalestBackup, f.Age)
+	}
+	if jobs == nil {
+		return compliance
+	}
+	for _, j := range jobs.restores {
+		i, ok := index[j.ResourceType]
+		if !ok {
+			continue
+		}
+		c := &compliance[i]
+		c.SlowestRestore = max(c.SlowestRestore, Seconds(j.Duration()))
+		c.RTOViolation = c.RTO > 0 && c.SlowestRestore > c.RTO
+	}
+	return compliance
+}
+
+// Violations returns the number of uncovered resources plus the number of
+// resources outside the RPO and resource types outside the RTO.
+func (s *Summary) Violations() int {
+	n := len(s.Uncovered)
+	for _, f := range s.Freshness {
+		if f.Violation {
+			n++
+		}
+	}
+	for _, c := range s.Compliance {
+		if c.RTOViolation {
+			n++
+		}
+	}
+	return n
+}
+
+// Subject is a one-line subject for the summary, e.g. for an email.
+func (s *Summary) Subject() string {
+	switch n := s.Violations(); n {
+	case 0:
+		return fmt.Sprintf("backup-tui %s: OK", s.Stack)
+	case 1:
+		return fmt.Sprintf("backup-tui %s: 1 violation", s.Stack)
+	default:
+		return fmt.Sprintf("backup-tui %s: %d violations", s.Stack, n)
+	}
+}
+
+// JSON renders the summary, with the job report, as indented JSON.
+func (s *Summary) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode summary: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Markdown renders the summary as a markdown document, followed by the job
+// report.
+func (s *Summary) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Backup Summary: %s\n\n", s.Stack)
+	fmt.Fprintf(&b, "- **Vault:** %s (%s)\n", s.Vault, s.Region)
+	fmt.Fprintf(&b, "- **Generated:** %s\n", s.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"))
+	fmt.Fprintf(&b, "- **Violations:** %d\n\n", s.Violations())
+
+	b.WriteString("## Coverage\n\n")
+	if len(s.Uncovered) == 0 {
+		b.WriteString("All protected resources are in a backup selection.\n")
+	} else {
+		b.WriteString("Not in any backup selection (run `backup-tui doctor -fix`):\n\n")
+		for _, arn := range s.Uncovered {
+			fmt.Fprintf(&b, "- %s\n", arn)
+		}
+	}
+
+	b.WriteString("\n## Recovery Point Objective\n\n")
+	if len(s.Freshness) == 0 {
+		b.WriteString("No RDS clusters or EFS file systems found in the stack.\n")
+	} else {
+		b.WriteString("| | Resource | Latest backup (UTC) | Age | RPO |\n")
+		b.WriteString("|-|----------|---------------------|----:|----:|\n")
+		for _, f := range s.Freshness {
+			mark, latest, age := "✓", "none", "n/a"
+			if f.Violation {
+				mark = "✗"
+			}
+			if !f.LatestBackup.IsZero() {
+				latest = f.LatestBackup.UTC().Format("2006-01-02 15:04")
+				age = formatDuration(time.Duration(f.Age))
+			}
+			fmt.Fprintf(&b, "| %s | %s %s | %s | %s | %s |\n", mark, f.ResourceType, resourceName(f.ResourceARN), latest, age, formatDuration(time.Duration(f.RPO)))
+		}
+	}
+
+	if len(s.Compliance) > 0 {
+		b.WriteString("\n## Recovery Objectives Compliance\n\n")
+		b.WriteString("| Resource type | RPO target | Stalest backup | RPO | RTO target | Slowest restore | RTO |\n")
+		b.WriteString("|---------------|-----------:|---------------:|:---:|-----------:|----------------:|:---:|\n")
+		for _, c := range s.Compliance {
+			stalest, rpoMark := "none", "✓"
+			if c.StalestBackup > 0 {
+				stalest = formatDuration(time.Duration(c.StalestBackup))
+			}
+			if c.RPOViolation {
+				rpoMark = "✗"
+			}
+			rto, slowest, rtoMark := "not set", "none", "n/a"
+			if c.SlowestRestore > 0 {
+				slowest = formatDuration(time.Duration(c.SlowestRestore))
+			}
+			if c.RTO > 0 {
+				rto = formatDuration(time.Duration(c.RTO))
+				switch {
+				case c.RTOViolation:
+					rtoMark = "✗"
+				case c.SlowestRestore > 0:
+					rtoMark = "✓"
+				default:
+					rtoMark = "untested"
+				}
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				c.ResourceType, formatDuration(time.Duration(c.RPO)), stalest, rpoMark, rto, slowest, rtoMark)
+		}
+	}
+
+	if s.Jobs != nil {
+		// Demote the job report's headings under the summary's
+		b.WriteString("\n#" + strings.ReplaceAll(s.Jobs.Markdown(), "\n#", "\n##"))
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+)
+
+func TestBuildSummary_CoverageAndRPO(t *testing.T) {
+	db := aws.ProtectedResource{Type: "RDS", ARN: "arn:aws:rds:us-west-2:1:cluster:db"}
+	fs := aws.ProtectedResource{Type: "EFS", ARN: "arn:aws:elasticfilesystem:us-west-2:1:file-system/fs-1"}
+	coverage := []aws.CoverageResult{{Resource: db, Covered: true}, {Resource: fs}}
+	points := []aws.RecoveryPoint{
+		{ResourceARN: db.ARN, Status: "COMPLETED", CreationDate: testUntil.Add(-30 * time.Hour)},
+		{ResourceARN: db.ARN, Status: "COMPLETED", CreationDate: testUntil.Add(-10 * time.Hour)},
+		{ResourceARN: fs.ARN, Status: "PARTIAL", CreationDate: testUntil.Add(-time.Hour)},
+		{ResourceARN: fs.ARN, Status: "COMPLETED", CreationDate: testUntil.Add(-48 * time.Hour)},
+	}
+
+	s := BuildSummary(coverage, points, nil, 26*time.Hour, nil, testUntil)
+	s.Stack = "Stack"
+	if f := s.Freshness[0]; f.Violation || time.Duration(f.Age) != 10*time.Hour {
+		t.Errorf("database backed up 10h ago should be within the RPO: %+v", f)
+	}
+	if f := s.Freshness[1]; !f.Violation || time.Duration(f.Age) != 48*time.Hour {
+		t.Errorf("partial backups should not count toward the RPO: %+v", f)
+	}
+	if s.Violations() != 2 || s.Subject() != "backup-tui Stack: 2 violations" {
+		t.Errorf("uncovered and stale file system should both be violations, got %d (%q)", s.Violations(), s.Subject())
+	}
+
+	s = BuildSummary(coverage[:1], points, nil, 26*time.Hour, nil, testUntil)
+	s.Stack = "Stack"
+	if s.Violations() != 0 || s.Subject() != "backup-tui Stack: OK" {
+		t.Errorf("expected no violations, got %d (%q)", s.Violations(), s.Subject())
+	}
+}
+
+func TestSummaryMarkdown(t *testing.T) {
+	coverage := []aws.CoverageResult{{Resource: aws.ProtectedResource{Type: "EFS", ARN: "arn:aws:elasticfilesystem:us-west-2:1:file-system/fs-1"}}}
+	jobs := BuildJobs([]aws.JobRecord{job(aws.JobKindBackup, "FAILED", 1, 2)}, testSince, testUntil)
+	md := BuildSummary(coverage, nil, jobs, 26*time.Hour, nil, testUntil).Markdown()
+
+	for _, want := range []string{"file-system/fs-1", "| ✗ | EFS fs-1 | none | n/a |", "## Backup Job Report", "### Failures"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("summary should contain %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestBuildSummary_Targets(t *testing.T) {
+	db := aws.ProtectedResource{Type: "RDS", ARN: "arn:aws:rds:us-west-2:1:cluster:db"}
+	fs := aws.ProtectedResource{Type: "EFS", ARN: "arn:aws:elasticfilesystem:us-west-2:1:file-system/fs-1"}
+	coverage := []aws.CoverageResult{{Resource: db, Covered: true}, {Resource: fs, Covered: true}}
+	points := []aws.RecoveryPoint{
+		{ResourceARN: db.ARN, Status: "COMPLETED", CreationDate: testUntil.Add(-10 * time.Hour)},
+		{ResourceARN: fs.ARN, Status: "COMPLETED", CreationDate: testUntil.Add(-30 * time.Hour)},
+	}
+	jobs := BuildJobs([]aws.JobRecord{
+		job(aws.JobKindRestore, "COMPLETED", 1, 50),
+		job(aws.JobKindRestore, "COMPLETED", 2, 90),
+		job(aws.JobKindRestore, "FAILED", 3, 300),
+	}, testSince, testUntil)
+	cfg := &config.Config{Targets: map[string]config.Target{
+		"rds": {RPO: config.Duration(6 * time.Hour), RTO: config.Duration(time.Hour)},
+		"EFS": {RPO: config.Duration(2 * 24 * time.Hour)},
+	}}
+
+	s := BuildSummary(coverage, points, jobs, 26*time.Hour, cfg, testUntil)
+	if f := s.Freshness[0]; !f.Violation || time.Duration(f.RPO) != 6*time.Hour {
+		t.Errorf("database backed up 10h ago should violate its 6h RPO target: %+v", f)
+	}
+	if f := s.Freshness[1]; f.Violation || time.Duration(f.RPO) != 48*time.Hour {
+		t.Errorf("file system backed up 30h ago should be within its 2d RPO target: %+v", f)
+	}
+	if len(s.Compliance) != 2 {
+		t.Fatalf("expected compliance for RDS and EFS, got %+v", s.Compliance)
+	}
+	if c := s.Compliance[0]; c.ResourceType != "RDS" || !c.RPOViolation || time.Duration(c.SlowestRestore) != 90*time.Minute || !c.RTOViolation {
+		t.Errorf("RDS should be outside its RPO and, with a 90m restore, its 1h RTO: %+v", c)
+	}
+	if c := s.Compliance[1]; c.RPOViolation || c.RTO != 0 || c.RTOViolation {
+		t.Errorf("EFS has no RTO target and is within its RPO: %+v", c)
+	}
+	if s.Violations() != 2 {
+		t.Errorf("stale database and slow restore should be 2 violations, got %d", s.Violations())
+	}
+
+	md := s.Markdown()
+	for _, want := range []string{
+		"## Recovery Objectives Compliance",
+		"| RDS | 6h00m | 10h00m | ✗ | 1h00m | 1h30m | ✗ |",
+		"| EFS | 48h00m | 30h00m | ✓ | not set | none | n/a |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("summary should contain %q:\n%s", want, md)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Package report builds operational reports from AWS Backup job history.
+// This file implements backup verification: judging what was measured in a
+// test restore against the config's checks and the resource's last
+// verified backup, and the verification report listing the results.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+// VerifyTarget is a completed test restore of a recovery point.
+type VerifyTarget struct {
+	RecoveryPoint aws.RecoveryPoint
+	RestoreJobID  string
+	RestoredARN   string
+	RestoredAt    time.Time // When the restore completed
+}
+
+// InPlace reports whether the restore wrote into the resource the recovery
+// point was taken of, as an EFS restore to the original file system does.
+func (t VerifyTarget) InPlace() bool {
+	return t.RestoredARN == t.RecoveryPoint.ResourceARN
+}
+
+// FileSample is the files counted in a restored file system mounted on the
+// machine verifying it.
+type FileSample struct {
+	Root  string
+	Files int64
+	Bytes int64
+}
+
+// BuildVerification judges m, measured in the test restore t, and files,
+// when the restored file system was mounted, against checks and baseline,
+// the resource's last verified backup (nil when there is none).
+func BuildVerification(t VerifyTarget, m aws.RestoreMeasurement, files *FileSample, checks config.Verification, baseline *store.Verification, now time.Time) store.Verification {
+	rp := t.RecoveryPoint
+	v := store.Verification{
+		RecoveryPointARN: rp.RecoveryPointARN,
+		ResourceType:     rp.ResourceType,
+		ResourceID:       rp.ResourceID,
+		BackupCreated:    rp.CreationDate,
+		RestoreJobID:     t.RestoreJobID,
+		RestoredARN:      t.RestoredARN,
+		VerifiedAt:       now,
+	}
+	switch rp.ResourceType {
+	case "RDS":
+		v.Checks = rowChecks(m, checks, baseline)
+	case "EFS":
+		v.Checks = append(v.Checks, sizeCheck(t, m, checks))
+		if files != nil {
+			v.Checks = append(v.Checks, filesCheck(*files, checks, baseline))
+		}
+	default:
+		v.Checks = []store.VerifyCheck{{Name: "restore", Result: store.CheckSkipped, Detail: "no checks for " + rp.ResourceType + " restores"}}
+	}
+	v.Judge()
+	return v
+}
+
+// rowChecks checks each table's row count against its minimum and the
+// baseline's count.
+func rowChecks(m aws.RestoreMeasurement, checks config.Verification, baseline *store.Verification) []store.VerifyCheck {
+	switch {
+	case m.RowsErr != nil:
+		return []store.VerifyCheck{{Name: "rows", Result: store.VerifyFailed, Detail: m.RowsErr.Error()}}
+	case m.Rows == nil:
+		return []store.VerifyCheck{{Name: "rows", Result: store.CheckSkipped, Detail: "no verify.databaseSecret in the config to sign in to the database with"}}
+	}
+	minRows := make(map[string]int64, len(checks.Tables))
+	for _, tc := range checks.Tables {
+		minRows[tc.Name] = tc.MinRows
+	}
+	out := make([]store.VerifyCheck, 0, len(m.Rows))
+	for _, tc := range m.Rows {
+		c := store.VerifyCheck{Name: "rows " + tc.Table, Value: tc.Rows}
+		switch {
+		case tc.Err != nil:
+			c.Result, c.Detail = store.VerifyFailed, tc.Err.Error()
+		case tc.Rows < minRows[tc.Table]:
+			c.Result, c.Detail = store.VerifyFailed, fmt.Sprintf("%d rows, fewer than the minimum of %d", tc.Rows, minRows[tc.Table])
+		default:
+			c.Result, c.Detail = compareBaseline(c.Name, tc.Rows, "rows", checks, baseline)
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// sizeCheck checks that a restored file system holds most of its backup's
+// size. An in-place restore shares its file system with the live data, and
+// a size metered before the restore completed is stale, so neither is
+// checked.
+func sizeCheck(t VerifyTarget, m aws.RestoreMeasurement, checks config.Verification) store.VerifyCheck {
+	c := store.VerifyCheck{Name: "size", Result: store.CheckSkipped}
+	backupSize := t.RecoveryPoint.BackupSizeInBytes
+	switch {
+	case t.InPlace():
+		c.Detail = "restored in place: the file system's size includes the live data"
+	case m.FileSystemErr != nil:
+		c.Result, c.Detail = store.VerifyFailed, m.FileSystemErr.Error()
+	case m.FileSystem == nil:
+		c.Detail = "the restored file system was not described"
+	case !m.FileSystem.SizeMeasuredAt.IsZero() && m.FileSystem.SizeMeasuredAt.Before(t.RestoredAt):
+		c.Value = m.FileSystem.SizeBytes
+		c.Detail = fmt.Sprintf("last metered %s, before the restore completed; verify again within the hour",
+			m.FileSystem.SizeMeasuredAt.UTC().Format("15:04 MST"))
+	case backupSize == 0:
+		c.Value = m.FileSystem.SizeBytes
+		c.Detail = "the backup's size is unknown"
+	default:
+		c.Value = m.FileSystem.SizeBytes
+		percent := 100 * float64(c.Value) / float64(backupSize)
+		c.Detail = fmt.Sprintf("%s, %.0f%% of the backup's %s", formatBytes(c.Value), percent, formatBytes(backupSize))
+		c.Result = store.VerifyPassed
+		if percent < checks.EFSMinSizePercent {
+			c.Result = store.VerifyFailed
+			c.Detail += fmt.Sprintf(", below %g%%", checks.EFSMinSizePercent)
+		}
+	}
+	return c
+}
+
+// filesCheck checks the files counted in the mounted file system against
+// the baseline's count.
+func filesCheck(f FileSample, checks config.Verification, baseline *store.Verification) store.VerifyCheck {
+	c := store.VerifyCheck{Name: "files", Value: f.Files}
+	if f.Files == 0 {
+		c.Result, c.Detail = store.VerifyFailed, "no files under "+f.Root
+		return c
+	}
+	c.Result, c.Detail = compareBaseline(c.Name, f.Files, "files", checks, baseline)
+	c.Detail += fmt.Sprintf(" (%s) under %s", formatBytes(f.Bytes), f.Root)
+	return c
+}
+
+// compareBaseline judges count, measured by the check of the given name,
+// against the baseline's: it may shrink by at most checks.MaxShrinkPercent.
+func compareBaseline(name string, count int64, unit string, checks config.Verification, baseline *store.Verification) (string, string) {
+	detail := fmt.Sprintf("%d %s", count, unit)
+	if baseline == nil {
+		return store.VerifyPassed, detail
+	}
+	prev, ok := baseline.Check(name)
+	if !ok || prev.Result != store.VerifyPassed {
+		return store.VerifyPassed, detail
+	}
+	since := fmt.Sprintf("%d in the backup of %s", prev.Value, baseline.BackupCreated.UTC().Format("2006-01-02"))
+	if count >= prev.Value {
+		return store.VerifyPassed, detail + ", " + since
+	}
+	shrunk := 100 * float64(prev.Value-count) / float64(prev.Value)
+	detail = fmt.Sprintf("%s, down %.1f%% from %s", detail, shrunk, since)
+	if shrunk > checks.MaxShrinkPercent {
+		return store.VerifyFailed, detail + fmt.Sprintf(", more than the %g%% allowed", checks.MaxShrinkPercent)
+	}
+	return store.VerifyPassed, detail
+}
+
+// VerificationReport lists verifications of test restores.
+type VerificationReport struct {
+	Stack         string               `json:"stack,omitempty"`
+	Region        string               `json:"region"`
+	GeneratedAt   time.Time            `json:"generatedAt"`
+	Verifications []store.Verification `json:"verifications"`
+}
+
+// Failed returns the number of verifications that failed.
+func (r *VerificationReport) Failed() int {
+	n := 0
+	for _, v := range r.Verifications {
+		if v.Result == store.VerifyFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// JSON renders the report as indented JSON.
+func (r *VerificationReport) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode verification report: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// verifyIcons mark results in the markdown report.
+var verifyIcons = map[string]string{
+	store.VerifyPassed:       "✓",
+	store.VerifyFailed:       "✗",
+	store.VerifyInconclusive: "?",
+	store.CheckSkipped:       "–",
+}
+
+// Markdown renders the report as a markdown document, newest verification
+// first.
+func (r *VerificationReport) Markdown() string {
+	var b strings.Builder
+	title := "Backup Verification"
+	if r.Stack != "" {
+		title += ": " + r.Stack
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "- **Region:** %s\n", r.Region)
+	fmt.Fprintf(&b, "- **Generated:** %s\n", r.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"))
+	fmt.Fprintf(&b, "- **Verifications:** %d, %d failed\n", len(r.Verifications), r.Failed())
+	if len(r.Verifications) == 0 {
+		b.WriteString("\nNo test restores have been verified.\n")
+		return b.String()
+	}
+	for i := len(r.Verifications) - 1; i >= 0; i-- {
+		v := r.Verifications[i]
+		fmt.Fprintf(&b, "\n## %s %s %s: %s\n\n", verifyIcons[v.Result], v.ResourceType, v.ResourceID, v.Result)
+		fmt.Fprintf(&b, "- **Backup:** %s, %s\n", formatDate(&v.BackupCreated), v.RecoveryPointARN)
+		fmt.Fprintf(&b, "- **Restore:** job %s, %s\n", v.RestoreJobID, v.RestoredARN)
+		fmt.Fprintf(&b, "- **Verified:** %s\n\n", formatDate(&v.VerifiedAt))
+		b.WriteString("| | Check | Result |\n")
+		b.WriteString("|-|-------|--------|\n")
+		for _, c := range v.Checks {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", verifyIcons[c.Result], c.Name, c.Detail)
+		}
+	}
+	return b.String()
+}
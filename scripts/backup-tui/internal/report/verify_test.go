@@ -0,0 +1,108 @@
+package report
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+func TestBuildVerification_Rows(t *testing.T) {
+	checks := (&config.Config{Verify: &config.Verification{
+		Tables:           []config.TableCheck{{Name: "patient_data"}, {Name: "users", MinRows: 1}, {Name: "documents"}},
+		MaxShrinkPercent: 5,
+	}}).VerifyChecks()
+	target := VerifyTarget{
+		RecoveryPoint: aws.RecoveryPoint{RecoveryPointARN: "arn:rp-2", ResourceType: "RDS", ResourceID: "openemr", CreationDate: testNow.Add(-24 * time.Hour)},
+		RestoreJobID:  "job-2",
+		RestoredARN:   "arn:aws:rds:us-west-2:1:cluster:openemr-restore-test",
+	}
+	baseline := &store.Verification{BackupCreated: testNow.Add(-48 * time.Hour), Result: store.VerifyPassed, Checks: []store.VerifyCheck{
+		{Name: "rows patient_data", Result: store.VerifyPassed, Value: 1000},
+		{Name: "rows documents", Result: store.VerifyPassed, Value: 1000},
+	}}
+	m := aws.RestoreMeasurement{Rows: []aws.TableCount{
+		{Table: "patient_data", Rows: 960},
+		{Table: "users", Rows: 0},
+		{Table: "documents", Err: errors.New("table missing")},
+	}}
+
+	v := BuildVerification(target, m, nil, checks, baseline, testNow)
+	if v.Result != store.VerifyFailed || v.RecoveryPointARN != "arn:rp-2" || v.RestoreJobID != "job-2" || !v.VerifiedAt.Equal(testNow) {
+		t.Fatalf("unexpected verification %+v", v)
+	}
+	for name, want := range map[string]string{
+		"rows patient_data": "960 rows, down 4.0% from 1000 in the backup of 2026-03-29",
+		"rows users":        "0 rows, fewer than the minimum of 1",
+		"rows documents":    "table missing",
+	} {
+		if c, ok := v.Check(name); !ok || c.Detail != want {
+			t.Errorf("check %s = %+v, want detail %q", name, c, want)
+		}
+	}
+	if c, _ := v.Check("rows patient_data"); c.Result != store.VerifyPassed {
+		t.Errorf("a 4%% drop is within 5%%, got %+v", c)
+	}
+	m.Rows[0].Rows = 900
+	if c, _ := BuildVerification(target, m, nil, checks, baseline, testNow).Check("rows patient_data"); c.Result != store.VerifyFailed {
+		t.Errorf("a 10%% drop should fail, got %+v", c)
+	}
+
+	v = BuildVerification(target, aws.RestoreMeasurement{}, nil, checks, nil, testNow)
+	if v.Result != store.VerifyInconclusive {
+		t.Errorf("without database credentials nothing is checked, got %+v", v)
+	}
+}
+
+func TestBuildVerification_EFS(t *testing.T) {
+	checks := (*config.Config)(nil).VerifyChecks()
+	target := VerifyTarget{
+		RecoveryPoint: aws.RecoveryPoint{RecoveryPointARN: "arn:rp-fs", ResourceType: "EFS", ResourceID: "fs-live",
+			ResourceARN: "arn:aws:elasticfilesystem:us-west-2:1:file-system/fs-live", BackupSizeInBytes: 1000, CreationDate: testNow.Add(-time.Hour)},
+		RestoredARN: "arn:aws:elasticfilesystem:us-west-2:1:file-system/fs-new",
+		RestoredAt:  testNow.Add(-10 * time.Minute),
+	}
+	fs := &aws.FileSystemInfo{SizeBytes: 950, SizeMeasuredAt: testNow}
+	v := BuildVerification(target, aws.RestoreMeasurement{FileSystem: fs}, &FileSample{Root: "/mnt/restore", Files: 42, Bytes: 900}, checks, nil, testNow)
+	if v.Result != store.VerifyPassed || len(v.Checks) != 2 {
+		t.Fatalf("a file system with 95%% of its backup should pass, got %+v", v)
+	}
+	if c, _ := v.Check("files"); c.Value != 42 || c.Detail != "42 files (900 B) under /mnt/restore" {
+		t.Errorf("unexpected files check %+v", c)
+	}
+
+	fs.SizeBytes = 500
+	if v := BuildVerification(target, aws.RestoreMeasurement{FileSystem: fs}, nil, checks, nil, testNow); v.Result != store.VerifyFailed {
+		t.Errorf("half the backup's size should fail, got %+v", v)
+	}
+	fs.SizeMeasuredAt = testNow.Add(-time.Hour)
+	if v := BuildVerification(target, aws.RestoreMeasurement{FileSystem: fs}, nil, checks, nil, testNow); v.Result != store.VerifyInconclusive {
+		t.Errorf("a size metered before the restore completed should not be judged, got %+v", v)
+	}
+	target.RestoredARN = target.RecoveryPoint.ResourceARN
+	if c, _ := BuildVerification(target, aws.RestoreMeasurement{}, nil, checks, nil, testNow).Check("size"); c.Result != store.CheckSkipped {
+		t.Errorf("an in-place restore's size should not be checked, got %+v", c)
+	}
+}
+
+func TestVerificationReport_Markdown(t *testing.T) {
+	r := &VerificationReport{Stack: "OpenemrEcsStack", Region: "us-west-2", GeneratedAt: testNow, Verifications: []store.Verification{
+		{ResourceType: "RDS", ResourceID: "openemr", Result: store.VerifyPassed, RestoreJobID: "job-1",
+			Checks: []store.VerifyCheck{{Name: "rows users", Result: store.VerifyPassed, Detail: "37 rows"}}},
+		{ResourceType: "EFS", ResourceID: "fs-live", Result: store.VerifyFailed, RestoreJobID: "job-2",
+			Checks: []store.VerifyCheck{{Name: "size", Result: store.VerifyFailed, Detail: "500 B, 50% of the backup's 1000 B, below 90%"}}},
+	}}
+	md := r.Markdown()
+	for _, want := range []string{"# Backup Verification: OpenemrEcsStack", "**Verifications:** 2, 1 failed", "## ✗ EFS fs-live: FAILED", "| ✓ | rows users | 37 rows |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Index(md, "EFS fs-live") > strings.Index(md, "RDS openemr") {
+		t.Error("the newest verification should come first")
+	}
+}
@@ -0,0 +1,148 @@
+// Package store persists backup TUI state on the local disk.
+// This file implements encryption of state files at rest: they hold
+// recovery point ARNs and account IDs, so they are sealed with AES-256-GCM
+// under a key generated on first use and kept in the OS keyring (macOS
+// Keychain, Windows Credential Manager, or the Secret Service on Linux),
+// never on disk. Plaintext files written by earlier versions are still
+// read and are encrypted the next time they are saved.
+package store
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+// The state key's entry in the OS keyring.
+const (
+	keyringService = "backup-tui"
+	keyringUser    = "state-encryption-key"
+)
+
+// stateCipher is the encryption of state files.
+const stateCipher = "AES-256-GCM"
+
+// envelope is an encrypted state file.
+type envelope struct {
+	Cipher string `json:"cipher"`
+	Nonce  []byte `json:"nonce"`
+	Data   []byte `json:"data"`
+}
+
+// stateKey caches the key read from the keyring for the process.
+var stateKey struct {
+	sync.Mutex
+	key []byte
+}
+
+// loadStateKey returns the state encryption key, generating it and saving
+// it to the OS keyring on first use.
+func loadStateKey() ([]byte, error) {
+	stateKey.Lock()
+	defer stateKey.Unlock()
+	if stateKey.key != nil {
+		return stateKey.key, nil
+	}
+
+	encoded, err := keyring.Get(keyringService, keyringUser)
+	if errors.Is(err, keyring.ErrNotFound) {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate state encryption key: %w", err)
+		}
+		encoded = base64.StdEncoding.EncodeToString(key)
+		err = keyring.Set(keyringService, keyringUser, encoded)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to access the state encryption key in the OS keyring: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("the state encryption key in the OS keyring (%s/%s) is invalid", keyringService, keyringUser)
+	}
+	stateKey.key = key
+	return key, nil
+}
+
+// gcm returns the AEAD for the state key.
+func gcm() (cipher.AEAD, error) {
+	key, err := loadStateKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts a state file's contents.
+func seal(plaintext []byte) ([]byte, error) {
+	aead, err := gcm()
+	if err != nil {
+		return nil, err
+	}
+	env := envelope{Cipher: stateCipher, Nonce: make([]byte, aead.NonceSize())}
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	env.Data = aead.Seal(nil, env.Nonce, plaintext, nil)
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// unseal decrypts a state file's contents. Contents that are not an
+// encrypted envelope are plaintext from an earlier version and are returned
+// as they are.
+func unseal(path string, data []byte) ([]byte, error) {
+	var env envelope
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) || json.Unmarshal(data, &env) != nil || env.Cipher == "" {
+		return data, nil
+	}
+	if env.Cipher != stateCipher {
+		return nil, fmt.Errorf("%s is encrypted with unsupported cipher %q", path, env.Cipher)
+	}
+	aead, err := gcm()
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("%s has an invalid nonce", path)
+	}
+	plaintext, err := aead.Open(nil, env.Nonce, env.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: it was encrypted with a different key or has been modified", path)
+	}
+	return plaintext, nil
+}
+
+// writeState encrypts data and replaces the state file at path with it,
+// creating its directory if needed. The file is written to a temporary name
+// and renamed so a crash never leaves a truncated file.
+func writeState(path string, data []byte) error {
+	sealed, err := seal(data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(sealed, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+// TestMain keeps the state key in memory rather than the OS keyring.
+func TestMain(m *testing.M) {
+	keyring.MockInit()
+	os.Exit(m.Run())
+}
+
+// resetStateKey forgets the cached state key, as a new process would.
+func resetStateKey(t *testing.T) {
+	t.Helper()
+	stateKey.Lock()
+	stateKey.key = nil
+	stateKey.Unlock()
+	t.Cleanup(func() {
+		stateKey.Lock()
+		stateKey.key = nil
+		stateKey.Unlock()
+		keyring.MockInit()
+	})
+}
+
+func TestSaveJobs_EncryptsAtRest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFile)
+	arn := "arn:aws:backup:us-west-2:123456789012:recovery-point:rp-1"
+	if _, err := SaveJobs(path, TrackedJob{JobID: "job-1", RecoveryPointARN: arn}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "123456789012") || strings.Contains(string(data), "job-1") {
+		t.Errorf("history file should not hold ARNs or job IDs in plaintext:\n%s", data)
+	}
+
+	resetStateKey(t) // A later run reads the key back from the keyring
+	jobs, err := LoadHistory(path)
+	if err != nil || len(jobs) != 1 || jobs[0].RecoveryPointARN != arn {
+		t.Errorf("LoadHistory = %+v, %v", jobs, err)
+	}
+}
+
+func TestLoadHistory_MigratesPlaintext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFile)
+	plaintext := `[{"jobId": "job-1", "kind": "restore", "region": "us-west-2", "startedAt": "2026-03-01T10:00:00Z"}]`
+	if err := os.WriteFile(path, []byte(plaintext), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if jobs, err := LoadHistory(path); err != nil || len(jobs) != 1 || jobs[0].JobID != "job-1" {
+		t.Fatalf("history written before encryption should still load, got %+v, %v", jobs, err)
+	}
+
+	if _, err := SaveJobs(path, TrackedJob{JobID: "job-2", StartedAt: testStart}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "job-1") {
+		t.Errorf("saving should encrypt the migrated history:\n%s", data)
+	}
+	if jobs, err := LoadHistory(path); err != nil || len(jobs) != 2 {
+		t.Errorf("migrated history should keep its jobs, got %+v, %v", jobs, err)
+	}
+}
+
+func TestLoadHistory_WrongKeyOrTampered(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFile)
+	if _, err := SaveJobs(path, TrackedJob{JobID: "job-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resetStateKey(t)
+	keyring.MockInit() // A different keyring, e.g. another user
+	if _, err := LoadHistory(path); err == nil || !strings.Contains(err.Error(), "different key") {
+		t.Errorf("history encrypted with another key should not load, got %v", err)
+	}
+	if _, err := SaveJobs(path, TrackedJob{JobID: "job-2"}); err == nil {
+		t.Error("saving should not overwrite history it cannot read")
+	}
+}
+
+func TestSaveJobs_KeyringUnavailable(t *testing.T) {
+	resetStateKey(t)
+	keyring.MockInitWithError(errors.New("no secret service"))
+
+	path := filepath.Join(t.TempDir(), historyFile)
+	if _, err := SaveJobs(path, TrackedJob{JobID: "job-1"}); err == nil || !strings.Contains(err.Error(), "OS keyring") {
+		t.Errorf("history should not be saved unencrypted without the keyring, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("no history file should be written, got %v", err)
+	}
+}
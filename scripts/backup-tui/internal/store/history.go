@@ -0,0 +1,111 @@
+// Package store persists backup TUI state on the local disk.
+// This file implements the job history file: job IDs started from the TUI,
+// saved when they start and updated when they finish, so that jobs still
+// running when the TUI exits are tracked again on the next launch or can be
+// followed with "backup-tui watch". The file is encrypted (encrypt.go).
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// historyFile is the name of the job history file in the config directory.
+const historyFile = "history.json"
+
+// TrackedJob is a job started from the TUI.
+type TrackedJob struct {
+	JobID            string    `json:"jobId"`
+	Kind             string    `json:"kind"` // aws.JobKindRestore, JobKindBackup, JobKindCopy, JobKindExport, or JobKindClone
+	Region           string    `json:"region"`
+	Vault            string    `json:"vault,omitempty"`
+	ResourceType     string    `json:"resourceType,omitempty"`
+	ResourceID       string    `json:"resourceId,omitempty"`
+	RecoveryPointARN string    `json:"recoveryPointArn,omitempty"`
+	StartedAt        time.Time `json:"startedAt"`
+	State            string    `json:"state,omitempty"`  // Terminal AWS state once finished; empty while in flight
+	PlanID           string    `json:"planId,omitempty"` // Restore plan the job was started by "backup-tui apply" from
+}
+
+// Finished reports whether the job's last known state is terminal.
+func (j TrackedJob) Finished() bool {
+	switch j.State {
+	case "COMPLETED", "FAILED", "ABORTED", "EXPIRED", "PARTIAL":
+		return true
+	}
+	return false
+}
+
+// DefaultHistoryPath returns the job history file in the user's config
+// directory, e.g. ~/.config/backup-tui/history.json on Linux.
+func DefaultHistoryPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "backup-tui", historyFile), nil
+}
+
+// LoadHistory reads the job history file, oldest job first. A missing file
+// is an empty history.
+func LoadHistory(path string) ([]TrackedJob, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job history: %w", err)
+	}
+	if data, err = unseal(path, data); err != nil {
+		return nil, fmt.Errorf("failed to read job history: %w", err)
+	}
+	var jobs []TrackedJob
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse job history %s: %w", path, err)
+	}
+	return jobs, nil
+}
+
+// SaveJobs adds jobs to the history file, replacing entries with the same
+// job ID, and returns the number of jobs saved.
+func SaveJobs(path string, jobs ...TrackedJob) (int, error) {
+	history, err := LoadHistory(path)
+	if err != nil {
+		return 0, err
+	}
+	index := make(map[string]int, len(history))
+	for i, j := range history {
+		index[j.JobID] = i
+	}
+	for _, j := range jobs {
+		if i, ok := index[j.JobID]; ok {
+			history[i] = j
+			continue
+		}
+		index[j.JobID] = len(history)
+		history = append(history, j)
+	}
+	sort.SliceStable(history, func(i, k int) bool { return history[i].StartedAt.Before(history[k].StartedAt) })
+	if err := writeHistory(path, history); err != nil {
+		return 0, err
+	}
+	return len(jobs), nil
+}
+
+// writeHistory encrypts and replaces the history file.
+func writeHistory(path string, history []TrackedJob) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode job history: %w", err)
+	}
+	if err := writeState(path, data); err != nil {
+		return fmt.Errorf("failed to save job history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testStart = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func TestLoadHistory_Missing(t *testing.T) {
+	jobs, err := LoadHistory(filepath.Join(t.TempDir(), "none", historyFile))
+	if err != nil || jobs != nil {
+		t.Errorf("a missing history file should be empty, got %v, %v", jobs, err)
+	}
+}
+
+func TestSaveJobs_MergesByJobID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup-tui", historyFile)
+
+	if _, err := SaveJobs(path,
+		TrackedJob{JobID: "job-2", Kind: "restore", StartedAt: testStart.Add(time.Hour)},
+		TrackedJob{JobID: "job-1", Kind: "restore", StartedAt: testStart},
+	); err != nil {
+		t.Fatal(err)
+	}
+	n, err := SaveJobs(path, TrackedJob{JobID: "job-2", Kind: "restore", StartedAt: testStart.Add(time.Hour), State: "COMPLETED"})
+	if err != nil || n != 1 {
+		t.Fatalf("SaveJobs = %d, %v", n, err)
+	}
+
+	jobs, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].JobID != "job-1" || jobs[1].State != "COMPLETED" {
+		t.Errorf("history should hold both jobs oldest first with the update applied, got %+v", jobs)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("history file should be private, got %v, %v", info.Mode(), err)
+	}
+}
+
+func TestLoadHistory_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFile)
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadHistory(path); err == nil {
+		t.Error("a corrupt history file should be reported")
+	}
+	if _, err := SaveJobs(path, TrackedJob{JobID: "job-1"}); err == nil {
+		t.Error("saving should not overwrite a corrupt history file")
+	}
+}
+
+func TestTrackedJob_Finished(t *testing.T) {
+	for state, want := range map[string]bool{"": false, "RUNNING": false, "PENDING": false, "COMPLETED": true, "FAILED": true, "ABORTED": true} {
+		if got := (TrackedJob{State: state}).Finished(); got != want {
+			t.Errorf("Finished() for %q = %v, want %v", state, got, want)
+		}
+	}
+}
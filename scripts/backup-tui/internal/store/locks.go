@@ -0,0 +1,473 @@
+// Package store persists backup TUI state on the local disk.
+// This file implements advisory restore locks: while a session restores a
+// stack it holds a lock on the stack, refreshed as its jobs are polled, so
+// other sessions targeting the stack warn their operators that a restore
+// is already in progress. Locks are advisory: nothing is refused. A lock
+// not refreshed within LockTTL, e.g. left by a session that crashed, is
+// ignored. Another session's live lock is never overwritten: taking it
+// reports the conflict instead. The lock file is encrypted (encrypt.go),
+// and rewritten by one process at a time, under an exclusive lock file
+// beside it. A lock is shared with other machines as a vault tag in the
+// compact form of TagValue. StackLock checks, takes, and releases both,
+// for every way a restore is started: the TUI, "restore", "apply", and the
+// JSON API.
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// locksFile is the name of the restore lock file in the config directory.
+const locksFile = "locks.json"
+
+// LockTTL is how long a lock is honored after it was last refreshed.
+const LockTTL = 15 * time.Minute
+
+// lockFileStale is how old the lock file guarding the restore lock file may
+// be before it is taken as left by a process that exited while holding it.
+// Holding it takes a read and a write of a small file.
+const lockFileStale = 10 * time.Second
+
+// lockFileRetry is how often a lock file held by another process is tried
+// again.
+const lockFileRetry = 10 * time.Millisecond
+
+// maxTagValue is the longest value AWS allows for a tag.
+const maxTagValue = 256
+
+// RestoreLock is a session's claim that it is restoring a stack.
+type RestoreLock struct {
+	Holder  string    `json:"holder,omitempty"` // ARN of the operator's identity
+	Host    string    `json:"host"`
+	PID     int       `json:"pid"`
+	Since   time.Time `json:"since"`   // When the session's first restore started
+	Updated time.Time `json:"updated"` // When the lock was last refreshed
+}
+
+// Stale reports whether the lock has not been refreshed within LockTTL of
+// now, so its session has most likely exited.
+func (l RestoreLock) Stale(now time.Time) bool {
+	return now.Sub(l.Updated) > LockTTL
+}
+
+// SameSession reports whether l and o were taken by the same process. Hosts
+// are compared as TagValue writes them, so a lock read back from a tag
+// matches the one written.
+func (l RestoreLock) SameSession(o RestoreLock) bool {
+	return tagSafe(l.Host) == tagSafe(o.Host) && l.PID == o.PID
+}
+
+// SessionLock returns a lock identifying this process, held by the AWS
+// identity holder, without times.
+func SessionLock(holder string) RestoreLock {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return RestoreLock{Holder: holder, Host: host, PID: os.Getpid()}
+}
+
+// LockConflictError is returned when a lock was not written because another
+// session holds a live lock in its place, which is kept.
+type LockConflictError struct {
+	Lock RestoreLock // The other session's lock
+}
+
+// Error describes the other session's lock.
+func (e *LockConflictError) Error() string {
+	return "the stack is locked by another session: " + e.Lock.String()
+}
+
+// String describes who holds l, e.g. "alice on ops-1 (pid 4242), since
+// 09:00".
+func (l RestoreLock) String() string {
+	who := "unknown identity"
+	if l.Holder != "" {
+		who = l.Holder[strings.LastIndexAny(l.Holder, "/:")+1:]
+	}
+	return fmt.Sprintf("%s on %s (pid %d), since %s", who, l.Host, l.PID, l.Since.Local().Format("15:04"))
+}
+
+// LockEnv returns the environment the lock file keys the restore lock of
+// stack in region by.
+func LockEnv(region, stack string) string {
+	return region + "/" + stack
+}
+
+// DefaultLocksPath returns the restore lock file in the user's config
+// directory, e.g. ~/.config/backup-tui/locks.json on Linux.
+func DefaultLocksPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "backup-tui", locksFile), nil
+}
+
+// LoadLocks reads the restore lock file, by environment (region and
+// stack). A missing file holds no locks.
+func LoadLocks(path string) (map[string]RestoreLock, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read restore locks: %w", err)
+	}
+	if data, err = unseal(path, data); err != nil {
+		return nil, fmt.Errorf("failed to read restore locks: %w", err)
+	}
+	var locks map[string]RestoreLock
+	if err := json.Unmarshal(data, &locks); err != nil {
+		return nil, fmt.Errorf("failed to parse restore locks %s: %w", path, err)
+	}
+	return locks, nil
+}
+
+// SaveLock records l as the lock of env, replacing l's session's lock or a
+// stale one, and drops stale locks of other environments. Another session's
+// live lock is kept, and returned as a *LockConflictError.
+func SaveLock(path, env string, l RestoreLock) error {
+	return updateLocks(path, func(locks map[string]RestoreLock) error {
+		if held, ok := locks[env]; ok && !held.SameSession(l) {
+			return &LockConflictError{Lock: held}
+		}
+		locks[env] = l
+		return nil
+	})
+}
+
+// ReleaseLock removes the lock of env if l's session holds it. Another
+// session's lock is kept.
+func ReleaseLock(path, env string, l RestoreLock) error {
+	return updateLocks(path, func(locks map[string]RestoreLock) error {
+		if held, ok := locks[env]; ok && held.SameSession(l) {
+			delete(locks, env)
+		}
+		return nil
+	})
+}
+
+// updateLocks applies change to the lock file, which is left as it was if
+// change returns an error. The file is read and written under lockFile, so
+// a session of another process cannot take a lock between the two.
+func updateLocks(path string, change func(map[string]RestoreLock) error) error {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	locks, err := LoadLocks(path)
+	if err != nil {
+		return err
+	}
+	if locks == nil {
+		locks = make(map[string]RestoreLock, 1)
+	}
+	now := time.Now()
+	for env, l := range locks {
+		if l.Stale(now) {
+			delete(locks, env)
+		}
+	}
+	if err := change(locks); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(locks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode restore locks: %w", err)
+	}
+	if err := writeState(path, data); err != nil {
+		return fmt.Errorf("failed to save restore locks: %w", err)
+	}
+	return nil
+}
+
+// lockFile takes the exclusive lock on the restore lock file at path: the
+// file path + ".lock", which only one process can create. It waits while
+// another process holds it, and takes it over once older than
+// lockFileStale. The returned unlock releases it.
+func lockFile(path string) (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(2 * lockFileStale)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, _ = fmt.Fprintf(f, "%d\n", os.Getpid())
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock restore locks: %w", err)
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockFileStale {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock restore locks: %s is held by another process", lockPath)
+		}
+		time.Sleep(lockFileRetry)
+	}
+}
+
+// TagValue encodes l as a tag value, e.g. "host=ops-1 pid=4242
+// since=2026-10-17T09:00:00Z updated=2026-10-17T09:05:00Z
+// by=arn:aws:iam::123456789012:user/alice". Characters tags do not allow
+// are replaced, and a holder too long for a tag is cut short.
+func (l RestoreLock) TagValue() string {
+	v := fmt.Sprintf("host=%s pid=%d since=%s updated=%s by=%s",
+		tagSafe(l.Host), l.PID, l.Since.UTC().Format(time.RFC3339), l.Updated.UTC().Format(time.RFC3339), tagSafe(l.Holder))
+	if len(v) > maxTagValue {
+		v = v[:maxTagValue]
+	}
+	return v
+}
+
+// ParseLockTag decodes a lock from the tag value TagValue wrote.
+func ParseLockTag(v string) (RestoreLock, error) {
+	var l RestoreLock
+	for field := range strings.FieldsSeq(v) {
+		key, val, ok := strings.Cut(field, "=")
+		if !ok {
+			return RestoreLock{}, fmt.Errorf("malformed restore lock tag %q", v)
+		}
+		var err error
+		switch key {
+		case "host":
+			l.Host = val
+		case "pid":
+			l.PID, err = strconv.Atoi(val)
+		case "since":
+			l.Since, err = time.Parse(time.RFC3339, val)
+		case "updated":
+			l.Updated, err = time.Parse(time.RFC3339, val)
+		case "by":
+			l.Holder = val
+		}
+		if err != nil {
+			return RestoreLock{}, fmt.Errorf("malformed restore lock tag %q: %w", v, err)
+		}
+	}
+	if l.Host == "" || l.Updated.IsZero() {
+		return RestoreLock{}, fmt.Errorf("malformed restore lock tag %q", v)
+	}
+	return l, nil
+}
+
+// tagSafe replaces the characters AWS does not allow in tag values, and
+// spaces, which separate the fields of TagValue, with "_".
+func tagSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.:/=+-@", r) {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// SharedLocks keeps restore locks where sessions on other machines read
+// them, as aws.BackupClient does in vault tags.
+type SharedLocks interface {
+	SharedRestoreLock(ctx context.Context, vaultName, stack string) (string, error)
+	SetSharedRestoreLock(ctx context.Context, vaultName, stack, value string) error
+	ClearSharedRestoreLock(ctx context.Context, vaultName, stack string) error
+}
+
+// StackLock is the restore lock of one stack: in the local lock file and,
+// with Shared, in the shared lock on the stack's vault.
+type StackLock struct {
+	Path   string      // Local lock file ("" disables it)
+	Env    string      // Environment the file keys the lock by, see LockEnv
+	Shared SharedLocks // Shared lock (nil when not shared)
+	Vault  string
+	Stack  string
+}
+
+// Enabled reports whether the lock is kept anywhere.
+func (k StackLock) Enabled() bool {
+	return k.Stack != "" && (k.Path != "" || k.Shared != nil)
+}
+
+// Other returns the newest live lock on the stack not held by self's
+// session, or nil if there is none. Locks that could be read are returned
+// along with the errors reading the others.
+func (k StackLock) Other(ctx context.Context, self RestoreLock) (*RestoreLock, error) {
+	if !k.Enabled() {
+		return nil, nil
+	}
+	var found []RestoreLock
+	var errs []error
+	if k.Path != "" {
+		locks, err := LoadLocks(k.Path)
+		errs = append(errs, err)
+		if l, ok := locks[k.Env]; ok {
+			found = append(found, l)
+		}
+	}
+	if k.Shared != nil {
+		v, err := k.Shared.SharedRestoreLock(ctx, k.Vault, k.Stack)
+		errs = append(errs, err)
+		if v != "" {
+			l, err := ParseLockTag(v)
+			errs = append(errs, err)
+			if err == nil {
+				found = append(found, l)
+			}
+		}
+	}
+	var other *RestoreLock
+	now := time.Now()
+	for _, l := range found {
+		if !l.Stale(now) && !l.SameSession(self) && (other == nil || l.Updated.After(other.Updated)) {
+			other = &l
+		}
+	}
+	return other, errors.Join(errs...)
+}
+
+// Write records l as the stack's lock in the lock file and the shared
+// lock, replacing l's session's lock or a stale one. Another session's live
+// lock is kept, and returned as a *LockConflictError.
+func (k StackLock) Write(ctx context.Context, l RestoreLock) error {
+	conflict, err := k.write(ctx, l)
+	if conflict != nil {
+		err = errors.Join(&LockConflictError{Lock: *conflict}, err)
+	}
+	return err
+}
+
+// write is Write, returning the newest other session's lock kept, if any,
+// apart from the errors.
+func (k StackLock) write(ctx context.Context, l RestoreLock) (conflict *RestoreLock, err error) {
+	if !k.Enabled() {
+		return nil, nil
+	}
+	var errs []error
+	keep := func(err error) {
+		var c *LockConflictError
+		if errors.As(err, &c) {
+			if conflict == nil || c.Lock.Updated.After(conflict.Updated) {
+				conflict = &c.Lock
+			}
+			return
+		}
+		errs = append(errs, err)
+	}
+	if k.Path != "" {
+		keep(SaveLock(k.Path, k.Env, l))
+	}
+	if k.Shared != nil {
+		keep(k.writeShared(ctx, l))
+	}
+	return conflict, errors.Join(errs...)
+}
+
+// writeShared sets the shared lock to l unless another session holds it
+// and it is live. The tag is read and then written, so sessions on two
+// machines may still both take it.
+func (k StackLock) writeShared(ctx context.Context, l RestoreLock) error {
+	v, err := k.Shared.SharedRestoreLock(ctx, k.Vault, k.Stack)
+	if err != nil {
+		return err
+	}
+	if held, err := ParseLockTag(v); v != "" && err == nil && !held.Stale(time.Now()) && !held.SameSession(l) {
+		return &LockConflictError{Lock: held}
+	}
+	return k.Shared.SetSharedRestoreLock(ctx, k.Vault, k.Stack, l.TagValue())
+}
+
+// Release removes l from the lock file and the shared lock. A lock another
+// session has since taken is kept.
+func (k StackLock) Release(ctx context.Context, l RestoreLock) error {
+	if !k.Enabled() {
+		return nil
+	}
+	var errs []error
+	if k.Path != "" {
+		errs = append(errs, ReleaseLock(k.Path, k.Env, l))
+	}
+	if k.Shared != nil {
+		errs = append(errs, k.releaseShared(ctx, l))
+	}
+	return errors.Join(errs...)
+}
+
+// releaseShared removes the shared lock if l's session holds it.
+func (k StackLock) releaseShared(ctx context.Context, l RestoreLock) error {
+	v, err := k.Shared.SharedRestoreLock(ctx, k.Vault, k.Stack)
+	if err != nil || v == "" {
+		return err
+	}
+	if held, err := ParseLockTag(v); err != nil || !held.SameSession(l) {
+		return nil
+	}
+	return k.Shared.ClearSharedRestoreLock(ctx, k.Vault, k.Stack)
+}
+
+// Take checks the stack for another session's lock and then takes it for
+// self, since now. It returns the lock taken and the other session's, for
+// the caller to warn about: like every restore lock, it refuses nothing.
+// Another session's live lock is kept rather than overwritten, whether the
+// check found it or it was taken since; self's lock is then written by the
+// first refresh after it is released or goes stale. A lock that was not
+// written is an error, but is still returned, so a restore started anyway
+// can refresh and release it.
+func (k StackLock) Take(ctx context.Context, self RestoreLock) (held RestoreLock, other *RestoreLock, err error) {
+	other, checkErr := k.Other(ctx, self)
+	now := time.Now()
+	held = self
+	held.Since, held.Updated = now, now
+	conflict, err := k.write(ctx, held)
+	if conflict != nil && (other == nil || conflict.Updated.After(other.Updated)) {
+		other = conflict
+	}
+	return held, other, errors.Join(checkErr, err)
+}
+
+// Hold keeps l fresh, rewriting it every LockTTL/3, until ctx is done or
+// the returned stop is called; stop releases it. Errors refreshing and
+// releasing are passed to onErr, including a *LockConflictError while
+// another session holds the lock.
+func (k StackLock) Hold(ctx context.Context, l RestoreLock, onErr func(error)) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(LockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				l.Updated = time.Now()
+				if err := k.Write(ctx, l); err != nil {
+					onErr(err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+		if err := k.Release(context.WithoutCancel(ctx), l); err != nil {
+			onErr(err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSaveLock_ReleasesOnlyOwnSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup-tui", locksFile)
+	now := time.Now().Truncate(time.Second)
+	mine := RestoreLock{Host: "ops-1", PID: 100, Since: now, Updated: now}
+	theirs := RestoreLock{Host: "ops-2", PID: 200, Since: now, Updated: now}
+
+	if err := SaveLock(path, "us-west-2/openemr", theirs); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveLock(path, "us-west-2/openemr-staging", RestoreLock{Host: "ops-3", PID: 300, Updated: now.Add(-2 * LockTTL)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReleaseLock(path, "us-west-2/openemr", mine); err != nil {
+		t.Fatal(err)
+	}
+	locks, err := LoadLocks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := locks["us-west-2/openemr"]; !got.SameSession(theirs) {
+		t.Errorf("another session's lock should be kept, got %+v", got)
+	}
+
+	// Nor is it overwritten while live
+	var conflict *LockConflictError
+	if err := SaveLock(path, "us-west-2/openemr", mine); !errors.As(err, &conflict) || !conflict.Lock.SameSession(theirs) {
+		t.Fatalf("SaveLock() over another session's lock = %v, want a conflict", err)
+	}
+	if err := ReleaseLock(path, "us-west-2/openemr", theirs); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveLock(path, "us-west-2/openemr", mine); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReleaseLock(path, "us-west-2/openemr", mine); err != nil {
+		t.Fatal(err)
+	}
+	if locks, err = LoadLocks(path); err != nil {
+		t.Fatal(err)
+	}
+	if len(locks) != 0 {
+		t.Errorf("the released lock and the stale one should be gone, got %+v", locks)
+	}
+}
+
+func TestSaveLock_OneSessionWinsAcrossProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup-tui", locksFile)
+	now := time.Now()
+
+	// Sessions of different processes taking the same stack at once
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Go(func() {
+			errs[i] = SaveLock(path, "us-west-2/openemr", RestoreLock{Host: "ops-1", PID: 100 + i, Since: now, Updated: now})
+		})
+	}
+	wg.Wait()
+	won := 0
+	for _, err := range errs {
+		var conflict *LockConflictError
+		switch {
+		case err == nil:
+			won++
+		case !errors.As(err, &conflict):
+			t.Errorf("SaveLock() = %v, want a conflict", err)
+		}
+	}
+	if won != 1 {
+		t.Errorf("%d sessions took the lock, want 1", won)
+	}
+	if _, err := os.Stat(path + ".lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("the lock file should be removed, got %v", err)
+	}
+}
+
+func TestSaveLock_TakesOverStaleLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), locksFile)
+	// Left by a process that exited while updating the lock file
+	if err := os.WriteFile(path+".lock", []byte(fmt.Sprintln(1)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * lockFileStale)
+	if err := os.Chtimes(path+".lock", old, old); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := SaveLock(path, "us-west-2/openemr", RestoreLock{Host: "ops-1", PID: 100, Since: now, Updated: now}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestoreLock_TagValue(t *testing.T) {
+	since := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	l := RestoreLock{
+		Holder:  "arn:aws:sts::123456789012:assumed-role/OnCall/alice@example.com",
+		Host:    "alice's laptop",
+		PID:     4242,
+		Since:   since,
+		Updated: since.Add(5 * time.Minute),
+	}
+	v := l.TagValue()
+	want := "host=alice_s_laptop pid=4242 since=2026-10-17T09:00:00Z updated=2026-10-17T09:05:00Z by=" + l.Holder
+	if v != want {
+		t.Errorf("TagValue() = %q, want %q", v, want)
+	}
+	got, err := ParseLockTag(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Holder != l.Holder || got.PID != l.PID || !got.Since.Equal(l.Since) || !got.Updated.Equal(l.Updated) {
+		t.Errorf("ParseLockTag() = %+v, want %+v", got, l)
+	}
+
+	if _, err := ParseLockTag("someone else's tag"); err == nil {
+		t.Error("a value TagValue did not write should be rejected")
+	}
+	if len(RestoreLock{Host: "h", Holder: string(make([]byte, 300))}.TagValue()) > maxTagValue {
+		t.Error("the value should fit in a tag")
+	}
+}
+
+// fakeSharedLocks keeps the shared lock tags in memory.
+type fakeSharedLocks map[string]string
+
+func (f fakeSharedLocks) SharedRestoreLock(_ context.Context, vault, stack string) (string, error) {
+	return f[vault+"/"+stack], nil
+}
+
+func (f fakeSharedLocks) SetSharedRestoreLock(_ context.Context, vault, stack, value string) error {
+	f[vault+"/"+stack] = value
+	return nil
+}
+
+func (f fakeSharedLocks) ClearSharedRestoreLock(_ context.Context, vault, stack string) error {
+	delete(f, vault+"/"+stack)
+	return nil
+}
+
+func TestStackLock_TakeKeepsOtherSessionsLock(t *testing.T) {
+	ctx := context.Background()
+	shared := fakeSharedLocks{}
+	k := StackLock{
+		Path:   filepath.Join(t.TempDir(), "backup-tui", locksFile),
+		Env:    LockEnv("us-west-2", "openemr"),
+		Shared: shared,
+		Vault:  "openemr-vault",
+		Stack:  "openemr",
+	}
+	theirs := RestoreLock{Holder: "arn:aws:iam::123456789012:user/bob", Host: "ops-2", PID: 200}
+	mine := RestoreLock{Holder: "arn:aws:iam::123456789012:user/alice", Host: "ops-1", PID: 100}
+
+	theirsHeld, other, err := k.Take(ctx, theirs)
+	if err != nil || other != nil {
+		t.Fatalf("Take() on a free stack = %v, %v", other, err)
+	}
+	held, other, err := k.Take(ctx, mine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == nil || !other.SameSession(theirs) {
+		t.Fatalf("Take() should report the other session's lock, got %+v", other)
+	}
+	if other, err := k.Other(ctx, mine); err != nil || other == nil || !other.SameSession(theirs) {
+		t.Errorf("the other session's lock should be kept, Other() = %+v, %v", other, err)
+	}
+
+	// Once it is released, refreshing ours takes the lock
+	if err := k.Release(ctx, theirsHeld); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Write(ctx, held); err != nil {
+		t.Fatal(err)
+	}
+	var conflict *LockConflictError
+	if err := k.Write(ctx, theirsHeld); !errors.As(err, &conflict) || !conflict.Lock.SameSession(mine) {
+		t.Errorf("Write() over our lock = %v, want a conflict", err)
+	}
+	if other, err := k.Other(ctx, theirs); err != nil || other == nil || !other.SameSession(mine) {
+		t.Errorf("our lock should be kept, Other() = %+v, %v", other, err)
+	}
+	if err := k.Release(ctx, held); err != nil {
+		t.Fatal(err)
+	}
+	if other, err := k.Other(ctx, theirs); err != nil || other != nil {
+		t.Errorf("Other() after release = %+v, %v, want none", other, err)
+	}
+	if len(shared) != 0 {
+		t.Errorf("the shared lock should be cleared, got %v", shared)
+	}
+}
@@ -0,0 +1,166 @@
+// Package store persists backup TUI state on the local disk.
+// This file implements the verification file: the result of each
+// verification of a test restore, per recovery point, so the backup list
+// and reports show which backups were proven restorable, and later
+// verifications of a resource can compare their counts with the last one
+// that passed. The file is encrypted like the job history (encrypt.go).
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// verificationsFile is the name of the verification file in the config
+// directory.
+const verificationsFile = "verifications.json"
+
+// maxVerifications caps the verifications kept; the oldest are dropped.
+const maxVerifications = 500
+
+// Verification and check results.
+const (
+	VerifyPassed       = "PASSED"
+	VerifyFailed       = "FAILED"
+	VerifyInconclusive = "INCONCLUSIVE" // Nothing could be checked, e.g. the size was not yet metered
+	CheckSkipped       = "SKIPPED"
+)
+
+// Verification is the result of checking a test restore of a recovery
+// point.
+type Verification struct {
+	RecoveryPointARN string        `json:"recoveryPointArn"`
+	ResourceType     string        `json:"resourceType"`
+	ResourceID       string        `json:"resourceId"` // Resource the recovery point was taken of
+	BackupCreated    time.Time     `json:"backupCreated"`
+	RestoreJobID     string        `json:"restoreJobId"`
+	RestoredARN      string        `json:"restoredArn"`
+	VerifiedAt       time.Time     `json:"verifiedAt"`
+	Result           string        `json:"result"` // VerifyPassed, VerifyFailed, or VerifyInconclusive
+	Checks           []VerifyCheck `json:"checks"`
+}
+
+// VerifyCheck is one check of a verification.
+type VerifyCheck struct {
+	Name   string `json:"name"`            // e.g. "rows patient_data", "size", "files"
+	Result string `json:"result"`          // VerifyPassed, VerifyFailed, or CheckSkipped
+	Value  int64  `json:"value,omitempty"` // Rows, bytes, or files measured
+	Detail string `json:"detail,omitempty"`
+}
+
+// Passed reports whether the verification passed.
+func (v Verification) Passed() bool {
+	return v.Result == VerifyPassed
+}
+
+// Check returns the check with the given name, if v has one.
+func (v Verification) Check(name string) (VerifyCheck, bool) {
+	for _, c := range v.Checks {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return VerifyCheck{}, false
+}
+
+// Judge sets v's result from its checks: failed if any check failed,
+// passed if any passed, and otherwise inconclusive.
+func (v *Verification) Judge() {
+	v.Result = VerifyInconclusive
+	for _, c := range v.Checks {
+		switch c.Result {
+		case VerifyFailed:
+			v.Result = VerifyFailed
+			return
+		case VerifyPassed:
+			v.Result = VerifyPassed
+		}
+	}
+}
+
+// DefaultVerificationsPath returns the verification file in the user's
+// config directory, e.g. ~/.config/backup-tui/verifications.json on Linux.
+func DefaultVerificationsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "backup-tui", verificationsFile), nil
+}
+
+// LoadVerifications reads the verification file, oldest first. A missing
+// file has no verifications.
+func LoadVerifications(path string) ([]Verification, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read verifications: %w", err)
+	}
+	if data, err = unseal(path, data); err != nil {
+		return nil, fmt.Errorf("failed to read verifications: %w", err)
+	}
+	var vs []Verification
+	if err := json.Unmarshal(data, &vs); err != nil {
+		return nil, fmt.Errorf("failed to parse verifications %s: %w", path, err)
+	}
+	return vs, nil
+}
+
+// SaveVerification adds v to the verification file, keeping the newest
+// 500 verifications.
+func SaveVerification(path string, v Verification) error {
+	vs, err := LoadVerifications(path)
+	if err != nil {
+		return err
+	}
+	vs = append(vs, v)
+	sort.SliceStable(vs, func(i, k int) bool { return vs[i].VerifiedAt.Before(vs[k].VerifiedAt) })
+	if len(vs) > maxVerifications {
+		vs = vs[len(vs)-maxVerifications:]
+	}
+	data, err := json.MarshalIndent(vs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode verifications: %w", err)
+	}
+	if err := writeState(path, data); err != nil {
+		return fmt.Errorf("failed to save verification: %w", err)
+	}
+	return nil
+}
+
+// LatestVerifications returns the newest verification of each recovery
+// point in vs, by recovery point ARN.
+func LatestVerifications(vs []Verification) map[string]Verification {
+	latest := make(map[string]Verification, len(vs))
+	for _, v := range vs {
+		if prev, ok := latest[v.RecoveryPointARN]; !ok || !v.VerifiedAt.Before(prev.VerifiedAt) {
+			latest[v.RecoveryPointARN] = v
+		}
+	}
+	return latest
+}
+
+// Baseline returns the verification later ones of a resource are compared
+// with: the passed verification of the resource's newest backup taken
+// before the given time, or nil if there is none.
+func Baseline(vs []Verification, resourceType, resourceID string, before time.Time) *Verification {
+	var base *Verification
+	for i, v := range vs {
+		if v.ResourceType != resourceType || v.ResourceID != resourceID || !v.Passed() || !v.BackupCreated.Before(before) {
+			continue
+		}
+		if base == nil || v.BackupCreated.After(base.BackupCreated) ||
+			v.BackupCreated.Equal(base.BackupCreated) && v.VerifiedAt.After(base.VerifiedAt) {
+			base = &vs[i]
+		}
+	}
+	return base
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveVerification_LatestAndBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup-tui", verificationsFile)
+	day := 24 * time.Hour
+	for _, v := range []Verification{
+		{RecoveryPointARN: "arn:rp-1", ResourceType: "RDS", ResourceID: "db", BackupCreated: testStart, VerifiedAt: testStart.Add(time.Hour), Result: VerifyPassed},
+		{RecoveryPointARN: "arn:rp-2", ResourceType: "RDS", ResourceID: "db", BackupCreated: testStart.Add(day), VerifiedAt: testStart.Add(day + time.Hour), Result: VerifyFailed},
+		{RecoveryPointARN: "arn:rp-2", ResourceType: "RDS", ResourceID: "db", BackupCreated: testStart.Add(day), VerifiedAt: testStart.Add(day + 2*time.Hour), Result: VerifyPassed},
+		{RecoveryPointARN: "arn:rp-3", ResourceType: "EFS", ResourceID: "fs", BackupCreated: testStart.Add(day), VerifiedAt: testStart.Add(day), Result: VerifyPassed},
+	} {
+		if err := SaveVerification(path, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vs, err := LoadVerifications(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vs) != 4 || vs[0].RecoveryPointARN != "arn:rp-1" {
+		t.Fatalf("verifications should be kept oldest first, got %+v", vs)
+	}
+	if latest := LatestVerifications(vs); len(latest) != 3 || !latest["arn:rp-2"].Passed() {
+		t.Errorf("the newest verification of each recovery point should win, got %+v", latest)
+	}
+
+	if base := Baseline(vs, "RDS", "db", testStart.Add(2*day)); base == nil || base.RecoveryPointARN != "arn:rp-2" {
+		t.Errorf("the baseline should be the passed verification of the newest earlier backup, got %+v", base)
+	}
+	if base := Baseline(vs, "RDS", "db", testStart.Add(day)); base == nil || base.RecoveryPointARN != "arn:rp-1" {
+		t.Errorf("a backup is not its own baseline, got %+v", base)
+	}
+	if base := Baseline(vs, "RDS", "other", testStart.Add(2*day)); base != nil {
+		t.Errorf("another resource has no baseline, got %+v", base)
+	}
+}
+
+func TestVerification_Judge(t *testing.T) {
+	for _, tc := range []struct {
+		results []string
+		want    string
+	}{
+		{[]string{VerifyPassed, CheckSkipped}, VerifyPassed},
+		{[]string{VerifyPassed, VerifyFailed}, VerifyFailed},
+		{[]string{CheckSkipped}, VerifyInconclusive},
+		{nil, VerifyInconclusive},
+	} {
+		var v Verification
+		for _, r := range tc.results {
+			v.Checks = append(v.Checks, VerifyCheck{Result: r})
+		}
+		if v.Judge(); v.Result != tc.want {
+			t.Errorf("checks %v judged %s, want %s", tc.results, v.Result, tc.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Package store persists backup TUI state on the local disk.
+// This file implements the view state file: the sort order, filter, last
+// active view, vault, and list columns of each environment (region and stack), so the TUI opens
+// the way the operator left it for that deployment. The file is encrypted
+// (encrypt.go).
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// viewsFile is the name of the view state file in the config directory.
+const viewsFile = "views.json"
+
+// ViewState is how the TUI was left for one environment. Empty fields are
+// the defaults.
+type ViewState struct {
+	Sort   string `json:"sort,omitempty"`
+	Filter string `json:"filter,omitempty"` // AWS Backup resource type
+	Tab    string `json:"tab,omitempty"`    // View open when last left, e.g. "jobs"
+	Vault  string `json:"vault,omitempty"`  // Vault shown when last left
+
+	// Columns of the backup list, comma-separated, e.g. "type,created,tier"
+	// (empty for the config file's)
+	Columns string `json:"columns,omitempty"`
+}
+
+// DefaultViewsPath returns the view state file in the user's config
+// directory, e.g. ~/.config/backup-tui/views.json on Linux.
+func DefaultViewsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "backup-tui", viewsFile), nil
+}
+
+// LoadViews reads the view state file, by environment. A missing file holds
+// no views.
+func LoadViews(path string) (map[string]ViewState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read view state: %w", err)
+	}
+	if data, err = unseal(path, data); err != nil {
+		return nil, fmt.Errorf("failed to read view state: %w", err)
+	}
+	var views map[string]ViewState
+	if err := json.Unmarshal(data, &views); err != nil {
+		return nil, fmt.Errorf("failed to parse view state %s: %w", path, err)
+	}
+	return views, nil
+}
+
+// SaveView replaces the view state of env, keeping other environments'.
+func SaveView(path, env string, v ViewState) error {
+	views, err := LoadViews(path)
+	if err != nil {
+		return err
+	}
+	if views == nil {
+		views = make(map[string]ViewState, 1)
+	}
+	views[env] = v
+	data, err := json.MarshalIndent(views, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode view state: %w", err)
+	}
+	if err := writeState(path, data); err != nil {
+		return fmt.Errorf("failed to save view state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveView_PerEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup-tui", viewsFile)
+	if views, err := LoadViews(path); err != nil || views != nil {
+		t.Fatalf("a missing view state file should be empty, got %v, %v", views, err)
+	}
+
+	if err := SaveView(path, "us-west-2/openemr", ViewState{Sort: "oldest", Filter: "RDS", Tab: "jobs"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveView(path, "us-east-1/openemr-staging", ViewState{Sort: "largest"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveView(path, "us-west-2/openemr", ViewState{Sort: "oldest", Tab: "list"}); err != nil {
+		t.Fatal(err)
+	}
+
+	views, err := LoadViews(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := views["us-west-2/openemr"]; got != (ViewState{Sort: "oldest", Tab: "list"}) {
+		t.Errorf("the environment's view should be replaced, got %+v", got)
+	}
+	if got := views["us-east-1/openemr-staging"]; got.Sort != "largest" {
+		t.Errorf("other environments should be kept, got %+v", got)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("view state file should be private, got %v, %v", info.Mode(), err)
+	}
+}
+
+func TestLoadViews_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), viewsFile)
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadViews(path); err == nil {
+		t.Error("a corrupt view state file should be reported")
+	}
+	if err := SaveView(path, "env", ViewState{}); err == nil {
+		t.Error("saving should not overwrite a corrupt view state file")
+	}
+}
@@ -0,0 +1,177 @@
+// Package store persists backup TUI state on the local disk.
+// This file implements the workflow file: the steps of multi-step workflows
+// (restore chains queued in the TUI, "dr copy" runs), saved whenever a step
+// is queued, starts, or finishes, so a workflow interrupted by a closed
+// terminal or a crash can be resumed from its last completed step with the
+// same job IDs. The file is encrypted like the job history (encrypt.go).
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// workflowsFile is the name of the workflow file in the config directory.
+const workflowsFile = "workflows.json"
+
+// closedRetention is how long finished or discarded workflows are kept in
+// the file before they are dropped.
+const closedRetention = 30 * 24 * time.Hour
+
+// Workflow kinds.
+const (
+	WorkflowRestoreChain = "restore-chain" // Restores chained in the TUI, each starting when the one before completes
+	WorkflowDRCopy       = "dr-copy"       // "backup-tui dr copy": copies to the recovery account, then restores there
+)
+
+// Step states besides the AWS job states of started steps.
+const (
+	StepPending   = "PENDING"   // Not started yet
+	StepCancelled = "CANCELLED" // Cancelled by the operator before it started
+	StepSkipped   = "SKIPPED"   // Not started because the step before it did not complete
+)
+
+// Workflow is a multi-step workflow and the state of each step.
+type Workflow struct {
+	ID        string            `json:"id"`
+	Kind      string            `json:"kind"` // WorkflowRestoreChain or WorkflowDRCopy
+	Region    string            `json:"region"`
+	Vault     string            `json:"vault"`
+	Target    string            `json:"target,omitempty"` // Where the workflow writes, e.g. "dr copy"'s recovery account and vault
+	StartedAt time.Time         `json:"startedAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+	Params    map[string]string `json:"params,omitempty"` // Options the remaining steps need, e.g. "restore": "true"
+	Steps     []WorkflowStep    `json:"steps"`
+	// Closed is set once the workflow has finished, or the operator chose
+	// not to resume it.
+	Closed bool `json:"closed,omitempty"`
+}
+
+// WorkflowStep is one job of a workflow.
+type WorkflowStep struct {
+	Kind             string    `json:"kind"` // aws.JobKindRestore or JobKindCopy
+	ResourceType     string    `json:"resourceType,omitempty"`
+	ResourceID       string    `json:"resourceId,omitempty"`
+	RecoveryPointARN string    `json:"recoveryPointArn,omitempty"`
+	KMSKeyID         string    `json:"kmsKeyId,omitempty"` // Restore options chosen when the step was queued
+	SubnetGroup      string    `json:"subnetGroup,omitempty"`
+	SecurityGroupIDs []string  `json:"securityGroupIds,omitempty"`
+	CopyTags         bool      `json:"copyTags,omitempty"`
+	ClusterSuffix    string    `json:"clusterSuffix,omitempty"`
+	ItemsToRestore   []string  `json:"itemsToRestore,omitempty"`
+	JobID            string    `json:"jobId,omitempty"` // Set once the step has started
+	StartedAt        time.Time `json:"startedAt,omitzero"`
+	State            string    `json:"state"` // StepPending, the AWS job state, StepCancelled, or StepSkipped
+	// Result is what the step produced that later steps use, e.g. the ARN
+	// of a copy in the recovery vault.
+	Result string `json:"result,omitempty"`
+}
+
+// Started reports whether the step's job has been started.
+func (s WorkflowStep) Started() bool {
+	return s.JobID != ""
+}
+
+// Finished reports whether the step has reached a state it will not leave.
+func (s WorkflowStep) Finished() bool {
+	switch s.State {
+	case StepCancelled, StepSkipped:
+		return true
+	}
+	return TrackedJob{State: s.State}.Finished()
+}
+
+// Completed reports whether the step's job completed.
+func (s WorkflowStep) Completed() bool {
+	return s.State == "COMPLETED"
+}
+
+// Unfinished returns the number of steps that have not finished.
+func (w Workflow) Unfinished() int {
+	n := 0
+	for _, s := range w.Steps {
+		if !s.Finished() {
+			n++
+		}
+	}
+	return n
+}
+
+// Resumable reports whether w was interrupted: it is not closed, has steps
+// left, and was last updated within window of now.
+func (w Workflow) Resumable(now time.Time, window time.Duration) bool {
+	return !w.Closed && w.Unfinished() > 0 && now.Sub(w.UpdatedAt) < window
+}
+
+// NewWorkflowID returns a random workflow ID.
+func NewWorkflowID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// DefaultWorkflowsPath returns the workflow file in the user's config
+// directory, e.g. ~/.config/backup-tui/workflows.json on Linux.
+func DefaultWorkflowsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "backup-tui", workflowsFile), nil
+}
+
+// LoadWorkflows reads the workflow file, oldest workflow first. A missing
+// file has no workflows.
+func LoadWorkflows(path string) ([]Workflow, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflows: %w", err)
+	}
+	if data, err = unseal(path, data); err != nil {
+		return nil, fmt.Errorf("failed to read workflows: %w", err)
+	}
+	var workflows []Workflow
+	if err := json.Unmarshal(data, &workflows); err != nil {
+		return nil, fmt.Errorf("failed to parse workflows %s: %w", path, err)
+	}
+	return workflows, nil
+}
+
+// SaveWorkflow adds w to the workflow file, replacing the workflow with the
+// same ID, and stamps it as updated now. Closed workflows not updated for
+// 30 days are dropped.
+func SaveWorkflow(path string, w Workflow) error {
+	workflows, err := LoadWorkflows(path)
+	if err != nil {
+		return err
+	}
+	w.UpdatedAt = time.Now()
+	kept := workflows[:0]
+	for _, old := range workflows {
+		if old.ID != w.ID && (!old.Closed || w.UpdatedAt.Sub(old.UpdatedAt) < closedRetention) {
+			kept = append(kept, old)
+		}
+	}
+	kept = append(kept, w)
+	sort.SliceStable(kept, func(i, k int) bool { return kept[i].StartedAt.Before(kept[k].StartedAt) })
+
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode workflows: %w", err)
+	}
+	if err := writeState(path, data); err != nil {
+		return fmt.Errorf("failed to save workflow: %w", err)
+	}
+	return nil
+}
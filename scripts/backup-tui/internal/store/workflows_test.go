@@ -0,0 +1,97 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveWorkflow_ReplacesByID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup-tui", workflowsFile)
+
+	w := Workflow{
+		ID: "wf-1", Kind: WorkflowRestoreChain, Region: "us-west-2", Vault: "vault", StartedAt: testStart,
+		Steps: []WorkflowStep{
+			{Kind: "restore", RecoveryPointARN: "arn:rp-1", JobID: "job-1", State: "RUNNING"},
+			{Kind: "restore", RecoveryPointARN: "arn:rp-2", State: StepPending},
+		},
+	}
+	if err := SaveWorkflow(path, w); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveWorkflow(path, Workflow{ID: "wf-0", Kind: WorkflowDRCopy, StartedAt: testStart.Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	w.Steps[0].State = "COMPLETED"
+	if err := SaveWorkflow(path, w); err != nil {
+		t.Fatal(err)
+	}
+
+	workflows, err := LoadWorkflows(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workflows) != 2 || workflows[0].ID != "wf-0" || workflows[1].Steps[0].State != "COMPLETED" {
+		t.Fatalf("workflows should be kept oldest first with the update applied, got %+v", workflows)
+	}
+	if !workflows[1].Resumable(time.Now(), time.Hour) {
+		t.Error("a workflow with a pending step should be resumable")
+	}
+	if workflows[1].UpdatedAt.IsZero() {
+		t.Error("saving should stamp the update time")
+	}
+}
+
+func TestSaveWorkflow_DropsOldClosed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), workflowsFile)
+	old := []Workflow{
+		{ID: "closed-old", StartedAt: testStart, UpdatedAt: time.Now().Add(-closedRetention - time.Hour), Closed: true},
+		{ID: "open-old", StartedAt: testStart, UpdatedAt: time.Now().Add(-closedRetention - time.Hour)},
+		{ID: "closed-recent", StartedAt: testStart, UpdatedAt: time.Now().Add(-time.Hour), Closed: true},
+	}
+	data, err := json.Marshal(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeState(path, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveWorkflow(path, Workflow{ID: "new", StartedAt: testStart.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	workflows, err := LoadWorkflows(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, w := range workflows {
+		ids = append(ids, w.ID)
+	}
+	if fmt.Sprint(ids) != "[open-old closed-recent new]" {
+		t.Errorf("only workflows closed over 30 days ago should be dropped, got %v", ids)
+	}
+}
+
+func TestWorkflow_Resumable(t *testing.T) {
+	now := testStart.Add(time.Hour)
+	pending := []WorkflowStep{{JobID: "job-1", State: "COMPLETED"}, {State: StepPending}}
+	done := []WorkflowStep{{JobID: "job-1", State: "COMPLETED"}, {State: StepSkipped}}
+
+	for _, tc := range []struct {
+		name string
+		w    Workflow
+		want bool
+	}{
+		{"pending step", Workflow{UpdatedAt: testStart, Steps: pending}, true},
+		{"closed", Workflow{UpdatedAt: testStart, Steps: pending, Closed: true}, false},
+		{"all steps finished", Workflow{UpdatedAt: testStart, Steps: done}, false},
+		{"too old", Workflow{UpdatedAt: testStart.Add(-24 * time.Hour), Steps: pending}, false},
+	} {
+		if got := tc.w.Resumable(now, 12*time.Hour); got != tc.want {
+			t.Errorf("%s: Resumable() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
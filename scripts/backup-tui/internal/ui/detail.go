@@ -7,6 +7,9 @@ package ui
 import (
 	"fmt"
 	"image/color"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	tea "charm.land/bubbletea/v2"
@@ -19,9 +22,18 @@ import (
 // It displays information about a selected recovery point and allows the user
 // to initiate restore operations.
 type DetailModel struct {
-	recoveryPoint *aws.RecoveryPoint // Currently displayed recovery point (nil if none selected)
-	width         int                // Available width for rendering
-	height        int                // Available height for rendering
+	recoveryPoint *aws.RecoveryPoint        // Currently displayed recovery point (nil if none selected)
+	fileSystem    *aws.FileSystemInfo       // Live file system for EFS recovery points (nil until loaded)
+	fileSystemErr error                     // Error looking up the live file system
+	cluster       *aws.ClusterHealth        // Live cluster for RDS recovery points (nil until loaded)
+	clusterErr    error                     // Error looking up the live cluster
+	details       *aws.RecoveryPointDetails // Creator, encryption, and tags (nil until enriched)
+	preflight     []PreflightItem           // Restore prerequisites, looked up ahead of the restore
+	protectedTill time.Time                 // End of the vault's Vault Lock minimum retention (zero if none)
+	verification  *Verification             // Last verified test restore (nil if never verified)
+	minRetention  int64                     // Vault Lock minimum retention in days
+	width         int                       // Available width for rendering
+	height        int                       // Available height for rendering
 }
 
 // Styling constants for the detail view component.
@@ -80,6 +92,23 @@ var (
 		})
 )
 
+// PreflightItem is a restore prerequisite shown in the detail view, e.g.
+// the subnet group or IAM role the restore would use.
+type PreflightItem struct {
+	Label   string
+	Value   string
+	Warning string // Why the prerequisite may stop the restore; empty when met
+	Loading bool
+}
+
+// Verification is the last verified test restore of a recovery point,
+// shown in the detail view.
+type Verification struct {
+	Result     string // PASSED, FAILED, or INCONCLUSIVE
+	VerifiedAt time.Time
+	Failed     []string // Details of the checks that failed
+}
+
 // NewDetailModel creates a new DetailModel with no recovery point selected.
 func NewDetailModel() DetailModel {
 	return DetailModel{}
@@ -109,6 +138,14 @@ func (m DetailModel) Update(msg tea.Msg) (DetailModel, tea.Cmd) {
 	return m, nil
 }
 
+// liveResourceViews render the live resource a restore of a resource type
+// targets, keyed as AWS Backup spells the type: for EFS the file system the
+// restore writes into, for RDS the cluster it would replace.
+var liveResourceViews = map[string]func(DetailModel) string{
+	"EFS": DetailModel.fileSystemView,
+	"RDS": DetailModel.clusterView,
+}
+
 // View renders the detail component as a string.
 // Displays comprehensive information about the selected recovery point,
 // including resource type, ID, status, creation date, size, and ARN.
@@ -134,9 +171,20 @@ func (m DetailModel) View() string {
 		lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render("Resource Type:"), valueStyle.Render(rp.ResourceType)),
 		lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render("Resource ID:"), valueStyle.Render(rp.ResourceID)),
 		lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render("Status:"), valueStyle.Render(rp.Status)),
-		lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render("Created:"), dateStyle.Render(fmt.Sprintf("%s (%s)", dateStr, relStr))),
+		lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render("Created:"), dateStyle.Render(fmt.Sprintf("%s %s (%s)", FreshnessLevel(rp.CreationDate).Glyph(), dateStr, relStr))),
 		lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render("Size:"), valueStyle.Render(formatBytes(rp.BackupSizeInBytes))),
+		lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render("Retention:"), valueStyle.Render(retentionText(rp, time.Now()))),
 	)
+	if time.Now().Before(m.protectedTill) {
+		lockStyle := lipgloss.NewStyle().Foreground(ColorWarn)
+		basicInfo = lipgloss.JoinVertical(lipgloss.Left, basicInfo,
+			lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render("Vault Lock:"), lockStyle.Render(fmt.Sprintf(
+				"cannot be deleted before %s (minimum retention %d days)", m.protectedTill.Format("2006-01-02"), m.minRetention))))
+	}
+	if v := m.verification; v != nil {
+		basicInfo = lipgloss.JoinVertical(lipgloss.Left, basicInfo,
+			lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render("Verified:"), verificationText(v)))
+	}
 
 	// Recovery Point ARN Section
 	// ARNs can be very long, so we truncate for display while keeping it readable
@@ -146,6 +194,19 @@ func (m DetailModel) View() string {
 
 	sections = append(sections, basicInfo, "", arnRow)
 
+	if m.details != nil {
+		sections = append(sections, "", m.detailsView())
+	}
+
+	// The live resource the restore would write into or replace
+	if view, ok := liveResourceViews[rp.ResourceType]; ok {
+		sections = append(sections, "", view(m))
+	}
+
+	if len(m.preflight) > 0 {
+		sections = append(sections, "", m.preflightView())
+	}
+
 	actionButton := buttonStyle.Render("Press ENTER to restore this backup")
 
 	sections = append(sections, "", actionButton)
@@ -164,6 +225,22 @@ func (m DetailModel) View() string {
 	return detailStyle.Render(content)
 }
 
+// retentionText describes when AWS Backup deletes the recovery point and,
+// if it does, when it moves to cold storage.
+func retentionText(rp *aws.RecoveryPoint, now time.Time) string {
+	text := "kept indefinitely"
+	if !rp.DeleteAt.IsZero() {
+		text = fmt.Sprintf("deleted %s (in %d days)", rp.DeleteAt.Format("2006-01-02"), int(rp.DeleteAt.Sub(now).Hours()/24))
+	}
+	switch {
+	case rp.InColdStorage(now):
+		text += " · in cold storage"
+	case !rp.MoveToColdAt.IsZero():
+		text += " · cold storage " + rp.MoveToColdAt.Format("2006-01-02")
+	}
+	return text
+}
+
 // SetRecoveryPoint sets the recovery point to display in the detail view.
 // This is called when the user selects a backup from the list view.
 //
@@ -171,6 +248,290 @@ func (m DetailModel) View() string {
 //   - rp: Pointer to the recovery point to display (nil to clear the view)
 func (m *DetailModel) SetRecoveryPoint(rp *aws.RecoveryPoint) {
 	m.recoveryPoint = rp
+	m.details = nil
+	m.preflight = nil
+	m.fileSystem = nil
+	m.fileSystemErr = nil
+	m.cluster = nil
+	m.clusterErr = nil
+	m.protectedTill = time.Time{}
+	m.minRetention = 0
+}
+
+// SetDetails sets the recovery point's details from DescribeRecoveryPoint.
+// Details of a different recovery point than the one shown are ignored.
+func (m *DetailModel) SetDetails(d *aws.RecoveryPointDetails) {
+	if m.recoveryPoint != nil && d != nil && d.RecoveryPointARN == m.recoveryPoint.RecoveryPointARN {
+		m.details = d
+	}
+}
+
+// detailsView renders who created the recovery point, how it is encrypted,
+// and its tags.
+func (m DetailModel) detailsView() string {
+	row := func(label, value string) string {
+		return lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render(label), valueStyle.Render(value))
+	}
+	d := m.details
+	createdBy := "on-demand backup"
+	if !d.OnDemand() {
+		createdBy = "backup plan " + d.CreatedBy
+		if d.BackupRule != "" {
+			createdBy += " (rule " + d.BackupRule + ")"
+		}
+	}
+	encryption := "NOT encrypted"
+	if d.Encrypted {
+		encryption = truncateString(d.EncryptionKeyARN, 60)
+	}
+	lines := []string{
+		row("Created By:", createdBy),
+		row("Encryption Key:", encryption),
+	}
+	if d.StorageClass != "" {
+		lines = append(lines, row("Storage Class:", d.StorageClass))
+	}
+	if d.IAMRoleARN != "" {
+		lines = append(lines, row("IAM Role:", truncateString(d.IAMRoleARN, 60)))
+	}
+	if len(d.Tags) == 0 {
+		lines = append(lines, row("Tags:", "none"))
+	} else {
+		var tags []string
+		for _, k := range slices.Sorted(maps.Keys(d.Tags)) {
+			tags = append(tags, k+"="+d.Tags[k])
+		}
+		lines = append(lines, row("Tags:", strings.Join(tags, ", ")))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// SetDeleteProtection sets until when the vault's Vault Lock keeps the
+// recovery point from being deleted, and its minimum retention in days.
+// A zero time means the recovery point is not protected.
+func (m *DetailModel) SetDeleteProtection(until time.Time, minRetentionDays int64) {
+	m.protectedTill = until
+	m.minRetention = minRetentionDays
+}
+
+// SetVerification sets the last verified test restore of the recovery
+// point, or nil if it was never verified.
+func (m *DetailModel) SetVerification(v *Verification) {
+	m.verification = v
+}
+
+// verificationText renders a verification's result, when it was verified,
+// and why it failed.
+func verificationText(v *Verification) string {
+	text := fmt.Sprintf("%s %s (%s)", v.Result, v.VerifiedAt.Format("2006-01-02 15:04"), DetailRelativeTime(v.VerifiedAt))
+	switch v.Result {
+	case "PASSED":
+		return lipgloss.NewStyle().Foreground(ColorOK).Render("✓ " + text)
+	case "FAILED":
+		if len(v.Failed) > 0 {
+			text += ": " + strings.Join(v.Failed, "; ")
+		}
+		return lipgloss.NewStyle().Foreground(ColorFail).Render("✗ " + text)
+	}
+	return lipgloss.NewStyle().Foreground(ColorWarn).Render("? " + text)
+}
+
+// SetFileSystem sets the live file system details shown for an EFS recovery
+// point, or the error from looking them up.
+func (m *DetailModel) SetFileSystem(info *aws.FileSystemInfo, err error) {
+	m.fileSystem = info
+	m.fileSystemErr = err
+}
+
+// fileSystemView renders the live file system section: size and its change
+// since the backup, throughput, lifecycle policy, and mount targets, so the
+// restore target can be sanity-checked before restoring.
+func (m DetailModel) fileSystemView() string {
+	row := func(label, value string) string {
+		return lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render(label), valueStyle.Render(value))
+	}
+	warnStyle := lipgloss.NewStyle().Foreground(ColorWarn)
+	lines := []string{labelStyle.UnsetWidth().Render("Live File System (restore target):")}
+
+	switch {
+	case m.fileSystemErr != nil:
+		return lipgloss.JoinVertical(lipgloss.Left, append(lines,
+			warnStyle.Render("  Unavailable: "+m.fileSystemErr.Error()))...)
+	case m.fileSystem == nil:
+		return lipgloss.JoinVertical(lipgloss.Left, append(lines, valueStyle.Render("  Loading..."))...)
+	}
+
+	fs := m.fileSystem
+	name := fs.FileSystemID
+	if fs.Name != "" {
+		name += " (" + fs.Name + ")"
+	}
+	throughput := fs.ThroughputMode
+	if fs.ProvisionedMiBps > 0 {
+		throughput += fmt.Sprintf(" (%.0f MiB/s)", fs.ProvisionedMiBps)
+	}
+	encrypted := "yes"
+	if !fs.Encrypted {
+		encrypted = "NO"
+	}
+	lifecycle := "none (all data in Standard)"
+	if len(fs.Lifecycle) > 0 {
+		lifecycle = strings.Join(fs.Lifecycle, ", ")
+	}
+
+	lines = append(lines,
+		row("  File System:", name+" — "+fs.LifeCycleState),
+		row("  Current Size:", formatBytes(fs.SizeBytes)),
+		row("  Since Backup:", sizeChange(fs.SizeBytes, m.recoveryPoint.BackupSizeInBytes)),
+		row("  Throughput:", throughput+" · "+fs.PerformanceMode),
+		row("  Encrypted:", encrypted),
+		row("  Lifecycle:", lifecycle),
+		row("  Mount Targets:", fmt.Sprintf("%d", len(fs.MountTargets))),
+	)
+	for _, mt := range fs.MountTargets {
+		lines = append(lines, valueStyle.Render(fmt.Sprintf("    %-12s %-18s %-24s %s (%s)", mt.AvailabilityZone, mt.ID, mt.SubnetID, mt.IPAddress, mt.State)))
+	}
+	if len(fs.MountTargets) == 0 {
+		lines = append(lines, warnStyle.Render("  No mount targets: ECS tasks cannot mount this file system"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// sizeChange describes how much a file system has grown or shrunk since a
+// backup of backup bytes, a rough measure of the data a restore to it
+// would lose: EFS meters its size about hourly, and the change counts
+// bytes, not which files were rewritten or deleted.
+func sizeChange(live, backup int64) string {
+	if backup <= 0 {
+		return "unknown (AWS Backup reports no size for the backup)"
+	}
+	delta := live - backup
+	pct := float64(delta) / float64(backup) * 100
+	switch {
+	case delta > 0:
+		return fmt.Sprintf("+%s (+%.0f%%): roughly the data written since, which a restore to it would lose", formatBytes(delta), pct)
+	case delta < 0:
+		return fmt.Sprintf("-%s (%.0f%%): the file system has shrunk since the backup", formatBytes(-delta), pct)
+	}
+	return "unchanged"
+}
+
+// SetPreflight sets the restore prerequisites shown for the recovery point.
+func (m *DetailModel) SetPreflight(items []PreflightItem) {
+	m.preflight = items
+}
+
+// preflightView renders the restore prerequisites: met, loading, or with
+// the reason they may stop the restore.
+func (m DetailModel) preflightView() string {
+	warnStyle := lipgloss.NewStyle().Foreground(ColorWarn)
+	okStyle := lipgloss.NewStyle().Foreground(ColorOK)
+	lines := []string{labelStyle.UnsetWidth().Render("Restore Preflight:")}
+	for _, item := range m.preflight {
+		label := labelStyle.Render("  " + item.Label + ":")
+		switch {
+		case item.Loading:
+			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Left, label, valueStyle.Render("Loading...")))
+		case item.Warning != "":
+			text := item.Warning
+			if item.Value != "" {
+				text = item.Value + " — " + text
+			}
+			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Left, label, warnStyle.Render("⚠ "+text)))
+		default:
+			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Left, label, okStyle.Render("✓ "), valueStyle.Render(item.Value)))
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// SetClusterHealth sets the live cluster health shown for an RDS recovery
+// point, or the error from looking it up.
+func (m *DetailModel) SetClusterHealth(h *aws.ClusterHealth, err error) {
+	m.cluster = h
+	m.clusterErr = err
+}
+
+// clusterView renders the live cluster section: status, engine version,
+// instances, storage, and recent failovers, so the operator knows what a
+// restore would replace or be compared against.
+func (m DetailModel) clusterView() string {
+	row := func(label, value string) string {
+		return lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render(label), valueStyle.Render(value))
+	}
+	warnStyle := lipgloss.NewStyle().Foreground(ColorWarn)
+	lines := []string{labelStyle.UnsetWidth().Render("Live Cluster (restore target):")}
+
+	switch {
+	case m.clusterErr != nil:
+		return lipgloss.JoinVertical(lipgloss.Left, append(lines,
+			warnStyle.Render("  Unavailable: "+m.clusterErr.Error()))...)
+	case m.cluster == nil:
+		return lipgloss.JoinVertical(lipgloss.Left, append(lines, valueStyle.Render("  Loading..."))...)
+	}
+
+	h := m.cluster
+	status := valueStyle.Render(h.ClusterID + " — " + h.Status)
+	if !h.Healthy() {
+		status = warnStyle.Render(h.ClusterID + " — " + h.Status)
+	}
+	storage := h.StorageType
+	if h.AllocatedGiB > 0 && h.StorageType != "aurora" && h.StorageType != "aurora-iopt1" {
+		storage += fmt.Sprintf(" (%d GiB)", h.AllocatedGiB)
+	}
+	if h.StorageEncrypted {
+		storage += " · encrypted"
+	} else {
+		storage += " · NOT encrypted"
+	}
+
+	lines = append(lines,
+		lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render("  Cluster:"), status),
+		row("  Engine:", h.Engine+" "+h.EngineVersion),
+		row("  Storage:", storage),
+	)
+	if !h.EarliestRestorable.IsZero() && m.recoveryPoint != nil {
+		lines = append(lines, row("  PITR Window:", pitrWindow(h, m.recoveryPoint.CreationDate)))
+	}
+	if h.ServerlessMaxACU > 0 {
+		lines = append(lines, row("  Serverless v2:", fmt.Sprintf("%g–%g ACU", h.ServerlessMinACU, h.ServerlessMaxACU)))
+	}
+	lines = append(lines, row("  Instances:", fmt.Sprintf("%d", len(h.Instances))))
+	for _, inst := range h.Instances {
+		role := "reader"
+		if inst.Writer {
+			role = "writer"
+		}
+		line := fmt.Sprintf("    %-6s %-32s %-16s %-12s %s", role, inst.ID, inst.Class, inst.AvailabilityZone, inst.Status)
+		if inst.Status != "available" {
+			lines = append(lines, warnStyle.Render(line))
+		} else {
+			lines = append(lines, valueStyle.Render(line))
+		}
+	}
+
+	if len(h.Failovers) == 0 {
+		lines = append(lines, row("  Failovers (7d):", "none"))
+	} else {
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Left, labelStyle.Render("  Failovers (7d):"),
+			warnStyle.Render(fmt.Sprintf("%d", len(h.Failovers)))))
+		for _, e := range h.Failovers {
+			lines = append(lines, valueStyle.Render(fmt.Sprintf("    %s (%s)  %s",
+				e.Time.Local().Format("2006-01-02 15:04"), DetailRelativeTime(e.Time), e.Message)))
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// pitrWindow describes the window the cluster's automated backups can
+// restore it to any moment of, and whether a backup taken at created falls
+// inside it, where point-in-time restore also reaches the moments around it.
+func pitrWindow(h *aws.ClusterHealth, created time.Time) string {
+	window := fmt.Sprintf("%s – %s", h.EarliestRestorable.Local().Format("2006-01-02 15:04"), h.LatestRestorable.Local().Format("2006-01-02 15:04"))
+	if h.CoversPITR(created) {
+		return window + " (covers this backup and the moments around it)"
+	}
+	return window + " (this backup is outside it)"
 }
 
 // formatBytes formats a byte count into a human-readable string.
@@ -228,15 +589,7 @@ func defaultRelativeTime(t time.Time) string {
 }
 
 func defaultFreshnessColor(t time.Time) color.Color {
-	age := time.Since(t)
-	switch {
-	case age < 24*time.Hour:
-		return lipgloss.Color("114")
-	case age < 7*24*time.Hour:
-		return lipgloss.Color("214")
-	default:
-		return lipgloss.Color("196")
-	}
+	return FreshnessLevel(t).Color()
 }
 
 // truncateString truncates a string to the specified maximum length,
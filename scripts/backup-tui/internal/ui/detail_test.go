@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"errors"
 	"image/color"
 	"strings"
 	"testing"
@@ -174,6 +175,27 @@ func TestDetailModel_ViewContainsFields(t *testing.T) {
 	}
 }
 
+func TestRetentionText(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		rp   aws.RecoveryPoint
+		want string
+	}{
+		{"No lifecycle", aws.RecoveryPoint{}, "kept indefinitely"},
+		{"Deleted later", aws.RecoveryPoint{DeleteAt: now.AddDate(0, 0, 10)}, "deleted 2026-03-11 (in 10 days)"},
+		{"Moves to cold", aws.RecoveryPoint{MoveToColdAt: now.AddDate(0, 0, 5)}, "kept indefinitely · cold storage 2026-03-06"},
+		{"In cold storage", aws.RecoveryPoint{MoveToColdAt: now.AddDate(0, 0, -5), DeleteAt: now.AddDate(0, 0, 100)}, "deleted 2026-06-09 (in 100 days) · in cold storage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retentionText(&tt.rp, now); got != tt.want {
+				t.Errorf("retentionText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestDetailModel_ViewContainsRestoreButton(t *testing.T) {
 	model := NewDetailModel()
 	rp := &aws.RecoveryPoint{
@@ -549,3 +571,165 @@ func TestDetailModel_ViewContainsAllLabels(t *testing.T) {
 		}
 	}
 }
+
+func TestDetailModel_EFSFileSystemSection(t *testing.T) {
+	m := NewDetailModel()
+	m.SetRecoveryPoint(&aws.RecoveryPoint{ResourceType: "EFS", ResourceID: "fs-1", CreationDate: time.Now()})
+
+	if !strings.Contains(m.View(), "Loading...") {
+		t.Error("file system section should show loading until details arrive")
+	}
+
+	m.SetFileSystem(&aws.FileSystemInfo{
+		FileSystemID:   "fs-1",
+		Name:           "sites",
+		LifeCycleState: "available",
+		SizeBytes:      1024 * 1024 * 1024,
+		ThroughputMode: "elastic",
+		Encrypted:      true,
+		Lifecycle:      []string{"TransitionToIA: AFTER_30_DAYS"},
+		MountTargets:   []aws.MountTarget{{ID: "fsmt-a", AvailabilityZone: "us-west-2a", SubnetID: "subnet-a", IPAddress: "10.0.1.5", State: "available"}},
+	}, nil)
+	view := m.View()
+	for _, want := range []string{"fs-1 (sites)", "1.0 GB", "Since Backup:", "elastic", "TransitionToIA: AFTER_30_DAYS", "us-west-2a", "fsmt-a"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+
+	m.SetRecoveryPoint(&aws.RecoveryPoint{ResourceType: "EFS", ResourceID: "fs-2", CreationDate: time.Now()})
+	if strings.Contains(m.View(), "fs-1 (sites)") {
+		t.Error("selecting another recovery point should clear the file system details")
+	}
+}
+
+func TestSizeChange(t *testing.T) {
+	const gb = 1024 * 1024 * 1024
+	tests := []struct {
+		live, backup int64
+		want         string
+	}{
+		{12 * gb, 10 * gb, "+2.0 GB (+20%)"},
+		{9 * gb, 10 * gb, "-1.0 GB (-10%)"},
+		{10 * gb, 10 * gb, "unchanged"},
+		{10 * gb, 0, "unknown"},
+	}
+	for _, tt := range tests {
+		if got := sizeChange(tt.live, tt.backup); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("sizeChange(%d, %d) = %q, want prefix %q", tt.live, tt.backup, got, tt.want)
+		}
+	}
+}
+
+func TestDetailModel_EFSFileSystemWarnings(t *testing.T) {
+	m := NewDetailModel()
+	m.SetRecoveryPoint(&aws.RecoveryPoint{ResourceType: "EFS", ResourceID: "fs-1", CreationDate: time.Now()})
+	m.SetFileSystem(&aws.FileSystemInfo{FileSystemID: "fs-1"}, nil)
+	if view := m.View(); !strings.Contains(view, "No mount targets") || !strings.Contains(view, "none (all data in Standard)") {
+		t.Error("expected mount target warning and no-lifecycle note")
+	}
+
+	m.SetFileSystem(nil, errors.New("file system fs-1 not found"))
+	if !strings.Contains(m.View(), "Unavailable: file system fs-1 not found") {
+		t.Error("lookup errors should be shown")
+	}
+}
+
+func TestDetailModel_RDSHasNoFileSystemSection(t *testing.T) {
+	m := NewDetailModel()
+	m.SetRecoveryPoint(&aws.RecoveryPoint{ResourceType: "RDS", ResourceID: "db", CreationDate: time.Now()})
+	if strings.Contains(m.View(), "Live File System") {
+		t.Error("RDS recovery points should not show the file system section")
+	}
+}
+
+func TestDetailModel_RDSClusterSection(t *testing.T) {
+	m := NewDetailModel()
+	m.SetRecoveryPoint(&aws.RecoveryPoint{ResourceType: "RDS", ResourceID: "db", CreationDate: time.Now().Add(-time.Hour)})
+	if view := m.View(); !strings.Contains(view, "Live Cluster") || !strings.Contains(view, "Loading...") {
+		t.Error("cluster section should show loading until health arrives")
+	}
+
+	m.SetClusterHealth(&aws.ClusterHealth{
+		ClusterID:          "db",
+		Status:             "available",
+		Engine:             "aurora-mysql",
+		EngineVersion:      "8.0.mysql_aurora.3.08.0",
+		StorageType:        "aurora",
+		StorageEncrypted:   true,
+		ServerlessMinACU:   0.5,
+		ServerlessMaxACU:   16,
+		Instances:          []aws.ClusterInstance{{ID: "db-1", Class: "db.serverless", Writer: true, AvailabilityZone: "us-west-2a", Status: "available"}},
+		Failovers:          []aws.ClusterEvent{{Time: time.Now().Add(-time.Hour), Message: "Completed failover"}},
+		EarliestRestorable: time.Now().Add(-7 * 24 * time.Hour),
+		LatestRestorable:   time.Now().Add(-5 * time.Minute),
+	}, nil)
+	view := m.View()
+	for _, want := range []string{"8.0.mysql_aurora.3.08.0", "0.5–16 ACU", "writer", "db.serverless", "Completed failover", "PITR Window:", "covers this backup"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+
+	m.SetClusterHealth(nil, errors.New("DB cluster not found: db"))
+	if !strings.Contains(m.View(), "Unavailable: DB cluster not found: db") {
+		t.Error("lookup errors should be shown")
+	}
+}
+
+func TestDetailModel_DetailsSection(t *testing.T) {
+	m := NewDetailModel()
+	m.SetRecoveryPoint(&aws.RecoveryPoint{RecoveryPointARN: "rp-1", ResourceType: "RDS", CreationDate: time.Now()})
+	if strings.Contains(m.View(), "Created By:") {
+		t.Error("details should not be shown before they are loaded")
+	}
+
+	m.SetDetails(&aws.RecoveryPointDetails{RecoveryPointARN: "rp-other", CreatedBy: "other-plan"})
+	if strings.Contains(m.View(), "other-plan") {
+		t.Error("details of another recovery point should be ignored")
+	}
+
+	m.SetDetails(&aws.RecoveryPointDetails{
+		RecoveryPointARN: "rp-1",
+		CreatedBy:        "daily-plan",
+		Encrypted:        true,
+		EncryptionKeyARN: "arn:aws:kms:us-west-2:1:key/k1",
+		Tags:             map[string]string{"Owner": "ops", "Env": "prod"},
+	})
+	view := m.View()
+	for _, want := range []string{"backup plan daily-plan", "key/k1", "Env=prod, Owner=ops"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+
+	m.SetRecoveryPoint(&aws.RecoveryPoint{RecoveryPointARN: "rp-2", ResourceType: "RDS", CreationDate: time.Now()})
+	if strings.Contains(m.View(), "daily-plan") {
+		t.Error("selecting another recovery point should clear the details")
+	}
+}
+
+func TestDetailModel_Preflight(t *testing.T) {
+	m := NewDetailModel()
+	m.SetRecoveryPoint(&aws.RecoveryPoint{RecoveryPointARN: "rp-1", ResourceType: "RDS", CreationDate: time.Now()})
+	if strings.Contains(m.View(), "Restore Preflight") {
+		t.Error("preflight should not be shown before it starts")
+	}
+
+	m.SetPreflight([]PreflightItem{
+		{Label: "Cluster", Value: "openemr-db"},
+		{Label: "Subnet Group", Value: "old-subnets", Warning: "not found in the stack's VPC vpc-1"},
+		{Label: "KMS Keys", Loading: true},
+	})
+	view := m.View()
+	for _, want := range []string{"Restore Preflight", "✓", "openemr-db", "⚠ old-subnets — not found", "Loading..."} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+
+	m.SetRecoveryPoint(&aws.RecoveryPoint{RecoveryPointARN: "rp-2", ResourceType: "RDS", CreationDate: time.Now()})
+	if strings.Contains(m.View(), "Restore Preflight") {
+		t.Error("selecting another recovery point should clear the preflight")
+	}
+}
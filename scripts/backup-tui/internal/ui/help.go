@@ -1,20 +1,41 @@
 // Package ui provides user interface components for the backup TUI.
-// This file implements the help screen component, which displays keyboard
-// shortcuts, usage tips, and general application guidance.
+// This file implements the help screen component, which displays the
+// keyboard shortcuts of the current view and of every view, usage tips, and
+// general application guidance, and filters them as the operator types.
 package ui
 
 import (
+	"strconv"
+	"strings"
+
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"charm.land/lipgloss/v2/compat"
 )
 
+// HelpBinding is a key binding listed on the help screen.
+type HelpBinding struct {
+	Key  string // e.g. "Enter" or "↑/↓, k/j"
+	Desc string
+}
+
+// HelpSection is a titled group of bindings, e.g. those of one view.
+type HelpSection struct {
+	Title    string
+	Bindings []HelpBinding
+}
+
 // HelpModel manages the state and rendering of the help screen.
-// The help screen provides users with information about keyboard shortcuts,
-// navigation controls, and usage tips.
+// The help screen lists the bindings of the view it was opened from first,
+// then the bindings that work everywhere, and typing after "/" filters
+// both by key or description.
 type HelpModel struct {
-	width  int // Available width for rendering
-	height int // Available height for rendering
+	width         int           // Available width for rendering
+	height        int           // Available height for rendering
+	resourceTypes []string      // Resource types the filter cycles through
+	context       []HelpSection // Bindings of the current view; nil for the backup list
+	query         string        // Filter typed after "/"
+	searching     bool          // Whether keys are typed into the filter
 }
 
 // Styling constants for the help screen component.
@@ -79,61 +100,198 @@ func (m HelpModel) Init() tea.Cmd {
 	return nil
 }
 
-// Update handles messages and updates the help model state.
-// Currently only handles window resize events to adjust rendering dimensions.
+// Update handles messages and updates the help model state: window
+// resizes, and keys typed into the filter while searching.
 //
 // Parameters:
-//   - msg: Bubbletea message (tea.WindowSizeMsg for resize)
+//   - msg: Bubbletea message (tea.WindowSizeMsg for resize, tea.KeyPressMsg while searching)
 //
 // Returns:
 //   - HelpModel: Updated model state
 //   - tea.Cmd: Command to execute (nil for this component)
 func (m HelpModel) Update(msg tea.Msg) (HelpModel, tea.Cmd) {
-	if sizeMsg, ok := msg.(tea.WindowSizeMsg); ok {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
 		// Store window dimensions for proper rendering
-		m.width = sizeMsg.Width
-		m.height = sizeMsg.Height
+		m.width = msg.Width
+		m.height = msg.Height
+	case tea.KeyPressMsg:
+		if !m.searching {
+			break
+		}
+		switch msg.String() {
+		case "enter":
+			m.searching = false
+		case "esc":
+			m.searching = false
+			m.query = ""
+		case "backspace":
+			if r := []rune(m.query); len(r) > 0 {
+				m.query = string(r[:len(r)-1])
+			}
+		default:
+			if msg.Text != "" {
+				m.query += msg.Text
+			}
+		}
 	}
 	return m, nil
 }
 
-// View renders the help screen as a string.
-// Displays organized sections of keyboard shortcuts, actions, general controls,
-// and usage tips in a readable, formatted layout.
+// Search starts typing into the filter.
+func (m *HelpModel) Search() {
+	m.searching = true
+}
+
+// Searching reports whether keys are typed into the filter.
+func (m HelpModel) Searching() bool {
+	return m.searching
+}
+
+// Query returns the filter.
+func (m HelpModel) Query() string {
+	return m.query
+}
+
+// SetContext sets the bindings of the view the help screen was opened from,
+// listed before the global bindings, and clears the filter. Nil lists the
+// backup list's bindings.
+func (m *HelpModel) SetContext(sections []HelpSection) {
+	m.context = sections
+	m.query = ""
+	m.searching = false
+}
+
+// SetResourceTypes sets the resource types the "f" filter cycles through,
+// as listed on the help screen.
+func (m *HelpModel) SetResourceTypes(types []string) {
+	m.resourceTypes = types
+}
+
+// listSections returns the backup list's bindings.
+func listSections(types []string) []HelpSection {
+	return []HelpSection{
+		{Title: "Navigation", Bindings: []HelpBinding{
+			{"↑/↓, k/j", "Navigate backup list"},
+			{"PgUp/PgDn", "Scroll one page up/down"},
+			{"Home/g", "Jump to first backup"},
+			{"End/G", "Jump to last backup"},
+			{"Tab/Shift+Tab", "Switch to the Backup Jobs and Restore Jobs tabs"},
+			{"Enter", "Select backup / Confirm action"},
+			{"@", "Select the backups closest to but not after a moment, e.g. 2026-01-15T03:00Z"},
+		}},
+		{Title: "Actions", Bindings: []HelpBinding{
+			{"f", "Cycle filter: All → " + strings.Join(types, " → ")},
+			{"F", "Clear the filters (f and -type)"},
+			{"s", "Cycle sort: newest → oldest → largest"},
+			{"v", "Switch vault (pick one of the region's, or type a name or region/vault)"},
+			{"-", "Return to the previous vault"},
+			{"C", "Choose the list's columns and their order"},
+			{"r", "Refresh backup list"},
+			{"Space", "Mark the backup for a legal hold, a tag edit, or deletion"},
+			{"#", "Edit tags: add key=value or remove -key on the marked backups (or this one)"},
+			{"d", "Delete the marked backups (or this one) after typing \"delete\"; protected ones are kept"},
+			{"u", "Undo the last deletion during its grace period"},
+			{"H", "Legal holds: hold marked backups (n), release a hold (x)"},
+			{"i", "Environment info: account, stack, vault, role, and resource identifiers to copy"},
+			{"P", "Backup selections: what the vault's plan backs up, and why"},
+			{"J", "Jobs view: restores this session and jobs started elsewhere"},
+			{"T", "OpenEMR task definition history for the selected backup"},
+			{"t", "Timeline: backups, restores, copies, and deployments"},
+			{"A", "Vault activity: backups created, copied in, and deleted in the last day; w widens the window"},
+			{"O", "Team activity: operations every operator started, from the shared journal"},
+		}},
+	}
+}
+
+// globalBindings are the bindings that work in every view.
+var globalBindings = []HelpBinding{
+	{"?", "Show/hide this help"},
+	{"/", "Search this help (Enter keeps the filter, Esc clears it)"},
+	{"b, ←, Esc", "Go back"},
+	{"e", "Error log: recent errors and warnings with AWS error codes and request IDs"},
+	{"ctrl+d", "API calls per service, with retries, throttles, and the call budget"},
+	{"q", "Quit application (from the backup list; elsewhere, go back)"},
+}
+
+// matches reports whether a binding or tip contains the filter, ignoring case.
+func (m HelpModel) matches(text ...string) bool {
+	if m.query == "" {
+		return true
+	}
+	q := strings.ToLower(m.query)
+	for _, t := range text {
+		if strings.Contains(strings.ToLower(t), q) {
+			return true
+		}
+	}
+	return false
+}
+
+// View renders the help screen as a string: the current view's bindings,
+// the global bindings, and usage tips, narrowed to the filter.
 //
 // Returns:
 //   - string: Rendered help screen
 func (m HelpModel) View() string {
 	title := titleStyle.Render("Help - OpenEMR Backup Manager")
 
-	// Organize help content into logical sections
-	sections := []string{
-		title,
-		"",
-		sectionStyle.Render("Navigation:"),
-		formatHelpItem("↑/↓, k/j", "Navigate backup list"),
-		formatHelpItem("PgUp/PgDn", "Scroll one page up/down"),
-		formatHelpItem("Home/g", "Jump to first backup"),
-		formatHelpItem("End/G", "Jump to last backup"),
-		formatHelpItem("Enter", "Select backup / Confirm action"),
-		formatHelpItem("b, ←, Esc", "Go back"),
-		"",
-		sectionStyle.Render("Actions:"),
-		formatHelpItem("f", "Cycle filter: All → RDS → EFS"),
-		formatHelpItem("r", "Refresh backup list"),
-		formatHelpItem("Enter", "Restore backup (from detail view)"),
-		formatHelpItem("y / n", "Confirm or cancel restore"),
-		"",
-		sectionStyle.Render("General:"),
-		formatHelpItem("?", "Show/hide this help"),
-		formatHelpItem("q", "Quit application"),
-		"",
-		sectionStyle.Render("Tips:"),
-		descStyle.Render("• Backups are color-coded by age: green (<24h), yellow (1-7d), red (>7d)"),
-		descStyle.Render("• Press f to cycle through resource type filters without restarting"),
-		descStyle.Render("• Restore progress is monitored live after confirmation"),
-		descStyle.Render("• You can press Esc during restore monitoring to return to the list"),
-		descStyle.Render("• Use -type flag to pre-filter by RDS or EFS at launch"),
+	// Until the vault's backups are loaded, describe the stack's own types
+	types := m.resourceTypes
+	if len(types) == 0 {
+		types = []string{"RDS", "EFS"}
+	}
+
+	search := descStyle.Render("Press / to search")
+	switch {
+	case m.searching:
+		search = descStyle.Render("Search: " + m.query + "▏")
+	case m.query != "":
+		search = descStyle.Render("Search: " + m.query + "  (/ to edit)")
+	}
+	sections := []string{title, search}
+
+	context := m.context
+	if context == nil {
+		context = listSections(types)
+	}
+	matched := 0
+	for _, sec := range append(context, HelpSection{Title: "General", Bindings: globalBindings}) {
+		var items []string
+		for _, b := range sec.Bindings {
+			if m.matches(b.Key, b.Desc) {
+				items = append(items, formatHelpItem(b.Key, b.Desc))
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+		matched += len(items)
+		sections = append(sections, "", sectionStyle.Render(sec.Title+":"))
+		sections = append(sections, items...)
+	}
+
+	tips := []string{
+		"Backups are color-coded by age: green (<24h), yellow (1-7d), red (>7d)",
+		"Press f to cycle through resource type filters without restarting",
+		"Restore progress is monitored live after confirmation",
+		"You can press Esc during restore monitoring to return to the list",
+		"Use -type flag to pre-filter by resource type at launch (this vault: " + strings.Join(types, ", ") + ")",
+		"Chain restores (e.g. EFS after RDS): a queued step is skipped if the one before it fails",
+		"Each vault remembers its filter, sort, and cursor; - flips between two vaults",
+	}
+	var tipLines []string
+	for _, tip := range tips {
+		if m.matches(tip) {
+			tipLines = append(tipLines, descStyle.Render("• "+tip))
+		}
+	}
+	if len(tipLines) > 0 {
+		sections = append(sections, "", sectionStyle.Render("Tips:"))
+		sections = append(sections, tipLines...)
+	}
+	if matched == 0 && len(tipLines) == 0 {
+		sections = append(sections, "", descStyle.Render("No bindings match "+strconv.Quote(m.query)+"."))
 	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
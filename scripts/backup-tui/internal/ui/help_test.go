@@ -266,3 +266,44 @@ func TestFormatHelpItem_LongDescription(t *testing.T) {
 		t.Error("should contain the full description")
 	}
 }
+
+func TestHelpModel_ContextAndSearch(t *testing.T) {
+	model := NewHelpModel()
+	model.SetContext([]HelpSection{{Title: "Jobs", Bindings: []HelpBinding{
+		{Key: "D", Desc: "Delete what a failed job left behind"},
+		{Key: "i", Desc: "Import a job ID"},
+	}}})
+	view := model.View()
+	if strings.Contains(view, "Navigation") || !strings.Contains(view, "Jobs:") {
+		t.Fatal("a view's bindings should replace the backup list's")
+	}
+	if strings.Index(view, "Jobs:") > strings.Index(view, "General:") {
+		t.Error("the view's bindings should come before the global ones")
+	}
+
+	model.Search()
+	for _, r := range "DELETE" {
+		model, _ = model.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if model.Searching() || model.Query() != "DELETE" {
+		t.Fatalf("enter should keep the filter, got %q (searching %v)", model.Query(), model.Searching())
+	}
+	view = model.View()
+	if !strings.Contains(view, "failed job") || strings.Contains(view, "Import a job ID") {
+		t.Error("the filter should match descriptions, ignoring case")
+	}
+
+	model.Search()
+	for range "DELETE" {
+		model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	}
+	model, _ = model.Update(tea.KeyPressMsg{Code: 'z', Text: "zzz"})
+	if view = model.View(); !strings.Contains(view, "No bindings match") {
+		t.Error("a filter matching nothing should say so")
+	}
+	model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if model.Searching() || model.Query() != "" {
+		t.Error("esc should clear the filter")
+	}
+}
@@ -1,10 +1,13 @@
 // Package ui provides user interface components for the backup TUI.
 // This file implements the list view component, which displays a scrollable
-// list of backup recovery points with keyboard navigation support.
+// table of backup recovery points with keyboard navigation support. Columns
+// have fixed widths, except one flexible column that takes the width the
+// others leave; cells wider than their column are truncated with "…".
 package ui
 
 import (
 	"fmt"
+	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
@@ -15,14 +18,47 @@ import (
 // It handles cursor navigation, item selection, viewport scrolling,
 // and visual styling for the list of recovery points displayed to the user.
 type ListModel struct {
-	items    []string // Formatted backup items to display
-	cursor   int      // Currently selected item index (0-based)
-	offset   int      // Scroll offset (first visible item index)
-	height   int      // Available height for rendering (from window size)
-	width    int      // Available width for rendering (from window size)
-	pageSize int      // Number of items visible in viewport
+	columns  []Column
+	items    []Row // Backup items to display, a cell per column
+	widest   []int // Width of each column's widest cell
+	cursor   int   // Currently selected item index (0-based)
+	offset   int   // Scroll offset (first visible item index)
+	height   int   // Available height for rendering (from window size)
+	width    int   // Available width for rendering (from window size)
+	pageSize int   // Number of items visible in viewport
 }
 
+// Column is a column of the list.
+type Column struct {
+	Title string
+	Width int               // Width in cells; the minimum width of the Flex column
+	Flex  bool              // Takes the width the other columns leave, up to its widest cell
+	Align lipgloss.Position // lipgloss.Left (the default) or lipgloss.Right
+	// HideEmpty leaves the column out while no item has a value in it,
+	// e.g. a mark column while nothing is marked.
+	HideEmpty bool
+}
+
+// Row is an item of the list: its cells in column order. Cells may be
+// styled; their width is measured without the escape sequences.
+type Row []string
+
+// String returns the cells of r separated by spaces.
+func (r Row) String() string {
+	return strings.Join(r, " ")
+}
+
+const (
+	columnGap = 2 // Spaces between columns
+	rowIndent = 4 // Padding and cursor before a row's first column
+	rowChrome = 6 // rowIndent, and the padding and margin after the selected row
+
+	// listChrome is the lines of the list that are not items: the header
+	// with its padding, border, and margin, the "more above" and "more
+	// below" lines, and the position.
+	listChrome = 7
+)
+
 // Styling constants for the list view component.
 // These styles use adaptive colors that work well in both light and dark terminals.
 //
@@ -40,7 +76,7 @@ var (
 
 	// selectedItemStyle styles the currently selected/highlighted item
 	selectedItemStyle = lipgloss.NewStyle().
-				PaddingLeft(1).
+				PaddingLeft(2).
 				PaddingRight(1).
 				Foreground(lipgloss.Color("229")). // Light yellow text
 				Background(compat.AdaptiveColor{
@@ -67,12 +103,17 @@ var (
 		Bold(true)
 )
 
-// NewListModel creates a new ListModel with empty items and cursor at position 0.
-// This should be called when initializing the application model.
-func NewListModel() ListModel {
+// NewListModel creates a new ListModel with the given columns, empty items,
+// and cursor at position 0. Without columns, each item is one flexible
+// column. This should be called when initializing the application model.
+func NewListModel(columns ...Column) ListModel {
+	if len(columns) == 0 {
+		columns = []Column{{Flex: true}}
+	}
 	return ListModel{
-		items:  []string{},
-		cursor: 0,
+		columns: columns,
+		items:   []Row{},
+		cursor:  0,
 	}
 }
 
@@ -148,6 +189,8 @@ func (m *ListModel) adjustOffset() {
 	if m.cursor >= m.offset+visible {
 		m.offset = m.cursor - visible + 1
 	}
+	// A taller page shows more items above rather than leaving lines empty
+	m.offset = min(m.offset, len(m.items)-visible)
 	if m.offset < 0 {
 		m.offset = 0
 	}
@@ -167,7 +210,12 @@ func (m ListModel) View() string {
 			Render("No backups found")
 	}
 
-	header := listHeaderStyle.Render("Type | Resource ID | Creation Date | Size")
+	widths := m.columnWidths()
+	titles := make(Row, len(m.columns))
+	for i, c := range m.columns {
+		titles[i] = c.Title
+	}
+	header := listHeaderStyle.Render(strings.Repeat(" ", rowIndent) + m.renderRow(titles, widths))
 
 	visible := m.visibleItems()
 	end := m.offset + visible
@@ -185,10 +233,11 @@ func (m ListModel) View() string {
 	}
 
 	for i := m.offset; i < end; i++ {
+		row := m.renderRow(m.items[i], widths)
 		if i == m.cursor {
-			items = append(items, selectedItemStyle.Render("▶ "+m.items[i]))
+			items = append(items, selectedItemStyle.Render("▶ "+row))
 		} else {
-			items = append(items, listItemStyle.Render("  "+m.items[i]))
+			items = append(items, listItemStyle.Render("  "+row))
 		}
 	}
 
@@ -209,16 +258,77 @@ func (m ListModel) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, header, list)
 }
 
+// columnWidths returns the width of each column, 0 for hidden ones. The
+// Flex column is as wide as its widest cell, narrowed to fit the window
+// but not below its Width.
+func (m ListModel) columnWidths() []int {
+	widths := make([]int, len(m.columns))
+	used, shown, flex := 0, 0, -1
+	for i, c := range m.columns {
+		switch {
+		case c.HideEmpty && m.widest[i] == 0:
+			continue
+		case c.Flex:
+			flex = i
+			widths[i] = max(c.Width, m.widest[i], lipgloss.Width(c.Title))
+		default:
+			widths[i] = c.Width
+			used += c.Width
+		}
+		shown++
+	}
+	if flex >= 0 && m.width > 0 {
+		available := m.width - rowChrome - used - columnGap*(shown-1)
+		widths[flex] = max(m.columns[flex].Width, min(widths[flex], available))
+	}
+	return widths
+}
+
+// renderRow lays out cells in columns of the given widths.
+func (m ListModel) renderRow(cells Row, widths []int) string {
+	var parts []string
+	for i, width := range widths {
+		if width == 0 {
+			continue
+		}
+		var cell string
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		parts = append(parts, fitCell(cell, width, m.columns[i].Align))
+	}
+	return strings.Join(parts, strings.Repeat(" ", columnGap))
+}
+
+// fitCell pads cell to width, aligned as given, truncating it with "…"
+// when it is wider.
+func fitCell(cell string, width int, align lipgloss.Position) string {
+	if lipgloss.Width(cell) > width {
+		if width > 1 {
+			cell = lipgloss.NewStyle().MaxWidth(width-1).Render(cell) + "…"
+		} else {
+			cell = lipgloss.NewStyle().MaxWidth(width).Render(cell)
+		}
+	}
+	return lipgloss.NewStyle().Width(width).Align(align).Render(cell)
+}
+
 // SetItems updates the list items and adjusts the cursor position if necessary.
 // This is called when backup data is loaded or refreshed.
 //
 // Parameters:
-//   - items: New list of formatted backup items to display
+//   - items: New list of backup items to display, a cell per column
 //
 // Note: If the cursor is beyond the new item count, it's adjusted to the last item.
 // If the list is empty, cursor is set to 0.
-func (m *ListModel) SetItems(items []string) {
+func (m *ListModel) SetItems(items []Row) {
 	m.items = items
+	m.widest = make([]int, len(m.columns))
+	for _, row := range items {
+		for i := range min(len(row), len(m.columns)) {
+			m.widest[i] = max(m.widest[i], lipgloss.Width(row[i]))
+		}
+	}
 	// Ensure cursor stays within valid range
 	if m.cursor >= len(m.items) {
 		m.cursor = len(m.items) - 1
@@ -228,6 +338,36 @@ func (m *ListModel) SetItems(items []string) {
 	}
 }
 
+// SetColumns replaces the list's columns, keeping the cursor. The items
+// must then be set again, a cell per new column.
+func (m *ListModel) SetColumns(columns ...Column) {
+	if len(columns) == 0 {
+		columns = []Column{{Flex: true}}
+	}
+	m.columns = columns
+	m.widest = make([]int, len(columns))
+}
+
+// SetWidth sets the width the list's columns fit, without changing its
+// page size.
+//
+// Parameters:
+//   - width: Available width in cells (0 to size columns by their content)
+func (m *ListModel) SetWidth(width int) {
+	m.width = width
+}
+
+// SetHeight sets the lines the list may take, its header and scroll
+// indicators included, and scrolls the cursor into view.
+//
+// Parameters:
+//   - height: Available height in lines (at least one item is shown)
+func (m *ListModel) SetHeight(height int) {
+	m.height = height
+	m.pageSize = max(height-listChrome, 1)
+	m.adjustOffset()
+}
+
 // SelectedIndex returns the index of the currently selected item.
 // This is used by the parent model to determine which backup was selected
 // when the user presses Enter.
@@ -237,3 +377,26 @@ func (m *ListModel) SetItems(items []string) {
 func (m ListModel) SelectedIndex() int {
 	return m.cursor
 }
+
+// SetCursor moves the selection to index, clamped to the item range, and
+// scrolls it into view. Used to restore the cursor when returning to a list.
+//
+// Parameters:
+//   - index: Zero-based index of the item to select
+func (m *ListModel) SetCursor(index int) {
+	m.cursor = min(index, len(m.items)-1)
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.adjustOffset()
+}
+
+// VisibleRange returns the indexes of the items on screen, from start to
+// end exclusive. Used to fetch details for only the rows being shown.
+//
+// Returns:
+//   - start: Index of the first visible item
+//   - end: Index after the last visible item
+func (m ListModel) VisibleRange() (start, end int) {
+	return m.offset, min(m.offset+m.visibleItems(), len(m.items))
+}
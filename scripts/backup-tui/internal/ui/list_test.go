@@ -1,12 +1,23 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
 )
 
+// rows returns single-cell rows of items.
+func rows(items ...string) []Row {
+	r := make([]Row, len(items))
+	for i, item := range items {
+		r[i] = Row{item}
+	}
+	return r
+}
+
 func TestNewListModel(t *testing.T) {
 	model := NewListModel()
 
@@ -14,7 +25,7 @@ func TestNewListModel(t *testing.T) {
 		t.Errorf("NewListModel() SelectedIndex() = %d, want 0", model.SelectedIndex())
 	}
 
-	items := []string{"item1", "item2", "item3"}
+	items := rows("item1", "item2", "item3")
 	model.SetItems(items)
 
 	if model.SelectedIndex() != 0 {
@@ -22,7 +33,7 @@ func TestNewListModel(t *testing.T) {
 	}
 
 	emptyModel := NewListModel()
-	emptyModel.SetItems([]string{})
+	emptyModel.SetItems(rows())
 	if emptyModel.SelectedIndex() != 0 {
 		t.Errorf("SelectedIndex() with empty items = %d, want 0", emptyModel.SelectedIndex())
 	}
@@ -30,7 +41,7 @@ func TestNewListModel(t *testing.T) {
 
 func TestListModel_SetItems(t *testing.T) {
 	model := NewListModel()
-	items := []string{"item1", "item2", "item3"}
+	items := rows("item1", "item2", "item3")
 
 	model.SetItems(items)
 
@@ -41,7 +52,7 @@ func TestListModel_SetItems(t *testing.T) {
 
 func TestListModel_SelectedIndex(t *testing.T) {
 	model := NewListModel()
-	items := []string{"item1", "item2", "item3"}
+	items := rows("item1", "item2", "item3")
 	model.SetItems(items)
 
 	if model.SelectedIndex() != 0 {
@@ -66,7 +77,7 @@ func TestListModel_View(t *testing.T) {
 		t.Error("ListModel.View() returned empty string with empty items")
 	}
 
-	items := []string{"item1", "item2", "item3"}
+	items := rows("item1", "item2", "item3")
 	model.SetItems(items)
 	view2 := model.View()
 
@@ -81,7 +92,7 @@ func TestListModel_View(t *testing.T) {
 
 func TestListModel_Update(t *testing.T) {
 	model := NewListModel()
-	items := []string{"item1", "item2", "item3", "item4"}
+	items := rows("item1", "item2", "item3", "item4")
 	model.SetItems(items)
 
 	sizeMsg := tea.WindowSizeMsg{Width: 100, Height: 50}
@@ -123,7 +134,7 @@ func TestListModel_Update(t *testing.T) {
 func TestListModel_SetItems_CursorAdjustment(t *testing.T) {
 	model := NewListModel()
 
-	items1 := []string{"item1", "item2", "item3"}
+	items1 := rows("item1", "item2", "item3")
 	model.SetItems(items1)
 
 	for range len(items1) {
@@ -131,7 +142,7 @@ func TestListModel_SetItems_CursorAdjustment(t *testing.T) {
 		model, _ = model.Update(downKey)
 	}
 
-	items2 := []string{"item1", "item2"}
+	items2 := rows("item1", "item2")
 	model.SetItems(items2)
 
 	if model.SelectedIndex() >= len(items2) {
@@ -141,7 +152,7 @@ func TestListModel_SetItems_CursorAdjustment(t *testing.T) {
 
 func TestListModel_View_EmptyList(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{})
+	model.SetItems(rows())
 
 	view := model.View()
 	if view == "" {
@@ -155,7 +166,7 @@ func TestListModel_View_EmptyList(t *testing.T) {
 
 func TestListModel_Navigation_UpDown(t *testing.T) {
 	model := NewListModel()
-	items := []string{"a", "b", "c", "d"}
+	items := rows("a", "b", "c", "d")
 	model.SetItems(items)
 
 	if model.SelectedIndex() != 0 {
@@ -187,7 +198,7 @@ func TestListModel_Navigation_UpDown(t *testing.T) {
 
 func TestListModel_Navigation_HomeEnd(t *testing.T) {
 	model := NewListModel()
-	items := []string{"a", "b", "c", "d", "e"}
+	items := rows("a", "b", "c", "d", "e")
 	model.SetItems(items)
 
 	model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyDown})
@@ -208,9 +219,9 @@ func TestListModel_Navigation_HomeEnd(t *testing.T) {
 
 func TestListModel_Navigation_PageUpDown(t *testing.T) {
 	model := NewListModel()
-	items := make([]string, 50)
+	items := make([]Row, 50)
 	for i := range items {
-		items[i] = "item"
+		items[i] = Row{"item"}
 	}
 	model.SetItems(items)
 
@@ -231,9 +242,9 @@ func TestListModel_Navigation_PageUpDown(t *testing.T) {
 
 func TestListModel_ViewportScrollIndicators(t *testing.T) {
 	model := NewListModel()
-	items := make([]string, 50)
+	items := make([]Row, 50)
 	for i := range items {
-		items[i] = "item"
+		items[i] = Row{"item"}
 	}
 	model.SetItems(items)
 	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
@@ -256,7 +267,7 @@ func TestListModel_ViewportScrollIndicators(t *testing.T) {
 
 func TestListModel_VimKeys_JK(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{"a", "b", "c"})
+	model.SetItems(rows("a", "b", "c"))
 
 	// j moves down
 	model, _ = model.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
@@ -280,7 +291,7 @@ func TestListModel_VimKeys_JK(t *testing.T) {
 
 func TestListModel_UpAtZero(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{"a", "b"})
+	model.SetItems(rows("a", "b"))
 
 	model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyUp})
 	if model.SelectedIndex() != 0 {
@@ -295,7 +306,7 @@ func TestListModel_UpAtZero(t *testing.T) {
 
 func TestListModel_DownAtEnd(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{"a", "b"})
+	model.SetItems(rows("a", "b"))
 
 	model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyDown})
 	model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyDown})
@@ -309,7 +320,7 @@ func TestListModel_DownAtEnd(t *testing.T) {
 
 func TestListModel_ViewShowsSelectedIndicator(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{"first item", "second item"})
+	model.SetItems(rows("first item", "second item"))
 
 	view := model.View()
 	if !strings.Contains(view, "▶") {
@@ -321,7 +332,7 @@ func TestListModel_ViewShowsSelectedIndicator(t *testing.T) {
 
 func TestListModel_ViewShowsPosition(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{"a", "b", "c"})
+	model.SetItems(rows("a", "b", "c"))
 
 	view := model.View()
 	if !strings.Contains(view, "1/3") {
@@ -338,8 +349,8 @@ func TestListModel_ViewShowsPosition(t *testing.T) {
 // --- View shows header with column names ---
 
 func TestListModel_ViewShowsHeader(t *testing.T) {
-	model := NewListModel()
-	model.SetItems([]string{"item"})
+	model := NewListModel(Column{Title: "Type", Width: 4}, Column{Title: "Resource ID", Flex: true})
+	model.SetItems([]Row{{"RDS", "my-cluster"}})
 
 	view := model.View()
 	if !strings.Contains(view, "Type") || !strings.Contains(view, "Resource") {
@@ -347,13 +358,74 @@ func TestListModel_ViewShowsHeader(t *testing.T) {
 	}
 }
 
+// --- Columns: alignment, truncation, and hidden columns ---
+
+func TestListModel_Columns(t *testing.T) {
+	model := NewListModel(
+		Column{Width: 1, HideEmpty: true},
+		Column{Title: "Type", Width: 4},
+		Column{Title: "Resource ID", Width: 8, Flex: true},
+		Column{Title: "Size", Width: 8, Align: lipgloss.Right},
+	)
+	model.SetItems([]Row{
+		{"", "RDS", "openemr-production-aurora-cluster", "1.0 GB"},
+		{"", "EFS", "fs-1", "512.0 MB"},
+	})
+
+	row := func(text string) string {
+		lines := strings.Split(model.View(), "\n")
+		for _, line := range lines {
+			if strings.Contains(line, text) {
+				return line
+			}
+		}
+		t.Fatalf("no line with %q:\n%s", text, model.View())
+		return ""
+	}
+	// Without a window width the flexible column fits its widest cell, so
+	// the size column lines up on every row
+	column := func(line, text string) int { return lipgloss.Width(line[:strings.Index(line, text)]) }
+	if a, b := row("openemr-production-aurora-cluster"), row("fs-1"); column(a, "1.0 GB") != column(b, "512.0 MB")+2 {
+		t.Errorf("sizes should be right-aligned in one column:\n%s\n%s", a, b)
+	}
+	if strings.HasPrefix(strings.TrimLeft(row("fs-1"), " "), " ") {
+		t.Error("the empty mark column should be hidden")
+	}
+
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 40, Height: 20})
+	if line := row("openemr-"); !strings.Contains(line, "…") || strings.Contains(line, "cluster") || lipgloss.Width(line) > 40 {
+		t.Errorf("a long resource ID should be truncated to the window: %q", line)
+	}
+
+	model.SetItems([]Row{{"✓", "RDS", "fs-1", "1.0 GB"}})
+	if !strings.Contains(model.View(), "✓  RDS") {
+		t.Errorf("the mark column should show once an item has a mark:\n%s", model.View())
+	}
+}
+
+func TestListModel_SetColumns(t *testing.T) {
+	model := NewListModel(Column{Title: "Type", Width: 4}, Column{Title: "Size", Width: 8})
+	model.SetItems([]Row{{"RDS", "1.0 GB"}, {"EFS", "2.0 GB"}})
+	model.SetCursor(1)
+
+	model.SetColumns(Column{Title: "Tier", Width: 4}, Column{Title: "Type", Width: 4})
+	model.SetItems([]Row{{"warm", "RDS"}, {"cold", "EFS"}})
+	view := model.View()
+	if !strings.Contains(view, "Tier") || strings.Contains(view, "Size") || !strings.Contains(view, "cold  EFS") {
+		t.Errorf("the new columns should replace the old ones:\n%s", view)
+	}
+	if model.SelectedIndex() != 1 {
+		t.Errorf("the cursor should be kept, got %d", model.SelectedIndex())
+	}
+}
+
 // --- PageUp at start stays at 0 ---
 
 func TestListModel_PageUpAtStart(t *testing.T) {
 	model := NewListModel()
-	items := make([]string, 30)
+	items := make([]Row, 30)
 	for i := range items {
-		items[i] = "item"
+		items[i] = Row{"item"}
 	}
 	model.SetItems(items)
 	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
@@ -368,9 +440,9 @@ func TestListModel_PageUpAtStart(t *testing.T) {
 
 func TestListModel_PageDownThenPageUp(t *testing.T) {
 	model := NewListModel()
-	items := make([]string, 100)
+	items := make([]Row, 100)
 	for i := range items {
-		items[i] = "item"
+		items[i] = Row{"item"}
 	}
 	model.SetItems(items)
 	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
@@ -393,7 +465,7 @@ func TestListModel_PageDownThenPageUp(t *testing.T) {
 
 func TestListModel_HomeEndEmpty(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{})
+	model.SetItems(rows())
 
 	model, _ = model.Update(tea.KeyPressMsg{Code: 'G', Text: "G"})
 	if model.SelectedIndex() != 0 {
@@ -410,7 +482,7 @@ func TestListModel_HomeEndEmpty(t *testing.T) {
 
 func TestListModel_SetItems_ShrinkList(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{"a", "b", "c", "d", "e"})
+	model.SetItems(rows("a", "b", "c", "d", "e"))
 
 	// Navigate to end
 	model, _ = model.Update(tea.KeyPressMsg{Code: 'G', Text: "G"})
@@ -419,7 +491,7 @@ func TestListModel_SetItems_ShrinkList(t *testing.T) {
 	}
 
 	// Shrink to 2 items
-	model.SetItems([]string{"x", "y"})
+	model.SetItems(rows("x", "y"))
 	if model.SelectedIndex() != 1 {
 		t.Errorf("cursor should clamp to last item (1), got %d", model.SelectedIndex())
 	}
@@ -429,13 +501,13 @@ func TestListModel_SetItems_ShrinkList(t *testing.T) {
 
 func TestListModel_SetItems_EmptyThenPopulate(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{})
+	model.SetItems(rows())
 
 	if model.SelectedIndex() != 0 {
 		t.Error("empty list cursor should be 0")
 	}
 
-	model.SetItems([]string{"a", "b", "c"})
+	model.SetItems(rows("a", "b", "c"))
 	if model.SelectedIndex() != 0 {
 		t.Error("populated list cursor should start at 0")
 	}
@@ -445,9 +517,9 @@ func TestListModel_SetItems_EmptyThenPopulate(t *testing.T) {
 
 func TestListModel_WindowSizeMsg(t *testing.T) {
 	model := NewListModel()
-	items := make([]string, 5)
+	items := make([]Row, 5)
 	for i := range items {
-		items[i] = "item"
+		items[i] = Row{"item"}
 	}
 	model.SetItems(items)
 
@@ -460,13 +532,55 @@ func TestListModel_WindowSizeMsg(t *testing.T) {
 	}
 }
 
+func TestListModel_SetHeight(t *testing.T) {
+	model := NewListModel()
+	items := make([]Row, 150)
+	for i := range items {
+		items[i] = Row{fmt.Sprintf("item-%03d", i+1)}
+	}
+	model.SetItems(items)
+	model.SetHeight(20)
+
+	if lines := lipgloss.Height(model.View()); lines > 20 {
+		t.Errorf("the list should fit 20 lines, got %d", lines)
+	}
+
+	model, _ = model.Update(tea.KeyPressMsg{Code: 'G', Text: "G"})
+	view := model.View()
+	if !strings.Contains(view, "item-150") || !strings.Contains(view, "150/150") || lipgloss.Height(view) > 20 {
+		t.Errorf("G should scroll to the last item within the height:\n%s", view)
+	}
+
+	model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyPgUp})
+	if got, want := model.SelectedIndex(), 149-(20-listChrome); got != want {
+		t.Errorf("pgup should move a page of %d items, got index %d, want %d", 20-listChrome, got, want)
+	}
+	if !strings.Contains(model.View(), fmt.Sprintf("item-%03d", model.SelectedIndex()+1)) {
+		t.Error("the cursor should stay on screen after pgup")
+	}
+
+	// Shrinking the window keeps the cursor on screen
+	model.SetHeight(10)
+	view = model.View()
+	if !strings.Contains(view, fmt.Sprintf("item-%03d", model.SelectedIndex()+1)) || lipgloss.Height(view) > 10 {
+		t.Errorf("the cursor should stay on screen in a shorter window:\n%s", view)
+	}
+
+	// Growing it past the items leaves no empty page
+	model, _ = model.Update(tea.KeyPressMsg{Code: 'g', Text: "g"})
+	model.SetHeight(200)
+	if view := model.View(); !strings.Contains(view, "item-001") || !strings.Contains(view, "item-150") || strings.Contains(view, "more") {
+		t.Errorf("a window taller than the list should show every item:\n%s", view)
+	}
+}
+
 // --- No scroll indicators at top ---
 
 func TestListModel_NoScrollUp_AtTop(t *testing.T) {
 	model := NewListModel()
-	items := make([]string, 50)
+	items := make([]Row, 50)
 	for i := range items {
-		items[i] = "item"
+		items[i] = Row{"item"}
 	}
 	model.SetItems(items)
 	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
@@ -481,7 +595,7 @@ func TestListModel_NoScrollUp_AtTop(t *testing.T) {
 
 func TestListModel_NoScrollIndicators_SmallList(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{"a", "b", "c"})
+	model.SetItems(rows("a", "b", "c"))
 	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 50})
 
 	view := model.View()
@@ -494,7 +608,7 @@ func TestListModel_NoScrollIndicators_SmallList(t *testing.T) {
 
 func TestListModel_PageDown_EmptyList(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{})
+	model.SetItems(rows())
 	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
 
 	model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyPgDown})
@@ -507,7 +621,7 @@ func TestListModel_PageDown_EmptyList(t *testing.T) {
 
 func TestListModel_IgnoresUnknownMsg(t *testing.T) {
 	model := NewListModel()
-	model.SetItems([]string{"a", "b"})
+	model.SetItems(rows("a", "b"))
 
 	type customMsg struct{}
 	updated, cmd := model.Update(customMsg{})
@@ -518,3 +632,56 @@ func TestListModel_IgnoresUnknownMsg(t *testing.T) {
 		t.Error("unknown message should not change cursor")
 	}
 }
+
+func TestListModel_SetCursor(t *testing.T) {
+	model := NewListModel()
+	model.SetItems(rows("a", "b", "c"))
+
+	model.SetCursor(2)
+	if model.SelectedIndex() != 2 {
+		t.Errorf("SelectedIndex() = %d, want 2", model.SelectedIndex())
+	}
+	model.SetCursor(10)
+	if model.SelectedIndex() != 2 {
+		t.Errorf("out-of-range cursor should clamp to last item, got %d", model.SelectedIndex())
+	}
+	model.SetCursor(-1)
+	if model.SelectedIndex() != 0 {
+		t.Errorf("negative cursor should clamp to 0, got %d", model.SelectedIndex())
+	}
+}
+
+func TestListModel_SetCursor_ScrollsIntoView(t *testing.T) {
+	model := NewListModel()
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 13}) // 5 visible
+	items := make([]Row, 30)
+	for i := range items {
+		items[i] = Row{"item"}
+	}
+	model.SetItems(items)
+
+	model.SetCursor(20)
+	if !strings.Contains(model.View(), "more above") {
+		t.Error("list should scroll so the restored cursor is visible")
+	}
+}
+
+func TestListModel_VisibleRange(t *testing.T) {
+	model := NewListModel()
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 13}) // 5 visible
+	if start, end := model.VisibleRange(); start != 0 || end != 0 {
+		t.Errorf("empty list should show nothing, got %d-%d", start, end)
+	}
+	items := make([]Row, 30)
+	for i := range items {
+		items[i] = Row{"item"}
+	}
+	model.SetItems(items)
+	if start, end := model.VisibleRange(); start != 0 || end != 5 {
+		t.Errorf("expected 0-5, got %d-%d", start, end)
+	}
+	model.SetCursor(29)
+	if start, end := model.VisibleRange(); start != 25 || end != 30 {
+		t.Errorf("expected 25-30 at the end of the list, got %d-%d", start, end)
+	}
+}
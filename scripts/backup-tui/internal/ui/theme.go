@@ -0,0 +1,139 @@
+// Package ui provides user interface components for the backup TUI.
+// This file implements color themes: the colors of healthy, nearing, and
+// failed states, which the default theme draws in green, yellow, and red
+// and the colorblind theme in the Okabe-Ito blue, yellow, and vermillion
+// that read apart under red-green color blindness. Color never carries a
+// status alone: each Level also has its own glyph, so the views read the
+// same without color, e.g. under NO_COLOR or on a monochrome terminal.
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"regexp"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/compat"
+)
+
+// Theme is a set of status colors.
+type Theme struct {
+	Name     string
+	OK       color.Color // Healthy or done, e.g. a fresh backup
+	Warn     color.Color // Nearing a limit or in progress
+	Fail     color.Color // Failed, stale, or destructive
+	Critical color.Color // Status bar errors
+}
+
+// Themes are the themes -theme and the config file's theme choose from.
+var Themes = []Theme{
+	{
+		Name:     "default",
+		OK:       lipgloss.Color("114"),
+		Warn:     lipgloss.Color("214"),
+		Fail:     lipgloss.Color("196"),
+		Critical: lipgloss.Color("203"),
+	},
+	{
+		Name:     "colorblind",
+		OK:       compat.AdaptiveColor{Light: lipgloss.Color("#0072B2"), Dark: lipgloss.Color("#56B4E9")},
+		Warn:     compat.AdaptiveColor{Light: lipgloss.Color("#E69F00"), Dark: lipgloss.Color("#F0E442")},
+		Fail:     lipgloss.Color("#D55E00"),
+		Critical: lipgloss.Color("#D55E00"),
+	},
+}
+
+// The current theme's colors, set by SetTheme.
+var (
+	ColorOK       = Themes[0].OK
+	ColorWarn     = Themes[0].Warn
+	ColorFail     = Themes[0].Fail
+	ColorCritical = Themes[0].Critical
+)
+
+// SetTheme switches the status colors to the theme named name. An empty
+// name keeps the current theme.
+func SetTheme(name string) error {
+	if name == "" {
+		return nil
+	}
+	for _, t := range Themes {
+		if t.Name == name {
+			ColorOK, ColorWarn, ColorFail, ColorCritical = t.OK, t.Warn, t.Fail, t.Critical
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown theme %q (want one of %s)", name, strings.Join(ThemeNames(), ", "))
+}
+
+// ThemeNames returns the names of Themes.
+func ThemeNames() []string {
+	names := make([]string, len(Themes))
+	for i, t := range Themes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Level is how healthy a status is.
+type Level int
+
+const (
+	LevelOK   Level = iota // Within its target
+	LevelWarn              // Nearing its target
+	LevelFail              // Past its target
+)
+
+// Color returns the current theme's color for the level.
+func (l Level) Color() color.Color {
+	switch l {
+	case LevelWarn:
+		return ColorWarn
+	case LevelFail:
+		return ColorFail
+	default:
+		return ColorOK
+	}
+}
+
+// Glyph returns the level's mark: a full, half, or empty circle, so the
+// level reads without color.
+func (l Level) Glyph() string {
+	switch l {
+	case LevelWarn:
+		return "◐"
+	case LevelFail:
+		return "○"
+	default:
+		return "●"
+	}
+}
+
+// Dot returns the level's glyph in its color.
+func (l Level) Dot() string {
+	return lipgloss.NewStyle().Foreground(l.Color()).Render(l.Glyph())
+}
+
+// ansiPattern matches the SGR escapes lipgloss styles text with.
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;:]*m")
+
+// StripANSI returns s without its colors, as a monochrome terminal or a
+// color-blind operator sees it.
+func StripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// FreshnessLevel returns the level of a backup's age with no RPO target:
+// OK under 24h, Warn under 7 days, and Fail when older.
+func FreshnessLevel(t time.Time) Level {
+	switch age := time.Since(t); {
+	case age < 24*time.Hour:
+		return LevelOK
+	case age < 7*24*time.Hour:
+		return LevelWarn
+	default:
+		return LevelFail
+	}
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetTheme(t *testing.T) {
+	defer func() { _ = SetTheme("default") }()
+
+	if err := SetTheme("colorblind"); err != nil {
+		t.Fatalf("SetTheme(colorblind): %v", err)
+	}
+	if ColorOK != Themes[1].OK || ColorFail != Themes[1].Fail {
+		t.Error("SetTheme should switch the status colors")
+	}
+	if err := SetTheme(""); err != nil || ColorOK != Themes[1].OK {
+		t.Error("an empty theme should keep the current one")
+	}
+	if err := SetTheme("sepia"); err == nil {
+		t.Error("an unknown theme should be refused")
+	}
+	if ColorOK != Themes[1].OK {
+		t.Error("a refused theme should keep the current one")
+	}
+}
+
+func TestLevel_GlyphsWithoutColor(t *testing.T) {
+	defer func() { _ = SetTheme("default") }()
+
+	levels := []Level{LevelOK, LevelWarn, LevelFail}
+	for _, theme := range ThemeNames() {
+		if err := SetTheme(theme); err != nil {
+			t.Fatal(err)
+		}
+		seen := map[string]bool{}
+		for _, l := range levels {
+			glyph := StripANSI(l.Dot())
+			if glyph != l.Glyph() {
+				t.Errorf("%s theme: level %d dot without color is %q, want %q", theme, l, glyph, l.Glyph())
+			}
+			seen[glyph] = true
+		}
+		if len(seen) != len(levels) {
+			t.Errorf("%s theme: each level should have its own glyph, got %v", theme, seen)
+		}
+	}
+}
+
+func TestFreshnessLevel(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		age  time.Duration
+		want Level
+	}{
+		{time.Hour, LevelOK},
+		{25 * time.Hour, LevelWarn},
+		{8 * 24 * time.Hour, LevelFail},
+	}
+	for _, tt := range tests {
+		if got := FreshnessLevel(now.Add(-tt.age)); got != tt.want {
+			t.Errorf("FreshnessLevel(%v ago) = %d, want %d", tt.age, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Package webhook posts workflow step transitions to the webhooks in the
+// config file, so incident tooling (PagerDuty, Opsgenie, a chat channel)
+// can follow a restore or DR run as it happens.
+package webhook
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+)
+
+// timeout bounds each POST, so a slow endpoint never holds up a workflow.
+const timeout = 10 * time.Second
+
+// Event is the JSON document posted for a step transition.
+type Event struct {
+	Event      string    `json:"event"` // config.EventStarted, EventCompleted, or EventFailed
+	Time       time.Time `json:"time"`
+	Workflow   string    `json:"workflow"`             // e.g. "restore-chain", "dr-copy", or the job kind of a single job
+	WorkflowID string    `json:"workflowId,omitempty"` // Set for saved workflows
+	Stack      string    `json:"stack,omitempty"`
+	Region     string    `json:"region"`
+	Vault      string    `json:"vault"`
+	Step       Step      `json:"step"`
+}
+
+// Step is the step an Event reports on.
+type Step struct {
+	Index            int    `json:"index"` // 1-based position in the workflow
+	Total            int    `json:"total"` // Steps in the workflow
+	Kind             string `json:"kind"`  // e.g. "restore", "copy", "export", "clone"
+	ResourceType     string `json:"resourceType,omitempty"`
+	ResourceID       string `json:"resourceId,omitempty"`
+	RecoveryPointARN string `json:"recoveryPointArn,omitempty"`
+	JobID            string `json:"jobId,omitempty"`   // Empty when the step failed to start
+	State            string `json:"state,omitempty"`   // AWS job state, e.g. "COMPLETED" or "ABORTED"
+	Message          string `json:"message,omitempty"` // Why the step failed
+}
+
+// hook is a webhook with its secrets revealed.
+type hook struct {
+	name    string
+	url     string
+	headers map[string]string
+	config  config.Webhook
+}
+
+// Notifier posts events to the configured webhooks. A nil Notifier posts
+// nothing.
+type Notifier struct {
+	hooks  []hook
+	client *http.Client
+}
+
+// New returns a Notifier for hooks, reading their URLs and headers from the
+// OS keyring where the config references it, or nil if there are none.
+func New(hooks []config.Webhook) (*Notifier, error) {
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+	n := &Notifier{client: &http.Client{Timeout: timeout}}
+	for i, w := range hooks {
+		h := hook{name: cmp.Or(w.Name, strconv.Itoa(i)), headers: make(map[string]string, len(w.Headers)), config: w}
+		var err error
+		if h.url, err = w.URL.Reveal(); err != nil {
+			return nil, fmt.Errorf("webhook %s: %w", h.name, err)
+		}
+		for name, value := range w.Headers {
+			if h.headers[name], err = value.Reveal(); err != nil {
+				return nil, fmt.Errorf("webhook %s: header %s: %w", h.name, name, err)
+			}
+		}
+		n.hooks = append(n.hooks, h)
+	}
+	return n, nil
+}
+
+// Send posts e to each webhook that receives its event and returns the
+// failures, naming the webhook but never its URL.
+func (n *Notifier) Send(ctx context.Context, e Event) error {
+	if n == nil {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+	var errs []error
+	for _, h := range n.hooks {
+		if !h.config.Wants(e.Event) {
+			continue
+		}
+		if err := n.post(ctx, h, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", h.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// post sends body to h and reports a failed request or a non-2xx response.
+func (n *Notifier) post(ctx context.Context, h hook, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("invalid URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "backup-tui")
+	for name, value := range h.headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		// The error quotes the URL, which may hold a token
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
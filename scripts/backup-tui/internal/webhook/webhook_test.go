@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+)
+
+func TestNotifier_Send(t *testing.T) {
+	var got []Event
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var e Event
+		if err := json.Unmarshal(data, &e); err != nil {
+			t.Errorf("body is not an event: %v", err)
+		}
+		got = append(got, e)
+		auth = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	n, err := New([]config.Webhook{
+		{URL: config.Secret{Value: srv.URL}, Headers: map[string]config.Secret{"Authorization": {Value: "Token t0k3n"}}},
+		{URL: config.Secret{Value: srv.URL + "/failures"}, Events: []string{config.EventFailed}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := Event{Event: config.EventStarted, Workflow: "restore-chain", Region: "us-west-2", Vault: "vault",
+		Step: Step{Index: 1, Total: 2, Kind: "restore", ResourceType: "RDS", JobID: "job-1"}}
+	if err := n.Send(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Step.JobID != "job-1" || got[0].Time.IsZero() || auth != "Token t0k3n" {
+		t.Fatalf("only the webhook receiving started events should get it, with its headers, got %+v (auth %q)", got, auth)
+	}
+
+	e.Event = config.EventFailed
+	if err := n.Send(context.Background(), e); err != nil || len(got) != 3 {
+		t.Errorf("failed events should reach both webhooks, got %d, %v", len(got), err)
+	}
+}
+
+func TestNotifier_SendErrorHidesURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	n, _ := New([]config.Webhook{{Name: "pager", URL: config.Secret{Value: srv.URL + "/routing-key-secret"}}})
+	err := n.Send(context.Background(), Event{Event: config.EventCompleted})
+	if err == nil || !strings.Contains(err.Error(), "webhook pager") || !strings.Contains(err.Error(), "403") {
+		t.Errorf("a rejected event should be reported, got %v", err)
+	}
+
+	srv.Close()
+	err = n.Send(context.Background(), Event{Event: config.EventCompleted})
+	if err == nil || strings.Contains(err.Error(), "routing-key-secret") {
+		t.Errorf("a failed request should be reported without the URL, got %v", err)
+	}
+}
+
+func TestNotifier_Nil(t *testing.T) {
+	n, err := New(nil)
+	if n != nil || err != nil {
+		t.Fatalf("no webhooks should give a nil Notifier, got %v, %v", n, err)
+	}
+	if err := n.Send(context.Background(), Event{Event: config.EventStarted}); err != nil {
+		t.Errorf("a nil Notifier should send nothing, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/report"
+)
+
+// runInventory implements "backup-tui inventory <subcommand>".
+func runInventory(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "Usage: backup-tui inventory export [-type RDS,EFS] [-output file.csv] [options]")
+		fmt.Fprintln(os.Stderr, "       backup-tui inventory diff [-type RDS,EFS] [-format markdown|json] [-output file] [options] previous.csv")
+		return 2
+	}
+	switch args[0] {
+	case "export":
+		return runInventoryExport(args[1:])
+	case "diff":
+		return runInventoryDiff(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown inventory command %q. Run 'backup-tui -help' for usage.\n", args[0])
+		return 2
+	}
+}
+
+// runInventoryExport implements "backup-tui inventory export": it writes
+// every recovery point in the vault to CSV, to keep for a later
+// "inventory diff" or to open in a spreadsheet.
+//
+// Exit codes: 0 on success, 1 when the vault could not be listed or the
+// file written, 2 for usage errors.
+func runInventoryExport(args []string) int {
+	fs := flag.NewFlagSet("inventory export", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	resourceType := fs.String("type", "", "AWS Backup resource types to export, e.g. RDS or RDS,EFS (empty for all)")
+	output := fs.String("output", "", "Write the CSV to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	types, err := aws.ParseResourceTypes(*resourceType)
+	if err != nil {
+		printError(fmt.Errorf("invalid -type: %w", err))
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	_, vaultName, points, err := listInventory(ctx, conn, types)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	var buf bytes.Buffer
+	if err := report.WriteInventoryCSV(&buf, points); err != nil {
+		printError(err)
+		return 1
+	}
+
+	if *output == "" {
+		_, _ = os.Stdout.Write(buf.Bytes())
+		return 0
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write inventory: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d recovery point(s) of vault %s to %s\n", len(points), vaultName, *output)
+	return 0
+}
+
+// runInventoryDiff implements "backup-tui inventory diff": it compares the
+// vault with a previous "inventory export" and reports the recovery points
+// created since, those that expired or were deleted (flagging deletions
+// before their scheduled date), and those whose size changed, as markdown
+// with a sign-off section for compliance attestations, or JSON.
+//
+// Exit codes: 0 on success, 1 when the previous export could not be read or
+// the vault listed, 2 for usage errors.
+func runInventoryDiff(args []string) int {
+	fs := flag.NewFlagSet("inventory diff", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	resourceType := fs.String("type", "", "AWS Backup resource types to compare, e.g. RDS or RDS,EFS (empty for all; match the export)")
+	format := fs.String("format", "markdown", "Output format: markdown or json")
+	output := fs.String("output", "", "Write the reconciliation to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Error: specify the previous export, e.g. backup-tui inventory diff inventory-2026-03.csv")
+		return 2
+	}
+	renderer, err := report.NewRenderer(*format)
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	types, err := aws.ParseResourceTypes(*resourceType)
+	if err != nil {
+		printError(fmt.Errorf("invalid -type: %w", err))
+		return 2
+	}
+
+	previousPath := fs.Arg(0)
+	f, err := os.Open(previousPath)
+	if err != nil {
+		printError(fmt.Errorf("failed to read the previous export: %w", err))
+		return 1
+	}
+	previous, err := report.ReadInventoryCSV(f)
+	_ = f.Close()
+	if err != nil {
+		printError(fmt.Errorf("%s: %w", previousPath, err))
+		return 1
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, vaultName, points, err := listInventory(ctx, conn, types)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	r := report.Reconcile(previous, points, time.Now())
+	r.Stack, r.Vault, r.Region, r.Previous = env.stackName, vaultName, env.region.Region, filepath.Base(previousPath)
+
+	data, err := renderer.Render(r)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	if *output == "" {
+		_, _ = os.Stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(*output, data, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write reconciliation: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Wrote reconciliation to %s (%d new, %d expired or deleted, %d changed size)\n",
+		*output, len(r.New), len(r.Removed), len(r.Resized))
+	return 0
+}
+
+// listInventory connects and lists the vault's recovery points of types
+// (all when empty).
+func listInventory(ctx context.Context, conn connectOptions, types []string) (*environment, string, []aws.RecoveryPoint, error) {
+	env, err := connect(ctx, conn)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	vaultName := conn.vault
+	if vaultName == "" {
+		if vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName); err != nil {
+			return nil, "", nil, err
+		}
+	}
+	points, err := env.client.ListRecoveryPoints(ctx, vaultName, types...)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return env, vaultName, points, nil
+}
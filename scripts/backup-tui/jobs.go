@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/report"
+)
+
+// runJobs implements "backup-tui jobs <subcommand>".
+func runJobs(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "Usage: backup-tui jobs report [-window 7d] [-format markdown|json] [-output file] [options]")
+		return 2
+	}
+	switch args[0] {
+	case "report":
+		return runJobsReport(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown jobs command %q. Run 'backup-tui -help' for usage.\n", args[0])
+		return 2
+	}
+}
+
+// runJobsReport implements "backup-tui jobs report": it summarizes backup,
+// restore, and copy job success rates, average durations, and failures over
+// a window, as markdown or JSON.
+//
+// Exit codes: 0 on success, 1 when the report could not be produced, 2 for
+// usage errors. Failed jobs do not change the exit code; the report is
+// meant for review, not alerting.
+func runJobsReport(args []string) int {
+	fs := flag.NewFlagSet("jobs report", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	window := fs.String("window", "7d", "How far back to report, e.g. 7d, 30d, or 36h")
+	format := fs.String("format", "markdown", "Output format: markdown or json")
+	output := fs.String("output", "", "Write the report to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	span, err := parseWindow(*window)
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	renderer, err := report.NewRenderer(*format)
+	if err != nil {
+		printError(err)
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+
+	until := time.Now()
+	since := until.Add(-span)
+	records, err := env.client.ListJobs(ctx, vaultName, since)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	r := report.BuildJobs(records, since, until)
+	r.Stack, r.Vault, r.Region = env.stackName, vaultName, env.region.Region
+
+	data, err := renderer.Render(r)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	if *output == "" {
+		_, _ = os.Stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(*output, data, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write report: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Wrote report to %s\n", *output)
+	return 0
+}
+
+// parseWindow parses a report window: a number of days ("7d") or a Go
+// duration ("36h").
+func parseWindow(s string) (time.Duration, error) {
+	return parseSpan("-window", s)
+}
+
+// parseSpan parses the value of flag name as a number of days ("7d") or a
+// Go duration ("36h").
+func parseSpan(name, s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: expected e.g. 7d or 36h", name, s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid %s %q: expected e.g. 7d or 36h", name, s)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s must be positive, got %q", name, s)
+	}
+	return d, nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// latestOutput is the JSON document printed by "backup-tui latest -output json".
+type latestOutput struct {
+	Stack       string            `json:"stack"`
+	Vault       string            `json:"vault"`
+	Region      string            `json:"region"`
+	GeneratedAt time.Time         `json:"generatedAt"`
+	At          *time.Time        `json:"at,omitempty"`   // The moment of -at
+	PITR        *pitrWindow       `json:"pitr,omitempty"` // With -at, the live cluster's point-in-time restore window
+	Resources   []aws.LatestPoint `json:"resources"`
+}
+
+// pitrWindow is the window the stack's live Aurora cluster can be restored
+// to any moment of from its automated backups, and whether it covers the
+// moment asked for.
+type pitrWindow struct {
+	ClusterID string    `json:"clusterId"`
+	Earliest  time.Time `json:"earliest"`
+	Latest    time.Time `json:"latest"`
+	Covers    bool      `json:"covers"`
+}
+
+// runLatest implements "backup-tui latest": it prints the latest restorable
+// point of each resource in the vault (newest completed, warm storage, not
+// failed by a restore test), as text or as JSON for other tooling. With -at
+// it prints the restorable point closest to but not after that moment
+// instead, and whether a point-in-time restore of the live cluster reaches
+// it.
+//
+// Exit codes: 0 when every resource has a restorable point, 1 when one has
+// none or the lookup failed, 2 for usage errors.
+func runLatest(args []string) int {
+	fs := flag.NewFlagSet("latest", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	output := fs.String("output", "text", "Output format: text or json")
+	atFlag := fs.String("at", "", "Pick the restorable point closest to but not after this moment, e.g. 2026-01-15T03:00Z")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "Error: -output must be text or json, got %q\n", *output)
+		return 2
+	}
+	var at time.Time
+	if *atFlag != "" {
+		var err error
+		if at, err = aws.ParseMoment(*atFlag); err != nil {
+			printError(err)
+			return 2
+		}
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+
+	var latest []aws.LatestPoint
+	var pitr *pitrWindow
+	if at.IsZero() {
+		latest, err = env.client.LatestRestorablePoints(ctx, vaultName)
+	} else {
+		latest, err = env.client.RestorablePointsAt(ctx, vaultName, at)
+		pitr = lookupPITR(ctx, env, latest, at)
+	}
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	if *output == "json" {
+		doc := latestOutput{
+			Stack:       env.stackName,
+			Vault:       vaultName,
+			Region:      env.region.Region,
+			GeneratedAt: time.Now().UTC(),
+			PITR:        pitr,
+			Resources:   latest,
+		}
+		if !at.IsZero() {
+			at := at.UTC()
+			doc.At = &at
+		}
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to encode output: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+	} else if at.IsZero() {
+		printLatest(os.Stdout, vaultName, latest)
+	} else {
+		printRestorableAt(os.Stdout, vaultName, at, latest, pitr)
+	}
+
+	for _, p := range latest {
+		if !p.Found() {
+			return 1
+		}
+	}
+	return 0
+}
+
+// printLatest writes the latest restorable points as text.
+func printLatest(out io.Writer, vaultName string, latest []aws.LatestPoint) {
+	fmt.Fprintf(out, "Latest restorable backups in vault %s:\n\n", vaultName)
+	if len(latest) == 0 {
+		fmt.Fprintln(out, "  No backups in the vault.")
+		return
+	}
+	for _, p := range latest {
+		if !p.Found() {
+			fmt.Fprintf(out, "  ✗ %-4s %s\n         no completed backup in warm storage\n", p.ResourceType, p.ResourceID)
+			continue
+		}
+		note := ""
+		if p.Validated {
+			note = " (restore tested)"
+		}
+		fmt.Fprintf(out, "  ✓ %-4s %s  %s%s\n         %s\n", p.ResourceType, p.ResourceID, p.CreatedAt.UTC().Format("2006-01-02 15:04 MST"), note, p.RecoveryPointARN)
+		if p.Skipped > 0 {
+			fmt.Fprintf(out, "         %d newer backup(s) skipped: not completed, in cold storage, or failed a restore test\n", p.Skipped)
+		}
+	}
+}
+
+// printRestorableAt writes the restorable points closest to but not after
+// at as text, with the live cluster's point-in-time restore window.
+func printRestorableAt(out io.Writer, vaultName string, at time.Time, latest []aws.LatestPoint, pitr *pitrWindow) {
+	fmt.Fprintf(out, "Restorable backups in vault %s at or before %s:\n\n", vaultName, at.UTC().Format("2006-01-02 15:04 MST"))
+	if len(latest) == 0 {
+		fmt.Fprintln(out, "  No backups in the vault.")
+	}
+	for _, p := range latest {
+		if !p.Found() {
+			fmt.Fprintf(out, "  ✗ %-4s %s\n         no completed backup in warm storage before then\n", p.ResourceType, p.ResourceID)
+			continue
+		}
+		fmt.Fprintf(out, "  ✓ %-4s %s  %s (%s before)\n         %s\n", p.ResourceType, p.ResourceID,
+			p.CreatedAt.UTC().Format("2006-01-02 15:04 MST"), strings.TrimSuffix(at.Sub(p.CreatedAt).Round(time.Minute).String(), "0s"), p.RecoveryPointARN)
+		if p.Skipped > 0 {
+			fmt.Fprintf(out, "         %d backup(s) closer to then skipped: not completed, in cold storage, or failed a restore test\n", p.Skipped)
+		}
+	}
+	printPITR(out, pitr)
+}
+
+// printPITR writes whether a point-in-time restore of the live cluster
+// reaches the moment, when that is known.
+func printPITR(out io.Writer, pitr *pitrWindow) {
+	if pitr == nil {
+		return
+	}
+	window := fmt.Sprintf("%s to %s", pitr.Earliest.UTC().Format("2006-01-02 15:04"), pitr.Latest.UTC().Format("2006-01-02 15:04 MST"))
+	if pitr.Covers {
+		fmt.Fprintf(out, "\nAurora cluster %s can also be restored to exactly that moment (point-in-time restore covers %s).\n", pitr.ClusterID, window)
+	} else {
+		fmt.Fprintf(out, "\nAurora cluster %s cannot be restored to that moment by point-in-time restore (it covers %s).\n", pitr.ClusterID, window)
+	}
+}
+
+// lookupPITR returns the live cluster's point-in-time restore window for
+// at, or nil when no RDS resource is among latest or the window cannot be
+// looked up, which is reported as a warning.
+func lookupPITR(ctx context.Context, env *environment, latest []aws.LatestPoint, at time.Time) *pitrWindow {
+	if !slices.ContainsFunc(latest, func(p aws.LatestPoint) bool { return p.ResourceType == "RDS" }) {
+		return nil
+	}
+	h, err := env.client.GetClusterHealth(ctx, env.stackName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: the cluster's point-in-time restore window is unknown: %v\n", err)
+		return nil
+	}
+	if h.EarliestRestorable.IsZero() {
+		return nil
+	}
+	return &pitrWindow{ClusterID: h.ClusterID, Earliest: h.EarliestRestorable.UTC(), Latest: h.LatestRestorable.UTC(), Covers: h.CoversPITR(at)}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/api"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// listOutput is the JSON document printed by "backup-tui list -output json".
+// Recovery points have the fields of the JSON API's.
+type listOutput struct {
+	Stack          string              `json:"stack"`
+	Vault          string              `json:"vault"`
+	Region         string              `json:"region"`
+	GeneratedAt    time.Time           `json:"generatedAt"`
+	RecoveryPoints []api.RecoveryPoint `json:"recoveryPoints"`
+}
+
+// runList implements "backup-tui list": it prints the recovery points in
+// the vault, newest first, as the TUI lists them, for cron jobs and CI
+// pipelines without a terminal.
+//
+// Exit codes: 0 on success, 1 when the vault could not be listed, 2 for
+// usage errors.
+func runList(args []string) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	resourceType := fs.String("type", "", "AWS Backup resource types to list, e.g. RDS or RDS,EFS (empty for all)")
+	limit := fs.Int("limit", 0, "List at most this many recovery points, newest first (0 for all)")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !checkOutput(*output) {
+		return 2
+	}
+	types, err := aws.ParseResourceTypes(*resourceType)
+	if err != nil {
+		printFailure(*output, fmt.Errorf("invalid -type: %w", err))
+		return 2
+	}
+	if *limit < 0 {
+		printFailure(*output, fmt.Errorf("-limit must not be negative, got %d", *limit))
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printFailure(*output, err)
+		return 1
+	}
+
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printFailure(*output, err)
+			return 1
+		}
+	}
+
+	points, err := env.client.ListRecoveryPoints(ctx, vaultName, types...)
+	if err != nil {
+		printFailure(*output, err)
+		return 1
+	}
+	if *limit > 0 && len(points) > *limit {
+		points = points[:*limit]
+	}
+
+	if *output == "json" {
+		out := listOutput{
+			Stack:          env.stackName,
+			Vault:          vaultName,
+			Region:         env.region.Region,
+			GeneratedAt:    time.Now().UTC(),
+			RecoveryPoints: make([]api.RecoveryPoint, len(points)),
+		}
+		for i, p := range points {
+			out.RecoveryPoints[i] = api.NewRecoveryPoint(p)
+		}
+		return printJSON(out)
+	}
+	printRecoveryPoints(os.Stdout, vaultName, points)
+	return 0
+}
+
+// printRecoveryPoints writes recovery points as text, one per line, with
+// the ARN last so it can be cut out for "backup-tui restore".
+func printRecoveryPoints(out io.Writer, vaultName string, points []aws.RecoveryPoint) {
+	fmt.Fprintf(out, "Recovery points in vault %s: %d\n\n", vaultName, len(points))
+	for _, p := range points {
+		fmt.Fprintf(out, "%-4s  %-30s  %s  %-9s  %9s  %s\n", p.ResourceType, p.ResourceID,
+			p.CreationDate.UTC().Format("2006-01-02 15:04 MST"), p.Status, formatBytes(p.BackupSizeInBytes), p.RecoveryPointARN)
+	}
+}
+
+// formatBytes converts a byte count to a human-readable string.
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
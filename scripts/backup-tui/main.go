@@ -7,28 +7,73 @@
 package main
 
 import (
+	"bufio"
+	"cmp"
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
+	"os/exec"
 	"os/signal"
+	"slices"
 	"strings"
 	"syscall"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/api"
 	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/app"
 	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/deeplink"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/ui"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/webhook"
 )
 
 func main() {
+	// A deep link, e.g. passed by the OS's URI handler, opens its context
+	if len(os.Args) > 1 && deeplink.IsLink(os.Args[1]) {
+		os.Args = slices.Insert(os.Args, 1, "-link")
+	}
+
+	// Subcommands (e.g. "backup-tui doctor") run without the TUI
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runSubcommand(os.Args[1], os.Args[2:]))
+	}
+
 	// Parse command-line arguments
+	var conn connectOptions
+	conn.register(flag.CommandLine)
 	var (
-		stackName    = flag.String("stack", "", "CloudFormation stack name (auto-discovered if not provided)")
-		vaultName    = flag.String("vault", "", "Backup vault name (auto-discovered if not provided)")
-		region       = flag.String("region", "us-west-2", "AWS region")
-		resourceType = flag.String("type", "", "Resource type to filter (RDS or EFS, empty for all)")
+		resourceType = flag.String("type", "", "AWS Backup resource types to filter, e.g. RDS or RDS,EFS (empty for all)")
+		recordPath   = flag.String("record-fixtures", "", "Record the stack and vault as a -simulate fixture file and exit")
+		statusAddr   = flag.String("status-addr", "", "Serve the session's job state as JSON on this address, e.g. 127.0.0.1:8099")
+		apiAddr      = flag.String("api", "", "Serve the JSON API for automation on this address instead of starting the TUI, e.g. 127.0.0.1:8098")
+		apiRemote    = flag.Bool("api-allow-remote", false, "Allow -api to listen on an address other than loopback")
+		statusRemote = flag.Bool("status-allow-remote", false, "Allow -status-addr to listen on an address other than loopback")
+		noCache      = flag.Bool("no-cache", false, "Keep no local state: restores are not saved to or resumed from the job history, and the sort order, filter, and view are not remembered")
+		override     = flag.Bool("override-freeze", false, "Allow restores during the config file's change freeze windows")
+		plain        = flag.Bool("plain", false, "Draw the backup list, details, and jobs as unstyled text, e.g. for screen readers")
+		theme        = flag.String("theme", "", "Status colors: "+strings.Join(ui.ThemeNames(), ", ")+" (default: the config file's theme, or default)")
+		apiBudget    = flag.Int("api-budget", 0, "Pause background refresh once the session has made this many AWS API calls (0 for no budget)")
+		openView     = flag.String("open", "", "Launch into a view: "+strings.Join(app.StartViews, ", "))
+		openResource = flag.String("resource", "", "Launch into the detail view of a resource's latest restorable backup, by resource ID or ARN")
+		openARN      = flag.String("arn", "", "Launch into the detail view of the recovery point with this ARN")
+		lockAfter    = flag.Duration("lock-after", 0, "Lock the screen after this long without a key press, e.g. 15m; shortens but cannot lengthen the config file's idleLock")
+		link         = flag.String("link", "", "Open the region, stack, vault, and view or backup of a backup-tui:// deep link; other flags take precedence")
 		showHelp     = flag.Bool("help", false, "Show help message")
 	)
+	var export aws.ExportDestination
+	flag.StringVar(&export.Bucket, "export-bucket", "", "S3 bucket for snapshot exports (x in the detail view)")
+	flag.StringVar(&export.Prefix, "export-prefix", "", "Key prefix for snapshot exports within -export-bucket")
+	flag.StringVar(&export.IAMRoleARN, "export-role", "", "IAM role ARN RDS assumes to write snapshot exports")
+	flag.StringVar(&export.KMSKeyID, "export-kms-key", "", "KMS key that encrypts snapshot exports")
 	flag.Parse()
 
 	// Show help and exit if requested
@@ -37,56 +82,175 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Create context with cancellation for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
+	resourceTypes, err := aws.ParseResourceTypes(*resourceType)
+	if err != nil {
+		printError(fmt.Errorf("invalid -type: %w", err))
+		os.Exit(2)
+	}
+
+	if *link != "" {
+		l, err := deeplink.Parse(*link)
+		if err == nil && l.Region != "" && !aws.ValidRegion(l.Region) {
+			err = fmt.Errorf("region %q: %w", l.Region, aws.ErrInvalidRegion)
+		}
+		if err != nil {
+			printError(fmt.Errorf("invalid -link: %w", err))
+			os.Exit(2)
+		}
+		conn.region, conn.stack, conn.vault = cmp.Or(conn.region, l.Region), cmp.Or(conn.stack, l.Stack), cmp.Or(conn.vault, l.Vault)
+		if *openView == "" && *openResource == "" && *openARN == "" {
+			*openView, *openResource, *openARN = l.View, l.Resource, l.ARN
+		}
+	}
+	if *openView != "" {
+		if err := app.ValidateStartView(*openView); err != nil {
+			printError(fmt.Errorf("invalid -open: %w", err))
+			os.Exit(2)
+		}
+	}
+	if *apiAddr != "" && !*apiRemote && !loopbackAddr(*apiAddr) {
+		printError(fmt.Errorf("-api %s is reachable from other machines; listen on 127.0.0.1 or localhost, or add -api-allow-remote", *apiAddr))
+		os.Exit(2)
+	}
+	if *statusAddr != "" && !*statusRemote && !loopbackAddr(*statusAddr) {
+		printError(fmt.Errorf("-status-addr %s is reachable from other machines; listen on 127.0.0.1 or localhost, or add -status-allow-remote", *statusAddr))
+		os.Exit(2)
+	}
+	if len(slices.DeleteFunc([]string{*openView, *openResource, *openARN}, func(s string) bool { return s == "" })) > 1 {
+		printError(errors.New("use only one of -open, -resource, and -arn"))
+		os.Exit(2)
+	}
+
+	if err := ui.SetTheme(*theme); err != nil {
+		printError(fmt.Errorf("invalid -theme: %w", err))
+		os.Exit(2)
+	}
+
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printError(err)
+		os.Exit(1)
+	}
+	// -theme takes precedence over the config file's theme
+	if *theme == "" {
+		if err := ui.SetTheme(cfg.Theme); err != nil {
+			printError(fmt.Errorf("invalid config: theme: %w", err))
+			os.Exit(1)
+		}
+	}
+
+	// The API does not start without its token
+	var apiToken string
+	if *apiAddr != "" {
+		if apiToken, err = cfg.BearerToken(); err != nil {
+			printError(fmt.Errorf("-api requires a bearer token: %w", err))
+			os.Exit(1)
+		}
+	}
+
+	ctx, cancel := signalContext()
 	defer cancel()
 
-	// Handle interrupt signals (Ctrl+C, SIGTERM) for graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigChan
-		cancel()
-	}()
+	env, err := connectClient(ctx, conn)
+	if err == nil {
+		err = env.discoverStack(ctx)
+	}
+	// With several stacks and no -stack, the TUI asks which one to open;
+	// recording and the API need it up front
+	var several *aws.MultipleStacksError
+	if errors.As(err, &several) && *recordPath == "" && *apiAddr == "" {
+		err = nil
+	}
+	if err != nil {
+		printError(err)
+		cancel() // Cancel context before exiting
+		//nolint:gocritic // exitAfterDefer: we explicitly call cancel() before os.Exit
+		os.Exit(1)
+	}
 
-	// Auto-discover stack name if not provided
-	finalStackName := *stackName
-	if finalStackName == "" {
-		// Create a temporary AWS client for stack discovery
-		backupClient, err := aws.NewBackupClient(ctx, *region)
-		if err != nil {
-			errMsg := err.Error()
-			fmt.Fprintf(os.Stderr, "Error: Failed to create AWS client: %v\n", err)
-			if strings.Contains(errMsg, "credentials") || strings.Contains(errMsg, "NoCredentialProviders") ||
-				strings.Contains(errMsg, "EC2RoleRequestError") || strings.Contains(errMsg, "SharedCredsLoad") {
-				fmt.Fprintf(os.Stderr, "\nAWS credentials are required to launch the TUI.\n")
-				fmt.Fprintf(os.Stderr, "Configure AWS credentials using one of:\n")
-				fmt.Fprintf(os.Stderr, "  - Environment variables: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY\n")
-				fmt.Fprintf(os.Stderr, "  - AWS credentials file: ~/.aws/credentials (run 'aws configure')\n")
-				fmt.Fprintf(os.Stderr, "  - IAM role: if running on EC2/ECS, ensure instance/task role has permissions\n")
-			} else {
-				fmt.Fprintf(os.Stderr, "Please ensure AWS credentials are configured.\n")
-			}
+	// Record fixtures for later -simulate sessions instead of starting the TUI
+	if *recordPath != "" {
+		if err := recordFixtures(ctx, env.client, env.stackName, conn.vault, *recordPath); err != nil {
+			printError(err)
 			cancel() // Cancel context before exiting
 			//nolint:gocritic // exitAfterDefer: we explicitly call cancel() before os.Exit
 			os.Exit(1)
 		}
+		fmt.Fprintf(os.Stderr, "Recorded fixtures to %s\n", *recordPath)
+		return
+	}
 
-		discoveredStack, err := backupClient.DiscoverStackName(ctx)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Failed to auto-discover CloudFormation stack: %v\n", err)
-			fmt.Fprintf(os.Stderr, "\nPlease specify a stack name using the -stack flag:\n")
-			fmt.Fprintln(os.Stderr, "  backup-tui -stack YourStackName")
+	// Serve the JSON API for automation instead of starting the TUI
+	if *apiAddr != "" {
+		apiOpts := api.Options{Vault: conn.vault, Token: apiToken, Config: cfg, OverrideFreeze: *override}
+		if !env.client.Simulated() && !*noCache {
+			apiOpts.HistoryPath, _ = store.DefaultHistoryPath()
+			apiOpts.LocksPath, _ = store.DefaultLocksPath()
+		}
+		code := serveAPI(ctx, *apiAddr, env, apiOpts)
+		cancel() // Cancel context before exiting
+		//nolint:gocritic // exitAfterDefer: we explicitly call cancel() before os.Exit
+		os.Exit(code)
+	}
+
+	// -lock-after can tighten the config file's idle lock but not loosen it
+	idleLock := time.Duration(cfg.IdleLock)
+	if *lockAfter > 0 && (idleLock == 0 || *lockAfter < idleLock) {
+		idleLock = *lockAfter
+	}
+
+	// Initialize the application model with configuration
+	opts := app.Options{
+		StackName:     env.stackName,
+		VaultName:     conn.vault,
+		Region:        env.region.Region,
+		RegionSource:  env.region.Source,
+		ResourceTypes: resourceTypes,
+		Export:        export,
+		Config:        cfg,
+		Client:        env.client,
+
+		OverrideFreeze: *override,
+		APIBudget:      *apiBudget,
+		OpenView:       *openView,
+		OpenResource:   *openResource,
+		OpenARN:        *openARN,
+		IdleLock:       idleLock,
+	}
+	if several != nil {
+		opts.Stacks = several.Stacks
+	}
+	if *plain {
+		opts.Renderer = app.PlainRenderer{}
+	}
+	// Simulated job IDs cannot be watched, so they are never saved, and a
+	// rehearsal does not change how the real environment opens
+	if !env.client.Simulated() && !*noCache {
+		opts.HistoryPath, _ = store.DefaultHistoryPath()
+		opts.ViewsPath, _ = store.DefaultViewsPath()
+		opts.WorkflowsPath, _ = store.DefaultWorkflowsPath()
+		opts.LocksPath, _ = store.DefaultLocksPath()
+		opts.VerificationsPath, _ = store.DefaultVerificationsPath()
+	}
+	// A rehearsal must not page anyone
+	if !env.client.Simulated() {
+		if opts.Notifier, err = webhook.New(cfg.Webhooks); err != nil {
+			printError(err)
 			cancel() // Cancel context before exiting
 			//nolint:gocritic // exitAfterDefer: we explicitly call cancel() before os.Exit
 			os.Exit(1)
 		}
-		finalStackName = discoveredStack
-		fmt.Fprintf(os.Stderr, "Auto-discovered stack: %s\n", finalStackName)
 	}
-
-	// Initialize the application model with configuration
-	model := app.NewModel(ctx, finalStackName, *vaultName, *region, *resourceType)
+	if *statusAddr != "" {
+		opts.Status = app.NewStatusBoard()
+		if err := serveStatus(*statusAddr, opts.Status); err != nil {
+			printError(err)
+			cancel() // Cancel context before exiting
+			//nolint:gocritic // exitAfterDefer: we explicitly call cancel() before os.Exit
+			os.Exit(1)
+		}
+	}
+	model := app.NewModel(ctx, opts)
 
 	p := tea.NewProgram(model)
 	if _, err := p.Run(); err != nil {
@@ -95,6 +259,394 @@ func main() {
 	}
 }
 
+// serveStatus starts serving board on addr in the background. It returns an
+// error if addr cannot be listened on, e.g. because the port is in use.
+// Requests must name addr in their Host header, as for -api.
+func serveStatus(addr string, board *app.StatusBoard) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on -status-addr %s: %w", addr, err)
+	}
+	// Clients name the address as given, e.g. localhost:8099, or as resolved
+	hosts := []string{addr, ln.Addr().String()}
+	srv := &http.Server{Handler: api.CheckHost(hosts, board), ReadHeaderTimeout: 5 * time.Second}
+	go func() { _ = srv.Serve(ln) }()
+	fmt.Fprintf(os.Stderr, "Serving job status on http://%s/status\n", ln.Addr())
+	return nil
+}
+
+// signalContext returns a context that is cancelled on Ctrl+C or SIGTERM,
+// for graceful shutdown.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+	return ctx, cancel
+}
+
+// connectOptions are the flags shared by the TUI and subcommands that
+// determine which account, region, stack, and vault to work against.
+type connectOptions struct {
+	stack    string
+	vault    string
+	region   string
+	simulate bool
+	fixtures string
+	config   string
+	auth     string
+	profile  string
+}
+
+// authUsage describes the -auth flag.
+const authUsage = "Where AWS credentials come from: auto (default chain), env, profile, sso, or web-identity"
+
+// profileUsage describes the -profile flag.
+const profileUsage = "Shared config profile for credentials and region, e.g. an IAM Identity Center profile (default: AWS_PROFILE, or default)"
+
+// register defines the connection flags on fs.
+func (o *connectOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.stack, "stack", "", "CloudFormation stack name (auto-discovered if not provided)")
+	fs.StringVar(&o.vault, "vault", "", "Backup vault name (auto-discovered if not provided)")
+	regionVar(fs, &o.region, "AWS `region` (resolved from AWS_REGION, AWS_DEFAULT_REGION, or shared config if not provided)")
+	fs.BoolVar(&o.simulate, "simulate", false, "Rehearse restores against recorded fixtures without touching AWS")
+	fs.StringVar(&o.fixtures, "fixtures", "", "Fixture file for -simulate (built-in sample environment if not provided)")
+	fs.StringVar(&o.auth, "auth", "auto", authUsage)
+	fs.StringVar(&o.profile, "profile", "", profileUsage)
+	fs.StringVar(&o.config, "config", "", "Config file with RPO/RTO targets, deletion protection, freeze windows, the recovery account, and webhooks (default: backup-tui/config.json in the user config directory)")
+}
+
+// regionVar defines a -region flag on fs that refuses values that are not
+// AWS region names, as the region prompt does, so a typo is a usage error
+// rather than a failed endpoint lookup.
+func regionVar(fs *flag.FlagSet, p *string, usage string) {
+	fs.Func("region", usage, func(s string) error {
+		if s != "" && !aws.ValidRegion(s) {
+			return aws.ErrInvalidRegion
+		}
+		*p = s
+		return nil
+	})
+}
+
+// loadConfig reads the -config file, or the default config file if none was
+// given. A missing default file is an empty config. Secrets written in the
+// file in plaintext are reported with how to move them to the keyring.
+func (o connectOptions) loadConfig() (*config.Config, error) {
+	path := o.config
+	if path == "" {
+		var err error
+		if path, err = config.DefaultPath(); err != nil {
+			return &config.Config{}, nil
+		}
+	} else if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to read -config: %w", err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if names := cfg.PlaintextSecrets(); len(names) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %s holds %s in plaintext; move them to the OS keyring with: backup-tui config migrate-secrets -config %s\n",
+			path, strings.Join(names, ", "), path)
+	}
+	return cfg, nil
+}
+
+// environment is a connected AWS client with its resolved region and stack.
+type environment struct {
+	client    *aws.BackupClient
+	region    aws.RegionResolution
+	stackName string
+}
+
+// connect resolves the region, creates the AWS (or simulated) client, and
+// auto-discovers the stack name if none was given. Progress is printed to
+// stderr; errors already include guidance for the operator.
+func connect(ctx context.Context, o connectOptions) (*environment, error) {
+	env, err := connectClient(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := env.discoverStack(ctx); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// discoverStack auto-discovers the stack name if none was given. Several
+// matching stacks are an error wrapping an *aws.MultipleStacksError.
+func (env *environment) discoverStack(ctx context.Context) error {
+	if env.stackName != "" {
+		return nil
+	}
+	discoveredStack, err := env.client.DiscoverStackName(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to auto-discover CloudFormation stack: %w\n\nPlease specify a stack name using the -stack flag:\n  backup-tui -stack YourStackName", err)
+	}
+	env.stackName = discoveredStack
+	fmt.Fprintf(os.Stderr, "Auto-discovered stack: %s\n", env.stackName)
+	return nil
+}
+
+// connectClient resolves the region and creates the AWS (or simulated)
+// client, without looking up the stack.
+func connectClient(ctx context.Context, o connectOptions) (*environment, error) {
+	env := &environment{stackName: o.stack}
+
+	// In simulation mode every AWS call is served from fixtures, so neither
+	// a region nor credentials are needed.
+	if o.simulate || o.fixtures != "" {
+		fx, err := aws.LoadFixtures(o.fixtures)
+		if err != nil {
+			return nil, err
+		}
+		env.client = aws.NewSimulatedBackupClient(fx)
+		env.region = aws.RegionResolution{Region: fx.Region, Source: aws.RegionSourceSimulation}
+		fmt.Fprintln(os.Stderr, "SIMULATION MODE: no AWS APIs will be called and nothing will be restored.")
+	} else {
+		// Resolve the region before any AWS call: flag → env → shared config → prompt
+		var err error
+		env.region, err = aws.ResolveRegion(ctx, o.region, o.profile)
+		if errors.Is(err, aws.ErrInvalidRegion) {
+			return nil, err
+		}
+		if errors.Is(err, aws.ErrRegionUnresolved) && stdinIsTerminal() {
+			var prompted string
+			prompted, err = promptRegion(stdin, os.Stderr)
+			env.region = aws.RegionResolution{Region: prompted, Source: aws.RegionSourcePrompt}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w\n\nSpecify a region with -region, set AWS_REGION or AWS_DEFAULT_REGION, or configure a region in your AWS profile", err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Using AWS region: %s\n", env.region)
+
+	if env.client == nil {
+		auth, err := aws.ParseAuthProvider(o.auth)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -auth: %w", err)
+		}
+		env.client, err = newClient(ctx, env.region.Region, aws.AuthOptions{Provider: auth, Profile: o.profile})
+		if err != nil {
+			return nil, credentialError(err)
+		}
+		healthRegion = env.region.Region
+	}
+	return env, nil
+}
+
+// healthRegion is the region of the AWS client, whose status feeds explain
+// regional failures in printError; empty until connected and in simulation.
+var healthRegion string
+
+// newClient creates the AWS client for region. When the credentials are
+// an IAM Identity Center profile's whose sign-in is missing or expired and
+// there is a terminal, it offers to sign in with 'aws sso login' and tries
+// again, so operators need not leave the tool to do it.
+func newClient(ctx context.Context, region string, auth aws.AuthOptions) (*aws.BackupClient, error) {
+	client, err := aws.NewBackupClient(ctx, region, auth)
+	var authErr *aws.AuthError
+	if err == nil || !errors.As(err, &authErr) || !authErr.NeedsSSOLogin() || !stdinIsTerminal() {
+		return client, err
+	}
+	if _, lookErr := exec.LookPath("aws"); lookErr != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "The IAM Identity Center sign-in of profile %s is missing or expired.\n", authErr.Profile)
+	if !confirm(stdin, os.Stderr, fmt.Sprintf("Sign in now with 'aws sso login --profile %s'? [y/N]: ", authErr.Profile)) {
+		return nil, err
+	}
+	login := exec.CommandContext(ctx, "aws", "sso", "login", "--profile", authErr.Profile)
+	login.Stdin, login.Stdout, login.Stderr = os.Stdin, os.Stderr, os.Stderr
+	if err := login.Run(); err != nil {
+		return nil, fmt.Errorf("aws sso login --profile %s failed: %w", authErr.Profile, err)
+	}
+	return aws.NewBackupClient(ctx, region, auth)
+}
+
+// credentialError wraps an AWS client creation error with guidance on how
+// to configure the auth provider's credentials.
+func credentialError(err error) error {
+	var authErr *aws.AuthError
+	if errors.As(err, &authErr) {
+		return fmt.Errorf("failed to create AWS client: %w\n\n%s", err, authErr.Hint())
+	}
+	return fmt.Errorf("failed to create AWS client: %w\nPlease ensure AWS credentials are configured", err)
+}
+
+// printError prints err to stderr. Errors from AWS calls are followed by
+// their operation and request ID, which AWS support asks for, and by the
+// incidents AWS reports in the region when the call failed the way an
+// outage makes calls fail.
+func printError(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if d := aws.DescribeError(err); !d.IsZero() {
+		fmt.Fprintf(os.Stderr, "AWS: %s\n", d)
+	}
+	printIncidents(err)
+}
+
+// printIncidents prints the ongoing incidents of Backup, RDS, and EFS in
+// the region to stderr if err is a regional failure. Status feeds that
+// cannot be read are not reported.
+func printIncidents(err error) {
+	if healthRegion == "" || !aws.IsRegionalFailure(err) {
+		return
+	}
+	incidents, _ := aws.NewHealthChecker().Incidents(context.Background(), healthRegion)
+	for _, inc := range incidents {
+		fmt.Fprintf(os.Stderr, "Status: %s\n", inc)
+	}
+}
+
+// outputFlag registers -output and its shorthand -o, text or json, for the
+// subcommands that print results for scripts.
+func outputFlag(fs *flag.FlagSet) *string {
+	output := fs.String("output", "text", "Output format: text or json")
+	fs.StringVar(output, "o", "text", "Shorthand for -output")
+	return output
+}
+
+// checkOutput reports an -output other than text or json.
+func checkOutput(output string) bool {
+	if output != "text" && output != "json" {
+		fmt.Fprintf(os.Stderr, "Error: -output must be text or json, got %q\n", output)
+		return false
+	}
+	return true
+}
+
+// printJSON writes v to stdout as indented JSON, returning 1 if it cannot
+// be encoded.
+func printJSON(v any) int {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to encode output: %v\n", err)
+		return 1
+	}
+	fmt.Println(string(data))
+	return 0
+}
+
+// printFailure reports err as printError does, or with -output json as the
+// JSON API's {"error": ...} object on stdout, so a pipeline into jq sees
+// why the command failed.
+func printFailure(output string, err error) {
+	if output != "json" {
+		printError(err)
+		return
+	}
+	printJSON(api.NewError(err))
+	printIncidents(err)
+}
+
+// runSubcommand dispatches a subcommand and returns the process exit code.
+func runSubcommand(name string, args []string) int {
+	switch name {
+	case "doctor":
+		return runDoctor(args)
+	case "jobs":
+		return runJobs(args)
+	case "watch":
+		return runWatch(args)
+	case "retention":
+		return runRetention(args)
+	case "cron":
+		return runCron(args)
+	case "latest":
+		return runLatest(args)
+	case "backup":
+		return runBackup(args)
+	case "prune":
+		return runPrune(args)
+	case "config":
+		return runConfig(args)
+	case "dr":
+		return runDR(args)
+	case "serve":
+		return runServe(args)
+	case "inventory":
+		return runInventory(args)
+	case "plan":
+		return runPlan(args)
+	case "apply":
+		return runApply(args)
+	case "verify":
+		return runVerify(args)
+	case "list":
+		return runList(args)
+	case "restore":
+		return runRestore(args)
+	case "status":
+		return runStatus(args)
+	case "teardown":
+		return runTeardown(args)
+	case "help":
+		printHelp()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q. Run 'backup-tui -help' for usage.\n", name)
+		return 2
+	}
+}
+
+// recordFixtures captures the stack's vault, recovery points, backup plans,
+// and database cluster as a fixture file for -simulate. The vault is
+// auto-discovered from the stack when vaultName is empty.
+func recordFixtures(ctx context.Context, client *aws.BackupClient, stackName, vaultName, path string) error {
+	if vaultName == "" {
+		discovered, err := client.DiscoverVaultByStack(ctx, stackName)
+		if err != nil {
+			return err
+		}
+		vaultName = discovered
+	}
+	fx, err := client.RecordFixtures(ctx, stackName, vaultName)
+	if err != nil {
+		return err
+	}
+	return aws.WriteFixtures(fx, path)
+}
+
+// stdinIsTerminal reports whether standard input is an interactive terminal,
+// in which case the operator can be prompted for missing configuration.
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// stdin is the one reader of standard input every prompt shares. A
+// bufio.Reader reads ahead, so a reader per prompt would lose the answers
+// to later prompts that an earlier one buffered, e.g. piped in or typed
+// ahead.
+var stdin = bufio.NewReader(os.Stdin)
+
+// promptRegion asks the operator for an AWS region, re-prompting on invalid
+// input. It gives up after three attempts or at end of input.
+func promptRegion(reader *bufio.Reader, out io.Writer) (string, error) {
+	fmt.Fprintln(out, "No AWS region found in -region, AWS_REGION, AWS_DEFAULT_REGION, or your AWS profile.")
+	for attempt := 0; attempt < 3; attempt++ {
+		fmt.Fprint(out, "Enter AWS region (e.g. us-west-2): ")
+		line, err := reader.ReadString('\n')
+		region := strings.TrimSpace(line)
+		if aws.ValidRegion(region) {
+			return region, nil
+		}
+		if err != nil {
+			break
+		}
+		fmt.Fprintf(out, "%q is not a valid AWS region name.\n", region)
+	}
+	return "", aws.ErrRegionUnresolved
+}
+
 // printHelp displays usage information and exits.
 // This provides users with information about available command-line options,
 // examples, and environment variables that can be used to configure the application.
@@ -103,12 +655,214 @@ func printHelp() {
 
 Usage:
   backup-tui [options]
+  backup-tui backup-tui://open?region=...&stack=...&view=... [options]
+  backup-tui list [-type RDS,EFS] [-limit n] [-o text|json] [options]
+  backup-tui restore [-yes] [-wait] [-interval 30s] [-label name] [-kms-key id]
+                     [-subnet-group name] [-security-groups ids]
+                     [-override-freeze] [-no-cache] [-o text|json] [options]
+                     {recovery-point-arn | -at moment [-type RDS|EFS]}
+  backup-tui status [-kind restore|backup|...] [-o text|json]
+                    [-region region] [-auth provider] [-profile name] job-id
+  backup-tui teardown -label drill-label [-dry-run] [-yes] [-o text|json] [options]
+  backup-tui doctor [-fix] [-yes] [options]
+  backup-tui jobs report [-window 7d] [-format markdown|json] [-output file] [options]
+  backup-tui watch [-interval 30s] [-history file] [-region region] [-auth provider]
+                   [-profile name] [job-id ...]
+  backup-tui retention plan [-delete-after days] [-cold-after days] [-type RDS,EFS]
+                            [-format markdown|json] [-output file] [options]
+  backup-tui latest [-at moment] [-output text|json] [options]
+  backup-tui cron [-rpo 26h] [-window 7d] [-email-from addr -email-to addrs]
+                  [-sns-topic arn] [options]
+  backup-tui backup [-type RDS,EFS] [-parallel 2] [-tag key=value ...]
+                    [-role arn] [-interval 30s] [options]
+  backup-tui prune [-keep-last n] [-keep-weekly n] [-keep-monthly n] [-apply [-yes]]
+                   [-type RDS,EFS] [-format markdown|json] [-output file] [options]
+  backup-tui config migrate-secrets [-config file]
+  backup-tui config set-secret name < value
+  backup-tui dr copy [-type RDS,EFS] [-recovery-point arn] [-role arn] [-restore]
+                     [-resume] [-subnet-group name] [-security-groups ids] [options]
+  backup-tui dr restore -recovery-point arn [-subnet-group name]
+                        [-security-groups ids] [options]
+  backup-tui dr scan [-regions us-east-1,...] [-max-age 2d] [-output text|json]
+                     [options]
+  backup-tui serve [-addr :8080] [-rpo 26h] [-window 7d] [-refresh 5m] [options]
+  backup-tui inventory export [-type RDS,EFS] [-output file.csv] [options]
+  backup-tui inventory diff [-type RDS,EFS] [-format markdown|json] [-output file]
+                            [options] previous.csv
+  backup-tui plan -recovery-point arn [-kms-key id] [-subnet-group name]
+                  [-security-groups ids] [-out restore.plan.json] [-expires 24h]
+                  [options]
+  backup-tui apply [-override-freeze] [options] plan-file
+  backup-tui verify -job id [-efs-mount dir] [-format markdown|json]
+                    [-output file] [options]
+  backup-tui verify -history [-format markdown|json] [-output file]
+
+Commands:
+  list              Print the vault's recovery points, newest first, one per
+                    line with the ARN last.
+  restore           Start a restore of a recovery point in the vault with the
+                    parameters the TUI would use: print what it creates or
+                    modifies, ask for confirmation (-yes skips it, and is
+                    required without a terminal), and save the job for
+                    watch. -wait follows it and exits 1 if it fails. -at
+                    2026-01-15T03:00Z restores the restorable backup closest
+                    to but not after that moment instead of a named one.
+                    -no-cache keeps it out of the local job history and
+                    lock file.
+  status            Print a restore or backup job's status once. Exits 0
+                    when it completed, 1 when it failed, 3 while running.
+                    For list, restore, and status, -o json (or -output json)
+                    prints results and errors as JSON for jq and other tools.
+  teardown          Delete the clusters, DB instances, and file systems tagged
+                    with a drill label (restore -label sets it), with their
+                    instances and mount targets, in dependency order, after a
+                    preview and confirmation. -dry-run only previews. The
+                    stack's own resources are never deleted.
+  doctor            Check that the stack's RDS cluster and EFS file systems
+                    are in a backup selection. With -fix, show a diff of a
+                    selection that adds the missing resources and apply it
+                    after confirmation (-yes skips the prompt). Accepts the
+                    -stack, -vault, -region, -simulate, and -fixtures options.
+  jobs report       Summarize backup, restore, and copy job success rates,
+                    average durations, and failures over -window (default
+                    7d) as markdown or JSON, for weekly ops reviews.
+  watch             Follow restore or backup jobs until they finish,
+                    printing each status change. Without job IDs, follows
+                    the unfinished jobs in the TUI's job history file.
+  retention plan    Dry run of a lifecycle change: list the recovery points
+                    that would be deleted or moved to cold storage, as
+                    markdown with a sign-off section or JSON. Changes nothing.
+  latest            Print each resource's latest restorable backup: the
+                    newest completed one in warm storage that has not
+                    failed a restore test. -output json for other tooling.
+                    -at prints the one closest to but not after a moment.
+  cron              For scheduled runs: check coverage, check that each
+                    resource's newest backup is within its type's RPO target
+                    from the config file or -rpo (default 26h), check
+                    restores against RTO targets, and report jobs over
+                    -window. Prints the summary, emails it through SES
+                    and/or publishes it to SNS, and exits 1 on any violation.
+  backup            Take on-demand backups of the stack's RDS cluster and
+                    EFS file systems, -parallel at a time, tagging every
+                    recovery point with the -tag flags and a shared
+                    backup-tui:batch tag. Waits for them and prints a summary;
+                    exits 1 if any did not complete.
+  prune             Preview which on-demand backups created by backup-tui a
+                    keep-last/weekly/monthly policy (-keep-* flags, or the
+                    config file's "prune" policy) deletes, per resource, as
+                    markdown or JSON. -apply prints the plan and deletes them
+                    once confirmed (-yes skips asking, and is required without
+                    a terminal). Backups the config file protects are kept.
+  config migrate-secrets
+                    Move webhook URLs, external IDs, and tokens written in
+                    plaintext in the config file into the OS keyring, and
+                    rewrite the file to reference them.
+  config set-secret Store a value read from standard input in the OS keyring,
+                    for the config file to reference as {"keyring": "name"}.
+  dr copy           Recover from the loss of the stack's account: assume the
+                    config file's crossAccount role, copy each resource's
+                    latest restorable backup to the recovery vault in that
+                    account, and wait for the copies. With -restore, restore
+                    them there as a new Aurora cluster and EFS file systems.
+                    -resume continues an interrupted run with its job IDs.
+  dr restore        Restore a copy in the recovery vault as new resources,
+                    using only your credentials and the recovery role.
+  dr scan           Look in each DR region (-regions, or the config file's
+                    drRegions) for the copies cross-region copy rules made of
+                    the vault, and report per resource how many there are
+                    and how old the newest is. Exits 1 if a resource has no
+                    copy newer than -max-age (default 2d) in a region.
+  serve             Serve a read-only HTML dashboard of the vault on -addr
+                    (default :8080) for NOC wall displays: inventory per
+                    resource, RPO/RTO status as cron checks it, and recent
+                    jobs, reloaded from AWS every -refresh (default 5m). The
+                    same content is served as JSON on /dashboard.json.
+  inventory export  Write every recovery point in the vault to CSV (ARN,
+                    resource, status, creation date, size, lifecycle dates).
+  inventory diff    Compare the vault with a previous export: recovery points
+                    created since, expired or deleted (flagging deletions
+                    before their scheduled date), and changed in size, as
+                    markdown with a sign-off section or JSON.
+  plan              Resolve what restoring -recovery-point would create or
+                    modify, print it, and write it to a plan file signed with
+                    the config file's planSigningKey for review. Changes
+                    nothing; the plan can be applied until -expires (24h).
+  apply             Restore from a reviewed plan file: refuse it if its
+                    signature does not match, it expired, it was already
+                    applied, or the live parameters changed since it was
+                    made; otherwise start the restore and save it for watch.
+  verify            Check a completed test restore: count the rows of key
+                    tables in a restored cluster through the RDS Data API,
+                    or the size (and, with -efs-mount, files) of a restored
+                    file system, against the config file's "verify" checks
+                    and the resource's last verified backup. Records the
+                    result and exits 1 if it failed. -history prints the
+                    recorded results.
 
 Options:
   -stack string     CloudFormation stack name (auto-discovered if not provided)
   -vault string     Backup vault name (auto-discovered if not provided)
-  -region string    AWS region (default: "us-west-2")
-  -type string      Resource type to filter (RDS or EFS, empty for all)
+  -region string    AWS region (see Region Resolution below)
+  -auth string      Where AWS credentials come from: auto (default chain), env,
+                    profile, sso, or web-identity (see Credentials below)
+  -profile string   Shared config profile for credentials and region, e.g. an
+                    IAM Identity Center profile (default: AWS_PROFILE)
+  -type string      AWS Backup resource types to filter, e.g. RDS or Aurora,RDS
+                    (empty for all)
+  -simulate         Rehearse restores against fixtures without touching AWS
+  -fixtures string  Fixture file for -simulate (built-in sample if not provided)
+  -config string    Config file with RPO/RTO targets, deletion protection,
+                    freeze windows, the recovery account, and webhooks
+                    (default: backup-tui/config.json in the user config
+                    directory)
+  -no-cache         Keep no local state: restores are not saved to or resumed
+                    from the encrypted job history, and the sort order,
+                    filter, and view are not remembered
+  -override-freeze  Allow restores during the config file's change freeze
+                    windows, which otherwise refuse them
+  -plain            Draw the backup list, details, and jobs as unstyled text,
+                    e.g. for screen readers
+  -theme string     Status colors: default (green, yellow, red) or colorblind
+                    (blue, yellow, vermillion). Either way, states also differ
+                    by glyph, e.g. ● ◐ ○ for a backup's age
+  -api-budget int   Pause background refresh (backup details and the restore
+                    prefetch) once the session has made this many AWS API
+                    calls; ctrl+d shows the calls per service
+  -open string      Launch into a view instead of the last one used: list,
+                    jobs, timeline, legal-holds, selections, activity, team,
+                    backup-jobs, or restore-jobs
+  -resource string  Launch into the detail view of a resource's latest
+                    restorable backup, by resource ID or ARN, e.g. for
+                    runbook links
+  -arn string       Launch into the detail view of the recovery point with
+                    this ARN (use only one of -open, -resource, and -arn)
+  -lock-after duration
+                    Lock the screen after this long without a key press,
+                    e.g. 15m; resuming asks for confirmation and checks the
+                    AWS credentials. Shortens but cannot lengthen the config
+                    file's idleLock
+  -link string      Open the region, stack, vault, and view or backup of a
+                    backup-tui:// deep link, as the environment info panel
+                    (i) and reports show them; other flags take precedence.
+                    The link can also be given as the first argument.
+  -record-fixtures string
+                    Record the stack and vault to a fixture file and exit
+  -export-bucket string, -export-prefix string
+                    S3 destination for snapshot exports (x in the detail view)
+  -export-role string
+                    IAM role ARN RDS assumes to write snapshot exports
+  -export-kms-key string
+                    KMS key that encrypts snapshot exports
+  -status-addr string
+                    Serve the session's job state as JSON, e.g. 127.0.0.1:8099
+  -status-allow-remote
+                    Allow -status-addr to listen on an address other than
+                    loopback
+  -api string       Serve the JSON API for automation (list, describe, restore,
+                    jobs, doctor) on this address, e.g. 127.0.0.1:8098,
+                    instead of starting the TUI. Requests present the config
+                    file's apiToken as a bearer token
+  -api-allow-remote Allow -api to listen on an address other than loopback
   -help             Show this help message
 
 Examples:
@@ -118,19 +872,97 @@ Examples:
   # Specify stack explicitly
   backup-tui -stack MyStack -region us-east-1
 
-  # Filter by resource type
+  # Filter by resource type, or several
   backup-tui -type RDS
+  backup-tui -type Aurora,RDS
+
+  # Allow exporting Aurora backups to S3 as Parquet
+  backup-tui -export-bucket openemr-exports -export-role arn:aws:iam::123456789012:role/rds-export \
+             -export-kms-key alias/openemr-exports
+
+  # Runbook links: open the jobs view, or the cluster's latest backup
+  backup-tui -open jobs
+  backup-tui -resource openemr-db
+  backup-tui 'backup-tui://open?region=us-west-2&stack=MyStack&view=jobs'
+
+  # Follow a long DR workflow from another terminal
+  backup-tui -status-addr 127.0.0.1:8099
+  curl -s http://127.0.0.1:8099/status
+
+  # Let other automation list backups and start restores over HTTP
+  backup-tui -api 127.0.0.1:8098
+  curl -s -H "Authorization: Bearer $TOKEN" http://127.0.0.1:8098/v1/recovery-points?type=RDS
+
+  # Back up everything before an upgrade, tagged with the change ticket
+  backup-tui backup -tag Reason=pre-upgrade -tag Ticket=CHG-1234
+
+  # Check backup coverage and repair it interactively
+  backup-tui doctor -fix
+
+  # Weekly ops review: last 7 days of jobs as markdown
+  backup-tui jobs report -output weekly-backups.md
+
+  # Keep following restores after the TUI exited with an error
+  backup-tui watch
+
+  # Keep the last 3 pre-upgrade backups and one per month for 6 months
+  backup-tui prune -keep-last 3 -keep-monthly 6
+  backup-tui prune -keep-last 3 -keep-monthly 6 -apply
+  backup-tui prune -keep-last 3 -keep-monthly 6 -apply -yes
+
+  # Review what shortening retention to 14 days would delete
+  backup-tui retention plan -delete-after 14 -output retention-review.md
+
+  # Monthly compliance attestation: reconcile with last month's export
+  backup-tui inventory diff -output attestation-2026-04.md inventory-2026-03.csv
+  backup-tui inventory export -output inventory-2026-04.csv
+
+  # Restore with a reviewed change: plan now, apply after approval
+  backup-tui plan -recovery-point arn:aws:backup:...:recovery-point:... -out chg-1234.plan.json
+  backup-tui apply chg-1234.plan.json
+
+  # Wall display dashboard on the office network
+  backup-tui serve -addr :8080 -config backup-tui.json
+
+  # DR training: record a real environment once, then rehearse offline
+  backup-tui -record-fixtures dr-drill.json
+  backup-tui -simulate -fixtures dr-drill.json
 
 Environment Variables (Required):
   AWS_ACCESS_KEY_ID          AWS access key (REQUIRED)
   AWS_SECRET_ACCESS_KEY      AWS secret key (REQUIRED)
   AWS_SESSION_TOKEN          AWS session token (for temporary credentials)
-  AWS_DEFAULT_REGION         AWS region (overridden by -region flag)
+  AWS_REGION                 AWS region (overridden by -region flag)
+  AWS_DEFAULT_REGION         AWS region, as the AWS CLI reads it (overridden by
+                             -region and AWS_REGION)
+  AWS_PROFILE                Shared config profile (its region is used if no flag/env region);
+                             -profile overrides it
+  AWS_WEB_IDENTITY_TOKEN_FILE
+                             Web identity token, e.g. injected by EKS (IRSA)
+  AWS_ROLE_ARN               Role assumed with the web identity token
+
+Region Resolution:
+  The region is resolved in this order and printed before anything runs:
+    1. -region flag
+    2. AWS_REGION environment variable
+    3. AWS_DEFAULT_REGION environment variable
+    4. region of the -profile (or AWS_PROFILE, or default) profile in ~/.aws/config
+    5. interactive prompt (when running in a terminal)
 
-Note: AWS credentials are REQUIRED to use this application. Configure them using:
-  - Environment variables: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
-  - AWS credentials file: ~/.aws/credentials (run 'aws configure')
-  - IAM role: if running on EC2/ECS, ensure instance/task role has permissions
+Credentials:
+  AWS credentials are REQUIRED to use this application. -auth selects where
+  they come from; when they cannot be loaded, the error says how to fix it.
+  -profile names the profile of auto, profile, and sso; with auto it is used
+  even if access keys are set in the environment. When an IAM Identity
+  Center profile's sign-in is missing or expired, backup-tui offers to run
+  'aws sso login' for it (in a terminal, with the AWS CLI installed).
+    auto          The default chain: environment, web identity, credentials
+                  file and SSO profiles, then the EC2/ECS instance/task role
+    env           AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY only
+    profile       The -profile (or AWS_PROFILE, or default) profile, even if access keys
+                  are set in the environment (run 'aws configure')
+    sso           An IAM Identity Center profile (run 'aws sso login')
+    web-identity  AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN, e.g. EKS IRSA
 
 Controls:
   ↑/↓            Navigate backup list
@@ -138,6 +970,11 @@ Controls:
   b/←/Backspace  Go back
   Esc/q          Quit application
   r              Refresh backup list
+  f / s          Cycle filter / sort order
+  v / -          Switch vault / return to previous vault
+  J              Jobs view (restores, queued chain steps, imported jobs)
+  T              OpenEMR task definition history
+  t              Timeline of backups, restores, and deployments
   ?              Show help
 
 Features:
@@ -146,5 +983,6 @@ Features:
   • Initiate restore operations
   • Filter by resource type (RDS/EFS)
   • Auto-discover stack name and backup vault
+  • Simulation mode for DR training (-simulate)
 `)
 }
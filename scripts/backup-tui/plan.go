@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/plan"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+// runPlan implements "backup-tui plan": it resolves everything a restore of
+// a recovery point would create or modify, prints it, and writes it to a
+// plan file signed with the config's planSigningKey, to be reviewed and
+// executed later with "backup-tui apply". Nothing is changed.
+//
+// Exit codes: 0 when the plan was written, 1 when it could not be resolved
+// or signed, 2 for usage errors.
+func runPlan(args []string) int {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	recoveryPoint := fs.String("recovery-point", "", "ARN of the recovery point to restore (see 'backup-tui latest')")
+	kmsKey := fs.String("kms-key", "", "KMS key to encrypt the restored cluster or file system with instead of the backup's key (creates a new EFS file system)")
+	subnetGroup := fs.String("subnet-group", "", "DB subnet group for a restored Aurora cluster instead of the live cluster's")
+	securityGroups := fs.String("security-groups", "", "Comma-separated security group IDs for a restored Aurora cluster instead of the live cluster's")
+	newCluster := fs.String("new-cluster", "", "Restore an RDS backup to a new cluster named after the live one with this suffix, e.g. verify, to check the data before cutting over")
+	out := fs.String("out", "restore.plan.json", "Plan file to write")
+	expires := fs.Duration("expires", 24*time.Hour, "How long the plan can be applied for")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	switch {
+	case *recoveryPoint == "":
+		fmt.Fprintln(os.Stderr, "Error: specify the recovery point to restore with -recovery-point")
+		return 2
+	case *expires <= 0:
+		fmt.Fprintf(os.Stderr, "Error: -expires must be positive, got %s\n", *expires)
+		return 2
+	}
+	opts := aws.RestoreOptions{KMSKeyID: *kmsKey, SubnetGroup: *subnetGroup, ClusterSuffix: strings.ToLower(strings.TrimSpace(*newCluster))}
+	for _, id := range strings.Split(*securityGroups, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			opts.SecurityGroupIDs = append(opts.SecurityGroupIDs, id)
+		}
+	}
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	key, err := cfg.PlanKey()
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+	rp, err := findRecoveryPoint(ctx, env.client, vaultName, *recoveryPoint)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	current, err := resolveRestore(ctx, env, vaultName, rp, opts)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	p := plan.New(rp, opts, current.meta, current.roleARN)
+	p.Stack, p.Vault, p.Region = env.stackName, vaultName, env.region.Region
+	p.CreatedBy = env.client.CallerARN()
+	p.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	p.ExpiresAt = p.CreatedAt.Add(*expires)
+	if err := p.Sign(key); err != nil {
+		printError(err)
+		return 1
+	}
+	if err := p.Write(*out); err != nil {
+		printError(err)
+		return 1
+	}
+	fmt.Print(p.Text())
+	fmt.Printf("\nSaved the plan to %s. Once it is reviewed, restore with:\n  backup-tui apply %s\n", *out, *out)
+	return 0
+}
+
+// runApply implements "backup-tui apply": it verifies a plan file's
+// signature and expiry, checks that the recovery point still exists and
+// that restoring it now would use exactly the planned parameters, and starts
+// the restore. A plan is applied at most once per job history, and not
+// during the config's freeze windows unless overridden. The job is saved to
+// the history to follow with "backup-tui watch".
+//
+// Exit codes: 0 when the restore was started, 1 when the plan was refused
+// or the restore could not be started, 2 for usage errors.
+func runApply(args []string) int {
+	fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	override := fs.Bool("override-freeze", false, "Apply the plan during the config file's change freeze windows")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: backup-tui apply [options] plan-file")
+		return 2
+	}
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	key, err := cfg.PlanKey()
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	p, err := plan.Load(fs.Arg(0))
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	if err := p.Verify(key); err != nil {
+		printError(fmt.Errorf("refusing to apply %s: %w", fs.Arg(0), err))
+		return 1
+	}
+	now := time.Now()
+	if p.Expired(now) {
+		printError(fmt.Errorf("plan %s expired at %s; run 'backup-tui plan' again", p.ID, p.ExpiresAt.UTC().Format(time.RFC3339)))
+		return 1
+	}
+	if !*override {
+		if f, until := cfg.ActiveFreeze(now); f != nil {
+			printError(fmt.Errorf("restores are frozen by config window %s until %s; rerun with -override-freeze to apply anyway",
+				f, until.UTC().Format(time.RFC3339)))
+			return 1
+		}
+	}
+
+	// The plan says where it restores; flags may only repeat it
+	for _, flagValue := range []struct{ name, given, planned string }{
+		{"stack", conn.stack, p.Stack},
+		{"vault", conn.vault, p.Vault},
+		{"region", conn.region, p.Region},
+	} {
+		if flagValue.given != "" && flagValue.given != flagValue.planned {
+			printError(fmt.Errorf("-%s %s does not match the plan's %s %s", flagValue.name, flagValue.given, flagValue.name, flagValue.planned))
+			return 2
+		}
+	}
+	conn.stack, conn.vault, conn.region = p.Stack, p.Vault, p.Region
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	if env.region.Region != p.Region {
+		printError(fmt.Errorf("plan %s is for region %s, but this session is in %s", p.ID, p.Region, env.region.Region))
+		return 1
+	}
+	historyPath := ""
+	if !env.client.Simulated() {
+		historyPath, _ = store.DefaultHistoryPath()
+	}
+	if historyPath != "" {
+		history, err := store.LoadHistory(historyPath)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+		for _, j := range history {
+			if j.PlanID == p.ID {
+				printError(fmt.Errorf("plan %s was already applied as restore job %s at %s", p.ID, j.JobID, j.StartedAt.UTC().Format(time.RFC3339)))
+				return 1
+			}
+		}
+	}
+
+	rp, err := findRecoveryPoint(ctx, env.client, p.Vault, p.RecoveryPoint.ARN)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	current, err := resolveRestore(ctx, env, p.Vault, rp, p.RestoreOptions())
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	if drift := p.Drift(plan.RestoreFor(current.meta, current.roleARN)); len(drift) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: the environment changed since plan %s was made:\n", p.ID)
+		for _, d := range drift {
+			fmt.Fprintf(os.Stderr, "  %s\n", d)
+		}
+		fmt.Fprintln(os.Stderr, "Run 'backup-tui plan' again and have the new plan reviewed.")
+		return 1
+	}
+
+	fmt.Print(p.Text())
+	// apply exits once the job starts, so the lock is left to expire
+	lock := stackLock(env, p.Vault, cfg)
+	held, _ := takeRestoreLock(ctx, lock, env, os.Stdout)
+	jobID, err := env.client.StartRestoreJob(ctx, rp, p.Stack, p.Vault, p.RestoreOptions())
+	if err != nil {
+		releaseRestoreLock(ctx, lock, held)
+		printError(err)
+		return 1
+	}
+	fmt.Printf("\nStarted restore job %s\n", jobID)
+	if historyPath != "" {
+		_, err := store.SaveJobs(historyPath, store.TrackedJob{
+			JobID:            jobID,
+			Kind:             aws.JobKindRestore,
+			Region:           p.Region,
+			Vault:            p.Vault,
+			ResourceType:     rp.ResourceType,
+			ResourceID:       rp.ResourceID,
+			RecoveryPointARN: rp.RecoveryPointARN,
+			StartedAt:        now,
+			PlanID:           p.ID,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: the restore is not saved to the job history: %v\n", err)
+			return 0
+		}
+		fmt.Println("Follow it with: backup-tui watch")
+	}
+	return 0
+}
+
+// plannedRestore is what a restore of a recovery point would be started
+// with now.
+type plannedRestore struct {
+	meta         *aws.RestoreMetadata
+	roleARN      string
+	fallbackRole bool // No backup plan targets the vault, so roleARN is the default service role
+}
+
+// resolveRestore looks up the parameters a restore of rp with opts would be
+// started with, as StartRestoreJob derives them.
+func resolveRestore(ctx context.Context, env *environment, vaultName string, rp aws.RecoveryPoint, opts aws.RestoreOptions) (plannedRestore, error) {
+	meta, err := env.client.GetRestoreMetadata(ctx, rp, env.stackName)
+	if err != nil {
+		return plannedRestore{}, err
+	}
+	if err := meta.ValidateOptions(opts); err != nil {
+		return plannedRestore{}, err
+	}
+	meta.ApplyOptions(opts)
+	role, err := env.client.ResolvePlanRole(ctx, vaultName, false)
+	if err != nil {
+		return plannedRestore{}, fmt.Errorf("failed to get backup plan role ARN: %w", err)
+	}
+	return plannedRestore{meta: meta, roleARN: role.RoleARN, fallbackRole: role.Fallback}, nil
+}
+
+// findRecoveryPoint looks up the recovery point with the given ARN in the
+// vault.
+func findRecoveryPoint(ctx context.Context, client *aws.BackupClient, vaultName, arn string) (aws.RecoveryPoint, error) {
+	points, err := client.ListRecoveryPoints(ctx, vaultName)
+	if err != nil {
+		return aws.RecoveryPoint{}, err
+	}
+	for _, p := range points {
+		if p.RecoveryPointARN == arn {
+			return p, nil
+		}
+	}
+	return aws.RecoveryPoint{}, fmt.Errorf("recovery point %s is not in vault %s", arn, vaultName)
+}
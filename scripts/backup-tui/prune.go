@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/report"
+)
+
+// runPrune implements "backup-tui prune": it applies a keep-last, weekly,
+// and monthly policy to the on-demand backups the tool created, which no
+// backup plan lifecycle ever deletes. Without -apply it previews what would
+// be deleted, as markdown or JSON; with -apply it prints the plan, asks for
+// confirmation unless given -yes, and deletes them. Backups protected by the
+// config file or inside the vault's Vault Lock minimum retention are kept
+// either way.
+//
+// Exit codes: 0 on success, 1 when the plan could not be produced or a
+// deletion failed, 2 for usage errors and for deletions not confirmed.
+func runPrune(args []string) int {
+	fs := flag.NewFlagSet("prune", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	keepLast := fs.Int("keep-last", 0, "Keep the newest N backups of each resource")
+	keepWeekly := fs.Int("keep-weekly", 0, "Keep the newest backup of each of the last N weeks with one")
+	keepMonthly := fs.Int("keep-monthly", 0, "Keep the newest backup of each of the last N months with one")
+	resourceType := fs.String("type", "", "AWS Backup resource types to prune, e.g. RDS or RDS,EFS (empty for all)")
+	format := fs.String("format", "markdown", "Preview format: markdown or json")
+	output := fs.String("output", "", "Write the preview to a file instead of stdout")
+	apply := fs.Bool("apply", false, "Delete the backups the policy does not keep")
+	yes := fs.Bool("yes", false, "With -apply, delete without asking for confirmation (required without a terminal)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	// Without a terminal nobody can answer the prompt, so a scheduled prune
+	// must say -yes rather than delete by default
+	if *apply && !*yes && !stdinIsTerminal() {
+		printError(errors.New("no terminal to confirm the deletions on; pass -yes to delete without confirmation"))
+		return 2
+	}
+	renderer, err := report.NewRenderer(*format)
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	types, err := aws.ParseResourceTypes(*resourceType)
+	if err != nil {
+		printError(fmt.Errorf("invalid -type: %w", err))
+		return 2
+	}
+
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	// The -keep flags replace the config file's policy as a whole
+	var policy config.PrunePolicy
+	flagged := false
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "keep-last", "keep-weekly", "keep-monthly":
+			flagged = true
+		}
+	})
+	if flagged {
+		policy = config.PrunePolicy{KeepLast: *keepLast, KeepWeekly: *keepWeekly, KeepMonthly: *keepMonthly}
+	} else if cfg.Prune != nil {
+		policy = *cfg.Prune
+	}
+	if err := policy.Validate(); err != nil {
+		printError(err)
+		return 2
+	}
+	if policy.IsZero() {
+		fmt.Fprintln(os.Stderr, "Error: specify what to keep with -keep-last, -keep-weekly, and/or -keep-monthly, or a \"prune\" policy in the config file")
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+
+	// AWS Backup refuses to delete backups inside a Vault Lock's minimum
+	// retention, so the plan keeps them rather than failing on each
+	vault, err := env.client.DescribeVault(ctx, vaultName)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	points, details, err := toolCreatedBackups(ctx, env.client, vaultName, types)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	now := time.Now()
+	protection := func(rp aws.RecoveryPoint) string {
+		p := config.ProtectedPoint{ARN: rp.RecoveryPointARN, ResourceType: rp.ResourceType, Created: rp.CreationDate,
+			Tags: details[rp.RecoveryPointARN].Tags}
+		if rule := cfg.Protection(p, now); rule != nil {
+			return rule.String()
+		}
+		return ""
+	}
+	p := report.BuildPrunePlan(points, policy, protection, vault, now)
+	p.Stack, p.Vault, p.Region, p.ResourceType = env.stackName, vaultName, env.region.Region, strings.Join(types, ", ")
+
+	if *apply {
+		if !*yes && len(p.Delete) > 0 {
+			p.Confirming = true
+			data, err := report.MarkdownRenderer{}.Render(p)
+			if err != nil {
+				printError(err)
+				return 1
+			}
+			_, _ = os.Stdout.Write(data)
+			if !confirm(stdin, os.Stdout, fmt.Sprintf("\nDelete these %d backup(s)? [y/N]: ", len(p.Delete))) {
+				fmt.Println("Nothing deleted.")
+				return 2
+			}
+			fmt.Println()
+		}
+		return applyPrune(ctx, env.client, p)
+	}
+
+	data, err := renderer.Render(p)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	if *output == "" {
+		_, _ = os.Stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(*output, data, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write preview: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Wrote prune preview to %s (%d deleted when applied, %d kept, %d protected)\n",
+		*output, len(p.Delete), len(p.Keep), len(p.Protected))
+	return 0
+}
+
+// toolCreatedBackups returns the completed on-demand backups in vaultName
+// the tool created, with their details. A backup whose details cannot be
+// read is an error rather than skipped, so a preview never silently
+// understates what a policy keeps.
+func toolCreatedBackups(ctx context.Context, client *aws.BackupClient, vaultName string, resourceTypes []string) ([]aws.RecoveryPoint, map[string]*aws.RecoveryPointDetails, error) {
+	all, err := client.ListRecoveryPoints(ctx, vaultName, resourceTypes...)
+	if err != nil {
+		return nil, nil, err
+	}
+	byARN := make(map[string]aws.RecoveryPoint, len(all))
+	arns := make([]string, 0, len(all))
+	for _, rp := range all {
+		if rp.Status == "COMPLETED" || rp.Status == "AVAILABLE" {
+			byARN[rp.RecoveryPointARN] = rp
+			arns = append(arns, rp.RecoveryPointARN)
+		}
+	}
+
+	enricher := aws.NewEnricher(ctx, client, aws.EnrichWorkers)
+	enricher.Prioritize(vaultName, arns)
+	var points []aws.RecoveryPoint
+	details := make(map[string]*aws.RecoveryPointDetails)
+	for range arns {
+		var r aws.EnrichResult
+		select {
+		case r = <-enricher.Results():
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		if r.Err != nil {
+			return nil, nil, r.Err
+		}
+		if r.Details.ToolCreated() {
+			points = append(points, byARN[r.RecoveryPointARN])
+			details[r.RecoveryPointARN] = r.Details
+		}
+	}
+	return points, details, nil
+}
+
+// applyPrune deletes the backups p does not keep, oldest first, and
+// reports each. It returns 1 if any deletion failed.
+func applyPrune(ctx context.Context, client *aws.BackupClient, p *report.PrunePlan) int {
+	fmt.Printf("Pruning on-demand backups in vault %s (%s): %d to delete, %d kept, %d protected\n\n",
+		p.Vault, p.Policy, len(p.Delete), len(p.Keep), len(p.Protected))
+	failed := 0
+	for _, e := range p.Delete {
+		if err := client.DeleteRecoveryPoint(ctx, p.Vault, e.RecoveryPointARN); err != nil {
+			failed++
+			fmt.Printf("  ✗ %-4s %s  %s: %v\n", e.ResourceType, e.ResourceID, e.CreatedAt.UTC().Format("2006-01-02 15:04"), err)
+			continue
+		}
+		fmt.Printf("  ✓ %-4s %s  %s deleted\n", e.ResourceType, e.ResourceID, e.CreatedAt.UTC().Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("\n%d of %d backup(s) deleted.\n", len(p.Delete)-failed, len(p.Delete))
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
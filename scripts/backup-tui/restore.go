@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/api"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/plan"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+// restoreOutput is the JSON document printed by "backup-tui restore -output
+// json", with the fields of the JSON API's response to a restore.
+type restoreOutput struct {
+	JobID         string            `json:"jobId"`
+	RecoveryPoint api.RecoveryPoint `json:"recoveryPoint"`
+	At            *time.Time        `json:"at,omitempty"`     // The moment of -at the recovery point was picked for
+	Status        *api.JobStatus    `json:"status,omitempty"` // The finished job's status, with -wait
+	Warning       string            `json:"warning,omitempty"`
+}
+
+// runRestore implements "backup-tui restore": it starts a restore of a
+// recovery point in the vault with the parameters the TUI's confirmation
+// would show, after printing them and asking for confirmation, or without
+// asking with -yes, e.g. in a CI pipeline. Restores are refused during the
+// config's freeze windows unless overridden. The job is saved to the job
+// history for "watch", and with -wait followed until it finishes. With
+// -output json, the plan, prompt, and progress go to stderr and the job to
+// stdout as JSON. Instead of a recovery point ARN, -at picks the restorable
+// point closest to but not after a moment of an incident timeline. Like
+// the TUI, it takes the stack's advisory restore lock, warning of another
+// session's even with -yes, and holds it until the restore ends with -wait.
+// With -no-cache, as in the TUI, nothing is written to the local job history
+// or lock file.
+//
+// Exit codes: 0 when the restore started (with -wait, completed), 1 when it
+// could not be started or did not complete, 2 for usage errors and for a
+// restore that was not confirmed.
+func runRestore(args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	kmsKey := fs.String("kms-key", "", "KMS key to encrypt the restored cluster or file system with instead of the backup's key (creates a new EFS file system)")
+	subnetGroup := fs.String("subnet-group", "", "DB subnet group for a restored Aurora cluster instead of the live cluster's")
+	securityGroups := fs.String("security-groups", "", "Comma-separated security group IDs for a restored Aurora cluster instead of the live cluster's")
+	newCluster := fs.String("new-cluster", "", "Restore an RDS backup to a new cluster named after the live one with this suffix, e.g. verify, to check the data before cutting over")
+	override := fs.Bool("override-freeze", false, "Restore even during one of the config file's change freeze windows")
+	yes := fs.Bool("yes", false, "Start the restore without asking for confirmation (required without a terminal)")
+	wait := fs.Bool("wait", false, "Follow the restore until it finishes, and exit 1 if it does not complete")
+	label := fs.String("label", "", "Tag the restored cluster or file system with this drill label once it completes, for \"backup-tui teardown\" (requires -wait)")
+	interval := fs.Duration("interval", 30*time.Second, "How often to poll the restore's status with -wait")
+	atFlag := fs.String("at", "", "Restore the restorable point closest to but not after this moment instead of a recovery point ARN, e.g. 2026-01-15T03:00Z")
+	resourceType := fs.String("type", "", "With -at, the resource type to restore, RDS or EFS (required when the vault backs up more than one resource)")
+	noCache := fs.Bool("no-cache", false, "Keep no local state: the restore is not saved to the job history, and its lock is kept only in the vault tag if the config shares it")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if (fs.NArg() == 1) == (*atFlag != "") || fs.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "Usage: backup-tui restore [-yes] [-wait] [-label name] [-o text|json] [options] {recovery-point-arn | -at moment [-type RDS|EFS]}")
+		return 2
+	}
+	if !checkOutput(*output) {
+		return 2
+	}
+	var at time.Time
+	if *atFlag != "" {
+		var err error
+		if at, err = aws.ParseMoment(*atFlag); err != nil {
+			printFailure(*output, err)
+			return 2
+		}
+	} else if *resourceType != "" {
+		printFailure(*output, errors.New("-type picks the resource to restore with -at; a recovery point ARN names it already"))
+		return 2
+	}
+	if *interval <= 0 {
+		printFailure(*output, fmt.Errorf("-interval must be positive, got %s", *interval))
+		return 2
+	}
+	// AWS Backup cannot tag what a restore creates until it completes
+	if *label != "" && !*wait {
+		printFailure(*output, errors.New("-label requires -wait: the restored resource can only be labelled once the restore completes"))
+		return 2
+	}
+	// Without a terminal nobody can answer the prompt, so a pipeline must
+	// say -yes rather than hang or restore by default
+	if !*yes && !stdinIsTerminal() {
+		printFailure(*output, errors.New("no terminal to confirm the restore on; pass -yes to restore without confirmation"))
+		return 2
+	}
+	// Keep stdout to the JSON document for tooling reading it
+	out := io.Writer(os.Stdout)
+	if *output == "json" {
+		out = os.Stderr
+	}
+	opts := aws.RestoreOptions{KMSKeyID: *kmsKey, SubnetGroup: *subnetGroup, ClusterSuffix: strings.ToLower(strings.TrimSpace(*newCluster))}
+	for _, id := range strings.Split(*securityGroups, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			opts.SecurityGroupIDs = append(opts.SecurityGroupIDs, id)
+		}
+	}
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printFailure(*output, err)
+		return 1
+	}
+	if !*override {
+		if f, until := cfg.ActiveFreeze(time.Now()); f != nil {
+			printFailure(*output, fmt.Errorf("restores are frozen by config window %s until %s; rerun with -override-freeze to restore anyway",
+				f, until.UTC().Format(time.RFC3339)))
+			return 1
+		}
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printFailure(*output, err)
+		return 1
+	}
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printFailure(*output, err)
+			return 1
+		}
+	}
+	var rp aws.RecoveryPoint
+	if at.IsZero() {
+		rp, err = findRecoveryPoint(ctx, env.client, vaultName, fs.Arg(0))
+	} else {
+		rp, err = findRecoveryPointAt(ctx, env, vaultName, at, *resourceType, out)
+	}
+	if err != nil {
+		printFailure(*output, err)
+		return 1
+	}
+	current, err := resolveRestore(ctx, env, vaultName, rp, opts)
+	if err != nil {
+		printFailure(*output, err)
+		return 1
+	}
+
+	printRestore(out, rp, plan.New(rp, opts, current.meta, current.roleARN))
+	if current.fallbackRole {
+		fmt.Fprintln(out, "  No backup plan targets this vault, so that is the account's default AWS Backup service role")
+	}
+	// The lock is taken before the prompt, so its warning is printed even
+	// with -yes, and released if the restore does not start
+	lock := stackLock(env, vaultName, cfg)
+	if *noCache {
+		lock.Path = ""
+	}
+	held, other := takeRestoreLock(ctx, lock, env, out)
+	if !*yes && !confirm(stdin, out, "\nStart this restore? [y/N]: ") {
+		releaseRestoreLock(ctx, lock, held)
+		if *output == "json" {
+			printFailure(*output, errors.New("the restore was not confirmed"))
+		} else {
+			fmt.Println("Not restored.")
+		}
+		return 2
+	}
+
+	started := time.Now()
+	jobID, err := env.client.StartRestoreJob(ctx, rp, env.stackName, vaultName, opts)
+	if err != nil {
+		releaseRestoreLock(ctx, lock, held)
+		printFailure(*output, err)
+		return 1
+	}
+	fmt.Fprintf(out, "Started restore job %s\n", jobID)
+	result := restoreOutput{JobID: jobID, RecoveryPoint: api.NewRecoveryPoint(rp)}
+	var warnings []string
+	if other != nil {
+		warnings = append(warnings, lockWarning(env.stackName, other))
+	}
+	if !at.IsZero() {
+		at := at.UTC()
+		result.At = &at
+	}
+	job := store.TrackedJob{
+		JobID:            jobID,
+		Kind:             aws.JobKindRestore,
+		Region:           env.region.Region,
+		Vault:            vaultName,
+		ResourceType:     rp.ResourceType,
+		ResourceID:       rp.ResourceID,
+		RecoveryPointARN: rp.RecoveryPointARN,
+		StartedAt:        started,
+	}
+	table := cfg.JournalTable()
+	journalRestore(ctx, env, table, job, config.EventStarted)
+
+	// Simulated job IDs cannot be watched, so they are never saved, nor
+	// anything with -no-cache
+	historyPath := ""
+	if !env.client.Simulated() && !*noCache {
+		historyPath, _ = store.DefaultHistoryPath()
+	}
+	if historyPath != "" {
+		if _, err := store.SaveJobs(historyPath, job); err != nil {
+			warning := fmt.Sprintf("the restore is not saved to the job history: %v", err)
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+			warnings = append(warnings, warning)
+			historyPath = ""
+		}
+	}
+	result.Warning = strings.Join(warnings, "; ")
+	// Without -wait the command exits while the restore runs, so the lock
+	// is left to expire; with it, the lock is held until the restore ends
+	if !*wait {
+		if historyPath != "" {
+			fmt.Fprintln(out, "Follow it with: backup-tui watch")
+		}
+		if *output == "json" {
+			return printJSON(result)
+		}
+		return 0
+	}
+
+	clientFor := func(string) (*aws.BackupClient, error) { return env.client, nil }
+	onFinish := func(j store.TrackedJob) {
+		if historyPath != "" {
+			if _, err := store.SaveJobs(historyPath, j); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		event := config.EventCompleted
+		if j.State != "COMPLETED" {
+			event = config.EventFailed
+		} else {
+			tagRestored(ctx, env, out, rp, j.JobID, *label)
+		}
+		journalRestore(ctx, env, table, j, event)
+	}
+	stopLock := lock.Hold(ctx, held, func(err error) {
+		fmt.Fprintf(os.Stderr, "Warning: restore lock of %s: %v\n", env.stackName, err)
+	})
+	failed := watchJobs(ctx, []store.TrackedJob{job}, clientFor, *interval, out, onFinish)
+	// Interrupted, the restore still runs, so its lock is left to expire
+	if ctx.Err() == nil {
+		stopLock()
+	}
+	if *output == "json" {
+		status, err := env.client.GetJobStatus(ctx, aws.JobKindRestore, jobID)
+		if err != nil {
+			printFailure(*output, err)
+			return 1
+		}
+		st := api.NewJobStatus(aws.JobKindRestore, status)
+		result.Status = &st
+		if code := printJSON(result); code != 0 {
+			return code
+		}
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// findRecoveryPointAt returns the restorable recovery point of the vault's
+// resource of resourceType ("" when the vault backs up one resource)
+// closest to but not after at, and writes which it picked to out, with
+// whether a point-in-time restore of the live cluster reaches at instead.
+func findRecoveryPointAt(ctx context.Context, env *environment, vaultName string, at time.Time, resourceType string, out io.Writer) (aws.RecoveryPoint, error) {
+	latest, err := env.client.RestorablePointsAt(ctx, vaultName, at)
+	if err != nil {
+		return aws.RecoveryPoint{}, err
+	}
+	var matches []aws.LatestPoint
+	var names []string
+	for _, p := range latest {
+		if resourceType == "" || strings.EqualFold(p.ResourceType, resourceType) {
+			matches = append(matches, p)
+			names = append(names, p.ResourceType+" "+p.ResourceID)
+		}
+	}
+	switch {
+	case len(matches) == 0 && resourceType != "":
+		return aws.RecoveryPoint{}, fmt.Errorf("vault %s has no %s backups", vaultName, resourceType)
+	case len(matches) == 0:
+		return aws.RecoveryPoint{}, fmt.Errorf("vault %s has no backups", vaultName)
+	case len(matches) > 1:
+		return aws.RecoveryPoint{}, fmt.Errorf("vault %s backs up %s; pick one with -type or restore by recovery point ARN", vaultName, strings.Join(names, ", "))
+	}
+	p := matches[0]
+	if !p.Found() {
+		return aws.RecoveryPoint{}, fmt.Errorf("%s %s has no completed backup in warm storage at or before %s", p.ResourceType, p.ResourceID, at.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	fmt.Fprintf(out, "Closest restorable backup of %s %s at or before %s: %s before\n", p.ResourceType, p.ResourceID,
+		at.UTC().Format("2006-01-02 15:04 MST"), strings.TrimSuffix(at.Sub(p.CreatedAt).Round(time.Minute).String(), "0s"))
+	printPITR(out, lookupPITR(ctx, env, matches, at))
+	fmt.Fprintln(out)
+	return findRecoveryPoint(ctx, env.client, vaultName, p.RecoveryPointARN)
+}
+
+// printRestore writes what restoring rp as p resolves it would create or
+// modify.
+func printRestore(out io.Writer, rp aws.RecoveryPoint, p *plan.Plan) {
+	fmt.Fprintf(out, "Recovery point %s\n", rp.RecoveryPointARN)
+	fmt.Fprintf(out, "  %s %s, backed up %s\n\n", rp.ResourceType, rp.ResourceID, rp.CreationDate.UTC().Format("2006-01-02 15:04 MST"))
+	fmt.Fprintln(out, "Restoring it will:")
+	for _, c := range p.Changes {
+		symbol := "~"
+		if c.Action == "create" {
+			symbol = "+"
+		}
+		fmt.Fprintf(out, "  %s %s %s\n", symbol, c.Action, c.Resource)
+		if c.Detail != "" {
+			fmt.Fprintf(out, "      %s\n", c.Detail)
+		}
+	}
+	fmt.Fprintf(out, "  The restore job runs as %s\n", p.Restore.RoleARN)
+}
+
+// tagRestored stamps the operator's identity, as the TUI does, and the
+// drill label if there is one, on what the completed restore job created.
+// Tags that cannot be added are reported, but the restore has completed.
+func tagRestored(ctx context.Context, env *environment, out io.Writer, rp aws.RecoveryPoint, jobID, label string) {
+	status, err := env.client.GetJobStatus(ctx, aws.JobKindRestore, jobID)
+	if err == nil {
+		err = env.client.TagRestoredResource(ctx, rp, status)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: the restored resource is not tagged: %v\n", err)
+		return
+	}
+	if label == "" {
+		return
+	}
+	switch arn, err := env.client.LabelRestoredResource(ctx, rp, status, label); {
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Warning: the restored resource is not labelled: %v\n", err)
+	case arn == "":
+		fmt.Fprintln(out, "The restore created no new resource to label")
+	default:
+		fmt.Fprintf(out, "Labelled %s with %s=%s\n", arn, aws.LabelTagKey, label)
+	}
+}
+
+// journalRestore records the restore job's event in the shared journal
+// table, or does nothing without one. A journal that cannot be written is
+// reported, but the restore goes ahead.
+func journalRestore(ctx context.Context, env *environment, table string, j store.TrackedJob, event string) {
+	if table == "" {
+		return
+	}
+	host, _ := os.Hostname()
+	e := aws.JournalEntry{
+		Env:              env.region.Region + "/" + env.stackName,
+		At:               time.Now(),
+		Operator:         env.client.CallerARN(),
+		Host:             host,
+		Kind:             aws.JobKindRestore,
+		Event:            event,
+		ResourceType:     j.ResourceType,
+		ResourceID:       j.ResourceID,
+		RecoveryPointARN: j.RecoveryPointARN,
+		JobID:            j.JobID,
+	}
+	if event == config.EventFailed {
+		e.Message = "restore job ended " + j.State
+	}
+	if err := env.client.RecordOperation(ctx, table, e); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
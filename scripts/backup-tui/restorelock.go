@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/config"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+// stackLock returns the advisory restore lock of the environment's stack,
+// kept where the TUI keeps it: the local lock file, and the vault tag when
+// the config shares it. Simulated sessions keep no local lock, as the TUI.
+func stackLock(env *environment, vaultName string, cfg *config.Config) store.StackLock {
+	k := store.StackLock{Env: store.LockEnv(env.region.Region, env.stackName), Vault: vaultName, Stack: env.stackName}
+	if !env.client.Simulated() {
+		k.Path, _ = store.DefaultLocksPath()
+	}
+	if cfg.SharedRestoreLock {
+		k.Shared = env.client
+	}
+	return k
+}
+
+// takeRestoreLock takes the stack's restore lock before a restore starts.
+// Another session's lock is written to out as a warning, and returned: it
+// is advisory, so the restore goes ahead, but even -yes cannot keep the
+// operator from seeing it. A lock that could not be checked or written is
+// reported on stderr.
+func takeRestoreLock(ctx context.Context, k store.StackLock, env *environment, out io.Writer) (held store.RestoreLock, other *store.RestoreLock) {
+	held, other, err := k.Take(ctx, store.SessionLock(env.client.CallerARN()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: restore lock of %s: %v; other sessions may not see this restore\n", env.stackName, err)
+	}
+	if other != nil {
+		fmt.Fprintf(out, "Warning: %s\n", lockWarning(env.stackName, other))
+	}
+	return held, other
+}
+
+// lockWarning describes another session's lock on stack.
+func lockWarning(stack string, other *store.RestoreLock) string {
+	return fmt.Sprintf("a restore of %s is in progress in another session: %s (advisory lock)", stack, other)
+}
+
+// releaseRestoreLock releases the lock takeRestoreLock took, e.g. when the
+// restore did not start.
+func releaseRestoreLock(ctx context.Context, k store.StackLock, l store.RestoreLock) {
+	if err := k.Release(ctx, l); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: restore lock not released; it expires on its own: %v\n", err)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/report"
+)
+
+// runRetention implements "backup-tui retention <subcommand>".
+func runRetention(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "Usage: backup-tui retention plan [-delete-after days] [-cold-after days] [-type RDS,EFS] [-format markdown|json] [-output file] [options]")
+		return 2
+	}
+	switch args[0] {
+	case "plan":
+		return runRetentionPlan(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown retention command %q. Run 'backup-tui -help' for usage.\n", args[0])
+		return 2
+	}
+}
+
+// runRetentionPlan implements "backup-tui retention plan": a dry run of a
+// lifecycle change that lists the recovery points it would delete or move
+// to cold storage, as markdown for sign-off or JSON. Nothing is changed.
+//
+// Exit codes: 0 on success, 1 when the plan could not be produced, 2 for
+// usage errors.
+func runRetentionPlan(args []string) int {
+	fs := flag.NewFlagSet("retention plan", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	deleteAfter := fs.Int64("delete-after", 0, "Proposed days after creation to delete recovery points (0 = never)")
+	coldAfter := fs.Int64("cold-after", 0, "Proposed days after creation to move recovery points to cold storage (0 = never)")
+	resourceType := fs.String("type", "", "AWS Backup resource types to plan for, e.g. RDS or RDS,EFS (empty for all)")
+	format := fs.String("format", "markdown", "Output format: markdown or json")
+	output := fs.String("output", "", "Write the plan to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	proposed := aws.Lifecycle{MoveToColdStorageAfterDays: *coldAfter, DeleteAfterDays: *deleteAfter}
+	if proposed == (aws.Lifecycle{}) {
+		fmt.Fprintln(os.Stderr, "Error: specify the proposed lifecycle with -delete-after and/or -cold-after")
+		return 2
+	}
+	if err := proposed.Validate(); err != nil {
+		printError(err)
+		return 2
+	}
+	renderer, err := report.NewRenderer(*format)
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	types, err := aws.ParseResourceTypes(*resourceType)
+	if err != nil {
+		printError(fmt.Errorf("invalid -type: %w", err))
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+
+	// A locked vault refuses lifecycles outside its retention range, so a
+	// plan that AWS Backup would not apply is not produced
+	if info, err := env.client.DescribeVault(ctx, vaultName); err == nil {
+		if err := info.CheckRetention(proposed); err != nil {
+			printError(err)
+			return 1
+		}
+	}
+
+	points, err := env.client.ListRecoveryPoints(ctx, vaultName, types...)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	p := report.BuildRetentionPlan(points, proposed, time.Now())
+	p.Stack, p.Vault, p.Region, p.ResourceType = env.stackName, vaultName, env.region.Region, strings.Join(types, ", ")
+
+	data, err := renderer.Render(p)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	if *output == "" {
+		_, _ = os.Stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(*output, data, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write plan: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Wrote retention plan to %s (%d deleted when applied, %d deleted sooner, %d moved to cold storage sooner)\n",
+		*output, len(p.Deleted), len(p.ExpireSooner), len(p.MovedToCold))
+	return 0
+}
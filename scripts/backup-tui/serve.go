@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/report"
+)
+
+// dashboardServer serves the latest dashboard read-only: the HTML page on
+// "/" and the same content as JSON on "/dashboard.json".
+type dashboardServer struct {
+	mu        sync.RWMutex
+	dashboard *report.Dashboard
+}
+
+// update replaces the dashboard after a refresh.
+func (s *dashboardServer) update(d *report.Dashboard) {
+	s.mu.Lock()
+	s.dashboard = d
+	s.mu.Unlock()
+}
+
+// ServeHTTP writes the latest dashboard as HTML or JSON.
+func (s *dashboardServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" && r.URL.Path != "/dashboard.json" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.RLock()
+	d := s.dashboard
+	s.mu.RUnlock()
+	if d == nil {
+		http.Error(w, "dashboard not loaded yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if r.URL.Path == "/dashboard.json" {
+		w.Header().Set("Content-Type", "application/json")
+		data, err = d.JSON()
+	} else {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		data, err = d.HTML()
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(data)
+}
+
+// runServe implements "backup-tui serve": it serves a read-only HTML
+// dashboard of the vault (inventory, RPO/RTO status, recent jobs) for NOC
+// wall displays, built like the cron summary and refreshed every -refresh.
+// Nothing can be changed through it.
+//
+// Exit codes: 0 when stopped with Ctrl+C or SIGTERM, 1 when the first
+// refresh or the server failed, 2 for usage errors.
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	addr := fs.String("addr", ":8080", "Address to serve the dashboard on, e.g. :8080 or 127.0.0.1:8080")
+	rpo := fs.String("rpo", "26h", "Maximum age of each resource's newest backup, e.g. 26h or 2d, for resource types without an RPO target in the config")
+	window := fs.String("window", "7d", "How far back to show jobs, e.g. 7d, 30d, or 36h")
+	refresh := fs.Duration("refresh", 5*time.Minute, "How often to reload the dashboard from AWS (at least 1m)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	maxAge, err := parseSpan("-rpo", *rpo)
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	span, err := parseWindow(*window)
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	if *refresh < time.Minute {
+		fmt.Fprintf(os.Stderr, "Error: -refresh must be at least 1m, got %s\n", *refresh)
+		return 2
+	}
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printError(err)
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+
+	load := func() (*report.Dashboard, error) {
+		summary, points, records, err := collectSummary(ctx, env, vaultName, maxAge, span, cfg)
+		if err != nil {
+			return nil, err
+		}
+		d := report.BuildDashboard(summary, points, records)
+		d.Refresh = *refresh
+		return d, nil
+	}
+	d, err := load()
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	srv := &dashboardServer{dashboard: d}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		printError(fmt.Errorf("failed to listen on -addr %s: %w", *addr, err))
+		return 1
+	}
+	httpServer := &http.Server{Handler: srv, ReadHeaderTimeout: 5 * time.Second}
+	go refreshDashboard(ctx, srv, load, *refresh)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving the %s dashboard on http://%s/ (refreshed every %s)\n", env.stackName, ln.Addr(), *refresh)
+	if err := httpServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		printError(err)
+		return 1
+	}
+	return 0
+}
+
+// refreshDashboard reloads the dashboard every interval until ctx is done.
+// A failed refresh is printed and the last dashboard is kept; its update
+// time shows the wall display how old it is.
+func refreshDashboard(ctx context.Context, srv *dashboardServer, load func() (*report.Dashboard, error), interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		d, err := load()
+		if err != nil {
+			if ctx.Err() == nil {
+				printError(fmt.Errorf("dashboard refresh failed, showing the last one: %w", err))
+			}
+			continue
+		}
+		srv.update(d)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/api"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// Exit codes of "backup-tui status" beyond the usual 0, 1, and 2.
+const statusExitRunning = 3 // The job has not finished
+
+// runStatus implements "backup-tui status": it prints the status of a
+// restore or backup job once, for scripts that poll on their own; "watch"
+// follows jobs until they finish.
+//
+// Exit codes: 0 when the job completed, 1 when it failed or could not be
+// looked up, 2 for usage errors, 3 while it is still running.
+func runStatus(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	var region string
+	regionVar(fs, &region, "AWS `region` of the job (resolved from AWS_REGION, AWS_DEFAULT_REGION, or shared config if not provided)")
+	authName := fs.String("auth", "auto", authUsage)
+	profile := fs.String("profile", "", profileUsage)
+	simulate := fs.Bool("simulate", false, "Look the job up in the simulation fixtures")
+	fixtures := fs.String("fixtures", "", "Fixture file for -simulate (built-in sample environment if not provided)")
+	kind := fs.String("kind", "", "Job kind: restore, backup, copy, export, or clone (looked up if not provided)")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: backup-tui status [-kind restore|backup|copy|export|clone] [-output text|json] [options] job-id")
+		return 2
+	}
+	if !checkOutput(*output) {
+		return 2
+	}
+	switch *kind {
+	case "", aws.JobKindRestore, aws.JobKindBackup, aws.JobKindCopy, aws.JobKindExport, aws.JobKindClone:
+	default:
+		printFailure(*output, fmt.Errorf("invalid -kind %q: expected restore, backup, copy, export, or clone", *kind))
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	// A job ID is looked up without a stack
+	env, err := connectClient(ctx, connectOptions{region: region, auth: *authName, profile: *profile, simulate: *simulate, fixtures: *fixtures})
+	if err != nil {
+		printFailure(*output, err)
+		return 1
+	}
+
+	jobID := fs.Arg(0)
+	var status *aws.RestoreJobStatus
+	if *kind == "" {
+		*kind, status, err = env.client.LookupJob(ctx, jobID)
+	} else {
+		status, err = env.client.GetJobStatus(ctx, *kind, jobID)
+	}
+	if err != nil {
+		printFailure(*output, err)
+		return 1
+	}
+
+	if *output == "json" {
+		if code := printJSON(api.NewJobStatus(*kind, status)); code != 0 {
+			return code
+		}
+	} else {
+		printJobStatus(os.Stdout, *kind, status)
+	}
+
+	switch {
+	case !status.IsTerminal:
+		return statusExitRunning
+	case status.Status != "COMPLETED":
+		return 1
+	}
+	return 0
+}
+
+// printJobStatus writes a job's status as text.
+func printJobStatus(out io.Writer, kind string, st *aws.RestoreJobStatus) {
+	line := st.Status
+	if st.PercentDone != "" && !st.IsTerminal {
+		line += " " + st.PercentDone + "%"
+	}
+	fmt.Fprintf(out, "%s job %s: %s\n", kind, st.JobID, line)
+	if st.StatusMessage != "" {
+		fmt.Fprintf(out, "  %s\n", st.StatusMessage)
+	}
+	if st.ResourceType != "" || st.ResourceID != "" {
+		fmt.Fprintf(out, "  Resource:       %s %s\n", st.ResourceType, st.ResourceID)
+	}
+	if st.RecoveryPointARN != "" {
+		fmt.Fprintf(out, "  Recovery point: %s\n", st.RecoveryPointARN)
+	}
+	if st.CreatedResourceARN != "" {
+		fmt.Fprintf(out, "  Restored to:    %s\n", st.CreatedResourceARN)
+	}
+	if !st.CreatedAt.IsZero() {
+		fmt.Fprintf(out, "  Created:        %s\n", st.CreatedAt.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	if !st.CompletedAt.IsZero() {
+		fmt.Fprintf(out, "  Completed:      %s (took %s)\n", st.CompletedAt.UTC().Format("2006-01-02 15:04 MST"), st.CompletedAt.Sub(st.CreatedAt).Round(time.Second))
+	}
+}
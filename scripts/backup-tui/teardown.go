@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+)
+
+// teardownOutput is the JSON document printed by "backup-tui teardown
+// -output json".
+type teardownOutput struct {
+	Stack  string               `json:"stack"`
+	Region string               `json:"region"`
+	Label  string               `json:"label"`
+	DryRun bool                 `json:"dryRun"`
+	Steps  []teardownStepOutput `json:"steps"`
+}
+
+// teardownStepOutput is a resource of a teardown and what became of it.
+type teardownStepOutput struct {
+	Kind    string `json:"kind"`
+	ID      string `json:"id"`
+	Parent  string `json:"parent,omitempty"`
+	Skip    string `json:"skip,omitempty"`
+	Deleted bool   `json:"deleted"`
+	Error   string `json:"error,omitempty"`
+}
+
+// runTeardown implements "backup-tui teardown": it deletes what a restore
+// drill or staging restore left behind, every cluster, DB instance, and
+// file system tagged with the drill's label along with the instances and
+// mount targets in them, in the order AWS requires. It previews the
+// deletions and asks for confirmation first (-yes skips it, -dry-run stops
+// after the preview). The stack's own resources are never deleted.
+//
+// Exit codes: 0 when everything was deleted (or previewed), 1 when the
+// resources could not be listed or a deletion failed, 2 for usage errors
+// and for a teardown that was not confirmed.
+func runTeardown(args []string) int {
+	fs := flag.NewFlagSet("teardown", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fs)
+	label := fs.String("label", "", "Drill label of the resources to delete, the value of their "+aws.LabelTagKey+" tag, e.g. drill-2026-01")
+	dryRun := fs.Bool("dry-run", false, "Only preview what would be deleted")
+	yes := fs.Bool("yes", false, "Delete without asking for confirmation (required without a terminal)")
+	interval := fs.Duration("interval", 15*time.Second, "How often to check whether a file system's mount targets are gone before deleting it")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *label == "" || fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: backup-tui teardown -label drill-label [-dry-run] [-yes] [-o text|json] [options]")
+		return 2
+	}
+	if !checkOutput(*output) {
+		return 2
+	}
+	if *interval <= 0 {
+		printFailure(*output, fmt.Errorf("-interval must be positive, got %s", *interval))
+		return 2
+	}
+	if !*dryRun && !*yes && !stdinIsTerminal() {
+		printFailure(*output, errors.New("no terminal to confirm the teardown on; pass -yes to delete without confirmation, or -dry-run to preview"))
+		return 2
+	}
+	// Keep stdout to the JSON document for tooling reading it
+	out := io.Writer(os.Stdout)
+	if *output == "json" {
+		out = os.Stderr
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printFailure(*output, err)
+		return 1
+	}
+	plan, err := env.client.PlanTeardown(ctx, env.stackName, *label)
+	if err != nil {
+		printFailure(*output, err)
+		return 1
+	}
+
+	result := teardownOutput{Stack: env.stackName, Region: env.region.Region, Label: *label, DryRun: *dryRun, Steps: make([]teardownStepOutput, len(plan.Steps))}
+	index := make(map[aws.TeardownStep]int, len(plan.Steps))
+	for i, step := range plan.Steps {
+		result.Steps[i] = teardownStepOutput{Kind: step.Kind, ID: step.ID, Parent: step.Parent, Skip: step.Skip}
+		index[step] = i
+	}
+	finish := func(code int) int {
+		if *output == "json" {
+			if c := printJSON(result); c != 0 {
+				return c
+			}
+		}
+		return code
+	}
+
+	printTeardown(out, plan)
+	if *dryRun || plan.Deletes() == 0 {
+		return finish(0)
+	}
+	if !*yes && !confirm(stdin, out, fmt.Sprintf("\nDelete these %d resources? [y/N]: ", plan.Deletes())) {
+		if *output == "json" {
+			printFailure(*output, errors.New("the teardown was not confirmed"))
+		} else {
+			fmt.Println("Nothing deleted.")
+		}
+		return 2
+	}
+
+	err = env.client.Teardown(ctx, plan, *interval, func(step aws.TeardownStep, err error) {
+		i := index[step]
+		if err != nil {
+			result.Steps[i].Error = err.Error()
+			printWatchError(out, "Error", err)
+			return
+		}
+		result.Steps[i].Deleted = true
+		fmt.Fprintf(out, "%s  Deleted %s\n", time.Now().Format(time.TimeOnly), step)
+	})
+	if err != nil {
+		fmt.Fprintln(out, "Stopped; rerun the teardown to delete the rest.")
+		return finish(1)
+	}
+	if env.client.Simulated() {
+		fmt.Fprintln(out, "Simulated: nothing was deleted.")
+	} else {
+		fmt.Fprintln(out, "RDS and EFS finish deleting in the background.")
+	}
+	return finish(0)
+}
+
+// printTeardown writes what a teardown deletes, in order, and what it
+// leaves.
+func printTeardown(out io.Writer, plan *aws.TeardownPlan) {
+	skipped := len(plan.Steps) - plan.Deletes()
+	switch {
+	case len(plan.Steps) == 0:
+		fmt.Fprintf(out, "No resources are tagged %s=%s.\n", aws.LabelTagKey, plan.Label)
+		return
+	case skipped > 0:
+		fmt.Fprintf(out, "Resources tagged %s=%s: %d to delete, %d left\n\n", aws.LabelTagKey, plan.Label, plan.Deletes(), skipped)
+	default:
+		fmt.Fprintf(out, "Resources tagged %s=%s: %d to delete\n\n", aws.LabelTagKey, plan.Label, plan.Deletes())
+	}
+	for _, step := range plan.Steps {
+		if step.Skip != "" {
+			fmt.Fprintf(out, "  leave   %s: %s\n", step, step.Skip)
+		} else {
+			fmt.Fprintf(out, "  delete  %s\n", step)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/report"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+// runVerify implements "backup-tui verify": it checks a completed test
+// restore against the config file's verification checks (row counts of key
+// tables for an RDS restore, size and file count for an EFS restore),
+// compares it with the resource's last verified backup, records the result
+// in the local verification history, and prints it as markdown or JSON.
+// With -history it prints the recorded verifications instead.
+//
+// Exit codes: 0 when the restore passed or was inconclusive, 1 when it
+// failed or could not be verified, 2 for usage errors.
+func runVerify(args []string) int {
+	fset := flag.NewFlagSet("verify", flag.ContinueOnError)
+	var conn connectOptions
+	conn.register(fset)
+	jobID := fset.String("job", "", "Completed restore job to verify")
+	mount := fset.String("efs-mount", "", "Directory the restored EFS file system is mounted on, to count its files")
+	history := fset.Bool("history", false, "Print the recorded verifications instead of verifying a restore")
+	format := fset.String("format", "markdown", "Output format: markdown or json")
+	output := fset.String("output", "", "Write the report to a file instead of stdout")
+	if err := fset.Parse(args); err != nil {
+		return 2
+	}
+	if (*jobID == "") == !*history {
+		fmt.Fprintln(os.Stderr, "Usage: backup-tui verify -job id [-efs-mount dir] [options], or backup-tui verify -history")
+		return 2
+	}
+	renderer, err := report.NewRenderer(*format)
+	if err != nil {
+		printError(err)
+		return 2
+	}
+	cfg, err := conn.loadConfig()
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	if *history {
+		path := ""
+		if !conn.simulate {
+			path, _ = store.DefaultVerificationsPath()
+		}
+		vs, err := store.LoadVerifications(path)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+		r := &report.VerificationReport{Stack: conn.stack, Region: conn.region, GeneratedAt: time.Now().UTC(), Verifications: vs}
+		return writeReport(renderer, r, *output)
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	env, err := connect(ctx, conn)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+
+	vaultName := conn.vault
+	if vaultName == "" {
+		vaultName, err = env.client.DiscoverVaultByStack(ctx, env.stackName)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+	}
+
+	status, rp, err := env.client.LookupTestRestore(ctx, vaultName, *jobID)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	target := report.VerifyTarget{RecoveryPoint: rp, RestoreJobID: status.JobID, RestoredARN: status.CreatedResourceARN, RestoredAt: status.CompletedAt}
+
+	checks := cfg.VerifyChecks()
+	fmt.Fprintf(os.Stderr, "Verifying restore job %s of %s %s...\n", status.JobID, rp.ResourceType, rp.ResourceID)
+	m := env.client.MeasureRestore(ctx, aws.RestoreToMeasure{
+		ResourceType: rp.ResourceType,
+		RestoredARN:  target.RestoredARN,
+		InPlace:      target.InPlace(),
+		SecretARN:    checks.DatabaseSecret,
+		Database:     checks.Database,
+		Tables:       checks.TableNames(),
+	})
+	var files *report.FileSample
+	if *mount != "" && rp.ResourceType == "EFS" {
+		if files, err = sampleFiles(*mount); err != nil {
+			printError(err)
+			return 1
+		}
+	}
+
+	// Simulated restores are not recorded, so they never become a baseline
+	path := ""
+	if !env.client.Simulated() {
+		path, _ = store.DefaultVerificationsPath()
+	}
+	previous, err := store.LoadVerifications(path)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	v := report.BuildVerification(target, m, files, checks,
+		store.Baseline(previous, rp.ResourceType, rp.ResourceID, rp.CreationDate), time.Now().UTC())
+	if path != "" {
+		if err := store.SaveVerification(path, v); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record the verification: %v\n", err)
+		}
+	}
+
+	r := &report.VerificationReport{Stack: env.stackName, Region: env.region.Region, GeneratedAt: v.VerifiedAt, Verifications: []store.Verification{v}}
+	if code := writeReport(renderer, r, *output); code != 0 {
+		return code
+	}
+	if v.Result == store.VerifyFailed {
+		return 1
+	}
+	return 0
+}
+
+// writeReport renders doc to the -output file, or to stdout when none was
+// given.
+func writeReport(renderer report.Renderer, doc report.Document, output string) int {
+	data, err := renderer.Render(doc)
+	if err != nil {
+		printError(err)
+		return 1
+	}
+	if output == "" {
+		_, _ = os.Stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(output, data, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write report: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Wrote report to %s\n", output)
+	return 0
+}
+
+// sampleFiles counts the regular files under root and their total size.
+func sampleFiles(root string) (*report.FileSample, error) {
+	sample := &report.FileSample{Root: root}
+	err := filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		sample.Files++
+		sample.Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count files under -efs-mount: %w", err)
+	}
+	return sample, nil
+}
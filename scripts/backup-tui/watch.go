@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/aws"
+	"github.com/openemr/openemr-on-ecs/scripts/backup-tui/internal/store"
+)
+
+// runWatch implements "backup-tui watch": it follows restore and backup jobs
+// until they finish, printing each status change. Without job IDs it follows
+// the unfinished jobs in the TUI's job history file.
+//
+// Exit codes: 0 when every job completed, 1 when a job failed or could not
+// be followed, 2 for usage errors.
+func runWatch(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	var region string
+	regionVar(fs, &region, "AWS `region` of job IDs given as arguments (saved jobs use their own region)")
+	historyPath := fs.String("history", "", "Job history file (default: backup-tui/history.json in the user config directory)")
+	interval := fs.Duration("interval", 30*time.Second, "How often to poll job status")
+	authName := fs.String("auth", "auto", authUsage)
+	profile := fs.String("profile", "", profileUsage)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	auth, err := aws.ParseAuthProvider(*authName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -auth: %v\n", err)
+		return 2
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: -interval must be positive, got %s\n", *interval)
+		return 2
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	var jobs []store.TrackedJob
+	fromHistory := fs.NArg() == 0
+	if fromHistory {
+		if *historyPath == "" {
+			path, err := store.DefaultHistoryPath()
+			if err != nil {
+				printError(err)
+				return 1
+			}
+			*historyPath = path
+		}
+		history, err := store.LoadHistory(*historyPath)
+		if err != nil {
+			printError(err)
+			return 1
+		}
+		for _, j := range history {
+			if !j.Finished() {
+				jobs = append(jobs, j)
+			}
+		}
+		if len(jobs) == 0 {
+			fmt.Printf("No unfinished jobs in %s.\n", *historyPath)
+			return 0
+		}
+	} else {
+		resolved, err := aws.ResolveRegion(ctx, region, *profile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\nSpecify a region with -region or set AWS_REGION or AWS_DEFAULT_REGION\n", err)
+			return 1
+		}
+		for _, id := range fs.Args() {
+			// The kind is looked up on the first poll
+			jobs = append(jobs, store.TrackedJob{JobID: id, Region: resolved.Region})
+		}
+	}
+
+	// Saved jobs may come from sessions in different regions
+	clients := make(map[string]*aws.BackupClient)
+	clientFor := func(region string) (*aws.BackupClient, error) {
+		if c, ok := clients[region]; ok {
+			return c, nil
+		}
+		c, err := newClient(ctx, region, aws.AuthOptions{Provider: auth, Profile: *profile})
+		if err != nil {
+			return nil, credentialError(err)
+		}
+		clients[region] = c
+		return c, nil
+	}
+
+	onFinish := func(store.TrackedJob) {}
+	if fromHistory {
+		onFinish = func(j store.TrackedJob) {
+			if _, err := store.SaveJobs(*historyPath, j); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+	}
+
+	fmt.Printf("Watching %d job(s), polling every %s. Press Ctrl+C to stop.\n", len(jobs), *interval)
+	if failed := watchJobs(ctx, jobs, clientFor, *interval, os.Stdout, onFinish); failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// printWatchError prints a timestamped line for an error polling the job
+// labelled label, with the AWS operation and request ID when there are any.
+func printWatchError(out io.Writer, label string, err error) {
+	line := fmt.Sprintf("%s  %s: %v", time.Now().Format(time.TimeOnly), label, err)
+	if d := aws.DescribeError(err); !d.IsZero() {
+		line += " [" + d.String() + "]"
+	}
+	fmt.Fprintln(out, line)
+}
+
+// watchJobs polls jobs until each reaches a terminal state or ctx is
+// cancelled, printing status changes, and returns the number of jobs that
+// did not complete. onFinish is called with each job as it finishes.
+func watchJobs(ctx context.Context, jobs []store.TrackedJob, clientFor func(region string) (*aws.BackupClient, error),
+	interval time.Duration, out io.Writer, onFinish func(store.TrackedJob)) int {
+	failed := 0
+	last := make(map[string]string, len(jobs))
+	pending := jobs
+	for {
+		var next []store.TrackedJob
+		for _, j := range pending {
+			label := fmt.Sprintf("%s %s", j.ResourceType, j.ResourceID)
+			if j.ResourceType == "" {
+				label = j.JobID
+			}
+			client, err := clientFor(j.Region)
+			if err != nil {
+				printWatchError(out, label, err)
+				failed++
+				continue
+			}
+
+			if j.Kind == "" {
+				kind, _, err := client.LookupJob(ctx, j.JobID)
+				if err != nil {
+					printWatchError(out, label, err)
+					failed++
+					continue
+				}
+				j.Kind = kind
+			}
+			status, err := client.GetJobStatus(ctx, j.Kind, j.JobID)
+			if err != nil {
+				// Transient errors are retried on the next poll
+				printWatchError(out, label, err)
+				next = append(next, j)
+				continue
+			}
+			line := status.Status
+			if status.PercentDone != "" && !status.IsTerminal {
+				line += " " + status.PercentDone + "%"
+			}
+			if status.IsTerminal && status.StatusMessage != "" {
+				line += ": " + status.StatusMessage
+			}
+			if line != last[j.JobID] {
+				fmt.Fprintf(out, "%s  %s (%s): %s\n", time.Now().Format(time.TimeOnly), label, j.JobID, line)
+				last[j.JobID] = line
+			}
+
+			if !status.IsTerminal {
+				next = append(next, j)
+				continue
+			}
+			if status.Status != "COMPLETED" {
+				failed++
+			}
+			j.State = status.Status
+			onFinish(j)
+		}
+
+		pending = next
+		if len(pending) == 0 {
+			return failed
+		}
+		select {
+		case <-ctx.Done():
+			fmt.Fprintf(out, "Stopped with %d job(s) still running.\n", len(pending))
+			return failed + len(pending)
+		case <-time.After(interval):
+		}
+	}
+}